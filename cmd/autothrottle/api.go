@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/honeycombio/kafka-kit/kafkazk"
 )
@@ -13,20 +14,30 @@ import (
 // APIConfig holds configuration
 // params for the admin API.
 type APIConfig struct {
-	Listen      string
-	ZKPrefix    string
-	RateSetting string
+	Listen       string
+	ZKPrefix     string
+	RateSetting  string
+	PauseSetting string
+	// Optional throttle change audit log.
+	Audit AuditLog
+	// Control loop status.
+	Status *LoopStatus
 }
 
 var (
 	rateSettingsZNode = "override_rate"
+	pauseZNode        = "paused"
+	auditZNode        = "audit"
+	electionZNode     = "leader"
 	incorrectMethod   = "disallowed method\n"
 )
 
 func initAPI(c *APIConfig, zk kafkazk.Handler) {
 	c.RateSetting = rateSettingsZNode
+	c.PauseSetting = pauseZNode
 
 	p := fmt.Sprintf("/%s/%s", c.ZKPrefix, c.RateSetting)
+	pp := fmt.Sprintf("/%s/%s", c.ZKPrefix, c.PauseSetting)
 	m := http.NewServeMux()
 
 	// Check ZK for override rate config znode.
@@ -64,6 +75,15 @@ func initAPI(c *APIConfig, zk kafkazk.Handler) {
 	m.HandleFunc("/get_throttle", func(w http.ResponseWriter, req *http.Request) { getThrottle(w, req, zk, p) })
 	m.HandleFunc("/set_throttle", func(w http.ResponseWriter, req *http.Request) { setThrottle(w, req, zk, p) })
 	m.HandleFunc("/remove_throttle", func(w http.ResponseWriter, req *http.Request) { removeThrottle(w, req, zk, p) })
+	m.HandleFunc("/get_broker_throttle", func(w http.ResponseWriter, req *http.Request) { getBrokerThrottle(w, req, zk, p) })
+	m.HandleFunc("/set_broker_throttle", func(w http.ResponseWriter, req *http.Request) { setBrokerThrottle(w, req, zk, p) })
+	m.HandleFunc("/get_pause", func(w http.ResponseWriter, req *http.Request) { getPauseState(w, req, zk, pp) })
+	m.HandleFunc("/pause", func(w http.ResponseWriter, req *http.Request) { pause(w, req, zk, pp) })
+	m.HandleFunc("/resume", func(w http.ResponseWriter, req *http.Request) { resume(w, req, zk, pp) })
+	m.HandleFunc("/metrics", getMetrics)
+	m.HandleFunc("/status", func(w http.ResponseWriter, req *http.Request) { getStatus(w, req, zk, pp, c.Status) })
+	m.HandleFunc("/audit", func(w http.ResponseWriter, req *http.Request) { getAudit(w, req, c.Audit) })
+	m.HandleFunc("/remove_broker_throttle", func(w http.ResponseWriter, req *http.Request) { removeBrokerThrottle(w, req, zk, p) })
 
 	go func() {
 		err := http.ListenAndServe(c.Listen, m)
@@ -83,6 +103,7 @@ func getThrottle(w http.ResponseWriter, req *http.Request, zk kafkazk.Handler, p
 
 	r, err := getThrottleOverride(zk, p)
 	if err != nil {
+		metrics.Inc(metricAPIErrorsTotal, "endpoint", req.URL.Path)
 		io.WriteString(w, err.Error())
 		return
 	}
@@ -91,9 +112,12 @@ func getThrottle(w http.ResponseWriter, req *http.Request, zk kafkazk.Handler, p
 	case 0:
 		io.WriteString(w, "no throttle override is set\n")
 	default:
-		resp := fmt.Sprintf("a throttle override is configured at %dMB/s, autoremove==%v\n",
+		resp := fmt.Sprintf("a throttle override is configured at %dMB/s, autoremove==%v",
 			r.Rate, r.AutoRemove)
-		io.WriteString(w, resp)
+		if r.Expires != 0 {
+			resp += fmt.Sprintf(", expires %s", time.Unix(r.Expires, 0).UTC().Format(time.RFC3339))
+		}
+		io.WriteString(w, resp+"\n")
 	}
 }
 
@@ -145,13 +169,29 @@ func setThrottle(w http.ResponseWriter, req *http.Request, zk kafkazk.Handler, p
 		AutoRemove: remove,
 	}
 
+	// Get the optional TTL param.
+
+	if t := req.URL.Query().Get("ttl"); t != "" {
+		ttl, err := time.ParseDuration(t)
+		if err != nil || ttl <= 0 {
+			io.WriteString(w, "ttl param must be a positive duration (e.g. 30m)\n")
+			return
+		}
+		rateCfg.Expires = time.Now().Add(ttl).Unix()
+	}
+
 	err = setThrottleOverride(zk, p, rateCfg)
 	if err != nil {
+		metrics.Inc(metricAPIErrorsTotal, "endpoint", req.URL.Path)
 		io.WriteString(w, fmt.Sprintf("%s\n", err))
-	} else {
-		io.WriteString(w, fmt.Sprintf("throttle successfully set to %dMB/s, autoremove==%v\n",
-			rate, remove))
+		return
+	}
+
+	resp := fmt.Sprintf("throttle successfully set to %dMB/s, autoremove==%v", rate, remove)
+	if rateCfg.Expires != 0 {
+		resp += fmt.Sprintf(", expires %s", time.Unix(rateCfg.Expires, 0).UTC().Format(time.RFC3339))
 	}
+	io.WriteString(w, resp+"\n")
 }
 
 func removeThrottle(w http.ResponseWriter, req *http.Request, zk kafkazk.Handler, p string) {
@@ -169,12 +209,145 @@ func removeThrottle(w http.ResponseWriter, req *http.Request, zk kafkazk.Handler
 
 	err := setThrottleOverride(zk, p, c)
 	if err != nil {
+		metrics.Inc(metricAPIErrorsTotal, "endpoint", req.URL.Path)
 		io.WriteString(w, fmt.Sprintf("%s\n", err))
 	} else {
 		io.WriteString(w, "throttle successfully removed\n")
 	}
 }
 
+func getBrokerThrottle(w http.ResponseWriter, req *http.Request, zk kafkazk.Handler, p string) {
+	logReq(req)
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		io.WriteString(w, incorrectMethod)
+		return
+	}
+
+	overrides, err := getBrokerOverrides(zk, p)
+	if err != nil {
+		metrics.Inc(metricAPIErrorsTotal, "endpoint", req.URL.Path)
+		io.WriteString(w, fmt.Sprintf("%s\n", err))
+		return
+	}
+
+	// Optionally scope to a single broker.
+	ids := overrides.IDs()
+	if i := req.URL.Query().Get("id"); i != "" {
+		id, err := strconv.Atoi(i)
+		if err != nil {
+			io.WriteString(w, "id param must be supplied as an integer\n")
+			return
+		}
+		ids = []int{id}
+	}
+
+	if len(overrides) == 0 {
+		io.WriteString(w, "no broker throttle overrides are set\n")
+		return
+	}
+
+	for _, id := range ids {
+		c, exists := overrides[id]
+		if !exists {
+			io.WriteString(w, fmt.Sprintf("no throttle override is set for broker %d\n", id))
+			continue
+		}
+
+		resp := fmt.Sprintf("a throttle override is configured at %dMB/s for broker %d", c.Rate, id)
+		if c.Expires != 0 {
+			resp += fmt.Sprintf(", expires %s", time.Unix(c.Expires, 0).UTC().Format(time.RFC3339))
+		}
+		io.WriteString(w, resp+"\n")
+	}
+}
+
+func setBrokerThrottle(w http.ResponseWriter, req *http.Request, zk kafkazk.Handler, p string) {
+	logReq(req)
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		io.WriteString(w, incorrectMethod)
+		return
+	}
+
+	// Get broker ID param.
+
+	id, err := strconv.Atoi(req.URL.Query().Get("id"))
+	if err != nil {
+		io.WriteString(w, "id param must be supplied as an integer\n")
+		return
+	}
+
+	// Get rate param.
+
+	r := req.URL.Query().Get("rate")
+	rate, err := strconv.Atoi(r)
+
+	switch {
+	case r == "":
+		io.WriteString(w, "rate param must be supplied\n")
+		return
+	case err != nil:
+		io.WriteString(w, "rate param must be supplied as an integer\n")
+		return
+	case rate <= 0:
+		io.WriteString(w, "rate param must be >0\n")
+		return
+	}
+
+	// Get the optional TTL param.
+
+	c := BrokerOverrideConfig{Rate: rate}
+
+	if t := req.URL.Query().Get("ttl"); t != "" {
+		ttl, err := time.ParseDuration(t)
+		if err != nil || ttl <= 0 {
+			io.WriteString(w, "ttl param must be a positive duration (e.g. 30m)\n")
+			return
+		}
+		c.Expires = time.Now().Add(ttl).Unix()
+	}
+
+	err = setBrokerOverride(zk, p, id, c)
+	if err != nil {
+		metrics.Inc(metricAPIErrorsTotal, "endpoint", req.URL.Path)
+		io.WriteString(w, fmt.Sprintf("%s\n", err))
+		return
+	}
+
+	resp := fmt.Sprintf("throttle successfully set to %dMB/s for broker %d", rate, id)
+	if c.Expires != 0 {
+		resp += fmt.Sprintf(", expires %s", time.Unix(c.Expires, 0).UTC().Format(time.RFC3339))
+	}
+	io.WriteString(w, resp+"\n")
+}
+
+func removeBrokerThrottle(w http.ResponseWriter, req *http.Request, zk kafkazk.Handler, p string) {
+	logReq(req)
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		io.WriteString(w, incorrectMethod)
+		return
+	}
+
+	id, err := strconv.Atoi(req.URL.Query().Get("id"))
+	if err != nil {
+		io.WriteString(w, "id param must be supplied as an integer\n")
+		return
+	}
+
+	removed, err := removeBrokerOverride(zk, p, id)
+	switch {
+	case err != nil:
+		metrics.Inc(metricAPIErrorsTotal, "endpoint", req.URL.Path)
+		io.WriteString(w, fmt.Sprintf("%s\n", err))
+	case !removed:
+		io.WriteString(w, fmt.Sprintf("no throttle override is set for broker %d\n", id))
+	default:
+		io.WriteString(w, fmt.Sprintf("throttle successfully removed for broker %d\n", id))
+	}
+}
+
 func logReq(req *http.Request) {
 	log.Printf("[API] %s %s %s\n", req.Method, req.RequestURI, req.RemoteAddr)
 }
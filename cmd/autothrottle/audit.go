@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// AuditRecord is a throttle change applied to a broker,
+// along with the inputs used to determine the throttles.
+type AuditRecord struct {
+	// Unix timestamp of the change.
+	Timestamp int64 `json:"timestamp"`
+	Broker    int   `json:"broker"`
+	// Previous and applied rates in MB/s;
+	// 0 is no throttle.
+	PrevLeaderRate   float64 `json:"prev_leader_rate"`
+	LeaderRate       float64 `json:"leader_rate"`
+	PrevFollowerRate float64 `json:"prev_follower_rate"`
+	FollowerRate     float64 `json:"follower_rate"`
+	// How the throttles were determined.
+	Reasons []string `json:"reasons,omitempty"`
+	// Topics undergoing reassignment.
+	Topics []string `json:"topics,omitempty"`
+}
+
+// AuditLog durably stores AuditRecords.
+type AuditLog interface {
+	// Write stores the records.
+	Write([]AuditRecord) error
+	// Records returns up to the last n
+	// records, oldest first.
+	Records(n int) ([]AuditRecord, error)
+}
+
+// zkAuditLog is an AuditLog storing the
+// last size records in a znode as JSON.
+type zkAuditLog struct {
+	sync.Mutex
+	zk   kafkazk.Handler
+	path string
+	size int
+}
+
+// NewZKAuditLog returns an AuditLog that retains the last
+// size records in the znode at path. Records are stored in
+// a single znode, so size should account for the ZooKeeper
+// znode size limit (1MB by default).
+func NewZKAuditLog(zk kafkazk.Handler, path string, size int) AuditLog {
+	return &zkAuditLog{zk: zk, path: path, size: size}
+}
+
+// Write implements AuditLog.
+func (a *zkAuditLog) Write(records []AuditRecord) error {
+	a.Lock()
+	defer a.Unlock()
+
+	exists, err := a.zk.Exists(a.path)
+	if err != nil {
+		return fmt.Errorf("Error writing audit records: %s", err)
+	}
+
+	stored, err := a.records()
+	if err != nil {
+		return err
+	}
+
+	stored = append(stored, records...)
+	if len(stored) > a.size {
+		stored = stored[len(stored)-a.size:]
+	}
+
+	d, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("Error marshalling audit records: %s", err)
+	}
+
+	if exists {
+		err = a.zk.Set(a.path, string(d))
+	} else {
+		err = a.zk.Create(a.path, string(d))
+	}
+
+	if err != nil {
+		return fmt.Errorf("Error writing audit records: %s", err)
+	}
+
+	return nil
+}
+
+// Records implements AuditLog.
+func (a *zkAuditLog) Records(n int) ([]AuditRecord, error) {
+	a.Lock()
+	defer a.Unlock()
+
+	records, err := a.records()
+	if err != nil {
+		return nil, err
+	}
+
+	return lastRecords(records, n), nil
+}
+
+func (a *zkAuditLog) records() ([]AuditRecord, error) {
+	records := []AuditRecord{}
+
+	exists, err := a.zk.Exists(a.path)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting audit records: %s", err)
+	}
+
+	if !exists {
+		return records, nil
+	}
+
+	data, err := a.zk.Get(a.path)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting audit records: %s", err)
+	}
+
+	if len(data) == 0 {
+		return records, nil
+	}
+
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("Error unmarshalling audit records: %s", err)
+	}
+
+	return records, nil
+}
+
+// fileAuditLog is an AuditLog appending
+// records to a file as JSON lines.
+type fileAuditLog struct {
+	sync.Mutex
+	path string
+}
+
+// NewFileAuditLog returns an AuditLog that appends records
+// to the file at path, one JSON encoded record per line.
+func NewFileAuditLog(path string) AuditLog {
+	return &fileAuditLog{path: path}
+}
+
+// Write implements AuditLog.
+func (a *fileAuditLog) Write(records []AuditRecord) error {
+	a.Lock()
+	defer a.Unlock()
+
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("Error opening audit log: %s", err)
+	}
+
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return fmt.Errorf("Error writing audit records: %s", err)
+		}
+	}
+
+	return f.Sync()
+}
+
+// Records implements AuditLog.
+func (a *fileAuditLog) Records(n int) ([]AuditRecord, error) {
+	a.Lock()
+	defer a.Unlock()
+
+	records := []AuditRecord{}
+
+	f, err := os.Open(a.path)
+	switch {
+	case os.IsNotExist(err):
+		return records, nil
+	case err != nil:
+		return nil, fmt.Errorf("Error opening audit log: %s", err)
+	}
+
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	for s.Scan() {
+		r := AuditRecord{}
+		if err := json.Unmarshal(s.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("Error unmarshalling audit records: %s", err)
+		}
+
+		records = append(records, r)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("Error reading audit log: %s", err)
+	}
+
+	return lastRecords(records, n), nil
+}
+
+// lastRecords returns up to the last n records.
+func lastRecords(records []AuditRecord, n int) []AuditRecord {
+	if n > 0 && len(records) > n {
+		return records[len(records)-n:]
+	}
+
+	return records
+}
+
+// auditThrottleChanges takes the *ReplicationThrottleMeta, the throttles
+// of each broker prior to an update and the reasons for the update. An
+// AuditRecord is written for each broker with changed throttles.
+func auditThrottleChanges(params *ReplicationThrottleMeta, prev brokerRates, reasons []string) {
+	if params.audit == nil {
+		return
+	}
+
+	now := time.Now().Unix()
+	topics := append([]string{}, params.topics...)
+
+	var records []AuditRecord
+	for _, id := range prev.IDs() {
+		l, f := params.throttles[id], params.followerThrottles[id]
+		if l == prev[id][0] && f == prev[id][1] {
+			continue
+		}
+
+		records = append(records, AuditRecord{
+			Timestamp:        now,
+			Broker:           id,
+			PrevLeaderRate:   prev[id][0],
+			LeaderRate:       l,
+			PrevFollowerRate: prev[id][1],
+			FollowerRate:     f,
+			Reasons:          reasons,
+			Topics:           topics,
+		})
+	}
+
+	if len(records) == 0 {
+		return
+	}
+
+	if err := params.audit.Write(records); err != nil {
+		log.Println(err)
+	}
+}
+
+func getAudit(w http.ResponseWriter, req *http.Request, a AuditLog) {
+	logReq(req)
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		io.WriteString(w, incorrectMethod)
+		return
+	}
+
+	if a == nil {
+		io.WriteString(w, "the audit log is not enabled\n")
+		return
+	}
+
+	// Get the optional limit param.
+	var n int
+	if l := req.URL.Query().Get("limit"); l != "" {
+		var err error
+		n, err = strconv.Atoi(l)
+		if err != nil || n < 0 {
+			io.WriteString(w, "limit param must be supplied as a non-negative integer\n")
+			return
+		}
+	}
+
+	records, err := a.Records(n)
+	if err != nil {
+		metrics.Inc(metricAPIErrorsTotal, "endpoint", req.URL.Path)
+		io.WriteString(w, fmt.Sprintf("%s\n", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func testAuditLog(t *testing.T, a AuditLog) {
+	for i := 0; i < 3; i++ {
+		err := a.Write([]AuditRecord{
+			AuditRecord{Timestamp: int64(i), Broker: 1001, LeaderRate: float64(i)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := a.Records(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 3 || records[0].Timestamp != 0 {
+		t.Errorf("Unexpected records: %+v", records)
+	}
+
+	records, _ = a.Records(2)
+	if len(records) != 2 || records[0].Timestamp != 1 || records[1].Timestamp != 2 {
+		t.Errorf("Unexpected records: %+v", records)
+	}
+}
+
+func TestZKAuditLog(t *testing.T) {
+	zk := newMemZK()
+	a := NewZKAuditLog(zk, "/autothrottle/audit", 2)
+
+	if records, err := a.Records(0); err != nil || len(records) != 0 {
+		t.Errorf("Expected no records, got %v, %v", records, err)
+	}
+
+	a.Write([]AuditRecord{AuditRecord{Timestamp: 0}, AuditRecord{Timestamp: 1}, AuditRecord{Timestamp: 2}})
+
+	// Retains the last 2.
+	records, _ := a.Records(0)
+	if len(records) != 2 || records[0].Timestamp != 1 {
+		t.Errorf("Unexpected records: %+v", records)
+	}
+
+	testAuditLog(t, NewZKAuditLog(newMemZK(), "/autothrottle/audit", 10))
+}
+
+func TestFileAuditLog(t *testing.T) {
+	f, err := ioutil.TempFile("", "autothrottle-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.Close()
+	os.Remove(f.Name())
+	defer os.Remove(f.Name())
+
+	a := NewFileAuditLog(f.Name())
+
+	if records, err := a.Records(0); err != nil || len(records) != 0 {
+		t.Errorf("Expected no records, got %v, %v", records, err)
+	}
+
+	testAuditLog(t, a)
+}
+
+func TestAuditThrottleChanges(t *testing.T) {
+	a := NewZKAuditLog(newMemZK(), "/autothrottle/audit", 10)
+	rtm := &ReplicationThrottleMeta{
+		topics:            []string{"mock"},
+		throttles:         map[int]float64{1001: 100, 1002: 50},
+		followerThrottles: map[int]float64{1001: 100, 1002: 50},
+		audit:             a,
+	}
+
+	prev := brokerRates{
+		1001: [2]float64{100, 100},
+		1002: [2]float64{80, 0},
+	}
+
+	auditThrottleChanges(rtm, prev, []string{"reason"})
+
+	records, _ := a.Records(0)
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+
+	r := records[0]
+	if r.Broker != 1002 || r.PrevLeaderRate != 80 || r.LeaderRate != 50 || r.PrevFollowerRate != 0 ||
+		r.FollowerRate != 50 || r.Reasons[0] != "reason" || r.Topics[0] != "mock" || r.Timestamp == 0 {
+		t.Errorf("Unexpected record: %+v", r)
+	}
+}
+
+func TestGetAudit(t *testing.T) {
+	req := func(method, url string, a AuditLog) string {
+		w := httptest.NewRecorder()
+		getAudit(w, httptest.NewRequest(method, url, nil), a)
+		return w.Body.String()
+	}
+
+	if r := req("GET", "/audit", nil); r != "the audit log is not enabled\n" {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	a := NewZKAuditLog(newMemZK(), "/autothrottle/audit", 10)
+	a.Write([]AuditRecord{AuditRecord{Broker: 1001}, AuditRecord{Broker: 1002}})
+
+	if r := req(http.MethodPost, "/audit", a); r != incorrectMethod {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	if r := req("GET", "/audit?limit=x", a); r != "limit param must be supplied as a non-negative integer\n" {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	var records []AuditRecord
+	if err := json.Unmarshal([]byte(req("GET", "/audit?limit=1", a)), &records); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 1 || records[0].Broker != 1002 {
+		t.Errorf("Unexpected records: %+v", records)
+	}
+}
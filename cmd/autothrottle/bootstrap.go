@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// BootstrapCheck detects replication to brokers catching up after joining
+// the cluster, such as a broker replacing a failed broker under the same ID.
+// This replication occurs without any partition reassignment, but can
+// saturate source brokers just the same.
+type BootstrapCheck struct {
+	zk kafkazk.Handler
+	// Brokers that registered within window
+	// are considered to be bootstrapping.
+	window time.Duration
+
+	// Brokers bootstrapping as of the last check. These
+	// remain bootstrapping past the window until they
+	// have no lagging replicas.
+	bootstrapping map[int]struct{}
+}
+
+// NewBootstrapCheck returns a *BootstrapCheck.
+func NewBootstrapCheck(zk kafkazk.Handler, window time.Duration) *BootstrapCheck {
+	return &BootstrapCheck{
+		zk:            zk,
+		window:        window,
+		bootstrapping: map[int]struct{}{},
+	}
+}
+
+// Reassignments takes the current time and the ongoing reassignments. A
+// kafkazk.Reassignments of partitions (not being reassigned) with out of
+// sync replicas on bootstrapping brokers to those replicas is returned,
+// along with the IDs of the bootstrapping brokers. The partitions can be
+// throttled as if they were being reassigned to the lagging replicas.
+func (b *BootstrapCheck) Reassignments(now time.Time, ongoing kafkazk.Reassignments) (kafkazk.Reassignments, []int, error) {
+	meta, errs := b.zk.GetAllBrokerMeta(false)
+	if errs != nil {
+		return nil, nil, fmt.Errorf("Error fetching broker metadata: %v", errs)
+	}
+
+	candidates := map[int]struct{}{}
+	for id, m := range meta {
+		// The registration timestamp is
+		// in milliseconds since epoch.
+		ms, err := strconv.ParseInt(m.Timestamp, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if now.Sub(time.Unix(0, ms*int64(time.Millisecond))) < b.window {
+			candidates[id] = struct{}{}
+		}
+	}
+
+	for id := range b.bootstrapping {
+		if _, registered := meta[id]; registered {
+			candidates[id] = struct{}{}
+		}
+	}
+
+	r := kafkazk.Reassignments{}
+	bootstrapping := map[int]struct{}{}
+
+	// Partition states are only
+	// fetched if necessary.
+	if len(candidates) > 0 {
+		pm, err := kafkazk.PartitionMapFromZK(topicsRegex, b.zk)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		oos, err := pm.OutOfSync(b.zk, meta)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, o := range oos {
+			if _, reassigning := ongoing[o.Topic][o.Partition]; reassigning {
+				continue
+			}
+
+			var lagging []int
+			for _, id := range o.Lagging {
+				if _, exists := candidates[id]; exists {
+					lagging = append(lagging, id)
+					bootstrapping[id] = struct{}{}
+				}
+			}
+
+			if len(lagging) == 0 {
+				continue
+			}
+
+			if r[o.Topic] == nil {
+				r[o.Topic] = map[int][]int{}
+			}
+
+			r[o.Topic][o.Partition] = lagging
+		}
+	}
+
+	b.bootstrapping = bootstrapping
+
+	var ids []int
+	for id := range bootstrapping {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	return r, ids, nil
+}
+
+// mergeReassignments returns a kafkazk.Reassignments
+// of all partitions in a and b; partitions in a take
+// precedence.
+func mergeReassignments(a, b kafkazk.Reassignments) kafkazk.Reassignments {
+	merged := kafkazk.Reassignments{}
+
+	for _, r := range []kafkazk.Reassignments{b, a} {
+		for t, partitions := range r {
+			if merged[t] == nil {
+				merged[t] = map[int][]int{}
+			}
+
+			for p, replicas := range partitions {
+				merged[t][p] = replicas
+			}
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// registrationZK returns broker
+// metadata with registration times.
+type registrationZK struct {
+	kafkazk.Mock
+	registered map[int]time.Time
+}
+
+func (zk *registrationZK) GetAllBrokerMeta(withMetrics bool) (kafkazk.BrokerMetaMap, []error) {
+	bm, errs := zk.Mock.GetAllBrokerMeta(withMetrics)
+	for id, t := range zk.registered {
+		if m, exists := bm[id]; exists {
+			m.Timestamp = strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+		}
+	}
+
+	return bm, errs
+}
+
+func TestBootstrapCheck(t *testing.T) {
+	now := time.Now()
+
+	zk := &registrationZK{
+		registered: map[int]time.Time{
+			1001: now.Add(-30 * time.Second),
+			1003: now.Add(-time.Hour),
+		},
+	}
+
+	b := NewBootstrapCheck(zk, 5*time.Minute)
+
+	// Mock partitions (replicas, isr):
+	// p0 [1001 1002] [1000 1002]
+	// p1 [1002 1001] [1002 1003]
+	// p2 [1003 1004 1001] [1004 1005]
+	// p3 [1004 1003 1002] [1006 1007]
+	ongoing := kafkazk.Reassignments{"test_topic": {1: []int{1002, 1003}}}
+
+	r, ids, err := b.Reassignments(now, ongoing)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 1 || ids[0] != 1001 {
+		t.Errorf("Expected bootstrapping brokers [1001], got %v", ids)
+	}
+
+	// 1003 registered outside the window; p1 of
+	// test_topic is excluded as it's reassigning.
+	expected := map[string]map[int][]int{
+		"test_topic":  {0: {1001}, 2: {1001}},
+		"test_topic2": {0: {1001}, 1: {1001}, 2: {1001}},
+	}
+
+	for topic, partitions := range expected {
+		if len(r[topic]) != len(partitions) {
+			t.Errorf("Expected partitions %v for %s, got %v", partitions, topic, r[topic])
+			continue
+		}
+
+		for p, replicas := range partitions {
+			if len(r[topic][p]) != 1 || r[topic][p][0] != replicas[0] {
+				t.Errorf("Expected replicas %v for %s p%d, got %v", replicas, topic, p, r[topic][p])
+			}
+		}
+	}
+
+	// A broker remains bootstrapping past
+	// the window while replicas are lagging.
+	r, ids, err = b.Reassignments(now.Add(time.Hour), ongoing)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 1 || ids[0] != 1001 || len(r) != 2 {
+		t.Errorf("Expected broker 1001 to remain bootstrapping, got %v, %v", ids, r)
+	}
+
+	// No brokers bootstrapping.
+	b = NewBootstrapCheck(zk, time.Second)
+	r, ids, err = b.Reassignments(now, ongoing)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r) != 0 || len(ids) != 0 {
+		t.Errorf("Expected no bootstrapping brokers, got %v, %v", ids, r)
+	}
+}
+
+func TestMergeReassignments(t *testing.T) {
+	a := kafkazk.Reassignments{"a": {0: []int{1001, 1002}}}
+	b := kafkazk.Reassignments{
+		"a": {0: []int{1003}, 1: []int{1004}},
+		"b": {0: []int{1005}},
+	}
+
+	m := mergeReassignments(a, b)
+
+	if r := m["a"][0]; len(r) != 2 || r[0] != 1001 {
+		t.Errorf("Expected reassignment in a to take precedence, got %v", r)
+	}
+
+	if len(m["a"]) != 2 || len(m["b"]) != 1 {
+		t.Errorf("Unexpected merged reassignments %v", m)
+	}
+
+	// Inputs are unmodified.
+	if len(a["a"]) != 1 {
+		t.Error("Expected a to be unmodified")
+	}
+}
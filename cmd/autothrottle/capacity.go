@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+)
+
+// CapacityConfig holds broker network capacity
+// profiles in Mb/s, as read from the file
+// specified via -cap-config.
+type CapacityConfig struct {
+	// Map of instance type to capacity.
+	InstanceTypes map[string]float64 `json:"instance_types"`
+	// Map of broker ID to capacity; takes
+	// precedence over the instance type.
+	Brokers map[string]float64 `json:"brokers"`
+	// Capacity used for brokers of an
+	// instance type not otherwise listed.
+	Default float64 `json:"default"`
+}
+
+// megabitsToMB converts Mb/s to MB/s.
+func megabitsToMB(v float64) float64 {
+	return v / 8
+}
+
+// loadCapacityConfig reads a CapacityConfig from the file at path.
+func loadCapacityConfig(path string) (*CapacityConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading capacity config: %s", err)
+	}
+
+	c := &CapacityConfig{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("Error parsing capacity config: %s", err)
+	}
+
+	return c, nil
+}
+
+// apply populates the NewLimitsConfig with the capacities of the
+// CapacityConfig, converted to MB/s. Capacities from the CapacityConfig
+// take precedence over any already present in the NewLimitsConfig.
+func (c *CapacityConfig) apply(lc *NewLimitsConfig) error {
+	if c.Default < 0 {
+		return fmt.Errorf("Error in capacity config: default must be >= 0")
+	}
+
+	if lc.CapacityMap == nil {
+		lc.CapacityMap = map[string]float64{}
+	}
+
+	for it, v := range c.InstanceTypes {
+		if v <= 0 {
+			return fmt.Errorf("Error in capacity config: capacity for instance type %s must be > 0", it)
+		}
+		lc.CapacityMap[it] = megabitsToMB(v)
+	}
+
+	if lc.BrokerCapacityMap == nil {
+		lc.BrokerCapacityMap = map[int]float64{}
+	}
+
+	for k, v := range c.Brokers {
+		id, err := strconv.Atoi(k)
+		if err != nil {
+			return fmt.Errorf("Error in capacity config: invalid broker ID %s", k)
+		}
+		if v <= 0 {
+			return fmt.Errorf("Error in capacity config: capacity for broker %d must be > 0", id)
+		}
+		lc.BrokerCapacityMap[id] = megabitsToMB(v)
+	}
+
+	if c.Default > 0 {
+		lc.DefaultCapacity = megabitsToMB(c.Default)
+	}
+
+	return nil
+}
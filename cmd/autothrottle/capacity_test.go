@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestLoadCapacityConfig(t *testing.T) {
+	f, err := ioutil.TempFile("", "autothrottle-cap")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+
+	f.WriteString(`{"instance_types": {"d2.2xlarge": 1000, "n2-standard-16": 32000}, "brokers": {"1001": 800}, "default": 400}`)
+	f.Close()
+
+	cc, err := loadCapacityConfig(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lc := NewLimitsConfig{
+		Minimum: 10,
+		Maximum: 90,
+		// Superseded by the capacity config.
+		CapacityMap: map[string]float64{"d2.2xlarge": 120, "d2.4xlarge": 240},
+	}
+
+	if err := cc.apply(&lc); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]float64{"d2.2xlarge": 125, "d2.4xlarge": 240, "n2-standard-16": 4000}
+	for it, v := range expected {
+		if lc.CapacityMap[it] != v {
+			t.Errorf("Expected %s capacity %f, got %f", it, v, lc.CapacityMap[it])
+		}
+	}
+
+	if lc.BrokerCapacityMap[1001] != 100 {
+		t.Errorf("Expected broker 1001 capacity 100, got %f", lc.BrokerCapacityMap[1001])
+	}
+
+	if lc.DefaultCapacity != 50 {
+		t.Errorf("Expected default capacity 50, got %f", lc.DefaultCapacity)
+	}
+
+	// Invalid configs.
+	invalid := []*CapacityConfig{
+		&CapacityConfig{Brokers: map[string]float64{"a": 100}},
+		&CapacityConfig{Brokers: map[string]float64{"1001": 0}},
+		&CapacityConfig{InstanceTypes: map[string]float64{"mock": -1}},
+		&CapacityConfig{Default: -1},
+	}
+
+	for n, c := range invalid {
+		if err := c.apply(&NewLimitsConfig{}); err == nil {
+			t.Errorf("[test index %d] Expected non-nil error", n)
+		}
+	}
+
+	if _, err := loadCapacityConfig(f.Name() + "-missing"); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// clusterRestartBackoff is the wait before
+// restarting an exited cluster control loop.
+var clusterRestartBackoff = 10 * time.Second
+
+// ClustersConfig is a map of cluster names to the autothrottle flags
+// (without the leading -) used for the cluster, e.g. zk-addr, net-tx-query
+// and cap-config. Flags not set for a cluster default to the flags autothrottle
+// was started with.
+type ClustersConfig map[string]map[string]string
+
+// loadClustersConfig reads and validates the ClustersConfig at path.
+func loadClustersConfig(path string) (ClustersConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading clusters config: %s", err)
+	}
+
+	cc := ClustersConfig{}
+	if err := json.Unmarshal(data, &cc); err != nil {
+		return nil, fmt.Errorf("Error parsing clusters config: %s", err)
+	}
+
+	if err := cc.validate(flag.CommandLine); err != nil {
+		return nil, err
+	}
+
+	return cc, nil
+}
+
+// names returns the sorted cluster names.
+func (cc ClustersConfig) names() []string {
+	var names []string
+	for n := range cc {
+		names = append(names, n)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// validate checks that each cluster only sets known flags and
+// that clusters don't share listen addresses, as resolved against
+// the flags in fs.
+func (cc ClustersConfig) validate(fs *flag.FlagSet) error {
+	if len(cc) == 0 {
+		return fmt.Errorf("No clusters configured")
+	}
+
+	// Map of flag to address to cluster.
+	listeners := map[string]map[string]string{}
+
+	for _, name := range cc.names() {
+		for f := range cc[name] {
+			if f == "clusters-config" || fs.Lookup(f) == nil {
+				return fmt.Errorf("Invalid flag %s for cluster %s", f, name)
+			}
+		}
+
+		for _, f := range []string{"api-listen", "grpc-listen", "grpc-gateway-listen"} {
+			addr, set := cc[name][f]
+			if !set {
+				addr = fs.Lookup(f).Value.String()
+			}
+
+			if addr == "" {
+				continue
+			}
+
+			if listeners[f] == nil {
+				listeners[f] = map[string]string{}
+			}
+
+			if other, exists := listeners[f][addr]; exists {
+				return fmt.Errorf("Clusters %s and %s have the same %s address %s", other, name, f, addr)
+			}
+
+			listeners[f][addr] = name
+		}
+	}
+
+	return nil
+}
+
+// args takes the arguments autothrottle was started with and returns the
+// arguments for the named cluster: the clusters-config flag is removed,
+// and the cluster's flags are appended, taking precedence. The cluster
+// name is added to the event tags.
+func (cc ClustersConfig) args(base []string, name string) []string {
+	var args []string
+	for i := 0; i < len(base); i++ {
+		f := strings.TrimLeft(base[i], "-")
+		switch {
+		case f == "clusters-config":
+			// Skip the value.
+			i++
+		case strings.HasPrefix(f, "clusters-config="):
+		default:
+			args = append(args, base[i])
+		}
+	}
+
+	flags := cc[name]
+
+	var keys []string
+	for k := range flags {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if k != "dd-event-tags" {
+			args = append(args, fmt.Sprintf("-%s=%s", k, flags[k]))
+		}
+	}
+
+	tags, set := flags["dd-event-tags"]
+	if !set {
+		tags = Config.DDEventTags
+	}
+
+	if tags != "" {
+		tags += ","
+	}
+
+	return append(args, fmt.Sprintf("-dd-event-tags=%scluster:%s", tags, name))
+}
+
+// runClusters runs an autothrottle process for each cluster in the
+// ClustersConfig, taking the arguments autothrottle was started with.
+// Each process is restarted if it exits, and interrupt and terminate
+// signals are forwarded to each process; runClusters then waits for the
+// processes to exit (and apply the shutdown action) before exiting. The output of each process is
+// logged with the cluster name prefixed. runClusters doesn't return.
+func runClusters(cc ClustersConfig, base []string) {
+	// Clear the clusters config from the
+	// environment inherited by each process.
+	var env []string
+	for _, e := range os.Environ() {
+		if !strings.HasPrefix(e, "AUTOTHROTTLE_CLUSTERS_CONFIG=") {
+			env = append(env, e)
+		}
+	}
+
+	// Running processes by cluster name.
+	var mu sync.Mutex
+	running := map[string]*os.Process{}
+	var stopping bool
+	var procs sync.WaitGroup
+
+	// Signals are forwarded to each process.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-sigs
+		log.Printf("Received %s, stopping clusters\n", sig)
+
+		mu.Lock()
+		stopping = true
+		for _, p := range running {
+			p.Signal(sig)
+		}
+		mu.Unlock()
+
+		procs.Wait()
+		os.Exit(0)
+	}()
+
+	for _, name := range cc.names() {
+		go func(name string) {
+			args := cc.args(base, name)
+			for {
+				cmd := exec.Command(os.Args[0], args...)
+				cmd.Env = env
+
+				w := newPrefixWriter(os.Stderr, fmt.Sprintf("[%s] ", name))
+				cmd.Stdout, cmd.Stderr = w, w
+
+				// Processes aren't started
+				// or restarted once stopping.
+				mu.Lock()
+				if stopping {
+					mu.Unlock()
+					w.Close()
+					return
+				}
+
+				log.Printf("Starting autothrottle for cluster %s\n", name)
+
+				err := cmd.Start()
+				started := err == nil
+				if started {
+					running[name] = cmd.Process
+					procs.Add(1)
+				}
+				mu.Unlock()
+
+				if started {
+					err = cmd.Wait()
+
+					mu.Lock()
+					delete(running, name)
+					mu.Unlock()
+				}
+				w.Close()
+
+				mu.Lock()
+				stopped := stopping
+				mu.Unlock()
+
+				if stopped {
+					log.Printf("Autothrottle for cluster %s exited (%v)\n", name, err)
+				} else {
+					log.Printf("Autothrottle for cluster %s exited (%v), restarting in %s\n",
+						name, err, clusterRestartBackoff)
+				}
+
+				if started {
+					procs.Done()
+				}
+
+				time.Sleep(clusterRestartBackoff)
+			}
+		}(name)
+	}
+
+	// Exits once signaled.
+	select {}
+}
+
+// prefixWriter is an io.WriteCloser that writes
+// each line written to it to w with a prefix.
+type prefixWriter struct {
+	pw   *io.PipeWriter
+	done chan struct{}
+}
+
+func newPrefixWriter(w io.Writer, prefix string) *prefixWriter {
+	pr, pw := io.Pipe()
+	p := &prefixWriter{pw: pw, done: make(chan struct{})}
+
+	go func() {
+		defer close(p.done)
+		s := bufio.NewScanner(pr)
+		for s.Scan() {
+			fmt.Fprintf(w, "%s%s\n", prefix, s.Text())
+		}
+		// Drain on errors (e.g. long lines).
+		io.Copy(ioutil.Discard, pr)
+	}()
+
+	return p
+}
+
+// Write implements io.Writer.
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	return p.pw.Write(b)
+}
+
+// Close flushes any buffered output.
+func (p *prefixWriter) Close() error {
+	err := p.pw.Close()
+	<-p.done
+	return err
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func testFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("autothrottle", flag.ContinueOnError)
+	fs.String("zk-addr", "localhost:2181", "")
+	fs.String("api-listen", "localhost:8080", "")
+	fs.String("grpc-listen", "", "")
+	fs.String("grpc-gateway-listen", "", "")
+	fs.String("clusters-config", "", "")
+
+	return fs
+}
+
+func TestLoadClustersConfig(t *testing.T) {
+	f, err := ioutil.TempFile("", "autothrottle-clusters")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+
+	f.WriteString(`{"a": {"zk-addr": "zk-a:2181"}, "b": {"zk-addr": "zk-b:2181", "api-listen": "localhost:8081"}}`)
+	f.Close()
+
+	cc, err := loadClustersConfig(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := cc.names(); len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Errorf("Unexpected clusters: %v", names)
+	}
+}
+
+func TestClustersConfigValidate(t *testing.T) {
+	fs := testFlagSet()
+
+	tests := map[string]ClustersConfig{
+		"no clusters":  ClustersConfig{},
+		"unknown flag": ClustersConfig{"a": {"zk-address": "zk-a:2181"}},
+		"nested":       ClustersConfig{"a": {"clusters-config": "/tmp/c.json"}},
+		// Both default to localhost:8080.
+		"default api-listen": ClustersConfig{"a": {}, "b": {}},
+		"same grpc-listen": ClustersConfig{
+			"a": {"grpc-listen": ":9090"},
+			"b": {"grpc-listen": ":9090", "api-listen": ":8081"},
+		},
+	}
+
+	for name, cc := range tests {
+		if err := cc.validate(fs); err == nil {
+			t.Errorf("[%s] Expected non-nil error", name)
+		}
+	}
+
+	cc := ClustersConfig{
+		"a": {"zk-addr": "zk-a:2181"},
+		"b": {"zk-addr": "zk-b:2181", "api-listen": "localhost:8081"},
+	}
+
+	if err := cc.validate(fs); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClustersConfigArgs(t *testing.T) {
+	cc := ClustersConfig{
+		"a": {"zk-addr": "zk-a:2181", "api-listen": ":8081"},
+		"b": {"dd-event-tags": "env:prod"},
+	}
+
+	base := []string{"-clusters-config", "/tmp/c.json", "--interval=60", "-clusters-config=/tmp/c.json"}
+
+	expected := map[string][]string{
+		"a": []string{"--interval=60", "-api-listen=:8081", "-zk-addr=zk-a:2181", "-dd-event-tags=cluster:a"},
+		"b": []string{"--interval=60", "-dd-event-tags=env:prod,cluster:b"},
+	}
+
+	for name, exp := range expected {
+		args := cc.args(base, name)
+		if len(args) != len(exp) {
+			t.Errorf("[%s] Expected args %v, got %v", name, exp, args)
+			continue
+		}
+
+		for i := range exp {
+			if args[i] != exp[i] {
+				t.Errorf("[%s] Expected args %v, got %v", name, exp, args)
+				break
+			}
+		}
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := newPrefixWriter(&b, "[a] ")
+
+	w.Write([]byte("line 1\nline"))
+	w.Write([]byte(" 2\n"))
+	w.Close()
+
+	if b.String() != "[a] line 1\n[a] line 2\n" {
+		t.Errorf("Unexpected output: %q", b.String())
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// dryRunUpdater is a ConfigUpdater that logs config changes rather than
+// applying them. The configs that would have been applied are stored, so
+// that changes are reported as they would be against a cluster where
+// autothrottle is the only writer of throttle configs.
+type dryRunUpdater struct {
+	// Map of resource type and
+	// name to config key to value.
+	configs map[string]map[string]string
+}
+
+func newDryRunUpdater() *dryRunUpdater {
+	return &dryRunUpdater{configs: map[string]map[string]string{}}
+}
+
+// UpdateKafkaConfig implements ConfigUpdater. A config value of ""
+// deletes the config key. A bool is returned indicating whether any
+// config would have been changed.
+func (d *dryRunUpdater) UpdateKafkaConfig(c kafkazk.KafkaConfig) (bool, error) {
+	resource := c.Type + " " + c.Name
+	if d.configs[resource] == nil {
+		d.configs[resource] = map[string]string{}
+	}
+
+	current := d.configs[resource]
+
+	var changes []string
+	for _, kv := range c.Configs {
+		v, set := current[kv[0]]
+		switch {
+		case kv[1] == "" && set:
+			delete(current, kv[0])
+			changes = append(changes, kv[0]+" (removed)")
+		case kv[1] != "" && (!set || v != kv[1]):
+			current[kv[0]] = kv[1]
+			changes = append(changes, kv[0]+"="+kv[1])
+		}
+	}
+
+	if len(changes) == 0 {
+		return false, nil
+	}
+
+	log.Printf("[dry run] Would update %s configs: %s\n", resource, strings.Join(changes, ", "))
+
+	return true, nil
+}
+
+// eventLogger is an EventPoster that logs events, so that the
+// rationale for throttle changes is logged in dry run mode.
+type eventLogger struct{}
+
+// PostEvent implements EventPoster.
+func (eventLogger) PostEvent(e *kafkametrics.Event) error {
+	log.Printf("%s:\n%s\n", e.Title, e.Text)
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+func TestDryRunUpdateKafkaConfig(t *testing.T) {
+	d := newDryRunUpdater()
+
+	config := kafkazk.KafkaConfig{
+		Type: "broker",
+		Name: "1001",
+		Configs: [][2]string{
+			[2]string{"leader.replication.throttled.rate", "100000000"},
+			[2]string{"follower.replication.throttled.rate", "100000000"},
+		},
+	}
+
+	if changed, _ := d.UpdateKafkaConfig(config); !changed {
+		t.Error("Expected config to be changed")
+	}
+
+	// Unchanged.
+	if changed, _ := d.UpdateKafkaConfig(config); changed {
+		t.Error("Unexpected config change")
+	}
+
+	// Delete.
+	config.Configs[0][1] = ""
+	config.Configs[1][1] = ""
+
+	if changed, _ := d.UpdateKafkaConfig(config); !changed {
+		t.Error("Expected config to be changed")
+	}
+
+	if len(d.configs["broker 1001"]) != 0 {
+		t.Errorf("Expected configs to be removed, got %v", d.configs["broker 1001"])
+	}
+
+	// Deleting unset configs is a no-op.
+	if changed, _ := d.UpdateKafkaConfig(config); changed {
+		t.Error("Unexpected config change")
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// LeaderElection elects a single autothrottle instance, among instances
+// sharing a ZooKeeper election path, to manage throttles. Each instance
+// registers an ephemeral sequential znode; the instance with the lowest
+// sequence number is the leader. If the leader exits or its ZooKeeper
+// session expires, its znode is removed and the next instance takes over.
+type LeaderElection struct {
+	zk   kafkazk.Handler
+	path string
+	// Unique instance ID, stored as
+	// the registered znode data.
+	id string
+}
+
+// NewLeaderElection takes a kafkazk.Handler and election path and returns
+// a *LeaderElection. The election path is created if it doesn't exist.
+func NewLeaderElection(zk kafkazk.Handler, path string) (*LeaderElection, error) {
+	exists, err := zk.Exists(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error checking election path: %s", err)
+	}
+
+	if !exists {
+		if err := zk.Create(path, ""); err != nil {
+			return nil, fmt.Errorf("Error creating election path: %s", err)
+		}
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return &LeaderElection{
+		zk:   zk,
+		path: path,
+		id:   fmt.Sprintf("%s-%d", host, os.Getpid()),
+	}, nil
+}
+
+// ID returns the instance ID.
+func (e *LeaderElection) ID() string {
+	return e.id
+}
+
+// IsLeader returns whether this instance is the leader, along with the
+// leader instance ID. The instance is (re)registered if it isn't, e.g.
+// if its ZooKeeper session expired. An error is returned if the leader
+// can't be determined, in which case the instance shouldn't act as the
+// leader.
+func (e *LeaderElection) IsLeader() (bool, string, error) {
+	leader, registered, err := e.leader()
+	if err != nil {
+		return false, "", err
+	}
+
+	if !registered {
+		if _, err := e.zk.CreateEphemeralSequential(e.path+"/candidate_", e.id); err != nil {
+			return false, "", fmt.Errorf("Error registering election candidate: %s", err)
+		}
+
+		// Candidates registering concurrently
+		// are ordered once registered.
+		if leader, registered, err = e.leader(); err != nil {
+			return false, "", err
+		}
+
+		if !registered {
+			return false, "", fmt.Errorf("Election candidate %s not registered", e.id)
+		}
+	}
+
+	return leader == e.id, leader, nil
+}
+
+// leader returns the leader instance ID and whether
+// this instance is registered as a candidate.
+func (e *LeaderElection) leader() (string, bool, error) {
+	children, err := e.zk.Children(e.path)
+	if err != nil {
+		return "", false, fmt.Errorf("Error listing election candidates: %s", err)
+	}
+
+	// Znodes are named with a common prefix and
+	// a zero padded sequence number.
+	sort.Strings(children)
+
+	var leader string
+	var registered bool
+
+	// Instances are identified by znode data rather than
+	// name, so that any znode created by this instance
+	// in a failed request (e.g. on a connection loss)
+	// is recognized.
+	for i, c := range children {
+		d, err := e.zk.Get(fmt.Sprintf("%s/%s", e.path, c))
+		if err != nil {
+			return "", false, fmt.Errorf("Error fetching election candidate: %s", err)
+		}
+
+		if i == 0 {
+			leader = string(d)
+		}
+
+		if string(d) == e.id {
+			registered = true
+		}
+	}
+
+	return leader, registered, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestLeaderElection(t *testing.T) {
+	zk := newMemZK()
+	p := "/autothrottle/leader"
+
+	a, err := NewLeaderElection(zk, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, _ := zk.Exists(p); !exists {
+		t.Error("Expected election path to be created")
+	}
+
+	b, _ := NewLeaderElection(zk, p)
+	a.id, b.id = "a", "b"
+
+	// The first instance registered is the leader.
+	for _, test := range []struct {
+		e      *LeaderElection
+		leader bool
+	}{{a, true}, {b, false}, {a, true}} {
+		leader, id, err := test.e.IsLeader()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if leader != test.leader || id != "a" {
+			t.Errorf("[%s] Expected leader %v with leader ID a, got %v with leader ID %s",
+				test.e.id, test.leader, leader, id)
+		}
+	}
+
+	if c, _ := zk.Children(p); len(c) != 2 {
+		t.Errorf("Expected 2 candidates, got %d", len(c))
+	}
+
+	// The leader's session expires.
+	zk.Delete(p + "/candidate_0000000001")
+
+	if leader, id, _ := b.IsLeader(); !leader || id != "b" {
+		t.Errorf("Expected b to take over as leader, got %v with leader ID %s", leader, id)
+	}
+
+	// a re-registers as a standby.
+	if leader, id, _ := a.IsLeader(); leader || id != "b" {
+		t.Errorf("Expected a to be a standby, got %v with leader ID %s", leader, id)
+	}
+
+	if c, _ := zk.Children(p); len(c) != 2 {
+		t.Errorf("Expected 2 candidates, got %d", len(c))
+	}
+}
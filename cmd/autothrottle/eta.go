@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// transferEstimate is the estimated time to replicate the
+// out of sync replicas of ongoing reassignments.
+type transferEstimate struct {
+	// Bytes (on-disk) to be replicated.
+	bytes float64
+	// Estimated time to complete, as
+	// determined by the most constrained
+	// destination broker.
+	eta    time.Duration
+	broker int
+	// Destination brokers without a
+	// throttle, which aren't estimated.
+	unthrottled []int
+}
+
+// String returns a description of the estimate for logs and events.
+func (e transferEstimate) String() string {
+	if e.bytes == 0 {
+		return "All reassigned replicas are in-sync"
+	}
+
+	s := fmt.Sprintf("Estimated time to replicate %.2fGB at the applied throttles: %s (destination broker %d)",
+		e.bytes/1000000000.00, e.eta.Truncate(time.Second), e.broker)
+
+	if len(e.unthrottled) > 0 {
+		s += fmt.Sprintf("; excludes unthrottled brokers %v", e.unthrottled)
+	}
+
+	return s
+}
+
+// estimateTransfer takes a kafkazk.Handler, the ongoing reassignments
+// and the applied follower throttles (MB/s), and estimates the time to
+// complete the reassignments from the on-disk sizes of the partitions
+// being replicated to destination brokers that aren't yet in the ISR.
+// Replicas are assumed to be replicated in full at the throttle rates,
+// which limit replicated data as stored on disk; the estimates are
+// therefore an upper bound for partially replicated partitions.
+// Partition sizes are read from the partition metadata written by
+// metricsfetcher.
+func estimateTransfer(zk kafkazk.Handler, r kafkazk.Reassignments, throttles map[int]float64) (transferEstimate, error) {
+	var e transferEstimate
+
+	pm, err := zk.GetAllPartitionMeta()
+	if err != nil {
+		return e, fmt.Errorf("Error estimating reassignment time: %s", err)
+	}
+
+	// Bytes to be replicated by destination broker.
+	pending := map[int]float64{}
+
+	for t, partitions := range r {
+		states, err := zk.GetTopicStateISR(t)
+		if err != nil {
+			return e, fmt.Errorf("Error estimating reassignment time: %s", err)
+		}
+
+		for p, replicas := range partitions {
+			size, err := pm.Size(kafkazk.Partition{Topic: t, Partition: p})
+			if err != nil {
+				return e, fmt.Errorf("Error estimating reassignment time: %s", err)
+			}
+
+			isr := map[int]struct{}{}
+			for _, id := range states[strconv.Itoa(p)].ISR {
+				isr[id] = struct{}{}
+			}
+
+			for _, id := range replicas {
+				if _, inSync := isr[id]; !inSync {
+					pending[id] += size
+				}
+			}
+		}
+	}
+
+	var ids []int
+	for id := range pending {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		e.bytes += pending[id]
+
+		rate := throttles[id]
+		if rate <= 0 {
+			e.unthrottled = append(e.unthrottled, id)
+			continue
+		}
+
+		eta := time.Duration(pending[id] / (rate * 1000000.00) * float64(time.Second))
+		if eta > e.eta {
+			e.eta, e.broker = eta, id
+		}
+	}
+
+	return e, nil
+}
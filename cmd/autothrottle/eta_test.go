@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+func TestEstimateTransfer(t *testing.T) {
+	zk := &kafkazk.Mock{}
+
+	// Partition sizes are 1000 and 1500 bytes. Broker
+	// 1003 is already in the ISR of partition 1.
+	r := kafkazk.Reassignments{
+		"test_topic": {
+			0: []int{1003, 1004},
+			1: []int{1005, 1003},
+		},
+	}
+
+	// Rates in MB/s.
+	throttles := map[int]float64{1003: 0.001, 1005: 0.0005}
+
+	e, err := estimateTransfer(zk, r, throttles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e.bytes != 3500 || e.eta != 3*time.Second || e.broker != 1005 {
+		t.Errorf("Unexpected estimate %+v", e)
+	}
+
+	if len(e.unthrottled) != 1 || e.unthrottled[0] != 1004 {
+		t.Errorf("Expected unthrottled broker 1004, got %v", e.unthrottled)
+	}
+
+	// Partitions without metadata.
+	r["test_topic"][10] = []int{1003}
+	if _, err := estimateTransfer(zk, r, throttles); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
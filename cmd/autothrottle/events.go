@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/honeycombio/kafka-kit/kafkametrics"
 )
@@ -24,23 +25,34 @@ type EventGenerator struct {
 // and writes a *kafkametrics.Event
 // to the event channel, formatted
 // with the configured title and tags.
+// The event type is the title, lowercased
+// and hyphenated.
 func (e *EventGenerator) Write(t string, m string) {
 	e.c <- &kafkametrics.Event{
 		Title: fmt.Sprintf("[%s] %s", e.titlePrefix, t),
 		Text:  m,
 		Tags:  e.tags,
+		Type:  strings.Replace(strings.ToLower(t), " ", "-", -1),
 	}
 }
 
+// EventPoster posts events to a backend, such
+// as the metrics backend or Honeycomb markers.
+type EventPoster interface {
+	PostEvent(*kafkametrics.Event) error
+}
+
 // eventWriter reads from a channel of
-// kafkazk.Event and writes them to the
-// Datadog API. Errors are logged and
+// kafkazk.Event and writes them to each
+// EventPoster. Errors are logged and
 // do not affect progression.
-func eventWriter(k kafkametrics.Handler, c chan *kafkametrics.Event) {
+func eventWriter(c chan *kafkametrics.Event, posters ...EventPoster) {
 	for e := range c {
-		err := k.PostEvent(e)
-		if err != nil {
-			log.Printf("Error writing event: %s\n", err)
+		for _, p := range posters {
+			err := p.PostEvent(e)
+			if err != nil {
+				log.Printf("Error writing event: %s\n", err)
+			}
 		}
 	}
 }
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+)
+
+type mockPoster struct {
+	events []*kafkametrics.Event
+	err    error
+}
+
+func (m *mockPoster) PostEvent(e *kafkametrics.Event) error {
+	m.events = append(m.events, e)
+	return m.err
+}
+
+func TestEventGeneratorWrite(t *testing.T) {
+	c := make(chan *kafkametrics.Event, 1)
+	g := &EventGenerator{c: c, titlePrefix: "autothrottle", tags: []string{"a:b"}}
+
+	g.Write("Broker replication throttle set", "msg")
+	e := <-c
+
+	if e.Title != "[autothrottle] Broker replication throttle set" {
+		t.Errorf("Unexpected title '%s'", e.Title)
+	}
+
+	if e.Type != "broker-replication-throttle-set" {
+		t.Errorf("Unexpected type '%s'", e.Type)
+	}
+
+	if e.Text != "msg" || len(e.Tags) != 1 {
+		t.Errorf("Unexpected event %+v", e)
+	}
+}
+
+func TestEventWriter(t *testing.T) {
+	c := make(chan *kafkametrics.Event, 2)
+	c <- &kafkametrics.Event{Title: "a"}
+	c <- &kafkametrics.Event{Title: "b"}
+	close(c)
+
+	// A failing poster shouldn't
+	// affect the others.
+	p1 := &mockPoster{err: errors.New("error")}
+	p2 := &mockPoster{}
+
+	eventWriter(c, p1, p2)
+
+	for _, p := range []*mockPoster{p1, p2} {
+		if len(p.events) != 2 {
+			t.Errorf("Expected 2 events, got %d", len(p.events))
+		}
+	}
+}
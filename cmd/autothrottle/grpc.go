@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/honeycombio/kafka-kit/autothrottle/protos"
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc"
+)
+
+var (
+	// ErrRateZero error.
+	ErrRateZero = errors.New("rate must be >0")
+	// ErrBrokerIDEmpty error.
+	ErrBrokerIDEmpty = errors.New("broker id must be specified")
+	// ErrAlreadyPaused error.
+	ErrAlreadyPaused = errors.New("autothrottle is already paused")
+	// ErrNotPaused error.
+	ErrNotPaused = errors.New("autothrottle is not paused")
+	// ErrNoBrokerOverride error.
+	ErrNoBrokerOverride = errors.New("no throttle override is set for the broker")
+)
+
+// populate populates the *pb.StatusResponse reassigning
+// topics, applied throttles and how they were determined.
+func (s *LoopStatus) populate(resp *pb.StatusResponse) {
+	status := s.Get()
+
+	resp.ReassigningTopics = append([]string{}, status.ReassigningTopics...)
+	resp.OverrideRate = uint32(status.OverrideRate)
+	resp.Outcome = status.Outcome
+	resp.Reasons = append([]string{}, status.Reasons...)
+	resp.Updated = status.Updated
+	resp.TransferBytes = status.TransferBytes
+	resp.TransferEtaSeconds = status.TransferETASeconds
+
+	for _, b := range status.Brokers {
+		t := &pb.AppliedThrottle{
+			Broker:           uint32(b.ID),
+			Leader:           b.LeaderRate,
+			Follower:         b.FollowerRate,
+			OverrideRate:     uint32(b.OverrideRate),
+			LeaderReplicas:   b.LeaderReplicas,
+			FollowerReplicas: b.FollowerReplicas,
+		}
+
+		if i := b.Inputs; i != nil {
+			t.Inputs = &pb.BrokerInputs{
+				NetTx:           i.NetTX,
+				NetRx:           i.NetRX,
+				DiskUtil:        i.DiskUtil,
+				Capacity:        i.Capacity,
+				Headroom:        i.Headroom,
+				InboundHeadroom: i.InboundHeadroom,
+			}
+		}
+
+		resp.Throttles = append(resp.Throttles, t)
+	}
+}
+
+// RPCServer implements the autothrottle gRPC admin API. All settings
+// are stored in ZooKeeper and shared with the HTTP admin API.
+type RPCServer struct {
+	zk           kafkazk.Handler
+	overridePath string
+	pausePath    string
+	limits       Limits
+	status       *LoopStatus
+}
+
+// RPCConfig holds RPCServer configuration params.
+type RPCConfig struct {
+	// gRPC listen address.
+	Listen string
+	// gRPC gateway (HTTP/JSON) listen
+	// address; the gateway isn't run
+	// if empty.
+	GatewayListen string
+}
+
+// runRPC takes an *RPCConfig and *RPCServer and backgrounds
+// the gRPC listener and optional HTTP/JSON gateway.
+func runRPC(c *RPCConfig, s *RPCServer) error {
+	l, err := net.Listen("tcp", c.Listen)
+	if err != nil {
+		return err
+	}
+
+	srvr := grpc.NewServer()
+	pb.RegisterAutothrottleServer(srvr, s)
+
+	go func() {
+		if err := srvr.Serve(l); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	if c.GatewayListen == "" {
+		return nil
+	}
+
+	mux := runtime.NewServeMux()
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+
+	if err := pb.RegisterAutothrottleHandlerFromEndpoint(context.Background(), mux, l.Addr().String(), opts); err != nil {
+		return err
+	}
+
+	go func() {
+		err := http.ListenAndServe(c.GatewayListen, mux)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	return nil
+}
+
+// rpcError increments the API error metric
+// for the method and returns err.
+func rpcError(method string, err error) error {
+	metrics.Inc(metricAPIErrorsTotal, "endpoint", method)
+	return err
+}
+
+func logRPC(method string) {
+	log.Printf("[gRPC] %s\n", method)
+}
+
+// GetThrottle returns the global throttle override.
+func (s *RPCServer) GetThrottle(ctx context.Context, req *pb.ThrottleRequest) (*pb.ThrottleResponse, error) {
+	logRPC("GetThrottle")
+
+	c, err := getThrottleOverride(s.zk, s.overridePath)
+	if err != nil {
+		return nil, rpcError("GetThrottle", err)
+	}
+
+	return &pb.ThrottleResponse{Rate: uint32(c.Rate), Autoremove: c.AutoRemove, Expires: c.Expires}, nil
+}
+
+// SetThrottle sets the global throttle override.
+func (s *RPCServer) SetThrottle(ctx context.Context, req *pb.ThrottleRequest) (*pb.ThrottleResponse, error) {
+	logRPC("SetThrottle")
+
+	if req.Rate == 0 {
+		return nil, ErrRateZero
+	}
+
+	c := ThrottleOverrideConfig{Rate: int(req.Rate), AutoRemove: req.Autoremove}
+	if req.TtlSeconds > 0 {
+		c.Expires = time.Now().Add(time.Duration(req.TtlSeconds) * time.Second).Unix()
+	}
+
+	if err := setThrottleOverride(s.zk, s.overridePath, c); err != nil {
+		return nil, rpcError("SetThrottle", err)
+	}
+
+	return &pb.ThrottleResponse{Rate: req.Rate, Autoremove: req.Autoremove, Expires: c.Expires}, nil
+}
+
+// RemoveThrottle removes the global throttle override.
+func (s *RPCServer) RemoveThrottle(ctx context.Context, req *pb.ThrottleRequest) (*pb.ThrottleResponse, error) {
+	logRPC("RemoveThrottle")
+
+	if err := setThrottleOverride(s.zk, s.overridePath, ThrottleOverrideConfig{}); err != nil {
+		return nil, rpcError("RemoveThrottle", err)
+	}
+
+	return &pb.ThrottleResponse{}, nil
+}
+
+// GetBrokerThrottles returns broker throttle overrides.
+func (s *RPCServer) GetBrokerThrottles(ctx context.Context, req *pb.BrokerThrottleRequest) (*pb.BrokerThrottleResponse, error) {
+	logRPC("GetBrokerThrottles")
+
+	overrides, err := getBrokerOverrides(s.zk, s.overridePath)
+	if err != nil {
+		return nil, rpcError("GetBrokerThrottles", err)
+	}
+
+	resp := &pb.BrokerThrottleResponse{}
+	for _, id := range overrides.IDs() {
+		if req.Id != 0 && id != int(req.Id) {
+			continue
+		}
+
+		o := overrides[id]
+		resp.Throttles = append(resp.Throttles, &pb.BrokerThrottle{
+			Id:      uint32(id),
+			Rate:    uint32(o.Rate),
+			Expires: o.Expires,
+		})
+	}
+
+	return resp, nil
+}
+
+// SetBrokerThrottle sets a broker throttle override.
+func (s *RPCServer) SetBrokerThrottle(ctx context.Context, req *pb.BrokerThrottleRequest) (*pb.BrokerThrottleResponse, error) {
+	logRPC("SetBrokerThrottle")
+
+	switch {
+	case req.Id == 0:
+		return nil, ErrBrokerIDEmpty
+	case req.Rate == 0:
+		return nil, ErrRateZero
+	}
+
+	c := BrokerOverrideConfig{Rate: int(req.Rate)}
+	if req.TtlSeconds > 0 {
+		c.Expires = time.Now().Add(time.Duration(req.TtlSeconds) * time.Second).Unix()
+	}
+
+	if err := setBrokerOverride(s.zk, s.overridePath, int(req.Id), c); err != nil {
+		return nil, rpcError("SetBrokerThrottle", err)
+	}
+
+	return &pb.BrokerThrottleResponse{
+		Throttles: []*pb.BrokerThrottle{
+			&pb.BrokerThrottle{Id: req.Id, Rate: req.Rate, Expires: c.Expires},
+		},
+	}, nil
+}
+
+// RemoveBrokerThrottle removes a broker throttle override.
+func (s *RPCServer) RemoveBrokerThrottle(ctx context.Context, req *pb.BrokerThrottleRequest) (*pb.BrokerThrottleResponse, error) {
+	logRPC("RemoveBrokerThrottle")
+
+	if req.Id == 0 {
+		return nil, ErrBrokerIDEmpty
+	}
+
+	removed, err := removeBrokerOverride(s.zk, s.overridePath, int(req.Id))
+	switch {
+	case err != nil:
+		return nil, rpcError("RemoveBrokerThrottle", err)
+	case !removed:
+		return nil, ErrNoBrokerOverride
+	}
+
+	return &pb.BrokerThrottleResponse{}, nil
+}
+
+// Pause pauses the control loop.
+func (s *RPCServer) Pause(ctx context.Context, req *pb.PauseRequest) (*pb.StatusResponse, error) {
+	logRPC("Pause")
+
+	_, paused, err := getPause(s.zk, s.pausePath)
+	switch {
+	case err != nil:
+		return nil, rpcError("Pause", err)
+	case paused:
+		return nil, ErrAlreadyPaused
+	}
+
+	c := PauseConfig{Since: time.Now().Unix(), Reason: req.Reason}
+	if err := setPause(s.zk, s.pausePath, c); err != nil {
+		return nil, rpcError("Pause", err)
+	}
+
+	return s.GetStatus(ctx, &pb.StatusRequest{})
+}
+
+// Resume resumes a paused control loop.
+func (s *RPCServer) Resume(ctx context.Context, req *pb.PauseRequest) (*pb.StatusResponse, error) {
+	logRPC("Resume")
+
+	_, paused, err := getPause(s.zk, s.pausePath)
+	switch {
+	case err != nil:
+		return nil, rpcError("Resume", err)
+	case !paused:
+		return nil, ErrNotPaused
+	}
+
+	if err := removePause(s.zk, s.pausePath); err != nil {
+		return nil, rpcError("Resume", err)
+	}
+
+	return s.GetStatus(ctx, &pb.StatusRequest{})
+}
+
+// GetStatus returns the control loop status.
+func (s *RPCServer) GetStatus(ctx context.Context, req *pb.StatusRequest) (*pb.StatusResponse, error) {
+	logRPC("GetStatus")
+
+	c, paused, err := getPause(s.zk, s.pausePath)
+	if err != nil {
+		return nil, rpcError("GetStatus", err)
+	}
+
+	resp := &pb.StatusResponse{Paused: paused}
+	if paused {
+		resp.PausedSince = c.Since
+		resp.PauseReason = c.Reason
+	}
+
+	s.status.populate(resp)
+
+	return resp, nil
+}
+
+// GetCapacityProfiles returns the configured capacities and rate limits.
+func (s *RPCServer) GetCapacityProfiles(ctx context.Context, req *pb.CapacityRequest) (*pb.CapacityResponse, error) {
+	logRPC("GetCapacityProfiles")
+
+	resp := &pb.CapacityResponse{
+		InstanceTypes: map[string]float64{},
+		Brokers:       map[uint32]float64{},
+	}
+
+	for k, v := range s.limits {
+		switch {
+		case k == "minimum":
+			resp.Minimum = v
+		case k == "maximum":
+			resp.Maximum = v
+		case k == defaultCapacityKey:
+			resp.Default = v
+		case k == maxDiskUtilKey, k == compressionRatioKey:
+		case strings.HasPrefix(k, "broker:"):
+			id, err := strconv.Atoi(strings.TrimPrefix(k, "broker:"))
+			if err == nil {
+				resp.Brokers[uint32(id)] = v
+			}
+		default:
+			resp.InstanceTypes[k] = v
+		}
+	}
+
+	return resp, nil
+}
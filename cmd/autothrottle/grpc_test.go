@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/honeycombio/kafka-kit/autothrottle/protos"
+)
+
+func testRPCServer() *RPCServer {
+	lim, _ := NewLimits(NewLimitsConfig{
+		Minimum:           10,
+		Maximum:           90,
+		MaxDiskUtil:       80,
+		CapacityMap:       map[string]float64{"mock": 125},
+		BrokerCapacityMap: map[int]float64{1001: 100},
+		DefaultCapacity:   50,
+	})
+
+	return &RPCServer{
+		zk:           newMemZK(),
+		overridePath: "/autothrottle/override_rate",
+		pausePath:    "/autothrottle/paused",
+		limits:       lim,
+		status:       &LoopStatus{},
+	}
+}
+
+func TestRPCThrottle(t *testing.T) {
+	s := testRPCServer()
+	ctx := context.Background()
+
+	if _, err := s.SetThrottle(ctx, &pb.ThrottleRequest{}); err != ErrRateZero {
+		t.Errorf("Expected ErrRateZero, got %v", err)
+	}
+
+	if _, err := s.SetThrottle(ctx, &pb.ThrottleRequest{Rate: 100, Autoremove: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.GetThrottle(ctx, &pb.ThrottleRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Rate != 100 || !resp.Autoremove {
+		t.Errorf("Unexpected response %v", resp)
+	}
+
+	if _, err := s.SetThrottle(ctx, &pb.ThrottleRequest{Rate: 100, TtlSeconds: 60}); err != nil {
+		t.Fatal(err)
+	}
+
+	if resp, _ := s.GetThrottle(ctx, &pb.ThrottleRequest{}); resp.Expires == 0 {
+		t.Error("Expected non-zero expiry")
+	}
+
+	s.RemoveThrottle(ctx, &pb.ThrottleRequest{})
+
+	if resp, _ := s.GetThrottle(ctx, &pb.ThrottleRequest{}); resp.Rate != 0 {
+		t.Errorf("Expected rate 0, got %d", resp.Rate)
+	}
+}
+
+func TestRPCBrokerThrottles(t *testing.T) {
+	s := testRPCServer()
+	ctx := context.Background()
+
+	if _, err := s.SetBrokerThrottle(ctx, &pb.BrokerThrottleRequest{Rate: 50}); err != ErrBrokerIDEmpty {
+		t.Errorf("Expected ErrBrokerIDEmpty, got %v", err)
+	}
+
+	s.SetBrokerThrottle(ctx, &pb.BrokerThrottleRequest{Id: 1001, Rate: 50})
+
+	resp, err := s.SetBrokerThrottle(ctx, &pb.BrokerThrottleRequest{Id: 1002, Rate: 20, TtlSeconds: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Throttles[0].Expires == 0 {
+		t.Error("Expected non-zero expiry")
+	}
+
+	resp, _ = s.GetBrokerThrottles(ctx, &pb.BrokerThrottleRequest{})
+	if len(resp.Throttles) != 2 || resp.Throttles[0].Id != 1001 || resp.Throttles[0].Rate != 50 {
+		t.Errorf("Unexpected response %v", resp)
+	}
+
+	resp, _ = s.GetBrokerThrottles(ctx, &pb.BrokerThrottleRequest{Id: 1002})
+	if len(resp.Throttles) != 1 || resp.Throttles[0].Rate != 20 {
+		t.Errorf("Unexpected response %v", resp)
+	}
+
+	if _, err := s.RemoveBrokerThrottle(ctx, &pb.BrokerThrottleRequest{Id: 1001}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.RemoveBrokerThrottle(ctx, &pb.BrokerThrottleRequest{Id: 1001}); err != ErrNoBrokerOverride {
+		t.Errorf("Expected ErrNoBrokerOverride, got %v", err)
+	}
+}
+
+func TestRPCPauseStatus(t *testing.T) {
+	s := testRPCServer()
+	ctx := context.Background()
+
+	s.status.Set(&ReplicationThrottleMeta{
+		topics:            []string{"topic2", "topic1"},
+		throttles:         map[int]float64{1001: 100, 1002: 0},
+		followerThrottles: map[int]float64{1001: 80, 1002: 0},
+	}, intervalIdle)
+
+	resp, err := s.GetStatus(ctx, &pb.StatusRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Paused || len(resp.ReassigningTopics) != 2 || resp.ReassigningTopics[0] != "topic1" {
+		t.Errorf("Unexpected response %v", resp)
+	}
+
+	// Removed throttles are omitted.
+	if len(resp.Throttles) != 1 || resp.Throttles[0].Leader != 100 || resp.Throttles[0].Follower != 80 {
+		t.Errorf("Unexpected throttles %v", resp.Throttles)
+	}
+
+	if _, err := s.Resume(ctx, &pb.PauseRequest{}); err != ErrNotPaused {
+		t.Errorf("Expected ErrNotPaused, got %v", err)
+	}
+
+	resp, err = s.Pause(ctx, &pb.PauseRequest{Reason: "incident"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !resp.Paused || resp.PauseReason != "incident" || resp.PausedSince == 0 {
+		t.Errorf("Unexpected response %v", resp)
+	}
+
+	if _, err := s.Pause(ctx, &pb.PauseRequest{}); err != ErrAlreadyPaused {
+		t.Errorf("Expected ErrAlreadyPaused, got %v", err)
+	}
+
+	if resp, _ := s.Resume(ctx, &pb.PauseRequest{}); resp.Paused {
+		t.Error("Expected resumed status")
+	}
+}
+
+func TestRPCCapacityProfiles(t *testing.T) {
+	s := testRPCServer()
+
+	resp, err := s.GetCapacityProfiles(context.Background(), &pb.CapacityRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Minimum != 10 || resp.Maximum != 90 || resp.Default != 50 {
+		t.Errorf("Unexpected response %v", resp)
+	}
+
+	if len(resp.InstanceTypes) != 1 || resp.InstanceTypes["mock"] != 125 {
+		t.Errorf("Unexpected instance types %v", resp.InstanceTypes)
+	}
+
+	if len(resp.Brokers) != 1 || resp.Brokers[1001] != 100 {
+		t.Errorf("Unexpected brokers %v", resp.Brokers)
+	}
+}
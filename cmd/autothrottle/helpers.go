@@ -45,6 +45,39 @@ func (bm bmapBundle) lists() ([]int, []int, []int) {
 	return srcBrokers, dstBrokers, allBrokers
 }
 
+// excluding takes a BrokerOverrides and returns a copy of the bmapBundle
+// with the overridden brokers removed from the src, dst and all maps. The
+// bmapBundle is returned as is if no src or dst brokers would remain.
+func (bm bmapBundle) excluding(o BrokerOverrides) bmapBundle {
+	out := bmapBundle{
+		src:       map[int]struct{}{},
+		dst:       map[int]struct{}{},
+		throttled: bm.throttled,
+	}
+
+	for n, m := range []map[int]struct{}{bm.src, bm.dst} {
+		for b := range m {
+			if _, overridden := o[b]; overridden {
+				continue
+			}
+
+			if n == 0 {
+				out.src[b] = struct{}{}
+			} else {
+				out.dst[b] = struct{}{}
+			}
+		}
+	}
+
+	if len(out.src) == 0 || len(out.dst) == 0 {
+		return bm
+	}
+
+	out.all = mergeMaps(out.src, out.dst)
+
+	return out
+}
+
 // incompleteBrokerMetrics takes a []int of all broker IDs involved in
 // the current replication event and a kafkametrics.BrokerMetrics. If
 // any brokers in the ID list are not found in the BrokerMetrics, our
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+	"github.com/honeycombio/kafka-kit/kafkametrics/honeycomb"
+)
+
+// Control loop interval states.
+const (
+	intervalReassigning = "reassigning"
+	intervalPaused      = "paused"
+	intervalIdle        = "idle"
+)
+
+// Throttle update outcomes.
+const (
+	outcomeApplied   = "applied"
+	outcomeUnchanged = "within_threshold"
+	outcomeCooldown  = "cooldown"
+	outcomeRetained  = "metrics_failure"
+	outcomeError     = "error"
+)
+
+// intervalState holds the brokers and metrics observed
+// during the most recent throttle update, along with its
+// outcome and how the throttle rates were determined.
+type intervalState struct {
+	brokers bmapBundle
+	metrics kafkametrics.BrokerMetrics
+	outcome string
+	reasons []string
+	// Optional reassignment time estimate.
+	transfer *transferEstimate
+}
+
+// EventSender sends structured events.
+type EventSender interface {
+	Send([]honeycomb.Event) error
+}
+
+// IntervalEventWriter writes structured events describing each control
+// loop interval: an event for each broker participating in replication,
+// with its utilization, headroom and applied throttle rates, or a single
+// event if no replication is being throttled.
+type IntervalEventWriter struct {
+	sender EventSender
+	// Fields included in every event.
+	fields map[string]interface{}
+}
+
+// NewIntervalEventWriter takes an EventSender and a list of
+// key:value event tags, which are included as event fields.
+func NewIntervalEventWriter(s EventSender, tags []string) *IntervalEventWriter {
+	fields := map[string]interface{}{}
+	for _, t := range tags {
+		if kv := strings.SplitN(t, ":", 2); len(kv) == 2 && kv[0] != "" {
+			fields[kv[0]] = kv[1]
+		}
+	}
+
+	return &IntervalEventWriter{sender: s, fields: fields}
+}
+
+// Write sends the interval events for the ReplicationThrottleMeta and
+// control loop state. Per broker events are only written while
+// reassigning.
+func (w *IntervalEventWriter) Write(params *ReplicationThrottleMeta, state string, now time.Time) error {
+	return w.sender.Send(w.events(params, state, now))
+}
+
+func (w *IntervalEventWriter) events(params *ReplicationThrottleMeta, state string, now time.Time) []honeycomb.Event {
+	topics := append([]string{}, params.topics...)
+	sort.Strings(topics)
+
+	common := map[string]interface{}{
+		"state":                          state,
+		"reassigning_topics":             len(topics),
+		"topics":                         strings.Join(topics, ","),
+		"override_rate_bytes_per_second": float64(params.overrideRate) * 1000000.00,
+		"metrics_failures":               params.failures,
+	}
+
+	for k, v := range w.fields {
+		common[k] = v
+	}
+
+	i := params.interval
+	if state != intervalReassigning || len(i.brokers.all) == 0 {
+		return []honeycomb.Event{{Time: now, Data: common}}
+	}
+
+	outcome := i.outcome
+	if outcome == "" {
+		outcome = outcomeError
+	}
+
+	common["outcome"] = outcome
+	if len(i.reasons) > 0 {
+		common["reasons"] = strings.Join(i.reasons, "\n")
+	}
+
+	if e := i.transfer; e != nil {
+		common["transfer_bytes"] = e.bytes
+		common["transfer_eta_seconds"] = e.eta.Seconds()
+	}
+
+	var ids []int
+	for id := range i.brokers.all {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	var events []honeycomb.Event
+
+	for _, id := range ids {
+		data := map[string]interface{}{}
+		for k, v := range common {
+			data[k] = v
+		}
+
+		_, src := i.brokers.src[id]
+		_, dst := i.brokers.dst[id]
+
+		data["broker"] = id
+		data["source"] = src
+		data["destination"] = dst
+		data["leader_rate_bytes_per_second"] = params.throttles[id] * 1000000.00
+		data["follower_rate_bytes_per_second"] = params.followerThrottles[id] * 1000000.00
+
+		if o, exists := params.brokerOverrides[id]; exists {
+			data["broker_override_rate_bytes_per_second"] = float64(o.Rate) * 1000000.00
+		}
+
+		// Metrics aren't fetched if an
+		// override is set or on failures.
+		if b, exists := i.metrics[id]; exists {
+			data["net_tx_bytes_per_second"] = b.NetTX * 1000000.00
+			data["net_rx_bytes_per_second"] = b.NetRX * 1000000.00
+
+			if b.InstanceType != "" {
+				data["instance_type"] = b.InstanceType
+			}
+
+			if params.diskUtil {
+				data["disk_util_percent"] = b.DiskUtil
+			}
+
+			if c, known := params.limits.capacity(b); known {
+				data["capacity_bytes_per_second"] = c * 1000000.00
+
+				if h, err := params.limits.headroom(b, params.throttles[id]); err == nil {
+					data["headroom_bytes_per_second"] = h * 1000000.00
+				}
+
+				if h, err := params.limits.inboundHeadroom(b, params.followerThrottles[id]); params.inbound && err == nil {
+					data["inbound_headroom_bytes_per_second"] = h * 1000000.00
+				}
+			}
+		}
+
+		events = append(events, honeycomb.Event{Time: now, Data: data})
+	}
+
+	return events
+}
+
+// writeIntervalEvents writes the interval events
+// if the *IntervalEventWriter is non-nil.
+func writeIntervalEvents(w *IntervalEventWriter, params *ReplicationThrottleMeta, state string) {
+	if w == nil {
+		return
+	}
+
+	if err := w.Write(params, state, time.Now()); err != nil {
+		log.Printf("Error writing interval events: %s\n", err)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+	"github.com/honeycombio/kafka-kit/kafkametrics/honeycomb"
+)
+
+type mockSender struct {
+	events []honeycomb.Event
+}
+
+func (s *mockSender) Send(e []honeycomb.Event) error {
+	s.events = e
+	return nil
+}
+
+func TestIntervalEvents(t *testing.T) {
+	s := &mockSender{}
+	w := NewIntervalEventWriter(s, []string{"cluster:kafka-a", "", "invalid"})
+
+	l, _ := NewLimits(NewLimitsConfig{
+		Minimum:     10,
+		Maximum:     90,
+		CapacityMap: map[string]float64{"mock": 200},
+	})
+
+	params := &ReplicationThrottleMeta{
+		topics:            []string{"test_topic2", "test_topic"},
+		throttles:         map[int]float64{1001: 80, 1002: 60},
+		followerThrottles: map[int]float64{1001: 80, 1002: 60},
+		brokerOverrides:   BrokerOverrides{1002: {Rate: 60}},
+		limits:            l,
+		interval: intervalState{
+			brokers: bmapBundle{
+				src: map[int]struct{}{1001: {}},
+				dst: map[int]struct{}{1002: {}},
+				all: map[int]struct{}{1001: {}, 1002: {}},
+			},
+			metrics: kafkametrics.BrokerMetrics{
+				1001: {ID: 1001, InstanceType: "mock", NetTX: 120, NetRX: 40},
+			},
+			outcome: outcomeApplied,
+			reasons: []string{"Most constrained source broker: [1001]"},
+		},
+	}
+
+	now := time.Now()
+	if err := w.Write(params, intervalReassigning, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(s.events))
+	}
+
+	e := s.events[0].Data
+	expected := map[string]interface{}{
+		"broker":                         1001,
+		"source":                         true,
+		"destination":                    false,
+		"state":                          intervalReassigning,
+		"outcome":                        outcomeApplied,
+		"topics":                         "test_topic,test_topic2",
+		"reassigning_topics":             2,
+		"cluster":                        "kafka-a",
+		"net_tx_bytes_per_second":        120000000.00,
+		"capacity_bytes_per_second":      200000000.00,
+		"leader_rate_bytes_per_second":   80000000.00,
+		"follower_rate_bytes_per_second": 80000000.00,
+	}
+
+	for k, v := range expected {
+		if e[k] != v {
+			t.Errorf("Expected %s %v, got %v", k, v, e[k])
+		}
+	}
+
+	if _, exists := e["headroom_bytes_per_second"]; !exists {
+		t.Error("Expected headroom field")
+	}
+
+	// Without metrics.
+	e = s.events[1].Data
+	if e["broker"] != 1002 || e["broker_override_rate_bytes_per_second"] != 60000000.00 {
+		t.Errorf("Unexpected event %v", e)
+	}
+
+	if _, exists := e["net_tx_bytes_per_second"]; exists {
+		t.Error("Unexpected net_tx field")
+	}
+
+	// A single event is written if not reassigning.
+	w.Write(params, intervalPaused, now)
+
+	if len(s.events) != 1 || s.events[0].Data["state"] != intervalPaused {
+		t.Errorf("Unexpected events %v", s.events)
+	}
+
+	if _, exists := s.events[0].Data["broker"]; exists {
+		t.Error("Unexpected broker field")
+	}
+}
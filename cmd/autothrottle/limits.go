@@ -2,15 +2,30 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"math"
 
 	"github.com/honeycombio/kafka-kit/kafkametrics"
 )
 
 // Limits is a map of instance-type
-// to network bandwidth limits.
+// to network bandwidth limits. Broker
+// specific capacities and the default
+// capacity are stored under the keys
+// returned by brokerCapacityKey and
+// defaultCapacityKey.
 type Limits map[string]float64
 
+const (
+	defaultCapacityKey  = "default"
+	maxDiskUtilKey      = "max_disk_util"
+	compressionRatioKey = "compression_ratio"
+)
+
+func brokerCapacityKey(id int) string {
+	return fmt.Sprintf("broker:%d", id)
+}
+
 // NewLimitsConfig is used to initialize
 // a Limits.
 type NewLimitsConfig struct {
@@ -18,8 +33,23 @@ type NewLimitsConfig struct {
 	Minimum float64
 	// Max throttle rate as a portion of capacity.
 	Maximum float64
+	// Max disk utilization (percent) targeted when
+	// capping throttles by disk utilization.
+	MaxDiskUtil float64
 	// Map of instance-type to total network capacity in MB/s.
 	CapacityMap map[string]float64
+	// Map of broker ID to total network capacity in MB/s.
+	// Takes precedence over the instance-type capacity.
+	BrokerCapacityMap map[int]float64
+	// Network capacity in MB/s for brokers of an instance
+	// type not in the CapacityMap. Unknown instance types
+	// aren't throttled by metrics if 0.
+	DefaultCapacity float64
+	// Estimated ratio of on-disk to network bytes of
+	// replicated data for brokers that don't report a
+	// ratio. Replicated data isn't assumed to differ
+	// in size on the wire if 0.
+	CompressionRatio float64
 }
 
 // NewLimits takes a minimum float64 and a map
@@ -30,6 +60,10 @@ func NewLimits(c NewLimitsConfig) (Limits, error) {
 		return nil, errors.New("minimum must be > 0")
 	case c.Maximum <= 0 || c.Maximum > 100:
 		return nil, errors.New("maximum must be > 0 and < 100")
+	case c.MaxDiskUtil < 0 || c.MaxDiskUtil > 100:
+		return nil, errors.New("max disk utilization must be >= 0 and <= 100")
+	case c.CompressionRatio < 0:
+		return nil, errors.New("compression ratio must be >= 0")
 	}
 
 	lim := Limits{
@@ -38,15 +72,53 @@ func NewLimits(c NewLimitsConfig) (Limits, error) {
 		"maximum": c.Maximum,
 	}
 
+	if c.MaxDiskUtil > 0 {
+		lim[maxDiskUtilKey] = c.MaxDiskUtil
+	}
+
 	// Update with provided
 	// capacity map.
 	for k, v := range c.CapacityMap {
 		lim[k] = v
 	}
 
+	for id, v := range c.BrokerCapacityMap {
+		lim[brokerCapacityKey(id)] = v
+	}
+
+	if c.DefaultCapacity > 0 {
+		lim[defaultCapacityKey] = c.DefaultCapacity
+	}
+
+	if c.CompressionRatio > 0 {
+		lim[compressionRatioKey] = c.CompressionRatio
+	}
+
 	return lim, nil
 }
 
+// capacity returns the network capacity for a *kafkametrics.Broker
+// by broker ID, the capacity reported by the metrics backend, instance
+// type, or the default capacity, in order of precedence. False is
+// returned if the capacity isn't known.
+func (l Limits) capacity(b *kafkametrics.Broker) (float64, bool) {
+	if c, exists := l[brokerCapacityKey(b.ID)]; exists {
+		return c, true
+	}
+
+	if b.NetCapacity > 0 {
+		return b.NetCapacity, true
+	}
+
+	if c, exists := l[b.InstanceType]; exists {
+		return c, true
+	}
+
+	c, exists := l[defaultCapacityKey]
+
+	return c, exists
+}
+
 // headroom takes a *kafkametrics.Broker and last set
 // throttle rate and returns the headroom based on utilization
 // vs capacity. Headroom is determined by subtracting the current
@@ -63,15 +135,69 @@ func (l Limits) headroom(b *kafkametrics.Broker, t float64) (float64, error) {
 		return l["minimum"], errors.New("Nil broker provided")
 	}
 
-	if capacity, exists := l[b.InstanceType]; exists {
-		nonThrottleUtil := math.Max(b.NetTX-t, 0.00)
+	return l.headroomFor(b, b.NetTX, t)
+}
+
+// inboundHeadroom is the inbound counterpart to headroom. It takes
+// a *kafkametrics.Broker and last set follower throttle rate and
+// returns the headroom based on inbound network utilization vs
+// capacity.
+func (l Limits) inboundHeadroom(b *kafkametrics.Broker, t float64) (float64, error) {
+	if b == nil {
+		return l["minimum"], errors.New("Nil broker provided")
+	}
+
+	return l.headroomFor(b, b.NetRX, t)
+}
+
+// compressionRatio returns the ratio of on-disk to network bytes of
+// replicated data for a *kafkametrics.Broker, as reported by the metrics
+// backend or configured, in order of precedence. Defaults to 1.
+func (l Limits) compressionRatio(b *kafkametrics.Broker) float64 {
+	if b.CompressionRatio > 0 {
+		return b.CompressionRatio
+	}
+
+	if r, exists := l[compressionRatioKey]; exists {
+		return r
+	}
+
+	return 1
+}
+
+// headroomFor takes a *kafkametrics.Broker, network utilization and
+// last set throttle rate and returns the replication headroom. Throttles
+// limit replicated data as stored on disk, which is converted to and
+// from network bytes by the broker compression ratio.
+func (l Limits) headroomFor(b *kafkametrics.Broker, util, t float64) (float64, error) {
+	if capacity, exists := l.capacity(b); exists {
+		ratio := l.compressionRatio(b)
+		nonThrottleUtil := math.Max(util-t/ratio, 0.00)
 		// Determine if/how far over the target capacity
 		// we are. This is also subtracted from the available
 		// headroom.
-		overCap := math.Max(b.NetTX-capacity, 0.00)
+		overCap := math.Max(util-capacity, 0.00)
 
-		return math.Max((capacity-nonThrottleUtil-overCap)*(l["maximum"]/100), l["minimum"]), nil
+		return math.Max((capacity-nonThrottleUtil-overCap)*(l["maximum"]/100)*ratio, l["minimum"]), nil
 	}
 
 	return l["minimum"], errors.New("Unknown instance type")
 }
+
+// diskHeadroom takes a *kafkametrics.Broker and last set throttle
+// rate and returns the replication rate that's expected to bring the
+// broker disk utilization to the configured max disk utilization.
+// The non-replication write load is unknown, so utilization is
+// assumed to scale linearly with the throttle rate. This is crude,
+// but errs towards lowering throttles on disk-bound brokers where
+// network headroom remains. The rate can't be determined (and no
+// rate and false are returned) if no throttle was previously set or
+// disk utilization isn't reported.
+func (l Limits) diskHeadroom(b *kafkametrics.Broker, t float64) (float64, bool) {
+	max, configured := l[maxDiskUtilKey]
+	if !configured || b == nil || b.DiskUtil <= 0 || t <= 0 {
+		return 0.00, false
+	}
+
+	return math.Max(t*(max/b.DiskUtil), l["minimum"]), true
+}
@@ -61,3 +61,129 @@ func TestHeadroom(t *testing.T) {
 		}
 	}
 }
+
+func TestCompressionRatioHeadroom(t *testing.T) {
+	c := NewLimitsConfig{
+		Minimum: 10,
+		Maximum: 80,
+		CapacityMap: map[string]float64{
+			"mock": 100,
+		},
+		CompressionRatio: 2,
+	}
+
+	l, _ := NewLimits(c)
+	b := &kafkametrics.Broker{
+		InstanceType: "mock",
+		NetTX:        80,
+	}
+
+	// A 70MB/s throttle is 35MB/s on the wire, and the
+	// 44MB/s of network headroom allows a 88MB/s throttle.
+	if h, _ := l.headroom(b, 70); h != 88 {
+		t.Errorf("Expected headroom value of 88, got %f", h)
+	}
+
+	// Broker reported ratios take precedence.
+	b.CompressionRatio = 0.5
+	if h, _ := l.headroom(b, 20); h != 24 {
+		t.Errorf("Expected headroom value of 24, got %f", h)
+	}
+
+	c.CompressionRatio = -1
+	if _, err := NewLimits(c); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
+
+func TestInboundHeadroom(t *testing.T) {
+	c := NewLimitsConfig{
+		Minimum: 10,
+		Maximum: 80,
+		CapacityMap: map[string]float64{
+			"mock": 100,
+		},
+	}
+
+	l, _ := NewLimits(c)
+	b := &kafkametrics.Broker{
+		InstanceType: "mock",
+		// Outbound utilization is irrelevant.
+		NetTX: 200,
+	}
+
+	// [current utilization, current throttle, expected headroom]
+	expected := [][3]float64{
+		[3]float64{70, 0, 24},
+		[3]float64{80, 70, 72},
+		[3]float64{200, 70, 10},
+	}
+
+	for n, params := range expected {
+		b.NetRX = params[0]
+		h, _ := l.inboundHeadroom(b, params[1])
+		if h != params[2] {
+			t.Errorf("[test index %d] Expected headroom value of %f, got %f\n", n, params[2], h)
+		}
+	}
+
+	b.InstanceType = "unknown"
+	if _, err := l.inboundHeadroom(b, 0); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
+
+func TestCapacity(t *testing.T) {
+	c := NewLimitsConfig{
+		Minimum: 10,
+		Maximum: 80,
+		CapacityMap: map[string]float64{
+			"mock": 100,
+		},
+		BrokerCapacityMap: map[int]float64{
+			1001: 200,
+		},
+	}
+
+	l, _ := NewLimits(c)
+
+	tests := []struct {
+		id       int
+		it       string
+		expected float64
+		known    bool
+	}{
+		{1000, "mock", 100, true},
+		{1001, "mock", 200, true},
+		{1001, "unknown", 200, true},
+		{1002, "unknown", 0, false},
+	}
+
+	for n, test := range tests {
+		cap, known := l.capacity(&kafkametrics.Broker{ID: test.id, InstanceType: test.it})
+		if cap != test.expected || known != test.known {
+			t.Errorf("[test index %d] Expected capacity %f/%v, got %f/%v\n", n, test.expected, test.known, cap, known)
+		}
+	}
+
+	// With a default capacity.
+	c.DefaultCapacity = 50
+	l, _ = NewLimits(c)
+
+	if cap, known := l.capacity(&kafkametrics.Broker{ID: 1002, InstanceType: "unknown"}); cap != 50 || !known {
+		t.Errorf("Expected default capacity 50, got %f", cap)
+	}
+
+	if cap, _ := l.capacity(&kafkametrics.Broker{ID: 1000, InstanceType: "mock"}); cap != 100 {
+		t.Errorf("Expected capacity 100, got %f", cap)
+	}
+
+	// With a backend reported capacity.
+	if cap, _ := l.capacity(&kafkametrics.Broker{ID: 1000, InstanceType: "mock", NetCapacity: 150}); cap != 150 {
+		t.Errorf("Expected capacity 150, got %f", cap)
+	}
+
+	if cap, _ := l.capacity(&kafkametrics.Broker{ID: 1001, NetCapacity: 150}); cap != 200 {
+		t.Errorf("Expected capacity 200, got %f", cap)
+	}
+}
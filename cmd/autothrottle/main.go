@@ -1,17 +1,26 @@
 package main
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
 	"github.com/honeycombio/kafka-kit/kafkametrics"
+	_ "github.com/honeycombio/kafka-kit/kafkametrics/cruisecontrol"
 	"github.com/honeycombio/kafka-kit/kafkametrics/datadog"
+	"github.com/honeycombio/kafka-kit/kafkametrics/honeycomb"
+	"github.com/honeycombio/kafka-kit/kafkametrics/webhook"
 	"github.com/honeycombio/kafka-kit/kafkazk"
 
 	"github.com/jamiealquiza/envy"
@@ -21,52 +30,173 @@ var (
 	// Config holds configuration
 	// parameters.
 	Config struct {
-		APIKey           string
-		AppKey           string
-		NetworkTXQuery   string
-		BrokerIDTag      string
-		MetricsWindow    int
-		ZKAddr           string
-		ZKPrefix         string
-		Interval         int
-		APIListen        string
-		ConfigZKPrefix   string
-		DDEventTags      string
-		MinRate          float64
-		MaxRate          float64
-		ChangeThreshold  float64
-		FailureThreshold int
-		CapMap           map[string]float64
-		CleanupAfter     int64
+		MetricsBackend     string
+		MetricsParams      map[string]string
+		APIKey             string
+		AppKey             string
+		NetworkTXQuery     string
+		NetworkRXQuery     string
+		DiskUtilQuery      string
+		MaxDiskUtil        float64
+		CompressionRatio   float64
+		CompressionQuery   string
+		EstimateTransfer   bool
+		ZKMetricsPrefix    string
+		BrokerIDTag        string
+		MetricsWindow      int
+		ZKAddr             string
+		ZKTLS              bool
+		ZKTLSCACert        string
+		ZKTLSCert          string
+		ZKTLSKey           string
+		ZKTLSServerName    string
+		KafkaBootstrap     string
+		KafkaTLS           bool
+		KafkaTLSCACert     string
+		KafkaTLSCert       string
+		KafkaTLSKey        string
+		KafkaSASLMechanism string
+		KafkaSASLUsername  string
+		KafkaSASLPassword  string
+		AdminReassign      bool
+		AdminMetadata      bool
+		DryRun             bool
+		AuditLog           string
+		AuditLogSize       int
+		ClustersConfig     string
+		LeaderElection     bool
+		ZKPrefix           string
+		Interval           int
+		APIListen          string
+		GRPCListen         string
+		GRPCGWListen       string
+		ConfigZKPrefix     string
+		DDEventTags        string
+		MinRate            float64
+		MaxRate            float64
+		ChangeThreshold    float64
+		ChangeCooldown     int
+		MetricsSmoothing   int
+		SmoothingAlpha     float64
+		FailureThreshold   int
+		CapMap             map[string]float64
+		CapConfig          string
+		CleanupAfter       int64
+		HCAPIKey           string
+		HCDataset          string
+		HCEventsDataset    string
+		HCAPIHost          string
+		WebhookURL         string
+		VerifyISR          bool
+		NewBrokerWindow    int
+		IndependentRates   bool
+		TopicPriorities    string
+		RackLimits         string
+		QuotaConfig        string
+		RemovalSettle      int
+		WebhookFormat      string
+		Controller         string
+		PIDSetpoint        float64
+		PIDKp              float64
+		PIDKi              float64
+		PIDKd              float64
+		ShutdownAction     string
+		ShutdownRate       float64
 	}
 
 	// Misc.
 	topicsRegex = []*regexp.Regexp{regexp.MustCompile(".*")}
+	// Whether the simulate
+	// subcommand was given.
+	simulate bool
 )
 
 func init() {
 	// log.SetOutput(ioutil.Discard)
 
+	flag.StringVar(&Config.MetricsBackend, "metrics-backend", datadog.Backend, "Metrics backend for broker network metrics and events")
+	mp := flag.String("metrics-params", "", "JSON map of metrics backend specific parameters")
 	flag.StringVar(&Config.APIKey, "api-key", "", "Datadog API key")
 	flag.StringVar(&Config.AppKey, "app-key", "", "Datadog app key")
-	flag.StringVar(&Config.NetworkTXQuery, "net-tx-query", "avg:system.net.bytes_sent{service:kafka} by {host}", "Datadog query for broker outbound bandwidth by host")
-	flag.StringVar(&Config.BrokerIDTag, "broker-id-tag", "broker_id", "Datadog host tag for broker ID")
+	flag.StringVar(&Config.NetworkTXQuery, "net-tx-query", "avg:system.net.bytes_sent{service:kafka} by {host}", "Metrics query for broker outbound bandwidth by host")
+	flag.StringVar(&Config.NetworkRXQuery, "net-rx-query", "avg:system.net.bytes_rcvd{service:kafka} by {host}", "Metrics query for broker inbound bandwidth by host; if empty, follower throttles are set to the leader throttle rate")
+	flag.StringVar(&Config.DiskUtilQuery, "disk-util-query", "", "Metrics query for broker disk utilization (percent) by host, e.g. max:system.io.util{service:kafka} by {host}; if set, throttles are capped by destination disk utilization")
+	flag.Float64Var(&Config.MaxDiskUtil, "max-disk-util", 80, "Maximum destination broker disk utilization targeted when capping throttles (percent; requires -disk-util-query)")
+	flag.Float64Var(&Config.CompressionRatio, "compression-ratio", 1, "Estimated ratio of on-disk to network bytes of replicated data, used to convert network headroom to throttle rates")
+	flag.StringVar(&Config.CompressionQuery, "compression-ratio-query", "", "Metrics query for the ratio of on-disk to network bytes of replicated data by host; takes precedence over -compression-ratio for brokers with results")
+	flag.BoolVar(&Config.EstimateTransfer, "estimate-transfer", false, "Estimate the time to complete reassignments from partition sizes stored by metricsfetcher under -zk-metrics-prefix")
+	flag.StringVar(&Config.ZKMetricsPrefix, "zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics (with -estimate-transfer)")
+	flag.StringVar(&Config.BrokerIDTag, "broker-id-tag", "broker_id", "Metrics host tag for broker ID")
 	flag.IntVar(&Config.MetricsWindow, "metrics-window", 120, "Time span of metrics required (seconds)")
 	flag.StringVar(&Config.ZKAddr, "zk-addr", "localhost:2181", "ZooKeeper connect string (for broker metadata or rebuild-topic lookups)")
 	flag.StringVar(&Config.ZKPrefix, "zk-prefix", "", "ZooKeeper namespace prefix")
+	flag.BoolVar(&Config.ZKTLS, "zk-tls", false, "Connect to ZooKeeper with TLS (implied by -zk-tls-ca-cert and -zk-tls-cert)")
+	flag.StringVar(&Config.ZKTLSCACert, "zk-tls-ca-cert", "", "Path to a PEM CA certificate for verifying ZooKeeper servers; the system roots are used if empty")
+	flag.StringVar(&Config.ZKTLSCert, "zk-tls-cert", "", "Path to a PEM client certificate for ZooKeeper mTLS (requires -zk-tls-key)")
+	flag.StringVar(&Config.ZKTLSKey, "zk-tls-key", "", "Path to a PEM client key for ZooKeeper mTLS")
+	flag.StringVar(&Config.ZKTLSServerName, "zk-tls-server-name", "", "Server name verified against ZooKeeper server certificates (implies -zk-tls); the -zk-addr host is used if empty")
+	flag.StringVar(&Config.KafkaBootstrap, "kafka-bootstrap-servers", "", "Comma-delimited list of Kafka bootstrap servers; if set, throttles are applied via the Kafka Admin API rather than ZooKeeper (requires Kafka 2.3+)")
+	flag.BoolVar(&Config.KafkaTLS, "kafka-tls", false, "Connect to Kafka with TLS (implied by -kafka-tls-ca-cert and -kafka-tls-cert)")
+	flag.StringVar(&Config.KafkaTLSCACert, "kafka-tls-ca-cert", "", "Path to a PEM CA certificate for verifying Kafka brokers; the system roots are used if empty")
+	flag.StringVar(&Config.KafkaTLSCert, "kafka-tls-cert", "", "Path to a PEM client certificate for Kafka mTLS (requires -kafka-tls-key)")
+	flag.StringVar(&Config.KafkaTLSKey, "kafka-tls-key", "", "Path to a PEM client key for Kafka mTLS")
+	flag.StringVar(&Config.KafkaSASLMechanism, "kafka-sasl-mechanism", "", "Kafka SASL mechanism (PLAIN, SCRAM-SHA-256, SCRAM-SHA-512); SASL authentication is disabled if empty")
+	flag.StringVar(&Config.KafkaSASLUsername, "kafka-sasl-username", "", "Kafka SASL username")
+	flag.StringVar(&Config.KafkaSASLPassword, "kafka-sasl-password", "", "Kafka SASL password (preferably set via AUTOTHROTTLE_KAFKA_SASL_PASSWORD)")
+	flag.BoolVar(&Config.AdminReassign, "admin-api-reassignments", false, "Detect reassignments via the Kafka Admin API rather than the ZooKeeper reassign_partitions znode (requires -kafka-bootstrap-servers and Kafka 2.4+)")
+	flag.BoolVar(&Config.AdminMetadata, "admin-api-metadata", false, "Read topic, partition and broker metadata via the Kafka Admin API rather than ZooKeeper, e.g. for KRaft clusters (requires -kafka-bootstrap-servers and Kafka 2.4+; implies -admin-api-reassignments); ZooKeeper is still required for autothrottle state, leader election and quotas")
+	flag.BoolVar(&Config.DryRun, "dry-run", false, "Run the control loop and log the throttles and quotas that would be set without applying any configs")
+	flag.StringVar(&Config.AuditLog, "audit-log", "", "Throttle change audit log: zk (stored under -zk-config-prefix) or a file path; disabled if empty")
+	flag.IntVar(&Config.AuditLogSize, "audit-log-size", 1000, "Number of audit records retained in ZooKeeper (with -audit-log zk)")
+	flag.StringVar(&Config.ClustersConfig, "clusters-config", "", "Path to a JSON map of cluster names to autothrottle flags; if set, an autothrottle process is run for each cluster")
+	flag.BoolVar(&Config.LeaderElection, "leader-election", false, "Elect a leader among autothrottle instances sharing -zk-config-prefix; only the leader manages throttles and quotas, while standbys take over if the leader fails")
 	flag.IntVar(&Config.Interval, "interval", 180, "Autothrottle check interval (seconds)")
 	flag.StringVar(&Config.APIListen, "api-listen", "localhost:8080", "Admin API listen address:port")
+	flag.StringVar(&Config.GRPCListen, "grpc-listen", "", "gRPC admin API listen address:port; disabled if empty")
+	flag.StringVar(&Config.GRPCGWListen, "grpc-gateway-listen", "", "gRPC admin API HTTP/JSON gateway listen address:port; disabled if empty")
 	flag.StringVar(&Config.ConfigZKPrefix, "zk-config-prefix", "autothrottle", "ZooKeeper prefix to store autothrottle configuration")
 	flag.StringVar(&Config.DDEventTags, "dd-event-tags", "", "Comma-delimited list of Datadog event tags")
 	flag.Float64Var(&Config.MinRate, "min-rate", 10, "Minimum replication throttle rate (MB/s)")
 	flag.Float64Var(&Config.MaxRate, "max-rate", 90, "Maximum replication throttle rate (as a percentage of available capacity)")
 	flag.Float64Var(&Config.ChangeThreshold, "change-threshold", 10, "Required change in replication throttle to trigger an update (percent)")
+	flag.IntVar(&Config.ChangeCooldown, "change-cooldown", 0, "Minimum time after throttles are updated before they can be raised (seconds); throttle decreases are always applied")
+	flag.IntVar(&Config.MetricsSmoothing, "metrics-smoothing", 1, "Number of interval metrics samples that broker utilization is averaged over when determining throttles")
+	flag.Float64Var(&Config.SmoothingAlpha, "metrics-smoothing-alpha", 0, "Weight (> 0 and <= 1) of each interval metrics sample in an exponentially weighted moving average of broker utilization, used to determine throttles; an alternative to -metrics-smoothing, disabled if 0")
 	flag.IntVar(&Config.FailureThreshold, "failure-threshold", 1, "Number of iterations that throttle determinations can fail before reverting to the min-rate")
 	m := flag.String("cap-map", "", "JSON map of instance types to network capacity in MB/s")
+	flag.StringVar(&Config.CapConfig, "cap-config", "", "Path to a JSON file of network capacities in Mb/s by instance type and broker ID, with an optional default; takes precedence over -cap-map")
+	flag.StringVar(&Config.Controller, "controller", "headroom", "Throttle rate controller: headroom (a portion of the available headroom each interval) or pid (adjusts throttles towards -pid-setpoint)")
+	flag.Float64Var(&Config.PIDSetpoint, "pid-setpoint", 80, "Target network utilization of the most constrained broker for the pid controller (percentage of capacity)")
+	flag.Float64Var(&Config.PIDKp, "pid-kp", 0.2, "Proportional gain for the pid controller")
+	flag.Float64Var(&Config.PIDKi, "pid-ki", 0.5, "Integral gain for the pid controller")
+	flag.Float64Var(&Config.PIDKd, "pid-kd", 0, "Derivative gain for the pid controller")
+	flag.StringVar(&Config.HCAPIKey, "honeycomb-api-key", "", "Honeycomb API key; if set, events are also written as Honeycomb markers")
+	flag.StringVar(&Config.HCDataset, "honeycomb-dataset", "", "Honeycomb dataset to write markers to")
+	flag.StringVar(&Config.HCEventsDataset, "honeycomb-events-dataset", "", "Honeycomb dataset to write structured events for each interval to, detailing broker utilization, headroom and applied throttles (requires -honeycomb-api-key)")
+	flag.StringVar(&Config.HCAPIHost, "honeycomb-api-host", honeycomb.DefaultAPIHost, "Honeycomb API host")
+	flag.StringVar(&Config.WebhookURL, "webhook-url", "", "Webhook URL; if set, events are also posted to the webhook")
+	flag.StringVar(&Config.WebhookFormat, "webhook-format", webhook.FormatJSON, "Webhook payload format (json, slack)")
+	flag.BoolVar(&Config.IndependentRates, "independent-rates", false, "Determine the leader and follower throttles of each broker from its own outbound and inbound headroom")
+	flag.StringVar(&Config.TopicPriorities, "topic-priorities", "", "JSON map of topic regex to the percentage of the throttle rate given to brokers only replicating matching topics, e.g. {\"archive_.*\": 25}")
+	flag.StringVar(&Config.RackLimits, "rack-limits", "", "JSON map of broker rack to the ceiling (MB/s) of the aggregate leader and follower throttles of its brokers, e.g. {\"us-east-1a\": 500}")
+	flag.BoolVar(&Config.VerifyISR, "verify-isr", true, "Retain throttles after reassignments complete until all reassigned partitions are in-sync")
+	flag.IntVar(&Config.NewBrokerWindow, "new-broker-window", 0, "Throttle replication to brokers catching up that registered within this many seconds (e.g. new or replacement brokers), as if reassigned; disabled if 0")
+	flag.IntVar(&Config.RemovalSettle, "removal-settle", 0, "Seconds to wait after reassigned partitions are in-sync before removing throttles")
+	flag.StringVar(&Config.ShutdownAction, "shutdown-action", shutdownFreeze, "Action taken on the throttles set by autothrottle on an interrupt or SIGTERM: freeze (leave throttles in place), clear (remove all throttles) or fallback (set throttles to -shutdown-fallback-rate)")
+	flag.Float64Var(&Config.ShutdownRate, "shutdown-fallback-rate", 0, "Throttle rate (MB/s) set on throttled brokers on shutdown with -shutdown-action fallback")
+	flag.StringVar(&Config.QuotaConfig, "quota-config", "", "Path to a JSON client quota config; if set, quotas of the configured clients are managed by broker utilization")
 	flag.Int64Var(&Config.CleanupAfter, "cleanup-after", 60, "Number of intervals after which to issue a global throttle unset if no replication is running")
 
 	envy.Parse("AUTOTHROTTLE")
-	flag.Parse()
+
+	// The simulate subcommand takes the
+	// same flags, followed by a timeline.
+	if len(os.Args) > 1 && os.Args[1] == simulateCommand {
+		simulate = true
+		flag.CommandLine.Parse(os.Args[2:])
+	} else {
+		flag.Parse()
+	}
 
 	// Deserialize instance-type capacity map.
 	Config.CapMap = map[string]float64{}
@@ -77,24 +207,95 @@ func init() {
 			os.Exit(1)
 		}
 	}
+
+	// Deserialize metrics backend params. The Datadog
+	// key flags are kept as shorthand for the Datadog
+	// backend params.
+	Config.MetricsParams = map[string]string{}
+	if len(*mp) > 0 {
+		err := json.Unmarshal([]byte(*mp), &Config.MetricsParams)
+		if err != nil {
+			fmt.Printf("Error parsing metrics-params flag: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if Config.APIKey != "" {
+		Config.MetricsParams["api_key"] = Config.APIKey
+	}
+
+	if Config.AppKey != "" {
+		Config.MetricsParams["app_key"] = Config.AppKey
+	}
 }
 
 func main() {
+	if simulate {
+		if flag.NArg() != 1 {
+			fmt.Println("Usage: autothrottle simulate [flags] <timeline>")
+			os.Exit(1)
+		}
+
+		if err := runSimulation(flag.Arg(0), os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	// Run a control loop for each cluster
+	// if a clusters config is set.
+	if Config.ClustersConfig != "" {
+		cc, err := loadClustersConfig(Config.ClustersConfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("Autothrottle running for clusters: %s\n", strings.Join(cc.names(), ", "))
+		runClusters(cc, os.Args[1:])
+	}
+
 	log.Println("Autothrottle Running")
 	// Lazily prevent a tight restart
 	// loop from thrashing ZK.
 	time.Sleep(1 * time.Second)
 
 	// Init ZK.
-	zk, err := kafkazk.NewHandler(&kafkazk.Config{
-		Connect: Config.ZKAddr,
-		Prefix:  Config.ZKPrefix,
-	})
+	zkConfig := &kafkazk.Config{
+		Connect:       Config.ZKAddr,
+		Prefix:        Config.ZKPrefix,
+		MetricsPrefix: Config.ZKMetricsPrefix,
+		TLSCACert:     Config.ZKTLSCACert,
+		TLSCert:       Config.ZKTLSCert,
+		TLSKey:        Config.ZKTLSKey,
+		TLSServerName: Config.ZKTLSServerName,
+	}
+
+	if Config.ZKTLS {
+		zkConfig.TLS = &tls.Config{}
+	}
+
+	zk, err := kafkazk.NewHandler(zkConfig)
 
 	// Init the admin API.
 	apiConfig := &APIConfig{
 		Listen:   Config.APIListen,
 		ZKPrefix: Config.ConfigZKPrefix,
+		Status:   loopStatus,
+	}
+
+	// Init the optional audit log.
+	switch Config.AuditLog {
+	case "":
+	case "zk":
+		if Config.AuditLogSize < 1 {
+			log.Fatal("audit-log-size must be > 0")
+		}
+
+		p := fmt.Sprintf("/%s/%s", Config.ConfigZKPrefix, auditZNode)
+		apiConfig.Audit = NewZKAuditLog(zk, p, Config.AuditLogSize)
+	default:
+		apiConfig.Audit = NewFileAuditLog(Config.AuditLog)
 	}
 
 	initAPI(apiConfig, zk)
@@ -104,18 +305,84 @@ func main() {
 	}
 	defer zk.Close()
 
+	// Throttle configs are written to ZooKeeper
+	// unless a Kafka Admin API client is configured.
+	// Cluster metadata is read from ZooKeeper unless
+	// admin-api-metadata is set; autothrottle state
+	// is always stored in ZooKeeper.
+	var cluster kafkazk.Handler = zk
+	var configs ConfigUpdater = zk
+	var reassignmentLister ReassignmentLister = zkReassignments{zk}
+	kafkaTLS, err := kafkaTLSConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if Config.KafkaBootstrap != "" {
+		kc := kafkaadmin.Config{
+			BootstrapServers: Config.KafkaBootstrap,
+			ClientID:         "autothrottle",
+			TLS:              kafkaTLS,
+			SASL:             kafkaSASLConfig(),
+		}
+
+		var ka *kafkaadmin.Client
+		if Config.AdminMetadata {
+			if Config.EstimateTransfer {
+				log.Fatal("estimate-transfer isn't supported with admin-api-metadata")
+			}
+
+			h, err := kafkaadmin.NewHandler(kc)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer h.Close()
+
+			ka = h.Client
+			cluster = h
+			log.Println("Reading cluster metadata via the Kafka Admin API")
+		} else if ka, err = kafkaadmin.NewClient(kc); err != nil {
+			log.Fatal(err)
+		}
+
+		configs = ka
+		log.Printf("Applying throttles via the Kafka Admin API: %s\n", Config.KafkaBootstrap)
+
+		if Config.AdminReassign || Config.AdminMetadata {
+			reassignmentLister = ka
+			log.Println("Detecting reassignments via the Kafka Admin API")
+		}
+	} else if Config.AdminReassign || Config.AdminMetadata {
+		log.Fatal("admin-api-reassignments and admin-api-metadata require kafka-bootstrap-servers")
+	} else if kafkaTLS != nil || Config.KafkaSASLMechanism != "" {
+		log.Fatal("kafka-tls and kafka-sasl flags require kafka-bootstrap-servers")
+	}
+
+	// Config changes are only logged in dry run mode.
+	var quotaConfigs ConfigUpdater = zk
+	if Config.DryRun {
+		configs = newDryRunUpdater()
+		quotaConfigs = newDryRunUpdater()
+		log.Println("Dry run mode: configs won't be applied")
+	}
+
 	// Init a Kafka metrics fetcher.
-	km, err := datadog.NewHandler(&datadog.Config{
-		APIKey:         Config.APIKey,
-		AppKey:         Config.AppKey,
+	km, err := kafkametrics.NewHandler(Config.MetricsBackend, &kafkametrics.Config{
 		NetworkTXQuery: Config.NetworkTXQuery,
+		NetworkRXQuery: Config.NetworkRXQuery,
+		DiskUtilQuery:  Config.DiskUtilQuery,
 		BrokerIDTag:    Config.BrokerIDTag,
 		MetricsWindow:  Config.MetricsWindow,
+		Params:         Config.MetricsParams,
+
+		CompressionRatioQuery: Config.CompressionQuery,
 	})
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	log.Printf("Metrics backend: %s\n", Config.MetricsBackend)
+
 	// Get optional Datadog event tags.
 	t := strings.Split(Config.DDEventTags, ",")
 	tags := []string{"name:kafka-autothrottle"}
@@ -123,14 +390,78 @@ func main() {
 		tags = append(tags, tag)
 	}
 
-	// Init the Datadog event writer.
+	// Init the event writer.
 	echan := make(chan *kafkametrics.Event, 100)
-	go eventWriter(km, echan)
+	posters := []EventPoster{km}
+	titlePrefix := eventTitlePrefix
+	if Config.DryRun {
+		posters = append(posters, eventLogger{})
+		titlePrefix += " dry run"
+	}
+
+	// Init the optional Honeycomb marker writer. Markers
+	// aren't written if only an events dataset is set.
+	if Config.HCAPIKey != "" && (Config.HCDataset != "" || Config.HCEventsDataset == "") {
+		mw, err := honeycomb.NewMarkerWriter(&honeycomb.Config{
+			APIKey:  Config.HCAPIKey,
+			Dataset: Config.HCDataset,
+			APIHost: Config.HCAPIHost,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		posters = append(posters, mw)
+		log.Printf("Writing Honeycomb markers to dataset %s\n", Config.HCDataset)
+	}
+
+	// Init the optional Honeycomb interval event writer.
+	// Event tags are included as event fields.
+	var intervals *IntervalEventWriter
+	if Config.HCEventsDataset != "" {
+		es, err := honeycomb.NewEventSender(&honeycomb.Config{
+			APIKey:  Config.HCAPIKey,
+			Dataset: Config.HCEventsDataset,
+			APIHost: Config.HCAPIHost,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		intervals = NewIntervalEventWriter(es, t)
+		if Config.DryRun {
+			intervals.fields["dry_run"] = true
+		}
+
+		log.Printf("Writing Honeycomb interval events to dataset %s\n", Config.HCEventsDataset)
+	}
+
+	// Init the optional webhook notifier.
+	if Config.WebhookURL != "" {
+		n, err := webhook.NewNotifier(&webhook.Config{
+			URL:    Config.WebhookURL,
+			Format: Config.WebhookFormat,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		posters = append(posters, n)
+		log.Printf("Posting %s webhook notifications\n", Config.WebhookFormat)
+	}
+
+	// Events are flushed on shutdown
+	// once echan is closed.
+	eventsFlushed := make(chan struct{})
+	go func() {
+		eventWriter(echan, posters...)
+		close(eventsFlushed)
+	}()
 
 	// Init an EventGenerator.
 	events := &EventGenerator{
 		c:           echan,
-		titlePrefix: eventTitlePrefix,
+		titlePrefix: titlePrefix,
 		tags:        tags,
 	}
 
@@ -140,45 +471,227 @@ func main() {
 	knownThrottles := true
 
 	var reassignments kafkazk.Reassignments
+	var prevReassignments kafkazk.Reassignments
+	var prevBootstrap kafkazk.Reassignments
+	var prevBootstrapping []int
 	var replicatingPreviously map[string]struct{}
 	var replicatingNow map[string]struct{}
 	var done []string
+	var paused bool
+	// Previously seen overrides.
+	var prevOverride int
+	var prevBrokerOverrides BrokerOverrides
 
 	// Params for the updateReplicationThrottle
 	// request.
 
-	newLimitsConfig := NewLimitsConfig{
-		Minimum:     Config.MinRate,
-		Maximum:     Config.MaxRate,
-		CapacityMap: Config.CapMap,
-	}
+	lim := limitsFromConfig()
 
-	lim, err := NewLimits(newLimitsConfig)
-	if err != nil {
-		log.Fatal(err)
+	metrics.Set(metricMinRate, lim["minimum"]*1000000.00)
+	metrics.Set(metricMaxRate, lim["maximum"]/100)
+
+	throttleMeta := throttleMetaFromConfig(lim)
+	throttleMeta.zk = cluster
+	throttleMeta.configs = configs
+	throttleMeta.km = km
+	throttleMeta.events = events
+	throttleMeta.audit = apiConfig.Audit
+
+	// Init the optional client quota manager.
+	var quotas *QuotaManager
+	if Config.QuotaConfig != "" {
+		qc, err := loadQuotaConfig(Config.QuotaConfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		quotas = NewQuotaManager(quotaConfigs, events, lim, qc, Config.ChangeThreshold)
+		if quotas.manages(quotaProduce) && Config.NetworkRXQuery == "" {
+			log.Fatal("Produce quotas require a net-rx-query")
+		}
+
+		log.Printf("Managing quotas for %d clients\n", len(qc.Clients))
 	}
 
-	throttleMeta := &ReplicationThrottleMeta{
-		zk:               zk,
-		km:               km,
-		events:           events,
-		throttles:        make(map[int]float64),
-		limits:           lim,
-		failureThreshold: Config.FailureThreshold,
+	removal := NewRemovalCheck(cluster, Config.VerifyISR, time.Duration(Config.RemovalSettle)*time.Second)
+
+	// Init the optional new broker replication check.
+	var bootstrap *BootstrapCheck
+	if Config.NewBrokerWindow > 0 {
+		bootstrap = NewBootstrapCheck(cluster, time.Duration(Config.NewBrokerWindow)*time.Second)
 	}
 
 	overridePath := fmt.Sprintf("/%s/%s", apiConfig.ZKPrefix, apiConfig.RateSetting)
+	pausePath := fmt.Sprintf("/%s/%s", apiConfig.ZKPrefix, apiConfig.PauseSetting)
+
+	// Init the optional gRPC admin API.
+	if Config.GRPCListen != "" {
+		rpc := &RPCServer{
+			zk:           zk,
+			overridePath: overridePath,
+			pausePath:    pausePath,
+			limits:       lim,
+			status:       loopStatus,
+		}
+
+		rpcConfig := &RPCConfig{
+			Listen:        Config.GRPCListen,
+			GatewayListen: Config.GRPCGWListen,
+		}
+
+		if err := runRPC(rpcConfig, rpc); err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("gRPC admin API: %s\n", Config.GRPCListen)
+	}
+
+	// Init the optional leader election.
+	var election *LeaderElection
+	// Whether this instance is the leader; instances
+	// are the leader if leader election is disabled.
+	leader := true
+	if Config.LeaderElection {
+		p := fmt.Sprintf("/%s/%s", Config.ConfigZKPrefix, electionZNode)
+		election, err = NewLeaderElection(zk, p)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		leader = false
+		log.Printf("Leader election enabled, instance ID %s\n", election.ID())
+	}
+
+	if Config.Interval < 1 {
+		log.Fatal("interval must be > 0")
+	}
+
+	shutdownConfig := ShutdownConfig{
+		Action:       Config.ShutdownAction,
+		FallbackRate: Config.ShutdownRate,
+	}
+
+	if err := shutdownConfig.validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
 
 	// Run.
+	log.Printf("Checking throttles every %ds\n", Config.Interval)
 	var interval int64
 	var ticker = time.NewTicker(time.Duration(Config.Interval) * time.Second)
 
+	// wait waits for the next interval. If an interrupt
+	// or SIGTERM is received, the shutdown action is
+	// applied and autothrottle exits.
+	wait := func() {
+		select {
+		case <-ticker.C:
+			return
+		case sig := <-sigs:
+			log.Printf("Received %s, shutting down\n", sig)
+		}
+
+		// Standbys and paused instances
+		// don't modify throttles.
+		var reason string
+		switch {
+		case !leader:
+			reason = "instance is a standby"
+		case paused:
+			reason = "autothrottle is paused"
+		}
+
+		shutdown(cluster, throttleMeta, shutdownConfig, reason)
+
+		close(echan)
+		select {
+		case <-eventsFlushed:
+		case <-time.After(shutdownFlushTimeout):
+			log.Println("Timed out writing events")
+		}
+
+		zk.Close()
+		os.Exit(0)
+	}
+
 	for {
 		interval++
 		throttleMeta.topics = throttleMeta.topics[:0]
 
+		// Standbys leave throttles to the leader. If the
+		// leader can't be determined, this instance
+		// assumes it isn't the leader.
+		if election != nil {
+			nowLeader, id, err := election.IsLeader()
+			if err != nil {
+				log.Println(err)
+			}
+
+			switch {
+			case nowLeader && !leader:
+				m := fmt.Sprintf("Autothrottle instance %s elected leader", election.ID())
+				log.Println(m)
+				events.Write("Autothrottle leader elected", m)
+				// Throttles were managed by another instance;
+				// discard any previously applied rates so that
+				// throttles are reapplied and cleared as needed.
+				throttleMeta.throttles = make(map[int]float64)
+				throttleMeta.followerThrottles = make(map[int]float64)
+				throttleMeta.resetControllers()
+				knownThrottles = true
+			case !nowLeader && leader:
+				m := fmt.Sprintf("Autothrottle instance %s is no longer the leader", election.ID())
+				log.Println(m)
+				events.Write("Autothrottle leader lost", m)
+			}
+
+			leader = nowLeader
+
+			if !leader {
+				metrics.Set(metricLeader, 0)
+				if id != "" {
+					log.Printf("Standby, leader is %s\n", id)
+				}
+				wait()
+				continue
+			}
+
+			metrics.Set(metricLeader, 1)
+		}
+
 		// Get topics undergoing reassignment.
-		reassignments = zk.GetReassignments() // XXX This needs to return an error.
+		// If reassignments can't be listed, the previous
+		// reassignments are assumed to be ongoing.
+		reassignments, err = reassignmentLister.ListPartitionReassignments()
+		if err != nil {
+			log.Printf("Error fetching reassignments: %s\n", err)
+			reassignments = prevReassignments
+		}
+
+		// Partitions replicating to brokers that are
+		// catching up are throttled as if reassigning.
+		// If these can't be determined, the previous
+		// partitions are assumed to be replicating.
+		if bootstrap != nil {
+			br, ids, err := bootstrap.Reassignments(time.Now(), reassignments)
+			if err != nil {
+				log.Printf("Error checking for brokers catching up: %s\n", err)
+				br, ids = prevBootstrap, prevBootstrapping
+			}
+
+			if fmt.Sprint(ids) != fmt.Sprint(prevBootstrapping) && len(ids) > 0 {
+				m := fmt.Sprintf("Replication to brokers catching up: %v", ids)
+				log.Println(m)
+				events.Write("Brokers catching up", m)
+			}
+
+			prevBootstrap, prevBootstrapping = br, ids
+			reassignments = mergeReassignments(reassignments, br)
+		}
+
 		replicatingNow = make(map[string]struct{})
 		for t := range reassignments {
 			throttleMeta.topics = append(throttleMeta.topics, t)
@@ -194,6 +707,30 @@ func main() {
 			}
 		}
 
+		// Check for topics that started
+		// replicating in this interval.
+		var started []string
+		for t := range replicatingNow {
+			if _, replicating := replicatingPreviously[t]; !replicating {
+				started = append(started, t)
+			}
+		}
+
+		if len(started) > 0 {
+			sort.Strings(started)
+			m := fmt.Sprintf("Topics started reassigning: %s", started)
+			log.Println(m)
+			events.Write("Topics started reassigning", m)
+		}
+
+		// Partitions of completed reassignments are
+		// verified before removing throttles.
+		for _, t := range done {
+			removal.Add(t, prevReassignments[t])
+		}
+
+		prevReassignments = reassignments
+
 		// Log and write event.
 		if len(done) > 0 {
 			m := fmt.Sprintf("Topics done reassigning: %s", done)
@@ -209,10 +746,130 @@ func main() {
 			replicatingPreviously[t] = struct{}{}
 		}
 
+		metrics.Set(metricReassigning, float64(len(throttleMeta.topics)))
+
+		// Check whether the control loop is paused. If so,
+		// existing throttles are left as-is until resumed.
+		pauseCfg, nowPaused, err := getPause(zk, pausePath)
+		if err != nil {
+			log.Println(err)
+			// Don't risk changing throttles
+			// if the state is unknown.
+			nowPaused = paused
+		}
+
+		switch {
+		case nowPaused && !paused:
+			m := fmt.Sprintf("Autothrottle %s, throttles are frozen", pauseCfg)
+			log.Println(m)
+			events.Write("Autothrottle paused", m)
+		case !nowPaused && paused:
+			m := "Autothrottle resumed"
+			log.Println(m)
+			events.Write("Autothrottle resumed", m)
+			// Throttles may have been changed manually
+			// while paused; discard the previously applied
+			// rates so that throttles are reapplied and
+			// cleared as needed.
+			throttleMeta.throttles = make(map[int]float64)
+			throttleMeta.followerThrottles = make(map[int]float64)
+			throttleMeta.resetControllers()
+			knownThrottles = true
+		}
+
+		paused = nowPaused
+
+		if paused {
+			metrics.Set(metricPaused, 1)
+			log.Printf("Autothrottle %s\n", pauseCfg)
+			loopStatus.Set(throttleMeta, intervalPaused)
+			writeIntervalEvents(intervals, throttleMeta, intervalPaused)
+			wait()
+			continue
+		}
+
+		metrics.Set(metricPaused, 0)
+
 		// Fetch any throttle override config.
 		overrideCfg, err := getThrottleOverride(zk, overridePath)
 		if err != nil {
 			log.Println(err)
+		} else {
+			// Remove the override if it has expired.
+			rate := overrideCfg.Rate
+			expired, err := removeExpiredThrottleOverride(zk, overridePath, overrideCfg, time.Now())
+			if err != nil {
+				log.Println(err)
+			}
+
+			if expired {
+				m := fmt.Sprintf("Throttle override of %dMB/s expired", rate)
+				log.Println(m)
+				events.Write("Throttle override expired", m)
+				prevOverride = 0
+			}
+
+			// Write an event if the override changed.
+			if m := overrideChange(prevOverride, overrideCfg.Rate); m != "" {
+				log.Println(m)
+				events.Write("Throttle override changed", m)
+			}
+
+			prevOverride = overrideCfg.Rate
+		}
+
+		metrics.Set(metricOverride, float64(overrideCfg.Rate)*1000000.00)
+
+		// Fetch any broker throttle overrides,
+		// removing those that have expired.
+		brokerOverrides, err := getBrokerOverrides(zk, overridePath)
+		if err != nil {
+			log.Println(err)
+		}
+
+		brokerOverridesErr := err
+
+		expired, err := removeExpiredBrokerOverrides(zk, overridePath, brokerOverrides, time.Now())
+		if err != nil {
+			log.Println(err)
+		}
+
+		if len(expired) > 0 {
+			m := fmt.Sprintf("Throttle overrides expired on brokers: %v", expired)
+			log.Println(m)
+			events.Write("Broker throttle overrides expired", m)
+		}
+
+		// Write an event for any broker override changes.
+		// Expired overrides are reported above.
+		if brokerOverridesErr == nil {
+			for _, id := range expired {
+				delete(prevBrokerOverrides, id)
+			}
+
+			if c := brokerOverrideChanges(prevBrokerOverrides, brokerOverrides); len(c) > 0 {
+				m := strings.Join(c, "\n")
+				log.Println(m)
+				events.Write("Broker throttle overrides changed", m)
+			}
+
+			prevBrokerOverrides = brokerOverrides
+		}
+
+		metrics.Reset(metricBrokerOverride)
+		for id, o := range brokerOverrides {
+			metrics.Set(metricBrokerOverride, float64(o.Rate)*1000000.00, "broker", strconv.Itoa(id))
+		}
+
+		// Update client quotas. Quotas are retained
+		// if complete metrics can't be fetched.
+		if quotas != nil {
+			bm, errs := km.GetMetrics()
+			if errs != nil {
+				log.Printf("Errors fetching metrics for client quotas: %s\n", errs)
+			} else if err := quotas.Update(bm); err != nil {
+				log.Println(err)
+			}
 		}
 
 		// If topics are being reassigned, update
@@ -222,30 +879,53 @@ func main() {
 
 			// Update the throttleMeta.
 			throttleMeta.overrideRate = overrideCfg.Rate
+			throttleMeta.brokerOverrides = brokerOverrides
 			throttleMeta.reassignments = reassignments
 
 			err = updateReplicationThrottle(throttleMeta)
 			if err != nil {
 				log.Println(err)
 			}
+
+			// Estimate the time to complete
+			// the reassignments.
+			if Config.EstimateTransfer {
+				e, err := estimateTransfer(zk, reassignments, throttleMeta.followerThrottles)
+				if err != nil {
+					log.Println(err)
+				} else {
+					log.Println(e)
+					throttleMeta.interval.transfer = &e
+				}
+			}
 			// Set knownThrottles.
 			knownThrottles = true
 		} else {
 			log.Println("No topics undergoing reassignment")
 
-			// Unset any throttles.
+			// Unset any throttles once reassigned
+			// partitions are in-sync and settled.
 			if knownThrottles || interval == Config.CleanupAfter {
-				// Reset the interval.
-				interval = 0
+				ready, m, err := removal.Ready(time.Now())
+				switch {
+				case err != nil:
+					log.Println(err)
+				case !ready:
+					log.Println(m)
+				default:
+					// Reset the interval.
+					interval = 0
 
-				err := removeAllThrottles(zk, throttleMeta)
-				if err != nil {
-					log.Printf("Error removing throttles: %s\n", err.Error())
-				} else {
-					// Only set knownThrottles to
-					// false if we've removed all
-					// without error.
-					knownThrottles = false
+					err := removeAllThrottles(cluster, throttleMeta)
+					if err != nil {
+						log.Printf("Error removing throttles: %s\n", err.Error())
+					} else {
+						// Only set knownThrottles to
+						// false if we've removed all
+						// without error.
+						knownThrottles = false
+						removal.Reset()
+					}
 				}
 			}
 
@@ -261,7 +941,128 @@ func main() {
 			}
 		}
 
-		<-ticker.C
+		state := intervalIdle
+		if len(throttleMeta.topics) > 0 {
+			state = intervalReassigning
+		}
+
+		loopStatus.Set(throttleMeta, state)
+		writeIntervalEvents(intervals, throttleMeta, state)
+
+		wait()
+	}
+}
+
+// limitsFromConfig returns the Limits for the
+// configured rates and capacities.
+func limitsFromConfig() Limits {
+	newLimitsConfig := NewLimitsConfig{
+		Minimum:     Config.MinRate,
+		Maximum:     Config.MaxRate,
+		CapacityMap: Config.CapMap,
+		MaxDiskUtil: Config.MaxDiskUtil,
+
+		CompressionRatio: Config.CompressionRatio,
+	}
+
+	if Config.CapConfig != "" {
+		cc, err := loadCapacityConfig(Config.CapConfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := cc.apply(&newLimitsConfig); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	lim, err := NewLimits(newLimitsConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return lim
+}
+
+// throttleMetaFromConfig takes the Limits and returns a
+// *ReplicationThrottleMeta with the configured throttle
+// controller, smoothing, topic priorities and rack limits.
+// The ZooKeeper, metrics, config and event handlers aren't
+// set.
+func throttleMetaFromConfig(lim Limits) *ReplicationThrottleMeta {
+	throttleMeta := &ReplicationThrottleMeta{
+		throttles:         make(map[int]float64),
+		followerThrottles: make(map[int]float64),
+		inbound:           Config.NetworkRXQuery != "",
+		diskUtil:          Config.DiskUtilQuery != "",
+		independent:       Config.IndependentRates,
+		limits:            lim,
+		cooldown:          time.Duration(Config.ChangeCooldown) * time.Second,
+		failureThreshold:  Config.FailureThreshold,
+	}
+
+	switch {
+	case Config.SmoothingAlpha != 0 && Config.MetricsSmoothing > 1:
+		log.Fatal("metrics-smoothing and metrics-smoothing-alpha can't both be set")
+	case Config.SmoothingAlpha < 0 || Config.SmoothingAlpha > 1:
+		log.Fatal("metrics-smoothing-alpha must be > 0 and <= 1")
+	case Config.SmoothingAlpha > 0:
+		throttleMeta.smoother = NewEWMASmoother(Config.SmoothingAlpha)
+	case Config.MetricsSmoothing > 1:
+		throttleMeta.smoother = NewMetricsSmoother(Config.MetricsSmoothing)
+	}
+
+	if Config.TopicPriorities != "" {
+		tp, err := parseTopicPriorities(Config.TopicPriorities)
+		if err != nil {
+			log.Fatalf("Error parsing topic-priorities flag: %s\n", err)
+		}
+
+		throttleMeta.priorities = tp
+	}
+
+	if Config.RackLimits != "" {
+		rl, err := parseRackLimits(Config.RackLimits)
+		if err != nil {
+			log.Fatalf("Error parsing rack-limits flag: %s\n", err)
+		}
+
+		throttleMeta.rackLimits = rl
+	}
+
+	switch Config.Controller {
+	case "headroom":
+	case "pid":
+		if Config.IndependentRates {
+			log.Fatal("independent-rates isn't supported with the pid controller")
+		}
+
+		if Config.TopicPriorities != "" {
+			log.Fatal("topic-priorities isn't supported with the pid controller")
+		}
+
+		if Config.RackLimits != "" {
+			log.Fatal("rack-limits isn't supported with the pid controller")
+		}
+
+		if Config.PIDSetpoint <= 0 || Config.PIDSetpoint > 100 {
+			log.Fatal("pid-setpoint must be > 0 and <= 100")
+		}
+
+		newPID := func() *PIDController {
+			return &PIDController{
+				Kp:       Config.PIDKp,
+				Ki:       Config.PIDKi,
+				Kd:       Config.PIDKd,
+				Setpoint: Config.PIDSetpoint,
+			}
+		}
+
+		throttleMeta.leaderPID = newPID()
+		throttleMeta.followerPID = newPID()
+	default:
+		log.Fatalf("Unknown controller %s\n", Config.Controller)
 	}
 
+	return throttleMeta
 }
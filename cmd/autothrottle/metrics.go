@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Metric names. Rates are exported in bytes/s.
+const (
+	metricThrottleRate      = "autothrottle_throttle_rate_bytes_per_second"
+	metricNetTX             = "autothrottle_broker_net_tx_bytes_per_second"
+	metricNetRX             = "autothrottle_broker_net_rx_bytes_per_second"
+	metricDiskUtil          = "autothrottle_broker_disk_utilization_percent"
+	metricNetCapacity       = "autothrottle_broker_net_capacity_bytes_per_second"
+	metricHeadroom          = "autothrottle_replication_headroom_bytes_per_second"
+	metricMinRate           = "autothrottle_min_rate_bytes_per_second"
+	metricMaxRate           = "autothrottle_max_rate_ratio"
+	metricReassigning       = "autothrottle_reassigning_topics"
+	metricOverride          = "autothrottle_throttle_override_bytes_per_second"
+	metricBrokerOverride    = "autothrottle_broker_throttle_override_bytes_per_second"
+	metricPaused            = "autothrottle_paused"
+	metricMetricsFailures   = "autothrottle_metrics_failures"
+	metricMetricsErrorTotal = "autothrottle_metrics_errors_total"
+	metricAPIErrorsTotal    = "autothrottle_api_errors_total"
+	metricClientQuota       = "autothrottle_client_quota_bytes_per_second"
+	metricLeader            = "autothrottle_leader"
+)
+
+// metrics holds the autothrottle state exported
+// via the admin API /metrics endpoint.
+var metrics = NewMetrics()
+
+// Metrics is a minimal registry of gauges and counters
+// that are written in the Prometheus text format.
+type Metrics struct {
+	sync.Mutex
+	metrics map[string]*metric
+}
+
+type metric struct {
+	typ  string
+	help string
+	// Map of formatted label
+	// sets to values.
+	values map[string]float64
+}
+
+// NewMetrics returns a *Metrics with
+// all autothrottle metrics described.
+func NewMetrics() *Metrics {
+	m := &Metrics{metrics: map[string]*metric{}}
+
+	m.describe(metricThrottleRate, "gauge", "Applied replication throttle rate by broker and type (leader, follower).")
+	m.describe(metricNetTX, "gauge", "Measured outbound network throughput by broker.")
+	m.describe(metricNetRX, "gauge", "Measured inbound network throughput by broker.")
+	m.describe(metricDiskUtil, "gauge", "Measured disk utilization by broker, if a disk utilization query is configured.")
+	m.describe(metricNetCapacity, "gauge", "Configured network capacity by broker.")
+	m.describe(metricHeadroom, "gauge", "Replication headroom of the most constrained broker by type (leader, follower).")
+	m.describe(metricMinRate, "gauge", "Configured minimum replication throttle rate.")
+	m.describe(metricMaxRate, "gauge", "Configured maximum portion of free capacity used for replication.")
+	m.describe(metricReassigning, "gauge", "Number of topics undergoing reassignment.")
+	m.describe(metricOverride, "gauge", "Configured global throttle override rate (0 if unset).")
+	m.describe(metricBrokerOverride, "gauge", "Configured throttle override rate by broker.")
+	m.describe(metricPaused, "gauge", "Whether the control loop is paused (1) with throttles frozen.")
+	m.describe(metricMetricsFailures, "gauge", "Number of sequential iterations that failed to fetch complete broker metrics.")
+	m.describe(metricMetricsErrorTotal, "counter", "Total number of broker metrics fetch errors.")
+	m.describe(metricClientQuota, "gauge", "Applied client quota by client and type (produce, fetch), 0 if unset.")
+	m.describe(metricLeader, "gauge", "1 if this instance is the elected leader, 0 if a standby; only set with leader election.")
+	m.describe(metricAPIErrorsTotal, "counter", "Total number of admin API requests that failed by endpoint.")
+
+	return m
+}
+
+func (m *Metrics) describe(name, typ, help string) {
+	m.metrics[name] = &metric{typ: typ, help: help, values: map[string]float64{}}
+}
+
+// Set sets the value of metric name for the label
+// set specified as key, value pairs.
+func (m *Metrics) Set(name string, v float64, labels ...string) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.metrics[name].values[formatLabels(labels)] = v
+}
+
+// Inc increments the value of metric name for the
+// label set specified as key, value pairs.
+func (m *Metrics) Inc(name string, labels ...string) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.metrics[name].values[formatLabels(labels)]++
+}
+
+// Reset removes all values of metric name.
+func (m *Metrics) Reset(name string) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.metrics[name].values = map[string]float64{}
+}
+
+// WriteTo writes all metrics in the
+// Prometheus text format to w.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	var names []string
+	for n := range m.metrics {
+		names = append(names, n)
+	}
+
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	for _, n := range names {
+		mt := m.metrics[n]
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", n, mt.help, n, mt.typ)
+
+		var labels []string
+		for l := range mt.values {
+			labels = append(labels, l)
+		}
+
+		sort.Strings(labels)
+
+		for _, l := range labels {
+			fmt.Fprintf(&b, "%s%s %g\n", n, l, mt.values[l])
+		}
+	}
+
+	return b.WriteTo(w)
+}
+
+// formatLabels takes key, value pairs and
+// returns a Prometheus label set string.
+func formatLabels(kv []string) string {
+	if len(kv) == 0 {
+		return ""
+	}
+
+	var pairs []string
+	for i := 0; i+1 < len(kv); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", kv[i], kv[i+1]))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func getMetrics(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		io.WriteString(w, incorrectMethod)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	metrics.WriteTo(w)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetricsWriteTo(t *testing.T) {
+	m := NewMetrics()
+
+	m.Set(metricThrottleRate, 2e+07, "broker", "1002", "type", "leader")
+	m.Set(metricThrottleRate, 1e+07, "broker", "1001", "type", "leader")
+	m.Set(metricMinRate, 1e+07)
+	m.Inc(metricAPIErrorsTotal, "endpoint", "/set_throttle")
+	m.Inc(metricAPIErrorsTotal, "endpoint", "/set_throttle")
+
+	var b bytes.Buffer
+	m.WriteTo(&b)
+	out := b.String()
+
+	expected := []string{
+		"# TYPE autothrottle_throttle_rate_bytes_per_second gauge\n" +
+			"autothrottle_throttle_rate_bytes_per_second{broker=\"1001\",type=\"leader\"} 1e+07\n" +
+			"autothrottle_throttle_rate_bytes_per_second{broker=\"1002\",type=\"leader\"} 2e+07\n",
+		"autothrottle_min_rate_bytes_per_second 1e+07\n",
+		"# TYPE autothrottle_api_errors_total counter\n" +
+			"autothrottle_api_errors_total{endpoint=\"/set_throttle\"} 2\n",
+	}
+
+	for _, e := range expected {
+		if !bytes.Contains(b.Bytes(), []byte(e)) {
+			t.Errorf("Expected output to contain:\n%s\ngot:\n%s", e, out)
+		}
+	}
+
+	m.Reset(metricThrottleRate)
+
+	b.Reset()
+	m.WriteTo(&b)
+
+	if bytes.Contains(b.Bytes(), []byte("autothrottle_throttle_rate_bytes_per_second{")) {
+		t.Error("Expected throttle rate values to be reset")
+	}
+}
+
+func TestFormatLabels(t *testing.T) {
+	tests := map[string][]string{
+		"":                                   nil,
+		`{broker="1001"}`:                    {"broker", "1001"},
+		`{broker="1001",type="follower"}`:    {"broker", "1001", "type", "follower"},
+		`{endpoint="/set_throttle?x=\"y\""}`: {"endpoint", `/set_throttle?x="y"`},
+	}
+
+	for expected, kv := range tests {
+		if l := formatLabels(kv); l != expected {
+			t.Errorf("Expected labels %s, got %s", expected, l)
+		}
+	}
+}
+
+func TestGetMetrics(t *testing.T) {
+	rr := httptest.NewRecorder()
+	getMetrics(rr, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, got %d", http.StatusMethodNotAllowed, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	getMetrics(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := rr.Header().Get("Content-Type"); ct != "text/plain; version=0.0.4" {
+		t.Errorf("Unexpected Content-Type %s", ct)
+	}
+
+	if !bytes.Contains(rr.Body.Bytes(), []byte("# TYPE autothrottle_reassigning_topics gauge")) {
+		t.Errorf("Unexpected metrics output:\n%s", rr.Body.String())
+	}
+}
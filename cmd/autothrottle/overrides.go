@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// BrokerOverrideConfig holds a throttle
+// override for an individual broker.
+type BrokerOverrideConfig struct {
+	// Rate in MB.
+	Rate int `json:"rate"`
+	// Unix timestamp after which the override
+	// is removed. The override doesn't expire
+	// if 0.
+	Expires int64 `json:"expires,omitempty"`
+}
+
+// Expired returns whether the
+// override has expired as of t.
+func (c BrokerOverrideConfig) Expired(t time.Time) bool {
+	return c.Expires != 0 && t.Unix() >= c.Expires
+}
+
+// BrokerOverrides is a map of broker
+// IDs to BrokerOverrideConfig.
+type BrokerOverrides map[int]BrokerOverrideConfig
+
+// IDs returns a sorted []int of
+// broker IDs in the BrokerOverrides.
+func (b BrokerOverrides) IDs() []int {
+	var ids []int
+	for id := range b {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+// Changed takes another BrokerOverrides and a set of broker IDs and
+// returns whether the overrides for any of the brokers differ.
+func (b BrokerOverrides) Changed(b2 BrokerOverrides, ids map[int]struct{}) bool {
+	for id := range ids {
+		o1, exists1 := b[id]
+		o2, exists2 := b2[id]
+		if exists1 != exists2 || o1.Rate != o2.Rate {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Broker overrides are stored as children (named
+// by broker ID) of the global override znode p.
+
+func brokerOverridePath(p string, id int) string {
+	return fmt.Sprintf("%s/%d", p, id)
+}
+
+// getBrokerOverrides returns all configured broker throttle overrides.
+func getBrokerOverrides(zk kafkazk.Handler, p string) (BrokerOverrides, error) {
+	overrides := BrokerOverrides{}
+
+	ids, err := zk.Children(p)
+	if err != nil {
+		return overrides, fmt.Errorf("Error getting broker throttle overrides: %s", err)
+	}
+
+	for _, n := range ids {
+		id, err := strconv.Atoi(n)
+		if err != nil {
+			continue
+		}
+
+		data, err := zk.Get(brokerOverridePath(p, id))
+		if err != nil {
+			return overrides, fmt.Errorf("Error getting broker throttle override: %s", err)
+		}
+
+		c := BrokerOverrideConfig{}
+		if err := json.Unmarshal(data, &c); err != nil {
+			return overrides, fmt.Errorf("Error unmarshalling broker override config: %s", err)
+		}
+
+		overrides[id] = c
+	}
+
+	return overrides, nil
+}
+
+func setBrokerOverride(zk kafkazk.Handler, p string, id int, c BrokerOverrideConfig) error {
+	d, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("Error marshalling broker override config: %s", err)
+	}
+
+	path := brokerOverridePath(p, id)
+
+	exists, err := zk.Exists(path)
+	if err != nil {
+		return fmt.Errorf("Error setting broker throttle override: %s", err)
+	}
+
+	if exists {
+		err = zk.Set(path, string(d))
+	} else {
+		err = zk.Create(path, string(d))
+	}
+
+	if err != nil {
+		return fmt.Errorf("Error setting broker throttle override: %s", err)
+	}
+
+	return nil
+}
+
+// removeBrokerOverride removes the override for broker id, returning
+// false if no override was set.
+func removeBrokerOverride(zk kafkazk.Handler, p string, id int) (bool, error) {
+	path := brokerOverridePath(p, id)
+
+	exists, err := zk.Exists(path)
+	if err != nil {
+		return false, fmt.Errorf("Error removing broker throttle override: %s", err)
+	}
+
+	if !exists {
+		return false, nil
+	}
+
+	if err := zk.Delete(path); err != nil {
+		return false, fmt.Errorf("Error removing broker throttle override: %s", err)
+	}
+
+	return true, nil
+}
+
+// removeExpiredBrokerOverrides removes any overrides that have expired as
+// of t from ZooKeeper and the BrokerOverrides, returning the IDs of all
+// brokers where the override was removed.
+func removeExpiredBrokerOverrides(zk kafkazk.Handler, p string, b BrokerOverrides, t time.Time) ([]int, error) {
+	var removed []int
+
+	for _, id := range b.IDs() {
+		if !b[id].Expired(t) {
+			continue
+		}
+
+		if _, err := removeBrokerOverride(zk, p, id); err != nil {
+			return removed, err
+		}
+
+		delete(b, id)
+		removed = append(removed, id)
+	}
+
+	return removed, nil
+}
+
+// removeExpiredThrottleOverride removes the global throttle override if it
+// has expired as of t, in which case c is reset and true is returned.
+func removeExpiredThrottleOverride(zk kafkazk.Handler, p string, c *ThrottleOverrideConfig, t time.Time) (bool, error) {
+	if !c.Expired(t) {
+		return false, nil
+	}
+
+	if err := setThrottleOverride(zk, p, ThrottleOverrideConfig{}); err != nil {
+		return false, err
+	}
+
+	*c = ThrottleOverrideConfig{}
+
+	return true, nil
+}
+
+// overrideChange takes the previous and current global throttle override
+// rates and returns a description of the change, or "" if unchanged.
+func overrideChange(prev, curr int) string {
+	switch {
+	case prev == curr:
+		return ""
+	case prev == 0:
+		return fmt.Sprintf("Throttle override of %dMB/s set", curr)
+	case curr == 0:
+		return fmt.Sprintf("Throttle override of %dMB/s removed", prev)
+	}
+
+	return fmt.Sprintf("Throttle override changed from %dMB/s to %dMB/s", prev, curr)
+}
+
+// brokerOverrideChanges takes the previous and current BrokerOverrides
+// and returns a description of each change, ordered by broker ID.
+func brokerOverrideChanges(prev, curr BrokerOverrides) []string {
+	var changes []string
+
+	all := BrokerOverrides{}
+	for _, b := range []BrokerOverrides{prev, curr} {
+		for id, o := range b {
+			all[id] = o
+		}
+	}
+
+	for _, id := range all.IDs() {
+		if m := overrideChange(prev[id].Rate, curr[id].Rate); m != "" {
+			changes = append(changes, fmt.Sprintf("%s on broker %d", m, id))
+		}
+	}
+
+	return changes
+}
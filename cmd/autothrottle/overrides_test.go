@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// memZK is a kafkazk.Handler storing
+// znode data in memory.
+type memZK struct {
+	kafkazk.Mock
+	data map[string]string
+	// Sequence number of the last
+	// sequential znode created.
+	seq int
+}
+
+func newMemZK() *memZK {
+	return &memZK{data: map[string]string{}}
+}
+
+func (zk *memZK) Exists(p string) (bool, error) {
+	_, exists := zk.data[p]
+	return exists, nil
+}
+
+func (zk *memZK) Create(p, d string) error {
+	zk.data[p] = d
+	return nil
+}
+
+func (zk *memZK) CreateEphemeralSequential(p, d string) (string, error) {
+	zk.seq++
+	created := fmt.Sprintf("%s%010d", p, zk.seq)
+	zk.data[created] = d
+	return created, nil
+}
+
+func (zk *memZK) Set(p, d string) error {
+	zk.data[p] = d
+	return nil
+}
+
+func (zk *memZK) Get(p string) ([]byte, error) {
+	return []byte(zk.data[p]), nil
+}
+
+func (zk *memZK) Delete(p string) error {
+	delete(zk.data, p)
+	return nil
+}
+
+func (zk *memZK) Children(p string) ([]string, error) {
+	var c []string
+	for k := range zk.data {
+		if strings.HasPrefix(k, p+"/") && !strings.Contains(k[len(p)+1:], "/") {
+			c = append(c, k[len(p)+1:])
+		}
+	}
+
+	return c, nil
+}
+
+func TestBrokerOverrides(t *testing.T) {
+	zk := newMemZK()
+	p := "/autothrottle/override_rate"
+
+	now := time.Now()
+	setBrokerOverride(zk, p, 1001, BrokerOverrideConfig{Rate: 50})
+	setBrokerOverride(zk, p, 1002, BrokerOverrideConfig{Rate: 20, Expires: now.Add(-time.Second).Unix()})
+	setBrokerOverride(zk, p, 1003, BrokerOverrideConfig{Rate: 30, Expires: now.Add(time.Hour).Unix()})
+
+	o, err := getBrokerOverrides(zk, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := o.IDs(); len(ids) != 3 || ids[0] != 1001 || ids[2] != 1003 {
+		t.Errorf("Unexpected overrides: %v", ids)
+	}
+
+	removed, err := removeExpiredBrokerOverrides(zk, p, o, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(removed) != 1 || removed[0] != 1002 {
+		t.Errorf("Expected expired override for broker 1002, got %v", removed)
+	}
+
+	if _, exists := zk.data[p+"/1002"]; exists {
+		t.Error("Expected override for broker 1002 to be removed")
+	}
+
+	if _, exists := o[1002]; exists {
+		t.Error("Expected override for broker 1002 to be removed")
+	}
+
+	if ok, _ := removeBrokerOverride(zk, p, 1002); ok {
+		t.Error("Unexpected removal of nonexistent override")
+	}
+}
+
+func TestRemoveExpiredThrottleOverride(t *testing.T) {
+	zk := newMemZK()
+	p := "/autothrottle/override_rate"
+
+	now := time.Now()
+	c := &ThrottleOverrideConfig{Rate: 50, Expires: now.Add(time.Hour).Unix()}
+	setThrottleOverride(zk, p, *c)
+
+	if expired, _ := removeExpiredThrottleOverride(zk, p, c, now); expired || c.Rate != 50 {
+		t.Error("Unexpected removal of unexpired override")
+	}
+
+	expired, err := removeExpiredThrottleOverride(zk, p, c, now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !expired || c.Rate != 0 || c.Expires != 0 {
+		t.Errorf("Expected expired override to be removed, got %+v", c)
+	}
+
+	if r, _ := getThrottleOverride(zk, p); r.Rate != 0 {
+		t.Errorf("Expected override rate 0, got %d", r.Rate)
+	}
+
+	// Overrides without an expiry are retained.
+	c = &ThrottleOverrideConfig{Rate: 50}
+	if expired, _ := removeExpiredThrottleOverride(zk, p, c, now.Add(time.Hour)); expired {
+		t.Error("Unexpected removal of override without expiry")
+	}
+}
+
+func TestBrokerOverridesChanged(t *testing.T) {
+	o := BrokerOverrides{1001: BrokerOverrideConfig{Rate: 50}}
+	all := map[int]struct{}{1001: struct{}{}, 1002: struct{}{}}
+
+	if o.Changed(BrokerOverrides{1001: BrokerOverrideConfig{Rate: 50}}, all) {
+		t.Error("Unexpected change")
+	}
+
+	if !o.Changed(BrokerOverrides{1001: BrokerOverrideConfig{Rate: 40}}, all) {
+		t.Error("Expected changed rate")
+	}
+
+	if !o.Changed(BrokerOverrides{}, all) {
+		t.Error("Expected added override")
+	}
+
+	// Non-participating brokers are ignored.
+	if o.Changed(BrokerOverrides{1001: BrokerOverrideConfig{Rate: 50}, 1003: BrokerOverrideConfig{Rate: 10}}, all) {
+		t.Error("Unexpected change")
+	}
+}
+
+func TestExcluding(t *testing.T) {
+	b := mockBmapBundle()
+
+	out := b.excluding(BrokerOverrides{1000: BrokerOverrideConfig{}, 1005: BrokerOverrideConfig{}})
+	if len(out.src) != 4 || len(out.dst) != 4 || len(out.all) != 8 {
+		t.Errorf("Unexpected brokers: %v, %v", out.src, out.dst)
+	}
+
+	if _, exists := out.src[1000]; exists {
+		t.Error("Unexpected broker 1000")
+	}
+
+	// All dst brokers overridden.
+	o := BrokerOverrides{}
+	for id := range b.dst {
+		o[id] = BrokerOverrideConfig{}
+	}
+
+	if out := b.excluding(o); len(out.dst) != 5 {
+		t.Errorf("Expected unchanged bmapBundle, got dst %v", out.dst)
+	}
+}
+
+func TestBrokerThrottleAPI(t *testing.T) {
+	zk := newMemZK()
+	p := "/autothrottle/override_rate"
+
+	req := func(method, url string, h func(http.ResponseWriter, *http.Request, kafkazk.Handler, string)) string {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(method, url, nil), zk, p)
+		return w.Body.String()
+	}
+
+	if r := req("POST", "/set_broker_throttle?id=1001&rate=0", setBrokerThrottle); r != "rate param must be >0\n" {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	if r := req("POST", "/set_broker_throttle?id=1001&rate=50&ttl=x", setBrokerThrottle); !strings.HasPrefix(r, "ttl param") {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	if r := req("POST", "/set_broker_throttle?id=1001&rate=50", setBrokerThrottle); r != "throttle successfully set to 50MB/s for broker 1001\n" {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	if r := req("GET", "/get_broker_throttle?id=1001", getBrokerThrottle); r != "a throttle override is configured at 50MB/s for broker 1001\n" {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	if r := req("GET", "/set_broker_throttle?id=1001&rate=50", setBrokerThrottle); r != incorrectMethod {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	if r := req("POST", "/remove_broker_throttle?id=1001", removeBrokerThrottle); r != "throttle successfully removed for broker 1001\n" {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	if r := req("GET", "/get_broker_throttle", getBrokerThrottle); r != "no broker throttle overrides are set\n" {
+		t.Errorf("Unexpected response: %s", r)
+	}
+}
+
+func TestOverrideChange(t *testing.T) {
+	tests := []struct {
+		prev, curr int
+		expected   string
+	}{
+		{0, 0, ""},
+		{50, 50, ""},
+		{0, 50, "Throttle override of 50MB/s set"},
+		{50, 0, "Throttle override of 50MB/s removed"},
+		{50, 100, "Throttle override changed from 50MB/s to 100MB/s"},
+	}
+
+	for _, test := range tests {
+		if m := overrideChange(test.prev, test.curr); m != test.expected {
+			t.Errorf("Expected '%s', got '%s'", test.expected, m)
+		}
+	}
+}
+
+func TestBrokerOverrideChanges(t *testing.T) {
+	prev := BrokerOverrides{
+		1001: BrokerOverrideConfig{Rate: 50},
+		1002: BrokerOverrideConfig{Rate: 50},
+	}
+
+	curr := BrokerOverrides{
+		1002: BrokerOverrideConfig{Rate: 50, Expires: 100},
+		1003: BrokerOverrideConfig{Rate: 20},
+	}
+
+	expected := []string{
+		"Throttle override of 50MB/s removed on broker 1001",
+		"Throttle override of 20MB/s set on broker 1003",
+	}
+
+	changes := brokerOverrideChanges(prev, curr)
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, changes)
+	}
+
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Expected '%s', got '%s'", expected[i], changes[i])
+		}
+	}
+
+	if c := brokerOverrideChanges(nil, nil); len(c) != 0 {
+		t.Errorf("Unexpected changes %v", c)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// PauseConfig holds the state of a paused
+// autothrottle control loop. While paused,
+// throttles are neither updated nor removed.
+type PauseConfig struct {
+	// Unix timestamp when paused.
+	Since int64 `json:"since"`
+	// Optional operator supplied reason.
+	Reason string `json:"reason,omitempty"`
+}
+
+// String returns a description of the pause.
+func (c PauseConfig) String() string {
+	s := fmt.Sprintf("paused since %s", time.Unix(c.Since, 0).UTC().Format(time.RFC3339))
+	if c.Reason != "" {
+		s += fmt.Sprintf(" (%s)", c.Reason)
+	}
+
+	return s
+}
+
+// getPause returns the PauseConfig stored at p
+// and whether the control loop is paused.
+func getPause(zk kafkazk.Handler, p string) (PauseConfig, bool, error) {
+	c := PauseConfig{}
+
+	exists, err := zk.Exists(p)
+	if err != nil {
+		return c, false, fmt.Errorf("Error getting pause state: %s", err)
+	}
+
+	if !exists {
+		return c, false, nil
+	}
+
+	data, err := zk.Get(p)
+	if err != nil {
+		return c, false, fmt.Errorf("Error getting pause state: %s", err)
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, false, fmt.Errorf("Error unmarshalling pause config: %s", err)
+	}
+
+	return c, true, nil
+}
+
+func setPause(zk kafkazk.Handler, p string, c PauseConfig) error {
+	d, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("Error marshalling pause config: %s", err)
+	}
+
+	if err := zk.Create(p, string(d)); err != nil {
+		return fmt.Errorf("Error setting pause state: %s", err)
+	}
+
+	return nil
+}
+
+func removePause(zk kafkazk.Handler, p string) error {
+	if err := zk.Delete(p); err != nil {
+		return fmt.Errorf("Error removing pause state: %s", err)
+	}
+
+	return nil
+}
+
+func getPauseState(w http.ResponseWriter, req *http.Request, zk kafkazk.Handler, p string) {
+	logReq(req)
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		io.WriteString(w, incorrectMethod)
+		return
+	}
+
+	c, paused, err := getPause(zk, p)
+	switch {
+	case err != nil:
+		metrics.Inc(metricAPIErrorsTotal, "endpoint", req.URL.Path)
+		io.WriteString(w, fmt.Sprintf("%s\n", err))
+	case !paused:
+		io.WriteString(w, "autothrottle is running\n")
+	default:
+		io.WriteString(w, fmt.Sprintf("autothrottle is %s\n", c))
+	}
+}
+
+func pause(w http.ResponseWriter, req *http.Request, zk kafkazk.Handler, p string) {
+	logReq(req)
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		io.WriteString(w, incorrectMethod)
+		return
+	}
+
+	c, paused, err := getPause(zk, p)
+	if err != nil {
+		metrics.Inc(metricAPIErrorsTotal, "endpoint", req.URL.Path)
+		io.WriteString(w, fmt.Sprintf("%s\n", err))
+		return
+	}
+
+	if paused {
+		io.WriteString(w, fmt.Sprintf("autothrottle is already %s\n", c))
+		return
+	}
+
+	c = PauseConfig{
+		Since:  time.Now().Unix(),
+		Reason: req.URL.Query().Get("reason"),
+	}
+
+	if err := setPause(zk, p, c); err != nil {
+		metrics.Inc(metricAPIErrorsTotal, "endpoint", req.URL.Path)
+		io.WriteString(w, fmt.Sprintf("%s\n", err))
+		return
+	}
+
+	io.WriteString(w, "autothrottle successfully paused, throttles are frozen\n")
+}
+
+func resume(w http.ResponseWriter, req *http.Request, zk kafkazk.Handler, p string) {
+	logReq(req)
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		io.WriteString(w, incorrectMethod)
+		return
+	}
+
+	_, paused, err := getPause(zk, p)
+	if err != nil {
+		metrics.Inc(metricAPIErrorsTotal, "endpoint", req.URL.Path)
+		io.WriteString(w, fmt.Sprintf("%s\n", err))
+		return
+	}
+
+	if !paused {
+		io.WriteString(w, "autothrottle is not paused\n")
+		return
+	}
+
+	if err := removePause(zk, p); err != nil {
+		metrics.Inc(metricAPIErrorsTotal, "endpoint", req.URL.Path)
+		io.WriteString(w, fmt.Sprintf("%s\n", err))
+		return
+	}
+
+	io.WriteString(w, "autothrottle successfully resumed\n")
+}
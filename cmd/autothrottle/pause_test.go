@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+func TestPauseAPI(t *testing.T) {
+	zk := newMemZK()
+	p := "/autothrottle/paused"
+
+	req := func(method, url string, h func(http.ResponseWriter, *http.Request, kafkazk.Handler, string)) string {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(method, url, nil), zk, p)
+		return w.Body.String()
+	}
+
+	if r := req("GET", "/get_pause", getPauseState); r != "autothrottle is running\n" {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	if r := req("POST", "/resume", resume); r != "autothrottle is not paused\n" {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	if r := req("GET", "/pause", pause); r != incorrectMethod {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	if r := req("POST", "/pause?reason=incident", pause); r != "autothrottle successfully paused, throttles are frozen\n" {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	c, paused, err := getPause(zk, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !paused || c.Reason != "incident" || c.Since == 0 {
+		t.Errorf("Unexpected pause state %v: %+v", paused, c)
+	}
+
+	if r := req("POST", "/pause", pause); !strings.HasPrefix(r, "autothrottle is already paused since") {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	if r := req("GET", "/get_pause", getPauseState); !strings.HasSuffix(r, "(incident)\n") {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	if r := req("POST", "/resume", resume); r != "autothrottle successfully resumed\n" {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	if _, paused, _ := getPause(zk, p); paused {
+		t.Error("Expected autothrottle to be resumed")
+	}
+}
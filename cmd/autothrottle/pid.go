@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+)
+
+// PIDController adjusts a throttle rate to hold the network utilization
+// of the most constrained broker at a setpoint. This replaces the headroom
+// calculation, which sets the throttle to a portion of the currently
+// available headroom each interval; because the utilization measured
+// includes the previous throttle, this tends to oscillate between
+// conservative and saturating rates.
+//
+// The controller uses the velocity form: each interval the throttle
+// is changed by
+//
+//	Kp*(e - e1) + Ki*e + Kd*(e - 2*e1 + e2)
+//
+// where e is the difference (in MB/s) between the setpoint utilization
+// and the measured utilization, and e1 and e2 are the errors from the
+// previous two intervals. Since the output is derived from the throttle
+// actually applied and clamped to the configured limits, the integral
+// term can't wind up.
+type PIDController struct {
+	Kp float64
+	Ki float64
+	Kd float64
+	// Target utilization as a
+	// percentage of capacity.
+	Setpoint float64
+
+	// Errors from the previous two intervals.
+	e1, e2 float64
+	// Number of updates since a reset.
+	n int
+}
+
+// Reset clears the controller state, e.g. when
+// reassignments complete or throttles are removed.
+func (p *PIDController) Reset() {
+	p.e1, p.e2, p.n = 0, 0, 0
+}
+
+// Update takes the measured utilization, capacity and current throttle
+// (MB/s) and the min and max throttle rates and returns the new throttle.
+func (p *PIDController) Update(util, capacity, curr, min, max float64) float64 {
+	e := capacity*(p.Setpoint/100) - util
+
+	// With no error history, the
+	// proportional and derivative
+	// terms are 0.
+	e1, e2 := p.e1, p.e2
+	switch p.n {
+	case 0:
+		e1, e2 = e, e
+	case 1:
+		e2 = e1
+	}
+
+	delta := p.Kp*(e-e1) + p.Ki*e + p.Kd*(e-2*e1+e2)
+
+	p.e2, p.e1 = p.e1, e
+	p.n++
+
+	return math.Min(math.Max(curr+delta, min), max)
+}
+
+// pidCapacity takes a *PIDController, the most constrained broker, its
+// measured utilization and current throttle and returns the replication
+// capacity along with an event string. The headroom based capacity h is
+// returned if no throttle is currently applied, since the controller
+// requires a throttle to adjust; the controller is reset in that case.
+func pidCapacity(p *PIDController, l Limits, b *kafkametrics.Broker, util, curr, h float64) (float64, string, error) {
+	capacity, known := l.capacity(b)
+	if !known {
+		return 0.00, "", errors.New("Unknown instance type")
+	}
+
+	if curr <= 0 {
+		p.Reset()
+		return h, fmt.Sprintf("No throttle applied on broker %d, using the headroom based rate", b.ID), nil
+	}
+
+	max := capacity * (l["maximum"] / 100)
+	r := p.Update(util, capacity, curr, l["minimum"], max)
+
+	event := fmt.Sprintf("PID controller: utilization of %.2fMB/s on broker %d vs a setpoint of %.2fMB/s (%.0f%%), "+
+		"adjusting the throttle from %.2fMB/s to %.2fMB/s", util, b.ID, capacity*(p.Setpoint/100), p.Setpoint, curr, r)
+
+	return r, event, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+)
+
+func TestPIDControllerUpdate(t *testing.T) {
+	p := &PIDController{Kp: 0.2, Ki: 0.5, Setpoint: 80}
+
+	// Capacity 100, target 80. The first update is
+	// integral only: 50 + 0.5*(80-60) = 60.
+	r := p.Update(60, 100, 50, 10, 90)
+	if r != 60 {
+		t.Errorf("Expected 60, got %f", r)
+	}
+
+	// e=10 (70 util), e1=20: 60 + 0.2*(10-20) + 0.5*10 = 63.
+	r = p.Update(70, 100, r, 10, 90)
+	if math.Abs(r-63) > 0.0001 {
+		t.Errorf("Expected 63, got %f", r)
+	}
+
+	// Clamped to the max.
+	if r := p.Update(0, 100, 85, 10, 90); r != 90 {
+		t.Errorf("Expected 90, got %f", r)
+	}
+
+	// Clamped to the min.
+	if r := p.Update(200, 100, 20, 10, 90); r != 10 {
+		t.Errorf("Expected 10, got %f", r)
+	}
+
+	p.Reset()
+	if p.n != 0 || p.e1 != 0 || p.e2 != 0 {
+		t.Error("Expected controller state to be reset")
+	}
+}
+
+func TestPIDControllerConverges(t *testing.T) {
+	p := &PIDController{Kp: 0.2, Ki: 0.5, Setpoint: 80}
+
+	// A broker with a fixed 50MB/s of non-replication
+	// throughput; utilization is the throttle plus the
+	// non-replication throughput.
+	r := 10.00
+	for i := 0; i < 30; i++ {
+		r = p.Update(50+r, 100, r, 10, 90)
+	}
+
+	if math.Abs(r-30) > 0.01 {
+		t.Errorf("Expected throttle to converge on 30, got %f", r)
+	}
+}
+
+func TestPIDCapacity(t *testing.T) {
+	l, _ := NewLimits(NewLimitsConfig{
+		Minimum:     10,
+		Maximum:     90,
+		CapacityMap: map[string]float64{"mock": 100},
+	})
+
+	p := &PIDController{Ki: 0.5, Setpoint: 80}
+	b := &kafkametrics.Broker{ID: 1001, InstanceType: "mock"}
+
+	// No throttle applied; the headroom rate is used.
+	r, e, _ := pidCapacity(p, l, b, 60, 0, 42)
+	if r != 42 || !strings.HasPrefix(e, "No throttle applied") {
+		t.Errorf("Expected headroom rate 42, got %f (%s)", r, e)
+	}
+
+	r, _, _ = pidCapacity(p, l, b, 60, 50, 42)
+	if r != 60 {
+		t.Errorf("Expected 60, got %f", r)
+	}
+
+	b.InstanceType = "unknown"
+	if _, _, err := pidCapacity(p, l, b, 60, 50, 42); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TopicPriorities is a list of topic name patterns
+// and the portion of the replication throttle rate
+// that reassignments of matching topics receive.
+type TopicPriorities []topicPriority
+
+type topicPriority struct {
+	pattern *regexp.Regexp
+	// Percentage of the throttle rate.
+	rate float64
+}
+
+// parseTopicPriorities takes a JSON map of topic regex to throttle rate
+// percentage and returns a TopicPriorities.
+func parseTopicPriorities(s string) (TopicPriorities, error) {
+	m := map[string]float64{}
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		return nil, err
+	}
+
+	var patterns []string
+	for p := range m {
+		patterns = append(patterns, p)
+	}
+
+	sort.Strings(patterns)
+
+	tp := TopicPriorities{}
+	for _, p := range patterns {
+		if m[p] <= 0 || m[p] > 100 {
+			return nil, fmt.Errorf("Rate for topic pattern %s must be > 0 and <= 100", p)
+		}
+
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid topic pattern %s: %s", p, err)
+		}
+
+		tp = append(tp, topicPriority{pattern: re, rate: m[p]})
+	}
+
+	return tp, nil
+}
+
+// rate returns the throttle rate percentage for topic t. If t matches
+// several patterns, the lowest percentage is used. Topics that don't
+// match any pattern receive the full rate (100).
+func (tp TopicPriorities) rate(t string) float64 {
+	rate := 100.00
+	for _, p := range tp {
+		if p.pattern.MatchString(t) {
+			rate = math.Min(rate, p.rate)
+		}
+	}
+
+	return rate
+}
+
+// brokerRates takes a bmapBundle and returns the throttle rate percentage
+// of each broker: the highest percentage of any topic that the broker is
+// a throttled leader or follower for. A broker replicating any full rate
+// topic receives the full rate.
+func (tp TopicPriorities) brokerRates(bmb bmapBundle) map[int]float64 {
+	rates := map[int]float64{}
+
+	for t, throttled := range bmb.throttled {
+		rate := tp.rate(t)
+		for _, replicas := range throttled {
+			for _, r := range replicas {
+				// Replicas are formatted as
+				// partition:broker.
+				id, err := strconv.Atoi(r[strings.Index(r, ":")+1:])
+				if err != nil {
+					continue
+				}
+
+				rates[id] = math.Max(rates[id], rate)
+			}
+		}
+	}
+
+	return rates
+}
+
+// apply takes a bmapBundle, the per broker rates (or nil if shared), the
+// shared leader and follower throttles and a minimum rate. The throttles of
+// any brokers that only replicate reduced rate topics are scaled by the
+// broker's rate percentage, to no less than the minimum rate. The resulting
+// brokerRates are returned with an event string, or nil if no brokers
+// were scaled.
+func (tp TopicPriorities) apply(bmb bmapBundle, rates brokerRates, leader, follower, min float64) (brokerRates, string) {
+	pct := tp.brokerRates(bmb)
+
+	var scaled []int
+	out := brokerRates{}
+	for id := range bmb.all {
+		r, fr := leader, follower
+		if br, exists := rates[id]; exists {
+			r, fr = br[0], br[1]
+		}
+
+		if p, exists := pct[id]; exists && p < 100 {
+			r = math.Max(r*p/100, math.Min(r, min))
+			fr = math.Max(fr*p/100, math.Min(fr, min))
+			scaled = append(scaled, id)
+		}
+
+		out[id] = [2]float64{r, fr}
+	}
+
+	if len(scaled) == 0 {
+		return nil, ""
+	}
+
+	sort.Ints(scaled)
+
+	event := fmt.Sprintf("Throttles reduced on brokers replicating only lower priority topics: %v", scaled)
+
+	return out, event
+}
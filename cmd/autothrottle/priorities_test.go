@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestParseTopicPriorities(t *testing.T) {
+	tp, err := parseTopicPriorities(`{"archive_.*": 25, "mock": 50}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tp) != 2 {
+		t.Errorf("Expected 2 priorities, got %d", len(tp))
+	}
+
+	for _, s := range []string{`{"mock": 0}`, `{"mock": 101}`, `{"(": 50}`, `["mock"]`} {
+		if _, err := parseTopicPriorities(s); err == nil {
+			t.Errorf("Expected non-nil error for %s", s)
+		}
+	}
+}
+
+func TestTopicPriorityRate(t *testing.T) {
+	tp, _ := parseTopicPriorities(`{"archive_.*": 25, "archive_logs": 50}`)
+
+	expected := map[string]float64{
+		"archive_logs":    25,
+		"archive_metrics": 25,
+		"events":          100,
+	}
+
+	for topic, rate := range expected {
+		if r := tp.rate(topic); r != rate {
+			t.Errorf("Expected rate %.0f for %s, got %.0f", rate, topic, r)
+		}
+	}
+}
+
+func TestTopicPrioritiesApply(t *testing.T) {
+	tp, _ := parseTopicPriorities(`{"archive_.*": 25}`)
+
+	bmb := mockBmapBundle()
+	bmb.throttled["archive_logs"] = map[string][]string{
+		"leaders":   []string{"0:1000", "1:1010"},
+		"followers": []string{"0:1011"},
+	}
+
+	for _, id := range []int{1010, 1011} {
+		bmb.src[id] = struct{}{}
+		bmb.all[id] = struct{}{}
+	}
+
+	pct := tp.brokerRates(bmb)
+	// 1000 also replicates the full rate mock topic.
+	if pct[1000] != 100 || pct[1010] != 25 || pct[1011] != 25 {
+		t.Errorf("Unexpected broker rates %v", pct)
+	}
+
+	rates, event := tp.apply(bmb, nil, 100, 80, 10)
+	if rates == nil || event == "" {
+		t.Fatal("Expected scaled rates")
+	}
+
+	expected := brokerRates{
+		1000: [2]float64{100, 80},
+		1010: [2]float64{25, 20},
+		1011: [2]float64{25, 20},
+	}
+
+	for id, r := range expected {
+		if rates[id] != r {
+			t.Errorf("Expected broker %d rates %v, got %v", id, r, rates[id])
+		}
+	}
+
+	// Floored at the minimum.
+	rates, _ = tp.apply(bmb, nil, 20, 20, 10)
+	if rates[1010] != [2]float64{10, 10} {
+		t.Errorf("Expected broker 1010 rates [10 10], got %v", rates[1010])
+	}
+
+	// No brokers scaled.
+	delete(bmb.throttled, "archive_logs")
+	if rates, _ := tp.apply(bmb, nil, 100, 80, 10); rates != nil {
+		t.Errorf("Expected nil rates, got %v", rates)
+	}
+}
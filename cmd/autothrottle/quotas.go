@@ -0,0 +1,394 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"sort"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// Client quota types.
+const (
+	quotaProduce = "produce"
+	quotaFetch   = "fetch"
+)
+
+// quotaConfigKeys maps quota types to
+// the Kafka client config keys.
+var quotaConfigKeys = map[string]string{
+	quotaProduce: "producer_byte_rate",
+	quotaFetch:   "consumer_byte_rate",
+}
+
+// QuotaConfig holds the client quota management configuration,
+// as read from the file specified via -quota-config.
+type QuotaConfig struct {
+	// Broker network utilization, as a percentage
+	// of capacity, above which managed client
+	// quotas are applied.
+	Threshold float64 `json:"threshold"`
+	// Map of client ID to quota limits.
+	Clients map[string]ClientQuotaConfig `json:"clients"`
+}
+
+// ClientQuotaConfig holds the produce and fetch quota limits for a
+// client. A quota type is not managed if its limits are unset.
+type ClientQuotaConfig struct {
+	Produce *QuotaLimits `json:"produce,omitempty"`
+	Fetch   *QuotaLimits `json:"fetch,omitempty"`
+}
+
+// QuotaLimits holds the minimum and maximum quota in MB/s.
+type QuotaLimits struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// limits returns the QuotaLimits for quota type t.
+func (c ClientQuotaConfig) limits(t string) *QuotaLimits {
+	if t == quotaProduce {
+		return c.Produce
+	}
+
+	return c.Fetch
+}
+
+// loadQuotaConfig reads a QuotaConfig from the file at path.
+func loadQuotaConfig(path string) (*QuotaConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading quota config: %s", err)
+	}
+
+	c := &QuotaConfig{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("Error parsing quota config: %s", err)
+	}
+
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *QuotaConfig) validate() error {
+	if c.Threshold <= 0 || c.Threshold > 100 {
+		return errors.New("Error in quota config: threshold must be > 0 and <= 100")
+	}
+
+	for id, cc := range c.Clients {
+		for _, t := range []string{quotaProduce, quotaFetch} {
+			l := cc.limits(t)
+			if l == nil {
+				continue
+			}
+
+			if l.Min <= 0 || l.Max < l.Min {
+				return fmt.Errorf("Error in quota config: %s quota for client %s must have 0 < min <= max", t, id)
+			}
+		}
+	}
+
+	return nil
+}
+
+// QuotaManager dynamically applies client quotas based on broker
+// network utilization. When the most utilized broker exceeds the
+// configured threshold of its capacity, the quotas of all managed
+// clients are scaled down by the ratio of the threshold to the
+// utilization (to no less than the client minimum). As utilization
+// recedes, quotas are scaled up and are removed once they reach
+// the client maximum. Inbound utilization determines produce quotas
+// and outbound utilization determines fetch quotas.
+type QuotaManager struct {
+	// Quotas are applied via ZooKeeper.
+	configs ConfigUpdater
+	events  *EventGenerator
+	limits  Limits
+	config  *QuotaConfig
+	// Min percent change
+	// before updating quotas.
+	changeThreshold float64
+	// Applied quotas in MB/s by
+	// quota type and client ID.
+	quotas map[string]map[string]float64
+	// Whether quotas may be set; true on
+	// startup since quotas may remain from
+	// a previous autothrottle session.
+	known bool
+}
+
+// NewQuotaManager returns a *QuotaManager.
+func NewQuotaManager(cu ConfigUpdater, e *EventGenerator, l Limits, c *QuotaConfig, changeThreshold float64) *QuotaManager {
+	return &QuotaManager{
+		configs:         cu,
+		events:          e,
+		limits:          l,
+		config:          c,
+		changeThreshold: changeThreshold,
+		quotas: map[string]map[string]float64{
+			quotaProduce: map[string]float64{},
+			quotaFetch:   map[string]float64{},
+		},
+		known: true,
+	}
+}
+
+// manages returns whether quotas of type t are managed for any client.
+func (q *QuotaManager) manages(t string) bool {
+	for _, c := range q.config.Clients {
+		if c.limits(t) != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// utilization returns the most utilized broker and its utilization as a
+// percentage of capacity, for inbound (produce) or outbound (fetch) traffic.
+// Brokers with an unknown capacity are skipped.
+func (q *QuotaManager) utilization(bm kafkametrics.BrokerMetrics, t string) (*kafkametrics.Broker, float64) {
+	var ids []int
+	for id := range bm {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	var highest *kafkametrics.Broker
+	var util float64
+
+	for _, id := range ids {
+		b := bm[id]
+		capacity, known := q.limits.capacity(b)
+		if !known || capacity <= 0 {
+			continue
+		}
+
+		v := b.NetTX
+		if t == quotaProduce {
+			v = b.NetRX
+		}
+
+		if u := v / capacity * 100; highest == nil || u > util {
+			highest, util = b, u
+		}
+	}
+
+	return highest, util
+}
+
+// Update takes the current kafkametrics.BrokerMetrics and updates the
+// quotas of all managed clients.
+func (q *QuotaManager) Update(bm kafkametrics.BrokerMetrics) error {
+	if len(bm) == 0 {
+		return errors.New("Error updating client quotas: no broker metrics")
+	}
+
+	type change struct {
+		prev, curr float64
+	}
+
+	// Map of client ID to quota type to changes.
+	changes := map[string]map[string]change{}
+	var reasons []string
+
+	for _, t := range []string{quotaProduce, quotaFetch} {
+		if !q.manages(t) {
+			continue
+		}
+
+		b, util := q.utilization(bm, t)
+		if b == nil {
+			return fmt.Errorf("Error updating %s quotas: no brokers with a known capacity", t)
+		}
+
+		direction := "outbound"
+		if t == quotaProduce {
+			direction = "inbound"
+		}
+
+		over := util > q.config.Threshold
+		if over || len(q.quotas[t]) > 0 {
+			reasons = append(reasons, fmt.Sprintf("Most utilized broker (%s): %d at %.2f%% of capacity (threshold %.0f%%)",
+				direction, b.ID, util, q.config.Threshold))
+		}
+
+		for id, cc := range q.config.Clients {
+			l := cc.limits(t)
+			if l == nil {
+				continue
+			}
+
+			prev, set := q.quotas[t][id]
+			if !set && !over {
+				continue
+			}
+
+			base := prev
+			if !set {
+				base = l.Max
+			}
+
+			curr := math.Min(math.Max(base*q.config.Threshold/math.Max(util, 1), l.Min), l.Max)
+
+			switch {
+			// Quotas that are no longer
+			// constraining are removed.
+			case !over && curr >= l.Max:
+				curr = 0
+			// Skip minor changes.
+			case set && math.Abs(curr-prev)/prev*100 < q.changeThreshold:
+				continue
+			}
+
+			if curr == prev {
+				continue
+			}
+
+			if changes[id] == nil {
+				changes[id] = map[string]change{}
+			}
+
+			changes[id][t] = change{prev: prev, curr: curr}
+		}
+	}
+
+	// Quotas may remain from a previous session;
+	// remove any while no quotas are needed.
+	if q.known && len(changes) == 0 && len(q.quotas[quotaProduce]) == 0 && len(q.quotas[quotaFetch]) == 0 {
+		return q.RemoveAll()
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var clients []string
+	for id := range changes {
+		clients = append(clients, id)
+	}
+
+	sort.Strings(clients)
+
+	var b bytes.Buffer
+	var errs []string
+
+	for _, id := range clients {
+		config := kafkazk.KafkaConfig{Type: "client", Name: id}
+		for t, c := range changes[id] {
+			v := ""
+			if c.curr > 0 {
+				v = fmt.Sprintf("%.0f", c.curr*1000000.00)
+			}
+			config.Configs = append(config.Configs, [2]string{quotaConfigKeys[t], v})
+		}
+
+		sort.Slice(config.Configs, func(i, j int) bool { return config.Configs[i][0] < config.Configs[j][0] })
+
+		if _, err := q.configs.UpdateKafkaConfig(config); err != nil {
+			errs = append(errs, fmt.Sprintf("Error setting quotas for client %s: %s", id, err))
+			continue
+		}
+
+		q.known = true
+
+		for _, t := range []string{quotaProduce, quotaFetch} {
+			c, changed := changes[id][t]
+			if !changed {
+				continue
+			}
+
+			if c.curr == 0 {
+				delete(q.quotas[t], id)
+				metrics.Set(metricClientQuota, 0, "client", id, "type", t)
+				b.WriteString(fmt.Sprintf("Removed %s quota of %.2fMB/s for client %s\n", t, c.prev, id))
+				continue
+			}
+
+			q.quotas[t][id] = c.curr
+			metrics.Set(metricClientQuota, c.curr*1000000.00, "client", id, "type", t)
+
+			if c.prev == 0 {
+				b.WriteString(fmt.Sprintf("Set %s quota of %.2fMB/s for client %s\n", t, c.curr, id))
+			} else {
+				b.WriteString(fmt.Sprintf("Updated %s quota from %.2fMB/s to %.2fMB/s for client %s\n", t, c.prev, c.curr, id))
+			}
+		}
+	}
+
+	if b.Len() > 0 {
+		for _, r := range reasons {
+			b.WriteString(r + "\n")
+		}
+
+		log.Print(b.String())
+		q.events.Write("Client quotas updated", b.String())
+	}
+
+	if len(errs) > 0 {
+		return errors.New(fmt.Sprint(errs))
+	}
+
+	return nil
+}
+
+// RemoveAll removes the quotas of all managed clients.
+func (q *QuotaManager) RemoveAll() error {
+	var clients []string
+	for id := range q.config.Clients {
+		clients = append(clients, id)
+	}
+
+	sort.Strings(clients)
+
+	var removed []string
+	var errs []string
+
+	for _, id := range clients {
+		config := kafkazk.KafkaConfig{Type: "client", Name: id}
+		for _, t := range []string{quotaFetch, quotaProduce} {
+			if q.config.Clients[id].limits(t) != nil {
+				config.Configs = append(config.Configs, [2]string{quotaConfigKeys[t], ""})
+			}
+		}
+
+		changed, err := q.configs.UpdateKafkaConfig(config)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Error removing quotas for client %s: %s", id, err))
+			continue
+		}
+
+		for _, t := range []string{quotaProduce, quotaFetch} {
+			delete(q.quotas[t], id)
+			metrics.Set(metricClientQuota, 0, "client", id, "type", t)
+		}
+
+		if changed {
+			removed = append(removed, id)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(fmt.Sprint(errs))
+	}
+
+	q.known = false
+
+	if len(removed) > 0 {
+		m := fmt.Sprintf("Client quotas removed for clients: %v", removed)
+		log.Println(m)
+		q.events.Write("Client quotas removed", m)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// configZK stores Kafka configs
+// applied with UpdateKafkaConfig.
+type configZK struct {
+	kafkazk.Mock
+	// Map of type/name to config key to value.
+	configs map[string]map[string]string
+}
+
+func (zk *configZK) UpdateKafkaConfig(c kafkazk.KafkaConfig) (bool, error) {
+	k := c.Type + "/" + c.Name
+	if zk.configs[k] == nil {
+		zk.configs[k] = map[string]string{}
+	}
+
+	var changed bool
+	for _, kv := range c.Configs {
+		if zk.configs[k][kv[0]] == kv[1] {
+			continue
+		}
+
+		changed = true
+		if kv[1] == "" {
+			delete(zk.configs[k], kv[0])
+		} else {
+			zk.configs[k][kv[0]] = kv[1]
+		}
+	}
+
+	return changed, nil
+}
+
+func TestLoadQuotaConfig(t *testing.T) {
+	f, err := ioutil.TempFile("", "autothrottle-quota")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+
+	f.WriteString(`{"threshold": 80, "clients": {"loader": {"produce": {"min": 10, "max": 100}}}}`)
+	f.Close()
+
+	qc, err := loadQuotaConfig(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if qc.Threshold != 80 || qc.Clients["loader"].Produce.Max != 100 || qc.Clients["loader"].Fetch != nil {
+		t.Errorf("Unexpected config %+v", qc)
+	}
+
+	invalid := []*QuotaConfig{
+		&QuotaConfig{Threshold: 0},
+		&QuotaConfig{Threshold: 101},
+		&QuotaConfig{Threshold: 80, Clients: map[string]ClientQuotaConfig{
+			"loader": ClientQuotaConfig{Fetch: &QuotaLimits{Min: 0, Max: 100}},
+		}},
+		&QuotaConfig{Threshold: 80, Clients: map[string]ClientQuotaConfig{
+			"loader": ClientQuotaConfig{Fetch: &QuotaLimits{Min: 50, Max: 10}},
+		}},
+	}
+
+	for _, c := range invalid {
+		if err := c.validate(); err == nil {
+			t.Errorf("Expected non-nil error for %+v", c)
+		}
+	}
+}
+
+func TestQuotaManagerUpdate(t *testing.T) {
+	zk := &configZK{configs: map[string]map[string]string{}}
+	events := &EventGenerator{c: make(chan *kafkametrics.Event, 10)}
+	lim, _ := NewLimits(NewLimitsConfig{Minimum: 10, Maximum: 90, CapacityMap: map[string]float64{"mock": 100}})
+
+	qc := &QuotaConfig{
+		Threshold: 80,
+		Clients: map[string]ClientQuotaConfig{
+			"loader": ClientQuotaConfig{
+				Produce: &QuotaLimits{Min: 10, Max: 100},
+				Fetch:   &QuotaLimits{Min: 20, Max: 50},
+			},
+		},
+	}
+
+	q := NewQuotaManager(zk, events, lim, qc, 10)
+
+	bm := func(tx, rx float64) kafkametrics.BrokerMetrics {
+		return kafkametrics.BrokerMetrics{
+			1001: &kafkametrics.Broker{ID: 1001, InstanceType: "mock", NetTX: tx, NetRX: rx},
+			1002: &kafkametrics.Broker{ID: 1002, InstanceType: "mock", NetTX: tx / 2, NetRX: rx / 2},
+		}
+	}
+
+	// Below the threshold.
+	if err := q.Update(bm(50, 50)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(zk.configs["client/loader"]) != 0 || q.known {
+		t.Errorf("Unexpected quotas %v", zk.configs["client/loader"])
+	}
+
+	// Inbound over the threshold; a produce quota
+	// of 100 * 80/100 is set.
+	q.Update(bm(50, 100))
+
+	if v := zk.configs["client/loader"]["producer_byte_rate"]; v != "80000000" {
+		t.Errorf("Expected produce quota 80000000, got %s", v)
+	}
+
+	if _, exists := zk.configs["client/loader"]["consumer_byte_rate"]; exists {
+		t.Error("Unexpected fetch quota")
+	}
+
+	// Still over; scaled to 80 * 80/160,
+	// clamped to the min of 10.
+	q.Update(bm(50, 640))
+
+	if v := zk.configs["client/loader"]["producer_byte_rate"]; v != "10000000" {
+		t.Errorf("Expected produce quota 10000000, got %s", v)
+	}
+
+	// Minor changes are skipped: 10 -> 10.4.
+	q.Update(bm(50, 77))
+
+	if v := q.quotas[quotaProduce]["loader"]; v != 10 {
+		t.Errorf("Expected produce quota 10, got %f", v)
+	}
+
+	// Utilization receded; scaled up
+	// to 10 * 80/20.
+	q.Update(bm(50, 20))
+
+	if v := q.quotas[quotaProduce]["loader"]; v != 40 {
+		t.Errorf("Expected produce quota 40, got %f", v)
+	}
+
+	// Reaches the max and is removed.
+	q.Update(bm(50, 10))
+
+	if _, exists := zk.configs["client/loader"]["producer_byte_rate"]; exists {
+		t.Error("Expected produce quota to be removed")
+	}
+
+	if len(q.quotas[quotaProduce]) != 0 {
+		t.Errorf("Unexpected quotas %v", q.quotas)
+	}
+
+	if err := q.Update(kafkametrics.BrokerMetrics{}); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
+
+func TestQuotaManagerRemoveAll(t *testing.T) {
+	zk := &configZK{configs: map[string]map[string]string{
+		"client/loader": map[string]string{"producer_byte_rate": "1000000", "other": "x"},
+	}}
+
+	events := &EventGenerator{c: make(chan *kafkametrics.Event, 10)}
+	qc := &QuotaConfig{
+		Threshold: 80,
+		Clients: map[string]ClientQuotaConfig{
+			"loader": ClientQuotaConfig{Produce: &QuotaLimits{Min: 10, Max: 100}},
+		},
+	}
+
+	q := NewQuotaManager(zk, events, Limits{}, qc, 10)
+	if err := q.RemoveAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(zk.configs["client/loader"]) != 1 {
+		t.Errorf("Unexpected configs %v", zk.configs["client/loader"])
+	}
+
+	if len(events.c) != 1 {
+		t.Errorf("Expected 1 event, got %d", len(events.c))
+	}
+}
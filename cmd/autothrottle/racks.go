@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// RackLimits is a map of rack IDs to the ceiling (MB/s)
+// of the aggregate replication throttles of the brokers
+// in the rack, e.g. to respect inter-AZ link budgets.
+type RackLimits map[string]float64
+
+// parseRackLimits takes a JSON map of rack ID
+// to throttle ceiling and returns a RackLimits.
+func parseRackLimits(s string) (RackLimits, error) {
+	rl := RackLimits{}
+	if err := json.Unmarshal([]byte(s), &rl); err != nil {
+		return nil, err
+	}
+
+	for rack, c := range rl {
+		if c <= 0 {
+			return nil, fmt.Errorf("Ceiling for rack %s must be > 0", rack)
+		}
+	}
+
+	return rl, nil
+}
+
+// brokerRacks returns a map of broker IDs
+// to rack IDs for brokers with a rack set.
+func brokerRacks(zk kafkazk.Handler) (map[int]string, error) {
+	meta, errs := zk.GetAllBrokerMeta(false)
+	if errs != nil {
+		return nil, fmt.Errorf("Error fetching broker racks: %v", errs)
+	}
+
+	racks := map[int]string{}
+	for id, m := range meta {
+		if m.Rack != "" {
+			racks[id] = m.Rack
+		}
+	}
+
+	return racks, nil
+}
+
+// apply takes a bmapBundle, a map of broker IDs to racks, the per broker
+// rates (or nil if shared), the shared leader and follower throttles and
+// any broker overrides. In each rack with a ceiling, the leader throttles
+// of the source brokers and the follower throttles of the destination
+// brokers are each scaled proportionally so that their sum doesn't exceed
+// the ceiling. Overridden brokers are excluded. The resulting brokerRates
+// are returned with an event string for each scaled rack, or nil if no
+// brokers were scaled.
+func (rl RackLimits) apply(bmb bmapBundle, racks map[int]string, rates brokerRates, leader, follower float64, overrides BrokerOverrides) (brokerRates, []string) {
+	out := brokerRates{}
+	for id := range bmb.all {
+		r, fr := leader, follower
+		if br, exists := rates[id]; exists {
+			r, fr = br[0], br[1]
+		}
+
+		out[id] = [2]float64{r, fr}
+	}
+
+	// Brokers subject to the ceiling of each rack.
+	src, dst := map[string][]int{}, map[string][]int{}
+	for id := range bmb.all {
+		rack, exists := racks[id]
+		if _, limited := rl[rack]; !exists || !limited {
+			continue
+		}
+
+		if _, overridden := overrides[id]; overridden {
+			continue
+		}
+
+		if _, exists := bmb.src[id]; exists {
+			src[rack] = append(src[rack], id)
+		}
+
+		if _, exists := bmb.dst[id]; exists {
+			dst[rack] = append(dst[rack], id)
+		}
+	}
+
+	var names []string
+	for rack := range rl {
+		names = append(names, rack)
+	}
+
+	sort.Strings(names)
+
+	var events []string
+
+	// Index 0 is the leader throttle,
+	// 1 is the follower throttle.
+	for _, rack := range names {
+		for i, ids := range [2][]int{src[rack], dst[rack]} {
+			var sum float64
+			for _, id := range ids {
+				sum += out[id][i]
+			}
+
+			if sum <= rl[rack] {
+				continue
+			}
+
+			scale := rl[rack] / sum
+			for _, id := range ids {
+				br := out[id]
+				br[i] *= scale
+				out[id] = br
+			}
+
+			sort.Ints(ids)
+
+			t := "leader"
+			if i == 1 {
+				t = "follower"
+			}
+
+			events = append(events, fmt.Sprintf("Rack %s %s throttles scaled from an aggregate of %.2fMB/s to the %.2fMB/s rack ceiling on brokers: %v",
+				rack, t, sum, rl[rack], ids))
+		}
+	}
+
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	return out, events
+}
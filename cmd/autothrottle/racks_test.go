@@ -0,0 +1,92 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+func TestParseRackLimits(t *testing.T) {
+	rl, err := parseRackLimits(`{"a": 500, "b": 250}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rl) != 2 || rl["a"] != 500 {
+		t.Errorf("Unexpected rack limits %v", rl)
+	}
+
+	for _, s := range []string{`{"a": 0}`, `{"a": -1}`, `["a"]`} {
+		if _, err := parseRackLimits(s); err == nil {
+			t.Errorf("Expected non-nil error for %s", s)
+		}
+	}
+}
+
+func TestBrokerRacks(t *testing.T) {
+	racks, err := brokerRacks(&kafkazk.Mock{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(racks) != 5 || racks[1001] != "a" || racks[1005] != "b" {
+		t.Errorf("Unexpected broker racks %v", racks)
+	}
+}
+
+func TestRackLimitsApply(t *testing.T) {
+	rl := RackLimits{"a": 150, "b": 500}
+
+	// Sources are 1000-1004,
+	// destinations 1005-1009.
+	bmb := mockBmapBundle()
+	racks := map[int]string{
+		1000: "a", 1001: "a", 1005: "a", 1006: "a",
+		1002: "b", 1003: "b",
+		1008: "c",
+	}
+
+	rates, events := rl.apply(bmb, racks, nil, 100, 80, nil)
+	if len(events) != 2 {
+		t.Errorf("Expected 2 events, got %v", events)
+	}
+
+	expected := brokerRates{
+		// Rack a leaders (200MB/s)
+		// and followers (160MB/s).
+		1000: {75, 80},
+		1001: {75, 80},
+		1005: {100, 75},
+		1006: {100, 75},
+		// Rack b is within the ceiling.
+		1002: {100, 80},
+		// No ceiling.
+		1004: {100, 80},
+		1008: {100, 80},
+	}
+
+	for id, r := range expected {
+		if rates[id] != r {
+			t.Errorf("Expected rates %v for broker %d, got %v", r, id, rates[id])
+		}
+	}
+
+	// Per broker rates are scaled
+	// proportionally.
+	br := brokerRates{}
+	for id := range bmb.all {
+		br[id] = [2]float64{100, 80}
+	}
+	br[1000] = [2]float64{200, 80}
+
+	rates, _ = rl.apply(bmb, racks, br, 0, 0, nil)
+	if rates[1000][0] != 100 || rates[1001][0] != 50 {
+		t.Errorf("Unexpected rates %v, %v", rates[1000], rates[1001])
+	}
+
+	// Overridden brokers are excluded.
+	rates, events = rl.apply(bmb, racks, nil, 100, 80, BrokerOverrides{1001: {Rate: 50}, 1006: {Rate: 50}})
+	if rates != nil || events != nil {
+		t.Errorf("Expected no scaled rates, got %v", events)
+	}
+}
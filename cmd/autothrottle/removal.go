@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// RemovalCheck tracks the partitions of completed reassignments
+// and determines whether throttles can be safely removed. Removing
+// throttles while a reassigned replica is still catching up (e.g.
+// the reassignment znode was cleared but the follower dropped out of
+// the ISR) releases unthrottled catch-up traffic on the cluster.
+type RemovalCheck struct {
+	zk kafkazk.Handler
+	// Whether the ISR state of
+	// completed partitions is verified.
+	verifyISR bool
+	// Time to wait once all partitions
+	// are in-sync before removing throttles.
+	settle time.Duration
+
+	// Partitions of completed reassignments.
+	completed kafkazk.Reassignments
+	// When all completed partitions were
+	// first seen in-sync.
+	inSyncSince time.Time
+}
+
+// NewRemovalCheck returns a *RemovalCheck.
+func NewRemovalCheck(zk kafkazk.Handler, verifyISR bool, settle time.Duration) *RemovalCheck {
+	return &RemovalCheck{
+		zk:        zk,
+		verifyISR: verifyISR,
+		settle:    settle,
+		completed: kafkazk.Reassignments{},
+	}
+}
+
+// Add registers the partitions and target
+// replica sets of a completed reassignment.
+func (r *RemovalCheck) Add(t string, partitions map[int][]int) {
+	if r.completed[t] == nil {
+		r.completed[t] = map[int][]int{}
+	}
+
+	for p, replicas := range partitions {
+		r.completed[t][p] = replicas
+	}
+
+	// The settle period restarts.
+	r.inSyncSince = time.Time{}
+}
+
+// Reset clears all completed partitions,
+// e.g. once throttles have been removed.
+func (r *RemovalCheck) Reset() {
+	r.completed = kafkazk.Reassignments{}
+	r.inSyncSince = time.Time{}
+}
+
+// Ready takes the current time and returns whether throttles can be
+// removed, along with a description of why not. An error is returned
+// if the ISR state can't be determined, in which case throttles should
+// be retained.
+func (r *RemovalCheck) Ready(now time.Time) (bool, string, error) {
+	if len(r.completed) == 0 {
+		return true, "", nil
+	}
+
+	if r.verifyISR {
+		oos, err := r.outOfSync()
+		if err != nil {
+			return false, "", err
+		}
+
+		if len(oos) > 0 {
+			r.inSyncSince = time.Time{}
+			return false, fmt.Sprintf("Retaining throttles, %d reassigned partitions aren't in-sync: %s",
+				len(oos), oos), nil
+		}
+	}
+
+	if r.inSyncSince.IsZero() {
+		r.inSyncSince = now
+	}
+
+	if remaining := r.settle - now.Sub(r.inSyncSince); remaining > 0 {
+		return false, fmt.Sprintf("Retaining throttles, settle period ends in %s", remaining.Round(time.Second)), nil
+	}
+
+	return true, "", nil
+}
+
+// outOfSync returns all completed partitions where a target
+// replica isn't in the ISR. Topics that no longer exist are removed.
+func (r *RemovalCheck) outOfSync() (kafkazk.OutOfSyncPartitions, error) {
+	var topics []string
+	for t := range r.completed {
+		topics = append(topics, t)
+	}
+
+	sort.Strings(topics)
+
+	var oos kafkazk.OutOfSyncPartitions
+
+	for _, t := range topics {
+		pm := kafkazk.NewPartitionMap()
+		for p, replicas := range r.completed[t] {
+			pm.Partitions = append(pm.Partitions, kafkazk.Partition{Topic: t, Partition: p, Replicas: replicas})
+		}
+
+		sort.Sort(pm.Partitions)
+
+		o, err := pm.OutOfSync(r.zk, nil)
+		if err != nil {
+			// The topic may have been deleted.
+			re := regexp.MustCompile(fmt.Sprintf("^%s$", regexp.QuoteMeta(t)))
+			existing, terr := r.zk.GetTopics([]*regexp.Regexp{re})
+			if terr == nil && len(existing) == 0 {
+				delete(r.completed, t)
+				continue
+			}
+
+			return nil, fmt.Errorf("Error checking ISR state of topic %s: %s", t, err)
+		}
+
+		oos = append(oos, o...)
+	}
+
+	return oos, nil
+}
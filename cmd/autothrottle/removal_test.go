@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// isrZK returns an error fetching the
+// ISR state of topics that don't exist.
+type isrZK struct {
+	kafkazk.Mock
+}
+
+func (zk *isrZK) GetTopicStateISR(t string) (kafkazk.TopicStateISR, error) {
+	if t == "deleted_topic" {
+		return nil, errors.New("node does not exist")
+	}
+
+	return zk.Mock.GetTopicStateISR(t)
+}
+
+func (zk *isrZK) GetTopics(ts []*regexp.Regexp) ([]string, error) {
+	var matched []string
+	for _, t := range []string{"test_topic", "missing_state"} {
+		for _, re := range ts {
+			if re.MatchString(t) {
+				matched = append(matched, t)
+			}
+		}
+	}
+
+	return matched, nil
+}
+
+func TestRemovalCheck(t *testing.T) {
+	r := NewRemovalCheck(&isrZK{}, true, 0)
+	now := time.Now()
+
+	if ready, _, err := r.Ready(now); !ready || err != nil {
+		t.Errorf("Expected ready with no completed reassignments, got %v, %v", ready, err)
+	}
+
+	// Mock ISRs: p0 [1000 1002], p1 [1002 1003].
+	r.Add("test_topic", map[int][]int{0: []int{1000, 1002}})
+	r.Add("deleted_topic", map[int][]int{0: []int{1000, 1002}})
+
+	if ready, _, err := r.Ready(now); !ready || err != nil {
+		t.Errorf("Expected ready with in-sync partitions, got %v, %v", ready, err)
+	}
+
+	if _, exists := r.completed["deleted_topic"]; exists {
+		t.Error("Expected deleted topic to be removed")
+	}
+
+	r.Add("test_topic", map[int][]int{1: []int{1002, 1004}})
+
+	ready, m, err := r.Ready(now)
+	if ready || err != nil {
+		t.Errorf("Expected not ready with lagging partitions, got %v, %v", ready, err)
+	}
+
+	if !strings.Contains(m, "test_topic p1") {
+		t.Errorf("Unexpected message '%s'", m)
+	}
+
+	r.Reset()
+	if ready, _, _ := r.Ready(now); !ready {
+		t.Error("Expected ready after reset")
+	}
+
+	// Topics that exist but where the
+	// state can't be fetched are errors.
+	r.Add("missing_state", map[int][]int{9: []int{1000}})
+	if _, _, err := r.Ready(now); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
+
+func TestRemovalCheckSettle(t *testing.T) {
+	r := NewRemovalCheck(&isrZK{}, false, time.Minute)
+	now := time.Now()
+
+	// Lagging partitions aren't
+	// checked without verifyISR.
+	r.Add("test_topic", map[int][]int{1: []int{1002, 1004}})
+
+	if ready, _, _ := r.Ready(now); ready {
+		t.Error("Expected not ready during settle period")
+	}
+
+	if ready, m, _ := r.Ready(now.Add(30 * time.Second)); ready || m != "Retaining throttles, settle period ends in 30s" {
+		t.Errorf("Unexpected result %v, '%s'", ready, m)
+	}
+
+	if ready, _, _ := r.Ready(now.Add(time.Minute)); !ready {
+		t.Error("Expected ready after settle period")
+	}
+
+	// Newly completed reassignments
+	// restart the settle period.
+	r.Add("test_topic", map[int][]int{0: []int{1000, 1002}})
+	if ready, _, _ := r.Ready(now.Add(time.Minute)); ready {
+		t.Error("Expected not ready during settle period")
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// Actions taken on throttles at shutdown.
+const (
+	shutdownFreeze   = "freeze"
+	shutdownClear    = "clear"
+	shutdownFallback = "fallback"
+)
+
+// shutdownFlushTimeout is how long pending
+// events are written for on shutdown.
+var shutdownFlushTimeout = 10 * time.Second
+
+// ShutdownConfig holds the action taken
+// on applied throttles at shutdown.
+type ShutdownConfig struct {
+	Action string
+	// Throttle rate (MB/s) applied
+	// with the fallback action.
+	FallbackRate float64
+}
+
+func (c ShutdownConfig) validate() error {
+	switch c.Action {
+	case shutdownFreeze, shutdownClear:
+	case shutdownFallback:
+		if c.FallbackRate <= 0 {
+			return errors.New("shutdown-fallback-rate must be > 0")
+		}
+	default:
+		return fmt.Errorf("Unknown shutdown action %s", c.Action)
+	}
+
+	return nil
+}
+
+// shutdownThrottles takes a kafkazk.Handler, *ReplicationThrottleMeta and
+// ShutdownConfig and applies the shutdown action to the throttles set by
+// autothrottle: throttles are left in place (freeze), removed (clear), or
+// set to the fallback rate on each throttled broker (fallback). Brokers
+// with a throttle override retain the override rate. A description of the
+// action taken is returned.
+func shutdownThrottles(zk kafkazk.Handler, params *ReplicationThrottleMeta, c ShutdownConfig) (string, error) {
+	throttled := map[int]struct{}{}
+	for _, rates := range []map[int]float64{params.throttles, params.followerThrottles} {
+		for id, r := range rates {
+			// Removed throttles are stored as 0.
+			if r > 0 {
+				throttled[id] = struct{}{}
+			}
+		}
+	}
+
+	var ids []int
+	for id := range throttled {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	switch {
+	case c.Action == shutdownClear:
+		if err := removeAllThrottles(zk, params); err != nil {
+			return "", fmt.Errorf("Error clearing throttles on shutdown: %s", err)
+		}
+
+		return "Throttles cleared on shutdown", nil
+	case len(ids) == 0:
+		return "No throttles set by autothrottle on shutdown", nil
+	case c.Action == shutdownFallback:
+		prevRates := params.currentRates(throttled)
+
+		errs := applyBrokerThrottles(throttled, c.FallbackRate, c.FallbackRate, nil, params, params.configs)
+		for _, e := range errs {
+			log.Println(e)
+		}
+
+		if errs != nil {
+			return "", errors.New("Error setting fallback throttles on shutdown")
+		}
+
+		m := fmt.Sprintf("Throttles set to the %.2fMB/s fallback rate on shutdown on brokers: %v", c.FallbackRate, ids)
+		auditThrottleChanges(params, prevRates, []string{m})
+
+		return m, nil
+	}
+
+	return fmt.Sprintf("Throttles left in place on shutdown on brokers: %v", ids), nil
+}
+
+// shutdown takes a kafkazk.Handler, *ReplicationThrottleMeta, ShutdownConfig
+// and the reason throttles are left in place regardless of the configured
+// action, if any (e.g. the instance is a standby). The shutdown action is
+// applied, and the action taken is logged and written as an event.
+func shutdown(zk kafkazk.Handler, params *ReplicationThrottleMeta, c ShutdownConfig, reason string) {
+	var m string
+	var err error
+
+	if reason != "" {
+		m = fmt.Sprintf("Throttles left in place on shutdown: %s", reason)
+	} else {
+		m, err = shutdownThrottles(zk, params, c)
+	}
+
+	if err != nil {
+		m = err.Error()
+	}
+
+	log.Println(m)
+	params.events.Write("Autothrottle shutdown", m)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+func TestShutdownConfigValidate(t *testing.T) {
+	tests := map[ShutdownConfig]bool{
+		ShutdownConfig{Action: shutdownFreeze}:                      true,
+		ShutdownConfig{Action: shutdownClear}:                       true,
+		ShutdownConfig{Action: shutdownFallback, FallbackRate: 50}:  true,
+		ShutdownConfig{Action: shutdownFallback}:                    false,
+		ShutdownConfig{Action: shutdownFallback, FallbackRate: -10}: false,
+		ShutdownConfig{Action: "remove"}:                            false,
+	}
+
+	for c, valid := range tests {
+		if err := c.validate(); (err == nil) != valid {
+			t.Errorf("Unexpected validation of %+v: %v", c, err)
+		}
+	}
+}
+
+func TestShutdownThrottles(t *testing.T) {
+	delay := configWriteDelay
+	configWriteDelay = 0
+	defer func() { configWriteDelay = delay }()
+
+	newParams := func() (*configZK, *ReplicationThrottleMeta) {
+		zk := &configZK{configs: map[string]map[string]string{}}
+		params := &ReplicationThrottleMeta{
+			throttles:         map[int]float64{1001: 100, 1002: 0},
+			followerThrottles: map[int]float64{1001: 80, 1002: 0, 1003: 60},
+			brokerOverrides:   BrokerOverrides{1003: BrokerOverrideConfig{Rate: 20}},
+			events:            &EventGenerator{c: make(chan *kafkametrics.Event, 10)},
+			configs:           zk,
+		}
+
+		zk.UpdateKafkaConfig(kafkazk.KafkaConfig{
+			Type: "broker",
+			Name: "1001",
+			Configs: [][2]string{
+				[2]string{"leader.replication.throttled.rate", "100000000"},
+				[2]string{"follower.replication.throttled.rate", "80000000"},
+			},
+		})
+
+		return zk, params
+	}
+
+	// Freeze.
+	zk, params := newParams()
+
+	m, err := shutdownThrottles(zk, params, ShutdownConfig{Action: shutdownFreeze})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "Throttles left in place on shutdown on brokers: [1001 1003]"; m != expected {
+		t.Errorf("Expected message '%s', got '%s'", expected, m)
+	}
+
+	if zk.configs["broker/1001"]["leader.replication.throttled.rate"] != "100000000" {
+		t.Errorf("Unexpected configs %v", zk.configs["broker/1001"])
+	}
+
+	// Fallback.
+	zk, params = newParams()
+
+	m, err = shutdownThrottles(zk, params, ShutdownConfig{Action: shutdownFallback, FallbackRate: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "Throttles set to the 50.00MB/s fallback rate on shutdown on brokers: [1001 1003]"; m != expected {
+		t.Errorf("Expected message '%s', got '%s'", expected, m)
+	}
+
+	// Overridden brokers retain the override rate.
+	expected := map[string]string{"broker/1001": "50000000", "broker/1003": "20000000"}
+	for k, rate := range expected {
+		for _, c := range []string{"leader.replication.throttled.rate", "follower.replication.throttled.rate"} {
+			if zk.configs[k][c] != rate {
+				t.Errorf("Expected %s %s %s, got %s", k, c, rate, zk.configs[k][c])
+			}
+		}
+	}
+
+	if _, exists := zk.configs["broker/1002"]; exists {
+		t.Errorf("Unexpected configs for broker 1002: %v", zk.configs["broker/1002"])
+	}
+
+	// Clear.
+	zk, params = newParams()
+
+	m, err = shutdownThrottles(zk, params, ShutdownConfig{Action: shutdownClear})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "Throttles cleared on shutdown"; m != expected {
+		t.Errorf("Expected message '%s', got '%s'", expected, m)
+	}
+
+	if len(zk.configs["broker/1001"]) != 0 {
+		t.Errorf("Unexpected configs %v", zk.configs["broker/1001"])
+	}
+
+	if params.throttles[1001] != 0 || params.followerThrottles[1003] != 0 {
+		t.Errorf("Unexpected stored throttles %v %v", params.throttles, params.followerThrottles)
+	}
+
+	// Nothing is throttled.
+	zk, params = newParams()
+	params.throttles, params.followerThrottles = map[int]float64{}, map[int]float64{}
+
+	m, _ = shutdownThrottles(zk, params, ShutdownConfig{Action: shutdownFallback, FallbackRate: 50})
+	if expected := "No throttles set by autothrottle on shutdown"; m != expected {
+		t.Errorf("Expected message '%s', got '%s'", expected, m)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	c := make(chan *kafkametrics.Event, 10)
+	zk := &configZK{configs: map[string]map[string]string{}}
+	params := &ReplicationThrottleMeta{
+		throttles:         map[int]float64{1001: 100},
+		followerThrottles: map[int]float64{1001: 100},
+		events:            &EventGenerator{c: c, titlePrefix: "autothrottle"},
+		configs:           zk,
+	}
+
+	// The configured action isn't applied
+	// if a reason is given.
+	shutdown(zk, params, ShutdownConfig{Action: shutdownClear}, "instance is a standby")
+
+	if params.throttles[1001] != 100 {
+		t.Errorf("Unexpected stored throttles %v", params.throttles)
+	}
+
+	e := <-c
+	if e.Title != "[autothrottle] Autothrottle shutdown" {
+		t.Errorf("Unexpected event title %s", e.Title)
+	}
+
+	if expected := "Throttles left in place on shutdown: instance is a standby"; e.Text != expected {
+		t.Errorf("Expected event text '%s', got '%s'", expected, e.Text)
+	}
+}
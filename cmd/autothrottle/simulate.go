@@ -0,0 +1,392 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// simulateCommand is the subcommand that replays
+// a metrics timeline through the throttle controller.
+const simulateCommand = "simulate"
+
+// simulatedTopic is the topic that simulated
+// reassignments are attributed to.
+const simulatedTopic = "simulated"
+
+// timelineRecord is the utilization of a broker at a point in a metrics
+// timeline. Fields match the Honeycomb interval event fields, so that
+// interval events can be replayed. Rates are in bytes/s.
+type timelineRecord struct {
+	Time         time.Time `json:"time"`
+	Broker       int       `json:"broker"`
+	Source       bool      `json:"source"`
+	Destination  bool      `json:"destination"`
+	InstanceType string    `json:"instance_type"`
+	Rack         string    `json:"rack"`
+	NetTX        float64   `json:"net_tx_bytes_per_second"`
+	NetRX        float64   `json:"net_rx_bytes_per_second"`
+	DiskUtil     float64   `json:"disk_util_percent"`
+	Capacity     float64   `json:"capacity_bytes_per_second"`
+	// Throttles applied when recorded.
+	LeaderRate   float64 `json:"leader_rate_bytes_per_second"`
+	FollowerRate float64 `json:"follower_rate_bytes_per_second"`
+}
+
+// timelineSample is the utilization
+// of all brokers at a point in time.
+type timelineSample struct {
+	time    time.Time
+	records []timelineRecord
+}
+
+// readTimeline reads a metrics timeline of JSON lines, each a
+// timelineRecord. Records are grouped into samples by time, ordered
+// oldest first.
+func readTimeline(r io.Reader) ([]timelineSample, error) {
+	byTime := map[time.Time][]timelineRecord{}
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for n := 1; s.Scan(); n++ {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+
+		var rec timelineRecord
+		if err := json.Unmarshal(s.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("Error parsing timeline line %d: %s", n, err)
+		}
+
+		// Interval events without a broker are skipped;
+		// 0 is a valid broker ID, so the field is checked.
+		var broker struct {
+			Broker *int `json:"broker"`
+		}
+
+		json.Unmarshal(s.Bytes(), &broker)
+		if broker.Broker == nil {
+			continue
+		}
+
+		if rec.Time.IsZero() {
+			return nil, fmt.Errorf("Timeline line %d has no time", n)
+		}
+
+		t := rec.Time.UTC()
+		byTime[t] = append(byTime[t], rec)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	var samples []timelineSample
+	for t, records := range byTime {
+		sort.Slice(records, func(i, j int) bool { return records[i].Broker < records[j].Broker })
+		samples = append(samples, timelineSample{time: t, records: records})
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i].time.Before(samples[j].time) })
+
+	return samples, nil
+}
+
+// simulatedZK is a kafkazk.Handler for simulated reassignments.
+// Topic state and broker metadata are derived from a sample.
+type simulatedZK struct {
+	kafkazk.Mock
+	sample timelineSample
+}
+
+// reassignments returns the kafkazk.Reassignments
+// of the sample source and destination brokers.
+// Partitions are led by source brokers and, for
+// each destination, reassigned to the destination.
+func (zk *simulatedZK) reassignments() (kafkazk.Reassignments, kafkazk.TopicStateISR) {
+	var src, dst []int
+	for _, r := range zk.sample.records {
+		if r.Source {
+			src = append(src, r.Broker)
+		}
+		if r.Destination {
+			dst = append(dst, r.Broker)
+		}
+	}
+
+	reassignments := kafkazk.Reassignments{}
+	states := kafkazk.TopicStateISR{}
+
+	if len(src) == 0 {
+		return reassignments, states
+	}
+
+	partitions := map[int][]int{}
+	for _, id := range src {
+		p := len(partitions)
+		partitions[p] = []int{}
+		states[strconv.Itoa(p)] = kafkazk.PartitionState{Leader: id}
+	}
+
+	for i, id := range dst {
+		// The leader of a partition reassigned
+		// to a destination can't be the destination.
+		leader := src[i%len(src)]
+		if leader == id {
+			leader = src[(i+1)%len(src)]
+		}
+
+		if leader == id {
+			continue
+		}
+
+		p := len(partitions)
+		partitions[p] = []int{id}
+		states[strconv.Itoa(p)] = kafkazk.PartitionState{Leader: leader}
+	}
+
+	reassignments[simulatedTopic] = partitions
+
+	return reassignments, states
+}
+
+// GetTopicStateISR implements kafkazk.Handler.
+func (zk *simulatedZK) GetTopicStateISR(t string) (kafkazk.TopicStateISR, error) {
+	_, states := zk.reassignments()
+	return states, nil
+}
+
+// GetAllBrokerMeta implements kafkazk.Handler.
+func (zk *simulatedZK) GetAllBrokerMeta(withMetrics bool) (kafkazk.BrokerMetaMap, []error) {
+	bm := kafkazk.BrokerMetaMap{}
+	for _, r := range zk.sample.records {
+		bm[r.Broker] = &kafkazk.BrokerMeta{Rack: r.Rack}
+	}
+
+	return bm, nil
+}
+
+// simulatedMetrics is a kafkametrics.Handler that returns the
+// modeled broker utilization under the simulated throttles.
+type simulatedMetrics struct {
+	metrics kafkametrics.BrokerMetrics
+}
+
+// GetMetrics implements kafkametrics.Handler.
+func (m *simulatedMetrics) GetMetrics() (kafkametrics.BrokerMetrics, []error) {
+	// Smoothing may retain the
+	// returned *Broker values.
+	bm := kafkametrics.BrokerMetrics{}
+	for id, b := range m.metrics {
+		c := *b
+		bm[id] = &c
+	}
+
+	return bm, nil
+}
+
+// PostEvent implements kafkametrics.Handler.
+func (m *simulatedMetrics) PostEvent(e *kafkametrics.Event) error {
+	return nil
+}
+
+// modelMetrics takes a sample and the simulated throttles in effect
+// when it was measured and returns the modeled broker metrics. Recorded
+// utilization less the recorded throttles is taken as non-replication
+// (client) throughput, and replication is assumed to run at the
+// simulated throttle rates.
+func modelMetrics(s timelineSample, params *ReplicationThrottleMeta) kafkametrics.BrokerMetrics {
+	bm := kafkametrics.BrokerMetrics{}
+
+	for _, r := range s.records {
+		tx, rx := r.NetTX/1000000.00, r.NetRX/1000000.00
+
+		if r.Source {
+			tx = math.Max(tx-r.LeaderRate/1000000.00, 0) + params.throttles[r.Broker]
+		}
+
+		if r.Destination {
+			rx = math.Max(rx-r.FollowerRate/1000000.00, 0) + params.followerThrottles[r.Broker]
+		}
+
+		bm[r.Broker] = &kafkametrics.Broker{
+			ID:           r.Broker,
+			InstanceType: r.InstanceType,
+			NetTX:        tx,
+			NetRX:        rx,
+			DiskUtil:     r.DiskUtil,
+			NetCapacity:  r.Capacity / 1000000.00,
+		}
+	}
+
+	return bm
+}
+
+// simulation replays a metrics timeline through the
+// throttle controller configured by the autothrottle
+// flags.
+type simulation struct {
+	params *ReplicationThrottleMeta
+	zk     *simulatedZK
+	km     *simulatedMetrics
+	// Number of intervals where
+	// throttles were updated.
+	updates int
+}
+
+// newSimulation takes a *ReplicationThrottleMeta, as configured
+// from flags, and returns a *simulation. Configs are applied to an
+// in-memory dry run updater and events are discarded.
+func newSimulation(params *ReplicationThrottleMeta) *simulation {
+	zk := &simulatedZK{}
+	km := &simulatedMetrics{}
+
+	params.zk = zk
+	params.km = km
+	params.configs = newDryRunUpdater()
+	params.audit = nil
+
+	ec := make(chan *kafkametrics.Event, 100)
+	go func() {
+		for range ec {
+		}
+	}()
+
+	params.events = &EventGenerator{c: ec, titlePrefix: eventTitlePrefix + " simulation"}
+
+	return &simulation{params: params, zk: zk, km: km}
+}
+
+// step runs the throttle controller for the sample, writing a report
+// row for each broker to w. Throttles are removed for samples with no
+// source brokers, as when reassignments complete.
+func (s *simulation) step(sample timelineSample, w io.Writer) error {
+	params := s.params
+
+	s.zk.sample = sample
+	s.km.metrics = modelMetrics(sample, params)
+
+	reassignments, _ := s.zk.reassignments()
+	params.reassignments = reassignments
+	params.topics = params.topics[:0]
+	for t := range reassignments {
+		params.topics = append(params.topics, t)
+	}
+
+	clock = func() time.Time { return sample.time }
+
+	outcome := intervalIdle
+	if len(params.topics) > 0 {
+		err := updateReplicationThrottle(params)
+		outcome = params.interval.outcome
+
+		if err != nil {
+			log.Println(err)
+		}
+
+		if err != nil || outcome == "" {
+			outcome = outcomeError
+		}
+
+		if outcome == outcomeApplied {
+			s.updates++
+		}
+	} else if len(params.throttles) > 0 {
+		params.throttles = make(map[int]float64)
+		params.followerThrottles = make(map[int]float64)
+		params.resetControllers()
+	}
+
+	for _, r := range sample.records {
+		role := "-"
+		switch {
+		case r.Source && r.Destination:
+			role = "src,dst"
+		case r.Source:
+			role = "src"
+		case r.Destination:
+			role = "dst"
+		}
+
+		b := s.km.metrics[r.Broker]
+		fmt.Fprintf(w, "%s\t%d\t%s\t%.2f\t%.2f\t%.2f\t%.2f\t%s\n",
+			sample.time.Format(time.RFC3339), r.Broker, role, b.NetTX, b.NetRX,
+			params.throttles[r.Broker], params.followerThrottles[r.Broker], outcome)
+	}
+
+	return nil
+}
+
+// runSimulation replays the metrics timeline at path through the
+// configured throttle controller, reporting the throttles that would
+// have been set at each sample to w. Logs are written to stderr.
+func runSimulation(path string, w io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	samples, err := readTimeline(f)
+	if err != nil {
+		return err
+	}
+
+	if len(samples) == 0 {
+		return errors.New("Timeline has no samples")
+	}
+
+	params := throttleMetaFromConfig(limitsFromConfig())
+
+	// Disk utilization caps are simulated
+	// if the timeline includes utilization.
+	params.diskUtil = false
+	for _, s := range samples {
+		for _, r := range s.records {
+			if r.DiskUtil > 0 {
+				params.diskUtil = true
+			}
+		}
+	}
+
+	sim := newSimulation(params)
+
+	// Configs are only applied in memory.
+	delay := configWriteDelay
+	configWriteDelay = 0
+	defer func() {
+		configWriteDelay = delay
+		clock = time.Now
+	}()
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tBROKER\tROLE\tNET TX (MB/s)\tNET RX (MB/s)\tLEADER (MB/s)\tFOLLOWER (MB/s)\tOUTCOME")
+
+	for _, s := range samples {
+		if err := sim.step(s, tw); err != nil {
+			return err
+		}
+	}
+
+	tw.Flush()
+
+	fmt.Fprintf(w, "\nSimulated %d samples from %s to %s: throttles updated %d times\n",
+		len(samples), samples[0].time.Format(time.RFC3339),
+		samples[len(samples)-1].time.Format(time.RFC3339), sim.updates)
+
+	return nil
+}
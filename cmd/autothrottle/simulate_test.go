@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var mockTimeline = `{"time": "2026-01-01T00:00:30Z", "broker": 1001, "source": true, "instance_type": "mock", "capacity_bytes_per_second": 200000000, "net_tx_bytes_per_second": 150000000, "net_rx_bytes_per_second": 20000000, "leader_rate_bytes_per_second": 100000000}
+{"time": "2026-01-01T00:00:30Z", "broker": 1002, "destination": true, "instance_type": "mock", "capacity_bytes_per_second": 200000000, "net_tx_bytes_per_second": 20000000, "net_rx_bytes_per_second": 140000000, "follower_rate_bytes_per_second": 100000000}
+{"time": "2026-01-01T00:00:00Z", "broker": 1002, "destination": true, "instance_type": "mock", "capacity_bytes_per_second": 200000000, "net_tx_bytes_per_second": 20000000, "net_rx_bytes_per_second": 40000000}
+{"time": "2026-01-01T00:00:00Z", "broker": 1001, "source": true, "instance_type": "mock", "capacity_bytes_per_second": 200000000, "net_tx_bytes_per_second": 50000000, "net_rx_bytes_per_second": 20000000}
+
+{"time": "2026-01-01T00:01:00Z", "state": "idle"}
+{"time": "2026-01-01T00:01:00Z", "broker": 1001, "instance_type": "mock", "capacity_bytes_per_second": 200000000, "net_tx_bytes_per_second": 50000000}
+`
+
+func TestReadTimeline(t *testing.T) {
+	samples, err := readTimeline(strings.NewReader(mockTimeline))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(samples) != 3 {
+		t.Fatalf("Expected 3 samples, got %d", len(samples))
+	}
+
+	if !samples[0].time.Before(samples[1].time) {
+		t.Error("Expected samples ordered by time")
+	}
+
+	if r := samples[0].records; len(r) != 2 || r[0].Broker != 1001 || !r[0].Source {
+		t.Errorf("Unexpected records %v", r)
+	}
+
+	if _, err := readTimeline(strings.NewReader(`{"broker": 1001}`)); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	// Broker 0 records aren't skipped.
+	samples, err = readTimeline(strings.NewReader(`{"time": "2026-01-01T00:00:00Z", "broker": 0, "net_tx_bytes_per_second": 50000000}
+{"time": "2026-01-01T00:00:00Z", "state": "idle"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(samples) != 1 || len(samples[0].records) != 1 || samples[0].records[0].NetTX != 50000000 {
+		t.Errorf("Expected a broker 0 record, got %v", samples)
+	}
+}
+
+func TestModelMetrics(t *testing.T) {
+	samples, _ := readTimeline(strings.NewReader(mockTimeline))
+
+	params := &ReplicationThrottleMeta{
+		throttles:         map[int]float64{1001: 60},
+		followerThrottles: map[int]float64{1002: 60},
+	}
+
+	// Recorded throttles of 100MB/s
+	// are replaced by the simulated
+	// throttles.
+	bm := modelMetrics(samples[1], params)
+
+	if bm[1001].NetTX != 110 || bm[1001].NetRX != 20 {
+		t.Errorf("Unexpected metrics %v", bm[1001])
+	}
+
+	if bm[1002].NetRX != 100 || bm[1002].NetTX != 20 {
+		t.Errorf("Unexpected metrics %v", bm[1002])
+	}
+}
+
+func TestSimulatedZK(t *testing.T) {
+	samples, _ := readTimeline(strings.NewReader(mockTimeline))
+	zk := &simulatedZK{sample: samples[0]}
+
+	reassignments, _ := zk.reassignments()
+	bmb, err := mapsFromReassigments(reassignments, zk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, exists := bmb.src[1001]; !exists || len(bmb.src) != 1 {
+		t.Errorf("Unexpected sources %v", bmb.src)
+	}
+
+	if _, exists := bmb.dst[1002]; !exists || len(bmb.dst) != 1 {
+		t.Errorf("Unexpected destinations %v", bmb.dst)
+	}
+
+	// No reassignments without sources.
+	zk.sample = samples[2]
+	if reassignments, _ := zk.reassignments(); len(reassignments) != 0 {
+		t.Errorf("Unexpected reassignments %v", reassignments)
+	}
+}
+
+func TestRunSimulation(t *testing.T) {
+	f, err := ioutil.TempFile("", "timeline")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+
+	f.WriteString(mockTimeline)
+	f.Close()
+
+	var out bytes.Buffer
+	if err := runSimulation(f.Name(), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	// Header, 5 broker rows, a blank line and the summary.
+	if len(lines) != 8 {
+		t.Fatalf("Unexpected report:\n%s", out.String())
+	}
+
+	if !strings.Contains(lines[1], outcomeApplied) || !strings.Contains(lines[5], intervalIdle) {
+		t.Errorf("Unexpected report:\n%s", out.String())
+	}
+
+	if !strings.HasPrefix(lines[7], "Simulated 3 samples") {
+		t.Errorf("Unexpected summary %s", lines[7])
+	}
+
+	if configWriteDelay == 0 || clock().Before(time.Now().Add(-time.Minute)) {
+		t.Error("Expected config write delay and clock restored")
+	}
+}
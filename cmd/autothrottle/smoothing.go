@@ -0,0 +1,105 @@
+package main
+
+import (
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+)
+
+// MetricsSmoother averages broker metrics over the last window samples,
+// or as an exponentially weighted moving average, reducing throttle changes
+// caused by short lived utilization spikes.
+type MetricsSmoother struct {
+	window int
+	// EWMA weight of each new sample;
+	// 0 if averaging over a window.
+	alpha float64
+	// Previous samples by broker ID,
+	// oldest first. Only the average
+	// is retained for an EWMA.
+	samples map[int][]kafkametrics.Broker
+}
+
+// NewMetricsSmoother returns a *MetricsSmoother that averages over
+// window samples. A window of 1 or less returns metrics as is.
+func NewMetricsSmoother(window int) *MetricsSmoother {
+	return &MetricsSmoother{
+		window:  window,
+		samples: map[int][]kafkametrics.Broker{},
+	}
+}
+
+// NewEWMASmoother returns a *MetricsSmoother that averages samples as an
+// exponentially weighted moving average, where alpha (> 0 and <= 1) is the
+// weight of each new sample. An alpha of 1 returns metrics as is.
+func NewEWMASmoother(alpha float64) *MetricsSmoother {
+	return &MetricsSmoother{
+		alpha:   alpha,
+		samples: map[int][]kafkametrics.Broker{},
+	}
+}
+
+// Smooth takes a kafkametrics.BrokerMetrics sample and returns a
+// kafkametrics.BrokerMetrics with the network and disk utilization of
+// each broker averaged over the broker's samples in the window, or
+// the broker's EWMA.
+func (s *MetricsSmoother) Smooth(bm kafkametrics.BrokerMetrics) kafkametrics.BrokerMetrics {
+	if s.alpha > 0 {
+		return s.ewma(bm)
+	}
+
+	if s.window <= 1 {
+		return bm
+	}
+
+	smoothed := kafkametrics.BrokerMetrics{}
+
+	for id, b := range bm {
+		samples := append(s.samples[id], *b)
+		if len(samples) > s.window {
+			samples = samples[len(samples)-s.window:]
+		}
+
+		s.samples[id] = samples
+
+		avg := *b
+		avg.NetTX, avg.NetRX, avg.DiskUtil = 0, 0, 0
+		for _, sb := range samples {
+			avg.NetTX += sb.NetTX
+			avg.NetRX += sb.NetRX
+			avg.DiskUtil += sb.DiskUtil
+		}
+
+		n := float64(len(samples))
+		avg.NetTX, avg.NetRX, avg.DiskUtil = avg.NetTX/n, avg.NetRX/n, avg.DiskUtil/n
+
+		smoothed[id] = &avg
+	}
+
+	return smoothed
+}
+
+func (s *MetricsSmoother) ewma(bm kafkametrics.BrokerMetrics) kafkametrics.BrokerMetrics {
+	smoothed := kafkametrics.BrokerMetrics{}
+
+	for id, b := range bm {
+		avg := *b
+
+		// The first sample of a
+		// broker seeds the average.
+		if prev, exists := s.samples[id]; exists {
+			avg.NetTX = s.alpha*b.NetTX + (1-s.alpha)*prev[0].NetTX
+			avg.NetRX = s.alpha*b.NetRX + (1-s.alpha)*prev[0].NetRX
+			avg.DiskUtil = s.alpha*b.DiskUtil + (1-s.alpha)*prev[0].DiskUtil
+		}
+
+		s.samples[id] = []kafkametrics.Broker{avg}
+		smoothed[id] = &avg
+	}
+
+	return smoothed
+}
+
+// Reset clears all samples, e.g. when
+// reassignments complete or throttles are removed.
+func (s *MetricsSmoother) Reset() {
+	s.samples = map[int][]kafkametrics.Broker{}
+}
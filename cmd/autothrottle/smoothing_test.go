@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+)
+
+func TestMetricsSmootherSmooth(t *testing.T) {
+	s := NewMetricsSmoother(3)
+
+	sample := func(tx float64) kafkametrics.BrokerMetrics {
+		return kafkametrics.BrokerMetrics{
+			1001: &kafkametrics.Broker{ID: 1001, NetTX: tx, NetRX: tx / 2, DiskUtil: tx / 10},
+		}
+	}
+
+	expected := []float64{100, 150, 200, 300}
+	for i, tx := range []float64{100, 200, 300, 400} {
+		b := s.Smooth(sample(tx))[1001]
+		if b.NetTX != expected[i] || b.NetRX != expected[i]/2 || b.DiskUtil != expected[i]/10 {
+			t.Errorf("Sample %d: expected net tx %.2f, got %v", i, expected[i], b)
+		}
+	}
+
+	s.Reset()
+	if b := s.Smooth(sample(50))[1001]; b.NetTX != 50 {
+		t.Errorf("Expected net tx 50.00, got %.2f", b.NetTX)
+	}
+
+	// No smoothing.
+	s = NewMetricsSmoother(1)
+	bm := sample(100)
+	if s.Smooth(bm)[1001] != bm[1001] {
+		t.Error("Expected unmodified metrics")
+	}
+}
+
+func TestMetricsSmootherEWMA(t *testing.T) {
+	s := NewEWMASmoother(0.5)
+
+	sample := func(tx float64) kafkametrics.BrokerMetrics {
+		return kafkametrics.BrokerMetrics{
+			1001: &kafkametrics.Broker{ID: 1001, NetTX: tx, NetRX: tx / 2, DiskUtil: tx / 10},
+		}
+	}
+
+	expected := []float64{100, 150, 225, 112.5}
+	for i, tx := range []float64{100, 200, 300, 0} {
+		b := s.Smooth(sample(tx))[1001]
+		if b.NetTX != expected[i] || b.NetRX != expected[i]/2 || b.DiskUtil != expected[i]/10 {
+			t.Errorf("Sample %d: expected net tx %.2f, got %v", i, expected[i], b)
+		}
+	}
+
+	s.Reset()
+	if b := s.Smooth(sample(50))[1001]; b.NetTX != 50 {
+		t.Errorf("Expected net tx 50.00, got %.2f", b.NetTX)
+	}
+
+	// No smoothing.
+	s = NewEWMASmoother(1)
+	s.Smooth(sample(100))
+	if b := s.Smooth(sample(300))[1001]; b.NetTX != 300 {
+		t.Errorf("Expected net tx 300.00, got %.2f", b.NetTX)
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// loopStatus holds the control loop state
+// exported via the admin APIs.
+var loopStatus = &LoopStatus{}
+
+// ThrottleStatus describes the throttles applied by the control loop and
+// how they were determined in the most recent update. Rates are in MB/s.
+type ThrottleStatus struct {
+	// Unix timestamp of the latest
+	// control loop interval.
+	Updated           int64    `json:"updated"`
+	Paused            bool     `json:"paused"`
+	ReassigningTopics []string `json:"reassigning_topics"`
+	OverrideRate      int      `json:"override_rate"`
+	Outcome           string   `json:"outcome,omitempty"`
+	Reasons           []string `json:"reasons,omitempty"`
+	// Estimated bytes (on-disk) to replicate and time
+	// to complete reassignments, with -estimate-transfer.
+	TransferBytes      float64        `json:"transfer_bytes,omitempty"`
+	TransferETASeconds int64          `json:"transfer_eta_seconds,omitempty"`
+	Brokers            []BrokerStatus `json:"brokers"`
+}
+
+// BrokerStatus describes the throttles applied to a broker.
+type BrokerStatus struct {
+	ID           int     `json:"broker"`
+	LeaderRate   float64 `json:"leader_rate"`
+	FollowerRate float64 `json:"follower_rate"`
+	// Broker throttle override, if set.
+	OverrideRate int `json:"override_rate,omitempty"`
+	// Throttled replicas as topic:partition.
+	LeaderReplicas   []string `json:"leader_replicas,omitempty"`
+	FollowerReplicas []string `json:"follower_replicas,omitempty"`
+	// Broker metrics used by the most recent throttle determination;
+	// nil if metrics weren't used (e.g. the broker is overridden).
+	Inputs *BrokerInputs `json:"inputs,omitempty"`
+}
+
+// BrokerInputs holds the broker metrics and
+// capacity used to determine throttle rates.
+type BrokerInputs struct {
+	NetTX           float64 `json:"net_tx"`
+	NetRX           float64 `json:"net_rx"`
+	DiskUtil        float64 `json:"disk_util,omitempty"`
+	Capacity        float64 `json:"capacity,omitempty"`
+	Headroom        float64 `json:"headroom,omitempty"`
+	InboundHeadroom float64 `json:"inbound_headroom,omitempty"`
+}
+
+// LoopStatus is a snapshot of the control loop
+// state, safe for concurrent use.
+type LoopStatus struct {
+	sync.Mutex
+	status ThrottleStatus
+}
+
+// Set takes a *ReplicationThrottleMeta and the control loop
+// interval state and stores a snapshot of the applied throttles.
+// The throttled replicas, inputs and outcome of the most recent
+// throttle update are included unless the interval is idle.
+func (s *LoopStatus) Set(params *ReplicationThrottleMeta, state string) {
+	status := ThrottleStatus{
+		Updated:           clock().Unix(),
+		Paused:            state == intervalPaused,
+		ReassigningTopics: append([]string{}, params.topics...),
+		OverrideRate:      params.overrideRate,
+	}
+
+	sort.Strings(status.ReassigningTopics)
+
+	i := params.interval
+	if state == intervalIdle {
+		i = intervalState{}
+	}
+
+	if len(i.brokers.all) > 0 {
+		status.Outcome = i.outcome
+		if status.Outcome == "" {
+			status.Outcome = outcomeError
+		}
+
+		status.Reasons = append([]string{}, i.reasons...)
+	}
+
+	if e := i.transfer; e != nil {
+		status.TransferBytes = e.bytes
+		status.TransferETASeconds = int64(e.eta.Seconds())
+	}
+
+	leaders, followers := throttledReplicas(i.brokers.throttled)
+
+	ids := map[int]struct{}{}
+	for id, r := range params.throttles {
+		// Removed throttles are stored as 0.
+		if r > 0 || params.followerThrottles[id] > 0 {
+			ids[id] = struct{}{}
+		}
+	}
+
+	for id := range i.brokers.all {
+		ids[id] = struct{}{}
+	}
+
+	for id := range ids {
+		b := BrokerStatus{
+			ID:               id,
+			LeaderRate:       params.throttles[id],
+			FollowerRate:     params.followerThrottles[id],
+			LeaderReplicas:   leaders[id],
+			FollowerReplicas: followers[id],
+		}
+
+		if o, exists := params.brokerOverrides[id]; exists {
+			b.OverrideRate = o.Rate
+		}
+
+		if m, exists := i.metrics[id]; exists {
+			b.Inputs = &BrokerInputs{NetTX: m.NetTX, NetRX: m.NetRX}
+
+			if params.diskUtil {
+				b.Inputs.DiskUtil = m.DiskUtil
+			}
+
+			if c, known := params.limits.capacity(m); known {
+				b.Inputs.Capacity = c
+
+				if h, err := params.limits.headroom(m, b.LeaderRate); err == nil {
+					b.Inputs.Headroom = h
+				}
+
+				if h, err := params.limits.inboundHeadroom(m, b.FollowerRate); params.inbound && err == nil {
+					b.Inputs.InboundHeadroom = h
+				}
+			}
+		}
+
+		status.Brokers = append(status.Brokers, b)
+	}
+
+	sort.Slice(status.Brokers, func(i, j int) bool { return status.Brokers[i].ID < status.Brokers[j].ID })
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.status = status
+}
+
+// Get returns the most recent ThrottleStatus.
+func (s *LoopStatus) Get() ThrottleStatus {
+	s.Lock()
+	defer s.Unlock()
+
+	// Snapshots aren't modified once set.
+	return s.status
+}
+
+// throttledReplicas takes the map of topics to throttled "partition:broker"
+// leader and follower lists of a bmapBundle and returns maps of broker IDs
+// to the throttled leader and follower replicas, as sorted topic:partition
+// lists.
+func throttledReplicas(throttled map[string]map[string][]string) (map[int][]string, map[int][]string) {
+	leaders, followers := map[int][]string{}, map[int][]string{}
+
+	for topic, lists := range throttled {
+		for role, out := range map[string]map[int][]string{"leaders": leaders, "followers": followers} {
+			for _, r := range lists[role] {
+				kv := strings.SplitN(r, ":", 2)
+				if len(kv) != 2 {
+					continue
+				}
+
+				id, err := strconv.Atoi(kv[1])
+				if err != nil {
+					continue
+				}
+
+				out[id] = append(out[id], fmt.Sprintf("%s:%s", topic, kv[0]))
+			}
+		}
+	}
+
+	for _, m := range []map[int][]string{leaders, followers} {
+		for id := range m {
+			sort.Strings(m[id])
+		}
+	}
+
+	return leaders, followers
+}
+
+func getStatus(w http.ResponseWriter, req *http.Request, zk kafkazk.Handler, p string, s *LoopStatus) {
+	logReq(req)
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		io.WriteString(w, incorrectMethod)
+		return
+	}
+
+	status := s.Get()
+
+	// The pause state is read from ZooKeeper
+	// since it may have been set since the
+	// last interval.
+	_, paused, err := getPause(zk, p)
+	if err != nil {
+		metrics.Inc(metricAPIErrorsTotal, "endpoint", req.URL.Path)
+		io.WriteString(w, fmt.Sprintf("%s\n", err))
+		return
+	}
+
+	status.Paused = paused
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+)
+
+func mockStatusMeta() *ReplicationThrottleMeta {
+	l, _ := NewLimits(NewLimitsConfig{
+		Minimum:     10,
+		Maximum:     90,
+		CapacityMap: map[string]float64{"mock": 200},
+	})
+
+	return &ReplicationThrottleMeta{
+		topics:            []string{"test_topic2", "test_topic"},
+		overrideRate:      0,
+		throttles:         map[int]float64{1001: 80, 1002: 60, 1003: 0},
+		followerThrottles: map[int]float64{1001: 80, 1002: 60, 1003: 0},
+		brokerOverrides:   BrokerOverrides{1002: {Rate: 60}},
+		limits:            l,
+		interval: intervalState{
+			brokers: bmapBundle{
+				src: map[int]struct{}{1001: {}},
+				dst: map[int]struct{}{1002: {}},
+				all: map[int]struct{}{1001: {}, 1002: {}},
+				throttled: map[string]map[string][]string{
+					"test_topic": {
+						"leaders":   []string{"1:1001", "0:1001"},
+						"followers": []string{"0:1002"},
+					},
+					"test_topic2": {
+						"leaders":   []string{"0:1001"},
+						"followers": []string{},
+					},
+				},
+			},
+			metrics: kafkametrics.BrokerMetrics{
+				1001: {ID: 1001, InstanceType: "mock", NetTX: 120, NetRX: 40},
+			},
+			outcome: outcomeApplied,
+			reasons: []string{"Most constrained source broker: [1001]"},
+		},
+	}
+}
+
+func TestLoopStatus(t *testing.T) {
+	s := &LoopStatus{}
+	s.Set(mockStatusMeta(), intervalReassigning)
+
+	status := s.Get()
+
+	if status.ReassigningTopics[0] != "test_topic" || status.Outcome != outcomeApplied || len(status.Reasons) != 1 {
+		t.Errorf("Unexpected status %v", status)
+	}
+
+	// Removed throttles are omitted.
+	if len(status.Brokers) != 2 {
+		t.Fatalf("Expected 2 brokers, got %d", len(status.Brokers))
+	}
+
+	b := status.Brokers[0]
+	if b.ID != 1001 || b.LeaderRate != 80 || b.OverrideRate != 0 {
+		t.Errorf("Unexpected broker status %v", b)
+	}
+
+	expected := []string{"test_topic2:0", "test_topic:0", "test_topic:1"}
+	if !reflect.DeepEqual(b.LeaderReplicas, expected) {
+		t.Errorf("Expected leader replicas %v, got %v", expected, b.LeaderReplicas)
+	}
+
+	// Headroom is 90% of the capacity
+	// less non-replication traffic.
+	if b.Inputs == nil || b.Inputs.NetTX != 120 || b.Inputs.Capacity != 200 || b.Inputs.Headroom != 144 {
+		t.Errorf("Unexpected inputs %v", b.Inputs)
+	}
+
+	b = status.Brokers[1]
+	if b.OverrideRate != 60 || b.Inputs != nil || len(b.FollowerReplicas) != 1 {
+		t.Errorf("Unexpected broker status %v", b)
+	}
+
+	// The last update isn't included while idle.
+	params := mockStatusMeta()
+	params.topics = nil
+	s.Set(params, intervalIdle)
+
+	status = s.Get()
+	if status.Outcome != "" || len(status.Brokers) != 2 || status.Brokers[0].LeaderReplicas != nil {
+		t.Errorf("Unexpected status %v", status)
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	zk := newMemZK()
+	s := &LoopStatus{}
+	s.Set(mockStatusMeta(), intervalReassigning)
+
+	req := func(method string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		getStatus(w, httptest.NewRequest(method, "/status", nil), zk, "/autothrottle/paused", s)
+		return w
+	}
+
+	if r := req(http.MethodPost).Body.String(); r != incorrectMethod {
+		t.Errorf("Unexpected response: %s", r)
+	}
+
+	setPause(zk, "/autothrottle/paused", PauseConfig{Reason: "incident"})
+
+	var status ThrottleStatus
+	if err := json.NewDecoder(req(http.MethodGet).Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+
+	if !status.Paused || len(status.Brokers) != 2 || status.Brokers[0].Inputs.NetRX != 40 {
+		t.Errorf("Unexpected status %v", status)
+	}
+}
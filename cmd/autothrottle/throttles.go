@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"sort"
 	"strconv"
 	"time"
 
@@ -14,20 +15,155 @@ import (
 	"github.com/honeycombio/kafka-kit/kafkazk"
 )
 
+var (
+	// Delay between config writes,
+	// to reduce ZooKeeper load.
+	configWriteDelay = 250 * time.Millisecond
+	// Time source for throttle cooldowns;
+	// replaced in simulations.
+	clock = time.Now
+)
+
+// ConfigUpdater applies dynamic topic and broker configs. Both
+// kafkazk.Handler (ZooKeeper) and kafkaadmin.Client (Admin API)
+// implement ConfigUpdater.
+type ConfigUpdater interface {
+	UpdateKafkaConfig(kafkazk.KafkaConfig) (bool, error)
+}
+
+// ReassignmentLister lists ongoing partition reassignments. Both
+// zkReassignments (the ZooKeeper reassign_partitions znode) and
+// kafkaadmin.Client (Admin API) implement ReassignmentLister.
+type ReassignmentLister interface {
+	ListPartitionReassignments() (kafkazk.Reassignments, error)
+}
+
+// zkReassignments is a ReassignmentLister
+// for the reassign_partitions znode.
+type zkReassignments struct {
+	zk kafkazk.Handler
+}
+
+// ListPartitionReassignments implements ReassignmentLister.
+func (z zkReassignments) ListPartitionReassignments() (kafkazk.Reassignments, error) {
+	// XXX GetReassignments needs to return an error.
+	return z.zk.GetReassignments(), nil
+}
+
 // ReplicationThrottleMeta holds all types
 // needed to call the updateReplicationThrottle func.
 type ReplicationThrottleMeta struct {
 	topics        []string
 	reassignments kafkazk.Reassignments
 	zk            kafkazk.Handler
+	configs       ConfigUpdater
 	km            kafkametrics.Handler
 	overrideRate  int
 	events        *EventGenerator
 	// Map of broker ID to last set throttle rate.
-	throttles        map[int]float64
+	throttles map[int]float64
+	// Map of broker ID to last set follower throttle
+	// rate. If inbound is false, follower throttles
+	// are set to the leader throttle rate.
+	followerThrottles map[int]float64
+	inbound           bool
+	// Whether throttles are capped by
+	// destination disk utilization.
+	diskUtil bool
+	// Per-broker throttle overrides and the
+	// overrides in place as of the last update.
+	brokerOverrides  BrokerOverrides
+	appliedOverrides BrokerOverrides
 	limits           Limits
+	// Optional controllers for the leader and
+	// follower throttles. Throttles are set from
+	// the available headroom each interval if nil.
+	leaderPID   *PIDController
+	followerPID *PIDController
+	// Whether leader and follower throttles are
+	// determined from the headroom of each broker
+	// rather than the most constrained brokers.
+	independent bool
+	// Optional reduced throttle
+	// rates for low priority topics.
+	priorities TopicPriorities
+	// Optional aggregate throttle
+	// ceilings by rack.
+	rackLimits RackLimits
+	// Optional smoothing of broker metrics.
+	smoother *MetricsSmoother
+	// Minimum time between throttle increases
+	// and the time throttles were last applied.
+	cooldown   time.Duration
+	lastChange time.Time
+	// Optional log of throttle changes.
+	audit            AuditLog
 	failureThreshold int
 	failures         int
+	// State of the most recent update,
+	// for interval events.
+	interval intervalState
+}
+
+// resetControllers resets any throttle
+// controllers and metrics smoothing.
+func (r *ReplicationThrottleMeta) resetControllers() {
+	for _, p := range []*PIDController{r.leaderPID, r.followerPID} {
+		if p != nil {
+			p.Reset()
+		}
+	}
+
+	if r.smoother != nil {
+		r.smoother.Reset()
+	}
+}
+
+// currentRates returns the brokerRates of the
+// applied throttles of the brokers in ids.
+func (r *ReplicationThrottleMeta) currentRates(ids map[int]struct{}) brokerRates {
+	rates := brokerRates{}
+	for id := range ids {
+		rates[id] = [2]float64{r.throttles[id], r.followerThrottles[id]}
+	}
+
+	return rates
+}
+
+// inCooldown returns whether the cooldown period since
+// throttles were last applied hasn't yet elapsed as of now.
+func (r *ReplicationThrottleMeta) inCooldown(now time.Time) bool {
+	return r.cooldown > 0 && now.Sub(r.lastChange) < r.cooldown
+}
+
+// decreases takes a set of broker IDs, the proposed leader and follower
+// throttles and optional per broker rates and returns whether any
+// proposed throttle is lower than the previously applied throttle.
+func (r *ReplicationThrottleMeta) decreases(ids map[int]struct{}, leader, follower float64, rates brokerRates) bool {
+	for id := range ids {
+		l, f := leader, follower
+		if br, exists := rates[id]; exists {
+			l, f = br[0], br[1]
+		}
+
+		if l < r.throttles[id] || f < r.followerThrottles[id] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// previousThrottles returns the highest leader and follower
+// throttles previously applied to the brokers in ids.
+func (r *ReplicationThrottleMeta) previousThrottles(ids map[int]struct{}) (float64, float64) {
+	var leader, follower float64
+	for id := range ids {
+		leader = math.Max(leader, r.throttles[id])
+		follower = math.Max(follower, r.followerThrottles[id])
+	}
+
+	return leader, follower
 }
 
 // ThrottleOverrideConfig holds throttle
@@ -38,6 +174,16 @@ type ThrottleOverrideConfig struct {
 	// Whether the override rate should be
 	// removed when the current reassignments finish.
 	AutoRemove bool `json:"autoremove"`
+	// Unix timestamp after which the override
+	// is removed. The override doesn't expire
+	// if 0.
+	Expires int64 `json:"expires,omitempty"`
+}
+
+// Expired returns whether the
+// override has expired as of t.
+func (c ThrottleOverrideConfig) Expired(t time.Time) bool {
+	return c.Expires != 0 && t.Unix() >= c.Expires
 }
 
 // Failure increments the failures count
@@ -45,6 +191,7 @@ type ThrottleOverrideConfig struct {
 // the failures threshold.
 func (r *ReplicationThrottleMeta) Failure() bool {
 	r.failures++
+	metrics.Set(metricMetricsFailures, float64(r.failures))
 
 	if r.failures > r.failureThreshold {
 		return true
@@ -56,6 +203,7 @@ func (r *ReplicationThrottleMeta) Failure() bool {
 // ResetFailures resets the failures count.
 func (r *ReplicationThrottleMeta) ResetFailures() {
 	r.failures = 0
+	metrics.Set(metricMetricsFailures, 0)
 }
 
 // ReassigningBrokers is a list of brokers
@@ -66,15 +214,60 @@ type ReassigningBrokers struct {
 	Dst []*kafkametrics.Broker
 }
 
-// highestSrcNetTX takes a ReassigningBrokers and returns
-// the leader with the highest outbound network throughput.
-func (t ReassigningBrokers) highestSrcNetTX() *kafkametrics.Broker {
+// mostConstrainedSrc takes Limits and the applied leader throttles and
+// returns the src broker with the least outbound replication headroom,
+// along with the headroom. Brokers with differing capacities are compared
+// by headroom rather than throughput.
+func (t ReassigningBrokers) mostConstrainedSrc(l Limits, throttles map[int]float64) (*kafkametrics.Broker, float64, error) {
+	return mostConstrained(t.Src, throttles, l.headroom, func(b *kafkametrics.Broker) float64 { return b.NetTX })
+}
+
+// mostConstrainedDst is the inbound counterpart to mostConstrainedSrc. It
+// returns the dst broker with the least inbound replication headroom, along
+// with the headroom.
+func (t ReassigningBrokers) mostConstrainedDst(l Limits, throttles map[int]float64) (*kafkametrics.Broker, float64, error) {
+	return mostConstrained(t.Dst, throttles, l.inboundHeadroom, func(b *kafkametrics.Broker) float64 { return b.NetRX })
+}
+
+// mostConstrained takes a list of brokers, the applied throttles, a headroom
+// func and a network utilization func and returns the broker with the least
+// headroom and the headroom. Ties (e.g. brokers at the minimum rate) go to the
+// broker with the highest utilization. Brokers without utilization reported
+// are skipped; a nil broker is returned if no brokers remain.
+func mostConstrained(bs []*kafkametrics.Broker, throttles map[int]float64,
+	headroom func(*kafkametrics.Broker, float64) (float64, error),
+	util func(*kafkametrics.Broker) float64) (*kafkametrics.Broker, float64, error) {
+
+	var broker *kafkametrics.Broker
+	var min float64
+
+	for _, b := range bs {
+		if util(b) <= 0 {
+			continue
+		}
+
+		h, err := headroom(b, throttles[b.ID])
+		if err != nil {
+			return nil, 0.00, err
+		}
+
+		if broker == nil || h < min || (h == min && util(b) > util(broker)) {
+			broker, min = b, h
+		}
+	}
+
+	return broker, min, nil
+}
+
+// highestDstDiskUtil takes a ReassigningBrokers and returns
+// the follower with the highest disk utilization.
+func (t ReassigningBrokers) highestDstDiskUtil() *kafkametrics.Broker {
 	hwm := 0.00
 	var broker *kafkametrics.Broker
 
-	for _, b := range t.Src {
-		if b.NetTX > hwm {
-			hwm = b.NetTX
+	for _, b := range t.Dst {
+		if b.DiskUtil > hwm {
+			hwm = b.DiskUtil
 			broker = b
 		}
 	}
@@ -89,8 +282,13 @@ func (t ReassigningBrokers) highestSrcNetTX() *kafkametrics.Broker {
 // are fetched to determine replication headroom.
 // The replication throttle is then adjusted accordingly.
 // If a non-empty override is provided, that static value is used instead
-// of a dynamically determined value.
+// of a dynamically determined value. If inbound throttles are managed, the
+// follower throttle rate is determined separately from the inbound headroom
+// of the destination brokers. Brokers with a throttle override are set to
+// the override rate and otherwise excluded from throttle determinations.
 func updateReplicationThrottle(params *ReplicationThrottleMeta) error {
+	params.interval = intervalState{}
+
 	// Get the maps of brokers handling
 	// reassignments.
 	bmaps, err := mapsFromReassigments(params.reassignments, params.zk)
@@ -98,6 +296,12 @@ func updateReplicationThrottle(params *ReplicationThrottleMeta) error {
 		return err
 	}
 
+	params.interval.brokers = bmaps
+
+	// Overridden brokers shouldn't constrain
+	// the throttle rates of remaining brokers.
+	calcMaps := bmaps.excluding(params.brokerOverrides)
+
 	// Creates lists from maps.
 	srcBrokers, dstBrokers, allBrokers := bmaps.lists()
 
@@ -113,14 +317,24 @@ func updateReplicationThrottle(params *ReplicationThrottleMeta) error {
 	// capacity values.
 	var replicationCapacity float64
 	var currThrottle float64
+	var followerCapacity float64
+	var currFollowerThrottle float64
 	var useMetrics bool
 	var brokerMetrics kafkametrics.BrokerMetrics
 	var metricErrs []error
 	var inFailureMode bool
+	// How the throttle rates were
+	// determined, included in events.
+	var reasons []string
+	// Per broker rates, if independent
+	// or scaled by topic priorities.
+	var rates brokerRates
 
 	if params.overrideRate != 0 {
 		log.Printf("A throttle override is set: %dMB/s\n", params.overrideRate)
+		reasons = append(reasons, fmt.Sprintf("A throttle override is set: %dMB/s", params.overrideRate))
 		replicationCapacity = float64(params.overrideRate)
+		followerCapacity = replicationCapacity
 	} else {
 		useMetrics = true
 
@@ -131,6 +345,10 @@ func updateReplicationThrottle(params *ReplicationThrottleMeta) error {
 		// data for all target brokers. If we have broker
 		// metrics for all target brokers, we can ignore
 		// any errors.
+		for range metricErrs {
+			metrics.Inc(metricMetricsErrorTotal)
+		}
+
 		if metricErrs != nil {
 			if brokerMetrics == nil || incompleteBrokerMetrics(allBrokers, brokerMetrics) {
 				inFailureMode = true
@@ -149,13 +367,17 @@ func updateReplicationThrottle(params *ReplicationThrottleMeta) error {
 			// Over threshold. Set replicationCapacity which will be
 			// applied in the apply throttles stage.
 			if over {
-				log.Printf("Metrics fetch failure count %d exceeds threshold %d, reverting to min-rate %.2fMB/s\n",
+				e := fmt.Sprintf("Metrics fetch failure count %d exceeds threshold %d, reverting to min-rate %.2fMB/s",
 					params.failures, params.failureThreshold, params.limits["minimum"])
+				log.Println(e)
+				reasons = append(reasons, e)
 				replicationCapacity = params.limits["minimum"]
+				followerCapacity = replicationCapacity
 				// Not over threshold. Return and retain previous throttle.
 			} else {
 				log.Printf("Metrics fetch failure count %d doesn't exceed threshold %d, retaining previous throttle\n",
 					params.failures, params.failureThreshold)
+				params.interval.outcome = outcomeRetained
 				return nil
 			}
 		} else {
@@ -170,36 +392,183 @@ func updateReplicationThrottle(params *ReplicationThrottleMeta) error {
 	// fetched them, determine a tvalue based on
 	// the most-utilized path.
 	if useMetrics && !inFailureMode {
+		setBrokerMetrics(allBrokers, brokerMetrics, params.limits)
+
+		if params.smoother != nil {
+			brokerMetrics = params.smoother.Smooth(brokerMetrics)
+		}
+
+		params.interval.metrics = brokerMetrics
+
 		var e string
-		replicationCapacity, currThrottle, e, err = repCapacityByMetrics(params, bmaps, brokerMetrics)
+		replicationCapacity, currThrottle, e, err = repCapacityByMetrics(params, calcMaps, brokerMetrics)
 		if err != nil {
 			return err
 		}
 
 		log.Println(e)
+		reasons = append(reasons, e)
+
+		if params.leaderPID != nil {
+			src, _, _ := constrainingBrokers(calcMaps, brokerMetrics).mostConstrainedSrc(params.limits, params.throttles)
+			replicationCapacity, e, err = pidCapacity(params.leaderPID, params.limits, src, src.NetTX, currThrottle, replicationCapacity)
+			if err != nil {
+				return err
+			}
+
+			log.Println(e)
+			reasons = append(reasons, e)
+		}
+
 		log.Printf("Replication capacity (based on a %.0f%% max free capacity utilization): %0.2fMB/s\n",
 			params.limits["maximum"], replicationCapacity)
 
+		// Without inbound throttle management, the
+		// follower throttle follows the leader throttle.
+		followerCapacity, currFollowerThrottle = replicationCapacity, currThrottle
+
+		if params.inbound {
+			followerCapacity, currFollowerThrottle, e, err = inboundCapacityByMetrics(params, calcMaps, brokerMetrics)
+			if err != nil {
+				return err
+			}
+
+			log.Println(e)
+			reasons = append(reasons, e)
+
+			if params.followerPID != nil {
+				dst, _, _ := constrainingBrokers(calcMaps, brokerMetrics).mostConstrainedDst(params.limits, params.followerThrottles)
+				followerCapacity, e, err = pidCapacity(params.followerPID, params.limits, dst, dst.NetRX, currFollowerThrottle, followerCapacity)
+				if err != nil {
+					return err
+				}
+
+				log.Println(e)
+				reasons = append(reasons, e)
+			}
+
+			log.Printf("Inbound replication capacity (based on a %.0f%% max free capacity utilization): %0.2fMB/s\n",
+				params.limits["maximum"], followerCapacity)
+
+			// Sources can't replicate faster than the
+			// destinations can receive; the most constrained
+			// destination also caps the leader throttle.
+			if followerCapacity < replicationCapacity {
+				e := fmt.Sprintf("Leader throttle capped from %.2fMB/s to the destination broker capacity of %.2fMB/s",
+					replicationCapacity, followerCapacity)
+				log.Println(e)
+				reasons = append(reasons, e)
+				replicationCapacity = followerCapacity
+			}
+		}
+
+		// Replication writes land on the destination brokers;
+		// cap both throttles if the most utilized destination
+		// disk can't sustain the network based rates.
+		var diskCap float64
+		if params.diskUtil {
+			diskCapacity, capped, e, err := diskCapacityByMetrics(params, calcMaps, brokerMetrics)
+			if err != nil {
+				return err
+			}
+
+			log.Println(e)
+
+			if capped && diskCapacity < math.Max(replicationCapacity, followerCapacity) {
+				e = fmt.Sprintf("Capping replication capacity by disk utilization (based on a %.0f%% max disk utilization): %0.2fMB/s",
+					params.limits[maxDiskUtilKey], diskCapacity)
+				log.Println(e)
+				reasons = append(reasons, e)
+				replicationCapacity = math.Min(replicationCapacity, diskCapacity)
+				followerCapacity = math.Min(followerCapacity, diskCapacity)
+				diskCap = diskCapacity
+			}
+		}
+
+		metrics.Set(metricHeadroom, replicationCapacity*1000000.00, "type", "leader")
+		metrics.Set(metricHeadroom, followerCapacity*1000000.00, "type", "follower")
+
 		// Check if the delta between the newly calculated
-		// throttle and the previous throttle exceeds the
-		// ChangeThreshold param.
+		// throttles and the previous throttles exceeds the
+		// ChangeThreshold param. Changes to broker overrides
+		// are always applied.
 		d := math.Abs((currThrottle - replicationCapacity) / currThrottle * 100)
-		if d < Config.ChangeThreshold {
-			log.Printf("Proposed throttle is within %.2f%% of the previous throttle "+
+		df := math.Abs((currFollowerThrottle - followerCapacity) / currFollowerThrottle * 100)
+
+		// With independent rates, each broker's throttles are
+		// determined from its own outbound and inbound headroom.
+		// The largest change of any broker is checked if rates
+		// are set per broker.
+		if params.independent {
+			rates, err = brokerRatesByMetrics(params, calcMaps, brokerMetrics, diskCap)
+			if err != nil {
+				return err
+			}
+
+			reasons = append(reasons, "Leader and follower throttles determined by the outbound and inbound headroom of each broker")
+		}
+
+		// Brokers only replicating lower priority
+		// topics receive a portion of the throttles.
+		if len(params.priorities) > 0 {
+			pr, e := params.priorities.apply(bmaps, rates, replicationCapacity, followerCapacity, params.limits["minimum"])
+			if pr != nil {
+				rates = pr
+				reasons = append(reasons, e)
+			}
+		}
+
+		// Throttles of brokers in racks with a ceiling are
+		// scaled so that the rack aggregate stays within it.
+		if len(params.rackLimits) > 0 {
+			racks, err := brokerRacks(params.zk)
+			if err != nil {
+				return err
+			}
+
+			rr, e := params.rackLimits.apply(bmaps, racks, rates, replicationCapacity, followerCapacity, params.brokerOverrides)
+			if rr != nil {
+				rates = rr
+				for _, m := range e {
+					log.Println(m)
+				}
+				reasons = append(reasons, e...)
+			}
+		}
+
+		if rates != nil {
+			d, df = rates.maxChange(params.throttles, params.followerThrottles)
+		}
+
+		overridesChanged := params.brokerOverrides.Changed(params.appliedOverrides, bmaps.all)
+		if d < Config.ChangeThreshold && df < Config.ChangeThreshold && !overridesChanged {
+			log.Printf("Proposed throttles are within %.2f%% (leader) and %.2f%% (follower) of the previous throttles "+
 				"(below %.2f%% threshold), skipping throttle update\n",
-				d, Config.ChangeThreshold)
+				d, df, Config.ChangeThreshold)
+			params.interval.outcome, params.interval.reasons = outcomeUnchanged, reasons
+			return nil
+		}
+
+		// Throttle decreases are always applied so
+		// that brokers aren't left saturated.
+		if !overridesChanged && params.inCooldown(clock()) &&
+			!params.decreases(calcMaps.all, replicationCapacity, followerCapacity, rates) {
+			log.Printf("Throttles were last updated %s ago (within the %s cooldown), skipping throttle increase\n",
+				clock().Sub(params.lastChange).Truncate(time.Second), params.cooldown)
+			params.interval.outcome, params.interval.reasons = outcomeCooldown, reasons
 			return nil
 		}
 	}
 
-	// Get a rate string based on the final tvalue.
-	rateString := fmt.Sprintf("%.0f", replicationCapacity*1000000.00)
+	// Get the previous rates for the event
+	// before applying the new throttles.
+	prevThrottle, prevFollowerThrottle := params.previousThrottles(calcMaps.all)
 
 	/**************************
 	Set topic throttle configs.
 	**************************/
 
-	errs := applyTopicThrottles(bmaps.throttled, params.zk)
+	errs := applyTopicThrottles(bmaps.throttled, params.configs)
 	for _, e := range errs {
 		log.Println(e)
 	}
@@ -208,23 +577,62 @@ func updateReplicationThrottle(params *ReplicationThrottleMeta) error {
 	Set broker throttle configs.
 	***************************/
 
+	prevRates := params.currentRates(bmaps.all)
+
 	errs = applyBrokerThrottles(bmaps.all,
-		rateString,
 		replicationCapacity,
-		params.throttles,
-		params.zk)
+		followerCapacity,
+		rates,
+		params,
+		params.configs)
 	for _, e := range errs {
 		log.Println(e)
 	}
 
+	params.lastChange = clock()
+	params.interval.outcome, params.interval.reasons = outcomeApplied, reasons
+	auditThrottleChanges(params, prevRates, reasons)
+
 	/***********
 	Log success.
 	***********/
 
 	// Write event.
 	var b bytes.Buffer
-	b.WriteString(fmt.Sprintf("Replication throttle of %0.2fMB/s set on the following brokers: %v\n",
-		replicationCapacity, allBrokers))
+	switch {
+	case rates != nil:
+		b.WriteString("Replication throttles set per broker:\n")
+		for _, id := range rates.IDs() {
+			if _, overridden := params.brokerOverrides[id]; !overridden {
+				b.WriteString(fmt.Sprintf("[%d] %0.2fMB/s (leader), %0.2fMB/s (follower)\n",
+					id, rates[id][0], rates[id][1]))
+			}
+		}
+	case followerCapacity != replicationCapacity:
+		b.WriteString(fmt.Sprintf("Replication throttles of %0.2fMB/s (leader) and %0.2fMB/s (follower) set on the following brokers: %v\n",
+			replicationCapacity, followerCapacity, allBrokers))
+	default:
+		b.WriteString(fmt.Sprintf("Replication throttle of %0.2fMB/s set on the following brokers: %v\n",
+			replicationCapacity, allBrokers))
+	}
+	for _, id := range params.brokerOverrides.IDs() {
+		if _, participating := bmaps.all[id]; participating {
+			b.WriteString(fmt.Sprintf("Throttle override of %dMB/s set on broker %d\n",
+				params.brokerOverrides[id].Rate, id))
+		}
+	}
+	switch {
+	case prevThrottle == 0 && prevFollowerThrottle == 0:
+		b.WriteString("No throttle was previously set\n")
+	case prevFollowerThrottle != prevThrottle:
+		b.WriteString(fmt.Sprintf("Previous throttles: %0.2fMB/s (leader), %0.2fMB/s (follower)\n",
+			prevThrottle, prevFollowerThrottle))
+	default:
+		b.WriteString(fmt.Sprintf("Previous throttle: %0.2fMB/s\n", prevThrottle))
+	}
+	for _, r := range reasons {
+		b.WriteString(r + "\n")
+	}
 	b.WriteString(fmt.Sprintf("Topics currently undergoing replication: %v", params.topics))
 	params.events.Write("Broker replication throttle set", b.String())
 
@@ -295,17 +703,181 @@ func mapsFromReassigments(r kafkazk.Reassignments, zk kafkazk.Handler) (bmapBund
 // a calculated replication capacity, the currently applied throttle, a slice
 // of event strings and any errors if encountered.
 func repCapacityByMetrics(rtm *ReplicationThrottleMeta, bmb bmapBundle, bm kafkametrics.BrokerMetrics) (float64, float64, string, error) {
-	// Map src/dst broker IDs to a *ReassigningBrokers.
-	participatingBrokers := &ReassigningBrokers{}
+	var event string
+
+	participatingBrokers, err := reassigningBrokers(bmb, bm)
+	if err != nil {
+		return 0.00, 0.00, event, err
+	}
+
+	// Get the most constrained src broker and
+	// its current throttle, if applied.
+	constrainingSrc, replicationCapacity, err := participatingBrokers.mostConstrainedSrc(rtm.limits, rtm.throttles)
+	if err != nil {
+		return 0.00, 0.00, event, err
+	}
+
+	if constrainingSrc == nil {
+		return 0.00, 0.00, event, errors.New("No source brokers with outbound metrics")
+	}
+
+	currThrottle := rtm.throttles[constrainingSrc.ID]
+
+	event = fmt.Sprintf("Most constrained source broker: "+
+		"[%d] net tx of %.2fMB/s (over %ds) with an existing throttle rate of %.2fMB/s",
+		constrainingSrc.ID, constrainingSrc.NetTX, Config.MetricsWindow, currThrottle)
+
+	return replicationCapacity, currThrottle, event, nil
+}
+
+// inboundCapacityByMetrics is the inbound counterpart to repCapacityByMetrics.
+// It finds the most constrained dst broker and returns a calculated follower
+// replication capacity, the currently applied follower throttle, an event
+// string and any errors if encountered.
+func inboundCapacityByMetrics(rtm *ReplicationThrottleMeta, bmb bmapBundle, bm kafkametrics.BrokerMetrics) (float64, float64, string, error) {
+	var event string
+
+	participatingBrokers, err := reassigningBrokers(bmb, bm)
+	if err != nil {
+		return 0.00, 0.00, event, err
+	}
+
+	// Get the most constrained dst broker and
+	// its current follower throttle, if applied.
+	constrainingDst, replicationCapacity, err := participatingBrokers.mostConstrainedDst(rtm.limits, rtm.followerThrottles)
+	if err != nil {
+		return 0.00, 0.00, event, err
+	}
+
+	if constrainingDst == nil {
+		return 0.00, 0.00, event, errors.New("No destination brokers with inbound metrics")
+	}
+
+	currThrottle := rtm.followerThrottles[constrainingDst.ID]
+
+	event = fmt.Sprintf("Most constrained destination broker: "+
+		"[%d] net rx of %.2fMB/s (over %ds) with an existing follower throttle rate of %.2fMB/s",
+		constrainingDst.ID, constrainingDst.NetRX, Config.MetricsWindow, currThrottle)
+
+	return replicationCapacity, currThrottle, event, nil
+}
+
+// brokerRates is a map of broker ID to
+// leader and follower throttle rates.
+type brokerRates map[int][2]float64
+
+// IDs returns a sorted []int of
+// broker IDs in the brokerRates.
+func (r brokerRates) IDs() []int {
+	var ids []int
+	for id := range r {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+// maxChange takes the previously applied leader and follower throttles and
+// returns the largest percent change in the leader and follower throttles
+// of any broker.
+func (r brokerRates) maxChange(leader, follower map[int]float64) (float64, float64) {
+	var d, df float64
+	for id, br := range r {
+		d = math.Max(d, math.Abs((leader[id]-br[0])/leader[id]*100))
+		df = math.Max(df, math.Abs((follower[id]-br[1])/follower[id]*100))
+	}
+
+	return d, df
+}
+
+// brokerRatesByMetrics takes a *ReplicationThrottleMeta, bmapBundle,
+// broker metrics and disk based capacity (0 if uncapped) and returns the
+// leader and follower throttles of each broker, determined from the
+// outbound and inbound headroom of the broker, respectively. If inbound
+// throttles aren't managed, the follower throttle is the leader throttle.
+func brokerRatesByMetrics(rtm *ReplicationThrottleMeta, bmb bmapBundle, bm kafkametrics.BrokerMetrics, diskCap float64) (brokerRates, error) {
+	rates := brokerRates{}
+
+	for id := range bmb.all {
+		b, exists := bm[id]
+		if !exists {
+			return nil, fmt.Errorf("Broker %d not found in broker metrics", id)
+		}
+
+		r, err := rtm.limits.headroom(b, rtm.throttles[id])
+		if err != nil {
+			return nil, fmt.Errorf("Error determining throttle for broker %d: %s", id, err)
+		}
 
+		fr := r
+		if rtm.inbound {
+			fr, err = rtm.limits.inboundHeadroom(b, rtm.followerThrottles[id])
+			if err != nil {
+				return nil, fmt.Errorf("Error determining follower throttle for broker %d: %s", id, err)
+			}
+		}
+
+		if diskCap > 0 {
+			r, fr = math.Min(r, diskCap), math.Min(fr, diskCap)
+		}
+
+		rates[id] = [2]float64{r, fr}
+	}
+
+	return rates, nil
+}
+
+// diskCapacityByMetrics finds the dst broker with the highest disk utilization
+// and returns a replication capacity based on disk utilization, whether the
+// capacity could be determined, an event string and any errors if encountered.
+func diskCapacityByMetrics(rtm *ReplicationThrottleMeta, bmb bmapBundle, bm kafkametrics.BrokerMetrics) (float64, bool, string, error) {
 	var event string
 
+	participatingBrokers, err := reassigningBrokers(bmb, bm)
+	if err != nil {
+		return 0.00, false, event, err
+	}
+
+	constrainingDst := participatingBrokers.highestDstDiskUtil()
+	if constrainingDst == nil {
+		return 0.00, false, "No destination brokers with disk utilization metrics", nil
+	}
+
+	// The follower throttle bounds replication
+	// writes on the destination broker.
+	currThrottle := rtm.followerThrottles[constrainingDst.ID]
+
+	capacity, capped := rtm.limits.diskHeadroom(constrainingDst, currThrottle)
+
+	event = fmt.Sprintf("Most utilized destination disk: "+
+		"[%d] disk utilization of %.2f%% (over %ds) with an existing follower throttle rate of %.2fMB/s",
+		constrainingDst.ID, constrainingDst.DiskUtil, Config.MetricsWindow, currThrottle)
+
+	return capacity, capped, event, nil
+}
+
+// constrainingBrokers is a reassigningBrokers variant for callers that
+// have already validated the BrokerMetrics via reassigningBrokers.
+func constrainingBrokers(bmb bmapBundle, bm kafkametrics.BrokerMetrics) *ReassigningBrokers {
+	rb, _ := reassigningBrokers(bmb, bm)
+	return rb
+}
+
+// reassigningBrokers takes a bmapBundle and kafkametrics.BrokerMetrics and
+// returns a *ReassigningBrokers of the src and dst brokers. An error is
+// returned if any broker is missing from the BrokerMetrics.
+func reassigningBrokers(bmb bmapBundle, bm kafkametrics.BrokerMetrics) (*ReassigningBrokers, error) {
+	// Map src/dst broker IDs to a *ReassigningBrokers.
+	participatingBrokers := &ReassigningBrokers{}
+
 	// Source brokers.
 	for b := range bmb.src {
 		if broker, exists := bm[b]; exists {
 			participatingBrokers.Src = append(participatingBrokers.Src, broker)
 		} else {
-			return 0.00, 0.00, event, fmt.Errorf("Broker %d not found in broker metrics", b)
+			return nil, fmt.Errorf("Broker %d not found in broker metrics", b)
 		}
 	}
 
@@ -314,28 +886,38 @@ func repCapacityByMetrics(rtm *ReplicationThrottleMeta, bmb bmapBundle, bm kafka
 		if broker, exists := bm[b]; exists {
 			participatingBrokers.Dst = append(participatingBrokers.Dst, broker)
 		} else {
-			return 0.00, 0.00, event, fmt.Errorf("Broker %d not found in broker metrics", b)
+			return nil, fmt.Errorf("Broker %d not found in broker metrics", b)
 		}
 	}
 
-	// Get the most constrained src broker and
-	// its current throttle, if applied.
-	constrainingSrc := participatingBrokers.highestSrcNetTX()
-	currThrottle, exists := rtm.throttles[constrainingSrc.ID]
-	if !exists {
-		currThrottle = 0.00
-	}
+	return participatingBrokers, nil
+}
 
-	replicationCapacity, err := rtm.limits.headroom(constrainingSrc, currThrottle)
-	if err != nil {
-		return 0.00, 0.00, event, err
+// setBrokerMetrics exports the network metrics and
+// capacity of the brokers participating in replication.
+func setBrokerMetrics(ids []int, bm kafkametrics.BrokerMetrics, l Limits) {
+	for _, n := range []string{metricNetTX, metricNetRX, metricDiskUtil, metricNetCapacity} {
+		metrics.Reset(n)
 	}
 
-	event = fmt.Sprintf("Most utilized source broker: "+
-		"[%d] net tx of %.2fMB/s (over %ds) with an existing throttle rate of %.2fMB/s",
-		constrainingSrc.ID, constrainingSrc.NetTX, Config.MetricsWindow, currThrottle)
+	for _, id := range ids {
+		b, exists := bm[id]
+		if !exists {
+			continue
+		}
 
-	return replicationCapacity, currThrottle, event, nil
+		broker := strconv.Itoa(id)
+		metrics.Set(metricNetTX, b.NetTX*1000000.00, "broker", broker)
+		metrics.Set(metricNetRX, b.NetRX*1000000.00, "broker", broker)
+
+		if b.DiskUtil > 0 {
+			metrics.Set(metricDiskUtil, b.DiskUtil, "broker", broker)
+		}
+
+		if c, known := l.capacity(b); known {
+			metrics.Set(metricNetCapacity, c*1000000.00, "broker", broker)
+		}
+	}
 }
 
 // applyTopicThrottles updates the throttled brokers list for
@@ -350,7 +932,7 @@ func repCapacityByMetrics(rtm *ReplicationThrottleMeta, bmb bmapBundle, bm kafka
 // (a throttle list is applied) when a topic is initially set
 // for reassignment and cleared by autothrottle as soon as
 // the reassignment is done).
-func applyTopicThrottles(throttled map[string]map[string][]string, zk kafkazk.Handler) []string {
+func applyTopicThrottles(throttled map[string]map[string][]string, cu ConfigUpdater) []string {
 	var errs []string
 
 	for t := range throttled {
@@ -374,7 +956,7 @@ func applyTopicThrottles(throttled map[string]map[string][]string, zk kafkazk.Ha
 		}
 
 		// Write the config.
-		_, err := zk.UpdateKafkaConfig(config)
+		_, err := cu.UpdateKafkaConfig(config)
 		if err != nil {
 			errs = append(errs, fmt.Sprintf("Error setting throttle list on topic %s: %s\n", t, err))
 		}
@@ -383,39 +965,70 @@ func applyTopicThrottles(throttled map[string]map[string][]string, zk kafkazk.Ha
 	return errs
 }
 
-// applyBrokerThrottles take a list of brokers, a replication throttle rate string,
-// rate, map of applied throttles, and zk kafkazk.Handler zookeeper client.
-// For each broker, the throttle rate is applied and if successful, the rate
-// is stored in the throttles map for future reference.
-func applyBrokerThrottles(bs map[int]struct{}, ratestr string, r float64, ts map[int]float64, zk kafkazk.Handler) []string {
+// applyBrokerThrottles take a list of brokers, a leader and follower replication
+// throttle rate, optional per broker rates, the *ReplicationThrottleMeta holding
+// the applied throttles and broker overrides, and a ConfigUpdater. For each broker,
+// the throttle rates (the broker's rates if present in the brokerRates, or the
+// broker's override rate) are applied and if successful, the rates are stored in
+// the throttles maps for future reference.
+func applyBrokerThrottles(bs map[int]struct{}, leader, follower float64, rates brokerRates, params *ReplicationThrottleMeta, cu ConfigUpdater) []string {
 	var errs []string
 
 	// Generate a broker throttle config.
 	for b := range bs {
+		r, fr := leader, follower
+		if br, exists := rates[b]; exists {
+			r, fr = br[0], br[1]
+		}
+
+		o, overridden := params.brokerOverrides[b]
+		if overridden {
+			r, fr = float64(o.Rate), float64(o.Rate)
+		}
+
+		// Get rate strings.
+		ratestr := fmt.Sprintf("%.0f", r*1000000.00)
+		followerRatestr := fmt.Sprintf("%.0f", fr*1000000.00)
+
 		config := kafkazk.KafkaConfig{
 			Type: "broker",
 			Name: strconv.Itoa(b),
 			Configs: [][2]string{
 				[2]string{"leader.replication.throttled.rate", ratestr},
-				[2]string{"follower.replication.throttled.rate", ratestr},
+				[2]string{"follower.replication.throttled.rate", followerRatestr},
 			},
 		}
 
 		// Write the throttle config.
-		changed, err := zk.UpdateKafkaConfig(config)
+		changed, err := cu.UpdateKafkaConfig(config)
 		if err != nil {
 			errs = append(errs, fmt.Sprintf("Error setting throttle on broker %d: %s\n", b, err))
 		}
 
 		if changed {
-			// Store the configured rate.
-			ts[b] = r
-			log.Printf("Updated throttle to %0.2fMB/s on broker %d\n", r, b)
+			// Store the configured rates.
+			params.throttles[b] = r
+			params.followerThrottles[b] = fr
+			metrics.Set(metricThrottleRate, r*1000000.00, "broker", strconv.Itoa(b), "type", "leader")
+			metrics.Set(metricThrottleRate, fr*1000000.00, "broker", strconv.Itoa(b), "type", "follower")
+			switch {
+			case overridden:
+				log.Printf("Updated throttle to %0.2fMB/s (override) on broker %d\n", r, b)
+			case r != fr:
+				log.Printf("Updated throttle to %0.2fMB/s (leader), %0.2fMB/s (follower) on broker %d\n", r, fr, b)
+			default:
+				log.Printf("Updated throttle to %0.2fMB/s on broker %d\n", r, b)
+			}
 		}
 
 		// Hard coded sleep to reduce
 		// ZK load.
-		time.Sleep(250 * time.Millisecond)
+		time.Sleep(configWriteDelay)
+	}
+
+	params.appliedOverrides = BrokerOverrides{}
+	for id, o := range params.brokerOverrides {
+		params.appliedOverrides[id] = o
 	}
 
 	return errs
@@ -445,14 +1058,14 @@ func removeAllThrottles(zk kafkazk.Handler, params *ReplicationThrottleMeta) err
 		}
 
 		// Update the config.
-		_, err := zk.UpdateKafkaConfig(config)
+		_, err := params.configs.UpdateKafkaConfig(config)
 		if err != nil {
 			log.Printf("Error removing throttle config on topic %s: %s\n", topic, err)
 		}
 
 		// Hardcoded sleep to reduce
 		// ZK load.
-		time.Sleep(250 * time.Millisecond)
+		time.Sleep(configWriteDelay)
 	}
 
 	/**********************
@@ -478,7 +1091,7 @@ func removeAllThrottles(zk kafkazk.Handler, params *ReplicationThrottleMeta) err
 			},
 		}
 
-		changed, err := zk.UpdateKafkaConfig(config)
+		changed, err := params.configs.UpdateKafkaConfig(config)
 		switch err.(type) {
 		case nil:
 		case kafkazk.ErrNoNode:
@@ -501,7 +1114,7 @@ func removeAllThrottles(zk kafkazk.Handler, params *ReplicationThrottleMeta) err
 
 		// Hardcoded sleep to reduce
 		// ZK load.
-		time.Sleep(250 * time.Millisecond)
+		time.Sleep(configWriteDelay)
 	}
 
 	// Write event.
@@ -518,11 +1131,30 @@ func removeAllThrottles(zk kafkazk.Handler, params *ReplicationThrottleMeta) err
 		return errors.New("one or more throttles were not cleared")
 	}
 
+	removed := map[int]struct{}{}
+	for _, id := range unthrottledBrokers {
+		removed[id] = struct{}{}
+	}
+
+	prevRates := params.currentRates(removed)
+
 	// Unset all stored throttle rates.
 	for b := range params.throttles {
 		params.throttles[b] = 0.0
 	}
 
+	for b := range params.followerThrottles {
+		params.followerThrottles[b] = 0.0
+	}
+
+	auditThrottleChanges(params, prevRates, []string{"Throttles removed"})
+
+	params.appliedOverrides = BrokerOverrides{}
+	params.resetControllers()
+
+	metrics.Reset(metricThrottleRate)
+	metrics.Reset(metricHeadroom)
+
 	return nil
 }
 
@@ -4,17 +4,75 @@ import (
 	"fmt"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/honeycombio/kafka-kit/kafkametrics"
 	"github.com/honeycombio/kafka-kit/kafkazk"
 )
 
-func TestHighestSrcNetTX(t *testing.T) {
+func TestMostConstrainedSrc(t *testing.T) {
 	reassigning := mockReassigningBrokers()
 
-	b := reassigning.highestSrcNetTX()
-	if b.ID != 1004 {
-		t.Errorf("Expected broker ID 1004, got %d", b.ID)
+	l, _ := NewLimits(NewLimitsConfig{
+		Minimum:     10,
+		Maximum:     90,
+		CapacityMap: map[string]float64{"mock": 120.00},
+	})
+
+	b, h, err := reassigning.mostConstrainedSrc(l, map[int]float64{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b.ID != 1004 || fmt.Sprintf("%.2f", h) != "32.40" {
+		t.Errorf("Expected broker ID 1004 with 32.40 headroom, got %d with %.2f", b.ID, h)
+	}
+
+	// The least utilized broker has
+	// the least capacity.
+	reassigning.Src[0].InstanceType = "small"
+	l["small"] = 90.00
+
+	b, h, _ = reassigning.mostConstrainedSrc(l, map[int]float64{})
+	if b.ID != 1000 || h != 10.00 {
+		t.Errorf("Expected broker ID 1000 with 10.00 headroom, got %d with %.2f", b.ID, h)
+	}
+
+	// Unknown instance type.
+	delete(l, "small")
+	if _, _, err := reassigning.mostConstrainedSrc(l, map[int]float64{}); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
+
+func TestMostConstrainedDst(t *testing.T) {
+	reassigning := mockReassigningBrokers()
+
+	l, _ := NewLimits(NewLimitsConfig{
+		Minimum:     10,
+		Maximum:     90,
+		CapacityMap: map[string]float64{"mock": 120.00},
+	})
+
+	b, h, _ := reassigning.mostConstrainedDst(l, map[int]float64{})
+	if b.ID != 1000 || h != 36.00 {
+		t.Errorf("Expected broker ID 1000 with 36.00 headroom, got %d with %.2f", b.ID, h)
+	}
+
+	// Throttled inbound replication
+	// isn't included in headroom.
+	b, _, _ = reassigning.mostConstrainedDst(l, map[int]float64{1000: 20.00})
+	if b.ID != 1001 {
+		t.Errorf("Expected broker ID 1001, got %d", b.ID)
+	}
+
+	// No inbound metrics.
+	for _, b := range reassigning.Dst {
+		b.NetRX = 0
+	}
+
+	if b, _, _ := reassigning.mostConstrainedDst(l, map[int]float64{}); b != nil {
+		t.Errorf("Expected nil broker, got %d", b.ID)
 	}
 }
 
@@ -30,6 +88,7 @@ func mockReassigningBrokers() ReassigningBrokers {
 			Host:         fmt.Sprintf("host%d", i),
 			InstanceType: "mock",
 			NetTX:        float64(80 + i),
+			NetRX:        float64(80 - i),
 		}
 
 		r.Src = append(r.Src, b)
@@ -203,18 +262,22 @@ func TestRepCapacityByMetrics(t *testing.T) {
 	l, _ := NewLimits(c)
 
 	rtm := &ReplicationThrottleMeta{
-		limits: l,
-		throttles: map[int]float64{
-			1004: 80.00,
-		},
+		limits:    l,
+		throttles: map[int]float64{},
 	}
 
 	bmb := mockBmapBundle()
 
+	for id := range bmb.src {
+		rtm.throttles[id] = 80.00
+	}
+
 	km := &kafkametrics.Mock{}
 	bm, _ := km.GetMetrics()
 
-	// Test normal scenario.
+	// Test normal scenario. Broker 1004 has the
+	// highest outbound throughput and the least
+	// headroom of the src brokers.
 	cap, curr, _, _ := repCapacityByMetrics(rtm, bmb, bm)
 	if cap != 86.40 {
 		t.Errorf("Expected capacity of 86.40, got %.2f", cap)
@@ -239,6 +302,183 @@ func TestRepCapacityByMetrics(t *testing.T) {
 	}
 }
 
+func TestInboundCapacityByMetrics(t *testing.T) {
+	// Setup.
+	c := NewLimitsConfig{
+		Minimum: 20,
+		Maximum: 90,
+		CapacityMap: map[string]float64{
+			"mock": 120.00,
+		},
+	}
+
+	l, _ := NewLimits(c)
+
+	rtm := &ReplicationThrottleMeta{
+		limits:            l,
+		followerThrottles: map[int]float64{},
+	}
+
+	bmb := mockBmapBundle()
+
+	for id := range bmb.dst {
+		rtm.followerThrottles[id] = 80.00
+	}
+
+	km := &kafkametrics.Mock{}
+	bm, _ := km.GetMetrics()
+
+	// Broker 1005 has the highest inbound throughput
+	// and the least headroom of the dst brokers.
+	cap, curr, _, _ := inboundCapacityByMetrics(rtm, bmb, bm)
+	if cap != 103.50 {
+		t.Errorf("Expected capacity of 103.50, got %.2f", cap)
+	}
+
+	if curr != 80.00 {
+		t.Errorf("Expected current capacity of 80.00, got %.2f", curr)
+	}
+
+	// Test with a missing broker in the broker metrics.
+	delete(bm, 1005)
+	_, _, _, err := inboundCapacityByMetrics(rtm, bmb, bm)
+	if err.Error() != "Broker 1005 not found in broker metrics" {
+		t.Errorf("Expected error 'Broker 1005 not found in broker metrics', got '%s'", err.Error())
+	}
+}
+
+func TestBrokerRatesByMetrics(t *testing.T) {
+	// Setup.
+	c := NewLimitsConfig{
+		Minimum: 20,
+		Maximum: 90,
+		CapacityMap: map[string]float64{
+			"mock": 120.00,
+		},
+	}
+
+	l, _ := NewLimits(c)
+
+	rtm := &ReplicationThrottleMeta{
+		limits:            l,
+		inbound:           true,
+		throttles:         map[int]float64{1004: 80.00},
+		followerThrottles: map[int]float64{1005: 80.00},
+	}
+
+	bmb := mockBmapBundle()
+
+	km := &kafkametrics.Mock{}
+	bm, _ := km.GetMetrics()
+
+	rates, err := brokerRatesByMetrics(rtm, bmb, bm, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rates) != 10 {
+		t.Errorf("Expected 10 brokers, got %d", len(rates))
+	}
+
+	// 1004: net tx 104, rx 86. 1005: net tx 105, rx 85.
+	expected := brokerRates{
+		1004: [2]float64{86.40, 30.60},
+		1005: [2]float64{20.00, 103.50},
+	}
+
+	for id, r := range expected {
+		if fmt.Sprintf("%.2f %.2f", rates[id][0], rates[id][1]) != fmt.Sprintf("%.2f %.2f", r[0], r[1]) {
+			t.Errorf("Expected broker %d rates %v, got %v", id, r, rates[id])
+		}
+	}
+
+	// Capped by disk utilization.
+	rates, _ = brokerRatesByMetrics(rtm, bmb, bm, 50)
+	expected = brokerRates{
+		1004: [2]float64{50.00, 30.60},
+		1005: [2]float64{20.00, 50.00},
+	}
+
+	for id, r := range expected {
+		if fmt.Sprintf("%.2f %.2f", rates[id][0], rates[id][1]) != fmt.Sprintf("%.2f %.2f", r[0], r[1]) {
+			t.Errorf("Expected broker %d rates %v, got %v", id, r, rates[id])
+		}
+	}
+
+	// The follower throttle is the leader
+	// throttle without inbound metrics.
+	rtm.inbound = false
+	rates, _ = brokerRatesByMetrics(rtm, bmb, bm, 0)
+	if rates[1004][0] != rates[1004][1] {
+		t.Errorf("Expected equal rates, got %v", rates[1004])
+	}
+
+	delete(bm, 1009)
+	if _, err := brokerRatesByMetrics(rtm, bmb, bm, 0); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
+
+func TestBrokerRatesMaxChange(t *testing.T) {
+	rates := brokerRates{
+		1001: [2]float64{110, 50},
+		1002: [2]float64{100, 75},
+	}
+
+	d, df := rates.maxChange(map[int]float64{1001: 100, 1002: 100}, map[int]float64{1001: 50, 1002: 100})
+	if d != 10 || df != 25 {
+		t.Errorf("Expected 10, 25, got %.2f, %.2f", d, df)
+	}
+
+	if ids := rates.IDs(); len(ids) != 2 || ids[0] != 1001 {
+		t.Errorf("Unexpected IDs %v", ids)
+	}
+}
+
+func TestDiskCapacityByMetrics(t *testing.T) {
+	// Setup.
+	c := NewLimitsConfig{
+		Minimum:     20,
+		Maximum:     90,
+		MaxDiskUtil: 29.5,
+		CapacityMap: map[string]float64{
+			"mock": 120.00,
+		},
+	}
+
+	l, _ := NewLimits(c)
+
+	rtm := &ReplicationThrottleMeta{
+		limits:            l,
+		followerThrottles: map[int]float64{},
+	}
+
+	bmb := mockBmapBundle()
+
+	km := &kafkametrics.Mock{}
+	bm, _ := km.GetMetrics()
+
+	// Without a previous throttle,
+	// a capacity can't be determined.
+	if _, capped, _, _ := diskCapacityByMetrics(rtm, bmb, bm); capped {
+		t.Error("Unexpected disk capacity")
+	}
+
+	// Broker 1009 has the highest disk
+	// utilization (59%) of the dst brokers.
+	rtm.followerThrottles[1009] = 100.00
+	cap, capped, _, _ := diskCapacityByMetrics(rtm, bmb, bm)
+	if !capped || cap != 50.00 {
+		t.Errorf("Expected capacity of 50.00, got %.2f", cap)
+	}
+
+	// Capacity is floored at the minimum.
+	bm[1009].DiskUtil = 200
+	if cap, _, _, _ := diskCapacityByMetrics(rtm, bmb, bm); cap != 20.00 {
+		t.Errorf("Expected capacity of 20.00, got %.2f", cap)
+	}
+}
+
 // func TestApplyTopicThrottles(t *testing.T) {}
 // func TestApplyBrokerThrottles(t *testing.T) {}
 // func TestRemoveAllThrottles(t *testing.T) {}
@@ -261,3 +501,57 @@ func TestSliceToString(t *testing.T) {
 		t.Errorf("Expected string 'one,two', got '%s'", out)
 	}
 }
+
+func TestPreviousThrottles(t *testing.T) {
+	rtm := &ReplicationThrottleMeta{
+		throttles:         map[int]float64{1001: 50, 1002: 80, 1003: 200},
+		followerThrottles: map[int]float64{1001: 40, 1002: 60, 1003: 200},
+	}
+
+	l, f := rtm.previousThrottles(map[int]struct{}{1001: {}, 1002: {}, 1004: {}})
+	if l != 80 || f != 60 {
+		t.Errorf("Expected 80, 60, got %.2f, %.2f", l, f)
+	}
+
+	l, f = rtm.previousThrottles(map[int]struct{}{1004: {}})
+	if l != 0 || f != 0 {
+		t.Errorf("Expected 0, 0, got %.2f, %.2f", l, f)
+	}
+}
+
+func TestThrottleCooldown(t *testing.T) {
+	now := time.Now()
+	rtm := &ReplicationThrottleMeta{
+		throttles:         map[int]float64{1001: 100, 1002: 100},
+		followerThrottles: map[int]float64{1001: 100, 1002: 100},
+	}
+
+	rtm.lastChange = now.Add(-time.Minute)
+	if rtm.inCooldown(now) {
+		t.Error("Unexpected cooldown")
+	}
+
+	rtm.cooldown = 5 * time.Minute
+	if !rtm.inCooldown(now) {
+		t.Error("Expected cooldown")
+	}
+
+	if rtm.inCooldown(now.Add(5 * time.Minute)) {
+		t.Error("Unexpected cooldown")
+	}
+
+	ids := map[int]struct{}{1001: struct{}{}, 1002: struct{}{}}
+
+	if rtm.decreases(ids, 110, 100, nil) {
+		t.Error("Unexpected decrease")
+	}
+
+	if !rtm.decreases(ids, 110, 90, nil) {
+		t.Error("Expected decrease")
+	}
+
+	rates := brokerRates{1002: [2]float64{90, 110}}
+	if !rtm.decreases(ids, 110, 110, rates) {
+		t.Error("Expected decrease")
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
+)
+
+// tlsConfig takes an optional CA certificate path and client certificate
+// and key paths and returns a *tls.Config. The system roots are used if
+// no CA certificate is specified. A client certificate (for mTLS) must
+// be specified along with its key.
+func tlsConfig(caCert, cert, key string) (*tls.Config, error) {
+	c := &tls.Config{}
+
+	if caCert != "" {
+		pem, err := ioutil.ReadFile(caCert)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading CA certificate: %s", err)
+		}
+
+		c.RootCAs = x509.NewCertPool()
+		if !c.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("No valid certificates found in %s", caCert)
+		}
+	}
+
+	switch {
+	case cert != "" && key != "":
+		pair, err := tls.LoadX509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading client certificate: %s", err)
+		}
+
+		c.Certificates = []tls.Certificate{pair}
+	case cert != "" || key != "":
+		return nil, errors.New("A client certificate and key must be specified together")
+	}
+
+	return c, nil
+}
+
+// kafkaTLSConfig returns the *tls.Config for Kafka connections
+// from the kafka-tls flags, or nil if TLS isn't enabled.
+func kafkaTLSConfig() (*tls.Config, error) {
+	if !Config.KafkaTLS && Config.KafkaTLSCACert == "" && Config.KafkaTLSCert == "" {
+		return nil, nil
+	}
+
+	return tlsConfig(Config.KafkaTLSCACert, Config.KafkaTLSCert, Config.KafkaTLSKey)
+}
+
+// kafkaSASLConfig returns the *kafkaadmin.SASLConfig from
+// the kafka-sasl flags, or nil if SASL isn't enabled.
+func kafkaSASLConfig() *kafkaadmin.SASLConfig {
+	if Config.KafkaSASLMechanism == "" {
+		return nil
+	}
+
+	return &kafkaadmin.SASLConfig{
+		Mechanism: Config.KafkaSASLMechanism,
+		Username:  Config.KafkaSASLUsername,
+		Password:  Config.KafkaSASLPassword,
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCertificate writes a self-signed PEM certificate
+// and key to dir, returning the certificate and key paths.
+func writeTestCertificate(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "autothrottle"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPath, keyPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := ioutil.WriteFile(certPath, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := ioutil.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return certPath, keyPath
+}
+
+func TestTLSConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "autothrottle-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	cert, key := writeTestCertificate(t, dir)
+
+	// System roots.
+	c, err := tlsConfig("", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.RootCAs != nil || len(c.Certificates) != 0 {
+		t.Error("Expected system roots and no client certificate")
+	}
+
+	// CA and client certificate.
+	c, err = tlsConfig(cert, cert, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.RootCAs == nil {
+		t.Error("Expected non-nil RootCAs")
+	}
+
+	if len(c.Certificates) != 1 {
+		t.Errorf("Expected 1 client certificate, got %d", len(c.Certificates))
+	}
+
+	// Errors.
+	tests := [][3]string{
+		{filepath.Join(dir, "missing.pem"), "", ""},
+		// Not a certificate.
+		{key, "", ""},
+		// Certificate without a key.
+		{"", cert, ""},
+		{"", cert, cert},
+	}
+
+	for i, test := range tests {
+		if _, err := tlsConfig(test[0], test[1], test[2]); err == nil {
+			t.Errorf("[test %d] Expected non-nil error", i)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"compress/gzip"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -30,6 +31,17 @@ type Config struct {
 	Verbose     bool
 	DryRun      bool
 	Compression bool
+
+	// ZooKeeper TLS; see kafkazk.Config.
+	ZKTLS           bool
+	ZKTLSCACert     string
+	ZKTLSCert       string
+	ZKTLSKey        string
+	ZKTLSServerName string
+
+	// Optional; the broker storage
+	// capacity isn't fetched if empty.
+	BrokerCapacityQuery string
 }
 
 var config = &Config{} // :(
@@ -38,11 +50,17 @@ func init() {
 	flag.StringVar(&config.APIKey, "api-key", "", "Datadog API key")
 	flag.StringVar(&config.AppKey, "app-key", "", "Datadog app key")
 	bq := flag.String("broker-storage-query", "avg:system.disk.free{service:kafka,device:/data}", "Datadog metric query to get broker storage free")
+	bcq := flag.String("broker-storage-capacity-query", "avg:system.disk.total{service:kafka,device:/data}", "Datadog metric query to get broker storage capacity (empty string disables)")
 	flag.StringVar(&config.BrokerIDTag, "broker-id-tag", "broker_id", "Datadog host tag for broker ID")
 	pq := flag.String("partition-size-query", "max:kafka.log.partition.size{service:kafka} by {topic,partition}", "Datadog metric query to get partition size by topic, partition")
 	flag.IntVar(&config.Span, "span", 3600, "Query range in seconds (now - span)")
 	flag.StringVar(&config.ZKAddr, "zk-addr", "localhost:2181", "ZooKeeper connect string")
 	flag.StringVar(&config.ZKPrefix, "zk-prefix", "topicmappr", "ZooKeeper namespace prefix")
+	flag.BoolVar(&config.ZKTLS, "zk-tls", false, "Connect to ZooKeeper with TLS (implied by the other zk-tls flags)")
+	flag.StringVar(&config.ZKTLSCACert, "zk-tls-ca-cert", "", "Path to a PEM CA certificate for verifying ZooKeeper servers; the system roots are used if empty")
+	flag.StringVar(&config.ZKTLSCert, "zk-tls-cert", "", "Path to a PEM client certificate for ZooKeeper mTLS (requires -zk-tls-key)")
+	flag.StringVar(&config.ZKTLSKey, "zk-tls-key", "", "Path to a PEM client key for ZooKeeper mTLS")
+	flag.StringVar(&config.ZKTLSServerName, "zk-tls-server-name", "", "Server name verified against ZooKeeper server certificates; the -zk-addr host is used if empty")
 	flag.BoolVar(&config.Verbose, "verbose", false, "Verbose output")
 	flag.BoolVar(&config.DryRun, "dry-run", false, "Dry run mode (don't reach Zookeeper)")
 	flag.BoolVar(&config.Compression, "compression", true, "Whether to compress metrics data written to ZooKeeper")
@@ -52,6 +70,9 @@ func init() {
 
 	// Complete query string.
 	config.BrokerQuery = fmt.Sprintf("%s by {%s}.rollup(avg, %d)", *bq, config.BrokerIDTag, config.Span)
+	if *bcq != "" {
+		config.BrokerCapacityQuery = fmt.Sprintf("%s by {%s}.rollup(avg, %d)", *bcq, config.BrokerIDTag, config.Span)
+	}
 	config.PartnQuery = fmt.Sprintf("%s.rollup(avg, %d)", *pq, config.Span)
 }
 
@@ -68,9 +89,19 @@ func main() {
 	// Init ZK client.
 	var zk kafkazk.Handler
 	if !config.DryRun {
-		zk, err = kafkazk.NewHandler(&kafkazk.Config{
-			Connect: config.ZKAddr,
-		})
+		zc := &kafkazk.Config{
+			Connect:       config.ZKAddr,
+			TLSCACert:     config.ZKTLSCACert,
+			TLSCert:       config.ZKTLSCert,
+			TLSKey:        config.ZKTLSKey,
+			TLSServerName: config.ZKTLSServerName,
+		}
+
+		if config.ZKTLS {
+			zc.TLS = &tls.Config{}
+		}
+
+		zk, err = kafkazk.NewHandler(zc)
 		exitOnErr(err)
 	}
 
@@ -91,6 +122,9 @@ func main() {
 	exitOnErr(err)
 
 	fmt.Printf("Submitting %s\n", config.BrokerQuery)
+	if config.BrokerCapacityQuery != "" {
+		fmt.Printf("Submitting %s\n", config.BrokerCapacityQuery)
+	}
 	bm, err := brokerMetrics(config)
 	exitOnErr(err)
 	fmt.Println("success")
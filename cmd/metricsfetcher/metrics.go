@@ -37,14 +37,30 @@ func partitionMetrics(c *Config) (map[string]map[string]map[string]float64, erro
 }
 
 func brokerMetrics(c *Config) (map[string]map[string]float64, error) {
-	start := time.Now().Add(-time.Duration(c.Span) * time.Second).Unix()
-	o, err := c.Client.QueryMetrics(start, time.Now().Unix(), c.BrokerQuery)
-	if err != nil {
+	// Populate.
+	d := map[string]map[string]float64{}
+
+	if err := brokerMetric(c, c.BrokerQuery, "StorageFree", d); err != nil {
 		return nil, err
 	}
 
-	// Populate.
-	d := map[string]map[string]float64{}
+	if c.BrokerCapacityQuery != "" {
+		if err := brokerMetric(c, c.BrokerCapacityQuery, "StorageCapacity", d); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// brokerMetric runs the query q and populates the
+// results into d under the metric name k.
+func brokerMetric(c *Config, q, k string, d map[string]map[string]float64) error {
+	start := time.Now().Add(-time.Duration(c.Span) * time.Second).Unix()
+	o, err := c.Client.QueryMetrics(start, time.Now().Unix(), q)
+	if err != nil {
+		return err
+	}
 
 	for _, ts := range o {
 		broker := tagValFromScope(ts.GetScope(), c.BrokerIDTag)
@@ -62,10 +78,10 @@ func brokerMetrics(c *Config) (map[string]map[string]float64, error) {
 			d[broker] = map[string]float64{}
 		}
 
-		d[broker]["StorageFree"] = *ts.Points[0][1]
+		d[broker][k] = *ts.Points[0][1]
 	}
 
-	return d, nil
+	return nil
 }
 
 // tagValFromScope takes a metric scope string
@@ -2,12 +2,19 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"flag"
 	"log"
 	"os"
 	"os/signal"
 	"sync"
+	"time"
 
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+	_ "github.com/honeycombio/kafka-kit/kafkametrics/cruisecontrol"
+	_ "github.com/honeycombio/kafka-kit/kafkametrics/datadog"
 	"github.com/honeycombio/kafka-kit/kafkazk"
 	"github.com/honeycombio/kafka-kit/registry/server"
 
@@ -17,18 +24,101 @@ import (
 func main() {
 	serverConfig := server.Config{}
 	zkConfig := kafkazk.Config{}
+	kafkaConfig := kafkaadmin.Config{ClientID: "registry"}
+	metricsConfig := kafkametrics.Config{}
 
 	flag.StringVar(&serverConfig.HTTPListen, "http-listen", "localhost:8080", "Server HTTP listen address")
+	flag.StringVar(&serverConfig.HTTPCORSOrigins, "http-cors-origins", "", "Comma-delimited list of origins allowed cross-origin HTTP requests (e.g. https://dashboard.example.com); * allows any origin")
 	flag.StringVar(&serverConfig.GRPCListen, "grpc-listen", "localhost:8090", "Server gRPC listen address")
 	flag.IntVar(&serverConfig.ReadReqRate, "read-rate-limit", 5, "Read request rate limit (reqs/s)")
 	flag.IntVar(&serverConfig.WriteReqRate, "write-rate-limit", 1, "Write request rate limit (reqs/s)")
+	flag.IntVar(&serverConfig.MetadataReqRate, "metadata-rate-limit", 2, "Metadata-heavy read request (cluster state, mappings, consumer group, reassignment plan) rate limit (reqs/s); also limited by the read rate limit")
+	flag.IntVar(&serverConfig.ClientReadReqRate, "client-read-rate-limit", 0, "Per-client read request rate limit (reqs/s); clients are identified by authenticated identity or address; unlimited if 0")
+	flag.IntVar(&serverConfig.ClientWriteReqRate, "client-write-rate-limit", 0, "Per-client write request rate limit (reqs/s); unlimited if 0")
 	flag.StringVar(&serverConfig.ZKTagsPrefix, "zk-tags-prefix", "registry", "Tags storage ZooKeeper prefix")
+	flag.StringVar(&serverConfig.TagsBackend, "tags-backend", "zookeeper", "Tags storage backend [zookeeper, kafka, etcd]")
+	flag.StringVar(&serverConfig.TagsKafkaTopic, "tags-kafka-topic", "registry-tags", "Compacted topic used by the kafka tags storage backend; requires --kafka-bootstrap-servers")
+	flag.StringVar(&serverConfig.TagsEtcdEndpoints, "tags-etcd-endpoints", "", "Comma-delimited list of etcd endpoint URLs used by the etcd tags storage backend")
+	flag.StringVar(&serverConfig.TagsEtcdPrefix, "tags-etcd-prefix", "registry", "Tags storage etcd key prefix")
+	flag.BoolVar(&serverConfig.TagsMigrateZK, "tags-migrate-zk", false, "Copy tags stored in ZooKeeper (under --zk-tags-prefix) to the tags storage backend at startup, for objects without tags in the backend")
+	flag.DurationVar(&serverConfig.DeleteIdleWindow, "topic-delete-idle-window", 24*time.Hour, "Topics with messages produced within this window can only be deleted with force")
+	flag.StringVar(&serverConfig.ProtectedTag, "topic-delete-protected-tag", "protected:true", "Topics with this tag (key:value) can only be deleted with force; disabled if empty")
+	flag.DurationVar(&serverConfig.MetadataCacheTTL, "metadata-cache-ttl", 0, "How long topic and broker metadata is cached for topic and broker lookups (e.g. 5s); disabled if 0")
+	flag.BoolVar(&serverConfig.MetadataCacheWatch, "metadata-cache-watch", false, "Cache topic and broker metadata until ZooKeeper watches report a change (or for the --metadata-cache-ttl, if set)")
+	flag.DurationVar(&serverConfig.WatchInterval, "watch-interval", 5*time.Second, "Interval at which ZooKeeper is polled for cluster changes while there are watch subscribers")
+	flag.StringVar(&serverConfig.TLSCertFile, "tls-cert", "", "TLS certificate file for the gRPC and HTTP listeners")
+	flag.StringVar(&serverConfig.TLSKeyFile, "tls-key", "", "TLS key file for the gRPC and HTTP listeners")
+	flag.StringVar(&serverConfig.TLSClientCAFile, "tls-client-ca", "", "CA certificates file for verifying gRPC client certificates; clients are identified by certificate common name")
+	flag.StringVar(&serverConfig.AuthTokensFile, "auth-tokens-file", "", "JSON file of identities to bearer tokens that authenticate requests")
+	flag.BoolVar(&serverConfig.AuthReads, "auth-reads", false, "Require authentication for read requests (write requests require authentication if any method is configured)")
+	flag.BoolVar(&serverConfig.RBAC, "rbac", false, "Authorize write requests against the RBAC policy stored with the tags; requires an authentication method")
+	flag.StringVar(&serverConfig.RBACPolicyFile, "rbac-policy-file", "", "JSON RBAC policy stored with the tags at startup; implies --rbac")
+	flag.StringVar(&serverConfig.AuditLogFile, "audit-log-file", "", "File that audit log entries of mutating requests are appended to; required for audit log queries")
+	flag.StringVar(&serverConfig.StateEventsTopic, "state-events-topic", "", "Kafka topic each registry state change is published to as an event; requires --kafka-bootstrap-servers")
+	rebuildTags := flag.Bool("state-events-rebuild-tags", false, "Restore tags from the --state-events-topic at startup, for objects with tag events")
+	flag.StringVar(&serverConfig.SchemaRegistryURL, "schema-registry-url", "", "Confluent Schema Registry (or compatible) URL; required for topic schema requests")
+	flag.StringVar(&serverConfig.TopicPolicyFile, "topic-policy-file", "", "JSON file of topic policies enforced on topic creation and tag changes")
+	flag.StringVar(&serverConfig.TopicLifecycleFile, "topic-lifecycle-file", "", "JSON file of topic lifecycle rules; required for lifecycle requests")
+	flag.DurationVar(&serverConfig.TopicLifecycleInterval, "topic-lifecycle-interval", 0, "Interval at which topic lifecycle rules are evaluated and enforced; disabled if 0")
+	flag.StringVar(&serverConfig.TagDefaultsFile, "tag-defaults-file", "", "JSON file of tags inherited by topics and brokers from cluster and name prefix level defaults")
+	flag.StringVar(&serverConfig.ClusterName, "cluster-name", "", "Name of the default cluster; required with --clusters-file")
+	flag.StringVar(&serverConfig.ClustersFile, "clusters-file", "", "JSON file of cluster names to ZooKeeper and Kafka configs of additional clusters served by the registry")
+	flag.BoolVar(&serverConfig.LeaderElection, "leader-election", false, "Elect a leader among registries sharing --zk-tags-prefix; only the leader serves write requests, which other registries forward to it, and runs lifecycle enforcement and webhook deliveries")
+	flag.StringVar(&serverConfig.AdvertiseAddr, "advertise-addr", "", "gRPC address other registries forward write requests to while this registry is the leader; must be unique and reachable by other registries; required with --leader-election")
+	flag.StringVar(&serverConfig.PeerTokenFile, "peer-token-file", "", "File containing a token shared by registries that authenticates requests forwarded to the leader; required with --leader-election")
+	flag.StringVar(&serverConfig.LeaderTLSCAFile, "leader-tls-ca", "", "CA certificates file for verifying the leader TLS certificate when forwarding requests; the system roots if unset")
+	flag.StringVar(&serverConfig.WebhooksFile, "webhooks-file", "", "JSON file of webhooks sent broker, topic, config and tag change events")
 	flag.StringVar(&zkConfig.Connect, "zk-addr", "localhost:2181", "ZooKeeper connect string")
 	flag.StringVar(&zkConfig.Prefix, "zk-prefix", "", "ZooKeeper prefix (if Kafka is configured with a chroot path prefix)")
+	flag.StringVar(&zkConfig.MetricsPrefix, "zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics (included in cluster state requests)")
+	zkTLS := flag.Bool("zk-tls", false, "Connect to ZooKeeper with TLS (implied by the other zk-tls flags)")
+	flag.StringVar(&zkConfig.TLSCACert, "zk-tls-ca-cert", "", "Path to a PEM CA certificate for verifying ZooKeeper servers; the system roots are used if empty")
+	flag.StringVar(&zkConfig.TLSCert, "zk-tls-cert", "", "Path to a PEM client certificate for ZooKeeper mTLS (requires --zk-tls-key)")
+	flag.StringVar(&zkConfig.TLSKey, "zk-tls-key", "", "Path to a PEM client key for ZooKeeper mTLS")
+	flag.StringVar(&zkConfig.TLSServerName, "zk-tls-server-name", "", "Server name verified against ZooKeeper server certificates; the --zk-addr host is used if empty")
+	flag.StringVar(&kafkaConfig.BootstrapServers, "kafka-bootstrap-servers", "", "Comma-delimited list of Kafka bootstrap servers; required for consumer group and topic creation requests")
+	flag.BoolVar(&serverConfig.KafkaMetadata, "kafka-metadata", false, "Read cluster metadata via the Kafka Admin API rather than ZooKeeper, e.g. for KRaft clusters; requires --kafka-bootstrap-servers and a kafka or etcd --tags-backend, and can't be used with --leader-election, --clusters-file or --tags-migrate-zk")
+	metricsBackend := flag.String("metrics-backend", "", "Metrics backend for live broker network and disk metrics (e.g. datadog); required for broker utilization requests")
+	metricsParams := flag.String("metrics-params", "", "JSON map of metrics backend specific parameters")
+	flag.StringVar(&metricsConfig.NetworkTXQuery, "metrics-net-tx-query", "avg:system.net.bytes_sent{service:kafka} by {host}", "Metrics query for broker outbound bandwidth by host")
+	flag.StringVar(&metricsConfig.NetworkRXQuery, "metrics-net-rx-query", "avg:system.net.bytes_rcvd{service:kafka} by {host}", "Metrics query for broker inbound bandwidth by host")
+	flag.StringVar(&metricsConfig.DiskUtilQuery, "metrics-disk-util-query", "", "Metrics query for broker disk utilization (percent) by host")
+	flag.StringVar(&metricsConfig.BrokerIDTag, "metrics-broker-id-tag", "broker_id", "Metrics host tag for broker ID")
+	flag.IntVar(&metricsConfig.MetricsWindow, "metrics-window", 120, "Time span of metrics averaged for broker utilization (seconds)")
 
 	envy.Parse("REGISTRY")
 	flag.Parse()
 
+	if *zkTLS {
+		zkConfig.TLS = &tls.Config{}
+	}
+
+	if serverConfig.StateEventsTopic != "" && kafkaConfig.BootstrapServers == "" {
+		log.Fatal("--state-events-topic requires --kafka-bootstrap-servers")
+	}
+
+	if serverConfig.KafkaMetadata && kafkaConfig.BootstrapServers == "" {
+		log.Fatal("--kafka-metadata requires --kafka-bootstrap-servers")
+	}
+
+	// Init the broker metrics backend.
+	if *metricsBackend != "" {
+		metricsConfig.Params = map[string]string{}
+		if *metricsParams != "" {
+			if err := json.Unmarshal([]byte(*metricsParams), &metricsConfig.Params); err != nil {
+				log.Fatalf("Error parsing metrics-params flag: %s", err)
+			}
+		}
+
+		km, err := kafkametrics.NewHandler(*metricsBackend, &metricsConfig)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		serverConfig.BrokerMetrics = km
+		log.Printf("Metrics backend: %s\n", *metricsBackend)
+	}
+
 	log.Println("Registry running")
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -40,8 +130,71 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// Dial ZooKeeper.
-	if err := srvr.DialZK(ctx, wg, &zkConfig); err != nil {
+	// Dial ZooKeeper, unless cluster metadata
+	// is read via the Kafka Admin API.
+	if serverConfig.KafkaMetadata {
+		if err := srvr.DialKafkaMetadata(ctx, wg, &kafkaConfig); err != nil {
+			log.Fatal(err)
+		}
+	} else if err := srvr.DialZK(ctx, wg, &zkConfig); err != nil {
+		log.Fatal(err)
+	}
+
+	// Dial Kafka.
+	if kafkaConfig.BootstrapServers != "" {
+		if err := srvr.DialKafka(&kafkaConfig); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Initialize tag storage.
+	if err := srvr.InitTags(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Initialize the RBAC policy.
+	if err := srvr.InitRBAC(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Dial the federated clusters.
+	if err := srvr.DialClusters(ctx, wg, zkConfig, kafkaConfig); err != nil {
+		log.Fatal(err)
+	}
+
+	// Restore tags from the state events.
+	if *rebuildTags {
+		if err := srvr.RebuildTags(); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Start the health checks.
+	if err := srvr.RunElection(ctx, wg); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := srvr.RunHealthChecks(ctx, wg); err != nil {
+		log.Fatal(err)
+	}
+
+	// Start the watch poller.
+	if err := srvr.RunWatch(ctx, wg); err != nil {
+		log.Fatal(err)
+	}
+
+	// Start the webhook senders.
+	if err := srvr.RunWebhooks(ctx, wg); err != nil {
+		log.Fatal(err)
+	}
+
+	// Start the RBAC policy reloads.
+	if err := srvr.RunRBAC(ctx, wg); err != nil {
+		log.Fatal(err)
+	}
+
+	// Start the lifecycle rule enforcement.
+	if err := srvr.RunLifecycle(ctx, wg); err != nil {
 		log.Fatal(err)
 	}
 
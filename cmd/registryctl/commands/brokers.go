@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+
+	"github.com/spf13/cobra"
+)
+
+func newBrokersCmd() *cobra.Command {
+	brokersCmd := &cobra.Command{
+		Use:   "brokers",
+		Short: "List, describe and tag brokers",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List brokers",
+		Args:  cobra.NoArgs,
+		RunE:  brokersList,
+	}
+
+	listCmd.Flags().StringArray("tag", nil, "Only list brokers with the tag (key:value); may be repeated")
+	listCmd.Flags().String("tag-query", "", `Only list brokers matching the tag expression, e.g. "rack=us-east-1* AND NOT maintenance"`)
+
+	describeCmd := &cobra.Command{
+		Use:   "describe <id>",
+		Short: "Describe the metadata, tags and topics of a broker",
+		Args:  cobra.ExactArgs(1),
+		RunE:  brokersDescribe,
+	}
+
+	tagCmd := &cobra.Command{
+		Use:   "tag <id> <key:value>...",
+		Short: "Set broker tags; other tags are unmodified",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  brokersTag,
+	}
+
+	untagCmd := &cobra.Command{
+		Use:   "untag <id> <key>...",
+		Short: "Delete broker tags",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  brokersUntag,
+	}
+
+	brokersCmd.AddCommand(listCmd, describeCmd, tagCmd, untagCmd)
+
+	return brokersCmd
+}
+
+func brokersList(cmd *cobra.Command, _ []string) error {
+	s, done, err := newSession(cmd)
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	tags, _ := cmd.Flags().GetStringArray("tag")
+	query, _ := cmd.Flags().GetString("tag-query")
+
+	resp, err := s.client.GetBrokers(s.ctx, &pb.BrokerRequest{Tag: tags, TagQuery: query, Cluster: s.cluster})
+	if err != nil {
+		return err
+	}
+
+	if s.json {
+		return s.printJSON(resp)
+	}
+
+	var ids []int
+	for id := range resp.Brokers {
+		ids = append(ids, int(id))
+	}
+
+	sort.Ints(ids)
+
+	var rows [][]string
+	for _, id := range ids {
+		b := resp.Brokers[uint32(id)]
+		rows = append(rows, []string{fmt.Sprint(id), orDash(b.Rack), orDash(b.Host),
+			strconv.FormatBool(b.Maintenance), formatMap(b.Tags)})
+	}
+
+	return s.printTable([]string{"ID", "RACK", "HOST", "MAINTENANCE", "TAGS"}, rows)
+}
+
+func brokersDescribe(cmd *cobra.Command, args []string) error {
+	id, err := parseBrokerID(args[0])
+	if err != nil {
+		return err
+	}
+
+	s, done, err := newSession(cmd)
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	resp, err := s.client.GetBrokers(s.ctx, &pb.BrokerRequest{Id: id, Cluster: s.cluster})
+	if err != nil {
+		return err
+	}
+
+	b, exists := resp.Brokers[id]
+	if !exists {
+		return fmt.Errorf("broker %d does not exist", id)
+	}
+
+	topics, err := s.client.BrokerMappings(s.ctx, &pb.BrokerRequest{Id: id, Cluster: s.cluster})
+	if err != nil {
+		return err
+	}
+
+	if s.json {
+		return s.printJSON(map[string]interface{}{
+			"broker": b,
+			"topics": topics.Names,
+		})
+	}
+
+	return s.printFields([][2]string{
+		{"ID", fmt.Sprint(b.Id)},
+		{"Rack", orDash(b.Rack)},
+		{"Host", orDash(b.Host)},
+		{"Port", fmt.Sprint(b.Port)},
+		{"Endpoints", orDash(strings.Join(b.Endpoints, ","))},
+		{"Maintenance", strconv.FormatBool(b.Maintenance)},
+		{"Tags", formatMap(b.Tags)},
+		{"Topics", orDash(strings.Join(topics.Names, ","))},
+	})
+}
+
+func brokersTag(cmd *cobra.Command, args []string) error {
+	id, err := parseBrokerID(args[0])
+	if err != nil {
+		return err
+	}
+
+	s, done, err := newSession(cmd)
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	resp, err := s.client.TagBroker(s.ctx, &pb.BrokerRequest{Id: id, Tag: args[1:], Cluster: s.cluster})
+	if err != nil {
+		return err
+	}
+
+	return s.printTagResponse(resp, fmt.Sprintf("broker %d tagged", id))
+}
+
+func brokersUntag(cmd *cobra.Command, args []string) error {
+	id, err := parseBrokerID(args[0])
+	if err != nil {
+		return err
+	}
+
+	s, done, err := newSession(cmd)
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	resp, err := s.client.DeleteBrokerTags(s.ctx, &pb.BrokerRequest{Id: id, Tag: args[1:], Cluster: s.cluster})
+	if err != nil {
+		return err
+	}
+
+	return s.printTagResponse(resp, fmt.Sprintf("broker %d tags deleted", id))
+}
+
+func parseBrokerID(s string) (uint32, error) {
+	id, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid broker ID '%s'", s)
+	}
+
+	return uint32(id), nil
+}
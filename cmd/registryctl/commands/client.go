@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// session holds the registry client and
+// request settings of a command.
+type session struct {
+	client  pb.RegistryClient
+	ctx     context.Context
+	cluster string
+	json    bool
+	out     io.Writer
+}
+
+// newClient returns a pb.RegistryClient for the --addr registry
+// and a func that closes its connection. It's replaced in tests.
+var newClient = dialRegistry
+
+// newSession returns a *session for the command and a func
+// that must be called once the command is done with it.
+func newSession(cmd *cobra.Command) (*session, func(), error) {
+	flags := cmd.Flags()
+
+	output, _ := flags.GetString("output")
+	if output != "table" && output != "json" {
+		return nil, nil, fmt.Errorf("invalid output format '%s'", output)
+	}
+
+	timeout, _ := flags.GetDuration("timeout")
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+	if token, _ := flags.GetString("token"); token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+
+	client, closeConn, err := newClient(ctx, cmd)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	cluster, _ := flags.GetString("cluster")
+
+	s := &session{
+		client:  client,
+		ctx:     ctx,
+		cluster: cluster,
+		json:    output == "json",
+		out:     cmd.OutOrStdout(),
+	}
+
+	done := func() {
+		closeConn()
+		cancel()
+	}
+
+	return s, done, nil
+}
+
+// dialRegistry dials the --addr registry within the ctx
+// deadline, with TLS if configured.
+func dialRegistry(ctx context.Context, cmd *cobra.Command) (pb.RegistryClient, func(), error) {
+	addr, _ := cmd.Flags().GetString("addr")
+
+	creds, err := transportCredentials(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn, err := grpc.DialContext(ctx, addr, creds, grpc.WithBlock())
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error connecting to registry %s: %s", addr, err)
+	}
+
+	return pb.NewRegistryClient(conn), func() { conn.Close() }, nil
+}
+
+// transportCredentials returns the grpc.DialOption of the --tls flags.
+func transportCredentials(cmd *cobra.Command) (grpc.DialOption, error) {
+	flags := cmd.Flags()
+
+	useTLS, _ := flags.GetBool("tls")
+	ca, _ := flags.GetString("tls-ca")
+	cert, _ := flags.GetString("tls-cert")
+	key, _ := flags.GetString("tls-key")
+
+	if !useTLS && ca == "" && cert == "" {
+		return grpc.WithInsecure(), nil
+	}
+
+	c := &tls.Config{}
+
+	if ca != "" {
+		data, err := ioutil.ReadFile(ca)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading TLS CA: %s", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.New("No certificates found in TLS CA")
+		}
+
+		c.RootCAs = pool
+	}
+
+	if cert != "" || key != "" {
+		pair, err := tls.LoadX509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading TLS client certificate: %s", err)
+		}
+
+		c.Certificates = []tls.Certificate{pair}
+	}
+
+	return grpc.WithTransportCredentials(credentials.NewTLS(c)), nil
+}
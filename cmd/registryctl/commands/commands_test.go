@@ -0,0 +1,323 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+// registryMock is a pb.RegistryClient with three topics and two brokers.
+// Calls to unmocked methods panic.
+type registryMock struct {
+	pb.RegistryClient
+	// The latest request.
+	req interface{}
+}
+
+var mockTopics = []string{"events", "logs", "metrics"}
+
+func (r *registryMock) ListTopics(ctx context.Context, req *pb.TopicRequest, opts ...grpc.CallOption) (*pb.TopicResponse, error) {
+	r.req = req
+
+	// Pages of at most 2 names.
+	start := 0
+	if req.PageToken != "" {
+		start = 2
+	}
+
+	end := start + 2
+	resp := &pb.TopicResponse{}
+
+	if end < len(mockTopics) {
+		resp.NextPageToken = "page2"
+	} else {
+		end = len(mockTopics)
+	}
+
+	resp.Names = mockTopics[start:end]
+
+	return resp, nil
+}
+
+func (r *registryMock) GetTopics(ctx context.Context, req *pb.TopicRequest, opts ...grpc.CallOption) (*pb.TopicResponse, error) {
+	resp := &pb.TopicResponse{Topics: map[string]*pb.Topic{}}
+	if req.Name == "events" {
+		resp.Topics["events"] = &pb.Topic{
+			Name:        "events",
+			Partitions:  12,
+			Replication: 3,
+			Owner:       "alice",
+			Tags:        map[string]string{"team": "ingest", "owner": "alice"},
+		}
+	}
+
+	return resp, nil
+}
+
+func (r *registryMock) GetTopicConfig(ctx context.Context, req *pb.TopicConfigRequest, opts ...grpc.CallOption) (*pb.TopicConfigResponse, error) {
+	return &pb.TopicConfigResponse{Name: req.Name, Configs: map[string]string{"retention.ms": "86400000"}}, nil
+}
+
+func (r *registryMock) TopicMappings(ctx context.Context, req *pb.TopicRequest, opts ...grpc.CallOption) (*pb.BrokerResponse, error) {
+	return &pb.BrokerResponse{Ids: []uint32{1001, 1002, 1003}}, nil
+}
+
+func (r *registryMock) CreateTopics(ctx context.Context, req *pb.CreateTopicsRequest, opts ...grpc.CallOption) (*pb.CreateTopicsResponse, error) {
+	r.req = req
+
+	resp := &pb.CreateTopicsResponse{DryRun: req.DryRun}
+	for _, t := range req.Topics {
+		resp.Topics = append(resp.Topics, &pb.Topic{Name: t.Name, Partitions: t.Partitions, Replication: t.Replication, Tags: t.Tags})
+	}
+
+	return resp, nil
+}
+
+func (r *registryMock) TagTopic(ctx context.Context, req *pb.TopicRequest, opts ...grpc.CallOption) (*pb.TagResponse, error) {
+	r.req = req
+	return &pb.TagResponse{Message: "success"}, nil
+}
+
+func (r *registryMock) GetBrokers(ctx context.Context, req *pb.BrokerRequest, opts ...grpc.CallOption) (*pb.BrokerResponse, error) {
+	r.req = req
+
+	brokers := map[uint32]*pb.Broker{
+		1002: &pb.Broker{Id: 1002, Rack: "b", Host: "10.0.0.2", Maintenance: true, Tags: map[string]string{"maintenance": "true"}},
+		1001: &pb.Broker{Id: 1001, Rack: "a", Host: "10.0.0.1"},
+	}
+
+	if req.Id != 0 {
+		b, exists := brokers[req.Id]
+		brokers = map[uint32]*pb.Broker{}
+		if exists {
+			brokers[req.Id] = b
+		}
+	}
+
+	return &pb.BrokerResponse{Brokers: brokers}, nil
+}
+
+func (r *registryMock) BrokerMappings(ctx context.Context, req *pb.BrokerRequest, opts ...grpc.CallOption) (*pb.TopicResponse, error) {
+	return &pb.TopicResponse{Names: []string{"events", "logs"}}, nil
+}
+
+// run runs registryctl with the args against
+// the registryMock, returning the output.
+func run(r *registryMock, args ...string) (string, error) {
+	newClient = func(context.Context, *cobra.Command) (pb.RegistryClient, func(), error) {
+		return r, func() {}, nil
+	}
+
+	defer func() { newClient = dialRegistry }()
+
+	var out bytes.Buffer
+
+	cmd := newRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+
+	return out.String(), err
+}
+
+func TestTopicsList(t *testing.T) {
+	r := &registryMock{}
+
+	out, err := run(r, "topics", "list", "--tag", "team:ingest", "--tag", "tier:prod", "--name-regex", "s$", "--cluster", "east")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// All pages are listed.
+	expected := "NAME\nevents\nlogs\nmetrics\n"
+	if out != expected {
+		t.Errorf("Expected output:\n%s\ngot:\n%s", expected, out)
+	}
+
+	req := r.req.(*pb.TopicRequest)
+	if req.PageToken != "page2" || req.NameRegex != "s$" || req.Cluster != "east" || len(req.Tag) != 2 {
+		t.Errorf("Unexpected request %v", req)
+	}
+
+	out, err = run(r, "topics", "list", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp struct{ Names []string }
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Names) != 3 {
+		t.Errorf("Expected 3 names, got %v", resp.Names)
+	}
+}
+
+func TestTopicsDescribe(t *testing.T) {
+	r := &registryMock{}
+
+	out, err := run(r, "topics", "describe", "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"Name:         events",
+		"Partitions:   12",
+		"Replication:  3",
+		"Owner:        alice",
+		"Team:         -",
+		"Tags:         owner=alice,team=ingest",
+		"Configs:      retention.ms=86400000",
+		"Brokers:      1001,1002,1003",
+	}
+
+	if got := strings.Split(strings.TrimSpace(out), "\n"); strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected output:\n%s\ngot:\n%s", strings.Join(expected, "\n"), out)
+	}
+
+	out, err = run(r, "topics", "describe", "events", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp struct {
+		Topic   struct{ Partitions int }
+		Configs map[string]string
+		Brokers []int
+	}
+
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Topic.Partitions != 12 || resp.Configs["retention.ms"] != "86400000" || len(resp.Brokers) != 3 {
+		t.Errorf("Unexpected JSON output %s", out)
+	}
+
+	if _, err := run(r, "topics", "describe", "none"); err == nil || err.Error() != "topic none does not exist" {
+		t.Errorf("Expected topic does not exist error, got '%v'", err)
+	}
+}
+
+func TestTopicsCreate(t *testing.T) {
+	r := &registryMock{}
+
+	out, err := run(r, "topics", "create", "a", "b", "--partitions", "6", "--replication", "3",
+		"--config", "retention.ms=3600000", "--tag", "team:ingest", "--owner", "alice", "--dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := r.req.(*pb.CreateTopicsRequest)
+	if len(req.Topics) != 2 || !req.DryRun {
+		t.Fatalf("Unexpected request %v", req)
+	}
+
+	for _, spec := range req.Topics {
+		if spec.Partitions != 6 || spec.Replication != 3 || spec.Configs["retention.ms"] != "3600000" ||
+			spec.Tags["team"] != "ingest" || spec.Owner != "alice" {
+			t.Errorf("Unexpected topic spec %v", spec)
+		}
+	}
+
+	if !strings.HasPrefix(out, "Dry run") || !strings.Contains(out, "a      6            3             team=ingest") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+
+	// Invalid flags.
+	tests := map[int][]string{
+		0: []string{"topics", "create", "a", "--partitions", "6"},
+		1: []string{"topics", "create", "a", "--partitions", "6", "--replication", "3", "--config", "retention.ms"},
+		2: []string{"topics", "create", "a", "--partitions", "6", "--replication", "3", "--tag", ":x"},
+		3: []string{"topics", "create", "--partitions", "6", "--replication", "3"},
+	}
+
+	for i, args := range tests {
+		if _, err := run(r, args...); err == nil {
+			t.Errorf("[test %d] Expected error", i)
+		}
+	}
+}
+
+func TestTopicsTag(t *testing.T) {
+	r := &registryMock{}
+
+	out, err := run(r, "topics", "tag", "events", "team:ingest", "tier:prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out != "topic events tagged\n" {
+		t.Errorf("Unexpected output '%s'", out)
+	}
+
+	req := r.req.(*pb.TopicRequest)
+	if req.Name != "events" || len(req.Tag) != 2 || req.Tag[1] != "tier:prod" {
+		t.Errorf("Unexpected request %v", req)
+	}
+}
+
+func TestBrokersList(t *testing.T) {
+	r := &registryMock{}
+
+	out, err := run(r, "brokers", "list", "--tag-query", "rack=a OR rack=b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sorted by ID.
+	expected := "ID     RACK   HOST       MAINTENANCE   TAGS\n" +
+		"1001   a      10.0.0.1   false         -\n" +
+		"1002   b      10.0.0.2   true          maintenance=true\n"
+
+	if out != expected {
+		t.Errorf("Expected output:\n%s\ngot:\n%s", expected, out)
+	}
+
+	if q := r.req.(*pb.BrokerRequest).TagQuery; q != "rack=a OR rack=b" {
+		t.Errorf("Expected tag query 'rack=a OR rack=b', got '%s'", q)
+	}
+}
+
+func TestBrokersDescribe(t *testing.T) {
+	r := &registryMock{}
+
+	out, err := run(r, "brokers", "describe", "1002")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"Maintenance:  true", "Topics:       events,logs"} {
+		if !strings.Contains(out, line) {
+			t.Errorf("Expected output line '%s', got:\n%s", line, out)
+		}
+	}
+
+	tests := map[int]string{
+		0: "1003",
+		1: "x",
+		2: "0",
+	}
+
+	for i, id := range tests {
+		if _, err := run(r, "brokers", "describe", id); err == nil {
+			t.Errorf("[test %d] Expected error", i)
+		}
+	}
+}
+
+func TestOutputFormat(t *testing.T) {
+	if _, err := run(&registryMock{}, "topics", "list", "-o", "yaml"); err == nil {
+		t.Error("Expected invalid output format error")
+	}
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// jsonMarshaler marshals messages as with
+// the registry HTTP API.
+var jsonMarshaler = &jsonpb.Marshaler{OrigName: true}
+
+// printTable writes the rows as tab-aligned columns under the header.
+func (s *session) printTable(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(s.out, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, r := range rows {
+		fmt.Fprintln(w, strings.Join(r, "\t"))
+	}
+
+	return w.Flush()
+}
+
+// printFields writes the name, value pairs as tab-aligned
+// "name: value" lines.
+func (s *session) printFields(fields [][2]string) error {
+	w := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+
+	for _, f := range fields {
+		fmt.Fprintf(w, "%s:\t%s\n", f[0], f[1])
+	}
+
+	return w.Flush()
+}
+
+// printJSON writes v as indented JSON; proto.Messages are marshaled
+// as with the registry HTTP API. Values may be maps of proto.Messages.
+func (s *session) printJSON(v interface{}) error {
+	data, err := marshalJSON(v)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return err
+	}
+
+	buf.WriteString("\n")
+	_, err = buf.WriteTo(s.out)
+
+	return err
+}
+
+func marshalJSON(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case proto.Message:
+		str, err := jsonMarshaler.MarshalToString(v)
+		return []byte(str), err
+	case map[string]interface{}:
+		m := map[string]json.RawMessage{}
+		for k, e := range v {
+			data, err := marshalJSON(e)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = data
+		}
+		return json.Marshal(m)
+	default:
+		return json.Marshal(v)
+	}
+}
+
+// formatMap returns the sorted k=v pairs of the
+// map, comma delimited; "-" if empty.
+func formatMap(m map[string]string) string {
+	if len(m) == 0 {
+		return "-"
+	}
+
+	var pairs []string
+	for k, v := range m {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// formatIDs returns the IDs, comma delimited; "-" if empty.
+func formatIDs(ids []uint32) string {
+	if len(ids) == 0 {
+		return "-"
+	}
+
+	var s []string
+	for _, id := range ids {
+		s = append(s, fmt.Sprint(id))
+	}
+
+	return strings.Join(s, ",")
+}
+
+// orDash returns s, or "-" if empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jamiealquiza/envy"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+// Execute registryctl.
+func Execute() {
+	rootCmd := newRootCmd()
+	envy.ParseCobra(rootCmd, envy.CobraConfig{Prefix: "REGISTRYCTL", Persistent: true, Recursive: false})
+
+	if err := rootCmd.Execute(); err != nil {
+		// Print the message of gRPC status errors
+		// rather than the code and message.
+		fmt.Fprintln(os.Stderr, status.Convert(err).Message())
+		os.Exit(1)
+	}
+}
+
+// newRootCmd returns the registryctl command tree.
+func newRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "registryctl",
+		Short: "A command-line client for the registry API",
+		Long: `registryctl lists, describes, creates and tags topics and queries brokers
+via the registry gRPC API. Results are printed as tables, or as JSON in the
+format of the registry HTTP API with --output json.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	rootCmd.PersistentFlags().String("addr", "localhost:8090", "Registry gRPC address")
+	rootCmd.PersistentFlags().String("cluster", "", "Registry cluster, if the registry serves multiple clusters (the default cluster if empty)")
+	rootCmd.PersistentFlags().String("token", "", "Bearer token, if the registry requires authentication")
+	rootCmd.PersistentFlags().Bool("tls", false, "Connect to the registry with TLS")
+	rootCmd.PersistentFlags().String("tls-ca", "", "CA certificates file for verifying the registry certificate (the system CAs if empty); implies --tls")
+	rootCmd.PersistentFlags().String("tls-cert", "", "Client certificate file, if the registry verifies client certificates; implies --tls")
+	rootCmd.PersistentFlags().String("tls-key", "", "Client key file")
+	rootCmd.PersistentFlags().StringP("output", "o", "table", "Output format: [table, json]")
+	rootCmd.PersistentFlags().Duration("timeout", 30*time.Second, "Registry connection and request timeout")
+
+	rootCmd.AddCommand(newTopicsCmd(), newBrokersCmd())
+
+	return rootCmd
+}
@@ -0,0 +1,301 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+
+	"github.com/spf13/cobra"
+)
+
+// listPageSize is the number of topics
+// fetched per ListTopics request.
+const listPageSize = 1000
+
+func newTopicsCmd() *cobra.Command {
+	topicsCmd := &cobra.Command{
+		Use:   "topics",
+		Short: "List, describe, create and tag topics",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List topic names",
+		Args:  cobra.NoArgs,
+		RunE:  topicsList,
+	}
+
+	listCmd.Flags().StringArray("tag", nil, "Only list topics with the tag (key:value); may be repeated")
+	listCmd.Flags().String("tag-query", "", `Only list topics matching the tag expression, e.g. "team=ingest AND tier!=test"`)
+	listCmd.Flags().String("name-regex", "", "Only list topics with names matching the (unanchored) regex")
+
+	describeCmd := &cobra.Command{
+		Use:   "describe <name>",
+		Short: "Describe the metadata, tags, configs and brokers of a topic",
+		Args:  cobra.ExactArgs(1),
+		RunE:  topicsDescribe,
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create <name>...",
+		Short: "Create topics with the same settings",
+		Long: `create creates each named topic with the partitions, replication, configs and tags
+specified. Creation is all-or-nothing: the registry validates every topic before
+any topic is created. Use --dry-run to only validate the topics.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: topicsCreate,
+	}
+
+	createCmd.Flags().Int("partitions", 0, "Number of partitions")
+	createCmd.Flags().Int("replication", 0, "Replication factor")
+	createCmd.Flags().StringArray("config", nil, "Topic config override (config=value); may be repeated")
+	createCmd.Flags().StringArray("tag", nil, "Topic tag (key:value); may be repeated")
+	createCmd.Flags().String("owner", "", "Topic owner")
+	createCmd.Flags().String("team", "", "Topic team")
+	createCmd.Flags().Bool("dry-run", false, "Validate the topics without creating them")
+	createCmd.MarkFlagRequired("partitions")
+	createCmd.MarkFlagRequired("replication")
+
+	tagCmd := &cobra.Command{
+		Use:   "tag <name> <key:value>...",
+		Short: "Set topic tags; other tags are unmodified",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  topicsTag,
+	}
+
+	untagCmd := &cobra.Command{
+		Use:   "untag <name> <key>...",
+		Short: "Delete topic tags",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  topicsUntag,
+	}
+
+	topicsCmd.AddCommand(listCmd, describeCmd, createCmd, tagCmd, untagCmd)
+
+	return topicsCmd
+}
+
+func topicsList(cmd *cobra.Command, _ []string) error {
+	s, done, err := newSession(cmd)
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	tags, _ := cmd.Flags().GetStringArray("tag")
+	query, _ := cmd.Flags().GetString("tag-query")
+	regex, _ := cmd.Flags().GetString("name-regex")
+
+	req := &pb.TopicRequest{
+		Tag:       tags,
+		TagQuery:  query,
+		NameRegex: regex,
+		Cluster:   s.cluster,
+		Limit:     listPageSize,
+	}
+
+	// Fetch all pages.
+	names := []string{}
+	for {
+		resp, err := s.client.ListTopics(s.ctx, req)
+		if err != nil {
+			return err
+		}
+
+		names = append(names, resp.Names...)
+
+		if resp.NextPageToken == "" {
+			break
+		}
+
+		req.PageToken = resp.NextPageToken
+	}
+
+	if s.json {
+		return s.printJSON(&pb.TopicResponse{Names: names})
+	}
+
+	var rows [][]string
+	for _, n := range names {
+		rows = append(rows, []string{n})
+	}
+
+	return s.printTable([]string{"NAME"}, rows)
+}
+
+func topicsDescribe(cmd *cobra.Command, args []string) error {
+	s, done, err := newSession(cmd)
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	name := args[0]
+
+	resp, err := s.client.GetTopics(s.ctx, &pb.TopicRequest{Name: name, Cluster: s.cluster})
+	if err != nil {
+		return err
+	}
+
+	t, exists := resp.Topics[name]
+	if !exists {
+		return fmt.Errorf("topic %s does not exist", name)
+	}
+
+	configs, err := s.client.GetTopicConfig(s.ctx, &pb.TopicConfigRequest{Name: name, Cluster: s.cluster})
+	if err != nil {
+		return err
+	}
+
+	brokers, err := s.client.TopicMappings(s.ctx, &pb.TopicRequest{Name: name, Cluster: s.cluster})
+	if err != nil {
+		return err
+	}
+
+	if s.json {
+		return s.printJSON(map[string]interface{}{
+			"topic":   t,
+			"configs": configs.Configs,
+			"brokers": brokers.Ids,
+		})
+	}
+
+	return s.printFields([][2]string{
+		{"Name", t.Name},
+		{"Partitions", fmt.Sprint(t.Partitions)},
+		{"Replication", fmt.Sprint(t.Replication)},
+		{"Owner", orDash(t.Owner)},
+		{"Team", orDash(t.Team)},
+		{"Tags", formatMap(t.Tags)},
+		{"Configs", formatMap(configs.Configs)},
+		{"Brokers", formatIDs(brokers.Ids)},
+	})
+}
+
+func topicsCreate(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+
+	partitions, _ := flags.GetInt("partitions")
+	replication, _ := flags.GetInt("replication")
+	owner, _ := flags.GetString("owner")
+	team, _ := flags.GetString("team")
+	dryRun, _ := flags.GetBool("dry-run")
+
+	configArgs, _ := flags.GetStringArray("config")
+	configs, err := parsePairs(configArgs, "=")
+	if err != nil {
+		return fmt.Errorf("invalid --config: %s", err)
+	}
+
+	tagArgs, _ := flags.GetStringArray("tag")
+	tags, err := parsePairs(tagArgs, ":")
+	if err != nil {
+		return fmt.Errorf("invalid --tag: %s", err)
+	}
+
+	s, done, err := newSession(cmd)
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	req := &pb.CreateTopicsRequest{DryRun: dryRun, Cluster: s.cluster}
+	for _, name := range args {
+		req.Topics = append(req.Topics, &pb.TopicSpec{
+			Name:        name,
+			Partitions:  uint32(partitions),
+			Replication: uint32(replication),
+			Configs:     configs,
+			Tags:        tags,
+			Owner:       owner,
+			Team:        team,
+		})
+	}
+
+	resp, err := s.client.CreateTopics(s.ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if s.json {
+		return s.printJSON(resp)
+	}
+
+	if resp.DryRun {
+		fmt.Fprintln(s.out, "Dry run; no topics were created:")
+	}
+
+	var rows [][]string
+	for _, t := range resp.Topics {
+		rows = append(rows, []string{t.Name, fmt.Sprint(t.Partitions), fmt.Sprint(t.Replication), formatMap(t.Tags)})
+	}
+
+	return s.printTable([]string{"NAME", "PARTITIONS", "REPLICATION", "TAGS"}, rows)
+}
+
+func topicsTag(cmd *cobra.Command, args []string) error {
+	s, done, err := newSession(cmd)
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	resp, err := s.client.TagTopic(s.ctx, &pb.TopicRequest{Name: args[0], Tag: args[1:], Cluster: s.cluster})
+	if err != nil {
+		return err
+	}
+
+	return s.printTagResponse(resp, fmt.Sprintf("topic %s tagged", args[0]))
+}
+
+func topicsUntag(cmd *cobra.Command, args []string) error {
+	s, done, err := newSession(cmd)
+	if err != nil {
+		return err
+	}
+
+	defer done()
+
+	resp, err := s.client.DeleteTopicTags(s.ctx, &pb.TopicRequest{Name: args[0], Tag: args[1:], Cluster: s.cluster})
+	if err != nil {
+		return err
+	}
+
+	return s.printTagResponse(resp, fmt.Sprintf("topic %s tags deleted", args[0]))
+}
+
+// printTagResponse writes the *pb.TagResponse
+// as JSON, or otherwise the message.
+func (s *session) printTagResponse(resp *pb.TagResponse, msg string) error {
+	if s.json {
+		return s.printJSON(resp)
+	}
+
+	_, err := fmt.Fprintln(s.out, msg)
+
+	return err
+}
+
+// parsePairs parses the key<sep>value pairs into a map.
+func parsePairs(pairs []string, sep string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	m := map[string]string{}
+	for _, p := range pairs {
+		kv := strings.SplitN(p, sep, 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("'%s' is not of the form key%svalue", p, sep)
+		}
+
+		m[kv[0]] = kv[1]
+	}
+
+	return m, nil
+}
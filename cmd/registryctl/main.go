@@ -0,0 +1,7 @@
+package main
+
+import "github.com/honeycombio/kafka-kit/cmd/registryctl/commands"
+
+func main() {
+	commands.Execute()
+}
@@ -0,0 +1,382 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply <map file>...",
+	Short: "Apply a staged plan, gating each stage on completion and cluster health",
+	Long: `apply submits the partition maps provided as arguments as reassignments, one
+stage at a time and in the order given (e.g. the maps written by pipeline
+--write-stages). Each stage is monitored until complete before the next stage
+is submitted.
+
+Cluster health is checked before each stage is submitted. The apply pauses,
+exiting with the stage to resume from via --start-stage, if the number of
+under-replicated partitions outside of the active reassignment exceeds
+--max-urps for longer than --health-timeout, or if the replication throttle on
+any broker in the previous stage fell below --min-throttle-rate while it was
+applied. A throttle at that rate indicates that replication was saturated by a
+lack of available bandwidth (see autothrottle --min-rate). Under-replicated
+partitions of all topics in the cluster are counted, or only those of topics
+in the staged maps with --health-scope stages.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  apply,
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+
+	applyCmd.Flags().Int("start-stage", 1, "Stage to start from, e.g. to resume a paused apply; previous stages are assumed complete")
+	applyCmd.Flags().Int("poll-interval", 30, "Reassignment progress and cluster health check interval (seconds)")
+	applyCmd.Flags().Int("max-urps", 0, "Maximum number of under-replicated partitions outside of the active reassignment before pausing (-1 disables the check)")
+	applyCmd.Flags().String("health-scope", "cluster", "Topics checked for under-replicated partitions: cluster (all topics) or stages (only topics in the staged maps)")
+	applyCmd.Flags().Int("health-timeout", 300, "Time to wait for under-replicated partitions to recover before pausing (seconds)")
+	applyCmd.Flags().Float64("min-throttle-rate", 0, "Pause if the replication throttle on any broker in a stage falls below this rate while the stage is applied (MB/s, 0 disables the check)")
+}
+
+// allTopics matches all topics, for
+// cluster wide health checks.
+var allTopics = regexp.MustCompile(".*")
+
+// applyStage is a map to be applied as a single reassignment.
+type applyStage struct {
+	name string
+	pm   *kafkazk.PartitionMap
+}
+
+func apply(cmd *cobra.Command, args []string) {
+	start, _ := cmd.Flags().GetInt("start-stage")
+	if start < 1 || start > len(args) {
+		fmt.Printf("\n[ERROR] --start-stage must be between 1 and %d\n", len(args))
+		defaultsAndExit()
+	}
+
+	pi, _ := cmd.Flags().GetInt("poll-interval")
+	if pi < 1 {
+		fmt.Println("\n[ERROR] --poll-interval must be at least 1")
+		defaultsAndExit()
+	}
+
+	interval := time.Duration(pi) * time.Second
+	maxURPs, _ := cmd.Flags().GetInt("max-urps")
+	ht, _ := cmd.Flags().GetInt("health-timeout")
+
+	scope, _ := cmd.Flags().GetString("health-scope")
+	if scope != "cluster" && scope != "stages" {
+		fmt.Println("\n[ERROR] --health-scope must be one of cluster, stages")
+		defaultsAndExit()
+	}
+	mtr, _ := cmd.Flags().GetFloat64("min-throttle-rate")
+	minRate := mtr * 1000000.00
+
+	// Load all stages up front so that
+	// a bad map doesn't fail mid-apply.
+	var stages []applyStage
+	for _, path := range args {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Printf("Error reading partition map: %s\n", err)
+			os.Exit(1)
+		}
+
+		pm, err := kafkazk.PartitionMapFromString(string(data))
+		if err != nil {
+			fmt.Printf("Error parsing partition map %s: %s\n", path, err)
+			os.Exit(1)
+		}
+
+		stages = append(stages, applyStage{name: path, pm: pm})
+	}
+
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	// All topics are checked for under-replicated
+	// partitions if the topics are nil.
+	var topics []string
+	if scope == "stages" {
+		topics = stageTopics(stages)
+	}
+
+	fmt.Printf("\nStages:\n")
+	for n, s := range stages {
+		skipped := ""
+		if n+1 < start {
+			skipped = " (skipped)"
+		}
+		fmt.Printf("%s%d: %s, %d partitions%s\n", indent, n+1, s.name, len(s.pm.Partitions), skipped)
+	}
+
+	var saturated []error
+
+	for n := start - 1; n < len(stages); n++ {
+		stage := stages[n]
+		num := n + 1
+
+		// Gate the stage on cluster health.
+		var violations []error
+		if len(saturated) > 0 {
+			violations = saturated
+		} else {
+			violations, err = awaitHealthy(zk, topics, maxURPs, time.Duration(ht)*time.Second, interval)
+			if err != nil {
+				fmt.Printf("\nError checking cluster health: %s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if len(violations) > 0 {
+			fmt.Printf("\nPaused before stage %d:\n", num)
+			for _, e := range violations {
+				fmt.Printf("%s%s\n", indent, e)
+			}
+			fmt.Printf("\nResume with --start-stage %d\n", num)
+			os.Exit(1)
+		}
+
+		// Source brokers are looked up prior to the
+		// reassignment so that throttles on brokers
+		// losing replicas are checked as well.
+		brokers, err := stageBrokers(zk, stage.pm)
+		if err != nil {
+			fmt.Printf("\nError fetching partition state: %s\n", err)
+			os.Exit(1)
+		}
+
+		if err := zk.ReassignPartitions(stage.pm); err != nil {
+			fmt.Printf("\nError submitting stage %d: %s\n", num, err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("\nStage %d/%d: submitted reassignment of %d partitions\n", num, len(stages), len(stage.pm.Partitions))
+
+		// Monitor the stage until complete.
+		saturated = nil
+		for {
+			time.Sleep(interval)
+
+			pending := pendingPartitions(stage.pm, zk.GetReassignments())
+			fmt.Printf("%s%s %d/%d partitions remaining\n", indent,
+				time.Now().Format("15:04:05"), pending, len(stage.pm.Partitions))
+
+			if pending == 0 {
+				break
+			}
+
+			if minRate > 0 {
+				errs, err := saturatedThrottles(zk, brokers, minRate)
+				if err != nil {
+					fmt.Printf("\nError checking replication throttles: %s\n", err)
+					os.Exit(1)
+				}
+
+				for _, e := range errs {
+					fmt.Printf("%s%s\n", indent, e)
+				}
+
+				// Throttles are removed once the stage
+				// completes; retain any saturation observed
+				// while the stage was applied.
+				if len(errs) > 0 {
+					saturated = errs
+				}
+			}
+		}
+
+		fmt.Printf("\nStage %d/%d complete\n", num, len(stages))
+	}
+
+	fmt.Println("\nAll stages applied")
+}
+
+// stageTopics returns the sorted names of all topics referenced in the stages.
+func stageTopics(stages []applyStage) []string {
+	set := map[string]struct{}{}
+	for _, s := range stages {
+		for _, p := range s.pm.Partitions {
+			set[p.Topic] = struct{}{}
+		}
+	}
+
+	topics := []string{}
+	for t := range set {
+		topics = append(topics, t)
+	}
+
+	sort.Strings(topics)
+
+	return topics
+}
+
+// pendingPartitions returns the number of partitions in the
+// PartitionMap that are still being reassigned.
+func pendingPartitions(pm *kafkazk.PartitionMap, re kafkazk.Reassignments) int {
+	var n int
+	for _, p := range pm.Partitions {
+		if _, reassigning := re[p.Topic][p.Partition]; reassigning {
+			n++
+		}
+	}
+
+	return n
+}
+
+// stageBrokers returns the sorted IDs of all brokers currently
+// holding or assigned replicas of partitions in the PartitionMap.
+func stageBrokers(zk kafkazk.Handler, pm *kafkazk.PartitionMap) ([]int, error) {
+	set := map[int]struct{}{}
+	current := map[string]*kafkazk.PartitionMap{}
+
+	for _, p := range pm.Partitions {
+		if _, fetched := current[p.Topic]; !fetched {
+			c, err := zk.GetPartitionMap(p.Topic)
+			if err != nil {
+				return nil, err
+			}
+			current[p.Topic] = c
+		}
+
+		for _, id := range p.Replicas {
+			set[id] = struct{}{}
+		}
+
+		for _, cp := range current[p.Topic].Partitions {
+			if cp.Partition != p.Partition {
+				continue
+			}
+			for _, id := range cp.Replicas {
+				set[id] = struct{}{}
+			}
+		}
+	}
+
+	var ids []int
+	for id := range set {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	return ids, nil
+}
+
+// awaitHealthy checks the topics (all topics if nil) for under-replicated
+// partitions each interval until there are no more than max or the timeout
+// is exceeded.
+// An error is returned for each under-replicated partition remaining
+// past the timeout. No checks are made if max is negative.
+func awaitHealthy(zk kafkazk.Handler, topics []string, max int, timeout, interval time.Duration) ([]error, error) {
+	if max < 0 {
+		return nil, nil
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		urps, err := underReplicated(zk, topics)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(urps) <= max {
+			return nil, nil
+		}
+
+		if !time.Now().Before(deadline) {
+			var errs []error
+			for _, p := range urps {
+				errs = append(errs, fmt.Errorf("under-replicated: %s", p))
+			}
+			return errs, nil
+		}
+
+		fmt.Printf("\nWaiting for %d under-replicated partitions to recover (max %d)\n", len(urps), max)
+		time.Sleep(interval)
+	}
+}
+
+// underReplicated returns all partitions of the topics (all topics in the
+// cluster if nil) with assigned replicas missing from the ISR, excluding
+// partitions being reassigned.
+func underReplicated(zk kafkazk.Handler, topics []string) (kafkazk.OutOfSyncPartitions, error) {
+	if topics == nil {
+		var err error
+		if topics, err = zk.GetTopics([]*regexp.Regexp{allTopics}); err != nil {
+			return nil, err
+		}
+
+		sort.Strings(topics)
+	}
+
+	re := zk.GetReassignments()
+
+	var urps kafkazk.OutOfSyncPartitions
+	for _, t := range topics {
+		pm, err := zk.GetPartitionMap(t)
+		if err != nil {
+			return nil, err
+		}
+
+		oos, err := pm.OutOfSync(zk, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range oos {
+			if _, reassigning := re[p.Topic][p.Partition]; !reassigning {
+				urps = append(urps, p)
+			}
+		}
+	}
+
+	return urps, nil
+}
+
+// saturatedThrottles takes a list of broker IDs and a minimum rate in
+// bytes/s and returns an error for each broker with a leader or follower
+// replication throttle set below the minimum. Brokers without a
+// dynamic config are skipped.
+func saturatedThrottles(zk kafkazk.Handler, ids []int, min float64) ([]error, error) {
+	var errs []error
+
+	for _, id := range ids {
+		c, err := zk.GetBrokerConfig(id)
+		if err != nil {
+			if _, ok := err.(kafkazk.ErrNoNode); ok {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, k := range []string{"leader.replication.throttled.rate", "follower.replication.throttled.rate"} {
+			r, err := strconv.ParseFloat(c.Config[k], 64)
+			if err != nil || r >= min {
+				continue
+			}
+
+			errs = append(errs, fmt.Errorf("broker %d %s at %.2fMB/s, below %.2fMB/s",
+				id, k, r/1000000.00, min/1000000.00))
+			break
+		}
+	}
+
+	return errs, nil
+}
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+func TestPendingPartitions(t *testing.T) {
+	pm, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"mock","partition":0,"replicas":[1003,1004]},
+		{"topic":"mock","partition":2,"replicas":[1001,1002]},
+		{"topic":"other","partition":1,"replicas":[1001,1002]}]}`)
+
+	zk := &kafkazk.Mock{}
+
+	if n := pendingPartitions(pm, zk.GetReassignments()); n != 1 {
+		t.Errorf("Expected 1 pending partition, got %d", n)
+	}
+
+	if n := pendingPartitions(pm, kafkazk.Reassignments{}); n != 0 {
+		t.Errorf("Expected 0 pending partitions, got %d", n)
+	}
+}
+
+func TestUnderReplicated(t *testing.T) {
+	zk := &kafkazk.Mock{}
+
+	// All mock partitions have replicas outside of the ISR.
+	urps, err := underReplicated(zk, []string{"test_topic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(urps) != 4 {
+		t.Errorf("Expected 4 under-replicated partitions, got %d", len(urps))
+	}
+
+	// p0 and p1 are being reassigned.
+	urps, _ = underReplicated(zk, []string{"mock"})
+	if len(urps) != 2 || urps[0].Partition != 2 || urps[1].Partition != 3 {
+		t.Errorf("Unexpected under-replicated partitions: %v", urps)
+	}
+
+	// All topics.
+	urps, err = underReplicated(zk, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(urps) != 8 || urps[0].Topic != "test_topic" || urps[7].Topic != "test_topic2" {
+		t.Errorf("Unexpected under-replicated partitions: %v", urps)
+	}
+}
+
+func TestStageBrokers(t *testing.T) {
+	pm, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test_topic","partition":0,"replicas":[1005,1002]}]}`)
+
+	ids, err := stageBrokers(&kafkazk.Mock{}, pm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []int{1001, 1002, 1005}
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected brokers %v, got %v", expected, ids)
+	}
+
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Errorf("Expected brokers %v, got %v", expected, ids)
+		}
+	}
+}
+
+func TestSaturatedThrottles(t *testing.T) {
+	zk := &kafkazk.Mock{}
+
+	// Mock throttles are 100MB/s.
+	errs, err := saturatedThrottles(zk, []int{1001, 1002}, 50000000.00)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(errs) != 0 {
+		t.Errorf("Unexpected error(s): %s", errs)
+	}
+
+	errs, _ = saturatedThrottles(zk, []int{1001, 1002}, 200000000.00)
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %d", len(errs))
+	}
+
+	e := "broker 1001 leader.replication.throttled.rate at 100.00MB/s, below 200.00MB/s"
+	if errs[0].Error() != e {
+		t.Errorf("Expected error '%s', got '%s'", e, errs[0])
+	}
+}
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -10,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
 	"github.com/honeycombio/kafka-kit/kafkazk"
 
 	"github.com/spf13/cobra"
@@ -28,15 +31,43 @@ var (
 	Config struct {
 		topics  []*regexp.Regexp
 		brokers []int
+		// Topics provided by name
+		// rather than regex.
+		explicitTopics map[string]bool
+		// Brokers matching the --broker-tags;
+		// nil if unset.
+		taggedBrokers map[int]bool
+		// Brokers in maintenance per
+		// their registry tags.
+		maintenanceBrokers []int
+		// Placement constraints of
+		// constrained topics.
+		topicConstraints kafkazk.TopicConstraintsMap
+		// Leader pins of pinned partitions.
+		leaderPins kafkazk.LeaderPins
 	}
 )
 
 func bootstrap(cmd *cobra.Command) {
-	b, _ := cmd.Flags().GetString("brokers")
-	Config.brokers = brokerStringToSlice(b)
+	// Validate the metrics backend params.
+	mb := cmd.Flag("metrics-backend").Value.String()
+	if _, valid := metricsBackendDefaults[mb]; mb != "" && !valid {
+		fmt.Println("\n[ERROR] --metrics-backend must be one of 'statsd', 'pushgateway' or 'honeycomb'")
+		defaultsAndExit()
+	}
+
+	if mb == "honeycomb" && cmd.Flag("metrics-api-key").Value.String() == "" {
+		fmt.Println("\n[ERROR] --metrics-backend=honeycomb requires --metrics-api-key")
+		defaultsAndExit()
+	}
+
+	// Not all commands reference brokers.
+	if b, _ := cmd.Flags().GetString("brokers"); b != "" {
+		Config.brokers = brokerStringToSlice(b)
+	}
 
 	// Append trailing slash if not included.
-	op := cmd.Flag("out-path").Value.String()
+	op, _ := cmd.Flags().GetString("out-path")
 	if op != "" && !strings.HasSuffix(op, "/") {
 		cmd.Flags().Set("out-path", op+"/")
 	}
@@ -44,9 +75,11 @@ func bootstrap(cmd *cobra.Command) {
 	// Determine if regexp was provided in the topic
 	// name. If not, set the topic name to ^name$.
 	if t, _ := cmd.Flags().GetString("topics"); t != "" {
+		Config.explicitTopics = map[string]bool{}
 		topicNames := strings.Split(t, ",")
 		for n, t := range topicNames {
 			if !containsRegex(t) {
+				Config.explicitTopics[t] = true
 				topicNames[n] = fmt.Sprintf(`^%s$`, t)
 			}
 		}
@@ -72,18 +105,146 @@ func bootstrap(cmd *cobra.Command) {
 //    topic discovery` via ZooKeeper.
 //  - that the --placement flag was set to 'storage', which expects
 //    metrics metadata to be stored in ZooKeeper.
+//
+// If --from-snapshot is set, a read-only Handler backed by the
+// cluster state file is returned instead. Likewise, if --registry-addr
+// is set, a read-only Handler backed by the cluster state fetched from
+// the registry is returned. If --kafka-bootstrap-servers is set, a
+// Handler backed by the Kafka Admin API is returned. Only one of these
+// flags may be set.
 func initZooKeeper(cmd *cobra.Command) (kafkazk.Handler, error) {
+	var sources []string
+	for _, f := range []string{"from-snapshot", "registry-addr", "kafka-bootstrap-servers"} {
+		if v, _ := cmd.Flags().GetString(f); v != "" {
+			sources = append(sources, "--"+f)
+		}
+	}
+
+	if len(sources) > 1 {
+		return nil, fmt.Errorf("%s are mutually exclusive", strings.Join(sources, " and "))
+	}
+
+	if addr, _ := cmd.Flags().GetString("metrics-zk-addr"); addr != "" && (len(sources) == 0 || sources[0] != "--kafka-bootstrap-servers") {
+		return nil, fmt.Errorf("--metrics-zk-addr requires --kafka-bootstrap-servers")
+	}
+
+	if addr, _ := cmd.Flags().GetString("registry-addr"); addr != "" {
+		cluster, _ := cmd.Flags().GetString("registry-cluster")
+		s, err := getRegistryState(addr, cluster)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Printf("\nUsing cluster state from registry %s\n", addr)
+
+		return kafkazk.NewStateHandler(s), nil
+	}
+
+	if f, _ := cmd.Flags().GetString("from-snapshot"); f != "" {
+		s, err := kafkazk.ReadClusterState(f)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading cluster state %s: %s", f, err)
+		}
+
+		fmt.Printf("\nUsing cluster state %s (captured %s)\n", f,
+			time.Unix(s.Timestamp, 0).UTC().Format(time.RFC3339))
+
+		return kafkazk.NewStateHandler(s), nil
+	}
+
+	if servers, _ := cmd.Flags().GetString("kafka-bootstrap-servers"); servers != "" {
+		return initKafkaAdmin(cmd, servers)
+	}
+
+	return initZooKeeperAddr(cmd, cmd.Parent().Flag("zk-addr").Value.String(),
+		cmd.Parent().Flag("zk-prefix").Value.String())
+}
+
+// initKafkaAdmin returns a Handler backed by the Kafka Admin API,
+// connecting to the bootstrap servers with the kafka-tls and
+// kafka-sasl flags. Broker and partition metrics aren't available
+// via the Admin API; they're read from the ZooKeeper ensemble at
+// --metrics-zk-addr that metricsfetcher writes to, if set.
+func initKafkaAdmin(cmd *cobra.Command, servers string) (kafkazk.Handler, error) {
+	c := kafkaadmin.Config{
+		BootstrapServers: servers,
+		ClientID:         "topicmappr",
+	}
+
+	useTLS, _ := cmd.Flags().GetBool("kafka-tls")
+	caCert, _ := cmd.Flags().GetString("kafka-tls-ca-cert")
+
+	if useTLS || caCert != "" {
+		c.TLS = &tls.Config{}
+
+		if caCert != "" {
+			pem, err := ioutil.ReadFile(caCert)
+			if err != nil {
+				return nil, fmt.Errorf("Error reading CA certificate: %s", err)
+			}
+
+			c.TLS.RootCAs = x509.NewCertPool()
+			if !c.TLS.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("No valid certificates found in %s", caCert)
+			}
+		}
+	}
+
+	if mechanism, _ := cmd.Flags().GetString("kafka-sasl-mechanism"); mechanism != "" {
+		c.SASL = &kafkaadmin.SASLConfig{Mechanism: mechanism}
+		c.SASL.Username, _ = cmd.Flags().GetString("kafka-sasl-username")
+		c.SASL.Password, _ = cmd.Flags().GetString("kafka-sasl-password")
+	}
+
+	h, err := kafkaadmin.NewHandler(c)
+	if err != nil {
+		return nil, fmt.Errorf("Error connecting to Kafka: %s", err)
+	}
+
+	if addr, _ := cmd.Flags().GetString("metrics-zk-addr"); addr != "" {
+		zk, err := initZooKeeperAddr(cmd, addr, "")
+		if err != nil {
+			h.Close()
+			return nil, err
+		}
+
+		// The metrics are read from a ZooKeeper
+		// Handler, which is a MetricsSource.
+		h.Metrics = zk.(kafkaadmin.MetricsSource)
+	}
+
+	return h, nil
+}
+
+// initZooKeeperAddr inits a ZooKeeper connection to the
+// provided address and prefix, with the zk-tls flags.
+func initZooKeeperAddr(cmd *cobra.Command, zkAddr, zkPrefix string) (kafkazk.Handler, error) {
 	// Suppress underlying ZK client noise.
 	log.SetOutput(ioutil.Discard)
 
-	zkAddr := cmd.Parent().Flag("zk-addr").Value.String()
 	timeout := 250 * time.Millisecond
 
-	zk, err := kafkazk.NewHandler(&kafkazk.Config{
+	// Not all commands reference metrics.
+	mp, _ := cmd.Flags().GetString("zk-metrics-prefix")
+	c, _ := cmd.Flags().GetInt("zk-concurrency")
+
+	zc := &kafkazk.Config{
 		Connect:       zkAddr,
-		Prefix:        cmd.Parent().Flag("zk-prefix").Value.String(),
-		MetricsPrefix: cmd.Flag("zk-metrics-prefix").Value.String(),
-	})
+		Prefix:        zkPrefix,
+		MetricsPrefix: mp,
+		Concurrency:   c,
+	}
+
+	if useTLS, _ := cmd.Flags().GetBool("zk-tls"); useTLS {
+		zc.TLS = &tls.Config{}
+	}
+
+	zc.TLSCACert, _ = cmd.Flags().GetString("zk-tls-ca-cert")
+	zc.TLSCert, _ = cmd.Flags().GetString("zk-tls-cert")
+	zc.TLSKey, _ = cmd.Flags().GetString("zk-tls-key")
+	zc.TLSServerName, _ = cmd.Flags().GetString("zk-tls-server-name")
+
+	zk, err := kafkazk.NewHandler(zc)
 
 	if err != nil {
 		return nil, fmt.Errorf("Error connecting to ZooKeeper: %s", err)
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestInitZooKeeperSources(t *testing.T) {
+	tests := map[int]map[string]string{
+		0: {"from-snapshot": "state.json", "registry-addr": "registry:8090"},
+		1: {"from-snapshot": "state.json", "kafka-bootstrap-servers": "kafka:9092"},
+		2: {"registry-addr": "registry:8090", "kafka-bootstrap-servers": "kafka:9092"},
+		3: {"metrics-zk-addr": "zk:2181"},
+		4: {"metrics-zk-addr": "zk:2181", "from-snapshot": "state.json"},
+	}
+
+	expected := map[int]string{
+		0: "--from-snapshot and --registry-addr are mutually exclusive",
+		1: "--from-snapshot and --kafka-bootstrap-servers are mutually exclusive",
+		2: "--registry-addr and --kafka-bootstrap-servers are mutually exclusive",
+		3: "--metrics-zk-addr requires --kafka-bootstrap-servers",
+		4: "--metrics-zk-addr requires --kafka-bootstrap-servers",
+	}
+
+	for i, flags := range tests {
+		cmd := &cobra.Command{}
+		for _, f := range []string{"from-snapshot", "registry-addr", "kafka-bootstrap-servers", "metrics-zk-addr"} {
+			cmd.Flags().String(f, "", "")
+		}
+
+		for f, v := range flags {
+			cmd.Flags().Set(f, v)
+		}
+
+		if _, err := initZooKeeper(cmd); err == nil || err.Error() != expected[i] {
+			t.Errorf("[test %d] Expected error '%s', got '%v'", i, expected[i], err)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	"github.com/honeycombio/kafka-kit/planner"
+
+	"github.com/spf13/cobra"
+)
+
+// Default addresses for each supported
+// --metrics-backend.
+var metricsBackendDefaults = map[string]string{
+	"statsd":      "localhost:8125",
+	"pushgateway": "http://localhost:9091",
+	"honeycomb":   "https://api.honeycomb.io",
+}
+
+// planStats holds summary statistics
+// for a generated partition map.
+type planStats struct {
+	command         string
+	partitions      int
+	partitionsMoved int
+	replicasMoved   int
+	bytesMoved      float64
+	storageStdDev   float64
+	duration        time.Duration
+	// Whether bytesMoved and storageStdDev
+	// were populated from metrics metadata.
+	withStorage bool
+}
+
+// getPlanStats takes the input and output PartitionMap, PartitionMetaMap,
+// the output BrokerMap and the time that planning started and returns
+// a planStats. Storage related stats are only populated if the
+// PartitionMetaMap is non-nil.
+func getPlanStats(cmd *cobra.Command, pm1, pm2 *kafkazk.PartitionMap, pmm kafkazk.PartitionMetaMap, bm kafkazk.BrokerMap, start time.Time) planStats {
+	ps := planner.NewStats(pm1, pm2, nil, nil, nil)
+
+	s := planStats{
+		command:         cmd.Use,
+		partitions:      ps.Partitions,
+		partitionsMoved: ps.PartitionsMoved,
+		replicasMoved:   ps.ReplicasMoved,
+		duration:        time.Since(start),
+	}
+
+	if pmm != nil {
+		if incoming, err := pm1.IncomingStorage(pm2, pmm); err == nil {
+			s.withStorage = true
+			for _, v := range incoming {
+				s.bytesMoved += v
+			}
+			s.storageStdDev = bm.StorageStdDev()
+		}
+	}
+
+	return s
+}
+
+// metrics returns a map of metric names to values.
+func (s planStats) metrics() map[string]float64 {
+	m := map[string]float64{
+		"partitions":       float64(s.partitions),
+		"partitions_moved": float64(s.partitionsMoved),
+		"replicas_moved":   float64(s.replicasMoved),
+		"duration_seconds": s.duration.Seconds(),
+	}
+
+	if s.withStorage {
+		m["bytes_moved"] = s.bytesMoved
+		m["storage_stddev_bytes"] = s.storageStdDev
+	}
+
+	return m
+}
+
+// sortedNames returns the metric names
+// of m in lexical order.
+func sortedNames(m map[string]float64) []string {
+	var names []string
+	for n := range m {
+		names = append(names, n)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// emitPlanStats sends the planStats to the backend configured
+// via --metrics-backend. Emitting is best effort; errors are
+// printed but do not affect the exit status.
+func emitPlanStats(cmd *cobra.Command, s planStats) {
+	backend := cmd.Flag("metrics-backend").Value.String()
+	if backend == "" {
+		return
+	}
+
+	addr := cmd.Flag("metrics-addr").Value.String()
+	if addr == "" {
+		addr = metricsBackendDefaults[backend]
+	}
+
+	prefix := cmd.Flag("metrics-prefix").Value.String()
+
+	var err error
+
+	switch backend {
+	case "statsd":
+		err = emitStatsd(addr, prefix, s)
+	case "pushgateway":
+		err = emitPushgateway(addr, prefix, s)
+	case "honeycomb":
+		key := cmd.Flag("metrics-api-key").Value.String()
+		err = emitHoneycomb(addr, prefix, key, s)
+	}
+
+	if err != nil {
+		fmt.Printf("\nError emitting metrics to %s: %s\n", backend, err)
+		return
+	}
+
+	fmt.Printf("\nMetrics emitted to %s (%s)\n", backend, addr)
+}
+
+// emitStatsd writes each metric as a statsd gauge named
+// <prefix>.<command>.<metric> to the UDP address addr.
+func emitStatsd(addr, prefix string, s planStats) error {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	m := s.metrics()
+
+	var buf bytes.Buffer
+	for _, n := range sortedNames(m) {
+		fmt.Fprintf(&buf, "%s.%s.%s:%f|g\n", prefix, s.command, n, m[n])
+	}
+
+	_, err = conn.Write(buf.Bytes())
+
+	return err
+}
+
+// emitPushgateway pushes the metrics in the Prometheus text format
+// to a Pushgateway at addr, replacing any metrics previously pushed
+// under the job <prefix> and the command label.
+func emitPushgateway(addr, prefix string, s planStats) error {
+	m := s.metrics()
+
+	var buf bytes.Buffer
+	for _, n := range sortedNames(m) {
+		name := fmt.Sprintf("%s_%s", prefix, n)
+		fmt.Fprintf(&buf, "# TYPE %s gauge\n%s %f\n", name, name, m[n])
+	}
+
+	url := fmt.Sprintf("%s/metrics/job/%s/command/%s",
+		strings.TrimSuffix(addr, "/"), prefix, s.command)
+
+	req, err := http.NewRequest(http.MethodPut, url, &buf)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	return doMetricsRequest(req)
+}
+
+// emitHoneycomb sends the metrics as a single event
+// to the Honeycomb dataset <prefix>.
+func emitHoneycomb(addr, dataset, key string, s planStats) error {
+	event := map[string]interface{}{"command": s.command}
+	for n, v := range s.metrics() {
+		event[n] = v
+	}
+
+	var topics []string
+	for _, t := range Config.topics {
+		topics = append(topics, t.String())
+	}
+
+	event["topics"] = topics
+
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/1/events/%s", strings.TrimSuffix(addr, "/"), dataset)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Honeycomb-Team", key)
+
+	return doMetricsRequest(req)
+}
+
+// doMetricsRequest performs the request and returns
+// an error for any non 2xx response.
+func doMetricsRequest(req *http.Request) error {
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+
+	return nil
+}
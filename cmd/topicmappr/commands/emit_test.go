@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+func TestGetPlanStats(t *testing.T) {
+	pm1, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test","partition":0,"replicas":[1001,1002]},
+    {"topic":"test","partition":1,"replicas":[1002,1001]},
+    {"topic":"test","partition":2,"replicas":[1003,1004]}]}`)
+
+	pm2, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test","partition":0,"replicas":[1001,1002]},
+    {"topic":"test","partition":1,"replicas":[1001,1002]},
+    {"topic":"test","partition":2,"replicas":[1005,1006]}]}`)
+
+	cmd := &cobra.Command{Use: "rebuild"}
+
+	s := getPlanStats(cmd, pm1, pm2, nil, nil, time.Now())
+
+	if s.partitions != 3 {
+		t.Errorf("Expected 3 partitions, got %d", s.partitions)
+	}
+
+	// p1 only changed leadership.
+	if s.partitionsMoved != 2 {
+		t.Errorf("Expected 2 partitions moved, got %d", s.partitionsMoved)
+	}
+
+	if s.replicasMoved != 2 {
+		t.Errorf("Expected 2 replicas moved, got %d", s.replicasMoved)
+	}
+
+	// Storage metrics shouldn't be reported
+	// without partition metadata.
+	if _, exists := s.metrics()["bytes_moved"]; exists {
+		t.Error("Unexpected bytes_moved metric")
+	}
+}
+
+func TestEmitPushgateway(t *testing.T) {
+	var path, body string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer ts.Close()
+
+	s := planStats{command: "rebalance", partitionsMoved: 4}
+
+	if err := emitPushgateway(ts.URL, "topicmappr", s); err != nil {
+		t.Fatal(err)
+	}
+
+	if path != "/metrics/job/topicmappr/command/rebalance" {
+		t.Errorf("Unexpected path %s", path)
+	}
+
+	if !strings.Contains(body, "topicmappr_partitions_moved 4.000000\n") {
+		t.Errorf("Unexpected body:\n%s", body)
+	}
+}
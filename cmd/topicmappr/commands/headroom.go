@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+// checkStorageHeadroom takes the input and output PartitionMap, a
+// PartitionMetaMap and a BrokerMetaMap and estimates the peak storage
+// utilization for each broker receiving partitions. Kafka doesn't delete
+// replicas from source brokers until a reassignment completes, so the peak
+// only accounts for incoming data; storage freed by outgoing replicas is
+// ignored. An error is returned for each broker exceeding --max-utilization.
+func checkStorageHeadroom(cmd *cobra.Command, pm1, pm2 *kafkazk.PartitionMap, pmm kafkazk.PartitionMetaMap, bmm kafkazk.BrokerMetaMap) errors {
+	var errs errors
+
+	max, _ := cmd.Flags().GetFloat64("max-utilization")
+	if max == 0.00 {
+		return errs
+	}
+
+	// Only rebuild has a partition size factor.
+	psf := 1.00
+	if cmd.Flags().Lookup("partition-size-factor") != nil {
+		psf, _ = cmd.Flags().GetFloat64("partition-size-factor")
+	}
+
+	incoming, err := pm1.IncomingStorage(pm2, pmm)
+	if err != nil {
+		return append(errs, err)
+	}
+
+	fmt.Printf("\nPeak storage utilization estimations (%.2f%% limit):\n", max*100)
+
+	if len(incoming) == 0 {
+		fmt.Printf("%s[none]\n", indent)
+		return errs
+	}
+
+	ids := []int{}
+	for id := range incoming {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		in := incoming[id] * psf
+
+		meta, exists := bmm[id]
+		if !exists || meta.StorageCapacity <= 0 {
+			fmt.Printf("%sBroker %d: storage capacity unknown (+%.2fGB incoming)\n", indent, id, in/div)
+			errs = append(errs, fmt.Errorf("storage capacity unknown for broker %d", id))
+			continue
+		}
+
+		used := meta.StorageCapacity - meta.StorageFree
+		before := used / meta.StorageCapacity
+		peak := (used + in) / meta.StorageCapacity
+
+		fmt.Printf("%sBroker %d: %.2f%% -> %.2f%% (+%.2fGB incoming)\n",
+			indent, id, before*100, peak*100, in/div)
+
+		if peak > max {
+			errs = append(errs, fmt.Errorf("broker %d peak storage utilization of %.2f%% would exceed %.2f%%",
+				id, peak*100, max*100))
+		}
+	}
+
+	return errs
+}
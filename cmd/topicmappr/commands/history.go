@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"sort"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List and retrieve previously generated plans",
+	Long: `history lists plans recorded in ZooKeeper by commands run with the
+--record-history global flag. Each record includes the time, user, command parameters
+and the original and planned assignments of all changed partitions. A single record can
+be inspected via --id, and its planned map or its original map (--rollback) written
+as output via --write.`,
+	Run: history,
+}
+
+// Flags excluded from recorded
+// plan parameters.
+var historyExcludedFlags = map[string]struct{}{
+	"metrics-api-key": struct{}{},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().String("id", "", "Retrieve the plan with the provided ID")
+	historyCmd.Flags().Int("limit", 20, "Maximum number of most recent plans to list")
+	historyCmd.Flags().Bool("write", false, "Write the planned partition map of the plan specified via --id")
+	historyCmd.Flags().Bool("rollback", false, "Write the original partition map of the plan specified via --id, reverting it (implies --write)")
+	historyCmd.Flags().String("out-path", "", "Path to write output map files to")
+	historyCmd.Flags().String("out-file", "", "If defined, write a combined map of all topics to a file")
+}
+
+// recordPlan persists the plan from the PartitionMap pm1 to pm2 at the
+// --history-path if --record-history is set. Only changed partitions are
+// recorded. A ZooKeeper connection is initialized if zk is nil. Recording
+// is best effort; errors are printed but do not affect the exit status.
+func recordPlan(cmd *cobra.Command, zk kafkazk.Handler, pm1, pm2 *kafkazk.PartitionMap) {
+	if rh, _ := cmd.Flags().GetBool("record-history"); !rh {
+		return
+	}
+
+	input, output := skipReassignmentNoOps(pm1, pm2)
+	if len(output.Partitions) == 0 {
+		return
+	}
+
+	if zk == nil {
+		var err error
+		zk, err = initZooKeeper(cmd)
+		if err != nil {
+			fmt.Printf("\nError recording plan history: %s\n", err)
+			return
+		}
+
+		defer zk.Close()
+	}
+
+	r := &kafkazk.PlanRecord{
+		User:    currentUser(),
+		Command: cmd.Use,
+		Params:  map[string]string{},
+		Input:   input,
+		Output:  output,
+	}
+
+	// Record all explicitly set flags.
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if _, excluded := historyExcludedFlags[f.Name]; !excluded {
+			r.Params[f.Name] = f.Value.String()
+		}
+	})
+
+	path := cmd.Flag("history-path").Value.String()
+	if err := kafkazk.WritePlanRecord(zk, path, r); err != nil {
+		fmt.Printf("\nError recording plan history: %s\n", err)
+		return
+	}
+
+	fmt.Printf("\nPlan recorded as %s/%s\n", path, r.ID)
+}
+
+// currentUser returns the name of
+// the user running topicmappr.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+
+	return os.Getenv("USER")
+}
+
+func history(cmd *cobra.Command, _ []string) {
+	id := cmd.Flag("id").Value.String()
+	write, _ := cmd.Flags().GetBool("write")
+	rollback, _ := cmd.Flags().GetBool("rollback")
+
+	if (write || rollback) && id == "" {
+		fmt.Println("\n[ERROR] --write and --rollback require --id")
+		defaultsAndExit()
+	}
+
+	bootstrap(cmd)
+
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	path := cmd.Flag("history-path").Value.String()
+
+	// List recent plans.
+	if id == "" {
+		ids, err := kafkazk.GetPlanRecordIDs(zk, path)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if limit, _ := cmd.Flags().GetInt("limit"); limit > 0 && len(ids) > limit {
+			ids = ids[len(ids)-limit:]
+		}
+
+		fmt.Printf("\nPlans (%s):\n", path)
+		if len(ids) == 0 {
+			fmt.Printf("%s[none]\n", indent)
+		}
+
+		for _, id := range ids {
+			r, err := kafkazk.GetPlanRecord(zk, path, id)
+			if err != nil {
+				fmt.Printf("%s%s: %s\n", indent, id, err)
+				continue
+			}
+
+			fmt.Printf("%s%s %s, user: %s, partitions: %d\n",
+				indent, r.ID, r.Command, r.User, len(r.Output.Partitions))
+		}
+
+		return
+	}
+
+	// Retrieve a single plan.
+	r, err := kafkazk.GetPlanRecord(zk, path, id)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nPlan %s:\n", r.ID)
+	fmt.Printf("%stime: %s\n", indent, time.Unix(r.Timestamp, 0).UTC().Format(time.RFC3339))
+	fmt.Printf("%suser: %s\n", indent, r.User)
+	fmt.Printf("%scommand: %s\n", indent, r.Command)
+
+	var params []string
+	for p := range r.Params {
+		params = append(params, p)
+	}
+
+	sort.Strings(params)
+
+	fmt.Printf("\nParameters:\n")
+	for _, p := range params {
+		fmt.Printf("%s--%s=%s\n", indent, p, r.Params[p])
+	}
+
+	printMapChanges(r.Input, r.Output)
+
+	switch {
+	case rollback:
+		writeMaps(cmd, r.Input)
+	case write:
+		writeMaps(cmd, r.Output)
+	}
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	"github.com/honeycombio/kafka-kit/planner"
+
+	"github.com/spf13/cobra"
+)
+
+// getPlacementHooks returns the PlacementHooks specified via
+// --placement-hooks along with the spec of each. Exec hooks
+// are configured with the --placement-hook-timeout.
+func getPlacementHooks(cmd *cobra.Command) ([]planner.PlacementHook, []string) {
+	var hooks []planner.PlacementHook
+	var specs []string
+
+	timeout, _ := cmd.Flags().GetInt("placement-hook-timeout")
+
+	for _, spec := range strings.Split(cmd.Flag("placement-hooks").Value.String(), ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		h, err := planner.LookupHook(spec)
+		if err != nil {
+			fmt.Printf("\n[ERROR] %s\n", err)
+			defaultsAndExit()
+		}
+
+		if e, ok := h.(planner.ExecHook); ok {
+			e.Timeout = time.Duration(timeout) * time.Second
+			h = e
+		}
+
+		hooks = append(hooks, h)
+		specs = append(specs, spec)
+	}
+
+	return hooks, specs
+}
+
+// applyPlacementHooks applies the placement hooks (see getPlacementHooks)
+// to the output map pm2 and returns the resulting map. The StorageFree values of the BrokerMap
+// are updated if partition metadata is available. The number of partitions
+// changed by each hook is printed.
+func applyPlacementHooks(cmd *cobra.Command, hooks []planner.PlacementHook, specs []string, pm1, pm2 *kafkazk.PartitionMap, bm kafkazk.BrokerMap, bmm kafkazk.BrokerMetaMap, pmm kafkazk.PartitionMetaMap) *kafkazk.PartitionMap {
+	if len(hooks) == 0 {
+		return pm2
+	}
+
+	fmt.Printf("\nPlacement hooks:\n")
+
+	out := pm2
+	for i, h := range hooks {
+		next, err := planner.ApplyHooks([]planner.PlacementHook{h}, planner.HookInput{
+			Command:       cmd.Use,
+			Input:         pm1,
+			Output:        out,
+			Brokers:       bm,
+			BrokerMeta:    bmm,
+			PartitionMeta: pmm,
+		})
+		if err != nil {
+			if he, ok := err.(planner.HookError); ok {
+				err = he.Err
+			}
+			fmt.Printf("%s%s: %s\n", indent, specs[i], err)
+			os.Exit(1)
+		}
+
+		var changed int
+		for j := range out.Partitions {
+			if !out.Partitions[j].Equal(next.Partitions[j]) {
+				changed++
+			}
+		}
+
+		fmt.Printf("%s%s: %d partitions changed\n", indent, specs[i], changed)
+
+		out = next
+	}
+
+	return out
+}
+
+// printHookChanges prints the number of partitions
+// changed by each placement hook in a rebuild.
+func printHookChanges(changes []int, specs []string) {
+	if len(changes) == 0 {
+		return
+	}
+
+	fmt.Printf("\nPlacement hooks:\n")
+	for i, n := range changes {
+		fmt.Printf("%s%s: %d partitions changed\n", indent, specs[i], n)
+	}
+}
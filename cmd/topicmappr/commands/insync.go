@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	"github.com/honeycombio/kafka-kit/planner"
+
+	"github.com/spf13/cobra"
+)
+
+// handleOutOfSync checks the PartitionMap for partitions that have assigned
+// replicas outside of the ISR or on brokers that aren't registered. Depending
+// on the --out-of-sync param, these partitions are either excluded from the
+// returned PartitionMap or returned as errors to be handled as warnings.
+func handleOutOfSync(cmd *cobra.Command, zk kafkazk.Handler, pm *kafkazk.PartitionMap, bm kafkazk.BrokerMetaMap) (*kafkazk.PartitionMap, errors) {
+	var errs errors
+
+	oos, err := planner.OutOfSync(zk, pm, bm)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if len(oos) == 0 {
+		return pm, errs
+	}
+
+	exclude := cmd.Flag("out-of-sync").Value.String() == "exclude"
+	printOutOfSync(oos, exclude)
+
+	if exclude {
+		pm = pm.Exclude(oos)
+		if len(pm.Partitions) == 0 {
+			fmt.Println("\nNo partitions remaining after exclusions, skipping map generation")
+			os.Exit(0)
+		}
+
+		return pm, errs
+	}
+
+	for _, p := range oos {
+		errs = append(errs, fmt.Errorf("%s p%d has out of sync replicas", p.Topic, p.Partition))
+	}
+
+	return pm, errs
+}
+
+// printOutOfSync prints the out of sync partitions
+// and, if excluded, the number excluded.
+func printOutOfSync(oos kafkazk.OutOfSyncPartitions, exclude bool) {
+	if len(oos) == 0 {
+		return
+	}
+
+	fmt.Printf("\nOut of sync partitions:\n")
+	for _, p := range oos {
+		fmt.Printf("%s%s\n", indent, p)
+	}
+
+	if exclude {
+		fmt.Printf("%s-\n%sExcluding %d partition(s) from planning\n", indent, indent, len(oos))
+	}
+}
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+// filterInternalTopics removes internal topics (e.g. __consumer_offsets)
+// from a PartitionMap built from --topics unless --include-internal is
+// set or the topic was explicitly provided by name. The filtered map is
+// returned.
+func filterInternalTopics(cmd *cobra.Command, pm *kafkazk.PartitionMap) *kafkazk.PartitionMap {
+	if ii, _ := cmd.Flags().GetBool("include-internal"); ii {
+		return pm
+	}
+
+	out := kafkazk.NewPartitionMap()
+	excluded := map[string]struct{}{}
+
+	for _, p := range pm.Partitions {
+		if kafkazk.IsInternalTopic(p.Topic) && !Config.explicitTopics[p.Topic] {
+			excluded[p.Topic] = struct{}{}
+			continue
+		}
+		out.Partitions = append(out.Partitions, p)
+	}
+
+	if len(excluded) == 0 {
+		return pm
+	}
+
+	fmt.Printf("\nExcluding internal topics (override with --include-internal):\n")
+	for t := range excluded {
+		fmt.Printf("%s%s\n", indent, t)
+	}
+
+	if len(out.Partitions) == 0 {
+		fmt.Println("\nNo topics remaining after exclusions")
+		os.Exit(1)
+	}
+
+	return out
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+// loadLeaderPins reads the file specified via --leader-pins, storing the
+// pins for all partitions in the PartitionMap at Config.leaderPins. The file
+// is a JSON object mapping topic names and partition numbers to either a
+// broker ID or rack, e.g. {"topic": {"0": {"broker": 1001}, "1": {"rack":
+// "a"}}}. Racks are translated to placement domains if --rack-groups is set.
+// Pinned partitions are printed.
+func loadLeaderPins(cmd *cobra.Command, pm *kafkazk.PartitionMap) {
+	path, _ := cmd.Flags().GetString("leader-pins")
+	if path == "" {
+		return
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Printf("Error reading leader pins: %s\n", err)
+		os.Exit(1)
+	}
+
+	file := kafkazk.LeaderPins{}
+	if err := json.Unmarshal(data, &file); err != nil {
+		fmt.Printf("Error parsing leader pins: %s\n", err)
+		os.Exit(1)
+	}
+
+	g := getRackGroups(cmd)
+	lp := kafkazk.LeaderPins{}
+	var pinned kafkazk.PartitionList
+
+	for _, p := range pm.Partitions {
+		pin, exists := file.Pin(p)
+		if !exists {
+			continue
+		}
+
+		if (pin.Broker == 0) == (pin.Rack == "") {
+			fmt.Printf("Error parsing leader pins: %s p%d: exactly one of broker or rack must be set\n",
+				p.Topic, p.Partition)
+			os.Exit(1)
+		}
+
+		pin.Rack = g.RackDomain(pin.Rack)
+
+		if _, exists := lp[p.Topic]; !exists {
+			lp[p.Topic] = map[int]kafkazk.LeaderPin{}
+		}
+
+		lp[p.Topic][p.Partition] = pin
+		pinned = append(pinned, p)
+	}
+
+	if len(lp) == 0 {
+		return
+	}
+
+	Config.leaderPins = lp
+
+	sort.Sort(pinned)
+
+	fmt.Printf("\nLeader pins:\n")
+	for _, p := range pinned {
+		pin, _ := lp.Pin(p)
+		fmt.Printf("%s%s p%d: %s\n", indent, p.Topic, p.Partition, pin)
+	}
+}
@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+var leadershipCmd = &cobra.Command{
+	Use:   "leadership",
+	Short: "Report leadership skew and plan preferred leader elections",
+	Long: `leadership reports the current and preferred partition leaders per broker for
+the topics specified via --topics, along with all partitions not led by their
+preferred leader (the first replica). A preferred leader election for those
+partitions can be written to a file for use with kafka-leader-election.sh via
+--election-file or triggered directly via --execute. Only partitions where the
+preferred leader is in the ISR are included in the election. The --brokers flag
+scopes the election to partitions currently or preferably led by the listed brokers.
+
+If the skew stems from the preferred leaders themselves, --optimize-leadership
+writes a partition map that reorders replicas without any data movement; the
+election should be triggered once the reassignment has completed.`,
+	Run: leadership,
+}
+
+func init() {
+	rootCmd.AddCommand(leadershipCmd)
+
+	leadershipCmd.Flags().String("topics", "", "Topics (comma delim. list) to report leadership for by lookup in ZooKeeper")
+	leadershipCmd.Flags().String("brokers", "", "Broker list to scope the election to; partitions currently or preferably led by any listed broker are included")
+	leadershipCmd.Flags().Bool("optimize-leadership", false, "Write a map that reorders replicas to rebalance preferred leaders")
+	leadershipCmd.Flags().String("out-path", "", "Path to write output map files to")
+	leadershipCmd.Flags().String("out-file", "", "If defined, write a combined map of all topics to a file")
+	leadershipCmd.Flags().String("election-file", "", "If defined, write the preferred leader election to a file")
+	leadershipCmd.Flags().Bool("execute", false, "Trigger the preferred leader election")
+
+	// Required.
+	leadershipCmd.MarkFlagRequired("topics")
+}
+
+func leadership(cmd *cobra.Command, _ []string) {
+	ol, _ := cmd.Flags().GetBool("optimize-leadership")
+	execute, _ := cmd.Flags().GetBool("execute")
+	if ol && execute {
+		fmt.Println("\n[ERROR] --execute cannot be used with --optimize-leadership; trigger the election once the reassignment completes")
+		defaultsAndExit()
+	}
+
+	bootstrap(cmd)
+
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	pm, err := kafkazk.PartitionMapFromZK(Config.topics, zk)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	printTopics(pm)
+
+	pl, err := pm.Leadership(zk)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	printLeadershipSkew("Leadership skew", pl)
+
+	// Rebalance the preferred leaders. Replica
+	// sets are unchanged, so the ISR states
+	// hold for the optimized map.
+	if ol {
+		partitionMapOut := pm.Copy()
+		partitionMapOut.OptimizeLeaderFollower()
+
+		pl, err = partitionMapOut.Leadership(zk)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		printMapChanges(pm, partitionMapOut)
+
+		printLeadershipSkew("Leadership skew after reassignment", pl)
+
+		_, partitionMapOut = skipReassignmentNoOps(pm, partitionMapOut)
+		writeMaps(cmd, partitionMapOut)
+	}
+
+	pl = scopeLeaderships(pl, Config.brokers)
+	printImbalancedLeaderships(pl)
+
+	e := pl.Election()
+
+	if ef := cmd.Flag("election-file").Value.String(); ef != "" {
+		if err := writeElection(e, ef); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("\nPreferred leader election for %d partitions written to %s\n", len(e.Partitions), ef)
+		fmt.Printf("%skafka-leader-election.sh --bootstrap-server <broker> --election-type preferred --path-to-json-file %s\n", indent, ef)
+	}
+
+	if !execute {
+		return
+	}
+
+	if len(e.Partitions) == 0 {
+		fmt.Println("\nNo eligible partitions, skipping election")
+		return
+	}
+
+	if err := zk.ElectPreferredLeaders(e); err != nil {
+		fmt.Printf("\nError triggering election: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nTriggered preferred leader election for %d partitions\n", len(e.Partitions))
+}
+
+// printLeadershipSkew prints the current, preferred and post-election
+// number of partitions led per broker along with the spread of each.
+func printLeadershipSkew(title string, pl kafkazk.PartitionLeaderships) {
+	bl := pl.BrokerLeadership()
+
+	fmt.Printf("\n%s:\n", title)
+	if len(bl) == 0 {
+		fmt.Printf("%s[none]\n", indent)
+		return
+	}
+
+	for _, b := range bl {
+		fmt.Printf("%sBroker %d - leader: %d, preferred: %d, after election: %d\n",
+			indent, b.ID, b.Leader, b.Preferred, b.Elected)
+	}
+
+	spread := func(f func(kafkazk.BrokerLeadership) int) (int, int) {
+		min, max := f(bl[0]), f(bl[0])
+		for _, b := range bl[1:] {
+			if v := f(b); v < min {
+				min = v
+			} else if v > max {
+				max = v
+			}
+		}
+		return min, max
+	}
+
+	curMin, curMax := spread(func(b kafkazk.BrokerLeadership) int { return b.Leader })
+	elMin, elMax := spread(func(b kafkazk.BrokerLeadership) int { return b.Elected })
+
+	fmt.Printf("%s-\n", indent)
+	fmt.Printf("%sLeaders per broker - current: %d/%d (min/max), after election: %d/%d (min/max)\n",
+		indent, curMin, curMax, elMin, elMax)
+}
+
+// printImbalancedLeaderships prints all partitions
+// not led by their preferred leader.
+func printImbalancedLeaderships(pl kafkazk.PartitionLeaderships) {
+	fmt.Printf("\nPartitions not led by their preferred leader:\n")
+
+	imbalanced := pl.Imbalanced()
+	if len(imbalanced) == 0 {
+		fmt.Printf("%s[none]\n", indent)
+		return
+	}
+
+	for _, l := range imbalanced {
+		var note string
+		if !l.Eligible {
+			note = " (preferred leader not in ISR, skipping)"
+		}
+
+		fmt.Printf("%s%s p%d: leader %d, preferred %d%s\n",
+			indent, l.Topic, l.Partition, l.Leader, l.Preferred, note)
+	}
+}
+
+// scopeLeaderships takes a PartitionLeaderships and a broker list and
+// returns the PartitionLeaderships currently or preferably led by any
+// of the brokers. All PartitionLeaderships are returned if the broker
+// list is empty.
+func scopeLeaderships(pl kafkazk.PartitionLeaderships, brokers []int) kafkazk.PartitionLeaderships {
+	if len(brokers) == 0 {
+		return pl
+	}
+
+	ids := map[int]bool{}
+	for _, id := range brokers {
+		ids[id] = true
+	}
+
+	var out kafkazk.PartitionLeaderships
+	for _, l := range pl {
+		if ids[l.Leader] || ids[l.Preferred] {
+			out = append(out, l)
+		}
+	}
+
+	return out
+}
+
+// writeElection writes the PreferredReplicaElection to the file path.
+func writeElection(e kafkazk.PreferredReplicaElection, path string) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("Error writing election file: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+func TestScopeLeaderships(t *testing.T) {
+	pl := kafkazk.PartitionLeaderships{
+		{Topic: "a", Partition: 0, Preferred: 1001, Leader: 1002},
+		{Topic: "a", Partition: 1, Preferred: 1003, Leader: 1001},
+		{Topic: "a", Partition: 2, Preferred: 1003, Leader: 1004},
+	}
+
+	if out := scopeLeaderships(pl, nil); len(out) != 3 {
+		t.Errorf("Expected unscoped leaderships, got %v", out)
+	}
+
+	out := scopeLeaderships(pl, []int{1001})
+	if len(out) != 2 || out[0].Partition != 0 || out[1].Partition != 1 {
+		t.Errorf("Unexpected scoped leaderships: %v", out)
+	}
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+// getRackGroups reads the file specified via --rack-groups and returns
+// the kafkazk.RackGroups mapping physical racks to placement domains.
+// The file is a JSON object, e.g. {"racks": {"rack": "domain"},
+// "brokers": {"1001": "domain"}}. A zero value is returned if unset.
+func getRackGroups(cmd *cobra.Command) kafkazk.RackGroups {
+	var g kafkazk.RackGroups
+
+	path, _ := cmd.Flags().GetString("rack-groups")
+	if path == "" {
+		return g
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Printf("Error reading rack groups: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := json.Unmarshal(data, &g); err != nil {
+		fmt.Printf("Error parsing rack groups: %s\n", err)
+		os.Exit(1)
+	}
+
+	return g
+}
+
+// getConsumerRacks reads the file specified via --consumer-racks
+// and returns a mapping of topic names to the rack of their dominant
+// consumers. The file is a JSON object, e.g. {"topic": "rack"}. Racks
+// are translated to placement domains if --rack-groups is set.
+func getConsumerRacks(cmd *cobra.Command) map[string]string {
+	path := cmd.Flag("consumer-racks").Value.String()
+	if path == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Printf("Error reading consumer racks: %s\n", err)
+		os.Exit(1)
+	}
+
+	racks := map[string]string{}
+	if err := json.Unmarshal(data, &racks); err != nil {
+		fmt.Printf("Error parsing consumer racks: %s\n", err)
+		os.Exit(1)
+	}
+
+	g := getRackGroups(cmd)
+	for t, r := range racks {
+		racks[t] = g.RackDomain(r)
+	}
+
+	return racks
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
 	"github.com/honeycombio/kafka-kit/kafkazk"
 
 	"github.com/spf13/cobra"
@@ -12,6 +13,11 @@ import (
 
 func checkMetaAge(cmd *cobra.Command, zk kafkazk.Handler) {
 	age, err := zk.MaxMetaAge()
+	if err == kafkaadmin.ErrNotSupported {
+		fmt.Println("Broker and partition metrics aren't available via the Kafka Admin API; set --metrics-zk-addr")
+		os.Exit(1)
+	}
+
 	if err != nil {
 		fmt.Printf("Error fetching metrics metadata: %s\n", err)
 		os.Exit(1)
@@ -28,7 +34,8 @@ func checkMetaAge(cmd *cobra.Command, zk kafkazk.Handler) {
 // getBrokerMeta returns a map of brokers and broker metadata
 // for those registered in ZooKeeper. Optionally, metrics metadata
 // persisted in ZooKeeper (via an external mechanism*) can be merged
-// into the metadata.
+// into the metadata. Broker racks are translated to placement domains
+// if --rack-groups is set.
 func getBrokerMeta(cmd *cobra.Command, zk kafkazk.Handler, m bool) kafkazk.BrokerMetaMap {
 	brokerMeta, errs := zk.GetAllBrokerMeta(m)
 	// If no data is returned, report and exit.
@@ -43,6 +50,8 @@ func getBrokerMeta(cmd *cobra.Command, zk kafkazk.Handler, m bool) kafkazk.Broke
 		os.Exit(1)
 	}
 
+	getRackGroups(cmd).Apply(brokerMeta)
+
 	return brokerMeta
 }
 
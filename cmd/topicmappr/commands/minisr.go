@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	"github.com/honeycombio/kafka-kit/planner"
+
+	"github.com/spf13/cobra"
+)
+
+// checkMinISR takes the input and output PartitionMap and checks all changed
+// partitions against each topic's min.insync.replicas. Depending on
+// --min-isr-check, violations are returned as errors to be handled as
+// warnings, cause an exit, or are ignored.
+func checkMinISR(cmd *cobra.Command, zk kafkazk.Handler, pm1, pm2 *kafkazk.PartitionMap) errors {
+	var errs errors
+
+	policy := cmd.Flag("min-isr-check").Value.String()
+	if policy == "ignore" {
+		return errs
+	}
+
+	def, _ := cmd.Flags().GetInt("default-min-isr")
+
+	violations, err := planner.MinISRViolations(zk, pm1, pm2, def)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if len(violations) == 0 {
+		return errs
+	}
+
+	printMinISRViolations(violations)
+
+	if policy == "block" {
+		blockMinISR(violations)
+	}
+
+	for _, v := range violations {
+		errs = append(errs, fmt.Errorf("%s p%d could fall below min.insync.replicas", v.Topic, v.Partition))
+	}
+
+	return errs
+}
+
+// printMinISRViolations prints the min.insync.replicas violations.
+func printMinISRViolations(violations []kafkazk.MinISRViolation) {
+	if len(violations) == 0 {
+		return
+	}
+
+	fmt.Printf("\nmin.insync.replicas violations:\n")
+	for _, v := range violations {
+		fmt.Printf("%s%s\n", indent, v)
+	}
+}
+
+// blockMinISR exits following min.insync.replicas
+// violations with --min-isr-check=block.
+func blockMinISR(violations []kafkazk.MinISRViolation) {
+	fmt.Printf("\n%s%d partition(s) could fall below min.insync.replicas, partition map not created.\n",
+		indent, len(violations))
+	os.Exit(1)
+}
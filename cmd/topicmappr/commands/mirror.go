@@ -0,0 +1,293 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+var mirrorCmd = &cobra.Command{
+	Use:   "mirror",
+	Short: "Plan consistent placements for topics mirrored between two clusters",
+	Long: `mirror plans partition maps for topics in the destination cluster (specified
+via the --zk-addr and --zk-prefix global flags) that are mirrored from a source cluster
+(specified via --source-zk-addr and --source-zk-prefix), e.g. in active/active MirrorMaker
+setups. Source topics are matched via --topics; destination topics are assumed to have
+the same name unless mapped via --topic-mapping. Each destination topic is rebuilt across
+the --brokers list with the replication factor of its source topic. Partition count
+mismatches and any destination topics with a narrower broker or rack spread than
+their source topic are reported.`,
+	Run: mirror,
+}
+
+func init() {
+	rootCmd.AddCommand(mirrorCmd)
+
+	mirrorCmd.Flags().String("source-zk-addr", "", "Source cluster ZooKeeper connect string")
+	mirrorCmd.Flags().String("source-zk-prefix", "", "Source cluster ZooKeeper prefix (if Kafka is configured with a chroot path prefix)")
+	mirrorCmd.Flags().String("topics", "", "Source topics (comma delim. list) to mirror by lookup in the source ZooKeeper")
+	mirrorCmd.Flags().String("topic-mapping", "", "Comma delimited list of source:destination topic names for topics with differing names")
+	mirrorCmd.Flags().String("brokers", "", "Destination broker list to scope all partition placements to ('-1' automatically expands to all currently mapped brokers)")
+	mirrorCmd.Flags().Bool("force-rebuild", false, "Forces a complete map rebuild")
+	mirrorCmd.Flags().Int("min-rack-ids", 0, "Minimum number of required of unique rack IDs per replica set (0 requires that all are unique)")
+	mirrorCmd.Flags().Bool("optimize-leadership", false, "Rebalance all broker leader/follower ratios")
+	mirrorCmd.Flags().String("out-path", "", "Path to write output map files to")
+	mirrorCmd.Flags().String("out-file", "", "If defined, write a combined map of all topics to a file")
+	mirrorCmd.Flags().String("zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics")
+
+	// Required.
+	mirrorCmd.MarkFlagRequired("source-zk-addr")
+	mirrorCmd.MarkFlagRequired("topics")
+	mirrorCmd.MarkFlagRequired("brokers")
+}
+
+// topicSpread describes the distribution
+// of a topic's replicas.
+type topicSpread struct {
+	partitions  int
+	replication int
+	brokers     int
+	racks       int
+}
+
+func (t topicSpread) String() string {
+	return fmt.Sprintf("partitions: %d, replication: %d, brokers: %d, racks: %d",
+		t.partitions, t.replication, t.brokers, t.racks)
+}
+
+// getTopicSpread takes a PartitionMap holding a single topic and a
+// BrokerMetaMap and returns a topicSpread. The replication value
+// is the maximum replica set length observed.
+func getTopicSpread(pm *kafkazk.PartitionMap, bm kafkazk.BrokerMetaMap) topicSpread {
+	brokers := map[int]struct{}{}
+	racks := map[string]struct{}{}
+	s := topicSpread{partitions: len(pm.Partitions)}
+
+	for _, p := range pm.Partitions {
+		if len(p.Replicas) > s.replication {
+			s.replication = len(p.Replicas)
+		}
+
+		for _, id := range p.Replicas {
+			brokers[id] = struct{}{}
+			if meta, exists := bm[id]; exists && meta.Rack != "" {
+				racks[meta.Rack] = struct{}{}
+			}
+		}
+	}
+
+	s.brokers, s.racks = len(brokers), len(racks)
+
+	return s
+}
+
+// parseTopicMapping takes a comma delimited list of
+// source:destination topic name pairs and returns a
+// map of source to destination names.
+func parseTopicMapping(s string) (map[string]string, error) {
+	m := map[string]string{}
+	if s == "" {
+		return m, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		names := strings.Split(strings.TrimSpace(pair), ":")
+		if len(names) != 2 || names[0] == "" || names[1] == "" {
+			return nil, fmt.Errorf("invalid topic mapping '%s'", pair)
+		}
+
+		m[names[0]] = names[1]
+	}
+
+	return m, nil
+}
+
+func mirror(cmd *cobra.Command, _ []string) {
+	mapping, err := parseTopicMapping(cmd.Flag("topic-mapping").Value.String())
+	if err != nil {
+		fmt.Printf("\n[ERROR] %s\n", err)
+		defaultsAndExit()
+	}
+
+	bootstrap(cmd)
+
+	// ZooKeeper init for both clusters.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	srcZK, err := initZooKeeperAddr(cmd, cmd.Flag("source-zk-addr").Value.String(),
+		cmd.Flag("source-zk-prefix").Value.String())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer srcZK.Close()
+
+	// Fetch broker metadata from both clusters.
+	brokerMeta := getBrokerMeta(cmd, zk, false)
+	srcBrokerMeta := getBrokerMeta(cmd, srcZK, false)
+
+	// Get the source partition map.
+	srcMap, err := kafkazk.PartitionMapFromZK(Config.topics, srcZK)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	srcMap = filterInternalTopics(cmd, srcMap)
+
+	// Split the source map by topic.
+	srcTopics := map[string]*kafkazk.PartitionMap{}
+	for _, p := range srcMap.Partitions {
+		if srcTopics[p.Topic] == nil {
+			srcTopics[p.Topic] = kafkazk.NewPartitionMap()
+		}
+		srcTopics[p.Topic].Partitions = append(srcTopics[p.Topic].Partitions, p)
+	}
+
+	var names []string
+	for t := range srcTopics {
+		names = append(names, t)
+	}
+
+	sort.Strings(names)
+
+	var errs errors
+	partitionMapIn := kafkazk.NewPartitionMap()
+	dstNames := map[string]string{}
+
+	// Build the combined destination map with
+	// each topic's replication factor normalized
+	// to that of the source topic.
+	fmt.Printf("\nMirrored topics:\n")
+	for _, src := range names {
+		dst := src
+		if d, mapped := mapping[src]; mapped {
+			dst = d
+		}
+
+		dstNames[src] = dst
+		fmt.Printf("%s%s -> %s\n", indent, src, dst)
+
+		pm, err := zk.GetPartitionMap(dst)
+		if err != nil {
+			switch err.(type) {
+			case kafkazk.ErrNoNode:
+				errs = append(errs, fmt.Errorf("destination topic %s not found", dst))
+				continue
+			default:
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+
+		pm.SetReplication(getTopicSpread(srcTopics[src], srcBrokerMeta).replication)
+		partitionMapIn.Partitions = append(partitionMapIn.Partitions, pm.Partitions...)
+	}
+
+	if len(partitionMapIn.Partitions) == 0 {
+		handleOverridableErrs(cmd, errs)
+		fmt.Println("\nNo destination topics found, skipping map generation")
+		os.Exit(0)
+	}
+
+	sort.Sort(partitionMapIn.Partitions)
+	originalMap := partitionMapIn.Copy()
+
+	brokers, bs := getBrokers(cmd, partitionMapIn, brokerMeta)
+
+	// Count missing brokers as a warning.
+	if bs.Missing > 0 {
+		errs = append(errs, fmt.Errorf("%d provided brokers not found in ZooKeeper", bs.Missing))
+	}
+
+	// Rebuild the destination map.
+	mrrid, _ := cmd.Flags().GetInt("min-rack-ids")
+	rebuildParams := kafkazk.RebuildParams{
+		BM:               brokers,
+		Strategy:         "count",
+		Optimization:     "distribution",
+		PartnSzFactor:    1.00,
+		MinUniqueRackIDs: mrrid,
+	}
+
+	pm := partitionMapIn
+	if fr, _ := cmd.Flags().GetBool("force-rebuild"); fr {
+		pm = partitionMapIn.Strip()
+	}
+
+	partitionMapOut, rebuildErrs := pm.Rebuild(rebuildParams)
+	errs = append(errs, rebuildErrs...)
+
+	// Validate any included internal topics.
+	errs = append(errs, partitionMapOut.CheckInternalTopics(brokers)...)
+
+	if ol, _ := cmd.Flags().GetBool("optimize-leadership"); ol {
+		partitionMapOut.OptimizeLeaderFollower()
+	}
+
+	// Print map change results.
+	printMapChanges(originalMap, partitionMapOut)
+
+	// Split the output map by topic.
+	dstTopics := map[string]*kafkazk.PartitionMap{}
+	for _, p := range partitionMapOut.Partitions {
+		if dstTopics[p.Topic] == nil {
+			dstTopics[p.Topic] = kafkazk.NewPartitionMap()
+		}
+		dstTopics[p.Topic].Partitions = append(dstTopics[p.Topic].Partitions, p)
+	}
+
+	// The number of destination brokers available
+	// for placements.
+	available := len(brokers.Filter(func(b *kafkazk.Broker) bool {
+		return !b.Replace && b.ID != kafkazk.StubBrokerID
+	}))
+
+	// Compare source and destination spreads. A narrower
+	// broker spread is only reported if more destination
+	// brokers were available.
+	fmt.Printf("\nMirror spread:\n")
+	for _, src := range names {
+		dst := dstNames[src]
+		if dstTopics[dst] == nil {
+			continue
+		}
+
+		s1 := getTopicSpread(srcTopics[src], srcBrokerMeta)
+		s2 := getTopicSpread(dstTopics[dst], brokerMeta)
+
+		fmt.Printf("%s%s -> %s\n", indent, src, dst)
+		fmt.Printf("%s%s[%s] -> [%s]\n", indent, indent, s1, s2)
+
+		switch {
+		case s1.partitions != s2.partitions:
+			errs = append(errs, fmt.Errorf("%s has %d partitions, source topic %s has %d (partitions must be added with kafka-topics)",
+				dst, s2.partitions, src, s1.partitions))
+		case s2.racks < s1.racks:
+			errs = append(errs, fmt.Errorf("%s spans %d racks, source topic %s spans %d", dst, s2.racks, src, s1.racks))
+		case s2.brokers < s1.brokers && s2.brokers < available:
+			errs = append(errs, fmt.Errorf("%s spans %d brokers, source topic %s spans %d (try --force-rebuild)",
+				dst, s2.brokers, src, s1.brokers))
+		}
+	}
+
+	// Print error/warnings.
+	handleOverridableErrs(cmd, errs)
+
+	writeMaps(cmd, partitionMapOut)
+
+	// Record the plan if configured.
+	recordPlan(cmd, zk, originalMap, partitionMapOut)
+}
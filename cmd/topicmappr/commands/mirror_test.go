@@ -0,0 +1,22 @@
+package commands
+
+import (
+	"testing"
+)
+
+func TestParseTopicMapping(t *testing.T) {
+	m, err := parseTopicMapping("a:us.a, b:us.b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(m) != 2 || m["a"] != "us.a" || m["b"] != "us.b" {
+		t.Errorf("Unexpected mapping: %v", m)
+	}
+
+	for _, s := range []string{"a", "a:", ":b", "a:b:c"} {
+		if _, err := parseTopicMapping(s); err == nil {
+			t.Errorf("Expected error for mapping '%s'", s)
+		}
+	}
+}
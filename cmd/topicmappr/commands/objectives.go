@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/honeycombio/kafka-kit/planner"
+
+	"github.com/spf13/cobra"
+)
+
+// objectiveNames lists the --objective-weights
+// keys in the order objectives are printed.
+var objectiveNames = []string{"storage", "leadership", "movement", "rack-diversity", "cross-az"}
+
+// placementPolicy is the format of the --policy-file.
+type placementPolicy struct {
+	// A mapping of objective names
+	// (see objectiveNames) to weights.
+	ObjectiveWeights map[string]float64 `json:"objective_weights"`
+}
+
+// objectiveField returns a pointer to the field in
+// the Objectives for the objective name n.
+func objectiveField(o *planner.Objectives, n string) *float64 {
+	switch n {
+	case "storage":
+		return &o.Storage
+	case "leadership":
+		return &o.Leadership
+	case "movement":
+		return &o.Movement
+	case "rack-diversity":
+		return &o.RackDiversity
+	case "cross-az":
+		return &o.CrossAZ
+	}
+
+	return nil
+}
+
+// setObjectiveWeight sets the weight of the objective name n to v.
+func setObjectiveWeight(w *planner.ObjectiveWeights, n string, v float64) error {
+	f := objectiveField((*planner.Objectives)(w), n)
+	if f == nil {
+		return fmt.Errorf("invalid objective '%s'; must be one of [%s]", n, strings.Join(objectiveNames, ", "))
+	}
+
+	if v < 0 {
+		return fmt.Errorf("invalid weight %.2f for objective '%s'; must be 0 or greater", v, n)
+	}
+
+	*f = v
+
+	return nil
+}
+
+// parseObjectiveWeights takes a comma delimited list of name=weight
+// pairs (e.g. "storage=1,movement=0.5") and applies them to w.
+func parseObjectiveWeights(s string, w *planner.ObjectiveWeights) error {
+	for _, pair := range strings.FieldsFunc(s, isListDelim) {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid objective weight '%s': must be formatted as name=weight", pair)
+		}
+
+		v, err := strconv.ParseFloat(kv[1], 64)
+		if err != nil {
+			return fmt.Errorf("invalid objective weight '%s': %s", pair, err)
+		}
+
+		if err := setObjectiveWeight(w, kv[0], v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getObjectiveWeights returns the ObjectiveWeights from the --policy-file
+// and --objective-weights flags, where weights provided via flag take
+// precedence. The returned bool is false if neither flag is set, in which
+// case the fixed placement strategy selection applies.
+func getObjectiveWeights(cmd *cobra.Command) (planner.ObjectiveWeights, bool) {
+	var w planner.ObjectiveWeights
+
+	pf := cmd.Flag("policy-file").Value.String()
+	ow := cmd.Flag("objective-weights").Value.String()
+
+	if pf == "" && ow == "" {
+		return w, false
+	}
+
+	if pf != "" {
+		data, err := ioutil.ReadFile(pf)
+		if err != nil {
+			fmt.Printf("Error reading policy file: %s\n", err)
+			os.Exit(1)
+		}
+
+		var p placementPolicy
+		if err := json.Unmarshal(data, &p); err != nil {
+			fmt.Printf("Error parsing policy file: %s\n", err)
+			os.Exit(1)
+		}
+
+		for n, v := range p.ObjectiveWeights {
+			if err := setObjectiveWeight(&w, n, v); err != nil {
+				fmt.Printf("Error parsing policy file: %s\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	if err := parseObjectiveWeights(ow, &w); err != nil {
+		fmt.Printf("\n[ERROR] %s\n", err)
+		defaultsAndExit()
+	}
+
+	return w, true
+}
+
+// printObjectives prints the cost, weight and weighted
+// contribution of each objective along with the total.
+func printObjectives(o planner.Objectives, w planner.ObjectiveWeights) {
+	fmt.Printf("\nPlan objectives (cost x weight = contribution):\n")
+
+	weighted := o.Weighted(w)
+	for _, n := range objectiveNames {
+		fmt.Printf("%s%s: %.4f x %.2f = %.4f\n", indent, n,
+			*objectiveField(&o, n),
+			*objectiveField((*planner.Objectives)(&w), n),
+			*objectiveField(&weighted, n))
+	}
+
+	fmt.Printf("%s-\n", indent)
+	fmt.Printf("%stotal: %.4f\n", indent, weighted.Total())
+}
+
+// printCandidates prints the candidate plans evaluated
+// in a rebuild and the objectives of the selected plan.
+func printCandidates(candidates []planner.Candidate, w planner.ObjectiveWeights) {
+	if len(candidates) == 0 {
+		return
+	}
+
+	fmt.Printf("\nCandidate plans:\n")
+
+	var best planner.Candidate
+	for _, c := range candidates {
+		var selected string
+		if c.Selected {
+			best = c
+			selected = " (selected)"
+		}
+
+		fmt.Printf("%s%s -> cost: %.4f%s\n", indent, c, c.Objectives.Weighted(w).Total(), selected)
+	}
+
+	printObjectives(best.Objectives, w)
+}
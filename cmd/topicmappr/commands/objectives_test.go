@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/planner"
+)
+
+func TestParseObjectiveWeights(t *testing.T) {
+	w := planner.ObjectiveWeights{Storage: 1, Leadership: 1}
+
+	if err := parseObjectiveWeights("leadership=0.5, cross-az=2", &w); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := planner.ObjectiveWeights{Storage: 1, Leadership: 0.5, CrossAZ: 2}
+	if w != expected {
+		t.Errorf("Expected %+v, got %+v", expected, w)
+	}
+
+	for _, s := range []string{"storage", "storage=x", "latency=1", "movement=-1"} {
+		if err := parseObjectiveWeights(s, &w); err == nil {
+			t.Errorf("Expected error for '%s'", s)
+		}
+	}
+}
@@ -132,13 +132,27 @@ func printBrokerAssignmentStats(cmd *cobra.Command, pm1, pm2 *kafkazk.PartitionM
 
 		mb1, mb2 := bm1.Filter(mapped), bm2.Filter(nonReplaced)
 
+		// If the storage capacity of all brokers is known,
+		// storage is balanced by utilization; brokers of
+		// differing capacities may diverge in free storage.
+		capacityKnown := mb1.CapacityKnown() && mb2.CapacityKnown()
+
 		// Range before/after.
 		r1, r2 := mb1.StorageRange(), mb2.StorageRange()
 		fmt.Printf("%srange: %.2fGB -> %.2fGB\n", indent, r1/div, r2/div)
-		if r2 > r1 {
+		if r2 > r1 && !capacityKnown {
 			errs = append(errs, fmt.Errorf("broker free storage range increased"))
 		}
 
+		// Utilization range before/after.
+		if capacityKnown {
+			u1, u2 := mb1.StorageFreeRatioRange(), mb2.StorageFreeRatioRange()
+			fmt.Printf("%sutilization range: %.2f%% -> %.2f%%\n", indent, u1*100, u2*100)
+			if u2 > u1 {
+				errs = append(errs, fmt.Errorf("broker storage utilization range increased"))
+			}
+		}
+
 		// Range spread before/after.
 		rs1, rs2 := mb1.StorageRangeSpread(), mb2.StorageRangeSpread()
 		fmt.Printf("%srange spread: %.2f%% -> %.2f%%\n", indent, rs1, rs2)
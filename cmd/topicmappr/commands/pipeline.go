@@ -0,0 +1,401 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+var pipelineCmd = &cobra.Command{
+	Use:   "pipeline",
+	Short: "Plan a chain of rebuild, rebalance and leadership optimization steps",
+	Long: `pipeline chains multiple operations into a single staged plan, where the
+output of each step is the input to the next. Steps are provided in order via --steps:
+  rebuild: replaces brokers missing or omitted from the --brokers list
+  rebalance: offloads partitions from brokers beyond the storage threshold
+  optimize-leadership: rebalances all broker leader/follower ratios
+Each stage's changes are printed, followed by the combined changes from the
+current state to the final map. The final map is written as the output; each
+stage's map can optionally be written via --write-stages.
+
+With --min-available-replicas, each stage is checked to ensure that every
+partition retains the minimum number of in-sync replicas in distinct racks
+while the stage is applied. Stages that don't are split into waves that
+reassign the affected partitions one replica at a time.`,
+	Run: pipeline,
+}
+
+func init() {
+	rootCmd.AddCommand(pipelineCmd)
+
+	pipelineCmd.Flags().String("topics", "", "Topics (comma delim. list) to plan by lookup in ZooKeeper")
+	pipelineCmd.Flags().String("brokers", "", "Broker list to scope all partition placements to ('-1' automatically expands to all currently mapped brokers)")
+	pipelineCmd.Flags().Bool("ignore-maintenance", false, "Place partitions on brokers in maintenance (brokers with the registry tag maintenance:true)")
+	pipelineCmd.Flags().String("broker-tags", "", "Registry broker tags (comma delim. list of key:value) that brokers must match to receive partitions; brokers not matching are removed from the --brokers list")
+	pipelineCmd.Flags().String("steps", "rebuild,rebalance,optimize-leadership", "Comma delimited list of steps to perform in order: [rebuild, rebalance, optimize-leadership]")
+	pipelineCmd.Flags().String("placement", "storage", "Partition placement strategy for rebuild steps: [count, storage]")
+	pipelineCmd.Flags().Int("min-rack-ids", 0, "Minimum number of required of unique rack IDs per replica set (0 requires that all are unique)")
+	pipelineCmd.Flags().Float64("partition-size-factor", 1.0, "Factor by which to multiply partition sizes when using storage placement in rebuild steps")
+	pipelineCmd.Flags().Float64("count-weight", 0.00, "Weight (0.00-1.00) of partition counts relative to storage when using storage placement in rebuild steps (0 balances storage only)")
+	pipelineCmd.Flags().Float64("storage-threshold", 0.20, "Percent below the harmonic mean storage free to target for partition offload (0 targets a brokers)")
+	pipelineCmd.Flags().Float64("storage-threshold-gb", 0.00, "Storage free in gigabytes to target for partition offload (those below the specified value); 0 [default] defers target selection to --storage-threshold")
+	pipelineCmd.Flags().Float64("tolerance", 0.0, "Percent distance from the mean storage free to limit storage scheduling (0 performs automatic tolerance selection)")
+	pipelineCmd.Flags().Int("partition-limit", 30, "Limit the number of top partitions by size eligible for relocation per broker")
+	pipelineCmd.Flags().Int("partition-size-threshold", 512, "Size in megabytes where partitions below this value will not be moved in a rebalance")
+	pipelineCmd.Flags().String("topic-constraints", "", "Path to a JSON mapping of topic names to placement constraints (broker_tags, excluded_brokers, replication); takes precedence over registry topic tags")
+	pipelineCmd.Flags().Int("min-available-replicas", 0, "Minimum number of in-sync replicas in distinct racks each partition must retain while stages are applied; stages are split into waves to satisfy this (0 disables the check)")
+	pipelineCmd.Flags().Bool("locality-scoped", false, "Disallow a relocation to traverse rack.id values among brokers")
+	pipelineCmd.Flags().Bool("verbose", false, "Verbose output")
+	pipelineCmd.Flags().String("zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics")
+	pipelineCmd.Flags().Int("metrics-age", 60, "Kafka metrics age tolerance (in minutes)")
+	pipelineCmd.Flags().Bool("include-internal", false, "Include internal topics (e.g. __consumer_offsets) matched by topic regex")
+	pipelineCmd.Flags().Bool("broker-summary", false, "Print a per-broker before/after summary of leaders, partitions and free storage")
+	pipelineCmd.Flags().String("html-report", "", "If defined, write an HTML report of the per-broker before/after summary to a file (e.g. for change requests)")
+	pipelineCmd.Flags().String("out-path", "", "Path to write output map files to")
+	pipelineCmd.Flags().String("out-file", "", "If defined, write a combined map of all topics to a file")
+	pipelineCmd.Flags().Bool("write-stages", false, "Additionally write a combined map for each stage to stage<n>-<step>.json in --out-path (stage<n>-<step>-wave<w>.json for stages split into waves)")
+
+	// Required.
+	pipelineCmd.MarkFlagRequired("brokers")
+	pipelineCmd.MarkFlagRequired("topics")
+}
+
+// getPipelineSteps returns the validated
+// list of steps from --steps.
+func getPipelineSteps(cmd *cobra.Command) ([]string, error) {
+	var steps []string
+
+	for _, s := range strings.Split(cmd.Flag("steps").Value.String(), ",") {
+		s = strings.TrimSpace(s)
+		switch s {
+		case "rebuild", "rebalance", "optimize-leadership":
+			steps = append(steps, s)
+		default:
+			return nil, fmt.Errorf("invalid step '%s'", s)
+		}
+	}
+
+	return steps, nil
+}
+
+// getStageBrokers returns a BrokerMap for the PartitionMap pm, updated with
+// the --brokers list. Broker StorageFree values are estimated from the
+// current broker metadata along with all changes from the original
+// PartitionMap to pm.
+func getStageBrokers(original, pm *kafkazk.PartitionMap, bmm kafkazk.BrokerMetaMap, pmm kafkazk.PartitionMetaMap) (kafkazk.BrokerMap, *kafkazk.BrokerStatus, <-chan string, error) {
+	bm := kafkazk.BrokerMapFromPartitionMap(pm, bmm, false)
+	bs, msgs := bm.Update(scopeBrokers(bm), bmm)
+	bm.SetMaintenance(Config.maintenanceBrokers)
+
+	// Index the current map.
+	current := map[string]map[int][]int{}
+	for _, p := range pm.Partitions {
+		if current[p.Topic] == nil {
+			current[p.Topic] = map[int][]int{}
+		}
+		current[p.Topic][p.Partition] = p.Replicas
+	}
+
+	for _, p := range original.Partitions {
+		size, err := pmm.Size(p)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		before := map[int]struct{}{}
+		for _, id := range p.Replicas {
+			before[id] = struct{}{}
+		}
+
+		after := map[int]struct{}{}
+		for _, id := range current[p.Topic][p.Partition] {
+			after[id] = struct{}{}
+		}
+
+		// Brokers no longer holding the partition
+		// are credited with its size; brokers newly
+		// holding it are debited.
+		for id := range before {
+			if _, kept := after[id]; !kept && bm[id] != nil {
+				bm[id].StorageFree += size
+			}
+		}
+
+		for id := range after {
+			if _, held := before[id]; !held && bm[id] != nil {
+				bm[id].StorageFree -= size
+			}
+		}
+	}
+
+	return bm, bs, msgs, nil
+}
+
+func pipeline(cmd *cobra.Command, _ []string) {
+	steps, err := getPipelineSteps(cmd)
+	if err != nil {
+		fmt.Printf("\n[ERROR] %s\n", err)
+		defaultsAndExit()
+	}
+
+	if p := cmd.Flag("placement").Value.String(); p != "count" && p != "storage" {
+		fmt.Println("\n[ERROR] --placement must be either 'count' or 'storage'")
+		defaultsAndExit()
+	}
+
+	if cw, _ := cmd.Flags().GetFloat64("count-weight"); cw < 0.00 || cw > 1.00 {
+		fmt.Println("\n[ERROR] --count-weight must be between 0.00 and 1.00")
+		defaultsAndExit()
+	}
+
+	minAvail, _ := cmd.Flags().GetInt("min-available-replicas")
+	if minAvail < 0 {
+		fmt.Println("\n[ERROR] --min-available-replicas must be 0 or greater")
+		defaultsAndExit()
+	}
+
+	bootstrap(cmd)
+
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	// Scope brokers to those matching any provided tags.
+	loadBrokerTags(cmd, zk)
+	loadMaintenanceBrokers(cmd, zk)
+
+	// Get broker and partition metadata.
+	checkMetaAge(cmd, zk)
+	brokerMeta := getBrokerMeta(cmd, zk, true)
+	partitionMeta := getPartitionMeta(cmd, zk)
+
+	// Get the current partition map.
+	partitionMapIn, err := kafkazk.PartitionMapFromZK(Config.topics, zk)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	partitionMapIn = filterInternalTopics(cmd, partitionMapIn)
+	originalMap := partitionMapIn.Copy()
+
+	printTopics(partitionMapIn)
+
+	// Get any per-topic placement constraints.
+	loadTopicConstraints(cmd, zk, partitionMapIn)
+
+	// Get the initial broker state.
+	brokersOrig, bs, msgs, err := getStageBrokers(originalMap, partitionMapIn, brokerMeta, partitionMeta)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nBroker change summary:\n")
+	for m := range msgs {
+		fmt.Printf("%s%s\n", indent, m)
+	}
+
+	ensureBrokerMetrics(cmd, brokersOrig, brokerMeta)
+
+	var errs errors
+
+	// Count missing brokers as a warning.
+	if bs.Missing > 0 {
+		errs = append(errs, fmt.Errorf("%d provided brokers not found in ZooKeeper", bs.Missing))
+	}
+
+	// Get the current ISR states for
+	// transient availability checks.
+	var isrStates map[string]kafkazk.TopicStateISR
+	if minAvail > 0 {
+		isrStates, err = originalMap.ISRStates(zk)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	var stages []*kafkazk.PartitionMap
+	var stageNames []string
+	current := partitionMapIn
+	brokers, stageStatus := brokersOrig.Copy(), bs
+
+	for n, step := range steps {
+		fmt.Printf("\n----- Stage %d: %s -----\n", n+1, step)
+
+		var out *kafkazk.PartitionMap
+
+		switch step {
+		case "rebuild":
+			// Account for storage that'll be freed
+			// from brokers marked for replacement.
+			placement := cmd.Flag("placement").Value.String()
+			if placement == "storage" {
+				replaced := func(b *kafkazk.Broker) bool { return b.Replace }
+				if err := brokers.SubStorage(current, partitionMeta, replaced); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			}
+
+			mrrid, _ := cmd.Flags().GetInt("min-rack-ids")
+			psf, _ := cmd.Flags().GetFloat64("partition-size-factor")
+			cw, _ := cmd.Flags().GetFloat64("count-weight")
+			var rebuildErrs []error
+			input := current.Copy()
+			Config.topicConstraints.SetReplication(input)
+			out, rebuildErrs = input.Rebuild(kafkazk.RebuildParams{
+				PMM:              partitionMeta,
+				BM:               brokers,
+				Strategy:         placement,
+				Optimization:     "distribution",
+				PartnSzFactor:    psf,
+				MinUniqueRackIDs: mrrid,
+				CountWeight:      cw,
+				TopicConstraints: Config.topicConstraints,
+			})
+			errs = append(errs, rebuildErrs...)
+		case "rebalance":
+			// Brokers marked for replacement must be
+			// handled by a prior rebuild step.
+			if stageStatus.Replace > 0 || stageStatus.OldMissing > 0 {
+				fmt.Printf("%s[ERROR] rebalance steps only allow broker additions; replaced brokers require a prior rebuild step\n", indent)
+				os.Exit(1)
+			}
+
+			offloadTargets := selectOffloadTargets(cmd, brokers)
+			if len(offloadTargets) == 0 {
+				out = current.Copy()
+				break
+			}
+
+			results := planRebalance(cmd, current, brokers, partitionMeta, offloadTargets)
+			printRebalanceParams(cmd, results, brokers, results[0].Tolerance)
+			printPlannedRelocations(results[0].OffloadTargets, results[0].Relocations, partitionMeta)
+			out = results[0].PartitionMap
+		case "optimize-leadership":
+			out = current.Copy()
+			out.OptimizeLeaderFollower()
+		}
+
+		printMapChanges(current, out)
+
+		// Split the stage into waves that retain
+		// the minimum available replicas.
+		waves := []*kafkazk.PartitionMap{out}
+		if minAvail > 0 {
+			// The ISR states only hold for the first
+			// stage; prior stages are assumed complete.
+			var isr map[string]kafkazk.TopicStateISR
+			if n == 0 {
+				isr = isrStates
+			}
+
+			var violations []kafkazk.AvailabilityViolation
+			waves, violations = current.Waves(out, brokerMeta, isr, minAvail)
+			printStageWaves(current, waves, minAvail)
+
+			for _, v := range violations {
+				errs = append(errs, fmt.Errorf("stage %d: %s", n+1, v))
+			}
+		}
+
+		// Refresh the broker state for the next stage.
+		brokers, stageStatus, _, err = getStageBrokers(originalMap, out, brokerMeta, partitionMeta)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		for w, pm := range waves {
+			name := fmt.Sprintf("stage%d-%s", n+1, step)
+			if len(waves) > 1 {
+				name = fmt.Sprintf("%s-wave%d", name, w+1)
+			}
+
+			stages = append(stages, pm)
+			stageNames = append(stageNames, name)
+		}
+
+		current = out
+	}
+
+	fmt.Printf("\n----- Combined -----\n")
+
+	// Print map change results.
+	printMapChanges(originalMap, current)
+
+	// Print broker assignment statistics.
+	errs = append(errs, printBrokerAssignmentStats(cmd, originalMap, current, brokersOrig, brokers)...)
+
+	// Print and write the broker summary if configured.
+	summarizeBrokers(cmd, originalMap, current, brokersOrig, brokers)
+
+	// Check changed partitions and any
+	// included internal topics.
+	errs = append(errs, current.CheckInternalTopics(brokers)...)
+
+	// Check topic placement constraints.
+	errs = append(errs, Config.topicConstraints.Check(current)...)
+
+	// The combined map applies all changes at once,
+	// which may not retain the minimum available
+	// replicas where the stages do.
+	if minAvail > 0 {
+		if waves, _ := originalMap.Waves(current, brokerMeta, isrStates, minAvail); len(waves) > 1 {
+			errs = append(errs, fmt.Errorf("combined map doesn't retain %d available replicas for all partitions; apply the stage maps (--write-stages) in order", minAvail))
+		}
+	}
+
+	// Print error/warnings.
+	handleOverridableErrs(cmd, errs)
+
+	// Write stage maps.
+	if ws, _ := cmd.Flags().GetBool("write-stages"); ws {
+		op := cmd.Flag("out-path").Value.String()
+		fmt.Println("\nStage partition maps:")
+		for n, pm := range stages {
+			path := op + stageNames[n]
+			if err := kafkazk.WriteMap(pm, path); err != nil {
+				fmt.Printf("%s%s\n", indent, err)
+			} else {
+				fmt.Printf("%s%s.json\n", indent, path)
+			}
+		}
+	}
+
+	// Ignore no-ops.
+	_, partitionMapOut := skipReassignmentNoOps(originalMap, current)
+
+	writeMaps(cmd, partitionMapOut)
+
+	// Record the plan if configured.
+	recordPlan(cmd, zk, originalMap, current)
+}
+
+// printStageWaves prints the number of partitions reassigned in each wave
+// of a stage that's been split to retain the minimum available replicas.
+func printStageWaves(pm *kafkazk.PartitionMap, waves []*kafkazk.PartitionMap, min int) {
+	if len(waves) < 2 {
+		return
+	}
+
+	fmt.Printf("\nStage waves (retaining %d in-sync, rack-diverse replicas):\n", min)
+
+	prev := pm
+	for w, wave := range waves {
+		_, changes := skipReassignmentNoOps(prev, wave)
+		fmt.Printf("%swave %d: %d partitions reassigned\n", indent, w+1, len(changes.Partitions))
+		prev = wave
+	}
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+func TestGetPipelineSteps(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("steps", "", "")
+
+	cmd.Flags().Set("steps", "rebuild, rebalance,optimize-leadership")
+	steps, err := getPipelineSteps(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(steps) != 3 || steps[1] != "rebalance" {
+		t.Errorf("Unexpected steps: %v", steps)
+	}
+
+	cmd.Flags().Set("steps", "rebuild,shuffle")
+	if _, err := getPipelineSteps(cmd); err == nil {
+		t.Error("Expected error for invalid step")
+	}
+}
+
+func TestGetStageBrokers(t *testing.T) {
+	pm1, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test","partition":0,"replicas":[1001,1002]},
+    {"topic":"test","partition":1,"replicas":[1002,1001]}]}`)
+
+	// p0 moves from 1002 to 1003.
+	pm2, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test","partition":0,"replicas":[1001,1003]},
+    {"topic":"test","partition":1,"replicas":[1002,1001]}]}`)
+
+	bmm := kafkazk.BrokerMetaMap{
+		1001: &kafkazk.BrokerMeta{StorageFree: 1000},
+		1002: &kafkazk.BrokerMeta{StorageFree: 1000},
+		1003: &kafkazk.BrokerMeta{StorageFree: 1000},
+	}
+
+	pmm := kafkazk.NewPartitionMetaMap()
+	pmm["test"] = map[int]*kafkazk.PartitionMeta{
+		0: &kafkazk.PartitionMeta{Size: 100},
+		1: &kafkazk.PartitionMeta{Size: 200},
+	}
+
+	Config.brokers = []int{1001, 1002, 1003}
+
+	bm, _, _, err := getStageBrokers(pm1, pm2, bmm, pmm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[int]float64{1001: 1000, 1002: 1100, 1003: 900}
+	for id, sf := range expected {
+		if bm[id].StorageFree != sf {
+			t.Errorf("Expected broker %d storage free %.0f, got %.0f", id, sf, bm[id].StorageFree)
+		}
+	}
+}
@@ -3,10 +3,10 @@ package commands
 import (
 	"fmt"
 	"os"
-	"sort"
-	"sync"
+	"time"
 
 	"github.com/honeycombio/kafka-kit/kafkazk"
+	"github.com/honeycombio/kafka-kit/planner"
 
 	"github.com/spf13/cobra"
 )
@@ -18,20 +18,6 @@ var rebalanceCmd = &cobra.Command{
 	Run:   rebalance,
 }
 
-// Rebalance may be configured to run a series
-// of rebalance plans. A rebalanceResults holds
-// any relevant output along with metadata that
-// hints at the quality of the output, such as
-// the resulting storage utilization range.
-type rebalanceResults struct {
-	storageRange float64
-	stdDev       float64
-	tolerance    float64
-	partitionMap *kafkazk.PartitionMap
-	relocations  map[int][]relocation
-	brokers      kafkazk.BrokerMap
-}
-
 func init() {
 	rootCmd.AddCommand(rebalanceCmd)
 
@@ -44,11 +30,27 @@ func init() {
 	rebalanceCmd.Flags().Float64("tolerance", 0.0, "Percent distance from the mean storage free to limit storage scheduling (0 performs automatic tolerance selection)")
 	rebalanceCmd.Flags().Int("partition-limit", 30, "Limit the number of top partitions by size eligible for relocation per broker")
 	rebalanceCmd.Flags().Int("partition-size-threshold", 512, "Size in megabytes where partitions below this value will not be moved in a rebalance")
+	rebalanceCmd.Flags().Bool("ignore-maintenance", false, "Relocate partitions to brokers in maintenance (brokers with the registry tag maintenance:true)")
+	rebalanceCmd.Flags().String("topic-constraints", "", "Path to a JSON mapping of topic names to placement constraints (broker_tags, excluded_brokers, replication); takes precedence over registry topic tags")
+	rebalanceCmd.Flags().String("leader-pins", "", "Path to a JSON mapping of topic names and partition numbers to the broker or rack their leader is pinned to, e.g. {\"topic\": {\"0\": {\"broker\": 1001}, \"1\": {\"rack\": \"a\"}}}")
 	rebalanceCmd.Flags().Bool("locality-scoped", false, "Disallow a relocation to traverse rack.id values among brokers")
 	rebalanceCmd.Flags().Bool("verbose", false, "Verbose output")
 	rebalanceCmd.Flags().String("zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics")
 	rebalanceCmd.Flags().Int("metrics-age", 60, "Kafka metrics age tolerance (in minutes)")
 	rebalanceCmd.Flags().Bool("optimize-leadership", false, "Rebalance all broker leader/follower ratios")
+	rebalanceCmd.Flags().String("objective-weights", "", "Comma delimited list of objective=weight pairs (objectives: [storage, leadership, movement, rack-diversity, cross-az]); the rebalance result with the lowest weighted cost is selected rather than that with the lowest storage range")
+	rebalanceCmd.Flags().String("policy-file", "", "Path to a JSON placement policy providing objective weights, e.g. {\"objective_weights\": {\"storage\": 1}}; --objective-weights take precedence")
+	rebalanceCmd.Flags().Float64("max-utilization", 0.00, "Maximum estimated peak storage utilization (0.00-1.00) for brokers receiving partitions (0 disables the check)")
+	rebalanceCmd.Flags().String("min-isr-check", "warn", "Handling of plans where changed partitions could fall below min.insync.replicas: [warn, block, ignore]")
+	rebalanceCmd.Flags().Int("default-min-isr", 1, "The min.insync.replicas value assumed for topics without an override (the broker default)")
+	rebalanceCmd.Flags().Bool("include-internal", false, "Include internal topics (e.g. __consumer_offsets) matched by topic regex")
+	rebalanceCmd.Flags().Bool("broker-summary", false, "Print a per-broker before/after summary of leaders, partitions and free storage")
+	rebalanceCmd.Flags().String("html-report", "", "If defined, write an HTML report of the per-broker before/after summary to a file (e.g. for change requests)")
+	rebalanceCmd.Flags().String("output-template", "", "Path to a Go text/template used to render the plan (e.g. for runbooks or tickets)")
+	rebalanceCmd.Flags().String("output-template-file", "", "If defined, write the rendered --output-template to a file rather than stdout")
+	rebalanceCmd.Flags().String("placement-hooks", "", "Comma delimited list of placement hooks applied in order to the output map: 'exec:<command> [args]' runs an external command receiving the plan as JSON on stdin and writing a partition map to stdout, other values name hooks registered with the planner")
+	rebalanceCmd.Flags().Int("placement-hook-timeout", 60, "Timeout in seconds for each exec placement hook (0 disables the timeout)")
+	rebalanceCmd.Flags().String("out-of-sync", "warn", "Handling of partitions with replicas not in the ISR or on offline brokers: [warn, exclude]")
 
 	// Required.
 	rebalanceCmd.MarkFlagRequired("brokers")
@@ -56,8 +58,34 @@ func init() {
 }
 
 func rebalance(cmd *cobra.Command, _ []string) {
+	start := time.Now()
+
+	if oos := cmd.Flag("out-of-sync").Value.String(); oos != "warn" && oos != "exclude" {
+		fmt.Println("\n[ERROR] --out-of-sync must be either 'warn' or 'exclude'")
+		defaultsAndExit()
+	}
+
+	if mu, _ := cmd.Flags().GetFloat64("max-utilization"); mu < 0.00 || mu > 1.00 {
+		fmt.Println("\n[ERROR] --max-utilization must be between 0.00 and 1.00")
+		defaultsAndExit()
+	}
+
+	if mic := cmd.Flag("min-isr-check").Value.String(); mic != "warn" && mic != "block" && mic != "ignore" {
+		fmt.Println("\n[ERROR] --min-isr-check must be one of 'warn', 'block' or 'ignore'")
+		defaultsAndExit()
+	}
+
 	bootstrap(cmd)
 
+	// Get any objective weights.
+	weights, optimize := getObjectiveWeights(cmd)
+
+	// Parse the output template, if provided.
+	tmpl := getOutputTemplate(cmd)
+
+	// Get any placement hooks.
+	hooks, hookSpecs := getPlacementHooks(cmd)
+
 	// ZooKeeper init.
 	zk, err := initZooKeeper(cmd)
 	if err != nil {
@@ -67,6 +95,8 @@ func rebalance(cmd *cobra.Command, _ []string) {
 
 	defer zk.Close()
 
+	loadMaintenanceBrokers(cmd, zk)
+
 	// Get broker and partition metadata.
 	checkMetaAge(cmd, zk)
 	brokerMeta := getBrokerMeta(cmd, zk, true)
@@ -79,9 +109,20 @@ func rebalance(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	partitionMapIn = filterInternalTopics(cmd, partitionMapIn)
+
 	// Print topics matched to input params.
 	printTopics(partitionMapIn)
 
+	// Get any per-topic placement constraints.
+	loadTopicConstraints(cmd, zk, partitionMapIn)
+
+	// Get any per-partition leader pins.
+	loadLeaderPins(cmd, partitionMapIn)
+
+	// Check for partitions with out of sync replicas.
+	partitionMapIn, oosErrs := handleOutOfSync(cmd, zk, partitionMapIn, brokerMeta)
+
 	// Get a broker map.
 	brokersIn := kafkazk.BrokerMapFromPartitionMap(partitionMapIn, brokerMeta, false)
 
@@ -89,125 +130,40 @@ func rebalance(cmd *cobra.Command, _ []string) {
 	// broker IDs targeted for partition offloading.
 	offloadTargets := validateBrokersForRebalance(cmd, brokersIn, brokerMeta)
 
-	// Sort offloadTargets by storage free ascending.
-	sort.Sort(offloadTargetsBySize{t: offloadTargets, bm: brokersIn})
-
-	partitionLimit, _ := cmd.Flags().GetInt("partition-limit")
-	partitionSizeThreshold, _ := cmd.Flags().GetInt("partition-size-threshold")
+	// Compute rebalance results for all
+	// tolerance values, best first.
+	resultsByRange := planRebalance(cmd, partitionMapIn, brokersIn, partitionMeta, offloadTargets)
 
-	otm := map[int]struct{}{}
-	for _, id := range offloadTargets {
-		otm[id] = struct{}{}
-	}
+	// Chose the results with the lowest range, or
+	// the lowest weighted cost if configured.
+	m := resultsByRange[0]
+	var objectives []planner.Objectives
+	var best int
 
-	results := make(chan rebalanceResults, 100)
-	wg := &sync.WaitGroup{}
-
-	// Compute a rebalanceResults output for all tolerance
-	// values 0.01..0.99 in parallel.
-	for i := 0.01; i < 0.99; i += 0.01 {
-		// Whether we're using a fixed tolerance
-		// (non 0.00) set via flag or an iterative value.
-		tolFlag, _ := cmd.Flags().GetFloat64("tolerance")
-		var tol float64
-
-		if tolFlag == 0.00 {
-			tol = i
-		} else {
-			tol = tolFlag
+	if optimize {
+		outputs := make([]*kafkazk.PartitionMap, len(resultsByRange))
+		for i, r := range resultsByRange {
+			outputs[i] = r.PartitionMap
 		}
 
-		wg.Add(1)
-
-		go func() {
-			defer wg.Done()
-
-			partitionMap := partitionMapIn.Copy()
-
-			// Bundle planRelocationsForBrokerParams.
-			params := planRelocationsForBrokerParams{
-				relos:                  map[int][]relocation{},
-				mappings:               partitionMap.Mappings(),
-				brokers:                brokersIn.Copy(),
-				partitionMeta:          partitionMeta,
-				plan:                   relocationPlan{},
-				topPartitionsLimit:     partitionLimit,
-				partitionSizeThreshold: partitionSizeThreshold,
-				offloadTargetsMap:      otm,
-				tolerance:              tol,
-			}
-
-			// Iterate over offload targets, planning
-			// at most one relocation per iteration.
-			// Continue this loop until no more relocations
-			// can be planned.
-			for exhaustedCount := 0; exhaustedCount < len(offloadTargets); {
-				params.pass++
-				for _, sourceID := range offloadTargets {
-					// Update the source broker ID
-					params.sourceID = sourceID
-
-					relos := planRelocationsForBroker(cmd, params)
-
-					// If no relocations could be planned,
-					// increment the exhaustion counter.
-					if relos == 0 {
-						exhaustedCount++
-					}
-				}
-			}
-
-			// Update the partition map with the relocation plan.
-			applyRelocationPlan(cmd, partitionMap, params.plan)
-
-			// Insert the rebalanceResults.
-			results <- rebalanceResults{
-				storageRange: params.brokers.StorageRange(),
-				stdDev:       params.brokers.StorageStdDev(),
-				tolerance:    tol,
-				partitionMap: partitionMap,
-				relocations:  params.relos,
-				brokers:      params.brokers,
-			}
-
-		}()
-
-		// Break early if we're using a fixed tolerance value.
-		if tolFlag != 0.00 {
-			break
-		}
+		best, objectives = planner.SelectBest(partitionMapIn, outputs, brokerMeta, partitionMeta, weights)
+		m = resultsByRange[best]
 	}
 
-	wg.Wait()
-	close(results)
-
-	// Merge all results into a slice.
-	resultsByRange := []rebalanceResults{}
-	for r := range results {
-		resultsByRange = append(resultsByRange, r)
-	}
-
-	// Sort the rebalance results by range ascending.
-	sort.Slice(resultsByRange, func(i, j int) bool {
-		switch {
-		case resultsByRange[i].storageRange < resultsByRange[j].storageRange:
-			return true
-		case resultsByRange[i].storageRange > resultsByRange[j].storageRange:
-			return false
-		}
-
-		return resultsByRange[i].stdDev < resultsByRange[j].stdDev
-	})
-
-	// Chose the results with the lowest range.
-	m := resultsByRange[0]
-	partitionMapOut, brokersOut, relos := m.partitionMap, m.brokers, m.relocations
+	partitionMapOut, brokersOut, relos := m.PartitionMap, m.Brokers, m.Relocations
 
 	// Print parameters used for rebalance decisions.
-	printRebalanceParams(cmd, resultsByRange, brokersIn, m.tolerance)
+	printRebalanceParams(cmd, resultsByRange, brokersIn, m.Tolerance)
+
+	if optimize {
+		printObjectives(objectives[best], weights)
+	}
 
 	// Print planned relocations.
-	printPlannedRelocations(offloadTargets, relos, partitionMeta)
+	printPlannedRelocations(m.OffloadTargets, relos, partitionMeta)
+
+	// Apply any placement hooks.
+	partitionMapOut = applyPlacementHooks(cmd, hooks, hookSpecs, partitionMapIn, partitionMapOut, brokersOut, brokerMeta, partitionMeta)
 
 	// Print map change results.
 	printMapChanges(partitionMapIn, partitionMapOut)
@@ -215,6 +171,30 @@ func rebalance(cmd *cobra.Command, _ []string) {
 	// Print broker assignment statistics.
 	errs := printBrokerAssignmentStats(cmd, partitionMapIn, partitionMapOut, brokersIn, brokersOut)
 
+	// Print and write the broker summary if configured.
+	summarizeBrokers(cmd, partitionMapIn, partitionMapOut, brokersIn, brokersOut)
+	errs = append(errs, oosErrs...)
+
+	// Validate any included internal topics.
+	errs = append(errs, partitionMapOut.CheckInternalTopics(brokersOut)...)
+
+	// Check topic placement constraints.
+	errs = append(errs, Config.topicConstraints.Check(partitionMapOut)...)
+
+	// Check leader pins.
+	errs = append(errs, Config.leaderPins.Check(partitionMapOut, brokersOut)...)
+
+	// Check estimated peak storage utilization.
+	errs = append(errs, checkStorageHeadroom(cmd, partitionMapIn, partitionMapOut, partitionMeta, brokerMeta)...)
+
+	// Check changed partitions against min.insync.replicas.
+	errs = append(errs, checkMinISR(cmd, zk, partitionMapIn, partitionMapOut)...)
+
+	// Summarize the plan prior to
+	// pruning no-op reassignments.
+	stats := getPlanStats(cmd, partitionMapIn, partitionMapOut, partitionMeta, brokersOut, start)
+	output := getPlanOutput(partitionMapIn, partitionMapOut, brokersIn, brokersOut, errs, stats)
+
 	// Handle errors that are possible
 	// to be overridden by the user (aka
 	// 'WARN' in topicmappr console output).
@@ -226,4 +206,44 @@ func rebalance(cmd *cobra.Command, _ []string) {
 
 	// Write maps.
 	writeMaps(cmd, partitionMapOut)
+
+	// Record the plan if configured.
+	recordPlan(cmd, zk, partitionMapIn, partitionMapOut)
+
+	// Emit plan summary metrics if configured.
+	emitPlanStats(cmd, stats)
+
+	// Render the output template if configured.
+	renderPlanOutput(cmd, tmpl, output)
+}
+
+// planRebalance takes a PartitionMap, BrokerMap, PartitionMetaMap and a list
+// of broker IDs targeted for partition offloading and returns the
+// planner.Rebalance results, configured according to the command flags.
+func planRebalance(cmd *cobra.Command, partitionMapIn *kafkazk.PartitionMap, brokersIn kafkazk.BrokerMap, partitionMeta kafkazk.PartitionMetaMap, offloadTargets []int) []planner.RebalanceResult {
+	partitionLimit, _ := cmd.Flags().GetInt("partition-limit")
+	partitionSizeThreshold, _ := cmd.Flags().GetInt("partition-size-threshold")
+	tolerance, _ := cmd.Flags().GetFloat64("tolerance")
+	localityScoped, _ := cmd.Flags().GetBool("locality-scoped")
+	optimizeLeadership, _ := cmd.Flags().GetBool("optimize-leadership")
+
+	params := planner.RebalanceParams{
+		PartitionMap:           partitionMapIn,
+		Brokers:                brokersIn,
+		PartitionMeta:          partitionMeta,
+		OffloadTargets:         offloadTargets,
+		Tolerance:              tolerance,
+		PartitionLimit:         partitionLimit,
+		PartitionSizeThreshold: partitionSizeThreshold,
+		LocalityScoped:         localityScoped,
+		OptimizeLeadership:     optimizeLeadership,
+		TopicConstraints:       Config.topicConstraints,
+		LeaderPins:             Config.leaderPins,
+	}
+
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+		params.Log = os.Stdout
+	}
+
+	return planner.Rebalance(params)
 }
@@ -3,8 +3,10 @@ package commands
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/honeycombio/kafka-kit/kafkazk"
+	"github.com/honeycombio/kafka-kit/planner"
 
 	"github.com/spf13/cobra"
 )
@@ -36,17 +38,40 @@ func init() {
 	rebuildCmd.Flags().Int("min-rack-ids", 0, "Minimum number of required of unique rack IDs per replica set (0 requires that all are unique)")
 	rebuildCmd.Flags().String("optimize", "distribution", "Optimization priority for the storage placement strategy: [distribution, storage]")
 	rebuildCmd.Flags().Float64("partition-size-factor", 1.0, "Factor by which to multiply partition sizes when using storage placement")
+	rebuildCmd.Flags().Float64("count-weight", 0.00, "Weight (0.00-1.00) of partition counts relative to storage when using storage placement (0 balances storage only)")
 	rebuildCmd.Flags().String("brokers", "", "Broker list to scope all partition placements to ('-1' automatically expands to all currently mapped brokers)")
+	rebuildCmd.Flags().Bool("ignore-maintenance", false, "Place partitions on brokers in maintenance (brokers with the registry tag maintenance:true)")
+	rebuildCmd.Flags().String("broker-tags", "", "Registry broker tags (comma delim. list of key:value) that brokers must match to receive partitions; brokers not matching are removed from the --brokers list")
 	rebuildCmd.Flags().String("zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics (when using storage placement)")
 	rebuildCmd.Flags().Int("metrics-age", 60, "Kafka metrics age tolerance (in minutes) (when using storage placement)")
 	rebuildCmd.Flags().Bool("skip-no-ops", false, "Skip no-op partition assigments")
 	rebuildCmd.Flags().Bool("optimize-leadership", false, "Rebalance all broker leader/follower ratios")
+	rebuildCmd.Flags().Float64("max-utilization", 0.00, "Maximum estimated peak storage utilization (0.00-1.00) for brokers receiving partitions (0 disables the check)")
+	rebuildCmd.Flags().Int("observers", 0, "Number of trailing replicas in each replica set that are observers (never placed as leaders)")
+	rebuildCmd.Flags().String("observer-rack", "", "Rack ID to place observers in; all other replicas are placed outside of it (requires --observers)")
+	rebuildCmd.Flags().String("min-isr-check", "warn", "Handling of plans where changed partitions could fall below min.insync.replicas: [warn, block, ignore]")
+	rebuildCmd.Flags().Int("default-min-isr", 1, "The min.insync.replicas value assumed for topics without an override (the broker default)")
+	rebuildCmd.Flags().String("topic-constraints", "", "Path to a JSON mapping of topic names to placement constraints (broker_tags, excluded_brokers, replication); takes precedence over registry topic tags")
+	rebuildCmd.Flags().String("leader-pins", "", "Path to a JSON mapping of topic names and partition numbers to the broker or rack their leader is pinned to, e.g. {\"topic\": {\"0\": {\"broker\": 1001}, \"1\": {\"rack\": \"a\"}}}")
+	rebuildCmd.Flags().String("consumer-racks", "", "Path to a JSON mapping of topic names to the rack of their dominant consumers; at least one replica of each partition is placed in that rack")
+	rebuildCmd.Flags().Bool("include-internal", false, "Include internal topics (e.g. __consumer_offsets) matched by topic regex")
+	rebuildCmd.Flags().Bool("broker-summary", false, "Print a per-broker before/after summary of leaders, partitions and free storage")
+	rebuildCmd.Flags().String("html-report", "", "If defined, write an HTML report of the per-broker before/after summary to a file (e.g. for change requests)")
+	rebuildCmd.Flags().String("output-template", "", "Path to a Go text/template used to render the plan (e.g. for runbooks or tickets)")
+	rebuildCmd.Flags().String("output-template-file", "", "If defined, write the rendered --output-template to a file rather than stdout")
+	rebuildCmd.Flags().String("objective-weights", "", "Comma delimited list of objective=weight pairs (objectives: [storage, leadership, movement, rack-diversity, cross-az]); all placement strategies are evaluated and the plan with the lowest weighted cost is selected (overrides --placement and --optimize-leadership)")
+	rebuildCmd.Flags().String("policy-file", "", "Path to a JSON placement policy providing objective weights, e.g. {\"objective_weights\": {\"storage\": 1}}; --objective-weights take precedence")
+	rebuildCmd.Flags().String("placement-hooks", "", "Comma delimited list of placement hooks applied in order to the output map: 'exec:<command> [args]' runs an external command receiving the plan as JSON on stdin and writing a partition map to stdout, other values name hooks registered with the planner")
+	rebuildCmd.Flags().Int("placement-hook-timeout", 60, "Timeout in seconds for each exec placement hook (0 disables the timeout)")
+	rebuildCmd.Flags().String("out-of-sync", "warn", "Handling of partitions with replicas not in the ISR or on offline brokers: [warn, exclude]")
 
 	// Required.
 	rebuildCmd.MarkFlagRequired("brokers")
 }
 
 func rebuild(cmd *cobra.Command, _ []string) {
+	start := time.Now()
+
 	// Sanity check params.
 	t, _ := cmd.Flags().GetString("topics")
 	ms, _ := cmd.Flags().GetString("map-string")
@@ -55,6 +80,16 @@ func rebuild(cmd *cobra.Command, _ []string) {
 	fr, _ := cmd.Flags().GetBool("force-rebuild")
 	sa, _ := cmd.Flags().GetBool("sub-affinity")
 	m, _ := cmd.Flags().GetBool("use-meta")
+	oos := cmd.Flag("out-of-sync").Value.String()
+	mu, _ := cmd.Flags().GetFloat64("max-utilization")
+	cw, _ := cmd.Flags().GetFloat64("count-weight")
+	mic := cmd.Flag("min-isr-check").Value.String()
+	obs, _ := cmd.Flags().GetInt("observers")
+	obsRack := cmd.Flag("observer-rack").Value.String()
+	cr := cmd.Flag("consumer-racks").Value.String()
+	bt := cmd.Flag("broker-tags").Value.String()
+	tcf := cmd.Flag("topic-constraints").Value.String()
+	lp := cmd.Flag("leader-pins").Value.String()
 
 	switch {
 	case ms == "" && t == "":
@@ -66,18 +101,60 @@ func rebuild(cmd *cobra.Command, _ []string) {
 	case o != "distribution" && o != "storage":
 		fmt.Println("\n[ERROR] --optimize must be either 'distribution' or 'storage'")
 		defaultsAndExit()
+	case oos != "warn" && oos != "exclude":
+		fmt.Println("\n[ERROR] --out-of-sync must be either 'warn' or 'exclude'")
+		defaultsAndExit()
+	case mic != "warn" && mic != "block" && mic != "ignore":
+		fmt.Println("\n[ERROR] --min-isr-check must be one of 'warn', 'block' or 'ignore'")
+		defaultsAndExit()
 	case !m && p == "storage":
 		fmt.Println("\n[ERROR] --placement=storage requires --use-meta=true")
 		defaultsAndExit()
+	case cw < 0.00 || cw > 1.00:
+		fmt.Println("\n[ERROR] --count-weight must be between 0.00 and 1.00")
+		defaultsAndExit()
+	case cw > 0.00 && p != "storage":
+		fmt.Println("\n[ERROR] --count-weight requires --placement=storage")
+		defaultsAndExit()
+	case mu < 0.00 || mu > 1.00:
+		fmt.Println("\n[ERROR] --max-utilization must be between 0.00 and 1.00")
+		defaultsAndExit()
+	case !m && mu > 0.00:
+		fmt.Println("\n[ERROR] --max-utilization requires --use-meta=true")
+		defaultsAndExit()
+	case obs < 0:
+		fmt.Println("\n[ERROR] --observers must be 0 or greater")
+		defaultsAndExit()
+	case obsRack != "" && obs == 0:
+		fmt.Println("\n[ERROR] --observer-rack requires --observers")
+		defaultsAndExit()
+	case !m && obsRack != "":
+		fmt.Println("\n[ERROR] --observer-rack requires --use-meta=true")
+		defaultsAndExit()
+	case !m && cr != "":
+		fmt.Println("\n[ERROR] --consumer-racks requires --use-meta=true")
+		defaultsAndExit()
+	case !m && lp != "":
+		fmt.Println("\n[ERROR] --leader-pins requires --use-meta=true")
+		defaultsAndExit()
 	case fr && sa:
 		fmt.Println("\n[INFO] --force-rebuild disables --sub-affinity")
 	}
 
 	bootstrap(cmd)
 
+	// Get any objective weights.
+	weights, optimize := getObjectiveWeights(cmd)
+
+	// Parse the output template, if provided.
+	tmpl := getOutputTemplate(cmd)
+
+	// Get any placement hooks.
+	hooks, hookSpecs := getPlacementHooks(cmd)
+
 	// ZooKeeper init.
 	var zk kafkazk.Handler
-	if m || len(Config.topics) > 0 || p == "storage" {
+	if m || len(Config.topics) > 0 || p == "storage" || bt != "" || tcf != "" {
 		var err error
 		zk, err = initZooKeeper(cmd)
 		if err != nil {
@@ -87,23 +164,28 @@ func rebuild(cmd *cobra.Command, _ []string) {
 		defer zk.Close()
 	}
 
+	// Scope brokers to those matching any provided tags.
+	loadBrokerTags(cmd, zk)
+	loadMaintenanceBrokers(cmd, zk)
+
 	// General flow:
 	// 1) A PartitionMap is formed (either unmarshaled from the literal
 	//   map input via --rebuild-map or generated from ZooKeeper Metadata
 	//   for topics matching --topics).
-	// 2) A BrokerMap is formed from brokers found in the PartitionMap
-	//   along with any new brokers provided via the --brokers param.
-	// 3) The PartitionMap and BrokerMap are fed to a rebuild
-	//   function. Missing brokers, brokers marked for replacement,
-	//   and all other placements are performed, returning a new
-	//   PartitionMap.
-	// 4) Differences between the original and new PartitionMap
+	// 2) The PartitionMap, metadata and the --brokers list are fed to
+	//   the planner. A BrokerMap is formed from brokers found in the
+	//   PartitionMap along with any new brokers provided. Missing
+	//   brokers, brokers marked for replacement, and all other
+	//   placements are performed, returning a new PartitionMap.
+	// 3) Differences between the original and new PartitionMap
 	//   are detected and reported.
-	// 5) The new PartitionMap is split by topic. Map(s) are written.
+	// 4) The new PartitionMap is split by topic. Map(s) are written.
 
-	// Fetch broker metadata.
+	// Fetch broker metadata. Metrics are required by both
+	// the storage placement strategy and the peak storage
+	// utilization check.
 	var withMetrics bool
-	if cmd.Flag("placement").Value.String() == "storage" {
+	if p == "storage" || mu > 0.00 || (optimize && m && weights.Storage > 0) {
 		checkMetaAge(cmd, zk)
 		withMetrics = true
 	}
@@ -115,57 +197,86 @@ func rebuild(cmd *cobra.Command, _ []string) {
 
 	// Fetch partition metadata.
 	var partitionMeta kafkazk.PartitionMetaMap
-	if cmd.Flag("placement").Value.String() == "storage" {
+	if withMetrics {
 		partitionMeta = getPartitionMeta(cmd, zk)
 	}
 
 	// Build a partition map either from literal map text input or by fetching the
-	// map data from ZooKeeper. Store a copy of the original.
+	// map data from ZooKeeper.
 	partitionMapIn := getPartitionMap(cmd, zk)
-	originalMap := partitionMapIn.Copy()
 
 	// Get a list of affected topics.
 	printTopics(partitionMapIn)
 
-	brokers, bs := getBrokers(cmd, partitionMapIn, brokerMeta)
-	brokersOrig := brokers.Copy()
-
-	if bs.Changes() {
-		fmt.Printf("%s-\n", indent)
+	// Get any per-topic placement constraints.
+	loadTopicConstraints(cmd, zk, partitionMapIn)
+
+	// Get any per-partition leader pins.
+	loadLeaderPins(cmd, partitionMapIn)
+
+	r, _ := cmd.Flags().GetInt("replication")
+	psf, _ := cmd.Flags().GetFloat64("partition-size-factor")
+	mrrid, _ := cmd.Flags().GetInt("min-rack-ids")
+	ol, _ := cmd.Flags().GetBool("optimize-leadership")
+	def, _ := cmd.Flags().GetInt("default-min-isr")
+
+	params := planner.RebuildParams{
+		PartitionMap:         partitionMapIn,
+		BrokerMeta:           brokerMeta,
+		PartitionMeta:        partitionMeta,
+		Brokers:              scopeBrokers(kafkazk.BrokerMapFromPartitionMap(partitionMapIn, brokerMeta, fr)),
+		Strategy:             p,
+		Optimization:         o,
+		PartitionSizeFactor:  psf,
+		CountWeight:          cw,
+		MinUniqueRackIDs:     mrrid,
+		Replication:          r,
+		ForceRebuild:         fr,
+		SubstitutionAffinity: sa,
+		OptimizeLeadership:   ol,
+		Observers:            obs,
+		ObserverRack:         getRackGroups(cmd).RackDomain(obsRack),
+		ConsumerRacks:        getConsumerRacks(cmd),
+		TopicConstraints:     Config.topicConstraints,
+		LeaderPins:           Config.leaderPins,
+		Maintenance:          Config.maintenanceBrokers,
+		Hooks:                hooks,
+		ZK:                   zk,
+		OutOfSync:            oos,
+		MinISRCheck:          mic,
+		DefaultMinISR:        def,
 	}
 
-	// Check if any referenced brokers are marked as having
-	// missing/partial metrics data.
-	if m, _ := cmd.Flags().GetBool("use-meta"); m {
-		ensureBrokerMetrics(cmd, brokers, brokerMeta)
+	if optimize {
+		params.ObjectiveWeights = &weights
 	}
 
-	// Create substitution affinities.
-	affinities := getSubAffinities(cmd, brokers, brokersOrig, partitionMapIn)
-
-	if affinities != nil {
-		fmt.Printf("%s-\n", indent)
+	// Build a new map using the provided list of brokers.
+	// This is OK to run even when a no-op is intended.
+	plan, err := planner.Rebuild(params)
+	if err != nil {
+		handleRebuildErr(err, oos, hookSpecs)
 	}
 
-	// Print changes, actions.
-	printChangesActions(cmd, bs)
+	originalMap, partitionMapOut := plan.Input, plan.Output
+	brokersOrig, brokers := plan.BrokersBefore, plan.BrokersAfter
 
-	// Apply any replication factor settings.
-	updateReplicationFactor(cmd, partitionMapIn)
+	printOutOfSync(plan.OutOfSync, oos == "exclude")
 
-	// Build a new map using the provided list of brokers.
-	// This is OK to run even when a no-op is intended.
-	partitionMapOut, errs := buildMap(cmd, partitionMapIn, partitionMeta, brokers, affinities)
+	// Print broker changes, substitution
+	// affinities and actions.
+	printBrokerChanges(plan)
+	printSubAffinities(plan)
+	printChangesActions(cmd, plan.BrokerStatus)
 
-	// Optimize leaders.
-	if t, _ := cmd.Flags().GetBool("optimize-leadership"); t {
-		partitionMapOut.OptimizeLeaderFollower()
-	}
+	// Print the evaluated candidates
+	// if objective weights were set.
+	printCandidates(plan.Candidates, weights)
 
-	// Count missing brokers as a warning.
-	if bs.Missing > 0 {
-		errs = append(errs, fmt.Errorf("%d provided brokers not found in ZooKeeper", bs.Missing))
-	}
+	// Print the changes made by placement hooks.
+	printHookChanges(plan.HookChanges, hookSpecs)
+
+	errs := errors(plan.Warnings)
 
 	// Print map change results.
 	printMapChanges(originalMap, partitionMapOut)
@@ -173,6 +284,20 @@ func rebuild(cmd *cobra.Command, _ []string) {
 	// Print broker assignment statistics.
 	printBrokerAssignmentStats(cmd, originalMap, partitionMapOut, brokersOrig, brokers)
 
+	// Print and write the broker summary if configured.
+	summarizeBrokers(cmd, originalMap, partitionMapOut, brokersOrig, brokers)
+
+	// Check estimated peak storage utilization.
+	errs = append(errs, checkStorageHeadroom(cmd, originalMap, partitionMapOut, partitionMeta, brokerMeta)...)
+
+	// Print any min.insync.replicas violations.
+	printMinISRViolations(plan.MinISRViolations)
+
+	// Summarize the plan prior to
+	// pruning no-op reassignments.
+	stats := getPlanStats(cmd, originalMap, partitionMapOut, partitionMeta, brokers, start)
+	output := getPlanOutput(originalMap, partitionMapOut, brokersOrig, brokers, errs, stats)
+
 	// Print error/warnings.
 	handleOverridableErrs(cmd, errs)
 
@@ -182,4 +307,13 @@ func rebuild(cmd *cobra.Command, _ []string) {
 	}
 
 	writeMaps(cmd, partitionMapOut)
+
+	// Record the plan if configured.
+	recordPlan(cmd, zk, originalMap, partitionMapOut)
+
+	// Emit plan summary metrics if configured.
+	emitPlanStats(cmd, stats)
+
+	// Render the output template if configured.
+	renderPlanOutput(cmd, tmpl, output)
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/honeycombio/kafka-kit/kafkazk"
+	"github.com/honeycombio/kafka-kit/planner"
 
 	"github.com/spf13/cobra"
 )
@@ -39,39 +40,61 @@ func getPartitionMap(cmd *cobra.Command, zk kafkazk.Handler) *kafkazk.PartitionM
 			fmt.Println(err)
 			os.Exit(1)
 		}
-		return pm
+		return filterInternalTopics(cmd, pm)
 	}
 
 	return nil
 }
 
-// getSubAffinities, if enabled via --sub-affinity, takes reference broker maps
-// and a partition map and attempts to return a complete SubstitutionAffinities.
-func getSubAffinities(cmd *cobra.Command, bm kafkazk.BrokerMap, bmo kafkazk.BrokerMap, pm *kafkazk.PartitionMap) kafkazk.SubstitutionAffinities {
-	var affinities kafkazk.SubstitutionAffinities
-	sa, _ := cmd.Flags().GetBool("sub-affinity")
-	fr, _ := cmd.Flags().GetBool("force-rebuild")
+// handleRebuildErr prints the planner.Rebuild error and exits. Out of sync
+// partitions are listed if all were excluded (which isn't a failure), as are
+// min.insync.replicas violations if they were blocked.
+func handleRebuildErr(err error, oos string, hookSpecs []string) {
+	switch e := err.(type) {
+	case planner.OutOfSyncError:
+		printOutOfSync(e.Partitions, oos == "exclude")
+		fmt.Println("\nNo partitions remaining after exclusions, skipping map generation")
+		os.Exit(0)
+	case planner.MinISRError:
+		printMinISRViolations(e.Violations)
+		blockMinISR(e.Violations)
+	case planner.HookError:
+		fmt.Printf("\nPlacement hooks:\n")
+		fmt.Printf("%s%s: %s\n", indent, hookSpecs[e.Index], e.Err)
+	default:
+		fmt.Println(err)
+	}
+
+	os.Exit(1)
+}
 
-	if sa && !fr {
-		var err error
-		affinities, err = bm.SubstitutionAffinities(pm)
-		if err != nil {
-			fmt.Printf("Substitution affinity error: %s\n", err.Error())
-			os.Exit(1)
-		}
+// printBrokerChanges prints the changes
+// to the target brokers of a rebuild Plan.
+func printBrokerChanges(plan *planner.Plan) {
+	fmt.Printf("\nBroker change summary:\n")
+	for _, m := range plan.BrokerChanges {
+		fmt.Printf("%s%s\n", indent, m)
 	}
 
-	// Print whether any affinities
-	// were inferred.
-	for a, b := range affinities {
+	if plan.BrokerStatus.Changes() {
+		fmt.Printf("%s-\n", indent)
+	}
+}
+
+// printSubAffinities prints the substitution affinities of
+// a rebuild Plan and whether any affinities were inferred.
+func printSubAffinities(plan *planner.Plan) {
+	for a, b := range plan.Affinities {
 		var inferred string
-		if bmo[a].Missing {
+		if plan.BrokersBefore[a].Missing {
 			inferred = "(inferred)"
 		}
 		fmt.Printf("%sSubstitution affinity: %d -> %d %s\n", indent, a, b.ID, inferred)
 	}
 
-	return affinities
+	if plan.Affinities != nil {
+		fmt.Printf("%s-\n", indent)
+	}
 }
 
 // getBrokers takes a PartitionMap and BrokerMetaMap and returns a BrokerMap
@@ -95,11 +118,13 @@ func getBrokers(cmd *cobra.Command, pm *kafkazk.PartitionMap, bm kafkazk.BrokerM
 
 	// Update the currentBrokers list with
 	// the provided broker list.
-	bs, msgs := brokers.Update(Config.brokers, bm)
+	bs, msgs := brokers.Update(scopeBrokers(brokers), bm)
 	for m := range msgs {
 		fmt.Printf("%s%s\n", indent, m)
 	}
 
+	brokers.SetMaintenance(Config.maintenanceBrokers)
+
 	return brokers, bs
 }
 
@@ -157,84 +182,3 @@ func printChangesActions(cmd *cobra.Command, bs *kafkazk.BrokerStatus) {
 		fmt.Printf("%s%s\n", indent, a)
 	}
 }
-
-// updateReplicationFactor takes a PartitionMap and normalizes
-// the replica set length to an optionally provided value.
-func updateReplicationFactor(cmd *cobra.Command, pm *kafkazk.PartitionMap) {
-	r, _ := cmd.Flags().GetInt("replication")
-	// If the replication factor is changed,
-	// the partition map input needs to have stub
-	// brokers appended (r factor increase) or
-	// existing brokers removed (r factor decrease).
-	if r > 0 {
-		pm.SetReplication(r)
-	}
-}
-
-// buildMap takes an input PartitionMap, rebuild parameters, and all partition/broker
-// metadata structures required to generate the output PartitionMap. A []string of
-// warnings / advisories is returned if any are encountered.
-func buildMap(cmd *cobra.Command, pm *kafkazk.PartitionMap, pmm kafkazk.PartitionMetaMap, bm kafkazk.BrokerMap, af kafkazk.SubstitutionAffinities) (*kafkazk.PartitionMap, errors) {
-	placement := cmd.Flag("placement").Value.String()
-	psf, _ := cmd.Flags().GetFloat64("partition-size-factor")
-	mrrid, _ := cmd.Flags().GetInt("min-rack-ids")
-
-	rebuildParams := kafkazk.RebuildParams{
-		PMM:              pmm,
-		BM:               bm,
-		Strategy:         placement,
-		Optimization:     cmd.Flag("optimize").Value.String(),
-		PartnSzFactor:    psf,
-		MinUniqueRackIDs: mrrid,
-	}
-
-	if af != nil {
-		rebuildParams.Affinities = af
-	}
-
-	// If we're doing a force rebuild, the input map
-	// must have all brokers stripped out.
-	// A few notes about doing force rebuilds:
-	// - Map rebuilds should always be called on a stripped PartitionMap copy.
-	// - The BrokerMap provided in the Rebuild call should have
-	//   been built from the original PartitionMap, not the stripped map.
-	// - A force rebuild assumes that all partitions will be lifted from
-	//   all brokers and repositioned. This means you should call the
-	//   SubStorageAll method on the BrokerMap if we're doing a "storage" placement strategy.
-	//   The SubStorageAll takes a PartitionMap and PartitionMetaMap. The PartitionMap is
-	//   used to find partition to broker relationships so that the storage used can
-	//   be readded to the broker's StorageFree value. The amount to be readded, the
-	//   size of the partition, is referenced from the PartitionMetaMap.
-
-	if fr, _ := cmd.Flags().GetBool("force-rebuild"); fr {
-		// Get a stripped map that we'll call rebuild on.
-		partitionMapInStripped := pm.Strip()
-		// If the storage placement strategy is being used,
-		// update the broker StorageFree values.
-		if placement == "storage" {
-			allBrokers := func(b *kafkazk.Broker) bool { return true }
-			err := rebuildParams.BM.SubStorage(pm, pmm, allBrokers)
-			if err != nil {
-				fmt.Println(err)
-				os.Exit(1)
-			}
-		}
-
-		// Rebuild.
-		return partitionMapInStripped.Rebuild(rebuildParams)
-	}
-
-	// Update the StorageFree only on brokers
-	// marked for replacement.
-	if placement == "storage" {
-		replacedBrokers := func(b *kafkazk.Broker) bool { return b.Replace }
-		err := rebuildParams.BM.SubStorage(pm, pmm, replacedBrokers)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-	}
-
-	// Rebuild directly on the input map.
-	return pm.Rebuild(rebuildParams)
-}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+
+	"google.golang.org/grpc"
+)
+
+// registryTimeout is the timeout for
+// registry connections and requests.
+const registryTimeout = 30 * time.Second
+
+// getRegistryState fetches the ClusterState for all --topics, or all topics
+// if none are specified, from the registry gRPC API at addr. The cluster
+// selects a federated registry cluster; the default cluster if empty.
+func getRegistryState(addr, cluster string) (*kafkazk.ClusterState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), registryTimeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return nil, fmt.Errorf("Error connecting to registry %s: %s", addr, err)
+	}
+
+	defer conn.Close()
+
+	req := &pb.ClusterStateRequest{Cluster: cluster}
+	for _, t := range Config.topics {
+		req.Topic = append(req.Topic, t.String())
+	}
+
+	resp, err := pb.NewRegistryClient(conn).ClusterState(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("Error fetching cluster state from registry %s: %s", addr, err)
+	}
+
+	s := &kafkazk.ClusterState{}
+	if err := json.Unmarshal(resp.State, s); err != nil {
+		return nil, fmt.Errorf("Error parsing cluster state from registry %s: %s", addr, err)
+	}
+
+	if s.Version != kafkazk.ClusterStateVersion {
+		return nil, kafkazk.ErrUnsupportedClusterStateVersion
+	}
+
+	return s, nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
 	"github.com/jamiealquiza/envy"
 	"github.com/spf13/cobra"
 )
@@ -25,5 +27,29 @@ func Execute() {
 func init() {
 	rootCmd.PersistentFlags().String("zk-addr", "localhost:2181", "ZooKeeper connect string")
 	rootCmd.PersistentFlags().String("zk-prefix", "", "ZooKeeper prefix (if Kafka is configured with a chroot path prefix)")
+	rootCmd.PersistentFlags().Bool("zk-tls", false, "Connect to ZooKeeper with TLS (implied by the other zk-tls flags)")
+	rootCmd.PersistentFlags().String("zk-tls-ca-cert", "", "Path to a PEM CA certificate for verifying ZooKeeper servers; the system roots are used if empty")
+	rootCmd.PersistentFlags().String("zk-tls-cert", "", "Path to a PEM client certificate for ZooKeeper mTLS (requires --zk-tls-key)")
+	rootCmd.PersistentFlags().String("zk-tls-key", "", "Path to a PEM client key for ZooKeeper mTLS")
+	rootCmd.PersistentFlags().String("zk-tls-server-name", "", "Server name verified against ZooKeeper server certificates; the --zk-addr host is used if empty")
+	rootCmd.PersistentFlags().String("from-snapshot", "", "Plan offline from a cluster state file rather than ZooKeeper (see snapshot export)")
+	rootCmd.PersistentFlags().String("registry-addr", "", "Fetch cluster metadata and tags from the registry gRPC API at this address rather than ZooKeeper (read-only, as with --from-snapshot)")
+	rootCmd.PersistentFlags().String("kafka-bootstrap-servers", "", "Comma-delimited list of Kafka bootstrap servers; if set, cluster metadata is fetched and plans are applied via the Kafka Admin API rather than ZooKeeper, e.g. for KRaft clusters (requires Kafka 2.4+)")
+	rootCmd.PersistentFlags().String("metrics-zk-addr", "", "ZooKeeper connect string of the ensemble metricsfetcher stores broker and partition metrics in (under --zk-metrics-prefix), with --kafka-bootstrap-servers; required for storage placement and rebalancing via the Kafka Admin API")
+	rootCmd.PersistentFlags().Bool("kafka-tls", false, "Connect to Kafka with TLS (implied by --kafka-tls-ca-cert)")
+	rootCmd.PersistentFlags().String("kafka-tls-ca-cert", "", "Path to a PEM CA certificate for verifying Kafka brokers; the system roots are used if empty")
+	rootCmd.PersistentFlags().String("kafka-sasl-mechanism", "", "Kafka SASL mechanism (PLAIN, SCRAM-SHA-256, SCRAM-SHA-512); SASL authentication is disabled if empty")
+	rootCmd.PersistentFlags().String("kafka-sasl-username", "", "Kafka SASL username")
+	rootCmd.PersistentFlags().String("kafka-sasl-password", "", "Kafka SASL password (preferably set via TOPICMAPPR_KAFKA_SASL_PASSWORD)")
+	rootCmd.PersistentFlags().String("registry-cluster", "", "Registry cluster to fetch cluster metadata for with --registry-addr, if the registry serves multiple clusters")
+	rootCmd.PersistentFlags().String("rack-groups", "", "Path to a JSON mapping of rack IDs and/or broker IDs to logical placement domains; domains are used in place of rack IDs in all placement constraints")
+	rootCmd.PersistentFlags().String("zk-tags-prefix", "registry", "ZooKeeper prefix where the registry stores tags (see --broker-tags)")
+	rootCmd.PersistentFlags().Int("zk-concurrency", kafkazk.DefaultConcurrency, "Maximum number of concurrent ZooKeeper reads when fetching metadata")
 	rootCmd.PersistentFlags().Bool("ignore-warns", false, "Produce a map even if warnings are encountered")
+	rootCmd.PersistentFlags().String("metrics-backend", "", "Backend to emit plan summary metrics to: [statsd, pushgateway, honeycomb] (disabled if empty)")
+	rootCmd.PersistentFlags().String("metrics-addr", "", "Metrics backend address; a statsd host:port or a Pushgateway/Honeycomb API URL (uses the backend's default if empty)")
+	rootCmd.PersistentFlags().String("metrics-prefix", "topicmappr", "Metric name prefix (statsd), job name (Pushgateway) or dataset (Honeycomb)")
+	rootCmd.PersistentFlags().String("metrics-api-key", "", "Honeycomb API key")
+	rootCmd.PersistentFlags().Bool("record-history", false, "Record generated plans in ZooKeeper (see the history command)")
+	rootCmd.PersistentFlags().String("history-path", "/topicmappr/history", "ZooKeeper path where plan history is recorded")
 }
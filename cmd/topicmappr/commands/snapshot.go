@@ -0,0 +1,344 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save, compare and restore named partition assignment snapshots",
+	Long: `snapshot manages named captures of partition assignments stored in ZooKeeper.
+A snapshot taken prior to a risky operation serves as a checkpoint; the current
+assignments can later be compared against it, and a partition map that restores
+the snapshot assignments can be generated.`,
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Save the current assignments of topics under a name",
+	Run:   snapshotSave,
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved snapshots",
+	Run:   snapshotList,
+}
+
+var snapshotDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare the current assignments against a snapshot",
+	Run:   snapshotDiff,
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Write a partition map that restores the assignments of a snapshot",
+	Run:   snapshotRestore,
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a snapshot",
+	Run:   snapshotDelete,
+}
+
+var snapshotExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the cluster state to a file for offline planning",
+	Long: `export writes broker metadata, topic assignments, configs and partition states,
+in progress reassignments and the metrics metadata to a single versioned JSON file.
+The file can be used for offline planning with the --from-snapshot flag and for
+support and debugging workflows.`,
+	Run: snapshotExport,
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(snapshotSaveCmd, snapshotListCmd, snapshotDiffCmd, snapshotRestoreCmd, snapshotDeleteCmd, snapshotExportCmd)
+
+	snapshotCmd.PersistentFlags().String("snapshot-path", "/topicmappr/snapshots", "ZooKeeper path where snapshots are stored")
+
+	for _, c := range []*cobra.Command{snapshotSaveCmd, snapshotDiffCmd, snapshotRestoreCmd, snapshotDeleteCmd} {
+		c.Flags().String("name", "", "Snapshot name")
+		c.MarkFlagRequired("name")
+	}
+
+	snapshotSaveCmd.Flags().String("topics", "", "Topics (comma delim. list) to snapshot by lookup in ZooKeeper")
+	snapshotSaveCmd.Flags().Bool("force", false, "Overwrite an existing snapshot of the same name")
+	snapshotSaveCmd.MarkFlagRequired("topics")
+
+	snapshotRestoreCmd.Flags().String("out-path", "", "Path to write output map files to")
+	snapshotRestoreCmd.Flags().String("out-file", "", "If defined, write a combined map of all topics to a file")
+
+	snapshotExportCmd.Flags().String("topics", ".*", "Topics (comma delim. list) to export by lookup in ZooKeeper")
+	snapshotExportCmd.Flags().String("zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics")
+	snapshotExportCmd.Flags().String("out-file", "", "Path to write the cluster state file to")
+	snapshotExportCmd.MarkFlagRequired("out-file")
+}
+
+// getSnapshotDiff fetches the snapshot specified via --name
+// and returns the SnapshotDiff against current assignments.
+func getSnapshotDiff(cmd *cobra.Command, zk kafkazk.Handler) (*kafkazk.AssignmentSnapshot, kafkazk.SnapshotDiff) {
+	path := cmd.Flag("snapshot-path").Value.String()
+	name := cmd.Flag("name").Value.String()
+
+	s, err := kafkazk.GetAssignmentSnapshot(zk, path, name)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// Get the current map for all snapshot topics.
+	topics := map[string]struct{}{}
+	for _, p := range s.Map.Partitions {
+		topics[p.Topic] = struct{}{}
+	}
+
+	current := kafkazk.NewPartitionMap()
+	for t := range topics {
+		pm, err := zk.GetPartitionMap(t)
+		if err != nil {
+			switch err.(type) {
+			case kafkazk.ErrNoNode:
+				continue
+			default:
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+
+		current.Partitions = append(current.Partitions, pm.Partitions...)
+	}
+
+	fmt.Printf("\nSnapshot %s:\n", s.Name)
+	fmt.Printf("%stime: %s\n", indent, time.Unix(s.Timestamp, 0).UTC().Format(time.RFC3339))
+	fmt.Printf("%suser: %s\n", indent, s.User)
+	fmt.Printf("%spartitions: %d\n", indent, len(s.Map.Partitions))
+
+	return s, s.Diff(current)
+}
+
+// printSnapshotDiff prints the SnapshotDiff and returns
+// errors for any snapshot topics or partitions that
+// can't be restored.
+func printSnapshotDiff(d kafkazk.SnapshotDiff) errors {
+	var errs errors
+
+	printMapChanges(d.Current, d.Snapshot)
+
+	for _, t := range d.MissingTopics {
+		errs = append(errs, fmt.Errorf("topic %s no longer exists", t))
+	}
+
+	for _, p := range d.MissingPartitions {
+		errs = append(errs, fmt.Errorf("%s p%d no longer exists", p.Topic, p.Partition))
+	}
+
+	for _, p := range d.NewPartitions {
+		errs = append(errs, fmt.Errorf("%s p%d was created after the snapshot", p.Topic, p.Partition))
+	}
+
+	return errs
+}
+
+func snapshotSave(cmd *cobra.Command, _ []string) {
+	bootstrap(cmd)
+
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	pm, err := kafkazk.PartitionMapFromZK(Config.topics, zk)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	printTopics(pm)
+
+	s := &kafkazk.AssignmentSnapshot{
+		Name:      cmd.Flag("name").Value.String(),
+		Timestamp: time.Now().Unix(),
+		User:      currentUser(),
+		Map:       pm,
+	}
+
+	path := cmd.Flag("snapshot-path").Value.String()
+	force, _ := cmd.Flags().GetBool("force")
+
+	if err := kafkazk.WriteAssignmentSnapshot(zk, path, s, force); err != nil {
+		fmt.Printf("\nError saving snapshot %s: %s\n", s.Name, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nSaved %d partitions as %s/%s\n", len(pm.Partitions), path, s.Name)
+}
+
+func snapshotList(cmd *cobra.Command, _ []string) {
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	path := cmd.Flag("snapshot-path").Value.String()
+
+	names, err := kafkazk.GetAssignmentSnapshotNames(zk, path)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nSnapshots (%s):\n", path)
+	if len(names) == 0 {
+		fmt.Printf("%s[none]\n", indent)
+	}
+
+	for _, n := range names {
+		s, err := kafkazk.GetAssignmentSnapshot(zk, path, n)
+		if err != nil {
+			fmt.Printf("%s%s: %s\n", indent, n, err)
+			continue
+		}
+
+		fmt.Printf("%s%s %s, user: %s, partitions: %d\n", indent, s.Name,
+			time.Unix(s.Timestamp, 0).UTC().Format(time.RFC3339), s.User, len(s.Map.Partitions))
+	}
+}
+
+func snapshotDiff(cmd *cobra.Command, _ []string) {
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	_, d := getSnapshotDiff(cmd, zk)
+	errs := printSnapshotDiff(d)
+
+	fmt.Println("\nWARN:")
+	if len(errs) == 0 {
+		fmt.Printf("%s[none]\n", indent)
+	}
+
+	for _, err := range errs {
+		fmt.Printf("%s%s\n", indent, err)
+	}
+}
+
+func snapshotRestore(cmd *cobra.Command, _ []string) {
+	bootstrap(cmd)
+
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	_, d := getSnapshotDiff(cmd, zk)
+
+	// Partitions that no longer exist or were
+	// created since the snapshot are left as is.
+	handleOverridableErrs(cmd, printSnapshotDiff(d))
+
+	// Ignore no-ops.
+	_, partitionMapOut := skipReassignmentNoOps(d.Current, d.Snapshot)
+
+	writeMaps(cmd, partitionMapOut)
+
+	// Record the plan if configured.
+	recordPlan(cmd, zk, d.Current, d.Snapshot)
+}
+
+func snapshotDelete(cmd *cobra.Command, _ []string) {
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	path := cmd.Flag("snapshot-path").Value.String()
+	name := cmd.Flag("name").Value.String()
+
+	if err := kafkazk.DeleteAssignmentSnapshot(zk, path, name); err != nil {
+		fmt.Printf("\nError deleting snapshot %s: %s\n", name, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nDeleted %s/%s\n", path, name)
+}
+
+func snapshotExport(cmd *cobra.Command, _ []string) {
+	bootstrap(cmd)
+
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	s, err := kafkazk.ClusterStateFromZK(Config.topics, zk)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nCluster state:\n")
+	fmt.Printf("%sbrokers: %d\n", indent, len(s.Brokers))
+	fmt.Printf("%stopics: %d\n", indent, len(s.Topics))
+	fmt.Printf("%sreassignments in progress: %d\n", indent, len(s.Reassignments))
+
+	// Metrics metadata is optional.
+	var errs errors
+	if s.BrokerMetrics == nil {
+		errs = append(errs, fmt.Errorf("broker metrics unavailable"))
+	}
+
+	if s.PartitionMeta == nil {
+		errs = append(errs, fmt.Errorf("partition metrics unavailable"))
+	}
+
+	fmt.Println("\nWARN:")
+	if len(errs) == 0 {
+		fmt.Printf("%s[none]\n", indent)
+	}
+
+	for _, err := range errs {
+		fmt.Printf("%s%s\n", indent, err)
+	}
+
+	out := cmd.Flag("out-file").Value.String()
+	if err := kafkazk.WriteClusterState(s, out); err != nil {
+		fmt.Printf("\nError writing cluster state: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nCluster state written to %s\n", out)
+}
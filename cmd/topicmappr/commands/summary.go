@@ -0,0 +1,292 @@
+package commands
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+// brokerSummary describes the before and after
+// assignments and free storage of a broker.
+type brokerSummary struct {
+	ID                int
+	LeadersBefore     int
+	LeadersAfter      int
+	PartitionsBefore  int
+	PartitionsAfter   int
+	StorageFreeBefore float64
+	StorageFreeAfter  float64
+	Replace           bool
+}
+
+// LeadersDelta returns the change in leaders.
+func (b brokerSummary) LeadersDelta() int { return b.LeadersAfter - b.LeadersBefore }
+
+// PartitionsDelta returns the change in partitions.
+func (b brokerSummary) PartitionsDelta() int { return b.PartitionsAfter - b.PartitionsBefore }
+
+// StorageFreeDelta returns the change in free storage.
+func (b brokerSummary) StorageFreeDelta() float64 { return b.StorageFreeAfter - b.StorageFreeBefore }
+
+// getBrokerSummaries takes the input and output PartitionMap along with the
+// input and output BrokerMap and returns a brokerSummary for each broker
+// mapped in either PartitionMap, sorted by ID.
+func getBrokerSummaries(pm1, pm2 *kafkazk.PartitionMap, bm1, bm2 kafkazk.BrokerMap) []brokerSummary {
+	summaries := map[int]*brokerSummary{}
+
+	get := func(id int) *brokerSummary {
+		if _, exists := summaries[id]; !exists {
+			summaries[id] = &brokerSummary{ID: id}
+		}
+		return summaries[id]
+	}
+
+	for _, use := range pm1.UseStats() {
+		if use.ID == kafkazk.StubBrokerID {
+			continue
+		}
+		b := get(use.ID)
+		b.LeadersBefore = use.Leader
+		b.PartitionsBefore = use.Leader + use.Follower
+	}
+
+	for _, use := range pm2.UseStats() {
+		if use.ID == kafkazk.StubBrokerID {
+			continue
+		}
+		b := get(use.ID)
+		b.LeadersAfter = use.Leader
+		b.PartitionsAfter = use.Leader + use.Follower
+	}
+
+	var out []brokerSummary
+	for id, b := range summaries {
+		if broker, exists := bm1[id]; exists {
+			b.StorageFreeBefore = broker.StorageFree
+		}
+		if broker, exists := bm2[id]; exists {
+			b.StorageFreeAfter = broker.StorageFree
+			b.Replace = broker.Replace
+		}
+		out = append(out, *b)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+
+	return out
+}
+
+// storageEstimated returns whether the command
+// estimates broker free storage changes.
+func storageEstimated(cmd *cobra.Command) bool {
+	return cmd.Use == "rebalance" || cmd.Flag("placement").Value.String() == "storage"
+}
+
+// formatCountChange returns a before -> after string for a
+// count along with the signed delta, if changed.
+func formatCountChange(before, after int) string {
+	if before == after {
+		return fmt.Sprint(after)
+	}
+	return fmt.Sprintf("%d -> %d (%+d)", before, after, after-before)
+}
+
+// formatStorageChange returns a before -> after string for a
+// storage value along with the signed delta, if changed.
+func formatStorageChange(before, after float64) string {
+	if fmt.Sprintf("%.2f", before/div) == fmt.Sprintf("%.2f", after/div) {
+		return fmt.Sprintf("%.2fGB", after/div)
+	}
+	return fmt.Sprintf("%.2f -> %.2fGB (%+.2fGB)", before/div, after/div, (after-before)/div)
+}
+
+// printBrokerSummaries prints a table of per-broker leader, partition and
+// (if storage is true) free storage changes. Changed values are listed as
+// before -> after with the delta; changed brokers are marked with a '*'.
+func printBrokerSummaries(summaries []brokerSummary, storage bool) {
+	fmt.Printf("\nBroker summary:\n")
+	if len(summaries) == 0 {
+		fmt.Printf("%s[none]\n", indent)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	header := []string{"", "BROKER", "LEADERS", "PARTITIONS"}
+	if storage {
+		header = append(header, "STORAGE FREE")
+	}
+	fmt.Fprintf(w, "%s%s\t\n", indent, strings.Join(header, "\t"))
+
+	for _, b := range summaries {
+		mark := " "
+		if b.LeadersDelta() != 0 || b.PartitionsDelta() != 0 {
+			mark = "*"
+		}
+
+		id := fmt.Sprint(b.ID)
+		if b.Replace {
+			id += " (replaced)"
+		}
+
+		row := []string{mark, id,
+			formatCountChange(b.LeadersBefore, b.LeadersAfter),
+			formatCountChange(b.PartitionsBefore, b.PartitionsAfter)}
+		if storage {
+			row = append(row, formatStorageChange(b.StorageFreeBefore, b.StorageFreeAfter))
+		}
+
+		fmt.Fprintf(w, "%s%s\t\n", indent, strings.Join(row, "\t"))
+	}
+
+	w.Flush()
+}
+
+// htmlReport is the data model of the --html-report template.
+type htmlReport struct {
+	Command   string
+	Topics    []string
+	Generated time.Time
+	Storage   bool
+	Brokers   []brokerSummary
+	// The maximum partitions held by any broker
+	// before or after; used to scale bars.
+	MaxPartitions int
+}
+
+var htmlReportFuncs = template.FuncMap{
+	"gb":      func(b float64) string { return fmt.Sprintf("%.2f", b/div) },
+	"gbDelta": func(b float64) string { return fmt.Sprintf("%+.2f", b/div) },
+	"deltaClass": func(d float64) string {
+		switch {
+		case d > 0:
+			return "increase"
+		case d < 0:
+			return "decrease"
+		}
+		return ""
+	},
+	"float": func(i int) float64 { return float64(i) },
+	"pct": func(n, max int) int {
+		if max == 0 {
+			return 0
+		}
+		return n * 100 / max
+	},
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(htmlReportFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>topicmappr {{.Command}} plan</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th { background: #eee; }
+td.increase { background: #d9f2d9; }
+td.decrease { background: #f9d6d5; }
+tr.replaced td { color: #888; }
+.bar { display: inline-block; height: 10px; margin-right: 2px; }
+.bar.before { background: #aaa; }
+.bar.after { background: #4a7fd4; }
+</style>
+</head>
+<body>
+<h2>topicmappr {{.Command}} plan</h2>
+<p>Topics: {{range $i, $t := .Topics}}{{if $i}}, {{end}}{{$t}}{{end}}<br>
+Generated: {{.Generated.Format "2006-01-02 15:04:05 MST"}}</p>
+<table>
+<tr><th>Broker</th><th>Leaders before</th><th>Leaders after</th><th>Delta</th><th>Partitions before</th><th>Partitions after</th><th>Delta</th>{{if .Storage}}<th>Storage free before (GB)</th><th>Storage free after (GB)</th><th>Delta (GB)</th>{{end}}<th>Partitions</th></tr>
+{{- $max := .MaxPartitions}}{{$storage := .Storage}}
+{{range .Brokers}}<tr{{if .Replace}} class="replaced"{{end}}>
+<td>{{.ID}}{{if .Replace}} (replaced){{end}}</td>
+<td>{{.LeadersBefore}}</td><td>{{.LeadersAfter}}</td><td class="{{deltaClass (float .LeadersDelta)}}">{{printf "%+d" .LeadersDelta}}</td>
+<td>{{.PartitionsBefore}}</td><td>{{.PartitionsAfter}}</td><td class="{{deltaClass (float .PartitionsDelta)}}">{{printf "%+d" .PartitionsDelta}}</td>
+{{- if $storage}}
+<td>{{gb .StorageFreeBefore}}</td><td>{{gb .StorageFreeAfter}}</td><td class="{{deltaClass .StorageFreeDelta}}">{{gbDelta .StorageFreeDelta}}</td>
+{{- end}}
+<td style="text-align: left"><span class="bar before" style="width: {{pct .PartitionsBefore $max}}px"></span><br><span class="bar after" style="width: {{pct .PartitionsAfter $max}}px"></span></td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// writeHTMLReport renders an HTML report of the
+// brokerSummary list to the file path.
+func writeHTMLReport(path, command string, pm *kafkazk.PartitionMap, summaries []brokerSummary, storage bool) error {
+	r := htmlReport{
+		Command:   command,
+		Generated: time.Now(),
+		Storage:   storage,
+		Brokers:   summaries,
+	}
+
+	topics := map[string]struct{}{}
+	for _, p := range pm.Partitions {
+		topics[p.Topic] = struct{}{}
+	}
+	for t := range topics {
+		r.Topics = append(r.Topics, t)
+	}
+	sort.Strings(r.Topics)
+
+	for _, b := range summaries {
+		if b.PartitionsBefore > r.MaxPartitions {
+			r.MaxPartitions = b.PartitionsBefore
+		}
+		if b.PartitionsAfter > r.MaxPartitions {
+			r.MaxPartitions = b.PartitionsAfter
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("Error writing HTML report: %s", err)
+	}
+
+	defer f.Close()
+
+	if err := htmlReportTemplate.Execute(f, r); err != nil {
+		return fmt.Errorf("Error rendering HTML report: %s", err)
+	}
+
+	return nil
+}
+
+// summarizeBrokers prints the broker summary table if --broker-summary is
+// set and writes the HTML report if --html-report is defined.
+func summarizeBrokers(cmd *cobra.Command, pm1, pm2 *kafkazk.PartitionMap, bm1, bm2 kafkazk.BrokerMap) {
+	bs, _ := cmd.Flags().GetBool("broker-summary")
+	path := cmd.Flag("html-report").Value.String()
+
+	if !bs && path == "" {
+		return
+	}
+
+	summaries := getBrokerSummaries(pm1, pm2, bm1, bm2)
+	storage := storageEstimated(cmd)
+
+	if bs {
+		printBrokerSummaries(summaries, storage)
+	}
+
+	if path != "" {
+		if err := writeHTMLReport(path, cmd.Use, pm1, summaries, storage); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("\nHTML report written to %s\n", path)
+	}
+}
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+func TestGetBrokerSummaries(t *testing.T) {
+	pm1, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test","partition":0,"replicas":[1001,1002]},
+    {"topic":"test","partition":1,"replicas":[1002,1001]}]}`)
+
+	pm2, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test","partition":0,"replicas":[1003,1002]},
+    {"topic":"test","partition":1,"replicas":[1002,1003]}]}`)
+
+	bm1 := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, StorageFree: 100},
+		1002: &kafkazk.Broker{ID: 1002, StorageFree: 100},
+		1003: &kafkazk.Broker{ID: 1003, StorageFree: 200},
+	}
+
+	bm2 := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, StorageFree: 150, Replace: true},
+		1002: &kafkazk.Broker{ID: 1002, StorageFree: 100},
+		1003: &kafkazk.Broker{ID: 1003, StorageFree: 150},
+	}
+
+	s := getBrokerSummaries(pm1, pm2, bm1, bm2)
+
+	expected := []brokerSummary{
+		{ID: 1001, LeadersBefore: 1, PartitionsBefore: 2, StorageFreeBefore: 100, StorageFreeAfter: 150, Replace: true},
+		{ID: 1002, LeadersBefore: 1, LeadersAfter: 1, PartitionsBefore: 2, PartitionsAfter: 2, StorageFreeBefore: 100, StorageFreeAfter: 100},
+		{ID: 1003, LeadersAfter: 1, PartitionsAfter: 2, StorageFreeBefore: 200, StorageFreeAfter: 150},
+	}
+
+	if len(s) != len(expected) {
+		t.Fatalf("Expected %d summaries, got %d", len(expected), len(s))
+	}
+
+	for i := range expected {
+		if s[i] != expected[i] {
+			t.Errorf("Expected summary %+v, got %+v", expected[i], s[i])
+		}
+	}
+
+	if s[0].PartitionsDelta() != -2 || s[2].LeadersDelta() != 1 || s[2].StorageFreeDelta() != -50 {
+		t.Errorf("Unexpected deltas for summaries %+v", s)
+	}
+}
+
+func TestWriteHTMLReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	pm, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test","partition":0,"replicas":[1001,1002]}]}`)
+
+	s := []brokerSummary{
+		{ID: 1001, LeadersBefore: 1, PartitionsBefore: 1, StorageFreeBefore: 2 * div, StorageFreeAfter: 3 * div, Replace: true},
+		{ID: 1003, LeadersAfter: 1, PartitionsAfter: 1, StorageFreeBefore: 3 * div, StorageFreeAfter: 2 * div},
+	}
+
+	path := filepath.Join(dir, "report.html")
+	if err := writeHTMLReport(path, "rebuild", pm, s, true); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"<title>topicmappr rebuild plan</title>",
+		"Topics: test<br>",
+		`<td>1001 (replaced)</td>`,
+		`<td class="decrease">-1</td>`,
+		`<td class="increase">&#43;1.00</td>`,
+		`<td class="decrease">-1.00</td>`,
+	} {
+		if !bytes.Contains(data, []byte(expected)) {
+			t.Errorf("Expected report to contain '%s'", expected)
+		}
+	}
+}
+
+func TestFormatChanges(t *testing.T) {
+	if s := formatCountChange(2, 2); s != "2" {
+		t.Errorf("Expected '2', got '%s'", s)
+	}
+
+	if s := formatCountChange(2, 5); s != "2 -> 5 (+3)" {
+		t.Errorf("Expected '2 -> 5 (+3)', got '%s'", s)
+	}
+
+	if s := formatStorageChange(2*div, 1.5*div); !strings.HasSuffix(s, "(-0.50GB)") {
+		t.Errorf("Expected '2.00 -> 1.50GB (-0.50GB)', got '%s'", s)
+	}
+}
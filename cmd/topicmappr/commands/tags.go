@@ -0,0 +1,225 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+// parseBrokerTags takes a comma or space delimited list of "key:value"
+// (or "key=value") tags and returns a map of tag keys to values.
+func parseBrokerTags(s string) (map[string]string, error) {
+	tags := map[string]string{}
+
+	for _, t := range strings.FieldsFunc(s, isListDelim) {
+		i := strings.IndexAny(t, ":=")
+		if i < 1 || i == len(t)-1 {
+			return nil, fmt.Errorf("invalid tag '%s': must be formatted as key:value", t)
+		}
+
+		tags[t[:i]] = t[i+1:]
+	}
+
+	return tags, nil
+}
+
+// isListDelim returns whether r delimits
+// values in comma or space delimited lists.
+func isListDelim(r rune) bool {
+	return r == ',' || unicode.IsSpace(r)
+}
+
+// brokerTags returns the registry tags for the broker id with metadata m,
+// fetched from the tag storage under the --zk-tags-prefix. As with the
+// registry, the default tags (e.g. rack, host) are derived from the
+// broker metadata and merged with any user-defined tags.
+func brokerTags(cmd *cobra.Command, zk kafkazk.Handler, id int, m *kafkazk.BrokerMeta) (map[string]string, error) {
+	tags := map[string]string{
+		"id":        fmt.Sprintf("%d", id),
+		"rack":      m.Rack,
+		"jmxport":   fmt.Sprintf("%d", m.JMXPort),
+		"host":      m.Host,
+		"timestamp": m.Timestamp,
+		"port":      fmt.Sprintf("%d", m.Port),
+		"version":   fmt.Sprintf("%d", m.Version),
+	}
+
+	stored, err := storedTags(cmd, zk, "broker", fmt.Sprintf("%d", id))
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range stored {
+		tags[k] = v
+	}
+
+	return tags, nil
+}
+
+// storedTags returns the user-defined registry tags for the object (broker
+// or topic) with the id, fetched from the tag storage under the
+// --zk-tags-prefix or from the cluster state if planning from a snapshot or
+// the registry. An empty map is returned if none are stored.
+func storedTags(cmd *cobra.Command, zk kafkazk.Handler, kind, id string) (map[string]string, error) {
+	// Cluster states served by the
+	// registry include all tags.
+	if s, ok := zk.(*kafkazk.StateHandler); ok {
+		return s.State.ObjectTags(kind, id), nil
+	}
+
+	tags := map[string]string{}
+
+	prefix := strings.Trim(cmd.Flag("zk-tags-prefix").Value.String(), "/")
+	data, err := zk.Get(fmt.Sprintf("/%s/%s/%s", prefix, kind, id))
+	if err != nil {
+		switch err.(type) {
+		// No user-defined tags.
+		case kafkazk.ErrNoNode:
+			return tags, nil
+		default:
+			return nil, err
+		}
+	}
+
+	if len(data) == 0 {
+		return tags, nil
+	}
+
+	if err := json.Unmarshal(data, &tags); err != nil {
+		return nil, fmt.Errorf("Error unmarshalling tags for %s %s: %s", kind, id, err)
+	}
+
+	return tags, nil
+}
+
+// loadBrokerTags looks up the registry tags of all brokers if --broker-tags
+// is set, storing the IDs of those matching all provided tags at
+// Config.taggedBrokers. The matched brokers are printed.
+func loadBrokerTags(cmd *cobra.Command, zk kafkazk.Handler) {
+	bt, _ := cmd.Flags().GetString("broker-tags")
+	if bt == "" {
+		return
+	}
+
+	want, err := parseBrokerTags(bt)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	Config.taggedBrokers = map[int]bool{}
+	for _, id := range brokersMatchingTags(cmd, zk, want) {
+		Config.taggedBrokers[id] = true
+	}
+
+	var ids []int
+	for id := range Config.taggedBrokers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	fmt.Printf("\nBrokers matching tags %s:\n", bt)
+	if len(ids) == 0 {
+		fmt.Printf("%s[none]\n", indent)
+	} else {
+		fmt.Printf("%s%v\n", indent, ids)
+	}
+}
+
+// loadMaintenanceBrokers looks up the brokers in maintenance (those with
+// the registry tag maintenance:true), storing their IDs at
+// Config.maintenanceBrokers unless --ignore-maintenance is set. Brokers in
+// maintenance receive no new partitions but may be evacuated. The brokers
+// found are printed.
+func loadMaintenanceBrokers(cmd *cobra.Command, zk kafkazk.Handler) {
+	if im, _ := cmd.Flags().GetBool("ignore-maintenance"); im || zk == nil {
+		return
+	}
+
+	Config.maintenanceBrokers = brokersMatchingTags(cmd, zk, map[string]string{"maintenance": "true"})
+	if len(Config.maintenanceBrokers) == 0 {
+		return
+	}
+
+	fmt.Printf("\nBrokers in maintenance (receiving no new partitions):\n")
+	fmt.Printf("%s%v\n", indent, Config.maintenanceBrokers)
+}
+
+// brokersMatchingTags returns the sorted IDs of all
+// brokers with registry tags matching all tags in want.
+func brokersMatchingTags(cmd *cobra.Command, zk kafkazk.Handler, want map[string]string) []int {
+	bm, errs := zk.GetAllBrokerMeta(false)
+	if errs != nil {
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+		os.Exit(1)
+	}
+
+	ids := []int{}
+
+	for id, m := range bm {
+		tags, err := brokerTags(cmd, zk, id, m)
+		if err != nil {
+			fmt.Printf("Error fetching broker tags: %s\n", err)
+			os.Exit(1)
+		}
+
+		match := true
+		for k, v := range want {
+			if tags[k] != v {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+// scopeBrokers takes a BrokerMap of the currently mapped brokers and
+// returns the --brokers list scoped to brokers matching the --broker-tags.
+// The -1 placeholder is expanded to the mapped brokers prior to filtering.
+// Config.brokers is returned as-is if --broker-tags is unset.
+func scopeBrokers(b kafkazk.BrokerMap) []int {
+	if Config.taggedBrokers == nil {
+		return Config.brokers
+	}
+
+	provided := map[int]bool{}
+	for _, id := range Config.brokers {
+		if id == -1 {
+			for id := range b {
+				if id != kafkazk.StubBrokerID {
+					provided[id] = true
+				}
+			}
+			continue
+		}
+		provided[id] = true
+	}
+
+	var bl []int
+	for id := range provided {
+		if Config.taggedBrokers[id] {
+			bl = append(bl, id)
+		}
+	}
+
+	sort.Ints(bl)
+
+	return bl
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+func TestParseBrokerTags(t *testing.T) {
+	tags, err := parseBrokerTags("pool:general, storage=nvme")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tags) != 2 || tags["pool"] != "general" || tags["storage"] != "nvme" {
+		t.Errorf("Unexpected tags: %v", tags)
+	}
+
+	for _, s := range []string{"pool", ":general", "pool:"} {
+		if _, err := parseBrokerTags(s); err == nil {
+			t.Errorf("Expected error for tag '%s'", s)
+		}
+	}
+}
+
+func TestScopeBrokers(t *testing.T) {
+	defer func() { Config.brokers, Config.taggedBrokers = nil, nil }()
+
+	b := kafkazk.BrokerMap{
+		kafkazk.StubBrokerID: &kafkazk.Broker{ID: kafkazk.StubBrokerID},
+		1001:                 &kafkazk.Broker{ID: 1001},
+		1002:                 &kafkazk.Broker{ID: 1002},
+	}
+
+	Config.brokers = []int{-1, 1003, 1004}
+
+	// Unset tags are a no-op.
+	if bl := scopeBrokers(b); len(bl) != 3 {
+		t.Errorf("Expected unmodified broker list, got %v", bl)
+	}
+
+	Config.taggedBrokers = map[int]bool{1002: true, 1003: true}
+
+	bl := scopeBrokers(b)
+	expected := []int{1002, 1003}
+
+	if len(bl) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, bl)
+	}
+
+	for i := range bl {
+		if bl[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, bl)
+		}
+	}
+}
+
+func TestLoadMaintenanceBrokers(t *testing.T) {
+	defer func() { Config.maintenanceBrokers = nil }()
+
+	zk := kafkazk.NewStateHandler(&kafkazk.ClusterState{
+		Brokers: kafkazk.BrokerMetaMap{
+			1001: &kafkazk.BrokerMeta{},
+			1002: &kafkazk.BrokerMeta{},
+			1003: &kafkazk.BrokerMeta{},
+		},
+		Tags: map[string]map[string]map[string]string{
+			"broker": {
+				"1001": {"maintenance": "true"},
+				"1002": {"maintenance": "false"},
+				"1003": {"maintenance": "true", "pool": "ssd"},
+			},
+		},
+	})
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("ignore-maintenance", false, "")
+
+	loadMaintenanceBrokers(cmd, zk)
+
+	if m := Config.maintenanceBrokers; len(m) != 2 || m[0] != 1001 || m[1] != 1003 {
+		t.Errorf("Expected brokers [1001 1003] in maintenance, got %v", m)
+	}
+
+	Config.maintenanceBrokers = nil
+	cmd.Flags().Set("ignore-maintenance", "true")
+
+	if loadMaintenanceBrokers(cmd, zk); Config.maintenanceBrokers != nil {
+		t.Errorf("Expected maintenance ignored, got %v", Config.maintenanceBrokers)
+	}
+}
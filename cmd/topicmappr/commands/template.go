@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+// planOutput is the data model exposed
+// to --output-template templates.
+type planOutput struct {
+	Command  string
+	Topics   []string
+	Changes  []partitionChange
+	Brokers  []brokerChange
+	Warnings []string
+	// Summary statistics.
+	Partitions      int
+	PartitionsMoved int
+	ReplicasMoved   int
+	BytesMoved      float64
+	Duration        time.Duration
+	// The complete input and output maps.
+	Input  *kafkazk.PartitionMap
+	Output *kafkazk.PartitionMap
+}
+
+// partitionChange describes the
+// reassignment of a partition.
+type partitionChange struct {
+	Topic     string
+	Partition int
+	Before    []int
+	After     []int
+	// A description of the change, as
+	// listed in the console output.
+	Change string
+}
+
+// brokerChange describes the before and
+// after state of a broker.
+type brokerChange struct {
+	ID                int
+	Leader            int
+	Follower          int
+	StorageFreeBefore float64
+	StorageFreeAfter  float64
+	Replace           bool
+}
+
+// Functions available to --output-template templates.
+var templateFuncs = template.FuncMap{
+	"join": func(s []string, sep string) string { return strings.Join(s, sep) },
+	"ids": func(ids []int) string {
+		var s []string
+		for _, id := range ids {
+			s = append(s, fmt.Sprint(id))
+		}
+		return strings.Join(s, ",")
+	},
+	"gb": func(b float64) string { return fmt.Sprintf("%.2fGB", b/div) },
+}
+
+// getOutputTemplate parses the template file specified via
+// --output-template. A nil *template.Template is returned if
+// no template was specified.
+func getOutputTemplate(cmd *cobra.Command) *template.Template {
+	path := cmd.Flag("output-template").Value.String()
+	if path == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Printf("Error reading output template: %s\n", err)
+		os.Exit(1)
+	}
+
+	t, err := template.New("output").Funcs(templateFuncs).Parse(string(data))
+	if err != nil {
+		fmt.Printf("Error parsing output template: %s\n", err)
+		os.Exit(1)
+	}
+
+	return t
+}
+
+// getPlanOutput takes the input and output PartitionMap, the input and
+// output BrokerMap, any warnings and a planStats and returns a planOutput.
+func getPlanOutput(pm1, pm2 *kafkazk.PartitionMap, bm1, bm2 kafkazk.BrokerMap, e errors, s planStats) planOutput {
+	o := planOutput{
+		Command:         s.command,
+		Partitions:      s.partitions,
+		PartitionsMoved: s.partitionsMoved,
+		ReplicasMoved:   s.replicasMoved,
+		BytesMoved:      s.bytesMoved,
+		Duration:        s.duration,
+		Input:           pm1,
+		Output:          pm2,
+	}
+
+	topics := map[string]struct{}{}
+	for i := range pm1.Partitions {
+		p1, p2 := pm1.Partitions[i], pm2.Partitions[i]
+		topics[p1.Topic] = struct{}{}
+
+		o.Changes = append(o.Changes, partitionChange{
+			Topic:     p1.Topic,
+			Partition: p1.Partition,
+			Before:    p1.Replicas,
+			After:     p2.Replicas,
+			Change:    whatChanged(p1.Replicas, p2.Replicas),
+		})
+	}
+
+	for t := range topics {
+		o.Topics = append(o.Topics, t)
+	}
+
+	sort.Strings(o.Topics)
+
+	for _, use := range pm2.UseStats().List() {
+		b := brokerChange{ID: use.ID, Leader: use.Leader, Follower: use.Follower}
+		if broker, exists := bm1[use.ID]; exists {
+			b.StorageFreeBefore = broker.StorageFree
+		}
+		if broker, exists := bm2[use.ID]; exists {
+			b.StorageFreeAfter = broker.StorageFree
+			b.Replace = broker.Replace
+		}
+		o.Brokers = append(o.Brokers, b)
+	}
+
+	for _, err := range e {
+		o.Warnings = append(o.Warnings, err.Error())
+	}
+
+	sort.Strings(o.Warnings)
+
+	return o
+}
+
+// renderPlanOutput executes the template with the planOutput. The
+// output is written to the --output-template-file path if set,
+// otherwise to stdout.
+func renderPlanOutput(cmd *cobra.Command, t *template.Template, o planOutput) {
+	if t == nil {
+		return
+	}
+
+	var out = os.Stdout
+
+	if path := cmd.Flag("output-template-file").Value.String(); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			fmt.Printf("Error writing template output: %s\n", err)
+			os.Exit(1)
+		}
+
+		defer f.Close()
+		out = f
+	} else {
+		fmt.Println()
+	}
+
+	if err := t.Execute(out, o); err != nil {
+		fmt.Printf("Error rendering output template: %s\n", err)
+		os.Exit(1)
+	}
+}
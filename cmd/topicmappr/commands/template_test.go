@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+func TestPlanOutputTemplate(t *testing.T) {
+	pm1, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test","partition":0,"replicas":[1001,1002]},
+    {"topic":"test","partition":1,"replicas":[1002,1001]}]}`)
+
+	pm2, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test","partition":0,"replicas":[1001,1003]},
+    {"topic":"test","partition":1,"replicas":[1002,1001]}]}`)
+
+	s := planStats{command: "rebuild", partitions: 2, partitionsMoved: 1}
+	o := getPlanOutput(pm1, pm2, kafkazk.BrokerMap{}, kafkazk.BrokerMap{}, nil, s)
+
+	tmpl := template.Must(template.New("test").Funcs(templateFuncs).Parse(
+		`{{.Command}} {{join .Topics ","}}: {{.PartitionsMoved}}/{{.Partitions}}
+{{range .Changes}}{{if ne .Change "no-op"}}{{.Topic}} p{{.Partition}}: {{ids .Before}} -> {{ids .After}}{{end}}{{end}}`))
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, o); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "rebuild test: 1/2\ntest p0: 1001,1002 -> 1001,1003"
+	if buf.String() != expected {
+		t.Errorf("Expected output:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	if len(o.Brokers) != 3 {
+		t.Errorf("Expected 3 brokers, got %d", len(o.Brokers))
+	}
+}
@@ -0,0 +1,201 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+// Registry topic tag keys holding placement constraints.
+const (
+	tagPlacementBrokerTags      = "placement.broker_tags"
+	tagPlacementExcludedBrokers = "placement.excluded_brokers"
+	tagPlacementReplication     = "placement.replication"
+)
+
+// topicConstraintsConfig describes the placement constraints for a topic
+// as provided via the --topic-constraints file or registry topic tags.
+type topicConstraintsConfig struct {
+	// Broker tags (e.g. "pool:ssd") that
+	// brokers must match to hold replicas.
+	BrokerTags      string `json:"broker_tags"`
+	ExcludedBrokers []int  `json:"excluded_brokers"`
+	Replication     int    `json:"replication"`
+}
+
+// topicConstraintsFromTags takes a topic's registry tags and returns
+// the topicConstraintsConfig from any placement tags.
+func topicConstraintsFromTags(tags map[string]string) (topicConstraintsConfig, error) {
+	var c topicConstraintsConfig
+
+	c.BrokerTags = tags[tagPlacementBrokerTags]
+
+	for _, s := range strings.FieldsFunc(tags[tagPlacementExcludedBrokers], isListDelim) {
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			return c, fmt.Errorf("invalid %s '%s'", tagPlacementExcludedBrokers, s)
+		}
+		c.ExcludedBrokers = append(c.ExcludedBrokers, id)
+	}
+
+	if s := tags[tagPlacementReplication]; s != "" {
+		r, err := strconv.Atoi(s)
+		if err != nil || r < 1 {
+			return c, fmt.Errorf("invalid %s '%s'", tagPlacementReplication, s)
+		}
+		c.Replication = r
+	}
+
+	return c, nil
+}
+
+// merge returns the topicConstraintsConfig with all
+// non-zero fields of the topicConstraintsConfig o applied.
+func (c topicConstraintsConfig) merge(o topicConstraintsConfig) topicConstraintsConfig {
+	if o.BrokerTags != "" {
+		c.BrokerTags = o.BrokerTags
+	}
+
+	if o.ExcludedBrokers != nil {
+		c.ExcludedBrokers = o.ExcludedBrokers
+	}
+
+	if o.Replication != 0 {
+		c.Replication = o.Replication
+	}
+
+	return c
+}
+
+// loadTopicConstraints looks up the placement constraints for all topics in
+// the PartitionMap, storing them at Config.topicConstraints. Constraints are
+// read from registry topic tags if zk is non-nil and from the file specified
+// via --topic-constraints, which takes precedence. The file is a JSON object,
+// e.g. {"topic": {"broker_tags": "pool:ssd", "excluded_brokers": [1001],
+// "replication": 3}}. Constrained topics are printed.
+func loadTopicConstraints(cmd *cobra.Command, zk kafkazk.Handler, pm *kafkazk.PartitionMap) {
+	configs := map[string]topicConstraintsConfig{}
+
+	var topics []string
+	for _, p := range pm.Partitions {
+		if _, exists := configs[p.Topic]; !exists {
+			configs[p.Topic] = topicConstraintsConfig{}
+			topics = append(topics, p.Topic)
+		}
+	}
+
+	sort.Strings(topics)
+
+	// Registry topic tags.
+	if zk != nil {
+		for _, t := range topics {
+			tags, err := storedTags(cmd, zk, "topic", t)
+			if err != nil {
+				fmt.Printf("Error fetching topic tags: %s\n", err)
+				os.Exit(1)
+			}
+
+			c, err := topicConstraintsFromTags(tags)
+			if err != nil {
+				fmt.Printf("Error parsing topic tags for %s: %s\n", t, err)
+				os.Exit(1)
+			}
+
+			configs[t] = c
+		}
+	}
+
+	// The --topic-constraints file.
+	if path, _ := cmd.Flags().GetString("topic-constraints"); path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Printf("Error reading topic constraints: %s\n", err)
+			os.Exit(1)
+		}
+
+		file := map[string]topicConstraintsConfig{}
+		if err := json.Unmarshal(data, &file); err != nil {
+			fmt.Printf("Error parsing topic constraints: %s\n", err)
+			os.Exit(1)
+		}
+
+		for t, c := range file {
+			if _, exists := configs[t]; exists {
+				configs[t] = configs[t].merge(c)
+			}
+		}
+	}
+
+	// Resolve broker tags to broker IDs. Topics
+	// commonly share tags; each is resolved once.
+	matching := map[string][]int{}
+	tcm := kafkazk.TopicConstraintsMap{}
+
+	for _, t := range topics {
+		c := configs[t]
+		if c.BrokerTags == "" && c.ExcludedBrokers == nil && c.Replication == 0 {
+			continue
+		}
+
+		tc := kafkazk.TopicConstraints{
+			ExcludedBrokers: c.ExcludedBrokers,
+			Replication:     c.Replication,
+		}
+
+		if c.BrokerTags != "" {
+			if zk == nil {
+				fmt.Println("[ERROR] topic broker tag constraints require ZooKeeper")
+				os.Exit(1)
+			}
+
+			if _, exists := matching[c.BrokerTags]; !exists {
+				want, err := parseBrokerTags(c.BrokerTags)
+				if err != nil {
+					fmt.Printf("Error parsing broker tags for %s: %s\n", t, err)
+					os.Exit(1)
+				}
+				matching[c.BrokerTags] = brokersMatchingTags(cmd, zk, want)
+			}
+
+			tc.Brokers = matching[c.BrokerTags]
+		}
+
+		tcm[t] = tc
+	}
+
+	if len(tcm) == 0 {
+		return
+	}
+
+	Config.topicConstraints = tcm
+
+	fmt.Printf("\nTopic constraints:\n")
+	for _, t := range topics {
+		if _, exists := tcm[t]; !exists {
+			continue
+		}
+
+		c := configs[t]
+
+		var desc []string
+		if c.BrokerTags != "" {
+			desc = append(desc, fmt.Sprintf("brokers %v (tags %s)", tcm[t].Brokers, c.BrokerTags))
+		}
+		if c.ExcludedBrokers != nil {
+			desc = append(desc, fmt.Sprintf("excluded brokers %v", c.ExcludedBrokers))
+		}
+		if c.Replication != 0 {
+			desc = append(desc, fmt.Sprintf("replication %d", c.Replication))
+		}
+
+		fmt.Printf("%s%s: %s\n", indent, t, strings.Join(desc, ", "))
+	}
+}
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"testing"
+)
+
+func TestTopicConstraintsFromTags(t *testing.T) {
+	c, err := topicConstraintsFromTags(map[string]string{
+		"owner":                     "team",
+		tagPlacementBrokerTags:      "pool=ssd",
+		tagPlacementExcludedBrokers: "1001 1002",
+		tagPlacementReplication:     "3",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.BrokerTags != "pool=ssd" || len(c.ExcludedBrokers) != 2 || c.ExcludedBrokers[1] != 1002 || c.Replication != 3 {
+		t.Errorf("Unexpected constraints: %+v", c)
+	}
+
+	// Topics without placement tags are unconstrained.
+	c, _ = topicConstraintsFromTags(map[string]string{"owner": "team"})
+	if c.BrokerTags != "" || c.ExcludedBrokers != nil || c.Replication != 0 {
+		t.Errorf("Unexpected constraints: %+v", c)
+	}
+
+	for _, tags := range []map[string]string{
+		{tagPlacementExcludedBrokers: "1001,a"},
+		{tagPlacementReplication: "0"},
+	} {
+		if _, err := topicConstraintsFromTags(tags); err == nil {
+			t.Errorf("Expected error for tags %v", tags)
+		}
+	}
+}
+
+func TestTopicConstraintsConfigMerge(t *testing.T) {
+	c := topicConstraintsConfig{BrokerTags: "pool:general", Replication: 2}
+	c = c.merge(topicConstraintsConfig{Replication: 3, ExcludedBrokers: []int{1001}})
+
+	if c.BrokerTags != "pool:general" || c.Replication != 3 || len(c.ExcludedBrokers) != 1 {
+		t.Errorf("Unexpected constraints: %+v", c)
+	}
+}
@@ -6,4 +6,5 @@ require (
 	github.com/jamiealquiza/envy v1.1.0
 	github.com/samuel/go-zookeeper v0.0.0-20190810000440-0ceca61e4d75
 	github.com/spf13/cobra v0.0.5
+	github.com/spf13/pflag v1.0.3
 )
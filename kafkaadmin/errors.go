@@ -0,0 +1,99 @@
+package kafkaadmin
+
+import (
+	"fmt"
+)
+
+// errorNames maps Kafka error codes
+// commonly returned by config, reassignment,
+// election, group, offset, produce, fetch,
+// topic creation and deletion and SASL requests.
+var errorNames = map[int16]string{
+	1:  "OFFSET_OUT_OF_RANGE",
+	2:  "CORRUPT_MESSAGE",
+	3:  "UNKNOWN_TOPIC_OR_PARTITION",
+	6:  "NOT_LEADER_FOR_PARTITION",
+	10: "MESSAGE_TOO_LARGE",
+	14: "COORDINATOR_LOAD_IN_PROGRESS",
+	15: "COORDINATOR_NOT_AVAILABLE",
+	16: "NOT_COORDINATOR",
+	17: "INVALID_TOPIC_EXCEPTION",
+	22: "ILLEGAL_GENERATION",
+	25: "UNKNOWN_MEMBER_ID",
+	27: "REBALANCE_IN_PROGRESS",
+	29: "TOPIC_AUTHORIZATION_FAILED",
+	30: "GROUP_AUTHORIZATION_FAILED",
+	31: "CLUSTER_AUTHORIZATION_FAILED",
+	33: "UNSUPPORTED_SASL_MECHANISM",
+	34: "ILLEGAL_SASL_STATE",
+	35: "UNSUPPORTED_VERSION",
+	36: "TOPIC_ALREADY_EXISTS",
+	37: "INVALID_PARTITIONS",
+	38: "INVALID_REPLICATION_FACTOR",
+	40: "INVALID_CONFIG",
+	41: "NOT_CONTROLLER",
+	42: "INVALID_REQUEST",
+	44: "POLICY_VIOLATION",
+	58: "SASL_AUTHENTICATION_FAILED",
+	69: "GROUP_ID_NOT_FOUND",
+	73: "TOPIC_DELETION_DISABLED",
+	80: "PREFERRED_LEADER_NOT_AVAILABLE",
+	84: "ELECTION_NOT_NEEDED",
+	85: "NO_REASSIGNMENT_IN_PROGRESS",
+}
+
+// errUnknownTopicOrPartition is the error code
+// returned for topics that don't exist.
+const errUnknownTopicOrPartition = 3
+
+// errNotController is the error code returned for
+// requests sent to a broker that isn't the controller.
+const errNotController = 41
+
+// errElectionNotNeeded is the error code returned for
+// partitions already led by their preferred leader.
+const errElectionNotNeeded = 84
+
+// Error is a Kafka protocol error.
+type Error struct {
+	Code    int16
+	Message string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	name, known := errorNames[e.Code]
+	if !known {
+		name = fmt.Sprintf("error code %d", e.Code)
+	}
+
+	if e.Message != "" {
+		return fmt.Sprintf("%s: %s", name, e.Message)
+	}
+
+	return name
+}
+
+// ResourceError wraps an Error
+// for a specific resource.
+type ResourceError struct {
+	Type ResourceType
+	Name string
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *ResourceError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Type, e.Name, e.Err)
+}
+
+// ErrUnknownBroker is returned when a broker
+// isn't found in the cluster metadata.
+type ErrUnknownBroker struct {
+	ID int
+}
+
+// Error implements the error interface.
+func (e ErrUnknownBroker) Error() string {
+	return fmt.Sprintf("Broker %d not found in cluster metadata", e.ID)
+}
@@ -0,0 +1,247 @@
+package kafkaadmin
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+)
+
+// Special ListOffsets timestamps.
+const (
+	OffsetLatest   int64 = -1
+	OffsetEarliest int64 = -2
+)
+
+// consumerProtocolType is the protocol type of groups
+// using the Kafka consumer group protocol.
+const consumerProtocolType = "consumer"
+
+// Offsets is a map of topic to partition to offset.
+type Offsets map[string]map[int]int64
+
+// GroupListing is a group from a ListGroups response.
+type GroupListing struct {
+	GroupID      string
+	ProtocolType string
+}
+
+// GroupMember is a member of a group. Assignment holds the
+// topic partitions assigned to the member for consumer groups.
+type GroupMember struct {
+	MemberID   string
+	ClientID   string
+	ClientHost string
+	Assignment map[string][]int
+}
+
+// GroupDescription describes a group.
+type GroupDescription struct {
+	GroupID      string
+	State        string
+	ProtocolType string
+	Protocol     string
+	Members      []GroupMember
+}
+
+// ListGroups returns all groups in the cluster, sorted by group ID.
+// Each broker only lists the groups it coordinates, so all brokers
+// are queried.
+func (c *Client) ListGroups() ([]GroupListing, error) {
+	if err := c.refreshMetadata(); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]struct{}{}
+	var groups []GroupListing
+
+	for _, addr := range c.Brokers() {
+		d, err := c.request(addr, apiListGroups, listGroupsVersion, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		gs, err := decodeListGroupsResponse(d)
+		if err != nil {
+			return nil, fmt.Errorf("Error listing groups on %s: %s", addr, err)
+		}
+
+		for _, g := range gs {
+			if _, exists := seen[g.GroupID]; !exists {
+				seen[g.GroupID] = struct{}{}
+				groups = append(groups, g)
+			}
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].GroupID < groups[j].GroupID
+	})
+
+	return groups, nil
+}
+
+// DescribeGroup returns a *GroupDescription for the group. Groups that
+// don't exist are described with the state "Dead" and no members.
+func (c *Client) DescribeGroup(group string) (*GroupDescription, error) {
+	addr, err := c.coordinator(group)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := c.request(addr, apiDescribeGroups, describeGroupsVersion, encodeDescribeGroupsRequest([]string{group}))
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := decodeDescribeGroupsResponse(d)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(groups) != 1 {
+		return nil, fmt.Errorf("Expected 1 group description, got %d", len(groups))
+	}
+
+	return groups[0], nil
+}
+
+// FetchOffsets returns the committed offsets of the group for all
+// partitions that the group has committed offsets for.
+func (c *Client) FetchOffsets(group string) (Offsets, error) {
+	addr, err := c.coordinator(group)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := c.request(addr, apiOffsetFetch, offsetFetchVersion, encodeOffsetFetchRequest(group))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeOffsetFetchResponse(d)
+}
+
+// CommitOffsets commits the offsets of the group. The coordinator rejects
+// commits for groups with active members (UNKNOWN_MEMBER_ID).
+func (c *Client) CommitOffsets(group string, offsets Offsets) error {
+	addr, err := c.coordinator(group)
+	if err != nil {
+		return err
+	}
+
+	d, err := c.request(addr, apiOffsetCommit, offsetCommitVersion, encodeOffsetCommitRequest(group, offsets))
+	if err != nil {
+		return err
+	}
+
+	return decodeOffsetCommitResponse(d)
+}
+
+// ListOffsets takes a map of topic to partitions and returns the offset of
+// each partition at timestamp (in milliseconds), or the OffsetLatest or
+// OffsetEarliest offset. Requests are sent to the leader of each partition.
+func (c *Client) ListOffsets(partitions map[string][]int, timestamp int64) (Offsets, error) {
+	leaders, err := c.leaders(partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group partitions by leader.
+	byLeader := map[string]map[string][]int{}
+	for t, ps := range partitions {
+		for _, p := range ps {
+			l, exists := leaders[t][int32(p)]
+			if !exists || l < 0 {
+				return nil, fmt.Errorf("No leader for %s partition %d", t, p)
+			}
+
+			addr, exists := c.brokerAddr(l)
+			if !exists {
+				return nil, ErrUnknownBroker{ID: int(l)}
+			}
+
+			if byLeader[addr] == nil {
+				byLeader[addr] = map[string][]int{}
+			}
+			byLeader[addr][t] = append(byLeader[addr][t], p)
+		}
+	}
+
+	offsets := Offsets{}
+
+	for addr, ps := range byLeader {
+		d, err := c.request(addr, apiListOffsets, listOffsetsVersion, encodeListOffsetsRequest(ps, timestamp))
+		if err != nil {
+			return nil, err
+		}
+
+		o, err := decodeListOffsetsResponse(d)
+		if err != nil {
+			return nil, err
+		}
+
+		for t, po := range o {
+			if offsets[t] == nil {
+				offsets[t] = map[int]int64{}
+			}
+			for p, offset := range po {
+				offsets[t][p] = offset
+			}
+		}
+	}
+
+	return offsets, nil
+}
+
+// coordinator returns the address of the coordinator of a group.
+func (c *Client) coordinator(group string) (string, error) {
+	addr, err := c.addrFor(ResourceTopic, "")
+	if err != nil {
+		return "", err
+	}
+
+	d, err := c.request(addr, apiFindCoordinator, findCoordinatorVersion, encodeFindCoordinatorRequest(group))
+	if err != nil {
+		return "", err
+	}
+
+	b, err := decodeFindCoordinatorResponse(d)
+	if err != nil {
+		return "", fmt.Errorf("Error finding coordinator for group %s: %s", group, err)
+	}
+
+	return net.JoinHostPort(b.host, strconv.Itoa(int(b.port))), nil
+}
+
+// leaders returns the partition leaders of the topics in
+// partitions, refreshing the known brokers in the process.
+func (c *Client) leaders(partitions map[string][]int) (map[string]map[int32]int32, error) {
+	var topics []string
+	for t := range partitions {
+		topics = append(topics, t)
+	}
+
+	addr, err := c.addrFor(ResourceTopic, "")
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := c.request(addr, apiMetadata, metadataVersion, encodeTopicMetadataRequest(topics))
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := decodeMetadataResponse(d)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Lock()
+	for _, b := range m.brokers {
+		c.brokers[b.id] = net.JoinHostPort(b.host, strconv.Itoa(int(b.port)))
+	}
+	c.Unlock()
+
+	return m.leaders, nil
+}
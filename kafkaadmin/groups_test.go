@@ -0,0 +1,184 @@
+package kafkaadmin
+
+import (
+	"fmt"
+	"testing"
+)
+
+// newGroupsMockBroker returns a *mockBroker with a consumer
+// group "test_group" consuming "test_topic", a group with
+// another protocol type and an empty consumer group.
+func newGroupsMockBroker(t *testing.T) *mockBroker {
+	b := newMockBroker(t, 1001)
+
+	b.groups = map[string]*GroupDescription{
+		"test_group": &GroupDescription{
+			State:        "Stable",
+			ProtocolType: "consumer",
+			Protocol:     "range",
+			Members: []GroupMember{
+				GroupMember{
+					MemberID:   "consumer-1-abc",
+					ClientID:   "consumer-1",
+					ClientHost: "/10.0.0.1",
+					Assignment: map[string][]int{"test_topic": []int{0, 1}},
+				},
+			},
+		},
+		"connect_group": &GroupDescription{State: "Stable", ProtocolType: "connect"},
+		"empty_group":   &GroupDescription{State: "Empty", ProtocolType: "consumer"},
+	}
+
+	b.committed = map[string]Offsets{
+		"test_group": Offsets{"test_topic": map[int]int64{0: 80, 1: 100}},
+	}
+
+	b.logEnd = Offsets{"test_topic": map[int]int64{0: 100, 1: 100}}
+
+	return b
+}
+
+func TestListGroups(t *testing.T) {
+	b := newGroupsMockBroker(t)
+	defer b.close()
+
+	c, err := NewClient(Config{BootstrapServers: b.addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups, err := c.ListGroups()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "[{connect_group connect} {empty_group consumer} {test_group consumer}]"
+	if fmt.Sprint(groups) != expected {
+		t.Errorf("Expected groups %s, got %v", expected, groups)
+	}
+}
+
+func TestDescribeGroup(t *testing.T) {
+	b := newGroupsMockBroker(t)
+	defer b.close()
+
+	c, err := NewClient(Config{BootstrapServers: b.addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := c.DescribeGroup("test_group")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.GroupID != "test_group" || g.State != "Stable" || g.Protocol != "range" || len(g.Members) != 1 {
+		t.Fatalf("Unexpected group description %+v", g)
+	}
+
+	m := g.Members[0]
+	if m.MemberID != "consumer-1-abc" || m.ClientID != "consumer-1" || m.ClientHost != "/10.0.0.1" {
+		t.Errorf("Unexpected member %+v", m)
+	}
+
+	if fmt.Sprint(m.Assignment) != "map[test_topic:[0 1]]" {
+		t.Errorf("Unexpected assignment %v", m.Assignment)
+	}
+
+	// Unknown groups are dead.
+	g, err = c.DescribeGroup("unknown_group")
+	if err != nil || g.State != "Dead" {
+		t.Errorf("Expected a dead group, got %+v, %v", g, err)
+	}
+}
+
+func TestFetchOffsets(t *testing.T) {
+	b := newGroupsMockBroker(t)
+	defer b.close()
+
+	c, err := NewClient(Config{BootstrapServers: b.addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o, err := c.FetchOffsets("test_group")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if o["test_topic"][0] != 80 || o["test_topic"][1] != 100 {
+		t.Errorf("Unexpected offsets %v", o)
+	}
+
+	o, err = c.FetchOffsets("empty_group")
+	if err != nil || len(o) != 0 {
+		t.Errorf("Expected no offsets, got %v, %v", o, err)
+	}
+}
+
+func TestCommitOffsets(t *testing.T) {
+	b := newGroupsMockBroker(t)
+	defer b.close()
+
+	c, err := NewClient(Config{BootstrapServers: b.addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	offsets := Offsets{"test_topic": map[int]int64{0: 10, 1: 20}}
+	if err := c.CommitOffsets("empty_group", offsets); err != nil {
+		t.Fatal(err)
+	}
+
+	o, _ := c.FetchOffsets("empty_group")
+	if o["test_topic"][0] != 10 || o["test_topic"][1] != 20 {
+		t.Errorf("Unexpected offsets %v", o)
+	}
+
+	// Groups with active members.
+	err = c.CommitOffsets("test_group", offsets)
+	if err == nil || err.Error() != "topic test_topic: UNKNOWN_MEMBER_ID" {
+		t.Errorf("Expected UNKNOWN_MEMBER_ID error, got %v", err)
+	}
+}
+
+func TestListOffsets(t *testing.T) {
+	b := newGroupsMockBroker(t)
+	defer b.close()
+
+	c, err := NewClient(Config{BootstrapServers: b.addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	partitions := map[string][]int{"test_topic": []int{0, 1}}
+
+	o, err := c.ListOffsets(partitions, OffsetLatest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if o["test_topic"][0] != 100 || o["test_topic"][1] != 100 {
+		t.Errorf("Unexpected offsets %v", o)
+	}
+
+	o, err = c.ListOffsets(partitions, OffsetEarliest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if o["test_topic"][0] != 0 || o["test_topic"][1] != 0 {
+		t.Errorf("Unexpected offsets %v", o)
+	}
+
+	// Unknown topic.
+	_, err = c.ListOffsets(map[string][]int{"unknown_topic": []int{0}}, OffsetLatest)
+	if err == nil || err.Error() != "topic unknown_topic: UNKNOWN_TOPIC_OR_PARTITION" {
+		t.Errorf("Expected UNKNOWN_TOPIC_OR_PARTITION error, got %v", err)
+	}
+
+	// Unknown partition.
+	if _, err = c.ListOffsets(map[string][]int{"test_topic": []int{5}}, OffsetLatest); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
@@ -0,0 +1,559 @@
+package kafkaadmin
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// ErrNotSupported is returned by Handler methods
+// without a Kafka Admin API equivalent.
+var ErrNotSupported = errors.New("Not supported via the Kafka Admin API")
+
+// defaultWatchInterval is the interval at which
+// Handler watches poll the cluster metadata.
+const defaultWatchInterval = 10 * time.Second
+
+// Handler implements the kafkazk.Handler interface via the Kafka Admin API,
+// allowing kafka-kit tools to operate on clusters without ZooKeeper access,
+// such as KRaft clusters. Topic and broker state is read from the cluster
+// metadata, configs are described and altered with DescribeConfigs and
+// IncrementalAlterConfigs, and partitions are reassigned and preferred
+// leaders elected with AlterPartitionReassignments and ElectLeaders.
+// Requires Kafka 2.4+.
+//
+// ZooKeeper specific operations have no equivalent: znode reads return a
+// kafkazk.ErrNoNode, while znode writes, config change notifications and
+// quotas return an ErrNotSupported. Broker and partition metrics are read
+// from the Metrics source if set, otherwise they also return an
+// ErrNotSupported. Watches are implemented by polling the cluster metadata.
+type Handler struct {
+	*Client
+	// Metrics is the optional source of the broker and
+	// partition metrics stored by metricsfetcher, such as
+	// a *kafkazk.ZKHandler for the ZooKeeper ensemble
+	// metricsfetcher writes to. It's closed by Close.
+	Metrics MetricsSource
+
+	watchInterval time.Duration
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// MetricsSource reads the broker and partition
+// metrics stored by metricsfetcher.
+type MetricsSource interface {
+	GetBrokerMetrics() (kafkazk.BrokerMetricsMap, error)
+	GetAllPartitionMeta() (kafkazk.PartitionMetaMap, error)
+	MaxMetaAge() (time.Duration, error)
+	Close()
+}
+
+// NewHandler takes a Config and returns a *Handler, initialized
+// with the cluster metadata from the bootstrap brokers.
+func NewHandler(c Config) (*Handler, error) {
+	client, err := NewClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Handler{
+		Client:        client,
+		watchInterval: defaultWatchInterval,
+		done:          make(chan struct{}),
+	}, nil
+}
+
+// errNoNode returns a kafkazk.ErrNoNode for
+// the (ZooKeeper equivalent) path p.
+func errNoNode(p string) error {
+	return kafkazk.NewErrNoNode(fmt.Sprintf("[%s] not found via the Kafka Admin API", p))
+}
+
+// Exists returns false for all paths; znodes
+// aren't available via the Kafka Admin API.
+func (h *Handler) Exists(p string) (bool, error) {
+	return false, nil
+}
+
+// Create returns an ErrNotSupported.
+func (h *Handler) Create(p string, d string) error {
+	return ErrNotSupported
+}
+
+// CreateSequential returns an ErrNotSupported.
+func (h *Handler) CreateSequential(p string, d string) error {
+	return ErrNotSupported
+}
+
+// CreateEphemeralSequential returns an ErrNotSupported.
+func (h *Handler) CreateEphemeralSequential(p string, d string) (string, error) {
+	return "", ErrNotSupported
+}
+
+// Set returns an ErrNotSupported.
+func (h *Handler) Set(p string, d string) error {
+	return ErrNotSupported
+}
+
+// Get returns a kafkazk.ErrNoNode for all paths.
+func (h *Handler) Get(p string) ([]byte, error) {
+	return nil, errNoNode(p)
+}
+
+// Delete returns an ErrNotSupported.
+func (h *Handler) Delete(p string) error {
+	return ErrNotSupported
+}
+
+// Children returns a kafkazk.ErrNoNode for all paths.
+func (h *Handler) Children(p string) ([]string, error) {
+	return nil, errNoNode(p)
+}
+
+// Close stops any watches and closes the Metrics source.
+func (h *Handler) Close() {
+	h.closeOnce.Do(func() {
+		close(h.done)
+
+		if h.Metrics != nil {
+			h.Metrics.Close()
+		}
+	})
+}
+
+// Ready returns true if a bootstrap broker responds.
+func (h *Handler) Ready() bool {
+	return h.Ping() == nil
+}
+
+// metadata returns the cluster metadata
+// for the metadata request body.
+func (h *Handler) metadata(body []byte) (*metadata, error) {
+	addr, err := h.addrFor(ResourceTopic, "")
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := h.request(addr, apiMetadata, metadataVersion, body)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := decodeMetadataResponse(d)
+
+	// Topics with errors, e.g. those
+	// being created, are omitted.
+	if _, ok := err.(*ResourceError); err != nil && !ok {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// topicMetadata returns the partition state of topic t. The metadata of
+// all topics is fetched, as requesting an unknown topic by name creates
+// it on clusters with auto.create.topics.enable set.
+func (h *Handler) topicMetadata(t string) (map[int32]partitionMetadata, error) {
+	m, err := h.metadata(encodeAllTopicsMetadataRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	partitions, exists := m.partitions[t]
+	if !exists {
+		return nil, errNoNode(fmt.Sprintf("/brokers/topics/%s", t))
+	}
+
+	return partitions, nil
+}
+
+// GetTopicState returns the TopicState for topic t.
+func (h *Handler) GetTopicState(t string) (*kafkazk.TopicState, error) {
+	partitions, err := h.topicMetadata(t)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := &kafkazk.TopicState{Partitions: map[string][]int{}}
+	for p, pm := range partitions {
+		ts.Partitions[strconv.Itoa(int(p))] = ints(pm.replicas)
+	}
+
+	return ts, nil
+}
+
+// GetTopicStateISR returns the TopicStateISR for topic t. Only the
+// leader and ISR of each PartitionState are populated.
+func (h *Handler) GetTopicStateISR(t string) (kafkazk.TopicStateISR, error) {
+	partitions, err := h.topicMetadata(t)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := kafkazk.TopicStateISR{}
+	for p, pm := range partitions {
+		ts[strconv.Itoa(int(p))] = kafkazk.PartitionState{
+			Leader: int(pm.leader),
+			ISR:    ints(pm.isr),
+		}
+	}
+
+	return ts, nil
+}
+
+// ElectPreferredLeaders triggers a preferred leader election for
+// the partitions in the kafkazk.PreferredReplicaElection.
+func (h *Handler) ElectPreferredLeaders(e kafkazk.PreferredReplicaElection) error {
+	partitions := map[string][]int{}
+	for _, p := range e.Partitions {
+		partitions[p.Topic] = append(partitions[p.Topic], p.Partition)
+	}
+
+	return h.ElectLeaders(partitions)
+}
+
+// GetReassignments returns all ongoing partition reassignments.
+// An empty Reassignments is returned if they can't be listed.
+func (h *Handler) GetReassignments() kafkazk.Reassignments {
+	r, err := h.ListPartitionReassignments()
+	if err != nil {
+		return kafkazk.Reassignments{}
+	}
+
+	return r
+}
+
+// ReassignPartitions takes a *kafkazk.PartitionMap and reassigns all
+// partitions in the map. As with the ZooKeeper handler, a
+// kafkazk.ErrReassignmentInProgress is returned if any reassignment
+// is already in progress.
+func (h *Handler) ReassignPartitions(pm *kafkazk.PartitionMap) error {
+	current, err := h.ListPartitionReassignments()
+	if err != nil {
+		return err
+	}
+
+	if len(current) > 0 {
+		return kafkazk.ErrReassignmentInProgress
+	}
+
+	r := kafkazk.Reassignments{}
+	for _, p := range pm.Partitions {
+		if r[p.Topic] == nil {
+			r[p.Topic] = map[int][]int{}
+		}
+		r[p.Topic][p.Partition] = p.Replicas
+	}
+
+	return h.AlterPartitionReassignments(r)
+}
+
+// DeleteTopic deletes topic t. Topics can only be deleted
+// if delete.topic.enable is set on the controller.
+func (h *Handler) DeleteTopic(t string) error {
+	errs, err := h.DeleteTopics([]string{t})
+	if err != nil {
+		return err
+	}
+
+	if err := errs[t]; err != nil {
+		return &ResourceError{Type: ResourceTopic, Name: t, Err: err}
+	}
+
+	return nil
+}
+
+// GetTopics takes a []*regexp.Regexp and returns a []string of all topic
+// names that match any of the provided regex.
+func (h *Handler) GetTopics(ts []*regexp.Regexp) ([]string, error) {
+	m, err := h.metadata(encodeAllTopicsMetadataRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	matchingTopics := []string{}
+
+	for topic := range m.partitions {
+		for _, topicRe := range ts {
+			if topicRe.MatchString(topic) {
+				matchingTopics = append(matchingTopics, topic)
+				break
+			}
+		}
+	}
+
+	sort.Strings(matchingTopics)
+
+	return matchingTopics, nil
+}
+
+// GetTopicConfig returns the *TopicConfig for topic t,
+// holding the dynamic config overrides of the topic.
+func (h *Handler) GetTopicConfig(t string) (*kafkazk.TopicConfig, error) {
+	configs, err := h.DescribeConfigs(ResourceTopic, t, nil)
+	if err != nil {
+		if unknownTopic(err) {
+			return nil, errNoNode(fmt.Sprintf("/config/topics/%s", t))
+		}
+		return nil, err
+	}
+
+	return &kafkazk.TopicConfig{
+		Version: 1,
+		Config:  dynamicConfigs(configs, SourceDynamicTopic),
+	}, nil
+}
+
+// GetConfigChanges returns an ErrNotSupported; config change
+// notifications are specific to ZooKeeper.
+func (h *Handler) GetConfigChanges(since int64) ([]kafkazk.ConfigChange, error) {
+	return nil, ErrNotSupported
+}
+
+// GetBrokerConfig takes a broker ID and returns the
+// dynamic configs of the broker as a *KafkaConfigData.
+func (h *Handler) GetBrokerConfig(id int) (*kafkazk.KafkaConfigData, error) {
+	configs, err := h.DescribeConfigs(ResourceBroker, strconv.Itoa(id), nil)
+	if err != nil {
+		if _, ok := err.(ErrUnknownBroker); ok {
+			return nil, errNoNode(fmt.Sprintf("/config/brokers/%d", id))
+		}
+		return nil, err
+	}
+
+	return &kafkazk.KafkaConfigData{
+		Version: 1,
+		Config:  dynamicConfigs(configs, SourceDynamicBroker),
+	}, nil
+}
+
+// GetAllBrokerMeta returns a BrokerMetaMap of all brokers in the cluster
+// metadata. If withMetrics is true, broker metrics are merged in from the
+// Metrics source, as with the ZooKeeper handler; an error is returned if
+// the Metrics source isn't set.
+func (h *Handler) GetAllBrokerMeta(withMetrics bool) (kafkazk.BrokerMetaMap, []error) {
+	var bmetrics kafkazk.BrokerMetricsMap
+	if withMetrics {
+		if h.Metrics == nil {
+			return nil, []error{fmt.Errorf("Error fetching broker metrics: %s", ErrNotSupported)}
+		}
+
+		var err error
+		if bmetrics, err = h.Metrics.GetBrokerMetrics(); err != nil {
+			return nil, []error{err}
+		}
+	}
+
+	m, err := h.metadata(encodeMetadataRequest())
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	bmm := kafkazk.BrokerMetaMap{}
+	for _, b := range m.brokers {
+		bmm[int(b.id)] = &kafkazk.BrokerMeta{
+			Host: b.host,
+			Port: int(b.port),
+			Rack: b.rack,
+		}
+	}
+
+	if !withMetrics {
+		return bmm, nil
+	}
+
+	var errs []error
+	for id, bm := range bmm {
+		metrics, exists := bmetrics[id]
+		if !exists {
+			errs = append(errs, fmt.Errorf("Metrics not found for broker %d", id))
+			bm.MetricsIncomplete = true
+			continue
+		}
+
+		bm.StorageFree = metrics.StorageFree
+		bm.StorageCapacity = metrics.StorageCapacity
+	}
+
+	return bmm, errs
+}
+
+// GetAllPartitionMeta returns the partition metrics from the Metrics
+// source, or an ErrNotSupported if it isn't set.
+func (h *Handler) GetAllPartitionMeta() (kafkazk.PartitionMetaMap, error) {
+	if h.Metrics == nil {
+		return nil, fmt.Errorf("Error fetching partition meta: %s", ErrNotSupported)
+	}
+
+	return h.Metrics.GetAllPartitionMeta()
+}
+
+// MaxMetaAge returns the age of the metrics from the Metrics
+// source, or an ErrNotSupported if it isn't set.
+func (h *Handler) MaxMetaAge() (time.Duration, error) {
+	if h.Metrics == nil {
+		return time.Nanosecond, ErrNotSupported
+	}
+
+	return h.Metrics.MaxMetaAge()
+}
+
+// GetPartitionMap takes a topic name. If the topic exists, the state of
+// the topic is fetched and returned as a *PartitionMap, with the target
+// replica sets of any partitions being reassigned.
+func (h *Handler) GetPartitionMap(t string) (*kafkazk.PartitionMap, error) {
+	ts, err := h.GetTopicState(t)
+	if err != nil {
+		return nil, err
+	}
+
+	re := h.GetReassignments()
+
+	pm := kafkazk.NewPartitionMap()
+	for partition, replicas := range ts.Partitions {
+		i, _ := strconv.Atoi(partition)
+		if r, exists := re[t][i]; exists {
+			replicas = r
+		}
+
+		pm.Partitions = append(pm.Partitions, kafkazk.Partition{
+			Topic:     t,
+			Partition: i,
+			Replicas:  replicas,
+		})
+	}
+
+	sort.Sort(pm.Partitions)
+
+	return pm, nil
+}
+
+// GetQuotas returns an ErrNotSupported.
+func (h *Handler) GetQuotas() (kafkazk.QuotaMap, error) {
+	return nil, ErrNotSupported
+}
+
+// SetQuotas returns an ErrNotSupported.
+func (h *Handler) SetQuotas(e kafkazk.QuotaEntity, q kafkazk.Quotas) (bool, error) {
+	return false, ErrNotSupported
+}
+
+// GetTopicStateW is GetTopicState, also returning a channel that's closed
+// when the topic state changes or the topic is deleted, as observed by
+// polling the cluster metadata.
+func (h *Handler) GetTopicStateW(t string) (*kafkazk.TopicState, <-chan struct{}, error) {
+	ts, err := h.GetTopicState(t)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changed := func() bool {
+		current, err := h.GetTopicState(t)
+		if _, noNode := err.(kafkazk.ErrNoNode); noNode {
+			return true
+		}
+
+		return err == nil && !reflect.DeepEqual(current, ts)
+	}
+
+	return ts, h.watch(changed), nil
+}
+
+// WatchBrokers returns a channel that's closed when a broker joins or
+// leaves the cluster metadata, or its address changes, as observed by
+// polling the cluster metadata.
+func (h *Handler) WatchBrokers() (<-chan struct{}, error) {
+	brokers := func() (map[int32]broker, error) {
+		m, err := h.metadata(encodeMetadataRequest())
+		if err != nil {
+			return nil, err
+		}
+
+		b := map[int32]broker{}
+		for _, br := range m.brokers {
+			b[br.id] = br
+		}
+
+		return b, nil
+	}
+
+	initial, err := brokers()
+	if err != nil {
+		return nil, err
+	}
+
+	changed := func() bool {
+		current, err := brokers()
+		return err == nil && !reflect.DeepEqual(current, initial)
+	}
+
+	return h.watch(changed), nil
+}
+
+// watch returns a channel that's closed once changed returns true,
+// called every watchInterval until the Handler is closed.
+func (h *Handler) watch(changed func() bool) <-chan struct{} {
+	ch := make(chan struct{})
+
+	go func() {
+		t := time.NewTicker(h.watchInterval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-h.done:
+				return
+			case <-t.C:
+				if changed() {
+					close(ch)
+					return
+				}
+			}
+		}
+	}()
+
+	return ch
+}
+
+// dynamicConfigs returns the values of the
+// configs set from the ConfigSource s.
+func dynamicConfigs(configs map[string]ConfigEntry, s ConfigSource) map[string]string {
+	c := map[string]string{}
+	for name, e := range configs {
+		if e.Source == s {
+			c[name] = e.Value
+		}
+	}
+
+	return c
+}
+
+// unknownTopic returns whether err is
+// an UNKNOWN_TOPIC_OR_PARTITION error.
+func unknownTopic(err error) bool {
+	if re, ok := err.(*ResourceError); ok {
+		err = re.Err
+	}
+
+	e, ok := err.(*Error)
+
+	return ok && e.Code == errUnknownTopicOrPartition
+}
+
+func ints(v []int32) []int {
+	i := make([]int, len(v))
+	for n := range v {
+		i[n] = int(v[n])
+	}
+
+	return i
+}
@@ -0,0 +1,348 @@
+package kafkaadmin
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// newTestHandler returns a *Handler for a *mockBroker
+// with a topic of two partitions replicated on 1001
+// and 1002.
+func newTestHandler(t *testing.T) (*Handler, *mockBroker) {
+	b := newMockBroker(t, 1001)
+
+	b.logEnd = Offsets{"test_topic": {0: 0, 1: 0}, "other": {0: 0}}
+	b.replicas = map[string]map[int32][]int32{
+		"test_topic": {
+			0: []int32{1001, 1002},
+			1: []int32{1002, 1001},
+		},
+	}
+
+	h, err := NewHandler(Config{BootstrapServers: b.addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h, b
+}
+
+func TestHandlerZNodes(t *testing.T) {
+	h, b := newTestHandler(t)
+	defer b.close()
+
+	var _ kafkazk.Handler = h
+
+	if !h.Ready() {
+		t.Error("Expected handler to be ready")
+	}
+
+	if _, err := h.Get("/brokers/ids"); err == nil {
+		t.Error("Expected non-nil error")
+	} else if _, ok := err.(kafkazk.ErrNoNode); !ok {
+		t.Errorf("Expected a kafkazk.ErrNoNode, got %T", err)
+	}
+
+	if err := h.Create("/path", ""); err != ErrNotSupported {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+}
+
+func TestHandlerTopicState(t *testing.T) {
+	h, b := newTestHandler(t)
+	defer b.close()
+
+	ts, err := h.GetTopicState("test_topic")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]int{
+		"0": []int{1001, 1002},
+		"1": []int{1002, 1001},
+	}
+
+	if fmt.Sprint(ts.Partitions) != fmt.Sprint(expected) {
+		t.Errorf("Expected partitions %v, got %v", expected, ts.Partitions)
+	}
+
+	isr, err := h.GetTopicStateISR("test_topic")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if isr["1"].Leader != 1002 || fmt.Sprint(isr["1"].ISR) != "[1002 1001]" {
+		t.Errorf("Unexpected partition state %v", isr["1"])
+	}
+
+	if _, err := h.GetTopicState("unknown"); err == nil {
+		t.Error("Expected non-nil error")
+	} else if _, ok := err.(kafkazk.ErrNoNode); !ok {
+		t.Errorf("Expected a kafkazk.ErrNoNode, got %T", err)
+	}
+
+	topics, err := h.GetTopics([]*regexp.Regexp{regexp.MustCompile("test_.*"), regexp.MustCompile("^other$")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(topics) != "[other test_topic]" {
+		t.Errorf("Unexpected topics %v", topics)
+	}
+
+	// Partition maps have the target
+	// replicas of reassigning partitions.
+	b.reassignments = map[string]map[int32]mockReassignment{
+		"test_topic": {
+			1: mockReassignment{
+				replicas: []int32{1002, 1001, 1003},
+				adding:   []int32{1003},
+				removing: []int32{1001},
+			},
+		},
+	}
+
+	pm, err := h.GetPartitionMap("test_topic")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedPm := kafkazk.PartitionList{
+		{Topic: "test_topic", Partition: 0, Replicas: []int{1001, 1002}},
+		{Topic: "test_topic", Partition: 1, Replicas: []int{1002, 1003}},
+	}
+
+	if fmt.Sprint(pm.Partitions) != fmt.Sprint(expectedPm) {
+		t.Errorf("Expected partitions %v, got %v", expectedPm, pm.Partitions)
+	}
+}
+
+func TestHandlerConfigs(t *testing.T) {
+	h, b := newTestHandler(t)
+	defer b.close()
+
+	b.configs["test_topic"] = map[string]string{"retention.ms": "3600000"}
+	b.configs["1001"] = map[string]string{"leader.replication.throttled.rate": "100000"}
+
+	tc, err := h.GetTopicConfig("test_topic")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only dynamic configs are returned.
+	if len(tc.Config) != 1 || tc.Config["retention.ms"] != "3600000" {
+		t.Errorf("Unexpected topic config %v", tc.Config)
+	}
+
+	bc, err := h.GetBrokerConfig(1001)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bc.Config) != 1 || bc.Config["leader.replication.throttled.rate"] != "100000" {
+		t.Errorf("Unexpected broker config %v", bc.Config)
+	}
+
+	if _, err := h.GetBrokerConfig(1002); err == nil {
+		t.Error("Expected non-nil error")
+	} else if _, ok := err.(kafkazk.ErrNoNode); !ok {
+		t.Errorf("Expected a kafkazk.ErrNoNode, got %T", err)
+	}
+
+	changed, err := h.UpdateKafkaConfig(kafkazk.KafkaConfig{
+		Type:    "topic",
+		Name:    "test_topic",
+		Configs: [][2]string{{"retention.ms", ""}},
+	})
+
+	if err != nil || !changed {
+		t.Errorf("Expected config to be changed, got %v, %v", changed, err)
+	}
+}
+
+func TestHandlerBrokerMeta(t *testing.T) {
+	h, b := newTestHandler(t)
+	defer b.close()
+
+	bmm, errs := h.GetAllBrokerMeta(false)
+	if errs != nil {
+		t.Fatal(errs)
+	}
+
+	if len(bmm) != 1 || bmm[1001] == nil || b.addr() != fmt.Sprintf("%s:%d", bmm[1001].Host, bmm[1001].Port) {
+		t.Errorf("Unexpected broker metadata %v", bmm)
+	}
+
+	if _, errs := h.GetAllBrokerMeta(true); len(errs) == 0 {
+		t.Error("Expected errors fetching broker metrics")
+	}
+
+	if _, err := h.GetAllPartitionMeta(); err == nil {
+		t.Error("Expected error fetching partition meta")
+	}
+
+	// Metrics are read from the Metrics source.
+	h.Metrics = &mockMetrics{
+		brokers: kafkazk.BrokerMetricsMap{1001: {StorageFree: 2000, StorageCapacity: 4000}},
+	}
+
+	bmm, errs = h.GetAllBrokerMeta(true)
+	if errs != nil {
+		t.Fatal(errs)
+	}
+
+	if bmm[1001].StorageFree != 2000 || bmm[1001].StorageCapacity != 4000 || bmm[1001].MetricsIncomplete {
+		t.Errorf("Unexpected broker metadata %+v", bmm[1001])
+	}
+
+	if _, err := h.GetAllPartitionMeta(); err != nil {
+		t.Error(err)
+	}
+
+	if age, err := h.MaxMetaAge(); err != nil || age != time.Minute {
+		t.Errorf("Expected age 1m, got %s, %v", age, err)
+	}
+
+	// Brokers without metrics are incomplete.
+	h.Metrics = &mockMetrics{brokers: kafkazk.BrokerMetricsMap{}}
+
+	bmm, errs = h.GetAllBrokerMeta(true)
+	if len(errs) != 1 || !bmm[1001].MetricsIncomplete {
+		t.Errorf("Expected incomplete metrics for 1001, got %v", errs)
+	}
+
+	h.Close()
+	if !h.Metrics.(*mockMetrics).closed {
+		t.Error("Expected Metrics to be closed")
+	}
+}
+
+var _ MetricsSource = &kafkazk.ZKHandler{}
+
+// mockMetrics is a MetricsSource of the brokers.
+type mockMetrics struct {
+	brokers kafkazk.BrokerMetricsMap
+	closed  bool
+}
+
+func (m *mockMetrics) GetBrokerMetrics() (kafkazk.BrokerMetricsMap, error) {
+	return m.brokers, nil
+}
+
+func (m *mockMetrics) GetAllPartitionMeta() (kafkazk.PartitionMetaMap, error) {
+	return kafkazk.NewPartitionMetaMap(), nil
+}
+
+func (m *mockMetrics) MaxMetaAge() (time.Duration, error) {
+	return time.Minute, nil
+}
+
+func (m *mockMetrics) Close() {
+	m.closed = true
+}
+
+func TestHandlerReassignPartitions(t *testing.T) {
+	h, b := newTestHandler(t)
+	defer b.close()
+
+	pm := kafkazk.NewPartitionMap()
+	pm.Partitions = kafkazk.PartitionList{
+		{Topic: "test_topic", Partition: 0, Replicas: []int{1002, 1003}},
+	}
+
+	if err := h.ReassignPartitions(pm); err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(b.reassigned["test_topic"]) != "map[0:[1002 1003]]" {
+		t.Errorf("Unexpected reassignments %v", b.reassigned)
+	}
+
+	b.reassignments = map[string]map[int32]mockReassignment{
+		"other": {0: mockReassignment{replicas: []int32{1001, 1002}, adding: []int32{1002}}},
+	}
+
+	if err := h.ReassignPartitions(pm); err != kafkazk.ErrReassignmentInProgress {
+		t.Errorf("Expected ErrReassignmentInProgress, got %v", err)
+	}
+}
+
+func TestHandlerElectPreferredLeaders(t *testing.T) {
+	h, b := newTestHandler(t)
+	defer b.close()
+
+	e := kafkazk.PreferredReplicaElection{
+		Version: 1,
+		Partitions: []kafkazk.ElectionPartition{
+			{Topic: "test_topic", Partition: 0},
+			{Topic: "test_topic", Partition: 1},
+		},
+	}
+
+	// Partitions already led by their
+	// preferred leader aren't errors.
+	b.electErr = 84
+
+	if err := h.ElectPreferredLeaders(e); err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(b.elected) != "map[test_topic:[0 1]]" {
+		t.Errorf("Unexpected elected partitions %v", b.elected)
+	}
+
+	b.electErr = 80
+
+	expected := "topic test_topic: PREFERRED_LEADER_NOT_AVAILABLE"
+	if err := h.ElectPreferredLeaders(e); err == nil || err.Error() != expected {
+		t.Errorf("Expected error '%s', got '%v'", expected, err)
+	}
+}
+
+func TestHandlerWatches(t *testing.T) {
+	h, b := newTestHandler(t)
+	defer b.close()
+	defer h.Close()
+
+	h.watchInterval = 10 * time.Millisecond
+
+	_, topicChange, err := h.GetTopicStateW("test_topic")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	brokerChange, err := h.WatchBrokers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.Lock()
+	b.replicas["test_topic"][0] = []int32{1002, 1003}
+	b.Unlock()
+
+	select {
+	case <-topicChange:
+	case <-time.After(time.Second):
+		t.Error("Expected the topic watch to fire")
+	}
+
+	select {
+	case <-brokerChange:
+		t.Error("Unexpected broker watch")
+	default:
+	}
+
+	b.Lock()
+	b.id = 1002
+	b.Unlock()
+
+	select {
+	case <-brokerChange:
+	case <-time.After(time.Second):
+		t.Error("Expected the broker watch to fire")
+	}
+}
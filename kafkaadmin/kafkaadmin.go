@@ -0,0 +1,522 @@
+// Package kafkaadmin applies dynamic Kafka topic and broker configs
+// via the Kafka Admin API (IncrementalAlterConfigs), as an alternative
+// to writing config znodes in ZooKeeper. The Admin API is the only way
+// to apply dynamic configs to KRaft clusters. Requires Kafka 2.3+.
+// Ongoing partition reassignments can also be listed (Kafka 2.4+), as
+// can consumer groups, their committed offsets and partition offsets;
+// committed offsets of groups without active members can be reset, and
+// topics can be created.
+// Connections may use TLS and SASL (PLAIN or SCRAM) authentication.
+package kafkaadmin
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// ResourceType is a config resource type.
+type ResourceType int8
+
+// Config resource types.
+const (
+	ResourceTopic  ResourceType = 2
+	ResourceBroker ResourceType = 4
+)
+
+// String returns the resource type name.
+func (t ResourceType) String() string {
+	switch t {
+	case ResourceTopic:
+		return "topic"
+	case ResourceBroker:
+		return "broker"
+	}
+
+	return fmt.Sprintf("resource type %d", t)
+}
+
+// ConfigOp is an incremental config operation type.
+type ConfigOp int8
+
+// Config operations.
+const (
+	OpSet    ConfigOp = 0
+	OpDelete ConfigOp = 1
+)
+
+// maxResponseSize guards against reading
+// responses from non-Kafka listeners.
+const maxResponseSize = 64 << 20
+
+// Config holds Client configuration parameters.
+type Config struct {
+	// Comma-delimited list of host:port
+	// bootstrap broker addresses.
+	BootstrapServers string
+	// Client ID sent with requests.
+	// Defaults to kafka-kit.
+	ClientID string
+	// Network timeout for each
+	// request. Defaults to 10s.
+	Timeout time.Duration
+	// TLS config for broker connections;
+	// connections are plaintext if nil.
+	TLS *tls.Config
+	// SASL authentication parameters;
+	// no authentication is performed if nil.
+	SASL *SASLConfig
+}
+
+// Client is a Kafka Admin API client.
+type Client struct {
+	bootstrap []string
+	clientID  string
+	timeout   time.Duration
+	tls       *tls.Config
+	sasl      *SASLConfig
+
+	sync.Mutex
+	correlationID int32
+	brokers       map[int32]string
+	controller    int32
+}
+
+// NewClient takes a Config and returns a *Client, initialized
+// with the cluster metadata from the bootstrap brokers.
+func NewClient(c Config) (*Client, error) {
+	var bootstrap []string
+	for _, s := range strings.Split(c.BootstrapServers, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			bootstrap = append(bootstrap, s)
+		}
+	}
+
+	if len(bootstrap) == 0 {
+		return nil, errors.New("No bootstrap servers specified")
+	}
+
+	if c.SASL != nil {
+		if err := c.SASL.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	client := &Client{
+		bootstrap: bootstrap,
+		clientID:  c.ClientID,
+		timeout:   c.Timeout,
+		tls:       c.TLS,
+		sasl:      c.SASL,
+	}
+
+	if client.clientID == "" {
+		client.clientID = "kafka-kit"
+	}
+
+	if client.timeout == 0 {
+		client.timeout = 10 * time.Second
+	}
+
+	if err := client.refreshMetadata(); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// Brokers returns a map of broker IDs to
+// addresses from the cluster metadata.
+func (c *Client) Brokers() map[int]string {
+	c.Lock()
+	defer c.Unlock()
+
+	b := map[int]string{}
+	for id, addr := range c.brokers {
+		b[int(id)] = addr
+	}
+
+	return b
+}
+
+// Ping refreshes the cluster metadata, returning an
+// error if no bootstrap broker responds.
+func (c *Client) Ping() error {
+	return c.refreshMetadata()
+}
+
+// IncrementalAlterConfigs applies the config operations for each
+// ConfigResource. Broker resources are sent to the respective broker.
+func (c *Client) IncrementalAlterConfigs(rs []ConfigResource) error {
+	for _, r := range rs {
+		addr, err := c.addrFor(r.Type, r.Name)
+		if err != nil {
+			return err
+		}
+
+		body := encodeIncrementalAlterConfigsRequest([]ConfigResource{r})
+		d, err := c.request(addr, apiIncrementalAlterConfigs, incrementalAlterConfigsVersion, body)
+		if err != nil {
+			return err
+		}
+
+		if err := decodeIncrementalAlterConfigsResponse(d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DescribeConfigs returns the config entries for a resource. All configs
+// are returned if keys is nil.
+func (c *Client) DescribeConfigs(t ResourceType, name string, keys []string) (map[string]ConfigEntry, error) {
+	addr, err := c.addrFor(t, name)
+	if err != nil {
+		return nil, err
+	}
+
+	body := encodeDescribeConfigsRequest(t, name, keys)
+	d, err := c.request(addr, apiDescribeConfigs, describeConfigsVersion, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeDescribeConfigsResponse(d)
+}
+
+// ListPartitionReassignments returns all ongoing partition reassignments,
+// as a kafkazk.Reassignments of each reassigning partition to its target
+// replica set. Unlike the ZooKeeper reassign_partitions znode, this includes
+// reassignments made with the incremental reassignment API and is available
+// on KRaft clusters. Requires Kafka 2.4+.
+func (c *Client) ListPartitionReassignments() (kafkazk.Reassignments, error) {
+	return c.listPartitionReassignments(true)
+}
+
+func (c *Client) listPartitionReassignments(retry bool) (kafkazk.Reassignments, error) {
+	addr, err := c.addrFor(ResourceTopic, "")
+	if err != nil {
+		return nil, err
+	}
+
+	body := encodeListPartitionReassignmentsRequest(int32(c.timeout / time.Millisecond))
+	d, err := c.request(addr, apiListPartitionReassignments, listPartitionReassignmentsVersion, body)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := decodeListPartitionReassignmentsResponse(d)
+
+	// Retry once against the current
+	// controller if it has moved.
+	if e, ok := err.(*Error); ok && e.Code == errNotController && retry {
+		if err := c.refreshMetadata(); err != nil {
+			return nil, err
+		}
+
+		return c.listPartitionReassignments(false)
+	}
+
+	return r, err
+}
+
+// AlterPartitionReassignments reassigns each partition in the
+// kafkazk.Reassignments to its replica set. Reassignments of other
+// partitions in progress are unaffected. Requires Kafka 2.4+.
+func (c *Client) AlterPartitionReassignments(r kafkazk.Reassignments) error {
+	return c.alterPartitionReassignments(r, true)
+}
+
+func (c *Client) alterPartitionReassignments(r kafkazk.Reassignments, retry bool) error {
+	addr, err := c.addrFor(ResourceTopic, "")
+	if err != nil {
+		return err
+	}
+
+	body := encodeAlterPartitionReassignmentsRequest(r, int32(c.timeout/time.Millisecond))
+	d, err := c.request(addr, apiAlterPartitionReassignments, alterPartitionReassignmentsVersion, body)
+	if err != nil {
+		return err
+	}
+
+	err = decodeAlterPartitionReassignmentsResponse(d)
+
+	// Retry once against the current
+	// controller if it has moved.
+	if e, ok := err.(*Error); ok && e.Code == errNotController && retry {
+		if err := c.refreshMetadata(); err != nil {
+			return err
+		}
+
+		return c.alterPartitionReassignments(r, false)
+	}
+
+	return err
+}
+
+// ElectLeaders triggers a preferred leader election for the partitions
+// of each topic. Partitions already led by their preferred leader are
+// ignored. Requires Kafka 2.2+.
+func (c *Client) ElectLeaders(partitions map[string][]int) error {
+	return c.electLeaders(partitions, true)
+}
+
+func (c *Client) electLeaders(partitions map[string][]int, retry bool) error {
+	addr, err := c.addrFor(ResourceTopic, "")
+	if err != nil {
+		return err
+	}
+
+	body := encodeElectLeadersRequest(partitions, int32(c.timeout/time.Millisecond))
+	d, err := c.request(addr, apiElectLeaders, electLeadersVersion, body)
+	if err != nil {
+		return err
+	}
+
+	err = decodeElectLeadersResponse(d)
+
+	// Retry once against the current
+	// controller if it has moved.
+	if re, ok := err.(*ResourceError); ok && retry {
+		if e, ok := re.Err.(*Error); ok && e.Code == errNotController {
+			if err := c.refreshMetadata(); err != nil {
+				return err
+			}
+
+			return c.electLeaders(partitions, false)
+		}
+	}
+
+	return err
+}
+
+// UpdateKafkaConfig takes a kafkazk.KafkaConfig and applies it via the
+// Admin API, mirroring kafkazk.Handler.UpdateKafkaConfig: a config value
+// of "" deletes the config key, and a bool is returned indicating whether
+// any config was changed (determined by describing the current configs).
+func (c *Client) UpdateKafkaConfig(kc kafkazk.KafkaConfig) (bool, error) {
+	var t ResourceType
+	switch kc.Type {
+	case "topic":
+		t = ResourceTopic
+	case "broker":
+		t = ResourceBroker
+	default:
+		return false, kafkazk.ErrInvalidKafkaConfigType
+	}
+
+	var keys []string
+	for _, kv := range kc.Configs {
+		keys = append(keys, kv[0])
+	}
+
+	current, err := c.DescribeConfigs(t, kc.Name, keys)
+	if err != nil {
+		return false, err
+	}
+
+	r := ConfigResource{Type: t, Name: kc.Name}
+	for _, kv := range kc.Configs {
+		e, exists := current[kv[0]]
+		// Only dynamic configs of the resource are
+		// set; anything else (e.g. static broker
+		// configs) can't be deleted.
+		set := exists && e.Source == dynamicSource(t)
+
+		switch {
+		case kv[1] == "" && set:
+			r.Configs = append(r.Configs, AlterConfig{Name: kv[0], Op: OpDelete})
+		case kv[1] != "" && (!set || e.Value != kv[1]):
+			r.Configs = append(r.Configs, AlterConfig{Name: kv[0], Op: OpSet, Value: kv[1]})
+		}
+	}
+
+	if len(r.Configs) == 0 {
+		return false, nil
+	}
+
+	if err := c.IncrementalAlterConfigs([]ConfigResource{r}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// dynamicSource returns the ConfigSource of dynamic
+// configs set for a resource of type t.
+func dynamicSource(t ResourceType) ConfigSource {
+	if t == ResourceBroker {
+		return SourceDynamicBroker
+	}
+
+	return SourceDynamicTopic
+}
+
+// addrFor returns the address that requests for a resource are sent to.
+// Broker resources must be handled by the respective broker; all else
+// is sent to the controller.
+func (c *Client) addrFor(t ResourceType, name string) (string, error) {
+	if t == ResourceBroker {
+		id, err := strconv.Atoi(name)
+		if err != nil {
+			return "", fmt.Errorf("Invalid broker ID %s", name)
+		}
+
+		addr, exists := c.brokerAddr(int32(id))
+		if !exists {
+			// The broker may be new.
+			if err := c.refreshMetadata(); err != nil {
+				return "", err
+			}
+
+			if addr, exists = c.brokerAddr(int32(id)); !exists {
+				return "", ErrUnknownBroker{ID: id}
+			}
+		}
+
+		return addr, nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if addr, exists := c.brokers[c.controller]; exists {
+		return addr, nil
+	}
+
+	return c.bootstrap[0], nil
+}
+
+func (c *Client) brokerAddr(id int32) (string, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	addr, exists := c.brokers[id]
+
+	return addr, exists
+}
+
+// refreshMetadata fetches the brokers and controller from
+// the first bootstrap broker that responds.
+func (c *Client) refreshMetadata() error {
+	var err error
+
+	for _, addr := range c.bootstrap {
+		var d *decoder
+		d, err = c.request(addr, apiMetadata, metadataVersion, encodeMetadataRequest())
+		if err != nil {
+			continue
+		}
+
+		var m *metadata
+		m, err = decodeMetadataResponse(d)
+		if err != nil {
+			continue
+		}
+
+		c.Lock()
+		c.brokers = map[int32]string{}
+		for _, b := range m.brokers {
+			c.brokers[b.id] = net.JoinHostPort(b.host, strconv.Itoa(int(b.port)))
+		}
+		c.controller = m.controller
+		c.Unlock()
+
+		return nil
+	}
+
+	return fmt.Errorf("Error fetching cluster metadata: %s", err)
+}
+
+// request sends a request to addr and returns a *decoder
+// for the response body.
+func (c *Client) request(addr string, key, version int16, body []byte) (*decoder, error) {
+	conn, err := c.dial(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	return c.roundTrip(conn, addr, key, version, body)
+}
+
+// dial returns a connection to addr, established with
+// TLS and SASL authentication if configured. The
+// connection deadline is set to the request timeout.
+func (c *Client) dial(addr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: c.timeout}
+
+	var conn net.Conn
+	var err error
+
+	if c.tls != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, c.tls)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Now().Add(c.timeout))
+
+	if c.sasl != nil {
+		if err := c.authenticate(conn, addr); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+// roundTrip sends a request on conn and
+// returns a *decoder for the response body.
+func (c *Client) roundTrip(conn net.Conn, addr string, key, version int16, body []byte) (*decoder, error) {
+	c.Lock()
+	c.correlationID++
+	id := c.correlationID
+	c.Unlock()
+
+	if _, err := conn.Write(encodeRequest(key, version, id, c.clientID, body)); err != nil {
+		return nil, err
+	}
+
+	size := make([]byte, 4)
+	if _, err := io.ReadFull(conn, size); err != nil {
+		return nil, err
+	}
+
+	n := binary.BigEndian.Uint32(size)
+	if n > maxResponseSize {
+		return nil, fmt.Errorf("Response size %d from %s exceeds %d bytes", n, addr, maxResponseSize)
+	}
+
+	resp := make([]byte, n)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return nil, err
+	}
+
+	d := &decoder{b: resp}
+	if cid := d.int32(); cid != id {
+		return nil, fmt.Errorf("Unexpected correlation ID %d from %s, expected %d", cid, addr, id)
+	}
+
+	if flexible(key) {
+		d.taggedFields()
+	}
+
+	return d, d.err
+}
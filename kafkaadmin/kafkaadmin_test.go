@@ -0,0 +1,896 @@
+package kafkaadmin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// mockBroker is a minimal Kafka broker that serves
+// metadata, describe configs and alter configs
+// requests from an in-memory config store, and
+// group and offset requests from in-memory groups.
+type mockBroker struct {
+	id int32
+	ln net.Listener
+
+	sync.Mutex
+	// Map of resource name to config key to value.
+	configs map[string]map[string]string
+	// Resource names received in alter requests.
+	altered []string
+	// Error code returned for alter requests.
+	alterErr int16
+	// Ongoing reassignments by topic and partition.
+	reassignments map[string]map[int32]mockReassignment
+	// Error code returned for list reassignments requests.
+	listErr int16
+	// SASL credentials required of
+	// clients, if non-nil.
+	sasl *mockSASL
+	// Groups by group ID.
+	groups map[string]*GroupDescription
+	// Committed offsets by group ID.
+	committed map[string]Offsets
+	// Log end offsets of the partitions of
+	// each topic. Log start offsets are 0.
+	logEnd Offsets
+	// Produced records by topic and partition.
+	records map[string]map[int32][]Record
+	// Replicas of the partitions of each topic, if
+	// set; otherwise this broker is the only replica.
+	replicas map[string]map[int32][]int32
+	// Partitions received in alter
+	// reassignments requests.
+	reassigned kafkazk.Reassignments
+	// Partitions received in elect leaders requests.
+	elected map[string][]int
+	// Error code returned for elected partitions.
+	electErr int16
+}
+
+// mockReassignment holds the replicas
+// of a reassigning partition.
+type mockReassignment struct {
+	replicas, adding, removing []int32
+}
+
+func newMockBroker(t *testing.T, id int32) *mockBroker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return startMockBroker(id, ln)
+}
+
+// newMockTLSBroker returns a *mockBroker serving TLS
+// connections with the certificate in config.
+func newMockTLSBroker(t *testing.T, id int32, config *tls.Config) *mockBroker {
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return startMockBroker(id, ln)
+}
+
+func startMockBroker(id int32, ln net.Listener) *mockBroker {
+	b := &mockBroker{id: id, ln: ln, configs: map[string]map[string]string{}}
+	go b.serve()
+
+	return b
+}
+
+func (b *mockBroker) addr() string {
+	return b.ln.Addr().String()
+}
+
+func (b *mockBroker) close() {
+	b.ln.Close()
+}
+
+func (b *mockBroker) serve() {
+	for {
+		conn, err := b.ln.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(conn)
+	}
+}
+
+func (b *mockBroker) handle(conn net.Conn) {
+	defer conn.Close()
+
+	b.Lock()
+	sc := &mockSASLConn{config: b.sasl}
+	b.Unlock()
+
+	for {
+		size := make([]byte, 4)
+		if _, err := io.ReadFull(conn, size); err != nil {
+			return
+		}
+
+		req := make([]byte, binary.BigEndian.Uint32(size))
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+
+		d := &decoder{b: req}
+		key := d.int16()
+		d.int16() // Version.
+		cid := d.int32()
+		d.string() // Client ID.
+		if flexible(key) {
+			d.taggedFields()
+		}
+
+		e := &encoder{}
+		e.int32(cid)
+		if flexible(key) {
+			e.taggedFields()
+		}
+
+		// Unauthenticated connections are
+		// closed, as Kafka does.
+		if !sc.authenticated() && key != apiSaslHandshake && key != apiSaslAuthenticate {
+			return
+		}
+
+		switch key {
+		case apiSaslHandshake:
+			sc.handshake(d, e)
+		case apiSaslAuthenticate:
+			sc.authenticate(d, e)
+		case apiMetadata:
+			b.metadata(d, e)
+		case apiDescribeConfigs:
+			b.describeConfigs(d, e)
+		case apiIncrementalAlterConfigs:
+			b.alterConfigs(d, e)
+		case apiListPartitionReassignments:
+			b.listReassignments(e)
+		case apiFindCoordinator:
+			b.findCoordinator(e)
+		case apiListGroups:
+			b.listGroups(e)
+		case apiDescribeGroups:
+			b.describeGroups(d, e)
+		case apiOffsetFetch:
+			b.offsetFetch(d, e)
+		case apiOffsetCommit:
+			b.offsetCommit(d, e)
+		case apiListOffsets:
+			b.listOffsets(d, e)
+		case apiProduce:
+			b.produce(d, e)
+		case apiFetch:
+			b.fetch(d, e)
+		case apiCreateTopics:
+			b.createTopics(d, e)
+		case apiDeleteTopics:
+			b.deleteTopics(d, e)
+		case apiAlterPartitionReassignments:
+			b.alterReassignments(d, e)
+		case apiElectLeaders:
+			b.electLeaders(d, e)
+		default:
+			return
+		}
+
+		resp := make([]byte, 4)
+		binary.BigEndian.PutUint32(resp, uint32(len(e.b)))
+		conn.Write(append(resp, e.b...))
+	}
+}
+
+func (b *mockBroker) metadata(d *decoder, e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	host, port, _ := net.SplitHostPort(b.addr())
+	p, _ := strconv.Atoi(port)
+
+	e.arrayLen(1)
+	e.int32(b.id)
+	e.string(host)
+	e.int32(int32(p))
+	e.nullableString(nil)
+	e.int32(b.id) // Controller.
+
+	// A null topics array requests all topics.
+	var topics []string
+	if n := d.int32(); n < 0 {
+		for t := range b.logEnd {
+			topics = append(topics, t)
+		}
+	} else {
+		for i := 0; i < int(n); i++ {
+			topics = append(topics, d.string())
+		}
+	}
+
+	// The first replica (this broker, unless
+	// replicas are set) leads each partition,
+	// and all replicas are in sync.
+	e.arrayLen(len(topics))
+	for _, t := range topics {
+		var code int16
+		if _, exists := b.logEnd[t]; !exists {
+			code = 3
+		}
+
+		e.int16(code)
+		e.string(t)
+		e.bool(false)
+		e.arrayLen(len(b.logEnd[t]))
+		for p := range b.logEnd[t] {
+			replicas, exists := b.replicas[t][int32(p)]
+			if !exists {
+				replicas = []int32{b.id}
+			}
+
+			e.int16(0)
+			e.int32(int32(p))
+			e.int32(replicas[0])
+			for i := 0; i < 2; i++ {
+				e.arrayLen(len(replicas))
+				for _, id := range replicas {
+					e.int32(id)
+				}
+			}
+		}
+	}
+}
+
+func (b *mockBroker) alterReassignments(d *decoder, e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	d.int32() // Timeout.
+
+	b.reassigned = kafkazk.Reassignments{}
+
+	e.int32(0)            // Throttle time.
+	e.int16(0)            // Error code.
+	e.compactArrayLen(-1) // Error message.
+
+	nt := d.compactArrayLen()
+	e.compactArrayLen(nt)
+	for i := 0; i < nt; i++ {
+		t := d.compactString()
+		b.reassigned[t] = map[int][]int{}
+		e.compactString(t)
+
+		np := d.compactArrayLen()
+		e.compactArrayLen(np)
+		for j := 0; j < np; j++ {
+			p := d.int32()
+			b.reassigned[t][int(p)] = ints(d.compactInt32Array())
+			d.taggedFields()
+
+			e.int32(p)
+			e.int16(0)
+			e.compactArrayLen(-1)
+			e.taggedFields()
+		}
+
+		d.taggedFields()
+		e.taggedFields()
+	}
+
+	e.taggedFields()
+}
+
+func (b *mockBroker) electLeaders(d *decoder, e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.elected = map[string][]int{}
+
+	e.int32(0) // Throttle time.
+
+	nt := d.arrayLen()
+	e.arrayLen(nt)
+	for i := 0; i < nt; i++ {
+		t := d.string()
+		e.string(t)
+
+		np := d.arrayLen()
+		e.arrayLen(np)
+		for j := 0; j < np; j++ {
+			p := d.int32()
+			b.elected[t] = append(b.elected[t], int(p))
+
+			e.int32(p)
+			e.int16(b.electErr)
+			e.nullableString(nil)
+		}
+	}
+}
+
+func (b *mockBroker) findCoordinator(e *encoder) {
+	host, port, _ := net.SplitHostPort(b.addr())
+	p, _ := strconv.Atoi(port)
+
+	e.int16(0)
+	e.int32(b.id)
+	e.string(host)
+	e.int32(int32(p))
+}
+
+func (b *mockBroker) listGroups(e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	e.int16(0)
+	e.arrayLen(len(b.groups))
+	for id, g := range b.groups {
+		e.string(id)
+		e.string(g.ProtocolType)
+	}
+}
+
+func (b *mockBroker) describeGroups(d *decoder, e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	n := d.arrayLen()
+	e.arrayLen(n)
+	for i := 0; i < n; i++ {
+		id := d.string()
+		g, exists := b.groups[id]
+		if !exists {
+			g = &GroupDescription{GroupID: id, State: "Dead"}
+		}
+
+		e.int16(0)
+		e.string(id)
+		e.string(g.State)
+		e.string(g.ProtocolType)
+		e.string(g.Protocol)
+		e.arrayLen(len(g.Members))
+		for _, m := range g.Members {
+			e.string(m.MemberID)
+			e.string(m.ClientID)
+			e.string(m.ClientHost)
+			e.bytes(nil)
+
+			// Consumer protocol assignment.
+			a := &encoder{}
+			a.int16(0)
+			a.arrayLen(len(m.Assignment))
+			for t, ps := range m.Assignment {
+				a.string(t)
+				a.arrayLen(len(ps))
+				for _, p := range ps {
+					a.int32(int32(p))
+				}
+			}
+			a.bytes(nil) // User data.
+			e.bytes(a.b)
+		}
+	}
+}
+
+func (b *mockBroker) offsetFetch(d *decoder, e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	offsets := b.committed[d.string()]
+
+	e.arrayLen(len(offsets))
+	for t, ps := range offsets {
+		e.string(t)
+		e.arrayLen(len(ps))
+		for p, o := range ps {
+			e.int32(int32(p))
+			e.int64(o)
+			e.nullableString(nil)
+			e.int16(0)
+		}
+	}
+	e.int16(0)
+}
+
+func (b *mockBroker) offsetCommit(d *decoder, e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	group := d.string()
+	d.int32()  // Generation ID.
+	d.string() // Member ID.
+	d.int64()  // Retention time.
+
+	// Groups with members reject
+	// commits without a member ID.
+	var code int16
+	if g, exists := b.groups[group]; exists && len(g.Members) > 0 {
+		code = 25
+	}
+
+	if b.committed[group] == nil {
+		b.committed[group] = Offsets{}
+	}
+
+	nt := d.arrayLen()
+	e.arrayLen(nt)
+	for i := 0; i < nt; i++ {
+		t := d.string()
+		e.string(t)
+
+		np := d.arrayLen()
+		e.arrayLen(np)
+		for j := 0; j < np; j++ {
+			p := d.int32()
+			o := d.int64()
+			d.nullableString()
+
+			if code == 0 {
+				if b.committed[group][t] == nil {
+					b.committed[group][t] = map[int]int64{}
+				}
+				b.committed[group][t][int(p)] = o
+			}
+
+			e.int32(p)
+			e.int16(code)
+		}
+	}
+}
+
+func (b *mockBroker) listOffsets(d *decoder, e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	d.int32() // Replica ID.
+
+	nt := d.arrayLen()
+	e.arrayLen(nt)
+	for i := 0; i < nt; i++ {
+		t := d.string()
+		e.string(t)
+
+		np := d.arrayLen()
+		e.arrayLen(np)
+		for j := 0; j < np; j++ {
+			p := d.int32()
+			ts := d.int64()
+
+			offset := b.logEnd[t][int(p)]
+			if ts == OffsetEarliest {
+				offset = 0
+			}
+
+			e.int32(p)
+			e.int16(0)
+			e.int64(-1)
+			e.int64(offset)
+		}
+	}
+}
+
+func (b *mockBroker) describeConfigs(d *decoder, e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	d.arrayLen()
+	t := d.int8()
+	name := d.string()
+	var keys []string
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		keys = append(keys, d.string())
+	}
+	d.bool() // Include synonyms.
+
+	// All configs are described if keys is null:
+	// those set, and a default config.
+	if keys == nil {
+		for k := range b.configs[name] {
+			keys = append(keys, k)
+		}
+		keys = append(keys, "compression.type")
+	}
+
+	e.int32(0) // Throttle time.
+	e.arrayLen(1)
+	e.int16(0)
+	e.nullableString(nil)
+	e.int8(t)
+	e.string(name)
+	e.arrayLen(len(keys))
+	for _, k := range keys {
+		v, set := b.configs[name][k]
+		source := SourceDefault
+		if set {
+			source = dynamicSource(ResourceType(t))
+		}
+
+		e.string(k)
+		e.nullableString(&v)
+		e.bool(false)
+		e.int8(int8(source))
+		e.bool(false)
+		e.arrayLen(0) // Synonyms.
+	}
+}
+
+func (b *mockBroker) alterConfigs(d *decoder, e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	e.int32(0) // Throttle time.
+
+	n := d.arrayLen()
+	e.arrayLen(n)
+	for i := 0; i < n; i++ {
+		t := d.int8()
+		name := d.string()
+		b.altered = append(b.altered, name)
+
+		if b.configs[name] == nil {
+			b.configs[name] = map[string]string{}
+		}
+
+		for j, nc := 0, d.arrayLen(); j < nc; j++ {
+			k := d.string()
+			op := ConfigOp(d.int8())
+			v, _ := d.nullableString()
+			if b.alterErr != 0 {
+				continue
+			}
+			switch op {
+			case OpSet:
+				b.configs[name][k] = v
+			case OpDelete:
+				delete(b.configs[name], k)
+			}
+		}
+
+		e.int16(b.alterErr)
+		e.nullableString(nil)
+		e.int8(t)
+		e.string(name)
+	}
+}
+
+func (b *mockBroker) listReassignments(e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	e.int32(0) // Throttle time.
+	e.int16(b.listErr)
+	e.compactArrayLen(-1) // Error message.
+
+	var topics []string
+	for t := range b.reassignments {
+		topics = append(topics, t)
+	}
+
+	e.compactArrayLen(len(topics))
+	for _, t := range topics {
+		e.compactArrayLen(len(t)) // Compact string length.
+		e.b = append(e.b, t...)
+		e.compactArrayLen(len(b.reassignments[t]))
+		for p, r := range b.reassignments[t] {
+			e.int32(p)
+			for _, replicas := range [][]int32{r.replicas, r.adding, r.removing} {
+				e.compactArrayLen(len(replicas))
+				for _, id := range replicas {
+					e.int32(id)
+				}
+			}
+			e.taggedFields()
+		}
+		e.taggedFields()
+	}
+
+	e.taggedFields()
+}
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient(Config{}); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	b := newMockBroker(t, 1001)
+	defer b.close()
+
+	c, err := NewClient(Config{BootstrapServers: "127.0.0.1:1," + b.addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	brokers := c.Brokers()
+	if len(brokers) != 1 || brokers[1001] != b.addr() {
+		t.Errorf("Unexpected brokers: %v", brokers)
+	}
+
+	if _, err := c.addrFor(ResourceBroker, "1002"); err == nil {
+		t.Error("Expected non-nil error")
+	} else if _, ok := err.(ErrUnknownBroker); !ok {
+		t.Errorf("Unexpected error type %T", err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	b := newMockBroker(t, 1001)
+
+	c, err := NewClient(Config{BootstrapServers: b.addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Ping(); err != nil {
+		t.Errorf("Unexpected error '%s'", err)
+	}
+
+	b.close()
+
+	if err := c.Ping(); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
+
+func TestUpdateKafkaConfig(t *testing.T) {
+	b := newMockBroker(t, 1001)
+	defer b.close()
+
+	c, err := NewClient(Config{BootstrapServers: b.addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := kafkazk.KafkaConfig{
+		Type: "broker",
+		Name: "1001",
+		Configs: [][2]string{
+			[2]string{"leader.replication.throttled.rate", "100000000"},
+			[2]string{"follower.replication.throttled.rate", "100000000"},
+		},
+	}
+
+	changed, err := c.UpdateKafkaConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !changed {
+		t.Error("Expected config to be changed")
+	}
+
+	if v := b.configs["1001"]["leader.replication.throttled.rate"]; v != "100000000" {
+		t.Errorf("Unexpected config value %s", v)
+	}
+
+	// Unchanged.
+	changed, _ = c.UpdateKafkaConfig(config)
+	if changed {
+		t.Error("Unexpected config change")
+	}
+
+	if len(b.altered) != 1 {
+		t.Errorf("Expected 1 alter request, got %d", len(b.altered))
+	}
+
+	// Delete.
+	config.Configs[0][1] = ""
+	config.Configs[1][1] = ""
+
+	changed, _ = c.UpdateKafkaConfig(config)
+	if !changed {
+		t.Error("Expected config to be changed")
+	}
+
+	if len(b.configs["1001"]) != 0 {
+		t.Errorf("Expected configs to be removed, got %v", b.configs["1001"])
+	}
+
+	// Deleting unset configs is a no-op.
+	changed, _ = c.UpdateKafkaConfig(config)
+	if changed {
+		t.Error("Unexpected config change")
+	}
+
+	// Topic configs.
+	changed, err = c.UpdateKafkaConfig(kafkazk.KafkaConfig{
+		Type:    "topic",
+		Name:    "test_topic",
+		Configs: [][2]string{[2]string{"leader.replication.throttled.replicas", "0:1001"}},
+	})
+
+	if err != nil || !changed {
+		t.Errorf("Expected config to be changed, got %v, %v", changed, err)
+	}
+
+	if _, err := c.UpdateKafkaConfig(kafkazk.KafkaConfig{Type: "user"}); err != kafkazk.ErrInvalidKafkaConfigType {
+		t.Errorf("Expected ErrInvalidKafkaConfigType, got %v", err)
+	}
+}
+
+func TestIncrementalAlterConfigsError(t *testing.T) {
+	b := newMockBroker(t, 1001)
+	defer b.close()
+
+	b.alterErr = 40
+
+	c, err := NewClient(Config{BootstrapServers: b.addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.IncrementalAlterConfigs([]ConfigResource{
+		ConfigResource{
+			Type:    ResourceBroker,
+			Name:    "1001",
+			Configs: []AlterConfig{AlterConfig{Name: "leader.replication.throttled.rate", Value: "x"}},
+		},
+	})
+
+	expected := "broker 1001: INVALID_CONFIG"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error '%s', got '%v'", expected, err)
+	}
+}
+
+// testCertificate returns a self-signed
+// certificate for 127.0.0.1.
+func testCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "mock broker"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+func TestTLS(t *testing.T) {
+	cert, pool := testCertificate(t)
+
+	b := newMockTLSBroker(t, 1001, &tls.Config{Certificates: []tls.Certificate{cert}})
+	defer b.close()
+
+	c, err := NewClient(Config{BootstrapServers: b.addr(), TLS: &tls.Config{RootCAs: pool}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.DescribeConfigs(ResourceBroker, "1001", nil); err != nil {
+		t.Error(err)
+	}
+
+	// Untrusted certificate.
+	if _, err := NewClient(Config{BootstrapServers: b.addr(), TLS: &tls.Config{}}); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	// Plaintext.
+	if _, err := NewClient(Config{BootstrapServers: b.addr(), Timeout: time.Second}); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
+
+func TestDecoderShortBuffer(t *testing.T) {
+	d := &decoder{b: []byte{0, 5, 'a'}}
+	if s := d.string(); s != "" || d.err != errShortBuffer {
+		t.Errorf("Expected short buffer error, got '%s', %v", s, d.err)
+	}
+
+	// Errors are sticky.
+	if v := d.int8(); v != 0 {
+		t.Errorf("Expected 0, got %d", v)
+	}
+}
+
+func TestListPartitionReassignments(t *testing.T) {
+	b := newMockBroker(t, 1001)
+	defer b.close()
+
+	b.reassignments = map[string]map[int32]mockReassignment{
+		"test_topic": map[int32]mockReassignment{
+			// Moving from 1001, 1002 to 1002, 1003.
+			0: mockReassignment{
+				replicas: []int32{1001, 1002, 1003},
+				adding:   []int32{1003},
+				removing: []int32{1001},
+			},
+			1: mockReassignment{
+				replicas: []int32{1002, 1003},
+				adding:   []int32{1003},
+			},
+		},
+	}
+
+	c, err := NewClient(Config{BootstrapServers: b.addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := c.ListPartitionReassignments()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[int][]int{
+		0: []int{1002, 1003},
+		1: []int{1002, 1003},
+	}
+
+	if len(r) != 1 || len(r["test_topic"]) != 2 {
+		t.Fatalf("Unexpected reassignments: %v", r)
+	}
+
+	for p, replicas := range expected {
+		if fmt.Sprint(r["test_topic"][p]) != fmt.Sprint(replicas) {
+			t.Errorf("Expected partition %d replicas %v, got %v", p, replicas, r["test_topic"][p])
+		}
+	}
+
+	// No reassignments.
+	b.reassignments = nil
+	r, err = c.ListPartitionReassignments()
+	if err != nil || len(r) != 0 {
+		t.Errorf("Expected no reassignments, got %v, %v", r, err)
+	}
+
+	b.listErr = 31
+	if _, err := c.ListPartitionReassignments(); err == nil || err.Error() != "CLUSTER_AUTHORIZATION_FAILED" {
+		t.Errorf("Expected CLUSTER_AUTHORIZATION_FAILED error, got %v", err)
+	}
+}
+
+func TestDecoderTaggedFields(t *testing.T) {
+	// One tagged field (tag 0, size 2) followed by an int8.
+	d := &decoder{b: []byte{1, 0, 2, 'a', 'b', 7}}
+	d.taggedFields()
+	if v := d.int8(); v != 7 || d.err != nil {
+		t.Errorf("Expected 7, got %d (%v)", v, d.err)
+	}
+
+	d = &decoder{b: []byte{1, 0, 5, 'a'}}
+	d.taggedFields()
+	if d.err != errShortBuffer {
+		t.Errorf("Expected short buffer error, got %v", d.err)
+	}
+}
@@ -0,0 +1,1152 @@
+package kafkaadmin
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// Kafka API keys and the versions used.
+const (
+	apiProduce                     = 0
+	apiFetch                       = 1
+	apiListOffsets                 = 2
+	apiMetadata                    = 3
+	apiOffsetCommit                = 8
+	apiOffsetFetch                 = 9
+	apiFindCoordinator             = 10
+	apiDescribeGroups              = 15
+	apiListGroups                  = 16
+	apiSaslHandshake               = 17
+	apiCreateTopics                = 19
+	apiDeleteTopics                = 20
+	apiDescribeConfigs             = 32
+	apiSaslAuthenticate            = 36
+	apiElectLeaders                = 43
+	apiIncrementalAlterConfigs     = 44
+	apiAlterPartitionReassignments = 45
+	apiListPartitionReassignments  = 46
+
+	produceVersion                     = 3
+	fetchVersion                       = 4
+	listOffsetsVersion                 = 1
+	metadataVersion                    = 1
+	offsetCommitVersion                = 2
+	offsetFetchVersion                 = 2
+	findCoordinatorVersion             = 0
+	describeGroupsVersion              = 0
+	listGroupsVersion                  = 0
+	saslHandshakeVersion               = 1
+	createTopicsVersion                = 2
+	deleteTopicsVersion                = 1
+	saslAuthenticateVersion            = 0
+	describeConfigsVersion             = 1
+	electLeadersVersion                = 0
+	incrementalAlterConfigsVersion     = 0
+	alterPartitionReassignmentsVersion = 0
+	listPartitionReassignmentsVersion  = 0
+)
+
+// flexible returns whether the version used for an API key
+// is a flexible version (KIP-482), which uses compact types,
+// tagged fields and the v2 request and v1 response headers.
+func flexible(key int16) bool {
+	return key == apiListPartitionReassignments || key == apiAlterPartitionReassignments
+}
+
+var errShortBuffer = errors.New("Malformed response: short buffer")
+
+// encoder builds Kafka protocol
+// (non-flexible version) payloads.
+type encoder struct {
+	b []byte
+}
+
+func (e *encoder) int8(v int8) {
+	e.b = append(e.b, byte(v))
+}
+
+func (e *encoder) int16(v int16) {
+	e.b = append(e.b, byte(v>>8), byte(v))
+}
+
+func (e *encoder) int32(v int32) {
+	e.b = append(e.b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func (e *encoder) int64(v int64) {
+	e.int32(int32(v >> 32))
+	e.int32(int32(v))
+}
+
+func (e *encoder) bool(v bool) {
+	if v {
+		e.int8(1)
+		return
+	}
+	e.int8(0)
+}
+
+func (e *encoder) string(s string) {
+	e.int16(int16(len(s)))
+	e.b = append(e.b, s...)
+}
+
+// nullableString encodes s, or
+// a null string if s is nil.
+func (e *encoder) nullableString(s *string) {
+	if s == nil {
+		e.int16(-1)
+		return
+	}
+	e.string(*s)
+}
+
+func (e *encoder) bytes(b []byte) {
+	e.int32(int32(len(b)))
+	e.b = append(e.b, b...)
+}
+
+// arrayLen encodes an array length.
+// A negative length is a null array.
+func (e *encoder) arrayLen(n int) {
+	e.int32(int32(n))
+}
+
+func (e *encoder) uvarint(v uint64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	e.b = append(e.b, b[:binary.PutUvarint(b, v)]...)
+}
+
+// varint encodes a zigzag varint, as used in records.
+func (e *encoder) varint(v int64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	e.b = append(e.b, b[:binary.PutVarint(b, v)]...)
+}
+
+// varintBytes encodes varint length prefixed bytes,
+// as used in records. Nil bytes are encoded as null.
+func (e *encoder) varintBytes(b []byte) {
+	if b == nil {
+		e.varint(-1)
+		return
+	}
+	e.varint(int64(len(b)))
+	e.b = append(e.b, b...)
+}
+
+// compactArrayLen encodes a flexible version
+// array length. A negative length is a null array.
+func (e *encoder) compactArrayLen(n int) {
+	e.uvarint(uint64(n + 1))
+}
+
+func (e *encoder) compactString(s string) {
+	e.uvarint(uint64(len(s) + 1))
+	e.b = append(e.b, s...)
+}
+
+// compactInt32Array encodes a flexible
+// version array of int32 values.
+func (e *encoder) compactInt32Array(v []int32) {
+	e.compactArrayLen(len(v))
+	for _, i := range v {
+		e.int32(i)
+	}
+}
+
+// taggedFields encodes an empty tagged fields section.
+func (e *encoder) taggedFields() {
+	e.uvarint(0)
+}
+
+// decoder reads Kafka protocol payloads. The first
+// error encountered is retained and subsequent
+// reads return zero values.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+
+	if n < 0 || len(d.b) < n {
+		d.err = errShortBuffer
+		return nil
+	}
+
+	v := d.b[:n]
+	d.b = d.b[n:]
+
+	return v
+}
+
+func (d *decoder) int8() int8 {
+	b := d.next(1)
+	if b == nil {
+		return 0
+	}
+	return int8(b[0])
+}
+
+func (d *decoder) int16() int16 {
+	b := d.next(2)
+	if b == nil {
+		return 0
+	}
+	return int16(binary.BigEndian.Uint16(b))
+}
+
+func (d *decoder) int32() int32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b))
+}
+
+func (d *decoder) int64() int64 {
+	b := d.next(8)
+	if b == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
+
+func (d *decoder) bool() bool {
+	return d.int8() != 0
+}
+
+func (d *decoder) string() string {
+	n := d.int16()
+	return string(d.next(int(n)))
+}
+
+// nullableString returns the decoded string
+// and false if the string is null.
+func (d *decoder) nullableString() (string, bool) {
+	n := d.int16()
+	if n < 0 {
+		return "", false
+	}
+	return string(d.next(int(n))), true
+}
+
+// bytes returns decoded bytes.
+// Null bytes are returned as nil.
+func (d *decoder) bytes() []byte {
+	n := d.int32()
+	if n < 0 {
+		return nil
+	}
+	return d.next(int(n))
+}
+
+// arrayLen returns a decoded array length.
+// Null arrays are returned as 0.
+func (d *decoder) arrayLen() int {
+	n := d.int32()
+	if n < 0 {
+		return 0
+	}
+
+	// Each element is at least one byte; guard
+	// against allocating from a bad length.
+	if int(n) > len(d.b) {
+		d.err = errShortBuffer
+		return 0
+	}
+
+	return int(n)
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = errShortBuffer
+		return 0
+	}
+
+	d.b = d.b[n:]
+
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = errShortBuffer
+		return 0
+	}
+
+	d.b = d.b[n:]
+
+	return v
+}
+
+// varintBytes returns decoded varint length prefixed
+// bytes. Null bytes are returned as nil.
+func (d *decoder) varintBytes() []byte {
+	n := d.varint()
+	if n < 0 {
+		return nil
+	}
+	return d.next(int(n))
+}
+
+// compactArrayLen returns a decoded flexible version
+// array length. Null arrays are returned as 0.
+func (d *decoder) compactArrayLen() int {
+	n := d.uvarint()
+	if n == 0 {
+		return 0
+	}
+
+	if n-1 > uint64(len(d.b)) {
+		d.err = errShortBuffer
+		return 0
+	}
+
+	return int(n - 1)
+}
+
+// compactNullableString returns the decoded flexible
+// version string and false if the string is null.
+func (d *decoder) compactNullableString() (string, bool) {
+	n := d.uvarint()
+	if n == 0 {
+		return "", false
+	}
+
+	if n-1 > uint64(len(d.b)) {
+		d.err = errShortBuffer
+		return "", false
+	}
+
+	return string(d.next(int(n - 1))), true
+}
+
+func (d *decoder) compactString() string {
+	s, _ := d.compactNullableString()
+	return s
+}
+
+// int32Array decodes an array of int32 values.
+func (d *decoder) int32Array() []int32 {
+	var v []int32
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		v = append(v, d.int32())
+	}
+
+	return v
+}
+
+// compactInt32Array decodes a flexible
+// version array of int32 values.
+func (d *decoder) compactInt32Array() []int32 {
+	var v []int32
+	for i, n := 0, d.compactArrayLen(); i < n; i++ {
+		v = append(v, d.int32())
+	}
+
+	return v
+}
+
+// taggedFields skips a tagged fields section;
+// no tagged fields are currently used.
+func (d *decoder) taggedFields() {
+	for i, n := 0, d.uvarint(); i < int(n) && d.err == nil; i++ {
+		d.uvarint() // Tag.
+		size := d.uvarint()
+		if size > uint64(len(d.b)) {
+			d.err = errShortBuffer
+			return
+		}
+		d.next(int(size))
+	}
+}
+
+// encodeRequest returns a size delimited request with a
+// v1 (or v2, for flexible versions) request header for
+// the API key, version and body.
+func encodeRequest(key, version int16, correlationID int32, clientID string, body []byte) []byte {
+	e := &encoder{}
+	e.int16(key)
+	e.int16(version)
+	e.int32(correlationID)
+	e.string(clientID)
+	if flexible(key) {
+		e.taggedFields()
+	}
+	e.b = append(e.b, body...)
+
+	framed := make([]byte, 4, 4+len(e.b))
+	binary.BigEndian.PutUint32(framed, uint32(len(e.b)))
+
+	return append(framed, e.b...)
+}
+
+// broker is a broker from a metadata response.
+type broker struct {
+	id   int32
+	host string
+	port int32
+	rack string
+}
+
+// partitionMetadata is the state of a
+// partition from a metadata response.
+type partitionMetadata struct {
+	leader   int32
+	replicas []int32
+	isr      []int32
+}
+
+// metadata holds a decoded metadata response.
+type metadata struct {
+	brokers    []broker
+	controller int32
+	// Partition leaders by topic
+	// and partition, if requested.
+	leaders map[string]map[int32]int32
+	// Partition state by topic
+	// and partition, if requested.
+	partitions map[string]map[int32]partitionMetadata
+}
+
+// encodeMetadataRequest returns a metadata request body
+// for no topics (only brokers and the controller).
+func encodeMetadataRequest() []byte {
+	e := &encoder{}
+	e.arrayLen(0)
+	return e.b
+}
+
+// encodeTopicMetadataRequest returns a
+// metadata request body for the topics.
+func encodeTopicMetadataRequest(topics []string) []byte {
+	e := &encoder{}
+	e.arrayLen(len(topics))
+	for _, t := range topics {
+		e.string(t)
+	}
+	return e.b
+}
+
+// encodeAllTopicsMetadataRequest returns a metadata request body
+// for all topics. Unlike requests for named topics, this never
+// auto-creates topics on brokers with auto.create.topics.enable.
+func encodeAllTopicsMetadataRequest() []byte {
+	e := &encoder{}
+	// A null topics array
+	// requests all topics.
+	e.arrayLen(-1)
+	return e.b
+}
+
+// decodeMetadataResponse decodes a metadata response. The
+// first topic error is returned with the metadata of all
+// topics without errors.
+func decodeMetadataResponse(d *decoder) (*metadata, error) {
+	m := &metadata{}
+
+	n := d.arrayLen()
+	for i := 0; i < n; i++ {
+		b := broker{}
+		b.id = d.int32()
+		b.host = d.string()
+		b.port = d.int32()
+		b.rack, _ = d.nullableString()
+		m.brokers = append(m.brokers, b)
+	}
+
+	m.controller = d.int32()
+	m.leaders = map[string]map[int32]int32{}
+	m.partitions = map[string]map[int32]partitionMetadata{}
+
+	var first error
+
+	for i, nt := 0, d.arrayLen(); i < nt; i++ {
+		code := d.int16()
+		topic := d.string()
+		d.bool() // Internal.
+
+		partitions := map[int32]partitionMetadata{}
+
+		for j, np := 0, d.arrayLen(); j < np; j++ {
+			d.int16() // Partition error code.
+			p := d.int32()
+			pm := partitionMetadata{leader: d.int32()}
+			pm.replicas = d.int32Array()
+			pm.isr = d.int32Array()
+			partitions[p] = pm
+		}
+
+		if code != 0 {
+			if first == nil {
+				first = &ResourceError{Type: ResourceTopic, Name: topic, Err: &Error{Code: code}}
+			}
+			continue
+		}
+
+		m.leaders[topic] = map[int32]int32{}
+		for p, pm := range partitions {
+			m.leaders[topic][p] = pm.leader
+		}
+
+		m.partitions[topic] = partitions
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return m, first
+}
+
+// ConfigResource is a topic or broker and
+// the config operations to be applied.
+type ConfigResource struct {
+	Type    ResourceType
+	Name    string
+	Configs []AlterConfig
+}
+
+// AlterConfig is an incremental config operation.
+type AlterConfig struct {
+	Name  string
+	Op    ConfigOp
+	Value string
+}
+
+func encodeIncrementalAlterConfigsRequest(rs []ConfigResource) []byte {
+	e := &encoder{}
+
+	e.arrayLen(len(rs))
+	for _, r := range rs {
+		e.int8(int8(r.Type))
+		e.string(r.Name)
+		e.arrayLen(len(r.Configs))
+		for _, c := range r.Configs {
+			e.string(c.Name)
+			e.int8(int8(c.Op))
+			if c.Op == OpDelete {
+				e.nullableString(nil)
+			} else {
+				v := c.Value
+				e.nullableString(&v)
+			}
+		}
+	}
+
+	// Validate only.
+	e.bool(false)
+
+	return e.b
+}
+
+// decodeResourceError decodes a per-resource error result,
+// common to the alter configs and describe configs responses.
+func decodeResourceError(d *decoder) (ResourceType, string, error) {
+	code := d.int16()
+	msg, _ := d.nullableString()
+	t := ResourceType(d.int8())
+	name := d.string()
+
+	if code != 0 {
+		return t, name, &Error{Code: code, Message: msg}
+	}
+
+	return t, name, nil
+}
+
+func decodeIncrementalAlterConfigsResponse(d *decoder) error {
+	d.int32() // Throttle time.
+
+	var first error
+	n := d.arrayLen()
+	for i := 0; i < n; i++ {
+		t, name, err := decodeResourceError(d)
+		if err != nil && first == nil {
+			first = &ResourceError{Type: t, Name: name, Err: err}
+		}
+	}
+
+	if d.err != nil {
+		return d.err
+	}
+
+	return first
+}
+
+// ConfigSource is the source of a described config value.
+type ConfigSource int8
+
+// Config sources.
+const (
+	SourceUnknown              ConfigSource = 0
+	SourceDynamicTopic         ConfigSource = 1
+	SourceDynamicBroker        ConfigSource = 2
+	SourceDynamicDefaultBroker ConfigSource = 3
+	SourceStaticBroker         ConfigSource = 4
+	SourceDefault              ConfigSource = 5
+)
+
+// ConfigEntry is a described config value.
+type ConfigEntry struct {
+	Name      string
+	Value     string
+	ReadOnly  bool
+	IsDefault bool
+	Sensitive bool
+	// Source is where the value is set, e.g.
+	// SourceDynamicTopic for topic overrides.
+	Source ConfigSource
+}
+
+func encodeDescribeConfigsRequest(t ResourceType, name string, keys []string) []byte {
+	e := &encoder{}
+
+	e.arrayLen(1)
+	e.int8(int8(t))
+	e.string(name)
+
+	if keys == nil {
+		e.arrayLen(-1)
+	} else {
+		e.arrayLen(len(keys))
+		for _, k := range keys {
+			e.string(k)
+		}
+	}
+
+	// Include synonyms.
+	e.bool(false)
+
+	return e.b
+}
+
+func decodeDescribeConfigsResponse(d *decoder) (map[string]ConfigEntry, error) {
+	d.int32() // Throttle time.
+
+	entries := map[string]ConfigEntry{}
+	var first error
+
+	n := d.arrayLen()
+	for i := 0; i < n; i++ {
+		t, name, err := decodeResourceError(d)
+		if err != nil && first == nil {
+			first = &ResourceError{Type: t, Name: name, Err: err}
+		}
+
+		nc := d.arrayLen()
+		for j := 0; j < nc; j++ {
+			c := ConfigEntry{}
+			c.Name = d.string()
+			c.Value, _ = d.nullableString()
+			c.ReadOnly = d.bool()
+			c.Source = ConfigSource(d.int8())
+			c.IsDefault = c.Source == SourceDefault
+			c.Sensitive = d.bool()
+			for k, ns := 0, d.arrayLen(); k < ns; k++ {
+				// Synonyms.
+				d.string()
+				d.nullableString()
+				d.int8()
+			}
+			entries[c.Name] = c
+		}
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return entries, first
+}
+
+// encodeListPartitionReassignmentsRequest returns a list partition
+// reassignments request body for all topics.
+func encodeListPartitionReassignmentsRequest(timeout int32) []byte {
+	e := &encoder{}
+
+	e.int32(timeout)
+	// A null topics array
+	// lists all topics.
+	e.compactArrayLen(-1)
+	e.taggedFields()
+
+	return e.b
+}
+
+// decodeListPartitionReassignmentsResponse returns a kafkazk.Reassignments
+// of each reassigning partition to its target replica set. The target is
+// the current replica set without the replicas being removed.
+func decodeListPartitionReassignmentsResponse(d *decoder) (kafkazk.Reassignments, error) {
+	d.int32() // Throttle time.
+	code := d.int16()
+	msg, _ := d.compactNullableString()
+
+	reassignments := kafkazk.Reassignments{}
+
+	for i, nt := 0, d.compactArrayLen(); i < nt; i++ {
+		topic := d.compactString()
+		partitions := map[int][]int{}
+
+		for j, np := 0, d.compactArrayLen(); j < np; j++ {
+			partition := d.int32()
+			replicas := d.compactInt32Array()
+			d.compactInt32Array() // Adding replicas.
+			removing := d.compactInt32Array()
+			d.taggedFields()
+
+			removed := map[int32]struct{}{}
+			for _, r := range removing {
+				removed[r] = struct{}{}
+			}
+
+			target := []int{}
+			for _, r := range replicas {
+				if _, exists := removed[r]; !exists {
+					target = append(target, int(r))
+				}
+			}
+
+			partitions[int(partition)] = target
+		}
+
+		d.taggedFields()
+
+		if len(partitions) > 0 {
+			reassignments[topic] = partitions
+		}
+	}
+
+	d.taggedFields()
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if code != 0 {
+		return nil, &Error{Code: code, Message: msg}
+	}
+
+	return reassignments, nil
+}
+
+// encodeAlterPartitionReassignmentsRequest returns an alter partition
+// reassignments request body, reassigning each partition to its replicas.
+func encodeAlterPartitionReassignmentsRequest(r kafkazk.Reassignments, timeout int32) []byte {
+	e := &encoder{}
+
+	e.int32(timeout)
+
+	var topics []string
+	for t := range r {
+		topics = append(topics, t)
+	}
+
+	sort.Strings(topics)
+
+	e.compactArrayLen(len(topics))
+	for _, t := range topics {
+		var partitions []int
+		for p := range r[t] {
+			partitions = append(partitions, p)
+		}
+
+		sort.Ints(partitions)
+
+		e.compactString(t)
+		e.compactArrayLen(len(partitions))
+		for _, p := range partitions {
+			var replicas []int32
+			for _, id := range r[t][p] {
+				replicas = append(replicas, int32(id))
+			}
+
+			e.int32(int32(p))
+			e.compactInt32Array(replicas)
+			e.taggedFields()
+		}
+		e.taggedFields()
+	}
+
+	e.taggedFields()
+
+	return e.b
+}
+
+// decodeAlterPartitionReassignmentsResponse returns the
+// top level error, or else the first partition error.
+func decodeAlterPartitionReassignmentsResponse(d *decoder) error {
+	d.int32() // Throttle time.
+	code := d.int16()
+	msg, _ := d.compactNullableString()
+
+	var first error
+
+	for i, nt := 0, d.compactArrayLen(); i < nt; i++ {
+		topic := d.compactString()
+
+		for j, np := 0, d.compactArrayLen(); j < np; j++ {
+			d.int32() // Partition.
+			pcode := d.int16()
+			pmsg, _ := d.compactNullableString()
+			d.taggedFields()
+
+			if pcode != 0 && first == nil {
+				first = &ResourceError{Type: ResourceTopic, Name: topic, Err: &Error{Code: pcode, Message: pmsg}}
+			}
+		}
+
+		d.taggedFields()
+	}
+
+	d.taggedFields()
+
+	if d.err != nil {
+		return d.err
+	}
+
+	if code != 0 {
+		return &Error{Code: code, Message: msg}
+	}
+
+	return first
+}
+
+// encodeElectLeadersRequest returns a (preferred)
+// elect leaders request body for the partitions.
+func encodeElectLeadersRequest(partitions map[string][]int, timeout int32) []byte {
+	e := &encoder{}
+
+	var topics []string
+	for t := range partitions {
+		topics = append(topics, t)
+	}
+
+	sort.Strings(topics)
+
+	e.arrayLen(len(topics))
+	for _, t := range topics {
+		e.string(t)
+		e.arrayLen(len(partitions[t]))
+		for _, p := range partitions[t] {
+			e.int32(int32(p))
+		}
+	}
+
+	e.int32(timeout)
+
+	return e.b
+}
+
+// decodeElectLeadersResponse returns the first partition error.
+// Partitions already led by their preferred leader aren't errors.
+func decodeElectLeadersResponse(d *decoder) error {
+	d.int32() // Throttle time.
+
+	var first error
+
+	for i, nt := 0, d.arrayLen(); i < nt; i++ {
+		topic := d.string()
+
+		for j, np := 0, d.arrayLen(); j < np; j++ {
+			d.int32() // Partition.
+			code := d.int16()
+			msg, _ := d.nullableString()
+
+			if code != 0 && code != errElectionNotNeeded && first == nil {
+				first = &ResourceError{Type: ResourceTopic, Name: topic, Err: &Error{Code: code, Message: msg}}
+			}
+		}
+	}
+
+	if d.err != nil {
+		return d.err
+	}
+
+	return first
+}
+
+// encodeFindCoordinatorRequest returns a find
+// coordinator request body for a group.
+func encodeFindCoordinatorRequest(group string) []byte {
+	e := &encoder{}
+	e.string(group)
+	return e.b
+}
+
+func decodeFindCoordinatorResponse(d *decoder) (broker, error) {
+	code := d.int16()
+
+	b := broker{}
+	b.id = d.int32()
+	b.host = d.string()
+	b.port = d.int32()
+
+	if d.err != nil {
+		return b, d.err
+	}
+
+	if code != 0 {
+		return b, &Error{Code: code}
+	}
+
+	return b, nil
+}
+
+func decodeListGroupsResponse(d *decoder) ([]GroupListing, error) {
+	code := d.int16()
+
+	var groups []GroupListing
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		g := GroupListing{}
+		g.GroupID = d.string()
+		g.ProtocolType = d.string()
+		groups = append(groups, g)
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if code != 0 {
+		return nil, &Error{Code: code}
+	}
+
+	return groups, nil
+}
+
+func encodeDescribeGroupsRequest(groups []string) []byte {
+	e := &encoder{}
+	e.arrayLen(len(groups))
+	for _, g := range groups {
+		e.string(g)
+	}
+	return e.b
+}
+
+func decodeDescribeGroupsResponse(d *decoder) ([]*GroupDescription, error) {
+	var groups []*GroupDescription
+	var first error
+
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		code := d.int16()
+
+		g := &GroupDescription{}
+		g.GroupID = d.string()
+		g.State = d.string()
+		g.ProtocolType = d.string()
+		g.Protocol = d.string()
+
+		for j, nm := 0, d.arrayLen(); j < nm; j++ {
+			m := GroupMember{}
+			m.MemberID = d.string()
+			m.ClientID = d.string()
+			m.ClientHost = d.string()
+			d.bytes() // Member metadata.
+			assignment := d.bytes()
+
+			if g.ProtocolType == consumerProtocolType {
+				m.Assignment = decodeConsumerAssignment(assignment)
+			}
+
+			g.Members = append(g.Members, m)
+		}
+
+		if code != 0 && first == nil {
+			first = &Error{Code: code, Message: g.GroupID}
+		}
+
+		groups = append(groups, g)
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return groups, first
+}
+
+// decodeConsumerAssignment decodes a consumer protocol member
+// assignment as a map of topic to assigned partitions. Malformed
+// assignments (e.g. from non-Java clients with extensions) are
+// returned as decoded up to the error.
+func decodeConsumerAssignment(b []byte) map[string][]int {
+	assignment := map[string][]int{}
+	if len(b) == 0 {
+		return assignment
+	}
+
+	d := &decoder{b: b}
+	d.int16() // Version.
+
+	for i, n := 0, d.arrayLen(); i < n && d.err == nil; i++ {
+		topic := d.string()
+		for j, np := 0, d.arrayLen(); j < np; j++ {
+			p := d.int32()
+			if d.err == nil {
+				assignment[topic] = append(assignment[topic], int(p))
+			}
+		}
+	}
+
+	return assignment
+}
+
+// encodeOffsetFetchRequest returns an offset fetch
+// request body for all partitions of a group.
+func encodeOffsetFetchRequest(group string) []byte {
+	e := &encoder{}
+	e.string(group)
+	// A null topics array
+	// fetches all topics.
+	e.arrayLen(-1)
+	return e.b
+}
+
+func decodeOffsetFetchResponse(d *decoder) (Offsets, error) {
+	offsets := Offsets{}
+	var first error
+
+	for i, nt := 0, d.arrayLen(); i < nt; i++ {
+		topic := d.string()
+		offsets[topic] = map[int]int64{}
+
+		for j, np := 0, d.arrayLen(); j < np; j++ {
+			p := d.int32()
+			offset := d.int64()
+			d.nullableString() // Metadata.
+			code := d.int16()
+
+			if code != 0 && first == nil {
+				first = &ResourceError{Type: ResourceTopic, Name: topic, Err: &Error{Code: code}}
+			}
+
+			offsets[topic][int(p)] = offset
+		}
+	}
+
+	code := d.int16()
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if code != 0 {
+		return nil, &Error{Code: code}
+	}
+
+	return offsets, first
+}
+
+// encodeOffsetCommitRequest returns an offset commit request body
+// for the offsets of a group. Commits are made as an admin client
+// would: with no generation or member ID, which the coordinator only
+// accepts for groups without active members.
+func encodeOffsetCommitRequest(group string, offsets Offsets) []byte {
+	e := &encoder{}
+
+	e.string(group)
+	e.int32(-1)  // Generation ID.
+	e.string("") // Member ID.
+	e.int64(-1)  // Retention time; the broker default.
+	e.arrayLen(len(offsets))
+	for t, ps := range offsets {
+		e.string(t)
+		e.arrayLen(len(ps))
+		for p, o := range ps {
+			e.int32(int32(p))
+			e.int64(o)
+			e.nullableString(nil) // Metadata.
+		}
+	}
+
+	return e.b
+}
+
+func decodeOffsetCommitResponse(d *decoder) error {
+	var first error
+
+	for i, nt := 0, d.arrayLen(); i < nt; i++ {
+		topic := d.string()
+		for j, np := 0, d.arrayLen(); j < np; j++ {
+			d.int32() // Partition.
+			code := d.int16()
+
+			if code != 0 && first == nil {
+				first = &ResourceError{Type: ResourceTopic, Name: topic, Err: &Error{Code: code}}
+			}
+		}
+	}
+
+	if d.err != nil {
+		return d.err
+	}
+
+	return first
+}
+
+// encodeListOffsetsRequest returns a list offsets request body
+// for the offset at timestamp of each partition.
+func encodeListOffsetsRequest(partitions map[string][]int, timestamp int64) []byte {
+	e := &encoder{}
+
+	e.int32(-1) // Replica ID.
+	e.arrayLen(len(partitions))
+	for t, ps := range partitions {
+		e.string(t)
+		e.arrayLen(len(ps))
+		for _, p := range ps {
+			e.int32(int32(p))
+			e.int64(timestamp)
+		}
+	}
+
+	return e.b
+}
+
+func decodeListOffsetsResponse(d *decoder) (Offsets, error) {
+	offsets := Offsets{}
+	var first error
+
+	for i, nt := 0, d.arrayLen(); i < nt; i++ {
+		topic := d.string()
+		offsets[topic] = map[int]int64{}
+
+		for j, np := 0, d.arrayLen(); j < np; j++ {
+			p := d.int32()
+			code := d.int16()
+			d.int64() // Timestamp.
+			offset := d.int64()
+
+			if code != 0 && first == nil {
+				first = &ResourceError{Type: ResourceTopic, Name: topic, Err: &Error{Code: code}}
+			}
+
+			offsets[topic][int(p)] = offset
+		}
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return offsets, first
+}
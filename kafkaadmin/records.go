@@ -0,0 +1,339 @@
+package kafkaadmin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"time"
+)
+
+// Record batch attributes.
+const (
+	compressionMask = 0x07
+	compressionGzip = 1
+	controlBatch    = 0x20
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrUnsupportedRecords error.
+var ErrUnsupportedRecords = errors.New("Unsupported record format or compression codec")
+
+// Record is a Kafka record. A Record
+// with a nil Value is a tombstone.
+type Record struct {
+	Offset int64
+	Key    []byte
+	Value  []byte
+}
+
+// Produce takes a topic, partition and records and appends the records to
+// the partition, acknowledged by all in-sync replicas. The offset of the
+// first record is returned. Records are sent uncompressed in a single
+// record batch (magic v2).
+func (c *Client) Produce(topic string, partition int, records []Record) (int64, error) {
+	if len(records) == 0 {
+		return 0, errors.New("No records specified")
+	}
+
+	addr, err := c.leaderAddr(topic, partition)
+	if err != nil {
+		return 0, err
+	}
+
+	req := encodeProduceRequest(topic, partition, int32(c.timeout/time.Millisecond), encodeRecordBatch(0, records))
+
+	d, err := c.request(addr, apiProduce, produceVersion, req)
+	if err != nil {
+		return 0, err
+	}
+
+	return decodeProduceResponse(d)
+}
+
+// Fetch takes a topic, partition and offset and returns records of the
+// partition from the offset, up to approximately maxBytes, along with the
+// partition high watermark. Fewer records than are available may be
+// returned; all records are read by fetching from the offset following
+// the last record returned until the high watermark is reached. Offsets
+// may be non-contiguous in compacted topics.
+func (c *Client) Fetch(topic string, partition int, offset int64, maxBytes int) ([]Record, int64, error) {
+	addr, err := c.leaderAddr(topic, partition)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	d, err := c.request(addr, apiFetch, fetchVersion, encodeFetchRequest(topic, partition, offset, int32(maxBytes)))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data, hw, err := decodeFetchResponse(d)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	records, err := decodeRecordBatches(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Batches may start before the offset.
+	var fetched []Record
+	for _, r := range records {
+		if r.Offset >= offset {
+			fetched = append(fetched, r)
+		}
+	}
+
+	return fetched, hw, nil
+}
+
+// leaderAddr returns the address of the leader of a partition.
+func (c *Client) leaderAddr(topic string, partition int) (string, error) {
+	leaders, err := c.leaders(map[string][]int{topic: []int{partition}})
+	if err != nil {
+		return "", err
+	}
+
+	l, exists := leaders[topic][int32(partition)]
+	if !exists || l < 0 {
+		return "", fmt.Errorf("No leader for %s partition %d", topic, partition)
+	}
+
+	addr, exists := c.brokerAddr(l)
+	if !exists {
+		return "", ErrUnknownBroker{ID: int(l)}
+	}
+
+	return addr, nil
+}
+
+// encodeRecordBatch returns an uncompressed
+// record batch of the records from baseOffset.
+func encodeRecordBatch(baseOffset int64, records []Record) []byte {
+	ts := time.Now().UnixNano() / int64(time.Millisecond)
+
+	// The CRC covers the attributes
+	// through the end of the batch.
+	body := &encoder{}
+	body.int16(0) // Attributes.
+	body.int32(int32(len(records) - 1))
+	body.int64(ts) // First timestamp.
+	body.int64(ts) // Max timestamp.
+	body.int64(-1) // Producer ID.
+	body.int16(-1) // Producer epoch.
+	body.int32(-1) // Base sequence.
+	body.arrayLen(len(records))
+
+	for i, r := range records {
+		rec := &encoder{}
+		rec.int8(0)   // Attributes.
+		rec.varint(0) // Timestamp delta.
+		rec.varint(int64(i))
+		rec.varintBytes(r.Key)
+		rec.varintBytes(r.Value)
+		rec.varint(0) // Headers.
+
+		body.varint(int64(len(rec.b)))
+		body.b = append(body.b, rec.b...)
+	}
+
+	e := &encoder{}
+	e.int64(baseOffset)
+	// Partition leader epoch, magic
+	// and CRC precede the body.
+	e.int32(int32(4 + 1 + 4 + len(body.b)))
+	e.int32(-1)
+	e.int8(2)
+	e.int32(int32(crc32.Checksum(body.b, castagnoli)))
+	e.b = append(e.b, body.b...)
+
+	return e.b
+}
+
+// decodeRecordBatches returns the records of the record batches (magic v2)
+// in b. Control batches are skipped, as is a trailing partial batch, which
+// fetch responses may include.
+func decodeRecordBatches(b []byte) ([]Record, error) {
+	var records []Record
+
+	for len(b) >= 12 {
+		d := &decoder{b: b}
+		baseOffset := d.int64()
+		length := int(d.int32())
+
+		if len(d.b) < length {
+			break
+		}
+
+		d.b, b = d.b[:length], d.b[length:]
+
+		d.int32() // Partition leader epoch.
+		if magic := d.int8(); magic != 2 {
+			return nil, ErrUnsupportedRecords
+		}
+
+		crc := uint32(d.int32())
+		if d.err == nil && crc32.Checksum(d.b, castagnoli) != crc {
+			return nil, &Error{Code: 2}
+		}
+
+		attributes := d.int16()
+		d.int32() // Last offset delta.
+		d.int64() // First timestamp.
+		d.int64() // Max timestamp.
+		d.int64() // Producer ID.
+		d.int16() // Producer epoch.
+		d.int32() // Base sequence.
+		n := d.arrayLen()
+
+		if d.err != nil {
+			return nil, d.err
+		}
+
+		if attributes&controlBatch != 0 {
+			continue
+		}
+
+		switch attributes & compressionMask {
+		case 0:
+		case compressionGzip:
+			r, err := gzip.NewReader(bytes.NewReader(d.b))
+			if err != nil {
+				return nil, err
+			}
+
+			if d.b, err = ioutil.ReadAll(r); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, ErrUnsupportedRecords
+		}
+
+		for i := 0; i < n; i++ {
+			d.varint() // Length.
+			d.int8()   // Attributes.
+			d.varint() // Timestamp delta.
+			r := Record{Offset: baseOffset + d.varint()}
+			r.Key = d.varintBytes()
+			r.Value = d.varintBytes()
+
+			for j, nh := 0, int(d.varint()); j < nh; j++ {
+				d.varintBytes() // Header key.
+				d.varintBytes() // Header value.
+			}
+
+			records = append(records, r)
+		}
+
+		if d.err != nil {
+			return nil, d.err
+		}
+	}
+
+	return records, nil
+}
+
+func encodeProduceRequest(topic string, partition int, timeout int32, batch []byte) []byte {
+	e := &encoder{}
+
+	e.nullableString(nil) // Transactional ID.
+	e.int16(-1)           // Acks (all).
+	e.int32(timeout)
+	e.arrayLen(1)
+	e.string(topic)
+	e.arrayLen(1)
+	e.int32(int32(partition))
+	e.bytes(batch)
+
+	return e.b
+}
+
+func decodeProduceResponse(d *decoder) (int64, error) {
+	var offset int64
+	var first error
+
+	for i, nt := 0, d.arrayLen(); i < nt; i++ {
+		topic := d.string()
+		for j, np := 0, d.arrayLen(); j < np; j++ {
+			d.int32() // Partition.
+			code := d.int16()
+			offset = d.int64()
+			d.int64() // Log append time.
+
+			if code != 0 && first == nil {
+				first = &ResourceError{Type: ResourceTopic, Name: topic, Err: &Error{Code: code}}
+			}
+		}
+	}
+
+	d.int32() // Throttle time.
+
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	return offset, first
+}
+
+// encodeFetchRequest returns a fetch request body for the
+// partition that is answered immediately (without waiting
+// for records) with read uncommitted isolation.
+func encodeFetchRequest(topic string, partition int, offset int64, maxBytes int32) []byte {
+	e := &encoder{}
+
+	e.int32(-1) // Replica ID.
+	e.int32(0)  // Max wait time.
+	e.int32(0)  // Min bytes.
+	e.int32(maxBytes)
+	e.int8(0) // Isolation level.
+	e.arrayLen(1)
+	e.string(topic)
+	e.arrayLen(1)
+	e.int32(int32(partition))
+	e.int64(offset)
+	e.int32(maxBytes)
+
+	return e.b
+}
+
+// decodeFetchResponse returns the record batches and
+// high watermark of the single fetched partition.
+func decodeFetchResponse(d *decoder) ([]byte, int64, error) {
+	var records []byte
+	var hw int64
+	var first error
+
+	d.int32() // Throttle time.
+
+	for i, nt := 0, d.arrayLen(); i < nt; i++ {
+		topic := d.string()
+		for j, np := 0, d.arrayLen(); j < np; j++ {
+			d.int32() // Partition.
+			code := d.int16()
+			hw = d.int64()
+			d.int64() // Last stable offset.
+
+			for k, na := 0, d.arrayLen(); k < na; k++ {
+				d.int64() // Producer ID.
+				d.int64() // First offset.
+			}
+
+			records = d.bytes()
+
+			if code != 0 && first == nil {
+				first = &ResourceError{Type: ResourceTopic, Name: topic, Err: &Error{Code: code}}
+			}
+		}
+	}
+
+	if d.err != nil {
+		return nil, 0, d.err
+	}
+
+	return records, hw, first
+}
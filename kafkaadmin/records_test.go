@@ -0,0 +1,237 @@
+package kafkaadmin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"testing"
+)
+
+func (b *mockBroker) produce(d *decoder, e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	d.nullableString() // Transactional ID.
+	d.int16()          // Acks.
+	d.int32()          // Timeout.
+
+	nt := d.arrayLen()
+	e.arrayLen(nt)
+	for i := 0; i < nt; i++ {
+		t := d.string()
+		e.string(t)
+
+		np := d.arrayLen()
+		e.arrayLen(np)
+		for j := 0; j < np; j++ {
+			p := d.int32()
+			records, err := decodeRecordBatches(d.bytes())
+
+			var code int16
+			base := b.logEnd[t][int(p)]
+
+			switch _, exists := b.logEnd[t][int(p)]; {
+			case !exists:
+				code = 3
+			case err != nil:
+				code = 2
+			default:
+				if b.records == nil {
+					b.records = map[string]map[int32][]Record{}
+				}
+				if b.records[t] == nil {
+					b.records[t] = map[int32][]Record{}
+				}
+
+				for _, r := range records {
+					r.Offset = b.logEnd[t][int(p)]
+					b.records[t][p] = append(b.records[t][p], r)
+					b.logEnd[t][int(p)]++
+				}
+			}
+
+			e.int32(p)
+			e.int16(code)
+			e.int64(base)
+			e.int64(-1) // Log append time.
+		}
+	}
+
+	e.int32(0) // Throttle time.
+}
+
+func (b *mockBroker) fetch(d *decoder, e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	d.int32() // Replica ID.
+	d.int32() // Max wait time.
+	d.int32() // Min bytes.
+	d.int32() // Max bytes.
+	d.int8()  // Isolation level.
+
+	e.int32(0) // Throttle time.
+
+	nt := d.arrayLen()
+	e.arrayLen(nt)
+	for i := 0; i < nt; i++ {
+		t := d.string()
+		e.string(t)
+
+		np := d.arrayLen()
+		e.arrayLen(np)
+		for j := 0; j < np; j++ {
+			p := d.int32()
+			offset := d.int64()
+			d.int32() // Partition max bytes.
+
+			var code int16
+			if _, exists := b.logEnd[t][int(p)]; !exists {
+				code = 3
+			}
+
+			// Batches start at even offsets, which may
+			// precede the fetch offset, as in Kafka.
+			var batch []byte
+			start := offset - offset%2
+			if rs := b.records[t][p]; start < int64(len(rs)) {
+				batch = encodeRecordBatch(start, rs[start:])
+			}
+
+			e.int32(p)
+			e.int16(code)
+			e.int64(b.logEnd[t][int(p)])
+			e.int64(b.logEnd[t][int(p)])
+			e.arrayLen(0) // Aborted transactions.
+			e.bytes(batch)
+		}
+	}
+}
+
+func TestProduceFetch(t *testing.T) {
+	b := newMockBroker(t, 1001)
+	defer b.close()
+
+	b.logEnd = Offsets{"tags": {0: 0}}
+
+	c, err := NewClient(Config{BootstrapServers: b.addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := []Record{
+		{Key: []byte("topic/a"), Value: []byte(`{"team":"a"}`)},
+		{Key: []byte("topic/b"), Value: []byte(`{"team":"b"}`)},
+	}
+
+	offset, err := c.Produce("tags", 0, records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if offset != 0 {
+		t.Errorf("Expected offset 0, got %d", offset)
+	}
+
+	offset, err = c.Produce("tags", 0, []Record{{Key: []byte("topic/c")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if offset != 2 {
+		t.Errorf("Expected offset 2, got %d", offset)
+	}
+
+	// Records before the offset in the
+	// fetched batch are omitted.
+	fetched, hw, err := c.Fetch("tags", 0, 1, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hw != 3 {
+		t.Errorf("Expected high watermark 3, got %d", hw)
+	}
+
+	if len(fetched) != 2 || fetched[0].Offset != 1 || string(fetched[0].Key) != "topic/b" {
+		t.Fatalf("Unexpected records %v", fetched)
+	}
+
+	// Tombstones.
+	if fetched[1].Offset != 2 || fetched[1].Value != nil {
+		t.Errorf("Expected a tombstone at offset 2, got %v", fetched[1])
+	}
+
+	if _, err := c.Produce("other", 0, records); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	if _, _, err := c.Fetch("other", 0, 0, 1<<20); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
+
+// gzipRecordBatch returns the uncompressed
+// record batch with gzip compression.
+func gzipRecordBatch(batch []byte) []byte {
+	// Base offset through record count.
+	header := append([]byte{}, batch[:61]...)
+
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	w.Write(batch[61:])
+	w.Close()
+
+	b := append(header, buf.Bytes()...)
+	binary.BigEndian.PutUint32(b[8:], uint32(len(b)-12))
+	binary.BigEndian.PutUint16(b[21:], compressionGzip)
+	binary.BigEndian.PutUint32(b[17:], crc32.Checksum(b[21:], castagnoli))
+
+	return b
+}
+
+func TestDecodeRecordBatches(t *testing.T) {
+	batch := encodeRecordBatch(5, []Record{
+		{Key: []byte("k1"), Value: []byte("v1")},
+		{Key: []byte("k2")},
+	})
+
+	// Batches, including a compressed
+	// batch and a trailing partial batch.
+	data := append(append([]byte{}, batch...), gzipRecordBatch(encodeRecordBatch(7, []Record{{Key: []byte("k3")}}))...)
+	data = append(data, batch[:20]...)
+
+	records, err := decodeRecordBatches(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"5:k1:v1", "6:k2:", "7:k3:"}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d records, got %d", len(expected), len(records))
+	}
+
+	for i, r := range records {
+		if got := fmt.Sprintf("%d:%s:%s", r.Offset, r.Key, r.Value); got != expected[i] {
+			t.Errorf("Expected record %s, got %s", expected[i], got)
+		}
+	}
+
+	// Corrupt.
+	corrupt := append([]byte{}, batch...)
+	corrupt[len(corrupt)-1]++
+
+	if _, err := decodeRecordBatches(corrupt); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	// Message set (magic v1).
+	old := append([]byte{}, batch...)
+	old[16] = 1
+
+	if _, err := decodeRecordBatches(old); err != ErrUnsupportedRecords {
+		t.Errorf("Expected error '%s', got '%v'", ErrUnsupportedRecords, err)
+	}
+}
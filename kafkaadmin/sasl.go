@@ -0,0 +1,279 @@
+package kafkaadmin
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// SASL mechanisms.
+const (
+	SASLPlain       = "PLAIN"
+	SASLSCRAMSHA256 = "SCRAM-SHA-256"
+	SASLSCRAMSHA512 = "SCRAM-SHA-512"
+)
+
+// SASLConfig holds SASL authentication parameters.
+type SASLConfig struct {
+	// One of SASLPlain, SASLSCRAMSHA256
+	// or SASLSCRAMSHA512.
+	Mechanism string
+	Username  string
+	Password  string
+}
+
+// validate checks that the mechanism is supported.
+func (s *SASLConfig) validate() error {
+	switch s.Mechanism {
+	case SASLPlain, SASLSCRAMSHA256, SASLSCRAMSHA512:
+	default:
+		return fmt.Errorf("Unsupported SASL mechanism %s", s.Mechanism)
+	}
+
+	if s.Username == "" {
+		return errors.New("SASL username must be specified")
+	}
+
+	return nil
+}
+
+// authenticate performs the SASL handshake and authentication
+// exchange on a new connection to addr.
+func (c *Client) authenticate(conn net.Conn, addr string) error {
+	// Handshake.
+	e := &encoder{}
+	e.string(c.sasl.Mechanism)
+
+	d, err := c.roundTrip(conn, addr, apiSaslHandshake, saslHandshakeVersion, e.b)
+	if err != nil {
+		return err
+	}
+
+	code := d.int16()
+	var enabled []string
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		enabled = append(enabled, d.string())
+	}
+
+	if d.err != nil {
+		return d.err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("SASL handshake with %s failed: %s (enabled mechanisms: %s)",
+			addr, &Error{Code: code}, strings.Join(enabled, ", "))
+	}
+
+	// Authentication.
+	if c.sasl.Mechanism == SASLPlain {
+		msg := "\x00" + c.sasl.Username + "\x00" + c.sasl.Password
+		_, err := c.saslAuthenticate(conn, addr, []byte(msg))
+		return err
+	}
+
+	s, err := newSCRAMClient(c.sasl.Mechanism, c.sasl.Username, c.sasl.Password)
+	if err != nil {
+		return err
+	}
+
+	serverFirst, err := c.saslAuthenticate(conn, addr, s.first())
+	if err != nil {
+		return err
+	}
+
+	final, err := s.final(serverFirst)
+	if err != nil {
+		return err
+	}
+
+	serverFinal, err := c.saslAuthenticate(conn, addr, final)
+	if err != nil {
+		return err
+	}
+
+	return s.verify(serverFinal)
+}
+
+// saslAuthenticate sends a SaslAuthenticate request with the auth
+// bytes and returns the auth bytes from the response.
+func (c *Client) saslAuthenticate(conn net.Conn, addr string, auth []byte) ([]byte, error) {
+	e := &encoder{}
+	e.bytes(auth)
+
+	d, err := c.roundTrip(conn, addr, apiSaslAuthenticate, saslAuthenticateVersion, e.b)
+	if err != nil {
+		return nil, err
+	}
+
+	code := d.int16()
+	msg, _ := d.nullableString()
+	resp := d.bytes()
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if code != 0 {
+		return nil, fmt.Errorf("SASL authentication with %s failed: %s", addr, &Error{Code: code, Message: msg})
+	}
+
+	return resp, nil
+}
+
+// scramClient is the client side of
+// a SCRAM exchange (RFC 5802).
+type scramClient struct {
+	hash     func() hash.Hash
+	username string
+	password string
+	nonce    string
+	// Messages retained for the auth message.
+	clientFirstBare string
+	serverSignature []byte
+}
+
+func newSCRAMClient(mechanism, username, password string) (*scramClient, error) {
+	s := &scramClient{username: username, password: password}
+
+	switch mechanism {
+	case SASLSCRAMSHA256:
+		s.hash = sha256.New
+	case SASLSCRAMSHA512:
+		s.hash = sha512.New
+	default:
+		return nil, fmt.Errorf("Unsupported SASL mechanism %s", mechanism)
+	}
+
+	nonce := make([]byte, 24)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	s.nonce = base64.RawStdEncoding.EncodeToString(nonce)
+
+	return s, nil
+}
+
+// first returns the client-first-message.
+func (s *scramClient) first() []byte {
+	// Usernames escape = and , per the RFC.
+	user := strings.NewReplacer("=", "=3D", ",", "=2C").Replace(s.username)
+	s.clientFirstBare = fmt.Sprintf("n=%s,r=%s", user, s.nonce)
+
+	return []byte("n,," + s.clientFirstBare)
+}
+
+// final takes the server-first-message and
+// returns the client-final-message.
+func (s *scramClient) final(serverFirst []byte) ([]byte, error) {
+	attrs := scramAttrs(string(serverFirst))
+
+	if e, exists := attrs["e"]; exists {
+		return nil, fmt.Errorf("SCRAM authentication failed: %s", e)
+	}
+
+	nonce := attrs["r"]
+	if !strings.HasPrefix(nonce, s.nonce) || len(nonce) == len(s.nonce) {
+		return nil, errors.New("SCRAM authentication failed: invalid server nonce")
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(attrs["s"])
+	if err != nil {
+		return nil, fmt.Errorf("SCRAM authentication failed: invalid salt: %s", err)
+	}
+
+	iterations, err := strconv.Atoi(attrs["i"])
+	if err != nil || iterations < 1 {
+		return nil, fmt.Errorf("SCRAM authentication failed: invalid iteration count %s", attrs["i"])
+	}
+
+	// c=biws is the base64 encoded
+	// gs2 header (n,,).
+	withoutProof := "c=biws,r=" + nonce
+	authMessage := s.clientFirstBare + "," + string(serverFirst) + "," + withoutProof
+
+	salted := pbkdf2(s.hash, []byte(s.password), salt, iterations)
+	clientKey := s.hmac(salted, "Client Key")
+	storedKey := s.hash()
+	storedKey.Write(clientKey)
+
+	proof := s.hmac(storedKey.Sum(nil), authMessage)
+	for i := range proof {
+		proof[i] ^= clientKey[i]
+	}
+
+	s.serverSignature = s.hmac(s.hmac(salted, "Server Key"), authMessage)
+
+	return []byte(withoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof)), nil
+}
+
+// verify takes the server-final-message and
+// verifies the server signature.
+func (s *scramClient) verify(serverFinal []byte) error {
+	attrs := scramAttrs(string(serverFinal))
+
+	if e, exists := attrs["e"]; exists {
+		return fmt.Errorf("SCRAM authentication failed: %s", e)
+	}
+
+	v, err := base64.StdEncoding.DecodeString(attrs["v"])
+	if err != nil || !hmac.Equal(v, s.serverSignature) {
+		return errors.New("SCRAM authentication failed: invalid server signature")
+	}
+
+	return nil
+}
+
+func (s *scramClient) hmac(key []byte, msg string) []byte {
+	h := hmac.New(s.hash, key)
+	h.Write([]byte(msg))
+	return h.Sum(nil)
+}
+
+// scramAttrs parses a SCRAM message of
+// comma-delimited key=value attributes.
+func scramAttrs(msg string) map[string]string {
+	attrs := map[string]string{}
+	for _, a := range strings.Split(msg, ",") {
+		if len(a) > 1 && a[1] == '=' {
+			attrs[a[:1]] = a[2:]
+		}
+	}
+
+	return attrs
+}
+
+// pbkdf2 returns the PBKDF2 (RFC 8018) derived key of the
+// password, with a key length of the hash output size.
+func pbkdf2(h func() hash.Hash, password, salt []byte, iterations int) []byte {
+	prf := hmac.New(h, password)
+
+	// U1 is the HMAC of the salt and
+	// the block index (always 1).
+	idx := make([]byte, 4)
+	binary.BigEndian.PutUint32(idx, 1)
+	prf.Write(salt)
+	prf.Write(idx)
+	u := prf.Sum(nil)
+
+	key := append([]byte{}, u...)
+	for i := 1; i < iterations; i++ {
+		prf.Reset()
+		prf.Write(u)
+		u = prf.Sum(u[:0])
+		for j := range key {
+			key[j] ^= u[j]
+		}
+	}
+
+	return key
+}
@@ -0,0 +1,273 @@
+package kafkaadmin
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"strings"
+	"testing"
+)
+
+// mockSASL holds the SASL mechanism
+// and credentials of a mockBroker.
+type mockSASL struct {
+	mechanism string
+	username  string
+	password  string
+}
+
+// mockSASLConn is the SASL state
+// of a connection to a mockBroker.
+type mockSASLConn struct {
+	config *mockSASL
+	done   bool
+	// SCRAM exchange state.
+	step        int
+	authMessage string
+	salted      []byte
+}
+
+func (sc *mockSASLConn) authenticated() bool {
+	return sc.config == nil || sc.done
+}
+
+func (sc *mockSASLConn) hash() func() hash.Hash {
+	if sc.config.mechanism == SASLSCRAMSHA512 {
+		return sha512.New
+	}
+	return sha256.New
+}
+
+func (sc *mockSASLConn) handshake(d *decoder, e *encoder) {
+	var enabled []string
+	if sc.config != nil {
+		enabled = append(enabled, sc.config.mechanism)
+	}
+
+	var code int16
+	if len(enabled) == 0 || d.string() != enabled[0] {
+		code = 33
+	}
+
+	e.int16(code)
+	e.arrayLen(len(enabled))
+	for _, m := range enabled {
+		e.string(m)
+	}
+}
+
+func (sc *mockSASLConn) authenticate(d *decoder, e *encoder) {
+	auth := string(d.bytes())
+
+	resp, ok := sc.respond(auth)
+	if !ok {
+		e.int16(58)
+		msg := "Authentication failed"
+		e.nullableString(&msg)
+		e.bytes(nil)
+		return
+	}
+
+	e.int16(0)
+	e.nullableString(nil)
+	e.bytes([]byte(resp))
+}
+
+// respond returns the server response to
+// the client auth bytes and whether the
+// exchange is valid.
+func (sc *mockSASLConn) respond(auth string) (string, bool) {
+	c := sc.config
+
+	if c.mechanism == SASLPlain {
+		sc.done = auth == "\x00"+c.username+"\x00"+c.password
+		return "", sc.done
+	}
+
+	h := sc.hash()
+	mac := func(key []byte, msg string) []byte {
+		m := hmac.New(h, key)
+		m.Write([]byte(msg))
+		return m.Sum(nil)
+	}
+
+	sc.step++
+	switch sc.step {
+	case 1:
+		bare := strings.TrimPrefix(auth, "n,,")
+		attrs := scramAttrs(bare)
+		if attrs["n"] != c.username {
+			return "", false
+		}
+
+		salt := []byte("mock salt")
+		serverFirst := "r=" + attrs["r"] + "server-nonce,s=" +
+			base64.StdEncoding.EncodeToString(salt) + ",i=4096"
+		sc.authMessage = bare + "," + serverFirst
+		sc.salted = pbkdf2(h, []byte(c.password), salt, 4096)
+
+		return serverFirst, true
+	case 2:
+		i := strings.Index(auth, ",p=")
+		if i < 0 {
+			return "", false
+		}
+
+		proof, err := base64.StdEncoding.DecodeString(auth[i+3:])
+		if err != nil {
+			return "", false
+		}
+
+		sc.authMessage += "," + auth[:i]
+
+		clientKey := mac(sc.salted, "Client Key")
+		storedKey := h()
+		storedKey.Write(clientKey)
+		sig := mac(storedKey.Sum(nil), sc.authMessage)
+
+		if len(proof) != len(sig) {
+			return "", false
+		}
+
+		for i := range proof {
+			if proof[i]^sig[i] != clientKey[i] {
+				return "", false
+			}
+		}
+
+		sc.done = true
+		serverSig := mac(mac(sc.salted, "Server Key"), sc.authMessage)
+
+		return "v=" + base64.StdEncoding.EncodeToString(serverSig), true
+	}
+
+	return "", false
+}
+
+func TestPBKDF2(t *testing.T) {
+	// RFC 6070 test vectors.
+	tests := []struct {
+		iterations int
+		expected   string
+	}{
+		{1, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+		{2, "ea6c014dc72d6f8ccd1ed92ace1d41f0d8de8957"},
+		{4096, "4b007901b765489abead49d926f721d065a429c1"},
+	}
+
+	for _, test := range tests {
+		k := pbkdf2(sha1.New, []byte("password"), []byte("salt"), test.iterations)
+		if hex.EncodeToString(k) != test.expected {
+			t.Errorf("Expected key %s for %d iterations, got %x", test.expected, test.iterations, k)
+		}
+	}
+}
+
+func TestSCRAMClient(t *testing.T) {
+	// RFC 7677 SCRAM-SHA-256 example.
+	s, err := newSCRAMClient(SASLSCRAMSHA256, "user", "pencil")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.nonce = "rOprNGfwEbeRWgbNEkqO"
+
+	if first := string(s.first()); first != "n,,n=user,r=rOprNGfwEbeRWgbNEkqO" {
+		t.Errorf("Unexpected client-first-message %s", first)
+	}
+
+	serverFirst := "r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096"
+	final, err := s.final([]byte(serverFirst))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ="
+	if string(final) != expected {
+		t.Errorf("Expected client-final-message %s, got %s", expected, final)
+	}
+
+	if err := s.verify([]byte("v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=")); err != nil {
+		t.Error(err)
+	}
+
+	if err := s.verify([]byte("v=AAAA")); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	// The server nonce must extend the client nonce.
+	if _, err := s.final([]byte("r=other,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096")); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	if _, err := s.final([]byte("e=unknown-user")); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
+
+func TestSASLAuthentication(t *testing.T) {
+	for _, mechanism := range []string{SASLPlain, SASLSCRAMSHA256, SASLSCRAMSHA512} {
+		b := newMockBroker(t, 1001)
+		b.Lock()
+		b.sasl = &mockSASL{mechanism: mechanism, username: "admin", password: "secret"}
+		b.Unlock()
+
+		config := Config{
+			BootstrapServers: b.addr(),
+			SASL:             &SASLConfig{Mechanism: mechanism, Username: "admin", Password: "secret"},
+		}
+
+		c, err := NewClient(config)
+		if err != nil {
+			t.Fatalf("[%s] %s", mechanism, err)
+		}
+
+		if _, err := c.DescribeConfigs(ResourceBroker, "1001", nil); err != nil {
+			t.Errorf("[%s] %s", mechanism, err)
+		}
+
+		// Incorrect password.
+		config.SASL.Password = "wrong"
+		if _, err := NewClient(config); err == nil {
+			t.Errorf("[%s] Expected non-nil error", mechanism)
+		}
+
+		// Mechanism not enabled.
+		config.SASL = &SASLConfig{Mechanism: SASLSCRAMSHA512, Username: "admin", Password: "secret"}
+		if mechanism == SASLSCRAMSHA512 {
+			config.SASL.Mechanism = SASLPlain
+		}
+
+		if _, err := NewClient(config); err == nil {
+			t.Errorf("[%s] Expected non-nil error", mechanism)
+		}
+
+		// No authentication.
+		if _, err := NewClient(Config{BootstrapServers: b.addr()}); err == nil {
+			t.Errorf("[%s] Expected non-nil error", mechanism)
+		}
+
+		b.close()
+	}
+}
+
+func TestSASLConfigValidate(t *testing.T) {
+	tests := []struct {
+		config *SASLConfig
+		valid  bool
+	}{
+		{&SASLConfig{Mechanism: SASLSCRAMSHA256, Username: "admin"}, true},
+		{&SASLConfig{Mechanism: "GSSAPI", Username: "admin"}, false},
+		{&SASLConfig{Mechanism: SASLPlain}, false},
+	}
+
+	for i, test := range tests {
+		if err := test.config.validate(); (err == nil) != test.valid {
+			t.Errorf("[test %d] Expected valid %v, got error %v", i, test.valid, err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package kafkaadmin
+
+import (
+	"sort"
+	"time"
+)
+
+// TopicSpec describes a topic to create.
+type TopicSpec struct {
+	Name              string
+	Partitions        int
+	ReplicationFactor int
+	// Topic config overrides.
+	Configs map[string]string
+}
+
+// CreateTopics creates the topics, with replicas assigned by the controller.
+// With validateOnly, the controller validates the request (including topic
+// existence, configs and the replication factor against live brokers)
+// without creating any topic. A map of topic name to error is returned for
+// each topic that failed; the remaining topics are created. Requires Kafka
+// 0.10.2+.
+func (c *Client) CreateTopics(topics []TopicSpec, validateOnly bool) (map[string]error, error) {
+	return c.createTopics(topics, validateOnly, true)
+}
+
+func (c *Client) createTopics(topics []TopicSpec, validateOnly, retry bool) (map[string]error, error) {
+	addr, err := c.addrFor(ResourceTopic, "")
+	if err != nil {
+		return nil, err
+	}
+
+	body := encodeCreateTopicsRequest(topics, int32(c.timeout/time.Millisecond), validateOnly)
+	d, err := c.request(addr, apiCreateTopics, createTopicsVersion, body)
+	if err != nil {
+		return nil, err
+	}
+
+	errs, err := decodeCreateTopicsResponse(d)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retry once against the current controller if it has
+	// moved; topics are only created by the controller.
+	for _, e := range errs {
+		if ke, ok := e.(*Error); ok && ke.Code == errNotController && retry {
+			if err := c.refreshMetadata(); err != nil {
+				return nil, err
+			}
+
+			return c.createTopics(topics, validateOnly, false)
+		}
+	}
+
+	return errs, nil
+}
+
+func encodeCreateTopicsRequest(topics []TopicSpec, timeout int32, validateOnly bool) []byte {
+	e := &encoder{}
+
+	e.arrayLen(len(topics))
+	for _, t := range topics {
+		e.string(t.Name)
+		e.int32(int32(t.Partitions))
+		e.int16(int16(t.ReplicationFactor))
+		e.arrayLen(0) // Replica assignments.
+
+		var keys []string
+		for k := range t.Configs {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		e.arrayLen(len(keys))
+		for _, k := range keys {
+			v := t.Configs[k]
+			e.string(k)
+			e.nullableString(&v)
+		}
+	}
+
+	e.int32(timeout)
+	e.bool(validateOnly)
+
+	return e.b
+}
+
+func decodeCreateTopicsResponse(d *decoder) (map[string]error, error) {
+	d.int32() // Throttle time.
+
+	errs := map[string]error{}
+	n := d.arrayLen()
+	for i := 0; i < n; i++ {
+		name := d.string()
+		code := d.int16()
+		msg, _ := d.nullableString()
+
+		if code != 0 {
+			errs[name] = &Error{Code: code, Message: msg}
+		}
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return errs, nil
+}
+
+// DeleteTopics deletes the topics. A map of topic name to error is
+// returned for each topic that failed; the remaining topics are deleted.
+// Topics can only be deleted if delete.topic.enable is set on the
+// controller. Requires Kafka 0.10.1+.
+func (c *Client) DeleteTopics(topics []string) (map[string]error, error) {
+	return c.deleteTopics(topics, true)
+}
+
+func (c *Client) deleteTopics(topics []string, retry bool) (map[string]error, error) {
+	addr, err := c.addrFor(ResourceTopic, "")
+	if err != nil {
+		return nil, err
+	}
+
+	body := encodeDeleteTopicsRequest(topics, int32(c.timeout/time.Millisecond))
+	d, err := c.request(addr, apiDeleteTopics, deleteTopicsVersion, body)
+	if err != nil {
+		return nil, err
+	}
+
+	errs, err := decodeDeleteTopicsResponse(d)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retry once against the current controller if it has
+	// moved; topics are only deleted by the controller.
+	for _, e := range errs {
+		if ke, ok := e.(*Error); ok && ke.Code == errNotController && retry {
+			if err := c.refreshMetadata(); err != nil {
+				return nil, err
+			}
+
+			return c.deleteTopics(topics, false)
+		}
+	}
+
+	return errs, nil
+}
+
+func encodeDeleteTopicsRequest(topics []string, timeout int32) []byte {
+	e := &encoder{}
+
+	e.arrayLen(len(topics))
+	for _, t := range topics {
+		e.string(t)
+	}
+
+	e.int32(timeout)
+
+	return e.b
+}
+
+func decodeDeleteTopicsResponse(d *decoder) (map[string]error, error) {
+	d.int32() // Throttle time.
+
+	errs := map[string]error{}
+	n := d.arrayLen()
+	for i := 0; i < n; i++ {
+		name := d.string()
+		code := d.int16()
+
+		if code != 0 {
+			errs[name] = &Error{Code: code}
+		}
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return errs, nil
+}
@@ -0,0 +1,191 @@
+package kafkaadmin
+
+import (
+	"testing"
+)
+
+// createTopics creates topics with the log end offsets
+// of each partition at 0 and the topic configs. Topics
+// may have a replication factor of at most 3.
+func (b *mockBroker) createTopics(d *decoder, e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	var topics []TopicSpec
+
+	n := d.arrayLen()
+	for i := 0; i < n; i++ {
+		t := TopicSpec{
+			Name:              d.string(),
+			Partitions:        int(d.int32()),
+			ReplicationFactor: int(d.int16()),
+			Configs:           map[string]string{},
+		}
+
+		d.arrayLen() // Replica assignments.
+
+		nc := d.arrayLen()
+		for j := 0; j < nc; j++ {
+			k := d.string()
+			t.Configs[k], _ = d.nullableString()
+		}
+
+		topics = append(topics, t)
+	}
+
+	d.int32() // Timeout.
+	validateOnly := d.bool()
+
+	e.int32(0) // Throttle time.
+	e.arrayLen(len(topics))
+
+	for _, t := range topics {
+		var code int16
+		switch _, exists := b.logEnd[t.Name]; {
+		case exists:
+			code = 36
+		case t.Partitions < 1:
+			code = 37
+		case t.ReplicationFactor < 1 || t.ReplicationFactor > 3:
+			code = 38
+		}
+
+		e.string(t.Name)
+		e.int16(code)
+		e.nullableString(nil)
+
+		if code != 0 || validateOnly {
+			continue
+		}
+
+		if b.logEnd == nil {
+			b.logEnd = Offsets{}
+		}
+
+		b.logEnd[t.Name] = map[int]int64{}
+		for p := 0; p < t.Partitions; p++ {
+			b.logEnd[t.Name][p] = 0
+		}
+
+		b.configs[t.Name] = t.Configs
+	}
+}
+
+func TestCreateTopics(t *testing.T) {
+	b := newMockBroker(t, 1001)
+	defer b.close()
+
+	b.logEnd = Offsets{"existing": {0: 10}}
+
+	c, err := NewClient(Config{BootstrapServers: b.addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topics := []TopicSpec{
+		{Name: "orders", Partitions: 3, ReplicationFactor: 3, Configs: map[string]string{"retention.ms": "3600000"}},
+		{Name: "payments", Partitions: 1, ReplicationFactor: 2},
+	}
+
+	// Validation only.
+	errs, err := c.CreateTopics(topics, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(errs) != 0 || len(b.logEnd) != 1 {
+		t.Errorf("Expected no errors or topics created, got %v, %v", errs, b.logEnd)
+	}
+
+	errs, err = c.CreateTopics(topics, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(errs) != 0 {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	if len(b.logEnd["orders"]) != 3 || b.configs["orders"]["retention.ms"] != "3600000" {
+		t.Errorf("Unexpected topic orders: %v, configs %v", b.logEnd["orders"], b.configs["orders"])
+	}
+
+	// Per-topic errors.
+	errs, err = c.CreateTopics([]TopicSpec{
+		{Name: "existing", Partitions: 1, ReplicationFactor: 1},
+		{Name: "invalid_rf", Partitions: 1, ReplicationFactor: 4},
+		{Name: "ok", Partitions: 1, ReplicationFactor: 1},
+	}, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"existing":   "TOPIC_ALREADY_EXISTS",
+		"invalid_rf": "INVALID_REPLICATION_FACTOR",
+	}
+
+	if len(errs) != len(expected) {
+		t.Errorf("Expected errors for %v, got %v", expected, errs)
+	}
+
+	for topic, msg := range expected {
+		if err := errs[topic]; err == nil || err.Error() != msg {
+			t.Errorf("Expected %s error for %s, got %v", msg, topic, err)
+		}
+	}
+
+	if _, exists := b.logEnd["ok"]; !exists {
+		t.Error("Expected topic ok to be created")
+	}
+}
+
+// deleteTopics deletes topics, removing their log end offsets.
+func (b *mockBroker) deleteTopics(d *decoder, e *encoder) {
+	b.Lock()
+	defer b.Unlock()
+
+	e.int32(0) // Throttle time.
+
+	n := d.arrayLen()
+	e.arrayLen(n)
+	for i := 0; i < n; i++ {
+		t := d.string()
+
+		var code int16
+		if _, exists := b.logEnd[t]; !exists {
+			code = 3
+		}
+
+		delete(b.logEnd, t)
+
+		e.string(t)
+		e.int16(code)
+	}
+}
+
+func TestDeleteTopics(t *testing.T) {
+	b := newMockBroker(t, 1001)
+	defer b.close()
+
+	b.logEnd = Offsets{"orders": {0: 10}, "payments": {0: 10}}
+
+	c, err := NewClient(Config{BootstrapServers: b.addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs, err := c.DeleteTopics([]string{"orders", "unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(errs) != 1 || errs["unknown"] == nil || errs["unknown"].Error() != "UNKNOWN_TOPIC_OR_PARTITION" {
+		t.Errorf("Expected an UNKNOWN_TOPIC_OR_PARTITION error for unknown, got %v", errs)
+	}
+
+	if _, exists := b.logEnd["orders"]; exists || len(b.logEnd) != 1 {
+		t.Errorf("Expected topic orders to be deleted, got %v", b.logEnd)
+	}
+}
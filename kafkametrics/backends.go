@@ -0,0 +1,98 @@
+package kafkametrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Config holds backend agnostic Handler
+// configuration parameters.
+type Config struct {
+	// NetworkTXQuery is a query string that
+	// should return the outbound network metrics
+	// by host for the reference Kafka brokers.
+	NetworkTXQuery string
+	// NetworkRXQuery is an optional query string
+	// that should return the inbound network metrics
+	// by host for the reference Kafka brokers.
+	NetworkRXQuery string
+	// DiskUtilQuery is an optional query string
+	// that should return the disk utilization (percent)
+	// by host for the reference Kafka brokers.
+	DiskUtilQuery string
+	// CompressionRatioQuery is an optional query
+	// string that should return the ratio of on-disk
+	// to network bytes by host for the reference
+	// Kafka brokers.
+	CompressionRatioQuery string
+	// BrokerIDTag is the host tag name
+	// for Kafka broker IDs.
+	BrokerIDTag string
+	// MetricsWindow specifies the window size of
+	// timeseries data to evaluate in seconds.
+	MetricsWindow int
+	// Params holds backend specific parameters,
+	// such as credentials or endpoint addresses.
+	Params map[string]string
+}
+
+// Factory takes a *Config and returns
+// a Handler for a metrics backend.
+type Factory func(*Config) (Handler, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{}
+)
+
+// Register makes a metrics backend Factory available
+// by name. Backends typically call Register in an
+// init function. Register panics if the name is
+// registered twice or the Factory is nil.
+func Register(name string, f Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if f == nil {
+		panic("kafkametrics: Register factory is nil")
+	}
+
+	if _, dup := factories[name]; dup {
+		panic("kafkametrics: Register called twice for backend " + name)
+	}
+
+	factories[name] = f
+}
+
+// Backends returns the sorted names
+// of all registered backends.
+func Backends() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	var names []string
+	for n := range factories {
+		names = append(names, n)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// NewHandler takes a backend name and *Config and
+// returns a Handler from the registered Factory.
+func NewHandler(backend string, c *Config) (Handler, error) {
+	factoriesMu.RLock()
+	f, exists := factories[backend]
+	factoriesMu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("Unknown metrics backend %s (registered: %s)",
+			backend, strings.Join(Backends(), ", "))
+	}
+
+	return f(c)
+}
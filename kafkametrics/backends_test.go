@@ -0,0 +1,57 @@
+package kafkametrics
+
+import (
+	"testing"
+)
+
+func TestNewHandler(t *testing.T) {
+	var got *Config
+	Register("test", func(c *Config) (Handler, error) {
+		got = c
+		return &Mock{}, nil
+	})
+
+	c := &Config{Params: map[string]string{"key": "value"}}
+
+	h, err := NewHandler("test", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := h.(*Mock); !ok {
+		t.Errorf("Unexpected Handler type %T", h)
+	}
+
+	if got != c {
+		t.Error("Expected the Config to be passed to the Factory")
+	}
+
+	_, err = NewHandler("none", c)
+	if err == nil {
+		t.Error("Expected error for unregistered backend")
+	}
+
+	found := false
+	for _, n := range Backends() {
+		if n == "test" {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("Expected backend test in %v", Backends())
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	f := func(c *Config) (Handler, error) { return &Mock{}, nil }
+	Register("dup", f)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic on duplicate Register")
+		}
+	}()
+
+	Register("dup", f)
+}
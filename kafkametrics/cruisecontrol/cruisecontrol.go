@@ -0,0 +1,178 @@
+// Package cruisecontrol implements a kafkametrics Handler that reads
+// broker utilization and capacity from the Cruise Control broker load
+// JSON, fetched from the Cruise Control REST API or read from a file.
+package cruisecontrol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+)
+
+// Backend is the name that the Cruise Control
+// kafkametrics backend is registered under.
+const Backend = "cruisecontrol"
+
+// Config holds Handler
+// configuration parameters.
+type Config struct {
+	// Cruise Control REST API base URL,
+	// e.g. http://cruise-control:9090/kafkacruisecontrol.
+	URL string
+	// Path to a broker load JSON file, as returned
+	// by the load endpoint. Used if URL is empty.
+	File string
+	// Request timeout; defaults to 10s.
+	Timeout time.Duration
+}
+
+// load is the Cruise Control
+// broker load JSON.
+type load struct {
+	Brokers []brokerStats `json:"brokers"`
+}
+
+// brokerStats is the load of a broker. Network
+// rates and capacities are in KB/s.
+type brokerStats struct {
+	Broker             int     `json:"Broker"`
+	Host               string  `json:"Host"`
+	BrokerState        string  `json:"BrokerState"`
+	NwInRate           float64 `json:"NwInRate"`
+	NwOutRate          float64 `json:"NwOutRate"`
+	NetworkInCapacity  float64 `json:"NetworkInCapacity"`
+	NetworkOutCapacity float64 `json:"NetworkOutCapacity"`
+}
+
+type ccHandler struct {
+	c    *http.Client
+	url  string
+	file string
+}
+
+func init() {
+	kafkametrics.Register(Backend, newFromConfig)
+}
+
+// newFromConfig is a kafkametrics.Factory. The url or
+// file Params are used as the broker load source.
+func newFromConfig(c *kafkametrics.Config) (kafkametrics.Handler, error) {
+	return NewHandler(&Config{
+		URL:  c.Params["url"],
+		File: c.Params["file"],
+	})
+}
+
+// NewHandler takes a *Config and returns a Handler.
+func NewHandler(c *Config) (kafkametrics.Handler, error) {
+	if c.URL == "" && c.File == "" {
+		return nil, errors.New("A Cruise Control URL or load file must be specified")
+	}
+
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &ccHandler{
+		c:    &http.Client{Timeout: timeout},
+		url:  strings.TrimRight(c.URL, "/"),
+		file: c.File,
+	}, nil
+}
+
+// PostEvent is a no-op; Cruise Control doesn't store events.
+// Events can be written with another kafkametrics.EventPoster.
+func (h *ccHandler) PostEvent(e *kafkametrics.Event) error {
+	return nil
+}
+
+// GetMetrics returns a BrokerMetrics from the Cruise Control broker load.
+// Network rates are converted to MB/s. The NetCapacity of each broker is
+// the lesser of the inbound and outbound network capacities, if reported.
+// Brokers that Cruise Control reports as dead are excluded, as their
+// load isn't current.
+func (h *ccHandler) GetMetrics() (kafkametrics.BrokerMetrics, []error) {
+	l, err := h.load()
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	bm := kafkametrics.BrokerMetrics{}
+
+	for _, b := range l.Brokers {
+		if b.BrokerState == "DEAD" {
+			continue
+		}
+
+		bm[b.Broker] = &kafkametrics.Broker{
+			ID:          b.Broker,
+			Host:        b.Host,
+			NetTX:       b.NwOutRate / 1024,
+			NetRX:       b.NwInRate / 1024,
+			NetCapacity: math.Min(b.NetworkInCapacity, b.NetworkOutCapacity) / 1024,
+		}
+	}
+
+	if len(bm) == 0 {
+		return nil, []error{&kafkametrics.NoResults{
+			Message: "No brokers returned in Cruise Control load",
+		}}
+	}
+
+	return bm, nil
+}
+
+// load fetches the broker load from
+// the REST API or load file.
+func (h *ccHandler) load() (*load, error) {
+	var data []byte
+	var err error
+
+	if h.url != "" {
+		data, err = h.get(h.url + "/load?json=true")
+	} else {
+		data, err = ioutil.ReadFile(h.file)
+	}
+
+	if err != nil {
+		return nil, &kafkametrics.APIError{Request: "broker load", Message: err.Error()}
+	}
+
+	l := &load{}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, &kafkametrics.APIError{
+			Request: "broker load",
+			Message: fmt.Sprintf("Error parsing broker load: %s", err),
+		}
+	}
+
+	return l, nil
+}
+
+func (h *ccHandler) get(url string) ([]byte, error) {
+	resp, err := h.c.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
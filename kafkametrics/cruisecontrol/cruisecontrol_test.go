@@ -0,0 +1,142 @@
+package cruisecontrol
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+)
+
+const testLoad = `{
+  "version": 1,
+  "hosts": [],
+  "brokers": [
+    {"Broker": 1001, "Host": "kafka-1", "BrokerState": "ALIVE", "NwInRate": 10240, "NwOutRate": 20480, "NetworkInCapacity": 204800, "NetworkOutCapacity": 153600, "DiskPct": 40},
+    {"Broker": 1002, "Host": "kafka-2", "BrokerState": "ALIVE", "NwInRate": 5120, "NwOutRate": 1024, "DiskPct": 35},
+    {"Broker": 1003, "Host": "kafka-3", "BrokerState": "DEAD"}
+  ]
+}`
+
+func TestNewHandler(t *testing.T) {
+	if _, err := NewHandler(&Config{}); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	h, err := kafkametrics.NewHandler(Backend, &kafkametrics.Config{
+		Params: map[string]string{"url": "http://cruise-control:9090/kafkacruisecontrol/"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u := h.(*ccHandler).url; u != "http://cruise-control:9090/kafkacruisecontrol" {
+		t.Errorf("Unexpected URL %s", u)
+	}
+}
+
+func TestGetMetrics(t *testing.T) {
+	var path, query string
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, query = r.URL.Path, r.URL.RawQuery
+		w.Write([]byte(testLoad))
+	}))
+	defer s.Close()
+
+	h, _ := NewHandler(&Config{URL: s.URL + "/kafkacruisecontrol"})
+
+	bm, errs := h.GetMetrics()
+	if path != "/kafkacruisecontrol/load" || query != "json=true" {
+		t.Errorf("Unexpected request: path %s, query %s", path, query)
+	}
+
+	if errs != nil {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	// The dead broker is excluded.
+	if len(bm) != 2 {
+		t.Fatalf("Expected 2 brokers, got %d", len(bm))
+	}
+
+	b := bm[1001]
+	if b.Host != "kafka-1" || b.NetTX != 20 || b.NetRX != 10 {
+		t.Errorf("Unexpected broker metrics %+v", b)
+	}
+
+	if b.NetCapacity != 150 {
+		t.Errorf("Expected capacity 150, got %f", b.NetCapacity)
+	}
+
+	if b := bm[1002]; b.NetTX != 1 || b.NetCapacity != 0 {
+		t.Errorf("Unexpected broker metrics %+v", b)
+	}
+}
+
+func TestGetMetricsFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "cruisecontrol-load")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+
+	f.WriteString(testLoad)
+	f.Close()
+
+	h, _ := NewHandler(&Config{File: f.Name()})
+
+	if bm, _ := h.GetMetrics(); len(bm) != 2 {
+		t.Errorf("Expected 2 brokers, got %d", len(bm))
+	}
+
+	// Unparseable load.
+	ioutil.WriteFile(f.Name(), []byte("{"), 0600)
+
+	if _, errs := h.GetMetrics(); len(errs) != 1 {
+		t.Errorf("Expected 1 error, got %v", errs)
+	}
+}
+
+func TestGetMetricsError(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+	}))
+	defer s.Close()
+
+	h, _ := NewHandler(&Config{URL: s.URL})
+
+	_, errs := h.GetMetrics()
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+
+	if _, ok := errs[0].(*kafkametrics.APIError); !ok {
+		t.Errorf("Expected *kafkametrics.APIError, got %T", errs[0])
+	}
+
+	// No brokers.
+	s2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"brokers": []}`))
+	}))
+	defer s2.Close()
+
+	h, _ = NewHandler(&Config{URL: s2.URL})
+
+	if _, errs := h.GetMetrics(); len(errs) != 1 {
+		t.Errorf("Expected 1 error, got %v", errs)
+	}
+}
+
+func TestBackendRegistered(t *testing.T) {
+	for _, n := range kafkametrics.Backends() {
+		if n == Backend {
+			return
+		}
+	}
+
+	t.Errorf("Expected backend %s to be registered", Backend)
+}
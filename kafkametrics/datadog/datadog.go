@@ -24,6 +24,21 @@ type Config struct {
 	// by host for the reference Kafka brokers.
 	// For example (Datadog): "avg:system.net.bytes_sent{service:kafka} by {host}"
 	NetworkTXQuery string
+	// NetworkRXQuery is an optional query string
+	// that should return the inbound network metrics
+	// by host for the reference Kafka brokers.
+	// For example (Datadog): "avg:system.net.bytes_rcvd{service:kafka} by {host}"
+	NetworkRXQuery string
+	// DiskUtilQuery is an optional query string
+	// that should return the disk utilization (percent)
+	// by host for the reference Kafka brokers.
+	// For example (Datadog): "max:system.io.util{service:kafka} by {host}"
+	DiskUtilQuery string
+	// CompressionRatioQuery is an optional query
+	// string that should return the ratio of on-disk
+	// to network bytes by host for the reference
+	// Kafka brokers.
+	CompressionRatioQuery string
 	// BrokerIDTag is the host tag name
 	// for Kafka broker IDs.
 	BrokerIDTag string
@@ -36,18 +51,44 @@ type Config struct {
 type ddHandler struct {
 	c             *dd.Client
 	netTXQuery    string
+	netRXQuery    string
+	diskUtilQuery string
 	brokerIDTag   string
 	metricsWindow int
 	tagCache      map[string][]string
 	keysRegex     *regexp.Regexp
 	redactionSub  []byte
+	// Optional compression ratio query.
+	compressionRatioQuery string
+}
+
+// Backend is the name that the Datadog
+// kafkametrics backend is registered under.
+const Backend = "datadog"
+
+func init() {
+	kafkametrics.Register(Backend, newFromConfig)
+}
+
+// newFromConfig is a kafkametrics.Factory. The api_key and
+// app_key Params are used as the Datadog credentials.
+func newFromConfig(c *kafkametrics.Config) (kafkametrics.Handler, error) {
+	return NewHandler(&Config{
+		APIKey:         c.Params["api_key"],
+		AppKey:         c.Params["app_key"],
+		NetworkTXQuery: c.NetworkTXQuery,
+		NetworkRXQuery: c.NetworkRXQuery,
+		DiskUtilQuery:  c.DiskUtilQuery,
+		BrokerIDTag:    c.BrokerIDTag,
+		MetricsWindow:  c.MetricsWindow,
+
+		CompressionRatioQuery: c.CompressionRatioQuery,
+	})
 }
 
 // NewHandler takes a *Config and
 // returns a Handler, along with
 // any credential validation errors.
-// Further backends can be supported with
-// a type switch and some other changes.
 func NewHandler(c *Config) (kafkametrics.Handler, error) {
 	// The underlying client sometimes returns API errors
 	// with full dd URL, including parameterized app/api keys.
@@ -58,11 +99,15 @@ func NewHandler(c *Config) (kafkametrics.Handler, error) {
 
 	h := &ddHandler{
 		netTXQuery:    createNetTXQuery(c),
+		netRXQuery:    createNetRXQuery(c),
+		diskUtilQuery: createDiskUtilQuery(c),
 		metricsWindow: c.MetricsWindow,
 		brokerIDTag:   c.BrokerIDTag,
 		tagCache:      make(map[string][]string),
 		keysRegex:     keysRegex,
 		redactionSub:  []byte("xxx"),
+
+		compressionRatioQuery: createCompressionRatioQuery(c),
 	}
 
 	client := dd.NewClient(c.APIKey, c.AppKey)
@@ -142,6 +187,66 @@ func (h *ddHandler) GetMetrics() (kafkametrics.BrokerMetrics, []error) {
 		errors = append(errors, errs...)
 	}
 
+	// Populate the inbound network metric
+	// if an inbound query is configured.
+	if h.netRXQuery != "" {
+		o, err := h.c.QueryMetrics(start, time.Now().Unix(), h.netRXQuery)
+		if err != nil {
+			return nil, append(errors, &kafkametrics.APIError{
+				Request: "metrics query",
+				Message: h.scrubbedErrorText(err),
+			})
+		}
+
+		rx, errs := netRXFromSeries(o)
+		if errs != nil {
+			errors = append(errors, errs...)
+		}
+
+		errs = populateNetRX(bm, rx)
+		if errs != nil {
+			errors = append(errors, errs...)
+		}
+	}
+
+	// Populate the disk utilization metric
+	// if a disk utilization query is configured.
+	if h.diskUtilQuery != "" {
+		o, err := h.c.QueryMetrics(start, time.Now().Unix(), h.diskUtilQuery)
+		if err != nil {
+			return nil, append(errors, &kafkametrics.APIError{
+				Request: "metrics query",
+				Message: h.scrubbedErrorText(err),
+			})
+		}
+
+		util, errs := diskUtilFromSeries(o)
+		if errs != nil {
+			errors = append(errors, errs...)
+		}
+
+		errs = populateDiskUtil(bm, util)
+		if errs != nil {
+			errors = append(errors, errs...)
+		}
+	}
+
+	// Populate the compression ratio if a compression
+	// ratio query is configured. The ratio is optional;
+	// brokers without points are left unset.
+	if h.compressionRatioQuery != "" {
+		o, err := h.c.QueryMetrics(start, time.Now().Unix(), h.compressionRatioQuery)
+		if err != nil {
+			return nil, append(errors, &kafkametrics.APIError{
+				Request: "metrics query",
+				Message: h.scrubbedErrorText(err),
+			})
+		}
+
+		ratios, _ := hostValuesFromSeries(o, "compression ratio", 1)
+		populateCompressionRatio(bm, ratios)
+	}
+
 	return bm, errors
 }
 
@@ -28,6 +28,40 @@ func TestCreateNetTXQuery(t *testing.T) {
 	}
 }
 
+func TestCreateNetRXQuery(t *testing.T) {
+	c := &Config{
+		MetricsWindow: 300,
+	}
+
+	if s := createNetRXQuery(c); s != "" {
+		t.Errorf("Expected empty query, got %s\n", s)
+	}
+
+	c.NetworkRXQuery = "avg:system.net.bytes_rcvd{service:kafka} by {host}"
+	s := createNetRXQuery(c)
+
+	if s != "avg:system.net.bytes_rcvd{service:kafka} by {host}.rollup(avg, 300)" {
+		t.Errorf("Expected avg:system.net.bytes_rcvd{service:kafka} by {host}.rollup(avg, 300), got %s\n", s)
+	}
+}
+
+func TestCreateDiskUtilQuery(t *testing.T) {
+	c := &Config{
+		MetricsWindow: 300,
+	}
+
+	if s := createDiskUtilQuery(c); s != "" {
+		t.Errorf("Expected empty query, got %s\n", s)
+	}
+
+	c.DiskUtilQuery = "max:system.io.util{service:kafka} by {host}"
+	s := createDiskUtilQuery(c)
+
+	if s != "max:system.io.util{service:kafka} by {host}.rollup(avg, 300)" {
+		t.Errorf("Expected max:system.io.util{service:kafka} by {host}.rollup(avg, 300), got %s\n", s)
+	}
+}
+
 // func TestGetMetrics(t *testing.T) {}
 
 func TestBrokersFromSeries(t *testing.T) {
@@ -87,6 +121,83 @@ func mockSeriesWithoutPoints() []dd.Series {
 	return ss
 }
 
+func TestNetRXFromSeries(t *testing.T) {
+	var f1 = 0.00
+	var f2 = 104857600.00
+
+	ss := []dd.Series{}
+	for i := 0; i < 2; i++ {
+		scope := fmt.Sprintf("host:host%d", i)
+		s := dd.Series{Scope: &scope}
+		// Only host0 has points.
+		if i == 0 {
+			s.Points = []dd.DataPoint{dd.DataPoint{&f1, &f2}}
+		}
+		ss = append(ss, s)
+	}
+
+	rx, errs := netRXFromSeries(ss)
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error, got %d", len(errs))
+	}
+
+	if len(rx) != 1 || rx["host0"] != 100.00 {
+		t.Errorf("Unexpected inbound metrics: %v", rx)
+	}
+}
+
+func TestPopulateNetRX(t *testing.T) {
+	bm := kafkametrics.BrokerMetrics{
+		1000: &kafkametrics.Broker{ID: 1000, Host: "host0"},
+		1001: &kafkametrics.Broker{ID: 1001, Host: "host1"},
+	}
+
+	errs := populateNetRX(bm, map[string]float64{"host0": 100.00})
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error, got %d", len(errs))
+	}
+
+	if bm[1000].NetRX != 100.00 {
+		t.Errorf("Expected NetRX 100.00, got %.2f", bm[1000].NetRX)
+	}
+
+	// Brokers without inbound metrics are incomplete.
+	if _, exists := bm[1001]; exists {
+		t.Error("Expected broker 1001 to be removed")
+	}
+}
+
+func TestPopulateDiskUtil(t *testing.T) {
+	var f1 = 0.00
+	var f2 = 85.00
+
+	scope := "host:host0"
+	ss := []dd.Series{dd.Series{Scope: &scope, Points: []dd.DataPoint{dd.DataPoint{&f1, &f2}}}}
+
+	util, errs := diskUtilFromSeries(ss)
+	if errs != nil {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+
+	bm := kafkametrics.BrokerMetrics{
+		1000: &kafkametrics.Broker{ID: 1000, Host: "host0"},
+		1001: &kafkametrics.Broker{ID: 1001, Host: "host1"},
+	}
+
+	errs = populateDiskUtil(bm, util)
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error, got %d", len(errs))
+	}
+
+	if bm[1000].DiskUtil != 85.00 {
+		t.Errorf("Expected DiskUtil 85.00, got %.2f", bm[1000].DiskUtil)
+	}
+
+	if _, exists := bm[1001]; exists {
+		t.Error("Expected broker 1001 to be removed")
+	}
+}
+
 // This is essentially tested via TestGetHostTagMap
 // and TestPopulateFromTagMap.
 // func TestBrokerMetricsFromList(t *testing.T) {}
@@ -153,3 +264,44 @@ func TestTagValFromScope(t *testing.T) {
 		t.Errorf("Expected tag val mock, got %s\n", v)
 	}
 }
+
+func TestBackendRegistered(t *testing.T) {
+	for _, n := range kafkametrics.Backends() {
+		if n == Backend {
+			return
+		}
+	}
+
+	t.Errorf("Expected backend %s to be registered", Backend)
+}
+
+func TestPopulateCompressionRatio(t *testing.T) {
+	c := &Config{MetricsWindow: 300}
+	if s := createCompressionRatioQuery(c); s != "" {
+		t.Errorf("Expected empty query, got %s\n", s)
+	}
+
+	var f1 = 0.00
+	var f2 = 0.40
+
+	scope := "host:host0"
+	ss := []dd.Series{dd.Series{Scope: &scope, Points: []dd.DataPoint{dd.DataPoint{&f1, &f2}}}}
+
+	ratios, _ := hostValuesFromSeries(ss, "compression ratio", 1)
+
+	bm := kafkametrics.BrokerMetrics{
+		1000: &kafkametrics.Broker{ID: 1000, Host: "host0"},
+		1001: &kafkametrics.Broker{ID: 1001, Host: "host1"},
+	}
+
+	populateCompressionRatio(bm, ratios)
+
+	if bm[1000].CompressionRatio != 0.40 {
+		t.Errorf("Expected CompressionRatio 0.40, got %.2f", bm[1000].CompressionRatio)
+	}
+
+	// The ratio is optional.
+	if b, exists := bm[1001]; !exists || b.CompressionRatio != 0 {
+		t.Error("Expected broker 1001 without a compression ratio")
+	}
+}
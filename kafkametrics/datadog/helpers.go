@@ -16,9 +16,46 @@ import (
 // metric query is returned with an avg rollup
 // for the provided window.
 func createNetTXQuery(c *Config) string {
+	return rollupQuery(c.NetworkTXQuery, c.MetricsWindow)
+}
+
+// createNetRXQuery is the inbound counterpart to
+// createNetTXQuery. An empty string is returned
+// if no inbound query is configured.
+func createNetRXQuery(c *Config) string {
+	if c.NetworkRXQuery == "" {
+		return ""
+	}
+
+	return rollupQuery(c.NetworkRXQuery, c.MetricsWindow)
+}
+
+// createDiskUtilQuery is the disk utilization
+// counterpart to createNetTXQuery. An empty string
+// is returned if no disk utilization query is configured.
+func createDiskUtilQuery(c *Config) string {
+	if c.DiskUtilQuery == "" {
+		return ""
+	}
+
+	return rollupQuery(c.DiskUtilQuery, c.MetricsWindow)
+}
+
+// createCompressionRatioQuery is the compression ratio
+// counterpart to createNetTXQuery. An empty string is
+// returned if no compression ratio query is configured.
+func createCompressionRatioQuery(c *Config) string {
+	if c.CompressionRatioQuery == "" {
+		return ""
+	}
+
+	return rollupQuery(c.CompressionRatioQuery, c.MetricsWindow)
+}
+
+func rollupQuery(q string, w int) string {
 	var b bytes.Buffer
-	b.WriteString(c.NetworkTXQuery)
-	b.WriteString(fmt.Sprintf(".rollup(avg, %d)", c.MetricsWindow))
+	b.WriteString(q)
+	b.WriteString(fmt.Sprintf(".rollup(avg, %d)", w))
 	return b.String()
 }
 
@@ -52,6 +89,96 @@ func brokersFromSeries(s []dd.Series) ([]*kafkametrics.Broker, []error) {
 	return bs, errors
 }
 
+// netRXFromSeries takes inbound network metrics series as a
+// []dd.Series and returns a map of hostname to inbound network
+// throughput in MB/s. Hosts without points are excluded from the
+// map and an error is populated in the return []error.
+func netRXFromSeries(s []dd.Series) (map[string]float64, []error) {
+	return hostValuesFromSeries(s, "inbound", 1024*1024)
+}
+
+// diskUtilFromSeries takes disk utilization metrics series as a
+// []dd.Series and returns a map of hostname to disk utilization.
+// Hosts without points are excluded from the map and an error is
+// populated in the return []error.
+func diskUtilFromSeries(s []dd.Series) (map[string]float64, []error) {
+	return hostValuesFromSeries(s, "disk utilization", 1)
+}
+
+// hostValuesFromSeries takes metrics series as a []dd.Series, a
+// description of the metric for errors, and a divisor applied to
+// all values, and returns a map of hostname to value.
+func hostValuesFromSeries(s []dd.Series, desc string, div float64) (map[string]float64, []error) {
+	vals := map[string]float64{}
+	var errors []error
+
+	for _, ts := range s {
+		host := tagValFromScope(ts.GetScope(), "host")
+
+		if len(ts.Points) == 0 {
+			errors = append(errors, &kafkametrics.PartialResults{
+				Message: fmt.Sprintf("No %s points for host %s", desc, host),
+			})
+			continue
+		}
+
+		vals[host] = *ts.Points[0][1] / div
+	}
+
+	return vals, errors
+}
+
+// populateNetRX takes a kafkametrics.BrokerMetrics and a map of
+// hostname to inbound network throughput and populates the NetRX
+// value of each broker. Brokers missing from the map are removed
+// from the BrokerMetrics since their metrics are incomplete.
+func populateNetRX(bm kafkametrics.BrokerMetrics, rx map[string]float64) []error {
+	return populateHostValues(bm, rx, "inbound", func(b *kafkametrics.Broker, v float64) { b.NetRX = v })
+}
+
+// populateDiskUtil is the disk utilization counterpart to populateNetRX.
+func populateDiskUtil(bm kafkametrics.BrokerMetrics, util map[string]float64) []error {
+	return populateHostValues(bm, util, "disk utilization", func(b *kafkametrics.Broker, v float64) { b.DiskUtil = v })
+}
+
+// populateCompressionRatio takes a kafkametrics.BrokerMetrics and a map
+// of hostname to compression ratio and populates the CompressionRatio
+// value of each broker. Unlike other metrics, brokers missing from the
+// map are retained since the ratio is optional.
+func populateCompressionRatio(bm kafkametrics.BrokerMetrics, ratios map[string]float64) {
+	for _, b := range bm {
+		if r, exists := ratios[b.Host]; exists && r > 0 {
+			b.CompressionRatio = r
+		}
+	}
+}
+
+// populateHostValues takes a kafkametrics.BrokerMetrics, a map of hostname
+// to value, a description of the metric for errors, and a func that sets
+// the value on a broker. Brokers missing from the map are removed.
+func populateHostValues(bm kafkametrics.BrokerMetrics, vals map[string]float64, desc string, set func(*kafkametrics.Broker, float64)) []error {
+	var missing bytes.Buffer
+
+	for id, b := range bm {
+		v, exists := vals[b.Host]
+		if !exists {
+			missing.WriteString(fmt.Sprintf(" %s", b.Host))
+			delete(bm, id)
+			continue
+		}
+
+		set(b, v)
+	}
+
+	if missing.String() != "" {
+		return []error{&kafkametrics.PartialResults{
+			Message: fmt.Sprintf("Missing %s metrics for hosts:%s", desc, missing.String()),
+		}}
+	}
+
+	return nil
+}
+
 // brokerMetricsFromList takes a *[]kafkametrics.Broker and fetches
 // relevant host tags for all brokers in the list, returning
 // a BrokerMetrics.
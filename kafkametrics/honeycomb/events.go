@@ -0,0 +1,100 @@
+package honeycomb
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+)
+
+// Event is a structured event.
+type Event struct {
+	Time time.Time
+	Data map[string]interface{}
+}
+
+// batchEvent is a Batch API request event.
+type batchEvent struct {
+	Time string                 `json:"time"`
+	Data map[string]interface{} `json:"data"`
+}
+
+// batchResponse is the Batch API
+// response status of an event.
+type batchResponse struct {
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// EventSender sends structured events
+// via the Honeycomb Batch API.
+type EventSender struct {
+	c      *http.Client
+	apiKey string
+	url    string
+}
+
+// NewEventSender takes a *Config and returns an *EventSender.
+func NewEventSender(c *Config) (*EventSender, error) {
+	client, u, err := newClient(c, "batch")
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventSender{
+		c:      client,
+		apiKey: c.APIKey,
+		url:    u,
+	}, nil
+}
+
+// Send sends the events in a single batch. An error is returned
+// if the request fails or any event isn't accepted.
+func (s *EventSender) Send(events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	batch := make([]batchEvent, len(events))
+	for i, e := range events {
+		batch[i] = batchEvent{Time: e.Time.UTC().Format(time.RFC3339Nano), Data: e.Data}
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	resp, err := post(s.c, s.url, s.apiKey, "send events", body)
+	if err != nil {
+		return err
+	}
+
+	var statuses []batchResponse
+	if err := json.Unmarshal(resp, &statuses); err != nil {
+		return &kafkametrics.APIError{
+			Request: "send events",
+			Message: fmt.Sprintf("Error parsing response: %s", err),
+		}
+	}
+
+	var failed int
+	var msg string
+	for _, r := range statuses {
+		if r.Status/100 != 2 {
+			failed++
+			msg = r.Error
+		}
+	}
+
+	if failed > 0 {
+		return &kafkametrics.APIError{
+			Request: "send events",
+			Message: fmt.Sprintf("%d of %d events not accepted: %s", failed, len(events), msg),
+		}
+	}
+
+	return nil
+}
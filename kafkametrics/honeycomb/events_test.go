@@ -0,0 +1,71 @@
+package honeycomb
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSend(t *testing.T) {
+	var got []batchEvent
+	var key, path string
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get("X-Honeycomb-Team")
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&got)
+
+		w.Write([]byte(`[{"status":202},{"status":202}]`))
+	}))
+	defer s.Close()
+
+	es, err := NewEventSender(&Config{APIKey: "key", Dataset: "autothrottle", APIHost: s.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := time.Date(2018, 3, 16, 18, 31, 21, 0, time.UTC)
+	err = es.Send([]Event{
+		{Time: ts, Data: map[string]interface{}{"broker": 1001, "state": "reassigning"}},
+		{Time: ts, Data: map[string]interface{}{"broker": 1002, "state": "reassigning"}},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key != "key" || path != "/1/batch/autothrottle" {
+		t.Errorf("Unexpected request: key %s, path %s", key, path)
+	}
+
+	if len(got) != 2 || got[0].Time != "2018-03-16T18:31:21Z" || got[1].Data["broker"] != 1002.00 {
+		t.Errorf("Unexpected events: %+v", got)
+	}
+
+	// Empty batches aren't sent.
+	path = ""
+	if err := es.Send(nil); err != nil || path != "" {
+		t.Errorf("Unexpected request for empty batch: %v", err)
+	}
+}
+
+func TestSendError(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"status":202},{"status":400,"error":"event too large"}]`))
+	}))
+	defer s.Close()
+
+	es, _ := NewEventSender(&Config{APIKey: "key", Dataset: "autothrottle", APIHost: s.URL})
+
+	err := es.Send([]Event{{Time: time.Now()}, {Time: time.Now()}})
+	expected := "API error [send events]: 1 of 2 events not accepted: event too large"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error '%s', got '%v'", expected, err)
+	}
+
+	if _, err := NewEventSender(&Config{APIKey: "key"}); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
@@ -0,0 +1,138 @@
+// Package honeycomb writes kafkametrics Events as
+// Honeycomb markers, and sends structured events.
+package honeycomb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+)
+
+// DefaultAPIHost is the Honeycomb API host
+// used if none is configured.
+const DefaultAPIHost = "https://api.honeycomb.io"
+
+// Config holds MarkerWriter
+// configuration parameters.
+type Config struct {
+	// Honeycomb API key.
+	APIKey string
+	// Dataset that markers are written to.
+	Dataset string
+	// API host; defaults to DefaultAPIHost.
+	APIHost string
+	// Request timeout; defaults to 10s.
+	Timeout time.Duration
+}
+
+// MarkerWriter posts events as markers
+// via the Honeycomb Markers API.
+type MarkerWriter struct {
+	c      *http.Client
+	apiKey string
+	url    string
+}
+
+// marker is a Markers API request body.
+type marker struct {
+	Message string `json:"message"`
+	Type    string `json:"type,omitempty"`
+}
+
+// NewMarkerWriter takes a *Config and returns a *MarkerWriter.
+func NewMarkerWriter(c *Config) (*MarkerWriter, error) {
+	client, u, err := newClient(c, "markers")
+	if err != nil {
+		return nil, err
+	}
+
+	return &MarkerWriter{
+		c:      client,
+		apiKey: c.APIKey,
+		url:    u,
+	}, nil
+}
+
+// newClient validates the *Config and returns an *http.Client and
+// the URL of the dataset for the API endpoint.
+func newClient(c *Config, endpoint string) (*http.Client, string, error) {
+	switch {
+	case c.APIKey == "":
+		return nil, "", errors.New("Honeycomb API key must be specified")
+	case c.Dataset == "":
+		return nil, "", errors.New("Honeycomb dataset must be specified")
+	}
+
+	host := c.APIHost
+	if host == "" {
+		host = DefaultAPIHost
+	}
+
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	u := fmt.Sprintf("%s/1/%s/%s", strings.TrimRight(host, "/"), endpoint, url.PathEscape(c.Dataset))
+
+	return &http.Client{Timeout: timeout}, u, nil
+}
+
+// PostEvent writes the *kafkametrics.Event as a marker.
+// The marker type is the event Type, if set.
+func (m *MarkerWriter) PostEvent(e *kafkametrics.Event) error {
+	msg := e.Title
+	if t := strings.TrimSpace(e.Text); t != "" {
+		msg = fmt.Sprintf("%s: %s", msg, t)
+	}
+
+	body, err := json.Marshal(marker{Message: msg, Type: e.Type})
+	if err != nil {
+		return err
+	}
+
+	_, err = post(m.c, m.url, m.apiKey, "create marker", body)
+
+	return err
+}
+
+// post sends an API request, returning the response body. Errors
+// are returned as a *kafkametrics.APIError for the request name.
+func post(c *http.Client, url, apiKey, request string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Honeycomb-Team", apiKey)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, &kafkametrics.APIError{
+			Request: request,
+			Message: err.Error(),
+		}
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, &kafkametrics.APIError{
+			Request: request,
+			Message: fmt.Sprintf("%s: %s", resp.Status, strings.TrimSpace(string(b))),
+		}
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,82 @@
+package honeycomb
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+)
+
+func TestNewMarkerWriter(t *testing.T) {
+	if _, err := NewMarkerWriter(&Config{Dataset: "kafka"}); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	if _, err := NewMarkerWriter(&Config{APIKey: "key"}); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	m, err := NewMarkerWriter(&Config{APIKey: "key", Dataset: "kafka prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.url != "https://api.honeycomb.io/1/markers/kafka%20prod" {
+		t.Errorf("Unexpected URL %s", m.url)
+	}
+}
+
+func TestPostEvent(t *testing.T) {
+	var got marker
+	var key, path string
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get("X-Honeycomb-Team")
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&got)
+
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer s.Close()
+
+	m, _ := NewMarkerWriter(&Config{APIKey: "key", Dataset: "kafka", APIHost: s.URL + "/"})
+
+	err := m.PostEvent(&kafkametrics.Event{
+		Title: "[kafka-autothrottle] Broker replication throttle set",
+		Text:  "Replication throttle of 100.00MB/s set on the following brokers: [1001]\n",
+		Type:  "broker-replication-throttle-set",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key != "key" || path != "/1/markers/kafka" {
+		t.Errorf("Unexpected request: key %s, path %s", key, path)
+	}
+
+	expected := "[kafka-autothrottle] Broker replication throttle set: Replication throttle of 100.00MB/s set on the following brokers: [1001]"
+	if got.Message != expected || got.Type != "broker-replication-throttle-set" {
+		t.Errorf("Unexpected marker: %+v", got)
+	}
+}
+
+func TestPostEventError(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"unknown API key"}`))
+	}))
+	defer s.Close()
+
+	m, _ := NewMarkerWriter(&Config{APIKey: "key", Dataset: "kafka", APIHost: s.URL})
+
+	err := m.PostEvent(&kafkametrics.Event{Title: "title"})
+	expected := `API error [create marker]: 401 Unauthorized: {"error":"unknown API key"}`
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error '%s', got '%v'", expected, err)
+	}
+}
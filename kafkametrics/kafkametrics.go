@@ -21,6 +21,16 @@ type Broker struct {
 	Host         string
 	InstanceType string
 	NetTX        float64
+	NetRX        float64
+	// Disk utilization (percent).
+	DiskUtil float64
+	// Network capacity (MB/s), if reported
+	// by the backend; 0 if unknown.
+	NetCapacity float64
+	// Ratio of on-disk to network bytes of
+	// replicated data, if reported by the
+	// backend; 0 if unknown.
+	CompressionRatio float64
 }
 
 // Event is used to post autothrottle
@@ -29,4 +39,8 @@ type Event struct {
 	Title string
 	Text  string
 	Tags  []string
+	// Type is a short, stable identifier
+	// of the kind of event, used by backends
+	// that categorize events.
+	Type string
 }
@@ -17,6 +17,8 @@ func (k *Mock) GetMetrics() (BrokerMetrics, []error) {
 			Host:         fmt.Sprintf("host%d", i),
 			InstanceType: "mock",
 			NetTX:        100.00 + float64(i),
+			NetRX:        90.00 - float64(i),
+			DiskUtil:     50.00 + float64(i),
 		}
 	}
 
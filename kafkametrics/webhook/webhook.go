@@ -0,0 +1,136 @@
+// Package webhook writes kafkametrics Events
+// to Slack incoming webhooks or generic
+// JSON webhooks.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+)
+
+// Payload formats.
+const (
+	// FormatJSON posts the event
+	// fields as a JSON object.
+	FormatJSON = "json"
+	// FormatSlack posts a Slack
+	// incoming webhook message.
+	FormatSlack = "slack"
+)
+
+// Config holds Notifier
+// configuration parameters.
+type Config struct {
+	// Webhook URL.
+	URL string
+	// Payload format; FormatJSON
+	// or FormatSlack. Defaults to
+	// FormatJSON.
+	Format string
+	// Request timeout; defaults to 10s.
+	Timeout time.Duration
+}
+
+// Notifier posts events to a webhook.
+type Notifier struct {
+	c      *http.Client
+	url    string
+	format string
+}
+
+// jsonPayload is a FormatJSON request body.
+type jsonPayload struct {
+	Title     string   `json:"title"`
+	Text      string   `json:"text"`
+	Type      string   `json:"type,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Timestamp int64    `json:"timestamp"`
+}
+
+// slackPayload is a FormatSlack request body.
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// NewNotifier takes a *Config and returns a *Notifier.
+func NewNotifier(c *Config) (*Notifier, error) {
+	if c.URL == "" {
+		return nil, errors.New("Webhook URL must be specified")
+	}
+
+	format := c.Format
+	switch format {
+	case "":
+		format = FormatJSON
+	case FormatJSON, FormatSlack:
+	default:
+		return nil, fmt.Errorf("Unknown webhook format %s", format)
+	}
+
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &Notifier{
+		c:      &http.Client{Timeout: timeout},
+		url:    c.URL,
+		format: format,
+	}, nil
+}
+
+// PostEvent writes the *kafkametrics.Event to the webhook.
+func (n *Notifier) PostEvent(e *kafkametrics.Event) error {
+	body, err := n.payload(e)
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.c.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return &kafkametrics.APIError{
+			Request: "post webhook",
+			Message: err.Error(),
+		}
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return &kafkametrics.APIError{
+			Request: "post webhook",
+			Message: fmt.Sprintf("%s: %s", resp.Status, strings.TrimSpace(string(b))),
+		}
+	}
+
+	return nil
+}
+
+func (n *Notifier) payload(e *kafkametrics.Event) ([]byte, error) {
+	if n.format == FormatSlack {
+		text := fmt.Sprintf("*%s*", e.Title)
+		if t := strings.TrimSpace(e.Text); t != "" {
+			text = fmt.Sprintf("%s\n%s", text, t)
+		}
+
+		return json.Marshal(slackPayload{Text: text})
+	}
+
+	return json.Marshal(jsonPayload{
+		Title:     e.Title,
+		Text:      e.Text,
+		Type:      e.Type,
+		Tags:      e.Tags,
+		Timestamp: time.Now().Unix(),
+	})
+}
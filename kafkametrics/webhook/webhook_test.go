@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+)
+
+func TestNewNotifier(t *testing.T) {
+	if _, err := NewNotifier(&Config{}); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	if _, err := NewNotifier(&Config{URL: "http://localhost", Format: "xml"}); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	n, err := NewNotifier(&Config{URL: "http://localhost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n.format != FormatJSON {
+		t.Errorf("Expected format %s, got %s", FormatJSON, n.format)
+	}
+}
+
+var testEvent = &kafkametrics.Event{
+	Title: "[kafka-autothrottle] Broker replication throttle set",
+	Text:  "Replication throttle of 100.00MB/s set on the following brokers: [1001]\n",
+	Tags:  []string{"name:kafka-autothrottle"},
+	Type:  "broker-replication-throttle-set",
+}
+
+func TestPostEventJSON(t *testing.T) {
+	var got jsonPayload
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer s.Close()
+
+	n, _ := NewNotifier(&Config{URL: s.URL})
+	if err := n.PostEvent(testEvent); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Title != testEvent.Title || got.Text != testEvent.Text || got.Type != testEvent.Type {
+		t.Errorf("Unexpected payload %+v", got)
+	}
+
+	if len(got.Tags) != 1 || got.Timestamp == 0 {
+		t.Errorf("Unexpected payload %+v", got)
+	}
+}
+
+func TestPostEventSlack(t *testing.T) {
+	var got slackPayload
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer s.Close()
+
+	n, _ := NewNotifier(&Config{URL: s.URL, Format: FormatSlack})
+	if err := n.PostEvent(testEvent); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "*[kafka-autothrottle] Broker replication throttle set*\n" +
+		"Replication throttle of 100.00MB/s set on the following brokers: [1001]"
+
+	if got.Text != expected {
+		t.Errorf("Expected text '%s', got '%s'", expected, got.Text)
+	}
+}
+
+func TestPostEventError(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("no_service\n"))
+	}))
+	defer s.Close()
+
+	n, _ := NewNotifier(&Config{URL: s.URL, Format: FormatSlack})
+
+	err := n.PostEvent(testEvent)
+	if err == nil {
+		t.Fatal("Expected non-nil error")
+	}
+
+	if _, ok := err.(*kafkametrics.APIError); !ok {
+		t.Errorf("Expected *kafkametrics.APIError, got %T", err)
+	}
+}
@@ -0,0 +1,275 @@
+package kafkazk
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// AvailabilityViolation describes a partition that retains fewer in-sync,
+// rack-diverse replicas than required while a reassignment is applied.
+type AvailabilityViolation struct {
+	Topic     string
+	Partition int
+	// The wave in which the violation occurs.
+	Wave int
+	// Replicas that remain in sync throughout the
+	// transition, counting one per rack.
+	Available int
+	Min       int
+}
+
+// String returns a summary of the violation.
+func (v AvailabilityViolation) String() string {
+	return fmt.Sprintf("%s p%d: %d in-sync, rack-diverse replica(s) retained in wave %d, below minimum of %d",
+		v.Topic, v.Partition, v.Available, v.Wave, v.Min)
+}
+
+// ISRStates takes a Handler and returns the TopicStateISR
+// of every topic in the PartitionMap, keyed by topic name.
+func (pm *PartitionMap) ISRStates(zk Handler) (map[string]TopicStateISR, error) {
+	states := map[string]TopicStateISR{}
+
+	for _, p := range pm.Partitions {
+		if _, fetched := states[p.Topic]; fetched {
+			continue
+		}
+
+		s, err := zk.GetTopicStateISR(p.Topic)
+		if err != nil {
+			return nil, err
+		}
+		states[p.Topic] = s
+	}
+
+	return states, nil
+}
+
+// availableReplicas returns the number of distinct racks among the replicas
+// retained from the replica set r1 to r2 that are in sync. If isr is nil,
+// all replicas in r1 are assumed to be in sync.
+func availableReplicas(r1, r2, isr []int, bmm BrokerMetaMap) int {
+	inSync := map[int]bool{}
+	for _, id := range r1 {
+		inSync[id] = isr == nil
+	}
+	for _, id := range isr {
+		if _, exists := inSync[id]; exists {
+			inSync[id] = true
+		}
+	}
+
+	var retained []int
+	for _, id := range r2 {
+		if inSync[id] {
+			retained = append(retained, id)
+		}
+	}
+
+	return rackCount(retained, bmm)
+}
+
+// rackCount returns the number of distinct racks among the replica set r.
+// Brokers without a rack ID in the BrokerMetaMap are each counted as a
+// distinct rack.
+func rackCount(r []int, bmm BrokerMetaMap) int {
+	racks := map[string]struct{}{}
+	for _, id := range r {
+		rack := fmt.Sprintf("broker-%d", id)
+		if m, exists := bmm[id]; exists && m.Rack != "" {
+			rack = m.Rack
+		}
+
+		racks[rack] = struct{}{}
+	}
+
+	return len(racks)
+}
+
+// Waves takes an output PartitionMap pm2, a BrokerMetaMap, the current ISR
+// states (see ISRStates) and a minimum number of in-sync, rack-diverse
+// replicas that every partition must retain while the reassignment from pm
+// to pm2 is applied. The reassignment is split into ordered waves, each
+// represented by the PartitionMap to apply once the prior wave completes.
+//
+// Partitions where the complete reassignment retains the minimum are moved
+// in the first wave. All others are reassigned one replica at a time,
+// removing the replica whose removal retains the most racks and replacing
+// it with the replica that restores the most. The ISR states apply to the
+// first wave; replicas are assumed to be in sync at the completion of each
+// wave, and a nil ISR states map assumes all replicas are in sync.
+//
+// An AvailabilityViolation is returned for each wave where a partition falls
+// below the minimum regardless of ordering. The final wave is equal to pm2.
+// pm and pm2 must hold the same partitions in the same order.
+func (pm *PartitionMap) Waves(pm2 *PartitionMap, bmm BrokerMetaMap, isr map[string]TopicStateISR, min int) ([]*PartitionMap, []AvailabilityViolation) {
+	var violations []AvailabilityViolation
+
+	// The replica set of each partition
+	// at the completion of each wave.
+	steps := make([][][]int, len(pm.Partitions))
+
+	for i, p1 := range pm.Partitions {
+		p2 := pm2.Partitions[i]
+		if p1.Equal(p2) {
+			continue
+		}
+
+		var partnISR []int
+		if isr != nil {
+			partnISR = isr[p1.Topic][strconv.Itoa(p1.Partition)].ISR
+		}
+
+		// Move the partition in a single
+		// wave if it retains the minimum.
+		if availableReplicas(p1.Replicas, p2.Replicas, partnISR, bmm) >= min {
+			steps[i] = [][]int{p2.Replicas}
+			continue
+		}
+
+		target := map[int]bool{}
+		for _, id := range p2.Replicas {
+			target[id] = true
+		}
+
+		current := copyReplicas(p1.Replicas)
+		held := map[int]bool{}
+		for _, id := range current {
+			held[id] = true
+		}
+
+		var removals, additions []int
+		for _, id := range current {
+			if !target[id] {
+				removals = append(removals, id)
+			}
+		}
+		for _, id := range p2.Replicas {
+			if !held[id] {
+				additions = append(additions, id)
+			}
+		}
+
+		// Reassignments without removals retain all
+		// replicas; the partition is already below
+		// the minimum.
+		if len(removals) == 0 {
+			violations = append(violations, AvailabilityViolation{
+				Topic:     p1.Topic,
+				Partition: p1.Partition,
+				Wave:      1,
+				Available: availableReplicas(p1.Replicas, p2.Replicas, partnISR, bmm),
+				Min:       min,
+			})
+			steps[i] = [][]int{p2.Replicas}
+			continue
+		}
+
+		// Surplus additions retain all existing
+		// replicas and are applied in the first wave.
+		if surplus := len(additions) - len(removals); surplus > 0 {
+			current = append(current, additions[:surplus]...)
+			additions = additions[surplus:]
+		}
+
+		for n := 1; len(removals) > 0; n++ {
+			// Select the removal that retains the most racks.
+			best, bestAvail := 0, -1
+			for j, id := range removals {
+				next := without(current, id)
+				if a := availableReplicas(current, next, partnISR, bmm); a > bestAvail {
+					best, bestAvail = j, a
+				}
+			}
+
+			remove := removals[best]
+			removals = append(removals[:best], removals[best+1:]...)
+
+			if bestAvail < min {
+				violations = append(violations, AvailabilityViolation{
+					Topic:     p1.Topic,
+					Partition: p1.Partition,
+					Wave:      n,
+					Available: bestAvail,
+					Min:       min,
+				})
+			}
+
+			next := without(current, remove)
+
+			// Select the addition that restores the most racks.
+			if len(additions) > 0 {
+				best, bestAvail = 0, -1
+				for j, id := range additions {
+					if a := rackCount(append(copyReplicas(next), id), bmm); a > bestAvail {
+						best, bestAvail = j, a
+					}
+				}
+
+				// Replace the removed replica in position.
+				next = copyReplicas(current)
+				for k := range next {
+					if next[k] == remove {
+						next[k] = additions[best]
+					}
+				}
+
+				additions = append(additions[:best], additions[best+1:]...)
+			}
+
+			current = next
+			// All replicas are in sync following the first wave.
+			partnISR = nil
+
+			steps[i] = append(steps[i], current)
+		}
+
+		// The final step applies the output replica order.
+		steps[i][len(steps[i])-1] = p2.Replicas
+	}
+
+	n := 1
+	for _, s := range steps {
+		if len(s) > n {
+			n = len(s)
+		}
+	}
+
+	// Partitions hold their final
+	// replica set once complete.
+	waves := make([]*PartitionMap, n)
+	for w := range waves {
+		waves[w] = pm.Copy()
+		for i, s := range steps {
+			if len(s) == 0 {
+				continue
+			}
+
+			if w < len(s) {
+				waves[w].Partitions[i].Replicas = copyReplicas(s[w])
+			} else {
+				waves[w].Partitions[i].Replicas = copyReplicas(s[len(s)-1])
+			}
+		}
+	}
+
+	return waves, violations
+}
+
+// copyReplicas returns a copy of the replica set r.
+func copyReplicas(r []int) []int {
+	c := make([]int, len(r))
+	copy(c, r)
+	return c
+}
+
+// without returns a copy of the replica set r
+// with the broker ID id removed.
+func without(r []int, id int) []int {
+	var out []int
+	for _, b := range r {
+		if b != id {
+			out = append(out, b)
+		}
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestWaves(t *testing.T) {
+	bmm := BrokerMetaMap{
+		1001: &BrokerMeta{Rack: "a"},
+		1002: &BrokerMeta{Rack: "b"},
+		1003: &BrokerMeta{Rack: "c"},
+		1004: &BrokerMeta{Rack: "a"},
+		1005: &BrokerMeta{Rack: "b"},
+		1006: &BrokerMeta{Rack: "c"},
+	}
+
+	pm, _ := PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test_topic","partition":0,"replicas":[1001,1002,1003]},
+    {"topic":"test_topic","partition":1,"replicas":[1001,1002]},
+    {"topic":"test_topic","partition":2,"replicas":[1001,1002,1003]},
+    {"topic":"test_topic","partition":3,"replicas":[1001,1002,1003]}]}`)
+
+	pm2, _ := PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test_topic","partition":0,"replicas":[1006,1005,1004]},
+    {"topic":"test_topic","partition":1,"replicas":[1001,1003]},
+    {"topic":"test_topic","partition":2,"replicas":[1001,1002,1003]},
+    {"topic":"test_topic","partition":3,"replicas":[1001,1002,1004]}]}`)
+
+	waves, violations := pm.Waves(pm2, bmm, nil, 2)
+
+	// p0 is reassigned one replica at a time.
+	if len(waves) != 3 {
+		t.Fatalf("Expected 3 waves, got %d", len(waves))
+	}
+
+	for w, wave := range waves {
+		prev := pm
+		if w > 0 {
+			prev = waves[w-1]
+		}
+
+		held := map[int]bool{}
+		for _, id := range prev.Partitions[0].Replicas {
+			held[id] = true
+		}
+
+		var added int
+		for _, id := range wave.Partitions[0].Replicas {
+			if !held[id] {
+				added++
+			}
+		}
+
+		if added != 1 {
+			t.Errorf("Expected 1 replica added to p0 in wave %d, got %d", w+1, added)
+		}
+	}
+
+	// p3 retains 2 racks and is moved in the first wave;
+	// p2 is unchanged.
+	if !waves[0].Partitions[3].Equal(pm2.Partitions[3]) || !waves[0].Partitions[2].Equal(pm2.Partitions[2]) {
+		t.Errorf("Unexpected first wave: %v", waves[0].Partitions)
+	}
+
+	if eq, _ := waves[2].equal(pm2); !eq {
+		t.Errorf("Expected final wave to equal the output map, got %v", waves[2].Partitions)
+	}
+
+	// p1 can't retain 2 racks.
+	if len(violations) != 1 || violations[0].Partition != 1 || violations[0].Available != 1 {
+		t.Errorf("Unexpected violations: %v", violations)
+	}
+
+	// Replicas not in the ISR aren't available.
+	isr := map[string]TopicStateISR{
+		"test_topic": TopicStateISR{
+			"0": PartitionState{ISR: []int{1001, 1002, 1003}},
+			"1": PartitionState{ISR: []int{1001, 1002}},
+			"2": PartitionState{ISR: []int{1001, 1002, 1003}},
+			"3": PartitionState{ISR: []int{1001}},
+		},
+	}
+
+	_, violations = pm.Waves(pm2, bmm, isr, 2)
+	if len(violations) != 2 || violations[1].Partition != 3 {
+		t.Errorf("Unexpected violations: %v", violations)
+	}
+}
@@ -20,6 +20,7 @@ type BrokerMetaMap map[int]*BrokerMeta
 // used in satisfying constraints.
 type BrokerMeta struct {
 	StorageFree       float64 // In bytes.
+	StorageCapacity   float64 // In bytes; 0 if unknown.
 	MetricsIncomplete bool
 	// Metadata from ZooKeeper.
 	ListenerSecurityProtocolMap map[string]string `json:"listener_security_protocol_map"`
@@ -39,7 +40,8 @@ type BrokerMetricsMap map[int]*BrokerMetrics
 // BrokerMetrics holds broker metric
 // data fetched from ZK.
 type BrokerMetrics struct {
-	StorageFree float64
+	StorageFree     float64
+	StorageCapacity float64
 }
 
 // BrokerUseStats holds counts
@@ -99,9 +101,26 @@ type Broker struct {
 	Locality    string
 	Used        int
 	StorageFree float64
-	Replace     bool
-	Missing     bool
-	New         bool
+	// In bytes; 0 if unknown. If known for all
+	// brokers, storage balancing targets equal
+	// utilization rather than equal free bytes.
+	StorageCapacity float64
+	Replace         bool
+	Missing         bool
+	New             bool
+	// Brokers in maintenance keep their existing
+	// replicas but are never placement candidates.
+	Maintenance bool
+}
+
+// StorageFreeRatio returns the StorageFree as a portion
+// of the StorageCapacity; 0 if the capacity is unknown.
+func (b *Broker) StorageFreeRatio() float64 {
+	if b.StorageCapacity <= 0 {
+		return 0
+	}
+
+	return b.StorageFree / b.StorageCapacity
 }
 
 // BrokerMap holds a mapping of broker IDs to *Broker.
@@ -113,6 +132,7 @@ type BrokerList []*Broker
 // Wrapper types for sort by methods.
 type brokersByCount BrokerList
 type brokersByStorage BrokerList
+type brokersByStorageRatio BrokerList
 type brokersByID BrokerList
 
 // Satisfy the sort interface for BrokerList types.
@@ -145,6 +165,21 @@ func (b brokersByStorage) Less(i, j int) bool {
 	return b[i].ID < b[j].ID
 }
 
+// By StorageFreeRatio value.
+func (b brokersByStorageRatio) Len() int      { return len(b) }
+func (b brokersByStorageRatio) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b brokersByStorageRatio) Less(i, j int) bool {
+	r1, r2 := b[i].StorageFreeRatio(), b[j].StorageFreeRatio()
+	if r1 > r2 {
+		return true
+	}
+	if r1 < r2 {
+		return false
+	}
+
+	return b[i].ID < b[j].ID
+}
+
 // By ID value ascending.
 func (b brokersByID) Len() int           { return len(b) }
 func (b brokersByID) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
@@ -157,16 +192,88 @@ func (b BrokerList) SortByCount() {
 	sort.Sort(brokersByCount(b))
 }
 
-// SortByStorage sorts the BrokerList by StorageFree values.
+// SortByStorage sorts the BrokerList by StorageFree values. If the
+// StorageCapacity is known for all brokers, the list is sorted by
+// StorageFreeRatio values (utilization ascending) instead.
 func (b BrokerList) SortByStorage() {
+	if b.CapacityKnown() {
+		sort.Sort(brokersByStorageRatio(b))
+		return
+	}
+
 	sort.Sort(brokersByStorage(b))
 }
 
+// CapacityKnown returns whether the StorageCapacity is known for all
+// brokers in the BrokerList. Brokers marked for replacement are ignored.
+func (b BrokerList) CapacityKnown() bool {
+	var n int
+	for _, br := range b {
+		if br.Replace {
+			continue
+		}
+
+		if br.StorageCapacity <= 0 {
+			return false
+		}
+		n++
+	}
+
+	return n > 0
+}
+
 // SortByID sorts the BrokerList by ID values.
 func (b BrokerList) SortByID() {
 	sort.Sort(brokersByID(b))
 }
 
+// SortByStorageAndCount sorts the BrokerList by a weighted score of
+// storage utilization and Used counts, ascending. Both are normalized
+// to the greatest StorageFree and Used values in the list. The count
+// weight w (0.00-1.00) sets the priority of Used counts; storage is
+// weighted 1-w. A w of 0.00 is equivalent to SortByStorage. As with
+// SortByStorage, StorageFreeRatio values are used in place of StorageFree
+// if the StorageCapacity is known for all brokers.
+func (b BrokerList) SortByStorageAndCount(w float64) {
+	free := func(br *Broker) float64 { return br.StorageFree }
+	if b.CapacityKnown() {
+		free = func(br *Broker) float64 { return br.StorageFreeRatio() }
+	}
+
+	var maxFree float64
+	var maxUsed int
+
+	for _, br := range b {
+		if f := free(br); f > maxFree {
+			maxFree = f
+		}
+		if br.Used > maxUsed {
+			maxUsed = br.Used
+		}
+	}
+
+	scores := make(map[int]float64, len(b))
+	for _, br := range b {
+		var s float64
+		if maxFree > 0 {
+			s += (1 - w) * (1 - free(br)/maxFree)
+		}
+		if maxUsed > 0 {
+			s += w * float64(br.Used) / float64(maxUsed)
+		}
+		scores[br.ID] = s
+	}
+
+	sort.Slice(b, func(i, j int) bool {
+		s1, s2 := scores[b[i].ID], scores[b[j].ID]
+		if s1 != s2 {
+			return s1 < s2
+		}
+
+		return b[i].ID < b[j].ID
+	})
+}
+
 // BrokerFilterFn is a filter function
 // for BrokerList and BrokerMap types.
 type BrokerFilterFn func(*Broker) bool
@@ -184,7 +291,24 @@ func (b BrokerList) SortPseudoShuffle(seed int64) {
 		return
 	}
 
-	rand.Seed(seed)
+	// Seeding is comparatively expensive and runs of a
+	// single broker don't consume random values; only
+	// seed once a run that requires shuffling is found.
+	var seeded bool
+	shuffle := func(r BrokerList) {
+		if len(r) < 2 {
+			return
+		}
+
+		if !seeded {
+			rand.Seed(seed)
+			seeded = true
+		}
+
+		rand.Shuffle(len(r), func(i, j int) {
+			r[i], r[j] = r[j], r[i]
+		})
+	}
 
 	s := 0
 	stop := len(b) - 1
@@ -197,14 +321,10 @@ func (b BrokerList) SortPseudoShuffle(seed int64) {
 		switch {
 		case b[k].Used != currVal:
 			currVal = b[k].Used
-			rand.Shuffle(len(b[s:k]), func(i, j int) {
-				b[s:k][i], b[s:k][j] = b[s:k][j], b[s:k][i]
-			})
+			shuffle(b[s:k])
 			s = k
 		case k == stop:
-			rand.Shuffle(len(b[s:]), func(i, j int) {
-				b[s:][i], b[s:][j] = b[s:][j], b[s:][i]
-			})
+			shuffle(b[s:])
 		}
 	}
 }
@@ -297,12 +417,13 @@ func (b BrokerMap) Update(bl []int, bm BrokerMetaMap) (*BrokerStatus, <-chan str
 			// the broker metadata map.
 			if meta, exists := bm[id]; exists {
 				b[id] = &Broker{
-					Used:        0,
-					ID:          id,
-					Replace:     false,
-					Locality:    meta.Rack,
-					StorageFree: meta.StorageFree,
-					New:         true,
+					Used:            0,
+					ID:              id,
+					Replace:         false,
+					Locality:        meta.Rack,
+					StorageFree:     meta.StorageFree,
+					StorageCapacity: meta.StorageCapacity,
+					New:             true,
 				}
 				bs.New++
 			} else {
@@ -426,6 +547,7 @@ func BrokerMapFromPartitionMap(pm *PartitionMap, bm BrokerMetaMap, force bool) B
 			if meta, exists := bm[id]; exists {
 				bmap[id].Locality = meta.Rack
 				bmap[id].StorageFree = meta.StorageFree
+				bmap[id].StorageCapacity = meta.StorageCapacity
 			}
 		}
 	}
@@ -441,28 +563,42 @@ func (b BrokerMap) Copy() BrokerMap {
 	c := BrokerMap{}
 	for id, br := range b {
 		c[id] = &Broker{
-			ID:          br.ID,
-			Locality:    br.Locality,
-			Used:        br.Used,
-			StorageFree: br.StorageFree,
-			Replace:     br.Replace,
-			Missing:     br.Missing,
-			New:         br.New,
+			ID:              br.ID,
+			Locality:        br.Locality,
+			Used:            br.Used,
+			StorageFree:     br.StorageFree,
+			StorageCapacity: br.StorageCapacity,
+			Replace:         br.Replace,
+			Missing:         br.Missing,
+			New:             br.New,
+			Maintenance:     br.Maintenance,
 		}
 	}
 
 	return c
 }
 
+// SetMaintenance flags the brokers in the BrokerMap
+// with IDs in ids as in maintenance.
+func (b BrokerMap) SetMaintenance(ids []int) {
+	for _, id := range ids {
+		if br, exists := b[id]; exists {
+			br.Maintenance = true
+		}
+	}
+}
+
 // Copy returns a copy of a Broker.
 func (b Broker) Copy() Broker {
 	return Broker{
-		ID:          b.ID,
-		Locality:    b.Locality,
-		Used:        b.Used,
-		StorageFree: b.StorageFree,
-		Replace:     b.Replace,
-		Missing:     b.Missing,
-		New:         b.New,
+		ID:              b.ID,
+		Locality:        b.Locality,
+		Used:            b.Used,
+		StorageFree:     b.StorageFree,
+		StorageCapacity: b.StorageCapacity,
+		Replace:         b.Replace,
+		Missing:         b.Missing,
+		New:             b.New,
+		Maintenance:     b.Maintenance,
 	}
 }
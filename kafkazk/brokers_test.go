@@ -75,6 +75,81 @@ func TestSortBrokerListByStorage(t *testing.T) {
 	}
 }
 
+func TestSortBrokerListByStorageCapacity(t *testing.T) {
+	b := newMockBrokerMap2()
+	bl := b.Filter(func(b *Broker) bool { return true }).List()
+
+	// Free storage ratios: 1001 0.50, 1002 0.20,
+	// 1003 0.30, 1004-1007 0.10.
+	capacities := map[int]float64{
+		1001: 200.00, 1002: 1000.00, 1003: 1000.00,
+		1004: 4000.00, 1005: 4000.00, 1006: 4000.00, 1007: 4000.00,
+	}
+
+	for _, br := range bl {
+		br.StorageCapacity = capacities[br.ID]
+	}
+
+	if !bl.CapacityKnown() {
+		t.Fatal("Expected capacity to be known")
+	}
+
+	bl.SortByStorage()
+
+	var blIDs []int
+	for _, br := range bl {
+		blIDs = append(blIDs, br.ID)
+	}
+
+	expected := []int{1001, 1003, 1002, 1004, 1005, 1006, 1007}
+
+	for i, br := range bl {
+		if br.ID != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, blIDs)
+		}
+	}
+
+	// Free storage is compared if the
+	// capacity of any broker is unknown.
+	b[1001].StorageCapacity = 0
+
+	if bl.CapacityKnown() {
+		t.Fatal("Expected capacity to be unknown")
+	}
+
+	bl.SortByStorage()
+
+	if bl[0].ID != 1004 {
+		t.Errorf("Expected broker 1004 first, got %d", bl[0].ID)
+	}
+}
+
+func TestSortBrokerListByStorageAndCount(t *testing.T) {
+	b := newMockBrokerMap2()
+	bl := b.Filter(func(b *Broker) bool { return true }).List()
+
+	tests := map[float64][]int{
+		0.00: []int{1004, 1005, 1006, 1007, 1003, 1002, 1001},
+		0.50: []int{1004, 1005, 1006, 1007, 1002, 1003, 1001},
+		1.00: []int{1001, 1002, 1004, 1005, 1003, 1006, 1007},
+	}
+
+	for w, expected := range tests {
+		bl.SortByStorageAndCount(w)
+
+		var blIDs []int
+		for _, br := range bl {
+			blIDs = append(blIDs, br.ID)
+		}
+
+		for i, br := range bl {
+			if br.ID != expected[i] {
+				t.Fatalf("[weight %.2f] Expected %v, got %v", w, expected, blIDs)
+			}
+		}
+	}
+}
+
 func TestSortBrokerListByID(t *testing.T) {
 	b := newMockBrokerMap2()
 	bl := b.Filter(func(b *Broker) bool { return true }).List()
@@ -376,6 +451,8 @@ func TestBrokerMapCopy(t *testing.T) {
 			t.Error("replace field mismatch")
 		case bm1[b].StorageFree != bm2[b].StorageFree:
 			t.Error("StorageFree field mismatch")
+		case bm1[b].StorageCapacity != bm2[b].StorageCapacity:
+			t.Error("StorageCapacity field mismatch")
 		}
 	}
 }
@@ -394,6 +471,8 @@ func TestBrokerCopy(t *testing.T) {
 		t.Error("Used field mistmatch")
 	case b1.StorageFree != b2.StorageFree:
 		t.Error("StorageFree field mistmatch")
+	case b1.StorageCapacity != b2.StorageCapacity:
+		t.Error("StorageCapacity field mistmatch")
 	case b1.Replace != b2.Replace:
 		t.Error("Replace field mistmatch")
 	case b1.Missing != b2.Missing:
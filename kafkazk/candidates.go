@@ -0,0 +1,186 @@
+package kafkazk
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// BrokerCandidates is a BrokerList ordered for broker selection by a
+// selector method (see ConstraintsParams). Rather than sorting the full
+// list for each selection, the order is maintained as brokers are
+// selected by repositioning only the brokers whose values changed.
+type BrokerCandidates struct {
+	list   BrokerList
+	method string
+	// The count weight for the storage selector.
+	weight float64
+	// Whether StorageFreeRatio values are
+	// used in place of StorageFree.
+	ratio bool
+	// Normalization values for the weighted
+	// storage and count ordering.
+	maxFree float64
+	maxUsed int
+	// Breaks ties for the count selector.
+	rand *rand.Rand
+}
+
+// NewBrokerCandidates takes a BrokerList and a ConstraintsParams and returns
+// a *BrokerCandidates ordered by the SelectorMethod. The order is equivalent
+// to that of SortPseudoShuffle using the SeedVal for the count selector and
+// SortByStorage or SortByStorageAndCount using the CountWeight for the
+// storage selector. The BrokerList isn't modified.
+func NewBrokerCandidates(b BrokerList, p ConstraintsParams) (*BrokerCandidates, error) {
+	bc := &BrokerCandidates{
+		list:   append(BrokerList{}, b...),
+		method: p.SelectorMethod,
+		weight: p.CountWeight,
+		ratio:  b.CapacityKnown(),
+	}
+
+	switch p.SelectorMethod {
+	case "count":
+		bc.rand = rand.New(rand.NewSource(p.SeedVal))
+		bc.sortByCount()
+	case "storage":
+		bc.setNormalization()
+		sort.Slice(bc.list, func(i, j int) bool { return bc.less(bc.list[i], bc.list[j]) })
+	default:
+		return nil, ErrInvalidSelectionMethod
+	}
+
+	return bc, nil
+}
+
+// List returns the ordered BrokerList. The list
+// must not be modified.
+func (bc *BrokerCandidates) List() BrokerList {
+	return bc.list
+}
+
+// Update repositions the provided brokers following a change to
+// their StorageFree or Used values. Brokers not found in the
+// BrokerCandidates are ignored.
+func (bc *BrokerCandidates) Update(brokers ...*Broker) {
+	// A change in the normalization values
+	// of the weighted storage and count
+	// ordering affects all brokers.
+	if bc.method == "storage" && bc.weight > 0 {
+		if bc.setNormalization() {
+			sort.Slice(bc.list, func(i, j int) bool { return bc.less(bc.list[i], bc.list[j]) })
+			return
+		}
+	}
+
+	for _, b := range brokers {
+		for i := range bc.list {
+			if bc.list[i] == b {
+				bc.reposition(i)
+				break
+			}
+		}
+	}
+}
+
+// reposition moves the broker at index i to its position in the order.
+func (bc *BrokerCandidates) reposition(i int) {
+	b := bc.list[i]
+	l := append(bc.list[:i], bc.list[i+1:]...)
+
+	var j int
+	switch bc.method {
+	case "count":
+		// Place the broker at a random position
+		// among those with the same Used value.
+		lo := sort.Search(len(l), func(k int) bool { return l[k].Used >= b.Used })
+		hi := sort.Search(len(l), func(k int) bool { return l[k].Used > b.Used })
+		j = lo + bc.rand.Intn(hi-lo+1)
+	default:
+		j = sort.Search(len(l), func(k int) bool { return bc.less(b, l[k]) })
+	}
+
+	l = append(l, nil)
+	copy(l[j+1:], l[j:])
+	l[j] = b
+
+	bc.list = l
+}
+
+// sortByCount sorts the list by Used values
+// and shuffles each run of equal values.
+func (bc *BrokerCandidates) sortByCount() {
+	sort.Sort(brokersByCount(bc.list))
+
+	s := 0
+	for k := 1; k <= len(bc.list); k++ {
+		if k < len(bc.list) && bc.list[k].Used == bc.list[s].Used {
+			continue
+		}
+
+		r := bc.list[s:k]
+		bc.rand.Shuffle(len(r), func(i, j int) { r[i], r[j] = r[j], r[i] })
+		s = k
+	}
+}
+
+// free returns the StorageFree or StorageFreeRatio of the broker.
+func (bc *BrokerCandidates) free(b *Broker) float64 {
+	if bc.ratio {
+		return b.StorageFreeRatio()
+	}
+
+	return b.StorageFree
+}
+
+// setNormalization sets the greatest free storage and
+// Used values in the list, returning whether either changed.
+func (bc *BrokerCandidates) setNormalization() bool {
+	var maxFree float64
+	var maxUsed int
+
+	for _, b := range bc.list {
+		if f := bc.free(b); f > maxFree {
+			maxFree = f
+		}
+		if b.Used > maxUsed {
+			maxUsed = b.Used
+		}
+	}
+
+	changed := maxFree != bc.maxFree || maxUsed != bc.maxUsed
+	bc.maxFree, bc.maxUsed = maxFree, maxUsed
+
+	return changed
+}
+
+// score returns the weighted storage and
+// count score of the broker (see SortByStorageAndCount).
+func (bc *BrokerCandidates) score(b *Broker) float64 {
+	var s float64
+	if bc.maxFree > 0 {
+		s += (1 - bc.weight) * (1 - bc.free(b)/bc.maxFree)
+	}
+	if bc.maxUsed > 0 {
+		s += bc.weight * float64(b.Used) / float64(bc.maxUsed)
+	}
+
+	return s
+}
+
+// less returns whether the broker b1 precedes
+// b2 in the storage selector ordering.
+func (bc *BrokerCandidates) less(b1, b2 *Broker) bool {
+	if bc.weight > 0 {
+		if s1, s2 := bc.score(b1), bc.score(b2); s1 != s2 {
+			return s1 < s2
+		}
+
+		return b1.ID < b2.ID
+	}
+
+	if f1, f2 := bc.free(b1), bc.free(b2); f1 != f2 {
+		return f1 > f2
+	}
+
+	return b1.ID < b2.ID
+}
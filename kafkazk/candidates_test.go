@@ -0,0 +1,127 @@
+package kafkazk
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func testCandidateBrokers() BrokerList {
+	bl := BrokerList{}
+
+	for i := 0; i < 20; i++ {
+		bl = append(bl, &Broker{
+			ID:          1000 + i,
+			Locality:    []string{"a", "b", "c"}[i%3],
+			Used:        i % 4,
+			StorageFree: float64(1000 * (i % 7)),
+		})
+	}
+
+	return bl
+}
+
+func TestBrokerCandidatesStorage(t *testing.T) {
+	for _, w := range []float64{0, 0.50} {
+		bl := testCandidateBrokers()
+
+		bc, err := NewBrokerCandidates(bl, ConstraintsParams{SelectorMethod: "storage", CountWeight: w})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := rand.New(rand.NewSource(1))
+
+		// The order following updates must
+		// match that of a full sort.
+		for i := 0; i < 100; i++ {
+			b := bl[r.Intn(len(bl))]
+			b.StorageFree -= float64(r.Intn(500))
+			b.Used++
+			bc.Update(b)
+
+			expected := append(BrokerList{}, bl...)
+			if w > 0 {
+				expected.SortByStorageAndCount(w)
+			} else {
+				expected.SortByStorage()
+			}
+
+			for j := range expected {
+				if bc.List()[j] != expected[j] {
+					t.Fatalf("[weight %.2f] Expected broker %d at position %d, got %d",
+						w, expected[j].ID, j, bc.List()[j].ID)
+				}
+			}
+		}
+	}
+}
+
+func TestBrokerCandidatesCount(t *testing.T) {
+	bl := testCandidateBrokers()
+
+	bc, err := NewBrokerCandidates(bl, ConstraintsParams{SelectorMethod: "count", SeedVal: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewConstraints()
+
+	for i := 0; i < 40; i++ {
+		if _, err := c.SelectCandidate(bc, ConstraintsParams{}); err != nil {
+			t.Fatal(err)
+		}
+
+		// Reset the constraints so that
+		// all brokers remain candidates.
+		c = NewConstraints()
+
+		l := bc.List()
+		for j := 1; j < len(l); j++ {
+			if l[j].Used < l[j-1].Used {
+				t.Fatalf("Expected brokers ordered by Used, got %d before %d", l[j-1].Used, l[j].Used)
+			}
+		}
+	}
+
+	// 60 existing and 40 selected.
+	var used int
+	for _, b := range bl {
+		used += b.Used
+	}
+
+	if used != 70 {
+		t.Errorf("Expected a total Used of 70, got %d", used)
+	}
+
+	if _, err := NewBrokerCandidates(bl, ConstraintsParams{SelectorMethod: "random"}); err != ErrInvalidSelectionMethod {
+		t.Errorf("Expected ErrInvalidSelectionMethod, got %v", err)
+	}
+}
+
+func TestSelectCandidate(t *testing.T) {
+	bl := testCandidateBrokers()
+
+	bc, _ := NewBrokerCandidates(bl, ConstraintsParams{SelectorMethod: "storage"})
+
+	c := NewConstraints()
+	// Brokers 1006 and 1013 have the most storage free
+	// followed by 1005, 1012 and 1019. Removes 1006 and
+	// any brokers with locality "b" (1013).
+	c.id[1006] = true
+	c.locality["b"] = true
+
+	b, err := c.SelectCandidate(bc, ConstraintsParams{RequestSize: 500})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b.ID != 1005 {
+		t.Errorf("Expected candidate with ID 1005, got %d", b.ID)
+	}
+
+	// The selected broker is repositioned
+	// following its storage change.
+	if l := bc.List(); b.StorageFree != 4500 || l[3].ID != 1019 || l[4].ID != 1005 {
+		t.Errorf("Expected broker 1005 repositioned after 1019 with storage free of 4500, got %.2f", b.StorageFree)
+	}
+}
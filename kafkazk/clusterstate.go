@@ -0,0 +1,431 @@
+package kafkazk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// ClusterStateVersion is the current ClusterState format version.
+const ClusterStateVersion = 1
+
+var (
+	// ErrReadOnly error.
+	ErrReadOnly = errors.New("Cluster state is read-only")
+	// ErrUnsupportedClusterStateVersion error.
+	ErrUnsupportedClusterStateVersion = errors.New("Unsupported cluster state version")
+)
+
+// ClusterState is a capture of the Kafka cluster state stored in
+// ZooKeeper at a point in time. It includes everything referenced
+// for planning: broker metadata, topic assignments, configs and
+// partition states, in progress reassignments and the metrics
+// metadata.
+type ClusterState struct {
+	Version   int   `json:"version"`
+	Timestamp int64 `json:"timestamp"` // Unix seconds.
+	// Registered broker metadata, excluding metrics.
+	Brokers         BrokerMetaMap            `json:"brokers"`
+	Topics          map[string]TopicState    `json:"topics"`
+	TopicConfigs    map[string]TopicConfig   `json:"topic_configs"`
+	PartitionStates map[string]TopicStateISR `json:"partition_states"`
+	Reassignments   Reassignments            `json:"reassignments"`
+	// Metrics metadata; nil if unavailable. The MetricsTimestamp
+	// is that of the oldest metrics structure in Unix ns.
+	BrokerMetrics    BrokerMetricsMap `json:"broker_metrics"`
+	PartitionMeta    PartitionMetaMap `json:"partition_meta"`
+	MetricsTimestamp int64            `json:"metrics_timestamp"`
+	// User-defined registry tags by object type
+	// (broker, topic) and ID; only populated in
+	// states served by the registry.
+	Tags map[string]map[string]map[string]string `json:"tags,omitempty"`
+}
+
+// ObjectTags returns the registry tags for the object of type kind (broker,
+// topic) with the id. An empty map is returned if none are held.
+func (s *ClusterState) ObjectTags(kind, id string) map[string]string {
+	tags := map[string]string{}
+	for k, v := range s.Tags[kind][id] {
+		tags[k] = v
+	}
+
+	return tags
+}
+
+// ReadClusterState reads a ClusterState from the file at path.
+func ReadClusterState(path string) (*ClusterState, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ClusterState{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("Error unmarshalling cluster state: %s", err)
+	}
+
+	if s.Version != ClusterStateVersion {
+		return nil, ErrUnsupportedClusterStateVersion
+	}
+
+	return s, nil
+}
+
+// WriteClusterState writes the ClusterState to the file at path.
+func WriteClusterState(s *ClusterState, path string) error {
+	out, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(out, '\n'), 0644)
+}
+
+// ClusterStateFromZK takes a []*regexp.Regexp and Handler and returns a
+// *ClusterState of all topics matching any of the provided regex along
+// with all broker and metrics metadata. Unavailable metrics metadata is
+// omitted rather than treated as an error.
+func ClusterStateFromZK(t []*regexp.Regexp, zk Handler) (*ClusterState, error) {
+	s := &ClusterState{
+		Version:         ClusterStateVersion,
+		Timestamp:       time.Now().Unix(),
+		Topics:          map[string]TopicState{},
+		TopicConfigs:    map[string]TopicConfig{},
+		PartitionStates: map[string]TopicStateISR{},
+		Reassignments:   zk.GetReassignments(),
+	}
+
+	// Broker metadata.
+	bm, errs := zk.GetAllBrokerMeta(false)
+	if errs != nil {
+		return nil, errs[0]
+	}
+	s.Brokers = bm
+
+	// Topic states, configs and partition states.
+	topics, err := zk.GetTopics(t)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]*TopicState, len(topics))
+	configs := make([]*TopicConfig, len(topics))
+	isrs := make([]TopicStateISR, len(topics))
+
+	err = parallel(len(topics), concurrency(zk), func(i int) error {
+		var err error
+		if states[i], err = zk.GetTopicState(topics[i]); err != nil {
+			return err
+		}
+
+		// Partition states and configs may not yet
+		// exist for topics being created.
+		isrs[i], err = zk.GetTopicStateISR(topics[i])
+		if _, ok := err.(ErrNoNode); err != nil && !ok {
+			return err
+		}
+
+		configs[i], err = zk.GetTopicConfig(topics[i])
+		if _, ok := err.(ErrNoNode); err != nil && !ok {
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, topic := range topics {
+		s.Topics[topic] = *states[i]
+		if isrs[i] != nil {
+			s.PartitionStates[topic] = isrs[i]
+		}
+		if configs[i] != nil {
+			s.TopicConfigs[topic] = *configs[i]
+		}
+	}
+
+	// Metrics metadata.
+	if pmm, err := zk.GetAllPartitionMeta(); err == nil {
+		s.PartitionMeta = pmm
+	}
+
+	if bmm, _ := zk.GetAllBrokerMeta(true); bmm != nil {
+		s.BrokerMetrics = BrokerMetricsMap{}
+		for id, m := range bmm {
+			if !m.MetricsIncomplete {
+				s.BrokerMetrics[id] = &BrokerMetrics{
+					StorageFree:     m.StorageFree,
+					StorageCapacity: m.StorageCapacity,
+				}
+			}
+		}
+	}
+
+	if age, err := zk.MaxMetaAge(); err == nil {
+		s.MetricsTimestamp = time.Unix(s.Timestamp, 0).Add(-age).UnixNano()
+	}
+
+	return s, nil
+}
+
+// StateHandler implements the Handler interface backed by a
+// ClusterState, allowing planning without ZooKeeper connectivity.
+// All write operations return an ErrReadOnly.
+type StateHandler struct {
+	State *ClusterState
+}
+
+// NewStateHandler takes a *ClusterState and returns a Handler.
+func NewStateHandler(s *ClusterState) Handler {
+	return &StateHandler{State: s}
+}
+
+func errStateNoNode(p string) error {
+	return ErrNoNode{s: fmt.Sprintf("[%s] not found in cluster state", p)}
+}
+
+// Exists returns false for all paths; the
+// ClusterState holds no raw ZooKeeper data.
+func (s *StateHandler) Exists(p string) (bool, error) {
+	return false, nil
+}
+
+// Create returns an ErrReadOnly.
+func (s *StateHandler) Create(p string, d string) error {
+	return ErrReadOnly
+}
+
+// CreateSequential returns an ErrReadOnly.
+func (s *StateHandler) CreateSequential(p string, d string) error {
+	return ErrReadOnly
+}
+
+// CreateEphemeralSequential returns an ErrReadOnly.
+func (s *StateHandler) CreateEphemeralSequential(p string, d string) (string, error) {
+	return "", ErrReadOnly
+}
+
+// Set returns an ErrReadOnly.
+func (s *StateHandler) Set(p string, d string) error {
+	return ErrReadOnly
+}
+
+// Get returns an ErrNoNode for all paths.
+func (s *StateHandler) Get(p string) ([]byte, error) {
+	return nil, errStateNoNode(p)
+}
+
+// Delete returns an ErrReadOnly.
+func (s *StateHandler) Delete(p string) error {
+	return ErrReadOnly
+}
+
+// Children returns an ErrNoNode for all paths.
+func (s *StateHandler) Children(p string) ([]string, error) {
+	return nil, errStateNoNode(p)
+}
+
+// Close is a no-op.
+func (s *StateHandler) Close() {}
+
+// Ready always returns true.
+func (s *StateHandler) Ready() bool {
+	return true
+}
+
+// GetTopicState returns the TopicState for topic t.
+func (s *StateHandler) GetTopicState(t string) (*TopicState, error) {
+	ts, exists := s.State.Topics[t]
+	if !exists {
+		return nil, errStateNoNode(fmt.Sprintf("/brokers/topics/%s", t))
+	}
+
+	// Return a copy so that callers may
+	// modify the returned TopicState.
+	c := &TopicState{Partitions: map[string][]int{}}
+	for p, replicas := range ts.Partitions {
+		c.Partitions[p] = append([]int{}, replicas...)
+	}
+
+	return c, nil
+}
+
+// GetTopicStateISR returns the TopicStateISR for topic t.
+func (s *StateHandler) GetTopicStateISR(t string) (TopicStateISR, error) {
+	ts, exists := s.State.PartitionStates[t]
+	if !exists {
+		return nil, errStateNoNode(fmt.Sprintf("/brokers/topics/%s/partitions", t))
+	}
+
+	return ts, nil
+}
+
+// UpdateKafkaConfig returns an ErrReadOnly.
+func (s *StateHandler) UpdateKafkaConfig(c KafkaConfig) (bool, error) {
+	return false, ErrReadOnly
+}
+
+// ElectPreferredLeaders returns an ErrReadOnly.
+func (s *StateHandler) ElectPreferredLeaders(e PreferredReplicaElection) error {
+	return ErrReadOnly
+}
+
+// GetReassignments returns the Reassignments in
+// progress at the time of the ClusterState capture.
+func (s *StateHandler) GetReassignments() Reassignments {
+	if s.State.Reassignments == nil {
+		return Reassignments{}
+	}
+
+	return s.State.Reassignments
+}
+
+// ReassignPartitions returns an ErrReadOnly.
+func (s *StateHandler) ReassignPartitions(pm *PartitionMap) error {
+	return ErrReadOnly
+}
+
+// DeleteTopic returns an ErrReadOnly.
+func (s *StateHandler) DeleteTopic(t string) error {
+	return ErrReadOnly
+}
+
+// GetTopics takes a []*regexp.Regexp and returns a []string of all topic
+// names that match any of the provided regex.
+func (s *StateHandler) GetTopics(ts []*regexp.Regexp) ([]string, error) {
+	matchingTopics := []string{}
+
+	for topic := range s.State.Topics {
+		for _, topicRe := range ts {
+			if topicRe.MatchString(topic) {
+				matchingTopics = append(matchingTopics, topic)
+				break
+			}
+		}
+	}
+
+	sort.Strings(matchingTopics)
+
+	return matchingTopics, nil
+}
+
+// GetTopicConfig returns the *TopicConfig for topic t.
+func (s *StateHandler) GetTopicConfig(t string) (*TopicConfig, error) {
+	c, exists := s.State.TopicConfigs[t]
+	if !exists {
+		return nil, errStateNoNode(fmt.Sprintf("/config/topics/%s", t))
+	}
+
+	return &c, nil
+}
+
+// GetConfigChanges returns no changes; a
+// ClusterState holds no change notifications.
+func (s *StateHandler) GetConfigChanges(since int64) ([]ConfigChange, error) {
+	return nil, nil
+}
+
+// GetBrokerConfig returns an ErrNoNode; the
+// ClusterState holds no broker configs.
+func (s *StateHandler) GetBrokerConfig(id int) (*KafkaConfigData, error) {
+	return nil, errStateNoNode(fmt.Sprintf("/config/brokers/%d", id))
+}
+
+// GetAllBrokerMeta returns a BrokerMetaMap of all brokers in the
+// ClusterState. If withMetrics is true, broker metrics are included.
+func (s *StateHandler) GetAllBrokerMeta(withMetrics bool) (BrokerMetaMap, []error) {
+	var errs []error
+
+	bmm := BrokerMetaMap{}
+	for id, m := range s.State.Brokers {
+		c := *m
+		bmm[id] = &c
+	}
+
+	if !withMetrics {
+		return bmm, nil
+	}
+
+	if s.State.BrokerMetrics == nil {
+		return nil, []error{errors.New("Error fetching broker metrics: not found in cluster state")}
+	}
+
+	for id, m := range bmm {
+		metrics, exists := s.State.BrokerMetrics[id]
+		if !exists {
+			errs = append(errs, fmt.Errorf("Metrics not found for broker %d", id))
+			m.MetricsIncomplete = true
+		} else {
+			m.StorageFree = metrics.StorageFree
+			m.StorageCapacity = metrics.StorageCapacity
+		}
+	}
+
+	return bmm, errs
+}
+
+// GetAllPartitionMeta returns the PartitionMetaMap.
+func (s *StateHandler) GetAllPartitionMeta() (PartitionMetaMap, error) {
+	if s.State.PartitionMeta == nil {
+		return nil, errors.New("Error fetching partition meta: not found in cluster state")
+	}
+
+	return s.State.PartitionMeta, nil
+}
+
+// MaxMetaAge returns the age of the metrics metadata
+// at the time of the ClusterState capture.
+func (s *StateHandler) MaxMetaAge() (time.Duration, error) {
+	if s.State.MetricsTimestamp == 0 {
+		return time.Nanosecond, errStateNoNode("metrics")
+	}
+
+	captured := time.Unix(s.State.Timestamp, 0)
+
+	return captured.Sub(time.Unix(0, s.State.MetricsTimestamp)), nil
+}
+
+// GetPartitionMap returns the *PartitionMap for topic t.
+func (s *StateHandler) GetPartitionMap(t string) (*PartitionMap, error) {
+	ts, err := s.GetTopicState(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return partitionMapFromTopicState(t, ts, s.GetReassignments()), nil
+}
+
+// GetQuotas returns an empty QuotaMap; quotas
+// aren't captured in the ClusterState.
+func (s *StateHandler) GetQuotas() (QuotaMap, error) {
+	return QuotaMap{}, nil
+}
+
+// SetQuotas returns an ErrReadOnly.
+func (s *StateHandler) SetQuotas(e QuotaEntity, q Quotas) (bool, error) {
+	return false, ErrReadOnly
+}
+
+// GetTopicStateW returns the *TopicState of topic t. The ClusterState
+// doesn't change; the returned channel is never closed.
+func (s *StateHandler) GetTopicStateW(t string) (*TopicState, <-chan struct{}, error) {
+	ts, err := s.GetTopicState(t)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ts, make(chan struct{}), nil
+}
+
+// WatchBrokers returns a channel that's never
+// closed, as the ClusterState doesn't change.
+func (s *StateHandler) WatchBrokers() (<-chan struct{}, error) {
+	return make(chan struct{}), nil
+}
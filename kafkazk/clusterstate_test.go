@@ -0,0 +1,181 @@
+package kafkazk
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func testClusterState() *ClusterState {
+	return &ClusterState{
+		Version:   ClusterStateVersion,
+		Timestamp: 1500000060,
+		Brokers: BrokerMetaMap{
+			1001: &BrokerMeta{Rack: "a"},
+			1002: &BrokerMeta{Rack: "b"},
+			1003: &BrokerMeta{Rack: "c"},
+		},
+		Topics: map[string]TopicState{
+			"test_topic":  {Partitions: map[string][]int{"0": {1001, 1002}, "1": {1002, 1001}}},
+			"other_topic": {Partitions: map[string][]int{"0": {1003, 1001}}},
+		},
+		TopicConfigs: map[string]TopicConfig{
+			"test_topic": {Version: 1, Config: map[string]string{"min.insync.replicas": "2"}},
+		},
+		PartitionStates: map[string]TopicStateISR{
+			"test_topic": {"0": PartitionState{Leader: 1001, ISR: []int{1001, 1002}}},
+		},
+		Reassignments: Reassignments{
+			"test_topic": {1: []int{1002, 1003}},
+		},
+		BrokerMetrics: BrokerMetricsMap{
+			1001: &BrokerMetrics{StorageFree: 1000},
+			1002: &BrokerMetrics{StorageFree: 2000},
+		},
+		PartitionMeta: PartitionMetaMap{
+			"test_topic": {0: &PartitionMeta{Size: 100}},
+		},
+		MetricsTimestamp: 1500000000 * int64(time.Second),
+	}
+}
+
+func TestReadClusterState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kafkazk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "state.json")
+
+	s := testClusterState()
+	data, _ := json.Marshal(s)
+	ioutil.WriteFile(path, data, 0644)
+
+	s2, err := ReadClusterState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s2.Topics) != 2 || s2.Reassignments["test_topic"][1][1] != 1003 {
+		t.Error("Unexpected cluster state contents")
+	}
+
+	// Unsupported versions.
+	s.Version = 99
+	data, _ = json.Marshal(s)
+	ioutil.WriteFile(path, data, 0644)
+
+	if _, err := ReadClusterState(path); err != ErrUnsupportedClusterStateVersion {
+		t.Errorf("Expected error '%s', got '%v'", ErrUnsupportedClusterStateVersion, err)
+	}
+}
+
+func TestStateHandler(t *testing.T) {
+	zk := NewStateHandler(testClusterState())
+
+	topics, _ := zk.GetTopics([]*regexp.Regexp{regexp.MustCompile("topic")})
+	if len(topics) != 2 || topics[0] != "other_topic" {
+		t.Errorf("Unexpected topics: %v", topics)
+	}
+
+	// Pending reassignments are reflected.
+	pm, err := zk.GetPartitionMap("test_topic")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pm.Partitions) != 2 || pm.Partitions[1].Replicas[1] != 1003 {
+		t.Errorf("Unexpected partition map: %v", pm.Partitions)
+	}
+
+	// The underlying state is unmodified.
+	if ts, _ := zk.GetTopicState("test_topic"); ts.Partitions["1"][1] != 1001 {
+		t.Error("Unexpected modification of topic state")
+	}
+
+	if _, err := zk.GetPartitionMap("missing"); err == nil {
+		t.Error("Expected error for missing topic")
+	} else if _, ok := err.(ErrNoNode); !ok {
+		t.Errorf("Expected ErrNoNode, got %T", err)
+	}
+
+	bm, errs := zk.GetAllBrokerMeta(true)
+	if len(errs) != 1 || !bm[1003].MetricsIncomplete || bm[1002].StorageFree != 2000 {
+		t.Errorf("Unexpected broker meta: %v", errs)
+	}
+
+	if age, _ := zk.MaxMetaAge(); age != time.Minute {
+		t.Errorf("Expected metrics age of 1m, got %s", age)
+	}
+
+	if m, _ := MinISR(zk, "test_topic", 1); m != 2 {
+		t.Errorf("Expected min ISR 2, got %d", m)
+	}
+
+	if err := zk.Create("/path", ""); err != ErrReadOnly {
+		t.Errorf("Expected error '%s', got '%v'", ErrReadOnly, err)
+	}
+
+	merged, err := PartitionMapFromZK([]*regexp.Regexp{regexp.MustCompile(".*")}, zk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(merged.Partitions) != 3 {
+		t.Errorf("Expected 3 partitions, got %d", len(merged.Partitions))
+	}
+}
+
+func TestClusterStateFromZK(t *testing.T) {
+	s := testClusterState()
+	zk := NewStateHandler(s)
+
+	s2, err := ClusterStateFromZK([]*regexp.Regexp{regexp.MustCompile("test_topic")}, zk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s2.Topics) != 1 || len(s2.Brokers) != 3 {
+		t.Errorf("Expected 1 topic and 3 brokers, got %d and %d", len(s2.Topics), len(s2.Brokers))
+	}
+
+	if s2.TopicConfigs["test_topic"].Config["min.insync.replicas"] != "2" {
+		t.Error("Expected topic config for test_topic")
+	}
+
+	// Broker metadata excludes metrics.
+	if s2.Brokers[1001].StorageFree != 0 || s2.BrokerMetrics[1001].StorageFree != 1000 {
+		t.Error("Unexpected broker metrics")
+	}
+
+	if _, exists := s2.BrokerMetrics[1003]; exists {
+		t.Error("Unexpected metrics for broker 1003")
+	}
+
+	if age := time.Unix(s2.Timestamp, 0).Sub(time.Unix(0, s2.MetricsTimestamp)); age != time.Minute {
+		t.Errorf("Expected metrics age of 1m, got %s", age)
+	}
+
+	// Missing metrics are omitted.
+	s.BrokerMetrics, s.PartitionMeta, s.MetricsTimestamp = nil, nil, 0
+
+	s2, err = ClusterStateFromZK([]*regexp.Regexp{regexp.MustCompile(".*")}, zk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s2.BrokerMetrics != nil || s2.PartitionMeta != nil || s2.MetricsTimestamp != 0 {
+		t.Error("Expected metrics to be omitted")
+	}
+
+	// Topics without configs or partition states.
+	if len(s2.Topics) != 2 {
+		t.Errorf("Expected 2 topics, got %d", len(s2.Topics))
+	}
+}
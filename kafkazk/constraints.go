@@ -34,11 +34,25 @@ type ConstraintsParams struct {
 	MinUniqueRackIDs int
 	RequestSize      float64
 	SeedVal          int64
+	// The weight (0.00-1.00) of broker Used counts
+	// relative to storage for the storage selector.
+	CountWeight float64
+	// If set, candidates must be in
+	// the RequiredLocality.
+	RequiredLocality string
+	// If set, candidates must not be
+	// in the ExcludedLocality.
+	ExcludedLocality string
+	// If set, candidates must be permitted for
+	// the Topic by the TopicConstraints.
+	Topic            string
+	TopicConstraints TopicConstraintsMap
 }
 
 // SelectBroker takes a BrokerList and a ConstraintsParams and
 // selects the most suitable broker that passes all specified
-// constraints.
+// constraints. The BrokerList is sorted on each call; for repeated
+// selections from the same brokers, see SelectCandidate.
 func (c *Constraints) SelectBroker(b BrokerList, p ConstraintsParams) (*Broker, error) {
 	// Sort type based on the
 	// desired placement criteria.
@@ -48,15 +62,17 @@ func (c *Constraints) SelectBroker(b BrokerList, p ConstraintsParams) (*Broker,
 		// a dedicated Rand for this.
 		b.SortPseudoShuffle(p.SeedVal)
 	case "storage":
-		b.SortByStorage()
+		if p.CountWeight > 0 {
+			b.SortByStorageAndCount(p.CountWeight)
+		} else {
+			b.SortByStorage()
+		}
 	default:
 		return nil, ErrInvalidSelectionMethod
 	}
 
-	var candidate *Broker
-
 	// Iterate over candidates.
-	for _, candidate = range b.Filter(AllBrokersFn) {
+	for _, candidate := range b {
 		// Candidate passes, return.
 		if c.passesWithParams(candidate, p) {
 			c.requestSize = p.RequestSize
@@ -71,6 +87,26 @@ func (c *Constraints) SelectBroker(b BrokerList, p ConstraintsParams) (*Broker,
 	return nil, ErrNoBrokers
 }
 
+// SelectCandidate takes a *BrokerCandidates and a ConstraintsParams and
+// selects the first broker in the BrokerCandidates order that passes all
+// specified constraints. The selected broker is repositioned in the
+// BrokerCandidates; the ConstraintsParams SelectorMethod and SeedVal are
+// ignored in favor of those the BrokerCandidates were created with.
+func (c *Constraints) SelectCandidate(bc *BrokerCandidates, p ConstraintsParams) (*Broker, error) {
+	for _, candidate := range bc.List() {
+		if c.passesWithParams(candidate, p) {
+			c.requestSize = p.RequestSize
+			c.Add(candidate)
+			candidate.Used++
+			bc.Update(candidate)
+
+			return candidate, nil
+		}
+	}
+
+	return nil, ErrNoBrokers
+}
+
 // TODO deprecate.
 // BestCandidate takes a *Constraints, selection method and
 // pass / iteration number (for use as a seed value for
@@ -90,10 +126,8 @@ func (b BrokerList) BestCandidate(c *Constraints, by string, p int64) (*Broker,
 		return nil, ErrInvalidSelectionMethod
 	}
 
-	var candidate *Broker
-
 	// Iterate over candidates.
-	for _, candidate = range b.Filter(AllBrokersFn) {
+	for _, candidate := range b {
 		// Candidate passes, return.
 		if c.passes(candidate) {
 			c.Add(candidate)
@@ -154,6 +188,9 @@ func (c *Constraints) passes(b *Broker) bool {
 	// the existing replica set localities.
 	case c.locality[b.Locality]:
 		return false
+	// Fail if the candidate is in maintenance.
+	case b.Maintenance:
+		return false
 	// Fail if the candidate would run
 	// out of storage.
 	case b.StorageFree-c.requestSize < 0:
@@ -173,13 +210,27 @@ func (c *Constraints) passesWithParams(b *Broker, p ConstraintsParams) bool {
 	// Check the candidate against already used IDs.
 	case c.id[b.ID]:
 		return false
+	// Check the candidate against a required locality.
+	case p.RequiredLocality != "" && b.Locality != p.RequiredLocality:
+		return false
+	// Check the candidate against an excluded locality.
+	case p.ExcludedLocality != "" && b.Locality == p.ExcludedLocality:
+		return false
+	// Check the candidate against topic constraints.
+	case !p.TopicConstraints.Permits(p.Topic, b.ID):
+		return false
+	// Brokers in maintenance are never candidates.
+	case b.Maintenance:
+		return false
 	// Check the candidate against rack ID constraints
-	// where all rack IDs must be unique.
-	case c.locality[b.Locality] && p.MinUniqueRackIDs == 0:
+	// where all rack IDs must be unique. A required
+	// locality takes precedence over rack ID uniqueness
+	// (e.g. multiple observers may share a rack).
+	case c.locality[b.Locality] && p.MinUniqueRackIDs == 0 && p.RequiredLocality == "":
 		return false
 	// Check the candidate against rack ID constraints
 	// where a non-zero MinUniqueRackIDs is set.
-	case c.locality[b.Locality] && p.MinUniqueRackIDs > 0:
+	case c.locality[b.Locality] && p.MinUniqueRackIDs > 0 && p.RequiredLocality == "":
 		if !uniqueRackIDsSatisfied {
 			return false
 		}
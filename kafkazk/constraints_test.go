@@ -302,6 +302,14 @@ func TestConstraintsPassesWithParams(t *testing.T) {
 	if b := c.passesWithParams(b4, p); b != false {
 		t.Errorf("Expected broker b4 to fail constraints")
 	}
+
+	// Brokers in maintenance fail.
+	p.RequestSize = 0
+	b4.Maintenance = true
+
+	if b := c.passesWithParams(b4, p); b != false {
+		t.Errorf("Expected broker b4 in maintenance to fail constraints")
+	}
 }
 
 func TestMergeConstraints(t *testing.T) {
@@ -0,0 +1,74 @@
+package kafkazk
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// PlanRecord describes a generated partition map plan,
+// persisted in ZooKeeper for audit and rollback.
+type PlanRecord struct {
+	// The record ID; this is the znode name
+	// and is populated when read.
+	ID        string            `json:"-"`
+	Timestamp int64             `json:"timestamp"` // Unix seconds.
+	User      string            `json:"user"`
+	Command   string            `json:"command"`
+	Params    map[string]string `json:"params"`
+	// The original and planned assignments
+	// of all changed partitions.
+	Input  *PartitionMap `json:"input"`
+	Output *PartitionMap `json:"output"`
+}
+
+// PlanRecordID returns the ID for a PlanRecord
+// created at the time t. IDs sort by time.
+func PlanRecordID(t time.Time) string {
+	return t.UTC().Format("20060102T150405.000Z")
+}
+
+// WritePlanRecord takes a Handler, a ZooKeeper path and a *PlanRecord and
+// writes the record as a child of the path, named by the current time. The
+// record's ID is set accordingly. The path is created if it doesn't exist.
+// Records are stored gzip compressed.
+func WritePlanRecord(zk Handler, path string, r *PlanRecord) error {
+	now := time.Now()
+	r.ID = PlanRecordID(now)
+	if r.Timestamp == 0 {
+		r.Timestamp = now.Unix()
+	}
+
+	return createCompressedJSON(zk, fmt.Sprintf("%s/%s", path, r.ID), r)
+}
+
+// GetPlanRecordIDs takes a Handler and ZooKeeper path and returns
+// the IDs of all PlanRecords stored at the path, oldest first.
+func GetPlanRecordIDs(zk Handler, path string) ([]string, error) {
+	ids, err := zk.Children(path)
+	if err != nil {
+		switch err.(type) {
+		case ErrNoNode:
+			return []string{}, nil
+		default:
+			return nil, err
+		}
+	}
+
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// GetPlanRecord takes a Handler, ZooKeeper path and
+// record ID and returns the *PlanRecord.
+func GetPlanRecord(zk Handler, path, id string) (*PlanRecord, error) {
+	r := &PlanRecord{}
+	if err := getCompressedJSON(zk, fmt.Sprintf("%s/%s", path, id), r); err != nil {
+		return nil, err
+	}
+
+	r.ID = id
+
+	return r, nil
+}
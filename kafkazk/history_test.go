@@ -0,0 +1,123 @@
+package kafkazk
+
+import (
+	"strings"
+	"testing"
+)
+
+// memHandler is a Mock that stores
+// znodes in memory.
+type memHandler struct {
+	*Mock
+	nodes map[string]string
+}
+
+func newMemHandler() *memHandler {
+	return &memHandler{Mock: &Mock{}, nodes: map[string]string{}}
+}
+
+func (m *memHandler) Create(p, d string) error {
+	m.nodes[p] = d
+	return nil
+}
+
+func (m *memHandler) Exists(p string) (bool, error) {
+	_, exists := m.nodes[p]
+	return exists, nil
+}
+
+func (m *memHandler) Get(p string) ([]byte, error) {
+	d, exists := m.nodes[p]
+	if !exists {
+		return nil, ErrNoNode{s: p}
+	}
+	return []byte(d), nil
+}
+
+func (m *memHandler) Delete(p string) error {
+	if _, exists := m.nodes[p]; !exists {
+		return ErrNoNode{s: p}
+	}
+	delete(m.nodes, p)
+	return nil
+}
+
+func (m *memHandler) Children(p string) ([]string, error) {
+	if _, exists := m.nodes[p]; !exists {
+		return nil, ErrNoNode{s: p}
+	}
+
+	var c []string
+	for n := range m.nodes {
+		if strings.HasPrefix(n, p+"/") && !strings.Contains(n[len(p)+1:], "/") {
+			c = append(c, n[len(p)+1:])
+		}
+	}
+	return c, nil
+}
+
+func TestPlanRecords(t *testing.T) {
+	zk := newMemHandler()
+	path := "/topicmappr/history"
+
+	// No records.
+	ids, err := GetPlanRecordIDs(zk, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 0 {
+		t.Errorf("Expected 0 records, got %d", len(ids))
+	}
+
+	pm1, _ := PartitionMapFromString(testGetMapString("test_topic"))
+	pm2 := pm1.Copy()
+	pm2.Partitions[0].Replicas = []int{1003, 1002}
+
+	r := &PlanRecord{
+		User:    "user",
+		Command: "rebuild",
+		Params:  map[string]string{"brokers": "1002,1003"},
+		Input:   pm1,
+		Output:  pm2,
+	}
+
+	if err := WritePlanRecord(zk, path, r); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.ID == "" || r.Timestamp == 0 {
+		t.Errorf("Expected ID and timestamp to be set")
+	}
+
+	// Parent znodes should be created.
+	if _, exists := zk.nodes["/topicmappr"]; !exists {
+		t.Error("Expected /topicmappr to be created")
+	}
+
+	ids, _ = GetPlanRecordIDs(zk, path)
+	if len(ids) != 1 || ids[0] != r.ID {
+		t.Fatalf("Unexpected record IDs: %v", ids)
+	}
+
+	r2, err := GetPlanRecord(zk, path, ids[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r2.ID != r.ID || r2.User != "user" || r2.Command != "rebuild" || r2.Params["brokers"] != "1002,1003" {
+		t.Errorf("Unexpected record: %+v", r2)
+	}
+
+	if eq, _ := r2.Input.equal(pm1); !eq {
+		t.Error("Unexpected record input map")
+	}
+
+	if eq, _ := r2.Output.equal(pm2); !eq {
+		t.Error("Unexpected record output map")
+	}
+
+	if _, err := GetPlanRecord(zk, path, "nonexistent"); err == nil {
+		t.Error("Expected error for nonexistent record")
+	}
+}
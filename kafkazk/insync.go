@@ -0,0 +1,119 @@
+package kafkazk
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// OutOfSyncPartition describes a partition where one or more
+// assigned replicas are not currently in the ISR.
+type OutOfSyncPartition struct {
+	Topic     string
+	Partition int
+	Replicas  []int
+	ISR       []int
+	// Replicas that are assigned but not in the ISR.
+	Lagging []int
+	// Replicas that are assigned but whose broker
+	// isn't registered in ZooKeeper.
+	Offline []int
+}
+
+// String returns a summary of the out of sync replicas.
+func (o OutOfSyncPartition) String() string {
+	s := fmt.Sprintf("%s p%d: replicas %v, isr %v", o.Topic, o.Partition, o.Replicas, o.ISR)
+	if len(o.Offline) > 0 {
+		s += fmt.Sprintf(", offline %v", o.Offline)
+	}
+
+	return s
+}
+
+// OutOfSyncPartitions is a list of OutOfSyncPartition.
+type OutOfSyncPartitions []OutOfSyncPartition
+
+// Contains returns whether the Partition p is
+// in the OutOfSyncPartitions.
+func (o OutOfSyncPartitions) Contains(p Partition) bool {
+	for _, partn := range o {
+		if partn.Topic == p.Topic && partn.Partition == p.Partition {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OutOfSync takes a Handler and BrokerMetaMap and returns an OutOfSyncPartitions
+// of all partitions in the PartitionMap where assigned replicas are missing
+// from the ISR. Any replicas for brokers not found in the BrokerMetaMap are
+// additionally marked as offline. If the BrokerMetaMap is empty, the offline
+// check is skipped.
+func (pm *PartitionMap) OutOfSync(zk Handler, bm BrokerMetaMap) (OutOfSyncPartitions, error) {
+	var oos OutOfSyncPartitions
+
+	// Fetch the ISR state once per topic.
+	states := map[string]TopicStateISR{}
+
+	for _, partn := range pm.Partitions {
+		if _, fetched := states[partn.Topic]; !fetched {
+			s, err := zk.GetTopicStateISR(partn.Topic)
+			if err != nil {
+				return nil, err
+			}
+			states[partn.Topic] = s
+		}
+
+		state, exists := states[partn.Topic][strconv.Itoa(partn.Partition)]
+		if !exists {
+			return nil, fmt.Errorf("%s p%d: partition state not found", partn.Topic, partn.Partition)
+		}
+
+		isr := map[int]struct{}{}
+		for _, id := range state.ISR {
+			isr[id] = struct{}{}
+		}
+
+		o := OutOfSyncPartition{
+			Topic:     partn.Topic,
+			Partition: partn.Partition,
+			Replicas:  partn.Replicas,
+			ISR:       state.ISR,
+		}
+
+		for _, id := range partn.Replicas {
+			if _, ok := isr[id]; !ok {
+				o.Lagging = append(o.Lagging, id)
+			}
+
+			if len(bm) > 0 {
+				if _, registered := bm[id]; !registered {
+					o.Offline = append(o.Offline, id)
+				}
+			}
+		}
+
+		if len(o.Lagging) > 0 || len(o.Offline) > 0 {
+			sort.Ints(o.Lagging)
+			sort.Ints(o.Offline)
+			oos = append(oos, o)
+		}
+	}
+
+	return oos, nil
+}
+
+// Exclude returns a copy of the PartitionMap with all
+// partitions in the OutOfSyncPartitions removed.
+func (pm *PartitionMap) Exclude(o OutOfSyncPartitions) *PartitionMap {
+	out := NewPartitionMap()
+
+	for _, partn := range pm.Copy().Partitions {
+		if !o.Contains(partn) {
+			out.Partitions = append(out.Partitions, partn)
+		}
+	}
+
+	return out
+}
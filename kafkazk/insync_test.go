@@ -0,0 +1,78 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestOutOfSync(t *testing.T) {
+	zk := &Mock{}
+
+	pm, _ := PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test_topic","partition":0,"replicas":[1000,1002]},
+    {"topic":"test_topic","partition":1,"replicas":[1002,1001]},
+    {"topic":"test_topic","partition":2,"replicas":[1004,1005]}]}`)
+
+	bm, _ := zk.GetAllBrokerMeta(false)
+
+	oos, err := pm.OutOfSync(zk, bm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// p0 is in sync but 1000 isn't registered,
+	// p1 has 1001 lagging, p2 is fully in sync.
+	if len(oos) != 2 {
+		t.Fatalf("Expected 2 out of sync partitions, got %d", len(oos))
+	}
+
+	if oos[0].Partition != 0 || len(oos[0].Lagging) != 0 {
+		t.Errorf("Unexpected out of sync state for p0: %v", oos[0])
+	}
+
+	if len(oos[0].Offline) != 1 || oos[0].Offline[0] != 1000 {
+		t.Errorf("Expected offline replica 1000 for p0, got %v", oos[0].Offline)
+	}
+
+	if oos[1].Partition != 1 || len(oos[1].Lagging) != 1 || oos[1].Lagging[0] != 1001 {
+		t.Errorf("Unexpected out of sync state for p1: %v", oos[1])
+	}
+
+	// Without broker metadata, only ISR
+	// membership is considered.
+	oos, _ = pm.OutOfSync(zk, BrokerMetaMap{})
+	if len(oos) != 1 || oos[0].Partition != 1 {
+		t.Errorf("Unexpected out of sync partitions: %v", oos)
+	}
+
+	// Partitions without state should error.
+	pm.Partitions = append(pm.Partitions, Partition{Topic: "test_topic", Partition: 10, Replicas: []int{1001}})
+	if _, err := pm.OutOfSync(zk, bm); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestExclude(t *testing.T) {
+	pm, _ := PartitionMapFromString(testGetMapString("test_topic"))
+
+	oos := OutOfSyncPartitions{
+		OutOfSyncPartition{Topic: "test_topic", Partition: 1},
+		OutOfSyncPartition{Topic: "test_topic", Partition: 3},
+	}
+
+	out := pm.Exclude(oos)
+
+	if len(out.Partitions) != 2 {
+		t.Fatalf("Expected 2 partitions, got %d", len(out.Partitions))
+	}
+
+	for i, p := range []int{0, 2} {
+		if out.Partitions[i].Partition != p {
+			t.Errorf("Expected partition %d, got %d", p, out.Partitions[i].Partition)
+		}
+	}
+
+	// The input map should be unmodified.
+	if len(pm.Partitions) != 4 {
+		t.Error("Unexpected modification of input map")
+	}
+}
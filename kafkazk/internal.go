@@ -0,0 +1,56 @@
+package kafkazk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MinInternalReplication is the minimum replication
+// factor expected for internal topics; this matches the
+// Kafka defaults for offsets.topic.replication.factor and
+// transaction.state.log.replication.factor.
+const MinInternalReplication = 3
+
+// IsInternalTopic returns whether the topic t is a Kafka
+// internal topic, e.g. __consumer_offsets or __transaction_state.
+func IsInternalTopic(t string) bool {
+	return strings.HasPrefix(t, "__")
+}
+
+// CheckInternalTopics takes a BrokerMap and returns an error for each
+// internal topic partition in the PartitionMap that has a replication
+// factor below MinInternalReplication or that has more than one replica
+// in the same rack. Rack spread is only checked for brokers with a known
+// locality.
+func (pm *PartitionMap) CheckInternalTopics(bm BrokerMap) []error {
+	var errs []error
+
+	for _, partn := range pm.Partitions {
+		if !IsInternalTopic(partn.Topic) {
+			continue
+		}
+
+		if len(partn.Replicas) < MinInternalReplication {
+			errs = append(errs, fmt.Errorf("%s p%d: internal topic replication factor of %d is below %d",
+				partn.Topic, partn.Partition, len(partn.Replicas), MinInternalReplication))
+		}
+
+		racks := map[string]struct{}{}
+		for _, id := range partn.Replicas {
+			b, exists := bm[id]
+			if !exists || b.Locality == "" {
+				continue
+			}
+
+			if _, used := racks[b.Locality]; used {
+				errs = append(errs, fmt.Errorf("%s p%d: internal topic has multiple replicas in rack %s",
+					partn.Topic, partn.Partition, b.Locality))
+				break
+			}
+
+			racks[b.Locality] = struct{}{}
+		}
+	}
+
+	return errs
+}
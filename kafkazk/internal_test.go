@@ -0,0 +1,37 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestIsInternalTopic(t *testing.T) {
+	expected := map[string]bool{
+		"__consumer_offsets":  true,
+		"__transaction_state": true,
+		"test_topic":          false,
+		"_test_topic":         false,
+	}
+
+	for topic, internal := range expected {
+		if IsInternalTopic(topic) != internal {
+			t.Errorf("Expected IsInternalTopic(%s) to be %v", topic, internal)
+		}
+	}
+}
+
+func TestCheckInternalTopics(t *testing.T) {
+	pm, _ := PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"__consumer_offsets","partition":0,"replicas":[1001,1002,1003]},
+    {"topic":"__consumer_offsets","partition":1,"replicas":[1001,1002]},
+    {"topic":"__consumer_offsets","partition":2,"replicas":[1001,1004,1002]},
+    {"topic":"test_topic","partition":0,"replicas":[1001]}]}`)
+
+	bm := newMockBrokerMap()
+
+	// p1 has a replication factor of 2 and p2 has
+	// 1001 and 1004 in rack a. test_topic is ignored.
+	errs := pm.CheckInternalTopics(bm)
+	if len(errs) != 2 {
+		t.Errorf("Expected 2 errors, got %d: %s", len(errs), errs)
+	}
+}
@@ -0,0 +1,108 @@
+package kafkazk
+
+import (
+	"fmt"
+)
+
+// LeaderPin pins the leader of a partition to
+// either a specific broker or any broker in a rack.
+type LeaderPin struct {
+	Broker int    `json:"broker,omitempty"`
+	Rack   string `json:"rack,omitempty"`
+}
+
+func (p LeaderPin) String() string {
+	if p.Broker != 0 {
+		return fmt.Sprintf("broker %d", p.Broker)
+	}
+
+	return fmt.Sprintf("rack %s", p.Rack)
+}
+
+// satisfiedBy returns whether the broker satisfies the LeaderPin.
+func (p LeaderPin) satisfiedBy(b *Broker) bool {
+	if b == nil || b.ID == StubBrokerID {
+		return false
+	}
+
+	if p.Broker != 0 {
+		return b.ID == p.Broker
+	}
+
+	return b.Locality == p.Rack
+}
+
+// LeaderPins is a mapping of topic names to
+// partition numbers to LeaderPin.
+type LeaderPins map[string]map[int]LeaderPin
+
+// Pin returns the LeaderPin for the partition
+// and whether the partition is pinned.
+func (lp LeaderPins) Pin(p Partition) (LeaderPin, bool) {
+	pin, exists := lp[p.Topic][p.Partition]
+	return pin, exists
+}
+
+// Required takes a Partition, broker ID and BrokerMap and returns
+// whether the replica on the broker is the only replica of the
+// partition satisfying its LeaderPin.
+func (lp LeaderPins) Required(p Partition, id int, bm BrokerMap) bool {
+	pin, pinned := lp.Pin(p)
+	if !pinned || !pin.satisfiedBy(bm[id]) {
+		return false
+	}
+
+	for _, r := range p.Replicas {
+		if r != id && pin.satisfiedBy(bm[r]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Apply reorders the replica sets of all pinned partitions in the
+// PartitionMap so that the first replica satisfying the LeaderPin
+// leads. The order of the remaining replicas is retained. Partitions
+// where no replica satisfies the pin are left unchanged (see Check).
+func (lp LeaderPins) Apply(pm *PartitionMap, bm BrokerMap) {
+	for n, partn := range pm.Partitions {
+		pin, pinned := lp.Pin(partn)
+		if !pinned {
+			continue
+		}
+
+		for i, id := range partn.Replicas {
+			if !pin.satisfiedBy(bm[id]) {
+				continue
+			}
+
+			// Shift the preceding replicas down.
+			replicas := pm.Partitions[n].Replicas
+			copy(replicas[1:i+1], replicas[:i])
+			replicas[0] = id
+
+			break
+		}
+	}
+}
+
+// Check takes a PartitionMap and BrokerMap and returns an error for
+// each pinned partition with a leader not satisfying its LeaderPin.
+func (lp LeaderPins) Check(pm *PartitionMap, bm BrokerMap) []error {
+	var errs []error
+
+	for _, partn := range pm.Partitions {
+		pin, pinned := lp.Pin(partn)
+		if !pinned || len(partn.Replicas) == 0 {
+			continue
+		}
+
+		if leader := partn.Replicas[0]; !pin.satisfiedBy(bm[leader]) {
+			errs = append(errs, fmt.Errorf("%s p%d: leader %d does not satisfy pinned %s",
+				partn.Topic, partn.Partition, leader, pin))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,116 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestLeaderPinsApply(t *testing.T) {
+	pm, _ := PartitionMapFromString(testGetMapString("test_topic"))
+	bm := newMockBrokerMap()
+
+	lp := LeaderPins{
+		"test_topic": map[int]LeaderPin{
+			// 1002 follows.
+			0: LeaderPin{Broker: 1002},
+			// 1001 is the only replica in rack a.
+			1: LeaderPin{Rack: "a"},
+			// Already led from rack c.
+			2: LeaderPin{Rack: "c"},
+			// No replica in the rack.
+			3: LeaderPin{Broker: 1001},
+		},
+	}
+
+	lp.Apply(pm, bm)
+
+	expected := [][]int{
+		{1002, 1001},
+		{1001, 1002},
+		{1003, 1004, 1001},
+		{1004, 1003, 1002},
+	}
+
+	for i, p := range pm.Partitions {
+		if !p.Equal(Partition{Topic: p.Topic, Partition: p.Partition, Replicas: expected[i]}) {
+			t.Errorf("p%d: expected replicas %v, got %v", p.Partition, expected[i], p.Replicas)
+		}
+	}
+
+	// Only p3 can't be satisfied.
+	errs := lp.Check(pm, bm)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d", len(errs))
+	}
+
+	if e := "test_topic p3: leader 1004 does not satisfy pinned broker 1001"; errs[0].Error() != e {
+		t.Errorf("Expected error '%s', got '%s'", e, errs[0])
+	}
+}
+
+func TestLeaderPinsRequired(t *testing.T) {
+	bm := newMockBrokerMap()
+	p := Partition{Topic: "test_topic", Partition: 0, Replicas: []int{1001, 1002, 1004}}
+
+	lp := LeaderPins{"test_topic": map[int]LeaderPin{0: LeaderPin{Broker: 1002}}}
+
+	if !lp.Required(p, 1002, bm) {
+		t.Error("Expected pinned broker 1002 to be required")
+	}
+
+	if lp.Required(p, 1001, bm) {
+		t.Error("Unexpected required broker 1001")
+	}
+
+	// 1001 and 1004 are both in rack a.
+	lp = LeaderPins{"test_topic": map[int]LeaderPin{0: LeaderPin{Rack: "a"}}}
+
+	if lp.Required(p, 1001, bm) {
+		t.Error("Unexpected required broker 1001")
+	}
+
+	p.Replicas = []int{1001, 1002}
+	if !lp.Required(p, 1001, bm) {
+		t.Error("Expected broker 1001 to be required")
+	}
+
+	// Unpinned partitions.
+	p.Partition = 1
+	if lp.Required(p, 1001, bm) {
+		t.Error("Unexpected required broker for unpinned partition")
+	}
+}
+
+func TestRebuildWithLeaderPins(t *testing.T) {
+	pm, _ := PartitionMapFromString(testGetMapString("test_topic"))
+	bm := newMockBrokerMap()
+
+	// Replace 1001; p0 and p1 lose their
+	// only replica in rack a.
+	bm[1001].Replace = true
+
+	lp := LeaderPins{
+		"test_topic": map[int]LeaderPin{
+			0: LeaderPin{Rack: "a"},
+			1: LeaderPin{Rack: "a"},
+		},
+	}
+
+	rebuildParams := RebuildParams{
+		PMM:          NewPartitionMetaMap(),
+		BM:           bm,
+		Strategy:     "count",
+		Optimization: "distribution",
+		LeaderPins:   lp,
+	}
+
+	out, errs := pm.Rebuild(rebuildParams)
+	if errs != nil {
+		t.Fatalf("Unexpected error(s): %s", errs)
+	}
+
+	lp.Apply(out, bm)
+
+	if errs := lp.Check(out, bm); len(errs) != 0 {
+		t.Errorf("Unexpected error(s): %s", errs)
+	}
+}
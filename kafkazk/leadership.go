@@ -0,0 +1,203 @@
+package kafkazk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	zkclient "github.com/samuel/go-zookeeper/zk"
+)
+
+// ErrElectionInProgress error.
+var ErrElectionInProgress = errors.New("A preferred replica election is already in progress")
+
+// PartitionLeadership describes the current and
+// preferred leader of a partition.
+type PartitionLeadership struct {
+	Topic     string
+	Partition int
+	// The preferred leader is the
+	// first assigned replica.
+	Preferred int
+	// The current leader; -1 if none.
+	Leader int
+	// Whether the preferred leader is in the
+	// ISR and therefore eligible for election.
+	Eligible bool
+}
+
+// IsPreferred returns whether the partition
+// is led by its preferred leader.
+func (p PartitionLeadership) IsPreferred() bool {
+	return p.Leader == p.Preferred
+}
+
+// PartitionLeaderships is a list of PartitionLeadership.
+type PartitionLeaderships []PartitionLeadership
+
+// Leadership takes a Handler and returns the PartitionLeaderships of all
+// partitions in the PartitionMap, using the ISR states for current leaders.
+func (pm *PartitionMap) Leadership(zk Handler) (PartitionLeaderships, error) {
+	var pl PartitionLeaderships
+
+	// Fetch the ISR state once per topic.
+	states := map[string]TopicStateISR{}
+
+	for _, partn := range pm.Partitions {
+		if len(partn.Replicas) == 0 {
+			continue
+		}
+
+		if _, fetched := states[partn.Topic]; !fetched {
+			s, err := zk.GetTopicStateISR(partn.Topic)
+			if err != nil {
+				return nil, err
+			}
+			states[partn.Topic] = s
+		}
+
+		state, exists := states[partn.Topic][strconv.Itoa(partn.Partition)]
+		if !exists {
+			return nil, fmt.Errorf("%s p%d: partition state not found", partn.Topic, partn.Partition)
+		}
+
+		l := PartitionLeadership{
+			Topic:     partn.Topic,
+			Partition: partn.Partition,
+			Preferred: partn.Replicas[0],
+			Leader:    state.Leader,
+		}
+
+		for _, id := range state.ISR {
+			if id == l.Preferred {
+				l.Eligible = true
+				break
+			}
+		}
+
+		pl = append(pl, l)
+	}
+
+	return pl, nil
+}
+
+// Imbalanced returns the PartitionLeaderships
+// not led by their preferred leader.
+func (pl PartitionLeaderships) Imbalanced() PartitionLeaderships {
+	var out PartitionLeaderships
+
+	for _, l := range pl {
+		if !l.IsPreferred() {
+			out = append(out, l)
+		}
+	}
+
+	return out
+}
+
+// BrokerLeadership holds the number of partitions
+// currently led by a broker along with the number
+// it's the preferred leader for.
+type BrokerLeadership struct {
+	ID        int
+	Leader    int
+	Preferred int
+	// The number of partitions led following an
+	// election of all eligible preferred leaders.
+	Elected int
+}
+
+// BrokerLeadership returns the BrokerLeadership
+// of all referenced brokers, sorted by ID.
+func (pl PartitionLeaderships) BrokerLeadership() []BrokerLeadership {
+	bl := map[int]*BrokerLeadership{}
+
+	get := func(id int) *BrokerLeadership {
+		if _, exists := bl[id]; !exists {
+			bl[id] = &BrokerLeadership{ID: id}
+		}
+		return bl[id]
+	}
+
+	for _, l := range pl {
+		get(l.Preferred).Preferred++
+
+		if l.Leader >= 0 {
+			get(l.Leader).Leader++
+		}
+
+		switch {
+		case l.Eligible:
+			get(l.Preferred).Elected++
+		case l.Leader >= 0:
+			get(l.Leader).Elected++
+		}
+	}
+
+	var out []BrokerLeadership
+	for _, b := range bl {
+		out = append(out, *b)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+
+	return out
+}
+
+// PreferredReplicaElection holds the partitions for a preferred leader
+// election. It's serialized in the format of the /admin/preferred_replica_election
+// znode, which is also accepted as the --path-to-json-file input of the
+// kafka-leader-election.sh tool.
+type PreferredReplicaElection struct {
+	Version    int                 `json:"version"`
+	Partitions []ElectionPartition `json:"partitions"`
+}
+
+// ElectionPartition identifies a partition in
+// a PreferredReplicaElection.
+type ElectionPartition struct {
+	Topic     string `json:"topic"`
+	Partition int    `json:"partition"`
+}
+
+// Election returns a PreferredReplicaElection for all partitions not led by
+// their preferred leader where the preferred leader is eligible for election.
+func (pl PartitionLeaderships) Election() PreferredReplicaElection {
+	e := PreferredReplicaElection{Version: 1, Partitions: []ElectionPartition{}}
+
+	for _, l := range pl {
+		if !l.IsPreferred() && l.Eligible {
+			e.Partitions = append(e.Partitions, ElectionPartition{Topic: l.Topic, Partition: l.Partition})
+		}
+	}
+
+	return e
+}
+
+// ElectPreferredLeaders takes a PreferredReplicaElection and triggers the
+// election by creating the /admin/preferred_replica_election znode. An
+// ErrElectionInProgress is returned if an election is already in progress.
+func (z *ZKHandler) ElectPreferredLeaders(e PreferredReplicaElection) error {
+	var path string
+	if z.Prefix != "" {
+		path = fmt.Sprintf("/%s/admin/preferred_replica_election", z.Prefix)
+	} else {
+		path = "/admin/preferred_replica_election"
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if _, err := z.client.Create(path, data, 0, zkclient.WorldACL(31)); err != nil {
+		if err == zkclient.ErrNodeExists {
+			return ErrElectionInProgress
+		}
+		return fmt.Errorf("[%s] %s", path, err.Error())
+	}
+
+	return nil
+}
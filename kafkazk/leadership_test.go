@@ -0,0 +1,78 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestLeadership(t *testing.T) {
+	zk := &Mock{}
+
+	pm, _ := PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test_topic","partition":0,"replicas":[1002,1000]},
+    {"topic":"test_topic","partition":1,"replicas":[1003,1002]},
+    {"topic":"test_topic","partition":2,"replicas":[1004,1005]},
+    {"topic":"test_topic","partition":3,"replicas":[1001,1006]}]}`)
+
+	pl, err := pm.Leadership(zk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pl) != 4 {
+		t.Fatalf("Expected 4 partition leaderships, got %d", len(pl))
+	}
+
+	// p0 and p1 are led by non-preferred replicas,
+	// p3's preferred leader isn't in the ISR.
+	imbalanced := pl.Imbalanced()
+	if len(imbalanced) != 3 {
+		t.Fatalf("Expected 3 imbalanced partitions, got %d", len(imbalanced))
+	}
+
+	if imbalanced[2].Partition != 3 || imbalanced[2].Eligible {
+		t.Errorf("Expected ineligible p3, got %v", imbalanced[2])
+	}
+
+	e := pl.Election()
+	if len(e.Partitions) != 2 {
+		t.Fatalf("Expected 2 election partitions, got %d", len(e.Partitions))
+	}
+
+	for i, p := range []int{0, 1} {
+		if e.Partitions[i].Topic != "test_topic" || e.Partitions[i].Partition != p {
+			t.Errorf("Unexpected election partition %v", e.Partitions[i])
+		}
+	}
+
+	// Partitions without state should error.
+	pm.Partitions[0].Partition = 10
+	if _, err := pm.Leadership(zk); err == nil {
+		t.Error("Expected partition state not found error")
+	}
+}
+
+func TestBrokerLeadership(t *testing.T) {
+	pl := PartitionLeaderships{
+		{Topic: "a", Partition: 0, Preferred: 1001, Leader: 1002, Eligible: true},
+		{Topic: "a", Partition: 1, Preferred: 1001, Leader: 1002, Eligible: false},
+		{Topic: "a", Partition: 2, Preferred: 1003, Leader: 1003, Eligible: true},
+		{Topic: "a", Partition: 3, Preferred: 1003, Leader: -1, Eligible: false},
+	}
+
+	expected := []BrokerLeadership{
+		{ID: 1001, Leader: 0, Preferred: 2, Elected: 1},
+		{ID: 1002, Leader: 2, Preferred: 0, Elected: 1},
+		{ID: 1003, Leader: 1, Preferred: 2, Elected: 1},
+	}
+
+	bl := pl.BrokerLeadership()
+	if len(bl) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, bl)
+	}
+
+	for i := range bl {
+		if bl[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], bl[i])
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package kafkazk
+
+import (
+	"fmt"
+)
+
+// CheckConsumerLocality takes a BrokerMap and a mapping of topic names
+// to consumer racks and returns an error for each partition of a mapped
+// topic that has no replica in the consumer rack.
+func (pm *PartitionMap) CheckConsumerLocality(bm BrokerMap, racks map[string]string) []error {
+	var errs []error
+
+	for _, partn := range pm.Partitions {
+		rack, exists := racks[partn.Topic]
+		if !exists || rack == "" {
+			continue
+		}
+
+		var local bool
+		for _, id := range partn.Replicas {
+			if b, exists := bm[id]; exists && b.Locality == rack {
+				local = true
+				break
+			}
+		}
+
+		if !local {
+			errs = append(errs, fmt.Errorf("%s p%d: no replica in consumer rack %s",
+				partn.Topic, partn.Partition, rack))
+		}
+	}
+
+	return errs
+}
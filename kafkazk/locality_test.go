@@ -0,0 +1,47 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestRebuildWithConsumerRacks(t *testing.T) {
+	zk := &Mock{}
+	bm, _ := zk.GetAllBrokerMeta(false)
+	pm, _ := PartitionMapFromString(testGetMapString("test_topic"))
+	brokers := BrokerMapFromPartitionMap(pm, bm, true)
+	racks := map[string]string{"test_topic": "c"}
+
+	rebuildParams := RebuildParams{
+		PMM:           NewPartitionMetaMap(),
+		BM:            brokers,
+		Strategy:      "count",
+		Optimization:  "distribution",
+		ConsumerRacks: racks,
+	}
+
+	out, errs := pm.Strip().Rebuild(rebuildParams)
+	if errs != nil {
+		t.Fatalf("Unexpected error(s): %s", errs)
+	}
+
+	if errs := out.CheckConsumerLocality(brokers, racks); len(errs) != 0 {
+		t.Errorf("Unexpected error(s): %s", errs)
+	}
+}
+
+func TestCheckConsumerLocality(t *testing.T) {
+	pm, _ := PartitionMapFromString(testGetMapString("test_topic"))
+	bm := newMockBrokerMap()
+
+	// Only p2 and p3 have a replica (1003) in rack c.
+	errs := pm.CheckConsumerLocality(bm, map[string]string{"test_topic": "c"})
+	if len(errs) != 2 {
+		t.Errorf("Expected 2 errors, got %d", len(errs))
+	}
+
+	// Unmapped topics aren't checked.
+	errs = pm.CheckConsumerLocality(bm, map[string]string{"other_topic": "c"})
+	if len(errs) != 0 {
+		t.Errorf("Unexpected error(s): %s", errs)
+	}
+}
@@ -0,0 +1,130 @@
+package kafkazk
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// MinISRViolation describes a partition where a planned
+// reassignment could leave fewer in-sync replicas than
+// the topic's min.insync.replicas.
+type MinISRViolation struct {
+	Topic     string
+	Partition int
+	MinISR    int
+	// The replication factor after the reassignment.
+	Replicas int
+	// Replicas currently in the ISR that are
+	// retained after the reassignment.
+	InSync int
+}
+
+// String returns a summary of the violation.
+func (v MinISRViolation) String() string {
+	if v.Replicas < v.MinISR {
+		return fmt.Sprintf("%s p%d: replication factor of %d is below min.insync.replicas=%d",
+			v.Topic, v.Partition, v.Replicas, v.MinISR)
+	}
+
+	return fmt.Sprintf("%s p%d: %d in-sync replica(s) retained, below min.insync.replicas=%d",
+		v.Topic, v.Partition, v.InSync, v.MinISR)
+}
+
+// MinISR takes a topic name and a default value and returns the topic's
+// min.insync.replicas config. The default is returned if the topic has
+// no override, which should be set to the broker min.insync.replicas.
+func MinISR(zk Handler, t string, def int) (int, error) {
+	c, err := zk.GetTopicConfig(t)
+	if err != nil {
+		switch err.(type) {
+		case ErrNoNode:
+			return def, nil
+		default:
+			return 0, err
+		}
+	}
+
+	v, exists := c.Config["min.insync.replicas"]
+	if !exists {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("Error parsing min.insync.replicas for %s: %s", t, err)
+	}
+
+	return n, nil
+}
+
+// MinISRViolations takes an output PartitionMap pm2, a Handler and a default
+// min.insync.replicas value. For each partition that's changed from pm to pm2,
+// the min.insync.replicas is compared against both the output replication
+// factor and the number of replicas in the current ISR that are retained in
+// the output replica set. The latter represents the in-sync replicas that
+// remain if any newly assigned replicas have yet to catch up when old
+// replicas are removed. A MinISRViolation is returned for each partition
+// falling below the threshold.
+func (pm *PartitionMap) MinISRViolations(pm2 *PartitionMap, zk Handler, def int) ([]MinISRViolation, error) {
+	var violations []MinISRViolation
+
+	// Index the output map.
+	out := map[string]map[int]Partition{}
+	for _, p := range pm2.Partitions {
+		if _, exists := out[p.Topic]; !exists {
+			out[p.Topic] = map[int]Partition{}
+		}
+		out[p.Topic][p.Partition] = p
+	}
+
+	minISR := map[string]int{}
+	states := map[string]TopicStateISR{}
+
+	for _, p1 := range pm.Partitions {
+		p2, exists := out[p1.Topic][p1.Partition]
+		if !exists || p1.Equal(p2) {
+			continue
+		}
+
+		// Fetch configs and states once per topic.
+		if _, fetched := states[p1.Topic]; !fetched {
+			m, err := MinISR(zk, p1.Topic, def)
+			if err != nil {
+				return nil, err
+			}
+			minISR[p1.Topic] = m
+
+			s, err := zk.GetTopicStateISR(p1.Topic)
+			if err != nil {
+				return nil, err
+			}
+			states[p1.Topic] = s
+		}
+
+		isr := map[int]struct{}{}
+		for _, id := range states[p1.Topic][strconv.Itoa(p1.Partition)].ISR {
+			isr[id] = struct{}{}
+		}
+
+		var inSync int
+		for _, id := range p2.Replicas {
+			if _, ok := isr[id]; ok {
+				inSync++
+			}
+		}
+
+		v := MinISRViolation{
+			Topic:     p1.Topic,
+			Partition: p1.Partition,
+			MinISR:    minISR[p1.Topic],
+			Replicas:  len(p2.Replicas),
+			InSync:    inSync,
+		}
+
+		if v.Replicas < v.MinISR || v.InSync < v.MinISR {
+			violations = append(violations, v)
+		}
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,58 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestMinISR(t *testing.T) {
+	zk := &Mock{}
+
+	// The mock config has no min.insync.replicas
+	// override; the default should be returned.
+	m, err := MinISR(zk, "test_topic", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m != 2 {
+		t.Errorf("Expected min.insync.replicas 2, got %d", m)
+	}
+}
+
+func TestMinISRViolations(t *testing.T) {
+	zk := &Mock{}
+
+	pm, _ := PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test_topic","partition":0,"replicas":[1000,1002]},
+    {"topic":"test_topic","partition":1,"replicas":[1002,1003]},
+    {"topic":"test_topic","partition":2,"replicas":[1004,1005]}]}`)
+
+	pm2, _ := PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test_topic","partition":0,"replicas":[1000,1002]},
+    {"topic":"test_topic","partition":1,"replicas":[1002,1010]},
+    {"topic":"test_topic","partition":2,"replicas":[1004]}]}`)
+
+	v, err := pm.MinISRViolations(pm2, zk, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// p0 is unchanged, p1 retains a single in-sync
+	// replica and p2 is reduced to a single replica.
+	if len(v) != 2 {
+		t.Fatalf("Expected 2 violations, got %d", len(v))
+	}
+
+	if v[0].Partition != 1 || v[0].InSync != 1 {
+		t.Errorf("Unexpected violation for p1: %s", v[0])
+	}
+
+	if v[1].Partition != 2 || v[1].Replicas != 1 {
+		t.Errorf("Unexpected violation for p2: %s", v[1])
+	}
+
+	v, _ = pm.MinISRViolations(pm2, zk, 1)
+	if len(v) != 0 {
+		t.Errorf("Unexpected violations: %v", v)
+	}
+}
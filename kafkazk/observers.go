@@ -0,0 +1,51 @@
+package kafkazk
+
+import (
+	"fmt"
+)
+
+// CheckObservers takes a BrokerMap, an observer count n and an optional
+// observer rack and returns an error for each partition where the replica
+// set can't hold n observers along with a leader, or where an observer
+// (one of the last n replicas) isn't in the observer rack or a non-observer
+// is. Rack placement is only checked if the rack is non-empty.
+func (pm *PartitionMap) CheckObservers(bm BrokerMap, n int, rack string) []error {
+	var errs []error
+
+	if n == 0 {
+		return errs
+	}
+
+	for _, partn := range pm.Partitions {
+		rf := len(partn.Replicas)
+		if rf <= n {
+			errs = append(errs, fmt.Errorf("%s p%d: replication factor %d is too low for %d observer(s)",
+				partn.Topic, partn.Partition, rf, n))
+			continue
+		}
+
+		if rack == "" {
+			continue
+		}
+
+		for pos, id := range partn.Replicas {
+			b, exists := bm[id]
+			if !exists {
+				continue
+			}
+
+			observer := pos >= rf-n
+
+			switch {
+			case observer && b.Locality != rack:
+				errs = append(errs, fmt.Errorf("%s p%d: observer %d not in rack %s",
+					partn.Topic, partn.Partition, id, rack))
+			case !observer && b.Locality == rack:
+				errs = append(errs, fmt.Errorf("%s p%d: non-observer %d in observer rack %s",
+					partn.Topic, partn.Partition, id, rack))
+			}
+		}
+	}
+
+	return errs
+}
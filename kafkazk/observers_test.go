@@ -0,0 +1,71 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestRebuildWithObservers(t *testing.T) {
+	zk := &Mock{}
+	bm, _ := zk.GetAllBrokerMeta(false)
+	pm, _ := PartitionMapFromString(testGetMapString("test_topic"))
+	brokers := BrokerMapFromPartitionMap(pm, bm, true)
+
+	rebuildParams := RebuildParams{
+		PMM:          NewPartitionMetaMap(),
+		BM:           brokers,
+		Strategy:     "count",
+		Optimization: "distribution",
+		Observers:    1,
+		ObserverRack: "c",
+	}
+
+	out, errs := pm.Strip().Rebuild(rebuildParams)
+	if errs != nil {
+		t.Fatalf("Unexpected error(s): %s", errs)
+	}
+
+	// 1003 is the only broker in rack c.
+	for _, partn := range out.Partitions {
+		if last := partn.Replicas[len(partn.Replicas)-1]; last != 1003 {
+			t.Errorf("%s p%d: expected observer 1003, got %d", partn.Topic, partn.Partition, last)
+		}
+	}
+
+	if errs := out.CheckObservers(brokers, 1, "c"); len(errs) != 0 {
+		t.Errorf("Unexpected error(s): %s", errs)
+	}
+
+	// Leadership optimization shouldn't
+	// move the observers.
+	out.OptimizeLeaderFollowerObservers(1)
+
+	if errs := out.CheckObservers(brokers, 1, "c"); len(errs) != 0 {
+		t.Errorf("Unexpected error(s) after leadership optimization: %s", errs)
+	}
+}
+
+func TestCheckObservers(t *testing.T) {
+	zk := &Mock{}
+	bm, _ := zk.GetAllBrokerMeta(false)
+	pm, _ := PartitionMapFromString(testGetMapString("test_topic"))
+	brokers := BrokerMapFromPartitionMap(pm, bm, false)
+
+	// p0/p1 have no replicas in rack c (1 error each). p2/p3
+	// have 1003 as a non-observer and an observer outside
+	// of rack c (2 errors each).
+	errs := pm.CheckObservers(brokers, 1, "c")
+	if len(errs) != 6 {
+		t.Errorf("Expected 6 errors, got %d: %s", len(errs), errs)
+	}
+
+	// Without a rack, only the replication
+	// factor is checked.
+	if errs := pm.CheckObservers(brokers, 1, ""); len(errs) != 0 {
+		t.Errorf("Unexpected error(s): %s", errs)
+	}
+
+	// p0/p1 only have two replicas.
+	if errs := pm.CheckObservers(brokers, 2, ""); len(errs) != 2 {
+		t.Errorf("Expected 2 errors, got %d", len(errs))
+	}
+}
@@ -0,0 +1,56 @@
+package kafkazk
+
+import (
+	"sync"
+)
+
+// DefaultConcurrency is the default maximum number of concurrent
+// ZooKeeper reads issued by bulk metadata fetches.
+const DefaultConcurrency = 16
+
+// concurrency returns the configured read concurrency
+// for the Handler.
+func concurrency(zk Handler) int {
+	if z, ok := zk.(*ZKHandler); ok && z.Concurrency > 0 {
+		return z.Concurrency
+	}
+
+	return DefaultConcurrency
+}
+
+// parallel calls f for each index in [0, n) with at most c calls in flight.
+// Once an error is encountered, no further calls are started and the first
+// error is returned.
+func parallel(n, c int, f func(i int) error) error {
+	if c < 1 {
+		c = 1
+	}
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var err error
+
+	sem := make(chan struct{}, c)
+	done := make(chan struct{})
+
+	for i := 0; i < n; i++ {
+		select {
+		case <-done:
+			wg.Wait()
+			return err
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+			if e := f(i); e != nil {
+				once.Do(func() { err = e; close(done) })
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	return err
+}
@@ -0,0 +1,60 @@
+package kafkazk
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallel(t *testing.T) {
+	var inFlight, max, calls int32
+	results := make([]int, 100)
+
+	err := parallel(100, 4, func(i int) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+
+		atomic.AddInt32(&calls, 1)
+		results[i] = i * 2
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 100 {
+		t.Errorf("Expected 100 calls, got %d", calls)
+	}
+
+	if max > 4 {
+		t.Errorf("Expected at most 4 concurrent calls, got %d", max)
+	}
+
+	for i, v := range results {
+		if v != i*2 {
+			t.Errorf("Unexpected result at index %d: %d", i, v)
+		}
+	}
+
+	// The first error is returned.
+	e := errors.New("test error")
+	err = parallel(100, 4, func(i int) error {
+		if i == 10 {
+			return e
+		}
+		return nil
+	})
+
+	if err != e {
+		t.Errorf("Expected error '%s', got '%v'", e, err)
+	}
+}
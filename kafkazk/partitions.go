@@ -151,6 +151,51 @@ type RebuildParams struct {
 	Affinities       SubstitutionAffinities
 	PartnSzFactor    float64
 	MinUniqueRackIDs int
+	// The weight (0.00-1.00) of partition counts relative
+	// to storage for the storage strategy; 0 balances
+	// storage only.
+	CountWeight float64
+	// The number of trailing replicas in each
+	// replica set that are observers.
+	Observers int
+	// If set, observers are placed in the
+	// ObserverRack and all other replicas
+	// are placed outside of it.
+	ObserverRack string
+	// A mapping of topic names to the rack of
+	// their dominant consumers. At least one replica
+	// of each partition is placed in the rack where
+	// possible, allowing consumers to fetch from
+	// a local follower.
+	ConsumerRacks map[string]string
+	// Per-topic placement constraints. Replicas on
+	// brokers not permitted for a topic are replaced.
+	TopicConstraints TopicConstraintsMap
+	// Per-partition leader pins. Replacements for
+	// partitions pinned to a rack retain at least
+	// one replica in the rack where possible.
+	LeaderPins LeaderPins
+}
+
+// replaced returns whether the replica of topic t on
+// broker id requires a replacement; the broker is either
+// marked for replacement or not permitted for the topic.
+func (params RebuildParams) replaced(t string, id int) bool {
+	return params.BM[id].Replace || !params.TopicConstraints.Permits(t, id)
+}
+
+// observerConstraints sets locality constraints on the ConstraintsParams
+// for a replica at position pos in a replica set of length rf.
+func (params RebuildParams) observerConstraints(cp *ConstraintsParams, pos, rf int) {
+	if params.ObserverRack == "" || params.Observers == 0 {
+		return
+	}
+
+	if pos >= rf-params.Observers {
+		cp.RequiredLocality = params.ObserverRack
+	} else {
+		cp.ExcludedLocality = params.ObserverRack
+	}
 }
 
 // NewRebuildParams initializes a RebuildParams.
@@ -160,6 +205,60 @@ func NewRebuildParams() RebuildParams {
 	}
 }
 
+// consumerLocalityConstraints sets a RequiredLocality on the ConstraintsParams
+// for a replacement at position pos of partn if the partition has a consumer
+// rack (see rackConstraints).
+func (params RebuildParams) consumerLocalityConstraints(cp *ConstraintsParams, partn Partition, pos int, placed []int) {
+	params.rackConstraints(cp, partn, params.ConsumerRacks[partn.Topic], pos, placed)
+}
+
+// leaderPinConstraints sets a RequiredLocality on the ConstraintsParams for
+// a replacement at position pos of partn if the partition leader is pinned
+// to a rack (see rackConstraints). Broker pins are satisfied by retaining
+// the pinned broker and aren't constrained.
+func (params RebuildParams) leaderPinConstraints(cp *ConstraintsParams, partn Partition, pos int, placed []int) {
+	if pin, pinned := params.LeaderPins.Pin(partn); pinned {
+		params.rackConstraints(cp, partn, pin.Rack, pos, placed)
+	}
+}
+
+// rackConstraints sets a RequiredLocality of rack on the ConstraintsParams
+// for a replacement at position pos of partn if pos is the final replacement
+// in the replica set and no retained or already placed replica is in the rack.
+func (params RebuildParams) rackConstraints(cp *ConstraintsParams, partn Partition, rack string, pos int, placed []int) {
+	if rack == "" || cp.RequiredLocality != "" || cp.ExcludedLocality == rack {
+		return
+	}
+
+	// Find the final replacement position.
+	last := -1
+	for i, id := range partn.Replicas {
+		if params.replaced(partn.Topic, id) {
+			last = i
+		}
+	}
+
+	if pos != last {
+		return
+	}
+
+	// Check retained replicas.
+	for i, id := range partn.Replicas {
+		if i != pos && !params.replaced(partn.Topic, id) && params.BM[id].Locality == rack {
+			return
+		}
+	}
+
+	// Check already placed replicas.
+	for _, id := range placed {
+		if params.BM[id].Locality == rack {
+			return
+		}
+	}
+
+	cp.RequiredLocality = rack
+}
+
 // OptimizeLeaderFollower is a simple leadership optimization algorithm
 // that iterates over each partition's replica set and sorts brokers
 // according to their leader/follower position ratio, ascending. The idea
@@ -167,12 +266,43 @@ func NewRebuildParams() RebuildParams {
 // go further down the replica list. This ratio is recalculated at each
 // replica set visited to avoid extreme skew.
 func (pm *PartitionMap) OptimizeLeaderFollower() {
+	pm.OptimizeLeaderFollowerObservers(0)
+}
+
+// OptimizeLeaderFollowerObservers is the same as OptimizeLeaderFollower,
+// but leaves the last n replicas (observers) of each replica set in place
+// so that they're never considered for leadership.
+func (pm *PartitionMap) OptimizeLeaderFollowerObservers(n int) {
+	if len(pm.Partitions) == 0 {
+		return
+	}
+
+	// Use stats are updated incrementally as replica
+	// sets are sorted rather than rebuilt from the full
+	// map at each replica set visited.
+	stats := pm.UseStats()
+
 	for i := 0; i < len(pm.Partitions[0].Replicas); i++ {
 		for _, partn := range pm.Partitions {
+			if len(partn.Replicas) <= n {
+				continue
+			}
+
+			leader := partn.Replicas[0]
+
 			sort.Sort(replicasByLeaderFollowerRatio{
-				replicas: partn.Replicas,
-				stats:    pm.UseStats(),
+				replicas: partn.Replicas[:len(partn.Replicas)-n],
+				stats:    stats,
 			})
+
+			// Only a change in leadership
+			// affects the use stats.
+			if l := partn.Replicas[0]; l != leader {
+				stats[leader].Leader--
+				stats[leader].Follower++
+				stats[l].Leader++
+				stats[l].Follower--
+			}
 		}
 	}
 }
@@ -212,7 +342,7 @@ func (pm *PartitionMap) Rebuild(params RebuildParams) (*PartitionMap, []error) {
 			// brokers for each partition at a time (in contrast to placeByPosition).
 			// Shuffling has proven so far to distribute leadership even though
 			// it's purely by probability. Eventually, write a real optimizer.
-			newMap.shuffle(func(_ Partition) bool { return true })
+			newMap.shuffle(func(_ Partition) bool { return true }, params.Observers)
 		// Invalid optimization.
 		default:
 			return nil, []error{fmt.Errorf("Invalid optimization '%s'", params.Optimization)}
@@ -247,7 +377,14 @@ func placeByPosition(params RebuildParams) (*PartitionMap, []error) {
 		return true
 	}
 
-	bl := params.BM.Filter(f).List()
+	bl, err := NewBrokerCandidates(params.BM.Filter(f).List(), ConstraintsParams{
+		SelectorMethod: params.Strategy,
+		CountWeight:    params.CountWeight,
+		SeedVal:        1,
+	})
+	if err != nil {
+		return nil, []error{err}
+	}
 
 	var errs []error
 	var pass int
@@ -282,17 +419,20 @@ func placeByPosition(params RebuildParams) (*PartitionMap, []error) {
 			// If the current broker isn't
 			// marked for removal, just add it
 			// to the same position in the new map.
-			if !params.BM[bid].Replace {
+			if !params.replaced(partn.Topic, bid) {
 				newMap.Partitions[n].Replicas = append(newMap.Partitions[n].Replicas, bid)
 			} else {
 				// Otherwise, we need to find a replacement.
 
 				// Build a BrokerList from the
 				// IDs in the old replica set to
-				// get a *constraints.
+				// get a *constraints. Brokers not
+				// permitted for the topic are omitted.
 				replicaSet := BrokerList{}
 				for _, bid := range partn.Replicas {
-					replicaSet = append(replicaSet, params.BM[bid])
+					if params.TopicConstraints.Permits(partn.Topic, bid) {
+						replicaSet = append(replicaSet, params.BM[bid])
+					}
 				}
 				// Add existing brokers in the
 				// new replica set as well.
@@ -305,7 +445,13 @@ func placeByPosition(params RebuildParams) (*PartitionMap, []error) {
 				constraintsParams := ConstraintsParams{
 					SelectorMethod:   params.Strategy,
 					MinUniqueRackIDs: params.MinUniqueRackIDs,
+					CountWeight:      params.CountWeight,
+					Topic:            partn.Topic,
+					TopicConstraints: params.TopicConstraints,
 				}
+				params.observerConstraints(&constraintsParams, pass, len(partn.Replicas))
+				params.leaderPinConstraints(&constraintsParams, partn, pass, newMap.Partitions[n].Replicas)
+				params.consumerLocalityConstraints(&constraintsParams, partn, pass, newMap.Partitions[n].Replicas)
 				constraints.MergeConstraints(replicaSet)
 
 				// Add any necessary meta from current partition
@@ -343,8 +489,7 @@ func placeByPosition(params RebuildParams) (*PartitionMap, []error) {
 				} else {
 					// Otherwise, use the standard
 					// constraints based selector.
-					constraintsParams.SeedVal = int64(pass*n + 1)
-					replacement, err = constraints.SelectBroker(bl, constraintsParams)
+					replacement, err = constraints.SelectCandidate(bl, constraintsParams)
 				}
 
 				if err != nil {
@@ -389,7 +534,14 @@ func placeByPartition(params RebuildParams) (*PartitionMap, []error) {
 		return true
 	}
 
-	bl := params.BM.Filter(f).List()
+	bl, err := NewBrokerCandidates(params.BM.Filter(f).List(), ConstraintsParams{
+		SelectorMethod: params.Strategy,
+		CountWeight:    params.CountWeight,
+		SeedVal:        1,
+	})
+	if err != nil {
+		return nil, []error{err}
+	}
 
 	var errs []error
 
@@ -402,21 +554,24 @@ func placeByPartition(params RebuildParams) (*PartitionMap, []error) {
 		// partition replica list to the new,
 		// selecting replacemnt for those marked
 		// for replacement.
-		for _, bid := range partn.Replicas {
+		for pos, bid := range partn.Replicas {
 			// If the current broker isn't
 			// marked for removal, just add it
 			// to the same position in the new map.
-			if !params.BM[bid].Replace {
+			if !params.replaced(partn.Topic, bid) {
 				newPartn.Replicas = append(newPartn.Replicas, bid)
 			} else {
 				// Otherwise, we need to find a replacement.
 
 				// Build a BrokerList from the
 				// IDs in the old replica set to
-				// get a *constraints.
+				// get a *constraints. Brokers not
+				// permitted for the topic are omitted.
 				replicaSet := BrokerList{}
 				for _, bid := range partn.Replicas {
-					replicaSet = append(replicaSet, params.BM[bid])
+					if params.TopicConstraints.Permits(partn.Topic, bid) {
+						replicaSet = append(replicaSet, params.BM[bid])
+					}
 				}
 				// Add existing brokers in the
 				// new replica set as well.
@@ -429,8 +584,13 @@ func placeByPartition(params RebuildParams) (*PartitionMap, []error) {
 				constraintsParams := ConstraintsParams{
 					SelectorMethod:   params.Strategy,
 					MinUniqueRackIDs: params.MinUniqueRackIDs,
-					SeedVal:          1,
+					CountWeight:      params.CountWeight,
+					Topic:            partn.Topic,
+					TopicConstraints: params.TopicConstraints,
 				}
+				params.observerConstraints(&constraintsParams, pos, len(partn.Replicas))
+				params.leaderPinConstraints(&constraintsParams, partn, pos, newPartn.Replicas)
+				params.consumerLocalityConstraints(&constraintsParams, partn, pos, newPartn.Replicas)
 				constraints.MergeConstraints(replicaSet)
 
 				// Add any necessary meta from current partition
@@ -447,7 +607,7 @@ func placeByPartition(params RebuildParams) (*PartitionMap, []error) {
 				}
 
 				// Fetch the best candidate and append.
-				replacement, err := constraints.SelectBroker(bl, constraintsParams)
+				replacement, err := constraints.SelectCandidate(bl, constraintsParams)
 
 				if err != nil {
 					// Append any caught errors.
@@ -542,13 +702,16 @@ func (pm *PartitionMap) LocalitiesAvailable(bm BrokerMap, b *Broker) []string {
 	return diff
 }
 
-func (pm *PartitionMap) shuffle(f func(Partition) bool) {
+// shuffle shuffles the replica sets of all partitions where f returns
+// true. The last o replicas (observers) of each replica set are
+// left in place.
+func (pm *PartitionMap) shuffle(f func(Partition) bool, o int) {
 	var s int
 	for n := range pm.Partitions {
-		if f(pm.Partitions[n]) {
+		if f(pm.Partitions[n]) && len(pm.Partitions[n].Replicas) > o {
 			rand.Seed(int64(s << 20))
 			s++
-			rand.Shuffle(len(pm.Partitions[n].Replicas), func(i, j int) {
+			rand.Shuffle(len(pm.Partitions[n].Replicas)-o, func(i, j int) {
 				pm.Partitions[n].Replicas[i], pm.Partitions[n].Replicas[j] = pm.Partitions[n].Replicas[j], pm.Partitions[n].Replicas[i]
 			})
 		}
@@ -585,14 +748,21 @@ func PartitionMapFromZK(t []*regexp.Regexp, zk Handler) (*PartitionMap, error) {
 	}
 
 	// Get a partition map for each topic.
-	pmapMerged := NewPartitionMap()
-	for _, t := range topicsToRebuild {
-		pmap, err := zk.GetPartitionMap(t)
-		if err != nil {
-			return nil, err
-		}
+	pmaps := make([]*PartitionMap, len(topicsToRebuild))
+
+	err = parallel(len(topicsToRebuild), concurrency(zk), func(i int) error {
+		var err error
+		pmaps[i], err = zk.GetPartitionMap(topicsToRebuild[i])
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
 
-		// Merge multiple maps.
+	// Merge multiple maps.
+	pmapMerged := NewPartitionMap()
+	for _, pmap := range pmaps {
 		pmapMerged.Partitions = append(pmapMerged.Partitions, pmap.Partitions...)
 	}
 
@@ -611,21 +781,29 @@ func (pm *PartitionMap) SetReplication(r int) {
 	}
 
 	for n, p := range pm.Partitions {
-		l := len(p.Replicas)
+		pm.Partitions[n].Replicas = resizeReplicas(p.Replicas, r)
+	}
+}
 
-		switch {
-		// Truncate replicas beyond r.
-		case l > r:
-			pm.Partitions[n].Replicas = p.Replicas[:r]
-		// Add stub brokers to meet r.
-		case l < r:
-			r := make([]int, r-l)
-			for i := 0; i < len(r); i++ {
-				r[i] = StubBrokerID
-			}
-			pm.Partitions[n].Replicas = append(p.Replicas, r...)
+// resizeReplicas truncates or extends the replicas
+// with stub brokers to a length of r.
+func resizeReplicas(replicas []int, r int) []int {
+	l := len(replicas)
+
+	switch {
+	// Truncate replicas beyond r.
+	case l > r:
+		return replicas[:r]
+	// Add stub brokers to meet r.
+	case l < r:
+		stubs := make([]int, r-l)
+		for i := 0; i < len(stubs); i++ {
+			stubs[i] = StubBrokerID
 		}
+		return append(replicas, stubs...)
 	}
+
+	return replicas
 }
 
 // Copy returns a copy of a *PartitionMap.
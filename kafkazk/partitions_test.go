@@ -3,6 +3,7 @@ package kafkazk
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"testing"
 )
 
@@ -316,6 +317,38 @@ func TestUseStats(t *testing.T) {
 	}
 }
 
+func TestOptimizeLeaderFollower(t *testing.T) {
+	pm := NewPartitionMap()
+	for i := 0; i < 50; i++ {
+		pm.Partitions = append(pm.Partitions, Partition{
+			Topic:     "test_topic",
+			Partition: i,
+			Replicas:  []int{1001 + i%3, 1004 + i%2, 1006 + i%5},
+		})
+	}
+
+	// Reference implementation recomputing
+	// use stats at each replica set visited.
+	expected := pm.Copy()
+	for i := 0; i < len(expected.Partitions[0].Replicas); i++ {
+		for _, partn := range expected.Partitions {
+			sort.Sort(replicasByLeaderFollowerRatio{
+				replicas: partn.Replicas,
+				stats:    expected.UseStats(),
+			})
+		}
+	}
+
+	pm.OptimizeLeaderFollower()
+
+	if same, err := pm.equal(expected); !same {
+		t.Error(err)
+	}
+
+	// An empty map is a no-op.
+	NewPartitionMap().OptimizeLeaderFollower()
+}
+
 // Count rebuild.
 func TestRebuildByCount(t *testing.T) {
 	forceRebuild := true
@@ -384,13 +417,13 @@ func TestRebuildByCount(t *testing.T) {
 	out, _ = pmStripped.Rebuild(rebuildParams)
 
 	expected = pm.Copy()
-	expected.Partitions[0].Replicas = []int{1001, 1003}
-	expected.Partitions[1].Replicas = []int{1002, 1004}
-	expected.Partitions[2].Replicas = []int{1003, 1001}
-	expected.Partitions[3].Replicas = []int{1004, 1002}
-	expected.Partitions[4].Replicas = []int{1001, 1002}
-	expected.Partitions[5].Replicas = []int{1002, 1004}
-	expected.Partitions[6].Replicas = []int{1003, 1001}
+	expected.Partitions[0].Replicas = []int{1001, 1002}
+	expected.Partitions[1].Replicas = []int{1002, 1001}
+	expected.Partitions[2].Replicas = []int{1004, 1003}
+	expected.Partitions[3].Replicas = []int{1003, 1002}
+	expected.Partitions[4].Replicas = []int{1004, 1003}
+	expected.Partitions[5].Replicas = []int{1003, 1004}
+	expected.Partitions[6].Replicas = []int{1001, 1002}
 
 	same, err := out.equal(expected)
 	if !same {
@@ -610,9 +643,95 @@ func TestShuffle(t *testing.T) {
 		},
 	}
 
-	pm.shuffle((func(_ Partition) bool { return true }))
+	pm.shuffle((func(_ Partition) bool { return true }), 0)
 
 	if same, _ := pm.equal(expected); !same {
 		t.Errorf("Unexpected shuffle results")
 	}
 }
+
+// benchmarkRebuildInputs returns a PartitionMap of 20,000 partitions with
+// a replication factor of 3 over 200 brokers in 5 racks along with broker
+// and partition metadata. Replica sets span 3 racks.
+func benchmarkRebuildInputs() (*PartitionMap, BrokerMetaMap, PartitionMetaMap) {
+	pm := NewPartitionMap()
+	bmm := BrokerMetaMap{}
+	pmm := NewPartitionMetaMap()
+
+	for id := 1000; id < 1200; id++ {
+		bmm[id] = &BrokerMeta{Rack: fmt.Sprintf("rack%d", id%5), StorageFree: 20000.00}
+	}
+
+	for t := 0; t < 20; t++ {
+		topic := fmt.Sprintf("topic%d", t)
+		pmm[topic] = map[int]*PartitionMeta{}
+
+		for p := 0; p < 1000; p++ {
+			i := t*1000 + p
+			replicas := []int{1000 + i%200, 1000 + (i+41)%200, 1000 + (i+82)%200}
+
+			pm.Partitions = append(pm.Partitions, Partition{Topic: topic, Partition: p, Replicas: replicas})
+			pmm[topic][p] = &PartitionMeta{Size: float64(1 + i%100)}
+		}
+	}
+
+	return pm, bmm, pmm
+}
+
+func BenchmarkRebuild(b *testing.B) {
+	pm, bmm, pmm := benchmarkRebuildInputs()
+
+	// Replace 20 of the 200 brokers.
+	var targets []int
+	for id := 1020; id < 1200; id++ {
+		targets = append(targets, id)
+	}
+
+	tests := []struct {
+		name         string
+		strategy     string
+		optimization string
+		countWeight  float64
+		force        bool
+	}{
+		{"count", "count", "distribution", 0, false},
+		{"count/force", "count", "distribution", 0, true},
+		{"storage/distribution", "storage", "distribution", 0, false},
+		{"storage/storage", "storage", "storage", 0, false},
+		{"storage/count-weight", "storage", "distribution", 0.50, false},
+	}
+
+	for _, test := range tests {
+		b.Run(test.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				in := pm.Copy()
+				brokers := BrokerMapFromPartitionMap(in, bmm, test.force)
+				brokers.Update(targets, bmm)
+
+				filter := func(br *Broker) bool { return br.Replace }
+				if test.force {
+					filter = func(br *Broker) bool { return true }
+				}
+
+				if test.strategy == "storage" {
+					brokers.SubStorage(in, pmm, filter)
+				}
+
+				if test.force {
+					in = in.Strip()
+				}
+				b.StartTimer()
+
+				in.Rebuild(RebuildParams{
+					PMM:           pmm,
+					BM:            brokers,
+					Strategy:      test.strategy,
+					Optimization:  test.optimization,
+					PartnSzFactor: 1,
+					CountWeight:   test.countWeight,
+				})
+			}
+		})
+	}
+}
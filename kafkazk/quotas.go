@@ -0,0 +1,294 @@
+package kafkazk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Client quota config keys.
+const (
+	ProducerByteRate  = "producer_byte_rate"
+	ConsumerByteRate  = "consumer_byte_rate"
+	RequestPercentage = "request_percentage"
+)
+
+// DefaultQuotaEntity is the entity name of
+// default user and client ID quotas.
+const DefaultQuotaEntity = "<default>"
+
+var (
+	// ErrInvalidQuotaEntity error.
+	ErrInvalidQuotaEntity = errors.New("a quota entity requires a user or client ID")
+
+	quotaKeys = map[string]struct{}{
+		ProducerByteRate:  struct{}{},
+		ConsumerByteRate:  struct{}{},
+		RequestPercentage: struct{}{},
+	}
+)
+
+// QuotaEntity is a client quota entity: a user, a client ID, or a client
+// ID of a user. Either may be the DefaultQuotaEntity.
+type QuotaEntity struct {
+	User     string
+	ClientID string
+}
+
+// String returns the entity as user=<user>,client-id=<client ID>,
+// omitting unset fields.
+func (e QuotaEntity) String() string {
+	var s []string
+	if e.User != "" {
+		s = append(s, "user="+e.User)
+	}
+
+	if e.ClientID != "" {
+		s = append(s, "client-id="+e.ClientID)
+	}
+
+	return strings.Join(s, ",")
+}
+
+// config returns the KafkaConfig type and name of the entity.
+func (e QuotaEntity) config() (string, string) {
+	switch {
+	case e.User != "" && e.ClientID != "":
+		return "user", fmt.Sprintf("%s/clients/%s", sanitizeEntity(e.User), sanitizeEntity(e.ClientID))
+	case e.User != "":
+		return "user", sanitizeEntity(e.User)
+	}
+
+	return "client", sanitizeEntity(e.ClientID)
+}
+
+// Quotas is a map of quota config key
+// (e.g. ProducerByteRate) to value.
+type Quotas map[string]float64
+
+// QuotaMap is a map of QuotaEntity to Quotas.
+type QuotaMap map[QuotaEntity]Quotas
+
+// Entities returns the entities of the QuotaMap,
+// sorted by user then client ID.
+func (q QuotaMap) Entities() []QuotaEntity {
+	var e []QuotaEntity
+	for k := range q {
+		e = append(e, k)
+	}
+
+	sort.Slice(e, func(i, j int) bool {
+		if e[i].User != e[j].User {
+			return e[i].User < e[j].User
+		}
+		return e[i].ClientID < e[j].ClientID
+	})
+
+	return e
+}
+
+// ValidQuotaKey returns whether k is a client quota config key.
+func ValidQuotaKey(k string) bool {
+	_, valid := quotaKeys[k]
+	return valid
+}
+
+// sanitizeEntity encodes a quota entity name as Kafka does for
+// ZooKeeper paths: URL encoding, with spaces encoded as %20, '*'
+// as %2A and '~' left as is. The default entity isn't encoded.
+func sanitizeEntity(s string) string {
+	if s == DefaultQuotaEntity {
+		return s
+	}
+
+	s = url.QueryEscape(s)
+	s = strings.Replace(s, "+", "%20", -1)
+	s = strings.Replace(s, "*", "%2A", -1)
+
+	return strings.Replace(s, "%7E", "~", -1)
+}
+
+// desanitizeEntity decodes a
+// sanitized quota entity name.
+func desanitizeEntity(s string) string {
+	d, err := url.PathUnescape(s)
+	if err != nil {
+		return s
+	}
+
+	return d
+}
+
+// quotasFromConfig returns the
+// client quota configs in c.
+func quotasFromConfig(c KafkaConfigData) Quotas {
+	q := Quotas{}
+	for k, v := range c.Config {
+		if !ValidQuotaKey(k) {
+			continue
+		}
+
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			q[k] = f
+		}
+	}
+
+	return q
+}
+
+// GetQuotas returns all client quotas configured in ZooKeeper, for users
+// (/config/users), client IDs of users (/config/users/<user>/clients) and
+// client IDs (/config/clients). Entities without quotas are omitted.
+func (z *ZKHandler) GetQuotas() (QuotaMap, error) {
+	qm := QuotaMap{}
+
+	base := "/config"
+	if z.Prefix != "" {
+		base = fmt.Sprintf("/%s/config", z.Prefix)
+	}
+
+	// get stores the quotas at path
+	// p, if any, for the entity e.
+	get := func(p string, e QuotaEntity) error {
+		data, err := z.Get(p)
+		switch err.(type) {
+		case nil:
+		case ErrNoNode:
+			return nil
+		default:
+			return err
+		}
+
+		// Parent znodes of user client
+		// quotas may hold no data.
+		if len(data) == 0 {
+			return nil
+		}
+
+		config := NewKafkaConfigData()
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("Error unmarshalling config for %s: %s", e, err)
+		}
+
+		if q := quotasFromConfig(config); len(q) > 0 {
+			qm[e] = q
+		}
+
+		return nil
+	}
+
+	// children returns the children of
+	// path p, if p exists.
+	children := func(p string) ([]string, error) {
+		c, err := z.Children(p)
+		if _, noNode := err.(ErrNoNode); noNode {
+			return nil, nil
+		}
+
+		return c, err
+	}
+
+	users, err := children(base + "/users")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range users {
+		e := QuotaEntity{User: desanitizeEntity(u)}
+		p := fmt.Sprintf("%s/users/%s", base, u)
+
+		if err := get(p, e); err != nil {
+			return nil, err
+		}
+
+		clients, err := children(p + "/clients")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range clients {
+			e := QuotaEntity{User: e.User, ClientID: desanitizeEntity(c)}
+			if err := get(fmt.Sprintf("%s/clients/%s", p, c), e); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	clients, err := children(base + "/clients")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range clients {
+		e := QuotaEntity{ClientID: desanitizeEntity(c)}
+		if err := get(fmt.Sprintf("%s/clients/%s", base, c), e); err != nil {
+			return nil, err
+		}
+	}
+
+	return qm, nil
+}
+
+// SetQuotas takes a QuotaEntity and Quotas and applies the quotas to the
+// entity. Quotas with a value of 0 are removed; any existing quotas not
+// specified are left unmodified. A bool is returned indicating whether any
+// quota was changed.
+func (z *ZKHandler) SetQuotas(e QuotaEntity, q Quotas) (bool, error) {
+	if e.User == "" && e.ClientID == "" {
+		return false, ErrInvalidQuotaEntity
+	}
+
+	t, name := e.config()
+	config := KafkaConfig{Type: t, Name: name}
+
+	var keys []string
+	for k := range q {
+		if !ValidQuotaKey(k) {
+			return false, fmt.Errorf("Invalid quota %s", k)
+		}
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		var v string
+		if q[k] != 0 {
+			v = strconv.FormatFloat(q[k], 'f', -1, 64)
+		}
+		config.Configs = append(config.Configs, [2]string{k, v})
+	}
+
+	// Parent znodes of the entity config
+	// must exist. These hold no data, as
+	// when created by Kafka.
+	base := "/config"
+	if z.Prefix != "" {
+		base = fmt.Sprintf("/%s/config", z.Prefix)
+	}
+
+	parents := []string{fmt.Sprintf("%s/%ss", base, t)}
+	if t == "user" && e.ClientID != "" {
+		u := fmt.Sprintf("%s/users/%s", base, sanitizeEntity(e.User))
+		parents = append(parents, u, u+"/clients")
+	}
+
+	for _, p := range parents {
+		exists, err := z.Exists(p)
+		if err != nil {
+			return false, err
+		}
+
+		if !exists {
+			if err := z.Create(p, ""); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return z.UpdateKafkaConfig(config)
+}
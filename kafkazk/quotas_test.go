@@ -0,0 +1,48 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestQuotaEntityConfig(t *testing.T) {
+	tests := map[QuotaEntity][2]string{
+		QuotaEntity{User: "alice"}:                           [2]string{"user", "alice"},
+		QuotaEntity{ClientID: "loader"}:                      [2]string{"client", "loader"},
+		QuotaEntity{User: "alice", ClientID: "app"}:          [2]string{"user", "alice/clients/app"},
+		QuotaEntity{User: DefaultQuotaEntity}:                [2]string{"user", "<default>"},
+		QuotaEntity{User: "CN=svc,O=org", ClientID: "a b*~"}: [2]string{"user", "CN%3Dsvc%2CO%3Dorg/clients/a%20b%2A~"},
+	}
+
+	for e, expected := range tests {
+		typ, name := e.config()
+		if typ != expected[0] || name != expected[1] {
+			t.Errorf("Expected %s config %s/%s, got %s/%s", e, expected[0], expected[1], typ, name)
+		}
+	}
+}
+
+func TestDesanitizeEntity(t *testing.T) {
+	for _, s := range []string{"alice", "CN=svc,O=org", "a b*~", DefaultQuotaEntity} {
+		if d := desanitizeEntity(sanitizeEntity(s)); d != s {
+			t.Errorf("Expected %s, got %s", s, d)
+		}
+	}
+}
+
+func TestQuotaMapEntities(t *testing.T) {
+	zk := &Mock{}
+	qm, _ := zk.GetQuotas()
+
+	expected := []string{"client-id=loader", "user=alice", "user=alice,client-id=app"}
+
+	entities := qm.Entities()
+	if len(entities) != len(expected) {
+		t.Fatalf("Expected %d entities, got %d", len(expected), len(entities))
+	}
+
+	for i, e := range entities {
+		if e.String() != expected[i] {
+			t.Errorf("Expected entity %s, got %s", expected[i], e)
+		}
+	}
+}
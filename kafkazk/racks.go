@@ -0,0 +1,41 @@
+package kafkazk
+
+// RackGroups maps physical rack IDs onto logical placement domains,
+// e.g. to treat paired availability zones as a single domain or to
+// split a large availability zone into multiple failure domains.
+type RackGroups struct {
+	// A mapping of physical rack IDs to domains.
+	Racks map[string]string `json:"racks"`
+	// A mapping of broker IDs to domains. Broker
+	// mappings take precedence over rack mappings.
+	Brokers map[int]string `json:"brokers"`
+}
+
+// Domain returns the placement domain for the broker
+// ID id in rack. Unmapped racks are their own domain.
+func (g RackGroups) Domain(id int, rack string) string {
+	if d, exists := g.Brokers[id]; exists {
+		return d
+	}
+
+	return g.RackDomain(rack)
+}
+
+// RackDomain returns the placement domain for the rack
+// ID rack, ignoring any broker mappings.
+func (g RackGroups) RackDomain(rack string) string {
+	if d, exists := g.Racks[rack]; exists {
+		return d
+	}
+
+	return rack
+}
+
+// Apply sets the Rack of each broker in the BrokerMetaMap to
+// its placement domain. All placement constraints referencing
+// rack IDs then operate on domains.
+func (g RackGroups) Apply(bmm BrokerMetaMap) {
+	for id, m := range bmm {
+		m.Rack = g.Domain(id, m.Rack)
+	}
+}
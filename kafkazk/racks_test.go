@@ -0,0 +1,46 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestRackGroups(t *testing.T) {
+	g := RackGroups{
+		Racks:   map[string]string{"a": "ab", "b": "ab"},
+		Brokers: map[int]string{1004: "c-2"},
+	}
+
+	bmm := BrokerMetaMap{
+		1001: &BrokerMeta{Rack: "a"},
+		1002: &BrokerMeta{Rack: "b"},
+		1003: &BrokerMeta{Rack: "c"},
+		1004: &BrokerMeta{Rack: "c"},
+	}
+
+	g.Apply(bmm)
+
+	expected := map[int]string{1001: "ab", 1002: "ab", 1003: "c", 1004: "c-2"}
+	for id, d := range expected {
+		if bmm[id].Rack != d {
+			t.Errorf("Expected domain %s for broker %d, got %s", d, id, bmm[id].Rack)
+		}
+	}
+
+	// Paired racks are treated as a single
+	// domain by placement constraints.
+	bm := BrokerMapFromPartitionMap(&PartitionMap{Partitions: PartitionList{
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1003}},
+	}}, bmm, false)
+	bm.Update([]int{1001, 1002, 1003}, bmm)
+
+	c := NewConstraints()
+	c.MergeConstraints(BrokerList{bm[1001]})
+
+	if c.passesWithParams(bm[1002], ConstraintsParams{}) {
+		t.Error("Expected broker 1002 to fail constraints in domain ab")
+	}
+
+	if g.RackDomain("d") != "d" {
+		t.Error("Expected unmapped rack to be its own domain")
+	}
+}
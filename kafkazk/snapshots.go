@@ -0,0 +1,164 @@
+package kafkazk
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrSnapshotExists error.
+	ErrSnapshotExists = errors.New("Snapshot already exists")
+	// ErrInvalidSnapshotName error.
+	ErrInvalidSnapshotName = errors.New("Invalid snapshot name")
+)
+
+// AssignmentSnapshot is a named capture of
+// partition assignments at a point in time.
+type AssignmentSnapshot struct {
+	Name      string        `json:"name"`
+	Timestamp int64         `json:"timestamp"` // Unix seconds.
+	User      string        `json:"user"`
+	Map       *PartitionMap `json:"map"`
+}
+
+// WriteAssignmentSnapshot takes a Handler, ZooKeeper path and an
+// *AssignmentSnapshot and writes the snapshot as a child of the path
+// named by the snapshot name. If overwrite is false, an
+// ErrSnapshotExists is returned for existing snapshots.
+func WriteAssignmentSnapshot(zk Handler, path string, s *AssignmentSnapshot, overwrite bool) error {
+	if s.Name == "" || strings.Contains(s.Name, "/") {
+		return ErrInvalidSnapshotName
+	}
+
+	p := fmt.Sprintf("%s/%s", path, s.Name)
+
+	exists, err := zk.Exists(p)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		if !overwrite {
+			return ErrSnapshotExists
+		}
+
+		if err := zk.Delete(p); err != nil {
+			return err
+		}
+	}
+
+	return createCompressedJSON(zk, p, s)
+}
+
+// GetAssignmentSnapshot takes a Handler, ZooKeeper path and
+// snapshot name and returns the *AssignmentSnapshot.
+func GetAssignmentSnapshot(zk Handler, path, name string) (*AssignmentSnapshot, error) {
+	s := &AssignmentSnapshot{}
+	if err := getCompressedJSON(zk, fmt.Sprintf("%s/%s", path, name), s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// GetAssignmentSnapshotNames takes a Handler and ZooKeeper path and
+// returns the names of all snapshots stored at the path, sorted.
+func GetAssignmentSnapshotNames(zk Handler, path string) ([]string, error) {
+	names, err := zk.Children(path)
+	if err != nil {
+		switch err.(type) {
+		case ErrNoNode:
+			return []string{}, nil
+		default:
+			return nil, err
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// DeleteAssignmentSnapshot takes a Handler, ZooKeeper path
+// and snapshot name and deletes the snapshot.
+func DeleteAssignmentSnapshot(zk Handler, path, name string) error {
+	return zk.Delete(fmt.Sprintf("%s/%s", path, name))
+}
+
+// SnapshotDiff describes the differences between the
+// current assignments and an AssignmentSnapshot.
+type SnapshotDiff struct {
+	// The current and snapshot assignments of all
+	// partitions found in both, ordered identically.
+	Current  *PartitionMap
+	Snapshot *PartitionMap
+	// Topics in the snapshot no longer present.
+	MissingTopics []string
+	// Partitions in the snapshot no longer present.
+	MissingPartitions PartitionList
+	// Partitions present but not in the snapshot.
+	NewPartitions PartitionList
+}
+
+// Diff takes a PartitionMap of the current assignments for the
+// snapshot topics and returns a SnapshotDiff. Topics in the snapshot
+// that aren't in the PartitionMap are considered missing.
+func (s *AssignmentSnapshot) Diff(pm *PartitionMap) SnapshotDiff {
+	d := SnapshotDiff{
+		Current:  NewPartitionMap(),
+		Snapshot: NewPartitionMap(),
+	}
+
+	current := map[string]map[int]Partition{}
+	for _, p := range pm.Partitions {
+		if current[p.Topic] == nil {
+			current[p.Topic] = map[int]Partition{}
+		}
+		current[p.Topic][p.Partition] = p
+	}
+
+	snapshot := map[string]map[int]struct{}{}
+	missing := map[string]struct{}{}
+
+	for _, p := range s.Map.Partitions {
+		if snapshot[p.Topic] == nil {
+			snapshot[p.Topic] = map[int]struct{}{}
+		}
+		snapshot[p.Topic][p.Partition] = struct{}{}
+
+		if current[p.Topic] == nil {
+			missing[p.Topic] = struct{}{}
+			continue
+		}
+
+		c, exists := current[p.Topic][p.Partition]
+		if !exists {
+			d.MissingPartitions = append(d.MissingPartitions, p)
+			continue
+		}
+
+		d.Current.Partitions = append(d.Current.Partitions, c)
+		d.Snapshot.Partitions = append(d.Snapshot.Partitions, p)
+	}
+
+	for t := range missing {
+		d.MissingTopics = append(d.MissingTopics, t)
+	}
+
+	sort.Strings(d.MissingTopics)
+
+	for _, p := range pm.Partitions {
+		if _, exists := snapshot[p.Topic][p.Partition]; !exists {
+			d.NewPartitions = append(d.NewPartitions, p)
+		}
+	}
+
+	sort.Sort(d.Current.Partitions)
+	sort.Sort(d.Snapshot.Partitions)
+	sort.Sort(d.MissingPartitions)
+	sort.Sort(d.NewPartitions)
+
+	return d
+}
@@ -0,0 +1,90 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestAssignmentSnapshots(t *testing.T) {
+	zk := newMemHandler()
+	path := "/topicmappr/snapshots"
+
+	pm, _ := PartitionMapFromString(testGetMapString("test_topic"))
+	s := &AssignmentSnapshot{Name: "before-upgrade", Map: pm}
+
+	if err := WriteAssignmentSnapshot(zk, path, s, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteAssignmentSnapshot(zk, path, s, false); err != ErrSnapshotExists {
+		t.Errorf("Expected ErrSnapshotExists, got %v", err)
+	}
+
+	if err := WriteAssignmentSnapshot(zk, path, s, true); err != nil {
+		t.Errorf("Unexpected error overwriting snapshot: %s", err)
+	}
+
+	bad := &AssignmentSnapshot{Name: "a/b", Map: pm}
+	if err := WriteAssignmentSnapshot(zk, path, bad, false); err != ErrInvalidSnapshotName {
+		t.Errorf("Expected ErrInvalidSnapshotName, got %v", err)
+	}
+
+	names, _ := GetAssignmentSnapshotNames(zk, path)
+	if len(names) != 1 || names[0] != "before-upgrade" {
+		t.Errorf("Unexpected snapshot names: %v", names)
+	}
+
+	s2, err := GetAssignmentSnapshot(zk, path, "before-upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if eq, _ := s2.Map.equal(pm); !eq {
+		t.Error("Unexpected snapshot map")
+	}
+
+	if err := DeleteAssignmentSnapshot(zk, path, "before-upgrade"); err != nil {
+		t.Fatal(err)
+	}
+
+	names, _ = GetAssignmentSnapshotNames(zk, path)
+	if len(names) != 0 {
+		t.Errorf("Expected 0 snapshots, got %d", len(names))
+	}
+}
+
+func TestAssignmentSnapshotDiff(t *testing.T) {
+	pm, _ := PartitionMapFromString(testGetMapString("test_topic"))
+	s := &AssignmentSnapshot{Name: "test", Map: pm.Copy()}
+
+	// Add a snapshot topic that no longer exists.
+	s.Map.Partitions = append(s.Map.Partitions, Partition{Topic: "deleted", Partition: 0, Replicas: []int{1001}})
+
+	// Change p0, drop p3 and add p4.
+	current := pm.Copy()
+	current.Partitions[0].Replicas = []int{1003, 1002}
+	current.Partitions = current.Partitions[:3]
+	current.Partitions = append(current.Partitions, Partition{Topic: "test_topic", Partition: 4, Replicas: []int{1001}})
+
+	d := s.Diff(current)
+
+	if len(d.Current.Partitions) != 3 || len(d.Snapshot.Partitions) != 3 {
+		t.Fatalf("Expected 3 partitions in both maps, got %d, %d",
+			len(d.Current.Partitions), len(d.Snapshot.Partitions))
+	}
+
+	if d.Current.Partitions[0].Replicas[0] != 1003 || d.Snapshot.Partitions[0].Replicas[0] != 1001 {
+		t.Errorf("Unexpected p0 assignments")
+	}
+
+	if len(d.MissingTopics) != 1 || d.MissingTopics[0] != "deleted" {
+		t.Errorf("Unexpected missing topics: %v", d.MissingTopics)
+	}
+
+	if len(d.MissingPartitions) != 1 || d.MissingPartitions[0].Partition != 3 {
+		t.Errorf("Unexpected missing partitions: %v", d.MissingPartitions)
+	}
+
+	if len(d.NewPartitions) != 1 || d.NewPartitions[0].Partition != 4 {
+		t.Errorf("Unexpected new partitions: %v", d.NewPartitions)
+	}
+}
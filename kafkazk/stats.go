@@ -116,6 +116,48 @@ func (b BrokerMap) StorageDiff(b2 BrokerMap) map[int][2]float64 {
 	return d
 }
 
+// IncomingStorage takes a PartitionMap that pm is being reassigned to
+// along with a PartitionMetaMap and returns a map of broker IDs to the
+// total size of partitions that each broker will newly receive. Replicas
+// that are already held by a broker in pm aren't counted.
+func (pm *PartitionMap) IncomingStorage(pm2 *PartitionMap, pmm PartitionMetaMap) (map[int]float64, error) {
+	d := map[int]float64{}
+
+	current := map[string]map[int]map[int]struct{}{}
+	for _, partn := range pm.Partitions {
+		if _, exists := current[partn.Topic]; !exists {
+			current[partn.Topic] = map[int]map[int]struct{}{}
+		}
+
+		ids := map[int]struct{}{}
+		for _, id := range partn.Replicas {
+			ids[id] = struct{}{}
+		}
+		current[partn.Topic][partn.Partition] = ids
+	}
+
+	for _, partn := range pm2.Partitions {
+		for _, id := range partn.Replicas {
+			if id == StubBrokerID {
+				continue
+			}
+
+			if _, held := current[partn.Topic][partn.Partition][id]; held {
+				continue
+			}
+
+			size, err := pmm.Size(partn)
+			if err != nil {
+				return nil, err
+			}
+
+			d[id] += size
+		}
+	}
+
+	return d, nil
+}
+
 // StorageRangeSpread returns the range spread
 // of free storage for all brokers in the BrokerMap.
 func (b BrokerMap) StorageRangeSpread() float64 {
@@ -265,3 +307,90 @@ func (b BrokerMap) BelowMean(d float64, f func() float64) []int {
 
 	return ids
 }
+
+// CapacityKnown returns whether the StorageCapacity is known for all
+// brokers in the BrokerMap. Brokers marked for replacement are ignored.
+func (b BrokerMap) CapacityKnown() bool {
+	return b.List().CapacityKnown()
+}
+
+// storageFreeRatios returns the StorageFreeRatio
+// of all brokers with a known StorageCapacity.
+func (b BrokerMap) storageFreeRatios() []float64 {
+	var r []float64
+
+	for _, br := range b {
+		if br.ID != StubBrokerID && br.StorageCapacity > 0 {
+			r = append(r, br.StorageFreeRatio())
+		}
+	}
+
+	return r
+}
+
+// StorageFreeRatioRange returns the range of StorageFreeRatio values
+// for all brokers in the BrokerMap with a known StorageCapacity.
+func (b BrokerMap) StorageFreeRatioRange() float64 {
+	r := b.storageFreeRatios()
+	if len(r) == 0 {
+		return 0
+	}
+
+	sort.Float64s(r)
+
+	return r[len(r)-1] - r[0]
+}
+
+// StorageFreeRatioStdDev returns the standard deviation of StorageFreeRatio
+// values for all brokers in the BrokerMap with a known StorageCapacity.
+func (b BrokerMap) StorageFreeRatioStdDev() float64 {
+	r := b.storageFreeRatios()
+	if len(r) == 0 {
+		return 0
+	}
+
+	m := b.StorageFreeRatioMean()
+
+	var s float64
+	for _, v := range r {
+		s += math.Pow(m-v, 2)
+	}
+
+	return math.Sqrt(s / float64(len(r)))
+}
+
+// StorageFreeRatioMean returns the arithmetic mean of StorageFreeRatio
+// values for all brokers in the BrokerMap with a known StorageCapacity.
+func (b BrokerMap) StorageFreeRatioMean() float64 {
+	r := b.storageFreeRatios()
+	if len(r) == 0 {
+		return 0
+	}
+
+	var t float64
+	for _, v := range r {
+		t += v
+	}
+
+	return t / float64(len(r))
+}
+
+// StorageFreeRatioHMean returns the harmonic mean of StorageFreeRatio
+// values for all brokers in the BrokerMap with a known StorageCapacity.
+func (b BrokerMap) StorageFreeRatioHMean() float64 {
+	var t float64
+	var c float64
+
+	for _, v := range b.storageFreeRatios() {
+		if v > 0 {
+			c++
+			t += 1.00 / v
+		}
+	}
+
+	if c == 0 {
+		return 0
+	}
+
+	return c / t
+}
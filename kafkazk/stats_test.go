@@ -73,6 +73,37 @@ func TestDegreeDistribution(t *testing.T) {
 	}
 }
 
+func TestIncomingStorage(t *testing.T) {
+	zk := &Mock{}
+	pm1, _ := zk.GetPartitionMap("test_topic")
+	pmm, _ := zk.GetAllPartitionMeta()
+
+	pm2 := pm1.Copy()
+	pm2.Partitions[0].Replicas = []int{1001, 1005}
+	pm2.Partitions[3].Replicas = []int{1005, 1003, 1002}
+	// Reordering replicas shouldn't count as incoming.
+	pm2.Partitions[1].Replicas = []int{1001, 1002}
+
+	in, err := pm1.IncomingStorage(pm2, pmm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(in) != 1 {
+		t.Errorf("Expected 1 broker with incoming storage, got %d", len(in))
+	}
+
+	if in[1005] != 3500.00 {
+		t.Errorf("Expected incoming storage of 3500.00 for 1005, got %f", in[1005])
+	}
+
+	// Missing partition meta should error.
+	delete(pmm["test_topic"], 0)
+	if _, err := pm1.IncomingStorage(pm2, pmm); err == nil {
+		t.Error("Expected error")
+	}
+}
+
 func TestBrokerMapStorageDiff(t *testing.T) {
 	bm1 := newMockBrokerMap()
 	bm2 := newMockBrokerMap()
@@ -170,6 +201,36 @@ func TestMean(t *testing.T) {
 	}
 }
 
+func TestStorageFreeRatioStats(t *testing.T) {
+	bm := newMockBrokerMap()
+
+	if bm.CapacityKnown() {
+		t.Error("Expected capacity to be unknown")
+	}
+
+	// Free storage ratios: 0.10, 0.20, 0.30, 0.40.
+	for _, b := range bm {
+		b.StorageCapacity = 1000.00
+	}
+
+	if !bm.CapacityKnown() {
+		t.Error("Expected capacity to be known")
+	}
+
+	tests := map[string][2]float64{
+		"range":   {bm.StorageFreeRatioRange(), 0.30},
+		"mean":    {bm.StorageFreeRatioMean(), 0.25},
+		"hmean":   {bm.StorageFreeRatioHMean(), 0.192},
+		"std dev": {bm.StorageFreeRatioStdDev(), 0.1118},
+	}
+
+	for name, v := range tests {
+		if math.Abs(v[0]-v[1]) > 0.0001 {
+			t.Errorf("Expected %s of %.4f, got %.4f", name, v[1], v[0])
+		}
+	}
+}
+
 func TestAboveMean(t *testing.T) {
 	bm := newMockBrokerMap2()
 
@@ -0,0 +1,89 @@
+package kafkazk
+
+import (
+	"fmt"
+)
+
+// TopicConstraints holds placement constraints for a topic.
+type TopicConstraints struct {
+	// If non-nil, replicas may only be
+	// placed on the listed brokers.
+	Brokers []int
+	// Replicas are never placed on
+	// the listed brokers.
+	ExcludedBrokers []int
+	// The required replication factor;
+	// 0 if unconstrained.
+	Replication int
+}
+
+// TopicConstraintsMap is a mapping of topic
+// names to TopicConstraints.
+type TopicConstraintsMap map[string]TopicConstraints
+
+// Permits takes a topic name and broker ID and returns whether
+// the broker may hold replicas of the topic.
+func (m TopicConstraintsMap) Permits(t string, id int) bool {
+	c, exists := m[t]
+	if !exists {
+		return true
+	}
+
+	for _, e := range c.ExcludedBrokers {
+		if e == id {
+			return false
+		}
+	}
+
+	if c.Brokers == nil {
+		return true
+	}
+
+	for _, b := range c.Brokers {
+		if b == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetReplication resets the replica sets of all partitions in the
+// PartitionMap belonging to topics with a required replication factor.
+// Sets exceeding the replication factor are truncated, sets below are
+// extended with stub brokers.
+func (m TopicConstraintsMap) SetReplication(pm *PartitionMap) {
+	for n, p := range pm.Partitions {
+		if r := m[p.Topic].Replication; r > 0 {
+			pm.Partitions[n].Replicas = resizeReplicas(p.Replicas, r)
+		}
+	}
+}
+
+// Check takes a PartitionMap and returns an error for each partition
+// with a replication factor other than that required by its topic or
+// with replicas on brokers not permitted for its topic.
+func (m TopicConstraintsMap) Check(pm *PartitionMap) []error {
+	var errs []error
+
+	for _, partn := range pm.Partitions {
+		c, exists := m[partn.Topic]
+		if !exists {
+			continue
+		}
+
+		if rf := len(partn.Replicas); c.Replication > 0 && rf != c.Replication {
+			errs = append(errs, fmt.Errorf("%s p%d: replication factor %d, topic requires %d",
+				partn.Topic, partn.Partition, rf, c.Replication))
+		}
+
+		for _, id := range partn.Replicas {
+			if !m.Permits(partn.Topic, id) {
+				errs = append(errs, fmt.Errorf("%s p%d: replica on broker %d not permitted for topic",
+					partn.Topic, partn.Partition, id))
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,93 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestTopicConstraintsPermits(t *testing.T) {
+	m := TopicConstraintsMap{
+		"allowed":  TopicConstraints{Brokers: []int{1001, 1002}},
+		"excluded": TopicConstraints{Brokers: []int{1001, 1002}, ExcludedBrokers: []int{1002}},
+	}
+
+	tests := []struct {
+		topic   string
+		id      int
+		permits bool
+	}{
+		{"allowed", 1001, true},
+		{"allowed", 1003, false},
+		{"excluded", 1001, true},
+		{"excluded", 1002, false},
+		{"other", 1003, true},
+	}
+
+	for _, test := range tests {
+		if p := m.Permits(test.topic, test.id); p != test.permits {
+			t.Errorf("[%s/%d] Expected %v, got %v", test.topic, test.id, test.permits, p)
+		}
+	}
+
+	// A nil map permits all.
+	var n TopicConstraintsMap
+	if !n.Permits("allowed", 1003) {
+		t.Error("Expected nil TopicConstraintsMap to permit all brokers")
+	}
+}
+
+func TestTopicConstraintsSetReplication(t *testing.T) {
+	pm, _ := PartitionMapFromString(testGetMapString("test_topic"))
+	m := TopicConstraintsMap{"test_topic": TopicConstraints{Replication: 3}}
+
+	if errs := m.Check(pm); len(errs) != 2 {
+		t.Errorf("Expected 2 errors, got %d", len(errs))
+	}
+
+	m.SetReplication(pm)
+
+	for _, p := range pm.Partitions {
+		if len(p.Replicas) != 3 {
+			t.Errorf("Expected replication factor 3 for p%d, got %d", p.Partition, len(p.Replicas))
+		}
+	}
+
+	if pm.Partitions[0].Replicas[2] != StubBrokerID {
+		t.Errorf("Expected stub broker in p0, got %v", pm.Partitions[0].Replicas)
+	}
+}
+
+func TestRebuildWithTopicConstraints(t *testing.T) {
+	zk := &Mock{}
+	bm, _ := zk.GetAllBrokerMeta(false)
+	pm, _ := PartitionMapFromString(testGetMapString("test_topic"))
+	brokers := BrokerMapFromPartitionMap(pm, bm, false)
+	brokers.Update([]int{1001, 1002, 1003, 1004, 1005}, bm)
+
+	tc := TopicConstraintsMap{"test_topic": TopicConstraints{ExcludedBrokers: []int{1001}}}
+
+	if errs := tc.Check(pm); len(errs) != 3 {
+		t.Errorf("Expected 3 errors, got %d", len(errs))
+	}
+
+	rebuildParams := RebuildParams{
+		PMM:              NewPartitionMetaMap(),
+		BM:               brokers,
+		Strategy:         "count",
+		Optimization:     "distribution",
+		TopicConstraints: tc,
+	}
+
+	out, errs := pm.Rebuild(rebuildParams)
+	if errs != nil {
+		t.Fatalf("Unexpected error(s): %s", errs)
+	}
+
+	if errs := tc.Check(out); len(errs) != 0 {
+		t.Errorf("Unexpected error(s): %s", errs)
+	}
+
+	// Replicas on permitted brokers are retained.
+	if out.Partitions[3].Replicas[0] != 1004 {
+		t.Errorf("Expected p3 leader 1004 to be retained, got %v", out.Partitions[3].Replicas)
+	}
+}
@@ -3,14 +3,21 @@ package kafkazk
 import (
 	"bytes"
 	"compress/gzip"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
+	"net"
+	"path"
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	zkclient "github.com/samuel/go-zookeeper/zk"
@@ -19,11 +26,17 @@ import (
 var (
 	// ErrInvalidKafkaConfigType error.
 	ErrInvalidKafkaConfigType = errors.New("Invalid Kafka config type")
+	// ErrReassignmentInProgress error.
+	ErrReassignmentInProgress = errors.New("A partition reassignment is already in progress")
+	// ErrTopicDeletionInProgress error.
+	ErrTopicDeletionInProgress = errors.New("The topic is already marked for deletion")
 	// validKafkaConfigTypes is used as a set
 	// to define valid configuration type names.
 	validKafkaConfigTypes = map[string]struct{}{
 		"broker": struct{}{},
 		"topic":  struct{}{},
+		"client": struct{}{},
+		"user":   struct{}{},
 	}
 )
 
@@ -38,12 +51,19 @@ func (e ErrNoNode) Error() string {
 	return e.s
 }
 
+// NewErrNoNode returns an ErrNoNode with the error string s,
+// for Handler implementations not backed by ZooKeeper.
+func NewErrNoNode(s string) ErrNoNode {
+	return ErrNoNode{s: s}
+}
+
 // Handler provides basic ZooKeeper operations along with
 // calls that return kafkazk types describing Kafka states.
 type Handler interface {
 	Exists(string) (bool, error)
 	Create(string, string) error
 	CreateSequential(string, string) error
+	CreateEphemeralSequential(string, string) (string, error)
 	Set(string, string) error
 	Get(string) ([]byte, error)
 	Delete(string) error
@@ -54,13 +74,23 @@ type Handler interface {
 	GetTopicState(string) (*TopicState, error)
 	GetTopicStateISR(string) (TopicStateISR, error)
 	UpdateKafkaConfig(KafkaConfig) (bool, error)
+	ElectPreferredLeaders(PreferredReplicaElection) error
 	GetReassignments() Reassignments
+	ReassignPartitions(*PartitionMap) error
+	DeleteTopic(string) error
 	GetTopics([]*regexp.Regexp) ([]string, error)
 	GetTopicConfig(string) (*TopicConfig, error)
+	GetConfigChanges(int64) ([]ConfigChange, error)
+	GetBrokerConfig(int) (*KafkaConfigData, error)
 	GetAllBrokerMeta(bool) (BrokerMetaMap, []error)
 	GetAllPartitionMeta() (PartitionMetaMap, error)
 	MaxMetaAge() (time.Duration, error)
 	GetPartitionMap(string) (*PartitionMap, error)
+	GetQuotas() (QuotaMap, error)
+	SetQuotas(QuotaEntity, Quotas) (bool, error)
+	// Watches.
+	GetTopicStateW(string) (*TopicState, <-chan struct{}, error)
+	WatchBrokers() (<-chan struct{}, error)
 }
 
 // TopicState is used for unmarshing ZooKeeper json data from a topic:
@@ -104,6 +134,25 @@ type TopicConfig struct {
 	Config  map[string]string `json:"config"`
 }
 
+// ConfigChange is a Kafka dynamic config change notification,
+// stored at /config/changes/config_change_<seq>.
+type ConfigChange struct {
+	Seq int64
+	// The changed config entity, e.g.
+	// topics/<topic> or brokers/<id>.
+	EntityPath string
+}
+
+// configChangeNotification is used for unmarshalling config change
+// notifications. Notifications are version 1 (entity type and name)
+// or version 2 (entity path).
+type configChangeNotification struct {
+	Version    int    `json:"version"`
+	EntityType string `json:"entity_type"`
+	EntityName string `json:"entity_name"`
+	EntityPath string `json:"entity_path"`
+}
+
 // KafkaConfig is used to issue configuration updates to either
 // topics or brokers in ZooKeeper.
 type KafkaConfig struct {
@@ -134,17 +183,31 @@ type ZKHandler struct {
 	Connect       string
 	Prefix        string
 	MetricsPrefix string
+	Concurrency   int
 }
 
 // Config holds initialization paramaters for a Handler. Connect
 // is a ZooKeeper connect string. Prefix should reflect any prefix
 // used for Kafka on the reference ZooKeeper cluster (excluding slashes).
 // MetricsPrefix is the prefix used for broker metrics metadata persisted
-// in ZooKeeper.
+// in ZooKeeper. Concurrency limits the number of concurrent reads issued
+// by bulk metadata fetches (defaults to DefaultConcurrency). If TLS is
+// non-nil, connections are made with TLS (e.g. to a ZooKeeper secureClientPort).
+// TLSCACert is the path to a PEM CA certificate that servers are verified
+// against (the system roots if empty), TLSCert and TLSKey the paths to a PEM
+// client certificate and key for mTLS, and TLSServerName the name verified
+// against server certificates (the connect string host if empty). These are
+// applied to a copy of TLS, and setting any enables TLS.
 type Config struct {
 	Connect       string
 	Prefix        string
 	MetricsPrefix string
+	Concurrency   int
+	TLS           *tls.Config
+	TLSCACert     string
+	TLSCert       string
+	TLSKey        string
+	TLSServerName string
 }
 
 // NewHandler takes a *Config, performs
@@ -154,10 +217,25 @@ func NewHandler(c *Config) (Handler, error) {
 		Connect:       c.Connect,
 		Prefix:        c.Prefix,
 		MetricsPrefix: c.MetricsPrefix,
+		Concurrency:   c.Concurrency,
 	}
 
-	var err error
-	z.client, _, err = zkclient.Connect([]string{z.Connect}, 10*time.Second, zkclient.WithLogInfo(false))
+	if z.Concurrency <= 0 {
+		z.Concurrency = DefaultConcurrency
+	}
+
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var dialer zkclient.Dialer = net.DialTimeout
+	if tlsConfig != nil {
+		dialer = tlsDialer(tlsConfig)
+	}
+
+	z.client, _, err = zkclient.Connect([]string{z.Connect}, 10*time.Second,
+		zkclient.WithLogInfo(false), zkclient.WithDialer(dialer))
 	if err != nil {
 		return nil, err
 	}
@@ -165,6 +243,57 @@ func NewHandler(c *Config) (Handler, error) {
 	return z, nil
 }
 
+// tlsConfig returns the *tls.Config for connections,
+// or nil if TLS isn't enabled.
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	if c.TLS == nil && c.TLSCACert == "" && c.TLSCert == "" && c.TLSKey == "" && c.TLSServerName == "" {
+		return nil, nil
+	}
+
+	t := &tls.Config{}
+	if c.TLS != nil {
+		t = c.TLS.Clone()
+	}
+
+	if c.TLSCACert != "" {
+		pem, err := ioutil.ReadFile(c.TLSCACert)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading CA certificate: %s", err)
+		}
+
+		t.RootCAs = x509.NewCertPool()
+		if !t.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("No valid certificates found in %s", c.TLSCACert)
+		}
+	}
+
+	switch {
+	case c.TLSCert != "" && c.TLSKey != "":
+		pair, err := tls.LoadX509KeyPair(c.TLSCert, c.TLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading client certificate: %s", err)
+		}
+
+		t.Certificates = []tls.Certificate{pair}
+	case c.TLSCert != "" || c.TLSKey != "":
+		return nil, errors.New("A client certificate and key must be specified together")
+	}
+
+	if c.TLSServerName != "" {
+		t.ServerName = c.TLSServerName
+	}
+
+	return t, nil
+}
+
+// tlsDialer returns a zkclient.Dialer
+// that establishes TLS connections.
+func tlsDialer(c *tls.Config) zkclient.Dialer {
+	return func(network, addr string, timeout time.Duration) (net.Conn, error) {
+		return tls.DialWithDialer(&net.Dialer{Timeout: timeout}, network, addr, c)
+	}
+}
+
 // Ready returns true if the client is in either state
 // StateConnected or StateHasSession.
 // See https://godoc.org/github.com/samuel/go-zookeeper/zk#State.
@@ -238,6 +367,19 @@ func (z *ZKHandler) CreateSequential(p string, d string) error {
 	return err
 }
 
+// CreateEphemeralSequential takes a path p and data d and creates
+// an ephemeral sequential znode at p with data d, which is removed
+// when the session ends. The created znode path is returned with
+// any error encountered.
+func (z *ZKHandler) CreateEphemeralSequential(p string, d string) (string, error) {
+	created, e := z.client.Create(p, []byte(d), zkclient.FlagEphemeral|zkclient.FlagSequence, zkclient.WorldACL(31))
+	if e != nil {
+		return "", fmt.Errorf("[%s] %s", p, e.Error())
+	}
+
+	return created, nil
+}
+
 // Create creates the provided path p with the data
 // from the provided string d and returns an error
 // if encountered.
@@ -317,6 +459,56 @@ func (z *ZKHandler) GetReassignments() Reassignments {
 	return reassigns
 }
 
+// ReassignPartitions takes a *PartitionMap and triggers a reassignment of
+// all partitions in the map by creating the /admin/reassign_partitions
+// znode. An ErrReassignmentInProgress is returned if a reassignment is
+// already in progress.
+func (z *ZKHandler) ReassignPartitions(pm *PartitionMap) error {
+	var path string
+	if z.Prefix != "" {
+		path = fmt.Sprintf("/%s/admin/reassign_partitions", z.Prefix)
+	} else {
+		path = "/admin/reassign_partitions"
+	}
+
+	data, err := json.Marshal(pm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := z.client.Create(path, data, 0, zkclient.WorldACL(31)); err != nil {
+		if err == zkclient.ErrNodeExists {
+			return ErrReassignmentInProgress
+		}
+		return fmt.Errorf("[%s] %s", path, err.Error())
+	}
+
+	return nil
+}
+
+// DeleteTopic takes a topic name and marks the topic for deletion by
+// creating the /admin/delete_topics/<topic> znode; the controller then
+// deletes the topic if topic deletion is enabled (delete.topic.enable).
+// An ErrTopicDeletionInProgress is returned if the topic is already
+// marked for deletion.
+func (z *ZKHandler) DeleteTopic(t string) error {
+	var path string
+	if z.Prefix != "" {
+		path = fmt.Sprintf("/%s/admin/delete_topics/%s", z.Prefix, t)
+	} else {
+		path = fmt.Sprintf("/admin/delete_topics/%s", t)
+	}
+
+	if _, err := z.client.Create(path, nil, 0, zkclient.WorldACL(31)); err != nil {
+		if err == zkclient.ErrNodeExists {
+			return ErrTopicDeletionInProgress
+		}
+		return fmt.Errorf("[%s] %s", path, err.Error())
+	}
+
+	return nil
+}
+
 // GetTopics takes a []*regexp.Regexp and returns a []string of all topic
 // names that match any of the provided regex.
 func (z *ZKHandler) GetTopics(ts []*regexp.Regexp) ([]string, error) {
@@ -377,6 +569,80 @@ func (z *ZKHandler) GetTopicConfig(t string) (*TopicConfig, error) {
 	return config, nil
 }
 
+// GetConfigChanges returns all config change notifications with a sequence
+// number greater than since, ordered by sequence number. Kafka purges
+// notifications 15 minutes after their creation.
+func (z *ZKHandler) GetConfigChanges(since int64) ([]ConfigChange, error) {
+	path := "/config/changes"
+	if z.Prefix != "" {
+		path = fmt.Sprintf("/%s%s", z.Prefix, path)
+	}
+
+	children, err := z.Children(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []ConfigChange
+
+	for _, c := range children {
+		seq, err := strconv.ParseInt(strings.TrimPrefix(c, "config_change_"), 10, 64)
+		if err != nil || seq <= since {
+			continue
+		}
+
+		data, err := z.Get(fmt.Sprintf("%s/%s", path, c))
+		if err != nil {
+			// The notification may have been
+			// purged since listing.
+			if _, noNode := err.(ErrNoNode); noNode {
+				continue
+			}
+			return nil, err
+		}
+
+		var n configChangeNotification
+		if err := json.Unmarshal(data, &n); err != nil {
+			return nil, fmt.Errorf("Error unmarshalling config change %s: %s", c, err)
+		}
+
+		if n.EntityPath == "" {
+			n.EntityPath = fmt.Sprintf("%s/%s", n.EntityType, n.EntityName)
+		}
+
+		changes = append(changes, ConfigChange{Seq: seq, EntityPath: n.EntityPath})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Seq < changes[j].Seq
+	})
+
+	return changes, nil
+}
+
+// GetBrokerConfig takes a broker ID. If the broker has dynamic configs
+// applied, the config is returned as a *KafkaConfigData.
+func (z *ZKHandler) GetBrokerConfig(id int) (*KafkaConfigData, error) {
+	config := NewKafkaConfigData()
+
+	var path string
+	if z.Prefix != "" {
+		path = fmt.Sprintf("/%s/config/brokers/%d", z.Prefix, id)
+	} else {
+		path = fmt.Sprintf("/config/brokers/%d", id)
+	}
+
+	// Get broker config.
+	data, err := z.Get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	json.Unmarshal(data, &config)
+
+	return &config, nil
+}
+
 // GetAllBrokerMeta looks up all registered Kafka brokers and returns their
 // metadata as a BrokerMetaMap. A withMetrics bool param determines whether
 // we additionally want to fetch stored broker metrics.
@@ -397,36 +663,40 @@ func (z *ZKHandler) GetAllBrokerMeta(withMetrics bool) (BrokerMetaMap, []error)
 	}
 
 	bmm := BrokerMetaMap{}
+	var mu sync.Mutex
 
-	// Map each broker.
-	for _, b := range entries {
+	// Fetch & unmarshal the data for each broker.
+	parallel(len(entries), concurrency(z), func(i int) error {
 		bm := &BrokerMeta{}
 		// In case we encounter non-ints (broker IDs) for
 		// whatever reason, just continue.
-		bid, err := strconv.Atoi(b)
+		bid, err := strconv.Atoi(entries[i])
 		if err != nil {
-			continue
+			return nil
 		}
 
-		// Fetch & unmarshal the data for each broker.
-		bpath := fmt.Sprintf("%s/%s", path, b)
+		bpath := fmt.Sprintf("%s/%s", path, entries[i])
 		data, err := z.Get(bpath)
 		// XXX do something else.
 		if err != nil {
-			continue
+			return nil
 		}
 
 		err = json.Unmarshal(data, bm)
 		if err != nil {
-			continue
+			return nil
 		}
 
+		mu.Lock()
 		bmm[bid] = bm
-	}
+		mu.Unlock()
+
+		return nil
+	})
 
 	// Fetch and populate in metrics.
 	if withMetrics {
-		bmetrics, err := z.getBrokerMetrics()
+		bmetrics, err := z.GetBrokerMetrics()
 		if err != nil {
 			return nil, []error{err}
 		}
@@ -440,6 +710,7 @@ func (z *ZKHandler) GetAllBrokerMeta(withMetrics bool) (BrokerMetaMap, []error)
 				bmm[bid].MetricsIncomplete = true
 			} else {
 				bmm[bid].StorageFree = m.StorageFree
+				bmm[bid].StorageCapacity = m.StorageCapacity
 			}
 		}
 
@@ -450,7 +721,7 @@ func (z *ZKHandler) GetAllBrokerMeta(withMetrics bool) (BrokerMetaMap, []error)
 
 // GetBrokerMetrics fetches broker metrics stored in ZooKeeper and returns
 // a BrokerMetricsMap and an error if encountered.
-func (z *ZKHandler) getBrokerMetrics() (BrokerMetricsMap, error) {
+func (z *ZKHandler) GetBrokerMetrics() (BrokerMetricsMap, error) {
 	var path string
 	if z.MetricsPrefix != "" {
 		path = fmt.Sprintf("/%s/brokermetrics", z.MetricsPrefix)
@@ -587,6 +858,67 @@ func (z *ZKHandler) GetTopicState(t string) (*TopicState, error) {
 	return ts, nil
 }
 
+// GetTopicStateW is GetTopicState, also returning a channel that's closed
+// when the topic state changes (e.g. partitions are added or reassigned),
+// the topic is deleted, or the ZooKeeper session is lost. No watch is set
+// if the topic doesn't exist.
+func (z *ZKHandler) GetTopicStateW(t string) (*TopicState, <-chan struct{}, error) {
+	var path string
+	if z.Prefix != "" {
+		path = fmt.Sprintf("/%s/brokers/topics/%s", z.Prefix, t)
+	} else {
+		path = fmt.Sprintf("/brokers/topics/%s", t)
+	}
+
+	data, _, ev, err := z.client.GetW(path)
+	if err != nil {
+		switch err {
+		case zkclient.ErrNoNode:
+			return nil, nil, ErrNoNode{s: fmt.Sprintf("[%s] %s", path, err.Error())}
+		default:
+			return nil, nil, fmt.Errorf("[%s] %s", path, err.Error())
+		}
+	}
+
+	ts := &TopicState{}
+	if err := json.Unmarshal(data, ts); err != nil {
+		return nil, nil, err
+	}
+
+	return ts, watchDone(ev), nil
+}
+
+// WatchBrokers returns a channel that's closed when a broker registers or
+// deregisters (including on restarts), or the ZooKeeper session is lost.
+func (z *ZKHandler) WatchBrokers() (<-chan struct{}, error) {
+	var path string
+	if z.Prefix != "" {
+		path = fmt.Sprintf("/%s/brokers/ids", z.Prefix)
+	} else {
+		path = "/brokers/ids"
+	}
+
+	_, _, ev, err := z.client.ChildrenW(path)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %s", path, err)
+	}
+
+	return watchDone(ev), nil
+}
+
+// watchDone returns a channel that's closed once
+// the ZooKeeper watch event channel ev fires.
+func watchDone(ev <-chan zkclient.Event) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		<-ev
+		close(done)
+	}()
+
+	return done
+}
+
 // GetTopicStateISR takes a topic name. If the topic exists, the topic state
 // is returned as a TopicStateISR. GetTopicStateCurrentISR differs from
 // GetTopicState in that the actual, current broker IDs in the ISR are
@@ -609,21 +941,25 @@ func (z *ZKHandler) GetTopicStateISR(t string) (TopicStateISR, error) {
 	}
 
 	// Get partition data.
-	for _, p := range partitions {
-		ppath := fmt.Sprintf("%s/%s/state", path, p)
+	states := make([]PartitionState, len(partitions))
+
+	err = parallel(len(partitions), concurrency(z), func(i int) error {
+		ppath := fmt.Sprintf("%s/%s/state", path, partitions[i])
 		data, err := z.Get(ppath)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		state := PartitionState{}
-		err = json.Unmarshal(data, &state)
-		if err != nil {
-			return nil, err
-		}
+		return json.Unmarshal(data, &states[i])
+	})
 
-		// Populate into TopicState.
-		ts[p] = state
+	if err != nil {
+		return nil, err
+	}
+
+	// Populate into TopicState.
+	for i, p := range partitions {
+		ts[p] = states[i]
 	}
 
 	return ts, nil
@@ -641,6 +977,12 @@ func (z *ZKHandler) GetPartitionMap(t string) (*PartitionMap, error) {
 	// Get current reassign_partitions.
 	re := z.GetReassignments()
 
+	return partitionMapFromTopicState(t, ts, re), nil
+}
+
+// partitionMapFromTopicState maps the TopicState ts for topic t to a
+// *PartitionMap, overriding replica sets with any pending Reassignments.
+func partitionMapFromTopicState(t string, ts *TopicState, re Reassignments) *PartitionMap {
 	// Update with partitions in reassignment.
 	// We might have this in /admin/reassign_partitions:
 	// {"version":1,"partitions":[{"topic":"myTopic","partition":14,"replicas":[1039,1044]}]}
@@ -672,7 +1014,7 @@ func (z *ZKHandler) GetPartitionMap(t string) (*PartitionMap, error) {
 
 	sort.Sort(pm.Partitions)
 
-	return pm, nil
+	return pm
 }
 
 // UpdateKafkaConfig takes a KafkaConfig with key value pairs of
@@ -725,6 +1067,11 @@ func (z *ZKHandler) UpdateKafkaConfig(c KafkaConfig) (bool, error) {
 	} else {
 		config = NewKafkaConfigData()
 		json.Unmarshal(data, &config)
+		// Parent znodes of user client quota
+		// configs may be created with no data.
+		if config.Version == 0 {
+			config.Version = 1
+		}
 	}
 
 	// Populate configs.
@@ -796,3 +1143,74 @@ func uncompress(b []byte) ([]byte, bool) {
 
 	return nil, false
 }
+
+// createCompressedJSON takes a Handler, path p and value v and creates
+// the znode p, and any missing parents, with v as gzip compressed JSON.
+func createCompressedJSON(zk Handler, p string, v interface{}) error {
+	if err := createPath(zk, path.Dir(p)); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	return zk.Create(p, buf.String())
+}
+
+// getCompressedJSON takes a Handler, path p and value v and unmarshals
+// the data at p into v. Uncompressed data is also accepted.
+func getCompressedJSON(zk Handler, p string, v interface{}) error {
+	data, err := zk.Get(p)
+	if err != nil {
+		return err
+	}
+
+	if uncompressed, ok := uncompress(data); ok {
+		data = uncompressed
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("Error unmarshalling %s: %s", p, err)
+	}
+
+	return nil
+}
+
+// createPath creates each znode in the path p
+// that doesn't already exist.
+func createPath(zk Handler, p string) error {
+	var current string
+
+	for _, node := range strings.Split(strings.Trim(p, "/"), "/") {
+		if node == "" {
+			continue
+		}
+
+		current = fmt.Sprintf("%s/%s", current, node)
+
+		exists, err := zk.Exists(current)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			if err := zk.Create(current, ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
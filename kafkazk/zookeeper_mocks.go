@@ -22,6 +22,12 @@ func (zk *Mock) GetReassignments() Reassignments {
 	return r
 }
 
+// ReassignPartitions mocks ReassignPartitions.
+func (zk *Mock) ReassignPartitions(pm *PartitionMap) error {
+	_ = pm
+	return nil
+}
+
 // Create mocks Create.
 func (zk *Mock) Create(a, b string) error {
 	_, _ = a, b
@@ -34,6 +40,12 @@ func (zk *Mock) CreateSequential(a, b string) error {
 	return nil
 }
 
+// CreateEphemeralSequential mocks CreateEphemeralSequential.
+func (zk *Mock) CreateEphemeralSequential(a, b string) (string, error) {
+	_ = b
+	return a + "0000000000", nil
+}
+
 // Exists mocks Exists.
 func (zk *Mock) Exists(a string) (bool, error) {
 	_ = a
@@ -116,6 +128,18 @@ func (zk *Mock) UpdateKafkaConfig(c KafkaConfig) (bool, error) {
 	return true, nil
 }
 
+// DeleteTopic mocks DeleteTopic.
+func (zk *Mock) DeleteTopic(t string) error {
+	_ = t
+	return nil
+}
+
+// ElectPreferredLeaders mocks ElectPreferredLeaders.
+func (zk *Mock) ElectPreferredLeaders(e PreferredReplicaElection) error {
+	_ = e
+	return nil
+}
+
 // GetTopics mocks GetTopics.
 func (zk *Mock) GetTopics(ts []*regexp.Regexp) ([]string, error) {
 	t := []string{"test_topic", "test_topic2"}
@@ -151,6 +175,24 @@ func (zk *Mock) GetTopicConfig(t string) (*TopicConfig, error) {
 	}, nil
 }
 
+// GetConfigChanges mocks GetConfigChanges.
+func (zk *Mock) GetConfigChanges(since int64) ([]ConfigChange, error) {
+	_ = since
+	return nil, nil
+}
+
+// GetBrokerConfig mocks GetBrokerConfig.
+func (zk *Mock) GetBrokerConfig(id int) (*KafkaConfigData, error) {
+	_ = id
+	return &KafkaConfigData{
+		Version: 1,
+		Config: map[string]string{
+			"leader.replication.throttled.rate":   "100000000",
+			"follower.replication.throttled.rate": "100000000",
+		},
+	}, nil
+}
+
 // GetAllBrokerMeta mocks GetAllBrokerMeta.
 func (zk *Mock) GetAllBrokerMeta(withMetrics bool) (BrokerMetaMap, []error) {
 	b := BrokerMetaMap{
@@ -166,6 +208,7 @@ func (zk *Mock) GetAllBrokerMeta(withMetrics bool) (BrokerMetaMap, []error) {
 
 		for bid := range b {
 			b[bid].StorageFree = m[bid].StorageFree
+			b[bid].StorageCapacity = m[bid].StorageCapacity
 		}
 	}
 
@@ -175,11 +218,11 @@ func (zk *Mock) GetAllBrokerMeta(withMetrics bool) (BrokerMetaMap, []error) {
 // GetBrokerMetrics mocks GetBrokerMetrics.
 func (zk *Mock) GetBrokerMetrics() (BrokerMetricsMap, error) {
 	bm := BrokerMetricsMap{
-		1001: &BrokerMetrics{StorageFree: 2000.00},
-		1002: &BrokerMetrics{StorageFree: 4000.00},
-		1003: &BrokerMetrics{StorageFree: 6000.00},
-		1004: &BrokerMetrics{StorageFree: 8000.00},
-		1005: &BrokerMetrics{StorageFree: 10000.00},
+		1001: &BrokerMetrics{StorageFree: 2000.00, StorageCapacity: 20000.00},
+		1002: &BrokerMetrics{StorageFree: 4000.00, StorageCapacity: 20000.00},
+		1003: &BrokerMetrics{StorageFree: 6000.00, StorageCapacity: 20000.00},
+		1004: &BrokerMetrics{StorageFree: 8000.00, StorageCapacity: 20000.00},
+		1005: &BrokerMetrics{StorageFree: 10000.00, StorageCapacity: 20000.00},
 	}
 
 	return bm, nil
@@ -219,3 +262,38 @@ func (zk *Mock) GetPartitionMap(t string) (*PartitionMap, error) {
 func (zk *Mock) MaxMetaAge() (time.Duration, error) {
 	return time.Since(time.Now()), nil
 }
+
+// GetQuotas mocks GetQuotas.
+func (zk *Mock) GetQuotas() (QuotaMap, error) {
+	return QuotaMap{
+		QuotaEntity{ClientID: "loader"}: Quotas{
+			ProducerByteRate: 1048576,
+		},
+		QuotaEntity{User: "alice"}: Quotas{
+			ConsumerByteRate:  2097152,
+			RequestPercentage: 50,
+		},
+		QuotaEntity{User: "alice", ClientID: "app"}: Quotas{
+			ProducerByteRate: 524288,
+		},
+	}, nil
+}
+
+// SetQuotas mocks SetQuotas.
+func (zk *Mock) SetQuotas(e QuotaEntity, q Quotas) (bool, error) {
+	_, _ = e, q
+	return true, nil
+}
+
+// GetTopicStateW mocks GetTopicStateW.
+// The returned channel is never closed.
+func (zk *Mock) GetTopicStateW(t string) (*TopicState, <-chan struct{}, error) {
+	ts, _ := zk.GetTopicState(t)
+	return ts, make(chan struct{}), nil
+}
+
+// WatchBrokers mocks WatchBrokers. The
+// returned channel is never closed.
+func (zk *Mock) WatchBrokers() (<-chan struct{}, error) {
+	return make(chan struct{}), nil
+}
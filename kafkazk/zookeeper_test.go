@@ -3,8 +3,11 @@ package kafkazk
 import (
 	"bytes"
 	"compress/gzip"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"regexp"
 	"sort"
 	"testing"
@@ -237,6 +240,57 @@ func setBrokerMetrics() error {
 	return err
 }
 
+func TestConfigTLS(t *testing.T) {
+	// TLS isn't enabled.
+	c := &Config{}
+	if tc, err := c.tlsConfig(); tc != nil || err != nil {
+		t.Errorf("Expected nil config and error, got %v, %v", tc, err)
+	}
+
+	// Parameters are applied to a copy of TLS.
+	base := &tls.Config{MinVersion: tls.VersionTLS12}
+	c = &Config{TLS: base, TLSServerName: "zk.example.com"}
+
+	tc, err := c.tlsConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tc.ServerName != "zk.example.com" || tc.MinVersion != tls.VersionTLS12 {
+		t.Errorf("Unexpected config %+v", tc)
+	}
+
+	if base.ServerName != "" {
+		t.Error("Expected TLS to be unmodified")
+	}
+
+	// The server name alone enables TLS.
+	c = &Config{TLSServerName: "zk.example.com"}
+	if tc, err := c.tlsConfig(); err != nil || tc == nil {
+		t.Errorf("Expected a TLS config, got %v, %v", tc, err)
+	}
+
+	f, err := ioutil.TempFile("", "kafkazk_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+	f.WriteString("not a certificate")
+	f.Close()
+
+	errs := map[string]*Config{
+		fmt.Sprintf("No valid certificates found in %s", f.Name()): {TLSCACert: f.Name()},
+		"A client certificate and key must be specified together":  {TLSCert: f.Name()},
+	}
+
+	for expected, c := range errs {
+		if _, err := c.tlsConfig(); err == nil || err.Error() != expected {
+			t.Errorf("Expected error '%s', got '%v'", expected, err)
+		}
+	}
+}
+
 func TestCreateSetGetDelete(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -363,6 +417,21 @@ func TestGetReassignments(t *testing.T) {
 	}
 }
 
+func TestReassignPartitions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	pm, _ := PartitionMapFromString(testGetMapString("topic0"))
+
+	// The reassignment from TestSetup
+	// is still in progress.
+	err := zki.ReassignPartitions(pm)
+	if err != ErrReassignmentInProgress {
+		t.Errorf("Expected error '%s', got '%v'", ErrReassignmentInProgress, err)
+	}
+}
+
 func TestGetTopics(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -847,6 +916,31 @@ func TestUpdateKafkaConfigBroker(t *testing.T) {
 	}
 }
 
+func TestGetBrokerConfig(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	// Written in TestUpdateKafkaConfigBroker.
+	c, err := zki.GetBrokerConfig(1001)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, exist := c.Config["leader.replication.throttled.rate"]
+	if !exist {
+		t.Error("Expected 'leader.replication.throttled.rate' config key to exist")
+	}
+
+	if v != "100000" {
+		t.Errorf("Expected config value '100000', got '%s'", v)
+	}
+
+	if _, err := zki.GetBrokerConfig(1010); err == nil {
+		t.Error("Expected error for broker without config")
+	}
+}
+
 func TestUpdateKafkaConfigTopic(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -901,6 +995,192 @@ func TestUpdateKafkaConfigTopic(t *testing.T) {
 	}
 }
 
+func TestGetConfigChanges(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	changes, err := zki.GetConfigChanges(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ConfigChange{
+		ConfigChange{Seq: 0, EntityPath: "brokers/1001"},
+		ConfigChange{Seq: 1, EntityPath: "topics/topic0"},
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected changes %v, got %v", expected, changes)
+	}
+
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Expected change %v, got %v", expected[i], changes[i])
+		}
+	}
+
+	// Changes since the last seen.
+	changes, _ = zki.GetConfigChanges(1)
+	if len(changes) != 0 {
+		t.Errorf("Expected no changes, got %v", changes)
+	}
+}
+
+func TestSetGetQuotas(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	quotas := QuotaMap{
+		QuotaEntity{User: "alice", ClientID: "app"}: Quotas{ProducerByteRate: 1048576},
+		QuotaEntity{ClientID: "loader"}:             Quotas{RequestPercentage: 12.5},
+	}
+
+	for _, e := range quotas.Entities() {
+		changed, err := zki.SetQuotas(e, quotas[e])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !changed {
+			t.Errorf("Expected config update change status for %s", e)
+		}
+	}
+
+	paths = append(paths,
+		zkprefix+"/config/users",
+		zkprefix+"/config/users/alice",
+		zkprefix+"/config/users/alice/clients",
+		zkprefix+"/config/users/alice/clients/app",
+		zkprefix+"/config/clients",
+		zkprefix+"/config/clients/loader",
+		zkprefix+"/config/changes/config_change_0000000002",
+		zkprefix+"/config/changes/config_change_0000000003",
+	)
+
+	d, _, err := zkc.Get(zkprefix + "/config/changes/config_change_0000000003")
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := `{"version":2,"entity_path":"users/alice/clients/app"}`
+	if string(d) != expected {
+		t.Errorf("Expected config '%s', got '%s'", expected, string(d))
+	}
+
+	qm, err := zki.GetQuotas()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(qm) != len(quotas) {
+		t.Errorf("Expected %d quota entities, got %d", len(quotas), len(qm))
+	}
+
+	for e, q := range quotas {
+		for k, v := range q {
+			if qm[e][k] != v {
+				t.Errorf("Expected %s %s %f, got %f", e, k, v, qm[e][k])
+			}
+		}
+	}
+}
+
+func TestDeleteTopic(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	// Kafka creates the parent
+	// znode on startup.
+	p := zkprefix + "/admin/delete_topics"
+	if _, err := zkc.Create(p, []byte{}, 0, zkclient.WorldACL(31)); err != nil {
+		t.Fatal(err)
+	}
+
+	paths = append(paths, p, p+"/topic4")
+
+	if err := zki.DeleteTopic("topic4"); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, _ := zki.Exists(p + "/topic4"); !exists {
+		t.Error("Expected topic4 to be marked for deletion")
+	}
+
+	if err := zki.DeleteTopic("topic4"); err != ErrTopicDeletionInProgress {
+		t.Errorf("Expected error '%s', got '%v'", ErrTopicDeletionInProgress, err)
+	}
+}
+
+func TestGetTopicStateW(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	p := zkprefix + "/brokers/topics/topic1"
+
+	d, _, err := zkc.Get(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts, changed, err := zki.GetTopicStateW("topic1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ts.Partitions) == 0 {
+		t.Error("Expected topic1 partitions")
+	}
+
+	select {
+	case <-changed:
+		t.Fatal("Unexpected topic1 watch event")
+	default:
+	}
+
+	// Rewrite the unchanged state.
+	if _, err := zkc.Set(p, d, -1); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Error("Expected topic1 watch event")
+	}
+
+	if _, _, err := zki.GetTopicStateW("nonexistent"); err == nil {
+		t.Error("Expected error for nonexistent topic")
+	}
+}
+
+func TestWatchBrokers(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	changed, err := zki.WatchBrokers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := zkprefix + "/brokers/ids/1099"
+	if _, err := zkc.Create(p, []byte("{}"), 0, zkclient.WorldACL(31)); err != nil {
+		t.Fatal(err)
+	}
+
+	paths = append(paths, p)
+
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Error("Expected broker watch event")
+	}
+}
+
 // TestTearDown does any tear down cleanup.
 func TestTearDown(t *testing.T) {
 	if testing.Short() {
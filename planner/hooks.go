@@ -0,0 +1,288 @@
+package planner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// HookInput is the input to a PlacementHook.
+type HookInput struct {
+	// The operation being planned, e.g. rebuild or rebalance.
+	Command string `json:"command"`
+	// The current assignments and the planned output. The
+	// output is that of the built-in placement or the
+	// prior hook.
+	Input  *kafkazk.PartitionMap `json:"input"`
+	Output *kafkazk.PartitionMap `json:"output"`
+	// The brokers available for placement. StorageFree values
+	// are estimations following the planned output.
+	Brokers       kafkazk.BrokerMap        `json:"brokers"`
+	BrokerMeta    kafkazk.BrokerMetaMap    `json:"broker_meta"`
+	PartitionMeta kafkazk.PartitionMetaMap `json:"partition_meta"`
+}
+
+// PlacementHook is an extension point for custom placement strategies and
+// post-processing filters. A hook takes a HookInput and returns a
+// PartitionMap holding the same partitions as the HookInput Output. A hook
+// may return the Output unmodified, adjust it (e.g. reordering replicas or
+// swapping brokers) or replace it entirely.
+type PlacementHook interface {
+	Apply(HookInput) (*kafkazk.PartitionMap, error)
+}
+
+// HookFunc is a function implementing PlacementHook.
+type HookFunc func(HookInput) (*kafkazk.PartitionMap, error)
+
+// Apply calls f(in).
+func (f HookFunc) Apply(in HookInput) (*kafkazk.PartitionMap, error) {
+	return f(in)
+}
+
+// ExecHook is a PlacementHook that runs an external command. The HookInput
+// is written to the command's stdin as JSON and the output is read from its
+// stdout in the partition map format. A non-zero exit status is returned as
+// an error along with the command's stderr.
+type ExecHook struct {
+	Path string
+	Args []string
+	// If non-zero, the command is killed
+	// after the timeout.
+	Timeout time.Duration
+}
+
+// Apply runs the command.
+func (h ExecHook) Apply(in HookInput) (*kafkazk.PartitionMap, error) {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if h.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.Timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	c := exec.CommandContext(ctx, h.Path, h.Args...)
+	c.Stdin = bytes.NewReader(data)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	if err := c.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%s timed out after %s", h.Path, h.Timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %s: %s", h.Path, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %s", h.Path, err)
+	}
+
+	pm, err := kafkazk.PartitionMapFromString(stdout.String())
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid output: %s", h.Path, err)
+	}
+
+	return pm, nil
+}
+
+var (
+	hooksMu sync.Mutex
+	hooks   = map[string]PlacementHook{}
+)
+
+// RegisterHook makes a PlacementHook available by name (see LookupHook).
+// It's intended to be called from an init function in programs embedding
+// the planner. RegisterHook panics if the name is already registered.
+func RegisterHook(name string, h PlacementHook) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	if _, exists := hooks[name]; exists {
+		panic(fmt.Sprintf("planner: hook %s already registered", name))
+	}
+
+	hooks[name] = h
+}
+
+// LookupHook takes a hook spec and returns the PlacementHook. A spec
+// prefixed with 'exec:' returns an ExecHook for the following command
+// and space delimited arguments, otherwise the spec is the name of a
+// hook registered with RegisterHook.
+func LookupHook(spec string) (PlacementHook, error) {
+	if strings.HasPrefix(spec, "exec:") {
+		fields := strings.Fields(strings.TrimPrefix(spec, "exec:"))
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("Invalid hook '%s': no command specified", spec)
+		}
+		return ExecHook{Path: fields[0], Args: fields[1:]}, nil
+	}
+
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	h, exists := hooks[spec]
+	if !exists {
+		return nil, fmt.Errorf("Hook '%s' not registered", spec)
+	}
+
+	return h, nil
+}
+
+// HookError is returned by ApplyHooks for a failed hook.
+type HookError struct {
+	// The index of the failed hook.
+	Index int
+	Err   error
+}
+
+func (e HookError) Error() string {
+	return fmt.Sprintf("Hook %d: %s", e.Index+1, e.Err)
+}
+
+// ApplyHooks takes a list of PlacementHook and a HookInput and applies each
+// hook in order, where each receives the output of the prior hook. The final
+// output is returned. Hook outputs are validated to hold exactly the input
+// partitions, with replica sets referencing only brokers in the HookInput
+// Brokers and without duplicates; partitions are returned in the order of
+// the HookInput Output. If the PartitionMeta is non-nil, the StorageFree of
+// the Brokers is updated to reflect any changes made by hooks. Errors are
+// returned as a HookError.
+func ApplyHooks(hs []PlacementHook, in HookInput) (*kafkazk.PartitionMap, error) {
+	for i, h := range hs {
+		out, err := h.Apply(in)
+		if err != nil {
+			return nil, HookError{Index: i, Err: err}
+		}
+
+		out, err = orderHookOutput(in.Output, out, in.Brokers)
+		if err != nil {
+			return nil, HookError{Index: i, Err: err}
+		}
+
+		if in.PartitionMeta != nil {
+			if err := updateStorage(in.Brokers, in.Output, out, in.PartitionMeta); err != nil {
+				return nil, HookError{Index: i, Err: err}
+			}
+		}
+
+		in.Output = out
+	}
+
+	return in.Output, nil
+}
+
+// orderHookOutput validates the hook output pm2 against the hook input pm
+// and returns pm2 with partitions in the order of pm.
+func orderHookOutput(pm, pm2 *kafkazk.PartitionMap, bm kafkazk.BrokerMap) (*kafkazk.PartitionMap, error) {
+	if pm2 == nil {
+		return nil, fmt.Errorf("No partition map returned")
+	}
+
+	type key struct {
+		topic     string
+		partition int
+	}
+
+	returned := map[key]kafkazk.Partition{}
+	for _, p := range pm2.Partitions {
+		k := key{p.Topic, p.Partition}
+		if _, exists := returned[k]; exists {
+			return nil, fmt.Errorf("%s p%d returned more than once", p.Topic, p.Partition)
+		}
+		returned[k] = p
+	}
+
+	out := kafkazk.NewPartitionMap()
+
+	for _, p := range pm.Partitions {
+		p2, exists := returned[key{p.Topic, p.Partition}]
+		if !exists {
+			return nil, fmt.Errorf("%s p%d missing from output", p.Topic, p.Partition)
+		}
+		delete(returned, key{p.Topic, p.Partition})
+
+		if len(p2.Replicas) == 0 {
+			return nil, fmt.Errorf("%s p%d has no replicas", p.Topic, p.Partition)
+		}
+
+		seen := map[int]struct{}{}
+		for _, id := range p2.Replicas {
+			// Stub IDs denote unfilled replicas.
+			if id == kafkazk.StubBrokerID {
+				continue
+			}
+
+			if _, dupe := seen[id]; dupe {
+				return nil, fmt.Errorf("%s p%d has duplicate replica %d", p.Topic, p.Partition, id)
+			}
+			seen[id] = struct{}{}
+
+			if _, exists := bm[id]; bm != nil && !exists {
+				return nil, fmt.Errorf("%s p%d: broker %d not found in broker map", p.Topic, p.Partition, id)
+			}
+		}
+
+		out.Partitions = append(out.Partitions, p2)
+	}
+
+	if len(returned) > 0 {
+		var extra []string
+		for k := range returned {
+			extra = append(extra, fmt.Sprintf("%s p%d", k.topic, k.partition))
+		}
+		sort.Strings(extra)
+		return nil, fmt.Errorf("Unexpected partitions in output: %s", strings.Join(extra, ", "))
+	}
+
+	return out, nil
+}
+
+// updateStorage updates the StorageFree of brokers in the BrokerMap for
+// all replicas added or removed from pm1 to pm2. pm1 and pm2 must hold
+// the same partitions in the same order.
+func updateStorage(bm kafkazk.BrokerMap, pm1, pm2 *kafkazk.PartitionMap, pmm kafkazk.PartitionMetaMap) error {
+	for i, p1 := range pm1.Partitions {
+		p2 := pm2.Partitions[i]
+		if p1.Equal(p2) {
+			continue
+		}
+
+		size, err := pmm.Size(p1)
+		if err != nil {
+			return err
+		}
+
+		before, after := replicaSet(p1.Replicas), replicaSet(p2.Replicas)
+
+		for id := range before {
+			if _, kept := after[id]; !kept {
+				if b, exists := bm[id]; exists {
+					b.StorageFree += size
+				}
+			}
+		}
+
+		for id := range after {
+			if _, held := before[id]; !held {
+				if b, exists := bm[id]; exists {
+					b.StorageFree -= size
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package planner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+func TestApplyHooks(t *testing.T) {
+	pm := testPartitionMap()
+	pmm := testPartitionMeta()
+	bm := kafkazk.BrokerMapFromPartitionMap(pm, testBrokerMeta(), false)
+
+	// Moves p0 from 1001 to 1005 and returns
+	// partitions in reverse order.
+	move := HookFunc(func(in HookInput) (*kafkazk.PartitionMap, error) {
+		out := in.Output.Copy()
+		out.Partitions[0].Replicas = []int{1005, 1002}
+
+		for i, j := 0, len(out.Partitions)-1; i < j; i, j = i+1, j-1 {
+			out.Partitions[i], out.Partitions[j] = out.Partitions[j], out.Partitions[i]
+		}
+
+		return out, nil
+	})
+
+	// Swaps the leader of p0.
+	lead := HookFunc(func(in HookInput) (*kafkazk.PartitionMap, error) {
+		out := in.Output.Copy()
+		r := out.Partitions[0].Replicas
+		r[0], r[1] = r[1], r[0]
+		return out, nil
+	})
+
+	bm[1005] = &kafkazk.Broker{ID: 1005, StorageFree: 500 * div}
+
+	out, err := ApplyHooks([]PlacementHook{move, lead}, HookInput{
+		Input:         pm,
+		Output:        pm.Copy(),
+		Brokers:       bm,
+		PartitionMeta: pmm,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Partitions[0].Partition != 0 {
+		t.Errorf("Expected partitions in input order")
+	}
+
+	if r := out.Partitions[0].Replicas; r[0] != 1002 || r[1] != 1005 {
+		t.Errorf("Expected replicas [1002 1005], got %v", r)
+	}
+
+	if bm[1001].StorageFree != 150*div {
+		t.Errorf("Expected 1001 storage free of %.2f, got %.2f", 150.0*div, bm[1001].StorageFree)
+	}
+
+	if bm[1005].StorageFree != 450*div {
+		t.Errorf("Expected 1005 storage free of %.2f, got %.2f", 450.0*div, bm[1005].StorageFree)
+	}
+}
+
+func TestApplyHooksInvalid(t *testing.T) {
+	pm := testPartitionMap()
+	bm := kafkazk.BrokerMapFromPartitionMap(pm, testBrokerMeta(), false)
+
+	tests := map[string]func(*kafkazk.PartitionMap){
+		"missing from output":     func(out *kafkazk.PartitionMap) { out.Partitions = out.Partitions[1:] },
+		"has duplicate replica":   func(out *kafkazk.PartitionMap) { out.Partitions[0].Replicas = []int{1001, 1001} },
+		"not found in broker map": func(out *kafkazk.PartitionMap) { out.Partitions[0].Replicas = []int{1001, 2001} },
+		"has no replicas":         func(out *kafkazk.PartitionMap) { out.Partitions[0].Replicas = nil },
+		"returned more than once": func(out *kafkazk.PartitionMap) { out.Partitions[1] = out.Partitions[0] },
+		"Unexpected partitions in": func(out *kafkazk.PartitionMap) {
+			out.Partitions = append(out.Partitions, kafkazk.Partition{Topic: "other", Replicas: []int{1001}})
+		},
+	}
+
+	for expected, f := range tests {
+		hook := HookFunc(func(in HookInput) (*kafkazk.PartitionMap, error) {
+			out := in.Output.Copy()
+			f(out)
+			return out, nil
+		})
+
+		_, err := ApplyHooks([]PlacementHook{hook}, HookInput{Input: pm, Output: pm.Copy(), Brokers: bm})
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error containing '%s', got '%v'", expected, err)
+		}
+
+		if he, ok := err.(HookError); !ok || he.Index != 0 {
+			t.Errorf("Expected HookError for hook index 0, got %v", err)
+		}
+	}
+}
+
+func TestRebuildHooks(t *testing.T) {
+	// Swaps the leader of p0.
+	lead := HookFunc(func(in HookInput) (*kafkazk.PartitionMap, error) {
+		out := in.Output.Copy()
+		r := out.Partitions[0].Replicas
+		r[0], r[1] = r[1], r[0]
+		return out, nil
+	})
+
+	noop := HookFunc(func(in HookInput) (*kafkazk.PartitionMap, error) { return in.Output, nil })
+
+	params := RebuildParams{
+		PartitionMap: testPartitionMap(),
+		BrokerMeta:   testBrokerMeta(),
+		Brokers:      []int{-1},
+		Hooks:        []PlacementHook{lead, noop},
+	}
+
+	plan, err := Rebuild(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c := plan.HookChanges; len(c) != 2 || c[0] != 1 || c[1] != 0 {
+		t.Errorf("Expected hook changes [1 0], got %v", c)
+	}
+
+	// Errors reference the failed hook.
+	failing := HookFunc(func(in HookInput) (*kafkazk.PartitionMap, error) { return nil, nil })
+	params.Hooks = append(params.Hooks, failing)
+
+	if _, err := Rebuild(params); err == nil {
+		t.Error("Expected hook error")
+	} else if he, ok := err.(HookError); !ok || he.Index != 2 {
+		t.Errorf("Expected HookError for hook index 2, got %v", err)
+	}
+}
+
+func TestExecHook(t *testing.T) {
+	pm := testPartitionMap()
+
+	dir, err := ioutil.TempDir("", "hooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	script := filepath.Join(dir, "hook.sh")
+	err = ioutil.WriteFile(script, []byte(`#!/bin/sh
+cat > /dev/null
+echo '{"version":1,"partitions":[{"topic":"test","partition":0,"replicas":[1002,1001]}]}'
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := LookupHook("exec:" + script)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := kafkazk.NewPartitionMap()
+	in.Partitions = pm.Partitions[:1]
+
+	out, err := ApplyHooks([]PlacementHook{h}, HookInput{Input: in, Output: in.Copy()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r := out.Partitions[0].Replicas; r[0] != 1002 || r[1] != 1001 {
+		t.Errorf("Expected replicas [1002 1001], got %v", r)
+	}
+
+	failing := ExecHook{Path: "sh", Args: []string{"-c", "echo failed >&2; exit 1"}}
+	if _, err := failing.Apply(HookInput{Input: in, Output: in}); err == nil || !strings.Contains(err.Error(), "failed") {
+		t.Errorf("Expected error containing stderr, got '%v'", err)
+	}
+}
+
+func TestLookupHook(t *testing.T) {
+	noop := HookFunc(func(in HookInput) (*kafkazk.PartitionMap, error) { return in.Output, nil })
+	RegisterHook("test-noop", noop)
+
+	if _, err := LookupHook("test-noop"); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := LookupHook("test-missing"); err == nil {
+		t.Error("Expected error for unregistered hook")
+	}
+
+	if _, err := LookupHook("exec:"); err == nil {
+		t.Error("Expected error for empty exec hook")
+	}
+}
@@ -0,0 +1,67 @@
+package planner
+
+import (
+	"fmt"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// OutOfSyncError is returned by Rebuild when all partitions are
+// excluded for having out of sync replicas.
+type OutOfSyncError struct {
+	Partitions kafkazk.OutOfSyncPartitions
+}
+
+func (e OutOfSyncError) Error() string {
+	return "No partitions remaining after out of sync exclusions"
+}
+
+// MinISRError is returned by Rebuild when changed partitions could fall
+// below min.insync.replicas and the MinISRCheck policy is block.
+type MinISRError struct {
+	Violations []kafkazk.MinISRViolation
+}
+
+func (e MinISRError) Error() string {
+	return fmt.Sprintf("%d partition(s) could fall below min.insync.replicas", len(e.Violations))
+}
+
+// OutOfSync takes a Handler, PartitionMap and BrokerMetaMap and returns all
+// partitions with assigned replicas outside of the ISR or on brokers not
+// found in the BrokerMetaMap. Topics that don't exist yet (and have no ISR
+// state) are skipped, as are all checks if the Handler is nil.
+func OutOfSync(zk kafkazk.Handler, pm *kafkazk.PartitionMap, bm kafkazk.BrokerMetaMap) (kafkazk.OutOfSyncPartitions, error) {
+	if zk == nil {
+		return nil, nil
+	}
+
+	oos, err := pm.OutOfSync(zk, bm)
+	if err != nil {
+		if _, ok := err.(kafkazk.ErrNoNode); ok {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Error fetching ISR state: %s", err)
+	}
+
+	return oos, nil
+}
+
+// MinISRViolations takes a Handler, the input and output PartitionMap and
+// the min.insync.replicas assumed for topics without an override and returns
+// all changed partitions that could fall below min.insync.replicas. Topics
+// that don't exist yet are skipped, as are all checks if the Handler is nil.
+func MinISRViolations(zk kafkazk.Handler, pm1, pm2 *kafkazk.PartitionMap, def int) ([]kafkazk.MinISRViolation, error) {
+	if zk == nil {
+		return nil, nil
+	}
+
+	violations, err := pm1.MinISRViolations(pm2, zk, def)
+	if err != nil {
+		if _, ok := err.(kafkazk.ErrNoNode); ok {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Error checking min.insync.replicas: %s", err)
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,354 @@
+package planner
+
+import (
+	"math"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// Objectives holds the cost of a plan for each placement objective.
+// Costs are normalized such that 0 is optimal and values are roughly
+// comparable across objectives.
+type Objectives struct {
+	// The coefficient of variation of
+	// estimated broker free storage.
+	Storage float64
+	// The range of leaders per broker
+	// relative to the mean.
+	Leadership float64
+	// The fraction of replica data moved (in bytes if
+	// partition metrics are available, otherwise in
+	// replicas).
+	Movement float64
+	// The mean fraction of partition replica sets
+	// falling short of the maximum achievable
+	// rack spread.
+	RackDiversity float64
+	// The fraction of followers replicating
+	// from a leader in a different rack.
+	CrossAZ float64
+}
+
+// ObjectiveWeights holds the weight of each objective.
+type ObjectiveWeights Objectives
+
+// Weighted returns the Objectives with each
+// cost multiplied by its weight in w.
+func (o Objectives) Weighted(w ObjectiveWeights) Objectives {
+	return Objectives{
+		Storage:       o.Storage * w.Storage,
+		Leadership:    o.Leadership * w.Leadership,
+		Movement:      o.Movement * w.Movement,
+		RackDiversity: o.RackDiversity * w.RackDiversity,
+		CrossAZ:       o.CrossAZ * w.CrossAZ,
+	}
+}
+
+// Total returns the sum of all objective costs.
+func (o Objectives) Total() float64 {
+	return o.Storage + o.Leadership + o.Movement + o.RackDiversity + o.CrossAZ
+}
+
+// Evaluate takes the input and output PartitionMap, a BrokerMetaMap and
+// a PartitionMetaMap and returns the Objectives of the output map. The
+// free storage of brokers holding replicas in pm2 is estimated from the
+// BrokerMetaMap StorageFree values along with all changes from pm1 to pm2.
+// The storage objective is 0 if
+// metrics are unavailable; the rack objectives are 0 if fewer than two
+// racks are known. pm1 and pm2 must hold the same partitions in the same
+// order.
+func Evaluate(pm1, pm2 *kafkazk.PartitionMap, bmm kafkazk.BrokerMetaMap, pmm kafkazk.PartitionMetaMap) Objectives {
+	return Objectives{
+		Storage:       storageObjective(pm1, pm2, bmm, pmm),
+		Leadership:    leadershipObjective(pm2),
+		Movement:      movementObjective(pm1, pm2, pmm),
+		RackDiversity: rackDiversityObjective(pm2, bmm),
+		CrossAZ:       crossAZObjective(pm2, bmm),
+	}
+}
+
+// storageObjective returns the coefficient of variation of estimated broker
+// free storage, or of free storage as a portion of capacity if the storage
+// capacity of all brokers is known.
+func storageObjective(pm1, pm2 *kafkazk.PartitionMap, bmm kafkazk.BrokerMetaMap, pmm kafkazk.PartitionMetaMap) float64 {
+	if pmm == nil {
+		return 0
+	}
+
+	free := map[int]float64{}
+	for _, p := range pm2.Partitions {
+		for _, id := range p.Replicas {
+			if m, exists := bmm[id]; exists && id != kafkazk.StubBrokerID {
+				free[id] = m.StorageFree
+			}
+		}
+	}
+
+	for i, p1 := range pm1.Partitions {
+		size, err := pmm.Size(p1)
+		if err != nil {
+			return 0
+		}
+
+		before, after := replicaSet(p1.Replicas), replicaSet(pm2.Partitions[i].Replicas)
+
+		for id := range before {
+			if _, kept := after[id]; !kept {
+				if _, tracked := free[id]; tracked {
+					free[id] += size
+				}
+			}
+		}
+
+		for id := range after {
+			if _, held := before[id]; !held {
+				if _, tracked := free[id]; tracked {
+					free[id] -= size
+				}
+			}
+		}
+	}
+
+	// Compare free storage as a portion of capacity
+	// if the capacity of all brokers is known.
+	capacityKnown := len(free) > 0
+	for id := range free {
+		if bmm[id].StorageCapacity <= 0 {
+			capacityKnown = false
+			break
+		}
+	}
+
+	var vals []float64
+	for id, v := range free {
+		if capacityKnown {
+			v /= bmm[id].StorageCapacity
+		}
+		vals = append(vals, v)
+	}
+
+	mean, sd := meanStdDev(vals)
+	if mean <= 0 {
+		return 0
+	}
+
+	return sd / mean
+}
+
+// leadershipObjective returns the range of
+// leaders per broker relative to the mean.
+func leadershipObjective(pm *kafkazk.PartitionMap) float64 {
+	leaders := map[int]int{}
+	for _, p := range pm.Partitions {
+		for _, id := range p.Replicas {
+			if _, exists := leaders[id]; !exists && id != kafkazk.StubBrokerID {
+				leaders[id] = 0
+			}
+		}
+
+		if len(p.Replicas) > 0 && p.Replicas[0] != kafkazk.StubBrokerID {
+			leaders[p.Replicas[0]]++
+		}
+	}
+
+	if len(leaders) == 0 {
+		return 0
+	}
+
+	min, max, total := math.MaxInt32, 0, 0
+	for _, n := range leaders {
+		if n < min {
+			min = n
+		}
+		if n > max {
+			max = n
+		}
+		total += n
+	}
+
+	mean := float64(total) / float64(len(leaders))
+	if mean == 0 {
+		return 0
+	}
+
+	return float64(max-min) / mean
+}
+
+// movementObjective returns the fraction of
+// replica data moved from pm1 to pm2.
+func movementObjective(pm1, pm2 *kafkazk.PartitionMap, pmm kafkazk.PartitionMetaMap) float64 {
+	var moved, total, movedBytes, totalBytes float64
+	bytes := pmm != nil
+
+	for i, p1 := range pm1.Partitions {
+		p2 := pm2.Partitions[i]
+
+		var size float64
+		if bytes {
+			s, err := pmm.Size(p1)
+			if err != nil {
+				bytes = false
+			}
+			size = s
+		}
+
+		before := replicaSet(p1.Replicas)
+		for _, id := range p2.Replicas {
+			total++
+			totalBytes += size
+			if _, held := before[id]; !held {
+				moved++
+				movedBytes += size
+			}
+		}
+	}
+
+	if bytes && totalBytes > 0 {
+		return movedBytes / totalBytes
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	return moved / total
+}
+
+// rackDiversityObjective returns the mean fraction of replica
+// sets falling short of the maximum achievable rack spread.
+func rackDiversityObjective(pm *kafkazk.PartitionMap, bmm kafkazk.BrokerMetaMap) float64 {
+	racks := knownRacks(pm, bmm)
+	if racks < 2 {
+		return 0
+	}
+
+	var deficit float64
+	var n int
+
+	for _, p := range pm.Partitions {
+		if len(p.Replicas) == 0 {
+			continue
+		}
+
+		want := len(p.Replicas)
+		if racks < want {
+			want = racks
+		}
+
+		have := map[string]struct{}{}
+		for _, id := range p.Replicas {
+			if m, exists := bmm[id]; exists && m.Rack != "" {
+				have[m.Rack] = struct{}{}
+			}
+		}
+
+		if len(have) < want {
+			deficit += float64(want-len(have)) / float64(want)
+		}
+		n++
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	return deficit / float64(n)
+}
+
+// crossAZObjective returns the fraction of followers
+// replicating from a leader in a different rack.
+func crossAZObjective(pm *kafkazk.PartitionMap, bmm kafkazk.BrokerMetaMap) float64 {
+	if knownRacks(pm, bmm) < 2 {
+		return 0
+	}
+
+	var cross, followers float64
+
+	for _, p := range pm.Partitions {
+		if len(p.Replicas) < 2 {
+			continue
+		}
+
+		leader, exists := bmm[p.Replicas[0]]
+		if !exists || leader.Rack == "" {
+			continue
+		}
+
+		for _, id := range p.Replicas[1:] {
+			followers++
+			if m, exists := bmm[id]; !exists || m.Rack != leader.Rack {
+				cross++
+			}
+		}
+	}
+
+	if followers == 0 {
+		return 0
+	}
+
+	return cross / followers
+}
+
+// knownRacks returns the number of distinct racks
+// among brokers referenced in the PartitionMap.
+func knownRacks(pm *kafkazk.PartitionMap, bmm kafkazk.BrokerMetaMap) int {
+	racks := map[string]struct{}{}
+	for _, p := range pm.Partitions {
+		for _, id := range p.Replicas {
+			if m, exists := bmm[id]; exists && m.Rack != "" {
+				racks[m.Rack] = struct{}{}
+			}
+		}
+	}
+
+	return len(racks)
+}
+
+// replicaSet returns the replica set r as a set.
+func replicaSet(r []int) map[int]struct{} {
+	s := map[int]struct{}{}
+	for _, id := range r {
+		s[id] = struct{}{}
+	}
+	return s
+}
+
+// meanStdDev returns the mean and
+// standard deviation of vals.
+func meanStdDev(vals []float64) (float64, float64) {
+	if len(vals) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	mean := sum / float64(len(vals))
+
+	var sq float64
+	for _, v := range vals {
+		sq += (v - mean) * (v - mean)
+	}
+
+	return mean, math.Sqrt(sq / float64(len(vals)))
+}
+
+// SelectBest takes an input PartitionMap, a list of candidate output maps,
+// a BrokerMetaMap, a PartitionMetaMap and ObjectiveWeights. Each candidate
+// is evaluated (see Evaluate) and the index of the candidate with the lowest
+// weighted total cost is returned along with the Objectives of all
+// candidates. Ties are resolved in favor of the earliest candidate.
+func SelectBest(pm *kafkazk.PartitionMap, candidates []*kafkazk.PartitionMap, bmm kafkazk.BrokerMetaMap, pmm kafkazk.PartitionMetaMap, w ObjectiveWeights) (int, []Objectives) {
+	best := -1
+	objectives := make([]Objectives, len(candidates))
+
+	for i, c := range candidates {
+		objectives[i] = Evaluate(pm, c, bmm, pmm)
+		if best < 0 || objectives[i].Weighted(w).Total() < objectives[best].Weighted(w).Total() {
+			best = i
+		}
+	}
+
+	return best, objectives
+}
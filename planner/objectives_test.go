@@ -0,0 +1,87 @@
+package planner
+
+import (
+	"math"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+func TestEvaluate(t *testing.T) {
+	pm := testPartitionMap()
+
+	// Move p0 from 1001 to 1005.
+	pm2 := pm.Copy()
+	pm2.Partitions[0].Replicas = []int{1005, 1002}
+
+	o := Evaluate(pm, pm2, testBrokerMeta(), testPartitionMeta())
+
+	expected := Objectives{
+		// Free storage of 150, 500, 500, 500 and 450GB.
+		Storage: 0.3247,
+		// 1003 leads no partitions.
+		Leadership: 1.25,
+		// 50 of 240GB moved.
+		Movement: 0.2083,
+		// All followers are cross-rack.
+		CrossAZ: 1.00,
+	}
+
+	for _, v := range [][2]float64{
+		{o.Storage, expected.Storage},
+		{o.Leadership, expected.Leadership},
+		{o.Movement, expected.Movement},
+		{o.RackDiversity, expected.RackDiversity},
+		{o.CrossAZ, expected.CrossAZ},
+	} {
+		if math.Abs(v[0]-v[1]) > 0.0001 {
+			t.Errorf("Expected objectives %+v, got %+v", expected, o)
+			break
+		}
+	}
+
+	// With known capacities, utilization is compared;
+	// each broker is left at 50% free storage.
+	bmm := testBrokerMeta()
+	for id, free := range map[int]float64{1001: 150, 1002: 500, 1003: 500, 1004: 500, 1005: 450} {
+		bmm[id].StorageCapacity = free * 2 * div
+	}
+
+	if o := Evaluate(pm, pm2, bmm, testPartitionMeta()); math.Abs(o.Storage) > 0.0001 {
+		t.Errorf("Expected storage cost of 0 with known capacities, got %f", o.Storage)
+	}
+
+	// Without metrics, movement is measured in replicas.
+	o = Evaluate(pm, pm2, testBrokerMeta(), nil)
+	if o.Storage != 0 || o.Movement != 0.125 {
+		t.Errorf("Unexpected objectives without metrics: %+v", o)
+	}
+
+	// Placing p0 in a single rack.
+	pm2.Partitions[0].Replicas = []int{1001, 1005}
+	o = Evaluate(pm, pm2, testBrokerMeta(), nil)
+	if o.RackDiversity != 0.125 {
+		t.Errorf("Expected rack diversity cost of 0.125, got %f", o.RackDiversity)
+	}
+}
+
+func TestSelectBest(t *testing.T) {
+	pm := testPartitionMap()
+
+	moved := pm.Copy()
+	moved.Partitions[0].Replicas = []int{1005, 1002}
+
+	candidates := []*kafkazk.PartitionMap{moved, pm.Copy()}
+
+	// Storage favors offloading 1001.
+	best, objectives := SelectBest(pm, candidates, testBrokerMeta(), testPartitionMeta(), ObjectiveWeights{Storage: 1})
+	if best != 0 || len(objectives) != 2 {
+		t.Errorf("Expected candidate 0, got %d", best)
+	}
+
+	// Movement favors the no-op.
+	best, _ = SelectBest(pm, candidates, testBrokerMeta(), testPartitionMeta(), ObjectiveWeights{Storage: 1, Movement: 2})
+	if best != 1 {
+		t.Errorf("Expected candidate 1, got %d", best)
+	}
+}
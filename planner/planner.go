@@ -0,0 +1,125 @@
+// Package planner exposes the topicmappr partition placement engine for
+// programmatic use. Given a current PartitionMap, broker metadata and
+// partition size metrics, the planner produces a Plan: the resulting
+// PartitionMap, the before and after broker states and summary Stats.
+//
+// Two operations are provided:
+//   - Rebuild maps partitions from replaced brokers onto a set of target
+//     brokers using the count or storage placement strategies.
+//   - Rebalance incrementally relocates partitions off of brokers with
+//     less free storage than their peers.
+//
+// All inputs are treated as read-only; the planner operates on copies.
+package planner
+
+import (
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// Plan is the output of a planning operation.
+type Plan struct {
+	// The input and planned PartitionMap. Partitions
+	// are ordered identically in both.
+	Input  *kafkazk.PartitionMap
+	Output *kafkazk.PartitionMap
+	// Broker states before and after the plan. Storage
+	// values are estimations if partition metrics were
+	// provided.
+	BrokersBefore kafkazk.BrokerMap
+	BrokersAfter  kafkazk.BrokerMap
+	Stats         Stats
+	// Non-fatal issues encountered while planning,
+	// such as unsatisfiable placement constraints.
+	Warnings []error
+
+	// The following are only populated by Rebuild.
+
+	// Broker change counts and a description
+	// of each change to the target brokers.
+	BrokerStatus  *kafkazk.BrokerStatus
+	BrokerChanges []string
+	// Substitution affinities, if enabled.
+	Affinities kafkazk.SubstitutionAffinities
+	// The candidates evaluated, if ObjectiveWeights were set.
+	Candidates []Candidate
+	// The number of partitions changed by each hook.
+	HookChanges []int
+	// Partitions with out of sync replicas. These are
+	// omitted from the Input if they were excluded.
+	OutOfSync kafkazk.OutOfSyncPartitions
+	// Changed partitions that could fall
+	// below min.insync.replicas.
+	MinISRViolations []kafkazk.MinISRViolation
+}
+
+// Changes returns the input and output PartitionMap
+// with all unchanged partitions removed.
+func (p *Plan) Changes() (*kafkazk.PartitionMap, *kafkazk.PartitionMap) {
+	in, out := kafkazk.NewPartitionMap(), kafkazk.NewPartitionMap()
+
+	for i := range p.Input.Partitions {
+		p1, p2 := p.Input.Partitions[i], p.Output.Partitions[i]
+		if !p1.Equal(p2) {
+			in.Partitions = append(in.Partitions, p1)
+			out.Partitions = append(out.Partitions, p2)
+		}
+	}
+
+	return in, out
+}
+
+// Stats summarizes a Plan.
+type Stats struct {
+	Partitions      int
+	PartitionsMoved int
+	ReplicasMoved   int
+	// Storage stats; only populated if
+	// partition metrics were provided.
+	BytesMoved          float64
+	StorageRangeBefore  float64
+	StorageRangeAfter   float64
+	StorageStdDevBefore float64
+	StorageStdDevAfter  float64
+}
+
+// NewStats takes the input and output PartitionMap, a PartitionMetaMap and
+// the before and after BrokerMap and returns Stats. Storage stats are
+// omitted if the PartitionMetaMap is nil.
+func NewStats(pm1, pm2 *kafkazk.PartitionMap, pmm kafkazk.PartitionMetaMap, bm1, bm2 kafkazk.BrokerMap) Stats {
+	s := Stats{Partitions: len(pm1.Partitions)}
+
+	for i := range pm1.Partitions {
+		p1, p2 := pm1.Partitions[i], pm2.Partitions[i]
+		if p1.Equal(p2) {
+			continue
+		}
+
+		s.PartitionsMoved++
+
+		existing := map[int]struct{}{}
+		for _, id := range p1.Replicas {
+			existing[id] = struct{}{}
+		}
+
+		for _, id := range p2.Replicas {
+			if _, exists := existing[id]; !exists {
+				s.ReplicasMoved++
+			}
+		}
+	}
+
+	if pmm == nil {
+		return s
+	}
+
+	if incoming, err := pm1.IncomingStorage(pm2, pmm); err == nil {
+		for _, v := range incoming {
+			s.BytesMoved += v
+		}
+	}
+
+	s.StorageRangeBefore, s.StorageRangeAfter = bm1.StorageRange(), bm2.StorageRange()
+	s.StorageStdDevBefore, s.StorageStdDevAfter = bm1.StorageStdDev(), bm2.StorageStdDev()
+
+	return s
+}
@@ -0,0 +1,568 @@
+package planner
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+func testPartitionMap() *kafkazk.PartitionMap {
+	pm, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test","partition":0,"replicas":[1001,1002]},
+    {"topic":"test","partition":1,"replicas":[1001,1003]},
+    {"topic":"test","partition":2,"replicas":[1002,1003]},
+    {"topic":"test","partition":3,"replicas":[1004,1002]}]}`)
+
+	return pm
+}
+
+func testPartitionMeta() kafkazk.PartitionMetaMap {
+	pmm := kafkazk.NewPartitionMetaMap()
+	pmm["test"] = map[int]*kafkazk.PartitionMeta{
+		0: &kafkazk.PartitionMeta{Size: 50 * div},
+		1: &kafkazk.PartitionMeta{Size: 50 * div},
+		2: &kafkazk.PartitionMeta{Size: 10 * div},
+		3: &kafkazk.PartitionMeta{Size: 10 * div},
+	}
+
+	return pmm
+}
+
+func testBrokerMeta() kafkazk.BrokerMetaMap {
+	return kafkazk.BrokerMetaMap{
+		1001: &kafkazk.BrokerMeta{Rack: "a", StorageFree: 100 * div},
+		1002: &kafkazk.BrokerMeta{Rack: "b", StorageFree: 500 * div},
+		1003: &kafkazk.BrokerMeta{Rack: "c", StorageFree: 500 * div},
+		1004: &kafkazk.BrokerMeta{Rack: "d", StorageFree: 500 * div},
+		1005: &kafkazk.BrokerMeta{Rack: "a", StorageFree: 500 * div},
+	}
+}
+
+func TestRebalance(t *testing.T) {
+	pm := testPartitionMap()
+	brokers := kafkazk.BrokerMapFromPartitionMap(pm, testBrokerMeta(), false)
+
+	params := RebalanceParams{
+		PartitionMap:     pm,
+		Brokers:          brokers,
+		PartitionMeta:    testPartitionMeta(),
+		StorageThreshold: 0.20,
+		PartitionLimit:   30,
+	}
+
+	results := Rebalance(params)
+	if len(results) == 0 {
+		t.Fatal("Expected rebalance results")
+	}
+
+	r := results[0]
+
+	if len(r.OffloadTargets) != 1 || r.OffloadTargets[0] != 1001 {
+		t.Errorf("Expected offload targets [1001], got %v", r.OffloadTargets)
+	}
+
+	if len(r.Relocations[1001]) == 0 {
+		t.Error("Expected relocations from broker 1001")
+	}
+
+	if r.StorageRange >= brokers.StorageRange() {
+		t.Errorf("Expected storage range to decrease from %.2f, got %.2f",
+			brokers.StorageRange()/div, r.StorageRange/div)
+	}
+
+	// Results are ordered best first.
+	for i := 1; i < len(results); i++ {
+		if results[i].StorageRange < results[0].StorageRange {
+			t.Errorf("Unexpected result order")
+		}
+	}
+
+	// Inputs must be unmodified.
+	if pm.Partitions[0].Replicas[0] != 1001 || brokers[1001].StorageFree != 100*div {
+		t.Error("Unexpected modification of inputs")
+	}
+
+	plan := r.Plan(params)
+	if plan.Stats.PartitionsMoved != len(r.Relocations[1001]) {
+		t.Errorf("Expected %d partitions moved, got %d",
+			len(r.Relocations[1001]), plan.Stats.PartitionsMoved)
+	}
+
+	// A fixed tolerance yields a single result.
+	params.Tolerance = 0.10
+	if results := Rebalance(params); len(results) != 1 || results[0].Tolerance != 0.10 {
+		t.Errorf("Expected a single result with a tolerance of 0.10")
+	}
+}
+
+func TestRebalanceCapacityAware(t *testing.T) {
+	pm := testPartitionMap()
+	bmm := testBrokerMeta()
+
+	// 1001 has the least free storage but the lowest
+	// utilization; 1002 is the most utilized.
+	capacities := map[int]float64{1001: 200, 1002: 5000, 1003: 1000, 1004: 1000, 1005: 1000}
+	for id, m := range bmm {
+		m.StorageCapacity = capacities[id] * div
+	}
+
+	brokers := kafkazk.BrokerMapFromPartitionMap(pm, bmm, false)
+
+	if targets := SelectOffloadTargets(brokers, 0.20, 0); len(targets) != 1 || targets[0] != 1002 {
+		t.Fatalf("Expected offload targets [1002], got %v", targets)
+	}
+
+	params := RebalanceParams{
+		PartitionMap:     pm,
+		Brokers:          brokers,
+		PartitionMeta:    testPartitionMeta(),
+		StorageThreshold: 0.20,
+		PartitionLimit:   30,
+	}
+
+	results := Rebalance(params)
+	if len(results) == 0 {
+		t.Fatal("Expected rebalance results")
+	}
+
+	r := results[0]
+
+	if len(r.Relocations[1002]) == 0 {
+		t.Error("Expected relocations from broker 1002")
+	}
+
+	if r.Brokers.StorageFreeRatioRange() >= brokers.StorageFreeRatioRange() {
+		t.Errorf("Expected utilization range to decrease from %.2f, got %.2f",
+			brokers.StorageFreeRatioRange(), r.Brokers.StorageFreeRatioRange())
+	}
+}
+
+func TestRebalanceWithLeaderPins(t *testing.T) {
+	pm := testPartitionMap()
+	brokers := kafkazk.BrokerMapFromPartitionMap(pm, testBrokerMeta(), false)
+	brokers.Update([]int{1001, 1002, 1003, 1004, 1005}, testBrokerMeta())
+
+	params := RebalanceParams{
+		PartitionMap:   pm,
+		Brokers:        brokers,
+		PartitionMeta:  testPartitionMeta(),
+		OffloadTargets: []int{1001},
+		PartitionLimit: 30,
+		LeaderPins: kafkazk.LeaderPins{
+			"test": map[int]kafkazk.LeaderPin{
+				0: kafkazk.LeaderPin{Broker: 1001},
+				1: kafkazk.LeaderPin{Rack: "a"},
+			},
+		},
+	}
+
+	results := Rebalance(params)
+	if len(results) == 0 {
+		t.Fatal("Expected rebalance results")
+	}
+
+	for _, r := range results {
+		out := r.PartitionMap
+
+		// p0 may not be relocated, p1 only within rack a.
+		if l := out.Partitions[0].Replicas[0]; l != 1001 {
+			t.Errorf("[tolerance %.2f] Expected test p0 leader 1001, got %d", r.Tolerance, l)
+		}
+
+		if l := out.Partitions[1].Replicas[0]; l != 1001 && l != 1005 {
+			t.Errorf("[tolerance %.2f] Expected test p1 leader in rack a, got %d", r.Tolerance, l)
+		}
+
+		if w := r.Plan(params).Warnings; len(w) != 0 {
+			t.Errorf("[tolerance %.2f] Unexpected warnings: %v", r.Tolerance, w)
+		}
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	params := RebuildParams{
+		PartitionMap:  testPartitionMap(),
+		BrokerMeta:    testBrokerMeta(),
+		PartitionMeta: testPartitionMeta(),
+		// Replace 1001.
+		Brokers: []int{1002, 1003, 1004, 1005},
+	}
+
+	plan, err := Rebuild(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range plan.Output.Partitions {
+		for _, id := range p.Replicas {
+			if id == 1001 {
+				t.Errorf("Unexpected replica on replaced broker 1001: %s p%d", p.Topic, p.Partition)
+			}
+		}
+	}
+
+	if plan.Stats.PartitionsMoved != 2 || plan.Stats.ReplicasMoved != 2 {
+		t.Errorf("Expected 2 partitions and replicas moved, got %d, %d",
+			plan.Stats.PartitionsMoved, plan.Stats.ReplicasMoved)
+	}
+
+	if plan.Stats.BytesMoved != 100*div {
+		t.Errorf("Expected 100GB moved, got %.2fGB", plan.Stats.BytesMoved/div)
+	}
+
+	in, out := plan.Changes()
+	if len(in.Partitions) != 2 || len(out.Partitions) != 2 {
+		t.Errorf("Expected 2 changed partitions, got %d", len(out.Partitions))
+	}
+
+	// Storage placement.
+	params.Strategy = "storage"
+	plan, err = Rebuild(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Warnings) != 0 {
+		t.Errorf("Unexpected warnings: %v", plan.Warnings)
+	}
+
+	// Topic constraints.
+	params.TopicConstraints = kafkazk.TopicConstraintsMap{
+		"test": kafkazk.TopicConstraints{ExcludedBrokers: []int{1002}},
+	}
+
+	plan, err = Rebuild(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Warnings) != 0 {
+		t.Errorf("Unexpected warnings: %v", plan.Warnings)
+	}
+
+	for _, p := range plan.Output.Partitions {
+		for _, id := range p.Replicas {
+			if id == 1002 {
+				t.Errorf("Unexpected replica on excluded broker 1002: %s p%d", p.Topic, p.Partition)
+			}
+		}
+	}
+	params.TopicConstraints = nil
+
+	// Leader pins. 1005 is the only
+	// remaining broker in rack a.
+	params.LeaderPins = kafkazk.LeaderPins{
+		"test": map[int]kafkazk.LeaderPin{
+			0: kafkazk.LeaderPin{Rack: "a"},
+			3: kafkazk.LeaderPin{Broker: 1002},
+		},
+	}
+
+	plan, err = Rebuild(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Warnings) != 0 {
+		t.Errorf("Unexpected warnings: %v", plan.Warnings)
+	}
+
+	for p, leader := range map[int]int{0: 1005, 3: 1002} {
+		if l := plan.Output.Partitions[p].Replicas[0]; l != leader {
+			t.Errorf("Expected test p%d leader %d, got %d", p, leader, l)
+		}
+	}
+	params.LeaderPins = nil
+
+	// Weighted storage and count placement.
+	params.CountWeight = 0.50
+	if _, err := Rebuild(params); err != nil {
+		t.Fatal(err)
+	}
+
+	params.CountWeight = 1.50
+	if _, err := Rebuild(params); err == nil {
+		t.Error("Expected error for invalid count weight")
+	}
+	params.CountWeight = 0
+
+	params.PartitionMeta = nil
+	if _, err := Rebuild(params); err == nil {
+		t.Error("Expected error for storage placement without partition metrics")
+	}
+
+	params.Strategy = "random"
+	if _, err := Rebuild(params); err == nil {
+		t.Error("Expected error for invalid strategy")
+	}
+}
+
+func TestRebuildMaintenance(t *testing.T) {
+	params := RebuildParams{
+		PartitionMap: testPartitionMap(),
+		BrokerMeta:   testBrokerMeta(),
+		// Replace 1001; 1002 and 1005 are in
+		// maintenance, leaving 1003 and 1004.
+		Brokers:     []int{1002, 1003, 1004, 1005},
+		Maintenance: []int{1002, 1005},
+	}
+
+	plan, err := Rebuild(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, p := range plan.Output.Partitions {
+		before := params.PartitionMap.Partitions[i].Replicas
+
+		for j, id := range p.Replicas {
+			switch {
+			case id == 1005:
+				t.Errorf("Unexpected replica on broker 1005 in maintenance: %s p%d", p.Topic, p.Partition)
+			case id == 1002 && before[j] != 1002:
+				t.Errorf("Unexpected new replica on broker 1002 in maintenance: %s p%d", p.Topic, p.Partition)
+			case before[j] == 1002 && id != 1002:
+				t.Errorf("Expected replica on broker 1002 to remain: %s p%d", p.Topic, p.Partition)
+			}
+		}
+	}
+
+	if !plan.BrokersAfter[1002].Maintenance {
+		t.Error("Expected broker 1002 flagged in maintenance")
+	}
+}
+
+func TestRebuildObjectiveWeights(t *testing.T) {
+	params := RebuildParams{
+		PartitionMap:  testPartitionMap(),
+		BrokerMeta:    testBrokerMeta(),
+		PartitionMeta: testPartitionMeta(),
+		// Replace 1001.
+		Brokers:          []int{1002, 1003, 1004, 1005},
+		ObjectiveWeights: &ObjectiveWeights{Storage: 1, Movement: 1},
+	}
+
+	plan, err := Rebuild(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both strategies, with and
+	// without leadership optimization.
+	if len(plan.Candidates) != 4 {
+		t.Fatalf("Expected 4 candidates, got %d", len(plan.Candidates))
+	}
+
+	var selected []Candidate
+	for _, c := range plan.Candidates {
+		if c.Selected {
+			selected = append(selected, c)
+		}
+	}
+
+	if len(selected) != 1 {
+		t.Fatalf("Expected 1 selected candidate, got %d", len(selected))
+	}
+
+	cost := selected[0].Objectives.Weighted(*params.ObjectiveWeights).Total()
+	for _, c := range plan.Candidates {
+		if c.Objectives.Weighted(*params.ObjectiveWeights).Total() < cost {
+			t.Errorf("Candidate %s has a lower cost than the selected %s", c, selected[0])
+		}
+	}
+
+	// The count strategy only
+	// without partition metrics.
+	params.PartitionMeta = nil
+	params.Strategy = "storage"
+
+	plan, err = Rebuild(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Candidates) != 2 {
+		t.Errorf("Expected 2 candidates, got %d", len(plan.Candidates))
+	}
+}
+
+func TestRebuildObservers(t *testing.T) {
+	params := RebuildParams{
+		PartitionMap:       testPartitionMap(),
+		BrokerMeta:         testBrokerMeta(),
+		Brokers:            []int{1002, 1003, 1004, 1005},
+		OptimizeLeadership: true,
+		Observers:          1,
+	}
+
+	plan, err := Rebuild(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Observers aren't considered for leadership;
+	// the trailing replicas of retained sets are
+	// unchanged.
+	for i, p := range plan.Output.Partitions {
+		before := params.PartitionMap.Partitions[i].Replicas
+		if before[1] != 1001 && p.Replicas[1] != before[1] {
+			t.Errorf("Expected observer %d retained in %s p%d, got %v",
+				before[1], p.Topic, p.Partition, p.Replicas)
+		}
+	}
+
+	params.Observers = -1
+	if _, err := Rebuild(params); err == nil {
+		t.Error("Expected error for invalid observer count")
+	}
+
+	params.Observers, params.ObserverRack = 0, "a"
+	if _, err := Rebuild(params); err == nil {
+		t.Error("Expected error for observer rack without observers")
+	}
+}
+
+func TestRebuildISR(t *testing.T) {
+	// All partitions in the testPartitionMap have
+	// replicas outside of the kafkazk.Mock ISR.
+	params := RebuildParams{
+		PartitionMap: testPartitionMap(),
+		BrokerMeta:   testBrokerMeta(),
+		// Replace 1001.
+		Brokers: []int{1002, 1003, 1004, 1005},
+		ZK:      &kafkazk.Mock{},
+	}
+
+	plan, err := Rebuild(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.OutOfSync) != 4 {
+		t.Errorf("Expected 4 out of sync partitions, got %d", len(plan.OutOfSync))
+	}
+
+	if len(plan.Warnings) != 4 {
+		t.Errorf("Expected 4 warnings, got %v", plan.Warnings)
+	}
+
+	params.OutOfSync = "exclude"
+	if _, err := Rebuild(params); err == nil {
+		t.Error("Expected error with all partitions excluded")
+	} else if e, ok := err.(OutOfSyncError); !ok || len(e.Partitions) != 4 {
+		t.Errorf("Expected OutOfSyncError with 4 partitions, got %v", err)
+	}
+	params.OutOfSync = ""
+
+	// Replacing 1001 leaves test p0 with a single
+	// in-sync replica, 1002, and test p1 with none.
+	params.DefaultMinISR = 2
+
+	plan, err = Rebuild(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.MinISRViolations) == 0 {
+		t.Error("Expected min.insync.replicas violations")
+	}
+
+	params.MinISRCheck = "block"
+	if _, err := Rebuild(params); err == nil {
+		t.Error("Expected error for blocked min.insync.replicas violations")
+	} else if _, ok := err.(MinISRError); !ok {
+		t.Errorf("Expected MinISRError, got %v", err)
+	}
+
+	params.MinISRCheck = "ignore"
+	plan, err = Rebuild(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.MinISRViolations) != 0 {
+		t.Errorf("Unexpected min.insync.replicas violations: %v", plan.MinISRViolations)
+	}
+}
+
+func TestRebalanceMaintenance(t *testing.T) {
+	pm := testPartitionMap()
+	brokers := kafkazk.BrokerMapFromPartitionMap(pm, testBrokerMeta(), false)
+
+	// Include 1005 as a destination,
+	// but in maintenance.
+	brokers.Update([]int{-1, 1005}, testBrokerMeta())
+	brokers.SetMaintenance([]int{1005})
+
+	params := RebalanceParams{
+		PartitionMap:     pm,
+		Brokers:          brokers,
+		PartitionMeta:    testPartitionMeta(),
+		StorageThreshold: 0.20,
+		PartitionLimit:   30,
+	}
+
+	for _, r := range Rebalance(params) {
+		for _, relos := range r.Relocations {
+			for _, relo := range relos {
+				if relo.Destination == 1005 {
+					t.Errorf("Unexpected relocation to broker 1005 in maintenance: %v", relo)
+				}
+			}
+		}
+	}
+
+	// Brokers in maintenance may be offload targets.
+	brokers.SetMaintenance([]int{1001})
+
+	results := Rebalance(params)
+	if len(results) == 0 || len(results[0].Relocations[1001]) == 0 {
+		t.Error("Expected relocations from broker 1001 in maintenance")
+	}
+}
+
+func BenchmarkRebalance(b *testing.B) {
+	pm := kafkazk.NewPartitionMap()
+	pmm := kafkazk.NewPartitionMetaMap()
+	bmm := kafkazk.BrokerMetaMap{}
+
+	// 200 brokers in 5 racks, where 1 in 10
+	// have half the storage free of the others.
+	for id := 1000; id < 1200; id++ {
+		free := 20000.00 * div
+		if id%10 == 0 {
+			free /= 2
+		}
+		bmm[id] = &kafkazk.BrokerMeta{Rack: fmt.Sprintf("rack%d", id%5), StorageFree: free}
+	}
+
+	// 20,000 partitions with a replication
+	// factor of 3 spanning 3 racks.
+	for t := 0; t < 20; t++ {
+		topic := fmt.Sprintf("topic%d", t)
+		pmm[topic] = map[int]*kafkazk.PartitionMeta{}
+
+		for p := 0; p < 1000; p++ {
+			i := t*1000 + p
+			replicas := []int{1000 + i%200, 1000 + (i+41)%200, 1000 + (i+82)%200}
+
+			pm.Partitions = append(pm.Partitions, kafkazk.Partition{Topic: topic, Partition: p, Replicas: replicas})
+			pmm[topic][p] = &kafkazk.PartitionMeta{Size: float64(1+i%100) * div}
+		}
+	}
+
+	params := RebalanceParams{
+		PartitionMap:     pm,
+		Brokers:          kafkazk.BrokerMapFromPartitionMap(pm, bmm, false),
+		PartitionMeta:    pmm,
+		StorageThreshold: 0.20,
+		PartitionLimit:   30,
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Rebalance(params)
+	}
+}
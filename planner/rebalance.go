@@ -0,0 +1,792 @@
+package planner
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+const (
+	indent = "\x20\x20"
+	div    = 1 << 30
+)
+
+// RebalanceParams holds the inputs for a Rebalance.
+type RebalanceParams struct {
+	// The current assignments to rebalance.
+	PartitionMap *kafkazk.PartitionMap
+	// The current brokers, with StorageFree values populated.
+	// Brokers not holding partitions in the PartitionMap may be
+	// included as relocation destinations. Brokers in maintenance
+	// are never destinations but may be offload targets.
+	Brokers kafkazk.BrokerMap
+	// Partition size metrics.
+	PartitionMeta kafkazk.PartitionMetaMap
+	// Brokers to offload partitions from. If empty, targets are
+	// selected with SelectOffloadTargets using StorageThreshold
+	// and StorageThresholdGB.
+	OffloadTargets     []int
+	StorageThreshold   float64
+	StorageThresholdGB float64
+	// Percent distance from the mean storage free to limit storage
+	// scheduling. If 0, all values 0.01..0.99 are planned and the
+	// result with the lowest storage range is chosen.
+	Tolerance float64
+	// Limit the number of top partitions by size
+	// eligible for relocation per broker.
+	PartitionLimit int
+	// Size in megabytes where partitions
+	// below this value will not be moved.
+	PartitionSizeThreshold int
+	// Disallow relocations that traverse localities.
+	LocalityScoped bool
+	// Per-topic placement constraints; partitions are
+	// never relocated to brokers not permitted for
+	// their topic.
+	TopicConstraints kafkazk.TopicConstraintsMap
+	// Rebalance broker leader/follower ratios.
+	OptimizeLeadership bool
+	// Per-partition leader pins. Replicas required to
+	// satisfy a pin are never relocated, other than
+	// within the pinned rack; pins are applied following
+	// any leadership optimization.
+	LeaderPins kafkazk.LeaderPins
+	// If non-nil, verbose planning details are written to Log.
+	Log io.Writer
+}
+
+// Relocation describes a partition replica planned
+// to move to the destination broker.
+type Relocation struct {
+	Partition   kafkazk.Partition
+	Destination int
+}
+
+// RebalanceResult is the output of a rebalance
+// planned with a specific tolerance.
+type RebalanceResult struct {
+	StorageRange float64
+	StdDev       float64
+	Tolerance    float64
+	PartitionMap *kafkazk.PartitionMap
+	// Offload targets in the order planned
+	// (by storage free ascending).
+	OffloadTargets []int
+	// Planned relocations by source broker ID.
+	Relocations map[int][]Relocation
+	Brokers     kafkazk.BrokerMap
+}
+
+// SelectOffloadTargets returns the IDs of brokers in the BrokerMap targeted
+// for partition offloading. If the threshold in gigabytes stg is non-zero,
+// brokers with less storage free are selected. Otherwise, brokers with a
+// storage free st percent below the harmonic mean are selected; an st of 0
+// selects all brokers. If the StorageCapacity of all brokers is known, the
+// StorageFreeRatio is compared in place of the storage free. New brokers are
+// never selected.
+func SelectOffloadTargets(brokers kafkazk.BrokerMap, st, stg float64) []int {
+	var offloadTargets []int
+
+	// Switch on the target selection method. If
+	// a storage threshold in gigabytes is specified,
+	// prefer this. Otherwise, use the percentage below
+	// mean threshold.
+	switch {
+	case stg > 0.00:
+		// Get all non-new brokers with a StorageFree
+		// below the storage threshold in GB.
+		f := func(b *kafkazk.Broker) bool {
+			if !b.New && b.StorageFree < stg*div {
+				return true
+			}
+			return false
+		}
+
+		matches := brokers.Filter(f)
+		for _, b := range matches {
+			offloadTargets = append(offloadTargets, b.ID)
+		}
+
+		sort.Ints(offloadTargets)
+	default:
+		// Find brokers where the storage free is t %
+		// below the harmonic mean. Specifying 0 targets
+		// all non-new brokers.
+		switch st {
+		case 0.00:
+			f := func(b *kafkazk.Broker) bool { return !b.New }
+
+			matches := brokers.Filter(f)
+			for _, b := range matches {
+				offloadTargets = append(offloadTargets, b.ID)
+			}
+
+			sort.Ints(offloadTargets)
+		default:
+			if brokers.CapacityKnown() {
+				offloadTargets = belowMeanRatio(brokers, st)
+			} else {
+				offloadTargets = brokers.BelowMean(st, brokers.HMean)
+			}
+		}
+	}
+
+	return offloadTargets
+}
+
+// belowMeanRatio returns a sorted []int of broker IDs with a StorageFreeRatio
+// d percent below the harmonic mean StorageFreeRatio.
+func belowMeanRatio(brokers kafkazk.BrokerMap, d float64) []int {
+	m := brokers.StorageFreeRatioHMean()
+	var ids []int
+
+	for _, b := range brokers {
+		if b.ID == kafkazk.StubBrokerID || b.New {
+			continue
+		}
+
+		if (m-b.StorageFreeRatio())/m > d {
+			ids = append(ids, b.ID)
+		}
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+// Rebalance takes RebalanceParams and returns a RebalanceResult for each
+// tolerance planned (or only the Tolerance value if set), ordered by storage
+// range and std. deviation ascending; the first result is the best. An
+// empty list is returned if there are no offload targets. If the
+// StorageCapacity of all brokers is known, storage is balanced by
+// StorageFreeRatio (equal utilization) rather than by free bytes and
+// results are ordered by the range of ratios.
+func Rebalance(params RebalanceParams) []RebalanceResult {
+	offloadTargets := params.OffloadTargets
+	if len(offloadTargets) == 0 {
+		offloadTargets = SelectOffloadTargets(params.Brokers, params.StorageThreshold, params.StorageThresholdGB)
+	}
+
+	if len(offloadTargets) == 0 {
+		return []RebalanceResult{}
+	}
+
+	// Sort offloadTargets by storage free ascending.
+	offloadTargets = append([]int{}, offloadTargets...)
+	sort.Sort(offloadTargetsBySize{t: offloadTargets, bm: params.Brokers})
+
+	capacityAware := params.Brokers.CapacityKnown()
+
+	otm := map[int]struct{}{}
+	for _, id := range offloadTargets {
+		otm[id] = struct{}{}
+	}
+
+	// Partitions held by each offload target, sorted
+	// by size once and shared by all planning runs.
+	partitions := newSizeSortedPartitions(params.PartitionMap, params.PartitionMeta, offloadTargets)
+
+	results := make(chan RebalanceResult, 100)
+	wg := &sync.WaitGroup{}
+
+	// Limit the number of concurrent planning runs.
+	sem := make(chan struct{}, runtime.NumCPU())
+
+	// Compute a RebalanceResult output for all tolerance
+	// values 0.01..0.99 in parallel.
+	for i := 0.01; i < 0.99; i += 0.01 {
+		// Whether we're using a fixed tolerance
+		// (non 0.00) or an iterative value.
+		var tol float64
+
+		if params.Tolerance == 0.00 {
+			tol = i
+		} else {
+			tol = params.Tolerance
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+
+			// The brokers ordered by storage; updated
+			// as relocations are planned.
+			brokers := params.Brokers.Copy()
+			candidates, _ := kafkazk.NewBrokerCandidates(brokers.List(), kafkazk.ConstraintsParams{SelectorMethod: "storage"})
+
+			// Bundle planRelocationsForBrokerParams.
+			p := planRelocationsForBrokerParams{
+				relos:                  map[int][]Relocation{},
+				partitions:             partitions,
+				relocated:              relocatedPartitions{},
+				brokers:                brokers,
+				candidates:             candidates,
+				partitionMeta:          params.PartitionMeta,
+				plan:                   relocationPlan{},
+				topPartitionsLimit:     params.PartitionLimit,
+				partitionSizeThreshold: params.PartitionSizeThreshold,
+				offloadTargetsMap:      otm,
+				tolerance:              tol,
+				localityScoped:         params.LocalityScoped,
+				capacityAware:          capacityAware,
+				topicConstraints:       params.TopicConstraints,
+				leaderPins:             params.LeaderPins,
+				log:                    params.Log,
+			}
+
+			// Iterate over offload targets, planning
+			// at most one relocation per iteration.
+			// Continue this loop until no more relocations
+			// can be planned.
+			for exhaustedCount := 0; exhaustedCount < len(offloadTargets); {
+				p.pass++
+				for _, sourceID := range offloadTargets {
+					// Update the source broker ID
+					p.sourceID = sourceID
+
+					relos := planRelocationsForBroker(p)
+
+					// If no relocations could be planned,
+					// increment the exhaustion counter.
+					if relos == 0 {
+						exhaustedCount++
+					}
+				}
+			}
+
+			// Update a copy of the partition
+			// map with the relocation plan.
+			partitionMap := params.PartitionMap.Copy()
+			applyRelocationPlan(partitionMap, p.plan)
+
+			// Optimize leaders.
+			if params.OptimizeLeadership {
+				partitionMap.OptimizeLeaderFollower()
+			}
+
+			// Apply leader pins.
+			params.LeaderPins.Apply(partitionMap, p.brokers)
+
+			// Insert the RebalanceResult.
+			results <- RebalanceResult{
+				StorageRange:   p.brokers.StorageRange(),
+				StdDev:         p.brokers.StorageStdDev(),
+				Tolerance:      tol,
+				PartitionMap:   partitionMap,
+				OffloadTargets: offloadTargets,
+				Relocations:    p.relos,
+				Brokers:        p.brokers,
+			}
+
+		}()
+
+		// Break early if we're using a fixed tolerance value.
+		if params.Tolerance != 0.00 {
+			break
+		}
+	}
+
+	wg.Wait()
+	close(results)
+
+	// Merge all results into a slice.
+	resultsByRange := []RebalanceResult{}
+	for r := range results {
+		resultsByRange = append(resultsByRange, r)
+	}
+
+	// Sort the rebalance results by range ascending.
+	spread := func(r RebalanceResult) (float64, float64) {
+		if capacityAware {
+			return r.Brokers.StorageFreeRatioRange(), r.Brokers.StorageFreeRatioStdDev()
+		}
+		return r.StorageRange, r.StdDev
+	}
+
+	sort.Slice(resultsByRange, func(i, j int) bool {
+		r1, sd1 := spread(resultsByRange[i])
+		r2, sd2 := spread(resultsByRange[j])
+
+		switch {
+		case r1 < r2:
+			return true
+		case r1 > r2:
+			return false
+		}
+
+		return sd1 < sd2
+	})
+
+	return resultsByRange
+}
+
+// Plan returns the RebalanceResult as a Plan. The input PartitionMap
+// and BrokerMap are those provided in the RebalanceParams. Partitions
+// violating any TopicConstraints or LeaderPins are included as warnings.
+func (r RebalanceResult) Plan(params RebalanceParams) *Plan {
+	return &Plan{
+		Input:         params.PartitionMap,
+		Output:        r.PartitionMap,
+		BrokersBefore: params.Brokers,
+		BrokersAfter:  r.Brokers,
+		Stats:         NewStats(params.PartitionMap, r.PartitionMap, params.PartitionMeta, params.Brokers, r.Brokers),
+		Warnings:      append(params.TopicConstraints.Check(r.PartitionMap), params.LeaderPins.Check(r.PartitionMap, r.Brokers)...),
+	}
+}
+
+// Sort offload targets by size.
+type offloadTargetsBySize struct {
+	t  []int
+	bm kafkazk.BrokerMap
+}
+
+// We work with storage free, so a sort by utilization
+// descending requires an ascending sort.
+func (o offloadTargetsBySize) Len() int      { return len(o.t) }
+func (o offloadTargetsBySize) Swap(i, j int) { o.t[i], o.t[j] = o.t[j], o.t[i] }
+func (o offloadTargetsBySize) Less(i, j int) bool {
+	s1 := o.bm[o.t[i]].StorageFree
+	s2 := o.bm[o.t[j]].StorageFree
+
+	if s1 < s2 {
+		return true
+	}
+
+	if s1 > s2 {
+		return false
+	}
+
+	return o.t[i] < o.t[j]
+}
+
+type planRelocationsForBrokerParams struct {
+	sourceID               int
+	relos                  map[int][]Relocation
+	partitions             sizeSortedPartitions
+	relocated              relocatedPartitions
+	brokers                kafkazk.BrokerMap
+	candidates             *kafkazk.BrokerCandidates
+	partitionMeta          kafkazk.PartitionMetaMap
+	plan                   relocationPlan
+	pass                   int
+	topPartitionsLimit     int
+	partitionSizeThreshold int
+	offloadTargetsMap      map[int]struct{}
+	tolerance              float64
+	localityScoped         bool
+	capacityAware          bool
+	topicConstraints       kafkazk.TopicConstraintsMap
+	leaderPins             kafkazk.LeaderPins
+	log                    io.Writer
+}
+
+// partitionID identifies a partition.
+type partitionID struct {
+	topic     string
+	partition int
+}
+
+// sizeSortedPartitions is a mapping of broker IDs to held
+// partitions, sorted by size descending.
+type sizeSortedPartitions map[int]kafkazk.PartitionList
+
+// newSizeSortedPartitions returns a sizeSortedPartitions for
+// the provided broker IDs. Ties in size are ordered by
+// partition number, then topic name.
+func newSizeSortedPartitions(pm *kafkazk.PartitionMap, pmm kafkazk.PartitionMetaMap, ids []int) sizeSortedPartitions {
+	s := sizeSortedPartitions{}
+	for _, id := range ids {
+		s[id] = kafkazk.PartitionList{}
+	}
+
+	for _, p := range pm.Partitions {
+		for _, id := range p.Replicas {
+			if _, ok := s[id]; ok {
+				s[id] = append(s[id], p)
+			}
+		}
+	}
+
+	for _, pl := range s {
+		sizes := make(map[partitionID]float64, len(pl))
+		for _, p := range pl {
+			sizes[partitionID{p.Topic, p.Partition}], _ = pmm.Size(p)
+		}
+
+		sort.Slice(pl, func(i, j int) bool {
+			s1 := sizes[partitionID{pl[i].Topic, pl[i].Partition}]
+			s2 := sizes[partitionID{pl[j].Topic, pl[j].Partition}]
+
+			switch {
+			case s1 != s2:
+				return s1 > s2
+			case pl[i].Partition != pl[j].Partition:
+				return pl[i].Partition < pl[j].Partition
+			}
+
+			return pl[i].Topic < pl[j].Topic
+		})
+	}
+
+	return s
+}
+
+// relocatedPartitions is a mapping of broker IDs to
+// partitions planned for relocation from the broker.
+type relocatedPartitions map[int]map[partitionID]struct{}
+
+// add marks the partition as relocated from broker id.
+func (r relocatedPartitions) add(id int, p kafkazk.Partition) {
+	if _, exist := r[id]; !exist {
+		r[id] = map[partitionID]struct{}{}
+	}
+
+	r[id][partitionID{p.Topic, p.Partition}] = struct{}{}
+}
+
+// largest returns the k largest partitions held by broker
+// id that aren't already planned for relocation.
+func (s sizeSortedPartitions) largest(id, k int, r relocatedPartitions) kafkazk.PartitionList {
+	var pl kafkazk.PartitionList
+
+	for _, p := range s[id] {
+		if len(pl) == k {
+			break
+		}
+
+		if _, relocated := r[id][partitionID{p.Topic, p.Partition}]; !relocated {
+			pl = append(pl, p)
+		}
+	}
+
+	return pl
+}
+
+// relocationPlan is a mapping of topic,
+// partition to a [][2]int describing a series of
+// source and destination brokers to relocate
+// a partition to and from.
+type relocationPlan map[string]map[int][][2]int
+
+// add takes a kafkazk.Partition and a [2]int pair of
+// source and destination broker IDs which the partition
+// is scheduled to relocate from and to.
+func (r relocationPlan) add(p kafkazk.Partition, ids [2]int) {
+	if _, exist := r[p.Topic]; !exist {
+		r[p.Topic] = make(map[int][][2]int)
+	}
+
+	r[p.Topic][p.Partition] = append(r[p.Topic][p.Partition], ids)
+}
+
+// isPlanned takes a kafkazk.Partition and returns whether
+// a relocation is planned for the partition, along with the
+// [][2]int list of source and destination broker ID pairs.
+func (r relocationPlan) isPlanned(p kafkazk.Partition) ([][2]int, bool) {
+	var pairs [][2]int
+
+	if _, exist := r[p.Topic]; !exist {
+		return pairs, false
+	}
+
+	if _, exist := r[p.Topic][p.Partition]; !exist {
+		return pairs, false
+	}
+
+	return r[p.Topic][p.Partition], true
+}
+
+// balanceValue takes a broker and a storage free value v for the broker
+// and returns the value used for storage balancing; the value as a portion
+// of the broker StorageCapacity if capacityAware is set.
+func (p planRelocationsForBrokerParams) balanceValue(b *kafkazk.Broker, v float64) float64 {
+	if p.capacityAware {
+		return v / b.StorageCapacity
+	}
+
+	return v
+}
+
+// formatBalanceValue formats a value returned by balanceValue.
+func (p planRelocationsForBrokerParams) formatBalanceValue(v float64) string {
+	if p.capacityAware {
+		return fmt.Sprintf("%.2f%%", v*100)
+	}
+
+	return fmt.Sprintf("%.2fGB", v/div)
+}
+
+// logf writes verbose output if a log is configured.
+func (p planRelocationsForBrokerParams) logf(format string, a ...interface{}) {
+	if p.log != nil {
+		fmt.Fprintf(p.log, format, a...)
+	}
+}
+
+func planRelocationsForBroker(params planRelocationsForBrokerParams) int {
+	relos := params.relos
+	brokers := params.brokers
+	partitionMeta := params.partitionMeta
+	plan := params.plan
+	sourceID := params.sourceID
+	topPartitionsLimit := params.topPartitionsLimit
+	partitionSizeThreshold := float64(params.partitionSizeThreshold * 1 << 20)
+	offloadTargetsMap := params.offloadTargetsMap
+	tolerance := params.tolerance
+
+	// Use the arithmetic mean for target
+	// thresholds.
+	meanStorageFree := brokers.Mean()
+	if params.capacityAware {
+		meanStorageFree = brokers.StorageFreeRatioMean()
+	}
+
+	// Get the top partitions for the target broker.
+	topPartn := params.partitions.largest(sourceID, topPartitionsLimit, params.relocated)
+
+	// Filter out partitions below the targeted size threshold.
+	for i, p := range topPartn {
+		pSize, _ := partitionMeta.Size(p)
+		if pSize < partitionSizeThreshold {
+			topPartn = topPartn[:i]
+			break
+		}
+	}
+
+	params.logf("\n[pass %d with tolerance %.2f] Broker %d has a storage free of %.2fGB. Top partitions:\n",
+		params.pass, tolerance, sourceID, brokers[sourceID].StorageFree/div)
+
+	for _, p := range topPartn {
+		pSize, _ := partitionMeta.Size(p)
+		params.logf("%s%s p%d: %.2fGB\n",
+			indent, p.Topic, p.Partition, pSize/div)
+	}
+
+	targetLocality := brokers[sourceID].Locality
+
+	// Get a storage sorted brokerList. Broker storage
+	// values only change once a relocation is planned,
+	// which ends the search below.
+	brokerList := params.candidates.List()
+
+	// Plan partition movements. Each time a partition is planned
+	// to be moved, it's unmapped from the broker so that it's
+	// not retried the next iteration.
+	var reloCount int
+	for _, partn := range topPartn {
+		pSize, _ := partitionMeta.Size(partn)
+
+		// Replicas required to satisfy a leader pin, considering
+		// relocations already planned, aren't relocated for broker
+		// pins and are only relocated within the rack for rack pins.
+		var pinRack string
+		if pin, pinned := params.leaderPins.Pin(partn); pinned {
+			planned := kafkazk.Partition{
+				Topic:     partn.Topic,
+				Partition: partn.Partition,
+				Replicas:  plannedReplicas(partn, plan),
+			}
+
+			if params.leaderPins.Required(planned, sourceID, brokers) {
+				if pin.Broker != 0 {
+					params.logf("%sSkipping %s p%d: leader pinned to broker %d\n",
+						indent, partn.Topic, partn.Partition, pin.Broker)
+					continue
+				}
+				pinRack = pin.Rack
+			}
+		}
+
+		// Find a destination broker.
+		var dest *kafkazk.Broker
+
+		// Whether or not the destination broker should have the same
+		// rack.id as the target. If so, choose the least utilized broker
+		// in same locality. If not, choose the least utilized broker
+		// the satisfies placement constraints considering the brokers
+		// in the replica list (excluding the sourceID broker since it
+		// will be replaced).
+		switch params.localityScoped {
+		case true:
+			for _, b := range brokerList {
+				if b.Locality == targetLocality && b.ID != sourceID {
+					// Don't select from offload targets.
+					if _, t := offloadTargetsMap[b.ID]; t {
+						continue
+					}
+
+					// Don't select brokers not
+					// permitted for the topic
+					// or in maintenance.
+					if !params.topicConstraints.Permits(partn.Topic, b.ID) || b.Maintenance {
+						continue
+					}
+
+					dest = b
+					break
+				}
+			}
+		case false:
+			// Get constraints for all brokers in the
+			// partition replica set, excluding the
+			// sourceID broker.
+			replicaSet := kafkazk.BrokerList{}
+			for _, id := range partn.Replicas {
+				if id != sourceID {
+					replicaSet = append(replicaSet, brokers[id])
+				}
+			}
+
+			// Include brokers already scheduled to
+			// receive this partition.
+			if pairs, planned := plan.isPlanned(partn); planned {
+				for _, p := range pairs {
+					replicaSet = append(replicaSet, brokers[p[1]])
+				}
+			}
+
+			c := kafkazk.MergeConstraints(replicaSet)
+
+			// Add all offload targets to the constraints.
+			// We're populating empty Brokers using just
+			// the IDs so that the rack IDs aren't excluded.
+			for id := range offloadTargetsMap {
+				c.Add(&kafkazk.Broker{ID: id})
+			}
+
+			// Likewise for brokers not
+			// permitted for the topic.
+			for _, b := range brokerList {
+				if !params.topicConstraints.Permits(partn.Topic, b.ID) {
+					c.Add(&kafkazk.Broker{ID: b.ID})
+				}
+			}
+
+			// Likewise for brokers outside of
+			// a required leader pin rack.
+			if pinRack != "" {
+				for _, b := range brokerList {
+					if b.Locality != pinRack {
+						c.Add(&kafkazk.Broker{ID: b.ID})
+					}
+				}
+			}
+
+			// Select the best candidate by storage.
+			dest, _ = c.SelectCandidate(params.candidates, kafkazk.ConstraintsParams{SelectorMethod: "storage"})
+		}
+
+		// If dest == nil, it's likely that the only available
+		// destination brokers that don't break placement constraints
+		// are already taking a replica for the partition. Continue
+		// to the next partition.
+		if dest == nil {
+			continue
+		}
+
+		params.logf("%s-\n", indent)
+		params.logf("%sAttempting migration plan for %s p%d\n", indent, partn.Topic, partn.Partition)
+		params.logf("%sCandidate destination broker %d has a storage free of %.2fGB\n",
+			indent, dest.ID, dest.StorageFree/div)
+
+		sourceFree := brokers[sourceID].StorageFree + pSize
+		destFree := dest.StorageFree - pSize
+
+		// If the estimated storage change pushes either the
+		// target or destination beyond the threshold distance
+		// from the mean, try the next partition.
+
+		sLim := meanStorageFree * (1 + tolerance)
+		if v := params.balanceValue(brokers[sourceID], sourceFree); v > sLim {
+			params.logf("%sCannot move partition from target: "+
+				"expected storage free %s above tolerated threshold of %s\n",
+				indent, params.formatBalanceValue(v), params.formatBalanceValue(sLim))
+
+			continue
+		}
+
+		dLim := meanStorageFree * (1 - tolerance)
+		if v := params.balanceValue(dest, destFree); v < dLim {
+			params.logf("%sCannot move partition to candidate: "+
+				"expected storage free %s below tolerated threshold of %s\n",
+				indent, params.formatBalanceValue(v), params.formatBalanceValue(dLim))
+
+			continue
+		}
+
+		// Otherwise, schedule the relocation.
+
+		relos[sourceID] = append(relos[sourceID], Relocation{Partition: partn, Destination: dest.ID})
+		reloCount++
+
+		// Add to plan.
+		plan.add(partn, [2]int{sourceID, dest.ID})
+
+		// Update StorageFree values.
+		brokers[sourceID].StorageFree = sourceFree
+		brokers[dest.ID].StorageFree = destFree
+		params.candidates.Update(brokers[sourceID], dest)
+
+		// Remove the partition as being mapped
+		// to the source broker.
+		params.relocated.add(sourceID, partn)
+
+		params.logf("%sPlanning relocation to candidate\n", indent)
+
+		// Break at the first placement.
+		break
+	}
+
+	if reloCount == 0 {
+		params.logf("%s-\n", indent)
+		params.logf("%sNo suitable relocation destinations were found for any partitions "+
+			"held by this broker. This is likely due to insufficient free candidates "+
+			"in rack IDs that won't break placement constraints and/or suitable candidates "+
+			"already being scheduled to take replicas of partitions held by this broker\n", indent)
+	}
+
+	return reloCount
+}
+
+// plannedReplicas returns the replica set of the
+// partition with any planned relocations applied.
+func plannedReplicas(p kafkazk.Partition, plan relocationPlan) []int {
+	replicas := append([]int{}, p.Replicas...)
+
+	if pairs, planned := plan.isPlanned(p); planned {
+		for i, r := range replicas {
+			for _, relo := range pairs {
+				if r == relo[0] {
+					replicas[i] = relo[1]
+				}
+			}
+		}
+	}
+
+	return replicas
+}
+
+func applyRelocationPlan(pm *kafkazk.PartitionMap, plan relocationPlan) {
+	// Traverse the partition list.
+	for _, partn := range pm.Partitions {
+		// If a relocation is planned for the partition,
+		// replace the source ID with the planned
+		// destination ID.
+		if pairs, planned := plan.isPlanned(partn); planned {
+			for i, r := range partn.Replicas {
+				for _, relo := range pairs {
+					if r == relo[0] {
+						partn.Replicas[i] = relo[1]
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,423 @@
+package planner
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// ErrNoPartitions error.
+var ErrNoPartitions = errors.New("No partitions provided")
+
+// RebuildParams holds the inputs for a Rebuild.
+type RebuildParams struct {
+	// The current assignments to rebuild.
+	PartitionMap *kafkazk.PartitionMap
+	// Broker metadata. If metrics are included, the StorageFree
+	// values are used for the storage placement strategy.
+	BrokerMeta kafkazk.BrokerMetaMap
+	// Partition size metrics; required for
+	// the storage placement strategy.
+	PartitionMeta kafkazk.PartitionMetaMap
+	// Target broker IDs. Brokers currently mapped that aren't
+	// listed are replaced; -1 expands to all currently mapped
+	// brokers.
+	Brokers []int
+	// Placement strategy: [count, storage].
+	Strategy string
+	// Optimization priority for the storage
+	// strategy: [distribution, storage].
+	Optimization string
+	// Factor by which to multiply partition sizes
+	// for the storage strategy (defaults to 1).
+	PartitionSizeFactor float64
+	// The weight (0.00-1.00) of partition counts relative
+	// to storage for the storage strategy; 0 balances
+	// storage only.
+	CountWeight float64
+	// Minimum number of unique rack IDs per replica
+	// set (0 requires that all are unique).
+	MinUniqueRackIDs int
+	// Normalize replica sets to this length (0 is a no-op).
+	Replication int
+	// Lift and reposition all replicas rather than
+	// only those on replaced brokers.
+	ForceRebuild bool
+	// Replace brokers with substitutes in the same rack
+	// (inferred for brokers not found in the BrokerMeta).
+	// Ignored for forced rebuilds.
+	SubstitutionAffinity bool
+	// Rebalance broker leader/follower ratios.
+	OptimizeLeadership bool
+	// If set, each placement strategy is evaluated with and
+	// without leadership optimization and the candidate with
+	// the lowest weighted cost is selected; Strategy and
+	// OptimizeLeadership are ignored. The storage strategy
+	// is only evaluated if PartitionMeta is set.
+	ObjectiveWeights *ObjectiveWeights
+	// The number of trailing replicas in each replica set
+	// that are observers; observers never become leaders.
+	Observers int
+	// The rack observers are placed in; all other
+	// replicas are placed outside of it.
+	ObserverRack string
+	// Topic names mapped to the rack of their dominant
+	// consumers; at least one replica of each partition
+	// is placed in that rack.
+	ConsumerRacks map[string]string
+	// Per-topic placement constraints. Required
+	// replication factors take precedence over
+	// Replication.
+	TopicConstraints kafkazk.TopicConstraintsMap
+	// Per-partition leader pins, applied following
+	// any leadership optimization.
+	LeaderPins kafkazk.LeaderPins
+	// Brokers in maintenance. They receive no new
+	// replicas; those they hold are only relocated
+	// if they're replaced.
+	Maintenance []int
+	// Hooks applied in order to the output map
+	// following any leadership optimization.
+	Hooks []PlacementHook
+	// Used to look up ISR states and topic configs for the
+	// out of sync and min.insync.replicas checks. Both checks
+	// are skipped if nil.
+	ZK kafkazk.Handler
+	// Handling of partitions with replicas not in the ISR or
+	// on offline brokers: [warn, exclude] (defaults to warn).
+	OutOfSync string
+	// Handling of changed partitions that could fall below
+	// min.insync.replicas: [warn, block, ignore] (defaults
+	// to warn).
+	MinISRCheck string
+	// The min.insync.replicas assumed for topics without
+	// an override (defaults to 1).
+	DefaultMinISR int
+}
+
+// Rebuild takes RebuildParams and returns a Plan that maps all partitions
+// onto the target brokers. An error is returned for invalid params, if
+// required metrics are unavailable or if a hook fails. An OutOfSyncError is
+// returned if all partitions are excluded as out of sync and a MinISRError
+// if min.insync.replicas violations are blocked.
+func Rebuild(params RebuildParams) (*Plan, error) {
+	if params.PartitionMap == nil || len(params.PartitionMap.Partitions) == 0 {
+		return nil, ErrNoPartitions
+	}
+
+	switch params.Strategy {
+	case "":
+		params.Strategy = "count"
+	case "count", "storage":
+	default:
+		return nil, fmt.Errorf("Invalid placement strategy '%s'", params.Strategy)
+	}
+
+	if params.Strategy == "storage" && params.PartitionMeta == nil && params.ObjectiveWeights == nil {
+		return nil, fmt.Errorf("The storage placement strategy requires partition metrics")
+	}
+
+	if params.CountWeight < 0 || params.CountWeight > 1 {
+		return nil, fmt.Errorf("Invalid count weight %.2f; must be between 0.00 and 1.00", params.CountWeight)
+	}
+
+	if params.Observers < 0 {
+		return nil, fmt.Errorf("Invalid observer count %d; must be 0 or greater", params.Observers)
+	}
+
+	if params.ObserverRack != "" && params.Observers == 0 {
+		return nil, fmt.Errorf("An observer rack requires observers")
+	}
+
+	switch params.OutOfSync {
+	case "":
+		params.OutOfSync = "warn"
+	case "warn", "exclude":
+	default:
+		return nil, fmt.Errorf("Invalid out of sync handling '%s'", params.OutOfSync)
+	}
+
+	switch params.MinISRCheck {
+	case "":
+		params.MinISRCheck = "warn"
+	case "warn", "block", "ignore":
+	default:
+		return nil, fmt.Errorf("Invalid min.insync.replicas check '%s'", params.MinISRCheck)
+	}
+
+	if params.DefaultMinISR == 0 {
+		params.DefaultMinISR = 1
+	}
+
+	if params.Optimization == "" {
+		params.Optimization = "distribution"
+	}
+
+	if params.PartitionSizeFactor == 0 {
+		params.PartitionSizeFactor = 1.0
+	}
+
+	if params.BrokerMeta == nil {
+		params.BrokerMeta = kafkazk.BrokerMetaMap{}
+	}
+
+	pm := params.PartitionMap.Copy()
+
+	// Exclude or warn on partitions with out of sync replicas.
+	oos, err := OutOfSync(params.ZK, pm, params.BrokerMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(oos) > 0 && params.OutOfSync == "exclude" {
+		if pm = pm.Exclude(oos); len(pm.Partitions) == 0 {
+			return nil, OutOfSyncError{Partitions: oos}
+		}
+	}
+
+	input := pm.Copy()
+
+	brokers := kafkazk.BrokerMapFromPartitionMap(pm, params.BrokerMeta, params.ForceRebuild)
+	bs, msgs := brokers.Update(params.Brokers, params.BrokerMeta)
+
+	var changes []string
+	for m := range msgs {
+		changes = append(changes, m)
+	}
+
+	brokers.SetMaintenance(params.Maintenance)
+	brokersBefore := brokers.Copy()
+
+	if err := checkBrokerMetrics(brokers, params.BrokerMeta); err != nil {
+		return nil, err
+	}
+
+	var affinities kafkazk.SubstitutionAffinities
+	if params.SubstitutionAffinity && !params.ForceRebuild {
+		if affinities, err = brokers.SubstitutionAffinities(pm); err != nil {
+			return nil, fmt.Errorf("Substitution affinity error: %s", err)
+		}
+	}
+
+	pm.SetReplication(params.Replication)
+	params.TopicConstraints.SetReplication(pm)
+
+	var output *kafkazk.PartitionMap
+	var warnings []error
+	var candidates []Candidate
+
+	if params.ObjectiveWeights != nil {
+		output, brokers, warnings, candidates, err = rebuildCandidates(params, input, pm, brokers, affinities)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if output, warnings, err = rebuildMap(params, pm, brokers, affinities, params.Strategy); err != nil {
+			return nil, err
+		}
+
+		// Observers are never considered for leadership.
+		if params.OptimizeLeadership {
+			output.OptimizeLeaderFollowerObservers(params.Observers)
+		}
+	}
+
+	params.LeaderPins.Apply(output, brokers)
+
+	// Apply hooks individually to track
+	// the changes made by each.
+	var hookChanges []int
+	for i, h := range params.Hooks {
+		next, err := ApplyHooks([]PlacementHook{h}, HookInput{
+			Command:       "rebuild",
+			Input:         input,
+			Output:        output,
+			Brokers:       brokers,
+			BrokerMeta:    params.BrokerMeta,
+			PartitionMeta: params.PartitionMeta,
+		})
+		if err != nil {
+			if he, ok := err.(HookError); ok {
+				he.Index = i
+				err = he
+			}
+			return nil, err
+		}
+
+		hookChanges = append(hookChanges, NewStats(output, next, nil, nil, nil).PartitionsMoved)
+		output = next
+	}
+
+	warnings = append(warnings, output.CheckObservers(brokers, params.Observers, params.ObserverRack)...)
+	warnings = append(warnings, output.CheckConsumerLocality(brokers, params.ConsumerRacks)...)
+	warnings = append(warnings, output.CheckInternalTopics(brokers)...)
+	warnings = append(warnings, params.TopicConstraints.Check(output)...)
+	warnings = append(warnings, params.LeaderPins.Check(output, brokers)...)
+
+	if params.OutOfSync == "warn" {
+		for _, p := range oos {
+			warnings = append(warnings, fmt.Errorf("%s p%d has out of sync replicas", p.Topic, p.Partition))
+		}
+	}
+
+	if bs.Missing > 0 {
+		warnings = append(warnings, fmt.Errorf("%d provided brokers not found in broker metadata", bs.Missing))
+	}
+
+	// Check changed partitions against min.insync.replicas.
+	var violations []kafkazk.MinISRViolation
+	if params.MinISRCheck != "ignore" {
+		if violations, err = MinISRViolations(params.ZK, input, output, params.DefaultMinISR); err != nil {
+			return nil, err
+		}
+
+		if len(violations) > 0 && params.MinISRCheck == "block" {
+			return nil, MinISRError{Violations: violations}
+		}
+
+		for _, v := range violations {
+			warnings = append(warnings, fmt.Errorf("%s p%d could fall below min.insync.replicas", v.Topic, v.Partition))
+		}
+	}
+
+	return &Plan{
+		Input:            input,
+		Output:           output,
+		BrokersBefore:    brokersBefore,
+		BrokersAfter:     brokers,
+		Stats:            NewStats(input, output, params.PartitionMeta, brokersBefore, brokers),
+		Warnings:         warnings,
+		BrokerStatus:     bs,
+		BrokerChanges:    changes,
+		Affinities:       affinities,
+		Candidates:       candidates,
+		HookChanges:      hookChanges,
+		OutOfSync:        oos,
+		MinISRViolations: violations,
+	}, nil
+}
+
+// Candidate describes a candidate map evaluated
+// in a Rebuild with ObjectiveWeights.
+type Candidate struct {
+	Strategy           string
+	OptimizeLeadership bool
+	Objectives         Objectives
+	Selected           bool
+}
+
+func (c Candidate) String() string {
+	return fmt.Sprintf("placement=%s, optimize-leadership=%t", c.Strategy, c.OptimizeLeadership)
+}
+
+// rebuildCandidates builds a candidate map for each placement strategy,
+// with and without leadership optimization, and returns the map, the
+// BrokerMap and the errors of the candidate with the lowest weighted
+// cost along with all candidates evaluated.
+func rebuildCandidates(params RebuildParams, input, pm *kafkazk.PartitionMap, bm kafkazk.BrokerMap, af kafkazk.SubstitutionAffinities) (*kafkazk.PartitionMap, kafkazk.BrokerMap, []error, []Candidate, error) {
+	strategies := []string{"count"}
+	if params.PartitionMeta != nil {
+		strategies = append(strategies, "storage")
+	}
+
+	var candidates []Candidate
+	var maps []*kafkazk.PartitionMap
+	var brokers []kafkazk.BrokerMap
+	var errs [][]error
+
+	for _, s := range strategies {
+		// Each candidate is built from copies;
+		// rebuilds update the BrokerMap.
+		b := bm.Copy()
+		out, e, err := rebuildMap(params, pm.Copy(), b, af, s)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+
+		optimized := out.Copy()
+		optimized.OptimizeLeaderFollowerObservers(params.Observers)
+
+		candidates = append(candidates, Candidate{Strategy: s}, Candidate{Strategy: s, OptimizeLeadership: true})
+		maps = append(maps, out, optimized)
+		brokers = append(brokers, b, b)
+		errs = append(errs, e, e)
+	}
+
+	best, objectives := SelectBest(input, maps, params.BrokerMeta, params.PartitionMeta, *params.ObjectiveWeights)
+
+	for i := range candidates {
+		candidates[i].Objectives = objectives[i]
+		candidates[i].Selected = i == best
+	}
+
+	return maps[best], brokers[best], errs[best], candidates, nil
+}
+
+// rebuildMap rebuilds the PartitionMap onto the BrokerMap with the
+// placement strategy s. The BrokerMap is updated with the placements.
+// Forced rebuilds lift all replicas; storage placements first free the
+// storage of all lifted replicas on their brokers.
+func rebuildMap(params RebuildParams, pm *kafkazk.PartitionMap, bm kafkazk.BrokerMap, af kafkazk.SubstitutionAffinities, s string) (*kafkazk.PartitionMap, []error, error) {
+	rebuildParams := kafkazk.RebuildParams{
+		PMM:              params.PartitionMeta,
+		BM:               bm,
+		Strategy:         s,
+		Optimization:     params.Optimization,
+		PartnSzFactor:    params.PartitionSizeFactor,
+		MinUniqueRackIDs: params.MinUniqueRackIDs,
+		CountWeight:      params.CountWeight,
+		TopicConstraints: params.TopicConstraints,
+		Observers:        params.Observers,
+		ObserverRack:     params.ObserverRack,
+		ConsumerRacks:    params.ConsumerRacks,
+		LeaderPins:       params.LeaderPins,
+	}
+
+	if af != nil {
+		rebuildParams.Affinities = af
+	}
+
+	// Free storage on all brokers for forced rebuilds,
+	// otherwise only on brokers marked for replacement.
+	// Forced rebuilds are called on a stripped copy while
+	// the BrokerMap reflects the unstripped map.
+	toRebuild := pm
+	filter := func(b *kafkazk.Broker) bool { return b.Replace }
+
+	if params.ForceRebuild {
+		toRebuild = pm.Strip()
+		filter = func(b *kafkazk.Broker) bool { return true }
+	}
+
+	if s == "storage" {
+		if err := bm.SubStorage(pm, params.PartitionMeta, filter); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	out, errs := toRebuild.Rebuild(rebuildParams)
+
+	return out, errs, nil
+}
+
+// checkBrokerMetrics returns an error for the lowest broker ID in the
+// BrokerMap that isn't missing and is marked as having incomplete
+// metrics in the BrokerMetaMap.
+func checkBrokerMetrics(bm kafkazk.BrokerMap, bmm kafkazk.BrokerMetaMap) error {
+	var ids []int
+	for id, b := range bm {
+		if m, exists := bmm[id]; exists && !b.Missing && m.MetricsIncomplete {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	sort.Ints(ids)
+
+	return fmt.Errorf("Metrics not found for broker %d", ids[0])
+}
@@ -63,8 +63,18 @@ func (m *TagResponse) GetMessage() string {
 }
 
 type BrokerRequest struct {
-	Tag                  []string `protobuf:"bytes,1,rep,name=tag,proto3" json:"tag,omitempty"`
-	Id                   uint32   `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Tag []string `protobuf:"bytes,1,rep,name=tag,proto3" json:"tag,omitempty"`
+	Id  uint32   `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster string `protobuf:"bytes,3,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	// A tag expression that brokers must match in addition
+	// to any tags, e.g. "rack=us-east-1* AND NOT maintenance".
+	TagQuery string `protobuf:"bytes,4,opt,name=tag_query,json=tagQuery,proto3" json:"tag_query,omitempty"`
+	// The maximum number of IDs to return (ListBrokers
+	// only); all IDs if 0.
+	Limit uint32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The next_page_token of the previous page.
+	PageToken            string   `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -109,12 +119,43 @@ func (m *BrokerRequest) GetId() uint32 {
 	return 0
 }
 
+func (m *BrokerRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+func (m *BrokerRequest) GetTagQuery() string {
+	if m != nil {
+		return m.TagQuery
+	}
+	return ""
+}
+
+func (m *BrokerRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *BrokerRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
 type BrokerResponse struct {
-	Brokers              map[uint32]*Broker `protobuf:"bytes,5,rep,name=brokers,proto3" json:"brokers,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Ids                  []uint32           `protobuf:"varint,6,rep,packed,name=ids,proto3" json:"ids,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	Brokers map[uint32]*Broker `protobuf:"bytes,5,rep,name=brokers,proto3" json:"brokers,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Ids     []uint32           `protobuf:"varint,6,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	// The page_token of the next page; empty
+	// if this is the last page.
+	NextPageToken        string   `protobuf:"bytes,7,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *BrokerResponse) Reset()         { *m = BrokerResponse{} }
@@ -156,6 +197,13 @@ func (m *BrokerResponse) GetIds() []uint32 {
 	return nil
 }
 
+func (m *BrokerResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 type Broker struct {
 	// Registry metadata.
 	Tags map[string]string `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
@@ -169,9 +217,12 @@ type Broker struct {
 	Timestamp                   int64             `protobuf:"varint,11,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	Port                        uint32            `protobuf:"varint,12,opt,name=port,proto3" json:"port,omitempty"`
 	Version                     uint32            `protobuf:"varint,13,opt,name=version,proto3" json:"version,omitempty"`
-	XXX_NoUnkeyedLiteral        struct{}          `json:"-"`
-	XXX_unrecognized            []byte            `json:"-"`
-	XXX_sizecache               int32             `json:"-"`
+	// Brokers in maintenance receive no new partitions
+	// in placements; stored as the maintenance tag.
+	Maintenance          bool     `protobuf:"varint,14,opt,name=maintenance,proto3" json:"maintenance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Broker) Reset()         { *m = Broker{} }
@@ -269,9 +320,32 @@ func (m *Broker) GetVersion() uint32 {
 	return 0
 }
 
+func (m *Broker) GetMaintenance() bool {
+	if m != nil {
+		return m.Maintenance
+	}
+	return false
+}
+
 type TopicRequest struct {
-	Tag                  []string `protobuf:"bytes,1,rep,name=tag,proto3" json:"tag,omitempty"`
-	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Tag  []string `protobuf:"bytes,1,rep,name=tag,proto3" json:"tag,omitempty"`
+	Name string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Include the Schema Registry subjects of
+	// each topic (GetTopics only).
+	Schemas bool `protobuf:"varint,3,opt,name=schemas,proto3" json:"schemas,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster string `protobuf:"bytes,4,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	// A tag expression that topics must match in addition
+	// to any tags, e.g. "team=ingest AND tier!=test".
+	TagQuery string `protobuf:"bytes,5,opt,name=tag_query,json=tagQuery,proto3" json:"tag_query,omitempty"`
+	// An (unanchored) regex that topic names
+	// must match, e.g. ^payments\.
+	NameRegex string `protobuf:"bytes,6,opt,name=name_regex,json=nameRegex,proto3" json:"name_regex,omitempty"`
+	// The maximum number of names to return (ListTopics
+	// only); all names if 0.
+	Limit uint32 `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The next_page_token of the previous page.
+	PageToken            string   `protobuf:"bytes,8,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -316,12 +390,57 @@ func (m *TopicRequest) GetName() string {
 	return ""
 }
 
+func (m *TopicRequest) GetSchemas() bool {
+	if m != nil {
+		return m.Schemas
+	}
+	return false
+}
+
+func (m *TopicRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+func (m *TopicRequest) GetTagQuery() string {
+	if m != nil {
+		return m.TagQuery
+	}
+	return ""
+}
+
+func (m *TopicRequest) GetNameRegex() string {
+	if m != nil {
+		return m.NameRegex
+	}
+	return ""
+}
+
+func (m *TopicRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *TopicRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
 type TopicResponse struct {
-	Topics               map[string]*Topic `protobuf:"bytes,5,rep,name=topics,proto3" json:"topics,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Names                []string          `protobuf:"bytes,6,rep,name=names,proto3" json:"names,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Topics map[string]*Topic `protobuf:"bytes,5,rep,name=topics,proto3" json:"topics,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Names  []string          `protobuf:"bytes,6,rep,name=names,proto3" json:"names,omitempty"`
+	// The page_token of the next page; empty
+	// if this is the last page.
+	NextPageToken        string   `protobuf:"bytes,7,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *TopicResponse) Reset()         { *m = TopicResponse{} }
@@ -363,16 +482,29 @@ func (m *TopicResponse) GetNames() []string {
 	return nil
 }
 
+func (m *TopicResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
 type Topic struct {
 	// Registry metadata.
 	Tags map[string]string `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	// Topic metadata from ZooKeeper.
-	Name                 string   `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
-	Partitions           uint32   `protobuf:"varint,6,opt,name=partitions,proto3" json:"partitions,omitempty"`
-	Replication          uint32   `protobuf:"varint,7,opt,name=replication,proto3" json:"replication,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Name        string `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	Partitions  uint32 `protobuf:"varint,6,opt,name=partitions,proto3" json:"partitions,omitempty"`
+	Replication uint32 `protobuf:"varint,7,opt,name=replication,proto3" json:"replication,omitempty"`
+	// Ownership metadata, stored as
+	// the owner and team tags.
+	Owner string `protobuf:"bytes,8,opt,name=owner,proto3" json:"owner,omitempty"`
+	Team  string `protobuf:"bytes,9,opt,name=team,proto3" json:"team,omitempty"`
+	// Schema Registry subjects of the topic.
+	Schemas              []*SchemaInfo `protobuf:"bytes,10,rep,name=schemas,proto3" json:"schemas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *Topic) Reset()         { *m = Topic{} }
@@ -428,234 +560,4418 @@ func (m *Topic) GetReplication() uint32 {
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*TagResponse)(nil), "registry.TagResponse")
-	proto.RegisterType((*BrokerRequest)(nil), "registry.BrokerRequest")
-	proto.RegisterType((*BrokerResponse)(nil), "registry.BrokerResponse")
-	proto.RegisterMapType((map[uint32]*Broker)(nil), "registry.BrokerResponse.BrokersEntry")
-	proto.RegisterType((*Broker)(nil), "registry.Broker")
-	proto.RegisterMapType((map[string]string)(nil), "registry.Broker.ListenersecurityprotocolmapEntry")
-	proto.RegisterMapType((map[string]string)(nil), "registry.Broker.TagsEntry")
-	proto.RegisterType((*TopicRequest)(nil), "registry.TopicRequest")
-	proto.RegisterType((*TopicResponse)(nil), "registry.TopicResponse")
-	proto.RegisterMapType((map[string]*Topic)(nil), "registry.TopicResponse.TopicsEntry")
-	proto.RegisterType((*Topic)(nil), "registry.Topic")
-	proto.RegisterMapType((map[string]string)(nil), "registry.Topic.TagsEntry")
+func (m *Topic) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
 }
 
-func init() { proto.RegisterFile("protos/registry.proto", fileDescriptor_4215e5fe8e6d7e5d) }
-
-var fileDescriptor_4215e5fe8e6d7e5d = []byte{
-	// 806 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x95, 0xdd, 0x6e, 0xe3, 0x44,
-	0x14, 0xc7, 0xe5, 0xa4, 0xf9, 0xf0, 0x71, 0xd2, 0x86, 0xa1, 0x6d, 0xa6, 0x6e, 0x41, 0xc6, 0xa8,
-	0x10, 0x55, 0x22, 0x56, 0x03, 0x12, 0x08, 0x2e, 0x90, 0x10, 0xa8, 0x12, 0x2a, 0x08, 0x59, 0x11,
-	0x02, 0x6e, 0x60, 0x9a, 0x8c, 0xcc, 0xd0, 0xc4, 0x36, 0x9e, 0x69, 0x45, 0x54, 0xf5, 0x86, 0x57,
-	0xd8, 0xb7, 0xd8, 0x8b, 0x95, 0xf6, 0x21, 0xf6, 0x09, 0xf6, 0x15, 0xf6, 0x09, 0xf6, 0x09, 0x56,
-	0x73, 0xc6, 0x6e, 0x9c, 0xa6, 0xde, 0xd5, 0x66, 0xef, 0x66, 0x4e, 0xce, 0xf9, 0x9d, 0xaf, 0x7f,
-	0xc6, 0xb0, 0x97, 0x66, 0x89, 0x4a, 0x64, 0x90, 0xf1, 0x48, 0x48, 0x95, 0x2d, 0x86, 0x78, 0x27,
-	0xed, 0xe2, 0xee, 0x1e, 0x45, 0x49, 0x12, 0xcd, 0x78, 0xc0, 0x52, 0x11, 0xb0, 0x38, 0x4e, 0x14,
-	0x53, 0x22, 0x89, 0xa5, 0xf1, 0xf3, 0x3f, 0x05, 0x67, 0xcc, 0xa2, 0x90, 0xcb, 0x34, 0x89, 0x25,
-	0x27, 0x14, 0x5a, 0x73, 0x2e, 0x25, 0x8b, 0x38, 0xb5, 0x3c, 0x6b, 0x60, 0x87, 0xc5, 0xd5, 0x3f,
-	0x85, 0xee, 0x77, 0x59, 0x72, 0xc9, 0xb3, 0x90, 0xff, 0x7b, 0xc5, 0xa5, 0x22, 0x3d, 0xa8, 0x2b,
-	0x16, 0x51, 0xcb, 0xab, 0x0f, 0xec, 0x50, 0x1f, 0xc9, 0x36, 0xd4, 0xc4, 0x94, 0xd6, 0x3c, 0x6b,
-	0xd0, 0x0d, 0x6b, 0x62, 0xea, 0x3f, 0xb5, 0x60, 0xbb, 0x88, 0xc9, 0xf9, 0xdf, 0x42, 0xeb, 0x02,
-	0x2d, 0x92, 0x36, 0xbc, 0xfa, 0xc0, 0x19, 0x1d, 0x0f, 0xef, 0x0a, 0x5f, 0x75, 0xcd, 0xaf, 0xf2,
-	0x87, 0x58, 0x65, 0x8b, 0xb0, 0x88, 0xd2, 0x59, 0xc5, 0x54, 0xd2, 0xa6, 0x57, 0x1f, 0x74, 0x43,
-	0x7d, 0x74, 0xcf, 0xa1, 0x53, 0x76, 0xd5, 0x1e, 0x97, 0x7c, 0x81, 0xe5, 0x77, 0x43, 0x7d, 0x24,
-	0x9f, 0x40, 0xe3, 0x9a, 0xcd, 0xae, 0x38, 0x96, 0xe6, 0x8c, 0x7a, 0x6b, 0x29, 0xcd, 0xcf, 0x5f,
-	0xd7, 0xbe, 0xb2, 0xfc, 0x97, 0x75, 0x68, 0x1a, 0x2b, 0x19, 0xc2, 0x96, 0x62, 0x91, 0xc4, 0x0e,
-	0x9d, 0x91, 0x7b, 0x3f, 0x6a, 0x38, 0x66, 0x51, 0x5e, 0x1d, 0xfa, 0xe5, 0xed, 0x37, 0x8a, 0xf6,
-	0x89, 0x84, 0xc3, 0x99, 0x90, 0x8a, 0xc7, 0x3c, 0x93, 0x7c, 0x72, 0x95, 0x09, 0xb5, 0xc0, 0x99,
-	0x4f, 0x92, 0xd9, 0x9c, 0xa5, 0xd8, 0x82, 0x33, 0x3a, 0x5d, 0xc3, 0x9e, 0x57, 0xc7, 0x98, 0x6c,
-	0xaf, 0xa3, 0x92, 0x23, 0xb0, 0x79, 0x3c, 0x4d, 0x13, 0x11, 0x2b, 0x49, 0x5b, 0xb8, 0x9b, 0xa5,
-	0x81, 0x10, 0xd8, 0xca, 0xd8, 0xe4, 0x92, 0xb6, 0x71, 0xb7, 0x78, 0xd6, 0x2b, 0xff, 0x67, 0xfe,
-	0x5f, 0x9a, 0x64, 0x8a, 0xda, 0x58, 0x7b, 0x71, 0xd5, 0xde, 0x7f, 0x27, 0x52, 0x51, 0x30, 0xde,
-	0xfa, 0xac, 0xf9, 0x4a, 0xcc, 0xb9, 0x54, 0x6c, 0x9e, 0x52, 0xc7, 0xb3, 0x06, 0xf5, 0x70, 0x69,
-	0xd0, 0x11, 0x08, 0xea, 0x20, 0x08, 0xcf, 0x9a, 0x7f, 0xcd, 0x33, 0x29, 0x92, 0x98, 0x76, 0x0d,
-	0x3f, 0xbf, 0xba, 0x5f, 0x82, 0x7d, 0x37, 0xc3, 0xf2, 0xda, 0x6c, 0xb3, 0xb6, 0xdd, 0xf2, 0xda,
-	0xec, 0xd2, 0x92, 0xdc, 0x9f, 0xc1, 0x7b, 0xd3, 0x94, 0xde, 0x86, 0xe7, 0x7f, 0x01, 0x9d, 0x71,
-	0x92, 0x8a, 0x49, 0xb5, 0xb4, 0x09, 0x6c, 0xc5, 0x6c, 0x5e, 0x84, 0xe2, 0xd9, 0x7f, 0x62, 0x41,
-	0x37, 0x0f, 0xcb, 0xd5, 0xfd, 0x0d, 0x34, 0x95, 0x36, 0x14, 0xe2, 0xfe, 0x78, 0xb9, 0xdc, 0x15,
-	0x47, 0x73, 0xcb, 0xc5, 0x93, 0x87, 0xe8, 0xf2, 0x34, 0xd6, 0x68, 0xdb, 0x0e, 0xcd, 0xc5, 0xfd,
-	0x11, 0x9c, 0x92, 0xf3, 0x03, 0x5d, 0x1d, 0xaf, 0x8a, 0x7b, 0xe7, 0x7e, 0xca, 0x52, 0x9b, 0xcf,
-	0x2c, 0x68, 0xa0, 0x91, 0x7c, 0xb6, 0x22, 0xed, 0x83, 0x7b, 0x31, 0x6b, 0xca, 0x2e, 0xba, 0x6f,
-	0x2c, 0xbb, 0x27, 0x1f, 0x02, 0xa4, 0x2c, 0x53, 0x02, 0x1f, 0x13, 0xda, 0xc4, 0xcd, 0x96, 0x2c,
-	0xc4, 0x03, 0x27, 0xe3, 0xe9, 0x4c, 0x4c, 0xf0, 0xb9, 0xa1, 0x2d, 0x74, 0x28, 0x9b, 0x36, 0x5e,
-	0xff, 0xe8, 0x71, 0x0b, 0xda, 0x61, 0x5e, 0x31, 0x19, 0x03, 0x9c, 0x71, 0x95, 0xbf, 0x00, 0xa4,
-	0xbf, 0xfe, 0x9c, 0xe0, 0x4a, 0x5d, 0x5a, 0xf5, 0xce, 0xf8, 0xef, 0xff, 0xff, 0xfc, 0xc5, 0xa3,
-	0x5a, 0x97, 0x38, 0xc1, 0xf5, 0x69, 0x50, 0x3c, 0x33, 0x7f, 0x80, 0xa3, 0x15, 0xf6, 0x0e, 0x58,
-	0x8a, 0x58, 0x42, 0x7a, 0x25, 0x6c, 0xa0, 0xff, 0xb9, 0xe4, 0x17, 0xb0, 0xcf, 0xb8, 0x32, 0x5b,
-	0x25, 0xfb, 0x6b, 0x12, 0x31, 0xe0, 0x7e, 0x85, 0x74, 0x7c, 0x82, 0xdc, 0x0e, 0x01, 0xcd, 0xcd,
-	0xa5, 0xf3, 0x2b, 0x80, 0xae, 0x76, 0x53, 0x64, 0x1f, 0x91, 0xef, 0x91, 0x9d, 0x25, 0xd2, 0x54,
-	0x3a, 0xcd, 0x05, 0xfe, 0x13, 0x4b, 0x53, 0x11, 0x47, 0xd5, 0xe8, 0xea, 0x31, 0x7c, 0x84, 0xec,
-	0x43, 0x72, 0xa0, 0xd9, 0xf3, 0x9c, 0x63, 0x92, 0x04, 0x37, 0x5a, 0x48, 0xb7, 0x64, 0x5a, 0x7c,
-	0x25, 0xee, 0xd2, 0x54, 0x8e, 0xbb, 0xb2, 0x05, 0x0f, 0xd3, 0xb8, 0x84, 0xae, 0xa4, 0x31, 0x63,
-	0x0f, 0x6e, 0xc4, 0xf4, 0x96, 0xfc, 0x06, 0xed, 0x31, 0x8b, 0x8c, 0xfc, 0xab, 0xda, 0xd8, 0x2b,
-	0xd9, 0x97, 0x1f, 0x45, 0xff, 0x03, 0x84, 0xf7, 0xdd, 0xbd, 0xd2, 0x7c, 0x14, 0x8b, 0x8a, 0xfa,
-	0xff, 0x84, 0x9d, 0xef, 0xf9, 0x8c, 0x2b, 0x8e, 0x2c, 0x2d, 0xe9, 0x0d, 0x13, 0x9c, 0x54, 0x24,
-	0xf8, 0x1d, 0xff, 0x28, 0xf9, 0x57, 0xa9, 0x72, 0x36, 0x15, 0xec, 0x23, 0x64, 0xef, 0xbb, 0xbb,
-	0x65, 0x1d, 0x22, 0x5c, 0x4f, 0xe5, 0x2f, 0xe8, 0x99, 0xda, 0x0d, 0x0b, 0x8b, 0xdf, 0x30, 0xc3,
-	0xc9, 0x83, 0x19, 0x2e, 0x9a, 0xf8, 0x32, 0x7f, 0xfe, 0x2a, 0x00, 0x00, 0xff, 0xff, 0xdf, 0x2e,
-	0x52, 0xc5, 0xa8, 0x08, 0x00, 0x00,
+func (m *Topic) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// RegistryClient is the client API for Registry service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type RegistryClient interface {
-	// GetBrokers returns a BrokerResponse with the brokers field populated
-	// with full broker metadata. If the input BrokerRequest.id field is
-	// non-nil, a single broker is returned matching the ID specified in the
-	// Broker object. Otherwise all brokers are returned, optionally filtered
-	// by any provided BrokerRequest.tags parameters.
-	GetBrokers(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*BrokerResponse, error)
-	// ListBrokers returns a BrokerResponse with the ids field populated
-	// with broker IDs. If the input BrokerRequest.id field is non-nil,
-	// a single broker ID is returned matching the ID specified in the
-	// Broker object if the broker exists. Otherwise all brokers are returned,
-	// optionally filtered by any provided BrokerRequest.tags parameters.
-	ListBrokers(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*BrokerResponse, error)
-	// GetTopics returns a TopicResponse with the topics field populated
-	// with full topic metadata. If the input TopicRequest.name field is
-	// non-nil, a single topic is returned matching the name specified in the
-	// Topic object. Otherwise all topics are returned, optionally filtered
-	// by any provided TopicRequest.tags parameters.
-	GetTopics(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TopicResponse, error)
-	// ListTopics returns a TopicResponse with the names field populated
-	// with topic names. If the input TopicRequest.name field is non-nil,
-	// a single topic name is returned matching the name specified in the
-	// Topic object if the topic exists. Otherwise all topics are returned,
-	// optionally filtered by any provided TopicRequest.tags parameters.
-	ListTopics(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TopicResponse, error)
-	// TopicMappings returns a BrokerResponse with the ids field
-	// populated with broker IDs that hold at least one partition
-	// for the requested topic. The topic is specified in the
-	// TopicRequest.name field.
-	TopicMappings(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*BrokerResponse, error)
-	// BrokerMappings returns a TopicResponse with the names field
-	// populated with topics that the broker holds at least one partition
-	// for the requested broker. The broker is specified in the
-	// BrokerRequest.id field.
-	BrokerMappings(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TopicResponse, error)
-	// TagTopic takes a TopicRequest and sets any specified
-	// tags for the named topic. Any existing tags that are
-	// not specified in the request are left unmodified.
-	TagTopic(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TagResponse, error)
-	// DeleteTopicTags takes a TopicRequest and deletes any
-	// specified tags for the named topic. Tags must be provided
-	// as key names only; "key:value" will not target the tag "key".
-	DeleteTopicTags(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TagResponse, error)
-	// TagBroker takes a BrokerRequest and sets any specified
-	// tags for the named broker. Any existing tags that are
-	// not specified in the request are left unmodified.
-	TagBroker(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TagResponse, error)
-	// DeleteBrokerTags takes a BrokerRequest and deletes any
-	// specified tags for the named broker. Tags must be provided
-	// as key names only; "key:value" will not target the tag "key".
-	DeleteBrokerTags(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TagResponse, error)
+func (m *Topic) GetSchemas() []*SchemaInfo {
+	if m != nil {
+		return m.Schemas
+	}
+	return nil
 }
 
-type registryClient struct {
-	cc *grpc.ClientConn
+type SchemaInfo struct {
+	// The subject, e.g. events-value.
+	Subject string `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	// The latest (or registered) version.
+	Id      int32  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Version int32  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Type    string `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	// The subject compatibility level, e.g. BACKWARD.
+	Compatibility string `protobuf:"bytes,5,opt,name=compatibility,proto3" json:"compatibility,omitempty"`
+	// Whether the schema specified in a CreateTopics
+	// request is compatible with the subject.
+	Compatible           bool     `protobuf:"varint,6,opt,name=compatible,proto3" json:"compatible,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func NewRegistryClient(cc *grpc.ClientConn) RegistryClient {
-	return &registryClient{cc}
+func (m *SchemaInfo) Reset()         { *m = SchemaInfo{} }
+func (m *SchemaInfo) String() string { return proto.CompactTextString(m) }
+func (*SchemaInfo) ProtoMessage()    {}
+func (*SchemaInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{7}
 }
 
-func (c *registryClient) GetBrokers(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*BrokerResponse, error) {
-	out := new(BrokerResponse)
-	err := c.cc.Invoke(ctx, "/registry.Registry/GetBrokers", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *SchemaInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SchemaInfo.Unmarshal(m, b)
+}
+func (m *SchemaInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SchemaInfo.Marshal(b, m, deterministic)
+}
+func (m *SchemaInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SchemaInfo.Merge(m, src)
+}
+func (m *SchemaInfo) XXX_Size() int {
+	return xxx_messageInfo_SchemaInfo.Size(m)
+}
+func (m *SchemaInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_SchemaInfo.DiscardUnknown(m)
 }
 
-func (c *registryClient) ListBrokers(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*BrokerResponse, error) {
-	out := new(BrokerResponse)
-	err := c.cc.Invoke(ctx, "/registry.Registry/ListBrokers", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_SchemaInfo proto.InternalMessageInfo
+
+func (m *SchemaInfo) GetSubject() string {
+	if m != nil {
+		return m.Subject
 	}
-	return out, nil
+	return ""
 }
 
-func (c *registryClient) GetTopics(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TopicResponse, error) {
-	out := new(TopicResponse)
-	err := c.cc.Invoke(ctx, "/registry.Registry/GetTopics", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SchemaInfo) GetId() int32 {
+	if m != nil {
+		return m.Id
 	}
-	return out, nil
+	return 0
 }
 
-func (c *registryClient) ListTopics(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TopicResponse, error) {
-	out := new(TopicResponse)
-	err := c.cc.Invoke(ctx, "/registry.Registry/ListTopics", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SchemaInfo) GetVersion() int32 {
+	if m != nil {
+		return m.Version
 	}
-	return out, nil
+	return 0
 }
 
-func (c *registryClient) TopicMappings(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*BrokerResponse, error) {
-	out := new(BrokerResponse)
-	err := c.cc.Invoke(ctx, "/registry.Registry/TopicMappings", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SchemaInfo) GetType() string {
+	if m != nil {
+		return m.Type
 	}
-	return out, nil
+	return ""
 }
 
-func (c *registryClient) BrokerMappings(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TopicResponse, error) {
-	out := new(TopicResponse)
-	err := c.cc.Invoke(ctx, "/registry.Registry/BrokerMappings", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SchemaInfo) GetCompatibility() string {
+	if m != nil {
+		return m.Compatibility
 	}
-	return out, nil
+	return ""
 }
 
-func (c *registryClient) TagTopic(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TagResponse, error) {
-	out := new(TagResponse)
-	err := c.cc.Invoke(ctx, "/registry.Registry/TagTopic", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SchemaInfo) GetCompatible() bool {
+	if m != nil {
+		return m.Compatible
 	}
-	return out, nil
+	return false
 }
 
-func (c *registryClient) DeleteTopicTags(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TagResponse, error) {
-	out := new(TagResponse)
-	err := c.cc.Invoke(ctx, "/registry.Registry/DeleteTopicTags", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type TopicConfigRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Configs to set.
+	Configs map[string]string `protobuf:"bytes,2,rep,name=configs,proto3" json:"configs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Configs to delete.
+	Delete []string `protobuf:"bytes,3,rep,name=delete,proto3" json:"delete,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster string `protobuf:"bytes,4,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	// Only validate the changes (SetTopicConfig only).
+	DryRun               bool     `protobuf:"varint,5,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (c *registryClient) TagBroker(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TagResponse, error) {
-	out := new(TagResponse)
-	err := c.cc.Invoke(ctx, "/registry.Registry/TagBroker", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *TopicConfigRequest) Reset()         { *m = TopicConfigRequest{} }
+func (m *TopicConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*TopicConfigRequest) ProtoMessage()    {}
+func (*TopicConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{8}
 }
 
-func (c *registryClient) DeleteBrokerTags(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TagResponse, error) {
-	out := new(TagResponse)
-	err := c.cc.Invoke(ctx, "/registry.Registry/DeleteBrokerTags", in, out, opts...)
+func (m *TopicConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopicConfigRequest.Unmarshal(m, b)
+}
+func (m *TopicConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopicConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *TopicConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicConfigRequest.Merge(m, src)
+}
+func (m *TopicConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_TopicConfigRequest.Size(m)
+}
+func (m *TopicConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopicConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopicConfigRequest proto.InternalMessageInfo
+
+func (m *TopicConfigRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TopicConfigRequest) GetConfigs() map[string]string {
+	if m != nil {
+		return m.Configs
+	}
+	return nil
+}
+
+func (m *TopicConfigRequest) GetDelete() []string {
+	if m != nil {
+		return m.Delete
+	}
+	return nil
+}
+
+func (m *TopicConfigRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+func (m *TopicConfigRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type TopicConfigResponse struct {
+	Name    string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Configs map[string]string `protobuf:"bytes,2,rep,name=configs,proto3" json:"configs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	DryRun  bool              `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// The config changes made, or that would be made
+	// for dry runs, e.g. "retention.ms: '' -> '3600000'"
+	// (SetTopicConfig only).
+	Changes              []string `protobuf:"bytes,4,rep,name=changes,proto3" json:"changes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TopicConfigResponse) Reset()         { *m = TopicConfigResponse{} }
+func (m *TopicConfigResponse) String() string { return proto.CompactTextString(m) }
+func (*TopicConfigResponse) ProtoMessage()    {}
+func (*TopicConfigResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{9}
+}
+
+func (m *TopicConfigResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopicConfigResponse.Unmarshal(m, b)
+}
+func (m *TopicConfigResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopicConfigResponse.Marshal(b, m, deterministic)
+}
+func (m *TopicConfigResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicConfigResponse.Merge(m, src)
+}
+func (m *TopicConfigResponse) XXX_Size() int {
+	return xxx_messageInfo_TopicConfigResponse.Size(m)
+}
+func (m *TopicConfigResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopicConfigResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopicConfigResponse proto.InternalMessageInfo
+
+func (m *TopicConfigResponse) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TopicConfigResponse) GetConfigs() map[string]string {
+	if m != nil {
+		return m.Configs
+	}
+	return nil
+}
+
+func (m *TopicConfigResponse) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *TopicConfigResponse) GetChanges() []string {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+type TopicDeleteRequest struct {
+	Name              string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Force             bool   `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+	ConfirmationToken string `protobuf:"bytes,3,opt,name=confirmation_token,json=confirmationToken,proto3" json:"confirmation_token,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster string `protobuf:"bytes,4,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	// Only run the safety checks; no confirmation
+	// token is issued (or redeemed) for forced
+	// dry runs.
+	DryRun               bool     `protobuf:"varint,5,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TopicDeleteRequest) Reset()         { *m = TopicDeleteRequest{} }
+func (m *TopicDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*TopicDeleteRequest) ProtoMessage()    {}
+func (*TopicDeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{10}
+}
+
+func (m *TopicDeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopicDeleteRequest.Unmarshal(m, b)
+}
+func (m *TopicDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopicDeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *TopicDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicDeleteRequest.Merge(m, src)
+}
+func (m *TopicDeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_TopicDeleteRequest.Size(m)
+}
+func (m *TopicDeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopicDeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopicDeleteRequest proto.InternalMessageInfo
+
+func (m *TopicDeleteRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TopicDeleteRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+func (m *TopicDeleteRequest) GetConfirmationToken() string {
+	if m != nil {
+		return m.ConfirmationToken
+	}
+	return ""
+}
+
+func (m *TopicDeleteRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+func (m *TopicDeleteRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type TopicDeleteResponse struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Deleted bool   `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	// Returned for the first step of a forced
+	// deletion; valid for a single request.
+	ConfirmationToken string `protobuf:"bytes,3,opt,name=confirmation_token,json=confirmationToken,proto3" json:"confirmation_token,omitempty"`
+	// The failed safety checks that
+	// were (or will be) overridden.
+	Overridden           []string `protobuf:"bytes,4,rep,name=overridden,proto3" json:"overridden,omitempty"`
+	DryRun               bool     `protobuf:"varint,5,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TopicDeleteResponse) Reset()         { *m = TopicDeleteResponse{} }
+func (m *TopicDeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*TopicDeleteResponse) ProtoMessage()    {}
+func (*TopicDeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{11}
+}
+
+func (m *TopicDeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopicDeleteResponse.Unmarshal(m, b)
+}
+func (m *TopicDeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopicDeleteResponse.Marshal(b, m, deterministic)
+}
+func (m *TopicDeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicDeleteResponse.Merge(m, src)
+}
+func (m *TopicDeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_TopicDeleteResponse.Size(m)
+}
+func (m *TopicDeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopicDeleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopicDeleteResponse proto.InternalMessageInfo
+
+func (m *TopicDeleteResponse) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TopicDeleteResponse) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+func (m *TopicDeleteResponse) GetConfirmationToken() string {
+	if m != nil {
+		return m.ConfirmationToken
+	}
+	return ""
+}
+
+func (m *TopicDeleteResponse) GetOverridden() []string {
+	if m != nil {
+		return m.Overridden
+	}
+	return nil
+}
+
+func (m *TopicDeleteResponse) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type TopicSpec struct {
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Partitions  uint32 `protobuf:"varint,2,opt,name=partitions,proto3" json:"partitions,omitempty"`
+	Replication uint32 `protobuf:"varint,3,opt,name=replication,proto3" json:"replication,omitempty"`
+	// Topic config overrides.
+	Configs map[string]string `protobuf:"bytes,4,rep,name=configs,proto3" json:"configs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Tags    map[string]string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Set as the owner and team tags.
+	Owner                string         `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`
+	Team                 string         `protobuf:"bytes,7,opt,name=team,proto3" json:"team,omitempty"`
+	Schemas              []*TopicSchema `protobuf:"bytes,8,rep,name=schemas,proto3" json:"schemas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *TopicSpec) Reset()         { *m = TopicSpec{} }
+func (m *TopicSpec) String() string { return proto.CompactTextString(m) }
+func (*TopicSpec) ProtoMessage()    {}
+func (*TopicSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{12}
+}
+
+func (m *TopicSpec) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopicSpec.Unmarshal(m, b)
+}
+func (m *TopicSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopicSpec.Marshal(b, m, deterministic)
+}
+func (m *TopicSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicSpec.Merge(m, src)
+}
+func (m *TopicSpec) XXX_Size() int {
+	return xxx_messageInfo_TopicSpec.Size(m)
+}
+func (m *TopicSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopicSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopicSpec proto.InternalMessageInfo
+
+func (m *TopicSpec) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TopicSpec) GetPartitions() uint32 {
+	if m != nil {
+		return m.Partitions
+	}
+	return 0
+}
+
+func (m *TopicSpec) GetReplication() uint32 {
+	if m != nil {
+		return m.Replication
+	}
+	return 0
+}
+
+func (m *TopicSpec) GetConfigs() map[string]string {
+	if m != nil {
+		return m.Configs
+	}
+	return nil
+}
+
+func (m *TopicSpec) GetTags() map[string]string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *TopicSpec) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *TopicSpec) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *TopicSpec) GetSchemas() []*TopicSchema {
+	if m != nil {
+		return m.Schemas
+	}
+	return nil
+}
+
+type TopicSchema struct {
+	// The schema definition, e.g. an Avro schema.
+	Schema string `protobuf:"bytes,1,opt,name=schema,proto3" json:"schema,omitempty"`
+	// AVRO (the default), JSON or PROTOBUF.
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// The schema is of message keys (the topic's
+	// <topic>-key subject) rather than values
+	// (the <topic>-value subject).
+	Key bool `protobuf:"varint,3,opt,name=key,proto3" json:"key,omitempty"`
+	// Only check the compatibility of the
+	// schema with the subject; don't register it.
+	ValidateOnly         bool     `protobuf:"varint,4,opt,name=validate_only,json=validateOnly,proto3" json:"validate_only,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TopicSchema) Reset()         { *m = TopicSchema{} }
+func (m *TopicSchema) String() string { return proto.CompactTextString(m) }
+func (*TopicSchema) ProtoMessage()    {}
+func (*TopicSchema) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{13}
+}
+
+func (m *TopicSchema) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopicSchema.Unmarshal(m, b)
+}
+func (m *TopicSchema) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopicSchema.Marshal(b, m, deterministic)
+}
+func (m *TopicSchema) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicSchema.Merge(m, src)
+}
+func (m *TopicSchema) XXX_Size() int {
+	return xxx_messageInfo_TopicSchema.Size(m)
+}
+func (m *TopicSchema) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopicSchema.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopicSchema proto.InternalMessageInfo
+
+func (m *TopicSchema) GetSchema() string {
+	if m != nil {
+		return m.Schema
+	}
+	return ""
+}
+
+func (m *TopicSchema) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *TopicSchema) GetKey() bool {
+	if m != nil {
+		return m.Key
+	}
+	return false
+}
+
+func (m *TopicSchema) GetValidateOnly() bool {
+	if m != nil {
+		return m.ValidateOnly
+	}
+	return false
+}
+
+type TopicTemplate struct {
+	// The topic name pattern; {name} is replaced
+	// by each of the names to create a topic for,
+	// e.g. payments.{name}.v1.
+	Pattern string   `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Names   []string `protobuf:"bytes,2,rep,name=names,proto3" json:"names,omitempty"`
+	// The settings of each topic.
+	Partitions           uint32            `protobuf:"varint,3,opt,name=partitions,proto3" json:"partitions,omitempty"`
+	Replication          uint32            `protobuf:"varint,4,opt,name=replication,proto3" json:"replication,omitempty"`
+	Configs              map[string]string `protobuf:"bytes,5,rep,name=configs,proto3" json:"configs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Tags                 map[string]string `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Owner                string            `protobuf:"bytes,7,opt,name=owner,proto3" json:"owner,omitempty"`
+	Team                 string            `protobuf:"bytes,8,opt,name=team,proto3" json:"team,omitempty"`
+	Schemas              []*TopicSchema    `protobuf:"bytes,9,rep,name=schemas,proto3" json:"schemas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *TopicTemplate) Reset()         { *m = TopicTemplate{} }
+func (m *TopicTemplate) String() string { return proto.CompactTextString(m) }
+func (*TopicTemplate) ProtoMessage()    {}
+func (*TopicTemplate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{14}
+}
+
+func (m *TopicTemplate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopicTemplate.Unmarshal(m, b)
+}
+func (m *TopicTemplate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopicTemplate.Marshal(b, m, deterministic)
+}
+func (m *TopicTemplate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicTemplate.Merge(m, src)
+}
+func (m *TopicTemplate) XXX_Size() int {
+	return xxx_messageInfo_TopicTemplate.Size(m)
+}
+func (m *TopicTemplate) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopicTemplate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopicTemplate proto.InternalMessageInfo
+
+func (m *TopicTemplate) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+func (m *TopicTemplate) GetNames() []string {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+func (m *TopicTemplate) GetPartitions() uint32 {
+	if m != nil {
+		return m.Partitions
+	}
+	return 0
+}
+
+func (m *TopicTemplate) GetReplication() uint32 {
+	if m != nil {
+		return m.Replication
+	}
+	return 0
+}
+
+func (m *TopicTemplate) GetConfigs() map[string]string {
+	if m != nil {
+		return m.Configs
+	}
+	return nil
+}
+
+func (m *TopicTemplate) GetTags() map[string]string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *TopicTemplate) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *TopicTemplate) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *TopicTemplate) GetSchemas() []*TopicSchema {
+	if m != nil {
+		return m.Schemas
+	}
+	return nil
+}
+
+type CreateTopicsRequest struct {
+	Topics   []*TopicSpec   `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"`
+	Template *TopicTemplate `protobuf:"bytes,2,opt,name=template,proto3" json:"template,omitempty"`
+	DryRun   bool           `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster              string   `protobuf:"bytes,4,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateTopicsRequest) Reset()         { *m = CreateTopicsRequest{} }
+func (m *CreateTopicsRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateTopicsRequest) ProtoMessage()    {}
+func (*CreateTopicsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{15}
+}
+
+func (m *CreateTopicsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateTopicsRequest.Unmarshal(m, b)
+}
+func (m *CreateTopicsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateTopicsRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateTopicsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateTopicsRequest.Merge(m, src)
+}
+func (m *CreateTopicsRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateTopicsRequest.Size(m)
+}
+func (m *CreateTopicsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateTopicsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateTopicsRequest proto.InternalMessageInfo
+
+func (m *CreateTopicsRequest) GetTopics() []*TopicSpec {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *CreateTopicsRequest) GetTemplate() *TopicTemplate {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
+func (m *CreateTopicsRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *CreateTopicsRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type CreateTopicsResponse struct {
+	// The topics created, or that would
+	// be created for dry runs.
+	Topics               []*Topic `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"`
+	DryRun               bool     `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateTopicsResponse) Reset()         { *m = CreateTopicsResponse{} }
+func (m *CreateTopicsResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateTopicsResponse) ProtoMessage()    {}
+func (*CreateTopicsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{16}
+}
+
+func (m *CreateTopicsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateTopicsResponse.Unmarshal(m, b)
+}
+func (m *CreateTopicsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateTopicsResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateTopicsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateTopicsResponse.Merge(m, src)
+}
+func (m *CreateTopicsResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateTopicsResponse.Size(m)
+}
+func (m *CreateTopicsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateTopicsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateTopicsResponse proto.InternalMessageInfo
+
+func (m *CreateTopicsResponse) GetTopics() []*Topic {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *CreateTopicsResponse) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type PlacementRequest struct {
+	// The name of the topic; it must not exist.
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Partitions  uint32 `protobuf:"varint,2,opt,name=partitions,proto3" json:"partitions,omitempty"`
+	Replication uint32 `protobuf:"varint,3,opt,name=replication,proto3" json:"replication,omitempty"`
+	// Broker IDs replicas may be placed on; all brokers if empty.
+	Brokers []uint32 `protobuf:"varint,4,rep,packed,name=brokers,proto3" json:"brokers,omitempty"`
+	// Broker IDs replicas are never placed on.
+	ExcludedBrokers []uint32 `protobuf:"varint,5,rep,packed,name=excluded_brokers,json=excludedBrokers,proto3" json:"excluded_brokers,omitempty"`
+	// Minimum number of unique rack IDs per replica
+	// set (0 requires that all are unique).
+	MinUniqueRackIds uint32 `protobuf:"varint,6,opt,name=min_unique_rack_ids,json=minUniqueRackIds,proto3" json:"min_unique_rack_ids,omitempty"`
+	// Placement strategy: count (default) or storage.
+	Strategy string `protobuf:"bytes,7,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	// Optimization priority for the storage
+	// strategy: distribution (default) or storage.
+	Optimization string `protobuf:"bytes,8,opt,name=optimization,proto3" json:"optimization,omitempty"`
+	// The estimated size (bytes) of each partition;
+	// required for the storage strategy.
+	PartitionSize      float64 `protobuf:"fixed64,9,opt,name=partition_size,json=partitionSize,proto3" json:"partition_size,omitempty"`
+	OptimizeLeadership bool    `protobuf:"varint,10,opt,name=optimize_leadership,json=optimizeLeadership,proto3" json:"optimize_leadership,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster              string   `protobuf:"bytes,11,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PlacementRequest) Reset()         { *m = PlacementRequest{} }
+func (m *PlacementRequest) String() string { return proto.CompactTextString(m) }
+func (*PlacementRequest) ProtoMessage()    {}
+func (*PlacementRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{17}
+}
+
+func (m *PlacementRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlacementRequest.Unmarshal(m, b)
+}
+func (m *PlacementRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlacementRequest.Marshal(b, m, deterministic)
+}
+func (m *PlacementRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlacementRequest.Merge(m, src)
+}
+func (m *PlacementRequest) XXX_Size() int {
+	return xxx_messageInfo_PlacementRequest.Size(m)
+}
+func (m *PlacementRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlacementRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlacementRequest proto.InternalMessageInfo
+
+func (m *PlacementRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PlacementRequest) GetPartitions() uint32 {
+	if m != nil {
+		return m.Partitions
+	}
+	return 0
+}
+
+func (m *PlacementRequest) GetReplication() uint32 {
+	if m != nil {
+		return m.Replication
+	}
+	return 0
+}
+
+func (m *PlacementRequest) GetBrokers() []uint32 {
+	if m != nil {
+		return m.Brokers
+	}
+	return nil
+}
+
+func (m *PlacementRequest) GetExcludedBrokers() []uint32 {
+	if m != nil {
+		return m.ExcludedBrokers
+	}
+	return nil
+}
+
+func (m *PlacementRequest) GetMinUniqueRackIds() uint32 {
+	if m != nil {
+		return m.MinUniqueRackIds
+	}
+	return 0
+}
+
+func (m *PlacementRequest) GetStrategy() string {
+	if m != nil {
+		return m.Strategy
+	}
+	return ""
+}
+
+func (m *PlacementRequest) GetOptimization() string {
+	if m != nil {
+		return m.Optimization
+	}
+	return ""
+}
+
+func (m *PlacementRequest) GetPartitionSize() float64 {
+	if m != nil {
+		return m.PartitionSize
+	}
+	return 0
+}
+
+func (m *PlacementRequest) GetOptimizeLeadership() bool {
+	if m != nil {
+		return m.OptimizeLeadership
+	}
+	return false
+}
+
+func (m *PlacementRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type PlacementPreview struct {
+	Name       string                `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Partitions []*PartitionPlacement `protobuf:"bytes,2,rep,name=partitions,proto3" json:"partitions,omitempty"`
+	// Brokers receiving replicas of the topic.
+	Brokers map[uint32]*BrokerPlacement `protobuf:"bytes,3,rep,name=brokers,proto3" json:"brokers,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Unsatisfied constraints, e.g. too few
+	// brokers for the replication factor.
+	Warnings             []string `protobuf:"bytes,4,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PlacementPreview) Reset()         { *m = PlacementPreview{} }
+func (m *PlacementPreview) String() string { return proto.CompactTextString(m) }
+func (*PlacementPreview) ProtoMessage()    {}
+func (*PlacementPreview) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{18}
+}
+
+func (m *PlacementPreview) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PlacementPreview.Unmarshal(m, b)
+}
+func (m *PlacementPreview) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PlacementPreview.Marshal(b, m, deterministic)
+}
+func (m *PlacementPreview) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PlacementPreview.Merge(m, src)
+}
+func (m *PlacementPreview) XXX_Size() int {
+	return xxx_messageInfo_PlacementPreview.Size(m)
+}
+func (m *PlacementPreview) XXX_DiscardUnknown() {
+	xxx_messageInfo_PlacementPreview.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PlacementPreview proto.InternalMessageInfo
+
+func (m *PlacementPreview) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PlacementPreview) GetPartitions() []*PartitionPlacement {
+	if m != nil {
+		return m.Partitions
+	}
+	return nil
+}
+
+func (m *PlacementPreview) GetBrokers() map[uint32]*BrokerPlacement {
+	if m != nil {
+		return m.Brokers
+	}
+	return nil
+}
+
+func (m *PlacementPreview) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
+type PartitionPlacement struct {
+	Partition uint32 `protobuf:"varint,1,opt,name=partition,proto3" json:"partition,omitempty"`
+	// The first replica is the preferred leader.
+	Replicas             []uint32 `protobuf:"varint,2,rep,packed,name=replicas,proto3" json:"replicas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PartitionPlacement) Reset()         { *m = PartitionPlacement{} }
+func (m *PartitionPlacement) String() string { return proto.CompactTextString(m) }
+func (*PartitionPlacement) ProtoMessage()    {}
+func (*PartitionPlacement) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{19}
+}
+
+func (m *PartitionPlacement) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PartitionPlacement.Unmarshal(m, b)
+}
+func (m *PartitionPlacement) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PartitionPlacement.Marshal(b, m, deterministic)
+}
+func (m *PartitionPlacement) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PartitionPlacement.Merge(m, src)
+}
+func (m *PartitionPlacement) XXX_Size() int {
+	return xxx_messageInfo_PartitionPlacement.Size(m)
+}
+func (m *PartitionPlacement) XXX_DiscardUnknown() {
+	xxx_messageInfo_PartitionPlacement.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PartitionPlacement proto.InternalMessageInfo
+
+func (m *PartitionPlacement) GetPartition() uint32 {
+	if m != nil {
+		return m.Partition
+	}
+	return 0
+}
+
+func (m *PartitionPlacement) GetReplicas() []uint32 {
+	if m != nil {
+		return m.Replicas
+	}
+	return nil
+}
+
+type BrokerPlacement struct {
+	// Replicas and preferred leaderships of the topic.
+	Replicas uint32 `protobuf:"varint,1,opt,name=replicas,proto3" json:"replicas,omitempty"`
+	Leaders  uint32 `protobuf:"varint,2,opt,name=leaders,proto3" json:"leaders,omitempty"`
+	// The broker's replica count of all topics.
+	ReplicasBefore uint32 `protobuf:"varint,3,opt,name=replicas_before,json=replicasBefore,proto3" json:"replicas_before,omitempty"`
+	ReplicasAfter  uint32 `protobuf:"varint,4,opt,name=replicas_after,json=replicasAfter,proto3" json:"replicas_after,omitempty"`
+	// Free storage in bytes; only populated
+	// for the storage strategy.
+	StorageFreeBefore    float64  `protobuf:"fixed64,5,opt,name=storage_free_before,json=storageFreeBefore,proto3" json:"storage_free_before,omitempty"`
+	StorageFreeAfter     float64  `protobuf:"fixed64,6,opt,name=storage_free_after,json=storageFreeAfter,proto3" json:"storage_free_after,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BrokerPlacement) Reset()         { *m = BrokerPlacement{} }
+func (m *BrokerPlacement) String() string { return proto.CompactTextString(m) }
+func (*BrokerPlacement) ProtoMessage()    {}
+func (*BrokerPlacement) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{20}
+}
+
+func (m *BrokerPlacement) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BrokerPlacement.Unmarshal(m, b)
+}
+func (m *BrokerPlacement) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BrokerPlacement.Marshal(b, m, deterministic)
+}
+func (m *BrokerPlacement) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BrokerPlacement.Merge(m, src)
+}
+func (m *BrokerPlacement) XXX_Size() int {
+	return xxx_messageInfo_BrokerPlacement.Size(m)
+}
+func (m *BrokerPlacement) XXX_DiscardUnknown() {
+	xxx_messageInfo_BrokerPlacement.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BrokerPlacement proto.InternalMessageInfo
+
+func (m *BrokerPlacement) GetReplicas() uint32 {
+	if m != nil {
+		return m.Replicas
+	}
+	return 0
+}
+
+func (m *BrokerPlacement) GetLeaders() uint32 {
+	if m != nil {
+		return m.Leaders
+	}
+	return 0
+}
+
+func (m *BrokerPlacement) GetReplicasBefore() uint32 {
+	if m != nil {
+		return m.ReplicasBefore
+	}
+	return 0
+}
+
+func (m *BrokerPlacement) GetReplicasAfter() uint32 {
+	if m != nil {
+		return m.ReplicasAfter
+	}
+	return 0
+}
+
+func (m *BrokerPlacement) GetStorageFreeBefore() float64 {
+	if m != nil {
+		return m.StorageFreeBefore
+	}
+	return 0
+}
+
+func (m *BrokerPlacement) GetStorageFreeAfter() float64 {
+	if m != nil {
+		return m.StorageFreeAfter
+	}
+	return 0
+}
+
+type ClusterStateRequest struct {
+	Topic []string `protobuf:"bytes,1,rep,name=topic,proto3" json:"topic,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster              string   `protobuf:"bytes,2,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClusterStateRequest) Reset()         { *m = ClusterStateRequest{} }
+func (m *ClusterStateRequest) String() string { return proto.CompactTextString(m) }
+func (*ClusterStateRequest) ProtoMessage()    {}
+func (*ClusterStateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{21}
+}
+
+func (m *ClusterStateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterStateRequest.Unmarshal(m, b)
+}
+func (m *ClusterStateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterStateRequest.Marshal(b, m, deterministic)
+}
+func (m *ClusterStateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterStateRequest.Merge(m, src)
+}
+func (m *ClusterStateRequest) XXX_Size() int {
+	return xxx_messageInfo_ClusterStateRequest.Size(m)
+}
+func (m *ClusterStateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterStateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterStateRequest proto.InternalMessageInfo
+
+func (m *ClusterStateRequest) GetTopic() []string {
+	if m != nil {
+		return m.Topic
+	}
+	return nil
+}
+
+func (m *ClusterStateRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type ClusterStateResponse struct {
+	State                []byte   `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClusterStateResponse) Reset()         { *m = ClusterStateResponse{} }
+func (m *ClusterStateResponse) String() string { return proto.CompactTextString(m) }
+func (*ClusterStateResponse) ProtoMessage()    {}
+func (*ClusterStateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{22}
+}
+
+func (m *ClusterStateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClusterStateResponse.Unmarshal(m, b)
+}
+func (m *ClusterStateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClusterStateResponse.Marshal(b, m, deterministic)
+}
+func (m *ClusterStateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClusterStateResponse.Merge(m, src)
+}
+func (m *ClusterStateResponse) XXX_Size() int {
+	return xxx_messageInfo_ClusterStateResponse.Size(m)
+}
+func (m *ClusterStateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClusterStateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClusterStateResponse proto.InternalMessageInfo
+
+func (m *ClusterStateResponse) GetState() []byte {
+	if m != nil {
+		return m.State
+	}
+	return nil
+}
+
+type UtilizationRequest struct {
+	// Topic regexes; all topics if empty.
+	Topic []string `protobuf:"bytes,1,rep,name=topic,proto3" json:"topic,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster              string   `protobuf:"bytes,2,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UtilizationRequest) Reset()         { *m = UtilizationRequest{} }
+func (m *UtilizationRequest) String() string { return proto.CompactTextString(m) }
+func (*UtilizationRequest) ProtoMessage()    {}
+func (*UtilizationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{23}
+}
+
+func (m *UtilizationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UtilizationRequest.Unmarshal(m, b)
+}
+func (m *UtilizationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UtilizationRequest.Marshal(b, m, deterministic)
+}
+func (m *UtilizationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UtilizationRequest.Merge(m, src)
+}
+func (m *UtilizationRequest) XXX_Size() int {
+	return xxx_messageInfo_UtilizationRequest.Size(m)
+}
+func (m *UtilizationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UtilizationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UtilizationRequest proto.InternalMessageInfo
+
+func (m *UtilizationRequest) GetTopic() []string {
+	if m != nil {
+		return m.Topic
+	}
+	return nil
+}
+
+func (m *UtilizationRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type UtilizationResponse struct {
+	Topics  map[string]*TopicUtilization  `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Brokers map[uint32]*BrokerUtilization `protobuf:"bytes,2,rep,name=brokers,proto3" json:"brokers,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The age (seconds) of the oldest
+	// partition size or broker metrics.
+	MetricsAge           int64    `protobuf:"varint,3,opt,name=metrics_age,json=metricsAge,proto3" json:"metrics_age,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UtilizationResponse) Reset()         { *m = UtilizationResponse{} }
+func (m *UtilizationResponse) String() string { return proto.CompactTextString(m) }
+func (*UtilizationResponse) ProtoMessage()    {}
+func (*UtilizationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{24}
+}
+
+func (m *UtilizationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UtilizationResponse.Unmarshal(m, b)
+}
+func (m *UtilizationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UtilizationResponse.Marshal(b, m, deterministic)
+}
+func (m *UtilizationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UtilizationResponse.Merge(m, src)
+}
+func (m *UtilizationResponse) XXX_Size() int {
+	return xxx_messageInfo_UtilizationResponse.Size(m)
+}
+func (m *UtilizationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UtilizationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UtilizationResponse proto.InternalMessageInfo
+
+func (m *UtilizationResponse) GetTopics() map[string]*TopicUtilization {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *UtilizationResponse) GetBrokers() map[uint32]*BrokerUtilization {
+	if m != nil {
+		return m.Brokers
+	}
+	return nil
+}
+
+func (m *UtilizationResponse) GetMetricsAge() int64 {
+	if m != nil {
+		return m.MetricsAge
+	}
+	return 0
+}
+
+// Sizes are in bytes.
+type TopicUtilization struct {
+	// The sum of the partition sizes.
+	Size float64 `protobuf:"fixed64,1,opt,name=size,proto3" json:"size,omitempty"`
+	// The size multiplied by the replication
+	// factor of each partition.
+	ReplicatedSize float64 `protobuf:"fixed64,2,opt,name=replicated_size,json=replicatedSize,proto3" json:"replicated_size,omitempty"`
+	// Partition number to size.
+	Partitions map[uint32]float64 `protobuf:"bytes,3,rep,name=partitions,proto3" json:"partitions,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	// Whether any partition sizes are missing
+	// from the metrics.
+	MetricsIncomplete    bool     `protobuf:"varint,4,opt,name=metrics_incomplete,json=metricsIncomplete,proto3" json:"metrics_incomplete,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TopicUtilization) Reset()         { *m = TopicUtilization{} }
+func (m *TopicUtilization) String() string { return proto.CompactTextString(m) }
+func (*TopicUtilization) ProtoMessage()    {}
+func (*TopicUtilization) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{25}
+}
+
+func (m *TopicUtilization) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopicUtilization.Unmarshal(m, b)
+}
+func (m *TopicUtilization) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopicUtilization.Marshal(b, m, deterministic)
+}
+func (m *TopicUtilization) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicUtilization.Merge(m, src)
+}
+func (m *TopicUtilization) XXX_Size() int {
+	return xxx_messageInfo_TopicUtilization.Size(m)
+}
+func (m *TopicUtilization) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopicUtilization.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopicUtilization proto.InternalMessageInfo
+
+func (m *TopicUtilization) GetSize() float64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *TopicUtilization) GetReplicatedSize() float64 {
+	if m != nil {
+		return m.ReplicatedSize
+	}
+	return 0
+}
+
+func (m *TopicUtilization) GetPartitions() map[uint32]float64 {
+	if m != nil {
+		return m.Partitions
+	}
+	return nil
+}
+
+func (m *TopicUtilization) GetMetricsIncomplete() bool {
+	if m != nil {
+		return m.MetricsIncomplete
+	}
+	return false
+}
+
+// Sizes are in bytes.
+type BrokerUtilization struct {
+	// Storage capacity is 0 if unknown.
+	StorageCapacity float64 `protobuf:"fixed64,1,opt,name=storage_capacity,json=storageCapacity,proto3" json:"storage_capacity,omitempty"`
+	StorageFree     float64 `protobuf:"fixed64,2,opt,name=storage_free,json=storageFree,proto3" json:"storage_free,omitempty"`
+	StorageUsed     float64 `protobuf:"fixed64,3,opt,name=storage_used,json=storageUsed,proto3" json:"storage_used,omitempty"`
+	// Storage used as a fraction of
+	// capacity; 0 if capacity is unknown.
+	StorageUtilization float64 `protobuf:"fixed64,4,opt,name=storage_utilization,json=storageUtilization,proto3" json:"storage_utilization,omitempty"`
+	// The number and total size of the partition
+	// replicas (of the requested topics) held.
+	Replicas    uint32  `protobuf:"varint,5,opt,name=replicas,proto3" json:"replicas,omitempty"`
+	ReplicaSize float64 `protobuf:"fixed64,6,opt,name=replica_size,json=replicaSize,proto3" json:"replica_size,omitempty"`
+	// Whether the broker metrics are missing.
+	MetricsIncomplete    bool     `protobuf:"varint,7,opt,name=metrics_incomplete,json=metricsIncomplete,proto3" json:"metrics_incomplete,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BrokerUtilization) Reset()         { *m = BrokerUtilization{} }
+func (m *BrokerUtilization) String() string { return proto.CompactTextString(m) }
+func (*BrokerUtilization) ProtoMessage()    {}
+func (*BrokerUtilization) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{26}
+}
+
+func (m *BrokerUtilization) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BrokerUtilization.Unmarshal(m, b)
+}
+func (m *BrokerUtilization) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BrokerUtilization.Marshal(b, m, deterministic)
+}
+func (m *BrokerUtilization) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BrokerUtilization.Merge(m, src)
+}
+func (m *BrokerUtilization) XXX_Size() int {
+	return xxx_messageInfo_BrokerUtilization.Size(m)
+}
+func (m *BrokerUtilization) XXX_DiscardUnknown() {
+	xxx_messageInfo_BrokerUtilization.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BrokerUtilization proto.InternalMessageInfo
+
+func (m *BrokerUtilization) GetStorageCapacity() float64 {
+	if m != nil {
+		return m.StorageCapacity
+	}
+	return 0
+}
+
+func (m *BrokerUtilization) GetStorageFree() float64 {
+	if m != nil {
+		return m.StorageFree
+	}
+	return 0
+}
+
+func (m *BrokerUtilization) GetStorageUsed() float64 {
+	if m != nil {
+		return m.StorageUsed
+	}
+	return 0
+}
+
+func (m *BrokerUtilization) GetStorageUtilization() float64 {
+	if m != nil {
+		return m.StorageUtilization
+	}
+	return 0
+}
+
+func (m *BrokerUtilization) GetReplicas() uint32 {
+	if m != nil {
+		return m.Replicas
+	}
+	return 0
+}
+
+func (m *BrokerUtilization) GetReplicaSize() float64 {
+	if m != nil {
+		return m.ReplicaSize
+	}
+	return 0
+}
+
+func (m *BrokerUtilization) GetMetricsIncomplete() bool {
+	if m != nil {
+		return m.MetricsIncomplete
+	}
+	return false
+}
+
+type BrokerUtilizationRequest struct {
+	// Broker IDs; all brokers if empty.
+	Id []uint32 `protobuf:"varint,1,rep,packed,name=id,proto3" json:"id,omitempty"`
+	// A tag expression brokers must match.
+	TagQuery string `protobuf:"bytes,2,opt,name=tag_query,json=tagQuery,proto3" json:"tag_query,omitempty"`
+	// The network capacity (MB/s) assumed for brokers
+	// the metrics backend reports no capacity for.
+	NetCapacity float64 `protobuf:"fixed64,3,opt,name=net_capacity,json=netCapacity,proto3" json:"net_capacity,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster              string   `protobuf:"bytes,4,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BrokerUtilizationRequest) Reset()         { *m = BrokerUtilizationRequest{} }
+func (m *BrokerUtilizationRequest) String() string { return proto.CompactTextString(m) }
+func (*BrokerUtilizationRequest) ProtoMessage()    {}
+func (*BrokerUtilizationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{27}
+}
+
+func (m *BrokerUtilizationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BrokerUtilizationRequest.Unmarshal(m, b)
+}
+func (m *BrokerUtilizationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BrokerUtilizationRequest.Marshal(b, m, deterministic)
+}
+func (m *BrokerUtilizationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BrokerUtilizationRequest.Merge(m, src)
+}
+func (m *BrokerUtilizationRequest) XXX_Size() int {
+	return xxx_messageInfo_BrokerUtilizationRequest.Size(m)
+}
+func (m *BrokerUtilizationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BrokerUtilizationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BrokerUtilizationRequest proto.InternalMessageInfo
+
+func (m *BrokerUtilizationRequest) GetId() []uint32 {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *BrokerUtilizationRequest) GetTagQuery() string {
+	if m != nil {
+		return m.TagQuery
+	}
+	return ""
+}
+
+func (m *BrokerUtilizationRequest) GetNetCapacity() float64 {
+	if m != nil {
+		return m.NetCapacity
+	}
+	return 0
+}
+
+func (m *BrokerUtilizationRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type BrokerUtilizationResponse struct {
+	Brokers map[uint32]*LiveBrokerUtilization `protobuf:"bytes,1,rep,name=brokers,proto3" json:"brokers,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Broker IDs in order of network headroom, most
+	// first; brokers without metrics are last.
+	Ranked []uint32 `protobuf:"varint,2,rep,packed,name=ranked,proto3" json:"ranked,omitempty"`
+	// Errors returned by the metrics backend,
+	// e.g. for brokers missing metrics.
+	Errors               []string `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BrokerUtilizationResponse) Reset()         { *m = BrokerUtilizationResponse{} }
+func (m *BrokerUtilizationResponse) String() string { return proto.CompactTextString(m) }
+func (*BrokerUtilizationResponse) ProtoMessage()    {}
+func (*BrokerUtilizationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{28}
+}
+
+func (m *BrokerUtilizationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BrokerUtilizationResponse.Unmarshal(m, b)
+}
+func (m *BrokerUtilizationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BrokerUtilizationResponse.Marshal(b, m, deterministic)
+}
+func (m *BrokerUtilizationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BrokerUtilizationResponse.Merge(m, src)
+}
+func (m *BrokerUtilizationResponse) XXX_Size() int {
+	return xxx_messageInfo_BrokerUtilizationResponse.Size(m)
+}
+func (m *BrokerUtilizationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BrokerUtilizationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BrokerUtilizationResponse proto.InternalMessageInfo
+
+func (m *BrokerUtilizationResponse) GetBrokers() map[uint32]*LiveBrokerUtilization {
+	if m != nil {
+		return m.Brokers
+	}
+	return nil
+}
+
+func (m *BrokerUtilizationResponse) GetRanked() []uint32 {
+	if m != nil {
+		return m.Ranked
+	}
+	return nil
+}
+
+func (m *BrokerUtilizationResponse) GetErrors() []string {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+// Network rates and capacities are in MB/s.
+type LiveBrokerUtilization struct {
+	Broker *Broker `protobuf:"bytes,1,opt,name=broker,proto3" json:"broker,omitempty"`
+	NetTx  float64 `protobuf:"fixed64,2,opt,name=net_tx,json=netTx,proto3" json:"net_tx,omitempty"`
+	NetRx  float64 `protobuf:"fixed64,3,opt,name=net_rx,json=netRx,proto3" json:"net_rx,omitempty"`
+	// The capacity reported by the metrics backend, otherwise
+	// the request net_capacity; 0 if unknown.
+	NetCapacity float64 `protobuf:"fixed64,4,opt,name=net_capacity,json=netCapacity,proto3" json:"net_capacity,omitempty"`
+	// The capacity less the greater of net_tx and net_rx,
+	// at least 0; 0 if the capacity is unknown.
+	NetHeadroom float64 `protobuf:"fixed64,5,opt,name=net_headroom,json=netHeadroom,proto3" json:"net_headroom,omitempty"`
+	// Disk utilization (percent).
+	DiskUtil     float64 `protobuf:"fixed64,6,opt,name=disk_util,json=diskUtil,proto3" json:"disk_util,omitempty"`
+	InstanceType string  `protobuf:"bytes,7,opt,name=instance_type,json=instanceType,proto3" json:"instance_type,omitempty"`
+	// Whether the metrics backend returned
+	// no metrics for the broker.
+	MetricsMissing       bool     `protobuf:"varint,8,opt,name=metrics_missing,json=metricsMissing,proto3" json:"metrics_missing,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LiveBrokerUtilization) Reset()         { *m = LiveBrokerUtilization{} }
+func (m *LiveBrokerUtilization) String() string { return proto.CompactTextString(m) }
+func (*LiveBrokerUtilization) ProtoMessage()    {}
+func (*LiveBrokerUtilization) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{29}
+}
+
+func (m *LiveBrokerUtilization) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LiveBrokerUtilization.Unmarshal(m, b)
+}
+func (m *LiveBrokerUtilization) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LiveBrokerUtilization.Marshal(b, m, deterministic)
+}
+func (m *LiveBrokerUtilization) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LiveBrokerUtilization.Merge(m, src)
+}
+func (m *LiveBrokerUtilization) XXX_Size() int {
+	return xxx_messageInfo_LiveBrokerUtilization.Size(m)
+}
+func (m *LiveBrokerUtilization) XXX_DiscardUnknown() {
+	xxx_messageInfo_LiveBrokerUtilization.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LiveBrokerUtilization proto.InternalMessageInfo
+
+func (m *LiveBrokerUtilization) GetBroker() *Broker {
+	if m != nil {
+		return m.Broker
+	}
+	return nil
+}
+
+func (m *LiveBrokerUtilization) GetNetTx() float64 {
+	if m != nil {
+		return m.NetTx
+	}
+	return 0
+}
+
+func (m *LiveBrokerUtilization) GetNetRx() float64 {
+	if m != nil {
+		return m.NetRx
+	}
+	return 0
+}
+
+func (m *LiveBrokerUtilization) GetNetCapacity() float64 {
+	if m != nil {
+		return m.NetCapacity
+	}
+	return 0
+}
+
+func (m *LiveBrokerUtilization) GetNetHeadroom() float64 {
+	if m != nil {
+		return m.NetHeadroom
+	}
+	return 0
+}
+
+func (m *LiveBrokerUtilization) GetDiskUtil() float64 {
+	if m != nil {
+		return m.DiskUtil
+	}
+	return 0
+}
+
+func (m *LiveBrokerUtilization) GetInstanceType() string {
+	if m != nil {
+		return m.InstanceType
+	}
+	return ""
+}
+
+func (m *LiveBrokerUtilization) GetMetricsMissing() bool {
+	if m != nil {
+		return m.MetricsMissing
+	}
+	return false
+}
+
+type QuotaRequest struct {
+	User     string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	ClientId string `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	// Quotas to set.
+	ProducerByteRate  float64 `protobuf:"fixed64,3,opt,name=producer_byte_rate,json=producerByteRate,proto3" json:"producer_byte_rate,omitempty"`
+	ConsumerByteRate  float64 `protobuf:"fixed64,4,opt,name=consumer_byte_rate,json=consumerByteRate,proto3" json:"consumer_byte_rate,omitempty"`
+	RequestPercentage float64 `protobuf:"fixed64,5,opt,name=request_percentage,json=requestPercentage,proto3" json:"request_percentage,omitempty"`
+	// Quotas to delete (producer_byte_rate,
+	// consumer_byte_rate, request_percentage).
+	Keys []string `protobuf:"bytes,6,rep,name=keys,proto3" json:"keys,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster              string   `protobuf:"bytes,7,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QuotaRequest) Reset()         { *m = QuotaRequest{} }
+func (m *QuotaRequest) String() string { return proto.CompactTextString(m) }
+func (*QuotaRequest) ProtoMessage()    {}
+func (*QuotaRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{30}
+}
+
+func (m *QuotaRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuotaRequest.Unmarshal(m, b)
+}
+func (m *QuotaRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuotaRequest.Marshal(b, m, deterministic)
+}
+func (m *QuotaRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuotaRequest.Merge(m, src)
+}
+func (m *QuotaRequest) XXX_Size() int {
+	return xxx_messageInfo_QuotaRequest.Size(m)
+}
+func (m *QuotaRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuotaRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuotaRequest proto.InternalMessageInfo
+
+func (m *QuotaRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *QuotaRequest) GetClientId() string {
+	if m != nil {
+		return m.ClientId
+	}
+	return ""
+}
+
+func (m *QuotaRequest) GetProducerByteRate() float64 {
+	if m != nil {
+		return m.ProducerByteRate
+	}
+	return 0
+}
+
+func (m *QuotaRequest) GetConsumerByteRate() float64 {
+	if m != nil {
+		return m.ConsumerByteRate
+	}
+	return 0
+}
+
+func (m *QuotaRequest) GetRequestPercentage() float64 {
+	if m != nil {
+		return m.RequestPercentage
+	}
+	return 0
+}
+
+func (m *QuotaRequest) GetKeys() []string {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *QuotaRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type QuotaResponse struct {
+	Quotas               []*Quota `protobuf:"bytes,1,rep,name=quotas,proto3" json:"quotas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QuotaResponse) Reset()         { *m = QuotaResponse{} }
+func (m *QuotaResponse) String() string { return proto.CompactTextString(m) }
+func (*QuotaResponse) ProtoMessage()    {}
+func (*QuotaResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{31}
+}
+
+func (m *QuotaResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuotaResponse.Unmarshal(m, b)
+}
+func (m *QuotaResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuotaResponse.Marshal(b, m, deterministic)
+}
+func (m *QuotaResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuotaResponse.Merge(m, src)
+}
+func (m *QuotaResponse) XXX_Size() int {
+	return xxx_messageInfo_QuotaResponse.Size(m)
+}
+func (m *QuotaResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuotaResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuotaResponse proto.InternalMessageInfo
+
+func (m *QuotaResponse) GetQuotas() []*Quota {
+	if m != nil {
+		return m.Quotas
+	}
+	return nil
+}
+
+type Quota struct {
+	// The quota entity; a user, client ID or both.
+	User     string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	ClientId string `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	// Quotas in bytes/s, or percent of request handler
+	// and network thread time. Unset quotas are 0.
+	ProducerByteRate     float64  `protobuf:"fixed64,3,opt,name=producer_byte_rate,json=producerByteRate,proto3" json:"producer_byte_rate,omitempty"`
+	ConsumerByteRate     float64  `protobuf:"fixed64,4,opt,name=consumer_byte_rate,json=consumerByteRate,proto3" json:"consumer_byte_rate,omitempty"`
+	RequestPercentage    float64  `protobuf:"fixed64,5,opt,name=request_percentage,json=requestPercentage,proto3" json:"request_percentage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Quota) Reset()         { *m = Quota{} }
+func (m *Quota) String() string { return proto.CompactTextString(m) }
+func (*Quota) ProtoMessage()    {}
+func (*Quota) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{32}
+}
+
+func (m *Quota) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Quota.Unmarshal(m, b)
+}
+func (m *Quota) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Quota.Marshal(b, m, deterministic)
+}
+func (m *Quota) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Quota.Merge(m, src)
+}
+func (m *Quota) XXX_Size() int {
+	return xxx_messageInfo_Quota.Size(m)
+}
+func (m *Quota) XXX_DiscardUnknown() {
+	xxx_messageInfo_Quota.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Quota proto.InternalMessageInfo
+
+func (m *Quota) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *Quota) GetClientId() string {
+	if m != nil {
+		return m.ClientId
+	}
+	return ""
+}
+
+func (m *Quota) GetProducerByteRate() float64 {
+	if m != nil {
+		return m.ProducerByteRate
+	}
+	return 0
+}
+
+func (m *Quota) GetConsumerByteRate() float64 {
+	if m != nil {
+		return m.ConsumerByteRate
+	}
+	return 0
+}
+
+func (m *Quota) GetRequestPercentage() float64 {
+	if m != nil {
+		return m.RequestPercentage
+	}
+	return 0
+}
+
+type ConsumerGroupRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster              string   `protobuf:"bytes,2,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConsumerGroupRequest) Reset()         { *m = ConsumerGroupRequest{} }
+func (m *ConsumerGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*ConsumerGroupRequest) ProtoMessage()    {}
+func (*ConsumerGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{33}
+}
+
+func (m *ConsumerGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConsumerGroupRequest.Unmarshal(m, b)
+}
+func (m *ConsumerGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConsumerGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *ConsumerGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConsumerGroupRequest.Merge(m, src)
+}
+func (m *ConsumerGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_ConsumerGroupRequest.Size(m)
+}
+func (m *ConsumerGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConsumerGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConsumerGroupRequest proto.InternalMessageInfo
+
+func (m *ConsumerGroupRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ConsumerGroupRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type ConsumerGroupResponse struct {
+	Groups               map[string]*ConsumerGroup `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Names                []string                  `protobuf:"bytes,2,rep,name=names,proto3" json:"names,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *ConsumerGroupResponse) Reset()         { *m = ConsumerGroupResponse{} }
+func (m *ConsumerGroupResponse) String() string { return proto.CompactTextString(m) }
+func (*ConsumerGroupResponse) ProtoMessage()    {}
+func (*ConsumerGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{34}
+}
+
+func (m *ConsumerGroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConsumerGroupResponse.Unmarshal(m, b)
+}
+func (m *ConsumerGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConsumerGroupResponse.Marshal(b, m, deterministic)
+}
+func (m *ConsumerGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConsumerGroupResponse.Merge(m, src)
+}
+func (m *ConsumerGroupResponse) XXX_Size() int {
+	return xxx_messageInfo_ConsumerGroupResponse.Size(m)
+}
+func (m *ConsumerGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConsumerGroupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConsumerGroupResponse proto.InternalMessageInfo
+
+func (m *ConsumerGroupResponse) GetGroups() map[string]*ConsumerGroup {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+func (m *ConsumerGroupResponse) GetNames() []string {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+type ConsumerGroup struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The group state (e.g. Stable,
+	// PreparingRebalance, Empty).
+	State        string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	ProtocolType string `protobuf:"bytes,3,opt,name=protocol_type,json=protocolType,proto3" json:"protocol_type,omitempty"`
+	// The partition assignor (e.g. range).
+	Protocol string         `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Members  []*GroupMember `protobuf:"bytes,5,rep,name=members,proto3" json:"members,omitempty"`
+	// Partitions sorted by topic and partition.
+	Partitions []*PartitionLag `protobuf:"bytes,6,rep,name=partitions,proto3" json:"partitions,omitempty"`
+	// The sum of all known partition lag.
+	Lag                  int64    `protobuf:"varint,7,opt,name=lag,proto3" json:"lag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConsumerGroup) Reset()         { *m = ConsumerGroup{} }
+func (m *ConsumerGroup) String() string { return proto.CompactTextString(m) }
+func (*ConsumerGroup) ProtoMessage()    {}
+func (*ConsumerGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{35}
+}
+
+func (m *ConsumerGroup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConsumerGroup.Unmarshal(m, b)
+}
+func (m *ConsumerGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConsumerGroup.Marshal(b, m, deterministic)
+}
+func (m *ConsumerGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConsumerGroup.Merge(m, src)
+}
+func (m *ConsumerGroup) XXX_Size() int {
+	return xxx_messageInfo_ConsumerGroup.Size(m)
+}
+func (m *ConsumerGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConsumerGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConsumerGroup proto.InternalMessageInfo
+
+func (m *ConsumerGroup) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ConsumerGroup) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *ConsumerGroup) GetProtocolType() string {
+	if m != nil {
+		return m.ProtocolType
+	}
+	return ""
+}
+
+func (m *ConsumerGroup) GetProtocol() string {
+	if m != nil {
+		return m.Protocol
+	}
+	return ""
+}
+
+func (m *ConsumerGroup) GetMembers() []*GroupMember {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+func (m *ConsumerGroup) GetPartitions() []*PartitionLag {
+	if m != nil {
+		return m.Partitions
+	}
+	return nil
+}
+
+func (m *ConsumerGroup) GetLag() int64 {
+	if m != nil {
+		return m.Lag
+	}
+	return 0
+}
+
+type GroupMember struct {
+	MemberId             string             `protobuf:"bytes,1,opt,name=member_id,json=memberId,proto3" json:"member_id,omitempty"`
+	ClientId             string             `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	ClientHost           string             `protobuf:"bytes,3,opt,name=client_host,json=clientHost,proto3" json:"client_host,omitempty"`
+	Assignments          []*TopicPartitions `protobuf:"bytes,4,rep,name=assignments,proto3" json:"assignments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *GroupMember) Reset()         { *m = GroupMember{} }
+func (m *GroupMember) String() string { return proto.CompactTextString(m) }
+func (*GroupMember) ProtoMessage()    {}
+func (*GroupMember) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{36}
+}
+
+func (m *GroupMember) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupMember.Unmarshal(m, b)
+}
+func (m *GroupMember) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupMember.Marshal(b, m, deterministic)
+}
+func (m *GroupMember) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupMember.Merge(m, src)
+}
+func (m *GroupMember) XXX_Size() int {
+	return xxx_messageInfo_GroupMember.Size(m)
+}
+func (m *GroupMember) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupMember.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupMember proto.InternalMessageInfo
+
+func (m *GroupMember) GetMemberId() string {
+	if m != nil {
+		return m.MemberId
+	}
+	return ""
+}
+
+func (m *GroupMember) GetClientId() string {
+	if m != nil {
+		return m.ClientId
+	}
+	return ""
+}
+
+func (m *GroupMember) GetClientHost() string {
+	if m != nil {
+		return m.ClientHost
+	}
+	return ""
+}
+
+func (m *GroupMember) GetAssignments() []*TopicPartitions {
+	if m != nil {
+		return m.Assignments
+	}
+	return nil
+}
+
+type TopicPartitions struct {
+	Topic                string   `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Partitions           []uint32 `protobuf:"varint,2,rep,packed,name=partitions,proto3" json:"partitions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TopicPartitions) Reset()         { *m = TopicPartitions{} }
+func (m *TopicPartitions) String() string { return proto.CompactTextString(m) }
+func (*TopicPartitions) ProtoMessage()    {}
+func (*TopicPartitions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{37}
+}
+
+func (m *TopicPartitions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopicPartitions.Unmarshal(m, b)
+}
+func (m *TopicPartitions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopicPartitions.Marshal(b, m, deterministic)
+}
+func (m *TopicPartitions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicPartitions.Merge(m, src)
+}
+func (m *TopicPartitions) XXX_Size() int {
+	return xxx_messageInfo_TopicPartitions.Size(m)
+}
+func (m *TopicPartitions) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopicPartitions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopicPartitions proto.InternalMessageInfo
+
+func (m *TopicPartitions) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *TopicPartitions) GetPartitions() []uint32 {
+	if m != nil {
+		return m.Partitions
+	}
+	return nil
+}
+
+type PartitionLag struct {
+	Topic     string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Partition uint32 `protobuf:"varint,2,opt,name=partition,proto3" json:"partition,omitempty"`
+	// The committed offset is -1 if the group has no
+	// committed offset for the partition, in which case
+	// the lag is unknown and also -1.
+	CommittedOffset int64 `protobuf:"varint,3,opt,name=committed_offset,json=committedOffset,proto3" json:"committed_offset,omitempty"`
+	EndOffset       int64 `protobuf:"varint,4,opt,name=end_offset,json=endOffset,proto3" json:"end_offset,omitempty"`
+	Lag             int64 `protobuf:"varint,5,opt,name=lag,proto3" json:"lag,omitempty"`
+	// The member assigned the partition, if any.
+	MemberId             string   `protobuf:"bytes,6,opt,name=member_id,json=memberId,proto3" json:"member_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PartitionLag) Reset()         { *m = PartitionLag{} }
+func (m *PartitionLag) String() string { return proto.CompactTextString(m) }
+func (*PartitionLag) ProtoMessage()    {}
+func (*PartitionLag) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{38}
+}
+
+func (m *PartitionLag) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PartitionLag.Unmarshal(m, b)
+}
+func (m *PartitionLag) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PartitionLag.Marshal(b, m, deterministic)
+}
+func (m *PartitionLag) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PartitionLag.Merge(m, src)
+}
+func (m *PartitionLag) XXX_Size() int {
+	return xxx_messageInfo_PartitionLag.Size(m)
+}
+func (m *PartitionLag) XXX_DiscardUnknown() {
+	xxx_messageInfo_PartitionLag.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PartitionLag proto.InternalMessageInfo
+
+func (m *PartitionLag) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *PartitionLag) GetPartition() uint32 {
+	if m != nil {
+		return m.Partition
+	}
+	return 0
+}
+
+func (m *PartitionLag) GetCommittedOffset() int64 {
+	if m != nil {
+		return m.CommittedOffset
+	}
+	return 0
+}
+
+func (m *PartitionLag) GetEndOffset() int64 {
+	if m != nil {
+		return m.EndOffset
+	}
+	return 0
+}
+
+func (m *PartitionLag) GetLag() int64 {
+	if m != nil {
+		return m.Lag
+	}
+	return 0
+}
+
+func (m *PartitionLag) GetMemberId() string {
+	if m != nil {
+		return m.MemberId
+	}
+	return ""
+}
+
+type OffsetResetRequest struct {
+	Name       string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Topic      string   `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	Partitions []uint32 `protobuf:"varint,3,rep,packed,name=partitions,proto3" json:"partitions,omitempty"`
+	// The offsets to reset to: earliest, latest, timestamp
+	// (the earliest offset at or after the timestamp field,
+	// in milliseconds) or offset (the offset field).
+	To        string `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	Timestamp int64  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Offset    int64  `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	DryRun    bool   `protobuf:"varint,7,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster              string   `protobuf:"bytes,8,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OffsetResetRequest) Reset()         { *m = OffsetResetRequest{} }
+func (m *OffsetResetRequest) String() string { return proto.CompactTextString(m) }
+func (*OffsetResetRequest) ProtoMessage()    {}
+func (*OffsetResetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{39}
+}
+
+func (m *OffsetResetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OffsetResetRequest.Unmarshal(m, b)
+}
+func (m *OffsetResetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OffsetResetRequest.Marshal(b, m, deterministic)
+}
+func (m *OffsetResetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OffsetResetRequest.Merge(m, src)
+}
+func (m *OffsetResetRequest) XXX_Size() int {
+	return xxx_messageInfo_OffsetResetRequest.Size(m)
+}
+func (m *OffsetResetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OffsetResetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OffsetResetRequest proto.InternalMessageInfo
+
+func (m *OffsetResetRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *OffsetResetRequest) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *OffsetResetRequest) GetPartitions() []uint32 {
+	if m != nil {
+		return m.Partitions
+	}
+	return nil
+}
+
+func (m *OffsetResetRequest) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *OffsetResetRequest) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *OffsetResetRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *OffsetResetRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *OffsetResetRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type OffsetResetResponse struct {
+	// Partitions sorted by partition.
+	Partitions           []*PartitionOffsetReset `protobuf:"bytes,1,rep,name=partitions,proto3" json:"partitions,omitempty"`
+	DryRun               bool                    `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *OffsetResetResponse) Reset()         { *m = OffsetResetResponse{} }
+func (m *OffsetResetResponse) String() string { return proto.CompactTextString(m) }
+func (*OffsetResetResponse) ProtoMessage()    {}
+func (*OffsetResetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{40}
+}
+
+func (m *OffsetResetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OffsetResetResponse.Unmarshal(m, b)
+}
+func (m *OffsetResetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OffsetResetResponse.Marshal(b, m, deterministic)
+}
+func (m *OffsetResetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OffsetResetResponse.Merge(m, src)
+}
+func (m *OffsetResetResponse) XXX_Size() int {
+	return xxx_messageInfo_OffsetResetResponse.Size(m)
+}
+func (m *OffsetResetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_OffsetResetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OffsetResetResponse proto.InternalMessageInfo
+
+func (m *OffsetResetResponse) GetPartitions() []*PartitionOffsetReset {
+	if m != nil {
+		return m.Partitions
+	}
+	return nil
+}
+
+func (m *OffsetResetResponse) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type PartitionOffsetReset struct {
+	Topic     string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Partition uint32 `protobuf:"varint,2,opt,name=partition,proto3" json:"partition,omitempty"`
+	// -1 if the group had no committed offset.
+	PreviousOffset       int64    `protobuf:"varint,3,opt,name=previous_offset,json=previousOffset,proto3" json:"previous_offset,omitempty"`
+	NewOffset            int64    `protobuf:"varint,4,opt,name=new_offset,json=newOffset,proto3" json:"new_offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PartitionOffsetReset) Reset()         { *m = PartitionOffsetReset{} }
+func (m *PartitionOffsetReset) String() string { return proto.CompactTextString(m) }
+func (*PartitionOffsetReset) ProtoMessage()    {}
+func (*PartitionOffsetReset) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{41}
+}
+
+func (m *PartitionOffsetReset) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PartitionOffsetReset.Unmarshal(m, b)
+}
+func (m *PartitionOffsetReset) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PartitionOffsetReset.Marshal(b, m, deterministic)
+}
+func (m *PartitionOffsetReset) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PartitionOffsetReset.Merge(m, src)
+}
+func (m *PartitionOffsetReset) XXX_Size() int {
+	return xxx_messageInfo_PartitionOffsetReset.Size(m)
+}
+func (m *PartitionOffsetReset) XXX_DiscardUnknown() {
+	xxx_messageInfo_PartitionOffsetReset.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PartitionOffsetReset proto.InternalMessageInfo
+
+func (m *PartitionOffsetReset) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *PartitionOffsetReset) GetPartition() uint32 {
+	if m != nil {
+		return m.Partition
+	}
+	return 0
+}
+
+func (m *PartitionOffsetReset) GetPreviousOffset() int64 {
+	if m != nil {
+		return m.PreviousOffset
+	}
+	return 0
+}
+
+func (m *PartitionOffsetReset) GetNewOffset() int64 {
+	if m != nil {
+		return m.NewOffset
+	}
+	return 0
+}
+
+type ReassignmentRequest struct {
+	// The operation to plan: rebuild or rebalance.
+	Operation string `protobuf:"bytes,1,opt,name=operation,proto3" json:"operation,omitempty"`
+	// Topic name regular expressions.
+	Topics []string `protobuf:"bytes,2,rep,name=topics,proto3" json:"topics,omitempty"`
+	// Target broker IDs; -1 expands to
+	// all currently mapped brokers.
+	Brokers []int32 `protobuf:"varint,3,rep,packed,name=brokers,proto3" json:"brokers,omitempty"`
+	// Rebuild params.
+	Strategy         string `protobuf:"bytes,4,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	Optimization     string `protobuf:"bytes,5,opt,name=optimization,proto3" json:"optimization,omitempty"`
+	Replication      uint32 `protobuf:"varint,6,opt,name=replication,proto3" json:"replication,omitempty"`
+	MinUniqueRackIds uint32 `protobuf:"varint,7,opt,name=min_unique_rack_ids,json=minUniqueRackIds,proto3" json:"min_unique_rack_ids,omitempty"`
+	ForceRebuild     bool   `protobuf:"varint,8,opt,name=force_rebuild,json=forceRebuild,proto3" json:"force_rebuild,omitempty"`
+	// Rebalance params.
+	StorageThreshold       float64 `protobuf:"fixed64,9,opt,name=storage_threshold,json=storageThreshold,proto3" json:"storage_threshold,omitempty"`
+	StorageThresholdGb     float64 `protobuf:"fixed64,10,opt,name=storage_threshold_gb,json=storageThresholdGb,proto3" json:"storage_threshold_gb,omitempty"`
+	Tolerance              float64 `protobuf:"fixed64,11,opt,name=tolerance,proto3" json:"tolerance,omitempty"`
+	PartitionLimit         uint32  `protobuf:"varint,12,opt,name=partition_limit,json=partitionLimit,proto3" json:"partition_limit,omitempty"`
+	PartitionSizeThreshold uint32  `protobuf:"varint,13,opt,name=partition_size_threshold,json=partitionSizeThreshold,proto3" json:"partition_size_threshold,omitempty"`
+	LocalityScoped         bool    `protobuf:"varint,14,opt,name=locality_scoped,json=localityScoped,proto3" json:"locality_scoped,omitempty"`
+	// Common params.
+	OptimizeLeadership bool `protobuf:"varint,15,opt,name=optimize_leadership,json=optimizeLeadership,proto3" json:"optimize_leadership,omitempty"`
+	IncludeInternal    bool `protobuf:"varint,16,opt,name=include_internal,json=includeInternal,proto3" json:"include_internal,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster              string   `protobuf:"bytes,17,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReassignmentRequest) Reset()         { *m = ReassignmentRequest{} }
+func (m *ReassignmentRequest) String() string { return proto.CompactTextString(m) }
+func (*ReassignmentRequest) ProtoMessage()    {}
+func (*ReassignmentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{42}
+}
+
+func (m *ReassignmentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReassignmentRequest.Unmarshal(m, b)
+}
+func (m *ReassignmentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReassignmentRequest.Marshal(b, m, deterministic)
+}
+func (m *ReassignmentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReassignmentRequest.Merge(m, src)
+}
+func (m *ReassignmentRequest) XXX_Size() int {
+	return xxx_messageInfo_ReassignmentRequest.Size(m)
+}
+func (m *ReassignmentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReassignmentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReassignmentRequest proto.InternalMessageInfo
+
+func (m *ReassignmentRequest) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *ReassignmentRequest) GetTopics() []string {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *ReassignmentRequest) GetBrokers() []int32 {
+	if m != nil {
+		return m.Brokers
+	}
+	return nil
+}
+
+func (m *ReassignmentRequest) GetStrategy() string {
+	if m != nil {
+		return m.Strategy
+	}
+	return ""
+}
+
+func (m *ReassignmentRequest) GetOptimization() string {
+	if m != nil {
+		return m.Optimization
+	}
+	return ""
+}
+
+func (m *ReassignmentRequest) GetReplication() uint32 {
+	if m != nil {
+		return m.Replication
+	}
+	return 0
+}
+
+func (m *ReassignmentRequest) GetMinUniqueRackIds() uint32 {
+	if m != nil {
+		return m.MinUniqueRackIds
+	}
+	return 0
+}
+
+func (m *ReassignmentRequest) GetForceRebuild() bool {
+	if m != nil {
+		return m.ForceRebuild
+	}
+	return false
+}
+
+func (m *ReassignmentRequest) GetStorageThreshold() float64 {
+	if m != nil {
+		return m.StorageThreshold
+	}
+	return 0
+}
+
+func (m *ReassignmentRequest) GetStorageThresholdGb() float64 {
+	if m != nil {
+		return m.StorageThresholdGb
+	}
+	return 0
+}
+
+func (m *ReassignmentRequest) GetTolerance() float64 {
+	if m != nil {
+		return m.Tolerance
+	}
+	return 0
+}
+
+func (m *ReassignmentRequest) GetPartitionLimit() uint32 {
+	if m != nil {
+		return m.PartitionLimit
+	}
+	return 0
+}
+
+func (m *ReassignmentRequest) GetPartitionSizeThreshold() uint32 {
+	if m != nil {
+		return m.PartitionSizeThreshold
+	}
+	return 0
+}
+
+func (m *ReassignmentRequest) GetLocalityScoped() bool {
+	if m != nil {
+		return m.LocalityScoped
+	}
+	return false
+}
+
+func (m *ReassignmentRequest) GetOptimizeLeadership() bool {
+	if m != nil {
+		return m.OptimizeLeadership
+	}
+	return false
+}
+
+func (m *ReassignmentRequest) GetIncludeInternal() bool {
+	if m != nil {
+		return m.IncludeInternal
+	}
+	return false
+}
+
+func (m *ReassignmentRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type ReassignmentPlan struct {
+	// The ID to execute the plan with;
+	// empty if the plan has no changes.
+	Id        string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Operation string `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`
+	// Unix timestamp (seconds) after
+	// which the plan can't be executed.
+	Expires int64 `protobuf:"varint,3,opt,name=expires,proto3" json:"expires,omitempty"`
+	// Changed partitions only.
+	Partitions           []*PartitionReassignment `protobuf:"bytes,4,rep,name=partitions,proto3" json:"partitions,omitempty"`
+	Stats                *ReassignmentStats       `protobuf:"bytes,5,opt,name=stats,proto3" json:"stats,omitempty"`
+	Warnings             []string                 `protobuf:"bytes,6,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *ReassignmentPlan) Reset()         { *m = ReassignmentPlan{} }
+func (m *ReassignmentPlan) String() string { return proto.CompactTextString(m) }
+func (*ReassignmentPlan) ProtoMessage()    {}
+func (*ReassignmentPlan) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{43}
+}
+
+func (m *ReassignmentPlan) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReassignmentPlan.Unmarshal(m, b)
+}
+func (m *ReassignmentPlan) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReassignmentPlan.Marshal(b, m, deterministic)
+}
+func (m *ReassignmentPlan) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReassignmentPlan.Merge(m, src)
+}
+func (m *ReassignmentPlan) XXX_Size() int {
+	return xxx_messageInfo_ReassignmentPlan.Size(m)
+}
+func (m *ReassignmentPlan) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReassignmentPlan.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReassignmentPlan proto.InternalMessageInfo
+
+func (m *ReassignmentPlan) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ReassignmentPlan) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *ReassignmentPlan) GetExpires() int64 {
+	if m != nil {
+		return m.Expires
+	}
+	return 0
+}
+
+func (m *ReassignmentPlan) GetPartitions() []*PartitionReassignment {
+	if m != nil {
+		return m.Partitions
+	}
+	return nil
+}
+
+func (m *ReassignmentPlan) GetStats() *ReassignmentStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+func (m *ReassignmentPlan) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
+type PartitionReassignment struct {
+	Topic                string   `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Partition            uint32   `protobuf:"varint,2,opt,name=partition,proto3" json:"partition,omitempty"`
+	Replicas             []uint32 `protobuf:"varint,3,rep,packed,name=replicas,proto3" json:"replicas,omitempty"`
+	PlannedReplicas      []uint32 `protobuf:"varint,4,rep,packed,name=planned_replicas,json=plannedReplicas,proto3" json:"planned_replicas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PartitionReassignment) Reset()         { *m = PartitionReassignment{} }
+func (m *PartitionReassignment) String() string { return proto.CompactTextString(m) }
+func (*PartitionReassignment) ProtoMessage()    {}
+func (*PartitionReassignment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{44}
+}
+
+func (m *PartitionReassignment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PartitionReassignment.Unmarshal(m, b)
+}
+func (m *PartitionReassignment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PartitionReassignment.Marshal(b, m, deterministic)
+}
+func (m *PartitionReassignment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PartitionReassignment.Merge(m, src)
+}
+func (m *PartitionReassignment) XXX_Size() int {
+	return xxx_messageInfo_PartitionReassignment.Size(m)
+}
+func (m *PartitionReassignment) XXX_DiscardUnknown() {
+	xxx_messageInfo_PartitionReassignment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PartitionReassignment proto.InternalMessageInfo
+
+func (m *PartitionReassignment) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *PartitionReassignment) GetPartition() uint32 {
+	if m != nil {
+		return m.Partition
+	}
+	return 0
+}
+
+func (m *PartitionReassignment) GetReplicas() []uint32 {
+	if m != nil {
+		return m.Replicas
+	}
+	return nil
+}
+
+func (m *PartitionReassignment) GetPlannedReplicas() []uint32 {
+	if m != nil {
+		return m.PlannedReplicas
+	}
+	return nil
+}
+
+type ReassignmentStats struct {
+	Partitions      uint32 `protobuf:"varint,1,opt,name=partitions,proto3" json:"partitions,omitempty"`
+	PartitionsMoved uint32 `protobuf:"varint,2,opt,name=partitions_moved,json=partitionsMoved,proto3" json:"partitions_moved,omitempty"`
+	ReplicasMoved   uint32 `protobuf:"varint,3,opt,name=replicas_moved,json=replicasMoved,proto3" json:"replicas_moved,omitempty"`
+	// Storage stats in bytes; only populated
+	// if partition metrics are available.
+	BytesMoved           float64  `protobuf:"fixed64,4,opt,name=bytes_moved,json=bytesMoved,proto3" json:"bytes_moved,omitempty"`
+	StorageRangeBefore   float64  `protobuf:"fixed64,5,opt,name=storage_range_before,json=storageRangeBefore,proto3" json:"storage_range_before,omitempty"`
+	StorageRangeAfter    float64  `protobuf:"fixed64,6,opt,name=storage_range_after,json=storageRangeAfter,proto3" json:"storage_range_after,omitempty"`
+	StorageStddevBefore  float64  `protobuf:"fixed64,7,opt,name=storage_stddev_before,json=storageStddevBefore,proto3" json:"storage_stddev_before,omitempty"`
+	StorageStddevAfter   float64  `protobuf:"fixed64,8,opt,name=storage_stddev_after,json=storageStddevAfter,proto3" json:"storage_stddev_after,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReassignmentStats) Reset()         { *m = ReassignmentStats{} }
+func (m *ReassignmentStats) String() string { return proto.CompactTextString(m) }
+func (*ReassignmentStats) ProtoMessage()    {}
+func (*ReassignmentStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{45}
+}
+
+func (m *ReassignmentStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReassignmentStats.Unmarshal(m, b)
+}
+func (m *ReassignmentStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReassignmentStats.Marshal(b, m, deterministic)
+}
+func (m *ReassignmentStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReassignmentStats.Merge(m, src)
+}
+func (m *ReassignmentStats) XXX_Size() int {
+	return xxx_messageInfo_ReassignmentStats.Size(m)
+}
+func (m *ReassignmentStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReassignmentStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReassignmentStats proto.InternalMessageInfo
+
+func (m *ReassignmentStats) GetPartitions() uint32 {
+	if m != nil {
+		return m.Partitions
+	}
+	return 0
+}
+
+func (m *ReassignmentStats) GetPartitionsMoved() uint32 {
+	if m != nil {
+		return m.PartitionsMoved
+	}
+	return 0
+}
+
+func (m *ReassignmentStats) GetReplicasMoved() uint32 {
+	if m != nil {
+		return m.ReplicasMoved
+	}
+	return 0
+}
+
+func (m *ReassignmentStats) GetBytesMoved() float64 {
+	if m != nil {
+		return m.BytesMoved
+	}
+	return 0
+}
+
+func (m *ReassignmentStats) GetStorageRangeBefore() float64 {
+	if m != nil {
+		return m.StorageRangeBefore
+	}
+	return 0
+}
+
+func (m *ReassignmentStats) GetStorageRangeAfter() float64 {
+	if m != nil {
+		return m.StorageRangeAfter
+	}
+	return 0
+}
+
+func (m *ReassignmentStats) GetStorageStddevBefore() float64 {
+	if m != nil {
+		return m.StorageStddevBefore
+	}
+	return 0
+}
+
+func (m *ReassignmentStats) GetStorageStddevAfter() float64 {
+	if m != nil {
+		return m.StorageStddevAfter
+	}
+	return 0
+}
+
+type ReassignmentExecuteRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster              string   `protobuf:"bytes,2,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReassignmentExecuteRequest) Reset()         { *m = ReassignmentExecuteRequest{} }
+func (m *ReassignmentExecuteRequest) String() string { return proto.CompactTextString(m) }
+func (*ReassignmentExecuteRequest) ProtoMessage()    {}
+func (*ReassignmentExecuteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{46}
+}
+
+func (m *ReassignmentExecuteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReassignmentExecuteRequest.Unmarshal(m, b)
+}
+func (m *ReassignmentExecuteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReassignmentExecuteRequest.Marshal(b, m, deterministic)
+}
+func (m *ReassignmentExecuteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReassignmentExecuteRequest.Merge(m, src)
+}
+func (m *ReassignmentExecuteRequest) XXX_Size() int {
+	return xxx_messageInfo_ReassignmentExecuteRequest.Size(m)
+}
+func (m *ReassignmentExecuteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReassignmentExecuteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReassignmentExecuteRequest proto.InternalMessageInfo
+
+func (m *ReassignmentExecuteRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ReassignmentExecuteRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type ReassignmentProgress struct {
+	Id         string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Partitions uint32 `protobuf:"varint,2,opt,name=partitions,proto3" json:"partitions,omitempty"`
+	// Partitions still being reassigned.
+	Remaining            uint32   `protobuf:"varint,3,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	Complete             bool     `protobuf:"varint,4,opt,name=complete,proto3" json:"complete,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReassignmentProgress) Reset()         { *m = ReassignmentProgress{} }
+func (m *ReassignmentProgress) String() string { return proto.CompactTextString(m) }
+func (*ReassignmentProgress) ProtoMessage()    {}
+func (*ReassignmentProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{47}
+}
+
+func (m *ReassignmentProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReassignmentProgress.Unmarshal(m, b)
+}
+func (m *ReassignmentProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReassignmentProgress.Marshal(b, m, deterministic)
+}
+func (m *ReassignmentProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReassignmentProgress.Merge(m, src)
+}
+func (m *ReassignmentProgress) XXX_Size() int {
+	return xxx_messageInfo_ReassignmentProgress.Size(m)
+}
+func (m *ReassignmentProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReassignmentProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReassignmentProgress proto.InternalMessageInfo
+
+func (m *ReassignmentProgress) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ReassignmentProgress) GetPartitions() uint32 {
+	if m != nil {
+		return m.Partitions
+	}
+	return 0
+}
+
+func (m *ReassignmentProgress) GetRemaining() uint32 {
+	if m != nil {
+		return m.Remaining
+	}
+	return 0
+}
+
+func (m *ReassignmentProgress) GetComplete() bool {
+	if m != nil {
+		return m.Complete
+	}
+	return false
+}
+
+type WatchRequest struct {
+	Types []string `protobuf:"bytes,1,rep,name=types,proto3" json:"types,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster              string   `protobuf:"bytes,2,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{48}
+}
+
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(m, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRequest.Size(m)
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+func (m *WatchRequest) GetTypes() []string {
+	if m != nil {
+		return m.Types
+	}
+	return nil
+}
+
+func (m *WatchRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type WatchEvent struct {
+	// broker, topic, config or tag.
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// added, removed or changed.
+	Action string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	// The broker ID, topic name, or for config and tag
+	// changes, the entity path (e.g. topics/<name>).
+	Name string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Unix timestamp (seconds) of when the
+	// change was observed.
+	Timestamp int64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The cluster the change was observed in.
+	Cluster              string   `protobuf:"bytes,5,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchEvent) Reset()         { *m = WatchEvent{} }
+func (m *WatchEvent) String() string { return proto.CompactTextString(m) }
+func (*WatchEvent) ProtoMessage()    {}
+func (*WatchEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{49}
+}
+
+func (m *WatchEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchEvent.Unmarshal(m, b)
+}
+func (m *WatchEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchEvent.Marshal(b, m, deterministic)
+}
+func (m *WatchEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchEvent.Merge(m, src)
+}
+func (m *WatchEvent) XXX_Size() int {
+	return xxx_messageInfo_WatchEvent.Size(m)
+}
+func (m *WatchEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchEvent proto.InternalMessageInfo
+
+func (m *WatchEvent) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *WatchEvent) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *WatchEvent) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *WatchEvent) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *WatchEvent) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type AuditLogRequest struct {
+	// The changed resource, e.g. topics/<name>
+	// or brokers/<id>.
+	Resource string `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
+	Identity string `protobuf:"bytes,2,opt,name=identity,proto3" json:"identity,omitempty"`
+	// The full method name, e.g.
+	// /registry.Registry/TagTopic.
+	Method string `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	// Unix timestamp (seconds) range, inclusive;
+	// unbounded if 0.
+	Since int64 `protobuf:"varint,4,opt,name=since,proto3" json:"since,omitempty"`
+	Until int64 `protobuf:"varint,5,opt,name=until,proto3" json:"until,omitempty"`
+	// Defaults to 100.
+	Limit uint32 `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster              string   `protobuf:"bytes,7,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditLogRequest) Reset()         { *m = AuditLogRequest{} }
+func (m *AuditLogRequest) String() string { return proto.CompactTextString(m) }
+func (*AuditLogRequest) ProtoMessage()    {}
+func (*AuditLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{50}
+}
+
+func (m *AuditLogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditLogRequest.Unmarshal(m, b)
+}
+func (m *AuditLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditLogRequest.Marshal(b, m, deterministic)
+}
+func (m *AuditLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditLogRequest.Merge(m, src)
+}
+func (m *AuditLogRequest) XXX_Size() int {
+	return xxx_messageInfo_AuditLogRequest.Size(m)
+}
+func (m *AuditLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditLogRequest proto.InternalMessageInfo
+
+func (m *AuditLogRequest) GetResource() string {
+	if m != nil {
+		return m.Resource
+	}
+	return ""
+}
+
+func (m *AuditLogRequest) GetIdentity() string {
+	if m != nil {
+		return m.Identity
+	}
+	return ""
+}
+
+func (m *AuditLogRequest) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *AuditLogRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *AuditLogRequest) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+func (m *AuditLogRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *AuditLogRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type AuditLogResponse struct {
+	Entries              []*AuditEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *AuditLogResponse) Reset()         { *m = AuditLogResponse{} }
+func (m *AuditLogResponse) String() string { return proto.CompactTextString(m) }
+func (*AuditLogResponse) ProtoMessage()    {}
+func (*AuditLogResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{51}
+}
+
+func (m *AuditLogResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditLogResponse.Unmarshal(m, b)
+}
+func (m *AuditLogResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditLogResponse.Marshal(b, m, deterministic)
+}
+func (m *AuditLogResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditLogResponse.Merge(m, src)
+}
+func (m *AuditLogResponse) XXX_Size() int {
+	return xxx_messageInfo_AuditLogResponse.Size(m)
+}
+func (m *AuditLogResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditLogResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditLogResponse proto.InternalMessageInfo
+
+func (m *AuditLogResponse) GetEntries() []*AuditEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type AuditEntry struct {
+	// Unix timestamp (seconds).
+	Timestamp int64  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Requestor string `protobuf:"bytes,2,opt,name=requestor,proto3" json:"requestor,omitempty"`
+	Identity  string `protobuf:"bytes,3,opt,name=identity,proto3" json:"identity,omitempty"`
+	Method    string `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
+	Resource  string `protobuf:"bytes,5,opt,name=resource,proto3" json:"resource,omitempty"`
+	Change    string `protobuf:"bytes,6,opt,name=change,proto3" json:"change,omitempty"`
+	// The JSON encoded state of the resource before
+	// and after the change; empty if it didn't exist.
+	Before string `protobuf:"bytes,7,opt,name=before,proto3" json:"before,omitempty"`
+	After  string `protobuf:"bytes,8,opt,name=after,proto3" json:"after,omitempty"`
+	// The cluster of the changed resource.
+	Cluster              string   `protobuf:"bytes,9,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditEntry) Reset()         { *m = AuditEntry{} }
+func (m *AuditEntry) String() string { return proto.CompactTextString(m) }
+func (*AuditEntry) ProtoMessage()    {}
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{52}
+}
+
+func (m *AuditEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditEntry.Unmarshal(m, b)
+}
+func (m *AuditEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditEntry.Marshal(b, m, deterministic)
+}
+func (m *AuditEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEntry.Merge(m, src)
+}
+func (m *AuditEntry) XXX_Size() int {
+	return xxx_messageInfo_AuditEntry.Size(m)
+}
+func (m *AuditEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEntry proto.InternalMessageInfo
+
+func (m *AuditEntry) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *AuditEntry) GetRequestor() string {
+	if m != nil {
+		return m.Requestor
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetIdentity() string {
+	if m != nil {
+		return m.Identity
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetResource() string {
+	if m != nil {
+		return m.Resource
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetChange() string {
+	if m != nil {
+		return m.Change
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetBefore() string {
+	if m != nil {
+		return m.Before
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type LifecycleRequest struct {
+	// Evaluate only the named rule; all rules if empty.
+	Rule string `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	// Report the actions that would be taken
+	// without taking them (EnforceLifecycle only).
+	DryRun bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// The federated cluster; the default cluster if empty.
+	Cluster              string   `protobuf:"bytes,3,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LifecycleRequest) Reset()         { *m = LifecycleRequest{} }
+func (m *LifecycleRequest) String() string { return proto.CompactTextString(m) }
+func (*LifecycleRequest) ProtoMessage()    {}
+func (*LifecycleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{53}
+}
+
+func (m *LifecycleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LifecycleRequest.Unmarshal(m, b)
+}
+func (m *LifecycleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LifecycleRequest.Marshal(b, m, deterministic)
+}
+func (m *LifecycleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LifecycleRequest.Merge(m, src)
+}
+func (m *LifecycleRequest) XXX_Size() int {
+	return xxx_messageInfo_LifecycleRequest.Size(m)
+}
+func (m *LifecycleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LifecycleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LifecycleRequest proto.InternalMessageInfo
+
+func (m *LifecycleRequest) GetRule() string {
+	if m != nil {
+		return m.Rule
+	}
+	return ""
+}
+
+func (m *LifecycleRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *LifecycleRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type LifecycleReport struct {
+	Violations           []*LifecycleViolation `protobuf:"bytes,1,rep,name=violations,proto3" json:"violations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *LifecycleReport) Reset()         { *m = LifecycleReport{} }
+func (m *LifecycleReport) String() string { return proto.CompactTextString(m) }
+func (*LifecycleReport) ProtoMessage()    {}
+func (*LifecycleReport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{54}
+}
+
+func (m *LifecycleReport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LifecycleReport.Unmarshal(m, b)
+}
+func (m *LifecycleReport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LifecycleReport.Marshal(b, m, deterministic)
+}
+func (m *LifecycleReport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LifecycleReport.Merge(m, src)
+}
+func (m *LifecycleReport) XXX_Size() int {
+	return xxx_messageInfo_LifecycleReport.Size(m)
+}
+func (m *LifecycleReport) XXX_DiscardUnknown() {
+	xxx_messageInfo_LifecycleReport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LifecycleReport proto.InternalMessageInfo
+
+func (m *LifecycleReport) GetViolations() []*LifecycleViolation {
+	if m != nil {
+		return m.Violations
+	}
+	return nil
+}
+
+type LifecycleViolation struct {
+	Rule        string `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Topic       string `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// The action of the rule, if any
+	// (delete or cap_retention).
+	Action string `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	// Whether the action was taken
+	// (EnforceLifecycle only).
+	Enforced bool `protobuf:"varint,5,opt,name=enforced,proto3" json:"enforced,omitempty"`
+	// Why the action wasn't taken, e.g.
+	// failed topic deletion checks.
+	Skipped              string   `protobuf:"bytes,6,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LifecycleViolation) Reset()         { *m = LifecycleViolation{} }
+func (m *LifecycleViolation) String() string { return proto.CompactTextString(m) }
+func (*LifecycleViolation) ProtoMessage()    {}
+func (*LifecycleViolation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{55}
+}
+
+func (m *LifecycleViolation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LifecycleViolation.Unmarshal(m, b)
+}
+func (m *LifecycleViolation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LifecycleViolation.Marshal(b, m, deterministic)
+}
+func (m *LifecycleViolation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LifecycleViolation.Merge(m, src)
+}
+func (m *LifecycleViolation) XXX_Size() int {
+	return xxx_messageInfo_LifecycleViolation.Size(m)
+}
+func (m *LifecycleViolation) XXX_DiscardUnknown() {
+	xxx_messageInfo_LifecycleViolation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LifecycleViolation proto.InternalMessageInfo
+
+func (m *LifecycleViolation) GetRule() string {
+	if m != nil {
+		return m.Rule
+	}
+	return ""
+}
+
+func (m *LifecycleViolation) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *LifecycleViolation) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *LifecycleViolation) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *LifecycleViolation) GetEnforced() bool {
+	if m != nil {
+		return m.Enforced
+	}
+	return false
+}
+
+func (m *LifecycleViolation) GetSkipped() string {
+	if m != nil {
+		return m.Skipped
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*TagResponse)(nil), "registry.TagResponse")
+	proto.RegisterType((*BrokerRequest)(nil), "registry.BrokerRequest")
+	proto.RegisterType((*BrokerResponse)(nil), "registry.BrokerResponse")
+	proto.RegisterMapType((map[uint32]*Broker)(nil), "registry.BrokerResponse.BrokersEntry")
+	proto.RegisterType((*Broker)(nil), "registry.Broker")
+	proto.RegisterMapType((map[string]string)(nil), "registry.Broker.ListenersecurityprotocolmapEntry")
+	proto.RegisterMapType((map[string]string)(nil), "registry.Broker.TagsEntry")
+	proto.RegisterType((*TopicRequest)(nil), "registry.TopicRequest")
+	proto.RegisterType((*TopicResponse)(nil), "registry.TopicResponse")
+	proto.RegisterMapType((map[string]*Topic)(nil), "registry.TopicResponse.TopicsEntry")
+	proto.RegisterType((*Topic)(nil), "registry.Topic")
+	proto.RegisterMapType((map[string]string)(nil), "registry.Topic.TagsEntry")
+	proto.RegisterType((*SchemaInfo)(nil), "registry.SchemaInfo")
+	proto.RegisterType((*TopicConfigRequest)(nil), "registry.TopicConfigRequest")
+	proto.RegisterMapType((map[string]string)(nil), "registry.TopicConfigRequest.ConfigsEntry")
+	proto.RegisterType((*TopicConfigResponse)(nil), "registry.TopicConfigResponse")
+	proto.RegisterMapType((map[string]string)(nil), "registry.TopicConfigResponse.ConfigsEntry")
+	proto.RegisterType((*TopicDeleteRequest)(nil), "registry.TopicDeleteRequest")
+	proto.RegisterType((*TopicDeleteResponse)(nil), "registry.TopicDeleteResponse")
+	proto.RegisterType((*TopicSpec)(nil), "registry.TopicSpec")
+	proto.RegisterMapType((map[string]string)(nil), "registry.TopicSpec.ConfigsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "registry.TopicSpec.TagsEntry")
+	proto.RegisterType((*TopicSchema)(nil), "registry.TopicSchema")
+	proto.RegisterType((*TopicTemplate)(nil), "registry.TopicTemplate")
+	proto.RegisterMapType((map[string]string)(nil), "registry.TopicTemplate.ConfigsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "registry.TopicTemplate.TagsEntry")
+	proto.RegisterType((*CreateTopicsRequest)(nil), "registry.CreateTopicsRequest")
+	proto.RegisterType((*CreateTopicsResponse)(nil), "registry.CreateTopicsResponse")
+	proto.RegisterType((*PlacementRequest)(nil), "registry.PlacementRequest")
+	proto.RegisterType((*PlacementPreview)(nil), "registry.PlacementPreview")
+	proto.RegisterMapType((map[uint32]*BrokerPlacement)(nil), "registry.PlacementPreview.BrokersEntry")
+	proto.RegisterType((*PartitionPlacement)(nil), "registry.PartitionPlacement")
+	proto.RegisterType((*BrokerPlacement)(nil), "registry.BrokerPlacement")
+	proto.RegisterType((*ClusterStateRequest)(nil), "registry.ClusterStateRequest")
+	proto.RegisterType((*ClusterStateResponse)(nil), "registry.ClusterStateResponse")
+	proto.RegisterType((*UtilizationRequest)(nil), "registry.UtilizationRequest")
+	proto.RegisterType((*UtilizationResponse)(nil), "registry.UtilizationResponse")
+	proto.RegisterMapType((map[uint32]*BrokerUtilization)(nil), "registry.UtilizationResponse.BrokersEntry")
+	proto.RegisterMapType((map[string]*TopicUtilization)(nil), "registry.UtilizationResponse.TopicsEntry")
+	proto.RegisterType((*TopicUtilization)(nil), "registry.TopicUtilization")
+	proto.RegisterMapType((map[uint32]float64)(nil), "registry.TopicUtilization.PartitionsEntry")
+	proto.RegisterType((*BrokerUtilization)(nil), "registry.BrokerUtilization")
+	proto.RegisterType((*BrokerUtilizationRequest)(nil), "registry.BrokerUtilizationRequest")
+	proto.RegisterType((*BrokerUtilizationResponse)(nil), "registry.BrokerUtilizationResponse")
+	proto.RegisterMapType((map[uint32]*LiveBrokerUtilization)(nil), "registry.BrokerUtilizationResponse.BrokersEntry")
+	proto.RegisterType((*LiveBrokerUtilization)(nil), "registry.LiveBrokerUtilization")
+	proto.RegisterType((*QuotaRequest)(nil), "registry.QuotaRequest")
+	proto.RegisterType((*QuotaResponse)(nil), "registry.QuotaResponse")
+	proto.RegisterType((*Quota)(nil), "registry.Quota")
+	proto.RegisterType((*ConsumerGroupRequest)(nil), "registry.ConsumerGroupRequest")
+	proto.RegisterType((*ConsumerGroupResponse)(nil), "registry.ConsumerGroupResponse")
+	proto.RegisterMapType((map[string]*ConsumerGroup)(nil), "registry.ConsumerGroupResponse.GroupsEntry")
+	proto.RegisterType((*ConsumerGroup)(nil), "registry.ConsumerGroup")
+	proto.RegisterType((*GroupMember)(nil), "registry.GroupMember")
+	proto.RegisterType((*TopicPartitions)(nil), "registry.TopicPartitions")
+	proto.RegisterType((*PartitionLag)(nil), "registry.PartitionLag")
+	proto.RegisterType((*OffsetResetRequest)(nil), "registry.OffsetResetRequest")
+	proto.RegisterType((*OffsetResetResponse)(nil), "registry.OffsetResetResponse")
+	proto.RegisterType((*PartitionOffsetReset)(nil), "registry.PartitionOffsetReset")
+	proto.RegisterType((*ReassignmentRequest)(nil), "registry.ReassignmentRequest")
+	proto.RegisterType((*ReassignmentPlan)(nil), "registry.ReassignmentPlan")
+	proto.RegisterType((*PartitionReassignment)(nil), "registry.PartitionReassignment")
+	proto.RegisterType((*ReassignmentStats)(nil), "registry.ReassignmentStats")
+	proto.RegisterType((*ReassignmentExecuteRequest)(nil), "registry.ReassignmentExecuteRequest")
+	proto.RegisterType((*ReassignmentProgress)(nil), "registry.ReassignmentProgress")
+	proto.RegisterType((*WatchRequest)(nil), "registry.WatchRequest")
+	proto.RegisterType((*WatchEvent)(nil), "registry.WatchEvent")
+	proto.RegisterType((*AuditLogRequest)(nil), "registry.AuditLogRequest")
+	proto.RegisterType((*AuditLogResponse)(nil), "registry.AuditLogResponse")
+	proto.RegisterType((*AuditEntry)(nil), "registry.AuditEntry")
+	proto.RegisterType((*LifecycleRequest)(nil), "registry.LifecycleRequest")
+	proto.RegisterType((*LifecycleReport)(nil), "registry.LifecycleReport")
+	proto.RegisterType((*LifecycleViolation)(nil), "registry.LifecycleViolation")
+}
+
+func init() { proto.RegisterFile("protos/registry.proto", fileDescriptor_4215e5fe8e6d7e5d) }
+
+var fileDescriptor_4215e5fe8e6d7e5d = []byte{
+	// 4208 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x3b, 0x4d, 0x6f, 0x1c, 0xc9,
+	0x75, 0xe8, 0x19, 0xce, 0x70, 0xe6, 0xcd, 0x0c, 0x67, 0x58, 0xfc, 0x50, 0xb3, 0x57, 0xa2, 0xa8,
+	0xde, 0x0f, 0x69, 0xb5, 0x2b, 0x51, 0x92, 0xe1, 0x64, 0xb1, 0x76, 0xd6, 0xd0, 0x4a, 0xb2, 0xac,
+	0x85, 0xd6, 0x2b, 0xb7, 0x28, 0xaf, 0x9d, 0x1c, 0x26, 0xcd, 0xe9, 0xe2, 0xb0, 0xcd, 0x99, 0xee,
+	0xd9, 0xee, 0x1a, 0x8a, 0x5c, 0xc3, 0x40, 0x12, 0x6c, 0x0e, 0x41, 0x80, 0x1c, 0x92, 0x9c, 0x0c,
+	0x04, 0x59, 0x20, 0xc8, 0xc5, 0x01, 0xf2, 0x07, 0x82, 0x9c, 0x02, 0x04, 0xb9, 0x07, 0x48, 0x90,
+	0x4b, 0x4e, 0x39, 0xe5, 0x90, 0x5c, 0x6c, 0x20, 0xc7, 0xa0, 0x5e, 0x55, 0x75, 0x57, 0xf5, 0x74,
+	0x93, 0xbb, 0x54, 0x0e, 0xf1, 0x85, 0x98, 0x7a, 0x55, 0xfd, 0xde, 0xab, 0xf7, 0x55, 0xef, 0xbd,
+	0x2a, 0xc2, 0xc6, 0x2c, 0x89, 0x59, 0x9c, 0xee, 0x26, 0x74, 0x1c, 0xa6, 0x2c, 0x39, 0xbd, 0x8d,
+	0x63, 0xd2, 0x52, 0x63, 0xe7, 0xf2, 0x38, 0x8e, 0xc7, 0x13, 0xba, 0xeb, 0xcf, 0xc2, 0x5d, 0x3f,
+	0x8a, 0x62, 0xe6, 0xb3, 0x30, 0x8e, 0x52, 0xb1, 0xce, 0xbd, 0x0e, 0x9d, 0x3d, 0x7f, 0xec, 0xd1,
+	0x74, 0x16, 0x47, 0x29, 0x25, 0x36, 0x2c, 0x4f, 0x69, 0x9a, 0xfa, 0x63, 0x6a, 0x5b, 0x3b, 0xd6,
+	0x8d, 0xb6, 0xa7, 0x86, 0xee, 0x5f, 0x58, 0xd0, 0xfb, 0x30, 0x89, 0x8f, 0x68, 0xe2, 0xd1, 0xcf,
+	0xe6, 0x34, 0x65, 0x64, 0x00, 0x75, 0xe6, 0x8f, 0x6d, 0x6b, 0xa7, 0x7e, 0xa3, 0xed, 0xf1, 0x9f,
+	0x64, 0x05, 0x6a, 0x61, 0x60, 0xd7, 0x76, 0xac, 0x1b, 0x3d, 0xaf, 0x16, 0x06, 0x1c, 0xdb, 0x68,
+	0x32, 0x4f, 0x19, 0x4d, 0xec, 0xba, 0xc0, 0x26, 0x87, 0xe4, 0x35, 0x68, 0x33, 0x7f, 0x3c, 0xfc,
+	0x6c, 0x4e, 0x93, 0x53, 0x7b, 0x09, 0xe7, 0x5a, 0xcc, 0x1f, 0xff, 0x80, 0x8f, 0xc9, 0x3a, 0x34,
+	0x26, 0xe1, 0x34, 0x64, 0x76, 0x03, 0x31, 0x89, 0x01, 0xb9, 0x02, 0x30, 0xf3, 0xc7, 0x74, 0xc8,
+	0xe2, 0x23, 0x1a, 0xd9, 0x4d, 0xfc, 0xa6, 0xcd, 0x21, 0x7b, 0x1c, 0xe0, 0xfe, 0x9b, 0x05, 0x2b,
+	0x8a, 0x3f, 0xb9, 0x99, 0xef, 0xc0, 0xf2, 0x3e, 0x42, 0x52, 0xbb, 0xb1, 0x53, 0xbf, 0xd1, 0xb9,
+	0xf7, 0xe6, 0xed, 0x4c, 0x4a, 0xe6, 0x52, 0x39, 0x4c, 0x1f, 0x45, 0x2c, 0x39, 0xf5, 0xd4, 0x57,
+	0x7c, 0x87, 0x61, 0x90, 0xda, 0xcd, 0x9d, 0xfa, 0x8d, 0x9e, 0xc7, 0x7f, 0x92, 0xb7, 0xa0, 0x1f,
+	0xd1, 0x13, 0x36, 0xd4, 0x38, 0x59, 0x46, 0x4e, 0x7a, 0x1c, 0xfc, 0x4c, 0x71, 0xe3, 0x3c, 0x85,
+	0xae, 0x8e, 0x92, 0x63, 0x3a, 0xa2, 0xa7, 0x28, 0xd3, 0x9e, 0xc7, 0x7f, 0x92, 0xb7, 0xa0, 0x71,
+	0xec, 0x4f, 0xe6, 0x14, 0xc5, 0xd5, 0xb9, 0x37, 0x58, 0x60, 0x4d, 0x4c, 0xbf, 0x5f, 0x7b, 0xcf,
+	0x72, 0xff, 0x7c, 0x09, 0x9a, 0x02, 0x4a, 0x6e, 0xc3, 0x12, 0xf3, 0xc7, 0x29, 0x4a, 0xbd, 0x73,
+	0xcf, 0x29, 0x7e, 0x75, 0x7b, 0xcf, 0x1f, 0xcb, 0x5d, 0xe0, 0x3a, 0xa9, 0x92, 0x46, 0xa6, 0x92,
+	0x14, 0x5e, 0x9b, 0x84, 0x29, 0xa3, 0x11, 0x4d, 0x52, 0x3a, 0x9a, 0x27, 0x21, 0x3b, 0x45, 0x43,
+	0x18, 0xc5, 0x93, 0xa9, 0x3f, 0xc3, 0xad, 0x76, 0xee, 0xdd, 0x5d, 0x40, 0xfb, 0xb4, 0xfa, 0x1b,
+	0x41, 0xed, 0x2c, 0xac, 0xe4, 0x32, 0xb4, 0x69, 0x14, 0xcc, 0xe2, 0x30, 0x62, 0xa9, 0xbd, 0x8c,
+	0xf6, 0x92, 0x03, 0x08, 0x81, 0xa5, 0xc4, 0x1f, 0x1d, 0xd9, 0x2d, 0x14, 0x24, 0xfe, 0xe6, 0x96,
+	0xf3, 0x93, 0xe9, 0xc9, 0x2c, 0x4e, 0x98, 0xdd, 0x46, 0xde, 0xd5, 0x90, 0xaf, 0x3e, 0x8c, 0x53,
+	0x66, 0x83, 0x58, 0xcd, 0x7f, 0x73, 0xfc, 0x2c, 0x9c, 0xd2, 0x94, 0xf9, 0xd3, 0x99, 0xdd, 0xd9,
+	0xb1, 0x6e, 0xd4, 0xbd, 0x1c, 0xc0, 0xbf, 0x40, 0x44, 0x5d, 0x44, 0x84, 0xbf, 0x39, 0xfe, 0x63,
+	0x9a, 0xa4, 0x61, 0x1c, 0xd9, 0x3d, 0x81, 0x5f, 0x0e, 0xc9, 0x0e, 0x74, 0xa6, 0x7e, 0x18, 0x31,
+	0x1a, 0xf9, 0xd1, 0x88, 0xda, 0x2b, 0x3b, 0xd6, 0x8d, 0x96, 0xa7, 0x83, 0x9c, 0xdf, 0x84, 0x76,
+	0x26, 0x65, 0x5d, 0xb1, 0x6d, 0xa1, 0xd8, 0x75, 0x5d, 0xb1, 0x6d, 0x4d, 0x8d, 0xce, 0xf7, 0x61,
+	0xe7, 0x3c, 0x39, 0x7e, 0x1d, 0x7c, 0xdc, 0xe4, 0xbb, 0x7b, 0xf1, 0x2c, 0x1c, 0x55, 0x7b, 0x24,
+	0x81, 0xa5, 0xc8, 0x9f, 0xaa, 0x6f, 0xf1, 0x37, 0xdf, 0x7b, 0x3a, 0x3a, 0xa4, 0x53, 0x3f, 0x45,
+	0xaf, 0x6c, 0x79, 0x6a, 0xa8, 0xfb, 0xeb, 0xd2, 0x19, 0xfe, 0xda, 0x28, 0xf8, 0xeb, 0x15, 0x00,
+	0x8e, 0x78, 0x98, 0xd0, 0x31, 0x3d, 0x51, 0x9e, 0xc9, 0x21, 0x1e, 0x07, 0xe4, 0xee, 0xbc, 0x5c,
+	0xed, 0xce, 0xad, 0xa2, 0x3b, 0xff, 0x8b, 0x05, 0x3d, 0xb9, 0x37, 0xe9, 0xcd, 0xdf, 0x82, 0x26,
+	0xe3, 0x00, 0xe5, 0xcc, 0xaf, 0xe7, 0x46, 0x6a, 0x2c, 0x14, 0x23, 0xe9, 0x04, 0xf2, 0x13, 0xce,
+	0x03, 0x67, 0x48, 0xf8, 0x72, 0xdb, 0x13, 0x83, 0xaf, 0xec, 0xcd, 0x1f, 0x41, 0x47, 0x43, 0x5a,
+	0xa2, 0xa3, 0x37, 0x4d, 0x67, 0xee, 0x17, 0x59, 0xd3, 0x94, 0xf6, 0x97, 0x35, 0x68, 0x20, 0x90,
+	0xdc, 0x32, 0x5c, 0x79, 0xab, 0xf0, 0xcd, 0x82, 0x27, 0x2b, 0x55, 0x36, 0x34, 0x55, 0x6e, 0x73,
+	0x21, 0x26, 0x2c, 0xc4, 0x88, 0x8e, 0x92, 0xef, 0x79, 0x1a, 0x84, 0x1b, 0x73, 0x42, 0x67, 0x93,
+	0x70, 0x84, 0x31, 0x5f, 0x2a, 0x40, 0x07, 0x71, 0xc1, 0xc4, 0x2f, 0x23, 0x9a, 0x48, 0x0d, 0x88,
+	0x01, 0xa7, 0xc5, 0xa8, 0x3f, 0x45, 0xdf, 0x6b, 0x7b, 0xf8, 0x9b, 0xdc, 0xce, 0xcd, 0x06, 0x90,
+	0xe3, 0xf5, 0x9c, 0xe3, 0xe7, 0x38, 0xf1, 0x24, 0x3a, 0x88, 0x33, 0x63, 0xba, 0xb0, 0x9b, 0xb8,
+	0xbf, 0xb0, 0x00, 0x72, 0x84, 0x68, 0xae, 0xf3, 0xfd, 0x9f, 0xd0, 0x11, 0x53, 0x47, 0x92, 0x1c,
+	0x6a, 0xc7, 0x4d, 0x43, 0x1d, 0x37, 0xca, 0xa9, 0xeb, 0x08, 0x54, 0x43, 0xdc, 0xcf, 0xe9, 0x8c,
+	0x4a, 0xab, 0xc6, 0xdf, 0xe4, 0x0d, 0xe8, 0x8d, 0xe2, 0xe9, 0xcc, 0x67, 0xe1, 0x7e, 0x38, 0x09,
+	0x99, 0x32, 0x6b, 0x13, 0xc8, 0x25, 0xac, 0x00, 0x13, 0x8a, 0x12, 0x6e, 0x79, 0x1a, 0xc4, 0xfd,
+	0xa5, 0x05, 0x04, 0xf5, 0xf5, 0x20, 0x8e, 0x0e, 0xc2, 0xb1, 0xf2, 0x44, 0xa5, 0x2c, 0x4b, 0x53,
+	0xd6, 0x03, 0x58, 0x1e, 0xe1, 0xa2, 0xd4, 0xae, 0xa1, 0x00, 0xdf, 0x2e, 0xa8, 0xdc, 0x40, 0x71,
+	0x5b, 0x8c, 0xd4, 0x91, 0x24, 0xbf, 0x24, 0x9b, 0xd0, 0x0c, 0xe8, 0x84, 0x32, 0x6a, 0xd7, 0xd1,
+	0x92, 0xe5, 0xe8, 0x0c, 0xd7, 0xbd, 0x04, 0xcb, 0x41, 0x72, 0x3a, 0x4c, 0xe6, 0x11, 0xee, 0xb0,
+	0xe5, 0x35, 0x83, 0xe4, 0xd4, 0x9b, 0x47, 0xce, 0xfb, 0xd0, 0xd5, 0x69, 0x7c, 0x2d, 0x1d, 0xfd,
+	0xbb, 0x05, 0x6b, 0x06, 0xcf, 0xd2, 0x49, 0xcb, 0xf6, 0xfd, 0xb0, 0xb8, 0xef, 0x9b, 0x15, 0xfb,
+	0x96, 0xfe, 0x5b, 0xbe, 0x71, 0x6d, 0x1b, 0x75, 0x7d, 0x1b, 0xb8, 0xf3, 0x43, 0x3f, 0x1a, 0xd3,
+	0xd4, 0x5e, 0x42, 0x91, 0xa8, 0xe1, 0x2b, 0x6d, 0xf0, 0x4b, 0xa5, 0xd7, 0x87, 0x28, 0xdf, 0xb3,
+	0xf4, 0xba, 0x0e, 0x8d, 0x83, 0x38, 0x19, 0x09, 0x24, 0x2d, 0x4f, 0x0c, 0xc8, 0x2d, 0x20, 0xc8,
+	0x7a, 0x32, 0x45, 0x47, 0x93, 0xe1, 0x45, 0xa4, 0x41, 0xab, 0xfa, 0x0c, 0x86, 0x98, 0x0b, 0xe8,
+	0xcf, 0xfd, 0x1b, 0xa5, 0x03, 0xc5, 0xe2, 0x19, 0x3a, 0xb0, 0x61, 0x59, 0x18, 0x4a, 0x20, 0xb9,
+	0x54, 0xc3, 0xaf, 0xcb, 0xe7, 0x36, 0x40, 0x7c, 0x4c, 0x93, 0x24, 0x0c, 0x02, 0x1a, 0x49, 0x81,
+	0x6b, 0x90, 0x6a, 0x6e, 0xbf, 0xac, 0x43, 0x1b, 0xb9, 0x7d, 0x3e, 0xa3, 0xa3, 0x52, 0x1e, 0xcd,
+	0x60, 0x56, 0x3b, 0x2f, 0x98, 0xd5, 0x17, 0x83, 0xd9, 0xfb, 0xb9, 0xa5, 0x2d, 0xa1, 0xa5, 0xed,
+	0x14, 0x2c, 0x8d, 0xd3, 0xae, 0xb0, 0xaf, 0xbb, 0x32, 0x1a, 0x8b, 0xc3, 0xe5, 0x4a, 0xd9, 0x87,
+	0xc5, 0x88, 0x9c, 0xc5, 0xce, 0x66, 0x59, 0xec, 0x5c, 0xd6, 0x62, 0xe7, 0x6e, 0x1e, 0x3b, 0x5b,
+	0x88, 0x7f, 0xa3, 0x88, 0x1f, 0x67, 0xf3, 0xe0, 0xf9, 0x0a, 0xa6, 0x7b, 0xf1, 0xc0, 0x3b, 0x83,
+	0x8e, 0xc6, 0x0c, 0x0f, 0x35, 0x82, 0x1d, 0xf9, 0xb5, 0x1c, 0x65, 0xc1, 0xb4, 0xa6, 0x05, 0x53,
+	0x49, 0x46, 0x78, 0x26, 0x92, 0x79, 0x1d, 0x7a, 0xc7, 0xfe, 0x24, 0x0c, 0x7c, 0x46, 0x87, 0x71,
+	0x34, 0x11, 0x59, 0x7e, 0xcb, 0xeb, 0x2a, 0xe0, 0x27, 0xd1, 0xe4, 0xd4, 0xfd, 0xa7, 0xba, 0x3c,
+	0xe5, 0xf7, 0xe8, 0x74, 0x36, 0xf1, 0x45, 0x1c, 0x9b, 0xf9, 0x8c, 0xd1, 0x24, 0x52, 0xd1, 0x5e,
+	0x0e, 0xf3, 0x23, 0xbc, 0xa6, 0x1f, 0xe1, 0xa6, 0xd1, 0xd4, 0xcf, 0x33, 0x9a, 0xa5, 0x45, 0xa3,
+	0xf9, 0x20, 0x37, 0x1a, 0xa1, 0xfb, 0x37, 0x0a, 0xba, 0x51, 0xbc, 0x55, 0x18, 0xce, 0x37, 0xa5,
+	0xe1, 0x88, 0xd4, 0xf9, 0x5a, 0xd5, 0xc7, 0x95, 0xc6, 0xb3, 0x5c, 0x66, 0x3c, 0xad, 0x72, 0xe3,
+	0x69, 0xff, 0xff, 0x35, 0x9e, 0x5f, 0x58, 0xb0, 0xf6, 0x20, 0xa1, 0x3e, 0xa3, 0xc8, 0x53, 0xaa,
+	0x22, 0xe6, 0x3b, 0x59, 0xda, 0x26, 0xf2, 0x9c, 0xb5, 0x12, 0xcf, 0xca, 0xd2, 0xb4, 0x6f, 0x40,
+	0x8b, 0x49, 0x81, 0xc9, 0x54, 0xea, 0x52, 0x85, 0x3c, 0xbd, 0x6c, 0xe1, 0xd9, 0x27, 0x43, 0x69,
+	0x4c, 0x75, 0x7f, 0x04, 0xeb, 0x26, 0xaf, 0x32, 0x74, 0x5e, 0x2f, 0x30, 0xbb, 0x90, 0xc8, 0x29,
+	0x46, 0x35, 0x9a, 0x35, 0x23, 0xcc, 0xfd, 0x49, 0x1d, 0x06, 0xcf, 0x26, 0xfe, 0x88, 0x4e, 0x69,
+	0xc4, 0xce, 0x3a, 0x35, 0x5e, 0x3d, 0xda, 0xd9, 0x79, 0x79, 0xbb, 0x84, 0x15, 0xaa, 0x1a, 0x92,
+	0xb7, 0x61, 0x40, 0x4f, 0x46, 0x93, 0x79, 0x40, 0x83, 0xa1, 0x5e, 0x01, 0xf7, 0xbc, 0xbe, 0x82,
+	0xcb, 0xea, 0x94, 0xdc, 0x82, 0xb5, 0x69, 0x18, 0x0d, 0xe7, 0x51, 0xf8, 0xd9, 0x9c, 0x0e, 0x79,
+	0xed, 0x35, 0x14, 0x25, 0x2f, 0x27, 0x37, 0x98, 0x86, 0xd1, 0x0b, 0x9c, 0xf1, 0xfc, 0xd1, 0xd1,
+	0x93, 0x20, 0x25, 0x0e, 0xb4, 0x52, 0x96, 0xf8, 0x8c, 0x8e, 0x4f, 0xa5, 0xe1, 0x66, 0x63, 0xe2,
+	0x42, 0x37, 0x9e, 0xb1, 0x70, 0x1a, 0x7e, 0x2e, 0x58, 0x16, 0x36, 0x6c, 0xc0, 0xc8, 0x9b, 0xb0,
+	0x92, 0xed, 0x71, 0x98, 0x86, 0x9f, 0x53, 0x4c, 0x31, 0x2d, 0xaf, 0x97, 0x41, 0x9f, 0x87, 0x9f,
+	0x53, 0xb2, 0x0b, 0x6b, 0xf2, 0x33, 0x3a, 0x9c, 0x50, 0x3f, 0xa0, 0x49, 0x7a, 0x18, 0xce, 0xb0,
+	0xe6, 0x6b, 0x79, 0x44, 0x4d, 0x3d, 0xcd, 0x66, 0x74, 0x55, 0x77, 0x4c, 0x55, 0xff, 0xbc, 0xa6,
+	0x29, 0xe4, 0x59, 0x42, 0x8f, 0x43, 0xfa, 0xb2, 0x54, 0x21, 0xdf, 0x2e, 0x28, 0x84, 0xeb, 0xff,
+	0x72, 0xae, 0xff, 0x67, 0x6a, 0x2e, 0xd7, 0xae, 0xae, 0xae, 0xfb, 0xb9, 0x32, 0xea, 0xf8, 0xe9,
+	0x75, 0xed, 0xd3, 0x02, 0xf9, 0x8a, 0x6e, 0x83, 0x03, 0xad, 0x97, 0x7e, 0x12, 0x85, 0xd1, 0x58,
+	0x65, 0x32, 0xd9, 0xd8, 0x79, 0x71, 0x6e, 0x3f, 0x61, 0xd7, 0x2c, 0x41, 0xb6, 0x8a, 0x25, 0x7c,
+	0xce, 0xb6, 0xe6, 0xb4, 0xdf, 0x07, 0xb2, 0xb8, 0x2f, 0x5e, 0x4e, 0x67, 0x3b, 0x93, 0x24, 0x72,
+	0x00, 0x67, 0x53, 0x5a, 0xa1, 0x90, 0x52, 0xcf, 0xcb, 0xc6, 0xee, 0xaf, 0x2c, 0xe8, 0x17, 0xc8,
+	0x19, 0xeb, 0x05, 0xb2, 0x6c, 0xcc, 0xd5, 0x26, 0xd5, 0x2b, 0x3d, 0x40, 0x0d, 0xc9, 0x75, 0xe8,
+	0xab, 0x55, 0xc3, 0x7d, 0x7a, 0x10, 0x27, 0x54, 0xba, 0xc0, 0x8a, 0x02, 0x7f, 0x88, 0x50, 0x6e,
+	0x51, 0xd9, 0x42, 0xff, 0x40, 0xf9, 0x7a, 0xcf, 0xeb, 0x29, 0xe8, 0x7d, 0x0e, 0x24, 0xb7, 0x61,
+	0x2d, 0x65, 0x71, 0xc2, 0x0b, 0xbd, 0x83, 0x84, 0x52, 0x85, 0xb3, 0x81, 0xd6, 0xb7, 0x2a, 0xa7,
+	0xbe, 0x9b, 0x50, 0x2a, 0xd1, 0xbe, 0x0b, 0xc4, 0x58, 0x2f, 0x50, 0x37, 0x71, 0xf9, 0x40, 0x5b,
+	0x8e, 0xd8, 0xdd, 0x47, 0xb0, 0xf6, 0x40, 0xd8, 0xdb, 0x73, 0xe6, 0xe7, 0xd9, 0xe2, 0x3a, 0x34,
+	0x30, 0x5e, 0xc8, 0x8a, 0x5c, 0x0c, 0x74, 0x5b, 0xad, 0x99, 0xb6, 0xfa, 0x2e, 0xac, 0x9b, 0x68,
+	0x64, 0x58, 0x5a, 0x87, 0x46, 0xca, 0x01, 0x28, 0xbf, 0xae, 0x27, 0x06, 0xee, 0x43, 0x20, 0x2f,
+	0x58, 0x38, 0x91, 0xae, 0x75, 0x51, 0x9a, 0xff, 0x5d, 0x83, 0x35, 0x03, 0x8d, 0xa4, 0x79, 0xbf,
+	0x10, 0x0a, 0xb5, 0x62, 0xa5, 0x64, 0x79, 0x69, 0xd1, 0xfd, 0x30, 0xf7, 0x89, 0x85, 0xc4, 0xbf,
+	0x0c, 0x47, 0xb9, 0x5b, 0x5c, 0x85, 0xce, 0x94, 0xb2, 0x24, 0x1c, 0xa5, 0x43, 0x7f, 0x2c, 0xac,
+	0xa0, 0xee, 0x81, 0x04, 0xdd, 0x1f, 0x53, 0xe7, 0xc5, 0x79, 0xd5, 0xf9, 0x1d, 0xd3, 0x35, 0x9c,
+	0x42, 0x50, 0xd7, 0x59, 0xd1, 0x4e, 0xc2, 0x4f, 0xcf, 0x75, 0xb9, 0xbb, 0x26, 0xde, 0xd7, 0x8a,
+	0x2e, 0x57, 0x8e, 0xd8, 0xfd, 0xa3, 0x1a, 0x0c, 0x8a, 0x84, 0x79, 0x44, 0xc2, 0x70, 0x68, 0xa1,
+	0x85, 0xe1, 0x6f, 0xcd, 0x07, 0x18, 0x0d, 0x44, 0xb4, 0xac, 0xe1, 0xf4, 0x4a, 0x0e, 0xc6, 0x70,
+	0xf9, 0x51, 0x21, 0x09, 0x2a, 0x2b, 0xb2, 0x34, 0x62, 0x79, 0x2c, 0x93, 0xb2, 0xd6, 0xbe, 0xe6,
+	0xf5, 0x80, 0x12, 0x77, 0x18, 0xf1, 0x42, 0x17, 0x8b, 0x4d, 0x91, 0xbc, 0xad, 0xca, 0x99, 0x27,
+	0xd9, 0x84, 0xf3, 0x5b, 0xd0, 0x2f, 0x60, 0x2b, 0x11, 0x94, 0x91, 0x35, 0x58, 0xba, 0x2c, 0xfe,
+	0xaa, 0x06, 0xab, 0x0b, 0xc2, 0xe2, 0xe7, 0x97, 0x72, 0xbe, 0x91, 0x3f, 0xf3, 0x47, 0x21, 0x13,
+	0xe8, 0x2c, 0xaf, 0x2f, 0xe1, 0x0f, 0x24, 0x98, 0x5c, 0x83, 0xae, 0xee, 0xa7, 0x92, 0x42, 0x47,
+	0xf3, 0x50, 0x7d, 0xc9, 0x3c, 0xa5, 0x81, 0x5d, 0x37, 0x96, 0xbc, 0x48, 0x69, 0xc0, 0xcf, 0x9b,
+	0x6c, 0x49, 0xce, 0x07, 0xee, 0xda, 0xf2, 0x54, 0x20, 0xd0, 0x39, 0xd4, 0x83, 0x5a, 0xa3, 0x10,
+	0xd4, 0xae, 0x41, 0x57, 0xfe, 0x16, 0x3a, 0x13, 0x41, 0x43, 0x1d, 0xdd, 0xa8, 0xb0, 0x72, 0x21,
+	0x2f, 0x57, 0x08, 0xd9, 0xfd, 0xc2, 0x02, 0x7b, 0xd1, 0xa4, 0xa4, 0xc3, 0x8b, 0x2e, 0x88, 0x85,
+	0x91, 0x98, 0x77, 0x41, 0x8c, 0x56, 0x5d, 0xad, 0xd0, 0xaa, 0xbb, 0x06, 0xdd, 0x88, 0xb2, 0x5c,
+	0xaa, 0x52, 0x16, 0x11, 0x65, 0x99, 0x44, 0xab, 0xb3, 0xa6, 0x5f, 0x5a, 0xb0, 0x55, 0xc2, 0x86,
+	0x0c, 0x18, 0x1f, 0xe5, 0xde, 0x2e, 0x22, 0xc6, 0x9d, 0xb3, 0xfc, 0xe1, 0x6c, 0x9f, 0xdf, 0x84,
+	0x66, 0xe2, 0x47, 0x47, 0x58, 0xad, 0xf2, 0x7d, 0xc9, 0x11, 0x87, 0xd3, 0x24, 0x89, 0xe5, 0x21,
+	0xdb, 0xf6, 0xe4, 0xc8, 0xf9, 0x9d, 0x73, 0x7d, 0xf5, 0x9b, 0xa6, 0xaf, 0x5e, 0xcd, 0x79, 0x7b,
+	0x1a, 0x1e, 0xd3, 0x33, 0xfd, 0xf5, 0xcb, 0x1a, 0x6c, 0x94, 0x2e, 0x22, 0x37, 0xa0, 0x29, 0x38,
+	0xb6, 0xad, 0x8a, 0x26, 0xbe, 0x9c, 0x27, 0x1b, 0xd0, 0xe4, 0x72, 0x67, 0x27, 0xca, 0x05, 0x22,
+	0xca, 0xf6, 0x4e, 0x14, 0x38, 0x39, 0xb1, 0xeb, 0x19, 0xd8, 0x3b, 0x59, 0xd0, 0xd2, 0xd2, 0xa2,
+	0x96, 0xe4, 0x92, 0x43, 0xea, 0x07, 0x49, 0x1c, 0x4f, 0xed, 0x46, 0xb6, 0xe4, 0x7b, 0x12, 0xc4,
+	0x0d, 0x21, 0x08, 0xd3, 0x23, 0xb4, 0x68, 0x69, 0x84, 0x2d, 0x0e, 0xe0, 0x3b, 0xe0, 0xe5, 0x59,
+	0x18, 0xa5, 0x8c, 0x37, 0xb4, 0x87, 0x58, 0xcd, 0x89, 0x6c, 0xae, 0xab, 0x80, 0x7b, 0xbc, 0xaa,
+	0xbb, 0x0e, 0x7d, 0x65, 0xa6, 0xd3, 0x30, 0x4d, 0xc3, 0x68, 0x8c, 0x49, 0x5d, 0xcb, 0x5b, 0x91,
+	0xe0, 0x8f, 0x05, 0xd4, 0xfd, 0x1f, 0x0b, 0xba, 0x3f, 0x98, 0xc7, 0xcc, 0xd7, 0x32, 0xde, 0x79,
+	0x2a, 0xe5, 0xd2, 0xf6, 0xf0, 0x37, 0xe7, 0x67, 0x34, 0x09, 0x69, 0xc4, 0x86, 0xb2, 0x6b, 0xd7,
+	0xf6, 0x5a, 0x02, 0xf0, 0x24, 0xe0, 0xe7, 0xed, 0x2c, 0x89, 0x83, 0xf9, 0x88, 0x26, 0xc3, 0xfd,
+	0x53, 0xc6, 0x53, 0x51, 0x46, 0xa5, 0x54, 0x06, 0x6a, 0xe6, 0xc3, 0x53, 0x46, 0x3d, 0x9e, 0xf2,
+	0xbf, 0x8b, 0x4d, 0x8b, 0x74, 0x3e, 0x35, 0x56, 0x0b, 0x31, 0x0d, 0xd4, 0x4c, 0xb6, 0xfa, 0x16,
+	0x90, 0x44, 0xf0, 0x35, 0x9c, 0xd1, 0x64, 0x44, 0x23, 0xc6, 0x0f, 0x12, 0x79, 0xf4, 0xcb, 0x99,
+	0x67, 0xd9, 0x04, 0xe7, 0xfd, 0x88, 0x9e, 0xaa, 0x56, 0x31, 0xfe, 0xd6, 0x9d, 0x62, 0xd9, 0x74,
+	0x8a, 0xf7, 0xa0, 0x27, 0x77, 0x9e, 0xd7, 0x10, 0x9f, 0x71, 0x40, 0x49, 0x0d, 0x21, 0x16, 0xca,
+	0x69, 0xf7, 0x1f, 0x2c, 0x68, 0x20, 0xe4, 0xd7, 0x59, 0x5a, 0xee, 0x43, 0x58, 0x7f, 0x20, 0x51,
+	0x3c, 0x4e, 0xe2, 0xf9, 0xec, 0xac, 0x9a, 0xa7, 0x3a, 0x0b, 0xf9, 0x47, 0x0b, 0x36, 0x0a, 0x68,
+	0xa4, 0x38, 0x1f, 0x40, 0x73, 0xcc, 0x01, 0x4a, 0x9c, 0xef, 0xe4, 0xe2, 0x2c, 0xfd, 0xe0, 0x36,
+	0x8e, 0x54, 0x26, 0x22, 0x3e, 0x2d, 0xef, 0x1d, 0x38, 0x1e, 0x74, 0xb4, 0xc5, 0x25, 0x89, 0xc3,
+	0x2d, 0x33, 0x68, 0x5c, 0xaa, 0x22, 0xad, 0x05, 0x8b, 0x5f, 0x59, 0xd0, 0x33, 0x26, 0xab, 0x5a,
+	0x86, 0x22, 0xa1, 0x93, 0x65, 0x34, 0x0e, 0xb8, 0x4f, 0xaa, 0xfb, 0x20, 0xe1, 0x93, 0xa2, 0x0b,
+	0xd7, 0x55, 0x40, 0xf4, 0x49, 0x07, 0x5a, 0x6a, 0xac, 0x2e, 0x4e, 0xd5, 0x98, 0x77, 0x0a, 0xa6,
+	0x74, 0xba, 0x9f, 0x5f, 0x78, 0x6a, 0x9d, 0x02, 0x64, 0xe6, 0x63, 0x9c, 0xf5, 0xd4, 0x2a, 0xf2,
+	0x1b, 0x85, 0xfb, 0x03, 0xfe, 0xcd, 0x66, 0x49, 0xcd, 0xf3, 0xd4, 0x1f, 0x1b, 0x49, 0xc2, 0x00,
+	0xea, 0x13, 0x7f, 0x8c, 0xae, 0x50, 0xf7, 0xf8, 0x4f, 0xf7, 0xaf, 0x2d, 0xe8, 0x68, 0x24, 0xb8,
+	0xf9, 0x0a, 0x22, 0x43, 0x3c, 0x9c, 0x90, 0x4f, 0x01, 0x78, 0x12, 0x9c, 0x6d, 0xdb, 0x57, 0xa1,
+	0x23, 0x27, 0xf1, 0x9e, 0x4f, 0xc8, 0x00, 0x04, 0xe8, 0x7b, 0x71, 0xca, 0xc8, 0xb7, 0xa0, 0xe3,
+	0xa7, 0x69, 0x38, 0x8e, 0x78, 0x79, 0xa1, 0x3a, 0x7d, 0xc5, 0xeb, 0x93, 0x8c, 0xf5, 0xd4, 0xd3,
+	0x57, 0xbb, 0x8f, 0xa1, 0x5f, 0x98, 0xd7, 0x33, 0x66, 0x2b, 0xcf, 0x98, 0xb7, 0x17, 0xca, 0x41,
+	0xa3, 0x3e, 0x77, 0xff, 0xce, 0x82, 0xae, 0x2e, 0x9f, 0x0a, 0x34, 0x46, 0x2d, 0x55, 0x2b, 0xd6,
+	0x52, 0x6f, 0xc3, 0x60, 0x14, 0x4f, 0xa7, 0x21, 0xe3, 0x09, 0x5e, 0x7c, 0x70, 0x90, 0x52, 0x26,
+	0x13, 0xdc, 0x7e, 0x06, 0xff, 0x04, 0xc1, 0xfc, 0xbe, 0x8c, 0x46, 0xd9, 0xa2, 0x25, 0x5c, 0xc4,
+	0x2f, 0x51, 0xe5, 0xb4, 0xd4, 0x48, 0x23, 0xd3, 0x88, 0xa9, 0x81, 0xa6, 0xa9, 0x01, 0xf7, 0x5f,
+	0x2d, 0x20, 0xe2, 0x4b, 0x8f, 0xe2, 0x9f, 0x33, 0xdb, 0xdb, 0x62, 0x5f, 0xb5, 0x6a, 0xf1, 0xd4,
+	0x8b, 0xe2, 0xe1, 0x59, 0x09, 0x8b, 0xa5, 0x81, 0xd6, 0x58, 0x6c, 0x5e, 0xd1, 0x36, 0x8a, 0x57,
+	0xb4, 0x9b, 0xd0, 0x94, 0x1b, 0x6b, 0xe2, 0x94, 0x1c, 0xe9, 0x6d, 0x96, 0xe5, 0xaa, 0xd6, 0x4e,
+	0xcb, 0x8c, 0x24, 0x11, 0xac, 0x19, 0x1b, 0x93, 0x61, 0xe4, 0x03, 0x83, 0x5f, 0x11, 0x4a, 0xb6,
+	0x4b, 0x2c, 0x5d, 0xff, 0x56, 0xdf, 0x4f, 0x65, 0xc3, 0xe7, 0x4f, 0x2d, 0x58, 0x2f, 0xfb, 0xfa,
+	0x42, 0xf6, 0x70, 0x1d, 0xfa, 0x33, 0xde, 0x23, 0x88, 0xe7, 0xa9, 0x69, 0x0e, 0x2b, 0x0a, 0x9c,
+	0x5b, 0x43, 0x44, 0x5f, 0x16, 0xac, 0x21, 0xa2, 0x2f, 0xc5, 0xb4, 0xfb, 0xf3, 0x06, 0xac, 0x79,
+	0x34, 0xb7, 0x7b, 0xa5, 0xdf, 0xcb, 0xd0, 0x8e, 0x67, 0x34, 0xf1, 0xb3, 0xca, 0xbe, 0xed, 0xe5,
+	0x00, 0xae, 0x05, 0x59, 0xf2, 0x89, 0x30, 0x29, 0x47, 0x7a, 0xa3, 0x89, 0x2b, 0xba, 0x61, 0xb4,
+	0x2c, 0xb2, 0x76, 0xd0, 0xd2, 0x39, 0xed, 0xa0, 0x46, 0x49, 0x3b, 0xa8, 0xd0, 0xe4, 0x6a, 0x2e,
+	0x36, 0xb9, 0x2a, 0xfa, 0x53, 0xcb, 0x15, 0xfd, 0xa9, 0xd7, 0xa1, 0x87, 0xd7, 0x2f, 0xc3, 0x84,
+	0xee, 0xcf, 0xc3, 0x49, 0x20, 0xf3, 0x95, 0x2e, 0x02, 0x3d, 0x01, 0x23, 0xef, 0x80, 0x2a, 0xf8,
+	0x87, 0xec, 0x30, 0xa1, 0xe9, 0x61, 0x3c, 0x09, 0x64, 0x1f, 0x4a, 0xd5, 0x1d, 0x7b, 0x0a, 0x4e,
+	0xee, 0xc0, 0xfa, 0xc2, 0xe2, 0xe1, 0x78, 0xdf, 0x06, 0xa3, 0x36, 0xc8, 0xd6, 0x3f, 0xde, 0x47,
+	0x53, 0x8f, 0x27, 0x34, 0xc1, 0xf7, 0x03, 0x1d, 0x5c, 0x96, 0x03, 0x50, 0xc5, 0x59, 0x07, 0x4c,
+	0xdc, 0x8b, 0x8b, 0x87, 0x09, 0x79, 0x63, 0xec, 0x29, 0x87, 0x92, 0xf7, 0xc0, 0x36, 0x5b, 0x65,
+	0x1a, 0xb3, 0xe2, 0xcd, 0xc2, 0xa6, 0xd1, 0x34, 0xcb, 0x59, 0xbe, 0x0e, 0xfd, 0x49, 0x3c, 0xf2,
+	0xf9, 0xfd, 0xe5, 0x30, 0x1d, 0xc5, 0x33, 0x1a, 0xc8, 0x67, 0x0c, 0x2b, 0x0a, 0xfc, 0x1c, 0xa1,
+	0x55, 0x6d, 0xb6, 0x7e, 0x65, 0x9b, 0xed, 0x6d, 0x18, 0x84, 0x11, 0x36, 0x10, 0x87, 0x61, 0xc4,
+	0x68, 0x12, 0xf9, 0x13, 0x7b, 0x80, 0xab, 0xfb, 0x12, 0xfe, 0x44, 0x82, 0x75, 0x0f, 0x5d, 0x35,
+	0x3d, 0xf4, 0x3f, 0x2d, 0x18, 0xe8, 0xc6, 0xf9, 0x6c, 0xe2, 0x47, 0x59, 0x15, 0x83, 0xf1, 0x22,
+	0x0c, 0x4c, 0x4b, 0xad, 0x15, 0x2d, 0xd5, 0x86, 0x65, 0x7a, 0x32, 0x0b, 0x13, 0x9a, 0x4a, 0xff,
+	0x50, 0x43, 0xf2, 0x1d, 0xc3, 0xcf, 0xc5, 0xd9, 0x70, 0xb5, 0xc4, 0xcf, 0x0d, 0xef, 0xd0, 0x1d,
+	0xfd, 0xae, 0x38, 0x9a, 0x45, 0x59, 0x67, 0x14, 0xf5, 0xfa, 0x27, 0xbc, 0x3f, 0x93, 0x8a, 0x73,
+	0xdb, 0x6c, 0xdc, 0x35, 0xcd, 0xc6, 0x1d, 0x0f, 0x0f, 0x1b, 0xa5, 0x44, 0x2f, 0x14, 0x1f, 0xf4,
+	0xb2, 0xb3, 0x6e, 0xf6, 0xde, 0xb8, 0x6e, 0x66, 0x13, 0x3f, 0x8a, 0x68, 0x30, 0xcc, 0xd6, 0x88,
+	0xbe, 0x70, 0x5f, 0xc2, 0x3d, 0x09, 0x76, 0xff, 0xab, 0x06, 0xab, 0x0b, 0xbb, 0x29, 0x84, 0x74,
+	0x6b, 0xa1, 0x23, 0xcd, 0x09, 0x64, 0xa3, 0xe1, 0x34, 0x3e, 0xa6, 0xea, 0xad, 0x57, 0x6e, 0xd1,
+	0xe9, 0xc7, 0x1c, 0x6c, 0x34, 0xe5, 0xc4, 0xc2, 0xba, 0xd9, 0x94, 0x13, 0xcb, 0xae, 0x42, 0x87,
+	0xe7, 0xa3, 0x6a, 0x8d, 0xc8, 0x48, 0x01, 0x41, 0x62, 0x81, 0xe6, 0x7c, 0x09, 0xbf, 0xd3, 0x35,
+	0xdb, 0x76, 0xca, 0xf9, 0x3c, 0x3e, 0x25, 0xfb, 0x76, 0x5a, 0x9f, 0x4f, 0x7c, 0xa1, 0x37, 0xee,
+	0x56, 0xf5, 0x0f, 0x44, 0x5f, 0xf0, 0x1e, 0x6c, 0xa8, 0xf5, 0x29, 0x0b, 0x02, 0x7a, 0xac, 0x48,
+	0x2c, 0xe3, 0x17, 0x0a, 0xd9, 0x73, 0x9c, 0x93, 0x34, 0x34, 0xae, 0xe4, 0x37, 0x82, 0x48, 0xcb,
+	0xe0, 0x4a, 0x7c, 0x22, 0xfa, 0x83, 0xdf, 0x05, 0x47, 0x97, 0xf7, 0xa3, 0x13, 0x3a, 0x9a, 0xe7,
+	0x6d, 0xc2, 0xa2, 0xed, 0x57, 0xa7, 0xc9, 0xbf, 0x67, 0xc1, 0xba, 0xe1, 0x3a, 0x49, 0x3c, 0x4e,
+	0x68, 0x9a, 0x2e, 0xa0, 0x38, 0xef, 0x76, 0xe1, 0x32, 0xb4, 0x13, 0xca, 0x1f, 0x35, 0xf1, 0x9a,
+	0x4e, 0xe8, 0x26, 0x07, 0x70, 0x33, 0x2b, 0x74, 0x7e, 0xb2, 0xb1, 0xfb, 0x01, 0x74, 0x3f, 0xf5,
+	0xd9, 0xe8, 0x50, 0xef, 0x37, 0x9e, 0xce, 0x68, 0x9a, 0xf5, 0x1b, 0xf9, 0xe0, 0x8c, 0x2d, 0x7c,
+	0x61, 0x01, 0x20, 0x82, 0x47, 0xc7, 0xdc, 0x0b, 0xd4, 0x65, 0xa2, 0xa5, 0x5d, 0x26, 0x6e, 0x42,
+	0xd3, 0x1f, 0x69, 0x8e, 0x2f, 0x47, 0x59, 0x76, 0x52, 0xd7, 0xb2, 0x13, 0x23, 0xaf, 0x58, 0x2a,
+	0xe6, 0x15, 0x1a, 0x1b, 0x0d, 0x93, 0x8d, 0xbf, 0xb7, 0xa0, 0x7f, 0x7f, 0x1e, 0x84, 0xec, 0x69,
+	0x9c, 0x3d, 0xda, 0x40, 0xef, 0x4a, 0xe3, 0x79, 0x32, 0x52, 0xfc, 0x64, 0x63, 0x3e, 0x17, 0x06,
+	0x34, 0x62, 0xbc, 0x1c, 0x97, 0x19, 0xab, 0x1a, 0x73, 0x7e, 0xa7, 0x94, 0x1d, 0xc6, 0x81, 0xe4,
+	0x4c, 0x8e, 0x30, 0xcb, 0x0f, 0xf9, 0x21, 0x20, 0xf8, 0x12, 0x03, 0x0e, 0x9d, 0x47, 0xbc, 0x24,
+	0x17, 0x59, 0x90, 0x18, 0xe4, 0x8f, 0xa4, 0x9a, 0xfa, 0x23, 0xa9, 0xea, 0xb2, 0xf3, 0x43, 0x18,
+	0xe4, 0xec, 0xcb, 0x1c, 0xe7, 0x36, 0x2c, 0xd3, 0x88, 0x25, 0x21, 0x55, 0x09, 0x8e, 0xf6, 0x42,
+	0x07, 0x17, 0xcb, 0x2e, 0x8b, 0x5c, 0xc4, 0xab, 0x76, 0xc8, 0xe1, 0xa6, 0x28, 0xad, 0xa2, 0x28,
+	0xd1, 0x62, 0x50, 0x4e, 0xb1, 0xd2, 0x69, 0x0e, 0x30, 0xc4, 0x53, 0xaf, 0x14, 0xcf, 0x92, 0x21,
+	0x1e, 0x5d, 0xdc, 0x8d, 0x82, 0xb8, 0x37, 0xa1, 0x29, 0x5e, 0x71, 0xc8, 0xcc, 0x55, 0x8e, 0x38,
+	0x5c, 0xf3, 0xcf, 0xb6, 0x27, 0x47, 0x5c, 0x7c, 0xb9, 0x0f, 0xb6, 0x3d, 0x31, 0xd0, 0xc5, 0xd7,
+	0x36, 0xc5, 0xf7, 0x63, 0x18, 0x3c, 0x0d, 0x0f, 0xe8, 0xe8, 0x74, 0x34, 0xd1, 0xdf, 0x76, 0x24,
+	0xf3, 0x49, 0x66, 0x8a, 0xfc, 0x77, 0x65, 0xda, 0x57, 0xfd, 0xb4, 0xd5, 0xfd, 0x04, 0xfa, 0x1a,
+	0x6a, 0x7c, 0x6d, 0xf8, 0x6d, 0x80, 0xe3, 0x30, 0x9e, 0xf8, 0x7a, 0xf2, 0x79, 0x59, 0xef, 0x40,
+	0xc9, 0xe5, 0x3f, 0x54, 0x8b, 0x3c, 0x6d, 0xbd, 0xfb, 0xb7, 0x16, 0x90, 0xc5, 0x25, 0xa5, 0xec,
+	0x96, 0xe7, 0xea, 0x3b, 0xd0, 0x09, 0x68, 0x3a, 0x4a, 0xc2, 0x59, 0x76, 0x95, 0xd8, 0xf6, 0x74,
+	0x90, 0xe6, 0x71, 0x4b, 0x86, 0xc7, 0x39, 0xd0, 0xa2, 0x11, 0xe6, 0x4e, 0x81, 0x7c, 0xce, 0x91,
+	0x8d, 0xb9, 0x04, 0xd2, 0xa3, 0x70, 0xc6, 0xb3, 0x0b, 0xa1, 0x23, 0x35, 0xbc, 0xf7, 0xc7, 0x36,
+	0xb4, 0x3c, 0xb9, 0x39, 0xb2, 0x07, 0xf0, 0x98, 0x32, 0x75, 0xdd, 0x78, 0x69, 0xf1, 0x05, 0x2e,
+	0x0a, 0xdf, 0xb1, 0xab, 0x9e, 0xe6, 0xba, 0x6b, 0x7f, 0xf0, 0xcf, 0xff, 0xf1, 0x67, 0xb5, 0x1e,
+	0xe9, 0xec, 0x1e, 0xdf, 0xdd, 0x55, 0x89, 0xe7, 0x6f, 0x43, 0x87, 0x3f, 0xa5, 0x7c, 0x05, 0xb4,
+	0x36, 0xa2, 0x25, 0x64, 0xa0, 0xa1, 0xdd, 0x9d, 0x84, 0x29, 0x23, 0xcf, 0xa0, 0xfd, 0x98, 0x32,
+	0x71, 0xa5, 0x40, 0x36, 0x17, 0x5e, 0x19, 0x0a, 0xc4, 0x97, 0x2a, 0x5e, 0x1f, 0xba, 0x04, 0xf1,
+	0x76, 0x09, 0x70, 0xbc, 0x32, 0x81, 0xfe, 0x21, 0x00, 0xe7, 0xf6, 0xa2, 0x28, 0x2f, 0x21, 0xca,
+	0x55, 0xd2, 0xcf, 0x51, 0x0a, 0x4e, 0x63, 0x58, 0x51, 0x9c, 0x8a, 0x0b, 0x7f, 0x72, 0xf9, 0xac,
+	0x27, 0x65, 0xce, 0x95, 0x33, 0x1f, 0x5e, 0xb9, 0x3b, 0x48, 0xc7, 0x21, 0xb6, 0x46, 0x47, 0xbc,
+	0x72, 0xd8, 0xfd, 0x29, 0x0f, 0xb6, 0x3f, 0xe3, 0x04, 0x9f, 0xff, 0xdf, 0x13, 0x74, 0xaa, 0x09,
+	0x52, 0xe8, 0x88, 0xd7, 0x4d, 0x7b, 0x22, 0x3b, 0x2a, 0xe0, 0x33, 0x1e, 0x67, 0x39, 0x57, 0x2a,
+	0x66, 0x25, 0xb5, 0x2d, 0xa4, 0xb6, 0x76, 0x73, 0x55, 0xa3, 0x26, 0xc9, 0x1c, 0x41, 0x57, 0x7f,
+	0x0f, 0x40, 0x34, 0x4c, 0x25, 0x6f, 0x1a, 0x9c, 0xed, 0xaa, 0x69, 0x49, 0xe9, 0x32, 0x52, 0xda,
+	0x74, 0x75, 0x4a, 0x23, 0x5c, 0xf8, 0xbe, 0x75, 0x93, 0x1c, 0xc1, 0x40, 0x5e, 0x04, 0x6b, 0x97,
+	0xa4, 0x25, 0xb7, 0xc5, 0x8a, 0x9a, 0x53, 0x7d, 0x93, 0xec, 0x5e, 0x45, 0x4a, 0x5b, 0xee, 0xba,
+	0x46, 0x69, 0xa6, 0x16, 0x71, 0x62, 0x81, 0x7c, 0x60, 0xf3, 0xb1, 0x3f, 0x9b, 0xf1, 0x84, 0xb4,
+	0xd2, 0xfa, 0xaa, 0x3d, 0xe5, 0x1a, 0xd2, 0x78, 0x8d, 0x6c, 0x71, 0x1a, 0x53, 0x89, 0x47, 0x10,
+	0x53, 0xf2, 0x0b, 0xd4, 0xdb, 0xfb, 0x8c, 0x4c, 0xa5, 0x47, 0x56, 0x5a, 0xb9, 0x61, 0x7d, 0x19,
+	0x19, 0xe1, 0x99, 0xbb, 0x3f, 0x0d, 0x83, 0x9f, 0x91, 0x1f, 0x41, 0x6b, 0xcf, 0x1f, 0x0b, 0x4b,
+	0xa8, 0xda, 0x86, 0xfe, 0x36, 0x26, 0xff, 0xbf, 0x06, 0xf7, 0x0a, 0x22, 0xbf, 0xe4, 0x6c, 0x68,
+	0x72, 0x62, 0x7e, 0x66, 0x66, 0x43, 0xe8, 0x6b, 0x66, 0xc6, 0x1f, 0xc0, 0x5c, 0x90, 0xc0, 0xcd,
+	0x0a, 0x02, 0x3f, 0xc6, 0x67, 0x35, 0x42, 0x12, 0xd5, 0xb2, 0xa9, 0xc0, 0x2d, 0xcd, 0xc9, 0x59,
+	0xd7, 0x43, 0x15, 0x22, 0xe7, 0x52, 0xf9, 0x5d, 0x18, 0x08, 0xde, 0x05, 0x2e, 0x64, 0xfe, 0x82,
+	0x14, 0x6e, 0x96, 0x53, 0x38, 0x84, 0xae, 0x7e, 0x2d, 0x6d, 0x78, 0xc7, 0xe2, 0xad, 0xb7, 0xb3,
+	0x5d, 0x35, 0x6d, 0xfa, 0x21, 0x41, 0xef, 0x90, 0xa7, 0xe6, 0xae, 0xe8, 0x80, 0x1e, 0x60, 0x40,
+	0xd3, 0xaf, 0x58, 0x2e, 0x57, 0x5c, 0x19, 0x2f, 0x78, 0x7c, 0xc9, 0xe5, 0x92, 0x19, 0x38, 0xb5,
+	0x8b, 0x3d, 0xf2, 0x87, 0x16, 0x6c, 0x3d, 0xc4, 0xf3, 0x6f, 0xbf, 0xe4, 0x5a, 0xc7, 0x3d, 0xf3,
+	0xe2, 0x4a, 0x50, 0x7e, 0xfd, 0x2b, 0x5c, 0x6e, 0x29, 0xef, 0x24, 0x97, 0x74, 0xb1, 0xea, 0x7c,
+	0x88, 0xa3, 0x06, 0x2f, 0x01, 0x0c, 0x8b, 0xd3, 0xef, 0x52, 0x9c, 0x4b, 0x0b, 0xf0, 0xb2, 0xa3,
+	0x46, 0x5c, 0x2a, 0x90, 0x4f, 0xa0, 0xf5, 0x5c, 0x62, 0xbc, 0x30, 0x42, 0x47, 0x47, 0xe8, 0xa9,
+	0x08, 0xfc, 0x6a, 0x38, 0x6f, 0xea, 0x38, 0x8f, 0x79, 0x42, 0x93, 0x32, 0xa3, 0x4f, 0x9e, 0x92,
+	0xed, 0xca, 0xce, 0xbe, 0x20, 0x71, 0xf5, 0x9c, 0xce, 0xbf, 0x29, 0x6e, 0x75, 0xaf, 0x21, 0x6e,
+	0x00, 0xc4, 0x79, 0xf9, 0x85, 0x05, 0x1b, 0x4a, 0xed, 0x06, 0x8a, 0x57, 0xa7, 0x7d, 0x13, 0x69,
+	0xbf, 0x41, 0xdc, 0x12, 0xda, 0x81, 0x24, 0xa9, 0x82, 0xc1, 0xef, 0x5b, 0xb0, 0x85, 0x3d, 0x42,
+	0x03, 0x95, 0x68, 0xdd, 0xa5, 0xba, 0xc5, 0x2f, 0x76, 0x68, 0x9d, 0x2b, 0x15, 0xb3, 0x92, 0x8d,
+	0xeb, 0xc8, 0xc6, 0x35, 0xe7, 0x6a, 0x09, 0x1b, 0x09, 0x5f, 0xa9, 0x78, 0x98, 0xe2, 0xab, 0x28,
+	0xb3, 0x23, 0x71, 0xa5, 0xbc, 0xd7, 0x51, 0x72, 0x0e, 0x15, 0xdb, 0x37, 0xee, 0x36, 0xd2, 0xb5,
+	0xc9, 0x26, 0xa7, 0x9b, 0x68, 0xb3, 0x78, 0x1c, 0xa1, 0xc3, 0xad, 0x65, 0x55, 0xaf, 0x46, 0xf2,
+	0x8d, 0x72, 0x9c, 0x66, 0x81, 0xec, 0x6c, 0x97, 0xaf, 0x52, 0xd5, 0xaf, 0xfb, 0x16, 0x52, 0xdf,
+	0x71, 0xb6, 0x17, 0xa9, 0x53, 0x81, 0x09, 0x03, 0xd9, 0x1d, 0x8b, 0x7c, 0x04, 0x0d, 0x2c, 0x3e,
+	0x75, 0x3b, 0xd6, 0xcb, 0x59, 0x67, 0xbd, 0x00, 0xc7, 0x2a, 0xd5, 0x5d, 0x45, 0x02, 0x1d, 0xd2,
+	0xe6, 0x04, 0x5e, 0x72, 0xf8, 0x1d, 0x8b, 0x7c, 0x0a, 0x9d, 0xc7, 0x94, 0xa9, 0x2a, 0x8c, 0x6c,
+	0x15, 0x8a, 0xad, 0xbc, 0xb0, 0x74, 0x9c, 0xb2, 0x29, 0xa9, 0x31, 0x03, 0xb5, 0xcf, 0x67, 0xc9,
+	0x3e, 0x90, 0xc7, 0x94, 0x15, 0x8b, 0x08, 0xa7, 0xa4, 0x60, 0x50, 0x04, 0xb6, 0x4a, 0xe7, 0xf8,
+	0x67, 0xee, 0x06, 0xe2, 0xef, 0x93, 0x1e, 0xc7, 0x3f, 0x51, 0x93, 0xe4, 0x10, 0x06, 0x8f, 0x44,
+	0x26, 0x9f, 0x7d, 0x70, 0x51, 0x0a, 0xf2, 0xe8, 0x73, 0x37, 0x0c, 0x0a, 0xbb, 0xb2, 0x50, 0xd8,
+	0x6f, 0xe2, 0xf5, 0xd4, 0x37, 0xfe, 0x77, 0x00, 0x63, 0xf7, 0xc1, 0x12, 0xa9, 0x38, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// RegistryClient is the client API for Registry service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RegistryClient interface {
+	// GetBrokers returns a BrokerResponse with the brokers field populated
+	// with full broker metadata. If the input BrokerRequest.id field is
+	// non-nil, a single broker is returned matching the ID specified in the
+	// Broker object. Otherwise all brokers are returned, optionally filtered
+	// by any provided BrokerRequest.tags parameters.
+	GetBrokers(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*BrokerResponse, error)
+	// ListBrokers returns a BrokerResponse with the ids field populated
+	// with broker IDs. If the input BrokerRequest.id field is non-nil,
+	// a single broker ID is returned matching the ID specified in the
+	// Broker object if the broker exists. Otherwise all brokers are returned,
+	// optionally filtered by any provided BrokerRequest.tags parameters.
+	// IDs are sorted and paginated by the BrokerRequest.limit; the next
+	// page is requested with the BrokerResponse.next_page_token.
+	ListBrokers(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*BrokerResponse, error)
+	// GetTopics returns a TopicResponse with the topics field populated
+	// with full topic metadata. If the input TopicRequest.name field is
+	// non-nil, a single topic is returned matching the name specified in the
+	// Topic object. Otherwise all topics are returned, optionally filtered
+	// by any provided TopicRequest.tags parameters.
+	GetTopics(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TopicResponse, error)
+	// ListTopics returns a TopicResponse with the names field populated
+	// with topic names. If the input TopicRequest.name field is non-nil,
+	// a single topic name is returned matching the name specified in the
+	// Topic object if the topic exists. Otherwise all topics are returned,
+	// optionally filtered by any provided TopicRequest.tags parameters.
+	// Names are sorted and paginated by the TopicRequest.limit; the next
+	// page is requested with the TopicResponse.next_page_token.
+	ListTopics(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TopicResponse, error)
+	// GetTopicConfig returns a TopicConfigResponse with the dynamic config
+	// overrides of the topic specified in the TopicConfigRequest.name field.
+	GetTopicConfig(ctx context.Context, in *TopicConfigRequest, opts ...grpc.CallOption) (*TopicConfigResponse, error)
+	// SetTopicConfig takes a TopicConfigRequest and sets the configs in the
+	// TopicConfigRequest.configs field and deletes the configs named in the
+	// TopicConfigRequest.delete field for the topic. Configs not specified
+	// are left unmodified. Only supported configs are accepted, and values
+	// are validated. The resulting topic configs are returned. With the
+	// TopicConfigRequest.dry_run field set, the request is only validated and
+	// the configs that would result are returned.
+	SetTopicConfig(ctx context.Context, in *TopicConfigRequest, opts ...grpc.CallOption) (*TopicConfigResponse, error)
+	// DeleteTopic takes a TopicDeleteRequest and marks the topic specified in
+	// the TopicDeleteRequest.name field for deletion. Deletion is denied if
+	// the topic has had messages produced within the configured idle window,
+	// is consumed by a consumer group with active members, or has the
+	// configured protected tag. Setting the TopicDeleteRequest.force field
+	// overrides these checks in two steps: the first request returns a
+	// confirmation token, which must be provided in the confirmation_token
+	// field of a second forced request within 5 minutes. With the
+	// TopicDeleteRequest.dry_run field set, the checks are run and the
+	// deletion (or failed checks if forced) reported, but not made.
+	DeleteTopic(ctx context.Context, in *TopicDeleteRequest, opts ...grpc.CallOption) (*TopicDeleteResponse, error)
+	// CreateTopics creates the topics in the CreateTopicsRequest.topics field
+	// and those expanded from the CreateTopicsRequest.template field. Topics
+	// are created all-or-nothing: the request fails before any topic is
+	// created if any topic is invalid or already exists. With the
+	// CreateTopicsRequest.dry_run field set, the topics are only validated.
+	CreateTopics(ctx context.Context, in *CreateTopicsRequest, opts ...grpc.CallOption) (*CreateTopicsResponse, error)
+	// PreviewPlacement returns a PlacementPreview with the replica placement
+	// the placement engine would choose for the topic described by the
+	// PlacementRequest, given the current assignments of all topics, and the
+	// resulting change of each broker's replica and leader counts (and free
+	// storage, for the storage strategy). Nothing is created.
+	PreviewPlacement(ctx context.Context, in *PlacementRequest, opts ...grpc.CallOption) (*PlacementPreview, error)
+	// TopicMappings returns a BrokerResponse with the ids field
+	// populated with broker IDs that hold at least one partition
+	// for the requested topic. The topic is specified in the
+	// TopicRequest.name field.
+	TopicMappings(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*BrokerResponse, error)
+	// BrokerMappings returns a TopicResponse with the names field
+	// populated with topics that the broker holds at least one partition
+	// for the requested broker. The broker is specified in the
+	// BrokerRequest.id field.
+	BrokerMappings(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TopicResponse, error)
+	// TagTopic takes a TopicRequest and sets any specified
+	// tags for the named topic. Any existing tags that are
+	// not specified in the request are left unmodified.
+	TagTopic(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TagResponse, error)
+	// DeleteTopicTags takes a TopicRequest and deletes any
+	// specified tags for the named topic. Tags must be provided
+	// as key names only; "key:value" will not target the tag "key".
+	DeleteTopicTags(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TagResponse, error)
+	// TagBroker takes a BrokerRequest and sets any specified
+	// tags for the named broker. Any existing tags that are
+	// not specified in the request are left unmodified.
+	TagBroker(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TagResponse, error)
+	// DeleteBrokerTags takes a BrokerRequest and deletes any
+	// specified tags for the named broker. Tags must be provided
+	// as key names only; "key:value" will not target the tag "key".
+	DeleteBrokerTags(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TagResponse, error)
+	// ClusterState returns a ClusterStateResponse holding a capture of the
+	// cluster state for all topics matching any of the ClusterStateRequest.topic
+	// regex (all topics if none are specified), along with all broker metadata,
+	// metrics metadata and user-defined tags. The state is JSON encoded in the
+	// topicmappr cluster state format (see topicmappr snapshot export).
+	ClusterState(ctx context.Context, in *ClusterStateRequest, opts ...grpc.CallOption) (*ClusterStateResponse, error)
+	// GetUtilization returns a UtilizationResponse with the size of each topic
+	// matching any of the UtilizationRequest.topic regex (all topics if none
+	// are specified) and its partitions, along with the storage utilization of
+	// each broker and the size of the replicas it holds of those topics. Sizes
+	// are sourced from the partition size and broker metrics metadata.
+	GetUtilization(ctx context.Context, in *UtilizationRequest, opts ...grpc.CallOption) (*UtilizationResponse, error)
+	// DescribeBrokerUtilization returns a BrokerUtilizationResponse with the
+	// live network and disk utilization of each broker matching the
+	// BrokerUtilizationRequest.id and tag_query (all brokers if unspecified)
+	// from the configured broker metrics backend, merged with the broker
+	// metadata. Brokers are ranked by network headroom.
+	DescribeBrokerUtilization(ctx context.Context, in *BrokerUtilizationRequest, opts ...grpc.CallOption) (*BrokerUtilizationResponse, error)
+	// GetQuotas returns a QuotaResponse with all client quotas, optionally
+	// filtered by the QuotaRequest.user and QuotaRequest.client_id fields.
+	// A user or client ID of "<default>" matches the default quotas.
+	GetQuotas(ctx context.Context, in *QuotaRequest, opts ...grpc.CallOption) (*QuotaResponse, error)
+	// SetQuota takes a QuotaRequest and sets any specified (non-zero)
+	// quotas for the user, client ID, or client ID of the user. Any
+	// existing quotas that are not specified in the request are left
+	// unmodified. The quotas of the entity are returned.
+	SetQuota(ctx context.Context, in *QuotaRequest, opts ...grpc.CallOption) (*QuotaResponse, error)
+	// DeleteQuota takes a QuotaRequest and deletes the quotas named in the
+	// QuotaRequest.keys field (all quotas if none are specified) for the
+	// user, client ID, or client ID of the user.
+	DeleteQuota(ctx context.Context, in *QuotaRequest, opts ...grpc.CallOption) (*QuotaResponse, error)
+	// ListConsumerGroups returns a ConsumerGroupResponse with the names
+	// field populated with the names of all consumer groups. Requires
+	// the registry to be configured with Kafka bootstrap servers.
+	ListConsumerGroups(ctx context.Context, in *ConsumerGroupRequest, opts ...grpc.CallOption) (*ConsumerGroupResponse, error)
+	// DescribeConsumerGroup returns a ConsumerGroupResponse with the groups
+	// field populated with the consumer group specified in the
+	// ConsumerGroupRequest.name field: its state, members and their
+	// assignments, and the committed offset, end offset and lag of each
+	// partition the group consumes. Requires the registry to be configured
+	// with Kafka bootstrap servers.
+	DescribeConsumerGroup(ctx context.Context, in *ConsumerGroupRequest, opts ...grpc.CallOption) (*ConsumerGroupResponse, error)
+	// ResetConsumerGroupOffsets resets the committed offsets of the consumer
+	// group specified in the OffsetResetRequest.name field for the partitions
+	// of OffsetResetRequest.topic (all partitions if none are specified). The
+	// group must have no active members. An OffsetResetResponse is returned
+	// with the previous and new offset of each partition; no offsets are
+	// committed if the OffsetResetRequest.dry_run field is true.
+	ResetConsumerGroupOffsets(ctx context.Context, in *OffsetResetRequest, opts ...grpc.CallOption) (*OffsetResetResponse, error)
+	// PlanReassignment returns a ReassignmentPlan for a rebuild or rebalance
+	// of the topics in the ReassignmentRequest, computed with the topicmappr
+	// placement engine. Plans are held for approval; no changes are made
+	// until the plan is executed with ExecuteReassignment.
+	PlanReassignment(ctx context.Context, in *ReassignmentRequest, opts ...grpc.CallOption) (*ReassignmentPlan, error)
+	// ExecuteReassignment submits the plan specified in the
+	// ReassignmentExecuteRequest.id field as a partition reassignment and
+	// streams ReassignmentProgress until the reassignment is complete.
+	ExecuteReassignment(ctx context.Context, in *ReassignmentExecuteRequest, opts ...grpc.CallOption) (Registry_ExecuteReassignmentClient, error)
+	// Watch streams a WatchEvent for each cluster change of the types in the
+	// WatchRequest.types field (broker, topic, config or tag), or of all
+	// types if none are specified.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Registry_WatchClient, error)
+	// GetAuditLog returns the audit log entries of mutating calls, most
+	// recent first, optionally filtered by the AuditLogRequest fields.
+	GetAuditLog(ctx context.Context, in *AuditLogRequest, opts ...grpc.CallOption) (*AuditLogResponse, error)
+	// GetLifecycleReport evaluates the topic lifecycle rules, or the rule
+	// named in the LifecycleRequest.rule field, and returns a LifecycleReport
+	// listing each topic violating a rule.
+	GetLifecycleReport(ctx context.Context, in *LifecycleRequest, opts ...grpc.CallOption) (*LifecycleReport, error)
+	// EnforceLifecycle evaluates the topic lifecycle rules as with
+	// GetLifecycleReport and takes the action of each violated rule that
+	// has one, e.g. deleting idle topics or capping retention. Actions are
+	// audit logged.
+	EnforceLifecycle(ctx context.Context, in *LifecycleRequest, opts ...grpc.CallOption) (*LifecycleReport, error)
+}
+
+type registryClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRegistryClient(cc *grpc.ClientConn) RegistryClient {
+	return &registryClient{cc}
+}
+
+func (c *registryClient) GetBrokers(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*BrokerResponse, error) {
+	out := new(BrokerResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/GetBrokers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) ListBrokers(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*BrokerResponse, error) {
+	out := new(BrokerResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/ListBrokers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) GetTopics(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TopicResponse, error) {
+	out := new(TopicResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/GetTopics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) ListTopics(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TopicResponse, error) {
+	out := new(TopicResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/ListTopics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) GetTopicConfig(ctx context.Context, in *TopicConfigRequest, opts ...grpc.CallOption) (*TopicConfigResponse, error) {
+	out := new(TopicConfigResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/GetTopicConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) SetTopicConfig(ctx context.Context, in *TopicConfigRequest, opts ...grpc.CallOption) (*TopicConfigResponse, error) {
+	out := new(TopicConfigResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/SetTopicConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) DeleteTopic(ctx context.Context, in *TopicDeleteRequest, opts ...grpc.CallOption) (*TopicDeleteResponse, error) {
+	out := new(TopicDeleteResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/DeleteTopic", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) CreateTopics(ctx context.Context, in *CreateTopicsRequest, opts ...grpc.CallOption) (*CreateTopicsResponse, error) {
+	out := new(CreateTopicsResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/CreateTopics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) PreviewPlacement(ctx context.Context, in *PlacementRequest, opts ...grpc.CallOption) (*PlacementPreview, error) {
+	out := new(PlacementPreview)
+	err := c.cc.Invoke(ctx, "/registry.Registry/PreviewPlacement", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) TopicMappings(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*BrokerResponse, error) {
+	out := new(BrokerResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/TopicMappings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) BrokerMappings(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TopicResponse, error) {
+	out := new(TopicResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/BrokerMappings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) TagTopic(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TagResponse, error) {
+	out := new(TagResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/TagTopic", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) DeleteTopicTags(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TagResponse, error) {
+	out := new(TagResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/DeleteTopicTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) TagBroker(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TagResponse, error) {
+	out := new(TagResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/TagBroker", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) DeleteBrokerTags(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TagResponse, error) {
+	out := new(TagResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/DeleteBrokerTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) ClusterState(ctx context.Context, in *ClusterStateRequest, opts ...grpc.CallOption) (*ClusterStateResponse, error) {
+	out := new(ClusterStateResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/ClusterState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) GetUtilization(ctx context.Context, in *UtilizationRequest, opts ...grpc.CallOption) (*UtilizationResponse, error) {
+	out := new(UtilizationResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/GetUtilization", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) DescribeBrokerUtilization(ctx context.Context, in *BrokerUtilizationRequest, opts ...grpc.CallOption) (*BrokerUtilizationResponse, error) {
+	out := new(BrokerUtilizationResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/DescribeBrokerUtilization", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) GetQuotas(ctx context.Context, in *QuotaRequest, opts ...grpc.CallOption) (*QuotaResponse, error) {
+	out := new(QuotaResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/GetQuotas", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) SetQuota(ctx context.Context, in *QuotaRequest, opts ...grpc.CallOption) (*QuotaResponse, error) {
+	out := new(QuotaResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/SetQuota", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) DeleteQuota(ctx context.Context, in *QuotaRequest, opts ...grpc.CallOption) (*QuotaResponse, error) {
+	out := new(QuotaResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/DeleteQuota", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) ListConsumerGroups(ctx context.Context, in *ConsumerGroupRequest, opts ...grpc.CallOption) (*ConsumerGroupResponse, error) {
+	out := new(ConsumerGroupResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/ListConsumerGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) DescribeConsumerGroup(ctx context.Context, in *ConsumerGroupRequest, opts ...grpc.CallOption) (*ConsumerGroupResponse, error) {
+	out := new(ConsumerGroupResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/DescribeConsumerGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) ResetConsumerGroupOffsets(ctx context.Context, in *OffsetResetRequest, opts ...grpc.CallOption) (*OffsetResetResponse, error) {
+	out := new(OffsetResetResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/ResetConsumerGroupOffsets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) PlanReassignment(ctx context.Context, in *ReassignmentRequest, opts ...grpc.CallOption) (*ReassignmentPlan, error) {
+	out := new(ReassignmentPlan)
+	err := c.cc.Invoke(ctx, "/registry.Registry/PlanReassignment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) ExecuteReassignment(ctx context.Context, in *ReassignmentExecuteRequest, opts ...grpc.CallOption) (Registry_ExecuteReassignmentClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Registry_serviceDesc.Streams[0], "/registry.Registry/ExecuteReassignment", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &registryExecuteReassignmentClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Registry_ExecuteReassignmentClient interface {
+	Recv() (*ReassignmentProgress, error)
+	grpc.ClientStream
+}
+
+type registryExecuteReassignmentClient struct {
+	grpc.ClientStream
+}
+
+func (x *registryExecuteReassignmentClient) Recv() (*ReassignmentProgress, error) {
+	m := new(ReassignmentProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *registryClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Registry_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Registry_serviceDesc.Streams[1], "/registry.Registry/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &registryWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Registry_WatchClient interface {
+	Recv() (*WatchEvent, error)
+	grpc.ClientStream
+}
+
+type registryWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *registryWatchClient) Recv() (*WatchEvent, error) {
+	m := new(WatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *registryClient) GetAuditLog(ctx context.Context, in *AuditLogRequest, opts ...grpc.CallOption) (*AuditLogResponse, error) {
+	out := new(AuditLogResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/GetAuditLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) GetLifecycleReport(ctx context.Context, in *LifecycleRequest, opts ...grpc.CallOption) (*LifecycleReport, error) {
+	out := new(LifecycleReport)
+	err := c.cc.Invoke(ctx, "/registry.Registry/GetLifecycleReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) EnforceLifecycle(ctx context.Context, in *LifecycleRequest, opts ...grpc.CallOption) (*LifecycleReport, error) {
+	out := new(LifecycleReport)
+	err := c.cc.Invoke(ctx, "/registry.Registry/EnforceLifecycle", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -675,6 +4991,8 @@ type RegistryServer interface {
 	// a single broker ID is returned matching the ID specified in the
 	// Broker object if the broker exists. Otherwise all brokers are returned,
 	// optionally filtered by any provided BrokerRequest.tags parameters.
+	// IDs are sorted and paginated by the BrokerRequest.limit; the next
+	// page is requested with the BrokerResponse.next_page_token.
 	ListBrokers(context.Context, *BrokerRequest) (*BrokerResponse, error)
 	// GetTopics returns a TopicResponse with the topics field populated
 	// with full topic metadata. If the input TopicRequest.name field is
@@ -687,7 +5005,43 @@ type RegistryServer interface {
 	// a single topic name is returned matching the name specified in the
 	// Topic object if the topic exists. Otherwise all topics are returned,
 	// optionally filtered by any provided TopicRequest.tags parameters.
+	// Names are sorted and paginated by the TopicRequest.limit; the next
+	// page is requested with the TopicResponse.next_page_token.
 	ListTopics(context.Context, *TopicRequest) (*TopicResponse, error)
+	// GetTopicConfig returns a TopicConfigResponse with the dynamic config
+	// overrides of the topic specified in the TopicConfigRequest.name field.
+	GetTopicConfig(context.Context, *TopicConfigRequest) (*TopicConfigResponse, error)
+	// SetTopicConfig takes a TopicConfigRequest and sets the configs in the
+	// TopicConfigRequest.configs field and deletes the configs named in the
+	// TopicConfigRequest.delete field for the topic. Configs not specified
+	// are left unmodified. Only supported configs are accepted, and values
+	// are validated. The resulting topic configs are returned. With the
+	// TopicConfigRequest.dry_run field set, the request is only validated and
+	// the configs that would result are returned.
+	SetTopicConfig(context.Context, *TopicConfigRequest) (*TopicConfigResponse, error)
+	// DeleteTopic takes a TopicDeleteRequest and marks the topic specified in
+	// the TopicDeleteRequest.name field for deletion. Deletion is denied if
+	// the topic has had messages produced within the configured idle window,
+	// is consumed by a consumer group with active members, or has the
+	// configured protected tag. Setting the TopicDeleteRequest.force field
+	// overrides these checks in two steps: the first request returns a
+	// confirmation token, which must be provided in the confirmation_token
+	// field of a second forced request within 5 minutes. With the
+	// TopicDeleteRequest.dry_run field set, the checks are run and the
+	// deletion (or failed checks if forced) reported, but not made.
+	DeleteTopic(context.Context, *TopicDeleteRequest) (*TopicDeleteResponse, error)
+	// CreateTopics creates the topics in the CreateTopicsRequest.topics field
+	// and those expanded from the CreateTopicsRequest.template field. Topics
+	// are created all-or-nothing: the request fails before any topic is
+	// created if any topic is invalid or already exists. With the
+	// CreateTopicsRequest.dry_run field set, the topics are only validated.
+	CreateTopics(context.Context, *CreateTopicsRequest) (*CreateTopicsResponse, error)
+	// PreviewPlacement returns a PlacementPreview with the replica placement
+	// the placement engine would choose for the topic described by the
+	// PlacementRequest, given the current assignments of all topics, and the
+	// resulting change of each broker's replica and leader counts (and free
+	// storage, for the storage strategy). Nothing is created.
+	PreviewPlacement(context.Context, *PlacementRequest) (*PlacementPreview, error)
 	// TopicMappings returns a BrokerResponse with the ids field
 	// populated with broker IDs that hold at least one partition
 	// for the requested topic. The topic is specified in the
@@ -714,80 +5068,244 @@ type RegistryServer interface {
 	// specified tags for the named broker. Tags must be provided
 	// as key names only; "key:value" will not target the tag "key".
 	DeleteBrokerTags(context.Context, *BrokerRequest) (*TagResponse, error)
+	// ClusterState returns a ClusterStateResponse holding a capture of the
+	// cluster state for all topics matching any of the ClusterStateRequest.topic
+	// regex (all topics if none are specified), along with all broker metadata,
+	// metrics metadata and user-defined tags. The state is JSON encoded in the
+	// topicmappr cluster state format (see topicmappr snapshot export).
+	ClusterState(context.Context, *ClusterStateRequest) (*ClusterStateResponse, error)
+	// GetUtilization returns a UtilizationResponse with the size of each topic
+	// matching any of the UtilizationRequest.topic regex (all topics if none
+	// are specified) and its partitions, along with the storage utilization of
+	// each broker and the size of the replicas it holds of those topics. Sizes
+	// are sourced from the partition size and broker metrics metadata.
+	GetUtilization(context.Context, *UtilizationRequest) (*UtilizationResponse, error)
+	// DescribeBrokerUtilization returns a BrokerUtilizationResponse with the
+	// live network and disk utilization of each broker matching the
+	// BrokerUtilizationRequest.id and tag_query (all brokers if unspecified)
+	// from the configured broker metrics backend, merged with the broker
+	// metadata. Brokers are ranked by network headroom.
+	DescribeBrokerUtilization(context.Context, *BrokerUtilizationRequest) (*BrokerUtilizationResponse, error)
+	// GetQuotas returns a QuotaResponse with all client quotas, optionally
+	// filtered by the QuotaRequest.user and QuotaRequest.client_id fields.
+	// A user or client ID of "<default>" matches the default quotas.
+	GetQuotas(context.Context, *QuotaRequest) (*QuotaResponse, error)
+	// SetQuota takes a QuotaRequest and sets any specified (non-zero)
+	// quotas for the user, client ID, or client ID of the user. Any
+	// existing quotas that are not specified in the request are left
+	// unmodified. The quotas of the entity are returned.
+	SetQuota(context.Context, *QuotaRequest) (*QuotaResponse, error)
+	// DeleteQuota takes a QuotaRequest and deletes the quotas named in the
+	// QuotaRequest.keys field (all quotas if none are specified) for the
+	// user, client ID, or client ID of the user.
+	DeleteQuota(context.Context, *QuotaRequest) (*QuotaResponse, error)
+	// ListConsumerGroups returns a ConsumerGroupResponse with the names
+	// field populated with the names of all consumer groups. Requires
+	// the registry to be configured with Kafka bootstrap servers.
+	ListConsumerGroups(context.Context, *ConsumerGroupRequest) (*ConsumerGroupResponse, error)
+	// DescribeConsumerGroup returns a ConsumerGroupResponse with the groups
+	// field populated with the consumer group specified in the
+	// ConsumerGroupRequest.name field: its state, members and their
+	// assignments, and the committed offset, end offset and lag of each
+	// partition the group consumes. Requires the registry to be configured
+	// with Kafka bootstrap servers.
+	DescribeConsumerGroup(context.Context, *ConsumerGroupRequest) (*ConsumerGroupResponse, error)
+	// ResetConsumerGroupOffsets resets the committed offsets of the consumer
+	// group specified in the OffsetResetRequest.name field for the partitions
+	// of OffsetResetRequest.topic (all partitions if none are specified). The
+	// group must have no active members. An OffsetResetResponse is returned
+	// with the previous and new offset of each partition; no offsets are
+	// committed if the OffsetResetRequest.dry_run field is true.
+	ResetConsumerGroupOffsets(context.Context, *OffsetResetRequest) (*OffsetResetResponse, error)
+	// PlanReassignment returns a ReassignmentPlan for a rebuild or rebalance
+	// of the topics in the ReassignmentRequest, computed with the topicmappr
+	// placement engine. Plans are held for approval; no changes are made
+	// until the plan is executed with ExecuteReassignment.
+	PlanReassignment(context.Context, *ReassignmentRequest) (*ReassignmentPlan, error)
+	// ExecuteReassignment submits the plan specified in the
+	// ReassignmentExecuteRequest.id field as a partition reassignment and
+	// streams ReassignmentProgress until the reassignment is complete.
+	ExecuteReassignment(*ReassignmentExecuteRequest, Registry_ExecuteReassignmentServer) error
+	// Watch streams a WatchEvent for each cluster change of the types in the
+	// WatchRequest.types field (broker, topic, config or tag), or of all
+	// types if none are specified.
+	Watch(*WatchRequest, Registry_WatchServer) error
+	// GetAuditLog returns the audit log entries of mutating calls, most
+	// recent first, optionally filtered by the AuditLogRequest fields.
+	GetAuditLog(context.Context, *AuditLogRequest) (*AuditLogResponse, error)
+	// GetLifecycleReport evaluates the topic lifecycle rules, or the rule
+	// named in the LifecycleRequest.rule field, and returns a LifecycleReport
+	// listing each topic violating a rule.
+	GetLifecycleReport(context.Context, *LifecycleRequest) (*LifecycleReport, error)
+	// EnforceLifecycle evaluates the topic lifecycle rules as with
+	// GetLifecycleReport and takes the action of each violated rule that
+	// has one, e.g. deleting idle topics or capping retention. Actions are
+	// audit logged.
+	EnforceLifecycle(context.Context, *LifecycleRequest) (*LifecycleReport, error)
 }
 
 func RegisterRegistryServer(s *grpc.Server, srv RegistryServer) {
 	s.RegisterService(&_Registry_serviceDesc, srv)
 }
 
-func _Registry_GetBrokers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(BrokerRequest)
+func _Registry_GetBrokers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BrokerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetBrokers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/GetBrokers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetBrokers(ctx, req.(*BrokerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_ListBrokers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BrokerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).ListBrokers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/ListBrokers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).ListBrokers(ctx, req.(*BrokerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_GetTopics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopicRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetTopics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/GetTopics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetTopics(ctx, req.(*TopicRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_ListTopics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopicRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).ListTopics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/ListTopics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).ListTopics(ctx, req.(*TopicRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_GetTopicConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopicConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetTopicConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/GetTopicConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetTopicConfig(ctx, req.(*TopicConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_SetTopicConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopicConfigRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RegistryServer).GetBrokers(ctx, in)
+		return srv.(RegistryServer).SetTopicConfig(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/registry.Registry/GetBrokers",
+		FullMethod: "/registry.Registry/SetTopicConfig",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RegistryServer).GetBrokers(ctx, req.(*BrokerRequest))
+		return srv.(RegistryServer).SetTopicConfig(ctx, req.(*TopicConfigRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Registry_ListBrokers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(BrokerRequest)
+func _Registry_DeleteTopic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopicDeleteRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RegistryServer).ListBrokers(ctx, in)
+		return srv.(RegistryServer).DeleteTopic(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/registry.Registry/ListBrokers",
+		FullMethod: "/registry.Registry/DeleteTopic",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RegistryServer).ListBrokers(ctx, req.(*BrokerRequest))
+		return srv.(RegistryServer).DeleteTopic(ctx, req.(*TopicDeleteRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Registry_GetTopics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TopicRequest)
+func _Registry_CreateTopics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTopicsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RegistryServer).GetTopics(ctx, in)
+		return srv.(RegistryServer).CreateTopics(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/registry.Registry/GetTopics",
+		FullMethod: "/registry.Registry/CreateTopics",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RegistryServer).GetTopics(ctx, req.(*TopicRequest))
+		return srv.(RegistryServer).CreateTopics(ctx, req.(*CreateTopicsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Registry_ListTopics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TopicRequest)
+func _Registry_PreviewPlacement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlacementRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(RegistryServer).ListTopics(ctx, in)
+		return srv.(RegistryServer).PreviewPlacement(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/registry.Registry/ListTopics",
+		FullMethod: "/registry.Registry/PreviewPlacement",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(RegistryServer).ListTopics(ctx, req.(*TopicRequest))
+		return srv.(RegistryServer).PreviewPlacement(ctx, req.(*PlacementRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -900,6 +5418,282 @@ func _Registry_DeleteBrokerTags_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Registry_ClusterState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClusterStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).ClusterState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/ClusterState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).ClusterState(ctx, req.(*ClusterStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_GetUtilization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UtilizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetUtilization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/GetUtilization",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetUtilization(ctx, req.(*UtilizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_DescribeBrokerUtilization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BrokerUtilizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).DescribeBrokerUtilization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/DescribeBrokerUtilization",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).DescribeBrokerUtilization(ctx, req.(*BrokerUtilizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_GetQuotas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetQuotas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/GetQuotas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetQuotas(ctx, req.(*QuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_SetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).SetQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/SetQuota",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).SetQuota(ctx, req.(*QuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_DeleteQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).DeleteQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/DeleteQuota",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).DeleteQuota(ctx, req.(*QuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_ListConsumerGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConsumerGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).ListConsumerGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/ListConsumerGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).ListConsumerGroups(ctx, req.(*ConsumerGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_DescribeConsumerGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConsumerGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).DescribeConsumerGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/DescribeConsumerGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).DescribeConsumerGroup(ctx, req.(*ConsumerGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_ResetConsumerGroupOffsets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OffsetResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).ResetConsumerGroupOffsets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/ResetConsumerGroupOffsets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).ResetConsumerGroupOffsets(ctx, req.(*OffsetResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_PlanReassignment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReassignmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).PlanReassignment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/PlanReassignment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).PlanReassignment(ctx, req.(*ReassignmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_ExecuteReassignment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReassignmentExecuteRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RegistryServer).ExecuteReassignment(m, &registryExecuteReassignmentServer{stream})
+}
+
+type Registry_ExecuteReassignmentServer interface {
+	Send(*ReassignmentProgress) error
+	grpc.ServerStream
+}
+
+type registryExecuteReassignmentServer struct {
+	grpc.ServerStream
+}
+
+func (x *registryExecuteReassignmentServer) Send(m *ReassignmentProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Registry_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RegistryServer).Watch(m, &registryWatchServer{stream})
+}
+
+type Registry_WatchServer interface {
+	Send(*WatchEvent) error
+	grpc.ServerStream
+}
+
+type registryWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *registryWatchServer) Send(m *WatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Registry_GetAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/GetAuditLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetAuditLog(ctx, req.(*AuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_GetLifecycleReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LifecycleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetLifecycleReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/GetLifecycleReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetLifecycleReport(ctx, req.(*LifecycleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_EnforceLifecycle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LifecycleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).EnforceLifecycle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/EnforceLifecycle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).EnforceLifecycle(ctx, req.(*LifecycleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Registry_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "registry.Registry",
 	HandlerType: (*RegistryServer)(nil),
@@ -920,6 +5714,26 @@ var _Registry_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListTopics",
 			Handler:    _Registry_ListTopics_Handler,
 		},
+		{
+			MethodName: "GetTopicConfig",
+			Handler:    _Registry_GetTopicConfig_Handler,
+		},
+		{
+			MethodName: "SetTopicConfig",
+			Handler:    _Registry_SetTopicConfig_Handler,
+		},
+		{
+			MethodName: "DeleteTopic",
+			Handler:    _Registry_DeleteTopic_Handler,
+		},
+		{
+			MethodName: "CreateTopics",
+			Handler:    _Registry_CreateTopics_Handler,
+		},
+		{
+			MethodName: "PreviewPlacement",
+			Handler:    _Registry_PreviewPlacement_Handler,
+		},
 		{
 			MethodName: "TopicMappings",
 			Handler:    _Registry_TopicMappings_Handler,
@@ -944,7 +5758,70 @@ var _Registry_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteBrokerTags",
 			Handler:    _Registry_DeleteBrokerTags_Handler,
 		},
+		{
+			MethodName: "ClusterState",
+			Handler:    _Registry_ClusterState_Handler,
+		},
+		{
+			MethodName: "GetUtilization",
+			Handler:    _Registry_GetUtilization_Handler,
+		},
+		{
+			MethodName: "DescribeBrokerUtilization",
+			Handler:    _Registry_DescribeBrokerUtilization_Handler,
+		},
+		{
+			MethodName: "GetQuotas",
+			Handler:    _Registry_GetQuotas_Handler,
+		},
+		{
+			MethodName: "SetQuota",
+			Handler:    _Registry_SetQuota_Handler,
+		},
+		{
+			MethodName: "DeleteQuota",
+			Handler:    _Registry_DeleteQuota_Handler,
+		},
+		{
+			MethodName: "ListConsumerGroups",
+			Handler:    _Registry_ListConsumerGroups_Handler,
+		},
+		{
+			MethodName: "DescribeConsumerGroup",
+			Handler:    _Registry_DescribeConsumerGroup_Handler,
+		},
+		{
+			MethodName: "ResetConsumerGroupOffsets",
+			Handler:    _Registry_ResetConsumerGroupOffsets_Handler,
+		},
+		{
+			MethodName: "PlanReassignment",
+			Handler:    _Registry_PlanReassignment_Handler,
+		},
+		{
+			MethodName: "GetAuditLog",
+			Handler:    _Registry_GetAuditLog_Handler,
+		},
+		{
+			MethodName: "GetLifecycleReport",
+			Handler:    _Registry_GetLifecycleReport_Handler,
+		},
+		{
+			MethodName: "EnforceLifecycle",
+			Handler:    _Registry_EnforceLifecycle_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExecuteReassignment",
+			Handler:       _Registry_ExecuteReassignment_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Registry_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "protos/registry.proto",
 }
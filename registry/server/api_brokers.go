@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/honeycombio/kafka-kit/kafkazk"
 	pb "github.com/honeycombio/kafka-kit/registry/protos"
@@ -33,6 +34,11 @@ func (s *Server) GetBrokers(ctx context.Context, req *pb.BrokerRequest) (*pb.Bro
 		return nil, err
 	}
 
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get brokers.
 	brokers, err := s.fetchBrokerSet(req)
 	if err != nil {
@@ -49,19 +55,30 @@ func (s *Server) GetBrokers(ctx context.Context, req *pb.BrokerRequest) (*pb.Bro
 // non-zero, the specified broker is matched if it exists. Otherwise, all
 // brokers found in ZooKeeper are matched. Matched brokers are then filtered
 // by all tags specified, if specified, in the *pb.BrokerRequest tag field.
+// The sorted IDs are paginated by the limit and page_token fields.
 func (s *Server) ListBrokers(ctx context.Context, req *pb.BrokerRequest) (*pb.BrokerResponse, error) {
 	if err := s.ValidateRequest(ctx, req, readRequest); err != nil {
 		return nil, err
 	}
 
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get brokers.
 	brokers, err := s.fetchBrokerSet(req)
 	if err != nil {
 		return nil, err
 	}
 
+	ids, next, err := pageIDs(brokers.IDs(), req.Limit, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
 	// Populate response Ids field.
-	resp := &pb.BrokerResponse{Ids: brokers.IDs()}
+	resp := &pb.BrokerResponse{Ids: ids, NextPageToken: next}
 
 	return resp, nil
 }
@@ -70,7 +87,12 @@ func (s *Server) ListBrokers(ctx context.Context, req *pb.BrokerRequest) (*pb.Br
 // held by the requested broker. The broker is specified in the BrokerRequest.ID
 // field.
 func (s *Server) BrokerMappings(ctx context.Context, req *pb.BrokerRequest) (*pb.TopicResponse, error) {
-	if err := s.ValidateRequest(ctx, req, readRequest); err != nil {
+	if err := s.ValidateRequest(ctx, req, metadataRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
 		return nil, err
 	}
 
@@ -79,8 +101,8 @@ func (s *Server) BrokerMappings(ctx context.Context, req *pb.BrokerRequest) (*pb
 	}
 
 	// Get a kafkazk.BrokerMetaMap.
-	bm, err := s.ZK.GetAllBrokerMeta(false)
-	if err != nil {
+	bm, errs := s.ZK.GetAllBrokerMeta(false)
+	if errs != nil {
 		return nil, ErrFetchingBrokers
 	}
 
@@ -90,8 +112,8 @@ func (s *Server) BrokerMappings(ctx context.Context, req *pb.BrokerRequest) (*pb
 	}
 
 	// Get all topic names.
-	ts, errs := s.ZK.GetTopics([]*regexp.Regexp{regexp.MustCompile(".*")})
-	if errs != nil {
+	ts, err := s.ZK.GetTopics([]*regexp.Regexp{regexp.MustCompile(".*")})
+	if err != nil {
 		return nil, ErrFetchingTopics
 	}
 
@@ -138,8 +160,13 @@ func (s *Server) BrokerMappings(ctx context.Context, req *pb.BrokerRequest) (*pb
 
 // fetchBrokerSet fetches metadata for all brokers.
 func (s *Server) fetchBrokerSet(req *pb.BrokerRequest) (BrokerSet, error) {
+	expr, err := ParseTagExpr(req.TagQuery)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get brokers from ZK.
-	brokers, errs := s.ZK.GetAllBrokerMeta(false)
+	brokers, errs := s.brokerMeta()
 	if errs != nil {
 		return nil, ErrFetchingBrokers
 	}
@@ -160,7 +187,7 @@ func (s *Server) fetchBrokerSet(req *pb.BrokerRequest) (BrokerSet, error) {
 	}
 
 	// Filter results by any supplied tags.
-	filtered, err := s.Tags.FilterBrokers(matched, req.Tag)
+	filtered, err := s.Tags.FilterBrokers(matched, req.Tag, expr)
 	if err != nil {
 		return nil, err
 	}
@@ -175,6 +202,11 @@ func (s *Server) TagBroker(ctx context.Context, req *pb.BrokerRequest) (*pb.TagR
 		return nil, err
 	}
 
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
 	if req.Id == 0 {
 		return nil, ErrBrokerIDEmpty
 	}
@@ -189,6 +221,10 @@ func (s *Server) TagBroker(ctx context.Context, req *pb.BrokerRequest) (*pb.TagR
 		return nil, err
 	}
 
+	if v, set := ts[maintenanceTag]; set && v != "true" && v != "false" {
+		return nil, ErrInvalidMaintenanceTag
+	}
+
 	// Ensure the broker exists.
 
 	// Get brokers from ZK.
@@ -203,11 +239,18 @@ func (s *Server) TagBroker(ctx context.Context, req *pb.BrokerRequest) (*pb.TagR
 
 	// Set the tags.
 	id := fmt.Sprintf("%d", req.Id)
-	err = s.Tags.Store.SetTags(KafkaObject{Type: "broker", ID: id}, ts)
+	o := KafkaObject{Type: "broker", ID: id}
+	before := s.storedTags(o)
+
+	err = s.Tags.Store.SetTags(o, ts)
 	if err != nil {
 		return nil, err
 	}
 
+	s.publishTagChange(o)
+	s.recordChange(ctx, eventBrokerTags, "brokers/"+id, fmt.Sprintf("broker %s tags set: %s", id, strings.Join(req.Tag, ", ")),
+		before, s.storedTags(o))
+
 	return &pb.TagResponse{Message: "success"}, nil
 }
 
@@ -217,6 +260,11 @@ func (s *Server) DeleteBrokerTags(ctx context.Context, req *pb.BrokerRequest) (*
 		return nil, err
 	}
 
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
 	if req.Id == 0 {
 		return nil, ErrBrokerIDEmpty
 	}
@@ -239,11 +287,20 @@ func (s *Server) DeleteBrokerTags(ctx context.Context, req *pb.BrokerRequest) (*
 
 	// Delete the tags.
 	id := fmt.Sprintf("%d", req.Id)
-	err := s.Tags.Store.DeleteTags(KafkaObject{Type: "broker", ID: id}, req.Tag)
-	if err != nil {
-		return nil, err
+	o := KafkaObject{Type: "broker", ID: id}
+	before := s.storedTags(o)
+
+	// Namespace wildcards delete all stored keys of the namespace.
+	if keys := expandTagKeys(before, req.Tag); len(keys) > 0 {
+		if err := s.Tags.Store.DeleteTags(o, keys); err != nil {
+			return nil, err
+		}
 	}
 
+	s.publishTagChange(o)
+	s.recordChange(ctx, eventBrokerTags, "brokers/"+id, fmt.Sprintf("broker %s tags deleted: %s", id, strings.Join(req.Tag, ", ")),
+		before, s.storedTags(o))
+
 	return &pb.TagResponse{Message: "success"}, nil
 }
 
@@ -119,6 +119,79 @@ func TestCustomTagBrokerFilter(t *testing.T) {
 	}
 }
 
+func TestTagQueryBrokerFilter(t *testing.T) {
+	s := testServer()
+
+	s.Tags.Store.SetTags(
+		KafkaObject{Type: "broker", ID: "1001"},
+		TagSet{"maintenance": "true"},
+	)
+
+	tests := map[int]*pb.BrokerRequest{
+		0: &pb.BrokerRequest{TagQuery: "rack=a AND maintenance!=true"},
+		1: &pb.BrokerRequest{TagQuery: "NOT rack=a"},
+		2: &pb.BrokerRequest{TagQuery: "maintenance", Tag: []string{"rack:a"}},
+	}
+
+	expected := map[int]idList{
+		0: idList{1004},
+		1: idList{1002, 1003, 1005},
+		2: idList{1001},
+	}
+
+	for i, req := range tests {
+		resp, err := s.ListBrokers(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if !intsEqual(expected[i], resp.Ids) {
+			t.Errorf("[test %d] Expected broker list %v, got %v", i, expected[i], resp.Ids)
+		}
+	}
+}
+
+func TestListBrokersPagination(t *testing.T) {
+	s := testServer()
+
+	var ids []uint32
+	var token string
+
+	// Page through all brokers, 2 at a time.
+	for pages := 1; ; pages++ {
+		resp, err := s.ListBrokers(context.Background(), &pb.BrokerRequest{Limit: 2, PageToken: token})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(resp.Ids) > 2 {
+			t.Errorf("Expected at most 2 IDs, got %d", len(resp.Ids))
+		}
+
+		ids = append(ids, resp.Ids...)
+		token = resp.NextPageToken
+
+		if token == "" {
+			if pages != 3 {
+				t.Errorf("Expected 3 pages, got %d", pages)
+			}
+			break
+		}
+	}
+
+	expected := []uint32{1001, 1002, 1003, 1004, 1005}
+
+	if !intsEqual(expected, ids) {
+		t.Errorf("Expected broker list %v, got %v", expected, ids)
+	}
+
+	// Topic page tokens aren't valid broker tokens.
+	_, err := s.ListBrokers(context.Background(), &pb.BrokerRequest{PageToken: encodePageToken("test_topic")})
+	if err != ErrInvalidPageToken {
+		t.Errorf("Expected error '%s', got '%v'", ErrInvalidPageToken, err)
+	}
+}
+
 func TestTagBroker(t *testing.T) {
 	s := testServer()
 
@@ -128,6 +201,7 @@ func TestTagBroker(t *testing.T) {
 		2: &pb.BrokerRequest{Id: 1001, Tag: []string{}},
 		3: &pb.BrokerRequest{Id: 1001},
 		4: &pb.BrokerRequest{Id: 1020, Tag: []string{"k:v"}},
+		5: &pb.BrokerRequest{Id: 1001, Tag: []string{"maintenance:yes"}},
 	}
 
 	expected := map[int]error{
@@ -136,6 +210,7 @@ func TestTagBroker(t *testing.T) {
 		2: ErrNilTags,
 		3: ErrNilTags,
 		4: ErrBrokerNotExist,
+		5: ErrInvalidMaintenanceTag,
 	}
 
 	for i, req := range tests {
@@ -1 +1,84 @@
 package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+var (
+	// ErrFetchingClusterState error.
+	ErrFetchingClusterState = errors.New("error fetching cluster state")
+)
+
+// ClusterState returns a capture of the cluster state for all topics
+// matching any of the (unanchored) regex in the *pb.ClusterStateRequest
+// topic field, or all topics if none are specified. The state is returned
+// as a JSON encoded kafkazk.ClusterState with the Tags field populated with
+// all user-defined broker and topic tags.
+func (s *Server) ClusterState(ctx context.Context, req *pb.ClusterStateRequest) (*pb.ClusterStateResponse, error) {
+	if err := s.ValidateRequest(ctx, req, metadataRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	topicRegex := []*regexp.Regexp{}
+	for _, t := range req.Topic {
+		r, err := regexp.Compile(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid topic regex '%s': %s", t, err)
+		}
+		topicRegex = append(topicRegex, r)
+	}
+
+	if len(topicRegex) == 0 {
+		topicRegex = append(topicRegex, tregex)
+	}
+
+	state, err := kafkazk.ClusterStateFromZK(topicRegex, s.ZK)
+	if err != nil {
+		return nil, ErrFetchingClusterState
+	}
+
+	state.Tags = map[string]map[string]map[string]string{}
+
+	var objects []KafkaObject
+	for id := range state.Brokers {
+		objects = append(objects, KafkaObject{Type: "broker", ID: fmt.Sprintf("%d", id)})
+	}
+	for t := range state.Topics {
+		objects = append(objects, KafkaObject{Type: "topic", ID: t})
+	}
+
+	for _, o := range objects {
+		ts, err := s.Tags.EffectiveTags(o)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(ts) == 0 {
+			continue
+		}
+
+		if _, exists := state.Tags[o.Type]; !exists {
+			state.Tags[o.Type] = map[string]map[string]string{}
+		}
+		state.Tags[o.Type][o.ID] = ts
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ClusterStateResponse{State: data}, nil
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+func TestClusterState(t *testing.T) {
+	s := testServer()
+
+	s.Tags.Store.SetTags(KafkaObject{Type: "broker", ID: "1001"}, TagSet{"pool": "tier1"})
+	s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: "test_topic"}, TagSet{"team": "ingest"})
+
+	resp, err := s.ClusterState(context.Background(), &pb.ClusterStateRequest{Topic: []string{"^test_topic$"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var state kafkazk.ClusterState
+	if err := json.Unmarshal(resp.State, &state); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(state.Topics) != 1 {
+		t.Errorf("Expected 1 topic, got %d", len(state.Topics))
+	}
+
+	if _, exists := state.Topics["test_topic"]; !exists {
+		t.Error("Expected topic test_topic in cluster state")
+	}
+
+	if len(state.Brokers) == 0 {
+		t.Error("Expected brokers in cluster state")
+	}
+
+	if v := state.ObjectTags("broker", "1001")["pool"]; v != "tier1" {
+		t.Errorf("Expected broker tag pool:tier1, got '%s'", v)
+	}
+
+	if v := state.ObjectTags("topic", "test_topic")["team"]; v != "ingest" {
+		t.Errorf("Expected topic tag team:ingest, got '%s'", v)
+	}
+
+	if len(state.ObjectTags("broker", "1002")) != 0 {
+		t.Error("Expected no tags for broker 1002")
+	}
+
+	// All topics are included if none are specified.
+	resp, err = s.ClusterState(context.Background(), &pb.ClusterStateRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state = kafkazk.ClusterState{}
+	json.Unmarshal(resp.State, &state)
+
+	if len(state.Topics) != 2 {
+		t.Errorf("Expected 2 topics, got %d", len(state.Topics))
+	}
+
+	if _, err := s.ClusterState(context.Background(), &pb.ClusterStateRequest{Topic: []string{"("}}); err == nil {
+		t.Error("Expected error for invalid topic regex")
+	}
+}
@@ -0,0 +1,379 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+var (
+	// ErrKafkaNotConfigured error.
+	ErrKafkaNotConfigured = errors.New("Kafka bootstrap servers not configured")
+	// ErrConsumerGroupNameEmpty error.
+	ErrConsumerGroupNameEmpty = errors.New("consumer group name field must be specified")
+	// ErrConsumerGroupNotFound error.
+	ErrConsumerGroupNotFound = errors.New("consumer group not found")
+	// ErrConsumerGroupActive error.
+	ErrConsumerGroupActive = errors.New("consumer group must have no active members")
+	// ErrInvalidOffsetReset error.
+	ErrInvalidOffsetReset = errors.New("to field must be one of earliest, latest, timestamp or offset")
+	// ErrInvalidOffset error.
+	ErrInvalidOffset = errors.New("timestamp and offset fields must be >= 0")
+)
+
+// Offset reset targets.
+const (
+	resetEarliest  = "earliest"
+	resetLatest    = "latest"
+	resetTimestamp = "timestamp"
+	resetOffset    = "offset"
+)
+
+// KafkaAdmin handles Kafka Admin API requests.
+// It's implemented by *kafkaadmin.Client.
+type KafkaAdmin interface {
+	ListGroups() ([]kafkaadmin.GroupListing, error)
+	DescribeGroup(string) (*kafkaadmin.GroupDescription, error)
+	FetchOffsets(string) (kafkaadmin.Offsets, error)
+	ListOffsets(map[string][]int, int64) (kafkaadmin.Offsets, error)
+	CommitOffsets(string, kafkaadmin.Offsets) error
+	CreateTopics([]kafkaadmin.TopicSpec, bool) (map[string]error, error)
+	Ping() error
+}
+
+// ListConsumerGroups returns a *pb.ConsumerGroupResponse with the names of
+// all consumer groups, sorted. Groups using the consumer protocol and groups
+// with no protocol (those only committing offsets) are listed; groups of
+// other protocols, such as Kafka Connect workers, are omitted.
+func (s *Server) ListConsumerGroups(ctx context.Context, req *pb.ConsumerGroupRequest) (*pb.ConsumerGroupResponse, error) {
+	if err := s.ValidateRequest(ctx, req, readRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Kafka == nil {
+		return nil, ErrKafkaNotConfigured
+	}
+
+	groups, err := s.Kafka.ListGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ConsumerGroupResponse{}
+	for _, g := range groups {
+		switch g.ProtocolType {
+		case "consumer", "":
+			resp.Names = append(resp.Names, g.GroupID)
+		}
+	}
+
+	return resp, nil
+}
+
+// DescribeConsumerGroup returns a *pb.ConsumerGroupResponse with the consumer
+// group specified in the *pb.ConsumerGroupRequest name field. The partitions
+// of the group are those it has committed offsets for or that are assigned
+// to its members; the lag of each is the difference between the partition
+// end offset and the committed offset.
+func (s *Server) DescribeConsumerGroup(ctx context.Context, req *pb.ConsumerGroupRequest) (*pb.ConsumerGroupResponse, error) {
+	if err := s.ValidateRequest(ctx, req, metadataRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Kafka == nil {
+		return nil, ErrKafkaNotConfigured
+	}
+
+	if req.Name == "" {
+		return nil, ErrConsumerGroupNameEmpty
+	}
+
+	desc, err := s.Kafka.DescribeGroup(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	committed, err := s.Kafka.FetchOffsets(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kafka describes unknown groups as dead.
+	if desc.State == "Dead" && len(desc.Members) == 0 && len(committed) == 0 {
+		return nil, ErrConsumerGroupNotFound
+	}
+
+	group := &pb.ConsumerGroup{
+		Name:         desc.GroupID,
+		State:        desc.State,
+		ProtocolType: desc.ProtocolType,
+		Protocol:     desc.Protocol,
+	}
+
+	// Map of topic to partition to the
+	// member ID assigned the partition.
+	assigned := map[string]map[int]string{}
+
+	for _, m := range desc.Members {
+		member := &pb.GroupMember{
+			MemberId:   m.MemberID,
+			ClientId:   m.ClientID,
+			ClientHost: m.ClientHost,
+		}
+
+		for _, t := range sortedTopics(m.Assignment) {
+			tp := &pb.TopicPartitions{Topic: t}
+			for _, p := range m.Assignment[t] {
+				tp.Partitions = append(tp.Partitions, uint32(p))
+				if assigned[t] == nil {
+					assigned[t] = map[int]string{}
+				}
+				assigned[t][p] = m.MemberID
+			}
+			member.Assignments = append(member.Assignments, tp)
+		}
+
+		group.Members = append(group.Members, member)
+	}
+
+	// All partitions of the group.
+	partitions := map[string][]int{}
+	for t, ps := range committed {
+		for p := range ps {
+			partitions[t] = append(partitions[t], p)
+		}
+	}
+
+	for t, ps := range assigned {
+		for p := range ps {
+			if _, exists := committed[t][p]; !exists {
+				partitions[t] = append(partitions[t], p)
+			}
+		}
+	}
+
+	var end kafkaadmin.Offsets
+	if len(partitions) > 0 {
+		if end, err = s.Kafka.ListOffsets(partitions, kafkaadmin.OffsetLatest); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, t := range sortedTopics(partitions) {
+		ps := partitions[t]
+		sort.Ints(ps)
+
+		for _, p := range ps {
+			pl := &pb.PartitionLag{
+				Topic:           t,
+				Partition:       uint32(p),
+				CommittedOffset: -1,
+				EndOffset:       end[t][p],
+				Lag:             -1,
+				MemberId:        assigned[t][p],
+			}
+
+			if o, exists := committed[t][p]; exists && o >= 0 {
+				pl.CommittedOffset = o
+				pl.Lag = pl.EndOffset - o
+				group.Lag += pl.Lag
+			}
+
+			group.Partitions = append(group.Partitions, pl)
+		}
+	}
+
+	resp := &pb.ConsumerGroupResponse{
+		Groups: map[string]*pb.ConsumerGroup{req.Name: group},
+	}
+
+	return resp, nil
+}
+
+// ResetConsumerGroupOffsets resets the committed offsets of the consumer
+// group specified in the *pb.OffsetResetRequest name field for the topic
+// partitions specified, to the earliest, latest, timestamp or a specific
+// offset. Specific offsets outside of the partition's offset range are
+// clamped to the range, as with kafka-consumer-groups.sh; partitions with
+// no offset at or after the timestamp are reset to the latest offset.
+// Resets are refused for groups with active members, as the members would
+// overwrite the reset offsets with their own commits.
+func (s *Server) ResetConsumerGroupOffsets(ctx context.Context, req *pb.OffsetResetRequest) (*pb.OffsetResetResponse, error) {
+	if err := s.ValidateRequest(ctx, req, writeRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Kafka == nil {
+		return nil, ErrKafkaNotConfigured
+	}
+
+	switch {
+	case req.Name == "":
+		return nil, ErrConsumerGroupNameEmpty
+	case req.Topic == "":
+		return nil, ErrTopicNameEmpty
+	case req.Timestamp < 0, req.Offset < 0:
+		return nil, ErrInvalidOffset
+	}
+
+	switch req.To {
+	case resetEarliest, resetLatest, resetTimestamp, resetOffset:
+	default:
+		return nil, ErrInvalidOffsetReset
+	}
+
+	desc, err := s.Kafka.DescribeGroup(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Groups without members are Empty,
+	// or Dead if they don't exist.
+	if desc.State != "Empty" && desc.State != "Dead" {
+		return nil, ErrConsumerGroupActive
+	}
+
+	// Get the partitions to reset.
+	ts, err := s.ZK.GetTopicState(req.Topic)
+	if err != nil {
+		if _, noNode := err.(kafkazk.ErrNoNode); noNode {
+			return nil, ErrTopicNotExist
+		}
+		return nil, err
+	}
+
+	var partitions []int
+	for p := range ts.Partitions {
+		if i, err := strconv.Atoi(p); err == nil {
+			partitions = append(partitions, i)
+		}
+	}
+
+	if len(req.Partitions) > 0 {
+		var specified []int
+		for _, p := range req.Partitions {
+			if _, exists := ts.Partitions[strconv.Itoa(int(p))]; !exists {
+				return nil, fmt.Errorf("partition %d does not exist", p)
+			}
+			specified = append(specified, int(p))
+		}
+		partitions = specified
+	}
+
+	sort.Ints(partitions)
+	tp := map[string][]int{req.Topic: partitions}
+
+	// The offset range of each partition.
+	earliest, err := s.Kafka.ListOffsets(tp, kafkaadmin.OffsetEarliest)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := s.Kafka.ListOffsets(tp, kafkaadmin.OffsetLatest)
+	if err != nil {
+		return nil, err
+	}
+
+	var atTimestamp kafkaadmin.Offsets
+	if req.To == resetTimestamp {
+		if atTimestamp, err = s.Kafka.ListOffsets(tp, req.Timestamp); err != nil {
+			return nil, err
+		}
+	}
+
+	committed, err := s.Kafka.FetchOffsets(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.OffsetResetResponse{DryRun: req.DryRun}
+	offsets := kafkaadmin.Offsets{req.Topic: map[int]int64{}}
+
+	for _, p := range partitions {
+		min, max := earliest[req.Topic][p], latest[req.Topic][p]
+
+		var offset int64
+		switch req.To {
+		case resetEarliest:
+			offset = min
+		case resetLatest:
+			offset = max
+		case resetTimestamp:
+			offset = atTimestamp[req.Topic][p]
+			if offset < 0 {
+				offset = max
+			}
+		case resetOffset:
+			offset = req.Offset
+			switch {
+			case offset < min:
+				offset = min
+			case offset > max:
+				offset = max
+			}
+		}
+
+		previous, exists := committed[req.Topic][p]
+		if !exists {
+			previous = -1
+		}
+
+		offsets[req.Topic][p] = offset
+		resp.Partitions = append(resp.Partitions, &pb.PartitionOffsetReset{
+			Topic:          req.Topic,
+			Partition:      uint32(p),
+			PreviousOffset: previous,
+			NewOffset:      offset,
+		})
+	}
+
+	if !req.DryRun {
+		if err := s.Kafka.CommitOffsets(req.Name, offsets); err != nil {
+			return nil, err
+		}
+
+		before, after := map[uint32]int64{}, map[uint32]int64{}
+		for _, p := range resp.Partitions {
+			before[p.Partition], after[p.Partition] = p.PreviousOffset, p.NewOffset
+		}
+
+		s.recordChange(ctx, eventGroupOffsets, "consumergroups/"+req.Name, fmt.Sprintf("consumer group %s offsets reset to %s for %d partitions of topic %s",
+			req.Name, req.To, len(partitions), req.Topic), map[string]interface{}{req.Topic: before}, map[string]interface{}{req.Topic: after})
+	}
+
+	return resp, nil
+}
+
+// sortedTopics returns the sorted topic
+// names of a map of topic to partitions.
+func sortedTopics(m map[string][]int) []string {
+	var topics []string
+	for t := range m {
+		topics = append(topics, t)
+	}
+
+	sort.Strings(topics)
+
+	return topics
+}
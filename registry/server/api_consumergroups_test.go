@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+func TestListConsumerGroups(t *testing.T) {
+	s := testServer()
+
+	resp, err := s.ListConsumerGroups(context.Background(), &pb.ConsumerGroupRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"empty_group", "test_group"}
+	if !stringsEqual(expected, resp.Names) {
+		t.Errorf("Expected names %v, got %v", expected, resp.Names)
+	}
+
+	// Not configured.
+	s.Kafka = nil
+	if _, err := s.ListConsumerGroups(context.Background(), &pb.ConsumerGroupRequest{}); err != ErrKafkaNotConfigured {
+		t.Errorf("Expected error '%s', got '%v'", ErrKafkaNotConfigured, err)
+	}
+}
+
+func TestDescribeConsumerGroup(t *testing.T) {
+	s := testServer()
+
+	resp, err := s.DescribeConsumerGroup(context.Background(), &pb.ConsumerGroupRequest{Name: "test_group"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, exists := resp.Groups["test_group"]
+	if !exists {
+		t.Fatalf("Expected group test_group, got %v", resp.Groups)
+	}
+
+	if g.State != "Stable" || g.Protocol != "range" || len(g.Members) != 1 {
+		t.Errorf("Unexpected group %v", g)
+	}
+
+	if a := g.Members[0].Assignments; len(a) != 1 || a[0].Topic != "test_topic" || !intsEqual(a[0].Partitions, []uint32{0, 1, 2}) {
+		t.Errorf("Unexpected assignments %v", a)
+	}
+
+	// Partition 2 has no committed offset.
+	expected := []string{
+		"test_topic 0 80 100 20 consumer-1-abc",
+		"test_topic 1 100 100 0 consumer-1-abc",
+		"test_topic 2 -1 20 -1 consumer-1-abc",
+	}
+
+	var partitions []string
+	for _, p := range g.Partitions {
+		partitions = append(partitions, fmt.Sprintf("%s %d %d %d %d %s",
+			p.Topic, p.Partition, p.CommittedOffset, p.EndOffset, p.Lag, p.MemberId))
+	}
+
+	if !stringsEqual(expected, partitions) {
+		t.Errorf("Expected partitions %v, got %v", expected, partitions)
+	}
+
+	if g.Lag != 20 {
+		t.Errorf("Expected lag 20, got %d", g.Lag)
+	}
+
+	// Groups without members.
+	resp, err = s.DescribeConsumerGroup(context.Background(), &pb.ConsumerGroupRequest{Name: "empty_group"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g := resp.Groups["empty_group"]; g.Lag != 50 || len(g.Partitions) != 1 || g.Partitions[0].MemberId != "" {
+		t.Errorf("Unexpected group %v", g)
+	}
+
+	// Errors.
+	errTests := map[string]error{
+		"":              ErrConsumerGroupNameEmpty,
+		"unknown_group": ErrConsumerGroupNotFound,
+	}
+
+	for name, expected := range errTests {
+		_, err := s.DescribeConsumerGroup(context.Background(), &pb.ConsumerGroupRequest{Name: name})
+		if err != expected {
+			t.Errorf("Expected error '%s' for '%s', got '%v'", expected, name, err)
+		}
+	}
+}
+
+func TestResetConsumerGroupOffsets(t *testing.T) {
+	s := testServer()
+	ka := s.Kafka.(*kafkaAdminMock)
+
+	// resetOffsets returns the new offsets of a reset.
+	resetOffsets := func(req *pb.OffsetResetRequest) []string {
+		resp, err := s.ResetConsumerGroupOffsets(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var offsets []string
+		for _, p := range resp.Partitions {
+			offsets = append(offsets, fmt.Sprintf("%d:%d->%d", p.Partition, p.PreviousOffset, p.NewOffset))
+		}
+
+		return offsets
+	}
+
+	// Dry run, all partitions.
+	req := &pb.OffsetResetRequest{Name: "empty_group", Topic: "test_topic", To: "latest", DryRun: true}
+	expected := []string{"0:50->100", "1:-1->100", "2:-1->20", "3:-1->0", "4:-1->40"}
+
+	if offsets := resetOffsets(req); !stringsEqual(expected, offsets) {
+		t.Errorf("Expected offsets %v, got %v", expected, offsets)
+	}
+
+	if o := ka.committed["empty_group"]["test_topic"]; len(o) != 1 || o[0] != 50 {
+		t.Errorf("Unexpected committed offsets %v", o)
+	}
+
+	tests := map[int]*pb.OffsetResetRequest{
+		0: &pb.OffsetResetRequest{To: "earliest", Partitions: []uint32{0, 1}},
+		1: &pb.OffsetResetRequest{To: "timestamp", Timestamp: 30},
+		2: &pb.OffsetResetRequest{To: "offset", Offset: 200, Partitions: []uint32{0}},
+		3: &pb.OffsetResetRequest{To: "offset", Offset: 5, Partitions: []uint32{1}},
+	}
+
+	expectedOffsets := map[int][]string{
+		0: []string{"0:50->10", "1:-1->10"},
+		// No offsets at the timestamp
+		// reset to the latest offset.
+		1: []string{"0:10->30", "1:10->30", "2:-1->20", "3:-1->0", "4:-1->30"},
+		// Offsets are clamped to
+		// the partition's range.
+		2: []string{"0:30->100"},
+		3: []string{"1:30->10"},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		req := tests[i]
+		req.Name, req.Topic = "empty_group", "test_topic"
+
+		if offsets := resetOffsets(req); !stringsEqual(expectedOffsets[i], offsets) {
+			t.Errorf("[test %d] Expected offsets %v, got %v", i, expectedOffsets[i], offsets)
+		}
+	}
+
+	expectedCommitted := map[int]int64{0: 100, 1: 10, 2: 20, 3: 0, 4: 30}
+	for p, o := range expectedCommitted {
+		if c := ka.committed["empty_group"]["test_topic"][p]; c != o {
+			t.Errorf("Expected partition %d committed offset %d, got %d", p, o, c)
+		}
+	}
+
+	// Errors.
+	errTests := map[*pb.OffsetResetRequest]error{
+		&pb.OffsetResetRequest{Topic: "test_topic", To: "latest"}:                                  ErrConsumerGroupNameEmpty,
+		&pb.OffsetResetRequest{Name: "empty_group", To: "latest"}:                                  ErrTopicNameEmpty,
+		&pb.OffsetResetRequest{Name: "empty_group", Topic: "test_topic", To: "next"}:               ErrInvalidOffsetReset,
+		&pb.OffsetResetRequest{Name: "empty_group", Topic: "test_topic", To: "offset", Offset: -1}: ErrInvalidOffset,
+		&pb.OffsetResetRequest{Name: "test_group", Topic: "test_topic", To: "latest"}:              ErrConsumerGroupActive,
+	}
+
+	for req, expected := range errTests {
+		if _, err := s.ResetConsumerGroupOffsets(context.Background(), req); err != expected {
+			t.Errorf("Expected error '%s' for %v, got '%v'", expected, req, err)
+		}
+	}
+
+	req = &pb.OffsetResetRequest{Name: "empty_group", Topic: "test_topic", To: "latest", Partitions: []uint32{10}}
+	if _, err := s.ResetConsumerGroupOffsets(context.Background(), req); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
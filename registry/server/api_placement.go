@@ -0,0 +1,236 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	"github.com/honeycombio/kafka-kit/planner"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+var (
+	// ErrPartitionSizeRequired error.
+	ErrPartitionSizeRequired = errors.New("partition_size must be specified for the storage strategy")
+)
+
+// PreviewPlacement takes a *pb.PlacementRequest and returns a
+// *pb.PlacementPreview with the placement the topicmappr engine would choose
+// for the prospective topic: its partitions are rebuilt onto the brokers
+// permitted by the request constraints alongside the current assignments of
+// all topics, which are left unchanged. Brokers in maintenance receive no
+// replicas. The replicas and leaders each broker would receive are returned
+// with its resulting replica count. Nothing is created.
+func (s *Server) PreviewPlacement(ctx context.Context, req *pb.PlacementRequest) (*pb.PlacementPreview, error) {
+	if err := s.ValidateRequest(ctx, req, metadataRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !validTopicName.MatchString(req.Name) || req.Name == "." || req.Name == "..":
+		return nil, errors.New("invalid topic name")
+	case req.Partitions == 0:
+		return nil, errors.New("partitions must be specified")
+	case req.Replication == 0:
+		return nil, errors.New("replication must be specified")
+	case req.Strategy == "storage" && req.PartitionSize <= 0:
+		return nil, ErrPartitionSizeRequired
+	}
+
+	existing, err := s.ZK.GetTopics([]*regexp.Regexp{regexp.MustCompile(fmt.Sprintf("^%s$", regexp.QuoteMeta(req.Name)))})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("topic %s already exists", req.Name)
+	}
+
+	topics, err := s.ZK.GetTopics([]*regexp.Regexp{tregex})
+	if err != nil {
+		return nil, err
+	}
+
+	current := kafkazk.NewPartitionMap()
+	if len(topics) > 0 {
+		if current, err = kafkazk.PartitionMapFromZK([]*regexp.Regexp{tregex}, s.ZK); err != nil {
+			return nil, err
+		}
+	}
+
+	plan, err := s.planPlacement(req, current)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replica counts of all topics.
+	used := map[int]int{}
+	for _, p := range current.Partitions {
+		for _, id := range p.Replicas {
+			used[id]++
+		}
+	}
+
+	resp := &pb.PlacementPreview{
+		Name:    req.Name,
+		Brokers: map[uint32]*pb.BrokerPlacement{},
+	}
+
+	for _, w := range plan.Warnings {
+		resp.Warnings = append(resp.Warnings, w.Error())
+	}
+
+	for _, p := range plan.Output.Partitions {
+		if p.Topic != req.Name {
+			continue
+		}
+
+		resp.Partitions = append(resp.Partitions, &pb.PartitionPlacement{
+			Partition: uint32(p.Partition),
+			Replicas:  uint32s(p.Replicas),
+		})
+
+		for i, id := range p.Replicas {
+			b, exists := resp.Brokers[uint32(id)]
+			if !exists {
+				b = &pb.BrokerPlacement{
+					ReplicasBefore: uint32(used[id]),
+					ReplicasAfter:  uint32(used[id]),
+				}
+
+				if req.Strategy == "storage" {
+					b.StorageFreeBefore = plan.BrokersBefore[id].StorageFree
+					b.StorageFreeAfter = b.StorageFreeBefore
+				}
+
+				resp.Brokers[uint32(id)] = b
+			}
+
+			b.Replicas++
+			b.ReplicasAfter++
+			if i == 0 {
+				b.Leaders++
+			}
+
+			if req.Strategy == "storage" {
+				b.StorageFreeAfter -= req.PartitionSize
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// planPlacement returns a rebuild *planner.Plan placing the partitions of
+// the *pb.PlacementRequest topic. The current PartitionMap is included so
+// that placements account for the load of each broker. Partitions with
+// replicas on unregistered brokers are omitted, as the rebuild would
+// otherwise relocate them, as are those without partition metrics for the
+// storage strategy; their storage is reflected in the broker metrics.
+func (s *Server) planPlacement(req *pb.PlacementRequest, current *kafkazk.PartitionMap) (*planner.Plan, error) {
+	storage := req.Strategy == "storage"
+
+	bm, errs := s.ZK.GetAllBrokerMeta(storage)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("error fetching broker metadata: %s", errs[0])
+	}
+
+	var pmm kafkazk.PartitionMetaMap
+	if storage {
+		var err error
+		if pmm, err = s.ZK.GetAllPartitionMeta(); err != nil {
+			return nil, fmt.Errorf("error fetching partition metadata: %s", err)
+		}
+	}
+
+	pm := kafkazk.NewPartitionMap()
+
+	for _, p := range current.Partitions {
+		include := true
+		for _, id := range p.Replicas {
+			if _, exists := bm[id]; !exists {
+				include = false
+			}
+		}
+
+		if storage {
+			if _, err := pmm.Size(p); err != nil {
+				include = false
+			}
+		}
+
+		if include {
+			pm.Partitions = append(pm.Partitions, p)
+		}
+	}
+
+	// The topic partitions are placed
+	// from the stub broker.
+	for i := 0; i < int(req.Partitions); i++ {
+		replicas := make([]int, req.Replication)
+		for j := range replicas {
+			replicas[j] = kafkazk.StubBrokerID
+		}
+
+		pm.Partitions = append(pm.Partitions, kafkazk.Partition{
+			Topic:     req.Name,
+			Partition: i,
+			Replicas:  replicas,
+		})
+	}
+
+	var brokers []int
+	for id := range bm {
+		brokers = append(brokers, id)
+	}
+
+	maintenance, err := s.maintenanceBrokers(bm)
+	if err != nil {
+		return nil, err
+	}
+
+	constraints := kafkazk.TopicConstraints{
+		ExcludedBrokers: uintsToInts(req.ExcludedBrokers),
+		Replication:     int(req.Replication),
+	}
+
+	if len(req.Brokers) > 0 {
+		constraints.Brokers = uintsToInts(req.Brokers)
+	}
+
+	if storage {
+		pmm[req.Name] = map[int]*kafkazk.PartitionMeta{}
+		for i := 0; i < int(req.Partitions); i++ {
+			pmm[req.Name][i] = &kafkazk.PartitionMeta{Size: req.PartitionSize}
+		}
+	}
+
+	return planner.Rebuild(planner.RebuildParams{
+		PartitionMap:       pm,
+		BrokerMeta:         bm,
+		PartitionMeta:      pmm,
+		Brokers:            brokers,
+		Strategy:           req.Strategy,
+		Optimization:       req.Optimization,
+		MinUniqueRackIDs:   int(req.MinUniqueRackIds),
+		OptimizeLeadership: req.OptimizeLeadership,
+		TopicConstraints:   kafkazk.TopicConstraintsMap{req.Name: constraints},
+		Maintenance:        maintenance,
+	})
+}
+
+func uintsToInts(s []uint32) []int {
+	is := make([]int, len(s))
+	for i, v := range s {
+		is[i] = int(v)
+	}
+
+	return is
+}
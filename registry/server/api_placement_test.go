@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+func TestPreviewPlacement(t *testing.T) {
+	s := testServer()
+
+	req := &pb.PlacementRequest{
+		Name:        "new_topic",
+		Partitions:  4,
+		Replication: 2,
+	}
+
+	resp, err := s.PreviewPlacement(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Partitions) != 4 {
+		t.Fatalf("Expected 4 partitions, got %d", len(resp.Partitions))
+	}
+
+	var replicas, leaders uint32
+	for _, b := range resp.Brokers {
+		replicas += b.Replicas
+		leaders += b.Leaders
+
+		if b.ReplicasAfter != b.ReplicasBefore+b.Replicas {
+			t.Errorf("Unexpected replica counts %v", b)
+		}
+	}
+
+	if replicas != 8 || leaders != 4 {
+		t.Errorf("Expected 8 replicas and 4 leaders, got %d and %d", replicas, leaders)
+	}
+
+	// 1005 holds no replicas of the mock
+	// topics, so receives the most.
+	if b := resp.Brokers[1005]; b == nil || b.ReplicasBefore != 0 || b.Replicas != 4 {
+		t.Errorf("Expected 4 replicas placed on 1005, got %v", b)
+	}
+
+	for _, p := range resp.Partitions {
+		if len(p.Replicas) != 2 || p.Replicas[0] == p.Replicas[1] {
+			t.Errorf("Unexpected replicas %v", p.Replicas)
+		}
+	}
+}
+
+func TestPreviewPlacementConstraints(t *testing.T) {
+	s := testServer()
+
+	req := &pb.PlacementRequest{
+		Name:            "new_topic",
+		Partitions:      2,
+		Replication:     2,
+		Brokers:         []uint32{1001, 1002, 1005},
+		ExcludedBrokers: []uint32{1005},
+	}
+
+	resp, err := s.PreviewPlacement(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range resp.Partitions {
+		if !intsEqual(p.Replicas, []uint32{1001, 1002}) && !intsEqual(p.Replicas, []uint32{1002, 1001}) {
+			t.Errorf("Expected replicas on 1001 and 1002, got %v", p.Replicas)
+		}
+	}
+
+	if len(resp.Brokers) != 2 || len(resp.Warnings) != 0 {
+		t.Errorf("Unexpected placement %v", resp)
+	}
+
+	// The storage strategy.
+	req = &pb.PlacementRequest{
+		Name:          "new_topic",
+		Partitions:    1,
+		Replication:   1,
+		Strategy:      "storage",
+		PartitionSize: 500,
+	}
+
+	resp, err = s.PreviewPlacement(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1005 has the most free storage.
+	b := resp.Brokers[1005]
+	if b == nil || b.StorageFreeBefore != 10000 || b.StorageFreeAfter != 9500 {
+		t.Errorf("Expected a replica placed on 1005, got %v", resp)
+	}
+}
+
+func TestPreviewPlacementErrors(t *testing.T) {
+	s := testServer()
+
+	tests := map[int]*pb.PlacementRequest{
+		0: {Name: "test_topic", Partitions: 1, Replication: 1},
+		1: {Name: "a b", Partitions: 1, Replication: 1},
+		2: {Name: "new_topic", Replication: 1},
+		3: {Name: "new_topic", Partitions: 1},
+		4: {Name: "new_topic", Partitions: 1, Replication: 1, Strategy: "storage"},
+		5: {Name: "new_topic", Partitions: 1, Replication: 1, Strategy: "random"},
+	}
+
+	expected := map[int]string{
+		0: "topic test_topic already exists",
+		1: "invalid topic name",
+		2: "partitions must be specified",
+		3: "replication must be specified",
+		4: ErrPartitionSizeRequired.Error(),
+		5: "Invalid placement strategy 'random'",
+	}
+
+	for i, req := range tests {
+		_, err := s.PreviewPlacement(context.Background(), req)
+		if err == nil || err.Error() != expected[i] {
+			t.Errorf("[test %d] Expected error '%s', got '%v'", i, expected[i], err)
+		}
+	}
+}
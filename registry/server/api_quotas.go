@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+var (
+	// ErrFetchingQuotas error.
+	ErrFetchingQuotas = errors.New("error fetching quotas")
+	// ErrQuotaEntityEmpty error.
+	ErrQuotaEntityEmpty = errors.New("user or client_id field must be specified")
+	// ErrNilQuotas error.
+	ErrNilQuotas = errors.New("must provide at least one quota")
+	// ErrInvalidQuota error.
+	ErrInvalidQuota = errors.New("quotas must be > 0")
+)
+
+// GetQuotas returns all client quotas. If the input *pb.QuotaRequest user
+// or client_id fields are non-empty, only quotas of entities matching the
+// specified fields are returned.
+func (s *Server) GetQuotas(ctx context.Context, req *pb.QuotaRequest) (*pb.QuotaResponse, error) {
+	if err := s.ValidateRequest(ctx, req, readRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	qm, err := s.ZK.GetQuotas()
+	if err != nil {
+		return nil, ErrFetchingQuotas
+	}
+
+	resp := &pb.QuotaResponse{}
+
+	for _, e := range qm.Entities() {
+		switch {
+		case req.User != "" && e.User != req.User:
+			continue
+		case req.ClientId != "" && e.ClientID != req.ClientId:
+			continue
+		}
+
+		resp.Quotas = append(resp.Quotas, quotaToPB(e, qm[e]))
+	}
+
+	return resp, nil
+}
+
+// SetQuota sets the quotas specified in the *pb.QuotaRequest for the user,
+// client ID, or client ID of the user. Any existing quotas that were not
+// specified in the request remain unmodified.
+func (s *Server) SetQuota(ctx context.Context, req *pb.QuotaRequest) (*pb.QuotaResponse, error) {
+	if err := s.ValidateRequest(ctx, req, writeRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := quotaEntity(req)
+	if err != nil {
+		return nil, err
+	}
+
+	q := kafkazk.Quotas{}
+	for k, v := range map[string]float64{
+		kafkazk.ProducerByteRate:  req.ProducerByteRate,
+		kafkazk.ConsumerByteRate:  req.ConsumerByteRate,
+		kafkazk.RequestPercentage: req.RequestPercentage,
+	} {
+		switch {
+		case v < 0:
+			return nil, ErrInvalidQuota
+		case v > 0:
+			q[k] = v
+		}
+	}
+
+	if len(q) == 0 {
+		return nil, ErrNilQuotas
+	}
+
+	before, err := s.entityQuotas(e)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.ZK.SetQuotas(e, q); err != nil {
+		return nil, err
+	}
+
+	after, err := s.entityQuotas(e)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditQuotas(ctx, e, "set", before, after)
+
+	return after, nil
+}
+
+// DeleteQuota deletes the quotas named in the *pb.QuotaRequest keys field
+// for the user, client ID, or client ID of the user. All quotas of the
+// entity are deleted if no keys are specified.
+func (s *Server) DeleteQuota(ctx context.Context, req *pb.QuotaRequest) (*pb.QuotaResponse, error) {
+	if err := s.ValidateRequest(ctx, req, writeRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := quotaEntity(req)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := req.Keys
+	if len(keys) == 0 {
+		keys = []string{kafkazk.ProducerByteRate, kafkazk.ConsumerByteRate, kafkazk.RequestPercentage}
+	}
+
+	// Quotas set to 0 are deleted.
+	q := kafkazk.Quotas{}
+	for _, k := range keys {
+		if !kafkazk.ValidQuotaKey(k) {
+			return nil, fmt.Errorf("invalid quota '%s'", k)
+		}
+		q[k] = 0
+	}
+
+	before, err := s.entityQuotas(e)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.ZK.SetQuotas(e, q); err != nil {
+		return nil, err
+	}
+
+	after, err := s.entityQuotas(e)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditQuotas(ctx, e, "deleted", before, after)
+
+	return after, nil
+}
+
+// entityQuotas returns a *pb.QuotaResponse with the
+// quotas of the kafkazk.QuotaEntity, if any.
+func (s *Server) entityQuotas(e kafkazk.QuotaEntity) (*pb.QuotaResponse, error) {
+	qm, err := s.ZK.GetQuotas()
+	if err != nil {
+		return nil, ErrFetchingQuotas
+	}
+
+	resp := &pb.QuotaResponse{}
+	if q, exists := qm[e]; exists {
+		resp.Quotas = append(resp.Quotas, quotaToPB(e, q))
+	}
+
+	return resp, nil
+}
+
+// auditQuotas audit logs a quota change of the kafkazk.QuotaEntity
+// given the entity quotas before and after the change.
+func (s *Server) auditQuotas(ctx context.Context, e kafkazk.QuotaEntity, action string, before, after *pb.QuotaResponse) {
+	var b, a *pb.Quota
+	if len(before.Quotas) > 0 {
+		b = before.Quotas[0]
+	}
+
+	if len(after.Quotas) > 0 {
+		a = after.Quotas[0]
+	}
+
+	s.recordChange(ctx, eventQuotas, "quotas/"+e.String(), fmt.Sprintf("quotas for %s %s", e, action), b, a)
+}
+
+// quotaEntity returns the kafkazk.QuotaEntity
+// specified in a *pb.QuotaRequest.
+func quotaEntity(req *pb.QuotaRequest) (kafkazk.QuotaEntity, error) {
+	if req.User == "" && req.ClientId == "" {
+		return kafkazk.QuotaEntity{}, ErrQuotaEntityEmpty
+	}
+
+	return kafkazk.QuotaEntity{User: req.User, ClientID: req.ClientId}, nil
+}
+
+func quotaToPB(e kafkazk.QuotaEntity, q kafkazk.Quotas) *pb.Quota {
+	return &pb.Quota{
+		User:              e.User,
+		ClientId:          e.ClientID,
+		ProducerByteRate:  q[kafkazk.ProducerByteRate],
+		ConsumerByteRate:  q[kafkazk.ConsumerByteRate],
+		RequestPercentage: q[kafkazk.RequestPercentage],
+	}
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+// quotaZK stores quotas
+// applied with SetQuotas.
+type quotaZK struct {
+	kafkazk.Mock
+	quotas kafkazk.QuotaMap
+}
+
+func (zk *quotaZK) GetQuotas() (kafkazk.QuotaMap, error) {
+	return zk.quotas, nil
+}
+
+func (zk *quotaZK) SetQuotas(e kafkazk.QuotaEntity, q kafkazk.Quotas) (bool, error) {
+	if zk.quotas[e] == nil {
+		zk.quotas[e] = kafkazk.Quotas{}
+	}
+
+	for k, v := range q {
+		if v == 0 {
+			delete(zk.quotas[e], k)
+		} else {
+			zk.quotas[e][k] = v
+		}
+	}
+
+	if len(zk.quotas[e]) == 0 {
+		delete(zk.quotas, e)
+	}
+
+	return true, nil
+}
+
+func TestGetQuotas(t *testing.T) {
+	s := testServer()
+
+	tests := map[int]*pb.QuotaRequest{
+		0: &pb.QuotaRequest{},
+		1: &pb.QuotaRequest{User: "alice"},
+		2: &pb.QuotaRequest{ClientId: "loader"},
+		3: &pb.QuotaRequest{User: "alice", ClientId: "app"},
+	}
+
+	expected := map[int][]string{
+		0: []string{"client-id=loader", "user=alice", "user=alice,client-id=app"},
+		1: []string{"user=alice", "user=alice,client-id=app"},
+		2: []string{"client-id=loader"},
+		3: []string{"user=alice,client-id=app"},
+	}
+
+	for i, req := range tests {
+		resp, err := s.GetQuotas(context.Background(), req)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+			continue
+		}
+
+		var entities []string
+		for _, q := range resp.Quotas {
+			e := kafkazk.QuotaEntity{User: q.User, ClientID: q.ClientId}
+			entities = append(entities, e.String())
+		}
+
+		if !stringsEqual(expected[i], entities) {
+			t.Errorf("[test %d] Expected entities %s, got %s", i, expected[i], entities)
+		}
+	}
+
+	resp, _ := s.GetQuotas(context.Background(), &pb.QuotaRequest{User: "alice"})
+	if q := resp.Quotas[0]; q.ConsumerByteRate != 2097152 || q.RequestPercentage != 50 || q.ProducerByteRate != 0 {
+		t.Errorf("Unexpected quotas %v", q)
+	}
+}
+
+func TestSetQuota(t *testing.T) {
+	s := testServer()
+	s.ZK = &quotaZK{quotas: kafkazk.QuotaMap{}}
+
+	// Set.
+	req := &pb.QuotaRequest{ClientId: "loader", ProducerByteRate: 1048576}
+	resp, err := s.SetQuota(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unspecified quotas are unmodified.
+	req = &pb.QuotaRequest{ClientId: "loader", ConsumerByteRate: 2097152}
+	resp, err = s.SetQuota(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Quotas) != 1 {
+		t.Fatalf("Expected 1 quota, got %d", len(resp.Quotas))
+	}
+
+	if q := resp.Quotas[0]; q.ClientId != "loader" || q.ProducerByteRate != 1048576 || q.ConsumerByteRate != 2097152 {
+		t.Errorf("Unexpected quotas %v", q)
+	}
+
+	// Errors.
+	errTests := map[*pb.QuotaRequest]error{
+		&pb.QuotaRequest{ProducerByteRate: 1048576}:                    ErrQuotaEntityEmpty,
+		&pb.QuotaRequest{User: "alice"}:                                ErrNilQuotas,
+		&pb.QuotaRequest{User: "alice", RequestPercentage: -10}:        ErrInvalidQuota,
+		&pb.QuotaRequest{User: "<default>", ConsumerByteRate: 1048576}: nil,
+	}
+
+	for req, expected := range errTests {
+		if _, err := s.SetQuota(context.Background(), req); err != expected {
+			t.Errorf("Expected error '%v' for %v, got '%v'", expected, req, err)
+		}
+	}
+}
+
+func TestDeleteQuota(t *testing.T) {
+	s := testServer()
+	s.ZK = &quotaZK{quotas: kafkazk.QuotaMap{
+		kafkazk.QuotaEntity{User: "alice"}: kafkazk.Quotas{
+			kafkazk.ConsumerByteRate:  2097152,
+			kafkazk.RequestPercentage: 50,
+		},
+	}}
+
+	// Delete a single quota.
+	req := &pb.QuotaRequest{User: "alice", Keys: []string{"request_percentage"}}
+	resp, err := s.DeleteQuota(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if q := resp.Quotas[0]; q.ConsumerByteRate != 2097152 || q.RequestPercentage != 0 {
+		t.Errorf("Unexpected quotas %v", q)
+	}
+
+	// Invalid quota.
+	req = &pb.QuotaRequest{User: "alice", Keys: []string{"producer_rate"}}
+	if _, err := s.DeleteQuota(context.Background(), req); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	// Delete all quotas.
+	resp, err = s.DeleteQuota(context.Background(), &pb.QuotaRequest{User: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Quotas) != 0 {
+		t.Errorf("Expected no quotas, got %v", resp.Quotas)
+	}
+}
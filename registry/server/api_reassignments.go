@@ -0,0 +1,472 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	"github.com/honeycombio/kafka-kit/planner"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+var (
+	// ErrInvalidOperation error.
+	ErrInvalidOperation = errors.New("operation field must be one of rebuild or rebalance")
+	// ErrReassignmentTopicsEmpty error.
+	ErrReassignmentTopicsEmpty = errors.New("topics field must be specified")
+	// ErrReassignmentBrokersEmpty error.
+	ErrReassignmentBrokersEmpty = errors.New("brokers field must be specified")
+	// ErrRebalanceBrokerChanges error.
+	ErrRebalanceBrokerChanges = errors.New("rebalance only allows broker additions")
+	// ErrPlanNotFound error.
+	ErrPlanNotFound = errors.New("reassignment plan not found or expired")
+	// ErrStalePlan error.
+	ErrStalePlan = errors.New("partition assignments have changed since the plan was made")
+	// ErrReassignmentInProgress error.
+	ErrReassignmentInProgress = errors.New("a partition reassignment is already in progress")
+)
+
+const (
+	// reassignmentPlanTTL is how long a
+	// plan can be executed once made.
+	reassignmentPlanTTL = 30 * time.Minute
+	// reassignmentPollInterval is the default
+	// reassignment progress check interval.
+	reassignmentPollInterval = 5 * time.Second
+)
+
+// topicNormalChar matches characters that topicmappr considers non-regex;
+// topic names made only of these characters are matched exactly.
+var topicNormalChar = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+
+// reassignmentPlans holds plans pending execution.
+type reassignmentPlans struct {
+	sync.Mutex
+	// Map of plan ID to plan.
+	plans map[string]reassignmentPlan
+}
+
+// reassignmentPlan holds the changed
+// partitions of a plan.
+type reassignmentPlan struct {
+	input   *kafkazk.PartitionMap
+	output  *kafkazk.PartitionMap
+	expires time.Time
+}
+
+func newReassignmentPlans() *reassignmentPlans {
+	return &reassignmentPlans{plans: map[string]reassignmentPlan{}}
+}
+
+// add stores the plan and returns its ID.
+func (r *reassignmentPlans) add(p reassignmentPlan) (string, error) {
+	id, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	// Drop expired plans.
+	for k, v := range r.plans {
+		if time.Now().After(v.expires) {
+			delete(r.plans, k)
+		}
+	}
+
+	r.plans[id] = p
+
+	return id, nil
+}
+
+// get returns the plan, if it exists and hasn't expired.
+func (r *reassignmentPlans) get(id string) (reassignmentPlan, bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	p, exists := r.plans[id]
+	if !exists || time.Now().After(p.expires) {
+		return reassignmentPlan{}, false
+	}
+
+	return p, true
+}
+
+// remove deletes the plan.
+func (r *reassignmentPlans) remove(id string) {
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.plans, id)
+}
+
+// PlanReassignment takes a *pb.ReassignmentRequest and returns a
+// *pb.ReassignmentPlan for a rebuild or rebalance of the topics specified,
+// planned with the same engine and inputs (current assignments, broker
+// metadata and metrics, partition metrics) as topicmappr. Brokers in
+// maintenance receive no new partitions. Plans with changes are given an ID
+// that may be executed with ExecuteReassignment until the plan expires.
+func (s *Server) PlanReassignment(ctx context.Context, req *pb.ReassignmentRequest) (*pb.ReassignmentPlan, error) {
+	// Plans are held by the leader,
+	// which executes them.
+	if err := s.requireLeader(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.ValidateRequest(ctx, req, metadataRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case req.Operation != "rebuild" && req.Operation != "rebalance":
+		return nil, ErrInvalidOperation
+	case len(req.Topics) == 0:
+		return nil, ErrReassignmentTopicsEmpty
+	case len(req.Brokers) == 0:
+		return nil, ErrReassignmentBrokersEmpty
+	}
+
+	// Topic names are matched exactly,
+	// other values as a regex.
+	var topics []*regexp.Regexp
+	for _, t := range req.Topics {
+		if topicNormalChar.MatchString(t) {
+			t = fmt.Sprintf("^%s$", t)
+		}
+
+		r, err := regexp.Compile(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid topic regex '%s': %s", t, err)
+		}
+
+		topics = append(topics, r)
+	}
+
+	pm, err := kafkazk.PartitionMapFromZK(topics, s.ZK)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.IncludeInternal {
+		filtered := kafkazk.NewPartitionMap()
+		for _, p := range pm.Partitions {
+			if !kafkazk.IsInternalTopic(p.Topic) {
+				filtered.Partitions = append(filtered.Partitions, p)
+			}
+		}
+
+		pm = filtered
+	}
+
+	if len(pm.Partitions) == 0 {
+		return nil, ErrTopicNotExist
+	}
+
+	var plan *planner.Plan
+	switch req.Operation {
+	case "rebuild":
+		plan, err = s.planRebuild(req, pm)
+	case "rebalance":
+		plan, err = s.planRebalance(req, pm)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ReassignmentPlan{
+		Operation: req.Operation,
+		Stats: &pb.ReassignmentStats{
+			Partitions:          uint32(plan.Stats.Partitions),
+			PartitionsMoved:     uint32(plan.Stats.PartitionsMoved),
+			ReplicasMoved:       uint32(plan.Stats.ReplicasMoved),
+			BytesMoved:          plan.Stats.BytesMoved,
+			StorageRangeBefore:  plan.Stats.StorageRangeBefore,
+			StorageRangeAfter:   plan.Stats.StorageRangeAfter,
+			StorageStddevBefore: plan.Stats.StorageStdDevBefore,
+			StorageStddevAfter:  plan.Stats.StorageStdDevAfter,
+		},
+	}
+
+	for _, w := range plan.Warnings {
+		resp.Warnings = append(resp.Warnings, w.Error())
+	}
+
+	in, out := plan.Changes()
+	for i := range in.Partitions {
+		resp.Partitions = append(resp.Partitions, &pb.PartitionReassignment{
+			Topic:           in.Partitions[i].Topic,
+			Partition:       uint32(in.Partitions[i].Partition),
+			Replicas:        uint32s(in.Partitions[i].Replicas),
+			PlannedReplicas: uint32s(out.Partitions[i].Replicas),
+		})
+	}
+
+	if len(out.Partitions) == 0 {
+		return resp, nil
+	}
+
+	expires := time.Now().Add(reassignmentPlanTTL)
+	resp.Expires = expires.Unix()
+
+	resp.Id, err = s.reassignmentPlans.add(reassignmentPlan{input: in, output: out, expires: expires})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// planRebuild returns a rebuild *planner.Plan for the PartitionMap.
+func (s *Server) planRebuild(req *pb.ReassignmentRequest, pm *kafkazk.PartitionMap) (*planner.Plan, error) {
+	storage := req.Strategy == "storage"
+
+	bm, errs := s.ZK.GetAllBrokerMeta(storage)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("error fetching broker metadata: %s", errs[0])
+	}
+
+	maintenance, err := s.maintenanceBrokers(bm)
+	if err != nil {
+		return nil, err
+	}
+
+	var pmm kafkazk.PartitionMetaMap
+	if storage {
+		if pmm, err = s.ZK.GetAllPartitionMeta(); err != nil {
+			return nil, fmt.Errorf("error fetching partition metadata: %s", err)
+		}
+	}
+
+	return planner.Rebuild(planner.RebuildParams{
+		PartitionMap:       pm,
+		BrokerMeta:         bm,
+		PartitionMeta:      pmm,
+		Brokers:            ints(req.Brokers),
+		Strategy:           req.Strategy,
+		Optimization:       req.Optimization,
+		MinUniqueRackIDs:   int(req.MinUniqueRackIds),
+		Replication:        int(req.Replication),
+		ForceRebuild:       req.ForceRebuild,
+		OptimizeLeadership: req.OptimizeLeadership,
+		Maintenance:        maintenance,
+		ZK:                 s.ZK,
+	})
+}
+
+// planRebalance returns a rebalance *planner.Plan for the PartitionMap.
+// The plan is that of the rebalance result with the lowest storage range.
+func (s *Server) planRebalance(req *pb.ReassignmentRequest, pm *kafkazk.PartitionMap) (*planner.Plan, error) {
+	bm, errs := s.ZK.GetAllBrokerMeta(true)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("error fetching broker metadata: %s", errs[0])
+	}
+
+	pmm, err := s.ZK.GetAllPartitionMeta()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching partition metadata: %s", err)
+	}
+
+	brokers := kafkazk.BrokerMapFromPartitionMap(pm, bm, false)
+	if c, _ := brokers.Update(ints(req.Brokers), bm); c.Missing > 0 || c.OldMissing > 0 || c.Replace > 0 {
+		return nil, ErrRebalanceBrokerChanges
+	}
+
+	maintenance, err := s.maintenanceBrokers(bm)
+	if err != nil {
+		return nil, err
+	}
+
+	brokers.SetMaintenance(maintenance)
+
+	// Defaults match those of topicmappr.
+	params := planner.RebalanceParams{
+		PartitionMap:           pm,
+		Brokers:                brokers,
+		PartitionMeta:          pmm,
+		StorageThreshold:       req.StorageThreshold,
+		StorageThresholdGB:     req.StorageThresholdGb,
+		Tolerance:              req.Tolerance,
+		PartitionLimit:         int(req.PartitionLimit),
+		PartitionSizeThreshold: int(req.PartitionSizeThreshold),
+		LocalityScoped:         req.LocalityScoped,
+		OptimizeLeadership:     req.OptimizeLeadership,
+	}
+
+	if params.StorageThreshold == 0 && params.StorageThresholdGB == 0 {
+		params.StorageThreshold = 0.20
+	}
+
+	if params.PartitionLimit == 0 {
+		params.PartitionLimit = 30
+	}
+
+	results := planner.Rebalance(params)
+	if len(results) == 0 {
+		return &planner.Plan{
+			Input:    pm,
+			Output:   pm.Copy(),
+			Stats:    planner.Stats{Partitions: len(pm.Partitions)},
+			Warnings: []error{errors.New("no brokers targeted for partition offloading")},
+		}, nil
+	}
+
+	return results[0].Plan(params), nil
+}
+
+// ExecuteReassignment takes a *pb.ReassignmentExecuteRequest and submits
+// the changes of the plan specified in the id field as a partition
+// reassignment. Plans are refused if another reassignment is in progress or
+// if the current assignments of any partition changed have diverged from
+// those the plan was made from. Progress is streamed at each poll interval
+// until the reassignment is complete; ending the stream early doesn't stop
+// the reassignment. Executions are audit logged.
+func (s *Server) ExecuteReassignment(req *pb.ReassignmentExecuteRequest, stream pb.Registry_ExecuteReassignmentServer) error {
+	ctx := stream.Context()
+
+	if err := s.ValidateRequest(ctx, req, writeRequest); err != nil {
+		return err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return err
+	}
+
+	plan, exists := s.reassignmentPlans.get(req.Id)
+	if !exists {
+		return ErrPlanNotFound
+	}
+
+	if len(s.ZK.GetReassignments()) > 0 {
+		return ErrReassignmentInProgress
+	}
+
+	// Check the plan input against
+	// current assignments.
+	current := map[string]*kafkazk.PartitionMap{}
+	for _, p := range plan.input.Partitions {
+		if _, exists := current[p.Topic]; !exists {
+			pm, err := s.ZK.GetPartitionMap(p.Topic)
+			if err != nil {
+				return err
+			}
+			current[p.Topic] = pm
+		}
+
+		if !containsPartition(current[p.Topic], p) {
+			return ErrStalePlan
+		}
+	}
+
+	if err := s.ZK.ReassignPartitions(plan.output); err != nil {
+		return err
+	}
+
+	s.metadataCache.invalidate(topicNames(plan.output)...)
+
+	s.reassignmentPlans.remove(req.Id)
+
+	s.recordChange(ctx, eventReassignment, "reassignments/"+req.Id, fmt.Sprintf("reassignment plan %s executed: %d partitions of topics %s",
+		req.Id, len(plan.output.Partitions), strings.Join(topicNames(plan.output), ", ")), plan.input.Partitions, plan.output.Partitions)
+
+	t := time.NewTicker(s.reassignmentPollInterval)
+	defer t.Stop()
+
+	for {
+		progress := &pb.ReassignmentProgress{
+			Id:         req.Id,
+			Partitions: uint32(len(plan.output.Partitions)),
+			Remaining:  uint32(reassigning(s.ZK.GetReassignments(), plan.output)),
+		}
+
+		progress.Complete = progress.Remaining == 0
+
+		if err := stream.Send(progress); err != nil {
+			return err
+		}
+
+		if progress.Complete {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// containsPartition returns whether the PartitionMap
+// holds the partition with the same replicas.
+func containsPartition(pm *kafkazk.PartitionMap, p kafkazk.Partition) bool {
+	for _, p2 := range pm.Partitions {
+		if p2.Topic == p.Topic && p2.Partition == p.Partition {
+			return p.Equal(p2)
+		}
+	}
+
+	return false
+}
+
+// reassigning returns the number of partitions in
+// the PartitionMap that are still being reassigned.
+func reassigning(r kafkazk.Reassignments, pm *kafkazk.PartitionMap) int {
+	var n int
+	for _, p := range pm.Partitions {
+		if _, exists := r[p.Topic][p.Partition]; exists {
+			n++
+		}
+	}
+
+	return n
+}
+
+// topicNames returns the sorted names
+// of all topics in the PartitionMap.
+func topicNames(pm *kafkazk.PartitionMap) []string {
+	seen := map[string]struct{}{}
+	var names []string
+
+	for _, p := range pm.Partitions {
+		if _, exists := seen[p.Topic]; !exists {
+			seen[p.Topic] = struct{}{}
+			names = append(names, p.Topic)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func ints(s []int32) []int {
+	is := make([]int, len(s))
+	for i, v := range s {
+		is[i] = int(v)
+	}
+
+	return is
+}
+
+func uint32s(s []int) []uint32 {
+	us := make([]uint32, len(s))
+	for i, v := range s {
+		us[i] = uint32(v)
+	}
+
+	return us
+}
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+
+	"google.golang.org/grpc"
+)
+
+// reassignZK records submitted reassignments. Each
+// GetReassignments call completes one partition.
+type reassignZK struct {
+	kafkazk.Mock
+	reassigning kafkazk.PartitionList
+	submitted   *kafkazk.PartitionMap
+}
+
+func (zk *reassignZK) GetReassignments() kafkazk.Reassignments {
+	r := kafkazk.Reassignments{}
+	for _, p := range zk.reassigning {
+		if r[p.Topic] == nil {
+			r[p.Topic] = map[int][]int{}
+		}
+		r[p.Topic][p.Partition] = p.Replicas
+	}
+
+	if len(zk.reassigning) > 0 {
+		zk.reassigning = zk.reassigning[1:]
+	}
+
+	return r
+}
+
+func (zk *reassignZK) ReassignPartitions(pm *kafkazk.PartitionMap) error {
+	zk.submitted = pm
+	zk.reassigning = append(kafkazk.PartitionList{}, pm.Partitions...)
+	return nil
+}
+
+// progressStream records sent ReassignmentProgress.
+type progressStream struct {
+	grpc.ServerStream
+	sent []*pb.ReassignmentProgress
+}
+
+func (p *progressStream) Send(m *pb.ReassignmentProgress) error {
+	p.sent = append(p.sent, m)
+	return nil
+}
+
+func (p *progressStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestPlanReassignmentRebuild(t *testing.T) {
+	s := testServer()
+
+	// Replace 1004.
+	req := &pb.ReassignmentRequest{
+		Operation: "rebuild",
+		Topics:    []string{"test_topic"},
+		Brokers:   []int32{1001, 1002, 1003, 1005},
+	}
+
+	resp, err := s.PlanReassignment(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Id == "" || resp.Expires == 0 {
+		t.Errorf("Expected a plan ID and expiry, got %v", resp)
+	}
+
+	// Only test_topic partitions with
+	// replicas on 1004 are changed.
+	if len(resp.Partitions) != 2 || resp.Stats.Partitions != 4 || resp.Stats.PartitionsMoved != 2 {
+		t.Fatalf("Unexpected plan %v", resp)
+	}
+
+	for _, p := range resp.Partitions {
+		if p.Topic != "test_topic" {
+			t.Errorf("Unexpected topic %s", p.Topic)
+		}
+
+		for _, id := range p.PlannedReplicas {
+			if id == 1004 {
+				t.Errorf("Unexpected replica 1004 for partition %d", p.Partition)
+			}
+		}
+	}
+
+	// No changes.
+	req.Brokers = []int32{-1}
+
+	resp, err = s.PlanReassignment(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Id != "" || len(resp.Partitions) != 0 {
+		t.Errorf("Expected an empty plan, got %v", resp)
+	}
+}
+
+func TestPlanReassignmentErrors(t *testing.T) {
+	s := testServer()
+
+	errTests := map[*pb.ReassignmentRequest]error{
+		&pb.ReassignmentRequest{Operation: "move", Topics: []string{"test_topic"}, Brokers: []int32{-1}}:                    ErrInvalidOperation,
+		&pb.ReassignmentRequest{Operation: "rebuild", Brokers: []int32{-1}}:                                                 ErrReassignmentTopicsEmpty,
+		&pb.ReassignmentRequest{Operation: "rebuild", Topics: []string{"test_topic"}}:                                       ErrReassignmentBrokersEmpty,
+		&pb.ReassignmentRequest{Operation: "rebalance", Topics: []string{"test_topic"}, Brokers: []int32{1001, 1002, 1003}}: ErrRebalanceBrokerChanges,
+	}
+
+	for req, expected := range errTests {
+		if _, err := s.PlanReassignment(context.Background(), req); err != expected {
+			t.Errorf("Expected error '%s' for %v, got '%v'", expected, req, err)
+		}
+	}
+
+	// Invalid topic regex.
+	req := &pb.ReassignmentRequest{Operation: "rebuild", Topics: []string{"test_topic["}, Brokers: []int32{-1}}
+	if _, err := s.PlanReassignment(context.Background(), req); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
+
+func TestPlanReassignmentRebalance(t *testing.T) {
+	s := testServer()
+
+	resp, err := s.PlanReassignment(context.Background(), &pb.ReassignmentRequest{
+		Operation: "rebalance",
+		Topics:    []string{"test_topic"},
+		Brokers:   []int32{-1, 1005},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Id == "" || len(resp.Partitions) == 0 || resp.Stats.BytesMoved == 0 {
+		t.Errorf("Unexpected plan %v", resp)
+	}
+}
+
+func TestExecuteReassignment(t *testing.T) {
+	s := testServer()
+	zk := &reassignZK{}
+	s.ZK = zk
+	s.reassignmentPollInterval = time.Millisecond
+
+	plan, err := s.PlanReassignment(context.Background(), &pb.ReassignmentRequest{
+		Operation: "rebuild",
+		Topics:    []string{"test_topic"},
+		Brokers:   []int32{1001, 1002, 1003, 1005},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stream := &progressStream{}
+	if err := s.ExecuteReassignment(&pb.ReassignmentExecuteRequest{Id: plan.Id}, stream); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(zk.submitted.Partitions) != 2 {
+		t.Errorf("Expected 2 partitions submitted, got %v", zk.submitted)
+	}
+
+	// Progress until complete.
+	var remaining []uint32
+	for _, p := range stream.sent {
+		if p.Id != plan.Id || p.Partitions != 2 {
+			t.Errorf("Unexpected progress %v", p)
+		}
+		remaining = append(remaining, p.Remaining)
+	}
+
+	if !intsEqual(remaining, []uint32{2, 1, 0}) || !stream.sent[len(stream.sent)-1].Complete {
+		t.Errorf("Expected remaining [2 1 0] and completion, got %v", stream.sent)
+	}
+
+	// Plans are single use.
+	err = s.ExecuteReassignment(&pb.ReassignmentExecuteRequest{Id: plan.Id}, &progressStream{})
+	if err != ErrPlanNotFound {
+		t.Errorf("Expected error '%s', got '%v'", ErrPlanNotFound, err)
+	}
+}
+
+func TestExecuteReassignmentRefused(t *testing.T) {
+	s := testServer()
+	s.ZK = &reassignZK{}
+
+	req := &pb.ReassignmentRequest{
+		Operation: "rebuild",
+		Topics:    []string{"test_topic"},
+		Brokers:   []int32{1001, 1002, 1003, 1005},
+	}
+
+	plan, err := s.PlanReassignment(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another reassignment is in progress; the
+	// kafkazk.Mock always has reassignments.
+	s.ZK = &kafkazk.Mock{}
+
+	err = s.ExecuteReassignment(&pb.ReassignmentExecuteRequest{Id: plan.Id}, &progressStream{})
+	if err != ErrReassignmentInProgress {
+		t.Errorf("Expected error '%s', got '%v'", ErrReassignmentInProgress, err)
+	}
+
+	// Assignments changed since planning.
+	zk := &reassignZK{}
+	s.ZK = zk
+
+	stale := s.reassignmentPlans.plans[plan.Id]
+	stale.input.Partitions[0].Replicas = []int{1004, 1005}
+
+	err = s.ExecuteReassignment(&pb.ReassignmentExecuteRequest{Id: plan.Id}, &progressStream{})
+	if err != ErrStalePlan || zk.submitted != nil {
+		t.Errorf("Expected error '%s', got '%v'", ErrStalePlan, err)
+	}
+}
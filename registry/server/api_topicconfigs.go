@@ -0,0 +1,279 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+var (
+	// ErrFetchingTopicConfig error.
+	ErrFetchingTopicConfig = errors.New("error fetching topic config")
+	// ErrNilTopicConfigs error.
+	ErrNilTopicConfigs = errors.New("must provide at least one config to set or delete")
+)
+
+// topicConfigValidators maps the supported topic configs to a func
+// validating a value, given the topic replication factor. Replication
+// throttle configs are omitted as these are managed by autothrottle.
+var topicConfigValidators = map[string]func(v string, replication int) error{
+	"retention.ms":                        intAtLeast(-1),
+	"retention.bytes":                     intAtLeast(-1),
+	"delete.retention.ms":                 intAtLeast(0),
+	"segment.ms":                          intAtLeast(1),
+	"segment.bytes":                       intAtLeast(14),
+	"max.message.bytes":                   intAtLeast(0),
+	"min.compaction.lag.ms":               intAtLeast(0),
+	"message.timestamp.difference.max.ms": intAtLeast(0),
+	"min.insync.replicas": func(v string, replication int) error {
+		if err := intAtLeast(1)(v, replication); err != nil {
+			return err
+		}
+
+		if n, _ := strconv.Atoi(v); n > replication {
+			return fmt.Errorf("must be <= the replication factor (%d)", replication)
+		}
+
+		return nil
+	},
+	"cleanup.policy":                 oneOf(true, "delete", "compact"),
+	"compression.type":               oneOf(false, "uncompressed", "zstd", "lz4", "snappy", "gzip", "producer"),
+	"message.timestamp.type":         oneOf(false, "CreateTime", "LogAppendTime"),
+	"unclean.leader.election.enable": oneOf(false, "true", "false"),
+	"message.downconversion.enable":  oneOf(false, "true", "false"),
+}
+
+// intAtLeast returns a validator requiring
+// an integer value of at least min.
+func intAtLeast(min int64) func(string, int) error {
+	return func(v string, _ int) error {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+
+		if n < min {
+			return fmt.Errorf("must be >= %d", min)
+		}
+
+		return nil
+	}
+}
+
+// oneOf returns a validator requiring one of the values
+// specified, or if list is true, a comma-delimited list
+// of the values specified.
+func oneOf(list bool, values ...string) func(string, int) error {
+	return func(v string, _ int) error {
+		vs := []string{v}
+		if list {
+			vs = strings.Split(v, ",")
+		}
+
+		for _, v := range vs {
+			var valid bool
+			for _, s := range values {
+				if strings.TrimSpace(v) == s {
+					valid = true
+				}
+			}
+
+			if !valid {
+				return fmt.Errorf("must be one of %s", strings.Join(values, ", "))
+			}
+		}
+
+		return nil
+	}
+}
+
+// GetTopicConfig returns a *pb.TopicConfigResponse with the dynamic
+// config overrides of the topic specified in the *pb.TopicConfigRequest
+// name field.
+func (s *Server) GetTopicConfig(ctx context.Context, req *pb.TopicConfigRequest) (*pb.TopicConfigResponse, error) {
+	if err := s.ValidateRequest(ctx, req, readRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		return nil, ErrTopicNameEmpty
+	}
+
+	if _, err := s.topicReplication(req.Name); err != nil {
+		return nil, err
+	}
+
+	return s.topicConfig(req.Name)
+}
+
+// SetTopicConfig takes a *pb.TopicConfigRequest and sets the configs in the
+// configs field and deletes the configs named in the delete field for the
+// topic specified in the name field. Configs not specified are left
+// unmodified. Any unsupported configs or invalid values fail the request
+// before any config is changed. Changes are audit logged. Dry runs return
+// the configs and changes that would result without changing any config.
+func (s *Server) SetTopicConfig(ctx context.Context, req *pb.TopicConfigRequest) (*pb.TopicConfigResponse, error) {
+	if err := s.ValidateRequest(ctx, req, writeRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		return nil, ErrTopicNameEmpty
+	}
+
+	if len(req.Configs) == 0 && len(req.Delete) == 0 {
+		return nil, ErrNilTopicConfigs
+	}
+
+	replication, err := s.topicReplication(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate configs.
+	var keys []string
+	for k := range req.Configs {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		validate, supported := topicConfigValidators[k]
+		if !supported {
+			return nil, fmt.Errorf("unsupported topic config '%s'", k)
+		}
+
+		if err := validate(req.Configs[k], replication); err != nil {
+			return nil, fmt.Errorf("invalid value for topic config %s: %s", k, err)
+		}
+	}
+
+	for _, k := range req.Delete {
+		if _, supported := topicConfigValidators[k]; !supported {
+			return nil, fmt.Errorf("unsupported topic config '%s'", k)
+		}
+
+		if _, exists := req.Configs[k]; exists {
+			return nil, fmt.Errorf("topic config %s can't be both set and deleted", k)
+		}
+	}
+
+	current, err := s.topicConfig(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// A value of "" deletes the config.
+	config := kafkazk.KafkaConfig{Type: "topic", Name: req.Name}
+	var changes []string
+
+	for _, k := range keys {
+		config.Configs = append(config.Configs, [2]string{k, req.Configs[k]})
+		if current.Configs[k] != req.Configs[k] {
+			changes = append(changes, fmt.Sprintf("%s: '%s' -> '%s'", k, current.Configs[k], req.Configs[k]))
+		}
+	}
+
+	for _, k := range req.Delete {
+		config.Configs = append(config.Configs, [2]string{k, ""})
+		if v, exists := current.Configs[k]; exists {
+			changes = append(changes, fmt.Sprintf("%s: '%s' -> deleted", k, v))
+		}
+	}
+
+	if req.DryRun {
+		resp := &pb.TopicConfigResponse{Name: req.Name, Configs: map[string]string{}, DryRun: true, Changes: changes}
+		for k, v := range current.Configs {
+			resp.Configs[k] = v
+		}
+
+		for _, c := range config.Configs {
+			if c[1] == "" {
+				delete(resp.Configs, c[0])
+			} else {
+				resp.Configs[c[0]] = c[1]
+			}
+		}
+
+		return resp, nil
+	}
+
+	if _, err := s.ZK.UpdateKafkaConfig(config); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.topicConfig(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) > 0 {
+		s.recordChange(ctx, eventTopicConfigs, "topics/"+req.Name, fmt.Sprintf("topic %s configs updated: %s", req.Name, strings.Join(changes, ", ")),
+			current.Configs, updated.Configs)
+	}
+
+	updated.Changes = changes
+
+	return updated, nil
+}
+
+// topicReplication returns the replication
+// factor of a topic, if the topic exists.
+func (s *Server) topicReplication(t string) (int, error) {
+	ts, err := s.ZK.GetTopicState(t)
+	if err != nil {
+		if _, noNode := err.(kafkazk.ErrNoNode); noNode {
+			return 0, ErrTopicNotExist
+		}
+		return 0, ErrFetchingTopics
+	}
+
+	var replication int
+	for _, replicas := range ts.Partitions {
+		if len(replicas) > replication {
+			replication = len(replicas)
+		}
+	}
+
+	return replication, nil
+}
+
+// topicConfig returns a *pb.TopicConfigResponse
+// with the dynamic configs of a topic.
+func (s *Server) topicConfig(t string) (*pb.TopicConfigResponse, error) {
+	resp := &pb.TopicConfigResponse{Name: t, Configs: map[string]string{}}
+
+	// Topics without config overrides
+	// may have no config znode.
+	tc, err := s.ZK.GetTopicConfig(t)
+	switch err.(type) {
+	case nil:
+	case kafkazk.ErrNoNode:
+		return resp, nil
+	default:
+		return nil, ErrFetchingTopicConfig
+	}
+
+	for k, v := range tc.Config {
+		resp.Configs[k] = v
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+// topicConfigZK stores topic configs
+// applied with UpdateKafkaConfig.
+type topicConfigZK struct {
+	kafkazk.Mock
+	configs map[string]map[string]string
+}
+
+func (zk *topicConfigZK) GetTopicConfig(t string) (*kafkazk.TopicConfig, error) {
+	if zk.configs[t] == nil {
+		return nil, kafkazk.ErrNoNode{}
+	}
+
+	return &kafkazk.TopicConfig{Version: 1, Config: zk.configs[t]}, nil
+}
+
+func (zk *topicConfigZK) UpdateKafkaConfig(c kafkazk.KafkaConfig) (bool, error) {
+	if zk.configs[c.Name] == nil {
+		zk.configs[c.Name] = map[string]string{}
+	}
+
+	for _, kv := range c.Configs {
+		if kv[1] == "" {
+			delete(zk.configs[c.Name], kv[0])
+		} else {
+			zk.configs[c.Name][kv[0]] = kv[1]
+		}
+	}
+
+	return true, nil
+}
+
+func TestGetTopicConfig(t *testing.T) {
+	s := testServer()
+
+	resp, err := s.GetTopicConfig(context.Background(), &pb.TopicConfigRequest{Name: "test_topic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := resp.Configs["leader.replication.throttled.replicas"]; v != "0:1001,0:1002" {
+		t.Errorf("Unexpected configs %v", resp.Configs)
+	}
+
+	// Topics without configs.
+	s.ZK = &topicConfigZK{configs: map[string]map[string]string{}}
+
+	resp, err = s.GetTopicConfig(context.Background(), &pb.TopicConfigRequest{Name: "test_topic"})
+	if err != nil || len(resp.Configs) != 0 {
+		t.Errorf("Expected no configs, got %v, %v", resp, err)
+	}
+
+	if _, err := s.GetTopicConfig(context.Background(), &pb.TopicConfigRequest{}); err != ErrTopicNameEmpty {
+		t.Errorf("Expected error '%s', got '%v'", ErrTopicNameEmpty, err)
+	}
+}
+
+func TestSetTopicConfig(t *testing.T) {
+	s := testServer()
+	s.ZK = &topicConfigZK{configs: map[string]map[string]string{
+		"test_topic": map[string]string{"retention.ms": "86400000"},
+	}}
+
+	req := &pb.TopicConfigRequest{
+		Name: "test_topic",
+		Configs: map[string]string{
+			"cleanup.policy":      "compact,delete",
+			"min.insync.replicas": "2",
+		},
+		Delete: []string{"retention.ms"},
+	}
+
+	resp, err := s.SetTopicConfig(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{"cleanup.policy": "compact,delete", "min.insync.replicas": "2"}
+	if len(resp.Configs) != len(expected) {
+		t.Errorf("Expected configs %v, got %v", expected, resp.Configs)
+	}
+
+	for k, v := range expected {
+		if resp.Configs[k] != v {
+			t.Errorf("Expected config %s value %s, got %s", k, v, resp.Configs[k])
+		}
+	}
+
+	// Invalid requests; the mock topic
+	// has a replication factor of 2.
+	errTests := []*pb.TopicConfigRequest{
+		&pb.TopicConfigRequest{Configs: map[string]string{"retention.ms": "1000"}},
+		&pb.TopicConfigRequest{Name: "test_topic"},
+		&pb.TopicConfigRequest{Name: "test_topic", Configs: map[string]string{"retention.ms": "1d"}},
+		&pb.TopicConfigRequest{Name: "test_topic", Configs: map[string]string{"retention.ms": "-2"}},
+		&pb.TopicConfigRequest{Name: "test_topic", Configs: map[string]string{"min.insync.replicas": "3"}},
+		&pb.TopicConfigRequest{Name: "test_topic", Configs: map[string]string{"cleanup.policy": "compact,archive"}},
+		&pb.TopicConfigRequest{Name: "test_topic", Configs: map[string]string{"leader.replication.throttled.replicas": "*"}},
+		&pb.TopicConfigRequest{Name: "test_topic", Delete: []string{"retention"}},
+		&pb.TopicConfigRequest{Name: "test_topic", Configs: map[string]string{"retention.ms": "1000"}, Delete: []string{"retention.ms"}},
+	}
+
+	for i, req := range errTests {
+		if _, err := s.SetTopicConfig(context.Background(), req); err == nil {
+			t.Errorf("[test %d] Expected non-nil error", i)
+		}
+	}
+
+	// Failed requests change nothing.
+	resp, _ = s.GetTopicConfig(context.Background(), &pb.TopicConfigRequest{Name: "test_topic"})
+	if len(resp.Configs) != len(expected) {
+		t.Errorf("Unexpected configs %v", resp.Configs)
+	}
+}
+
+func TestSetTopicConfigDryRun(t *testing.T) {
+	s := testServer()
+	zk := &topicConfigZK{configs: map[string]map[string]string{
+		"test_topic": map[string]string{"retention.ms": "86400000", "cleanup.policy": "delete"},
+	}}
+	s.ZK = zk
+
+	req := &pb.TopicConfigRequest{
+		Name:    "test_topic",
+		Configs: map[string]string{"retention.ms": "3600000", "min.insync.replicas": "2"},
+		Delete:  []string{"cleanup.policy"},
+		DryRun:  true,
+	}
+
+	resp, err := s.SetTopicConfig(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The configs that would result.
+	expected := map[string]string{"retention.ms": "3600000", "min.insync.replicas": "2"}
+	if !resp.DryRun || len(resp.Configs) != len(expected) {
+		t.Errorf("Expected dry run configs %v, got %v", expected, resp)
+	}
+
+	for k, v := range expected {
+		if resp.Configs[k] != v {
+			t.Errorf("Expected config %s value %s, got %s", k, v, resp.Configs[k])
+		}
+	}
+
+	expectedChanges := []string{
+		"min.insync.replicas: '' -> '2'",
+		"retention.ms: '86400000' -> '3600000'",
+		"cleanup.policy: 'delete' -> deleted",
+	}
+
+	if !stringsEqual(expectedChanges, resp.Changes) {
+		t.Errorf("Expected changes %v, got %v", expectedChanges, resp.Changes)
+	}
+
+	// Nothing is changed.
+	if c := zk.configs["test_topic"]; len(c) != 2 || c["retention.ms"] != "86400000" {
+		t.Errorf("Expected unchanged configs, got %v", c)
+	}
+
+	// Invalid dry runs fail as with changes.
+	req.Configs["min.insync.replicas"] = "3"
+	if _, err := s.SetTopicConfig(context.Background(), req); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	// Changes are returned for requests that aren't dry runs.
+	req = &pb.TopicConfigRequest{Name: "test_topic", Configs: map[string]string{"retention.ms": "3600000"}}
+	resp, err = s.SetTopicConfig(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.DryRun || !stringsEqual(resp.Changes, expectedChanges[1:2]) || zk.configs["test_topic"]["retention.ms"] != "3600000" {
+		t.Errorf("Unexpected response %v", resp)
+	}
+}
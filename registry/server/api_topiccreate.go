@@ -0,0 +1,336 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+var (
+	// ErrNilTopics error.
+	ErrNilTopics = errors.New("topics or a template must be specified")
+	// ErrInvalidTemplate error.
+	ErrInvalidTemplate = errors.New("template pattern must contain {name} and names must be specified")
+)
+
+// topicNamePlaceholder is replaced by each
+// name in topic template patterns.
+const topicNamePlaceholder = "{name}"
+
+// validTopicName matches legal Kafka topic names.
+var validTopicName = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+
+// CreateTopics takes a *pb.CreateTopicsRequest and creates the topics
+// specified in the topics field along with those expanded from the template
+// field. Topics are created all-or-nothing: every topic is validated
+// locally, against the topic policy, for schema compatibility and by the
+// Kafka controller before any is created, and should the creation of any
+// topic fail, those created are deleted. Topic tags are set and schemas
+// registered once all topics are created. Dry runs return the topics that
+// would be created. Creations are audit logged.
+func (s *Server) CreateTopics(ctx context.Context, req *pb.CreateTopicsRequest) (*pb.CreateTopicsResponse, error) {
+	if err := s.ValidateRequest(ctx, req, writeRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Kafka == nil {
+		return nil, ErrKafkaNotConfigured
+	}
+
+	specs, err := topicSpecs(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var topics []kafkaadmin.TopicSpec
+	resp := &pb.CreateTopicsResponse{DryRun: req.DryRun}
+
+	for _, t := range specs {
+		if err := s.validateTopicSpec(t); err != nil {
+			return nil, fmt.Errorf("topic %s: %s", t.Name, err)
+		}
+
+		topics = append(topics, kafkaadmin.TopicSpec{
+			Name:              t.Name,
+			Partitions:        int(t.Partitions),
+			ReplicationFactor: int(t.Replication),
+			Configs:           t.Configs,
+		})
+
+		// Topics are evaluated with their inherited
+		// tags; only the spec tags are stored.
+		tags := s.Tags.withInherited(KafkaObject{Type: "topic", ID: t.Name}, t.Tags)
+
+		resp.Topics = append(resp.Topics, &pb.Topic{
+			Name:        t.Name,
+			Partitions:  t.Partitions,
+			Replication: t.Replication,
+			Tags:        tags,
+			Owner:       tags[ownerTag],
+			Team:        tags[teamTag],
+		})
+	}
+
+	violations, err := s.topicPolicyViolations(resp.Topics)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := policyError(violations); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkSchemas(specs, resp.Topics); err != nil {
+		return nil, err
+	}
+
+	// The controller validates topic existence
+	// and the replication factor against the
+	// live brokers.
+	errs, err := s.Kafka.CreateTopics(topics, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := topicErrors(errs); err != nil {
+		return nil, err
+	}
+
+	if req.DryRun {
+		return resp, nil
+	}
+
+	errs, err = s.Kafka.CreateTopics(topics, false)
+	if err != nil {
+		// Which topics were created is unknown.
+		s.rollbackTopics(s.existingTopics(specs))
+		return nil, err
+	}
+
+	if err := topicErrors(errs); err != nil {
+		var created []string
+		for _, t := range specs {
+			if _, failed := errs[t.Name]; !failed {
+				created = append(created, t.Name)
+			}
+		}
+
+		s.rollbackTopics(created)
+		return nil, err
+	}
+
+	for _, t := range specs {
+		if len(t.Tags) == 0 {
+			continue
+		}
+
+		o := KafkaObject{Type: "topic", ID: t.Name}
+		if err := s.Tags.Store.SetTags(o, TagSet(t.Tags)); err != nil {
+			var names []string
+			for _, t := range specs {
+				names = append(names, t.Name)
+			}
+
+			s.rollbackTopics(names)
+			return nil, fmt.Errorf("error setting tags for topic %s: %s", t.Name, err)
+		}
+	}
+
+	// Schemas registered before a
+	// failure aren't removed.
+	if err := s.registerSchemas(specs, resp.Topics); err != nil {
+		var names []string
+		for _, t := range specs {
+			names = append(names, t.Name)
+		}
+
+		s.rollbackTopics(names)
+		return nil, err
+	}
+
+	for _, t := range resp.Topics {
+		s.recordChange(ctx, eventTopic, "topics/"+t.Name, fmt.Sprintf("topic %s created: %d partitions, replication factor %d",
+			t.Name, t.Partitions, t.Replication), nil, t)
+	}
+
+	// Tags set at creation are published as tag
+	// events, as they're audit logged with the topic.
+	for _, t := range specs {
+		if len(t.Tags) > 0 {
+			o := KafkaObject{Type: "topic", ID: t.Name}
+			s.publishStateEvent(ctx, eventTopicTags, "topics/"+t.Name, fmt.Sprintf("topic %s tags set at creation", t.Name),
+				nil, s.storedTags(o))
+		}
+	}
+
+	return resp, nil
+}
+
+// topicSpecs returns the *pb.TopicSpecs of the topics and the expanded
+// template of a *pb.CreateTopicsRequest. Owner and team fields are set
+// as tags.
+func topicSpecs(req *pb.CreateTopicsRequest) ([]*pb.TopicSpec, error) {
+	var specs []*pb.TopicSpec
+	for _, t := range req.Topics {
+		specs = append(specs, &pb.TopicSpec{
+			Name:        t.Name,
+			Partitions:  t.Partitions,
+			Replication: t.Replication,
+			Configs:     t.Configs,
+			Tags:        ownershipTags(t.Tags, t.Owner, t.Team),
+			Schemas:     t.Schemas,
+		})
+	}
+
+	if tmpl := req.Template; tmpl != nil {
+		if !strings.Contains(tmpl.Pattern, topicNamePlaceholder) || len(tmpl.Names) == 0 {
+			return nil, ErrInvalidTemplate
+		}
+
+		for _, n := range tmpl.Names {
+			specs = append(specs, &pb.TopicSpec{
+				Name:        strings.Replace(tmpl.Pattern, topicNamePlaceholder, n, -1),
+				Partitions:  tmpl.Partitions,
+				Replication: tmpl.Replication,
+				Configs:     tmpl.Configs,
+				Tags:        ownershipTags(tmpl.Tags, tmpl.Owner, tmpl.Team),
+				Schemas:     tmpl.Schemas,
+			})
+		}
+	}
+
+	if len(specs) == 0 {
+		return nil, ErrNilTopics
+	}
+
+	seen := map[string]struct{}{}
+	for _, t := range specs {
+		if _, dupe := seen[t.Name]; dupe {
+			return nil, fmt.Errorf("topic %s specified more than once", t.Name)
+		}
+		seen[t.Name] = struct{}{}
+	}
+
+	return specs, nil
+}
+
+// validateTopicSpec returns an error if the *pb.TopicSpec name, partitions,
+// replication, configs (see SetTopicConfig), tags or schemas are invalid.
+func (s *Server) validateTopicSpec(t *pb.TopicSpec) error {
+	switch {
+	case !validTopicName.MatchString(t.Name) || t.Name == "." || t.Name == "..":
+		return errors.New("invalid topic name")
+	case t.Partitions == 0:
+		return errors.New("partitions must be specified")
+	case t.Replication == 0:
+		return errors.New("replication must be specified")
+	}
+
+	var keys []string
+	for k := range t.Configs {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		validate, supported := topicConfigValidators[k]
+		if !supported {
+			return fmt.Errorf("unsupported topic config '%s'", k)
+		}
+
+		if err := validate(t.Configs[k], int(t.Replication)); err != nil {
+			return fmt.Errorf("invalid value for topic config %s: %s", k, err)
+		}
+	}
+
+	if len(t.Tags) > 0 {
+		o := KafkaObject{Type: "topic", ID: t.Name}
+		if err := checkSetTags(s.Tags.Store, o, TagSet(t.Tags)); err != nil {
+			return err
+		}
+	}
+
+	if len(t.Schemas) > 0 {
+		if s.schemaRegistry == nil {
+			return ErrSchemaRegistryNotConfigured
+		}
+
+		if err := validateTopicSchemas(t.Schemas); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// topicErrors returns an error describing the per-topic
+// errors of a kafkaadmin.Client.CreateTopics call, if any.
+func topicErrors(errs map[string]error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	var names []string
+	for n := range errs {
+		names = append(names, n)
+	}
+
+	sort.Strings(names)
+
+	var msgs []string
+	for _, n := range names {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", n, errs[n]))
+	}
+
+	return fmt.Errorf("topic creation failed; no topics were created: %s", strings.Join(msgs, "; "))
+}
+
+// existingTopics returns the names of the
+// *pb.TopicSpecs topics that exist.
+func (s *Server) existingTopics(specs []*pb.TopicSpec) []string {
+	var names []string
+	for _, t := range specs {
+		topics, err := s.ZK.GetTopics([]*regexp.Regexp{regexp.MustCompile(fmt.Sprintf("^%s$", regexp.QuoteMeta(t.Name)))})
+		if err == nil && len(topics) > 0 {
+			names = append(names, t.Name)
+		}
+	}
+
+	return names
+}
+
+// rollbackTopics deletes the topics created by a failed
+// CreateTopics request along with any tags set.
+func (s *Server) rollbackTopics(names []string) {
+	for _, n := range names {
+		if err := s.ZK.DeleteTopic(n); err != nil {
+			log.Printf("Error rolling back creation of topic %s: %s", n, err)
+			continue
+		}
+
+		o := KafkaObject{Type: "topic", ID: n}
+		if tags, err := s.Tags.Store.GetTags(o); err == nil && len(tags) > 0 {
+			var keys Tags
+			for k := range tags {
+				keys = append(keys, k)
+			}
+
+			s.Tags.Store.DeleteTags(o, keys)
+		}
+
+		log.Printf("Rolled back creation of topic %s", n)
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+func TestCreateTopics(t *testing.T) {
+	s := testServer()
+	k := s.Kafka.(*kafkaAdminMock)
+
+	req := &pb.CreateTopicsRequest{
+		Topics: []*pb.TopicSpec{
+			{Name: "payments.audit", Partitions: 1, Replication: 3},
+		},
+		Template: &pb.TopicTemplate{
+			Pattern:     "payments.{name}.v1",
+			Names:       []string{"events", "commands"},
+			Partitions:  12,
+			Replication: 3,
+			Configs:     map[string]string{"min.insync.replicas": "2"},
+			Tags:        map[string]string{"team": "payments"},
+		},
+		DryRun: true,
+	}
+
+	// Dry runs.
+	resp, err := s.CreateTopics(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, t := range resp.Topics {
+		names = append(names, t.Name)
+	}
+
+	expected := []string{"payments.audit", "payments.events.v1", "payments.commands.v1"}
+	if !resp.DryRun || !stringsEqual(names, expected) {
+		t.Errorf("Expected dry run topics %v, got %v", expected, names)
+	}
+
+	if len(k.created) != 0 {
+		t.Errorf("Expected no topics created, got %v", k.created)
+	}
+
+	req.DryRun = false
+	if _, err := s.CreateTopics(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(k.created) != 3 || k.created["payments.events.v1"].Partitions != 12 {
+		t.Errorf("Unexpected topics created %v", k.created)
+	}
+
+	tags, err := s.Tags.Store.GetTags(KafkaObject{Type: "topic", ID: "payments.commands.v1"})
+	if err != nil || tags["team"] != "payments" {
+		t.Errorf("Expected tag team:payments, got %v (%v)", tags, err)
+	}
+}
+
+func TestCreateTopicsFailures(t *testing.T) {
+	s := testServer()
+
+	tests := map[int]*pb.CreateTopicsRequest{
+		0: &pb.CreateTopicsRequest{},
+		1: &pb.CreateTopicsRequest{Template: &pb.TopicTemplate{Pattern: "payments", Names: []string{"a"}}},
+		2: &pb.CreateTopicsRequest{Topics: []*pb.TopicSpec{{Name: "a", Partitions: 1, Replication: 1}, {Name: "a", Partitions: 1, Replication: 1}}},
+		3: &pb.CreateTopicsRequest{Topics: []*pb.TopicSpec{{Name: "a b", Partitions: 1, Replication: 1}}},
+		4: &pb.CreateTopicsRequest{Topics: []*pb.TopicSpec{{Name: "a", Replication: 1}}},
+		5: &pb.CreateTopicsRequest{Topics: []*pb.TopicSpec{{Name: "a", Partitions: 1, Replication: 2, Configs: map[string]string{"min.insync.replicas": "3"}}}},
+		6: &pb.CreateTopicsRequest{Topics: []*pb.TopicSpec{{Name: "a", Partitions: 1, Replication: 1, Configs: map[string]string{"flush.ms": "1"}}}},
+		7: &pb.CreateTopicsRequest{Topics: []*pb.TopicSpec{{Name: "a", Partitions: 1, Replication: 1, Tags: map[string]string{"partitions": "2"}}}},
+		// test_topic exists.
+		8: &pb.CreateTopicsRequest{Topics: []*pb.TopicSpec{{Name: "a", Partitions: 1, Replication: 1}, {Name: "test_topic", Partitions: 1, Replication: 1}}},
+	}
+
+	expected := map[int]string{
+		0: ErrNilTopics.Error(),
+		1: ErrInvalidTemplate.Error(),
+		2: "topic a specified more than once",
+		3: "topic a b: invalid topic name",
+		4: "topic a: partitions must be specified",
+		5: "topic a: invalid value for topic config min.insync.replicas",
+		6: "topic a: unsupported topic config 'flush.ms'",
+		7: "topic a: tag 'partitions' is a reserved tag",
+		8: "topic creation failed; no topics were created: test_topic: TOPIC_ALREADY_EXISTS",
+	}
+
+	for i, req := range tests {
+		_, err := s.CreateTopics(context.Background(), req)
+		if err == nil || !strings.HasPrefix(err.Error(), expected[i]) {
+			t.Errorf("[test %d] Expected error '%s', got '%v'", i, expected[i], err)
+		}
+	}
+
+	if k := s.Kafka.(*kafkaAdminMock); len(k.created) != 0 {
+		t.Errorf("Expected no topics created, got %v", k.created)
+	}
+}
+
+func TestCreateTopicsRollback(t *testing.T) {
+	s := testServer()
+	zk := &deleteZK{}
+	s.ZK = zk
+
+	k := s.Kafka.(*kafkaAdminMock)
+	k.createErrs = map[string]error{"b": errors.New("INVALID_REPLICATION_FACTOR")}
+
+	req := &pb.CreateTopicsRequest{
+		Template: &pb.TopicTemplate{Pattern: "{name}", Names: []string{"a", "b", "c"}, Partitions: 1, Replication: 1},
+	}
+
+	_, err := s.CreateTopics(context.Background(), req)
+	if err == nil || err.Error() != "topic creation failed; no topics were created: b: INVALID_REPLICATION_FACTOR" {
+		t.Errorf("Unexpected error '%v'", err)
+	}
+
+	// The topics created are deleted.
+	if !stringsEqual(zk.deleted, []string{"a", "c"}) {
+		t.Errorf("Expected topics a, c deleted, got %v", zk.deleted)
+	}
+}
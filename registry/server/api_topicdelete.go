@@ -0,0 +1,315 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+var (
+	// ErrInvalidConfirmationToken error.
+	ErrInvalidConfirmationToken = errors.New("invalid or expired confirmation token")
+	// ErrConfirmationTokenUnforced error.
+	ErrConfirmationTokenUnforced = errors.New("confirmation_token requires force")
+)
+
+// deleteConfirmationTTL is how long a forced
+// deletion confirmation token is valid.
+const deleteConfirmationTTL = 5 * time.Minute
+
+// deleteTokens holds forced topic deletion confirmation tokens.
+type deleteTokens struct {
+	sync.Mutex
+	// Map of token to topic and expiry.
+	tokens map[string]deleteToken
+}
+
+type deleteToken struct {
+	topic   string
+	expires time.Time
+}
+
+func newDeleteTokens() *deleteTokens {
+	return &deleteTokens{tokens: map[string]deleteToken{}}
+}
+
+// issue returns a new confirmation token for the topic.
+func (d *deleteTokens) issue(topic string) (string, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	// Drop expired tokens.
+	for k, t := range d.tokens {
+		if time.Now().After(t.expires) {
+			delete(d.tokens, k)
+		}
+	}
+
+	d.tokens[token] = deleteToken{topic: topic, expires: time.Now().Add(deleteConfirmationTTL)}
+
+	return token, nil
+}
+
+// redeem returns whether the token is a valid confirmation
+// token for the topic. Tokens can only be redeemed once.
+func (d *deleteTokens) redeem(topic, token string) bool {
+	d.Lock()
+	defer d.Unlock()
+
+	t, exists := d.tokens[token]
+	if !exists {
+		return false
+	}
+
+	delete(d.tokens, token)
+
+	return t.topic == topic && time.Now().Before(t.expires)
+}
+
+// newToken returns a random hex token.
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// DeleteTopic takes a *pb.TopicDeleteRequest and marks the topic specified
+// in the name field for deletion if all safety checks pass: the topic must
+// have had no messages produced within the idle window, must not be consumed
+// by a consumer group with active members, and must not have the protected
+// tag. A forced deletion overrides failed checks but first returns a
+// confirmation token; the deletion is made by a second forced request with
+// the token. Deletions are audit logged, and any tags of the topic removed.
+// Dry runs run the checks and return the response of the deletion without
+// making it; forced dry runs neither issue nor redeem confirmation tokens.
+func (s *Server) DeleteTopic(ctx context.Context, req *pb.TopicDeleteRequest) (*pb.TopicDeleteResponse, error) {
+	if err := s.ValidateRequest(ctx, req, writeRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		return nil, ErrTopicNameEmpty
+	}
+
+	if req.ConfirmationToken != "" && !req.Force {
+		return nil, ErrConfirmationTokenUnforced
+	}
+
+	ts, err := s.ZK.GetTopicState(req.Name)
+	if err != nil {
+		if _, noNode := err.(kafkazk.ErrNoNode); noNode {
+			return nil, ErrTopicNotExist
+		}
+		return nil, ErrFetchingTopics
+	}
+
+	failed, err := s.topicDeletionChecks(req.Name, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.TopicDeleteResponse{Name: req.Name, Overridden: failed, DryRun: req.DryRun}
+
+	if !req.Force && len(failed) > 0 {
+		return nil, fmt.Errorf("topic deletion denied: %s", strings.Join(failed, "; "))
+	}
+
+	if req.DryRun {
+		return resp, nil
+	}
+
+	// Forced deletions are made by a second
+	// request with the confirmation token
+	// returned by the first.
+	if req.Force {
+		if req.ConfirmationToken == "" {
+			if resp.ConfirmationToken, err = s.deleteTokens.issue(req.Name); err != nil {
+				return nil, err
+			}
+			return resp, nil
+		}
+
+		if !s.deleteTokens.redeem(req.Name, req.ConfirmationToken) {
+			return nil, ErrInvalidConfirmationToken
+		}
+	}
+
+	change := fmt.Sprintf("topic %s marked for deletion", req.Name)
+	if len(failed) > 0 {
+		change = fmt.Sprintf("%s (forced; overridden checks: %s)", change, strings.Join(failed, "; "))
+	}
+
+	if err := s.deleteTopic(ctx, req.Name, ts, change); err != nil {
+		return nil, err
+	}
+
+	resp.Deleted = true
+
+	return resp, nil
+}
+
+// deleteTopic marks the topic for deletion, audit logging the change, and
+// removes any tags of the topic.
+func (s *Server) deleteTopic(ctx context.Context, t string, ts *kafkazk.TopicState, change string) error {
+	if err := s.ZK.DeleteTopic(t); err != nil {
+		return err
+	}
+
+	s.metadataCache.invalidate(t)
+
+	o := KafkaObject{Type: "topic", ID: t}
+	before := &pb.Topic{
+		Name:        t,
+		Partitions:  uint32(len(ts.Partitions)),
+		Replication: uint32(len(ts.Partitions["0"])),
+		Tags:        s.storedTags(o),
+	}
+
+	s.recordChange(ctx, eventTopic, "topics/"+t, change, before, nil)
+
+	// Remove the topic tags, so that they
+	// don't apply to a recreated topic.
+	if tags, err := s.Tags.Store.GetTags(o); err == nil && len(tags) > 0 {
+		var keys Tags
+		for k := range tags {
+			keys = append(keys, k)
+		}
+
+		if err := s.Tags.Store.DeleteTags(o, keys); err != nil {
+			log.Printf("Error deleting tags for topic %s: %s", t, err)
+		} else {
+			s.publishTagChange(o)
+			s.publishStateEvent(ctx, eventTopicTags, "topics/"+t, fmt.Sprintf("topic %s tags removed on deletion", t),
+				before.Tags, nil)
+		}
+	}
+
+	return nil
+}
+
+// topicDeletionChecks returns a description of each failed
+// topic deletion safety check for the topic. Produce traffic
+// and consumers are checked via the Kafka Admin API; both
+// checks fail if Kafka bootstrap servers aren't configured.
+func (s *Server) topicDeletionChecks(t string, ts *kafkazk.TopicState) ([]string, error) {
+	var failed []string
+
+	// Protected tag.
+	if len(s.protectedTag) > 0 {
+		tags, err := s.Tags.EffectiveTags(KafkaObject{Type: "topic", ID: t})
+		if err != nil {
+			return nil, err
+		}
+
+		if tags.matchAll(s.protectedTag) {
+			for k, v := range s.protectedTag {
+				failed = append(failed, fmt.Sprintf("topic has the protected tag %s:%s", k, v))
+			}
+		}
+	}
+
+	if s.Kafka == nil {
+		return append(failed, "produce traffic and consumers can't be checked without Kafka bootstrap servers"), nil
+	}
+
+	// Recent produce traffic.
+	produced, err := s.producedSince(t, ts, time.Now().Add(-s.deleteIdleWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	if produced {
+		failed = append(failed, fmt.Sprintf("messages were produced within the last %s", s.deleteIdleWindow))
+	}
+
+	// Active consumers.
+	groups, err := s.Kafka.ListGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	var consumers []string
+	for _, g := range groups {
+		if g.ProtocolType != "consumer" {
+			continue
+		}
+
+		desc, err := s.Kafka.DescribeGroup(g.GroupID)
+		if err != nil {
+			return nil, err
+		}
+
+		if consumesTopic(desc, t) {
+			consumers = append(consumers, g.GroupID)
+		}
+	}
+
+	if len(consumers) > 0 {
+		sort.Strings(consumers)
+		failed = append(failed, fmt.Sprintf("consumed by active consumer groups: %s", strings.Join(consumers, ", ")))
+	}
+
+	return failed, nil
+}
+
+// producedSince returns whether messages were produced to any partition of
+// the topic since the time. A ListOffsets for a timestamp returns the
+// earliest offset with a later timestamp, if any. Kafka must be configured.
+func (s *Server) producedSince(t string, ts *kafkazk.TopicState, since time.Time) (bool, error) {
+	var partitions []int
+	for p := range ts.Partitions {
+		if i, err := strconv.Atoi(p); err == nil {
+			partitions = append(partitions, i)
+		}
+	}
+
+	offsets, err := s.Kafka.ListOffsets(map[string][]int{t: partitions}, since.UnixNano()/int64(time.Millisecond))
+	if err != nil {
+		return false, err
+	}
+
+	for _, o := range offsets[t] {
+		if o >= 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// consumesTopic returns whether any partitions of
+// the topic are assigned to members of the group.
+func consumesTopic(g *kafkaadmin.GroupDescription, t string) bool {
+	for _, m := range g.Members {
+		if len(m.Assignment[t]) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+// deleteZK records topics
+// marked for deletion.
+type deleteZK struct {
+	kafkazk.Mock
+	deleted []string
+}
+
+func (zk *deleteZK) DeleteTopic(t string) error {
+	zk.deleted = append(zk.deleted, t)
+	return nil
+}
+
+func TestDeleteTopic(t *testing.T) {
+	s := testServer()
+	zk := &deleteZK{}
+	s.ZK = zk
+
+	// test_topic2 has no traffic or consumers.
+	resp, err := s.DeleteTopic(context.Background(), &pb.TopicDeleteRequest{Name: "test_topic2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !resp.Deleted || len(resp.Overridden) != 0 || !stringsEqual(zk.deleted, []string{"test_topic2"}) {
+		t.Errorf("Unexpected response %v, deleted %v", resp, zk.deleted)
+	}
+
+	// test_topic has an active consumer group.
+	_, err = s.DeleteTopic(context.Background(), &pb.TopicDeleteRequest{Name: "test_topic"})
+	if err == nil || !strings.Contains(err.Error(), "consumed by active consumer groups: test_group") {
+		t.Errorf("Expected active consumer error, got %v", err)
+	}
+
+	if _, err := s.DeleteTopic(context.Background(), &pb.TopicDeleteRequest{}); err != ErrTopicNameEmpty {
+		t.Errorf("Expected error '%s', got '%v'", ErrTopicNameEmpty, err)
+	}
+}
+
+func TestDeleteTopicChecks(t *testing.T) {
+	s := testServer()
+	s.ZK = &deleteZK{}
+	s.deleteIdleWindow = time.Hour
+	s.protectedTag = TagSet{"protected": "true"}
+
+	// Recent traffic; the mock writes each
+	// offset at the timestamp of its value.
+	ka := s.Kafka.(*kafkaAdminMock)
+	ka.logEnd["test_topic2"] = map[int]int64{0: time.Now().UnixNano() / int64(time.Millisecond)}
+
+	s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: "test_topic2"}, TagSet{"protected": "true"})
+
+	ts, _ := s.ZK.GetTopicState("test_topic2")
+	failed, err := s.topicDeletionChecks("test_topic2", ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"topic has the protected tag protected:true",
+		"messages were produced within the last 1h0m0s",
+	}
+
+	if !stringsEqual(expected, failed) {
+		t.Errorf("Expected failed checks %v, got %v", expected, failed)
+	}
+
+	// Without Kafka, traffic and
+	// consumers can't be checked.
+	s.Kafka = nil
+	s.protectedTag = nil
+
+	failed, _ = s.topicDeletionChecks("test_topic2", ts)
+	if len(failed) != 1 || !strings.Contains(failed[0], "without Kafka bootstrap servers") {
+		t.Errorf("Unexpected failed checks %v", failed)
+	}
+}
+
+func TestDeleteTopicForced(t *testing.T) {
+	s := testServer()
+	zk := &deleteZK{}
+	s.ZK = zk
+	s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: "test_topic"}, TagSet{"team": "data"})
+
+	// Step one returns a token.
+	req := &pb.TopicDeleteRequest{Name: "test_topic", Force: true}
+	resp, err := s.DeleteTopic(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Deleted || resp.ConfirmationToken == "" || len(resp.Overridden) != 1 || len(zk.deleted) != 0 {
+		t.Fatalf("Unexpected response %v, deleted %v", resp, zk.deleted)
+	}
+
+	token := resp.ConfirmationToken
+
+	// Tokens are topic specific and
+	// require force.
+	errTests := map[*pb.TopicDeleteRequest]error{
+		&pb.TopicDeleteRequest{Name: "test_topic", ConfirmationToken: token}:              ErrConfirmationTokenUnforced,
+		&pb.TopicDeleteRequest{Name: "test_topic", Force: true, ConfirmationToken: "abc"}: ErrInvalidConfirmationToken,
+	}
+
+	for req, expected := range errTests {
+		if _, err := s.DeleteTopic(context.Background(), req); err != expected {
+			t.Errorf("Expected error '%s' for %v, got '%v'", expected, req, err)
+		}
+	}
+
+	// Step two.
+	req.ConfirmationToken = token
+	resp, err = s.DeleteTopic(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !resp.Deleted || !stringsEqual(zk.deleted, []string{"test_topic"}) {
+		t.Errorf("Unexpected response %v, deleted %v", resp, zk.deleted)
+	}
+
+	// Tags are removed.
+	if tags, _ := s.Tags.Store.GetTags(KafkaObject{Type: "topic", ID: "test_topic"}); len(tags) != 0 {
+		t.Errorf("Expected no tags, got %v", tags)
+	}
+
+	// Tokens are single use.
+	if _, err := s.DeleteTopic(context.Background(), req); err != ErrInvalidConfirmationToken {
+		t.Errorf("Expected error '%s', got '%v'", ErrInvalidConfirmationToken, err)
+	}
+}
+
+func TestDeleteTopicDryRun(t *testing.T) {
+	s := testServer()
+	zk := &deleteZK{}
+	s.ZK = zk
+
+	resp, err := s.DeleteTopic(context.Background(), &pb.TopicDeleteRequest{Name: "test_topic2", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !resp.DryRun || resp.Deleted || len(resp.Overridden) != 0 || len(zk.deleted) != 0 {
+		t.Errorf("Unexpected response %v, deleted %v", resp, zk.deleted)
+	}
+
+	// Failed checks deny dry runs as with deletions.
+	_, err = s.DeleteTopic(context.Background(), &pb.TopicDeleteRequest{Name: "test_topic", DryRun: true})
+	if err == nil || !strings.Contains(err.Error(), "topic deletion denied") {
+		t.Errorf("Expected topic deletion denied error, got %v", err)
+	}
+
+	// Forced dry runs report the checks that
+	// would be overridden without a token.
+	resp, err = s.DeleteTopic(context.Background(), &pb.TopicDeleteRequest{Name: "test_topic", Force: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.ConfirmationToken != "" || len(resp.Overridden) != 1 || len(zk.deleted) != 0 {
+		t.Errorf("Unexpected response %v, deleted %v", resp, zk.deleted)
+	}
+
+	if n := len(s.deleteTokens.tokens); n != 0 {
+		t.Errorf("Expected no confirmation tokens issued, got %d", n)
+	}
+}
+
+func TestDeleteTokens(t *testing.T) {
+	d := newDeleteTokens()
+
+	token, _ := d.issue("test_topic")
+	if d.redeem("test_topic2", token) {
+		t.Error("Unexpected redemption for another topic")
+	}
+
+	// Expired.
+	token, _ = d.issue("test_topic")
+	d.tokens[token] = deleteToken{topic: "test_topic", expires: time.Now().Add(-time.Second)}
+
+	if d.redeem("test_topic", token) {
+		t.Error("Unexpected redemption of an expired token")
+	}
+}
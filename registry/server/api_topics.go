@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strings"
 
 	"github.com/honeycombio/kafka-kit/kafkazk"
 	pb "github.com/honeycombio/kafka-kit/registry/protos"
@@ -29,17 +30,36 @@ type TopicSet map[string]*pb.Topic
 // non-nil, the specified topic is matched if it exists. Otherwise, all
 // topics found in ZooKeeper are matched. Matched topics are then filtered
 // by all tags specified, if specified, in the *pb.TopicRequest tag field.
+// With the schemas field, the Schema Registry subjects of each topic are
+// included.
 func (s *Server) GetTopics(ctx context.Context, req *pb.TopicRequest) (*pb.TopicResponse, error) {
 	if err := s.ValidateRequest(ctx, req, readRequest); err != nil {
 		return nil, err
 	}
 
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Schemas && s.schemaRegistry == nil {
+		return nil, ErrSchemaRegistryNotConfigured
+	}
+
 	// Get topics.
 	topics, err := s.fetchTopicSet(req)
 	if err != nil {
 		return nil, err
 	}
 
+	if req.Schemas {
+		for _, t := range topics {
+			if t.Schemas, err = s.topicSchemas(t.Name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Populate the response Topics field.
 	resp := &pb.TopicResponse{Topics: topics}
 
@@ -50,19 +70,30 @@ func (s *Server) GetTopics(ctx context.Context, req *pb.TopicRequest) (*pb.Topic
 // non-nil, the specified topic is matched if it exists. Otherwise, all
 // topics found in ZooKeeper are matched. Matched topics are then filtered
 // by all tags specified, if specified, in the *pb.TopicRequest tag field.
+// The sorted names are paginated by the limit and page_token fields.
 func (s *Server) ListTopics(ctx context.Context, req *pb.TopicRequest) (*pb.TopicResponse, error) {
 	if err := s.ValidateRequest(ctx, req, readRequest); err != nil {
 		return nil, err
 	}
 
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get topics.
 	topics, err := s.fetchTopicSet(req)
 	if err != nil {
 		return nil, err
 	}
 
+	names, next, err := pageNames(topics.Names(), req.Limit, req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
 	// Populate the response Names field.
-	resp := &pb.TopicResponse{Names: topics.Names()}
+	resp := &pb.TopicResponse{Names: names, NextPageToken: next}
 
 	return resp, nil
 }
@@ -71,7 +102,12 @@ func (s *Server) ListTopics(ctx context.Context, req *pb.TopicRequest) (*pb.Topi
 // the requested topic. The topic is specified in the TopicRequest.Name
 // field.
 func (s *Server) TopicMappings(ctx context.Context, req *pb.TopicRequest) (*pb.BrokerResponse, error) {
-	if err := s.ValidateRequest(ctx, req, readRequest); err != nil {
+	if err := s.ValidateRequest(ctx, req, metadataRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
 		return nil, err
 	}
 
@@ -111,12 +147,18 @@ func (s *Server) TopicMappings(ctx context.Context, req *pb.TopicRequest) (*pb.B
 }
 
 // TagTopic sets custom tags for the specified topic. Any previously existing
-// tags that were not specified in the request remain unmodified.
+// tags that were not specified in the request remain unmodified. Changes
+// that would introduce topic policy violations are rejected.
 func (s *Server) TagTopic(ctx context.Context, req *pb.TopicRequest) (*pb.TagResponse, error) {
 	if err := s.ValidateRequest(ctx, req, writeRequest); err != nil {
 		return nil, err
 	}
 
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
 	if req.Name == "" {
 		return nil, ErrTopicNameEmpty
 	}
@@ -147,20 +189,47 @@ func (s *Server) TagTopic(ctx context.Context, req *pb.TopicRequest) (*pb.TagRes
 	}
 
 	// Set the tags.
-	err = s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: req.Name}, ts)
+	o := KafkaObject{Type: "topic", ID: req.Name}
+	before := s.storedTags(o)
+
+	after := TagSet{}
+	for k, v := range before {
+		after[k] = v
+	}
+
+	for k, v := range ts {
+		after[k] = v
+	}
+
+	if err := s.checkTopicTagsPolicy(req.Name, before, after); err != nil {
+		return nil, err
+	}
+
+	err = s.Tags.Store.SetTags(o, ts)
 	if err != nil {
 		return nil, err
 	}
 
+	s.publishTagChange(o)
+	s.recordChange(ctx, eventTopicTags, "topics/"+req.Name, fmt.Sprintf("topic %s tags set: %s", req.Name, strings.Join(req.Tag, ", ")),
+		before, s.storedTags(o))
+
 	return &pb.TagResponse{Message: "success"}, nil
 }
 
-// DeleteTopicTag deletes custom tags for the specified topic.
+// DeleteTopicTag deletes custom tags for the specified topic. Deletions
+// that would introduce topic policy violations, e.g. of required tags, are
+// rejected.
 func (s *Server) DeleteTopicTags(ctx context.Context, req *pb.TopicRequest) (*pb.TagResponse, error) {
 	if err := s.ValidateRequest(ctx, req, writeRequest); err != nil {
 		return nil, err
 	}
 
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
 	if req.Name == "" {
 		return nil, ErrTopicNameEmpty
 	}
@@ -185,16 +254,52 @@ func (s *Server) DeleteTopicTags(ctx context.Context, req *pb.TopicRequest) (*pb
 	}
 
 	// Delete the tags.
-	err := s.Tags.Store.DeleteTags(KafkaObject{Type: "topic", ID: req.Name}, req.Tag)
-	if err != nil {
+	o := KafkaObject{Type: "topic", ID: req.Name}
+	before := s.storedTags(o)
+
+	after := TagSet{}
+	for k, v := range before {
+		after[k] = v
+	}
+
+	// Namespace wildcards delete all stored keys of the namespace.
+	keys := expandTagKeys(before, req.Tag)
+
+	for _, k := range keys {
+		delete(after, k)
+	}
+
+	if err := s.checkTopicTagsPolicy(req.Name, before, after); err != nil {
 		return nil, err
 	}
 
+	if len(keys) > 0 {
+		if err := s.Tags.Store.DeleteTags(o, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	s.publishTagChange(o)
+	s.recordChange(ctx, eventTopicTags, "topics/"+req.Name, fmt.Sprintf("topic %s tags deleted: %s", req.Name, strings.Join(req.Tag, ", ")),
+		before, s.storedTags(o))
+
 	return &pb.TagResponse{Message: "success"}, nil
 }
 
-// fetchBrokerSet fetches metadata for all topics.
+// fetchTopicSet fetches metadata for all topics.
 func (s *Server) fetchTopicSet(req *pb.TopicRequest) (TopicSet, error) {
+	expr, err := ParseTagExpr(req.TagQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	var nameRegex *regexp.Regexp
+	if req.NameRegex != "" {
+		if nameRegex, err = regexp.Compile(req.NameRegex); err != nil {
+			return nil, fmt.Errorf("invalid name regex '%s': %s", req.NameRegex, err)
+		}
+	}
+
 	topicRegex := []*regexp.Regexp{}
 
 	// Check if a specific topic is being fetched.
@@ -215,7 +320,11 @@ func (s *Server) fetchTopicSet(req *pb.TopicRequest) (TopicSet, error) {
 
 	// Populate all topics.
 	for _, t := range topics {
-		s, _ := s.ZK.GetTopicState(t)
+		if nameRegex != nil && !nameRegex.MatchString(t) {
+			continue
+		}
+
+		s, _ := s.topicState(t)
 		matched[t] = &pb.Topic{
 			Name:       t,
 			Partitions: uint32(len(s.Partitions)),
@@ -225,7 +334,7 @@ func (s *Server) fetchTopicSet(req *pb.TopicRequest) (TopicSet, error) {
 		}
 	}
 
-	filtered, err := s.Tags.FilterTopics(matched, req.Tag)
+	filtered, err := s.Tags.FilterTopics(matched, req.Tag, expr)
 	if err != nil {
 		return nil, err
 	}
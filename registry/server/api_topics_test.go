@@ -119,6 +119,108 @@ func TestCustomTagTopicFilter(t *testing.T) {
 	}
 }
 
+func TestTagQueryTopicFilter(t *testing.T) {
+	s := testServer()
+
+	s.Tags.Store.SetTags(
+		KafkaObject{Type: "topic", ID: "test_topic"},
+		TagSet{"team": "ingest", "tier": "test"},
+	)
+
+	s.Tags.Store.SetTags(
+		KafkaObject{Type: "topic", ID: "test_topic2"},
+		TagSet{"team": "ingest"},
+	)
+
+	tests := map[int]*pb.TopicRequest{
+		0: &pb.TopicRequest{TagQuery: "team=ingest AND tier!=test"},
+		1: &pb.TopicRequest{TagQuery: "team=in* OR name=test_topic"},
+		2: &pb.TopicRequest{TagQuery: "NOT tier", Tag: []string{"team:billing"}},
+	}
+
+	expected := map[int][]string{
+		0: []string{"test_topic2"},
+		1: []string{"test_topic", "test_topic2"},
+		2: []string{},
+	}
+
+	for i, req := range tests {
+		resp, err := s.ListTopics(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if !stringsEqual(expected[i], resp.Names) {
+			t.Errorf("[test %d] Expected Topic list %s, got %s", i, expected[i], resp.Names)
+		}
+	}
+
+	if _, err := s.ListTopics(context.Background(), &pb.TopicRequest{TagQuery: "team AND"}); err == nil {
+		t.Error("Expected an invalid tag expression error")
+	}
+}
+
+func TestListTopicsPagination(t *testing.T) {
+	s := testServer()
+
+	resp, err := s.ListTopics(context.Background(), &pb.TopicRequest{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !stringsEqual(resp.Names, []string{"test_topic"}) || resp.NextPageToken == "" {
+		t.Fatalf("Expected first page [test_topic] with a next page token, got %v '%s'", resp.Names, resp.NextPageToken)
+	}
+
+	resp, err = s.ListTopics(context.Background(), &pb.TopicRequest{Limit: 1, PageToken: resp.NextPageToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !stringsEqual(resp.Names, []string{"test_topic2"}) || resp.NextPageToken != "" {
+		t.Errorf("Expected last page [test_topic2] without a next page token, got %v '%s'", resp.Names, resp.NextPageToken)
+	}
+
+	_, err = s.ListTopics(context.Background(), &pb.TopicRequest{PageToken: "!"})
+	if err != ErrInvalidPageToken {
+		t.Errorf("Expected error '%s', got '%v'", ErrInvalidPageToken, err)
+	}
+}
+
+func TestNameRegexTopicFilter(t *testing.T) {
+	s := testServer()
+
+	tests := map[int]*pb.TopicRequest{
+		0: &pb.TopicRequest{NameRegex: "2$"},
+		1: &pb.TopicRequest{NameRegex: "^test_"},
+		2: &pb.TopicRequest{NameRegex: "^none"},
+		3: &pb.TopicRequest{NameRegex: "topic", Tag: []string{"partitions:5"}, Limit: 1},
+	}
+
+	expected := map[int][]string{
+		0: []string{"test_topic2"},
+		1: []string{"test_topic", "test_topic2"},
+		2: []string{},
+		3: []string{"test_topic"},
+	}
+
+	for i, req := range tests {
+		resp, err := s.ListTopics(context.Background(), req)
+		if err != nil {
+			t.Errorf("[test %d] Unexpected error: %s", i, err)
+			continue
+		}
+
+		if !stringsEqual(expected[i], resp.Names) {
+			t.Errorf("[test %d] Expected Topic list %s, got %s", i, expected[i], resp.Names)
+		}
+	}
+
+	if _, err := s.ListTopics(context.Background(), &pb.TopicRequest{NameRegex: "["}); err == nil {
+		t.Error("Expected invalid name regex error")
+	}
+}
+
 func TestTagTopic(t *testing.T) {
 	s := testServer()
 
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+var (
+	// ErrBrokerMetricsNotConfigured error.
+	ErrBrokerMetricsNotConfigured = errors.New("broker metrics backend not configured")
+)
+
+// GetUtilization returns the sizes of all topics matching any of the
+// (unanchored) regex in the *pb.UtilizationRequest topic field, or all
+// topics if none are specified, and of their partitions. The storage
+// utilization of each broker is returned along with the number and size of
+// the replicas of those topics it holds. Brokers holding replicas that
+// aren't registered are included with metrics_incomplete set. Sizes are
+// those of the partition size and broker metrics metadata.
+func (s *Server) GetUtilization(ctx context.Context, req *pb.UtilizationRequest) (*pb.UtilizationResponse, error) {
+	if err := s.ValidateRequest(ctx, req, metadataRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	topicRegex := []*regexp.Regexp{}
+	for _, t := range req.Topic {
+		r, err := regexp.Compile(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid topic regex '%s': %s", t, err)
+		}
+		topicRegex = append(topicRegex, r)
+	}
+
+	if len(topicRegex) == 0 {
+		topicRegex = append(topicRegex, tregex)
+	}
+
+	topics, err := s.ZK.GetTopics(topicRegex)
+	if err != nil {
+		return nil, err
+	}
+
+	// Errors are returned for brokers missing metrics;
+	// these are reported as incomplete.
+	bm, errs := s.ZK.GetAllBrokerMeta(true)
+	if bm == nil && len(errs) > 0 {
+		return nil, fmt.Errorf("error fetching broker metadata: %s", errs[0])
+	}
+
+	pmm, err := s.ZK.GetAllPartitionMeta()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching partition metadata: %s", err)
+	}
+
+	age, err := s.ZK.MaxMetaAge()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching metrics age: %s", err)
+	}
+
+	resp := &pb.UtilizationResponse{
+		Topics:     map[string]*pb.TopicUtilization{},
+		Brokers:    map[uint32]*pb.BrokerUtilization{},
+		MetricsAge: int64(age.Seconds()),
+	}
+
+	for id, b := range bm {
+		u := &pb.BrokerUtilization{
+			StorageCapacity:   b.StorageCapacity,
+			StorageFree:       b.StorageFree,
+			MetricsIncomplete: b.MetricsIncomplete,
+		}
+
+		if b.StorageCapacity > 0 {
+			u.StorageUsed = b.StorageCapacity - b.StorageFree
+			u.StorageUtilization = u.StorageUsed / b.StorageCapacity
+		}
+
+		resp.Brokers[uint32(id)] = u
+	}
+
+	for _, t := range topics {
+		ts, err := s.ZK.GetTopicState(t)
+		if err != nil {
+			return nil, err
+		}
+
+		tu := &pb.TopicUtilization{Partitions: map[uint32]float64{}}
+
+		for p, replicas := range ts.Partitions {
+			n, err := strconv.Atoi(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid partition '%s' for topic %s", p, t)
+			}
+
+			var size float64
+			if m, exists := pmm[t][n]; exists {
+				size = m.Size
+			} else {
+				tu.MetricsIncomplete = true
+			}
+
+			tu.Partitions[uint32(n)] = size
+			tu.Size += size
+			tu.ReplicatedSize += size * float64(len(replicas))
+
+			for _, id := range replicas {
+				u, exists := resp.Brokers[uint32(id)]
+				if !exists {
+					u = &pb.BrokerUtilization{MetricsIncomplete: true}
+					resp.Brokers[uint32(id)] = u
+				}
+
+				u.Replicas++
+				u.ReplicaSize += size
+			}
+		}
+
+		resp.Topics[t] = tu
+	}
+
+	return resp, nil
+}
+
+// DescribeBrokerUtilization returns the live network and disk utilization
+// of each broker matching the *pb.BrokerUtilizationRequest id and tag_query
+// fields, or all brokers if unspecified, from the broker metrics backend
+// along with the broker metadata. The network headroom of each broker is its
+// capacity less the greater of its outbound and inbound throughput; brokers
+// are ranked by headroom, most first. Brokers the backend returns no metrics
+// for are included with metrics_missing set and ranked last. Broker metrics
+// are only available for the default cluster.
+func (s *Server) DescribeBrokerUtilization(ctx context.Context, req *pb.BrokerUtilizationRequest) (*pb.BrokerUtilizationResponse, error) {
+	if err := s.ValidateRequest(ctx, req, metadataRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.brokerMetrics == nil {
+		return nil, ErrBrokerMetricsNotConfigured
+	}
+
+	brokers, err := s.fetchBrokerSet(&pb.BrokerRequest{TagQuery: req.TagQuery})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Id) > 0 {
+		requested := BrokerSet{}
+		for _, id := range req.Id {
+			b, exists := brokers[id]
+			if !exists {
+				return nil, fmt.Errorf("broker %d: %s", id, ErrBrokerNotExist)
+			}
+			requested[id] = b
+		}
+		brokers = requested
+	}
+
+	// Backends may return errors along with
+	// the metrics of the remaining brokers.
+	bm, errs := s.brokerMetrics.GetMetrics()
+	if len(bm) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("error fetching broker metrics: %s", errs[0])
+	}
+
+	resp := &pb.BrokerUtilizationResponse{Brokers: map[uint32]*pb.LiveBrokerUtilization{}}
+	for _, err := range errs {
+		resp.Errors = append(resp.Errors, err.Error())
+	}
+
+	for id, b := range brokers {
+		u := &pb.LiveBrokerUtilization{Broker: b}
+		resp.Brokers[id] = u
+
+		m, exists := bm[int(id)]
+		if !exists {
+			u.MetricsMissing = true
+			continue
+		}
+
+		u.NetTx = m.NetTX
+		u.NetRx = m.NetRX
+		u.DiskUtil = m.DiskUtil
+		u.InstanceType = m.InstanceType
+
+		u.NetCapacity = m.NetCapacity
+		if u.NetCapacity == 0 {
+			u.NetCapacity = req.NetCapacity
+		}
+
+		if u.NetCapacity > 0 {
+			u.NetHeadroom = math.Max(u.NetCapacity-math.Max(m.NetTX, m.NetRX), 0)
+		}
+
+		resp.Ranked = append(resp.Ranked, id)
+	}
+
+	sort.Slice(resp.Ranked, func(i, j int) bool {
+		a, b := resp.Brokers[resp.Ranked[i]], resp.Brokers[resp.Ranked[j]]
+		if a.NetHeadroom != b.NetHeadroom {
+			return a.NetHeadroom > b.NetHeadroom
+		}
+		return resp.Ranked[i] < resp.Ranked[j]
+	})
+
+	var missing []uint32
+	for id, u := range resp.Brokers {
+		if u.MetricsMissing {
+			missing = append(missing, id)
+		}
+	}
+
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+
+	resp.Ranked = append(resp.Ranked, missing...)
+
+	return resp, nil
+}
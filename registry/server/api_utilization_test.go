@@ -0,0 +1,199 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkametrics"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+// brokerMetricsMock returns fixed broker metrics.
+type brokerMetricsMock struct {
+	kafkametrics.Mock
+	metrics kafkametrics.BrokerMetrics
+	errs    []error
+}
+
+func (m *brokerMetricsMock) GetMetrics() (kafkametrics.BrokerMetrics, []error) {
+	return m.metrics, m.errs
+}
+
+func TestGetUtilizationTopics(t *testing.T) {
+	s := testServer()
+
+	resp, err := s.GetUtilization(context.Background(), &pb.UtilizationRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Topics) != 2 {
+		t.Fatalf("Expected 2 topics, got %d", len(resp.Topics))
+	}
+
+	tu := resp.Topics["test_topic"]
+
+	if tu.Size != 9200 {
+		t.Errorf("Expected size 9200, got %f", tu.Size)
+	}
+
+	// Each partition has 2 replicas.
+	if tu.ReplicatedSize != 18400 {
+		t.Errorf("Expected replicated size 18400, got %f", tu.ReplicatedSize)
+	}
+
+	expected := map[uint32]float64{0: 1000, 1: 1500, 2: 2000, 3: 2500, 4: 2200}
+
+	for p, size := range expected {
+		if tu.Partitions[p] != size {
+			t.Errorf("Expected partition %d size %f, got %f", p, size, tu.Partitions[p])
+		}
+	}
+
+	if tu.MetricsIncomplete {
+		t.Error("Expected complete metrics for test_topic")
+	}
+
+	// test_topic2 has no partition metadata.
+	if tu := resp.Topics["test_topic2"]; !tu.MetricsIncomplete || tu.Size != 0 {
+		t.Errorf("Expected incomplete metrics and size 0 for test_topic2, got %v", tu)
+	}
+
+	// Filtered by regex.
+	resp, err = s.GetUtilization(context.Background(), &pb.UtilizationRequest{Topic: []string{"2$"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, exists := resp.Topics["test_topic2"]; len(resp.Topics) != 1 || !exists {
+		t.Errorf("Expected only test_topic2, got %v", resp.Topics)
+	}
+
+	if _, err := s.GetUtilization(context.Background(), &pb.UtilizationRequest{Topic: []string{"["}}); err == nil {
+		t.Error("Expected invalid topic regex error")
+	}
+}
+
+func TestGetUtilizationBrokers(t *testing.T) {
+	s := testServer()
+
+	resp, err := s.GetUtilization(context.Background(), &pb.UtilizationRequest{Topic: []string{"^test_topic$"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[uint32]*pb.BrokerUtilization{
+		1001: &pb.BrokerUtilization{StorageCapacity: 20000, StorageFree: 2000, StorageUsed: 18000, StorageUtilization: 0.9, Replicas: 1, ReplicaSize: 1000},
+		1005: &pb.BrokerUtilization{StorageCapacity: 20000, StorageFree: 10000, StorageUsed: 10000, StorageUtilization: 0.5, Replicas: 1, ReplicaSize: 2000},
+		1003: &pb.BrokerUtilization{StorageCapacity: 20000, StorageFree: 6000, StorageUsed: 14000, StorageUtilization: 0.7, Replicas: 1, ReplicaSize: 1500},
+		// Replicas on unregistered brokers.
+		1000: &pb.BrokerUtilization{Replicas: 1, ReplicaSize: 1000, MetricsIncomplete: true},
+		1009: &pb.BrokerUtilization{Replicas: 1, ReplicaSize: 2200, MetricsIncomplete: true},
+	}
+
+	for id, expected := range tests {
+		u, exists := resp.Brokers[id]
+		if !exists {
+			t.Errorf("Expected broker %d", id)
+			continue
+		}
+
+		if u.StorageCapacity != expected.StorageCapacity || u.StorageFree != expected.StorageFree ||
+			u.StorageUsed != expected.StorageUsed || u.StorageUtilization != expected.StorageUtilization {
+			t.Errorf("[broker %d] Expected storage %v, got %v", id, expected, u)
+		}
+
+		if u.Replicas != expected.Replicas || u.ReplicaSize != expected.ReplicaSize {
+			t.Errorf("[broker %d] Expected %d replicas of size %f, got %d of size %f",
+				id, expected.Replicas, expected.ReplicaSize, u.Replicas, u.ReplicaSize)
+		}
+
+		if u.MetricsIncomplete != expected.MetricsIncomplete {
+			t.Errorf("[broker %d] Expected metrics incomplete %v", id, expected.MetricsIncomplete)
+		}
+	}
+
+	// Brokers 1000-1009 hold replicas; only 1001-1005 are registered.
+	if len(resp.Brokers) != 10 {
+		t.Errorf("Expected 10 brokers, got %d", len(resp.Brokers))
+	}
+}
+
+func TestDescribeBrokerUtilization(t *testing.T) {
+	s := testServer()
+
+	if _, err := s.DescribeBrokerUtilization(context.Background(), &pb.BrokerUtilizationRequest{}); err != ErrBrokerMetricsNotConfigured {
+		t.Errorf("Expected error '%s', got '%v'", ErrBrokerMetricsNotConfigured, err)
+	}
+
+	s.brokerMetrics = &brokerMetricsMock{
+		metrics: kafkametrics.BrokerMetrics{
+			1001: &kafkametrics.Broker{ID: 1001, InstanceType: "mock", NetTX: 80, NetRX: 40, DiskUtil: 60},
+			1002: &kafkametrics.Broker{ID: 1002, InstanceType: "mock", NetTX: 20, NetRX: 50, DiskUtil: 30, NetCapacity: 200},
+			1003: &kafkametrics.Broker{ID: 1003, InstanceType: "mock", NetTX: 10, NetRX: 10, DiskUtil: 10},
+			1005: &kafkametrics.Broker{ID: 1005, InstanceType: "mock", NetTX: 120, NetRX: 90, DiskUtil: 95},
+		},
+		errs: []error{errors.New("no metrics for broker 1004")},
+	}
+
+	resp, err := s.DescribeBrokerUtilization(context.Background(), &pb.BrokerUtilizationRequest{NetCapacity: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1002 reports its own capacity; 1005 exceeds the assumed capacity.
+	expected := map[uint32]float64{1001: 20, 1002: 150, 1003: 90, 1004: 0, 1005: 0}
+
+	for id, headroom := range expected {
+		u, exists := resp.Brokers[id]
+		if !exists {
+			t.Errorf("Expected broker %d", id)
+			continue
+		}
+
+		if u.NetHeadroom != headroom {
+			t.Errorf("[broker %d] Expected headroom %f, got %f", id, headroom, u.NetHeadroom)
+		}
+
+		if u.Broker.Id != id {
+			t.Errorf("[broker %d] Expected broker metadata, got %v", id, u.Broker)
+		}
+	}
+
+	if u := resp.Brokers[1004]; !u.MetricsMissing || u.NetCapacity != 0 {
+		t.Errorf("Expected missing metrics for broker 1004, got %v", u)
+	}
+
+	if u := resp.Brokers[1002]; u.NetCapacity != 200 || u.NetTx != 20 || u.NetRx != 50 || u.DiskUtil != 30 {
+		t.Errorf("Unexpected utilization for broker 1002: %v", u)
+	}
+
+	if !intsEqual(resp.Ranked, []uint32{1002, 1003, 1001, 1005, 1004}) {
+		t.Errorf("Expected ranking [1002 1003 1001 1005 1004], got %v", resp.Ranked)
+	}
+
+	if len(resp.Errors) != 1 {
+		t.Errorf("Expected 1 metrics error, got %v", resp.Errors)
+	}
+
+	// Filtered by ID and tag query.
+	if _, err := s.DescribeBrokerUtilization(context.Background(), &pb.BrokerUtilizationRequest{Id: []uint32{1001, 1002}, TagQuery: "rack=a"}); err == nil {
+		t.Error("Expected error for broker 1002 not matching the tag query")
+	}
+
+	resp, err = s.DescribeBrokerUtilization(context.Background(), &pb.BrokerUtilizationRequest{TagQuery: "rack=a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !intsEqual(resp.Ranked, []uint32{1001, 1004}) {
+		t.Errorf("Expected brokers [1001 1004], got %v", resp.Ranked)
+	}
+
+	// All metrics missing.
+	s.brokerMetrics = &brokerMetricsMock{errs: []error{errors.New("backend unavailable")}}
+	if _, err := s.DescribeBrokerUtilization(context.Background(), &pb.BrokerUtilizationRequest{}); err == nil {
+		t.Error("Expected metrics backend error")
+	}
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+	pbv2 "github.com/honeycombio/kafka-kit/registry/protos/v2"
+)
+
+// V2 implements the v2 registry service. Requests are handled by the
+// underlying *Server, with the same authentication, throttling and cluster
+// federation as the v1 service.
+type V2 struct {
+	s *Server
+}
+
+// V2 returns the v2 registry service of the Server.
+func (s *Server) V2() *V2 {
+	return &V2{s: s}
+}
+
+// GetTopics gets topics with partition detail. Topics are matched as with
+// the v1 GetTopics. Each topic includes its partitions with their replicas,
+// leader and ISR, along with the size of each partition from the partition
+// metrics if the *pbv2.TopicRequest Sizes field is set.
+func (v *V2) GetTopics(ctx context.Context, req *pbv2.TopicRequest) (*pbv2.TopicResponse, error) {
+	s := v.s
+
+	if err := s.ValidateRequest(ctx, req, readRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := s.fetchTopicSet(&pb.TopicRequest{
+		Tag:       req.Tag,
+		Name:      req.Name,
+		TagQuery:  req.TagQuery,
+		NameRegex: req.NameRegex,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pmm kafkazk.PartitionMetaMap
+	if req.Sizes {
+		if pmm, err = s.ZK.GetAllPartitionMeta(); err != nil {
+			return nil, fmt.Errorf("error fetching partition metadata: %s", err)
+		}
+	}
+
+	resp := &pbv2.TopicResponse{Topics: map[string]*pbv2.Topic{}}
+
+	for name, t := range topics {
+		if resp.Topics[name], err = s.describeTopic(t, pmm); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// describeTopic takes a *pb.Topic and returns a *pbv2.Topic with the
+// partition state of the topic. Partition sizes are populated from the
+// PartitionMetaMap, if non-nil.
+func (s *Server) describeTopic(t *pb.Topic, pmm kafkazk.PartitionMetaMap) (*pbv2.Topic, error) {
+	state, err := s.topicState(t.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	isr, err := s.ZK.GetTopicStateISR(t.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	topic := &pbv2.Topic{
+		Name:        t.Name,
+		Tags:        t.Tags,
+		Owner:       t.Owner,
+		Team:        t.Team,
+		Replication: t.Replication,
+	}
+
+	for p, replicas := range state.Partitions {
+		id, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid partition %s for topic %s", p, t.Name)
+		}
+
+		partn := &pbv2.Partition{
+			Id:       uint32(id),
+			Replicas: uint32s(replicas),
+			Leader:   -1,
+		}
+
+		// Partitions without a state have
+		// no leader or in-sync replicas.
+		if ps, exists := isr[p]; exists {
+			partn.Leader = int32(ps.Leader)
+			partn.Isr = uint32s(ps.ISR)
+		}
+
+		if len(partn.Isr) < len(partn.Replicas) {
+			partn.UnderReplicated = true
+			topic.UnderReplicated++
+		}
+
+		if meta, exists := pmm[t.Name][id]; exists {
+			partn.Size = meta.Size
+		}
+
+		topic.Partitions = append(topic.Partitions, partn)
+	}
+
+	sort.Slice(topic.Partitions, func(i, j int) bool {
+		return topic.Partitions[i].Id < topic.Partitions[j].Id
+	})
+
+	return topic, nil
+}
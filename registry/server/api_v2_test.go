@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	pbv2 "github.com/honeycombio/kafka-kit/registry/protos/v2"
+)
+
+func TestV2GetTopics(t *testing.T) {
+	s := testServer()
+
+	resp, err := s.V2().GetTopics(context.Background(), &pbv2.TopicRequest{Name: "test_topic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topic := resp.Topics["test_topic"]
+	if len(resp.Topics) != 1 || topic == nil {
+		t.Fatalf("Unexpected topics %v", resp.Topics)
+	}
+
+	if len(topic.Partitions) != 5 || topic.Replication != 2 {
+		t.Fatalf("Expected 5 partitions with replication 2, got %v", topic)
+	}
+
+	for i, p := range topic.Partitions {
+		if p.Id != uint32(i) {
+			t.Errorf("Expected partition %d, got %d", i, p.Id)
+		}
+
+		if p.Size != 0 {
+			t.Errorf("Expected no size, got %f", p.Size)
+		}
+	}
+
+	p := topic.Partitions[0]
+	if !intsEqual(p.Replicas, []uint32{1000, 1001}) || p.Leader != 1000 || !intsEqual(p.Isr, []uint32{1000, 1002}) {
+		t.Errorf("Unexpected partition state %v", p)
+	}
+
+	// With sizes.
+	resp, err = s.V2().GetTopics(context.Background(), &pbv2.TopicRequest{Name: "test_topic", Sizes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []float64{1000, 1500, 2000, 2500, 2200}
+	for i, p := range resp.Topics["test_topic"].Partitions {
+		if p.Size != expected[i] {
+			t.Errorf("Expected partition %d size %f, got %f", i, expected[i], p.Size)
+		}
+	}
+}
+
+func TestV2GetTopicsFiltered(t *testing.T) {
+	s := testServer()
+
+	tests := map[int]*pbv2.TopicRequest{
+		0: {},
+		1: {NameRegex: "2$"},
+		2: {Tag: []string{"partitions:5"}},
+	}
+
+	expected := map[int][]string{
+		0: {"test_topic", "test_topic2"},
+		1: {"test_topic2"},
+		2: {"test_topic", "test_topic2"},
+	}
+
+	for i, req := range tests {
+		resp, err := s.V2().GetTopics(context.Background(), req)
+		if err != nil {
+			t.Fatalf("[test %d] Unexpected error: %s", i, err)
+		}
+
+		var names []string
+		for name := range resp.Topics {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		if !stringsEqual(expected[i], names) {
+			t.Errorf("[test %d] Expected Topic list %s, got %s", i, expected[i], names)
+		}
+	}
+}
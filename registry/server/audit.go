@@ -0,0 +1,248 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+var (
+	// ErrAuditLogNotConfigured error.
+	ErrAuditLogNotConfigured = errors.New("audit log file not configured")
+)
+
+// auditQueryLimit is the default maximum
+// number of entries returned per query.
+const auditQueryLimit = 100
+
+// AuditEntry is a record of a change made by a mutating call.
+type AuditEntry struct {
+	Timestamp int64  `json:"timestamp"`
+	Requestor string `json:"requestor"`
+	Identity  string `json:"identity"`
+	Method    string `json:"method"`
+	Resource  string `json:"resource"`
+	Change    string `json:"change"`
+	// The JSON encoded state of the resource before
+	// and after the change; empty if it didn't exist.
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+	// The cluster of the resource; empty
+	// if the registry isn't federated.
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// AuditQuery specifies the AuditEntry fields
+// matched in queries; empty fields match any.
+type AuditQuery struct {
+	Cluster  string
+	Resource string
+	Identity string
+	Method   string
+	// Unix timestamp (seconds) range,
+	// inclusive; unbounded if 0.
+	Since int64
+	Until int64
+	Limit int
+}
+
+// Matches returns whether the AuditEntry matches the AuditQuery.
+func (q AuditQuery) Matches(e AuditEntry) bool {
+	switch {
+	case q.Cluster != "" && e.Cluster != q.Cluster:
+		return false
+	case q.Resource != "" && e.Resource != q.Resource:
+		return false
+	case q.Identity != "" && e.Identity != q.Identity:
+		return false
+	case q.Method != "" && e.Method != q.Method:
+		return false
+	case q.Since != 0 && e.Timestamp < q.Since:
+		return false
+	case q.Until != 0 && e.Timestamp > q.Until:
+		return false
+	}
+
+	return true
+}
+
+// AuditStore durably stores AuditEntries.
+type AuditStore interface {
+	Append(AuditEntry) error
+	// Query returns the most recent entries
+	// matching the AuditQuery, most recent first.
+	Query(AuditQuery) ([]AuditEntry, error)
+}
+
+// FileAuditStore implements an AuditStore as an append-only file of
+// JSON encoded AuditEntries, one per line. Each append is synced to
+// disk before returning.
+type FileAuditStore struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+}
+
+// NewFileAuditStore opens or creates the audit log file at path.
+func NewFileAuditStore(path string) (*FileAuditStore, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileAuditStore{path: path, f: f}, nil
+}
+
+// Append writes the AuditEntry to the log.
+func (a *FileAuditStore) Append(e AuditEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, err := a.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	return a.f.Sync()
+}
+
+// Query scans the log for entries matching the AuditQuery.
+func (a *FileAuditStore) Query(q AuditQuery) ([]AuditEntry, error) {
+	if q.Limit <= 0 {
+		q.Limit = auditQueryLimit
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	f, err := os.Open(a.path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	// The log is in order; the last limit
+	// matches are kept as it's scanned.
+	var matched []AuditEntry
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for n := 1; scanner.Scan(); n++ {
+		e := AuditEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("error parsing audit log line %d: %s", n, err)
+		}
+
+		if !q.Matches(e) {
+			continue
+		}
+
+		matched = append(matched, e)
+		if len(matched) > q.Limit {
+			matched = matched[1:]
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Most recent first.
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+
+	return matched, nil
+}
+
+// auditState returns the JSON encoding of the resource state v for an
+// AuditEntry. Nil values, e.g. of resources that don't exist, are empty.
+func auditState(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("error encoding state: %s", err)
+	}
+
+	if string(b) == "null" {
+		return ""
+	}
+
+	return string(b)
+}
+
+// storedTags returns the stored TagSet of the KafkaObject
+// for audit logging; nil if the object has no tags.
+func (s *Server) storedTags(o KafkaObject) TagSet {
+	tags, err := s.Tags.Store.GetTags(o)
+	if err != nil {
+		return nil
+	}
+
+	// Stores may return their own copy.
+	stored := TagSet{}
+	for k, v := range tags {
+		stored[k] = v
+	}
+
+	return stored
+}
+
+// GetAuditLog returns the audit log entries matching the
+// *pb.AuditLogRequest, most recent first.
+func (s *Server) GetAuditLog(ctx context.Context, req *pb.AuditLogRequest) (*pb.AuditLogResponse, error) {
+	if err := s.ValidateRequest(ctx, req, readRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.audit == nil {
+		return nil, ErrAuditLogNotConfigured
+	}
+
+	entries, err := s.audit.Query(AuditQuery{
+		Cluster:  s.clusterName,
+		Resource: req.Resource,
+		Identity: req.Identity,
+		Method:   req.Method,
+		Since:    req.Since,
+		Until:    req.Until,
+		Limit:    int(req.Limit),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.AuditLogResponse{}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, &pb.AuditEntry{
+			Timestamp: e.Timestamp,
+			Requestor: e.Requestor,
+			Identity:  e.Identity,
+			Method:    e.Method,
+			Resource:  e.Resource,
+			Change:    e.Change,
+			Before:    e.Before,
+			After:     e.After,
+			Cluster:   e.Cluster,
+		})
+	}
+
+	return resp, nil
+}
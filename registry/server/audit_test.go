@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+func testAuditStore(t *testing.T) (*FileAuditStore, func()) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewFileAuditStore(filepath.Join(dir, "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a, func() { os.RemoveAll(dir) }
+}
+
+func TestFileAuditStore(t *testing.T) {
+	a, cleanup := testAuditStore(t)
+	defer cleanup()
+
+	entries := []AuditEntry{
+		{Timestamp: 10, Identity: "alice", Method: "/registry.Registry/TagTopic", Resource: "topics/a"},
+		{Timestamp: 20, Identity: "bob", Method: "/registry.Registry/TagTopic", Resource: "topics/b"},
+		{Timestamp: 30, Identity: "alice", Method: "/registry.Registry/DeleteTopic", Resource: "topics/a"},
+		{Timestamp: 40, Identity: "alice", Method: "/registry.Registry/TagBroker", Resource: "brokers/1001"},
+	}
+
+	for _, e := range entries {
+		if err := a.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[int]AuditQuery{
+		0: AuditQuery{},
+		1: AuditQuery{Resource: "topics/a"},
+		2: AuditQuery{Identity: "alice", Since: 20},
+		3: AuditQuery{Method: "/registry.Registry/TagTopic", Until: 15},
+		4: AuditQuery{Limit: 2},
+		5: AuditQuery{Identity: "carol"},
+	}
+
+	// Expected timestamps.
+	expected := map[int][]int64{
+		0: []int64{40, 30, 20, 10},
+		1: []int64{30, 10},
+		2: []int64{40, 30},
+		3: []int64{10},
+		4: []int64{40, 30},
+		5: nil,
+	}
+
+	for i, q := range tests {
+		results, err := a.Query(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []int64
+		for _, e := range results {
+			got = append(got, e.Timestamp)
+		}
+
+		if len(got) != len(expected[i]) {
+			t.Errorf("[test %d] Expected entries %v, got %v", i, expected[i], got)
+			continue
+		}
+
+		for j := range got {
+			if got[j] != expected[i][j] {
+				t.Errorf("[test %d] Expected entries %v, got %v", i, expected[i], got)
+				break
+			}
+		}
+	}
+
+	// Entries persist across reopens.
+	reopened, err := NewFileAuditStore(a.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if results, _ := reopened.Query(AuditQuery{}); len(results) != len(entries) {
+		t.Errorf("Expected %d entries, got %d", len(entries), len(results))
+	}
+}
+
+func TestGetAuditLog(t *testing.T) {
+	s := testServer()
+
+	if _, err := s.GetAuditLog(context.Background(), &pb.AuditLogRequest{}); err != ErrAuditLogNotConfigured {
+		t.Errorf("Expected error '%s', got '%v'", ErrAuditLogNotConfigured, err)
+	}
+
+	a, cleanup := testAuditStore(t)
+	defer cleanup()
+
+	s.audit = a
+
+	topic := &pb.TopicRequest{Name: "test_topic", Tag: []string{"team:data"}}
+	if _, err := s.TagTopic(context.Background(), topic); err != nil {
+		t.Fatal(err)
+	}
+
+	topic.Tag = []string{"team"}
+	if _, err := s.DeleteTopicTags(context.Background(), topic); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only mutating calls are
+	// audit logged.
+	s.GetTopics(context.Background(), &pb.TopicRequest{})
+
+	resp, err := s.GetAuditLog(context.Background(), &pb.AuditLogRequest{Resource: "topics/test_topic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(resp.Entries))
+	}
+
+	// Most recent first.
+	deleted, set := resp.Entries[0], resp.Entries[1]
+
+	if set.Change != "topic test_topic tags set: team:data" || set.Before != "" || set.After != `{"team":"data"}` {
+		t.Errorf("Unexpected entry %v", set)
+	}
+
+	if deleted.Change != "topic test_topic tags deleted: team" || deleted.Before != `{"team":"data"}` || deleted.After != "{}" {
+		t.Errorf("Unexpected entry %v", deleted)
+	}
+
+	if set.Identity != "none" {
+		t.Errorf("Expected identity none, got %s", set.Identity)
+	}
+}
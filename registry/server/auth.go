@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrUnauthenticated error.
+	ErrUnauthenticated = status.Error(codes.Unauthenticated, "request requires authentication")
+	// ErrInvalidToken error.
+	ErrInvalidToken = status.Error(codes.Unauthenticated, "invalid bearer token")
+)
+
+// authConfig holds the request authentication settings.
+type authConfig struct {
+	// Bearer tokens to identities.
+	tokens map[string]string
+	// Whether client certificates are verified.
+	clientCerts bool
+	// Whether read requests require authentication.
+	reads bool
+}
+
+// enabled returns whether any
+// authentication method is configured.
+func (a authConfig) enabled() bool {
+	return len(a.tokens) > 0 || a.clientCerts
+}
+
+// required returns whether
+// requests of kind require
+// authentication.
+func (a authConfig) required(kind int) bool {
+	if !a.enabled() {
+		return false
+	}
+
+	return kind == writeRequest || a.reads
+}
+
+// readAuthTokens reads a JSON object of identities
+// to bearer tokens from path, returning a map of
+// tokens to identities.
+func readAuthTokens(path string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	identities := map[string]string{}
+	if err := json.Unmarshal(data, &identities); err != nil {
+		return nil, fmt.Errorf("error parsing auth tokens: %s", err)
+	}
+
+	tokens := map[string]string{}
+	for id, token := range identities {
+		if id == "" || token == "" {
+			return nil, errors.New("auth tokens must have a non-empty identity and token")
+		}
+
+		if _, exists := tokens[token]; exists {
+			return nil, fmt.Errorf("auth token for %s is not unique", id)
+		}
+
+		tokens[token] = id
+	}
+
+	return tokens, nil
+}
+
+// serverTLSConfig returns a *tls.Config with the certificate and key pair.
+// If the clientCA path is non-empty, client certificates signed by the CA
+// are verified if provided; clients may alternatively authenticate with
+// bearer tokens.
+func serverTLSConfig(cert, key, clientCA string) (*tls.Config, error) {
+	pair, err := tls.LoadX509KeyPair(cert, key)
+	if err != nil {
+		return nil, fmt.Errorf("error loading TLS certificate: %s", err)
+	}
+
+	c := &tls.Config{Certificates: []tls.Certificate{pair}}
+
+	if clientCA != "" {
+		data, err := ioutil.ReadFile(clientCA)
+		if err != nil {
+			return nil, fmt.Errorf("error loading TLS client CA: %s", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.New("no certificates found in TLS client CA")
+		}
+
+		c.ClientCAs = pool
+		c.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return c, nil
+}
+
+// identity returns the authenticated identity of the request: the common
+// name of a verified client certificate or, otherwise, the identity of the
+// bearer token in the authorization metadata. An empty identity is returned
+// for unauthenticated requests, and an ErrInvalidToken for unknown tokens.
+// Requests forwarded by another registry have the identity authenticated
+// by that registry.
+func (s *Server) identity(ctx context.Context) (string, error) {
+	if s.forwardedByPeer(ctx) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if id := md[forwardedIdentityKey]; len(id) > 0 {
+			return id[0], nil
+		}
+
+		return "", nil
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			if chains := info.State.VerifiedChains; len(chains) > 0 && len(chains[0]) > 0 {
+				return chains[0][0].Subject.CommonName, nil
+			}
+		}
+	}
+
+	if len(s.auth.tokens) == 0 {
+		return "", nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md["authorization"]) == 0 {
+		return "", nil
+	}
+
+	token := strings.TrimPrefix(md["authorization"][0], "Bearer ")
+
+	// Compare all tokens in constant time.
+	var id string
+	for t, i := range s.auth.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			id = i
+		}
+	}
+
+	if id == "" {
+		return "", ErrInvalidToken
+	}
+
+	return id, nil
+}
+
+// identityString returns the request identity
+// for logging; none if unauthenticated.
+func (s *Server) identityString(ctx context.Context) string {
+	if id, _ := s.identity(ctx); id != "" {
+		return id
+	}
+
+	return "none"
+}
+
+// authenticate returns an error if the request of kind
+// requires authentication and isn't authenticated.
+func (s *Server) authenticate(ctx context.Context, kind int) error {
+	id, err := s.identity(ctx)
+	switch {
+	case err != nil:
+		return err
+	case id == "" && s.auth.required(kind):
+		return ErrUnauthenticated
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestReadAuthTokens(t *testing.T) {
+	f, err := ioutil.TempFile("", "registry-auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+
+	tests := map[string]bool{
+		`{"deploy-bot": "t1", "sre-oncall": "t2"}`: true,
+		`{"deploy-bot": "t1", "sre-oncall": "t1"}`: false,
+		`{"deploy-bot": ""}`:                       false,
+		`["t1"]`:                                   false,
+	}
+
+	for data, valid := range tests {
+		if err := ioutil.WriteFile(f.Name(), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		tokens, err := readAuthTokens(f.Name())
+		if valid != (err == nil) {
+			t.Errorf("Unexpected error '%v' for %s", err, data)
+		}
+
+		if valid && (tokens["t1"] != "deploy-bot" || tokens["t2"] != "sre-oncall") {
+			t.Errorf("Unexpected tokens %v", tokens)
+		}
+	}
+}
+
+func bearerContext(token string) context.Context {
+	md := metadata.Pairs("authorization", "Bearer "+token)
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestIdentity(t *testing.T) {
+	s := testServer()
+	s.auth.tokens = map[string]string{"t1": "deploy-bot"}
+
+	// Client certificate.
+	info := credentials.TLSInfo{State: tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{
+			{&x509.Certificate{Subject: pkix.Name{CommonName: "sre-oncall"}}},
+		},
+	}}
+
+	certCtx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: info})
+
+	tests := map[context.Context]string{
+		certCtx:              "sre-oncall",
+		bearerContext("t1"):  "deploy-bot",
+		context.Background(): "",
+	}
+
+	for ctx, expected := range tests {
+		id, err := s.identity(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id != expected {
+			t.Errorf("Expected identity '%s', got '%s'", expected, id)
+		}
+	}
+
+	if _, err := s.identity(bearerContext("t2")); err != ErrInvalidToken {
+		t.Errorf("Expected error '%s', got '%v'", ErrInvalidToken, err)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	s := testServer()
+
+	// Nothing is authenticated if
+	// no method is configured.
+	if err := s.authenticate(context.Background(), writeRequest); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	s.auth.tokens = map[string]string{"t1": "deploy-bot"}
+
+	if err := s.authenticate(context.Background(), writeRequest); err != ErrUnauthenticated {
+		t.Errorf("Expected error '%s', got '%v'", ErrUnauthenticated, err)
+	}
+
+	if err := s.authenticate(context.Background(), readRequest); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if err := s.authenticate(bearerContext("t1"), writeRequest); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	s.auth.reads = true
+
+	if err := s.authenticate(context.Background(), readRequest); err != ErrUnauthenticated {
+		t.Errorf("Expected error '%s', got '%v'", ErrUnauthenticated, err)
+	}
+
+	// Requests are authenticated
+	// before being throttled.
+	if err := s.ValidateRequest(context.Background(), nil, writeRequest); err != ErrUnauthenticated {
+		t.Errorf("Expected error '%s', got '%v'", ErrUnauthenticated, err)
+	}
+}
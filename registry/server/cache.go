@@ -0,0 +1,174 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// Metadata cache names, as reported in metrics.
+const (
+	topicStateCache = "topic_state"
+	brokerMetaCache = "broker_meta"
+)
+
+// metadataCache caches the topic states and broker metadata read from
+// ZooKeeper by topic and broker lookups, so that frequently polled lookups
+// (e.g. from dashboards) don't each read every topic and broker znode.
+// Entries are cached for the TTL and, if watch is set, until a ZooKeeper
+// watch reports a change of the topic or broker membership. Topics changed
+// through the registry are invalidated. The cache is disabled if the TTL is
+// 0 and watch isn't set.
+type metadataCache struct {
+	ttl   time.Duration
+	watch bool
+
+	sync.Mutex
+	topics         map[string]cachedTopicState
+	brokers        kafkazk.BrokerMetaMap
+	brokersExpires time.Time
+	brokersChanged <-chan struct{}
+}
+
+type cachedTopicState struct {
+	state   *kafkazk.TopicState
+	expires time.Time
+	// Closed when the topic changes;
+	// nil if watches aren't enabled.
+	changed <-chan struct{}
+}
+
+func newMetadataCache(ttl time.Duration, watch bool) *metadataCache {
+	return &metadataCache{
+		ttl:    ttl,
+		watch:  watch,
+		topics: map[string]cachedTopicState{},
+	}
+}
+
+// enabled returns whether the cache is enabled.
+func (c *metadataCache) enabled() bool {
+	return c.ttl > 0 || c.watch
+}
+
+// fresh returns whether an entry that expires at
+// the time and is watched by the changed channel
+// is still valid.
+func (c *metadataCache) fresh(expires time.Time, changed <-chan struct{}) bool {
+	if c.ttl > 0 && !time.Now().Before(expires) {
+		return false
+	}
+
+	return changed == nil || watching(changed)
+}
+
+// watching returns whether the changed
+// channel of an entry hasn't fired.
+func watching(changed <-chan struct{}) bool {
+	if changed == nil {
+		return false
+	}
+
+	select {
+	case <-changed:
+		return false
+	default:
+		return true
+	}
+}
+
+// invalidate removes the topics from the cache.
+func (c *metadataCache) invalidate(topics ...string) {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, t := range topics {
+		delete(c.topics, t)
+	}
+}
+
+// topicState returns the *kafkazk.TopicState of the
+// topic, from the metadata cache if enabled.
+func (s *Server) topicState(topic string) (*kafkazk.TopicState, error) {
+	c := s.metadataCache
+	if !c.enabled() {
+		return s.ZK.GetTopicState(topic)
+	}
+
+	c.Lock()
+	cached, exists := c.topics[topic]
+	c.Unlock()
+
+	if exists && c.fresh(cached.expires, cached.changed) {
+		s.metrics.observeCache(topicStateCache, true)
+		return cached.state, nil
+	}
+
+	s.metrics.observeCache(topicStateCache, false)
+
+	// Entries that expired while their watch is still
+	// set are refreshed without setting another watch.
+	var state *kafkazk.TopicState
+	var changed <-chan struct{}
+	var err error
+
+	switch {
+	case c.watch && !watching(cached.changed):
+		state, changed, err = s.ZK.GetTopicStateW(topic)
+	default:
+		state, err = s.ZK.GetTopicState(topic)
+		changed = cached.changed
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.Lock()
+	c.topics[topic] = cachedTopicState{state: state, expires: time.Now().Add(c.ttl), changed: changed}
+	c.Unlock()
+
+	return state, nil
+}
+
+// brokerMeta returns the kafkazk.BrokerMetaMap of all
+// brokers (without metrics), from the metadata cache
+// if enabled. The BrokerMetaMap must not be modified.
+func (s *Server) brokerMeta() (kafkazk.BrokerMetaMap, []error) {
+	c := s.metadataCache
+	if !c.enabled() {
+		return s.ZK.GetAllBrokerMeta(false)
+	}
+
+	c.Lock()
+	brokers, expires, changed := c.brokers, c.brokersExpires, c.brokersChanged
+	c.Unlock()
+
+	if brokers != nil && c.fresh(expires, changed) {
+		s.metrics.observeCache(brokerMetaCache, true)
+		return brokers, nil
+	}
+
+	s.metrics.observeCache(brokerMetaCache, false)
+
+	// The watch is set before the brokers are
+	// read, so that no change is missed.
+	if c.watch && !watching(changed) {
+		var err error
+		if changed, err = s.ZK.WatchBrokers(); err != nil {
+			return nil, []error{err}
+		}
+	}
+
+	brokers, errs := s.ZK.GetAllBrokerMeta(false)
+	if errs != nil {
+		return nil, errs
+	}
+
+	c.Lock()
+	c.brokers, c.brokersExpires, c.brokersChanged = brokers, time.Now().Add(c.ttl), changed
+	c.Unlock()
+
+	return brokers, nil
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+// countingZK counts topic state and broker metadata reads.
+type countingZK struct {
+	kafkazk.Mock
+	topicReads  int
+	brokerReads int
+}
+
+func (zk *countingZK) GetTopicState(t string) (*kafkazk.TopicState, error) {
+	zk.topicReads++
+	return zk.Mock.GetTopicState(t)
+}
+
+func (zk *countingZK) GetAllBrokerMeta(withMetrics bool) (kafkazk.BrokerMetaMap, []error) {
+	zk.brokerReads++
+	return zk.Mock.GetAllBrokerMeta(withMetrics)
+}
+
+// watchingZK is a countingZK whose watches
+// are closed by the test.
+type watchingZK struct {
+	countingZK
+	topicChanged  chan struct{}
+	brokerChanged chan struct{}
+	topicWatches  int
+	brokerWatches int
+}
+
+func (zk *watchingZK) GetTopicStateW(t string) (*kafkazk.TopicState, <-chan struct{}, error) {
+	zk.topicWatches++
+	ts, err := zk.GetTopicState(t)
+	return ts, zk.topicChanged, err
+}
+
+func (zk *watchingZK) WatchBrokers() (<-chan struct{}, error) {
+	zk.brokerWatches++
+	return zk.brokerChanged, nil
+}
+
+func TestMetadataCache(t *testing.T) {
+	s := testServer()
+	zk := &countingZK{}
+	s.ZK = zk
+
+	// Disabled.
+	s.topicState("test_topic")
+	s.topicState("test_topic")
+	s.brokerMeta()
+
+	if zk.topicReads != 2 || zk.brokerReads != 1 {
+		t.Errorf("Expected uncached reads, got %d topic and %d broker reads", zk.topicReads, zk.brokerReads)
+	}
+
+	s.metadataCache = newMetadataCache(time.Minute, false)
+	zk.topicReads, zk.brokerReads = 0, 0
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.topicState("test_topic"); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, errs := s.brokerMeta(); errs != nil {
+			t.Fatal(errs)
+		}
+	}
+
+	if zk.topicReads != 1 || zk.brokerReads != 1 {
+		t.Errorf("Expected cached reads, got %d topic and %d broker reads", zk.topicReads, zk.brokerReads)
+	}
+
+	s.metadataCache.invalidate("test_topic")
+	s.topicState("test_topic")
+
+	if zk.topicReads != 2 {
+		t.Errorf("Expected test_topic invalidated, got %d reads", zk.topicReads)
+	}
+
+	hits := s.metrics.cache[cacheLabels{topicStateCache, "hit"}]
+	misses := s.metrics.cache[cacheLabels{topicStateCache, "miss"}]
+	if hits != 2 || misses != 2 {
+		t.Errorf("Expected 2 hits and 2 misses, got %d and %d", hits, misses)
+	}
+
+	// Expired.
+	s.metadataCache.ttl = time.Nanosecond
+	s.topicState("test_topic2")
+	time.Sleep(time.Millisecond)
+	s.topicState("test_topic2")
+
+	if zk.topicReads != 4 {
+		t.Errorf("Expected expired entries read, got %d reads", zk.topicReads)
+	}
+}
+
+func TestMetadataCacheWatch(t *testing.T) {
+	s := testServer()
+	zk := &watchingZK{topicChanged: make(chan struct{}), brokerChanged: make(chan struct{})}
+	s.ZK = zk
+	s.metadataCache = newMetadataCache(0, true)
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.topicState("test_topic"); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, errs := s.brokerMeta(); errs != nil {
+			t.Fatal(errs)
+		}
+	}
+
+	if zk.topicReads != 1 || zk.brokerReads != 1 {
+		t.Errorf("Expected cached reads, got %d topic and %d broker reads", zk.topicReads, zk.brokerReads)
+	}
+
+	// Changes.
+	close(zk.topicChanged)
+	close(zk.brokerChanged)
+	zk.topicChanged, zk.brokerChanged = make(chan struct{}), make(chan struct{})
+
+	s.topicState("test_topic")
+	s.brokerMeta()
+
+	if zk.topicReads != 2 || zk.brokerReads != 2 {
+		t.Errorf("Expected changed entries read, got %d topic and %d broker reads", zk.topicReads, zk.brokerReads)
+	}
+
+	if zk.topicWatches != 2 || zk.brokerWatches != 2 {
+		t.Errorf("Expected watches reset, got %d topic and %d broker watches", zk.topicWatches, zk.brokerWatches)
+	}
+
+	// Expired entries with unfired
+	// watches aren't watched again.
+	s.metadataCache.ttl = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	s.topicState("test_topic")
+	s.brokerMeta()
+
+	if zk.topicReads != 3 || zk.brokerReads != 3 {
+		t.Errorf("Expected expired entries read, got %d topic and %d broker reads", zk.topicReads, zk.brokerReads)
+	}
+
+	if zk.topicWatches != 2 || zk.brokerWatches != 2 {
+		t.Errorf("Expected no new watches, got %d topic and %d broker watches", zk.topicWatches, zk.brokerWatches)
+	}
+}
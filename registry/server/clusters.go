@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+var (
+	// ErrUnknownCluster error.
+	ErrUnknownCluster = errors.New("unknown cluster")
+)
+
+// clusterNameRegex matches valid cluster names.
+var clusterNameRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
+// clusterConfig is the ZooKeeper and Kafka
+// configuration of a federated cluster.
+type clusterConfig struct {
+	ZKAddr          string `json:"zk_addr"`
+	ZKPrefix        string `json:"zk_prefix"`
+	ZKMetricsPrefix string `json:"zk_metrics_prefix"`
+	// ZooKeeper tags storage prefix; defaults
+	// to the registry --zk-tags-prefix.
+	ZKTagsPrefix string `json:"zk_tags_prefix"`
+	// Server name verified against ZooKeeper
+	// server certificates, if TLS is enabled.
+	ZKTLSServerName string `json:"zk_tls_server_name"`
+	// Optional; required for Kafka
+	// Admin API requests.
+	KafkaBootstrapServers string `json:"kafka_bootstrap_servers"`
+	// Tag defaults of the cluster; defaults
+	// to the registry --tag-defaults-file.
+	TagDefaultsFile string `json:"tag_defaults_file"`
+}
+
+// readClusters reads the clusterConfigs of the federated
+// clusters from the JSON object of names to configs at path.
+func readClusters(path string) (map[string]clusterConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := map[string]clusterConfig{}
+	if err := json.Unmarshal(data, &clusters); err != nil {
+		return nil, fmt.Errorf("error parsing clusters: %s", err)
+	}
+
+	for name, c := range clusters {
+		switch {
+		case !clusterNameRegex.MatchString(name):
+			return nil, fmt.Errorf("invalid cluster name '%s'", name)
+		case c.ZKAddr == "":
+			return nil, fmt.Errorf("cluster %s: zk_addr must be specified", name)
+		}
+
+		if c.ZKMetricsPrefix == "" {
+			c.ZKMetricsPrefix = "topicmappr"
+		}
+
+		clusters[name] = c
+	}
+
+	return clusters, nil
+}
+
+// newClusterServer returns a *Server for the named federated cluster. The
+// cluster Server shares the registry configuration, throttles and audit log
+// of s, and has its own ZooKeeper and Kafka connections (see DialClusters),
+// tags storage, reassignment plans and watch subscribers. Tags are stored
+// in the cluster ZooKeeper or Kafka cluster as with the default cluster;
+// etcd tags are stored under clusters/<name> beneath the etcd prefix.
+func (s *Server) newClusterServer(name string, c clusterConfig, tcfg TagHandlerConfig) (*Server, error) {
+	if c.ZKTagsPrefix != "" {
+		tcfg.Prefix = c.ZKTagsPrefix
+	}
+
+	tcfg.EtcdPrefix = fmt.Sprintf("%s/clusters/%s", tcfg.EtcdPrefix, name)
+
+	if c.TagDefaultsFile != "" {
+		d, err := readTagDefaults(c.TagDefaultsFile)
+		if err != nil {
+			return nil, fmt.Errorf("cluster %s: %s", name, err)
+		}
+		tcfg.Defaults = d
+	}
+
+	th, err := NewTagHandler(tcfg)
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: %s", name, err)
+	}
+
+	if s.test {
+		th.Store = newzkTagStorageMock()
+	}
+
+	var migratePrefix string
+	if _, zk := th.Store.(*ZKTagStorage); s.tagsMigratePrefix != "" && !zk {
+		migratePrefix = tcfg.Prefix
+	}
+
+	return &Server{
+		Tags:                     th,
+		readReqThrottle:          s.readReqThrottle,
+		writeReqThrottle:         s.writeReqThrottle,
+		metaReqThrottle:          s.metaReqThrottle,
+		clientReadThrottle:       s.clientReadThrottle,
+		clientWriteThrottle:      s.clientWriteThrottle,
+		tlsConfig:                s.tlsConfig,
+		auth:                     s.auth,
+		deleteIdleWindow:         s.deleteIdleWindow,
+		protectedTag:             s.protectedTag,
+		deleteTokens:             newDeleteTokens(),
+		reassignmentPlans:        newReassignmentPlans(),
+		reassignmentPollInterval: s.reassignmentPollInterval,
+		watchHub:                 newWatchHub(),
+		watchInterval:            s.watchInterval,
+		tagsMigratePrefix:        migratePrefix,
+		webhooks:                 s.webhooks,
+		audit:                    s.audit,
+		stateEvents:              s.stateEvents,
+		policy:                   s.policy,
+		lifecycleRules:           s.lifecycleRules,
+		lifecycleInterval:        s.lifecycleInterval,
+		schemaRegistry:           s.schemaRegistry,
+		metrics:                  s.metrics,
+		health:                   s.health,
+		metadataCache:            newMetadataCache(s.metadataCache.ttl, s.metadataCache.watch),
+		clusterName:              name,
+		clusterConfig:            c,
+		election:                 s.election,
+		test:                     s.test,
+	}, nil
+}
+
+// DialClusters dials ZooKeeper and, if configured, Kafka for each federated
+// cluster and initializes its tags storage, as with DialZK, DialKafka and
+// InitTags for the default cluster. The ZooKeeper and Kafka configs are used
+// for all connection parameters other than those of the cluster config.
+func (s *Server) DialClusters(ctx context.Context, wg *sync.WaitGroup, zc kafkazk.Config, kc kafkaadmin.Config) error {
+	for _, c := range s.clusterServers()[1:] {
+		zc.Connect = c.clusterConfig.ZKAddr
+		zc.Prefix = c.clusterConfig.ZKPrefix
+		zc.MetricsPrefix = c.clusterConfig.ZKMetricsPrefix
+		zc.TLSServerName = c.clusterConfig.ZKTLSServerName
+
+		if err := c.DialZK(ctx, wg, &zc); err != nil {
+			return fmt.Errorf("cluster %s: %s", c.clusterName, err)
+		}
+
+		if c.clusterConfig.KafkaBootstrapServers != "" {
+			kc.BootstrapServers = c.clusterConfig.KafkaBootstrapServers
+			if err := c.DialKafka(&kc); err != nil {
+				return fmt.Errorf("cluster %s: %s", c.clusterName, err)
+			}
+		}
+
+		if err := c.InitTags(); err != nil {
+			return fmt.Errorf("cluster %s: %s", c.clusterName, err)
+		}
+	}
+
+	if n := len(s.clusters); n > 0 {
+		log.Printf("Federated clusters: %d\n", n)
+	}
+
+	return nil
+}
+
+// cluster returns the *Server of the named cluster; s
+// if the name is empty or that of the default cluster.
+func (s *Server) cluster(name string) (*Server, error) {
+	if name == "" || name == s.clusterName {
+		return s, nil
+	}
+
+	if c, exists := s.clusters[name]; exists {
+		return c, nil
+	}
+
+	return nil, ErrUnknownCluster
+}
+
+// clusterServers returns the *Servers of all clusters:
+// s, followed by the federated clusters sorted by name.
+func (s *Server) clusterServers() []*Server {
+	var names []string
+	for name := range s.clusters {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	servers := []*Server{s}
+	for _, name := range names {
+		servers = append(servers, s.clusters[name])
+	}
+
+	return servers
+}
@@ -0,0 +1,265 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func testClustersFile(t *testing.T, data string) (string, func()) {
+	f, err := ioutil.TempFile("", "clusters")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.WriteString(data)
+	f.Close()
+
+	return f.Name(), func() { os.Remove(f.Name()) }
+}
+
+func testClusterServer(t *testing.T) (*Server, func()) {
+	path, cleanup := testClustersFile(t, `{"east": {"zk_addr": "zk-east:2181"}, "west": {"zk_addr": "zk-west:2181", "kafka_bootstrap_servers": "kafka-west:9092"}}`)
+
+	s, err := NewServer(Config{
+		ReadReqRate:     1,
+		WriteReqRate:    1,
+		MetadataReqRate: 1,
+		ZKTagsPrefix:    testConfig.Prefix,
+		WatchInterval:   time.Second,
+		ClusterName:     "main",
+		ClustersFile:    path,
+		test:            true,
+	})
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+
+	s.DialZK(nil, nil, nil)
+	s.DialKafka(nil)
+
+	if err := s.DialClusters(context.Background(), &sync.WaitGroup{}, kafkazk.Config{}, kafkaadmin.Config{}); err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+
+	return s, cleanup
+}
+
+func TestReadClusters(t *testing.T) {
+	tests := map[int]string{
+		0: `{"east": {"zk_addr": "zk-east:2181", "zk_prefix": "kafka"}}`,
+		1: `{"east": {}}`,
+		2: `{"east/1": {"zk_addr": "zk-east:2181"}}`,
+		3: `[]`,
+	}
+
+	expected := map[int]string{
+		0: "",
+		1: "cluster east: zk_addr must be specified",
+		2: "invalid cluster name 'east/1'",
+		3: "error parsing clusters: json: cannot unmarshal array into Go value of type map[string]server.clusterConfig",
+	}
+
+	for i, data := range tests {
+		path, cleanup := testClustersFile(t, data)
+		clusters, err := readClusters(path)
+		cleanup()
+
+		if expected[i] == "" {
+			if err != nil {
+				t.Fatalf("[test %d] Unexpected error: %s", i, err)
+			}
+
+			c := clusters["east"]
+			if c.ZKAddr != "zk-east:2181" || c.ZKPrefix != "kafka" || c.ZKMetricsPrefix != "topicmappr" {
+				t.Errorf("[test %d] Unexpected config %+v", i, c)
+			}
+
+			continue
+		}
+
+		if err == nil || err.Error() != expected[i] {
+			t.Errorf("[test %d] Expected error '%s', got '%v'", i, expected[i], err)
+		}
+	}
+}
+
+func TestNewServerClusters(t *testing.T) {
+	path, cleanup := testClustersFile(t, `{"main": {"zk_addr": "zk-main:2181"}}`)
+	defer cleanup()
+
+	tests := map[int]Config{
+		0: Config{ClustersFile: path},
+		1: Config{ClustersFile: path, ClusterName: "main"},
+		2: Config{ClusterName: "main cluster"},
+	}
+
+	expected := map[int]string{
+		0: "invalid configuration parameter(s)",
+		1: "cluster main is the default cluster",
+		2: "invalid configuration parameter(s)",
+	}
+
+	for i, c := range tests {
+		c.ReadReqRate, c.WriteReqRate, c.MetadataReqRate = 1, 1, 1
+		c.ZKTagsPrefix = testConfig.Prefix
+		c.WatchInterval = time.Second
+		c.test = true
+
+		if _, err := NewServer(c); err == nil || err.Error() != expected[i] {
+			t.Errorf("[test %d] Expected error '%s', got '%v'", i, expected[i], err)
+		}
+	}
+}
+
+func TestCluster(t *testing.T) {
+	s, cleanup := testClusterServer(t)
+	defer cleanup()
+
+	for _, name := range []string{"", "main"} {
+		if c, err := s.cluster(name); err != nil || c != s {
+			t.Errorf("Expected the default cluster for name '%s'", name)
+		}
+	}
+
+	east, err := s.cluster("east")
+	if err != nil || east.clusterName != "east" || east.clusterConfig.ZKAddr != "zk-east:2181" {
+		t.Errorf("Unexpected cluster %v (error %v)", east, err)
+	}
+
+	// Only west configures Kafka.
+	if west := s.clusters["west"]; west.Kafka == nil || east.Kafka != nil {
+		t.Error("Expected only cluster west to have a Kafka client")
+	}
+
+	if _, err := s.cluster("north"); err != ErrUnknownCluster {
+		t.Errorf("Expected error '%s', got '%v'", ErrUnknownCluster, err)
+	}
+
+	if _, err := s.GetBrokers(context.Background(), &pb.BrokerRequest{Cluster: "north"}); err != ErrUnknownCluster {
+		t.Errorf("Expected error '%s', got '%v'", ErrUnknownCluster, err)
+	}
+
+	var names []string
+	for _, c := range s.clusterServers() {
+		names = append(names, c.clusterName)
+	}
+
+	if !stringsEqual(names, []string{"main", "east", "west"}) {
+		t.Errorf("Expected clusters [main east west], got %v", names)
+	}
+}
+
+func TestClusterTags(t *testing.T) {
+	s, cleanup := testClusterServer(t)
+	defer cleanup()
+
+	req := &pb.TopicRequest{Name: "test_topic", Tag: []string{"team:data"}, Cluster: "east"}
+	if _, err := s.TagTopic(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	o := KafkaObject{Type: "topic", ID: "test_topic"}
+
+	// Tags are only set in the requested cluster.
+	for name, expected := range map[string]string{"main": "", "east": "data", "west": ""} {
+		c, _ := s.cluster(name)
+		if tags := c.storedTags(o); tags["team"] != expected {
+			t.Errorf("Cluster %s: expected team tag '%s', got %v", name, expected, tags)
+		}
+	}
+}
+
+func TestClusterAuditLog(t *testing.T) {
+	s, cleanup := testClusterServer(t)
+	defer cleanup()
+
+	a, cleanupAudit := testAuditStore(t)
+	defer cleanupAudit()
+
+	for _, c := range s.clusterServers() {
+		c.audit = a
+	}
+
+	for _, cluster := range []string{"", "east"} {
+		req := &pb.TopicRequest{Name: "test_topic", Tag: []string{"team:data"}, Cluster: cluster}
+		if _, err := s.TagTopic(context.Background(), req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := map[string]string{"": "main", "main": "main", "east": "east"}
+
+	for cluster, name := range expected {
+		resp, err := s.GetAuditLog(context.Background(), &pb.AuditLogRequest{Cluster: cluster})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(resp.Entries) != 1 || resp.Entries[0].Cluster != name {
+			t.Errorf("Cluster '%s': expected 1 entry for cluster %s, got %v", cluster, name, resp.Entries)
+		}
+	}
+}
+
+func TestClusterWatch(t *testing.T) {
+	s, cleanup := testClusterServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := &eventStream{ctx: ctx, events: make(chan *pb.WatchEvent, 10)}
+
+	go s.Watch(&pb.WatchRequest{Types: []string{"tag"}, Cluster: "west"}, stream)
+
+	for s.clusters["west"].watchHub.subscribers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Changes to other clusters aren't published.
+	for _, cluster := range []string{"east", "west"} {
+		req := &pb.TopicRequest{Name: "test_topic", Tag: []string{"team:data"}, Cluster: cluster}
+		if _, err := s.TagTopic(context.Background(), req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	e := <-stream.events
+	if e.Cluster != "west" || e.Name != "topics/test_topic" {
+		t.Errorf("Unexpected event %v", e)
+	}
+
+	if len(stream.events) != 0 {
+		t.Errorf("Expected 1 event, got %d", len(stream.events)+1)
+	}
+}
+
+func TestClusterHealth(t *testing.T) {
+	s, cleanup := testClusterServer(t)
+	defer cleanup()
+
+	s.checkHealth()
+
+	for _, svc := range []string{"", registryService, "cluster/east", "cluster/west"} {
+		resp, err := s.health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: svc})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.Status != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("Service '%s': expected SERVING, got %s", svc, resp.Status)
+		}
+	}
+}
@@ -0,0 +1,535 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrNoLeader error.
+	ErrNoLeader = status.Error(codes.Unavailable, "no registry leader is elected")
+	// ErrNotLeader error.
+	ErrNotLeader = status.Error(codes.Unavailable, "not the registry leader")
+	// errForwardToLeader is returned by ValidateRequest
+	// for requests that the interceptors forward to the
+	// leader.
+	errForwardToLeader = errors.New("forward to leader")
+)
+
+// electionZNode is the znode under the ZooKeeper tags
+// prefix that election candidates are registered in.
+const electionZNode = "leader"
+
+// electionInterval is the interval at which
+// the election leader is checked.
+const electionInterval = 5 * time.Second
+
+// forwardedByKey is the metadata key set on requests forwarded to the
+// leader, with the candidate ID of the forwarding registry.
+const forwardedByKey = "x-registry-forwarded-by"
+
+// peerTokenKey is the metadata key of the peer token, which
+// authenticates requests forwarded by another registry.
+const peerTokenKey = "x-registry-peer-token"
+
+// forwardedIdentityKey is the metadata key of the authenticated
+// identity of a forwarded request, if authenticated.
+const forwardedIdentityKey = "x-registry-forwarded-identity"
+
+// leaderElection elects a single registry, among registries sharing a
+// ZooKeeper election path, to serve write requests and run background
+// work. Each registry registers an ephemeral sequential znode with its
+// candidate ID, the gRPC address other registries forward requests to;
+// the registry with the lowest sequence number is the leader. If the leader
+// exits or its ZooKeeper session expires, its znode is removed and the
+// next registry takes over. Registries are identified by the name of the
+// znode they registered, rather than their ID.
+type leaderElection struct {
+	zk   kafkazk.Handler
+	path string
+	id   string
+	// The token shared by registries that
+	// authenticates forwarded requests.
+	peerToken string
+	// Dial options for connections to the leader.
+	dialOpts []grpc.DialOption
+
+	sync.Mutex
+	// The znode name of this candidate;
+	// empty until registered.
+	node string
+	// The leader ID and znode name as of the
+	// latest check; empty if the leader is unknown.
+	leader     string
+	leaderNode string
+	// The connection to the leader, if
+	// dialed, and its address.
+	conn     *grpc.ClientConn
+	connAddr string
+}
+
+// RunElection registers the registry as a leader election candidate and
+// checks the election leader every electionInterval. Until this registry
+// is elected, write requests are forwarded to the leader, and background
+// work (topic lifecycle enforcement, webhook deliveries) is paused. It's
+// a no-op if leader election isn't enabled. It should be called after
+// DialZK.
+func (s *Server) RunElection(ctx context.Context, wg *sync.WaitGroup) error {
+	e := s.election
+	if e == nil {
+		return nil
+	}
+
+	e.zk = s.ZK
+
+	exists, err := e.zk.Exists(e.path)
+	if err != nil {
+		return fmt.Errorf("error checking election path: %s", err)
+	}
+
+	if !exists {
+		if err := e.zk.Create(e.path, ""); err != nil {
+			return fmt.Errorf("error creating election path: %s", err)
+		}
+	}
+
+	log.Printf("Leader election enabled, candidate ID %s\n", e.id)
+
+	e.check()
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		t := time.NewTicker(electionInterval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				e.Lock()
+				if e.conn != nil {
+					e.conn.Close()
+				}
+				e.Unlock()
+				return
+			case <-t.C:
+				e.check()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// isLeader returns whether this registry is the leader. Registries
+// are always the leader if leader election isn't enabled.
+func (s *Server) isLeader() bool {
+	if s.election == nil {
+		return true
+	}
+
+	s.election.Lock()
+	defer s.election.Unlock()
+
+	return s.election.leaderNode != "" && s.election.leaderNode == s.election.node
+}
+
+// requireLeader returns errForwardToLeader if leader election is enabled
+// and this registry isn't the leader. Requests already forwarded by another
+// registry, e.g. while the leader changes, aren't forwarded again.
+func (s *Server) requireLeader(ctx context.Context) error {
+	if s.isLeader() {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md[forwardedByKey]) > 0 {
+		return ErrNotLeader
+	}
+
+	return errForwardToLeader
+}
+
+// check fetches the election leader, registering
+// this registry as a candidate if it isn't.
+func (e *leaderElection) check() {
+	leader, err := e.fetchLeader()
+	if err != nil {
+		log.Printf("[election] %s\n", err)
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	elected := leader.node != "" && leader.node == e.node
+	wasLeader := e.leaderNode != "" && e.leaderNode == e.node
+
+	if leader.node != e.leaderNode {
+		switch {
+		case elected:
+			log.Println("[election] elected leader")
+		case wasLeader:
+			log.Printf("[election] no longer the leader; leader: %s\n", leader.id)
+		case leader.id != "":
+			log.Printf("[election] leader: %s\n", leader.id)
+		}
+	}
+
+	e.leader, e.leaderNode = leader.id, leader.node
+}
+
+// candidate is a registered election candidate.
+type candidate struct {
+	// The candidate znode name.
+	node string
+	id   string
+}
+
+// fetchLeader returns the leader candidate. This registry is (re)registered
+// if its znode doesn't exist, e.g. if its ZooKeeper session expired. The
+// leader is empty if it can't be determined.
+func (e *leaderElection) fetchLeader() (candidate, error) {
+	candidates, err := e.candidates()
+	if err != nil {
+		return candidate{}, err
+	}
+
+	// The node is only set by
+	// this goroutine.
+	if !registered(candidates, e.node) {
+		created, err := e.zk.CreateEphemeralSequential(e.path+"/candidate_", e.id)
+		if err != nil {
+			return candidate{}, fmt.Errorf("error registering election candidate: %s", err)
+		}
+
+		e.Lock()
+		e.node = path.Base(created)
+		e.Unlock()
+
+		// Candidates previously registered with this ID are stale,
+		// e.g. znodes created by a failed request (such as on a
+		// connection loss) or a previous process whose session
+		// hasn't expired yet; remove them so that they aren't
+		// elected in place of this registry.
+		for _, c := range candidates {
+			if c.id == e.id {
+				if err := e.zk.Delete(fmt.Sprintf("%s/%s", e.path, c.node)); err != nil {
+					log.Printf("[election] error removing stale candidate %s: %s\n", c.node, err)
+				}
+			}
+		}
+
+		// Candidates registering concurrently
+		// are ordered once registered.
+		if candidates, err = e.candidates(); err != nil {
+			return candidate{}, err
+		}
+
+		if !registered(candidates, e.node) {
+			return candidate{}, fmt.Errorf("election candidate %s not registered", e.id)
+		}
+	}
+
+	return candidates[0], nil
+}
+
+// candidates returns the registered
+// candidates in the order of election.
+func (e *leaderElection) candidates() ([]candidate, error) {
+	children, err := e.zk.Children(e.path)
+	if err != nil {
+		return nil, fmt.Errorf("error listing election candidates: %s", err)
+	}
+
+	// Znodes are named with a common prefix and
+	// a zero padded sequence number.
+	sort.Strings(children)
+
+	var candidates []candidate
+	for _, c := range children {
+		d, err := e.zk.Get(fmt.Sprintf("%s/%s", e.path, c))
+		if err != nil {
+			return nil, fmt.Errorf("error fetching election candidate: %s", err)
+		}
+
+		candidates = append(candidates, candidate{node: c, id: string(d)})
+	}
+
+	return candidates, nil
+}
+
+// registered returns whether the
+// candidates include the znode.
+func registered(candidates []candidate, node string) bool {
+	for _, c := range candidates {
+		if node != "" && c.node == node {
+			return true
+		}
+	}
+
+	return false
+}
+
+// leaderConn returns a connection to the leader, dialing
+// it if the leader changed since the last call.
+func (e *leaderElection) leaderConn() (*grpc.ClientConn, error) {
+	e.Lock()
+	defer e.Unlock()
+
+	if e.leader == "" || e.leaderNode == e.node || e.leader == e.id {
+		return nil, ErrNoLeader
+	}
+
+	if e.conn != nil && e.connAddr == e.leader {
+		return e.conn, nil
+	}
+
+	if e.conn != nil {
+		e.conn.Close()
+	}
+
+	conn, err := grpc.Dial(e.leader, e.dialOpts...)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "error dialing leader %s: %s", e.leader, err)
+	}
+
+	e.conn, e.connAddr = conn, e.leader
+
+	return conn, nil
+}
+
+// leaderContext returns the outgoing context of a request forwarded to
+// the leader. The request is authenticated by this registry, and its
+// identity (by bearer token or client certificate) is forwarded along
+// with the client address and the peer token; see forwardedByPeer.
+func (s *Server) leaderContext(ctx context.Context) (context.Context, error) {
+	id, err := s.identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	md := metadata.Pairs(
+		forwardedByKey, s.election.id,
+		peerTokenKey, s.election.peerToken,
+		"x-forwarded-for", s.client(ctx),
+	)
+
+	if id != "" {
+		md.Set(forwardedIdentityKey, id)
+	}
+
+	return metadata.NewOutgoingContext(ctx, md), nil
+}
+
+// forwardedByPeer returns whether the request was forwarded by another
+// registry, authenticated by the peer token. The forwarded identity and
+// client address are only trusted from authenticated registries.
+func (s *Server) forwardedByPeer(ctx context.Context) bool {
+	if s.election == nil || s.election.peerToken == "" {
+		return false
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md[peerTokenKey]) == 0 {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(md[peerTokenKey][0]), []byte(s.election.peerToken)) == 1
+}
+
+// unaryForward is a gRPC interceptor that forwards requests refused
+// with errForwardToLeader to the leader; see requireLeader.
+func (s *Server) unaryForward(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != errForwardToLeader {
+		return resp, err
+	}
+
+	conn, err := s.election.leaderConn()
+	if err != nil {
+		return nil, err
+	}
+
+	lctx, err := s.leaderContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The response type of the method.
+	m := reflect.ValueOf(info.Server).MethodByName(methodName(info.FullMethod))
+	resp = reflect.New(m.Type().Out(0).Elem()).Interface()
+
+	if err := conn.Invoke(lctx, info.FullMethod, req, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// streamForward is the streaming equivalent of unaryForward;
+// the request of server streams is forwarded once received.
+func (s *Server) streamForward(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	rs := &recordedStream{ServerStream: ss}
+
+	err := handler(srv, rs)
+	if err != errForwardToLeader || rs.req == nil {
+		return err
+	}
+
+	conn, err := s.election.leaderConn()
+	if err != nil {
+		return err
+	}
+
+	// The response type of the method, sent by the
+	// Send method of the stream parameter.
+	m := reflect.ValueOf(srv).MethodByName(methodName(info.FullMethod))
+	send, _ := m.Type().In(1).MethodByName("Send")
+	respType := send.Type.In(0).Elem()
+
+	lctx, err := s.leaderContext(ss.Context())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(lctx)
+	defer cancel()
+
+	desc := &grpc.StreamDesc{ServerStreams: info.IsServerStream, ClientStreams: info.IsClientStream}
+	cs, err := conn.NewStream(ctx, desc, info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	if err := cs.SendMsg(rs.req); err != nil {
+		return err
+	}
+
+	if err := cs.CloseSend(); err != nil {
+		return err
+	}
+
+	for {
+		resp := reflect.New(respType).Interface()
+		if err := cs.RecvMsg(resp); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if err := ss.SendMsg(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// recordedStream records the request received.
+type recordedStream struct {
+	grpc.ServerStream
+	req interface{}
+}
+
+// RecvMsg receives and records the request message.
+func (r *recordedStream) RecvMsg(m interface{}) error {
+	if err := r.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	r.req = m
+
+	return nil
+}
+
+// methodName returns the method name of a
+// full gRPC method name, e.g. /pkg.Service/Method.
+func methodName(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
+
+// electionDialOpts returns the dial options of connections to the leader,
+// which serves TLS if this registry does. The leader certificate is verified
+// against the CA certificates in the caFile, if set.
+func electionDialOpts(tlsConfig *tls.Config, caFile string) ([]grpc.DialOption, error) {
+	if tlsConfig == nil {
+		return []grpc.DialOption{grpc.WithInsecure()}, nil
+	}
+
+	c := &tls.Config{}
+
+	if caFile != "" {
+		data, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading leader TLS CA: %s", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.New("no certificates found in leader TLS CA")
+		}
+
+		c.RootCAs = pool
+	}
+
+	return []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(c))}, nil
+}
+
+// validAdvertiseAddr returns an error if the advertise address isn't
+// reachable by other registries: its host must be set and not a loopback
+// or unspecified address. Registries are also identified by the address,
+// which should therefore be unique.
+func validAdvertiseAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid advertise address '%s': %s", addr, err)
+	}
+
+	ip := net.ParseIP(host)
+
+	switch {
+	case host == "", host == "localhost":
+		fallthrough
+	case ip != nil && (ip.IsLoopback() || ip.IsUnspecified()):
+		return fmt.Errorf("advertise address '%s' must be reachable by other registries", addr)
+	}
+
+	return nil
+}
+
+// readPeerToken reads the peer token from path.
+func readPeerToken(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading peer token: %s", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", errors.New("peer token must be non-empty")
+	}
+
+	return token, nil
+}
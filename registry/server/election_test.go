@@ -0,0 +1,367 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// memZK is an in-memory kafkazk.Handler
+// for the election znodes.
+type memZK struct {
+	kafkazk.Mock
+	sync.Mutex
+	data map[string]string
+	seq  int
+}
+
+func newMemZK() *memZK {
+	return &memZK{data: map[string]string{}}
+}
+
+func (zk *memZK) Exists(p string) (bool, error) {
+	zk.Lock()
+	defer zk.Unlock()
+	_, exists := zk.data[p]
+	return exists, nil
+}
+
+func (zk *memZK) Create(p, d string) error {
+	zk.Lock()
+	defer zk.Unlock()
+	zk.data[p] = d
+	return nil
+}
+
+func (zk *memZK) CreateEphemeralSequential(p, d string) (string, error) {
+	zk.Lock()
+	defer zk.Unlock()
+	zk.seq++
+	created := fmt.Sprintf("%s%010d", p, zk.seq)
+	zk.data[created] = d
+	return created, nil
+}
+
+func (zk *memZK) Get(p string) ([]byte, error) {
+	zk.Lock()
+	defer zk.Unlock()
+	return []byte(zk.data[p]), nil
+}
+
+func (zk *memZK) Delete(p string) error {
+	zk.Lock()
+	defer zk.Unlock()
+	delete(zk.data, p)
+	return nil
+}
+
+func (zk *memZK) Children(p string) ([]string, error) {
+	zk.Lock()
+	defer zk.Unlock()
+	var c []string
+	for k := range zk.data {
+		if strings.HasPrefix(k, p+"/") && !strings.Contains(k[len(p)+1:], "/") {
+			c = append(c, k[len(p)+1:])
+		}
+	}
+
+	return c, nil
+}
+
+// electionServer returns a test Server
+// with leader election using the zk.
+func electionServer(zk kafkazk.Handler, id string) *Server {
+	s := testServer()
+	s.ZK = zk
+	s.election = &leaderElection{
+		path:      "/registry/leader",
+		id:        id,
+		peerToken: "peer-token",
+		dialOpts:  []grpc.DialOption{grpc.WithInsecure()},
+	}
+
+	return s
+}
+
+func TestLeaderElection(t *testing.T) {
+	zk := newMemZK()
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+
+	a := electionServer(zk, "a:8090")
+	b := electionServer(zk, "b:8090")
+
+	for _, s := range []*Server{a, b} {
+		if err := s.RunElection(ctx, wg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if exists, _ := zk.Exists("/registry/leader"); !exists {
+		t.Error("Expected election path to be created")
+	}
+
+	// The first registry registered is the leader.
+	if !a.isLeader() || b.isLeader() {
+		t.Errorf("Expected a to be the leader, got a: %v, b: %v", a.isLeader(), b.isLeader())
+	}
+
+	// The leader's session expires.
+	zk.Delete("/registry/leader/candidate_0000000001")
+	b.election.check()
+
+	if !b.isLeader() {
+		t.Error("Expected b to take over as leader")
+	}
+
+	// a re-registers as a standby.
+	a.election.check()
+
+	if a.isLeader() {
+		t.Error("Expected a to be a standby")
+	}
+
+	if c, _ := zk.Children("/registry/leader"); len(c) != 2 {
+		t.Errorf("Expected 2 candidates, got %d", len(c))
+	}
+
+	cancel()
+	wg.Wait()
+
+	// Registries are the leader without election.
+	if s := testServer(); !s.isLeader() {
+		t.Error("Expected the leader without election")
+	}
+}
+
+func TestLeaderElectionStaleCandidate(t *testing.T) {
+	zk := newMemZK()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A candidate left by a previous
+	// registry process with a's ID.
+	zk.Create("/registry/leader", "")
+	zk.Create("/registry/leader/candidate_0000000000", "a:8090")
+
+	a := electionServer(zk, "a:8090")
+	b := electionServer(zk, "b:8090")
+
+	if err := b.RunElection(ctx, &sync.WaitGroup{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if b.isLeader() {
+		t.Error("Expected b not to be the leader")
+	}
+
+	// a registers its own candidate rather than
+	// matching the stale candidate by ID, and
+	// removes the stale candidate.
+	if err := a.RunElection(ctx, &sync.WaitGroup{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.isLeader() {
+		t.Error("Expected a not to be the leader")
+	}
+
+	if exists, _ := zk.Exists("/registry/leader/candidate_0000000000"); exists {
+		t.Error("Expected the stale candidate to be removed")
+	}
+
+	b.election.check()
+
+	if !b.isLeader() {
+		t.Error("Expected b to be the leader")
+	}
+}
+
+func TestForwardToLeader(t *testing.T) {
+	zk := newMemZK()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leader := electionServer(zk, l.Addr().String())
+	follower := electionServer(zk, "follower:8090")
+
+	for _, s := range []*Server{leader, follower} {
+		if err := s.RunElection(ctx, &sync.WaitGroup{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Writes require authentication by client
+	// certificate, which isn't presented by
+	// the follower; the identity is forwarded.
+	leader.auth = authConfig{clientCerts: true}
+	follower.auth = authConfig{clientCerts: true}
+
+	srvr := grpc.NewServer(grpc.UnaryInterceptor(leader.unaryInterceptor))
+	pb.RegisterRegistryServer(srvr, leader)
+	go srvr.Serve(l)
+	defer srvr.Stop()
+
+	// A write request to the follower.
+	req := &pb.TopicRequest{Name: "test_topic", Tag: []string{"team:data"}}
+	info := &grpc.UnaryServerInfo{Server: follower, FullMethod: "/" + registryService + "/TagTopic"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return follower.TagTopic(ctx, req.(*pb.TopicRequest))
+	}
+
+	resp, err := follower.unaryInterceptor(certContext("data-team"), req, info, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := resp.(*pb.TagResponse); !ok {
+		t.Errorf("Expected a *pb.TagResponse, got %T", resp)
+	}
+
+	// The tags are set by the leader.
+	o := KafkaObject{Type: "topic", ID: "test_topic"}
+	if tags, _ := leader.Tags.Store.GetTags(o); tags["team"] != "data" {
+		t.Errorf("Expected the leader to tag the topic, got tags %v", tags)
+	}
+
+	if tags, _ := follower.Tags.Store.GetTags(o); len(tags) != 0 {
+		t.Errorf("Expected no tags set by the follower, got %v", tags)
+	}
+
+	// Requests forwarded to a registry that
+	// isn't the leader aren't forwarded again.
+	fctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(forwardedByKey, "other:8090"))
+	if _, err := follower.unaryInterceptor(fctx, req, info, handler); err != ErrNotLeader {
+		t.Errorf("Expected error '%s', got '%v'", ErrNotLeader, err)
+	}
+
+	// Forwarded identities aren't trusted
+	// without the peer token.
+	follower.election.peerToken = "other-token"
+	if _, err := follower.unaryInterceptor(certContext("data-team"), req, info, handler); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected an Unauthenticated error, got '%v'", err)
+	}
+
+	// Reads are served by the follower.
+	if _, err := follower.GetTopics(context.Background(), &pb.TopicRequest{}); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+}
+
+// certContext returns an incoming request context
+// with a verified client certificate of the name.
+func certContext(name string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: name}}
+	state := tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr:     &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234},
+		AuthInfo: credentials.TLSInfo{State: state},
+	})
+}
+
+func TestForwardedByPeer(t *testing.T) {
+	s := electionServer(newMemZK(), "a:8090")
+
+	p := &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 1234}}
+	md := metadata.Pairs(forwardedByKey, "b:8090", forwardedIdentityKey, "admin", "x-forwarded-for", "192.0.2.1")
+
+	// Forwarded metadata without the
+	// peer token isn't trusted.
+	ctx := metadata.NewIncomingContext(peer.NewContext(context.Background(), p), md)
+
+	if id, _ := s.identity(ctx); id != "" {
+		t.Errorf("Expected no identity, got '%s'", id)
+	}
+
+	if c := s.client(ctx); c != "10.0.0.2" {
+		t.Errorf("Expected client 10.0.0.2, got '%s'", c)
+	}
+
+	// With the peer token.
+	md.Set(peerTokenKey, "peer-token")
+	ctx = metadata.NewIncomingContext(peer.NewContext(context.Background(), p), md)
+
+	if id, _ := s.identity(ctx); id != "admin" {
+		t.Errorf("Expected identity admin, got '%s'", id)
+	}
+
+	md.Delete(forwardedIdentityKey)
+	ctx = metadata.NewIncomingContext(peer.NewContext(context.Background(), p), md)
+
+	if c := s.client(ctx); c != "192.0.2.1" {
+		t.Errorf("Expected client 192.0.2.1, got '%s'", c)
+	}
+}
+
+func TestNewServerLeaderElection(t *testing.T) {
+	f, err := ioutil.TempFile("", "registry_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+	f.WriteString("peer-token\n")
+	f.Close()
+
+	tests := map[int]Config{
+		0: Config{PeerTokenFile: f.Name()},
+		1: Config{AdvertiseAddr: "registry-0:8090"},
+		2: Config{AdvertiseAddr: "localhost:8090", PeerTokenFile: f.Name()},
+		3: Config{AdvertiseAddr: "0.0.0.0:8090", PeerTokenFile: f.Name()},
+		4: Config{AdvertiseAddr: "registry-0", PeerTokenFile: f.Name()},
+		5: Config{AdvertiseAddr: "registry-0:8090", PeerTokenFile: f.Name()},
+	}
+
+	expected := map[int]string{
+		0: "invalid configuration parameter(s)",
+		1: "invalid configuration parameter(s)",
+		2: "advertise address 'localhost:8090' must be reachable by other registries",
+		3: "advertise address '0.0.0.0:8090' must be reachable by other registries",
+		4: "invalid advertise address 'registry-0': address registry-0: missing port in address",
+		5: "",
+	}
+
+	for i, c := range tests {
+		c.ReadReqRate, c.WriteReqRate, c.MetadataReqRate = 1, 1, 1
+		c.ZKTagsPrefix = testConfig.Prefix
+		c.WatchInterval = time.Second
+		c.LeaderElection = true
+		c.test = true
+
+		s, err := NewServer(c)
+
+		switch {
+		case expected[i] == "" && err != nil:
+			t.Errorf("[test %d] Unexpected error: %s", i, err)
+		case expected[i] != "" && (err == nil || err.Error() != expected[i]):
+			t.Errorf("[test %d] Expected error '%s', got '%v'", i, expected[i], err)
+		case err == nil && (s.election.id != c.AdvertiseAddr || s.election.peerToken != "peer-token"):
+			t.Errorf("[test %d] Unexpected election %+v", i, s.election)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HTTP paths of the liveness and readiness probes.
+const (
+	livenessPath  = "/healthz"
+	readinessPath = "/readyz"
+)
+
+// The gRPC service names reported by the health
+// service, along with the overall ("") server health.
+const (
+	registryService   = "registry.Registry"
+	registryV2Service = "registry.v2.Registry"
+)
+
+// healthCheckInterval is the interval at which ZooKeeper
+// and Kafka connectivity is checked.
+const healthCheckInterval = 5 * time.Second
+
+// healthState holds the results of the latest connectivity check.
+type healthState struct {
+	sync.Mutex
+	zk    bool
+	kafka bool
+}
+
+func newHealthServer() *health.Server {
+	h := health.NewServer()
+	for _, svc := range []string{"", registryService, registryV2Service} {
+		h.SetServingStatus(svc, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
+	return h
+}
+
+// RunHealthChecks checks the ZooKeeper and Kafka (if configured)
+// connectivity of each cluster every healthCheckInterval, updating the gRPC
+// health service and readiness probe status. A cluster is ready while
+// ZooKeeper is connected and Kafka, if configured, is reachable. The overall,
+// registry.Registry and registry.v2.Registry service status is that of the
+// default cluster; federated clusters are reported as the cluster/<name>
+// service. It should be called after InitTags and DialClusters.
+func (s *Server) RunHealthChecks(ctx context.Context, wg *sync.WaitGroup) error {
+	wg.Add(1)
+
+	s.checkHealth()
+
+	go func() {
+		defer wg.Done()
+
+		t := time.NewTicker(healthCheckInterval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				// Report NOT_SERVING while shutting down.
+				s.health.Shutdown()
+				return
+			case <-t.C:
+				s.checkHealth()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// checkHealth checks the connectivity of each
+// cluster and updates the gRPC health service status.
+func (s *Server) checkHealth() {
+	for _, c := range s.clusterServers() {
+		c.checkConn()
+
+		st := healthpb.HealthCheckResponse_NOT_SERVING
+		if c.ready() {
+			st = healthpb.HealthCheckResponse_SERVING
+		}
+
+		services := []string{"", registryService, registryV2Service}
+		if c != s {
+			services = []string{"cluster/" + c.clusterName}
+		}
+
+		for _, svc := range services {
+			s.health.SetServingStatus(svc, st)
+		}
+	}
+}
+
+// checkConn checks the ZooKeeper and Kafka connectivity of the cluster.
+func (s *Server) checkConn() {
+	zk := s.ZK != nil && s.ZK.Ready()
+
+	var kafka bool
+	if s.Kafka != nil {
+		err := s.Kafka.Ping()
+		if err != nil && !s.test {
+			log.Printf("Kafka health check failed: %s\n", err)
+		}
+		kafka = err == nil
+	}
+
+	s.healthState.Lock()
+	s.healthState.zk, s.healthState.kafka = zk, kafka
+	s.healthState.Unlock()
+}
+
+// connState returns whether ZooKeeper is connected and Kafka is
+// reachable as of the latest check.
+func (s *Server) connState() (zk, kafka bool) {
+	s.healthState.Lock()
+	defer s.healthState.Unlock()
+
+	return s.healthState.zk, s.healthState.kafka
+}
+
+// ready returns whether the cluster is ready to serve requests.
+func (s *Server) ready() bool {
+	zk, kafka := s.connState()
+	return zk && (kafka || s.Kafka == nil)
+}
+
+// serveLiveness serves the liveness probe; the
+// registry is live while it's serving HTTP.
+func (s *Server) serveLiveness(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok\n"))
+}
+
+// serveReadiness serves the readiness probe of the default cluster, or
+// that of the cluster param, returning a 503 listing the unavailable
+// dependencies if the cluster isn't ready.
+func (s *Server) serveReadiness(w http.ResponseWriter, r *http.Request) {
+	s, err := s.cluster(r.URL.Query().Get("cluster"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if s.ready() {
+		w.Write([]byte("ok\n"))
+		return
+	}
+
+	zk, kafka := s.connState()
+
+	w.WriteHeader(http.StatusServiceUnavailable)
+	if !zk {
+		fmt.Fprintln(w, "zookeeper: not connected")
+	}
+
+	if s.Kafka != nil && !kafka {
+		fmt.Fprintln(w, "kafka: unreachable")
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// unreadyZK is a kafkazk.Mock that isn't connected.
+type unreadyZK struct {
+	kafkazk.Mock
+}
+
+func (zk *unreadyZK) Ready() bool {
+	return false
+}
+
+func TestHealthChecks(t *testing.T) {
+	s := testServer()
+	k := s.Kafka.(*kafkaAdminMock)
+	h := s.httpHandler(http.NotFoundHandler())
+
+	probe := func(path string) int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w.Code
+	}
+
+	status := func() healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := s.health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: registryService})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Status
+	}
+
+	// Unchecked.
+	if st := status(); st != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Expected NOT_SERVING before the first check, got %s", st)
+	}
+
+	tests := map[int]struct {
+		zk    kafkazk.Handler
+		kafka error
+		ready bool
+		code  int
+	}{
+		0: {&kafkazk.Mock{}, nil, true, http.StatusOK},
+		1: {&kafkazk.Mock{}, errors.New("connection refused"), false, http.StatusServiceUnavailable},
+		2: {&unreadyZK{}, nil, false, http.StatusServiceUnavailable},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		test := tests[i]
+		s.ZK, k.pingErr = test.zk, test.kafka
+		s.checkHealth()
+
+		expected := healthpb.HealthCheckResponse_NOT_SERVING
+		if test.ready {
+			expected = healthpb.HealthCheckResponse_SERVING
+		}
+
+		if st := status(); st != expected {
+			t.Errorf("[test %d] Expected status %s, got %s", i, expected, st)
+		}
+
+		if code := probe(readinessPath); code != test.code {
+			t.Errorf("[test %d] Expected readiness code %d, got %d", i, test.code, code)
+		}
+
+		// Always live.
+		if code := probe(livenessPath); code != http.StatusOK {
+			t.Errorf("[test %d] Expected liveness code 200, got %d", i, code)
+		}
+	}
+
+	// Kafka isn't required if not configured.
+	s.ZK, s.Kafka = &kafkazk.Mock{}, nil
+	s.checkHealth()
+
+	if status() != healthpb.HealthCheckResponse_SERVING {
+		t.Error("Expected SERVING without Kafka configured")
+	}
+}
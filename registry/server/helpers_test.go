@@ -1,6 +1,8 @@
 package server
 
 import (
+	"time"
+
 	"github.com/honeycombio/kafka-kit/kafkazk"
 )
 
@@ -12,13 +14,16 @@ var (
 
 func testServer() *Server {
 	s, _ := NewServer(Config{
-		ReadReqRate:  1,
-		WriteReqRate: 1,
-		ZKTagsPrefix: testConfig.Prefix,
-		test:         true,
+		ReadReqRate:     1,
+		WriteReqRate:    1,
+		MetadataReqRate: 1,
+		ZKTagsPrefix:    testConfig.Prefix,
+		WatchInterval:   time.Second,
+		test:            true,
 	})
 
 	s.DialZK(nil, nil, nil)
+	s.DialKafka(nil)
 
 	return s
 }
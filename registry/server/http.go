@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+	pbv2 "github.com/honeycombio/kafka-kit/registry/protos/v2"
+)
+
+// HTTP paths of the v1 and v2
+// registry API OpenAPI specs.
+const (
+	swaggerPath   = "/swagger.json"
+	swaggerV2Path = "/v2/swagger.json"
+)
+
+// httpHandler takes the gRPC gateway handler and returns the registry
+// HTTP handler, which additionally serves the OpenAPI specs, metrics and
+// liveness / readiness probes and handles cross-origin requests from the
+// configured origins.
+func (s *Server) httpHandler(gw http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", gw)
+	mux.HandleFunc(metricsPath, s.serveMetrics)
+	mux.HandleFunc(livenessPath, s.serveLiveness)
+	mux.HandleFunc(readinessPath, s.serveReadiness)
+	mux.HandleFunc(swaggerPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(pb.SwaggerJSON))
+	})
+	mux.HandleFunc(swaggerV2Path, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(pbv2.SwaggerJSON))
+	})
+
+	if len(s.corsOrigins) == 0 {
+		return mux
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !s.corsAllowed(origin) {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+
+		// Preflight requests.
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// corsAllowed returns whether cross-origin
+// requests from the origin are allowed.
+func (s *Server) corsAllowed(origin string) bool {
+	for _, o := range s.corsOrigins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	s := testServer()
+	s.corsOrigins = []string{"https://dashboard.example.com"}
+
+	gw := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ids":[1001]}`))
+	})
+
+	h := s.httpHandler(gw)
+
+	// OpenAPI spec.
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", swaggerPath, nil))
+
+	spec := struct {
+		Swagger string
+		Paths   map[string]interface{}
+	}{}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	if spec.Swagger != "2.0" {
+		t.Errorf("Expected swagger 2.0, got '%s'", spec.Swagger)
+	}
+
+	if _, exists := spec.Paths["/v1/brokers/list"]; !exists {
+		t.Error("Expected path /v1/brokers/list in the spec")
+	}
+
+	// v2 OpenAPI spec.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", swaggerV2Path, nil))
+
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, exists := spec.Paths["/v2/topics"]; !exists {
+		t.Error("Expected path /v2/topics in the v2 spec")
+	}
+
+	// Gateway requests from allowed and disallowed origins.
+	tests := map[string]string{
+		"https://dashboard.example.com": "https://dashboard.example.com",
+		"https://other.example.com":     "",
+	}
+
+	for origin, expected := range tests {
+		r := httptest.NewRequest("GET", "/v1/brokers/list", nil)
+		r.Header.Set("Origin", origin)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != expected {
+			t.Errorf("[%s] Expected allowed origin '%s', got '%s'", origin, expected, got)
+		}
+
+		if w.Body.String() != `{"ids":[1001]}` {
+			t.Errorf("[%s] Unexpected response body %s", origin, w.Body.String())
+		}
+	}
+
+	// Preflight requests.
+	r := httptest.NewRequest("OPTIONS", "/v1/topics/tag/test_topic", nil)
+	r.Header.Set("Origin", "https://dashboard.example.com")
+	r.Header.Set("Access-Control-Request-Method", "PUT")
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Methods") == "" {
+		t.Errorf("Expected a preflight response, got %d %v", w.Code, w.Header())
+	}
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
+)
+
+// kafkaAdminMock mocks KafkaAdmin.
+type kafkaAdminMock struct {
+	groups    map[string]*kafkaadmin.GroupDescription
+	committed map[string]kafkaadmin.Offsets
+	logEnd    kafkaadmin.Offsets
+	// Topics created by CreateTopics.
+	created map[string]kafkaadmin.TopicSpec
+	// Errors returned by CreateTopics
+	// calls that aren't validate only.
+	createErrs map[string]error
+	// The error returned by Ping.
+	pingErr error
+}
+
+// newKafkaAdminMock initializes a kafkaAdminMock with groups:
+// test_group, consuming test_topic partitions 0 and 1 with a
+// member assigned partitions 0-2; empty_group, with committed
+// offsets only; and connect_group, a Kafka Connect group.
+func newKafkaAdminMock() *kafkaAdminMock {
+	return &kafkaAdminMock{
+		groups: map[string]*kafkaadmin.GroupDescription{
+			"test_group": &kafkaadmin.GroupDescription{
+				GroupID:      "test_group",
+				State:        "Stable",
+				ProtocolType: "consumer",
+				Protocol:     "range",
+				Members: []kafkaadmin.GroupMember{
+					kafkaadmin.GroupMember{
+						MemberID:   "consumer-1-abc",
+						ClientID:   "consumer-1",
+						ClientHost: "/10.0.0.1",
+						Assignment: map[string][]int{"test_topic": []int{0, 1, 2}},
+					},
+				},
+			},
+			"empty_group": &kafkaadmin.GroupDescription{
+				GroupID:      "empty_group",
+				State:        "Empty",
+				ProtocolType: "consumer",
+			},
+			"connect_group": &kafkaadmin.GroupDescription{
+				GroupID:      "connect_group",
+				State:        "Stable",
+				ProtocolType: "connect",
+			},
+		},
+		committed: map[string]kafkaadmin.Offsets{
+			"test_group":  kafkaadmin.Offsets{"test_topic": map[int]int64{0: 80, 1: 100}},
+			"empty_group": kafkaadmin.Offsets{"test_topic": map[int]int64{0: 50}},
+		},
+		logEnd: kafkaadmin.Offsets{"test_topic": map[int]int64{0: 100, 1: 100, 2: 20, 3: 0, 4: 40}},
+	}
+}
+
+// ListGroups mocks ListGroups.
+func (k *kafkaAdminMock) ListGroups() ([]kafkaadmin.GroupListing, error) {
+	var groups []kafkaadmin.GroupListing
+	for _, id := range []string{"connect_group", "empty_group", "test_group"} {
+		groups = append(groups, kafkaadmin.GroupListing{
+			GroupID:      id,
+			ProtocolType: k.groups[id].ProtocolType,
+		})
+	}
+
+	return groups, nil
+}
+
+// DescribeGroup mocks DescribeGroup.
+func (k *kafkaAdminMock) DescribeGroup(group string) (*kafkaadmin.GroupDescription, error) {
+	if g, exists := k.groups[group]; exists {
+		return g, nil
+	}
+
+	return &kafkaadmin.GroupDescription{GroupID: group, State: "Dead"}, nil
+}
+
+// FetchOffsets mocks FetchOffsets.
+func (k *kafkaAdminMock) FetchOffsets(group string) (kafkaadmin.Offsets, error) {
+	if o, exists := k.committed[group]; exists {
+		return o, nil
+	}
+
+	return kafkaadmin.Offsets{}, nil
+}
+
+// ListOffsets mocks ListOffsets. Log start offsets are 10 (or
+// the log end offset, if lower), and each offset is written at
+// the timestamp of its value; timestamps beyond the log end
+// offset have no offset (-1).
+func (k *kafkaAdminMock) ListOffsets(partitions map[string][]int, timestamp int64) (kafkaadmin.Offsets, error) {
+	offsets := kafkaadmin.Offsets{}
+	for t, ps := range partitions {
+		offsets[t] = map[int]int64{}
+		for _, p := range ps {
+			end := k.logEnd[t][p]
+			start := int64(10)
+			if end < start {
+				start = end
+			}
+
+			switch {
+			case timestamp == kafkaadmin.OffsetLatest:
+				offsets[t][p] = end
+			case timestamp == kafkaadmin.OffsetEarliest, timestamp < start:
+				offsets[t][p] = start
+			case timestamp < end:
+				offsets[t][p] = timestamp
+			default:
+				offsets[t][p] = -1
+			}
+		}
+	}
+
+	return offsets, nil
+}
+
+// CommitOffsets mocks CommitOffsets.
+func (k *kafkaAdminMock) CommitOffsets(group string, offsets kafkaadmin.Offsets) error {
+	if k.committed[group] == nil {
+		k.committed[group] = kafkaadmin.Offsets{}
+	}
+
+	for t, ps := range offsets {
+		if k.committed[group][t] == nil {
+			k.committed[group][t] = map[int]int64{}
+		}
+		for p, o := range ps {
+			k.committed[group][t][p] = o
+		}
+	}
+
+	return nil
+}
+
+// CreateTopics mocks CreateTopics. Topics with log end
+// offsets already exist.
+func (k *kafkaAdminMock) CreateTopics(topics []kafkaadmin.TopicSpec, validateOnly bool) (map[string]error, error) {
+	errs := map[string]error{}
+	for _, t := range topics {
+		if _, exists := k.logEnd[t.Name]; exists {
+			errs[t.Name] = &kafkaadmin.Error{Code: 36}
+			continue
+		}
+
+		if validateOnly {
+			continue
+		}
+
+		if err, exists := k.createErrs[t.Name]; exists {
+			errs[t.Name] = err
+			continue
+		}
+
+		if k.created == nil {
+			k.created = map[string]kafkaadmin.TopicSpec{}
+		}
+
+		k.created[t.Name] = t
+	}
+
+	return errs, nil
+}
+
+// Ping mocks Ping.
+func (k *kafkaAdminMock) Ping() error {
+	return k.pingErr
+}
@@ -0,0 +1,399 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+var (
+	// ErrLifecycleNotConfigured error.
+	ErrLifecycleNotConfigured = errors.New("topic lifecycle rules not configured")
+	// ErrUnknownLifecycleRule error.
+	ErrUnknownLifecycleRule = errors.New("unknown lifecycle rule")
+)
+
+// Lifecycle rule actions.
+const (
+	lifecycleDelete       = "delete"
+	lifecycleCapRetention = "cap_retention"
+)
+
+// lifecycleRule is a topic lifecycle rule. Rules apply to the topics
+// matching the TagQuery (all topics if empty) and are either idle rules,
+// violated by topics with no messages produced within IdleFor, or max
+// retention rules, violated by topics with a retention.ms above
+// MaxRetentionMs (including unlimited or unset retentions). Violations
+// are reported and, if the rule has an Action, enforced: idle topics can
+// be deleted and retentions capped at the maximum.
+type lifecycleRule struct {
+	Name     string `json:"name"`
+	TagQuery string `json:"tag_query"`
+	// A duration, e.g. 720h.
+	IdleFor        string `json:"idle_for"`
+	MaxRetentionMs int64  `json:"max_retention_ms"`
+	// One of delete (idle rules) or cap_retention
+	// (max retention rules); report only if empty.
+	Action string `json:"action"`
+
+	idleFor time.Duration
+}
+
+// readLifecycleRules reads the lifecycleRules from the JSON array at path.
+func readLifecycleRules(path string) ([]*lifecycleRule, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*lifecycleRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("error parsing lifecycle rules: %s", err)
+	}
+
+	names := map[string]struct{}{}
+
+	for _, r := range rules {
+		if _, exists := names[r.Name]; exists || r.Name == "" {
+			return nil, fmt.Errorf("lifecycle rules must have unique names; got '%s'", r.Name)
+		}
+
+		names[r.Name] = struct{}{}
+
+		if _, err := ParseTagExpr(r.TagQuery); err != nil {
+			return nil, fmt.Errorf("lifecycle rule %s: %s", r.Name, err)
+		}
+
+		if r.IdleFor != "" {
+			if r.idleFor, err = time.ParseDuration(r.IdleFor); err != nil || r.idleFor <= 0 {
+				return nil, fmt.Errorf("lifecycle rule %s: invalid idle_for '%s'", r.Name, r.IdleFor)
+			}
+		}
+
+		switch {
+		case (r.idleFor > 0) == (r.MaxRetentionMs > 0):
+			return nil, fmt.Errorf("lifecycle rule %s: exactly one of idle_for or max_retention_ms must be specified", r.Name)
+		case r.Action == "":
+		case r.Action == lifecycleDelete && r.idleFor > 0:
+		case r.Action == lifecycleCapRetention && r.MaxRetentionMs > 0:
+		default:
+			return nil, fmt.Errorf("lifecycle rule %s: invalid action '%s'", r.Name, r.Action)
+		}
+	}
+
+	return rules, nil
+}
+
+// GetLifecycleReport evaluates the topic lifecycle rules, or the rule
+// specified in the *pb.LifecycleRequest rule field, and returns the topics
+// violating each rule.
+func (s *Server) GetLifecycleReport(ctx context.Context, req *pb.LifecycleRequest) (*pb.LifecycleReport, error) {
+	if err := s.ValidateRequest(ctx, req, metadataRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	vs, err := s.lifecycleViolations(req.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.LifecycleReport{Violations: vs}, nil
+}
+
+// EnforceLifecycle evaluates the topic lifecycle rules as with
+// GetLifecycleReport and takes the action of each violated rule, if any.
+// Idle topics are only deleted if they pass the topic deletion checks (see
+// DeleteTopic); topics failing checks are reported as skipped. A dry run reports the violations without taking
+// any action.
+func (s *Server) EnforceLifecycle(ctx context.Context, req *pb.LifecycleRequest) (*pb.LifecycleReport, error) {
+	if err := s.ValidateRequest(ctx, req, writeRequest); err != nil {
+		return nil, err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	vs, err := s.lifecycleViolations(req.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.DryRun {
+		s.enforceLifecycle(ctx, vs)
+	}
+
+	return &pb.LifecycleReport{Violations: vs}, nil
+}
+
+// lifecycleViolations returns the violations of the named
+// lifecycle rule, or all rules if empty, sorted by topic.
+func (s *Server) lifecycleViolations(name string) ([]*pb.LifecycleViolation, error) {
+	if s.lifecycleRules == nil {
+		return nil, ErrLifecycleNotConfigured
+	}
+
+	var rules []*lifecycleRule
+	for _, r := range s.lifecycleRules {
+		if name == "" || r.Name == name {
+			rules = append(rules, r)
+		}
+	}
+
+	if len(rules) == 0 {
+		return nil, ErrUnknownLifecycleRule
+	}
+
+	var vs []*pb.LifecycleViolation
+
+	for _, r := range rules {
+		topics, err := s.fetchTopicSet(&pb.TopicRequest{TagQuery: r.TagQuery})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range topics.Names() {
+			var desc string
+			var err error
+
+			if r.idleFor > 0 {
+				desc, err = s.checkIdle(r, t)
+			} else {
+				desc, err = s.checkRetention(r, t)
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("lifecycle rule %s: %s", r.Name, err)
+			}
+
+			if desc != "" {
+				vs = append(vs, &pb.LifecycleViolation{Rule: r.Name, Topic: t, Description: desc, Action: r.Action})
+			}
+		}
+	}
+
+	sort.SliceStable(vs, func(i, j int) bool { return vs[i].Topic < vs[j].Topic })
+
+	return vs, nil
+}
+
+// checkIdle returns a description of the idle rule
+// violation of the topic, or an empty string if none.
+func (s *Server) checkIdle(r *lifecycleRule, t string) (string, error) {
+	if s.Kafka == nil {
+		return "", errors.New("idle rules require Kafka bootstrap servers")
+	}
+
+	ts, err := s.ZK.GetTopicState(t)
+	if err != nil {
+		return "", err
+	}
+
+	produced, err := s.producedSince(t, ts, time.Now().Add(-r.idleFor))
+	if err != nil || produced {
+		return "", err
+	}
+
+	return fmt.Sprintf("no messages produced within the last %s", r.idleFor), nil
+}
+
+// checkRetention returns a description of the max retention
+// rule violation of the topic, or an empty string if none.
+func (s *Server) checkRetention(r *lifecycleRule, t string) (string, error) {
+	tc, err := s.topicConfig(t)
+	if err != nil {
+		return "", err
+	}
+
+	v, set := tc.Configs["retention.ms"]
+	if !set {
+		return "retention.ms is unset (the broker default applies)", nil
+	}
+
+	ms, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || ms < 0 || ms > r.MaxRetentionMs {
+		return fmt.Sprintf("retention.ms %s exceeds the maximum of %d", v, r.MaxRetentionMs), nil
+	}
+
+	return "", nil
+}
+
+// enforceLifecycle takes the action of each lifecycle
+// violation, updating its enforced and skipped fields.
+func (s *Server) enforceLifecycle(ctx context.Context, vs []*pb.LifecycleViolation) {
+	// A topic may violate several rules; deleted
+	// topics (by rule) need no further action.
+	deleted := map[string]string{}
+
+	for _, v := range vs {
+		if v.Action == "" {
+			continue
+		}
+
+		if rule, ok := deleted[v.Topic]; ok {
+			v.Skipped = fmt.Sprintf("topic deleted by lifecycle rule %s", rule)
+			continue
+		}
+
+		var err error
+
+		switch v.Action {
+		case lifecycleDelete:
+			if err = s.lifecycleDeleteTopic(ctx, v); err == nil {
+				deleted[v.Topic] = v.Rule
+			}
+		case lifecycleCapRetention:
+			err = s.lifecycleCapRetention(ctx, v)
+		}
+
+		if err != nil {
+			v.Skipped = err.Error()
+		}
+	}
+}
+
+// lifecycleDeleteTopic deletes the topic of the idle rule
+// violation if it passes the topic deletion checks.
+func (s *Server) lifecycleDeleteTopic(ctx context.Context, v *pb.LifecycleViolation) error {
+	ts, err := s.ZK.GetTopicState(v.Topic)
+	if err != nil {
+		return err
+	}
+
+	failed, err := s.topicDeletionChecks(v.Topic, ts)
+	if err != nil {
+		return err
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("topic deletion checks failed: %s", strings.Join(failed, "; "))
+	}
+
+	change := fmt.Sprintf("topic %s marked for deletion by lifecycle rule %s: %s", v.Topic, v.Rule, v.Description)
+	if err := s.deleteTopic(ctx, v.Topic, ts, change); err != nil {
+		return err
+	}
+
+	v.Enforced = true
+
+	return nil
+}
+
+// lifecycleCapRetention sets the retention.ms of the topic of the
+// max retention rule violation to the rule maximum.
+func (s *Server) lifecycleCapRetention(ctx context.Context, v *pb.LifecycleViolation) error {
+	var rule *lifecycleRule
+	for _, r := range s.lifecycleRules {
+		if r.Name == v.Rule {
+			rule = r
+		}
+	}
+
+	current, err := s.topicConfig(v.Topic)
+	if err != nil {
+		return err
+	}
+
+	max := strconv.FormatInt(rule.MaxRetentionMs, 10)
+	config := kafkazk.KafkaConfig{Type: "topic", Name: v.Topic, Configs: [][2]string{{"retention.ms", max}}}
+
+	if _, err := s.ZK.UpdateKafkaConfig(config); err != nil {
+		return err
+	}
+
+	updated, err := s.topicConfig(v.Topic)
+	if err != nil {
+		return err
+	}
+
+	s.recordChange(ctx, eventTopicConfigs, "topics/"+v.Topic, fmt.Sprintf("topic %s configs updated by lifecycle rule %s: retention.ms: '%s' -> '%s'",
+		v.Topic, v.Rule, current.Configs["retention.ms"], max), current.Configs, updated.Configs)
+
+	v.Enforced = true
+
+	return nil
+}
+
+// RunLifecycle enforces the topic lifecycle rules of each cluster every
+// lifecycleInterval, logging each violation and the action taken. With
+// leader election, rules are enforced while this registry is the leader.
+// It's a no-op if lifecycle rules or the interval aren't configured.
+func (s *Server) RunLifecycle(ctx context.Context, wg *sync.WaitGroup) error {
+	if s.lifecycleRules == nil || s.lifecycleInterval == 0 {
+		return nil
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		t := time.NewTicker(s.lifecycleInterval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				// Rules are only enforced by the leader.
+				if !s.isLeader() {
+					continue
+				}
+
+				for _, c := range s.clusterServers() {
+					c.runLifecycle(ctx)
+				}
+			}
+		}
+	}()
+
+	log.Printf("Topic lifecycle rules enforced every %s\n", s.lifecycleInterval)
+
+	return nil
+}
+
+// runLifecycle evaluates and enforces the lifecycle rules once.
+func (s *Server) runLifecycle(ctx context.Context) {
+	var cluster string
+	if s.clusterName != "" {
+		cluster = fmt.Sprintf(" cluster:%s", s.clusterName)
+	}
+
+	vs, err := s.lifecycleViolations("")
+	if err != nil {
+		log.Printf("[lifecycle]%s error evaluating rules: %s\n", cluster, err)
+		return
+	}
+
+	s.enforceLifecycle(ctx, vs)
+
+	for _, v := range vs {
+		var action string
+		switch {
+		case v.Enforced:
+			action = fmt.Sprintf(" (%s)", v.Action)
+		case v.Skipped != "":
+			action = fmt.Sprintf(" (%s skipped: %s)", v.Action, v.Skipped)
+		}
+
+		log.Printf("[lifecycle]%s rule %s: topic %s: %s%s\n", cluster, v.Rule, v.Topic, v.Description, action)
+	}
+}
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+// lifecycleZK stores topic configs and
+// records topics marked for deletion.
+type lifecycleZK struct {
+	topicConfigZK
+	deleted []string
+}
+
+func (zk *lifecycleZK) DeleteTopic(t string) error {
+	zk.deleted = append(zk.deleted, t)
+	return nil
+}
+
+func testLifecycleRules(t *testing.T, data string) ([]*lifecycleRule, error) {
+	f, err := ioutil.TempFile("", "lifecycle")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+
+	f.WriteString(data)
+	f.Close()
+
+	return readLifecycleRules(f.Name())
+}
+
+func testLifecycleServer(t *testing.T) (*Server, *lifecycleZK) {
+	rules, err := testLifecycleRules(t, `[
+		{"name": "idle", "idle_for": "720h"},
+		{"name": "ephemeral", "tag_query": "ephemeral=true", "idle_for": "168h", "action": "delete"},
+		{"name": "retention", "tag_query": "name=test_topic*", "max_retention_ms": 604800000, "action": "cap_retention"}
+	]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := testServer()
+	s.lifecycleRules = rules
+
+	zk := &lifecycleZK{topicConfigZK: topicConfigZK{configs: map[string]map[string]string{
+		"test_topic":  {"retention.ms": "86400000"},
+		"test_topic2": {"retention.ms": "-1"},
+	}}}
+	s.ZK = zk
+
+	return s, zk
+}
+
+func TestReadLifecycleRules(t *testing.T) {
+	tests := map[int]string{
+		0: `[{"name": "idle", "idle_for": "24h"}, {"name": "idle", "idle_for": "48h"}]`,
+		1: `[{"name": "idle", "idle_for": "1 day"}]`,
+		2: `[{"name": "idle", "idle_for": "24h", "max_retention_ms": 1000}]`,
+		3: `[{"name": "idle"}]`,
+		4: `[{"name": "idle", "idle_for": "24h", "action": "cap_retention"}]`,
+		5: `[{"name": "idle", "idle_for": "24h", "tag_query": "team AND"}]`,
+	}
+
+	expected := map[int]string{
+		0: "lifecycle rules must have unique names; got 'idle'",
+		1: "lifecycle rule idle: invalid idle_for '1 day'",
+		2: "lifecycle rule idle: exactly one of idle_for or max_retention_ms must be specified",
+		3: "lifecycle rule idle: exactly one of idle_for or max_retention_ms must be specified",
+		4: "lifecycle rule idle: invalid action 'cap_retention'",
+		5: "lifecycle rule idle: invalid tag expression: unexpected end of expression",
+	}
+
+	for i, data := range tests {
+		if _, err := testLifecycleRules(t, data); err == nil || err.Error() != expected[i] {
+			t.Errorf("[test %d] Expected error '%s', got '%v'", i, expected[i], err)
+		}
+	}
+}
+
+func TestGetLifecycleReport(t *testing.T) {
+	s, zk := testLifecycleServer(t)
+	s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: "test_topic2"}, TagSet{"ephemeral": "true"})
+
+	resp, err := s.GetLifecycleReport(context.Background(), &pb.LifecycleRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both topics are idle in the mock.
+	expected := []string{
+		"idle test_topic: no messages produced within the last 720h0m0s",
+		"idle test_topic2: no messages produced within the last 720h0m0s",
+		"ephemeral test_topic2: no messages produced within the last 168h0m0s",
+		"retention test_topic2: retention.ms -1 exceeds the maximum of 604800000",
+	}
+
+	var got []string
+	for _, v := range resp.Violations {
+		got = append(got, v.Rule+" "+v.Topic+": "+v.Description)
+		if v.Enforced {
+			t.Errorf("Expected no actions taken, got %v", v)
+		}
+	}
+
+	if !stringsEqual(expected, got) {
+		t.Errorf("Expected violations %v, got %v", expected, got)
+	}
+
+	if len(zk.deleted) != 0 || zk.configs["test_topic2"]["retention.ms"] != "-1" {
+		t.Error("Expected no changes")
+	}
+
+	resp, err = s.GetLifecycleReport(context.Background(), &pb.LifecycleRequest{Rule: "retention"})
+	if err != nil || len(resp.Violations) != 1 {
+		t.Errorf("Expected 1 retention violation, got %v (error %v)", resp, err)
+	}
+
+	if _, err := s.GetLifecycleReport(context.Background(), &pb.LifecycleRequest{Rule: "other"}); err != ErrUnknownLifecycleRule {
+		t.Errorf("Expected error '%s', got '%v'", ErrUnknownLifecycleRule, err)
+	}
+
+	s.lifecycleRules = nil
+	if _, err := s.GetLifecycleReport(context.Background(), &pb.LifecycleRequest{}); err != ErrLifecycleNotConfigured {
+		t.Errorf("Expected error '%s', got '%v'", ErrLifecycleNotConfigured, err)
+	}
+}
+
+func TestEnforceLifecycle(t *testing.T) {
+	s, zk := testLifecycleServer(t)
+	s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: "test_topic"}, TagSet{"ephemeral": "true"})
+	s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: "test_topic2"}, TagSet{"ephemeral": "true"})
+
+	// Dry runs take no action.
+	if _, err := s.EnforceLifecycle(context.Background(), &pb.LifecycleRequest{DryRun: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(zk.deleted) != 0 {
+		t.Errorf("Expected no topics deleted, got %v", zk.deleted)
+	}
+
+	resp, err := s.EnforceLifecycle(context.Background(), &pb.LifecycleRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// test_topic has an active consumer group; test_topic2 is deleted
+	// before its retention is capped.
+	expected := map[string]string{
+		"ephemeral test_topic":  "topic deletion checks failed: consumed by active consumer groups: test_group",
+		"ephemeral test_topic2": "",
+		"retention test_topic2": "topic deleted by lifecycle rule ephemeral",
+	}
+
+	for _, v := range resp.Violations {
+		skipped, exists := expected[v.Rule+" "+v.Topic]
+		if !exists {
+			continue
+		}
+
+		if v.Skipped != skipped || v.Enforced != (skipped == "") {
+			t.Errorf("Unexpected violation %v", v)
+		}
+	}
+
+	if !stringsEqual(zk.deleted, []string{"test_topic2"}) {
+		t.Errorf("Expected test_topic2 deleted, got %v", zk.deleted)
+	}
+
+	// Retentions are capped.
+	zk.configs["test_topic"]["retention.ms"] = "1209600000"
+
+	resp, err = s.EnforceLifecycle(context.Background(), &pb.LifecycleRequest{Rule: "retention"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The mock still lists the deleted test_topic2.
+	for _, v := range resp.Violations {
+		if !v.Enforced {
+			t.Errorf("Expected the action taken for %v", v)
+		}
+	}
+
+	if v := zk.configs["test_topic"]["retention.ms"]; v != "604800000" {
+		t.Errorf("Expected the test_topic retention.ms capped at 604800000, got %s", v)
+	}
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/honeycombio/kafka-kit/kafkazk"
+)
+
+var (
+	// ErrInvalidMaintenanceTag error.
+	ErrInvalidMaintenanceTag = errors.New("maintenance tag value must be true or false")
+)
+
+// maintenanceTag is the broker tag key storing the
+// broker maintenance flag; brokers tagged
+// maintenance:true are in maintenance.
+const maintenanceTag = "maintenance"
+
+// maintenanceBrokers returns the sorted IDs of the
+// brokers in the BrokerMetaMap that are in maintenance.
+func (s *Server) maintenanceBrokers(bm kafkazk.BrokerMetaMap) ([]int, error) {
+	var ids []int
+
+	for id := range bm {
+		tags, err := s.Tags.EffectiveTags(KafkaObject{Type: "broker", ID: fmt.Sprintf("%d", id)})
+		if err != nil {
+			return nil, fmt.Errorf("error fetching tags of broker %d: %s", id, err)
+		}
+
+		if tags[maintenanceTag] == "true" {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Ints(ids)
+
+	return ids, nil
+}
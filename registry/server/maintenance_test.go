@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+func TestBrokerMaintenance(t *testing.T) {
+	s := testServer()
+
+	for _, id := range []uint32{1001, 1003} {
+		_, err := s.TagBroker(context.Background(), &pb.BrokerRequest{Id: id, Tag: []string{"maintenance:true"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := s.GetBrokers(context.Background(), &pb.BrokerRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for id, b := range resp.Brokers {
+		if b.Maintenance != (id == 1001 || id == 1003) {
+			t.Errorf("Unexpected maintenance flag %t for broker %d", b.Maintenance, id)
+		}
+	}
+
+	// Replace 1004.
+	plan, err := s.PlanReassignment(context.Background(), &pb.ReassignmentRequest{
+		Operation: "rebuild",
+		Topics:    []string{"test_topic"},
+		Brokers:   []int32{1001, 1002, 1003, 1005},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Brokers in maintenance keep their
+	// replicas but receive no new ones.
+	for _, p := range plan.Partitions {
+		current := map[uint32]struct{}{}
+		for _, id := range p.Replicas {
+			current[id] = struct{}{}
+		}
+
+		for _, id := range p.PlannedReplicas {
+			if _, exists := current[id]; !exists && (id == 1001 || id == 1003) {
+				t.Errorf("Unexpected new replica %d for partition %d", id, p.Partition)
+			}
+		}
+	}
+}
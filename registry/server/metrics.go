@@ -0,0 +1,230 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// metricsPath is the HTTP path of the registry
+// metrics, in the Prometheus text format.
+const metricsPath = "/metrics"
+
+// requestDurationBuckets are the upper bounds (in seconds)
+// of the request duration histogram buckets.
+var requestDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// registryMetrics holds the registry self-metrics.
+type registryMetrics struct {
+	sync.Mutex
+	// Request durations by gRPC method and status code.
+	requests map[requestLabels]*histogram
+	// Cache lookups by cache and result.
+	cache map[cacheLabels]uint64
+}
+
+type requestLabels struct {
+	method string
+	code   string
+}
+
+type cacheLabels struct {
+	cache  string
+	result string
+}
+
+// gauge is a boolean Prometheus gauge.
+type gauge struct {
+	name   string
+	labels string
+	value  bool
+}
+
+var gaugeHelp = map[string]string{
+	"registry_zookeeper_connected": "Whether the cluster ZooKeeper is connected.",
+	"registry_kafka_connected":     "Whether the cluster Kafka is reachable via the Admin API.",
+	"registry_ready":               "Whether the cluster is ready to serve requests.",
+	"registry_leader":              "Whether the registry is the elected leader; only set with leader election.",
+}
+
+// histogram is a Prometheus histogram; counts
+// are per bucket rather than cumulative.
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func newRegistryMetrics() *registryMetrics {
+	return &registryMetrics{
+		requests: map[requestLabels]*histogram{},
+		cache:    map[cacheLabels]uint64{},
+	}
+}
+
+// observeRequest records the duration of a request
+// to the gRPC method that returned err.
+func (m *registryMetrics) observeRequest(method string, err error, d time.Duration) {
+	l := requestLabels{method: method, code: status.Code(err).String()}
+
+	m.Lock()
+	defer m.Unlock()
+
+	h, exists := m.requests[l]
+	if !exists {
+		h = &histogram{counts: make([]uint64, len(requestDurationBuckets))}
+		m.requests[l] = h
+	}
+
+	secs := d.Seconds()
+	for i, le := range requestDurationBuckets {
+		if secs <= le {
+			h.counts[i]++
+			break
+		}
+	}
+
+	h.count++
+	h.sum += secs
+}
+
+// observeCache records a cache lookup.
+func (m *registryMetrics) observeCache(cache string, hit bool) {
+	l := cacheLabels{cache: cache, result: "miss"}
+	if hit {
+		l.result = "hit"
+	}
+
+	m.Lock()
+	m.cache[l]++
+	m.Unlock()
+}
+
+// unaryMetrics is a gRPC interceptor that records request durations.
+func (s *Server) unaryMetrics(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	s.metrics.observeRequest(info.FullMethod, err, time.Since(start))
+
+	return resp, err
+}
+
+// streamMetrics is the streaming equivalent of unaryMetrics; the
+// durations of streams are those of the entire stream.
+func (s *Server) streamMetrics(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	s.metrics.observeRequest(info.FullMethod, err, time.Since(start))
+
+	return err
+}
+
+// serveMetrics serves the registry metrics in the Prometheus text format.
+func (s *Server) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	s.writeMetrics(w)
+}
+
+// writeMetrics writes the request, connection state
+// and cache metrics in the Prometheus text format.
+func (s *Server) writeMetrics(w io.Writer) {
+	m := s.metrics
+	m.Lock()
+	defer m.Unlock()
+
+	// Requests.
+	var reqs []requestLabels
+	for l := range m.requests {
+		reqs = append(reqs, l)
+	}
+
+	sort.Slice(reqs, func(i, j int) bool {
+		if reqs[i].method != reqs[j].method {
+			return reqs[i].method < reqs[j].method
+		}
+		return reqs[i].code < reqs[j].code
+	})
+
+	name := "registry_request_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s Duration of gRPC and HTTP API requests.\n", name)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+
+	for _, l := range reqs {
+		h := m.requests[l]
+		labels := fmt.Sprintf("method=%q,code=%q", l.method, l.code)
+
+		var cumulative uint64
+		for i, le := range requestDurationBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "%s_bucket{%s,le=%q} %d\n", name, labels, strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+		}
+
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+		fmt.Fprintf(w, "%s_sum{%s} %g\n", name, labels, h.sum)
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, h.count)
+	}
+
+	// Connection states.
+	var gauges []gauge
+	for _, c := range s.clusterServers() {
+		zk, kafka := c.connState()
+		labels := fmt.Sprintf("cluster=%q", c.clusterName)
+
+		gauges = append(gauges,
+			gauge{"registry_zookeeper_connected", labels, zk},
+			gauge{"registry_ready", labels, c.ready()},
+		)
+
+		if c.Kafka != nil {
+			gauges = append(gauges, gauge{"registry_kafka_connected", labels, kafka})
+		}
+	}
+
+	if s.election != nil {
+		gauges = append(gauges, gauge{"registry_leader", "", s.isLeader()})
+	}
+
+	sort.SliceStable(gauges, func(i, j int) bool { return gauges[i].name < gauges[j].name })
+
+	for i, g := range gauges {
+		if i == 0 || gauges[i-1].name != g.name {
+			fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", g.name, gaugeHelp[g.name], g.name)
+		}
+
+		var v int
+		if g.value {
+			v = 1
+		}
+
+		fmt.Fprintf(w, "%s{%s} %d\n", g.name, g.labels, v)
+	}
+
+	// Caches.
+	var lookups []cacheLabels
+	for l := range m.cache {
+		lookups = append(lookups, l)
+	}
+
+	sort.Slice(lookups, func(i, j int) bool {
+		if lookups[i].cache != lookups[j].cache {
+			return lookups[i].cache < lookups[j].cache
+		}
+		return lookups[i].result < lookups[j].result
+	})
+
+	name = "registry_cache_lookups_total"
+	fmt.Fprintf(w, "# HELP %s Metadata cache lookups by result (hit or miss).\n", name)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+
+	for _, l := range lookups {
+		fmt.Fprintf(w, "%s{cache=%q,result=%q} %d\n", name, l.cache, l.result, m.cache[l])
+	}
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestMetrics(t *testing.T) {
+	s := testServer()
+	s.checkHealth()
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/registry.Registry/GetBrokers"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, ErrBrokerNotExist
+	}
+
+	s.unaryMetrics(context.Background(), nil, info, handler)
+	s.metrics.observeRequest(info.FullMethod, nil, 30*time.Millisecond)
+	s.metrics.observeRequest(info.FullMethod, nil, 20*time.Second)
+	s.metrics.observeCache(topicStateCache, true)
+
+	var buf bytes.Buffer
+	s.writeMetrics(&buf)
+	out := buf.String()
+
+	expected := []string{
+		`registry_request_duration_seconds_bucket{method="/registry.Registry/GetBrokers",code="OK",le="0.025"} 0`,
+		`registry_request_duration_seconds_bucket{method="/registry.Registry/GetBrokers",code="OK",le="0.05"} 1`,
+		`registry_request_duration_seconds_bucket{method="/registry.Registry/GetBrokers",code="OK",le="10"} 1`,
+		`registry_request_duration_seconds_bucket{method="/registry.Registry/GetBrokers",code="OK",le="+Inf"} 2`,
+		`registry_request_duration_seconds_count{method="/registry.Registry/GetBrokers",code="OK"} 2`,
+		`registry_request_duration_seconds_count{method="/registry.Registry/GetBrokers",code="Unknown"} 1`,
+		`registry_zookeeper_connected{cluster=""} 1`,
+		`registry_kafka_connected{cluster=""} 1`,
+		`registry_ready{cluster=""} 1`,
+		`registry_cache_lookups_total{cache="topic_state",result="hit"} 1`,
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Expected metric '%s', got:\n%s", line, out)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strconv"
+)
+
+var (
+	// ErrInvalidPageToken error.
+	ErrInvalidPageToken = errors.New("invalid page token")
+)
+
+// encodePageToken returns the page token of the page following the
+// last name or ID; tokens are opaque to clients.
+func encodePageToken(last string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(last))
+}
+
+func decodePageToken(token string) (string, error) {
+	last, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(last) == 0 {
+		return "", ErrInvalidPageToken
+	}
+
+	return string(last), nil
+}
+
+// pageEnd returns the end index of the page of at most
+// limit (unlimited if 0) of n items starting at start.
+func pageEnd(n int, start int, limit uint32) int {
+	if limit > 0 && start+int(limit) < n {
+		return start + int(limit)
+	}
+
+	return n
+}
+
+// pageNames returns the page of the sorted names following the
+// page token (from the first name if empty) of at most limit names
+// (all names if 0), and the page token of the next page; empty if
+// it's the last page.
+func pageNames(names []string, limit uint32, token string) ([]string, string, error) {
+	var start int
+
+	if token != "" {
+		last, err := decodePageToken(token)
+		if err != nil {
+			return nil, "", err
+		}
+
+		start = sort.Search(len(names), func(i int) bool { return names[i] > last })
+	}
+
+	end := pageEnd(len(names), start, limit)
+
+	var next string
+	if end < len(names) {
+		next = encodePageToken(names[end-1])
+	}
+
+	return names[start:end], next, nil
+}
+
+// pageIDs is the pageNames equivalent for sorted broker IDs.
+func pageIDs(ids []uint32, limit uint32, token string) ([]uint32, string, error) {
+	var start int
+
+	if token != "" {
+		last, err := decodePageToken(token)
+		if err != nil {
+			return nil, "", err
+		}
+
+		id, err := strconv.ParseUint(last, 10, 32)
+		if err != nil {
+			return nil, "", ErrInvalidPageToken
+		}
+
+		start = sort.Search(len(ids), func(i int) bool { return ids[i] > uint32(id) })
+	}
+
+	end := pageEnd(len(ids), start, limit)
+
+	var next string
+	if end < len(ids) {
+		next = encodePageToken(strconv.FormatUint(uint64(ids[end-1]), 10))
+	}
+
+	return ids[start:end], next, nil
+}
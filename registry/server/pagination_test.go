@@ -0,0 +1,66 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestPageNames(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e"}
+
+	type page struct {
+		limit uint32
+		after string
+	}
+
+	tests := map[int]page{
+		0: page{},
+		1: page{limit: 2},
+		2: page{limit: 2, after: "b"},
+		3: page{limit: 2, after: "d"},
+		4: page{limit: 5},
+		// The last name of a page needn't still exist.
+		5: page{limit: 1, after: "bb"},
+		6: page{after: "e"},
+	}
+
+	expected := map[int][]string{
+		0: []string{"a", "b", "c", "d", "e"},
+		1: []string{"a", "b"},
+		2: []string{"c", "d"},
+		3: []string{"e"},
+		4: []string{"a", "b", "c", "d", "e"},
+		5: []string{"c"},
+		6: []string{},
+	}
+
+	expectedNext := map[int]string{1: "b", 2: "d", 5: "c"}
+
+	for i, p := range tests {
+		var token string
+		if p.after != "" {
+			token = encodePageToken(p.after)
+		}
+
+		page, next, err := pageNames(names, p.limit, token)
+		if err != nil {
+			t.Fatalf("[test %d] Unexpected error: %s", i, err)
+		}
+
+		if !stringsEqual(expected[i], page) {
+			t.Errorf("[test %d] Expected page %v, got %v", i, expected[i], page)
+		}
+
+		var expectedToken string
+		if n := expectedNext[i]; n != "" {
+			expectedToken = encodePageToken(n)
+		}
+
+		if next != expectedToken {
+			t.Errorf("[test %d] Expected next page token '%s', got '%s'", i, expectedToken, next)
+		}
+	}
+
+	if _, _, err := pageNames(names, 0, "not base64!"); err != ErrInvalidPageToken {
+		t.Errorf("Expected error '%s', got '%v'", ErrInvalidPageToken, err)
+	}
+}
@@ -0,0 +1,266 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Topic ownership metadata is
+// stored as these tag keys.
+const (
+	ownerTag = "owner"
+	teamTag  = "team"
+)
+
+// Policy violation types.
+const (
+	policyNamePattern          = "NAME_PATTERN"
+	policyMinReplication       = "MIN_REPLICATION"
+	policyMaxPartitionsPerTeam = "MAX_PARTITIONS_PER_TEAM"
+	policyRequiredTag          = "REQUIRED_TAG"
+)
+
+// topicPolicy holds the policies topics are held to when created
+// or when their tags are changed. Zero value fields are not enforced.
+type topicPolicy struct {
+	// A regular expression topic names must match.
+	NamePattern    string `json:"name_pattern"`
+	MinReplication uint32 `json:"min_replication"`
+	// The maximum total partitions of the
+	// topics owned by any one team.
+	MaxPartitionsPerTeam uint32 `json:"max_partitions_per_team"`
+	// Tag keys every topic must have, e.g. owner.
+	RequiredTags []string `json:"required_tags"`
+
+	namePattern *regexp.Regexp
+}
+
+// policyViolation describes a topic that violates a topicPolicy.
+type policyViolation struct {
+	policy      string
+	topic       string
+	description string
+}
+
+// readTopicPolicy reads a topicPolicy from the JSON object at path.
+func readTopicPolicy(path string) (*topicPolicy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &topicPolicy{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("error parsing topic policy: %s", err)
+	}
+
+	if p.NamePattern != "" {
+		if p.namePattern, err = regexp.Compile(p.NamePattern); err != nil {
+			return nil, fmt.Errorf("invalid topic policy name_pattern: %s", err)
+		}
+	}
+
+	reserved := GetReservedFields()["topic"]
+	for _, k := range p.RequiredTags {
+		if _, r := reserved[k]; r || k == "" || strings.Contains(k, ":") {
+			return nil, fmt.Errorf("invalid topic policy required tag '%s'", k)
+		}
+	}
+
+	return p, nil
+}
+
+// check returns the violations of the topic name,
+// replication and required tags policies.
+func (p *topicPolicy) check(t *pb.Topic) []policyViolation {
+	var vs []policyViolation
+
+	if p.namePattern != nil && !p.namePattern.MatchString(t.Name) {
+		vs = append(vs, policyViolation{
+			policy:      policyNamePattern,
+			topic:       t.Name,
+			description: fmt.Sprintf("topic name must match %s", p.NamePattern),
+		})
+	}
+
+	if t.Replication < p.MinReplication {
+		vs = append(vs, policyViolation{
+			policy:      policyMinReplication,
+			topic:       t.Name,
+			description: fmt.Sprintf("replication factor %d is below the minimum of %d", t.Replication, p.MinReplication),
+		})
+	}
+
+	for _, k := range p.RequiredTags {
+		if t.Tags[k] == "" {
+			vs = append(vs, policyViolation{
+				policy:      policyRequiredTag,
+				topic:       t.Name,
+				description: fmt.Sprintf("tag '%s' is required", k),
+			})
+		}
+	}
+
+	return vs
+}
+
+// topicPolicyViolations returns the policy violations of the topics, as
+// if they were created or updated in place of any existing topics of the
+// same name.
+func (s *Server) topicPolicyViolations(topics []*pb.Topic) ([]policyViolation, error) {
+	if s.policy == nil {
+		return nil, nil
+	}
+
+	var vs []policyViolation
+	for _, t := range topics {
+		vs = append(vs, s.policy.check(t)...)
+	}
+
+	if s.policy.MaxPartitionsPerTeam == 0 {
+		return vs, nil
+	}
+
+	// Sum the partitions owned by each
+	// team, including those of the topics.
+	existing, err := s.fetchTopicSet(&pb.TopicRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range topics {
+		existing[t.Name] = t
+	}
+
+	partitions := map[string]uint32{}
+	for _, t := range existing {
+		if team := t.Tags[teamTag]; team != "" {
+			partitions[team] += t.Partitions
+		}
+	}
+
+	for _, t := range topics {
+		team := t.Tags[teamTag]
+		if team == "" || partitions[team] <= s.policy.MaxPartitionsPerTeam {
+			continue
+		}
+
+		vs = append(vs, policyViolation{
+			policy: policyMaxPartitionsPerTeam,
+			topic:  t.Name,
+			description: fmt.Sprintf("team %s would own %d partitions; the maximum is %d",
+				team, partitions[team], s.policy.MaxPartitionsPerTeam),
+		})
+	}
+
+	return vs, nil
+}
+
+// checkTopicTagsPolicy returns a policy error if changing the tags of the
+// named topic from before to after introduces policy violations. Existing
+// violations, e.g. of topics that predate the policy, don't prevent changes.
+func (s *Server) checkTopicTagsPolicy(name string, before, after TagSet) error {
+	if s.policy == nil {
+		return nil
+	}
+
+	state, err := s.ZK.GetTopicState(name)
+	if err != nil {
+		return err
+	}
+
+	topic := func(ts TagSet) *pb.Topic {
+		return &pb.Topic{
+			Name:        name,
+			Partitions:  uint32(len(state.Partitions)),
+			Replication: uint32(len(state.Partitions["0"])),
+			Tags:        s.Tags.withInherited(KafkaObject{Type: "topic", ID: name}, ts),
+		}
+	}
+
+	was, err := s.topicPolicyViolations([]*pb.Topic{topic(before)})
+	if err != nil {
+		return err
+	}
+
+	is, err := s.topicPolicyViolations([]*pb.Topic{topic(after)})
+	if err != nil {
+		return err
+	}
+
+	existing := map[policyViolation]struct{}{}
+	for _, v := range was {
+		existing[v] = struct{}{}
+	}
+
+	var introduced []policyViolation
+	for _, v := range is {
+		if _, ok := existing[v]; !ok {
+			introduced = append(introduced, v)
+		}
+	}
+
+	return policyError(introduced)
+}
+
+// policyError returns a FailedPrecondition error for the policy
+// violations, if any, with a PreconditionFailure detail listing each.
+func policyError(vs []policyViolation) error {
+	if len(vs) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(vs, func(i, j int) bool { return vs[i].topic < vs[j].topic })
+
+	var msgs []string
+	details := &errdetails.PreconditionFailure{}
+
+	for _, v := range vs {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", v.topic, v.description))
+		details.Violations = append(details.Violations, &errdetails.PreconditionFailure_Violation{
+			Type:        v.policy,
+			Subject:     "topics/" + v.topic,
+			Description: v.description,
+		})
+	}
+
+	st := status.New(codes.FailedPrecondition, fmt.Sprintf("topic policy violations: %s", strings.Join(msgs, "; ")))
+	if withDetails, err := st.WithDetails(details); err == nil {
+		st = withDetails
+	}
+
+	return st.Err()
+}
+
+// ownershipTags returns a copy of the tags with
+// the owner and team tags set, if specified.
+func ownershipTags(tags map[string]string, owner, team string) map[string]string {
+	if owner == "" && team == "" {
+		return tags
+	}
+
+	ts := map[string]string{}
+	for k, v := range tags {
+		ts[k] = v
+	}
+
+	if owner != "" {
+		ts[ownerTag] = owner
+	}
+
+	if team != "" {
+		ts[teamTag] = team
+	}
+
+	return ts
+}
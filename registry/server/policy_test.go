@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReadTopicPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	tests := map[int]string{
+		0: `{"name_pattern": "^[a-z]+\\.", "min_replication": 3, "required_tags": ["owner"]}`,
+		1: `{"name_pattern": "(["}`,
+		2: `{"required_tags": ["partitions"]}`,
+		3: `[]`,
+	}
+
+	expected := map[int]bool{0: true, 1: false, 2: false, 3: false}
+
+	for i, data := range tests {
+		path := filepath.Join(dir, "policy.json")
+		if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		p, err := readTopicPolicy(path)
+		if (err == nil) != expected[i] {
+			t.Errorf("[test %d] Unexpected error '%v'", i, err)
+		}
+
+		if i == 0 && (p.namePattern == nil || p.MinReplication != 3) {
+			t.Errorf("Unexpected policy %+v", p)
+		}
+	}
+}
+
+func TestCreateTopicsPolicy(t *testing.T) {
+	s := testServer()
+	s.policy = &topicPolicy{
+		NamePattern:          `^payments\.`,
+		MinReplication:       3,
+		MaxPartitionsPerTeam: 20,
+		RequiredTags:         []string{"owner"},
+	}
+	s.policy.namePattern = regexp.MustCompile(s.policy.NamePattern)
+
+	// test_topic has 5 partitions.
+	s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: "test_topic"}, TagSet{"team": "payments"})
+
+	req := &pb.CreateTopicsRequest{
+		Topics: []*pb.TopicSpec{
+			{Name: "payments.a", Partitions: 10, Replication: 3, Owner: "alice", Team: "payments"},
+			{Name: "events", Partitions: 10, Replication: 2, Team: "payments"},
+		},
+	}
+
+	_, err := s.CreateTopics(context.Background(), req)
+
+	st := status.Convert(err)
+	if st.Code() != codes.FailedPrecondition {
+		t.Fatalf("Expected FailedPrecondition, got '%v'", err)
+	}
+
+	var violations []string
+	for _, d := range st.Details() {
+		for _, v := range d.(*errdetails.PreconditionFailure).Violations {
+			violations = append(violations, v.Subject+" "+v.Type)
+		}
+	}
+
+	expected := []string{
+		"topics/events NAME_PATTERN",
+		"topics/events MIN_REPLICATION",
+		"topics/events REQUIRED_TAG",
+		"topics/events MAX_PARTITIONS_PER_TEAM",
+		"topics/payments.a MAX_PARTITIONS_PER_TEAM",
+	}
+
+	if !stringsEqual(violations, expected) {
+		t.Errorf("Expected violations %v, got %v", expected, violations)
+	}
+
+	if k := s.Kafka.(*kafkaAdminMock); len(k.created) != 0 {
+		t.Errorf("Expected no topics created, got %v", k.created)
+	}
+
+	// Within the policy.
+	req.Topics = req.Topics[:1]
+
+	resp, err := s.CreateTopics(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if topic := resp.Topics[0]; topic.Owner != "alice" || topic.Team != "payments" || topic.Tags["owner"] != "alice" {
+		t.Errorf("Unexpected topic %v", topic)
+	}
+}
+
+func TestTopicTagsPolicy(t *testing.T) {
+	s := testServer()
+	s.policy = &topicPolicy{MaxPartitionsPerTeam: 8, RequiredTags: []string{"owner"}}
+
+	// test_topic2 has 5 partitions.
+	s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: "test_topic2"}, TagSet{"team": "data"})
+
+	tests := map[int]struct {
+		tag     func(context.Context, *pb.TopicRequest) (*pb.TagResponse, error)
+		tags    []string
+		allowed bool
+	}{
+		// test_topic predates the
+		// required owner tag.
+		0: {s.TagTopic, []string{"team:payments"}, true},
+		1: {s.TagTopic, []string{"owner:alice"}, true},
+		2: {s.DeleteTopicTags, []string{"owner"}, false},
+		3: {s.TagTopic, []string{"team:data"}, false},
+		4: {s.DeleteTopicTags, []string{"team"}, true},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		test := tests[i]
+		_, err := test.tag(context.Background(), &pb.TopicRequest{Name: "test_topic", Tag: test.tags})
+
+		switch {
+		case test.allowed && err != nil:
+			t.Errorf("[test %d] Unexpected error '%s'", i, err)
+		case !test.allowed && status.Code(err) != codes.FailedPrecondition:
+			t.Errorf("[test %d] Expected FailedPrecondition, got '%v'", i, err)
+		}
+	}
+
+	resp, err := s.GetTopics(context.Background(), &pb.TopicRequest{Name: "test_topic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if topic := resp.Topics["test_topic"]; topic.Owner != "alice" || topic.Team != "" {
+		t.Errorf("Unexpected topic %v", topic)
+	}
+}
@@ -0,0 +1,501 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrRBACPolicyNotStored error.
+	ErrRBACPolicyNotStored = errors.New("no RBAC policy stored")
+)
+
+// RBAC resources: the kinds of changes permissions grant.
+const (
+	// Topic creations, deletions and config changes.
+	rbacTopics         = "topics"
+	rbacTopicTags      = "topic_tags"
+	rbacBrokerTags     = "broker_tags"
+	rbacQuotas         = "quotas"
+	rbacConsumerGroups = "consumer_groups"
+	rbacReassignments  = "reassignments"
+	rbacLifecycle      = "lifecycle"
+)
+
+// rbacMethods maps the write methods to the RBAC
+// resource they change. Read requests aren't authorized.
+var rbacMethods = map[string]string{
+	"/" + registryService + "/CreateTopics":              rbacTopics,
+	"/" + registryService + "/DeleteTopic":               rbacTopics,
+	"/" + registryService + "/SetTopicConfig":            rbacTopics,
+	"/" + registryService + "/TagTopic":                  rbacTopicTags,
+	"/" + registryService + "/DeleteTopicTags":           rbacTopicTags,
+	"/" + registryService + "/TagBroker":                 rbacBrokerTags,
+	"/" + registryService + "/DeleteBrokerTags":          rbacBrokerTags,
+	"/" + registryService + "/SetQuota":                  rbacQuotas,
+	"/" + registryService + "/DeleteQuota":               rbacQuotas,
+	"/" + registryService + "/ResetConsumerGroupOffsets": rbacConsumerGroups,
+	"/" + registryService + "/ExecuteReassignment":       rbacReassignments,
+	"/" + registryService + "/EnforceLifecycle":          rbacLifecycle,
+}
+
+// rbacObject is the tag storage object the RBAC
+// policy is stored in, as the rbacPolicyKey tag.
+var rbacObject = KafkaObject{Type: "rbac", ID: "policy"}
+
+const rbacPolicyKey = "policy"
+
+// rbacRefreshInterval is the interval at which
+// the stored RBAC policy is reloaded.
+const rbacRefreshInterval = 30 * time.Second
+
+// rbacPolicy grants authenticated identities the permissions
+// of the roles they're bound to. Identities without bindings
+// may make no changes.
+type rbacPolicy struct {
+	Roles map[string][]*rbacPermission `json:"roles"`
+	// Identities to the names of their roles.
+	Bindings map[string][]string `json:"bindings"`
+}
+
+// rbacPermission grants changes of the resources. Empty
+// fields don't restrict the changes granted.
+type rbacPermission struct {
+	Resources []string `json:"resources"`
+	// Tags ("key:value") each topic or broker changed must
+	// have, including inherited tags. Permissions with tags
+	// don't grant changes of cluster-wide resources (e.g.
+	// quotas), nor changes of the tags themselves.
+	Tags []string `json:"tags"`
+	// The tag key namespaces that may be set or deleted,
+	// "" for keys without a namespace.
+	Namespaces []string `json:"namespaces"`
+	// Cluster names; the default cluster is named
+	// by its ClusterName, "" if not federated.
+	Clusters []string `json:"clusters"`
+
+	tags TagSet
+}
+
+// rbacRequest describes the change a request makes.
+type rbacRequest struct {
+	resource string
+	cluster  string
+	// The tags of each topic or broker changed; nil
+	// for changes of cluster-wide resources.
+	objects []TagSet
+	// The tag keys set or deleted.
+	keys []string
+}
+
+// rbacAuthorizer holds the RBAC policy in use.
+type rbacAuthorizer struct {
+	sync.Mutex
+	policy *rbacPolicy
+	// The policy read from the RBACPolicyFile and
+	// stored by InitRBAC; nil if not configured.
+	file *rbacPolicy
+}
+
+// readRBACPolicy reads the rbacPolicy JSON object at path.
+func readRBACPolicy(path string) (*rbacPolicy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRBACPolicy(data)
+}
+
+// parseRBACPolicy parses and validates an rbacPolicy.
+func parseRBACPolicy(data []byte) (*rbacPolicy, error) {
+	p := &rbacPolicy{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("error parsing RBAC policy: %s", err)
+	}
+
+	valid := map[string]struct{}{}
+	for _, r := range rbacMethods {
+		valid[r] = struct{}{}
+	}
+
+	for name, perms := range p.Roles {
+		for _, perm := range perms {
+			for _, r := range perm.Resources {
+				if _, ok := valid[r]; !ok {
+					return nil, fmt.Errorf("RBAC role %s: invalid resource '%s'", name, r)
+				}
+			}
+
+			tags, err := Tags(perm.Tags).TagSet()
+			if err != nil {
+				return nil, fmt.Errorf("RBAC role %s: %s", name, err)
+			}
+			perm.tags = tags
+		}
+	}
+
+	for id, roles := range p.Bindings {
+		for _, r := range roles {
+			if _, exists := p.Roles[r]; !exists {
+				return nil, fmt.Errorf("RBAC binding of %s: undefined role '%s'", id, r)
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// permits returns whether the identity is
+// permitted to make the change of the request.
+func (p *rbacPolicy) permits(identity string, r *rbacRequest) bool {
+	for _, role := range p.Bindings[identity] {
+		for _, perm := range p.Roles[role] {
+			if perm.grants(r) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// grants returns whether the permission
+// grants the change of the request.
+func (p *rbacPermission) grants(r *rbacRequest) bool {
+	if !anyOrContains(p.Resources, r.resource) || !anyOrContains(p.Clusters, r.cluster) {
+		return false
+	}
+
+	if len(p.tags) > 0 {
+		if r.objects == nil {
+			return false
+		}
+
+		for _, ts := range r.objects {
+			if !ts.matchAll(p.tags) {
+				return false
+			}
+		}
+
+		// The tags scoping the permission
+		// can't themselves be changed.
+		for _, k := range r.keys {
+			for scoped := range p.tags {
+				if k == scoped || (tagKeyName(k) == tagNamespaceWildcard && tagNamespace(k) == tagNamespace(scoped)) {
+					return false
+				}
+			}
+		}
+	}
+
+	for _, k := range r.keys {
+		if !anyOrContains(p.Namespaces, tagNamespace(k)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// anyOrContains returns whether the list is empty or contains s.
+func anyOrContains(list []string, s string) bool {
+	if len(list) == 0 {
+		return true
+	}
+
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tagNamespace returns the namespace of the tag
+// key; "" if the key isn't namespaced.
+func tagNamespace(k string) string {
+	if i := strings.Index(k, tagNamespaceSep); i >= 0 {
+		return k[:i]
+	}
+
+	return ""
+}
+
+// tagKeyName returns the tag key without its namespace.
+func tagKeyName(k string) string {
+	return k[strings.Index(k, tagNamespaceSep)+1:]
+}
+
+// set replaces the policy in use.
+func (a *rbacAuthorizer) set(p *rbacPolicy) {
+	a.Lock()
+	a.policy = p
+	a.Unlock()
+}
+
+// get returns the policy in use.
+func (a *rbacAuthorizer) get() *rbacPolicy {
+	a.Lock()
+	defer a.Unlock()
+
+	return a.policy
+}
+
+// InitRBAC stores the RBAC policy read from the RBACPolicyFile or, if not
+// configured, loads the policy stored by another registry. It must be called
+// after InitTags. The policy is stored in the tag storage of the default
+// cluster, so that all registries sharing it enforce the same policy.
+func (s *Server) InitRBAC() error {
+	if s.rbac == nil {
+		return nil
+	}
+
+	if p := s.rbac.file; p != nil {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+
+		if err := s.Tags.Store.SetTags(rbacObject, TagSet{rbacPolicyKey: string(data)}); err != nil {
+			return fmt.Errorf("error storing RBAC policy: %s", err)
+		}
+
+		s.rbac.set(p)
+		log.Printf("Stored RBAC policy: %d roles, %d bindings\n", len(p.Roles), len(p.Bindings))
+
+		return nil
+	}
+
+	p, err := s.loadRBACPolicy()
+	if err != nil {
+		return err
+	}
+
+	s.rbac.set(p)
+	log.Printf("Loaded RBAC policy: %d roles, %d bindings\n", len(p.Roles), len(p.Bindings))
+
+	return nil
+}
+
+// loadRBACPolicy returns the stored rbacPolicy.
+func (s *Server) loadRBACPolicy() (*rbacPolicy, error) {
+	tags, err := s.Tags.Store.GetTags(rbacObject)
+	switch {
+	case err == ErrKafkaObjectDoesNotExist:
+		return nil, ErrRBACPolicyNotStored
+	case err != nil:
+		return nil, err
+	case tags[rbacPolicyKey] == "":
+		return nil, ErrRBACPolicyNotStored
+	}
+
+	return parseRBACPolicy([]byte(tags[rbacPolicyKey]))
+}
+
+// RunRBAC reloads the stored RBAC policy every rbacRefreshInterval, so
+// that policy changes stored by other registries take effect. The policy
+// in use is kept if the stored policy can't be loaded.
+func (s *Server) RunRBAC(ctx context.Context, wg *sync.WaitGroup) error {
+	if s.rbac == nil {
+		return nil
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		t := time.NewTicker(rbacRefreshInterval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+
+			p, err := s.loadRBACPolicy()
+			if err != nil {
+				log.Printf("[rbac] error loading RBAC policy: %s\n", err)
+				continue
+			}
+
+			s.rbac.set(p)
+		}
+	}()
+
+	return nil
+}
+
+// authorize returns a PermissionDenied error if RBAC is enabled and the
+// authenticated identity isn't permitted to make the write request.
+// Unauthenticated requests are left to the handler to refuse.
+func (s *Server) authorize(ctx context.Context, method string, req interface{}) error {
+	if s.rbac == nil {
+		return nil
+	}
+
+	resource, write := rbacMethods[method]
+	if !write {
+		return nil
+	}
+
+	id, err := s.identity(ctx)
+	if err != nil || id == "" {
+		return nil
+	}
+
+	r, err := s.rbacRequest(resource, req)
+	switch {
+	case err != nil:
+		return err
+	// The request is invalid; the
+	// handler returns the error.
+	case r == nil:
+		return nil
+	}
+
+	if s.rbac.get().permits(id, r) {
+		return nil
+	}
+
+	log.Printf("[rbac] %s denied %s\n", id, method)
+
+	return status.Errorf(codes.PermissionDenied, "%s is not permitted to make %s requests", id, strings.TrimPrefix(method, "/"+registryService+"/"))
+}
+
+// rbacRequest returns the rbacRequest describing the change of the write
+// request. A nil rbacRequest is returned for requests that are invalid,
+// e.g. of unknown clusters.
+func (s *Server) rbacRequest(resource string, req interface{}) (*rbacRequest, error) {
+	var cluster string
+	var objects []KafkaObject
+	var specs []*pb.TopicSpec
+	var keys []string
+	var scoped bool
+
+	switch r := req.(type) {
+	case *pb.TopicRequest:
+		cluster, scoped = r.Cluster, true
+		objects = []KafkaObject{{Type: "topic", ID: r.Name}}
+		keys = tagKeys(r.Tag)
+	case *pb.BrokerRequest:
+		cluster, scoped = r.Cluster, true
+		objects = []KafkaObject{{Type: "broker", ID: strconv.Itoa(int(r.Id))}}
+		keys = tagKeys(r.Tag)
+	case *pb.TopicConfigRequest:
+		cluster, scoped = r.Cluster, true
+		objects = []KafkaObject{{Type: "topic", ID: r.Name}}
+	case *pb.TopicDeleteRequest:
+		cluster, scoped = r.Cluster, true
+		objects = []KafkaObject{{Type: "topic", ID: r.Name}}
+	case *pb.CreateTopicsRequest:
+		var err error
+		if specs, err = topicSpecs(r); err != nil {
+			return nil, nil
+		}
+		cluster, scoped = r.Cluster, true
+	case *pb.QuotaRequest:
+		cluster = r.Cluster
+	case *pb.OffsetResetRequest:
+		cluster = r.Cluster
+	case *pb.ReassignmentExecuteRequest:
+		cluster = r.Cluster
+	case *pb.LifecycleRequest:
+		cluster = r.Cluster
+	default:
+		return nil, nil
+	}
+
+	c, err := s.cluster(cluster)
+	if err != nil {
+		return nil, nil
+	}
+
+	rr := &rbacRequest{resource: resource, cluster: c.clusterName, keys: keys}
+	if scoped {
+		rr.objects = []TagSet{}
+	}
+
+	for _, o := range objects {
+		tags, err := c.Tags.EffectiveTags(o)
+		if err != nil {
+			return nil, err
+		}
+		rr.objects = append(rr.objects, tags)
+	}
+
+	// Topics created are evaluated
+	// with their inherited tags.
+	for _, t := range specs {
+		rr.objects = append(rr.objects, c.Tags.withInherited(KafkaObject{Type: "topic", ID: t.Name}, t.Tags))
+	}
+
+	return rr, nil
+}
+
+// tagKeys returns the sorted keys of the key:value tags or tag keys.
+func tagKeys(tags []string) []string {
+	var keys []string
+	for _, t := range tags {
+		keys = append(keys, strings.SplitN(t, ":", 2)[0])
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// unaryAuthorize is a gRPC interceptor that
+// authorizes write requests; see authorize.
+func (s *Server) unaryAuthorize(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authorize(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// streamAuthorize is the streaming equivalent of unaryAuthorize;
+// the request of server streams is authorized once received.
+func (s *Server) streamAuthorize(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if _, write := rbacMethods[info.FullMethod]; !write || s.rbac == nil {
+		return handler(srv, ss)
+	}
+
+	return handler(srv, &authorizedStream{ServerStream: ss, s: s, method: info.FullMethod})
+}
+
+// authorizedStream authorizes the messages received.
+type authorizedStream struct {
+	grpc.ServerStream
+	s      *Server
+	method string
+}
+
+// RecvMsg receives and authorizes the request message.
+func (a *authorizedStream) RecvMsg(m interface{}) error {
+	if err := a.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	return a.s.authorize(a.Context(), a.method, m)
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"testing"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testRBACPolicy = `{
+  "roles": {
+    "data-team": [
+      {"resources": ["topics", "topic_tags"], "tags": ["team:data"]},
+      {"resources": ["topic_tags"], "namespaces": ["docs"]}
+    ],
+    "quota-admin": [
+      {"resources": ["quotas"], "clusters": ["us-east-1"]}
+    ]
+  },
+  "bindings": {
+    "data-ci": ["data-team"],
+    "sre-oncall": ["quota-admin"]
+  }
+}`
+
+func TestParseRBACPolicy(t *testing.T) {
+	tests := map[string]bool{
+		testRBACPolicy: true,
+		`{"roles": {"r": [{"resources": ["topics"]}]}}`:              true,
+		`{"roles": {"r": [{"resources": ["clusters"]}]}}`:            false,
+		`{"roles": {"r": [{"tags": ["team"]}]}}`:                     false,
+		`{"roles": {"r": [{}]}, "bindings": {"data-ci": ["admin"]}}`: false,
+		`["r"]`: false,
+	}
+
+	for data, valid := range tests {
+		if _, err := parseRBACPolicy([]byte(data)); valid != (err == nil) {
+			t.Errorf("Unexpected error '%v' for %s", err, data)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	s := testServer()
+	s.auth.tokens = map[string]string{"t1": "data-ci", "t2": "sre-oncall", "t3": "unbound"}
+	s.rbac = &rbacAuthorizer{}
+
+	p, err := parseRBACPolicy([]byte(testRBACPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.rbac.set(p)
+
+	s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: "test_topic"}, TagSet{"team": "data"})
+	s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: "test_topic2"}, TagSet{"team": "ingest"})
+
+	method := func(m string) string { return "/" + registryService + "/" + m }
+
+	tests := []struct {
+		token   string
+		method  string
+		req     interface{}
+		allowed bool
+	}{
+		// Topics with the team:data tag.
+		{"t1", "TagTopic", &pb.TopicRequest{Name: "test_topic", Tag: []string{"tier:1"}}, true},
+		{"t1", "TagTopic", &pb.TopicRequest{Name: "test_topic2", Tag: []string{"tier:1"}}, false},
+		{"t1", "SetTopicConfig", &pb.TopicConfigRequest{Name: "test_topic"}, true},
+		{"t1", "DeleteTopic", &pb.TopicDeleteRequest{Name: "test_topic2"}, false},
+		// The scoping tags can't be changed.
+		{"t1", "TagTopic", &pb.TopicRequest{Name: "test_topic", Tag: []string{"team:ingest"}}, false},
+		{"t1", "DeleteTopicTags", &pb.TopicRequest{Name: "test_topic", Tag: []string{"team"}}, false},
+		// Tags in the docs namespace of any topic.
+		{"t1", "TagTopic", &pb.TopicRequest{Name: "test_topic2", Tag: []string{"docs/url:x"}}, true},
+		{"t1", "DeleteTopicTags", &pb.TopicRequest{Name: "test_topic2", Tag: []string{"docs/*"}}, true},
+		{"t1", "DeleteTopicTags", &pb.TopicRequest{Name: "test_topic2", Tag: []string{"docs/url", "tier"}}, false},
+		// Creations are evaluated with the spec tags.
+		{"t1", "CreateTopics", &pb.CreateTopicsRequest{Topics: []*pb.TopicSpec{{Name: "new", Team: "data"}}}, true},
+		{"t1", "CreateTopics", &pb.CreateTopicsRequest{Topics: []*pb.TopicSpec{{Name: "new", Team: "data"}, {Name: "new2"}}}, false},
+		// Cluster-wide resources and clusters.
+		{"t1", "SetQuota", &pb.QuotaRequest{User: "alice"}, false},
+		{"t2", "SetQuota", &pb.QuotaRequest{User: "alice"}, false},
+		{"t2", "TagTopic", &pb.TopicRequest{Name: "test_topic", Tag: []string{"tier:1"}}, false},
+		// Identities without bindings.
+		{"t3", "TagTopic", &pb.TopicRequest{Name: "test_topic", Tag: []string{"tier:1"}}, false},
+		// Reads aren't authorized.
+		{"t3", "GetTopics", &pb.TopicRequest{}, true},
+	}
+
+	for i, test := range tests {
+		err := s.authorize(bearerContext(test.token), method(test.method), test.req)
+		if test.allowed != (err == nil) {
+			t.Errorf("[test %d] Expected allowed %v, got error '%v'", i, test.allowed, err)
+		}
+
+		if err != nil && status.Code(err) != codes.PermissionDenied {
+			t.Errorf("[test %d] Expected PermissionDenied, got '%s'", i, err)
+		}
+	}
+
+	// Clusters are matched by name.
+	s.clusterName = "us-east-1"
+	if err := s.authorize(bearerContext("t2"), method("SetQuota"), &pb.QuotaRequest{User: "alice"}); err != nil {
+		t.Errorf("Unexpected error '%s'", err)
+	}
+
+	// Unauthenticated requests are left to the handler.
+	if err := s.authorize(bearerContext("invalid"), method("TagTopic"), &pb.TopicRequest{Name: "test_topic"}); err != nil {
+		t.Errorf("Unexpected error '%s'", err)
+	}
+}
+
+func TestInitRBAC(t *testing.T) {
+	s := testServer()
+	s.rbac = &rbacAuthorizer{}
+
+	if err := s.InitRBAC(); err != ErrRBACPolicyNotStored {
+		t.Errorf("Expected error '%s', got '%v'", ErrRBACPolicyNotStored, err)
+	}
+
+	p, _ := parseRBACPolicy([]byte(testRBACPolicy))
+	s.rbac.file = p
+
+	if err := s.InitRBAC(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Load the stored policy.
+	s.rbac = &rbacAuthorizer{}
+	if err := s.InitRBAC(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := s.rbac.get()
+	if len(loaded.Roles) != 2 || len(loaded.Bindings["data-ci"]) != 1 {
+		t.Fatalf("Unexpected policy %v", loaded)
+	}
+
+	if perm := loaded.Roles["data-team"][0]; perm.tags["team"] != "data" {
+		t.Errorf("Expected permission tags parsed, got %v", perm.tags)
+	}
+}
@@ -0,0 +1,344 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+var (
+	// ErrSchemaRegistryNotConfigured error.
+	ErrSchemaRegistryNotConfigured = errors.New("schema registry URL not configured")
+)
+
+// schemaRegistryContentType is the
+// Schema Registry API content type.
+const schemaRegistryContentType = "application/vnd.schemaregistry.v1+json"
+
+// Schema types; Avro is the
+// Schema Registry default.
+var schemaTypes = map[string]struct{}{
+	"AVRO":     struct{}{},
+	"JSON":     struct{}{},
+	"PROTOBUF": struct{}{},
+}
+
+// schemaRegistryClient is a client for the Confluent
+// Schema Registry (or compatible) REST API.
+type schemaRegistryClient struct {
+	url    string
+	client *http.Client
+}
+
+// schemaRegistryError is an error response
+// from the Schema Registry.
+type schemaRegistryError struct {
+	status  int
+	Code    int    `json:"error_code"`
+	Message string `json:"message"`
+}
+
+func (e *schemaRegistryError) Error() string {
+	return fmt.Sprintf("schema registry error %d: %s", e.Code, e.Message)
+}
+
+// schemaVersion is a registered version of a subject schema.
+type schemaVersion struct {
+	Subject    string `json:"subject"`
+	ID         int32  `json:"id"`
+	Version    int32  `json:"version"`
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType"`
+}
+
+// schemaRequest is a schema as sent to the Schema Registry.
+type schemaRequest struct {
+	Schema string `json:"schema"`
+	// Omitted for Avro, as older Schema
+	// Registries only support Avro.
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+func newSchemaRegistryClient(u string) *schemaRegistryClient {
+	return &schemaRegistryClient{
+		url:    strings.TrimRight(u, "/"),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// do makes a request to the Schema Registry API path, decoding the JSON
+// response into out. Non-2xx responses are returned as a
+// *schemaRegistryError.
+func (c *schemaRegistryClient) do(method, path string, body, out interface{}) error {
+	var b []byte
+	if body != nil {
+		var err error
+		if b, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, c.url+path, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", schemaRegistryContentType)
+	if body != nil {
+		req.Header.Set("Content-Type", schemaRegistryContentType)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		e := &schemaRegistryError{status: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(e); err != nil || e.Message == "" {
+			e.Message = resp.Status
+		}
+
+		return e
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// notFound returns whether err is a Schema Registry
+// not found error, e.g. of a subject that doesn't exist.
+func notFound(err error) bool {
+	e, ok := err.(*schemaRegistryError)
+	return ok && e.status == http.StatusNotFound
+}
+
+// latest returns the latest version of the subject;
+// nil if the subject doesn't exist.
+func (c *schemaRegistryClient) latest(subject string) (*schemaVersion, error) {
+	v := &schemaVersion{}
+	err := c.do("GET", fmt.Sprintf("/subjects/%s/versions/latest", url.PathEscape(subject)), nil, v)
+
+	switch {
+	case notFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if v.SchemaType == "" {
+		v.SchemaType = "AVRO"
+	}
+
+	return v, nil
+}
+
+// compatible returns whether the schema is compatible with the
+// latest version of the subject, per the subject's compatibility level.
+// Schemas of subjects that don't exist are compatible.
+func (c *schemaRegistryClient) compatible(subject string, s schemaRequest) (bool, error) {
+	var resp struct {
+		Compatible bool `json:"is_compatible"`
+	}
+
+	err := c.do("POST", fmt.Sprintf("/compatibility/subjects/%s/versions/latest", url.PathEscape(subject)), s, &resp)
+
+	switch {
+	case notFound(err):
+		return true, nil
+	case err != nil:
+		return false, err
+	}
+
+	return resp.Compatible, nil
+}
+
+// register registers the schema under the subject, returning the schema ID.
+// Registering a schema identical to an existing version returns its ID.
+func (c *schemaRegistryClient) register(subject string, s schemaRequest) (int32, error) {
+	var resp struct {
+		ID int32 `json:"id"`
+	}
+
+	err := c.do("POST", fmt.Sprintf("/subjects/%s/versions", url.PathEscape(subject)), s, &resp)
+
+	return resp.ID, err
+}
+
+// compatibility returns the compatibility level of the subject,
+// or the global level if the subject has no level set.
+func (c *schemaRegistryClient) compatibility(subject string) (string, error) {
+	var resp struct {
+		Level string `json:"compatibilityLevel"`
+	}
+
+	err := c.do("GET", fmt.Sprintf("/config/%s", url.PathEscape(subject)), nil, &resp)
+	if notFound(err) {
+		err = c.do("GET", "/config", nil, &resp)
+	}
+
+	return resp.Level, err
+}
+
+// schemaSubject returns the Schema Registry subject of the key or value
+// schema of the topic, per the default topic name subject strategy.
+func schemaSubject(topic string, key bool) string {
+	if key {
+		return topic + "-key"
+	}
+
+	return topic + "-value"
+}
+
+// newSchemaRequest returns the schemaRequest of a *pb.TopicSchema.
+func newSchemaRequest(s *pb.TopicSchema) schemaRequest {
+	r := schemaRequest{Schema: s.Schema}
+	if t := strings.ToUpper(s.Type); t != "" && t != "AVRO" {
+		r.SchemaType = t
+	}
+
+	return r
+}
+
+// validateTopicSchemas returns an error if the *pb.TopicSchemas
+// of a topic are invalid; the syntax of Avro and JSON schemas is
+// checked locally.
+func validateTopicSchemas(schemas []*pb.TopicSchema) error {
+	seen := map[bool]struct{}{}
+
+	for _, s := range schemas {
+		t := strings.ToUpper(s.Type)
+		if t == "" {
+			t = "AVRO"
+		}
+
+		if _, ok := schemaTypes[t]; !ok {
+			return fmt.Errorf("unsupported schema type '%s'", s.Type)
+		}
+
+		if s.Schema == "" {
+			return errors.New("schema must be specified")
+		}
+
+		if t != "PROTOBUF" && !json.Valid([]byte(s.Schema)) {
+			return fmt.Errorf("invalid %s schema", t)
+		}
+
+		if _, dupe := seen[s.Key]; dupe {
+			return errors.New("more than one key or value schema specified")
+		}
+
+		seen[s.Key] = struct{}{}
+	}
+
+	return nil
+}
+
+// checkSchemas checks the compatibility of the schemas of the
+// *pb.TopicSpecs with their subjects, returning an error if any are
+// incompatible. The schemas fields of the respective *pb.Topics are
+// populated.
+func (s *Server) checkSchemas(specs []*pb.TopicSpec, topics []*pb.Topic) error {
+	for i, t := range specs {
+		for _, ts := range t.Schemas {
+			subject := schemaSubject(t.Name, ts.Key)
+
+			compatible, err := s.schemaRegistry.compatible(subject, newSchemaRequest(ts))
+			if err != nil {
+				return fmt.Errorf("error checking schema compatibility of subject %s: %s", subject, err)
+			}
+
+			if !compatible {
+				return fmt.Errorf("topic %s: schema is incompatible with subject %s", t.Name, subject)
+			}
+
+			level, err := s.schemaRegistry.compatibility(subject)
+			if err != nil {
+				return fmt.Errorf("error fetching compatibility level of subject %s: %s", subject, err)
+			}
+
+			schemaType := strings.ToUpper(ts.Type)
+			if schemaType == "" {
+				schemaType = "AVRO"
+			}
+
+			topics[i].Schemas = append(topics[i].Schemas, &pb.SchemaInfo{
+				Subject:       subject,
+				Type:          schemaType,
+				Compatibility: level,
+				Compatible:    true,
+			})
+		}
+	}
+
+	return nil
+}
+
+// registerSchemas registers the schemas of the *pb.TopicSpecs that
+// aren't validate only, setting the IDs and versions of the respective
+// *pb.Topic schemas fields.
+func (s *Server) registerSchemas(specs []*pb.TopicSpec, topics []*pb.Topic) error {
+	for i, t := range specs {
+		for j, ts := range t.Schemas {
+			if ts.ValidateOnly {
+				continue
+			}
+
+			info := topics[i].Schemas[j]
+			if _, err := s.schemaRegistry.register(info.Subject, newSchemaRequest(ts)); err != nil {
+				return fmt.Errorf("error registering schema of subject %s: %s", info.Subject, err)
+			}
+
+			v, err := s.schemaRegistry.latest(info.Subject)
+			if err != nil || v == nil {
+				return fmt.Errorf("error fetching registered schema of subject %s: %v", info.Subject, err)
+			}
+
+			info.Id, info.Version = v.ID, v.Version
+		}
+	}
+
+	return nil
+}
+
+// topicSchemas returns the *pb.SchemaInfo of
+// each existing key and value subject of the topic.
+func (s *Server) topicSchemas(topic string) ([]*pb.SchemaInfo, error) {
+	var schemas []*pb.SchemaInfo
+
+	for _, key := range []bool{true, false} {
+		subject := schemaSubject(topic, key)
+
+		v, err := s.schemaRegistry.latest(subject)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching schema of subject %s: %s", subject, err)
+		}
+
+		if v == nil {
+			continue
+		}
+
+		level, err := s.schemaRegistry.compatibility(subject)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching compatibility level of subject %s: %s", subject, err)
+		}
+
+		schemas = append(schemas, &pb.SchemaInfo{
+			Subject:       subject,
+			Id:            v.ID,
+			Version:       v.Version,
+			Type:          v.SchemaType,
+			Compatibility: level,
+		})
+	}
+
+	return schemas, nil
+}
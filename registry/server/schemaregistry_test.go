@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+// schemaRegistryMock mocks the Schema Registry API. Schemas
+// containing "breaking" are incompatible with any subject.
+type schemaRegistryMock struct {
+	sync.Mutex
+	// Subject to versions.
+	subjects map[string][]schemaVersion
+	nextID   int32
+}
+
+func newSchemaRegistryMock() (*schemaRegistryMock, *httptest.Server) {
+	m := &schemaRegistryMock{subjects: map[string][]schemaVersion{}, nextID: 1}
+	return m, httptest.NewServer(m)
+}
+
+func (m *schemaRegistryMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.Lock()
+	defer m.Unlock()
+
+	notFound := func() {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error_code": 40401, "message": "Subject not found."}`))
+	}
+
+	var req schemaRequest
+	if r.Method == "POST" {
+		json.NewDecoder(r.Body).Decode(&req)
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	// Only the global level is set.
+	case r.URL.Path == "/config":
+		w.Write([]byte(`{"compatibilityLevel": "BACKWARD"}`))
+	case parts[0] == "config":
+		notFound()
+	case parts[0] == "compatibility":
+		if _, exists := m.subjects[parts[2]]; !exists {
+			notFound()
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]bool{"is_compatible": !strings.Contains(req.Schema, "breaking")})
+	case r.Method == "POST":
+		v := schemaVersion{Subject: parts[1], ID: m.nextID, Version: int32(len(m.subjects[parts[1]]) + 1), Schema: req.Schema}
+		m.subjects[parts[1]] = append(m.subjects[parts[1]], v)
+		m.nextID++
+
+		json.NewEncoder(w).Encode(map[string]int32{"id": v.ID})
+	default:
+		versions, exists := m.subjects[parts[1]]
+		if !exists {
+			notFound()
+			return
+		}
+
+		json.NewEncoder(w).Encode(versions[len(versions)-1])
+	}
+}
+
+func TestCreateTopicsSchemas(t *testing.T) {
+	s := testServer()
+
+	schema := &pb.TopicSchema{Schema: `{"type": "string"}`}
+	req := &pb.CreateTopicsRequest{
+		Topics: []*pb.TopicSpec{{Name: "events", Partitions: 1, Replication: 1, Schemas: []*pb.TopicSchema{schema}}},
+	}
+
+	if _, err := s.CreateTopics(context.Background(), req); err == nil || err.Error() != "topic events: "+ErrSchemaRegistryNotConfigured.Error() {
+		t.Errorf("Expected error '%s', got '%v'", ErrSchemaRegistryNotConfigured, err)
+	}
+
+	m, srv := newSchemaRegistryMock()
+	defer srv.Close()
+
+	s.schemaRegistry = newSchemaRegistryClient(srv.URL)
+
+	resp, err := s.CreateTopics(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := resp.Topics[0].Schemas[0]
+	if info.Subject != "events-value" || info.Id != 1 || info.Version != 1 || info.Compatibility != "BACKWARD" || !info.Compatible {
+		t.Errorf("Unexpected schema info %v", info)
+	}
+
+	if len(m.subjects["events-value"]) != 1 {
+		t.Errorf("Expected schema registered, got %v", m.subjects)
+	}
+
+	// Incompatible and invalid schemas.
+	tests := map[int]*pb.TopicSchema{
+		0: &pb.TopicSchema{Schema: `{"type": "string", "doc": "breaking"}`},
+		1: &pb.TopicSchema{Schema: `{"type":`},
+		2: &pb.TopicSchema{Schema: `{"type": "string"}`, Type: "XML"},
+	}
+
+	expected := map[int]string{
+		0: "topic events2: schema is incompatible with subject events2-value",
+		1: "topic events2: invalid AVRO schema",
+		2: "topic events2: unsupported schema type 'XML'",
+	}
+
+	m.subjects["events2-value"] = m.subjects["events-value"]
+
+	for i, schema := range tests {
+		req := &pb.CreateTopicsRequest{
+			Topics: []*pb.TopicSpec{{Name: "events2", Partitions: 1, Replication: 1, Schemas: []*pb.TopicSchema{schema}}},
+		}
+
+		if _, err := s.CreateTopics(context.Background(), req); err == nil || err.Error() != expected[i] {
+			t.Errorf("[test %d] Expected error '%s', got '%v'", i, expected[i], err)
+		}
+	}
+
+	if k := s.Kafka.(*kafkaAdminMock); len(k.created) != 1 {
+		t.Errorf("Expected 1 topic created, got %v", k.created)
+	}
+}
+
+func TestCreateTopicsSchemasValidateOnly(t *testing.T) {
+	s := testServer()
+
+	m, srv := newSchemaRegistryMock()
+	defer srv.Close()
+
+	s.schemaRegistry = newSchemaRegistryClient(srv.URL)
+
+	req := &pb.CreateTopicsRequest{
+		Topics: []*pb.TopicSpec{{
+			Name:        "events",
+			Partitions:  1,
+			Replication: 1,
+			Schemas: []*pb.TopicSchema{
+				{Schema: `{"type": "string"}`, Key: true, ValidateOnly: true},
+				{Schema: `{"type": "string"}`},
+			},
+		}},
+		DryRun: true,
+	}
+
+	// Dry runs register no schemas.
+	if _, err := s.CreateTopics(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(m.subjects) != 0 {
+		t.Errorf("Expected no schemas registered, got %v", m.subjects)
+	}
+
+	req.DryRun = false
+	if _, err := s.CreateTopics(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, exists := m.subjects["events-key"]; exists || len(m.subjects["events-value"]) != 1 {
+		t.Errorf("Expected the value schema registered, got %v", m.subjects)
+	}
+}
+
+func TestGetTopicsSchemas(t *testing.T) {
+	s := testServer()
+
+	req := &pb.TopicRequest{Name: "test_topic", Schemas: true}
+	if _, err := s.GetTopics(context.Background(), req); err != ErrSchemaRegistryNotConfigured {
+		t.Errorf("Expected error '%s', got '%v'", ErrSchemaRegistryNotConfigured, err)
+	}
+
+	m, srv := newSchemaRegistryMock()
+	defer srv.Close()
+
+	s.schemaRegistry = newSchemaRegistryClient(srv.URL)
+	m.subjects["test_topic-value"] = []schemaVersion{
+		{Subject: "test_topic-value", ID: 3, Version: 1},
+		{Subject: "test_topic-value", ID: 7, Version: 2, SchemaType: "JSON"},
+	}
+
+	resp, err := s.GetTopics(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schemas := resp.Topics["test_topic"].Schemas
+	if len(schemas) != 1 {
+		t.Fatalf("Expected 1 schema, got %v", schemas)
+	}
+
+	info := schemas[0]
+	if info.Subject != "test_topic-value" || info.Id != 7 || info.Version != 2 || info.Type != "JSON" || info.Compatibility != "BACKWARD" {
+		t.Errorf("Unexpected schema info %v", info)
+	}
+}
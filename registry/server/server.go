@@ -2,38 +2,117 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
+	"github.com/honeycombio/kafka-kit/kafkametrics"
 	"github.com/honeycombio/kafka-kit/kafkazk"
 	pb "github.com/honeycombio/kafka-kit/registry/protos"
+	pbv2 "github.com/honeycombio/kafka-kit/registry/protos/v2"
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
 )
 
 const (
 	readRequest = iota
 	writeRequest
+	// Reads that fetch many ZooKeeper or Kafka
+	// objects; these are limited as reads and
+	// additionally by the metadata throttle.
+	metadataRequest
 )
 
+// throttleCapacity is the burst capacity
+// of all request throttles.
+const throttleCapacity = 10
+
 // Server implements the registry APIs.
 type Server struct {
 	HTTPListen       string
 	GRPCListen       string
 	ZK               kafkazk.Handler
+	Kafka            KafkaAdmin
 	Tags             *TagHandler
 	readReqThrottle  RequestThrottle
 	writeReqThrottle RequestThrottle
+	metaReqThrottle  RequestThrottle
 	reqID            uint64
+	tlsConfig        *tls.Config
+	auth             authConfig
+	// Write request authorization; nil
+	// if RBAC isn't enabled.
+	rbac *rbacAuthorizer
+	// Per-client throttles; nil if unlimited.
+	clientReadThrottle  *clientThrottles
+	clientWriteThrottle *clientThrottles
+	// Topic deletion safety checks.
+	deleteIdleWindow time.Duration
+	protectedTag     TagSet
+	deleteTokens     *deleteTokens
+	// Reassignment plans pending execution.
+	reassignmentPlans        *reassignmentPlans
+	reassignmentPollInterval time.Duration
+	// Watch subscribers.
+	watchHub      *watchHub
+	watchInterval time.Duration
+	// The ZooKeeper tags prefix migrated to the
+	// TagHandler Store; empty if not migrating.
+	tagsMigratePrefix string
+	// Origins allowed cross-origin HTTP requests.
+	corsOrigins []string
+	// Webhooks sent watch events.
+	webhooks webhookConfig
+	// Stores audit log entries; nil
+	// if not configured.
+	audit AuditStore
+	// Topic policies; nil if not configured.
+	policy *topicPolicy
+	// Topic lifecycle rules; nil if not configured,
+	// and the interval at which they're enforced.
+	lifecycleRules    []*lifecycleRule
+	lifecycleInterval time.Duration
+	// Schema Registry client; nil
+	// if not configured.
+	schemaRegistry *schemaRegistryClient
+	// Live broker metrics of the default
+	// cluster; nil if not configured.
+	brokerMetrics kafkametrics.Handler
+	// State change events; nil if not configured.
+	stateEvents *stateEvents
+	// Self-metrics and health checks.
+	metrics     *registryMetrics
+	health      *health.Server
+	healthState healthState
+	// Caches topic and broker metadata
+	// for topic and broker lookups.
+	metadataCache *metadataCache
+	// The cluster name; requests for the cluster
+	// or no cluster are served by this Server.
+	clusterName string
+	// The config of federated cluster Servers.
+	clusterConfig clusterConfig
+	// Federated cluster Servers by name;
+	// nil for federated cluster Servers.
+	clusters map[string]*Server
+	// Leader election; nil if not enabled.
+	election *leaderElection
 	// For tests.
 	test bool
 }
@@ -45,6 +124,118 @@ type Config struct {
 	ReadReqRate  int
 	WriteReqRate int
 	ZKTagsPrefix string
+	// The global rate limit of metadata-heavy
+	// reads in addition to the ReadReqRate.
+	MetadataReqRate int
+	// Per-client rate limits; clients are identified
+	// by authenticated identity, otherwise address.
+	// Unlimited if 0.
+	ClientReadReqRate  int
+	ClientWriteReqRate int
+	// The tag storage backend and its configs;
+	// see the TagHandlerConfig. Tags are stored
+	// in ZooKeeper under the ZKTagsPrefix if unset.
+	TagsBackend       string
+	TagsKafkaTopic    string
+	TagsEtcdEndpoints string
+	TagsEtcdPrefix    string
+	// Whether tags stored in ZooKeeper are migrated
+	// to the tag storage backend in InitTags.
+	TagsMigrateZK bool
+	// Topics with messages produced within the
+	// DeleteIdleWindow can't be deleted unforced.
+	DeleteIdleWindow time.Duration
+	// Topics with the ProtectedTag (key:value)
+	// can't be deleted unforced.
+	ProtectedTag string
+	// The interval at which ZooKeeper is polled
+	// for changes while there are watchers.
+	WatchInterval time.Duration
+	// TLS certificate and key for the gRPC and HTTP
+	// listeners; both are plaintext if unset.
+	TLSCertFile string
+	TLSKeyFile  string
+	// CA certificates for verifying client certificates,
+	// which authenticate gRPC clients by common name.
+	TLSClientCAFile string
+	// Path to a JSON object of identities to
+	// bearer tokens that authenticate clients.
+	AuthTokensFile string
+	// If any authentication method is configured, write
+	// requests require authentication. AuthReads
+	// requires it for read requests as well.
+	AuthReads bool
+	// Authorize write requests against the RBAC policy
+	// stored with the tags; requires authentication.
+	RBAC bool
+	// Path to a JSON RBAC policy stored by InitRBAC;
+	// implies RBAC.
+	RBACPolicyFile string
+	// Comma-delimited list of origins allowed
+	// cross-origin HTTP requests, e.g. from
+	// browser dashboards; * allows any origin.
+	HTTPCORSOrigins string
+	// Path to a JSON array of webhooks sent
+	// watch events; see RunWebhooks.
+	WebhooksFile string
+	// Path to the file audit log entries of
+	// mutating calls are stored in.
+	AuditLogFile string
+	// Kafka topic each audit logged change is published
+	// to as a StateEvent; requires a Kafka client.
+	StateEventsTopic string
+	// Path to a JSON topic policy enforced on
+	// topic creation and tag changes.
+	TopicPolicyFile string
+	// Path to a JSON array of topic lifecycle rules,
+	// enforced every TopicLifecycleInterval if non-0;
+	// see RunLifecycle.
+	TopicLifecycleFile     string
+	TopicLifecycleInterval time.Duration
+	// Path to a JSON object of tags inherited by
+	// topics and brokers; see TagDefaults.
+	TagDefaultsFile string
+	// Confluent Schema Registry (or compatible)
+	// URL, for topic schemas.
+	SchemaRegistryURL string
+	// How long topic and broker metadata read from
+	// ZooKeeper for topic and broker lookups is
+	// cached; caching is disabled if 0 and
+	// MetadataCacheWatch isn't set.
+	MetadataCacheTTL time.Duration
+	// Cache topic and broker metadata until ZooKeeper
+	// watches report a change (or for the TTL, if set).
+	MetadataCacheWatch bool
+	// Live broker network and disk metrics of the
+	// default cluster, for broker utilization requests.
+	BrokerMetrics kafkametrics.Handler
+	// The name of the default cluster, required if
+	// the ClustersFile is set.
+	ClusterName string
+	// Path to a JSON object of cluster names to the
+	// configs of additional clusters the registry
+	// serves; see DialClusters.
+	ClustersFile string
+	// Read cluster metadata via the Kafka Admin API
+	// rather than ZooKeeper, e.g. for KRaft clusters;
+	// see DialKafkaMetadata. ZooKeeper tag storage,
+	// TagsMigrateZK, the ClustersFile and
+	// LeaderElection require ZooKeeper and can't be
+	// used.
+	KafkaMetadata bool
+	// Elect a leader among registries sharing the
+	// ZKTagsPrefix; see RunElection. Registries
+	// advertise the AdvertiseAddr, the gRPC address
+	// other registries forward requests to, which is
+	// required. Forwarded requests are authenticated
+	// by a token shared by the registries, read from
+	// the PeerTokenFile, which is also required.
+	LeaderElection bool
+	AdvertiseAddr  string
+	PeerTokenFile  string
+	// CA certificates for verifying the TLS certificate
+	// of the leader; the system roots if unset.
+	LeaderTLSCAFile string
 
 	test bool
 }
@@ -57,36 +248,272 @@ func NewServer(c Config) (*Server, error) {
 	case c.ReadReqRate < 1:
 		fallthrough
 	case c.WriteReqRate < 1:
+		fallthrough
+	case c.MetadataReqRate < 1:
+		fallthrough
+	case c.ClientReadReqRate < 0, c.ClientWriteReqRate < 0:
+		fallthrough
+	case c.DeleteIdleWindow < 0:
+		fallthrough
+	case c.WatchInterval <= 0:
+		fallthrough
+	case c.MetadataCacheTTL < 0:
+		fallthrough
+	case c.TopicLifecycleInterval < 0:
+		fallthrough
+	case c.ClusterName != "" && !clusterNameRegex.MatchString(c.ClusterName):
+		fallthrough
+	case c.ClustersFile != "" && c.ClusterName == "":
+		fallthrough
+	case (c.TLSCertFile == "") != (c.TLSKeyFile == ""):
+		fallthrough
+	case c.TLSClientCAFile != "" && c.TLSCertFile == "":
+		fallthrough
+	case (c.RBAC || c.RBACPolicyFile != "") && c.AuthTokensFile == "" && c.TLSClientCAFile == "":
+		fallthrough
+	case c.LeaderElection && (c.AdvertiseAddr == "" || c.PeerTokenFile == ""):
+		fallthrough
+	case c.KafkaMetadata && (c.TagsBackend == "" || c.TagsBackend == "zookeeper"):
+		fallthrough
+	case c.KafkaMetadata && (c.TagsMigrateZK || c.ClustersFile != "" || c.LeaderElection):
 		return nil, errors.New("invalid configuration parameter(s)")
 	}
 
+	var tlsConfig *tls.Config
+	if c.TLSCertFile != "" {
+		var err error
+		if tlsConfig, err = serverTLSConfig(c.TLSCertFile, c.TLSKeyFile, c.TLSClientCAFile); err != nil {
+			return nil, err
+		}
+	}
+
+	auth := authConfig{clientCerts: c.TLSClientCAFile != "", reads: c.AuthReads}
+	if c.AuthTokensFile != "" {
+		var err error
+		if auth.tokens, err = readAuthTokens(c.AuthTokensFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var rbac *rbacAuthorizer
+	if c.RBAC || c.RBACPolicyFile != "" {
+		rbac = &rbacAuthorizer{}
+		if c.RBACPolicyFile != "" {
+			var err error
+			if rbac.file, err = readRBACPolicy(c.RBACPolicyFile); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	webhooks := webhookConfig{
+		client:       &http.Client{Timeout: 10 * time.Second},
+		retryBackoff: time.Second,
+	}
+
+	if c.WebhooksFile != "" {
+		var err error
+		if webhooks.hooks, err = readWebhooks(c.WebhooksFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var audit AuditStore
+	if c.AuditLogFile != "" {
+		var err error
+		if audit, err = NewFileAuditStore(c.AuditLogFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var policy *topicPolicy
+	if c.TopicPolicyFile != "" {
+		var err error
+		if policy, err = readTopicPolicy(c.TopicPolicyFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var lifecycleRules []*lifecycleRule
+	if c.TopicLifecycleFile != "" {
+		var err error
+		if lifecycleRules, err = readLifecycleRules(c.TopicLifecycleFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var election *leaderElection
+	if c.LeaderElection {
+		if err := validAdvertiseAddr(c.AdvertiseAddr); err != nil {
+			return nil, err
+		}
+
+		token, err := readPeerToken(c.PeerTokenFile)
+		if err != nil {
+			return nil, err
+		}
+
+		opts, err := electionDialOpts(tlsConfig, c.LeaderTLSCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		election = &leaderElection{
+			path:      fmt.Sprintf("/%s/%s", c.ZKTagsPrefix, electionZNode),
+			id:        c.AdvertiseAddr,
+			peerToken: token,
+			dialOpts:  opts,
+		}
+	}
+
+	var events *stateEvents
+	if c.StateEventsTopic != "" {
+		events = &stateEvents{topic: c.StateEventsTopic}
+	}
+
+	var tagDefaults *TagDefaults
+	if c.TagDefaultsFile != "" {
+		var err error
+		if tagDefaults, err = readTagDefaults(c.TagDefaultsFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var schemaRegistry *schemaRegistryClient
+	if c.SchemaRegistryURL != "" {
+		if u, err := url.Parse(c.SchemaRegistryURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid schema registry URL '%s'", c.SchemaRegistryURL)
+		}
+
+		schemaRegistry = newSchemaRegistryClient(c.SchemaRegistryURL)
+	}
+
+	var protected TagSet
+	if c.ProtectedTag != "" {
+		var err error
+		if protected, err = (Tags{c.ProtectedTag}).TagSet(); err != nil {
+			return nil, err
+		}
+	}
+
 	rrt, _ := NewRequestThrottle(RequestThrottleConfig{
-		Capacity: 10,
+		Capacity: throttleCapacity,
 		Rate:     c.ReadReqRate,
 	})
 
 	wrt, _ := NewRequestThrottle(RequestThrottleConfig{
-		Capacity: 10,
+		Capacity: throttleCapacity,
 		Rate:     c.WriteReqRate,
 	})
 
+	mrt, _ := NewRequestThrottle(RequestThrottleConfig{
+		Capacity: throttleCapacity,
+		Rate:     c.MetadataReqRate,
+	})
+
+	var crt, cwt *clientThrottles
+	if c.ClientReadReqRate > 0 {
+		crt, _ = newClientThrottles(RequestThrottleConfig{
+			Capacity: throttleCapacity,
+			Rate:     c.ClientReadReqRate,
+		})
+	}
+
+	if c.ClientWriteReqRate > 0 {
+		cwt, _ = newClientThrottles(RequestThrottleConfig{
+			Capacity: throttleCapacity,
+			Rate:     c.ClientWriteReqRate,
+		})
+	}
+
 	tcfg := TagHandlerConfig{
-		Prefix: c.ZKTagsPrefix,
+		Prefix:        c.ZKTagsPrefix,
+		Backend:       c.TagsBackend,
+		KafkaTopic:    c.TagsKafkaTopic,
+		EtcdEndpoints: c.TagsEtcdEndpoints,
+		EtcdPrefix:    c.TagsEtcdPrefix,
+		Defaults:      tagDefaults,
+	}
+
+	th, err := NewTagHandler(tcfg)
+	if err != nil {
+		return nil, err
 	}
 
-	th, _ := NewTagHandler(tcfg)
 	if c.test {
 		th.Store = newzkTagStorageMock()
 	}
 
-	return &Server{
-		HTTPListen:       c.HTTPListen,
-		GRPCListen:       c.GRPCListen,
-		Tags:             th,
-		readReqThrottle:  rrt,
-		writeReqThrottle: wrt,
-		test:             c.test,
-	}, nil
+	var corsOrigins []string
+	for _, o := range strings.Split(c.HTTPCORSOrigins, ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			corsOrigins = append(corsOrigins, strings.TrimSuffix(o, "/"))
+		}
+	}
+
+	// Migrations from ZooKeeper to
+	// ZooKeeper are meaningless.
+	var migratePrefix string
+	if _, zk := th.Store.(*ZKTagStorage); c.TagsMigrateZK && !zk {
+		migratePrefix = c.ZKTagsPrefix
+	}
+
+	s := &Server{
+		HTTPListen:               c.HTTPListen,
+		GRPCListen:               c.GRPCListen,
+		Tags:                     th,
+		readReqThrottle:          rrt,
+		writeReqThrottle:         wrt,
+		metaReqThrottle:          mrt,
+		clientReadThrottle:       crt,
+		clientWriteThrottle:      cwt,
+		tlsConfig:                tlsConfig,
+		auth:                     auth,
+		rbac:                     rbac,
+		deleteIdleWindow:         c.DeleteIdleWindow,
+		protectedTag:             protected,
+		deleteTokens:             newDeleteTokens(),
+		reassignmentPlans:        newReassignmentPlans(),
+		reassignmentPollInterval: reassignmentPollInterval,
+		watchHub:                 newWatchHub(),
+		watchInterval:            c.WatchInterval,
+		tagsMigratePrefix:        migratePrefix,
+		corsOrigins:              corsOrigins,
+		webhooks:                 webhooks,
+		audit:                    audit,
+		policy:                   policy,
+		lifecycleRules:           lifecycleRules,
+		lifecycleInterval:        c.TopicLifecycleInterval,
+		schemaRegistry:           schemaRegistry,
+		brokerMetrics:            c.BrokerMetrics,
+		stateEvents:              events,
+		metrics:                  newRegistryMetrics(),
+		health:                   newHealthServer(),
+		metadataCache:            newMetadataCache(c.MetadataCacheTTL, c.MetadataCacheWatch),
+		clusterName:              c.ClusterName,
+		election:                 election,
+		test:                     c.test,
+	}
+
+	if c.ClustersFile != "" {
+		clusters, err := readClusters(c.ClustersFile)
+		if err != nil {
+			return nil, err
+		}
+
+		s.clusters = map[string]*Server{}
+		for name, cc := range clusters {
+			if name == c.ClusterName {
+				return nil, fmt.Errorf("cluster %s is the default cluster", name)
+			}
+
+			if s.clusters[name], err = s.newClusterServer(name, cc, tcfg); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return s, nil
 }
 
 // Run* methods take a Context for cancellation and WaitGroup
@@ -105,8 +532,22 @@ func (s *Server) RunRPC(ctx context.Context, wg *sync.WaitGroup) error {
 		return err
 	}
 
-	srvr := grpc.NewServer()
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(s.unaryInterceptor),
+		grpc.StreamInterceptor(s.streamInterceptor),
+	}
+
+	if s.tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	}
+
+	srvr := grpc.NewServer(opts...)
 	pb.RegisterRegistryServer(srvr, s)
+	pbv2.RegisterRegistryServer(srvr, s.V2())
+	healthpb.RegisterHealthServer(srvr, s.health)
+	// Server reflection, for generic
+	// tooling such as grpcurl.
+	reflection.Register(srvr)
 
 	// Shutdown procedure.
 	go func() {
@@ -133,6 +574,26 @@ func (s *Server) RunRPC(ctx context.Context, wg *sync.WaitGroup) error {
 	return nil
 }
 
+// unaryInterceptor chains the gRPC interceptors: requests
+// refused by authorization are observed by the metrics, as
+// are requests forwarded to the leader.
+func (s *Server) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return s.unaryMetrics(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.unaryForward(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return s.unaryAuthorize(ctx, req, info, handler)
+		})
+	})
+}
+
+// streamInterceptor is the streaming equivalent of unaryInterceptor.
+func (s *Server) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return s.streamMetrics(srv, ss, info, func(srv interface{}, ss grpc.ServerStream) error {
+		return s.streamForward(srv, ss, info, func(srv interface{}, ss grpc.ServerStream) error {
+			return s.streamAuthorize(srv, ss, info, handler)
+		})
+	})
+}
+
 // RunHTTP runs the HTTP endpoint.
 func (s *Server) RunHTTP(ctx context.Context, wg *sync.WaitGroup) error {
 	wg.Add(1)
@@ -140,14 +601,31 @@ func (s *Server) RunHTTP(ctx context.Context, wg *sync.WaitGroup) error {
 	mux := runtime.NewServeMux()
 	opts := []grpc.DialOption{grpc.WithInsecure()}
 
+	// The gateway dials the local gRPC listener and
+	// forwards the HTTP authorization header; only
+	// bearer tokens authenticate HTTP requests.
+	if s.tlsConfig != nil {
+		creds := credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	}
+
 	err := pb.RegisterRegistryHandlerFromEndpoint(ctx, mux, s.GRPCListen, opts)
 	if err != nil {
 		return err
 	}
 
+	err = pbv2.RegisterRegistryHandlerFromEndpoint(ctx, mux, s.GRPCListen, opts)
+	if err != nil {
+		return err
+	}
+
 	srvr := &http.Server{
 		Addr:    s.HTTPListen,
-		Handler: mux,
+		Handler: s.httpHandler(mux),
+	}
+
+	if s.tlsConfig != nil {
+		srvr.TLSConfig = &tls.Config{Certificates: s.tlsConfig.Certificates}
 	}
 
 	// Shutdown procedure.
@@ -165,7 +643,14 @@ func (s *Server) RunHTTP(ctx context.Context, wg *sync.WaitGroup) error {
 	// Background the listener.
 	go func() {
 		log.Printf("HTTP up: %s\n", s.HTTPListen)
-		if err := srvr.ListenAndServe(); err != http.ErrServerClosed {
+		var err error
+		if s.tlsConfig != nil {
+			err = srvr.ListenAndServeTLS("", "")
+		} else {
+			err = srvr.ListenAndServe()
+		}
+
+		if err != http.ErrServerClosed {
 			log.Println(err)
 		}
 	}()
@@ -202,12 +687,11 @@ func (s *Server) DialZK(ctx context.Context, wg *sync.WaitGroup, c *kafkazk.Conf
 
 	log.Printf("Connected to ZooKeeper: %s\n", c.Connect)
 
-	// Pass the Handler to the underlying TagHandler Store
-	// and call the Init procedure.
-	// TODO this needs to go somewhere else.
-	s.Tags.Store.(*ZKTagStorage).ZK = zk
-	if err := s.Tags.Store.(*ZKTagStorage).Init(); err != nil {
-		return fmt.Errorf("failed to initialize ZooKeeper TagStorage backend")
+	// Pass the Handler to the underlying TagHandler
+	// Store if backed by ZooKeeper; it's initialized
+	// in InitTags.
+	if zs, ok := s.Tags.Store.(*ZKTagStorage); ok {
+		zs.ZK = zk
 	}
 
 	// Shutdown procedure.
@@ -220,15 +704,126 @@ func (s *Server) DialZK(ctx context.Context, wg *sync.WaitGroup, c *kafkazk.Conf
 	return nil
 }
 
+// DialKafkaMetadata takes a Context, WaitGroup and *kafkaadmin.Config and
+// initializes a kafkaadmin.Handler in place of the ZooKeeper Handler set by
+// DialZK, with KafkaMetadata set. Cluster metadata is then read via the Kafka
+// Admin API; requests that depend on ZooKeeper only data, such as broker and
+// partition metrics, return an error.
+func (s *Server) DialKafkaMetadata(ctx context.Context, wg *sync.WaitGroup, c *kafkaadmin.Config) error {
+	if s.test {
+		s.ZK = &kafkazk.Mock{}
+		return nil
+	}
+
+	h, err := kafkaadmin.NewHandler(*c)
+	if err != nil {
+		return err
+	}
+
+	s.ZK = h
+
+	log.Printf("Reading cluster metadata via the Kafka Admin API: %s\n", c.BootstrapServers)
+
+	// Shutdown procedure.
+	wg.Add(1)
+	go func() {
+		<-ctx.Done()
+		h.Close()
+		wg.Done()
+	}()
+
+	return nil
+}
+
+// DialKafka takes a *kafkaadmin.Config and initializes a Kafka Admin
+// API client, used for consumer group requests.
+func (s *Server) DialKafka(c *kafkaadmin.Config) error {
+	if s.test {
+		s.Kafka = newKafkaAdminMock()
+		return nil
+	}
+
+	ka, err := kafkaadmin.NewClient(*c)
+	if err != nil {
+		return err
+	}
+
+	s.Kafka = ka
+
+	// As in DialZK, the client is passed to
+	// the TagHandler Store if backed by Kafka.
+	if ks, ok := s.Tags.Store.(*KafkaTagStorage); ok {
+		ks.Kafka = ka
+	}
+
+	// State events of all clusters are
+	// published to the default cluster.
+	if s.stateEvents != nil {
+		s.stateEvents.log = ka
+	}
+
+	log.Printf("Connected to Kafka: %s\n", c.BootstrapServers)
+
+	return nil
+}
+
+// InitTags initializes the TagHandler Store and must be called after DialZK
+// and DialKafka. If configured, tags stored in ZooKeeper are then migrated
+// to the Store for all objects that have no tags in it.
+func (s *Server) InitTags() error {
+	if err := s.Tags.Store.Init(); err != nil {
+		return fmt.Errorf("failed to initialize TagStorage backend: %s", err)
+	}
+
+	if s.tagsMigratePrefix == "" {
+		return nil
+	}
+
+	n, err := MigrateZKTags(s.ZK, s.tagsMigratePrefix, s.Tags.Store)
+	if err != nil {
+		return fmt.Errorf("failed to migrate tags from ZooKeeper after %d objects: %s", n, err)
+	}
+
+	log.Printf("Migrated tags of %d objects from ZooKeeper\n", n)
+
+	return nil
+}
+
 // ValidateRequest takes an incoming request context, params, and request
-// kind. The request is logged and checked against the appropriate request
-// throttler.
+// kind. The request is logged, authenticated if required for the kind and
+// checked against the appropriate client and global request throttlers.
+// With leader election, write requests are refused for forwarding unless
+// this registry is the leader; see requireLeader.
 func (s *Server) ValidateRequest(ctx context.Context, req interface{}, kind int) error {
+	if kind == writeRequest {
+		if err := s.requireLeader(ctx); err != nil {
+			return err
+		}
+	}
+
 	reqID := atomic.AddUint64(&s.reqID, 1)
 
 	// Log the request.
 	s.LogRequest(ctx, fmt.Sprintf("%v", req), reqID)
 
+	if err := s.authenticate(ctx, kind); err != nil {
+		return err
+	}
+
+	// Check the client request throttle. Clients
+	// are refused without consuming global tokens.
+	ct := s.clientReadThrottle
+	if kind == writeRequest {
+		ct = s.clientWriteThrottle
+	}
+
+	if ct != nil {
+		if client := s.client(ctx); !ct.allow(client) {
+			log.Printf("[request %d] client %s rate limited", reqID, client)
+			return ErrClientRateLimited
+		}
+	}
+
 	var to context.Context
 
 	// If the request context didn't have a deadline,
@@ -248,19 +843,105 @@ func (s *Server) ValidateRequest(ctx context.Context, req interface{}, kind int)
 
 	// Check the appropriate request throttle.
 	switch kind {
-	case 0:
+	case readRequest:
 		if err = s.readReqThrottle.Request(to); err != nil {
 			return err
 		}
-	case 1:
+	case writeRequest:
 		if err = s.writeReqThrottle.Request(to); err != nil {
 			return err
 		}
+	case metadataRequest:
+		if err = s.readReqThrottle.Request(to); err != nil {
+			return err
+		}
+		if err = s.metaReqThrottle.Request(to); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// client returns the client of the request for per-client throttling:
+// the authenticated identity or, otherwise, the client host. Requests
+// via the HTTP gateway, which dials from the loopback address, are
+// attributed to the HTTP client address forwarded by the gateway, as are
+// requests forwarded to the leader by other registries (authenticated by
+// the peer token; see forwardedByPeer).
+func (s *Server) client(ctx context.Context) string {
+	if id, _ := s.identity(ctx); id != "" {
+		return id
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	ip := net.ParseIP(host)
+
+	if (ip != nil && ip.IsLoopback()) || s.forwardedByPeer(ctx) {
+		// The gateway appends the HTTP client address.
+		if fwd := md["x-forwarded-for"]; len(fwd) > 0 {
+			addrs := strings.Split(fwd[len(fwd)-1], ",")
+			return strings.TrimSpace(addrs[len(addrs)-1])
+		}
+	}
+
+	return host
+}
+
+// AuditLog takes a request context, the resource changed by the request
+// (e.g. topics/<name>), a description of the change and the state of the
+// resource before and after the change, and logs it along with the
+// requestor and its authenticated identity. The entry is also appended to
+// the audit store, if configured.
+func (s *Server) AuditLog(ctx context.Context, resource, change string, before, after interface{}) {
+	var requestor string
+	if p, ok := peer.FromContext(ctx); ok {
+		requestor = p.Addr.String()
+	}
+
+	method, _ := grpc.Method(ctx)
+	identity := s.identityString(ctx)
+
+	if !s.test {
+		var cluster string
+		if s.clusterName != "" {
+			cluster = fmt.Sprintf(" cluster:%s", s.clusterName)
+		}
+
+		log.Printf("[audit] requestor:%s identity:%s method:%s%s %s", requestor, identity, method, cluster, change)
+	}
+
+	if s.audit == nil {
+		return
+	}
+
+	e := AuditEntry{
+		Timestamp: time.Now().Unix(),
+		Requestor: requestor,
+		Identity:  identity,
+		Method:    method,
+		Resource:  resource,
+		Change:    change,
+		Before:    auditState(before),
+		After:     auditState(after),
+		Cluster:   s.clusterName,
+	}
+
+	if err := s.audit.Append(e); err != nil {
+		log.Printf("[audit] error storing audit entry for %s: %s", resource, err)
+	}
+}
+
 // LogRequest takes a request context and input parameters as a string
 // and logs the request data.
 func (s *Server) LogRequest(ctx context.Context, params string, reqID uint64) {
@@ -293,6 +974,6 @@ func (s *Server) LogRequest(ctx context.Context, params string, reqID uint64) {
 	// Get the gRPC method.
 	method, _ = grpc.Method(ctx)
 
-	log.Printf("[request %d] requestor:%s type:%s method:%s params:%s",
-		reqID, requestor, reqType, method, params)
+	log.Printf("[request %d] requestor:%s identity:%s type:%s method:%s params:%s",
+		reqID, requestor, s.identityString(ctx), reqType, method, params)
 }
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewServerKafkaMetadata(t *testing.T) {
+	tests := map[int]Config{
+		0: Config{},
+		1: Config{TagsBackend: "zookeeper"},
+		2: Config{TagsBackend: "kafka", TagsMigrateZK: true},
+		3: Config{TagsBackend: "kafka", LeaderElection: true, AdvertiseAddr: "registry-0:8090", PeerTokenFile: "peer-token"},
+		4: Config{TagsBackend: "kafka", ClustersFile: "clusters.json", ClusterName: "main"},
+		5: Config{TagsBackend: "kafka", TagsKafkaTopic: "registry-tags"},
+		6: Config{TagsBackend: "etcd", TagsEtcdEndpoints: "http://localhost:2379", TagsEtcdPrefix: "registry"},
+	}
+
+	expected := map[int]bool{
+		0: false,
+		1: false,
+		2: false,
+		3: false,
+		4: false,
+		5: true,
+		6: true,
+	}
+
+	for i, c := range tests {
+		c.ReadReqRate, c.WriteReqRate, c.MetadataReqRate = 1, 1, 1
+		c.ZKTagsPrefix = testConfig.Prefix
+		c.WatchInterval = time.Second
+		c.KafkaMetadata = true
+		c.test = true
+
+		_, err := NewServer(c)
+		if valid := err == nil; valid != expected[i] {
+			t.Errorf("[test %d] Expected valid %t, got error '%v'", i, expected[i], err)
+		}
+
+		if !expected[i] && err != nil && err.Error() != "invalid configuration parameter(s)" {
+			t.Errorf("[test %d] Unexpected error: %s", i, err)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
+
+	"google.golang.org/grpc"
+)
+
+var (
+	// ErrStateEventsNotConfigured error.
+	ErrStateEventsNotConfigured = errors.New("state events topic not configured")
+)
+
+// stateEventsVersion is the version of the StateEvent schema, incremented
+// on incompatible changes.
+const stateEventsVersion = 1
+
+// stateEventsFetchBytes is the maximum bytes
+// fetched per state events topic fetch request.
+const stateEventsFetchBytes = 1 << 20
+
+// StateEvent types.
+const (
+	eventTopic        = "topic"
+	eventTopicConfigs = "topic_configs"
+	eventTopicTags    = "topic_tags"
+	eventBrokerTags   = "broker_tags"
+	eventQuotas       = "quotas"
+	eventGroupOffsets = "consumer_group_offsets"
+	eventReassignment = "reassignment"
+)
+
+// StateEvent is a registry state change, published to the state events
+// topic for each change audit logged. Events hold the complete state of
+// the resource before and after the change, so that the last event of each
+// record key (see stateEventKey) describes its current state.
+type StateEvent struct {
+	Version int    `json:"version"`
+	Type    string `json:"type"`
+	// Unix timestamp (milliseconds).
+	Timestamp int64 `json:"timestamp"`
+	// The cluster of the resource; empty
+	// if the registry isn't federated.
+	Cluster  string `json:"cluster,omitempty"`
+	Resource string `json:"resource"`
+	Identity string `json:"identity"`
+	Method   string `json:"method,omitempty"`
+	Change   string `json:"change"`
+	// The state of the resource before and
+	// after the change; null if it didn't exist.
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// stateEventKey returns the record key of the StateEvent:
+// <type>:<resource>, prefixed by <cluster>: if federated.
+func stateEventKey(e StateEvent) string {
+	key := e.Type + ":" + e.Resource
+	if e.Cluster != "" {
+		key = e.Cluster + ":" + key
+	}
+
+	return key
+}
+
+// StateLog is the Kafka API the state events are published to and
+// read from, implemented by the *kafkaadmin.Client.
+type StateLog interface {
+	Produce(string, int, []kafkaadmin.Record) (int64, error)
+	Fetch(string, int, int64, int) ([]kafkaadmin.Record, int64, error)
+}
+
+// stateEvents publishes StateEvents to partition 0 of the
+// topic, in order of the changes. It's shared by the
+// servers of all federated clusters.
+type stateEvents struct {
+	mu    sync.Mutex
+	topic string
+	log   StateLog
+}
+
+// publish produces the StateEvent.
+func (se *stateEvents) publish(e StateEvent) error {
+	value, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	se.mu.Lock()
+	defer se.mu.Unlock()
+
+	record := kafkaadmin.Record{Key: []byte(stateEventKey(e)), Value: value}
+	_, err = se.log.Produce(se.topic, 0, []kafkaadmin.Record{record})
+
+	return err
+}
+
+// read calls fn with each StateEvent in the topic, in order,
+// up to the high watermark. Invalid events are skipped.
+func (se *stateEvents) read(fn func(StateEvent)) error {
+	var offset int64
+
+	for {
+		records, hw, err := se.log.Fetch(se.topic, 0, offset, stateEventsFetchBytes)
+		if err != nil {
+			return err
+		}
+
+		if len(records) == 0 {
+			return nil
+		}
+
+		for _, r := range records {
+			offset = r.Offset + 1
+
+			e := StateEvent{}
+			if err := json.Unmarshal(r.Value, &e); err != nil || e.Version != stateEventsVersion {
+				log.Printf("[events] skipping invalid state event at offset %d", r.Offset)
+				continue
+			}
+
+			fn(e)
+		}
+
+		if offset >= hw {
+			return nil
+		}
+	}
+}
+
+// eventState returns the JSON encoding of the resource state v for a
+// StateEvent. Nil values, e.g. of resources that don't exist, are null.
+func eventState(v interface{}) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+
+	return b
+}
+
+// recordChange audit logs the change of the resource and
+// publishes it as a StateEvent of the event type.
+func (s *Server) recordChange(ctx context.Context, typ, resource, change string, before, after interface{}) {
+	s.AuditLog(ctx, resource, change, before, after)
+	s.publishStateEvent(ctx, typ, resource, change, before, after)
+}
+
+// publishStateEvent publishes the change of the resource as a StateEvent of
+// the event type. It's a no-op if the state events topic isn't configured.
+func (s *Server) publishStateEvent(ctx context.Context, typ, resource, change string, before, after interface{}) {
+	if s.stateEvents == nil || s.stateEvents.log == nil {
+		return
+	}
+
+	method, _ := grpc.Method(ctx)
+
+	e := StateEvent{
+		Version:   stateEventsVersion,
+		Type:      typ,
+		Timestamp: time.Now().UnixNano() / int64(time.Millisecond),
+		Cluster:   s.clusterName,
+		Resource:  resource,
+		Identity:  s.identityString(ctx),
+		Method:    method,
+		Change:    change,
+		Before:    eventState(before),
+		After:     eventState(after),
+	}
+
+	if err := s.stateEvents.publish(e); err != nil {
+		log.Printf("[events] error publishing state event for %s: %s", resource, err)
+	}
+}
+
+// RebuildTags restores the tags of each cluster from the state events
+// topic and must be called after InitTags and DialClusters. The tags of each
+// object with tag events are set to those of its last event, removing any
+// other tags; objects without tag events are unmodified.
+func (s *Server) RebuildTags() error {
+	if s.stateEvents == nil || s.stateEvents.log == nil {
+		return ErrStateEventsNotConfigured
+	}
+
+	// The last tags of each object, by cluster.
+	latest := map[string]map[KafkaObject]TagSet{}
+
+	err := s.stateEvents.read(func(e StateEvent) {
+		var o KafkaObject
+		switch e.Type {
+		case eventTopicTags:
+			o = KafkaObject{Type: "topic", ID: strings.TrimPrefix(e.Resource, "topics/")}
+		case eventBrokerTags:
+			o = KafkaObject{Type: "broker", ID: strings.TrimPrefix(e.Resource, "brokers/")}
+		default:
+			return
+		}
+
+		tags := TagSet{}
+		if err := json.Unmarshal(e.After, &tags); err != nil {
+			return
+		}
+
+		if _, exists := latest[e.Cluster]; !exists {
+			latest[e.Cluster] = map[KafkaObject]TagSet{}
+		}
+		latest[e.Cluster][o] = tags
+	})
+
+	if err != nil {
+		return fmt.Errorf("error reading state events: %s", err)
+	}
+
+	for _, c := range s.clusterServers() {
+		n, err := c.restoreTags(latest[c.clusterName])
+		if err != nil {
+			return fmt.Errorf("error restoring tags after %d objects: %s", n, err)
+		}
+
+		var cluster string
+		if c.clusterName != "" {
+			cluster = fmt.Sprintf(" of cluster %s", c.clusterName)
+		}
+
+		log.Printf("Restored tags of %d objects%s from state events\n", n, cluster)
+	}
+
+	return nil
+}
+
+// restoreTags sets the stored tags of each object to
+// its TagSet, returning the number of objects restored.
+func (s *Server) restoreTags(tags map[KafkaObject]TagSet) (int, error) {
+	var n int
+
+	for o, ts := range tags {
+		current, err := s.Tags.Store.GetTags(o)
+		if err != nil && err != ErrKafkaObjectDoesNotExist {
+			return n, err
+		}
+
+		var stale Tags
+		for k := range current {
+			if _, exists := ts[k]; !exists {
+				stale = append(stale, k)
+			}
+		}
+
+		if len(stale) > 0 {
+			if err := s.Tags.Store.DeleteTags(o, stale); err != nil {
+				return n, err
+			}
+		}
+
+		if len(ts) > 0 {
+			if err := s.Tags.Store.SetTags(o, ts); err != nil {
+				return n, err
+			}
+		}
+
+		n++
+	}
+
+	return n, nil
+}
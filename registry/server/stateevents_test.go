@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+func testStateEventsServer(l *tagLogMock) *Server {
+	s := testServer()
+	s.stateEvents = &stateEvents{topic: "registry-events", log: l}
+
+	return s
+}
+
+func TestPublishStateEvents(t *testing.T) {
+	l := &tagLogMock{}
+	s := testStateEventsServer(l)
+	ctx := context.Background()
+
+	if _, err := s.TagTopic(ctx, &pb.TopicRequest{Name: "test_topic", Tag: []string{"team:data", "tier:1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.DeleteTopicTags(ctx, &pb.TopicRequest{Name: "test_topic", Tag: []string{"tier"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.TagBroker(ctx, &pb.BrokerRequest{Id: 1001, Tag: []string{"pool:a"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := &pb.CreateTopicsRequest{Topics: []*pb.TopicSpec{{Name: "new_topic", Partitions: 1, Replication: 1, Tags: map[string]string{"team": "ingest"}}}}
+	if _, err := s.CreateTopics(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		key   string
+		typ   string
+		after string
+	}{
+		{"topic_tags:topics/test_topic", eventTopicTags, `{"team":"data","tier":"1"}`},
+		{"topic_tags:topics/test_topic", eventTopicTags, `{"team":"data"}`},
+		{"broker_tags:brokers/1001", eventBrokerTags, `{"pool":"a"}`},
+		{"topic:topics/new_topic", eventTopic, ""},
+		{"topic_tags:topics/new_topic", eventTopicTags, `{"team":"ingest"}`},
+	}
+
+	if len(l.records) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(l.records))
+	}
+
+	for i, r := range l.records {
+		e := StateEvent{}
+		if err := json.Unmarshal(r.Value, &e); err != nil {
+			t.Fatal(err)
+		}
+
+		if string(r.Key) != expected[i].key || e.Type != expected[i].typ || e.Version != stateEventsVersion {
+			t.Errorf("[event %d] Expected key %s and type %s, got %s and %s", i, expected[i].key, expected[i].typ, r.Key, e.Type)
+		}
+
+		if expected[i].after != "" && string(e.After) != expected[i].after {
+			t.Errorf("[event %d] Expected after %s, got %s", i, expected[i].after, e.After)
+		}
+	}
+
+	// Topic creations have no before state.
+	e := StateEvent{}
+	json.Unmarshal(l.records[3].Value, &e)
+
+	if string(e.Before) != "null" || e.Resource != "topics/new_topic" || e.Identity != "none" {
+		t.Errorf("Unexpected topic creation event: %s", l.records[3].Value)
+	}
+}
+
+func TestRebuildTags(t *testing.T) {
+	l := &tagLogMock{}
+	ctx := context.Background()
+
+	s := testStateEventsServer(l)
+	s.TagTopic(ctx, &pb.TopicRequest{Name: "test_topic", Tag: []string{"team:data", "tier:1"}})
+	s.TagTopic(ctx, &pb.TopicRequest{Name: "test_topic2", Tag: []string{"team:ingest"}})
+	s.DeleteTopicTags(ctx, &pb.TopicRequest{Name: "test_topic", Tag: []string{"tier"}})
+	s.TagBroker(ctx, &pb.BrokerRequest{Id: 1002, Tag: []string{"pool:b"}})
+
+	// A registry with diverged tags.
+	r := testStateEventsServer(l)
+	r.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: "test_topic"}, TagSet{"tier": "2", "stale": "true"})
+	r.Tags.Store.SetTags(KafkaObject{Type: "broker", ID: "1001"}, TagSet{"pool": "a"})
+
+	if err := r.RebuildTags(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[KafkaObject]TagSet{
+		KafkaObject{Type: "topic", ID: "test_topic"}:  TagSet{"team": "data"},
+		KafkaObject{Type: "topic", ID: "test_topic2"}: TagSet{"team": "ingest"},
+		KafkaObject{Type: "broker", ID: "1002"}:       TagSet{"pool": "b"},
+		// No tag events.
+		KafkaObject{Type: "broker", ID: "1001"}: TagSet{"pool": "a"},
+	}
+
+	for o, ts := range expected {
+		if stored := r.storedTags(o); !reflect.DeepEqual(stored, ts) {
+			t.Errorf("[%s %s] Expected tags %v, got %v", o.Type, o.ID, ts, stored)
+		}
+	}
+
+	if err := testServer().RebuildTags(); err != ErrStateEventsNotConfigured {
+		t.Errorf("Expected error '%s', got '%v'", ErrStateEventsNotConfigured, err)
+	}
+}
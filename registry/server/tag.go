@@ -18,6 +18,8 @@ var (
 	ErrNilTagSet = errors.New("must provide a non-nil or non-empty TagSet")
 	// ErrNilTags error.
 	ErrNilTags = errors.New("must provide a non-nil or non-empty tags")
+	// ErrInvalidTagStorageBackend error.
+	ErrInvalidTagStorageBackend = errors.New("tag storage backend must be one of zookeeper, kafka or etcd")
 )
 
 // ErrReservedTag error.
@@ -33,10 +35,13 @@ func (e ErrReservedTag) Error() string {
 // along with tag storage and retrieval.
 type TagHandler struct {
 	Store TagStorage
+	// Tags inherited by objects; nil if none.
+	Defaults *TagDefaults
 }
 
 // TagStorage handles tag persistence to stable storage.
 type TagStorage interface {
+	Init() error
 	LoadReservedFields(ReservedFields) error
 	FieldReserved(KafkaObject, string) bool
 	SetTags(KafkaObject, TagSet) error
@@ -44,9 +49,26 @@ type TagStorage interface {
 	DeleteTags(KafkaObject, Tags) error
 }
 
-// NewTagHandler initializes a TagHandler.
+// NewTagHandler initializes a TagHandler with
+// the configured TagStorage backend.
 func NewTagHandler(c TagHandlerConfig) (*TagHandler, error) {
-	ts, err := NewZKTagStorage(ZKTagStorageConfig{Prefix: c.Prefix})
+	var ts TagStorage
+	var err error
+
+	switch c.Backend {
+	case "", "zookeeper":
+		ts, err = NewZKTagStorage(ZKTagStorageConfig{Prefix: c.Prefix})
+	case "kafka":
+		ts, err = NewKafkaTagStorage(KafkaTagStorageConfig{Topic: c.KafkaTopic})
+	case "etcd":
+		ts, err = NewEtcdTagStorage(EtcdTagStorageConfig{
+			Endpoints: c.EtcdEndpoints,
+			Prefix:    c.EtcdPrefix,
+		})
+	default:
+		return nil, ErrInvalidTagStorageBackend
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -57,15 +79,27 @@ func NewTagHandler(c TagHandlerConfig) (*TagHandler, error) {
 	}
 
 	return &TagHandler{
-		// More sophisticated initialization/config passing
-		// if additional TagStorage backends are written.
-		Store: ts,
+		Store:    ts,
+		Defaults: c.Defaults,
 	}, nil
 }
 
 // TagHandlerConfig holds TagHandler configuration.
 type TagHandlerConfig struct {
+	// The ZooKeeper tag storage prefix.
 	Prefix string
+	// The TagStorage backend: zookeeper
+	// (the default), kafka or etcd.
+	Backend string
+	// The compacted topic used by
+	// the kafka backend.
+	KafkaTopic string
+	// Comma-delimited etcd endpoints and
+	// key prefix used by the etcd backend.
+	EtcdEndpoints string
+	EtcdPrefix    string
+	// Tags inherited by objects; nil if none.
+	Defaults *TagDefaults
 }
 
 // Tags is a []string of "key:value" pairs.
@@ -75,7 +109,8 @@ type Tags []string
 type TagSet map[string]string
 
 // KafkaObject holds an object type (broker, topic) and
-// object identifier (ID, name).
+// object identifier (ID, name). The RBAC policy is stored
+// as the tags of the rbacObject.
 type KafkaObject struct {
 	Type string
 	ID   string
@@ -85,7 +120,7 @@ type KafkaObject struct {
 // Type field value.
 func (o KafkaObject) Valid() bool {
 	switch {
-	case o.Type == "broker", o.Type == "topic":
+	case o.Type == "broker", o.Type == "topic", o.Type == rbacObject.Type:
 		return true
 	}
 
@@ -98,6 +133,31 @@ func (o KafkaObject) Complete() bool {
 	return o.Valid() && o.ID != ""
 }
 
+// checkSetTags returns an error if the KafkaObject isn't complete,
+// the TagSet is empty or any tag is reserved for the object type
+// or has an invalid key.
+func checkSetTags(s TagStorage, o KafkaObject, ts TagSet) error {
+	if !o.Complete() {
+		return ErrInvalidKafkaObjectType
+	}
+
+	if len(ts) == 0 {
+		return ErrNilTagSet
+	}
+
+	for k := range ts {
+		if s.FieldReserved(o, k) {
+			return ErrReservedTag{t: k}
+		}
+
+		if err := validTagKey(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // TagSetFromObject takes a protobuf type and returns the
 // default TagSet along with any user-defined tags.
 func (t *TagHandler) TagSetFromObject(o interface{}) (TagSet, error) {
@@ -130,21 +190,13 @@ func (t *TagHandler) TagSetFromObject(o interface{}) (TagSet, error) {
 		ts["version"] = fmt.Sprintf("%d", b.Version)
 	}
 
-	// Fetch stored tags.
-	st, err := t.Store.GetTags(ko)
+	// Fetch stored and inherited tags.
+	st, err := t.EffectiveTags(ko)
 	if err != nil {
-		switch {
-		// ErrKafkaObjectDoesNotExist from TagStorage
-		// simply means we do not have any user-defined
-		// tags stored for the requested object.
-		case err == ErrKafkaObjectDoesNotExist:
-			break
-		default:
-			return nil, err
-		}
+		return nil, err
 	}
 
-	// Merge stored tags with default tags.
+	// Merge them with default tags.
 	for k, v := range st {
 		ts[k] = v
 	}
@@ -152,12 +204,12 @@ func (t *TagHandler) TagSetFromObject(o interface{}) (TagSet, error) {
 	return ts, nil
 }
 
-// FilterTopics takes a map of topic names to *pb.Topic and tags KV list.
-// A filtered map is returned that includes topics where all tags
-// values match the provided input tag KVs. Additionally, any custom
-// tags persisted in the TagStorage backend are populated into the
-// Tags field for each matched object.
-func (t *TagHandler) FilterTopics(in TopicSet, tags Tags) (TopicSet, error) {
+// FilterTopics takes a map of topic names to *pb.Topic, tags KV list and
+// TagExpr. A filtered map is returned that includes topics where all tags
+// values match the provided input tag KVs and that match the TagExpr, if
+// non-nil. Additionally, any custom tags persisted in the TagStorage
+// backend are populated into the Tags field for each matched object.
+func (t *TagHandler) FilterTopics(in TopicSet, tags Tags, expr TagExpr) (TopicSet, error) {
 	var out = make(TopicSet)
 
 	// Get tag key/values.
@@ -173,7 +225,7 @@ func (t *TagHandler) FilterTopics(in TopicSet, tags Tags) (TopicSet, error) {
 			return nil, err
 		}
 
-		if ts.matchAll(tagKV) {
+		if ts.matchAll(tagKV) && matchTagExpr(expr, ts) {
 			out[name] = topic
 
 			// Ensure that custom tags fetched from storage are
@@ -188,18 +240,21 @@ func (t *TagHandler) FilterTopics(in TopicSet, tags Tags) (TopicSet, error) {
 					out[name].Tags[k] = v
 				}
 			}
+
+			out[name].Owner = ts[ownerTag]
+			out[name].Team = ts[teamTag]
 		}
 	}
 
 	return out, nil
 }
 
-// FilterBrokers takes a map of broker IDs to *pb.Broker and tags KV list.
-// A filtered map is returned that includes brokers where all tags
-// values match the provided input tag KVs. Additionally, any custom
-// tags persisted in the TagStorage backend are populated into the
-// Tags field for each matched object.
-func (t *TagHandler) FilterBrokers(in BrokerSet, tags Tags) (BrokerSet, error) {
+// FilterBrokers takes a map of broker IDs to *pb.Broker, tags KV list and
+// TagExpr. A filtered map is returned that includes brokers where all tags
+// values match the provided input tag KVs and that match the TagExpr, if
+// non-nil. Additionally, any custom tags persisted in the TagStorage
+// backend are populated into the Tags field for each matched object.
+func (t *TagHandler) FilterBrokers(in BrokerSet, tags Tags, expr TagExpr) (BrokerSet, error) {
 	var out = make(BrokerSet)
 
 	// Get tag key/values.
@@ -215,7 +270,7 @@ func (t *TagHandler) FilterBrokers(in BrokerSet, tags Tags) (BrokerSet, error) {
 			return nil, err
 		}
 
-		if ts.matchAll(tagKV) {
+		if ts.matchAll(tagKV) && matchTagExpr(expr, ts) {
 			out[id] = broker
 
 			// Ensure that custom tags fetched from storage are
@@ -230,6 +285,8 @@ func (t *TagHandler) FilterBrokers(in BrokerSet, tags Tags) (BrokerSet, error) {
 					out[id].Tags[k] = v
 				}
 			}
+
+			out[id].Maintenance = ts[maintenanceTag] == "true"
 		}
 	}
 
@@ -298,6 +355,12 @@ func GetReservedFields() ReservedFields {
 	fs["topic"] = fieldsFromStruct(&pb.Topic{})
 	fs["broker"] = fieldsFromStruct(&pb.Broker{})
 
+	// Topic ownership fields and the broker
+	// maintenance flag are stored as tags.
+	delete(fs["topic"], ownerTag)
+	delete(fs["topic"], teamTag)
+	delete(fs["broker"], maintenanceTag)
+
 	return fs
 }
 
@@ -200,7 +200,7 @@ func TestFilterTopics(t *testing.T) {
 	}
 
 	for i, tags := range tests {
-		filtered, err := th.FilterTopics(topics, tags)
+		filtered, err := th.FilterTopics(topics, tags, nil)
 		if err != nil {
 			t.Errorf("Unexpected error: %s", err)
 		}
@@ -242,7 +242,7 @@ func TestFilterBrokers(t *testing.T) {
 	}
 
 	for i, tags := range tests {
-		filtered, err := th.FilterBrokers(brokers, tags)
+		filtered, err := th.FilterBrokers(brokers, tags, nil)
 		if err != nil {
 			t.Errorf("Unexpected error: %s", err)
 		}
@@ -261,6 +261,7 @@ func TestReservedFields(t *testing.T) {
 		"name":        struct{}{},
 		"partitions":  struct{}{},
 		"replication": struct{}{},
+		"schemas":     struct{}{},
 	}
 
 	brokerExpected := map[string]struct{}{
@@ -302,3 +303,26 @@ func TestReservedFields(t *testing.T) {
 		}
 	}
 }
+
+func TestNewTagHandler(t *testing.T) {
+	th, err := NewTagHandler(TagHandlerConfig{Backend: "kafka", KafkaTopic: "registry-tags"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := th.Store.(*KafkaTagStorage); !ok {
+		t.Errorf("Expected a *KafkaTagStorage, got %T", th.Store)
+	}
+
+	if !th.Store.FieldReserved(KafkaObject{Type: "topic"}, "partitions") {
+		t.Error("Expected reserved fields to be loaded")
+	}
+
+	if _, err := NewTagHandler(TagHandlerConfig{Backend: "etcd", EtcdPrefix: "registry"}); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	if _, err := NewTagHandler(TagHandlerConfig{Backend: "mysql"}); err != ErrInvalidTagStorageBackend {
+		t.Errorf("Expected error '%s', got '%v'", ErrInvalidTagStorageBackend, err)
+	}
+}
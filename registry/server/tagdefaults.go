@@ -0,0 +1,168 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// tagNamespaceSep separates the namespace and key
+// of namespaced tags, e.g. placement/rack-group.
+const tagNamespaceSep = "/"
+
+// tagNamespaceWildcard is the key of namespaced tag
+// deletions matching all keys of the namespace.
+const tagNamespaceWildcard = "*"
+
+// TagDefaults are tags inherited by topics and brokers from cluster
+// and name prefix level defaults. Each object type maps name prefixes
+// (broker IDs for brokers) to the default tags of the objects with the
+// prefix; the "" prefix applies to all objects of the cluster. Tags of
+// longer prefixes take precedence over those of shorter prefixes, and
+// tags set on an object take precedence over all defaults.
+type TagDefaults struct {
+	Topic  map[string]TagSet `json:"topic"`
+	Broker map[string]TagSet `json:"broker"`
+}
+
+// readTagDefaults reads the TagDefaults JSON object at path.
+func readTagDefaults(path string) (*TagDefaults, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &TagDefaults{}
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, fmt.Errorf("error parsing tag defaults: %s", err)
+	}
+
+	reserved := GetReservedFields()
+
+	for typ, prefixes := range map[string]map[string]TagSet{"topic": d.Topic, "broker": d.Broker} {
+		for prefix, ts := range prefixes {
+			for k := range ts {
+				if err := validTagKey(k); err != nil {
+					return nil, fmt.Errorf("tag defaults for %s prefix '%s': %s", typ, prefix, err)
+				}
+
+				if _, r := reserved[typ][k]; r {
+					return nil, fmt.Errorf("tag defaults for %s prefix '%s': %s", typ, prefix, ErrReservedTag{t: k})
+				}
+			}
+		}
+	}
+
+	return d, nil
+}
+
+// inherited returns the tags the object inherits; nil
+// if the TagDefaults are nil or the object has none.
+func (d *TagDefaults) inherited(o KafkaObject) TagSet {
+	if d == nil {
+		return nil
+	}
+
+	prefixes := d.Topic
+	if o.Type == "broker" {
+		prefixes = d.Broker
+	}
+
+	var matched []string
+	for p := range prefixes {
+		if strings.HasPrefix(o.ID, p) {
+			matched = append(matched, p)
+		}
+	}
+
+	if len(matched) == 0 {
+		return nil
+	}
+
+	// Apply the shortest prefixes first.
+	sort.Slice(matched, func(i, j int) bool { return len(matched[i]) < len(matched[j]) })
+
+	ts := TagSet{}
+	for _, p := range matched {
+		for k, v := range prefixes[p] {
+			ts[k] = v
+		}
+	}
+
+	return ts
+}
+
+// EffectiveTags returns the tags of the object: those inherited from the
+// TagDefaults, overridden by those stored. An empty TagSet is returned for
+// objects with neither.
+func (t *TagHandler) EffectiveTags(o KafkaObject) (TagSet, error) {
+	stored, err := t.Store.GetTags(o)
+	if err != nil && err != ErrKafkaObjectDoesNotExist {
+		return nil, err
+	}
+
+	return t.withInherited(o, stored), nil
+}
+
+// withInherited returns a copy of the TagSet of the object
+// merged over the tags it inherits from the TagDefaults.
+func (t *TagHandler) withInherited(o KafkaObject, ts TagSet) TagSet {
+	merged := TagSet{}
+	for k, v := range t.Defaults.inherited(o) {
+		merged[k] = v
+	}
+
+	for k, v := range ts {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// validTagKey returns an error if the tag key is empty or a malformed
+// namespaced key; namespaced keys are of the form <namespace>/<key>.
+func validTagKey(k string) error {
+	if k == "" {
+		return fmt.Errorf("tag keys must be non-empty")
+	}
+
+	if !strings.Contains(k, tagNamespaceSep) {
+		return nil
+	}
+
+	parts := strings.Split(k, tagNamespaceSep)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || parts[1] == tagNamespaceWildcard {
+		return fmt.Errorf("invalid tag key '%s': namespaced keys must be of the form <namespace>/<key>", k)
+	}
+
+	return nil
+}
+
+// expandTagKeys returns the keys with any namespace wildcards
+// (<namespace>/*) replaced by the keys of the namespace in
+// the TagSet, sorted.
+func expandTagKeys(ts TagSet, keys Tags) Tags {
+	var expanded Tags
+
+	for _, k := range keys {
+		ns := strings.TrimSuffix(k, tagNamespaceSep+tagNamespaceWildcard)
+		if ns == k || ns == "" {
+			expanded = append(expanded, k)
+			continue
+		}
+
+		var matched Tags
+		for key := range ts {
+			if strings.HasPrefix(key, ns+tagNamespaceSep) {
+				matched = append(matched, key)
+			}
+		}
+
+		sort.Strings(matched)
+		expanded = append(expanded, matched...)
+	}
+
+	return expanded
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+func testTagDefaults(t *testing.T, data string) (*TagDefaults, error) {
+	f, err := ioutil.TempFile("", "tagdefaults")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+
+	f.WriteString(data)
+	f.Close()
+
+	return readTagDefaults(f.Name())
+}
+
+func testTagDefaultsServer(t *testing.T) *Server {
+	d, err := testTagDefaults(t, `{
+		"topic": {
+			"": {"ownership/team": "platform", "placement/tier": "standard"},
+			"test_": {"placement/tier": "fast"},
+			"test_topic2": {"retention/class": "short"}
+		},
+		"broker": {
+			"100": {"placement/pool": "general"}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := testServer()
+	s.Tags.Defaults = d
+
+	return s
+}
+
+func TestReadTagDefaults(t *testing.T) {
+	tests := map[int]string{
+		0: `{"topic": {"": {"placement/": "x"}}}`,
+		1: `{"topic": {"": {"a/b/c": "x"}}}`,
+		2: `{"broker": {"10": {"rack": "a"}}}`,
+		3: `{"topic": []}`,
+	}
+
+	expected := map[int]string{
+		0: "tag defaults for topic prefix '': invalid tag key 'placement/': namespaced keys must be of the form <namespace>/<key>",
+		1: "tag defaults for topic prefix '': invalid tag key 'a/b/c': namespaced keys must be of the form <namespace>/<key>",
+		2: "tag defaults for broker prefix '10': tag 'rack' is a reserved tag",
+		3: "error parsing tag defaults: json: cannot unmarshal array into Go struct field TagDefaults.topic of type map[string]server.TagSet",
+	}
+
+	for i, data := range tests {
+		if _, err := testTagDefaults(t, data); err == nil || err.Error() != expected[i] {
+			t.Errorf("[test %d] Expected error '%s', got '%v'", i, expected[i], err)
+		}
+	}
+}
+
+func TestEffectiveTags(t *testing.T) {
+	s := testTagDefaultsServer(t)
+	s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: "test_topic2"}, TagSet{"ownership/team": "core"})
+
+	tests := map[int]KafkaObject{
+		0: KafkaObject{Type: "topic", ID: "test_topic"},
+		1: KafkaObject{Type: "topic", ID: "test_topic2"},
+		2: KafkaObject{Type: "topic", ID: "other"},
+		3: KafkaObject{Type: "broker", ID: "1001"},
+		4: KafkaObject{Type: "broker", ID: "2001"},
+	}
+
+	expected := map[int]TagSet{
+		0: TagSet{"ownership/team": "platform", "placement/tier": "fast"},
+		1: TagSet{"ownership/team": "core", "placement/tier": "fast", "retention/class": "short"},
+		2: TagSet{"ownership/team": "platform", "placement/tier": "standard"},
+		3: TagSet{"placement/pool": "general"},
+		4: TagSet{},
+	}
+
+	for i, o := range tests {
+		ts, err := s.Tags.EffectiveTags(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(ts, expected[i]) {
+			t.Errorf("[test %d] Expected tags %v, got %v", i, expected[i], ts)
+		}
+	}
+
+	// Inherited tags are returned and queryable.
+	resp, err := s.ListTopics(context.Background(), &pb.TopicRequest{TagQuery: "retention/class=short"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !stringsEqual(resp.Names, []string{"test_topic2"}) {
+		t.Errorf("Expected topics [test_topic2], got %v", resp.Names)
+	}
+
+	topics, err := s.GetTopics(context.Background(), &pb.TopicRequest{Name: "test_topic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tags := topics.Topics["test_topic"].Tags; tags["placement/tier"] != "fast" {
+		t.Errorf("Expected inherited tag placement/tier:fast, got %v", tags)
+	}
+
+	// Only stored tags are stored.
+	if stored := s.storedTags(KafkaObject{Type: "topic", ID: "test_topic"}); len(stored) != 0 {
+		t.Errorf("Expected no stored tags, got %v", stored)
+	}
+}
+
+func TestNamespacedTagKeys(t *testing.T) {
+	s := testServer()
+
+	tests := map[int][]string{
+		0: []string{"placement/:fast"},
+		1: []string{"/tier:fast"},
+		2: []string{"placement/tier/zone:a"},
+		3: []string{"placement/*:fast"},
+	}
+
+	for i, tags := range tests {
+		if _, err := s.TagTopic(context.Background(), &pb.TopicRequest{Name: "test_topic", Tag: tags}); err == nil {
+			t.Errorf("[test %d] Expected invalid tag key error for %v", i, tags)
+		}
+	}
+
+	if _, err := s.TagTopic(context.Background(), &pb.TopicRequest{Name: "test_topic", Tag: []string{"placement/tier:fast"}}); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+}
+
+func TestDeleteNamespacedTags(t *testing.T) {
+	s := testServer()
+
+	o := KafkaObject{Type: "topic", ID: "test_topic"}
+	s.Tags.Store.SetTags(o, TagSet{"placement/tier": "fast", "placement/zone": "a", "ownership/team": "core", "placement": "x"})
+
+	_, err := s.DeleteTopicTags(context.Background(), &pb.TopicRequest{Name: "test_topic", Tag: []string{"placement/*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := TagSet{"ownership/team": "core", "placement": "x"}
+	if stored := s.storedTags(o); !reflect.DeepEqual(stored, expected) {
+		t.Errorf("Expected tags %v, got %v", expected, stored)
+	}
+
+	// Wildcards matching no keys are a no-op.
+	if _, err := s.DeleteTopicTags(context.Background(), &pb.TopicRequest{Name: "test_topic", Tag: []string{"retention/*"}}); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	b := KafkaObject{Type: "broker", ID: "1001"}
+	s.Tags.Store.SetTags(b, TagSet{"placement/pool": "general", "maintenance": "true"})
+
+	if _, err := s.DeleteBrokerTags(context.Background(), &pb.BrokerRequest{Id: 1001, Tag: []string{"placement/*"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected = TagSet{"maintenance": "true"}
+	if stored := s.storedTags(b); !reflect.DeepEqual(stored, expected) {
+		t.Errorf("Expected tags %v, got %v", expected, stored)
+	}
+}
@@ -0,0 +1,315 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// TagExpr is a tag expression that matches TagSets. Expressions are
+// composed of tag predicates joined by AND, OR and NOT (in order of
+// increasing precedence) and grouped by parentheses:
+//
+//	key          the tag is set (to a non-empty value)
+//	key=value    the tag value matches the value
+//	key!=value   the tag value doesn't match the value, or the tag isn't set
+//
+// Values may contain * (any characters) and ? (any single character)
+// wildcards. Keys and values containing spaces, parentheses, = or ! or
+// that are keywords (AND, OR, NOT; case-insensitive) must be quoted with
+// double quotes, e.g. team="data eng" AND NOT "or".
+type TagExpr interface {
+	Match(TagSet) bool
+}
+
+// ParseTagExpr parses the tag expression s. A nil
+// TagExpr is returned if s is empty or only spaces.
+func ParseTagExpr(s string) (TagExpr, error) {
+	tokens, err := lexTagExpr(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tag expression: %s", err)
+	}
+
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	p := &tagExprParser{tokens: tokens}
+
+	e, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %s", p.tokens[p.pos])
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid tag expression: %s", err)
+	}
+
+	return e, nil
+}
+
+// matchTagExpr returns whether the TagSet matches
+// the TagExpr; all TagSets match a nil TagExpr.
+func matchTagExpr(e TagExpr, ts TagSet) bool {
+	return e == nil || e.Match(ts)
+}
+
+type tagAnd struct{ l, r TagExpr }
+
+func (e tagAnd) Match(ts TagSet) bool { return e.l.Match(ts) && e.r.Match(ts) }
+
+type tagOr struct{ l, r TagExpr }
+
+func (e tagOr) Match(ts TagSet) bool { return e.l.Match(ts) || e.r.Match(ts) }
+
+type tagNot struct{ e TagExpr }
+
+func (e tagNot) Match(ts TagSet) bool { return !e.e.Match(ts) }
+
+type tagExists struct{ key string }
+
+func (e tagExists) Match(ts TagSet) bool { return ts[e.key] != "" }
+
+// tagValue matches tags with values matching
+// the value glob, or not matching if negated.
+type tagValue struct {
+	key     string
+	value   *regexp.Regexp
+	negated bool
+}
+
+func (e tagValue) Match(ts TagSet) bool {
+	v, set := ts[e.key]
+	return (set && e.value.MatchString(v)) != e.negated
+}
+
+// globRegexp returns a *regexp.Regexp matching
+// the glob with * and ? wildcards.
+func globRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+
+	for _, c := range glob {
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("$")
+
+	return regexp.MustCompile(b.String())
+}
+
+// Tag expression token types.
+const (
+	tokenWord = iota
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+	tokenEq
+	tokenNotEq
+)
+
+type tagExprToken struct {
+	typ int
+	val string
+	pos int
+}
+
+func (t tagExprToken) String() string {
+	return fmt.Sprintf("'%s' at position %d", t.val, t.pos)
+}
+
+// lexTagExpr splits the tag expression s into tokens.
+func lexTagExpr(s string) ([]tagExprToken, error) {
+	var tokens []tagExprToken
+	r := []rune(s)
+
+	for i := 0; i < len(r); {
+		switch c := r[i]; {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, tagExprToken{tokenLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, tagExprToken{tokenRParen, ")", i})
+			i++
+		case c == '=':
+			tokens = append(tokens, tagExprToken{tokenEq, "=", i})
+			i++
+		case c == '!':
+			if i+1 == len(r) || r[i+1] != '=' {
+				return nil, fmt.Errorf("expected '!=' at position %d", i)
+			}
+			tokens = append(tokens, tagExprToken{tokenNotEq, "!=", i})
+			i += 2
+		case c == '"':
+			var b strings.Builder
+			start := i
+
+			for i++; ; i++ {
+				if i == len(r) {
+					return nil, fmt.Errorf("unterminated quote at position %d", start)
+				}
+
+				if r[i] == '\\' && i+1 < len(r) {
+					i++
+				} else if r[i] == '"' {
+					break
+				}
+
+				b.WriteRune(r[i])
+			}
+
+			tokens = append(tokens, tagExprToken{tokenWord, b.String(), start})
+			i++
+		default:
+			start := i
+			for i < len(r) && !unicode.IsSpace(r[i]) && !strings.ContainsRune(`()=!"`, r[i]) {
+				i++
+			}
+
+			w := string(r[start:i])
+			t := tagExprToken{tokenWord, w, start}
+
+			switch strings.ToUpper(w) {
+			case "AND":
+				t.typ = tokenAnd
+			case "OR":
+				t.typ = tokenOr
+			case "NOT":
+				t.typ = tokenNot
+			}
+
+			tokens = append(tokens, t)
+		}
+	}
+
+	return tokens, nil
+}
+
+// tagExprParser is a recursive descent parser of tag expression tokens.
+type tagExprParser struct {
+	tokens []tagExprToken
+	pos    int
+}
+
+// next returns the next token if of the type,
+// advancing the parser, and whether it was.
+func (p *tagExprParser) next(typ int) (tagExprToken, bool) {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].typ == typ {
+		p.pos++
+		return p.tokens[p.pos-1], true
+	}
+
+	return tagExprToken{}, false
+}
+
+// unexpected returns an error for the next token.
+func (p *tagExprParser) unexpected() error {
+	if p.pos == len(p.tokens) {
+		return fmt.Errorf("unexpected end of expression")
+	}
+
+	return fmt.Errorf("unexpected %s", p.tokens[p.pos])
+}
+
+func (p *tagExprParser) parseOr() (TagExpr, error) {
+	e, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.next(tokenOr); !ok {
+			return e, nil
+		}
+
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		e = tagOr{e, r}
+	}
+}
+
+func (p *tagExprParser) parseAnd() (TagExpr, error) {
+	e, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.next(tokenAnd); !ok {
+			return e, nil
+		}
+
+		r, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		e = tagAnd{e, r}
+	}
+}
+
+func (p *tagExprParser) parseNot() (TagExpr, error) {
+	if _, ok := p.next(tokenNot); ok {
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return tagNot{e}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *tagExprParser) parsePrimary() (TagExpr, error) {
+	if _, ok := p.next(tokenLParen); ok {
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := p.next(tokenRParen); !ok {
+			return nil, p.unexpected()
+		}
+
+		return e, nil
+	}
+
+	key, ok := p.next(tokenWord)
+	if !ok {
+		return nil, p.unexpected()
+	}
+
+	_, eq := p.next(tokenEq)
+
+	var notEq bool
+	if !eq {
+		_, notEq = p.next(tokenNotEq)
+	}
+
+	if !eq && !notEq {
+		return tagExists{key.val}, nil
+	}
+
+	value, ok := p.next(tokenWord)
+	if !ok {
+		return nil, p.unexpected()
+	}
+
+	return tagValue{key: key.val, value: globRegexp(value.val), negated: notEq}, nil
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestParseTagExpr(t *testing.T) {
+	ts := TagSet{
+		"team":  "ingest",
+		"tier":  "prod",
+		"rack":  "us-east-1a",
+		"owner": "",
+		"or":    "x",
+		"desc":  "data eng",
+	}
+
+	tests := map[string]bool{
+		"team=ingest":                true,
+		"team=ingest AND tier!=test": true,
+		"team=ingest and tier=test":  false,
+		"team=billing OR tier=prod":  true,
+		"NOT team=ingest":            false,
+		"NOT NOT team=ingest":        true,
+		"team":                       true,
+		"owner":                      false,
+		"missing":                    false,
+		"missing!=value":             true,
+		"rack=us-east-1*":            true,
+		"rack=us-east-1?":            true,
+		"rack=us-east-?":             false,
+		"rack=*.1a":                  false,
+		"team=billing OR team=ingest AND tier=test":   false,
+		"(team=billing OR team=ingest) AND tier=prod": true,
+		`desc="data eng" AND "or"=x`:                  true,
+		`NOT ("or" OR team)`:                          false,
+	}
+
+	for s, expected := range tests {
+		e, err := ParseTagExpr(s)
+		if err != nil {
+			t.Errorf("[%s] Unexpected error: %s", s, err)
+			continue
+		}
+
+		if m := e.Match(ts); m != expected {
+			t.Errorf("[%s] Expected match %v, got %v", s, expected, m)
+		}
+	}
+
+	if e, err := ParseTagExpr("  "); e != nil || err != nil {
+		t.Errorf("Expected nil TagExpr and error, got %v, %v", e, err)
+	}
+
+	errors := map[string]string{
+		"team=":            "invalid tag expression: unexpected end of expression",
+		"team=ingest tier": "invalid tag expression: unexpected 'tier' at position 12",
+		"(team":            "invalid tag expression: unexpected end of expression",
+		"team=ingest AND)": "invalid tag expression: unexpected ')' at position 15",
+		"team ! ingest":    "invalid tag expression: expected '!=' at position 5",
+		`team="ingest`:     "invalid tag expression: unterminated quote at position 5",
+		"OR team":          "invalid tag expression: unexpected 'OR' at position 0",
+	}
+
+	for s, expected := range errors {
+		if _, err := ParseTagExpr(s); err == nil || err.Error() != expected {
+			t.Errorf("[%s] Expected error '%s', got '%v'", s, expected, err)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrTagUpdateConflict error.
+	ErrTagUpdateConflict = errors.New("tags were concurrently modified; retry the request")
+)
+
+// etcdTxnAttempts is the number of attempts made to
+// update tags when the stored tags changed between
+// the read and the conditional write.
+const etcdTxnAttempts = 5
+
+// EtcdTagStorage implements tag persistence in etcd (v3.4+) through the
+// etcd JSON gRPC gateway. Each object's TagSet is stored at the key
+// /[prefix]/[type]/[id] and updated with transactions conditional on the
+// key revision, so that concurrent updates from any number of registries
+// are never lost.
+type EtcdTagStorage struct {
+	ReservedFields ReservedFields
+	Prefix         string
+	Endpoints      []string
+	Client         *http.Client
+}
+
+// EtcdTagStorageConfig holds EtcdTagStorage configs.
+type EtcdTagStorageConfig struct {
+	// Comma-delimited list of endpoint URLs,
+	// e.g. http://etcd-0:2379.
+	Endpoints string
+	Prefix    string
+}
+
+// NewEtcdTagStorage initializes an EtcdTagStorage.
+func NewEtcdTagStorage(c EtcdTagStorageConfig) (*EtcdTagStorage, error) {
+	if c.Prefix == "" {
+		return nil, fmt.Errorf("prefix required")
+	}
+
+	var endpoints []string
+	for _, e := range strings.Split(c.Endpoints, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			endpoints = append(endpoints, strings.TrimSuffix(e, "/"))
+		}
+	}
+
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("endpoints required")
+	}
+
+	return &EtcdTagStorage{
+		Prefix:    strings.Trim(c.Prefix, "/"),
+		Endpoints: endpoints,
+		Client:    &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+// etcdKV is a key-value pair. Bytes
+// are base64 encoded in the gateway.
+type etcdKV struct {
+	Key         []byte `json:"key"`
+	Value       []byte `json:"value"`
+	ModRevision int64  `json:"mod_revision,string"`
+}
+
+type etcdRangeRequest struct {
+	Key       []byte `json:"key"`
+	CountOnly bool   `json:"count_only,omitempty"`
+}
+
+type etcdRangeResponse struct {
+	Kvs []etcdKV `json:"kvs"`
+}
+
+type etcdPutRequest struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+type etcdCompare struct {
+	Key         []byte `json:"key"`
+	Result      string `json:"result"`
+	Target      string `json:"target"`
+	ModRevision int64  `json:"mod_revision,string"`
+}
+
+type etcdRequestOp struct {
+	RequestPut *etcdPutRequest `json:"request_put"`
+}
+
+type etcdTxnRequest struct {
+	Compare []etcdCompare   `json:"compare"`
+	Success []etcdRequestOp `json:"success"`
+}
+
+type etcdTxnResponse struct {
+	Succeeded bool `json:"succeeded"`
+}
+
+type etcdError struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// Init ensures etcd is reachable.
+func (t *EtcdTagStorage) Init() error {
+	req := etcdRangeRequest{Key: []byte("/" + t.Prefix), CountOnly: true}
+	if err := t.call("/v3/kv/range", req, &etcdRangeResponse{}); err != nil {
+		return fmt.Errorf("failed to reach etcd: %s", err)
+	}
+
+	return nil
+}
+
+// call sends the request to the gateway API path and decodes the response
+// into resp. Endpoints are tried in order until one is reachable.
+func (t *EtcdTagStorage) call(path string, req, resp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	var r *http.Response
+	for _, e := range t.Endpoints {
+		if r, err = t.Client.Post(e+path, "application/json", bytes.NewReader(body)); err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer r.Body.Close()
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	if r.StatusCode != http.StatusOK {
+		e := etcdError{}
+		if json.Unmarshal(data, &e) == nil && (e.Message != "" || e.Error != "") {
+			if e.Message == "" {
+				e.Message = e.Error
+			}
+			return fmt.Errorf("etcd error: %s", e.Message)
+		}
+		return fmt.Errorf("etcd error: %s", r.Status)
+	}
+
+	return json.Unmarshal(data, resp)
+}
+
+// key returns the key of the KafkaObject.
+func (t *EtcdTagStorage) key(o KafkaObject) []byte {
+	return []byte(fmt.Sprintf("/%s/%s/%s", t.Prefix, o.Type, o.ID))
+}
+
+// get returns the stored TagSet and mod revision
+// of the key. A nil TagSet is returned if the key
+// doesn't exist.
+func (t *EtcdTagStorage) get(key []byte) (TagSet, int64, error) {
+	resp := etcdRangeResponse{}
+	if err := t.call("/v3/kv/range", etcdRangeRequest{Key: key}, &resp); err != nil {
+		return nil, 0, err
+	}
+
+	if len(resp.Kvs) == 0 {
+		return nil, 0, nil
+	}
+
+	tags := TagSet{}
+	if len(resp.Kvs[0].Value) != 0 {
+		if err := json.Unmarshal(resp.Kvs[0].Value, &tags); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return tags, resp.Kvs[0].ModRevision, nil
+}
+
+// update takes a key and func that returns the updated TagSet from the
+// stored TagSet (nil if the key doesn't exist). The updated TagSet is
+// written if the key is unmodified since it was read, otherwise the
+// update is retried.
+func (t *EtcdTagStorage) update(key []byte, fn func(TagSet) (TagSet, error)) error {
+	for i := 0; i < etcdTxnAttempts; i++ {
+		stored, rev, err := t.get(key)
+		if err != nil {
+			return err
+		}
+
+		tags, err := fn(stored)
+		if err != nil {
+			return err
+		}
+
+		out, err := json.Marshal(tags)
+		if err != nil {
+			return err
+		}
+
+		// The mod revision of a
+		// missing key is 0.
+		req := etcdTxnRequest{
+			Compare: []etcdCompare{{Key: key, Result: "EQUAL", Target: "MOD", ModRevision: rev}},
+			Success: []etcdRequestOp{{RequestPut: &etcdPutRequest{Key: key, Value: out}}},
+		}
+
+		resp := etcdTxnResponse{}
+		if err := t.call("/v3/kv/txn", req, &resp); err != nil {
+			return err
+		}
+
+		if resp.Succeeded {
+			return nil
+		}
+	}
+
+	return ErrTagUpdateConflict
+}
+
+// SetTags takes a KafkaObject and TagSet and sets the
+// tag key:values for the object.
+func (t *EtcdTagStorage) SetTags(o KafkaObject, ts TagSet) error {
+	if err := checkSetTags(t, o, ts); err != nil {
+		return err
+	}
+
+	return t.update(t.key(o), func(stored TagSet) (TagSet, error) {
+		tags := TagSet{}
+		for k, v := range stored {
+			tags[k] = v
+		}
+
+		for k, v := range ts {
+			tags[k] = v
+		}
+
+		return tags, nil
+	})
+}
+
+// GetTags returns the TagSet for the requested KafkaObject.
+func (t *EtcdTagStorage) GetTags(o KafkaObject) (TagSet, error) {
+	if !o.Complete() {
+		return nil, ErrInvalidKafkaObjectType
+	}
+
+	tags, _, err := t.get(t.key(o))
+	if err != nil {
+		return nil, err
+	}
+
+	if tags == nil {
+		return nil, ErrKafkaObjectDoesNotExist
+	}
+
+	return tags, nil
+}
+
+// DeleteTags deletes all tags in the Tags for the requested KafkaObject.
+func (t *EtcdTagStorage) DeleteTags(o KafkaObject, ts Tags) error {
+	if !o.Complete() {
+		return ErrInvalidKafkaObjectType
+	}
+
+	if len(ts) == 0 {
+		return ErrNilTags
+	}
+
+	return t.update(t.key(o), func(stored TagSet) (TagSet, error) {
+		if stored == nil {
+			return nil, ErrKafkaObjectDoesNotExist
+		}
+
+		for _, k := range ts {
+			delete(stored, k)
+		}
+
+		return stored, nil
+	})
+}
+
+// FieldReserved takes a KafkaObject and field name. A bool
+// is returned that indicates whether the field is reserved
+// for the respective KafkaObject type.
+func (t *EtcdTagStorage) FieldReserved(o KafkaObject, f string) bool {
+	if !o.Valid() {
+		return false
+	}
+
+	_, ok := t.ReservedFields[o.Type][f]
+
+	return ok
+}
+
+// LoadReservedFields takes a ReservedFields and stores it at
+// EtcdTagStorage.ReservedFields and returns an error.
+func (t *EtcdTagStorage) LoadReservedFields(r ReservedFields) error {
+	t.ReservedFields = r
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// etcdMock is a minimal etcd JSON gateway serving range
+// and txn (compare mod revision, put) requests.
+type etcdMock struct {
+	sync.Mutex
+	kvs      map[string]etcdKV
+	revision int64
+	// Concurrent writes to inject on
+	// the next range requests.
+	conflicts int
+}
+
+func (m *etcdMock) put(key, value []byte) {
+	m.revision++
+	m.kvs[string(key)] = etcdKV{Key: key, Value: value, ModRevision: m.revision}
+}
+
+func (m *etcdMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.Lock()
+	defer m.Unlock()
+
+	switch r.URL.Path {
+	case "/v3/kv/range":
+		req := etcdRangeRequest{}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		resp := etcdRangeResponse{}
+		if kv, exists := m.kvs[string(req.Key)]; exists && !req.CountOnly {
+			resp.Kvs = append(resp.Kvs, kv)
+		}
+
+		if m.conflicts > 0 {
+			m.conflicts--
+			m.put(req.Key, []byte(`{"team":"other"}`))
+		}
+
+		json.NewEncoder(w).Encode(resp)
+	case "/v3/kv/txn":
+		req := etcdTxnRequest{}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		resp := etcdTxnResponse{}
+		if c := req.Compare[0]; m.kvs[string(c.Key)].ModRevision == c.ModRevision {
+			put := req.Success[0].RequestPut
+			m.put(put.Key, put.Value)
+			resp.Succeeded = true
+		}
+
+		json.NewEncoder(w).Encode(resp)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"Not Found","code":5,"message":"Not Found"}`))
+	}
+}
+
+func TestEtcdTagStorage(t *testing.T) {
+	m := &etcdMock{kvs: map[string]etcdKV{}}
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	// The first endpoint is unreachable.
+	s, err := NewEtcdTagStorage(EtcdTagStorageConfig{
+		Endpoints: "http://127.0.0.1:1, " + srv.URL,
+		Prefix:    "registry",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.LoadReservedFields(GetReservedFields())
+
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	topic := KafkaObject{Type: "topic", ID: "test_topic"}
+
+	if _, err := s.GetTags(topic); err != ErrKafkaObjectDoesNotExist {
+		t.Errorf("Expected error '%s', got '%v'", ErrKafkaObjectDoesNotExist, err)
+	}
+
+	if err := s.SetTags(topic, TagSet{"team": "data", "owner": "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, exists := m.kvs["/registry/topic/test_topic"]; !exists {
+		t.Errorf("Expected key /registry/topic/test_topic, got %v", m.kvs)
+	}
+
+	if err := s.DeleteTags(topic, Tags{"owner"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := s.GetTags(topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (TagSet{"team": "data"}); !tags.Equal(expected) {
+		t.Errorf("Expected tags %v, got %v", expected, tags)
+	}
+
+	// Updates are retried on conflicts; the
+	// concurrently written tags are kept.
+	m.conflicts = 1
+	if err := s.SetTags(topic, TagSet{"env": "prod"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, _ = s.GetTags(topic)
+	if expected := (TagSet{"team": "other", "env": "prod"}); !tags.Equal(expected) {
+		t.Errorf("Expected tags %v, got %v", expected, tags)
+	}
+
+	m.conflicts = etcdTxnAttempts
+	if err := s.SetTags(topic, TagSet{"env": "dev"}); err != ErrTagUpdateConflict {
+		t.Errorf("Expected error '%s', got '%v'", ErrTagUpdateConflict, err)
+	}
+
+	if err := s.DeleteTags(KafkaObject{Type: "broker", ID: "1001"}, Tags{"pool"}); err != ErrKafkaObjectDoesNotExist {
+		t.Errorf("Expected error '%s', got '%v'", ErrKafkaObjectDoesNotExist, err)
+	}
+
+	// Gateway errors.
+	if err := s.call("/v3/kv/missing", etcdRangeRequest{}, &etcdRangeResponse{}); err == nil || err.Error() != "etcd error: Not Found" {
+		t.Errorf("Expected error 'etcd error: Not Found', got '%v'", err)
+	}
+}
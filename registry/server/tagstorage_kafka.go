@@ -0,0 +1,252 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
+)
+
+// kafkaTagFetchBytes is the maximum bytes
+// fetched per tags topic fetch request.
+const kafkaTagFetchBytes = 1 << 20
+
+// TagLog is the Kafka API used by the KafkaTagStorage,
+// implemented by the *kafkaadmin.Client.
+type TagLog interface {
+	DescribeConfigs(kafkaadmin.ResourceType, string, []string) (map[string]kafkaadmin.ConfigEntry, error)
+	Produce(string, int, []kafkaadmin.Record) (int64, error)
+	Fetch(string, int, int64, int) ([]kafkaadmin.Record, int64, error)
+}
+
+// KafkaTagStorage implements tag persistence in partition 0 of a compacted
+// Kafka topic. Each record holds the complete TagSet of an object, keyed by
+// the object type and ID (e.g. topic/events). The topic is consumed into
+// memory and caught up before each operation so that registries sharing
+// the topic observe each other's changes; concurrent changes to the tags of
+// the same object from different registries are last write wins.
+type KafkaTagStorage struct {
+	ReservedFields ReservedFields
+	Topic          string
+	Kafka          TagLog
+
+	mu sync.Mutex
+	// Tags by record key, as of offset.
+	tags   map[string]TagSet
+	offset int64
+}
+
+// KafkaTagStorageConfig holds KafkaTagStorage configs.
+type KafkaTagStorageConfig struct {
+	Topic string
+}
+
+// NewKafkaTagStorage initializes a KafkaTagStorage.
+func NewKafkaTagStorage(c KafkaTagStorageConfig) (*KafkaTagStorage, error) {
+	if c.Topic == "" {
+		return nil, fmt.Errorf("topic required")
+	}
+
+	// As with the ZKTagStorage, the Kafka client is
+	// shared / passed in by the parent registry Server
+	// during setup in the DialKafka call.
+	return &KafkaTagStorage{
+		Topic: c.Topic,
+		tags:  map[string]TagSet{},
+	}, nil
+}
+
+// Init ensures the tags topic is compacted and
+// consumes the current tags.
+func (t *KafkaTagStorage) Init() error {
+	if t.Kafka == nil {
+		return errors.New("the kafka backend requires a Kafka client")
+	}
+
+	configs, err := t.Kafka.DescribeConfigs(kafkaadmin.ResourceTopic, t.Topic, []string{"cleanup.policy"})
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(configs["cleanup.policy"].Value, "compact") {
+		return fmt.Errorf("topic %s must have cleanup.policy=compact", t.Topic)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.catchUp()
+}
+
+// catchUp consumes the tags topic from
+// the last offset to the high watermark.
+func (t *KafkaTagStorage) catchUp() error {
+	for {
+		records, hw, err := t.Kafka.Fetch(t.Topic, 0, t.offset, kafkaTagFetchBytes)
+		if err != nil {
+			return err
+		}
+
+		// Compacted or control records
+		// may precede the high watermark.
+		if len(records) == 0 {
+			if hw > t.offset {
+				t.offset = hw
+			}
+			return nil
+		}
+
+		for _, r := range records {
+			t.offset = r.Offset + 1
+
+			if r.Value == nil {
+				delete(t.tags, string(r.Key))
+				continue
+			}
+
+			tags := TagSet{}
+			if err := json.Unmarshal(r.Value, &tags); err != nil {
+				log.Printf("Skipping invalid tags record at offset %d: %s", r.Offset, err)
+				continue
+			}
+
+			t.tags[string(r.Key)] = tags
+		}
+
+		if t.offset >= hw {
+			return nil
+		}
+	}
+}
+
+// put persists the TagSet under the key.
+func (t *KafkaTagStorage) put(key string, tags TagSet) error {
+	out, err := json.Marshal(tags)
+	if err != nil {
+		return err
+	}
+
+	record := kafkaadmin.Record{Key: []byte(key), Value: out}
+	if _, err := t.Kafka.Produce(t.Topic, 0, []kafkaadmin.Record{record}); err != nil {
+		return err
+	}
+
+	t.tags[key] = tags
+
+	return nil
+}
+
+// SetTags takes a KafkaObject and TagSet and sets the
+// tag key:values for the object.
+func (t *KafkaTagStorage) SetTags(o KafkaObject, ts TagSet) error {
+	if err := checkSetTags(t, o, ts); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := t.catchUp(); err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%s/%s", o.Type, o.ID)
+
+	tags := TagSet{}
+	for k, v := range t.tags[key] {
+		tags[k] = v
+	}
+
+	for k, v := range ts {
+		tags[k] = v
+	}
+
+	return t.put(key, tags)
+}
+
+// GetTags returns the TagSet for the requested KafkaObject.
+func (t *KafkaTagStorage) GetTags(o KafkaObject) (TagSet, error) {
+	if !o.Complete() {
+		return nil, ErrInvalidKafkaObjectType
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := t.catchUp(); err != nil {
+		return nil, err
+	}
+
+	stored, exists := t.tags[fmt.Sprintf("%s/%s", o.Type, o.ID)]
+	if !exists {
+		return nil, ErrKafkaObjectDoesNotExist
+	}
+
+	tags := TagSet{}
+	for k, v := range stored {
+		tags[k] = v
+	}
+
+	return tags, nil
+}
+
+// DeleteTags deletes all tags in the Tags for the requested KafkaObject.
+func (t *KafkaTagStorage) DeleteTags(o KafkaObject, ts Tags) error {
+	if !o.Complete() {
+		return ErrInvalidKafkaObjectType
+	}
+
+	if len(ts) == 0 {
+		return ErrNilTags
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := t.catchUp(); err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%s/%s", o.Type, o.ID)
+
+	stored, exists := t.tags[key]
+	if !exists {
+		return ErrKafkaObjectDoesNotExist
+	}
+
+	tags := TagSet{}
+	for k, v := range stored {
+		tags[k] = v
+	}
+
+	for _, k := range ts {
+		delete(tags, k)
+	}
+
+	return t.put(key, tags)
+}
+
+// FieldReserved takes a KafkaObject and field name. A bool
+// is returned that indicates whether the field is reserved
+// for the respective KafkaObject type.
+func (t *KafkaTagStorage) FieldReserved(o KafkaObject, f string) bool {
+	if !o.Valid() {
+		return false
+	}
+
+	_, ok := t.ReservedFields[o.Type][f]
+
+	return ok
+}
+
+// LoadReservedFields takes a ReservedFields and stores it at
+// KafkaTagStorage.ReservedFields and returns an error.
+func (t *KafkaTagStorage) LoadReservedFields(r ReservedFields) error {
+	t.ReservedFields = r
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
+)
+
+// tagLogMock is an in-memory TagLog. Fetches
+// return at most two records.
+type tagLogMock struct {
+	records       []kafkaadmin.Record
+	cleanupPolicy string
+}
+
+func (l *tagLogMock) DescribeConfigs(_ kafkaadmin.ResourceType, _ string, _ []string) (map[string]kafkaadmin.ConfigEntry, error) {
+	return map[string]kafkaadmin.ConfigEntry{
+		"cleanup.policy": {Name: "cleanup.policy", Value: l.cleanupPolicy},
+	}, nil
+}
+
+func (l *tagLogMock) Produce(_ string, p int, rs []kafkaadmin.Record) (int64, error) {
+	if p != 0 {
+		return 0, errors.New("unexpected partition")
+	}
+
+	offset := int64(len(l.records))
+	for i, r := range rs {
+		r.Offset = offset + int64(i)
+		l.records = append(l.records, r)
+	}
+
+	return offset, nil
+}
+
+func (l *tagLogMock) Fetch(_ string, _ int, offset int64, _ int) ([]kafkaadmin.Record, int64, error) {
+	hw := int64(len(l.records))
+	if offset >= hw {
+		return nil, hw, nil
+	}
+
+	end := offset + 2
+	if end > hw {
+		end = hw
+	}
+
+	return l.records[offset:end], hw, nil
+}
+
+func TestKafkaTagStorage(t *testing.T) {
+	l := &tagLogMock{
+		cleanupPolicy: "compact",
+		records: []kafkaadmin.Record{
+			{Key: []byte("topic/test_topic"), Value: []byte(`{"team":"data"}`)},
+			{Key: []byte("topic/test_topic2"), Value: []byte(`{"team":"data"}`)},
+			{Key: []byte("topic/test_topic2")},
+			{Key: []byte("broker/1001"), Value: []byte(`not json`)},
+			{Key: []byte("broker/1002"), Value: []byte(`{"pool":"a"}`)},
+		},
+	}
+
+	for i := range l.records {
+		l.records[i].Offset = int64(i)
+	}
+
+	s, _ := NewKafkaTagStorage(KafkaTagStorageConfig{Topic: "registry-tags"})
+	s.LoadReservedFields(GetReservedFields())
+	s.Kafka = l
+
+	if err := s.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.offset != 5 || len(s.tags) != 2 {
+		t.Errorf("Expected offset 5 and 2 objects, got %d and %v", s.offset, s.tags)
+	}
+
+	topic := KafkaObject{Type: "topic", ID: "test_topic"}
+
+	if err := s.SetTags(topic, TagSet{"owner": "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes from other registries
+	// sharing the topic are observed.
+	l.Produce("registry-tags", 0, []kafkaadmin.Record{
+		{Key: []byte("topic/test_topic"), Value: []byte(`{"team":"data","owner":"alice","env":"prod"}`)},
+	})
+
+	if err := s.DeleteTags(topic, Tags{"owner"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := s.GetTags(topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := TagSet{"team": "data", "env": "prod"}
+	if !tags.Equal(expected) {
+		t.Errorf("Expected tags %v, got %v", expected, tags)
+	}
+
+	// Deleted and missing objects.
+	for _, o := range []KafkaObject{{Type: "topic", ID: "test_topic2"}, {Type: "broker", ID: "1001"}} {
+		if _, err := s.GetTags(o); err != ErrKafkaObjectDoesNotExist {
+			t.Errorf("Expected error '%s' for %v, got '%v'", ErrKafkaObjectDoesNotExist, o, err)
+		}
+	}
+
+	if err := s.SetTags(topic, TagSet{"partitions": "6"}); err != (ErrReservedTag{t: "partitions"}) {
+		t.Errorf("Expected a reserved tag error, got '%v'", err)
+	}
+
+	// Uncompacted topics.
+	l.cleanupPolicy = "delete"
+	if err := s.Init(); err == nil {
+		t.Error("Expected non-nil error")
+	}
+}
@@ -55,6 +55,7 @@ func (t *ZKTagStorage) Init() error {
 		fmt.Sprintf("/%s", t.Prefix),
 		fmt.Sprintf("/%s/broker", t.Prefix),
 		fmt.Sprintf("/%s/topic", t.Prefix),
+		fmt.Sprintf("/%s/%s", t.Prefix, rbacObject.Type),
 	}
 
 	for _, p := range baseZnodes {
@@ -77,20 +78,8 @@ func (t *ZKTagStorage) Init() error {
 // tag key:values for the object.
 func (t *ZKTagStorage) SetTags(o KafkaObject, ts TagSet) error {
 	// Sanity checks.
-	if !o.Complete() {
-		return ErrInvalidKafkaObjectType
-	}
-
-	if ts == nil || len(ts) == 0 {
-		return ErrNilTagSet
-	}
-
-	// Check if any reserved tags are being
-	// attempted for use.
-	for k := range ts {
-		if t.FieldReserved(o, k) {
-			return ErrReservedTag{t: k}
-		}
+	if err := checkSetTags(t, o, ts); err != nil {
+		return err
 	}
 
 	znode := fmt.Sprintf("/%s/%s/%s", t.Prefix, o.Type, o.ID)
@@ -234,3 +223,51 @@ func (t *ZKTagStorage) LoadReservedFields(r ReservedFields) error {
 
 	return nil
 }
+
+// MigrateZKTags copies the tags stored in ZooKeeper under the prefix to the
+// TagStorage for all objects that have no tags in it, returning the number
+// of objects copied. Objects with tags in the TagStorage are left as is, so
+// that migrations may be repeated.
+func MigrateZKTags(zk kafkazk.Handler, prefix string, dst TagStorage) (int, error) {
+	src := &ZKTagStorage{Prefix: prefix, ZK: zk}
+	var n int
+
+	for _, kind := range []string{"broker", "topic"} {
+		ids, err := zk.Children(fmt.Sprintf("/%s/%s", prefix, kind))
+		if err != nil {
+			if _, noNode := err.(kafkazk.ErrNoNode); noNode {
+				continue
+			}
+			return n, err
+		}
+
+		for _, id := range ids {
+			o := KafkaObject{Type: kind, ID: id}
+
+			tags, err := src.GetTags(o)
+			if err != nil {
+				return n, err
+			}
+
+			if len(tags) == 0 {
+				continue
+			}
+
+			switch _, err := dst.GetTags(o); err {
+			case ErrKafkaObjectDoesNotExist:
+			case nil:
+				continue
+			default:
+				return n, err
+			}
+
+			if err := dst.SetTags(o, tags); err != nil {
+				return n, err
+			}
+
+			n++
+		}
+	}
+
+	return n, nil
+}
@@ -26,10 +26,15 @@ func newzkTagStorageMock() *zkTagStorageMock {
 	return zks
 }
 
+// Init mocks Init.
+func (t *zkTagStorageMock) Init() error {
+	return nil
+}
+
 // SetTags mocks SetTags.
 func (t *zkTagStorageMock) SetTags(o KafkaObject, ts TagSet) error {
-	if !o.Complete() {
-		return ErrInvalidKafkaObjectType
+	if err := checkSetTags(t, o, ts); err != nil {
+		return err
 	}
 
 	if _, exist := t.tags[o.Type]; !exist {
@@ -279,8 +279,66 @@ func TestDeleteTagsFailures(t *testing.T) {
 	}
 }
 
-// Sort by string length.
+// migrateZK serves tag znodes by path.
+type migrateZK struct {
+	kafkazk.Mock
+	znodes map[string]string
+}
+
+func (zk *migrateZK) Children(p string) ([]string, error) {
+	switch p {
+	case "/old/topic":
+		return []string{"test_topic", "test_topic2", "test_topic3"}, nil
+	case "/old/broker":
+		return nil, kafkazk.ErrNoNode{}
+	}
 
+	return nil, nil
+}
+
+func (zk *migrateZK) Get(p string) ([]byte, error) {
+	return []byte(zk.znodes[p]), nil
+}
+
+func TestMigrateZKTags(t *testing.T) {
+	zk := &migrateZK{znodes: map[string]string{
+		"/old/topic/test_topic":  `{"team":"data"}`,
+		"/old/topic/test_topic2": `{"team":"web"}`,
+		// Without tags.
+		"/old/topic/test_topic3": `{}`,
+	}}
+
+	dst := newzkTagStorageMock()
+	dst.SetTags(KafkaObject{Type: "topic", ID: "test_topic2"}, TagSet{"team": "infra"})
+
+	n, err := MigrateZKTags(zk, "old", dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 1 {
+		t.Errorf("Expected 1 object migrated, got %d", n)
+	}
+
+	// Objects with tags aren't overwritten.
+	expected := map[string]TagSet{
+		"test_topic":  TagSet{"team": "data"},
+		"test_topic2": TagSet{"team": "infra"},
+	}
+
+	for id, e := range expected {
+		tags, _ := dst.GetTags(KafkaObject{Type: "topic", ID: id})
+		if !tags.Equal(e) {
+			t.Errorf("Expected tags %v for %s, got %v", e, id, tags)
+		}
+	}
+
+	if _, err := dst.GetTags(KafkaObject{Type: "topic", ID: "test_topic3"}); err != ErrKafkaObjectDoesNotExist {
+		t.Errorf("Expected error '%s', got '%v'", ErrKafkaObjectDoesNotExist, err)
+	}
+}
+
+// Sort by string length.
 type byLen []string
 
 func (a byLen) Len() int           { return len(a) }
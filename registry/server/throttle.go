@@ -3,14 +3,25 @@ package server
 import (
 	"context"
 	"errors"
+	"math"
+	"sync"
 	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
 	// ErrRequestThrottleTimeout error.
 	ErrRequestThrottleTimeout = errors.New("wait time exceeded")
+	// ErrClientRateLimited error.
+	ErrClientRateLimited = status.Error(codes.ResourceExhausted, "client request rate limit exceeded")
 )
 
+// clientThrottleSweepInterval is the minimum interval
+// between removals of idle client token buckets.
+const clientThrottleSweepInterval = time.Minute
+
 // RequestThrottle controls request rates with
 // a configurable burst capacity and per-second rate
 // backed with a token bucket.
@@ -69,3 +80,86 @@ func (t *requestThrottle) Request(ctx context.Context) error {
 
 	return nil
 }
+
+// clientThrottles controls request rates per client with a token
+// bucket for each. Unlike the RequestThrottle, requests exceeding
+// the rate are refused immediately rather than waiting, and buckets
+// are refilled lazily so that clients cost nothing while idle.
+type clientThrottles struct {
+	sync.Mutex
+	capacity float64
+	rate     float64
+	buckets  map[string]*clientBucket
+	swept    time.Time
+}
+
+type clientBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newClientThrottles initializes a *clientThrottles
+// from the RequestThrottleConfig.
+func newClientThrottles(cfg RequestThrottleConfig) (*clientThrottles, error) {
+	switch {
+	case cfg.Rate < 1:
+		return nil, errors.New("rate must be >= 1")
+	case cfg.Capacity < 1:
+		return nil, errors.New("capacity must be >= 1")
+	}
+
+	return &clientThrottles{
+		capacity: float64(cfg.Capacity),
+		rate:     float64(cfg.Rate),
+		buckets:  map[string]*clientBucket{},
+		swept:    time.Now(),
+	}, nil
+}
+
+// allow takes a client and attempts to acquire
+// a token from its bucket, returning whether
+// the request is allowed.
+func (c *clientThrottles) allow(client string) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(c.swept) > clientThrottleSweepInterval {
+		c.sweep(now)
+	}
+
+	b, exists := c.buckets[client]
+	if !exists {
+		b = &clientBucket{tokens: c.capacity, last: now}
+		c.buckets[client] = b
+	}
+
+	b.tokens = c.refilled(b, now)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+
+	return true
+}
+
+// refilled returns the tokens of the bucket at now.
+func (c *clientThrottles) refilled(b *clientBucket, now time.Time) float64 {
+	return math.Min(c.capacity, b.tokens+now.Sub(b.last).Seconds()*c.rate)
+}
+
+// sweep removes the buckets of idle clients, which
+// are those that have refilled to capacity.
+func (c *clientThrottles) sweep(now time.Time) {
+	for client, b := range c.buckets {
+		if c.refilled(b, now) >= c.capacity {
+			delete(c.buckets, client)
+		}
+	}
+
+	c.swept = now
+}
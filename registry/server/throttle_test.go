@@ -2,8 +2,12 @@ package server
 
 import (
 	"context"
+	"net"
 	"testing"
 	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 )
 
 func TestRequestThrottle(t *testing.T) {
@@ -34,3 +38,89 @@ func TestRequestThrottle(t *testing.T) {
 		}
 	}
 }
+
+func TestClientThrottles(t *testing.T) {
+	ct, _ := newClientThrottles(RequestThrottleConfig{
+		Capacity: 2,
+		Rate:     10,
+	})
+
+	expected := []bool{true, true, false}
+	for i, e := range expected {
+		if ct.allow("test") != e {
+			t.Errorf("[request %d] Expected allowed %v", i, e)
+		}
+	}
+
+	// Clients have independent buckets.
+	if !ct.allow("test2") {
+		t.Error("Expected request to be allowed")
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	if !ct.allow("test") {
+		t.Error("Expected request to be allowed after refill")
+	}
+
+	// Idle clients are removed.
+	time.Sleep(200 * time.Millisecond)
+	ct.sweep(time.Now())
+
+	if len(ct.buckets) != 0 {
+		t.Errorf("Expected 0 buckets, got %d", len(ct.buckets))
+	}
+}
+
+func peerContext(addr string, md metadata.MD) context.Context {
+	tcp, _ := net.ResolveTCPAddr("tcp", addr)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: tcp})
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+func TestClient(t *testing.T) {
+	s := testServer()
+	s.auth.tokens = map[string]string{"t1": "deploy-bot"}
+
+	tests := map[context.Context]string{
+		peerContext("10.0.1.5:53112", metadata.Pairs("authorization", "Bearer t1")): "deploy-bot",
+		peerContext("10.0.1.5:53112", nil):                                          "10.0.1.5",
+		// Via the HTTP gateway.
+		peerContext("127.0.0.1:53112", metadata.Pairs("x-forwarded-for", "10.0.1.6, 10.0.1.7")): "10.0.1.7",
+		// Only trusted from the gateway.
+		peerContext("10.0.1.5:53112", metadata.Pairs("x-forwarded-for", "10.0.1.6")): "10.0.1.5",
+	}
+
+	for ctx, expected := range tests {
+		if c := s.client(ctx); c != expected {
+			t.Errorf("Expected client '%s', got '%s'", expected, c)
+		}
+	}
+}
+
+func TestValidateRequestClientThrottle(t *testing.T) {
+	s := testServer()
+	s.clientWriteThrottle, _ = newClientThrottles(RequestThrottleConfig{
+		Capacity: 1,
+		Rate:     1,
+	})
+
+	ctx := peerContext("10.0.1.5:53112", nil)
+
+	if err := s.ValidateRequest(ctx, nil, writeRequest); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if err := s.ValidateRequest(ctx, nil, writeRequest); err != ErrClientRateLimited {
+		t.Errorf("Expected error '%s', got '%v'", ErrClientRateLimited, err)
+	}
+
+	// Other clients and reads are unaffected.
+	if err := s.ValidateRequest(peerContext("10.0.1.6:53112", nil), nil, writeRequest); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if err := s.ValidateRequest(ctx, nil, readRequest); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,334 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+var (
+	// ErrInvalidWatchType error.
+	ErrInvalidWatchType = errors.New("types must be one of broker, topic, config or tag")
+	// ErrWatchSubscriberBehind error.
+	ErrWatchSubscriberBehind = errors.New("watch subscriber fell behind; events were dropped")
+)
+
+// watchBufferSize is the number of events buffered per
+// subscriber. Subscribers that fall further behind are
+// disconnected.
+const watchBufferSize = 256
+
+// watchTypes is used as a set of valid watch event types.
+var watchTypes = map[string]struct{}{
+	"broker": struct{}{},
+	"topic":  struct{}{},
+	"config": struct{}{},
+	"tag":    struct{}{},
+}
+
+// watchHub fans out watch events to subscribers.
+type watchHub struct {
+	sync.Mutex
+	subs map[*watchSubscriber]struct{}
+}
+
+// watchSubscriber receives events of the types
+// subscribed to; all types if types is empty. The
+// events channel is closed if the subscriber falls
+// behind.
+type watchSubscriber struct {
+	types  map[string]struct{}
+	events chan *pb.WatchEvent
+}
+
+func newWatchHub() *watchHub {
+	return &watchHub{subs: map[*watchSubscriber]struct{}{}}
+}
+
+// subscribe returns a watchSubscriber for the types.
+func (h *watchHub) subscribe(types []string) *watchSubscriber {
+	sub := &watchSubscriber{
+		types:  map[string]struct{}{},
+		events: make(chan *pb.WatchEvent, watchBufferSize),
+	}
+
+	for _, t := range types {
+		sub.types[t] = struct{}{}
+	}
+
+	h.Lock()
+	h.subs[sub] = struct{}{}
+	h.Unlock()
+
+	return sub
+}
+
+// unsubscribe removes the watchSubscriber.
+func (h *watchHub) unsubscribe(sub *watchSubscriber) {
+	h.Lock()
+	delete(h.subs, sub)
+	h.Unlock()
+}
+
+// subscribers returns the number of subscribers.
+func (h *watchHub) subscribers() int {
+	h.Lock()
+	defer h.Unlock()
+
+	return len(h.subs)
+}
+
+// publish sends the event to all subscribers of its type.
+// Subscribers with a full buffer are removed.
+func (h *watchHub) publish(e *pb.WatchEvent) {
+	h.Lock()
+	defer h.Unlock()
+
+	for sub := range h.subs {
+		if _, subscribed := sub.types[e.Type]; len(sub.types) > 0 && !subscribed {
+			continue
+		}
+
+		select {
+		case sub.events <- e:
+		default:
+			delete(h.subs, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Watch takes a *pb.WatchRequest and streams a *pb.WatchEvent for each
+// cluster change of the types specified: broker registrations (added,
+// removed), topics (added, removed), dynamic config changes of any entity
+// (changed) and tag changes made through the registry (changed). Broker,
+// topic and config changes are observed by polling ZooKeeper at the watch
+// interval while there are subscribers.
+func (s *Server) Watch(req *pb.WatchRequest, stream pb.Registry_WatchServer) error {
+	ctx := stream.Context()
+
+	if err := s.ValidateRequest(ctx, req, readRequest); err != nil {
+		return err
+	}
+
+	s, err := s.cluster(req.Cluster)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range req.Types {
+		if _, valid := watchTypes[t]; !valid {
+			return ErrInvalidWatchType
+		}
+	}
+
+	sub := s.watchHub.subscribe(req.Types)
+	defer s.watchHub.unsubscribe(sub)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e, ok := <-sub.events:
+			if !ok {
+				return ErrWatchSubscriberBehind
+			}
+
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RunWatch runs the background pollers that publish broker, topic
+// and config changes to the Watch subscribers of each cluster.
+func (s *Server) RunWatch(ctx context.Context, wg *sync.WaitGroup) error {
+	for _, c := range s.clusterServers() {
+		c.runWatch(ctx, wg)
+	}
+
+	return nil
+}
+
+// runWatch runs the background poller of the cluster.
+func (s *Server) runWatch(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		t := time.NewTicker(s.watchInterval)
+		defer t.Stop()
+
+		var state *watchState
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+
+			// Changes are only tracked while
+			// there are subscribers.
+			if s.watchHub.subscribers() == 0 {
+				state = nil
+				continue
+			}
+
+			next, events, err := s.pollChanges(state)
+			if err != nil {
+				log.Printf("Error polling cluster changes: %s", err)
+				continue
+			}
+
+			for _, e := range events {
+				s.watchHub.publish(e)
+			}
+
+			state = next
+		}
+	}()
+}
+
+// watchState is the cluster state
+// compared between polls.
+type watchState struct {
+	brokers map[int]struct{}
+	topics  map[string]struct{}
+	// The sequence number of the last
+	// config change notification seen.
+	configSeq int64
+}
+
+// pollChanges fetches the current watchState and returns it along with the
+// events for all changes from the previous state. No events are returned
+// if the previous state is nil.
+func (s *Server) pollChanges(prev *watchState) (*watchState, []*pb.WatchEvent, error) {
+	state := &watchState{
+		brokers:   map[int]struct{}{},
+		topics:    map[string]struct{}{},
+		configSeq: -1,
+	}
+
+	// A partial broker list would
+	// be seen as broker removals.
+	brokers, errs := s.ZK.GetAllBrokerMeta(false)
+	if len(errs) > 0 {
+		return nil, nil, errs[0]
+	}
+
+	for id := range brokers {
+		state.brokers[id] = struct{}{}
+	}
+
+	topics, err := s.ZK.GetTopics([]*regexp.Regexp{tregex})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, t := range topics {
+		state.topics[t] = struct{}{}
+	}
+
+	if prev != nil {
+		state.configSeq = prev.configSeq
+	}
+
+	// Config change notifications aren't
+	// available via the Kafka Admin API.
+	changes, err := s.ZK.GetConfigChanges(state.configSeq)
+	if _, noNode := err.(kafkazk.ErrNoNode); err != nil && !noNode && err != kafkaadmin.ErrNotSupported {
+		return nil, nil, err
+	}
+
+	if len(changes) > 0 {
+		state.configSeq = changes[len(changes)-1].Seq
+	}
+
+	if prev == nil {
+		return state, nil, nil
+	}
+
+	ts := time.Now().Unix()
+	var events []*pb.WatchEvent
+
+	event := func(kind, action, name string) {
+		events = append(events, &pb.WatchEvent{Type: kind, Action: action, Name: name, Timestamp: ts, Cluster: s.clusterName})
+	}
+
+	// Brokers.
+	for _, id := range sortedIDs(state.brokers, prev.brokers) {
+		event("broker", "added", strconv.Itoa(id))
+	}
+
+	for _, id := range sortedIDs(prev.brokers, state.brokers) {
+		event("broker", "removed", strconv.Itoa(id))
+	}
+
+	// Topics.
+	for _, t := range sortedNames(state.topics, prev.topics) {
+		event("topic", "added", t)
+	}
+
+	for _, t := range sortedNames(prev.topics, state.topics) {
+		event("topic", "removed", t)
+	}
+
+	// Configs.
+	for _, c := range changes {
+		event("config", "changed", c.EntityPath)
+	}
+
+	return state, events, nil
+}
+
+// publishTagChange publishes a tag change event for the KafkaObject.
+func (s *Server) publishTagChange(o KafkaObject) {
+	s.watchHub.publish(&pb.WatchEvent{
+		Type:      "tag",
+		Action:    "changed",
+		Name:      fmt.Sprintf("%ss/%s", o.Type, o.ID),
+		Timestamp: time.Now().Unix(),
+		Cluster:   s.clusterName,
+	})
+}
+
+// sortedIDs returns the sorted IDs in a that aren't in b.
+func sortedIDs(a, b map[int]struct{}) []int {
+	var ids []int
+	for id := range a {
+		if _, exists := b[id]; !exists {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
+// sortedNames returns the sorted names in a that aren't in b.
+func sortedNames(a, b map[string]struct{}) []string {
+	var names []string
+	for n := range a {
+		if _, exists := b[n]; !exists {
+			names = append(names, n)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
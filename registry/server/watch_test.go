@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/honeycombio/kafka-kit/kafkaadmin"
+	"github.com/honeycombio/kafka-kit/kafkazk"
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+
+	"google.golang.org/grpc"
+)
+
+// watchZK serves a mutable set of
+// brokers, topics and config changes.
+type watchZK struct {
+	kafkazk.Mock
+	brokers []int
+	topics  []string
+	changes []kafkazk.ConfigChange
+}
+
+func (zk *watchZK) GetAllBrokerMeta(bool) (kafkazk.BrokerMetaMap, []error) {
+	bm := kafkazk.BrokerMetaMap{}
+	for _, id := range zk.brokers {
+		bm[id] = &kafkazk.BrokerMeta{}
+	}
+
+	return bm, nil
+}
+
+func (zk *watchZK) GetTopics([]*regexp.Regexp) ([]string, error) {
+	return zk.topics, nil
+}
+
+func (zk *watchZK) GetConfigChanges(since int64) ([]kafkazk.ConfigChange, error) {
+	var changes []kafkazk.ConfigChange
+	for _, c := range zk.changes {
+		if c.Seq > since {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes, nil
+}
+
+// eventStream records sent WatchEvents.
+type eventStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *pb.WatchEvent
+}
+
+func (e *eventStream) Send(m *pb.WatchEvent) error {
+	e.events <- m
+	return nil
+}
+
+func (e *eventStream) Context() context.Context {
+	return e.ctx
+}
+
+func TestPollChanges(t *testing.T) {
+	s := testServer()
+	zk := &watchZK{
+		brokers: []int{1001, 1002},
+		topics:  []string{"test_topic"},
+		changes: []kafkazk.ConfigChange{{Seq: 0, EntityPath: "topics/test_topic"}},
+	}
+	s.ZK = zk
+
+	// The first poll is the baseline.
+	state, events, err := s.pollChanges(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 0 || state.configSeq != 0 {
+		t.Errorf("Unexpected events %v, config seq %d", events, state.configSeq)
+	}
+
+	zk.brokers = []int{1002, 1003}
+	zk.topics = []string{"test_topic2", "test_topic"}
+	zk.changes = append(zk.changes, kafkazk.ConfigChange{Seq: 1, EntityPath: "brokers/1002"})
+
+	_, events, err = s.pollChanges(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"broker added 1003",
+		"broker removed 1001",
+		"topic added test_topic2",
+		"config changed brokers/1002",
+	}
+
+	var got []string
+	for _, e := range events {
+		got = append(got, e.Type+" "+e.Action+" "+e.Name)
+	}
+
+	if !stringsEqual(got, expected) {
+		t.Errorf("Expected events %v, got %v", expected, got)
+	}
+}
+
+// adminWatchZK is a watchZK without config
+// change notifications, as with the
+// kafkaadmin.Handler.
+type adminWatchZK struct {
+	watchZK
+}
+
+func (zk *adminWatchZK) GetConfigChanges(since int64) ([]kafkazk.ConfigChange, error) {
+	return nil, kafkaadmin.ErrNotSupported
+}
+
+func TestPollChangesNotSupported(t *testing.T) {
+	s := testServer()
+	zk := &adminWatchZK{watchZK{brokers: []int{1001}}}
+	s.ZK = zk
+
+	state, _, err := s.pollChanges(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zk.topics = []string{"test_topic"}
+
+	_, events, err := s.pollChanges(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 1 || events[0].Type != "topic" || events[0].Name != "test_topic" {
+		t.Errorf("Unexpected events %v", events)
+	}
+}
+
+func TestWatchHub(t *testing.T) {
+	h := newWatchHub()
+	topics := h.subscribe([]string{"topic"})
+	all := h.subscribe(nil)
+
+	h.publish(&pb.WatchEvent{Type: "broker"})
+	h.publish(&pb.WatchEvent{Type: "topic"})
+
+	if len(topics.events) != 1 || len(all.events) != 2 {
+		t.Errorf("Expected 1 and 2 events, got %d and %d", len(topics.events), len(all.events))
+	}
+
+	// Subscribers that fall behind are removed.
+	for i := 0; i < watchBufferSize; i++ {
+		h.publish(&pb.WatchEvent{Type: "broker"})
+	}
+
+	if h.subscribers() != 1 {
+		t.Errorf("Expected 1 subscriber, got %d", h.subscribers())
+	}
+
+	for range all.events {
+	}
+
+	h.unsubscribe(topics)
+	if h.subscribers() != 0 {
+		t.Errorf("Expected 0 subscribers, got %d", h.subscribers())
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s := testServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &eventStream{ctx: ctx, events: make(chan *pb.WatchEvent, 10)}
+
+	done := make(chan error)
+	go func() {
+		done <- s.Watch(&pb.WatchRequest{Types: []string{"tag"}}, stream)
+	}()
+
+	for s.watchHub.subscribers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	req := &pb.TopicRequest{Name: "test_topic", Tag: []string{"team:data"}}
+	if _, err := s.TagTopic(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	e := <-stream.events
+	if e.Type != "tag" || e.Action != "changed" || e.Name != "topics/test_topic" {
+		t.Errorf("Unexpected event %v", e)
+	}
+
+	cancel()
+
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected error '%s', got '%v'", context.Canceled, err)
+	}
+
+	if s.watchHub.subscribers() != 0 {
+		t.Error("Expected the subscriber to be removed")
+	}
+
+	stream.ctx = context.Background()
+	if err := s.Watch(&pb.WatchRequest{Types: []string{"quota"}}, stream); err != ErrInvalidWatchType {
+		t.Errorf("Expected error '%s', got '%v'", ErrInvalidWatchType, err)
+	}
+}
@@ -0,0 +1,226 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+const (
+	// webhookAttempts is the number of delivery
+	// attempts made for each webhook event.
+	webhookAttempts = 5
+	// webhookSignatureHeader is the header holding the hex
+	// HMAC-SHA256 of the request body, keyed by the webhook
+	// secret (e.g. sha256=<hex>).
+	webhookSignatureHeader = "X-Registry-Signature"
+)
+
+// webhook is an endpoint that's sent
+// watch events of the types specified;
+// all types if empty.
+type webhook struct {
+	URL    string   `json:"url"`
+	Types  []string `json:"types"`
+	Secret string   `json:"secret"`
+}
+
+// webhookConfig holds the webhooks and delivery settings.
+type webhookConfig struct {
+	hooks  []webhook
+	client *http.Client
+	// The delay before the first retry,
+	// doubled for each subsequent retry.
+	retryBackoff time.Duration
+}
+
+// readWebhooks reads a JSON array of webhooks from path.
+func readWebhooks(path string) ([]webhook, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var hooks []webhook
+	if err := json.Unmarshal(data, &hooks); err != nil {
+		return nil, fmt.Errorf("error parsing webhooks: %s", err)
+	}
+
+	for _, h := range hooks {
+		if u, err := url.Parse(h.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL '%s'", h.URL)
+		}
+
+		for _, t := range h.Types {
+			if _, valid := watchTypes[t]; !valid {
+				return nil, fmt.Errorf("webhook %s: %s", h.URL, ErrInvalidWatchType)
+			}
+		}
+	}
+
+	return hooks, nil
+}
+
+// RunWebhooks runs a sender for each configured webhook and cluster that
+// delivers the watch events of the types the webhook is registered for.
+// Webhooks are Watch subscribers; see RunWatch. With leader election,
+// events are delivered while this registry is the leader.
+func (s *Server) RunWebhooks(ctx context.Context, wg *sync.WaitGroup) error {
+	for _, c := range s.clusterServers() {
+		for _, h := range s.webhooks.hooks {
+			wg.Add(1)
+
+			go func(c *Server, h webhook) {
+				defer wg.Done()
+				c.runWebhook(ctx, h)
+			}(c, h)
+		}
+	}
+
+	if n := len(s.webhooks.hooks); n > 0 {
+		log.Printf("Webhooks running: %d\n", n)
+	}
+
+	return nil
+}
+
+// runWebhook delivers events for the webhook until the context is
+// cancelled. Events are delivered in order; if the webhook falls behind
+// the watch buffer, it's resubscribed and the dropped events are lost.
+func (s *Server) runWebhook(ctx context.Context, h webhook) {
+	for {
+		sub := s.watchHub.subscribe(h.Types)
+
+		if !s.deliverEvents(ctx, h, sub) {
+			s.watchHub.unsubscribe(sub)
+			return
+		}
+
+		log.Printf("Webhook %s fell behind; events were dropped\n", h.URL)
+	}
+}
+
+// deliverEvents delivers events from the subscriber to the webhook. False
+// is returned if the context is cancelled, true if the subscriber is
+// closed.
+func (s *Server) deliverEvents(ctx context.Context, h webhook, sub *watchSubscriber) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case e, ok := <-sub.events:
+			if !ok {
+				return true
+			}
+
+			// Events are only delivered by the leader.
+			if !s.isLeader() {
+				continue
+			}
+
+			err := s.deliver(ctx, h, e)
+			switch {
+			case ctx.Err() != nil:
+				return false
+			case err != nil:
+				log.Printf("Webhook %s: failed to deliver %s %s event for %s: %s\n",
+					h.URL, e.Type, e.Action, e.Name, err)
+			}
+		}
+	}
+}
+
+// errNonRetryable wraps delivery errors
+// that aren't retried.
+type errNonRetryable struct{ error }
+
+// deliver sends the event to the webhook, retrying failed
+// attempts with exponential backoff.
+func (s *Server) deliver(ctx context.Context, h webhook, e *pb.WatchEvent) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	// Retries share the delivery ID, which
+	// receivers can use to drop duplicates.
+	id := make([]byte, 8)
+	rand.Read(id)
+	delivery := hex.EncodeToString(id)
+
+	backoff := s.webhooks.retryBackoff
+
+	for i := 1; ; i++ {
+		err = s.post(ctx, h, delivery, body)
+		if _, nonRetryable := err.(errNonRetryable); err == nil || nonRetryable || i == webhookAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+// post makes a single delivery attempt of the body to the webhook.
+func (s *Server) post(ctx context.Context, h webhook, delivery string, body []byte) error {
+	req, err := http.NewRequest("POST", h.URL, bytes.NewReader(body))
+	if err != nil {
+		return errNonRetryable{err}
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Registry-Delivery", delivery)
+
+	if h.Secret != "" {
+		req.Header.Set(webhookSignatureHeader, "sha256="+webhookSignature(h.Secret, body))
+	}
+
+	resp, err := s.webhooks.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	switch c := resp.StatusCode; {
+	case c >= 200 && c < 300:
+		return nil
+	// Client errors other than throttling
+	// won't succeed when retried.
+	case c >= 400 && c < 500 && c != http.StatusTooManyRequests:
+		return errNonRetryable{errors.New(resp.Status)}
+	default:
+		return errors.New(resp.Status)
+	}
+}
+
+// webhookSignature returns the hex HMAC-SHA256
+// of the body keyed by the secret.
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/honeycombio/kafka-kit/registry/protos"
+)
+
+func TestReadWebhooks(t *testing.T) {
+	tests := map[string]bool{
+		`[{"url": "https://cmdb.example.com/kafka", "types": ["topic", "tag"], "secret": "s"}]`: true,
+		`[{"url": "http://localhost:9000"}]`:                                                    true,
+		`[{"url": "cmdb.example.com"}]`:                                                         false,
+		`[{"url": "https://cmdb.example.com", "types": ["partition"]}]`:                         false,
+		`{"url": "https://cmdb.example.com"}`:                                                   false,
+	}
+
+	for input, valid := range tests {
+		f, err := ioutil.TempFile("", "webhooks")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f.WriteString(input)
+		f.Close()
+
+		_, err = readWebhooks(f.Name())
+		os.Remove(f.Name())
+
+		if (err == nil) != valid {
+			t.Errorf("[%s] Expected valid: %v, got error '%v'", input, valid, err)
+		}
+	}
+}
+
+// webhookReceiver records delivered events,
+// failing the first failures attempts.
+type webhookReceiver struct {
+	sync.Mutex
+	failures   int
+	failStatus int
+	attempts   int
+	deliveries map[string]struct{}
+	received   chan *pb.WatchEvent
+	signatures []string
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.attempts++
+	r.deliveries[req.Header.Get("X-Registry-Delivery")] = struct{}{}
+
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(r.failStatus)
+		return
+	}
+
+	body, _ := ioutil.ReadAll(req.Body)
+	r.signatures = append(r.signatures, req.Header.Get(webhookSignatureHeader))
+
+	e := &pb.WatchEvent{}
+	json.Unmarshal(body, e)
+	r.received <- e
+}
+
+func newWebhookReceiver() *webhookReceiver {
+	return &webhookReceiver{
+		deliveries: map[string]struct{}{},
+		received:   make(chan *pb.WatchEvent, 10),
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	s := testServer()
+	s.webhooks.retryBackoff = time.Millisecond
+
+	r := newWebhookReceiver()
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	h := webhook{URL: srv.URL, Secret: "secret"}
+	e := &pb.WatchEvent{Type: "topic", Action: "added", Name: "test_topic", Timestamp: 1}
+
+	// Retried server errors.
+	r.failures, r.failStatus = 2, http.StatusServiceUnavailable
+	if err := s.deliver(context.Background(), h, e); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.attempts != 3 || len(r.deliveries) != 1 {
+		t.Errorf("Expected 3 attempts of 1 delivery, got %d attempts of %d", r.attempts, len(r.deliveries))
+	}
+
+	got := <-r.received
+	if got.Type != "topic" || got.Action != "added" || got.Name != "test_topic" {
+		t.Errorf("Unexpected event %v", got)
+	}
+
+	body, _ := json.Marshal(e)
+	if expected := "sha256=" + webhookSignature("secret", body); r.signatures[0] != expected {
+		t.Errorf("Expected signature %s, got %s", expected, r.signatures[0])
+	}
+
+	// Attempts are exhausted.
+	r.attempts, r.failures = 0, webhookAttempts
+	if err := s.deliver(context.Background(), h, e); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	if r.attempts != webhookAttempts {
+		t.Errorf("Expected %d attempts, got %d", webhookAttempts, r.attempts)
+	}
+
+	// Client errors aren't retried.
+	r.attempts, r.failures, r.failStatus = 0, 1, http.StatusNotFound
+	if err := s.deliver(context.Background(), h, e); err == nil {
+		t.Error("Expected non-nil error")
+	}
+
+	if r.attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", r.attempts)
+	}
+}
+
+func TestRunWebhooks(t *testing.T) {
+	s := testServer()
+
+	r := newWebhookReceiver()
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	s.webhooks.hooks = []webhook{{URL: srv.URL, Types: []string{"tag"}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+
+	if err := s.RunWebhooks(ctx, wg); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the subscription.
+	for s.watchHub.subscribers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	s.watchHub.publish(&pb.WatchEvent{Type: "topic", Action: "added", Name: "test_topic"})
+	s.publishTagChange(KafkaObject{Type: "topic", ID: "test_topic"})
+
+	select {
+	case e := <-r.received:
+		if e.Type != "tag" || e.Name != "topics/test_topic" {
+			t.Errorf("Unexpected event %v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the webhook event")
+	}
+
+	cancel()
+	wg.Wait()
+
+	if n := s.watchHub.subscribers(); n != 0 {
+		t.Errorf("Expected 0 subscribers, got %d", n)
+	}
+}